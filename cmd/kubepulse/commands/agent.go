@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	"github.com/kubepulse/kubepulse/pkg/plugins"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+)
+
+var (
+	agentServer   string
+	agentName     string
+	agentToken    string
+	agentInterval time.Duration
+)
+
+// agentCmd represents the agent command
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run health checks and push the results to a central KubePulse server",
+	Long: `Agent runs the health checks of a check profile against the current
+cluster and pushes a summary of the results to a central "kubepulse serve"
+after every round. It has no dashboard, API, WebSocket, AI analysis, alerting
+or history, so it fits clusters with tight resource budgets.
+
+The central server lists agents with GET /api/v1/agents and includes them as
+clusters in /api/v1/health/multi. An agent that misses three reports in a
+row is shown as unknown. The server accepts reports only with an admin
+token, passed with --token or KUBEPULSE_TOKEN.`,
+	Example: `  kubepulse agent --server https://kubepulse.example.com
+  kubepulse agent --server https://kubepulse.example.com --name prod-eu --interval 1m --check-profile minimal`,
+	Args: cobra.NoArgs,
+	RunE: runAgent,
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+
+	agentCmd.Flags().StringVar(&agentServer, "server", "", "URL of the central KubePulse server")
+	agentCmd.Flags().StringVar(&agentName, "name", "", "Name to report the cluster under (defaults to the current context)")
+	agentCmd.Flags().StringVar(&agentToken, "token", os.Getenv("KUBEPULSE_TOKEN"), "API token for the central server")
+	agentCmd.Flags().DurationVarP(&agentInterval, "interval", "i", fleet.DefaultAgentInterval, "Interval between reports")
+	agentCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to monitor (empty for all)")
+	agentCmd.Flags().StringVar(&checkProfile, "check-profile", "", "Check profile to run: minimal, standard, deep or one from monitoring.check_profiles")
+	_ = agentCmd.MarkFlagRequired("server")
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	kubeClient := GetK8sClient()
+	if kubeClient == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+
+	currentContext := ""
+	if contextManager, err := newContextManager(); err == nil {
+		if ctx, err := contextManager.GetCurrentContext(); err == nil {
+			currentContext = ctx.Name
+		}
+	}
+	name := agentName
+	if name == "" {
+		name = currentContext
+	}
+	if name == "" {
+		return fmt.Errorf("no current context to name the agent after; set --name")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Register the built-in checks; the pod check skips log and scheduling
+	// analysis to keep the agent's API calls and memory down
+	registry := plugins.NewRegistry()
+	podConfig := map[string]interface{}{"log_analysis": false, "scheduling_analysis": false}
+	if namespace != "" {
+		podConfig["namespace"] = namespace
+	}
+	podCheck := health.NewPodHealthCheck()
+	if err := podCheck.Configure(podConfig); err != nil {
+		return fmt.Errorf("failed to configure pod check: %w", err)
+	}
+	if err := registry.Register(podCheck); err != nil {
+		return fmt.Errorf("failed to register pod check: %w", err)
+	}
+	if err := registry.Register(health.NewNodeHealthCheck()); err != nil {
+		return fmt.Errorf("failed to register node check: %w", err)
+	}
+	if err := registry.Register(health.NewNodeVersionCheck()); err != nil {
+		return fmt.Errorf("failed to register node version check: %w", err)
+	}
+	namespaced := []core.HealthCheck{
+		health.NewServiceHealthCheck(),
+		health.NewEventRateCheck(),
+		health.NewIngressHealthCheck(GetDynamicClient()),
+		health.NewServiceMeshHealthCheck(GetDynamicClient()),
+	}
+	for _, check := range namespaced {
+		if namespace != "" {
+			if err := check.Configure(map[string]interface{}{"namespace": namespace}); err != nil {
+				return fmt.Errorf("failed to configure %s check: %w", check.Name(), err)
+			}
+		}
+		if err := registry.Register(check); err != nil {
+			return fmt.Errorf("failed to register %s check: %w", check.Name(), err)
+		}
+	}
+	podSecurityCheck := health.NewPodSecurityCheck()
+	if err := podSecurityCheck.Configure(podSecurityConfig(cfg.Monitoring.PodSecurity, namespace)); err != nil {
+		return fmt.Errorf("failed to configure pod security check: %w", err)
+	}
+	if err := registry.Register(podSecurityCheck); err != nil {
+		return fmt.Errorf("failed to register pod security check: %w", err)
+	}
+
+	profile := cfg.Monitoring.CheckProfileFor(currentContext)
+	if cmd.Flags().Changed("check-profile") {
+		profile = checkProfile
+	}
+	checks, err := profileChecks(registry, cfg.Monitoring, profile, nil)
+	if err != nil {
+		return fmt.Errorf("failed to select health checks: %w", err)
+	}
+
+	apiClient, err := client.NewClient(client.Config{BaseURL: agentServer, Token: agentToken})
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+	agent := fleet.NewAgent(fleet.AgentConfig{
+		Name:     name,
+		Client:   kubeClient,
+		Checks:   checks,
+		Interval: agentInterval,
+		Push:     apiClient.ReportAgent,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	klog.Infof("Agent %s reporting %d checks from the %s check profile to %s every %s", name, len(checks), profile, agentServer, agentInterval)
+	agent.Run(ctx)
+	klog.Info("Agent stopped")
+	return nil
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"k8s.io/klog/v2"
+)
+
+// configureAlerting registers the enabled notification channels, their quiet
+// hours, the escalation policies, the routes by owner, the queue retrying rejected notifications
+// and the configured alert rules with the engine. Rules are only loaded when
+// every rule's embedded tests pass. It returns the signing secret of the Slack app whose
+// Acknowledge buttons the server should accept.
+func configureAlerting(engine *core.Engine, cfg config.AlertsConfig) (string, error) {
+	if !cfg.Enabled {
+		return "", nil
+	}
+
+	names := make([]string, 0, len(cfg.Channels))
+	for name := range cfg.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var slackSecret string
+	enabled := make(map[string]bool)
+	for _, name := range names {
+		channelCfg := cfg.Channels[name]
+		if !channelCfg.Enabled {
+			continue
+		}
+		channel, err := alerts.NewChannel(name, channelCfg.Type, channelCfg.Settings)
+		if err != nil {
+			// Channel types without a sender yet (such as email) are skipped
+			klog.Warningf("Skipping alert channel: %v", err)
+			continue
+		}
+		engine.RegisterAlertChannel(channel)
+		enabled[channel.Name()] = true
+
+		if quiet := channelCfg.QuietHours; quiet != nil {
+			if err := engine.SetChannelQuietHours(channel.Name(), alerts.QuietHours{
+				Start:    quiet.Start,
+				End:      quiet.End,
+				Timezone: quiet.Timezone,
+				Bypass:   alerts.AlertSeverity(quiet.Bypass),
+			}); err != nil {
+				return "", err
+			}
+		}
+		if secret, _ := channelCfg.Settings["signing_secret"].(string); secret != "" && slackSecret == "" {
+			slackSecret = secret
+		}
+	}
+
+	engine.SetAlertExternalURL(cfg.ExternalURL)
+
+	queue, err := alerts.NewDeliveryQueue(alerts.DeliveryConfig{
+		Path:           backup.ExpandHome(cfg.Delivery.QueueFile),
+		InitialBackoff: cfg.Delivery.InitialBackoff,
+		MaxBackoff:     cfg.Delivery.MaxBackoff,
+		MaxAttempts:    cfg.Delivery.MaxAttempts,
+	})
+	if err != nil {
+		return "", err
+	}
+	engine.SetDeliveryQueue(queue)
+	if pending := len(queue.List(alerts.DeliveryPending)); pending > 0 {
+		klog.Infof("Resuming delivery of %d queued notifications", pending)
+	}
+
+	for name, escalation := range cfg.Escalations {
+		policy := alerts.EscalationPolicy{Name: name}
+		for _, severity := range escalation.Severities {
+			policy.Severities = append(policy.Severities, alerts.AlertSeverity(severity))
+		}
+		for _, step := range escalation.Steps {
+			if !enabled[step.Channel] {
+				klog.Warningf("Escalation policy %s skips channel %s, which is not enabled", name, step.Channel)
+				continue
+			}
+			policy.Steps = append(policy.Steps, alerts.EscalationStep{Channel: step.Channel, After: step.After})
+		}
+		if len(policy.Steps) == 0 {
+			continue
+		}
+		if err := engine.AddEscalationPolicy(policy); err != nil {
+			return "", err
+		}
+	}
+
+	var routes []alerts.Route
+	for i, route := range cfg.Routes {
+		if !enabled[route.Channel] {
+			klog.Warningf("Alert route %d skips channel %s, which is not enabled", i, route.Channel)
+			continue
+		}
+		routes = append(routes, alerts.Route{Team: route.Team, App: route.App, Tier: route.Tier, Channel: route.Channel})
+	}
+	if err := engine.SetAlertRoutes(routes); err != nil {
+		return "", err
+	}
+
+	specs, results, err := configRules(cfg.Rules)
+	if err != nil {
+		return "", err
+	}
+	if err := alerts.RuleTestFailures(results); err != nil {
+		return "", fmt.Errorf("refusing to load alert rules: %w", err)
+	}
+	for _, spec := range specs {
+		if err := engine.UpsertAlertRule(spec); err != nil {
+			return "", err
+		}
+	}
+	if len(results) > 0 {
+		klog.Infof("Loaded %d alert rules from config; %d embedded tests passed", len(specs), len(results))
+	}
+
+	return slackSecret, nil
+}
+
+// applyEnvironment sets the engine up for the environment of a kubeconfig
+// context, if it has one: how its alerts are raised and routed, and how
+// much AI analysis its failures get
+func applyEnvironment(engineConfig *core.EngineConfig, cfg config.EnvironmentsConfig, context string) {
+	name, policy, ok := cfg.EnvironmentFor(context)
+	if !ok {
+		return
+	}
+	engineConfig.Environment = alerts.Environment{
+		Name:           name,
+		MaxSeverity:    alerts.AlertSeverity(policy.MaxSeverity),
+		Channel:        policy.Channel,
+		CooldownFactor: policy.CooldownFactor,
+		Escalate:       policy.Escalate,
+	}
+	engineConfig.AIMinSeverity = core.AlertSeverity(policy.AIMinSeverity)
+	if len(policy.AIQueue) > 0 {
+		engineConfig.AIQueueCapacity = make(map[core.AlertSeverity]int, len(policy.AIQueue))
+		for severity, capacity := range policy.AIQueue {
+			engineConfig.AIQueueCapacity[core.AlertSeverity(severity)] = capacity
+		}
+	}
+	klog.Infof("Monitoring context %s as a %s cluster", context, name)
+}
+
+// configRules builds the specs of the configured alert rules, sorted by
+// name, and runs their embedded tests. Rules with only the old free-text
+// condition can't be evaluated and are skipped.
+func configRules(rules map[string]config.AlertRuleConfig) ([]alerts.RuleSpec, []alerts.RuleTestResult, error) {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var specs []alerts.RuleSpec
+	var results []alerts.RuleTestResult
+	for _, name := range names {
+		ruleCfg := rules[name]
+		if ruleCfg.Check == "" && ruleCfg.Reason == "" {
+			klog.Warningf("Skipping alert rule %s: it sets neither a check nor an event reason", name)
+			continue
+		}
+
+		spec := alerts.RuleSpec{
+			Name:       name,
+			Check:      ruleCfg.Check,
+			Status:     alerts.HealthStatus(ruleCfg.Status),
+			Reason:     ruleCfg.Reason,
+			Threshold:  ruleCfg.Threshold,
+			Severity:   alerts.AlertSeverity(ruleCfg.Severity),
+			Channel:    ruleCfg.Channel,
+			Template:   ruleCfg.Template,
+			Escalation: ruleCfg.Escalation,
+			Runbook:    ruleCfg.Runbook,
+		}
+		if spec.Channel == "" && len(ruleCfg.Channels) > 0 {
+			spec.Channel = ruleCfg.Channels[0]
+		}
+		if ruleCfg.Cooldown > 0 {
+			spec.Cooldown = ruleCfg.Cooldown.String()
+		}
+		rule, err := spec.Build()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid alert rule %s: %w", name, err)
+		}
+
+		tests := make([]alerts.RuleTest, 0, len(ruleCfg.Tests))
+		for _, test := range ruleCfg.Tests {
+			tests = append(tests, alerts.RuleTest{
+				Name: test.Name,
+				Result: alerts.CheckResult{
+					Name:    test.Result.Check,
+					Status:  alerts.HealthStatus(test.Result.Status),
+					Message: test.Result.Message,
+					Details: test.Result.Details,
+				},
+				Fire: test.Fire,
+			})
+		}
+		specs = append(specs, spec)
+		results = append(results, alerts.RunRuleTests(rule, tests)...)
+	}
+	return specs, results, nil
+}
+
+// runbookLinks returns the distinct runbook URLs configured for checks, sorted
+func runbookLinks(runbooks map[string]string) []string {
+	seen := make(map[string]bool, len(runbooks))
+	links := make([]string, 0, len(runbooks))
+	for _, link := range runbooks {
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	sort.Strings(links)
+	return links
+}
+
+// verifyRunbooks warns about configured runbooks that do not load
+func verifyRunbooks(ctx context.Context, links []string) {
+	for _, result := range preflight.CheckRunbooks(ctx, links, nil) {
+		if result.Status != preflight.StatusPass {
+			klog.Warningf("Runbook check failed: %s", result.Message)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigureAlerting(t *testing.T) {
+	channels := map[string]config.ChannelConfig{
+		"log": {Type: "log", Enabled: true},
+		"slack": {
+			Type:       "slack",
+			Enabled:    true,
+			Settings:   map[string]interface{}{"webhook": "https://hooks.example.com/slack", "signing_secret": "s3cret"},
+			QuietHours: &config.QuietHoursConfig{Start: "22:00", End: "07:00", Timezone: "UTC"},
+		},
+		"pagerduty": {Type: "pagerduty", Enabled: false},
+		"email":     {Type: "email", Enabled: true},
+	}
+
+	tests := []struct {
+		name       string
+		alerts     config.AlertsConfig
+		wantSecret string
+		wantRules  []string
+		wantErr    string
+	}{
+		{
+			name: "disabled channels are skipped in policies",
+			alerts: config.AlertsConfig{
+				Enabled:  true,
+				Channels: channels,
+				Escalations: map[string]config.EscalationConfig{
+					"pages": {Severities: []string{"critical"}, Steps: []config.EscalationStepConfig{
+						{Channel: "slack"}, {Channel: "pagerduty", After: 15 * time.Minute},
+					}},
+				},
+			},
+			wantSecret: "s3cret",
+		},
+		{
+			name: "invalid quiet hours",
+			alerts: config.AlertsConfig{
+				Enabled: true,
+				Channels: map[string]config.ChannelConfig{
+					"slack": {Type: "slack", Enabled: true, Settings: map[string]interface{}{"webhook": "https://hooks.example.com/slack"},
+						QuietHours: &config.QuietHoursConfig{Start: "late", End: "07:00"}},
+				},
+			},
+			wantErr: "quiet hours",
+		},
+		{
+			name: "invalid policy",
+			alerts: config.AlertsConfig{
+				Enabled:  true,
+				Channels: channels,
+				Escalations: map[string]config.EscalationConfig{
+					"pages": {Steps: []config.EscalationStepConfig{{Channel: "slack", After: time.Minute}}},
+				},
+			},
+			wantErr: "immediately",
+		},
+		{
+			name: "rules whose tests pass are loaded",
+			alerts: config.AlertsConfig{
+				Enabled: true,
+				Rules: map[string]config.AlertRuleConfig{
+					"evictions": {Reason: "Evicted", Threshold: 2, Severity: "warning", Tests: []config.AlertRuleTestConfig{
+						{Name: "burst", Result: config.AlertRuleTestFixture{Details: map[string]interface{}{"rates": map[string]interface{}{"evicted": 5}}}, Fire: true},
+						{Name: "quiet", Result: config.AlertRuleTestFixture{Details: map[string]interface{}{"rates": map[string]interface{}{"evicted": 1}}}},
+					}},
+					"legacy": {Condition: "pods are down", Severity: "high"},
+				},
+			},
+			wantRules: []string{"evictions"},
+		},
+		{
+			name: "rules whose tests fail are refused",
+			alerts: config.AlertsConfig{
+				Enabled: true,
+				Rules: map[string]config.AlertRuleConfig{
+					"pods-down": {Check: "pod-health", Status: "unhealthy", Severity: "critical", Tests: []config.AlertRuleTestConfig{
+						{Name: "degraded", Result: config.AlertRuleTestFixture{Status: "degraded"}, Fire: true},
+					}},
+				},
+			},
+			wantErr: "refusing to load alert rules: 1 alert rule tests failed: pods-down: degraded: did not fire, want fire",
+		},
+		{
+			name: "invalid rule",
+			alerts: config.AlertsConfig{
+				Enabled: true,
+				Rules:   map[string]config.AlertRuleConfig{"pods-down": {Check: "pod-health", Severity: "high"}},
+			},
+			wantErr: "invalid alert rule pods-down",
+		},
+		{
+			name:   "alerting disabled",
+			alerts: config.AlertsConfig{Enabled: false, Channels: channels},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+			secret, err := configureAlerting(engine, tt.alerts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if secret != tt.wantSecret {
+				t.Errorf("expected signing secret %q, got %q", tt.wantSecret, secret)
+			}
+			for _, name := range tt.wantRules {
+				found := false
+				for _, rule := range engine.GetAlertRules() {
+					found = found || rule.Name == name
+				}
+				if !found {
+					t.Errorf("expected rule %s to be loaded", name)
+				}
+			}
+			for _, rule := range engine.GetAlertRules() {
+				if rule.Name == "legacy" {
+					t.Error("expected the free-text rule to be skipped")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertsRenderRule    string
+	alertsRenderFixture string
+)
+
+// alertsCmd represents the alerts command
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Work with alert rules offline",
+}
+
+// alertsRenderCmd represents the alerts render command
+var alertsRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Preview the notification an alert rule sends for a check result",
+	Long: `Render prints the message an alert rule's template produces for a check
+result read from a JSON fixture, without connecting to the cluster or sending
+anything. The rule is one of the built-in rules or one under alerts.rules in
+the config file. Links to the dashboard and silences use alerts.external_url.
+
+The fixture is a check result with the fields templates see as .Check, plus
+the cluster and an AI diagnosis:
+
+  {
+    "name": "pod-health",
+    "status": "unhealthy",
+    "message": "3 pods are failing",
+    "details": {"restarts": 12},
+    "cluster": "prod-eu",
+    "diagnosis": {"summary": "Image pull failures", "confidence": 0.85,
+                  "recommendations": ["Fix the image tag"]}
+  }
+
+The check defaults to the rule's and the cluster to kubernetes.context.`,
+	Example: `  kubepulse alerts render --rule pods-down --fixture pod-failure.json
+  kubepulse alerts render --rule pod-health-critical --fixture pod-failure.json -o json`,
+	Args: cobra.NoArgs,
+	RunE: runAlertsRender,
+}
+
+func init() {
+	rootCmd.AddCommand(alertsCmd)
+	alertsCmd.AddCommand(alertsRenderCmd)
+
+	alertsRenderCmd.Flags().StringVar(&alertsRenderRule, "rule", "", "Alert rule to render")
+	alertsRenderCmd.Flags().StringVar(&alertsRenderFixture, "fixture", "", "JSON file with the check result to render")
+	_ = alertsRenderCmd.MarkFlagRequired("rule")
+	_ = alertsRenderCmd.MarkFlagRequired("fixture")
+}
+
+// renderedAlert is the JSON and YAML output of the alerts render command
+type renderedAlert struct {
+	Rule     string               `json:"rule"`
+	Severity alerts.AlertSeverity `json:"severity"`
+	Channel  string               `json:"channel"`
+	Message  string               `json:"message"`
+}
+
+func runAlertsRender(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	specs, _, err := configRules(cfg.Alerts.Rules)
+	if err != nil {
+		return err
+	}
+	rules := append(alerts.CreateDefaultRules(), alerts.CreateEventRateRules()...)
+	for _, spec := range specs {
+		rule, err := spec.Build()
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	rule, err := findAlertRule(rules, alertsRenderRule)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(alertsRenderFixture) // #nosec G304 - user-selected fixture
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var result alerts.CheckResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %w", alertsRenderFixture, err)
+	}
+	if result.Name == "" {
+		result.Name = rule.Check
+	}
+	if result.Cluster == "" {
+		result.Cluster = cfg.Kubernetes.Context
+	}
+
+	manager := alerts.NewManager()
+	manager.SetExternalURL(cfg.Alerts.ExternalURL)
+	message, err := manager.RenderRule(rule, result)
+	if err != nil {
+		return fmt.Errorf("failed to render alert rule %s: %w", rule.Name, err)
+	}
+
+	rendered := renderedAlert{Rule: rule.Name, Severity: rule.Severity, Channel: rule.Channel, Message: message}
+	if rule.Escalation != "" {
+		rendered.Channel = "escalation " + rule.Escalation
+	}
+	return printer.Print(rendered, func(w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "# %s (%s) to %s\n", rendered.Rule, rendered.Severity, rendered.Channel)
+		_, _ = fmt.Fprintln(w, rendered.Message)
+		return nil
+	})
+}
+
+// findAlertRule returns the rule with a name, or an error listing the rules
+func findAlertRule(rules []alerts.AlertRule, name string) (alerts.AlertRule, error) {
+	names := make([]string, 0, len(rules))
+	for i := len(rules) - 1; i >= 0; i-- {
+		// Configured rules come last and replace built-in rules of the same name
+		if strings.EqualFold(rules[i].Name, name) {
+			return rules[i], nil
+		}
+		names = append(names, rules[i].Name)
+	}
+	sort.Strings(names)
+	return alerts.AlertRule{}, fmt.Errorf("alert rule %s not found; available: %s", name, strings.Join(names, ", "))
+}
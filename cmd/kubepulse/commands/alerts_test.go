@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunAlertsRender(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "kubepulse.yaml")
+	config := "kubernetes:\n  context: prod-eu\nalerts:\n  external_url: https://kubepulse.example.com\n  rules:\n    pods-down:\n      check: pod-health\n      severity: critical\n      runbook: https://runbooks.example.com/pods\n      template: \"[{{.Cluster}}] {{.Check.Message}}{{with .Diagnosis}} - AI: {{.Summary}}{{end}} {{.Runbook}} {{.DashboardURL}}\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	fixturePath := filepath.Join(dir, "fixture.json")
+	fixture := `{"status": "unhealthy", "message": "3 pods are failing", "diagnosis": {"summary": "Image pull failures", "confidence": 0.85}}`
+	if err := os.WriteFile(fixturePath, []byte(fixture), 0600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		fixture string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "configured rule",
+			rule:    "pods-down",
+			fixture: fixturePath,
+			want: []string{
+				"# pods-down (critical) to log",
+				"[prod-eu] 3 pods are failing - AI: Image pull failures https://runbooks.example.com/pods https://kubepulse.example.com",
+			},
+		},
+		{
+			name:    "built-in rule",
+			rule:    "pod-health-critical",
+			fixture: fixturePath,
+			want:    []string{"Critical pod health issue: 3 pods are failing"},
+		},
+		{
+			name:    "unknown rule",
+			rule:    "nodes-down",
+			fixture: fixturePath,
+			wantErr: "alert rule nodes-down not found; available: event-rate-evicted",
+		},
+		{
+			name:    "missing fixture",
+			rule:    "pods-down",
+			fixture: filepath.Join(dir, "missing.json"),
+			wantErr: "failed to read fixture",
+		},
+	}
+
+	defer func() {
+		cfgFile, alertsRenderRule, alertsRenderFixture = "", "", ""
+		alertsRenderCmd.SetOut(nil)
+	}()
+	cfgFile = configPath
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			alertsRenderCmd.SetOut(&buf)
+			alertsRenderRule, alertsRenderFixture = tt.rule, tt.fixture
+
+			err := runAlertsRender(alertsRenderCmd, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %q in output:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
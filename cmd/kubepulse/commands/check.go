@@ -2,68 +2,454 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/core"
 	"github.com/kubepulse/kubepulse/pkg/health"
+	"github.com/kubepulse/kubepulse/pkg/plugins"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
+var checkRecordFile string
+
 // checkCmd represents the check command
 var checkCmd = &cobra.Command{
 	Use:   "check [check-name]",
-	Short: "Run a specific health check",
-	Long: `Run a specific health check and display the results.
-Available checks: pod-health, node-health`,
-	Args: cobra.ExactArgs(1),
+	Short: "Run health checks once",
+	Long: `Run a specific health check and display the results, or without a name run
+every check once against the current context and summarize them.
+Available checks: pod-health, node-health, service-health, event-rates, ingress-health, service-mesh,
+pod-security, node-versions, the custom resource and custom metrics checks defined in the config file,
+and the health check plugins listed by "kubepulse plugins list".
+
+Check exits non-zero when a check is unhealthy or fails to run, so it can
+gate CI pipelines and cron jobs; degraded checks are reported without failing.
+
+With --record the API responses the check read are saved with its result, so
+the run can be reproduced later with "kubepulse replay".`,
+	Example: `  kubepulse check
+  kubepulse check --output json
+  kubepulse check pod-health --namespace default`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runCheck,
 }
 
+// builtinCheckNames are the checks builtinCheck builds, in the order a run
+// of every check reports them
+var builtinCheckNames = []string{
+	"pod-health", "node-health", "service-health", "event-rates",
+	"ingress-health", "service-mesh", "pod-security", "node-versions",
+}
+
+// checkReport is the result of running every check once
+type checkReport struct {
+	Status     core.HealthStatus  `json:"status" yaml:"status"`
+	Healthy    int                `json:"healthy" yaml:"healthy"`
+	Degraded   int                `json:"degraded" yaml:"degraded"`
+	Unhealthy  int                `json:"unhealthy" yaml:"unhealthy"`
+	Restricted int                `json:"restricted" yaml:"restricted"`
+	Unknown    int                `json:"unknown" yaml:"unknown"`
+	Failed     int                `json:"failed" yaml:"failed"`
+	Checks     []core.CheckResult `json:"checks" yaml:"checks"`
+}
+
 func init() {
 	rootCmd.AddCommand(checkCmd)
 	checkCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check (for pod checks)")
+	checkCmd.Flags().StringVar(&checkRecordFile, "record", "", "Save a replayable recording of the check run to this file")
+}
+
+// builtinCheck returns a built-in check by name, scoped to a namespace when
+// the check supports it. dynamicClient may be nil.
+func builtinCheck(name, namespace string, dynamicClient dynamic.Interface) (core.HealthCheck, error) {
+	var check core.HealthCheck
+	switch name {
+	case "pod-health":
+		check = health.NewPodHealthCheck()
+	case "node-health":
+		return health.NewNodeHealthCheck(), nil
+	case "service-health":
+		check = health.NewServiceHealthCheck()
+	case "event-rates":
+		check = health.NewEventRateCheck()
+	case "ingress-health":
+		check = health.NewIngressHealthCheck(dynamicClient)
+	case "service-mesh":
+		check = health.NewServiceMeshHealthCheck(dynamicClient)
+	case "pod-security":
+		check = health.NewPodSecurityCheck()
+	case "node-versions":
+		return health.NewNodeVersionCheck(), nil
+	default:
+		return nil, fmt.Errorf("unknown check: %s", name)
+	}
+
+	if namespace != "" {
+		if err := check.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to configure %s check: %w", name, err)
+		}
+	}
+	return check, nil
+}
+
+// findCheck returns a built-in check, a custom resource or custom metrics
+// check defined in the configuration, or a health check plugin
+func findCheck(name, namespace string, dynamicClient dynamic.Interface) (core.HealthCheck, error) {
+	check, err := builtinCheck(name, namespace, dynamicClient)
+	if err == nil {
+		return check, nil
+	}
+	if cfg, cfgErr := loadConfig(); cfgErr == nil {
+		for _, resource := range cfg.CustomResources {
+			if resource.Name == name {
+				return customResourceCheck(resource, dynamicClient), nil
+			}
+		}
+		for _, metric := range cfg.CustomMetrics {
+			if metric.Name == name {
+				return customMetricsCheck(metric), nil
+			}
+		}
+		plugins, _ := discoverPlugins(cfg.Plugins, contextName)
+		for _, plugin := range plugins {
+			if plugin.Name() == name {
+				return scopedPlugin(plugin, namespace), nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// allChecks returns the built-in checks, the custom resource and custom
+// metrics checks defined in the configuration, and the health check plugins
+func allChecks(namespace string, dynamicClient dynamic.Interface) ([]core.HealthCheck, error) {
+	checks := make([]core.HealthCheck, 0, len(builtinCheckNames))
+	for _, name := range builtinCheckNames {
+		check, err := builtinCheck(name, namespace, dynamicClient)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	for _, resource := range cfg.CustomResources {
+		checks = append(checks, customResourceCheck(resource, dynamicClient))
+	}
+	for _, metric := range cfg.CustomMetrics {
+		checks = append(checks, customMetricsCheck(metric))
+	}
+	plugins, errs := discoverPlugins(cfg.Plugins, contextName)
+	for _, err := range errs {
+		klog.Warningf("Skipping plugin: %v", err)
+	}
+	for _, plugin := range plugins {
+		checks = append(checks, scopedPlugin(plugin, namespace))
+	}
+	return checks, nil
+}
+
+// runChecks runs checks concurrently and reports their results in the
+// order given; a check that fails to run is reported unknown
+func runChecks(ctx context.Context, client kubernetes.Interface, checks []core.HealthCheck) checkReport {
+	results := make([]core.CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check core.HealthCheck) {
+			defer wg.Done()
+			start := time.Now()
+			result, err := check.Check(ctx, client)
+			if err != nil {
+				result = core.CheckResult{
+					Name:      check.Name(),
+					Status:    core.HealthStatusUnknown,
+					Message:   "Check failed to run",
+					Error:     err,
+					Timestamp: time.Now(),
+					Duration:  time.Since(start),
+				}
+			}
+			if result.Name == "" {
+				result.Name = check.Name()
+			}
+			results[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := checkReport{Status: core.HealthStatusHealthy, Checks: results}
+	for _, result := range results {
+		switch result.Status {
+		case core.HealthStatusHealthy:
+			report.Healthy++
+		case core.HealthStatusDegraded:
+			report.Degraded++
+		case core.HealthStatusUnhealthy:
+			report.Unhealthy++
+		case core.HealthStatusRestricted:
+			report.Restricted++
+		default:
+			report.Unknown++
+		}
+		if result.Error != nil {
+			report.Failed++
+		}
+		report.Status = core.WorstStatus(report.Status, result.Status)
+	}
+	return report
+}
+
+// err returns the error a run of every check exits with: some checks are
+// unhealthy or failed to run
+func (r checkReport) err() error {
+	switch {
+	case r.Unhealthy > 0 && r.Failed > 0:
+		return fmt.Errorf("%d of %d checks unhealthy, %d failed to run", r.Unhealthy, len(r.Checks), r.Failed)
+	case r.Unhealthy > 0:
+		return fmt.Errorf("%d of %d checks unhealthy", r.Unhealthy, len(r.Checks))
+	case r.Failed > 0:
+		return fmt.Errorf("%d of %d checks failed to run", r.Failed, len(r.Checks))
+	}
+	return nil
+}
+
+// scopedPlugin adds the namespace to a plugin's configuration
+func scopedPlugin(plugin *plugins.ExecCheck, namespace string) *plugins.ExecCheck {
+	if namespace == "" {
+		return plugin
+	}
+	settings := map[string]interface{}{"namespace": namespace}
+	for key, value := range plugin.Config() {
+		if key != "namespace" {
+			settings[key] = value
+		}
+	}
+	_ = plugin.Configure(settings)
+	return plugin
+}
+
+// podSecurityConfig returns the pod-security check's configuration from the
+// monitoring.pod_security section
+func podSecurityConfig(cfg config.PodSecurityConfig, namespace string) map[string]interface{} {
+	settings := map[string]interface{}{}
+	if cfg.DefaultLevel != "" {
+		settings["default_level"] = cfg.DefaultLevel
+	}
+	if len(cfg.Namespaces) > 0 {
+		settings["namespace_levels"] = cfg.Namespaces
+	}
+	if namespace != "" {
+		settings["namespace"] = namespace
+	}
+	return settings
+}
+
+// workloadCheckConfig returns the configuration of a check that resolves
+// the owners of the workloads it looks at
+func workloadCheckConfig(namespace string, ownership core.OwnershipRules) map[string]interface{} {
+	settings := map[string]interface{}{"ownership": ownership}
+	if namespace != "" {
+		settings["namespace"] = namespace
+	}
+	return settings
+}
+
+// customResourceCheck builds the check for a configured custom resource kind
+func customResourceCheck(cfg config.CustomResourceConfig, dynamicClient dynamic.Interface) core.HealthCheck {
+	conditions := make([]health.CustomResourceCondition, 0, len(cfg.Conditions))
+	for _, c := range cfg.Conditions {
+		condition := health.CustomResourceCondition{
+			Type:     c.Type,
+			Expected: c.Status,
+			Status:   core.HealthStatus(c.Health),
+		}
+		if condition.Expected == "" {
+			condition.Expected = "True"
+		}
+		if condition.Status == "" {
+			condition.Status = core.HealthStatusUnhealthy
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return health.NewCustomResourceCheck(dynamicClient, health.CustomResourceSpec{
+		Name:       cfg.Name,
+		Resource:   schema.GroupVersionResource{Group: cfg.Group, Version: cfg.Version, Resource: cfg.PluralResource()},
+		Kind:       cfg.Kind,
+		Namespace:  cfg.Namespace,
+		Conditions: conditions,
+	})
+}
+
+// customMetricsCheck builds the check for a configured custom or external
+// metric
+func customMetricsCheck(cfg config.CustomMetricConfig) core.HealthCheck {
+	return health.NewCustomMetricsCheck(health.CustomMetricSpec{
+		Name:      cfg.Name,
+		API:       cfg.API,
+		Metric:    cfg.Metric,
+		Namespace: cfg.Namespace,
+		Resource:  cfg.Resource,
+		Object:    cfg.Object,
+		Selector:  cfg.Selector,
+		Operator:  cfg.Operator,
+		Threshold: cfg.Threshold,
+		Status:    core.HealthStatus(cfg.Status),
+	})
 }
 
 func runCheck(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
 	client := GetK8sClient()
 	if client == nil {
 		return fmt.Errorf("kubernetes client not initialized")
 	}
 
+	if len(args) == 0 {
+		return runAllChecks(cmd, printer, client)
+	}
+
 	checkName := args[0]
+	check, err := findCheck(checkName, namespace, GetDynamicClient())
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	var result interface{}
-	var err error
-
-	switch checkName {
-	case "pod-health":
-		check := health.NewPodHealthCheck()
-		if namespace != "" {
-			if err := check.Configure(map[string]interface{}{
-				"namespace": namespace,
-			}); err != nil {
-				return fmt.Errorf("failed to configure pod check: %w", err)
-			}
+	var result core.CheckResult
+	if checkRecordFile != "" {
+		var recording core.Recording
+		recording, result, err = checkRecorder.Run(ctx, check, client, contextName)
+		if saveErr := saveRecording(checkRecordFile, recording); saveErr != nil {
+			return saveErr
 		}
+		printer.Infof("Recorded %d API responses to %s", len(recording.Responses), checkRecordFile)
+	} else {
 		result, err = check.Check(ctx, client)
+	}
+	if err != nil {
+		return fmt.Errorf("check failed: %w", err)
+	}
 
-	case "node-health":
-		check := health.NewNodeHealthCheck()
-		result, err = check.Check(ctx, client)
+	err = printer.Print(result, func(w io.Writer) error {
+		return printCheckResult(printer, w, result)
+	})
+	if err != nil {
+		return err
+	}
+	if result.Status == core.HealthStatusUnhealthy {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("check %s is unhealthy", result.Name)
+	}
+	return nil
+}
 
-	default:
-		return fmt.Errorf("unknown check: %s", checkName)
+// runAllChecks runs every check once and fails when any is unhealthy or
+// fails to run
+func runAllChecks(cmd *cobra.Command, printer *output.Printer, client kubernetes.Interface) error {
+	if checkRecordFile != "" {
+		return fmt.Errorf("--record needs the name of the check to record")
 	}
+	checks, err := allChecks(namespace, GetDynamicClient())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	report := runChecks(ctx, client, checks)
 
+	err = printer.Print(report, func(w io.Writer) error {
+		return printCheckReport(printer, w, report)
+	})
 	if err != nil {
-		return fmt.Errorf("check failed: %w", err)
+		return err
 	}
+	if err := report.err(); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	return nil
+}
 
-	// Display result
-	fmt.Printf("Check: %s\n", checkName)
-	fmt.Printf("Result: %+v\n", result)
+// printCheckReport writes a table of every check's result and a summary
+func printCheckReport(p *output.Printer, w io.Writer, report checkReport) error {
+	table := output.NewTable("CHECK", "STATUS", "DURATION", "MESSAGE")
+	for _, result := range report.Checks {
+		message := result.Message
+		if result.Error != nil {
+			message += ": " + result.Error.Error()
+		}
+		table.AddRow(result.Name,
+			statusSymbol(p, result.Status)+" "+p.Colorize(statusColor(result.Status), string(result.Status)),
+			result.Duration.Round(time.Millisecond).String(), message)
+	}
+	if err := table.Render(w); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%s: %d healthy, %d degraded, %d unhealthy, %d restricted, %d unknown\n",
+		p.Colorize(statusColor(report.Status), string(report.Status)),
+		report.Healthy, report.Degraded, report.Unhealthy, report.Restricted, report.Unknown)
+	return err
+}
+
+// printCheckResult writes a check result with its details in key order
+func printCheckResult(p *output.Printer, w io.Writer, result core.CheckResult) error {
+	table := output.NewTable()
+	table.AddRow("Check:", result.Name)
+	table.AddRow("Status:", statusSymbol(p, result.Status)+" "+p.Colorize(statusColor(result.Status), string(result.Status)))
+	table.AddRow("Message:", result.Message)
+	if result.Error != nil {
+		table.AddRow("Error:", result.Error.Error())
+	}
+	table.AddRow("Duration:", result.Duration.String())
+
+	keys := make([]string, 0, len(result.Details))
+	for key := range result.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		table.AddRow(key+":", fmt.Sprintf("%v", result.Details[key]))
+	}
+	if len(result.Metrics) > 0 {
+		table.AddRow("Metrics:", fmt.Sprintf("%d collected", len(result.Metrics)))
+	}
+	return table.Render(w)
+}
 
+// saveRecording writes a recording as indented JSON
+func saveRecording(path string, recording core.Recording) error {
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
 	return nil
 }
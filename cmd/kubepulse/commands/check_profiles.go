@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/plugins"
+)
+
+// profileChecks returns the registered checks a check profile selects,
+// followed by the always checks it does not already include
+func profileChecks(registry *plugins.Registry, monitoring config.MonitoringConfig, profile string, always []string) ([]core.HealthCheck, error) {
+	names, err := monitoring.ProfileChecks(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(names)+len(always))
+	checks := make([]core.HealthCheck, 0, len(names)+len(always))
+	for _, name := range append(names, always...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		check, err := registry.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("check profile %s: %w", profile, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	"github.com/kubepulse/kubepulse/pkg/plugins"
+)
+
+func TestProfileChecks(t *testing.T) {
+	registry := plugins.NewRegistry()
+	for _, check := range []core.HealthCheck{health.NewNodeHealthCheck(), health.NewPodHealthCheck(), health.NewEventRateCheck()} {
+		if err := registry.Register(check); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	monitoring := config.MonitoringConfig{CheckProfiles: map[string][]string{
+		"edge":   {"pod-health", "node-health"},
+		"broken": {"node-health", "gpu-health"},
+	}}
+	tests := []struct {
+		profile string
+		always  []string
+		want    []string
+		err     string
+	}{
+		{config.CheckProfileMinimal, nil, []string{"node-health"}, ""},
+		{"edge", []string{"event-rates", "node-health"}, []string{"pod-health", "node-health", "event-rates"}, ""},
+		{"broken", nil, nil, "gpu-health"},
+		{"huge", nil, nil, "unknown check profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			checks, err := profileChecks(registry, monitoring, tt.profile, tt.always)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error mentioning %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names := make([]string, 0, len(checks))
+			for _, check := range checks {
+				names = append(names, check.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got checks %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuiltinCheck(t *testing.T) {
+	for _, name := range builtinCheckNames {
+		check, err := builtinCheck(name, "default", nil)
+		if err != nil {
+			t.Fatalf("builtinCheck(%q) error = %v", name, err)
+		}
+		if check.Name() != name {
+			t.Errorf("builtinCheck(%q) returned %s", name, check.Name())
+		}
+	}
+	if _, err := builtinCheck("kafka-clusters", "", nil); err == nil {
+		t.Error("expected an error for an unknown check")
+	}
+}
+
+func TestCustomResourceCheck(t *testing.T) {
+	check := customResourceCheck(config.CustomResourceConfig{
+		Name:    "kafka-clusters",
+		Group:   "kafka.strimzi.io",
+		Version: "v1beta2",
+		Kind:    "Kafka",
+		Conditions: []config.CustomResourceConditionConfig{
+			{Type: "Ready"},
+			{Type: "Degraded", Status: "False", Health: "degraded"},
+		},
+	}, nil)
+
+	if check.Name() != "kafka-clusters" {
+		t.Errorf("expected the configured name, got %s", check.Name())
+	}
+	if _, ok := check.(*health.CustomResourceCheck); !ok {
+		t.Fatalf("expected a custom resource check, got %T", check)
+	}
+	if want := "Monitors status conditions of Kafka resources (kafkas.kafka.strimzi.io/v1beta2)"; check.Description() != want {
+		t.Errorf("expected description %q, got %q", want, check.Description())
+	}
+	if check.Criticality() != core.CriticalityHigh {
+		t.Errorf("expected high criticality, got %s", check.Criticality())
+	}
+}
+
+// staticCheck always reports the same status, or fails to run with err
+type staticCheck struct {
+	name   string
+	status core.HealthStatus
+	err    error
+}
+
+func (c *staticCheck) Name() string                                  { return c.name }
+func (c *staticCheck) Description() string                           { return "static check" }
+func (c *staticCheck) Configure(config map[string]interface{}) error { return nil }
+func (c *staticCheck) Interval() time.Duration                       { return time.Second }
+func (c *staticCheck) Criticality() core.Criticality                 { return core.CriticalityLow }
+
+func (c *staticCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	return core.CheckResult{Name: c.name, Status: c.status, Timestamp: time.Now()}, c.err
+}
+
+func TestRunChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []core.HealthCheck
+		want   core.HealthStatus
+		counts [6]int // healthy, degraded, unhealthy, restricted, unknown, failed
+		err    string
+	}{
+		{"none", nil, core.HealthStatusHealthy, [6]int{}, ""},
+		{"healthy and degraded", []core.HealthCheck{
+			&staticCheck{name: "pod-health", status: core.HealthStatusHealthy},
+			&staticCheck{name: "node-health", status: core.HealthStatusDegraded},
+			&staticCheck{name: "pod-security", status: core.HealthStatusRestricted},
+		}, core.HealthStatusDegraded, [6]int{1, 1, 0, 1, 0, 0}, ""},
+		{"unhealthy and failed", []core.HealthCheck{
+			&staticCheck{name: "pod-health", status: core.HealthStatusUnhealthy},
+			&staticCheck{name: "ingress-health", err: errors.New("forbidden")},
+		}, core.HealthStatusUnhealthy, [6]int{0, 0, 1, 0, 1, 1}, "1 of 2 checks unhealthy, 1 failed to run"},
+		{"all failed", []core.HealthCheck{
+			&staticCheck{name: "pod-health", err: errors.New("forbidden")},
+			&staticCheck{name: "node-health", err: errors.New("connection refused")},
+		}, core.HealthStatusUnknown, [6]int{0, 0, 0, 0, 2, 2}, "2 of 2 checks failed to run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := runChecks(context.Background(), fake.NewSimpleClientset(), tt.checks)
+			if report.Status != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, report.Status)
+			}
+			counts := [6]int{report.Healthy, report.Degraded, report.Unhealthy, report.Restricted, report.Unknown, report.Failed}
+			if counts != tt.counts {
+				t.Errorf("expected counts %v, got %v", tt.counts, counts)
+			}
+			if err := report.err(); (err == nil) != (tt.err == "") || err != nil && err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+			for i, result := range report.Checks {
+				if result.Name != tt.checks[i].Name() {
+					t.Errorf("expected results in check order, got %s at %d", result.Name, i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var configShowResolved bool
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect KubePulse configuration and profiles",
+	Long: `Config inspects the configuration KubePulse runs with.
+
+Configuration is built from layers, later layers winning:
+  1. built-in defaults
+  2. top-level settings in the config file (--config, default $HOME/.kubepulse.yaml)
+  3. the selected profile, after the profiles it inherits from
+  4. KUBEPULSE_* environment variables
+  5. command-line flags
+
+The profile is selected by --profile, then KUBEPULSE_PROFILE, then the
+file's top-level profile key.`,
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the configuration for a profile",
+	Long: `Show prints the settings the config file defines for the selected profile:
+the top-level settings when no profile is selected, or the profile's own
+overrides otherwise.
+
+With --resolved it prints the configuration actually in effect after every
+layer is applied. Values that differ from the built-in defaults are annotated
+with the layer that set them.`,
+	Example: `  kubepulse config show --profile prod
+  kubepulse config show --profile prod --resolved`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+// configProfilesCmd represents the config profiles command
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List the profiles defined in the config file",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigProfiles,
+}
+
+// configTestCmd represents the config test command
+var configTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run the tests embedded in the configured alert rules",
+	Long: `Test evaluates every alert rule under alerts.rules against the sample check
+results in its tests and reports whether each rule fired as expected. It exits
+non-zero when a rule is invalid or a test fails; kubepulse serve refuses to
+start in the same case.`,
+	Example: `  kubepulse config test
+  kubepulse config test --profile prod -o json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigTest,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configProfilesCmd)
+	configCmd.AddCommand(configTestCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowResolved, "resolved", false, "Show the effective configuration after all layers")
+}
+
+// configPath returns the config file selected by --config, falling back to
+// the file found in the home directory
+func configPath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if path := viper.GetString("config"); path != "" {
+		return path
+	}
+	if path := viper.ConfigFileUsed(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// configOptions returns the config layers selected on the command line
+func configOptions(overrides ...config.Override) config.LoadOptions {
+	var global []config.Override
+	if flag := rootCmd.PersistentFlags().Lookup("kubeconfig"); flag != nil && flag.Changed {
+		global = append(global, config.Override{Key: "kubernetes.kubeconfig", Value: kubeconfig, Flag: "--kubeconfig"})
+	}
+	if flag := rootCmd.PersistentFlags().Lookup("context"); flag != nil && flag.Changed {
+		global = append(global, config.Override{Key: "kubernetes.context", Value: contextName, Flag: "--context"})
+	}
+	if flag := rootCmd.PersistentFlags().Lookup("read-only"); flag != nil && flag.Changed {
+		global = append(global, config.Override{Key: "read_only", Value: readOnly, Flag: "--read-only"})
+	}
+
+	return config.LoadOptions{
+		Path:      configPath(),
+		Profile:   profileName,
+		Overrides: append(global, overrides...),
+	}
+}
+
+// loadConfig loads the configuration for a command with flags as the last layer
+func loadConfig(overrides ...config.Override) (*config.Config, error) {
+	return config.LoadConfigWithOptions(configOptions(overrides...))
+}
+
+// readOnlyMode reports whether read-only mode is on, from --read-only,
+// KUBEPULSE_READ_ONLY or the config file
+func readOnlyMode() bool {
+	if cfg, err := loadConfig(); err == nil {
+		return cfg.ReadOnly
+	}
+	return readOnly
+}
+
+// aiClientConfig returns the AI client config of the ai section, reading
+// the API key from its environment variable
+func aiClientConfig(cfg *config.Config) ai.Config {
+	clientConfig := ai.Config{
+		Provider:   cfg.AI.Provider,
+		Model:      cfg.AI.Model,
+		ClaudePath: cfg.AI.ClaudePath,
+		BaseURL:    cfg.AI.BaseURL,
+		Timeout:    cfg.AI.Timeout,
+		MaxTokens:  cfg.AI.MaxTokens,
+		MaxTurns:   3,
+		ReadOnly:   cfg.ReadOnly,
+
+		TestMode:     cfg.AI.TestMode,
+		TestScenario: cfg.AI.TestScenario,
+	}
+	if env := cfg.AI.KeyEnv(); env != "" {
+		clientConfig.APIKey = os.Getenv(env)
+	}
+	return clientConfig
+}
+
+// clusterEndpoints returns the clusters of the config file reached without
+// a kubeconfig
+func clusterEndpoints() []k8s.ClusterEndpoint {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	endpoints := make([]k8s.ClusterEndpoint, 0, len(cfg.Kubernetes.Clusters))
+	for _, cluster := range cfg.Kubernetes.Clusters {
+		endpoint := k8s.ClusterEndpoint{
+			Name:      cluster.Name,
+			Server:    cluster.Server,
+			Namespace: cluster.Namespace,
+			Token:     cluster.Token,
+			TokenFile: cluster.TokenFile,
+			CAFile:    cluster.CAFile,
+			CAData:    cluster.CAData,
+			Insecure:  cluster.InsecureSkipTLSVerify,
+		}
+		if cluster.Exec != nil {
+			endpoint.Exec = &k8s.ExecAuth{
+				Command:    cluster.Exec.Command,
+				Args:       cluster.Exec.Args,
+				Env:        cluster.Exec.Env,
+				APIVersion: cluster.Exec.APIVersion,
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// newContextManager creates a context manager over the kubeconfig and the
+// clusters of the config file
+func newContextManager() (*k8s.ContextManager, error) {
+	return k8s.NewContextManager(viper.GetString("kubeconfig"), clusterEndpoints()...)
+}
+
+// currentContextName returns the kube context commands run against: the
+// --context flag, else the kubeconfig's current context, else "default"
+func currentContextName() string {
+	if contextName != "" {
+		return contextName
+	}
+	if contextManager, err := newContextManager(); err == nil {
+		if ctx, err := contextManager.GetCurrentContext(); err == nil && ctx.Name != "" {
+			return ctx.Name
+		}
+	}
+	return "default"
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	opts := configOptions()
+	out := cmd.OutOrStdout()
+
+	// Configuration is YAML already; JSON drops the comments annotating it
+	if configShowResolved {
+		resolved, err := config.Resolve(opts)
+		if err != nil {
+			return err
+		}
+		if printer.Format() == output.FormatJSON {
+			return writeConfigJSON(out, resolved.Config)
+		}
+		return printResolvedConfig(out, opts.Path, resolved)
+	}
+
+	if opts.Path == "" {
+		return fmt.Errorf("no config file found; pass --config or use --resolved to see the defaults in effect")
+	}
+
+	profile := opts.Profile
+	if profile == "" {
+		profile = os.Getenv(config.ProfileEnvVar)
+	}
+	if profile == "" {
+		data, err := os.ReadFile(opts.Path) // #nosec G304 - user-selected config file
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		if printer.Format() == output.FormatJSON {
+			var settings map[string]interface{}
+			if err := yaml.Unmarshal(data, &settings); err != nil {
+				return fmt.Errorf("failed to parse config file: %w", err)
+			}
+			return writeConfigJSON(out, settings)
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	settings, parent, err := config.ProfileOverrides(opts.Path, profile)
+	if err != nil {
+		return err
+	}
+	if printer.Format() == output.FormatJSON {
+		return writeConfigJSON(out, settings)
+	}
+	_, _ = fmt.Fprintf(out, "# profile %s from %s\n", profile, opts.Path)
+	if parent != "" {
+		_, _ = fmt.Fprintf(out, "# inherits: %s\n", parent)
+	}
+	if len(settings) == 0 {
+		_, _ = fmt.Fprintln(out, "# no overrides")
+		return nil
+	}
+	return writeYAML(out, settings)
+}
+
+func runConfigProfiles(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	path := configPath()
+	if path == "" {
+		return fmt.Errorf("no config file found; pass --config")
+	}
+	profiles, err := config.Profiles(path)
+	if err != nil {
+		return err
+	}
+	return printer.Print(profiles, func(w io.Writer) error {
+		for _, name := range profiles {
+			_, _ = fmt.Fprintln(w, name)
+		}
+		return nil
+	})
+}
+
+func runConfigTest(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	specs, results, err := configRules(cfg.Alerts.Rules)
+	if err != nil {
+		return err
+	}
+	if results == nil {
+		results = []alerts.RuleTestResult{}
+	}
+
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	if err := printer.Print(results, func(w io.Writer) error {
+		for _, result := range results {
+			status := "PASS"
+			if !result.Passed {
+				status = "FAIL"
+			}
+			_, _ = fmt.Fprintf(w, "%s  %s\n", status, result)
+		}
+		_, _ = fmt.Fprintf(w, "%d of %d tests passed across %d rules\n", passed, len(results), len(specs))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if passed < len(results) {
+		return fmt.Errorf("%d alert rule tests failed", len(results)-passed)
+	}
+	return nil
+}
+
+// printResolvedConfig writes the effective configuration with a header
+// describing the layers it was built from
+func printResolvedConfig(out io.Writer, path string, resolved *config.Resolved) error {
+	data, err := resolved.AnnotatedYAML()
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		path = "none"
+	}
+	_, _ = fmt.Fprintf(out, "# config file: %s\n", path)
+	if resolved.Profile != "" {
+		_, _ = fmt.Fprintf(out, "# profile: %s (applied: %s)\n", resolved.Profile, strings.Join(resolved.Chain, " -> "))
+	}
+	_, _ = fmt.Fprintln(out, "# precedence: default < file < profile < env < flag")
+	_, err = out.Write(data)
+	return err
+}
+
+// writeYAML writes a value as YAML with two-space indentation
+func writeYAML(out io.Writer, value interface{}) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeConfigJSON writes configuration as JSON using its YAML key names
+func writeConfigJSON(out io.Writer, value interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	return output.WriteJSON(out, settings)
+}
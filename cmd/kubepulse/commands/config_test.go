@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunConfigShow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubepulse.yaml")
+	data := "server:\n  port: 8080\nprofiles:\n  staging:\n    ui:\n      theme: dark\n  prod:\n    inherits: staging\n    server:\n      port: 9090\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	defer func() {
+		cfgFile, profileName, configShowResolved = "", "", false
+		configShowCmd.SetOut(nil)
+	}()
+	cfgFile, profileName = path, "prod"
+
+	tests := []struct {
+		name     string
+		resolved bool
+		want     []string
+	}{
+		{
+			name: "profile overrides",
+			want: []string{"# profile prod from " + path, "# inherits: staging", "port: 9090"},
+		},
+		{
+			name:     "resolved",
+			resolved: true,
+			want: []string{
+				"# profile: prod (applied: staging -> prod)",
+				"port: 9090 # profile:prod",
+				"theme: dark # profile:staging",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			configShowCmd.SetOut(&buf)
+			configShowResolved = tt.resolved
+
+			if err := runConfigShow(configShowCmd, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %q in output:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	profileName = "qa"
+	if err := runConfigShow(configShowCmd, nil); err == nil || !strings.Contains(err.Error(), "available: prod, staging") {
+		t.Errorf("expected unknown profile error listing profiles, got %v", err)
+	}
+}
+
+func TestRunConfigTest(t *testing.T) {
+	rule := "alerts:\n  rules:\n    pods-down:\n      check: pod-health\n      status: unhealthy\n      severity: critical\n      tests:\n        - name: unhealthy pods\n          result: {status: unhealthy}\n          fire: true\n"
+	tests := []struct {
+		name    string
+		extra   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "passing",
+			want: []string{"PASS  pods-down: unhealthy pods: fired, want fire", "1 of 1 tests passed across 1 rules"},
+		},
+		{
+			name:    "failing",
+			extra:   "        - name: degraded pods\n          result: {status: degraded}\n          fire: true\n",
+			want:    []string{"FAIL  pods-down: degraded pods: did not fire, want fire"},
+			wantErr: "1 alert rule tests failed",
+		},
+	}
+
+	defer func() {
+		cfgFile = ""
+		configTestCmd.SetOut(nil)
+	}()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "kubepulse.yaml")
+			if err := os.WriteFile(path, []byte(rule+tt.extra), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			cfgFile = path
+			var buf bytes.Buffer
+			configTestCmd.SetOut(&buf)
+
+			err := runConfigTest(configTestCmd, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %q in output:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
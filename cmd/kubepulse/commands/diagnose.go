@@ -2,11 +2,12 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/kubepulse/kubepulse/internal/output"
 	"github.com/kubepulse/kubepulse/pkg/ai"
 	"github.com/kubepulse/kubepulse/pkg/core"
 	"github.com/kubepulse/kubepulse/pkg/health"
@@ -19,19 +20,44 @@ var (
 	enableHealing    bool
 	diagOutputFormat string
 	confidenceMin    float64
+	diagnoseNoAI     bool
 )
 
+// diagnoseReport is the JSON and YAML output of the diagnose command
+type diagnoseReport struct {
+	Check     core.CheckResult     `json:"check"`
+	Diagnosis *ai.AnalysisResponse `json:"diagnosis,omitempty"`
+	Healing   *ai.AnalysisResponse `json:"healing,omitempty"`
+}
+
+// resourceDiagnoseReport is the JSON and YAML output of diagnosing a
+// resource
+type resourceDiagnoseReport struct {
+	Triage    core.Triage          `json:"triage"`
+	Diagnosis *ai.AnalysisResponse `json:"diagnosis,omitempty"`
+}
+
 // diagnoseCmd represents the diagnose command
 var diagnoseCmd = &cobra.Command{
-	Use:   "diagnose [check-name]",
-	Short: "AI-powered diagnostic analysis of health check failures",
+	Use:   "diagnose [check-name | pod/<name> | node/<name> | deployment/<name>]",
+	Short: "AI-powered diagnostic analysis of health check failures and resources",
 	Long: `Diagnose uses Claude Code CLI to perform intelligent analysis of health check failures.
 It provides detailed diagnostic insights, root cause analysis, and actionable recommendations.
 
+Given a pod, node or deployment instead of a check, diagnose triages that
+resource: it reads its state and events as kubectl describe shows them,
+names the most likely root cause with the evidence for it, and suggests
+kubectl commands to dig further, adding the AI analysis unless --no-ai is
+set. Namespaced resources are looked up in --namespace, or given as
+kind/namespace/name.
+
 Examples:
   kubepulse diagnose pod-health
   kubepulse diagnose --healing node-health
-  kubepulse diagnose --format json pod-health`,
+  kubepulse diagnose -o json pod-health
+  kubepulse diagnose pod/web-7d4b9-x2x -n shop
+  kubepulse diagnose deployment/shop/checkout --no-ai
+  kubepulse diagnose node/worker-3`,
 	RunE: runDiagnose,
 }
 
@@ -39,14 +65,23 @@ func init() {
 	rootCmd.AddCommand(diagnoseCmd)
 
 	diagnoseCmd.Flags().BoolVar(&enableHealing, "healing", false, "Include self-healing suggestions")
-	diagnoseCmd.Flags().StringVar(&diagOutputFormat, "format", "text", "Output format (text, json)")
+	diagnoseCmd.Flags().StringVar(&diagOutputFormat, "format", "", "Output format (table, json, yaml)")
+	_ = diagnoseCmd.Flags().MarkDeprecated("format", "use --output instead")
 	diagnoseCmd.Flags().Float64Var(&confidenceMin, "confidence", 0.5, "Minimum AI confidence level")
-	diagnoseCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to analyze (for pod checks)")
+	diagnoseCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to analyze (for pod checks and namespaced resources)")
+	diagnoseCmd.Flags().BoolVar(&diagnoseNoAI, "no-ai", false, "Skip the AI analysis and only report what the cluster shows, e.g. offline")
 }
 
 func runDiagnose(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("health check name required")
+		return fmt.Errorf("health check name or resource required")
+	}
+	if diagOutputFormat != "" {
+		outputFlag = diagOutputFormat
+	}
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
 	}
 
 	checkName := args[0]
@@ -55,12 +90,16 @@ func runDiagnose(cmd *cobra.Command, args []string) error {
 	if client == nil {
 		return fmt.Errorf("kubernetes client not initialized")
 	}
+	if strings.Contains(checkName, "/") {
+		return runDiagnoseResource(cmd, printer, client, checkName)
+	}
 
 	// Initialize AI client
-	aiConfig := ai.Config{
-		ClaudePath: "claude", // Assume claude is in PATH
-		MaxTurns:   3,
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	aiConfig := aiClientConfig(cfg)
 	aiClient := ai.NewClient(aiConfig)
 
 	// Create monitoring engine to get health check results
@@ -72,67 +111,198 @@ func runDiagnose(cmd *cobra.Command, args []string) error {
 	engine := core.NewEngine(engineConfig)
 
 	// Run the specific health check
-	fmt.Printf("🔍 Running health check: %s\n\n", checkName)
+	printer.Infof("%s Running health check: %s\n", printer.Symbol("🔍", ">"), checkName)
 
 	checkResult, err := runSingleHealthCheck(engine, client, checkName, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to run health check: %w", err)
 	}
+	report := diagnoseReport{Check: checkResult}
 
-	// Display health check result
-	displayHealthCheckResult(checkResult)
+	if diagnoseNoAI {
+		return printer.Print(report, func(w io.Writer) error {
+			displayHealthCheckResult(printer, w, checkResult)
+			return nil
+		})
+	}
 
 	// Only run AI analysis if there are issues
 	if checkResult.Status == core.HealthStatusHealthy {
-		fmt.Println("✅ Health check passed - no AI analysis needed")
-		return nil
+		return printer.Print(report, func(w io.Writer) error {
+			displayHealthCheckResult(printer, w, checkResult)
+			_, err := fmt.Fprintf(w, "%s Health check passed - no AI analysis needed\n", printer.Symbol("✅", "[ok]"))
+			return err
+		})
 	}
 
-	fmt.Printf("\n🤖 Running AI diagnostic analysis...\n")
+	printer.Infof("%s Running AI diagnostic analysis...", printer.Symbol("🤖", ">"))
 
 	// Build diagnostic context
-	diagnosticContext := buildDiagnosticContextFromCheck(checkResult)
+	diagnosticContext := buildDiagnosticContextFromCheck(checkResult, currentContextName())
+	diagnosticContext.DescribedResources = core.NewResourceDescriber(client, core.DefaultDescribeTTL).
+		Describe(cmd.Context(), core.ImplicatedResources(checkResult))
 
 	// Convert to AI types and run diagnostic analysis
 	aiCheckResult := convertCoreToAICheckResult(checkResult)
-	diagnosisResp, err := aiClient.AnalyzeDiagnostic(cmd.Context(), &aiCheckResult, diagnosticContext)
+	report.Diagnosis, err = aiClient.AnalyzeDiagnostic(cmd.Context(), &aiCheckResult, diagnosticContext)
 	if err != nil {
 		return fmt.Errorf("AI diagnostic analysis failed: %w", err)
 	}
 
 	// Check confidence threshold
-	if diagnosisResp.Confidence < confidenceMin {
-		fmt.Printf("⚠️  AI confidence (%.2f) below minimum threshold (%.2f)\n",
-			diagnosisResp.Confidence, confidenceMin)
-	}
-
-	// Display results based on output format
-	switch diagOutputFormat {
-	case "json":
-		return displayJSONOutput(diagnosisResp, nil)
-	default:
-		displayTextDiagnosis(diagnosisResp)
+	if report.Diagnosis.Confidence < confidenceMin {
+		printer.Infof("%s AI confidence (%.2f) below minimum threshold (%.2f)",
+			printer.Symbol("⚠️ ", "[warn]"), report.Diagnosis.Confidence, confidenceMin)
 	}
 
 	// Run healing analysis if requested and confidence is sufficient
-	if enableHealing && diagnosisResp.Confidence >= confidenceMin {
-		fmt.Printf("\n🩺 Generating self-healing recommendations...\n")
+	if enableHealing && report.Diagnosis.Confidence >= confidenceMin {
+		printer.Infof("%s Generating self-healing recommendations...", printer.Symbol("🩺", ">"))
 
-		healingResp, err := aiClient.AnalyzeHealing(cmd.Context(), &aiCheckResult, diagnosticContext)
+		report.Healing, err = aiClient.AnalyzeHealing(cmd.Context(), &aiCheckResult, diagnosticContext)
 		if err != nil {
+			// Don't fail the command for healing errors
 			klog.Errorf("AI healing analysis failed: %v", err)
-			return nil // Don't fail the command for healing errors
 		}
+	}
+
+	return printer.Print(report, func(w io.Writer) error {
+		displayHealthCheckResult(printer, w, checkResult)
+		displayTextDiagnosis(printer, w, report.Diagnosis)
+		if report.Healing != nil {
+			displayTextHealing(printer, w, report.Healing)
+		}
+		return nil
+	})
+}
+
+// runDiagnoseResource triages a pod, node or deployment and adds the AI
+// analysis of what it found
+func runDiagnoseResource(cmd *cobra.Command, printer *output.Printer, client kubernetes.Interface, target string) error {
+	ref, err := parseDiagnoseTarget(target, namespace)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	printer.Infof("%s Triaging %s\n", printer.Symbol("🔍", ">"), ref)
+	triage, err := core.TriageResource(ctx, client, ref)
+	if err != nil {
+		return err
+	}
+	report := resourceDiagnoseReport{Triage: triage}
+
+	if !diagnoseNoAI && triage.Status != core.HealthStatusHealthy {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		printer.Infof("%s Running AI diagnostic analysis...", printer.Symbol("🤖", ">"))
+		checkResult, diagnosticContext := triageDiagnosticRequest(triage, currentContextName())
+		report.Diagnosis, err = ai.NewClient(aiClientConfig(cfg)).AnalyzeDiagnostic(cmd.Context(), &checkResult, diagnosticContext)
+		if err != nil {
+			// The triage stands on its own without a provider
+			printer.Infof("%s AI diagnostic analysis failed, showing the cluster's evidence only: %v",
+				printer.Symbol("⚠️ ", "[warn]"), err)
+			report.Diagnosis = nil
+		}
+	}
+
+	return printer.Print(report, func(w io.Writer) error {
+		displayTriage(printer, w, report)
+		if report.Diagnosis != nil {
+			displayTextDiagnosis(printer, w, report.Diagnosis)
+		}
+		return nil
+	})
+}
+
+// parseDiagnoseTarget parses kind/name or kind/namespace/name, accepting
+// kubectl's short and plural kind names; namespaced resources default to
+// the given namespace, then default
+func parseDiagnoseTarget(target, namespace string) (core.ResourceRef, error) {
+	ref, ok := core.ParseResourceRef(target)
+	if !ok {
+		return core.ResourceRef{}, fmt.Errorf("invalid resource %q: use kind/name or kind/namespace/name", target)
+	}
+	switch strings.ToLower(ref.Kind) {
+	case "pod", "pods", "po":
+		ref.Kind = "pod"
+	case "deployment", "deployments", "deploy":
+		ref.Kind = "deployment"
+	case "node", "nodes", "no":
+		if ref.Namespace != "" {
+			return core.ResourceRef{}, fmt.Errorf("nodes aren't namespaced: use node/<name>")
+		}
+		return core.ResourceRef{Kind: "node", Name: ref.Name}, nil
+	default:
+		return core.ResourceRef{}, fmt.Errorf("can't diagnose %s resources; use pod, node or deployment", ref.Kind)
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = "default"
+	}
+	return ref, nil
+}
+
+// triageDiagnosticRequest returns the check result and context the AI
+// analyzes for a triaged resource in a cluster
+func triageDiagnosticRequest(triage core.Triage, clusterName string) (ai.CheckResult, ai.DiagnosticContext) {
+	checkResult := ai.CheckResult{
+		Name:      "diagnose:" + triage.Resource.String(),
+		Status:    ai.HealthStatus(triage.Status),
+		Message:   triage.RootCause,
+		Details:   map[string]interface{}{"evidence": triage.Evidence, "suggested_commands": triage.Commands},
+		Timestamp: time.Now(),
+	}
+	diagnosticContext := ai.DiagnosticContext{
+		ClusterName:        clusterName,
+		Namespace:          triage.Resource.Namespace,
+		ResourceType:       triage.Resource.Kind,
+		ResourceName:       triage.Resource.Name,
+		ErrorLogs:          triage.Evidence,
+		Events:             triage.Description.Events,
+		DescribedResources: []ai.ResourceDescription{triage.Description},
+	}
+	return checkResult, diagnosticContext
+}
 
-		switch diagOutputFormat {
-		case "json":
-			return displayJSONOutput(diagnosisResp, healingResp)
-		default:
-			displayTextHealing(healingResp)
+// displayTriage shows a resource's root cause, evidence and the commands
+// to run next, including those the AI suggests
+func displayTriage(p *output.Printer, w io.Writer, report resourceDiagnoseReport) {
+	triage := report.Triage
+	_, _ = fmt.Fprintf(w, "%s %s: %s\n", statusSymbol(p, triage.Status), triage.Resource,
+		p.Colorize(statusColor(triage.Status), string(triage.Status)))
+	_, _ = fmt.Fprintf(w, "   Root cause: %s\n", triage.RootCause)
+
+	if len(triage.Evidence) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s Evidence:\n", p.Symbol("🔎", "=="))
+		for _, evidence := range triage.Evidence {
+			_, _ = fmt.Fprintf(w, "  - %s\n", evidence)
 		}
 	}
 
-	return nil
+	commands := append([]string{}, triage.Commands...)
+	if report.Diagnosis != nil {
+		for _, action := range report.Diagnosis.Actions {
+			if action.Type == ai.ActionTypeKubectl && action.Command != "" {
+				commands = append(commands, action.Command)
+			}
+		}
+	}
+	if len(commands) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%s Suggested commands:\n", p.Symbol("💻", "=="))
+		seen := make(map[string]bool)
+		for _, command := range commands {
+			if !seen[command] {
+				seen[command] = true
+				_, _ = fmt.Fprintf(w, "  $ %s\n", command)
+			}
+		}
+	}
 }
 
 // runSingleHealthCheck executes a single health check
@@ -166,6 +336,42 @@ func runSingleHealthCheck(engine *core.Engine, client kubernetes.Interface, chec
 		}
 		check = serviceCheck
 
+	case "ingress-health":
+		ingressCheck := health.NewIngressHealthCheck(GetDynamicClient())
+		if namespace != "" {
+			if err := ingressCheck.Configure(map[string]interface{}{
+				"namespace": namespace,
+			}); err != nil {
+				return core.CheckResult{}, fmt.Errorf("failed to configure ingress check: %w", err)
+			}
+		}
+		check = ingressCheck
+
+	case "service-mesh":
+		meshCheck := health.NewServiceMeshHealthCheck(GetDynamicClient())
+		if namespace != "" {
+			if err := meshCheck.Configure(map[string]interface{}{
+				"namespace": namespace,
+			}); err != nil {
+				return core.CheckResult{}, fmt.Errorf("failed to configure service mesh check: %w", err)
+			}
+		}
+		check = meshCheck
+
+	case "pod-security":
+		podSecurityCheck := health.NewPodSecurityCheck()
+		if namespace != "" {
+			if err := podSecurityCheck.Configure(map[string]interface{}{
+				"namespace": namespace,
+			}); err != nil {
+				return core.CheckResult{}, fmt.Errorf("failed to configure pod security check: %w", err)
+			}
+		}
+		check = podSecurityCheck
+
+	case "node-versions":
+		check = health.NewNodeVersionCheck()
+
 	default:
 		return core.CheckResult{}, fmt.Errorf("unknown health check: %s", checkName)
 	}
@@ -183,7 +389,8 @@ func runSingleHealthCheck(engine *core.Engine, client kubernetes.Interface, chec
 }
 
 // buildDiagnosticContextFromCheck creates diagnostic context from check result
-func buildDiagnosticContextFromCheck(result core.CheckResult) ai.DiagnosticContext {
+// in a cluster
+func buildDiagnosticContextFromCheck(result core.CheckResult, clusterName string) ai.DiagnosticContext {
 	// Convert metrics to AI format
 	aiMetrics := make([]ai.Metric, len(result.Metrics))
 	for i, metric := range result.Metrics {
@@ -198,124 +405,95 @@ func buildDiagnosticContextFromCheck(result core.CheckResult) ai.DiagnosticConte
 	}
 
 	return ai.DiagnosticContext{
-		ClusterName:  "default",
+		ClusterName:  clusterName,
 		ResourceType: extractResourceType(result.Name),
 		ResourceName: extractResourceName(result.Name),
 		ErrorLogs:    extractErrorLogs(result),
+		LogPatterns:  extractLogPatterns(result),
 		Events:       extractEvents(result),
 		Metrics:      aiMetrics,
+
+		ExpectedDisruptions: core.ExpectedDisruptions(result),
 	}
 }
 
 // displayHealthCheckResult shows the health check result
-func displayHealthCheckResult(result core.CheckResult) {
-	statusIcon := getStatusIcon(result.Status)
-	fmt.Printf("%s %s: %s\n", statusIcon, result.Name, result.Status)
+func displayHealthCheckResult(p *output.Printer, w io.Writer, result core.CheckResult) {
+	_, _ = fmt.Fprintf(w, "%s %s: %s\n", statusSymbol(p, result.Status), result.Name,
+		p.Colorize(statusColor(result.Status), string(result.Status)))
 
 	if result.Message != "" {
-		fmt.Printf("   Message: %s\n", result.Message)
+		_, _ = fmt.Fprintf(w, "   Message: %s\n", result.Message)
 	}
 
 	if result.Error != nil {
-		fmt.Printf("   Error: %s\n", result.Error.Error())
+		_, _ = fmt.Fprintf(w, "   Error: %s\n", result.Error.Error())
 	}
 
 	if len(result.Metrics) > 0 {
-		fmt.Printf("   Metrics: %d collected\n", len(result.Metrics))
+		_, _ = fmt.Fprintf(w, "   Metrics: %d collected\n", len(result.Metrics))
 	}
 }
 
 // displayTextDiagnosis displays diagnostic analysis in text format
-func displayTextDiagnosis(response *ai.AnalysisResponse) {
-	fmt.Printf("\n📋 AI Diagnostic Analysis\n")
-	fmt.Printf("─────────────────────────────\n")
-	fmt.Printf("Summary: %s\n", response.Summary)
-	fmt.Printf("Confidence: %.2f (%.0f%%)\n", response.Confidence, response.Confidence*100)
-	fmt.Printf("Severity: %s\n", response.Severity)
+func displayTextDiagnosis(p *output.Printer, w io.Writer, response *ai.AnalysisResponse) {
+	_, _ = fmt.Fprintf(w, "\n%s AI Diagnostic Analysis\n", p.Symbol("📋", "=="))
+	_, _ = fmt.Fprintf(w, "%s\n", p.Symbol("─────────────────────────────", "-----------------------------"))
+	_, _ = fmt.Fprintf(w, "Summary: %s\n", response.Summary)
+	_, _ = fmt.Fprintf(w, "Confidence: %.2f (%.0f%%)\n", response.Confidence, response.Confidence*100)
+	_, _ = fmt.Fprintf(w, "Severity: %s\n", response.Severity)
 
 	if response.Diagnosis != "" {
-		fmt.Printf("\n📝 Detailed Diagnosis:\n%s\n", response.Diagnosis)
+		_, _ = fmt.Fprintf(w, "\n%s Detailed Diagnosis:\n%s\n", p.Symbol("📝", "=="), response.Diagnosis)
 	}
 
 	if len(response.Recommendations) > 0 {
-		fmt.Printf("\n💡 Recommendations:\n")
+		_, _ = fmt.Fprintf(w, "\n%s Recommendations:\n", p.Symbol("💡", "=="))
 		for i, rec := range response.Recommendations {
-			fmt.Printf("  %d. %s\n", i+1, rec.Title)
-			fmt.Printf("     %s\n", rec.Description)
+			_, _ = fmt.Fprintf(w, "  %d. %s\n", i+1, rec.Title)
+			_, _ = fmt.Fprintf(w, "     %s\n", rec.Description)
 			if rec.Impact != "" {
-				fmt.Printf("     Impact: %s | Effort: %s\n", rec.Impact, rec.Effort)
+				_, _ = fmt.Fprintf(w, "     Impact: %s | Effort: %s\n", rec.Impact, rec.Effort)
 			}
-			fmt.Println()
+			_, _ = fmt.Fprintln(w)
 		}
 	}
 }
 
 // displayTextHealing displays healing suggestions in text format
-func displayTextHealing(response *ai.AnalysisResponse) {
-	fmt.Printf("\n🩺 Self-Healing Suggestions\n")
-	fmt.Printf("──────────────────────────────\n")
-	fmt.Printf("Summary: %s\n", response.Summary)
+func displayTextHealing(p *output.Printer, w io.Writer, response *ai.AnalysisResponse) {
+	_, _ = fmt.Fprintf(w, "\n%s Self-Healing Suggestions\n", p.Symbol("🩺", "=="))
+	_, _ = fmt.Fprintf(w, "%s\n", p.Symbol("──────────────────────────────", "------------------------------"))
+	_, _ = fmt.Fprintf(w, "Summary: %s\n", response.Summary)
 
 	if len(response.Actions) > 0 {
-		fmt.Printf("\n🔧 Suggested Actions:\n")
+		_, _ = fmt.Fprintf(w, "\n%s Suggested Actions:\n", p.Symbol("🔧", "=="))
 		for i, action := range response.Actions {
-			fmt.Printf("  %d. %s (%s)\n", i+1, action.Title, action.Type)
-			fmt.Printf("     %s\n", action.Description)
+			_, _ = fmt.Fprintf(w, "  %d. %s (%s)\n", i+1, action.Title, action.Type)
+			_, _ = fmt.Fprintf(w, "     %s\n", action.Description)
 
 			if action.Command != "" {
-				fmt.Printf("     Command: %s\n", action.Command)
+				_, _ = fmt.Fprintf(w, "     Command: %s\n", action.Command)
 			}
 
 			if action.RequiresApproval {
-				fmt.Printf("     ⚠️  Requires manual approval\n")
+				_, _ = fmt.Fprintf(w, "     %s Requires manual approval\n", p.Symbol("⚠️ ", "[!]"))
 			} else if action.IsAutomatic {
-				fmt.Printf("     ✅ Can be automated\n")
+				_, _ = fmt.Fprintf(w, "     %s Can be automated\n", p.Symbol("✅", "[auto]"))
 			}
-			fmt.Println()
+			_, _ = fmt.Fprintln(w)
 		}
 	}
 
 	if len(response.Recommendations) > 0 {
-		fmt.Printf("\n📋 Additional Recommendations:\n")
+		_, _ = fmt.Fprintf(w, "\n%s Additional Recommendations:\n", p.Symbol("📋", "=="))
 		for i, rec := range response.Recommendations {
-			fmt.Printf("  %d. %s\n", i+1, rec.Title)
-			fmt.Printf("     %s\n", rec.Description)
+			_, _ = fmt.Fprintf(w, "  %d. %s\n", i+1, rec.Title)
+			_, _ = fmt.Fprintf(w, "     %s\n", rec.Description)
 		}
 	}
 }
 
-// displayJSONOutput displays results in JSON format
-func displayJSONOutput(diagnosis *ai.AnalysisResponse, healing *ai.AnalysisResponse) error {
-	output := map[string]interface{}{
-		"diagnosis": diagnosis,
-	}
-
-	if healing != nil {
-		output["healing"] = healing
-	}
-
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(output); err != nil {
-		return fmt.Errorf("failed to encode JSON output: %w", err)
-	}
-	return nil
-}
-
-// getStatusIcon returns an icon for the health status
-func getStatusIcon(status core.HealthStatus) string {
-	switch status {
-	case core.HealthStatusHealthy:
-		return "✅"
-	case core.HealthStatusDegraded:
-		return "⚠️"
-	case core.HealthStatusUnhealthy:
-		return "❌"
-	default:
-		return "❓"
-	}
-}
-
 // Helper functions (reuse from engine.go)
 func extractResourceType(checkName string) string {
 	if strings.Contains(strings.ToLower(checkName), "pod") {
@@ -349,6 +527,25 @@ func extractErrorLogs(result core.CheckResult) []string {
 	return logs
 }
 
+func extractLogPatterns(result core.CheckResult) []ai.LogPattern {
+	patterns, ok := result.Details["log_patterns"].([]core.LogPattern)
+	if !ok {
+		return nil
+	}
+
+	aiPatterns := make([]ai.LogPattern, len(patterns))
+	for i, pattern := range patterns {
+		aiPatterns[i] = ai.LogPattern{
+			Pattern: pattern.Pattern,
+			Kind:    pattern.Kind,
+			Count:   pattern.Count,
+			Example: pattern.Example,
+			Pods:    pattern.Pods,
+		}
+	}
+	return aiPatterns
+}
+
 func extractEvents(result core.CheckResult) []string {
 	events := []string{}
 	if result.Details != nil {
@@ -383,6 +580,10 @@ func convertCoreToAICheckResult(result core.CheckResult) ai.CheckResult {
 			Probability: pred.Probability,
 			Reason:      pred.Reason,
 		}
+		if pred.Explanation != nil {
+			explanation := ai.AnomalyExplanation(*pred.Explanation)
+			aiPredictions[i].Explanation = &explanation
+		}
 	}
 
 	return ai.CheckResult{
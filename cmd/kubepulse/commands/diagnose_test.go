@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestParseDiagnoseTarget(t *testing.T) {
+	tests := []struct {
+		target    string
+		namespace string
+		want      core.ResourceRef
+		wantErr   bool
+	}{
+		{"pod/web-1", "", core.ResourceRef{Kind: "pod", Namespace: "default", Name: "web-1"}, false},
+		{"po/web-1", "shop", core.ResourceRef{Kind: "pod", Namespace: "shop", Name: "web-1"}, false},
+		{"deploy/shop/checkout", "other", core.ResourceRef{Kind: "deployment", Namespace: "shop", Name: "checkout"}, false},
+		{"nodes/worker-3", "shop", core.ResourceRef{Kind: "node", Name: "worker-3"}, false},
+		{"node/kube-system/worker-3", "", core.ResourceRef{}, true},
+		{"service/web", "", core.ResourceRef{}, true},
+		{"pod/", "", core.ResourceRef{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDiagnoseTarget(tt.target, tt.namespace)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDiagnoseTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDiagnoseTarget(%q) = %+v, want %+v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestTriageDiagnosticRequest(t *testing.T) {
+	triage := core.Triage{
+		Resource:  core.ResourceRef{Kind: "pod", Namespace: "shop", Name: "web-1"},
+		Status:    core.HealthStatusUnhealthy,
+		RootCause: "Container web is killed for exceeding its memory limit",
+	}
+	result, diagnosticContext := triageDiagnosticRequest(triage, "prod-eu")
+	if diagnosticContext.ClusterName != "prod-eu" || diagnosticContext.Namespace != "shop" || diagnosticContext.ResourceName != "web-1" {
+		t.Errorf("expected the pod in the prod-eu cluster, got %+v", diagnosticContext)
+	}
+	if result.Name != "diagnose:pod/shop/web-1" || result.Message != triage.RootCause {
+		t.Errorf("unexpected check result %+v", result)
+	}
+}
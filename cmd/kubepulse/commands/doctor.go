@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the environment is ready to run KubePulse",
+	Long: `Doctor runs pre-flight checks and prints a fix for each problem found:
+- kubeconfig and API server access
+- RBAC permissions needed by each built-in health check
+- metrics-server availability
+- Claude CLI availability for AI features
+- availability of the server listen port
+
+It exits with an error when any check fails. A running server exposes the
+same checks at /api/v1/system/preflight.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().IntVarP(&port, "port", "p", 8080, "Port the server will listen on")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	var overrides []config.Override
+	if cmd.Flags().Changed("port") {
+		overrides = append(overrides, config.Override{Key: "server.port", Value: port, Flag: "--port"})
+	}
+	cfg, err := loadConfig(overrides...)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	aiConfig := aiClientConfig(cfg)
+	report := preflight.Run(ctx, preflight.Config{
+		Client:     GetK8sClient(),
+		ClientErr:  k8sErr,
+		AIEnabled:  true, // serve always enables AI features
+		ClaudePath: aiConfig.ClaudePath,
+		AIProvider: aiConfig.Provider,
+		AIEndpoint: aiConfig.Endpoint(),
+		ListenAddr: net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)),
+		Runbooks:   runbookLinks(cfg.Monitoring.Runbooks),
+	})
+
+	err = printer.Print(report, func(w io.Writer) error {
+		printPreflightReport(printer, w, report)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("pre-flight checks failed")
+	}
+	return nil
+}
+
+// printPreflightReport writes a human-readable report grouped by category
+func printPreflightReport(p *output.Printer, out io.Writer, report preflight.Report) {
+	icons := map[preflight.Status]string{
+		preflight.StatusPass: p.Colorize(output.Green, p.Symbol("✅", "[pass]")),
+		preflight.StatusWarn: p.Colorize(output.Yellow, p.Symbol("⚠️", "[warn]")),
+		preflight.StatusFail: p.Colorize(output.Red, p.Symbol("❌", "[fail]")),
+		preflight.StatusSkip: p.Symbol("⏭️", "[skip]"),
+	}
+	iconWidth := 0
+	for _, icon := range icons {
+		iconWidth = max(iconWidth, output.Width(icon))
+	}
+
+	counts := make(map[preflight.Status]int)
+	category := ""
+	for _, result := range report.Results {
+		if result.Category != category {
+			category = result.Category
+			_, _ = fmt.Fprintf(out, "\n%s\n", category)
+		}
+		counts[result.Status]++
+		_, _ = fmt.Fprintf(out, "  %s %s %s\n", output.PadRight(icons[result.Status], iconWidth), output.PadRight(result.Name, 32), result.Message)
+		if result.Fix != "" {
+			_, _ = fmt.Fprintf(out, "     fix: %s\n", result.Fix)
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[preflight.StatusPass], counts[preflight.StatusWarn],
+		counts[preflight.StatusFail], counts[preflight.StatusSkip])
+}
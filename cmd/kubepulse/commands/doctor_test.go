@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+)
+
+func TestPrintPreflightReport(t *testing.T) {
+	var buf bytes.Buffer
+	printPreflightReport(output.NewPrinter(&buf, io.Discard, output.Options{}), &buf, preflight.Report{Results: []preflight.Result{
+		{Name: "kubeconfig", Category: preflight.CategoryCluster, Status: preflight.StatusPass, Message: "Connected"},
+		{Name: "event-rates: list events", Category: preflight.CategoryRBAC, Status: preflight.StatusFail,
+			Message: "Denied; event-rates will fail", Fix: `Grant "list" on "events"`},
+		{Name: "ai-provider", Category: preflight.CategoryAI, Status: preflight.StatusSkip, Message: "AI features are disabled"},
+	}})
+
+	out := buf.String()
+	for _, want := range []string{
+		"\ncluster\n", "\nrbac\n",
+		"event-rates: list events",
+		`fix: Grant "list" on "events"`,
+		"1 passed, 0 warnings, 1 failed, 1 skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output %q", want, out)
+		}
+	}
+}
+
+func TestRunDoctor_InvalidOutput(t *testing.T) {
+	defer func() { outputFlag = "table" }()
+	outputFlag = "xml"
+	if err := runDoctor(doctorCmd, nil); err == nil {
+		t.Error("expected error for unsupported output format")
+	}
+}
@@ -0,0 +1,318 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	handoffSince    string
+	handoffServer   string
+	handoffCluster  string
+	handoffMarkdown bool
+	handoffSlack    string
+)
+
+// handoffCmd represents the handoff command
+var handoffCmd = &cobra.Command{
+	Use:   "handoff",
+	Short: "Summarize the on-call shift for the next engineer",
+	Long: `Handoff asks a running "kubepulse serve" what happened during the shift:
+checks failing now, alerts fired and resolved, SLO burn, remediations
+executed, AI analyses completed, and what still needs follow-up, such as
+unacknowledged alerts, failed remediations, violated SLOs, stuck checks,
+undelivered notifications and critical or high findings opened during the
+shift.
+
+The summary is shown in the terminal, as Markdown with --markdown, or posted
+to a Slack alert channel from the config file with --slack.`,
+	Example: `  kubepulse handoff --since 8h
+  kubepulse handoff --since 12h --markdown > handoff.md
+  kubepulse handoff --slack oncall-slack`,
+	Args: cobra.NoArgs,
+	RunE: runHandoff,
+}
+
+func init() {
+	rootCmd.AddCommand(handoffCmd)
+
+	handoffCmd.Flags().StringVar(&handoffSince, "since", "8h", "Start of the shift: a duration or RFC 3339 time")
+	handoffCmd.Flags().StringVar(&handoffServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+	handoffCmd.Flags().StringVar(&handoffCluster, "cluster", "", "Cluster name to report (defaults to the server's current context)")
+	handoffCmd.Flags().BoolVar(&handoffMarkdown, "markdown", false, "Render the summary as Markdown")
+	handoffCmd.Flags().StringVar(&handoffSlack, "slack", "", "Post the summary to this Slack channel from alerts.channels")
+}
+
+func runHandoff(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	var slack *alerts.SlackChannel
+	if handoffSlack != "" {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		if slack, err = handoffSlackChannel(cfg.Alerts, handoffSlack); err != nil {
+			return err
+		}
+	}
+
+	apiClient, err := client.NewClient(client.Config{BaseURL: handoffServer})
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	handoff, err := apiClient.Handoff(ctx, handoffCluster, handoffSince)
+	if err != nil {
+		return fmt.Errorf("failed to get handoff summary: %w", err)
+	}
+
+	if slack != nil {
+		if err := slack.Post(ctx, handoffText(*handoff, slackHandoffStyle)); err != nil {
+			return fmt.Errorf("failed to post handoff to Slack: %w", err)
+		}
+		printer.Infof("Posted handoff for %s to Slack channel %s", handoff.ClusterName, handoffSlack)
+		return nil
+	}
+	return printer.Print(handoff, func(w io.Writer) error {
+		if handoffMarkdown {
+			_, err := io.WriteString(w, handoffText(*handoff, markdownHandoffStyle))
+			return err
+		}
+		return displayHandoff(printer, w, *handoff)
+	})
+}
+
+// handoffSlackChannel returns the configured Slack alert channel a handoff
+// is posted to
+func handoffSlackChannel(cfg config.AlertsConfig, name string) (*alerts.SlackChannel, error) {
+	channel, ok := cfg.Channels[name]
+	if !ok {
+		return nil, fmt.Errorf("no alert channel %q in alerts.channels", name)
+	}
+	if channel.Type != "slack" {
+		return nil, fmt.Errorf("alert channel %s is a %s channel, not slack", name, channel.Type)
+	}
+	webhook, _ := channel.Settings["webhook"].(string)
+	if webhook == "" {
+		return nil, fmt.Errorf("slack channel %s needs a webhook setting", name)
+	}
+	return alerts.NewSlackChannel(name, webhook), nil
+}
+
+// handoffStyle is the markup a text handoff is written in
+type handoffStyle struct {
+	heading func(text string) string
+	bold    func(text string) string
+	bullet  string
+}
+
+var (
+	markdownHandoffStyle = handoffStyle{
+		heading: func(text string) string { return "## " + text },
+		bold:    func(text string) string { return "**" + text + "**" },
+		bullet:  "- ",
+	}
+	// Slack mrkdwn has no headings and marks bold with single asterisks
+	slackHandoffStyle = handoffStyle{
+		heading: func(text string) string { return "*" + text + "*" },
+		bold:    func(text string) string { return "*" + text + "*" },
+		bullet:  "• ",
+	}
+)
+
+// handoffText renders a handoff as Markdown or Slack mrkdwn
+func handoffText(h core.Handoff, style handoffStyle) string {
+	var b strings.Builder
+	section := func(title string, items []string) {
+		fmt.Fprintf(&b, "\n%s\n", style.heading(title))
+		if len(items) == 0 {
+			b.WriteString("None\n")
+			return
+		}
+		for _, item := range items {
+			b.WriteString(style.bullet + item + "\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "%s\n", style.bold(fmt.Sprintf("Handoff for %s, %s", h.ClusterName, handoffWindow(h))))
+	fmt.Fprintf(&b, "Status %s, health score %.0f\n", h.Status, h.Score)
+
+	incidents := make([]string, 0, len(h.Incidents))
+	for _, incident := range h.Incidents {
+		line := fmt.Sprintf("%s %s (%s)%s: %s", style.bold(incident.Check), incident.Status, incident.Severity, failingFor(incident, h.Until), incident.Message)
+		if incident.Diagnosis != "" {
+			line += " — AI: " + incident.Diagnosis
+		}
+		incidents = append(incidents, line)
+	}
+	section(fmt.Sprintf("Active incidents (%d)", len(h.Incidents)), incidents)
+
+	alertLines := make([]string, 0, len(h.AlertsFired)+len(h.AlertsResolved))
+	for _, change := range h.AlertsFired {
+		alertLines = append(alertLines, fmt.Sprintf("%s fired %s (%s): %s", handoffTime(change.Timestamp), change.Resource, change.To, change.Message))
+	}
+	for _, change := range h.AlertsResolved {
+		alertLines = append(alertLines, fmt.Sprintf("%s resolved %s", handoffTime(change.Timestamp), change.Resource))
+	}
+	section(fmt.Sprintf("Alerts (%d fired, %d resolved)", len(h.AlertsFired), len(h.AlertsResolved)), alertLines)
+
+	slos := make([]string, 0, len(h.SLOs))
+	for _, status := range h.SLOs {
+		line := fmt.Sprintf("%s at %.2f (target %.2f), burn rate %.1fx, %.0f%% budget left", status.SLO.Name, status.CurrentValue, status.SLO.Target, status.BurnRate, status.ErrorBudget)
+		if status.IsViolated {
+			line += ", " + style.bold("violated")
+		}
+		slos = append(slos, line)
+	}
+	section("SLO burn", slos)
+
+	remediations := make([]string, 0, len(h.Remediations))
+	for _, change := range h.Remediations {
+		remediations = append(remediations, fmt.Sprintf("%s %s: %s (%s)", handoffTime(change.Timestamp), change.Resource, change.Message, remediationOutcome(change)))
+	}
+	section(fmt.Sprintf("Remediations (%d)", len(h.Remediations)), remediations)
+
+	insights := make([]string, 0, len(h.Insights))
+	for _, insight := range h.Insights {
+		insights = append(insights, fmt.Sprintf("%s %s: %s", handoffTime(insight.AnalyzedAt), insightSubject(insight), insight.Summary))
+	}
+	section(fmt.Sprintf("AI insights (%d)", len(h.Insights)), insights)
+
+	followUps := make([]string, 0, len(h.FollowUps))
+	for _, followUp := range h.FollowUps {
+		followUps = append(followUps, fmt.Sprintf("%s %s", style.bold(followUp.Subject), followUp.Message))
+	}
+	section(fmt.Sprintf("Needs follow-up (%d)", len(h.FollowUps)), followUps)
+	return b.String()
+}
+
+// displayHandoff renders a handoff for the terminal
+func displayHandoff(p *output.Printer, w io.Writer, h core.Handoff) error {
+	if _, err := fmt.Fprintf(w, "Handoff for %s, %s\n%s %s, health score %.0f\n", h.ClusterName, handoffWindow(h),
+		statusSymbol(p, h.Status), p.Colorize(statusColor(h.Status), string(h.Status)), h.Score); err != nil {
+		return err
+	}
+
+	incidents := output.NewTable("CHECK", "STATUS", "SEVERITY", "FAILING FOR", "MESSAGE")
+	for _, incident := range h.Incidents {
+		incidents.AddRow(incident.Check, p.Colorize(statusColor(incident.Status), string(incident.Status)),
+			p.Colorize(severityColor(incident.Severity), string(incident.Severity)),
+			strings.TrimPrefix(failingFor(incident, h.Until), " for "), incident.Message)
+	}
+	alertTable := output.NewTable("TIME", "EVENT", "ALERT", "MESSAGE")
+	for _, change := range h.AlertsFired {
+		alertTable.AddRow(handoffTime(change.Timestamp), p.Colorize(severityColor(core.AlertSeverity(change.To)), "fired "+change.To), change.Resource, change.Message)
+	}
+	for _, change := range h.AlertsResolved {
+		alertTable.AddRow(handoffTime(change.Timestamp), p.Colorize(output.Green, "resolved"), change.Resource, change.Message)
+	}
+	slos := output.NewTable("SLO", "CURRENT", "TARGET", "BURN RATE", "BUDGET LEFT")
+	for _, status := range h.SLOs {
+		name := status.SLO.Name
+		if status.IsViolated {
+			name = p.Colorize(output.Red, name+" (violated)")
+		}
+		slos.AddRow(name, fmt.Sprintf("%.2f", status.CurrentValue), fmt.Sprintf("%.2f", status.SLO.Target),
+			fmt.Sprintf("%.1fx", status.BurnRate), fmt.Sprintf("%.0f%%", status.ErrorBudget))
+	}
+	remediations := output.NewTable("TIME", "REMEDIATION", "OUTCOME", "ACTION")
+	for _, change := range h.Remediations {
+		outcome := remediationOutcome(change)
+		color := output.Green
+		if outcome == "failed" {
+			color = output.Red
+		}
+		remediations.AddRow(handoffTime(change.Timestamp), change.Resource, p.Colorize(color, outcome), change.Message)
+	}
+	insights := output.NewTable("TIME", "SUBJECT", "SUMMARY")
+	for _, insight := range h.Insights {
+		insights.AddRow(handoffTime(insight.AnalyzedAt), insightSubject(insight), insight.Summary)
+	}
+	followUps := output.NewTable("KIND", "SUBJECT", "DETAILS")
+	for _, followUp := range h.FollowUps {
+		followUps.AddRow(p.Colorize(output.Yellow, followUp.Kind), followUp.Subject, followUp.Message)
+	}
+
+	sections := []struct {
+		title string
+		table *output.Table
+	}{
+		{fmt.Sprintf("Active incidents (%d)", len(h.Incidents)), incidents},
+		{fmt.Sprintf("Alerts (%d fired, %d resolved)", len(h.AlertsFired), len(h.AlertsResolved)), alertTable},
+		{"SLO burn", slos},
+		{fmt.Sprintf("Remediations (%d)", len(h.Remediations)), remediations},
+		{fmt.Sprintf("AI insights (%d)", len(h.Insights)), insights},
+		{fmt.Sprintf("Needs follow-up (%d)", len(h.FollowUps)), followUps},
+	}
+	for _, section := range sections {
+		if _, err := fmt.Fprintf(w, "\n%s\n", p.Colorize(output.Blue, section.title)); err != nil {
+			return err
+		}
+		if section.table.Len() == 0 {
+			if _, err := fmt.Fprintln(w, "None"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := section.table.Render(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handoffWindow describes the shift a handoff covers, e.g.
+// "Oct 17 06:00 to 14:00 (8h)"
+func handoffWindow(h core.Handoff) string {
+	until := h.Until.Local().Format("15:04")
+	if h.Until.Local().YearDay() != h.Since.Local().YearDay() {
+		until = handoffTime(h.Until)
+	}
+	return fmt.Sprintf("%s to %s (%s)", handoffTime(h.Since), until, h.Until.Sub(h.Since).Round(time.Minute))
+}
+
+// handoffTime formats a time in a handoff in local time
+func handoffTime(t time.Time) string {
+	return t.Local().Format("Jan 2 15:04")
+}
+
+// failingFor describes how long an incident has been failing at the end of
+// the shift, e.g. " for 2h13m0s", or nothing when unknown
+func failingFor(incident core.Incident, until time.Time) string {
+	if incident.FailingSince.IsZero() {
+		return ""
+	}
+	return " for " + until.Sub(incident.FailingSince).Round(time.Minute).String()
+}
+
+// remediationOutcome reports whether an executed remediation succeeded
+func remediationOutcome(change core.Change) string {
+	if success, ok := change.Details["success"].(bool); ok && !success {
+		return "failed"
+	}
+	return "succeeded"
+}
+
+// insightSubject names what an AI insight analyzed
+func insightSubject(insight core.HandoffInsight) string {
+	if insight.Check == "" {
+		return "cluster"
+	}
+	return insight.Check
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestHandoffText(t *testing.T) {
+	now := time.Now()
+	handoff := core.Handoff{
+		ClusterName: "prod",
+		Since:       now.Add(-8 * time.Hour),
+		Until:       now,
+		Status:      core.HealthStatusDegraded,
+		Score:       82,
+		Incidents: []core.Incident{{Check: "pod-health", Status: core.HealthStatusUnhealthy, Severity: core.AlertSeverityCritical,
+			Message: "checkout crashlooping", FailingSince: now.Add(-90 * time.Minute), Diagnosis: "Missing DATABASE_URL"}},
+		AlertsFired: []core.Change{{Timestamp: now.Add(-90 * time.Minute), Resource: "check/pod-health", To: "critical", Message: "checkout crashlooping"}},
+		FollowUps:   []core.FollowUp{{Kind: core.FollowUpFailedRemediation, Subject: "remediation/restart-checkout", Message: "Restart checkout failed"}},
+	}
+
+	tests := []struct {
+		name  string
+		style handoffStyle
+		want  []string
+	}{
+		{"markdown", markdownHandoffStyle, []string{"**Handoff for prod", "## Active incidents (1)", "- **pod-health** unhealthy (critical)",
+			"AI: Missing DATABASE_URL", "## Alerts (1 fired, 0 resolved)", "## Remediations (0)\nNone", "## Needs follow-up (1)"}},
+		{"slack", slackHandoffStyle, []string{"*Handoff for prod", "*Active incidents (1)*", "• *pod-health*", "*SLO burn*\nNone",
+			"• *remediation/restart-checkout* Restart checkout failed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := handoffText(handoff, tt.style)
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandoffSlackChannel(t *testing.T) {
+	cfg := config.AlertsConfig{Channels: map[string]config.ChannelConfig{
+		"oncall":  {Type: "slack", Settings: map[string]interface{}{"webhook": "https://hooks.slack.com/services/T/B/X"}},
+		"pager":   {Type: "pagerduty", Settings: map[string]interface{}{"routing_key": "key"}},
+		"nowhere": {Type: "slack"},
+	}}
+
+	tests := []struct {
+		name    string
+		channel string
+		wantErr string
+	}{
+		{"slack channel", "oncall", ""},
+		{"unknown channel", "ops", "no alert channel"},
+		{"other type", "pager", "not slack"},
+		{"missing webhook", "nowhere", "needs a webhook"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, err := handoffSlackChannel(cfg, tt.channel)
+			if tt.wantErr == "" {
+				if err != nil || channel == nil {
+					t.Errorf("expected a channel, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	healthAt      string
+	healthServer  string
+	healthCluster string
+)
+
+// healthCmd represents the health command
+var healthCmd = &cobra.Command{
+	Use:   "health --at <time>",
+	Short: "Show cluster health as it was at a past moment",
+	Long: `Health asks a running "kubepulse serve" for the cluster health and check
+results as they were at the given time, for investigating past incidents.
+
+The time is RFC 3339 (2024-06-01T14:00:00Z) or a local date and time
+(2024-06-01T14:00). The server keeps history for monitoring.history_retention,
+7 days by default.`,
+	Example: `  kubepulse health --at "2024-06-01T14:00"
+  kubepulse health --at 2024-06-01T12:00:00Z --server http://kubepulse.internal:8080 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runHealth,
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+
+	healthCmd.Flags().StringVar(&healthAt, "at", "", "Time to inspect (required)")
+	healthCmd.Flags().StringVar(&healthServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+	healthCmd.Flags().StringVar(&healthCluster, "cluster", "", "Cluster name to report (defaults to the server's current context)")
+	_ = healthCmd.MarkFlagRequired("at")
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	at, err := core.ParseHistoryTime(healthAt, time.Local)
+	if err != nil {
+		return err
+	}
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewClient(client.Config{BaseURL: healthServer})
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	health, err := apiClient.HealthAt(ctx, healthCluster, at)
+	if err != nil {
+		return fmt.Errorf("failed to get health at %s: %w", at.Format(time.RFC3339), err)
+	}
+
+	return printer.Print(health, func(w io.Writer) error {
+		return displaySummary(printer, w, *health)
+	})
+}
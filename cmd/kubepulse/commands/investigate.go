@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	investigateServer string
+	investigateRun    bool
+	investigateCheck  string
+)
+
+// investigateCmd represents the investigate command
+var investigateCmd = &cobra.Command{
+	Use:   "investigate",
+	Short: "Plan and run kubectl investigations of failing checks",
+	Long: `Investigate works with the investigation plans of a running "kubepulse serve".
+
+A plan is an ordered list of kubectl commands for looking into a failing
+check: the follow-up commands the AI suggested, then the steps of the built-in
+templates matching the failure (crashloop, oom, image-pull, scheduling, node,
+service, admission). Placeholders are resolved to the pods, nodes, services
+and workloads the check implicates. The server saves a plan whenever the AI
+suggests follow-up commands.
+
+Running a plan executes only its read-only steps (get, describe, logs, top,
+events, rollout status) through the server's validated kubectl executor and
+saves their output with the plan. Other steps are shown for a person to run.`,
+}
+
+// investigatePlanCmd represents the investigate plan command
+var investigatePlanCmd = &cobra.Command{
+	Use:   "plan <check>",
+	Short: "Build and save an investigation plan for a check",
+	Example: `  kubepulse investigate plan pod-health
+  kubepulse investigate plan pod-health --run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInvestigatePlan,
+}
+
+// investigateRunCmd represents the investigate run command
+var investigateRunCmd = &cobra.Command{
+	Use:     "run <plan-id>",
+	Short:   "Run the read-only steps of a saved plan and show their output",
+	Example: `  kubepulse investigate run investigation-3 -o json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runInvestigateRun,
+}
+
+// investigateListCmd represents the investigate list command
+var investigateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved investigation plans",
+	Args:  cobra.NoArgs,
+	RunE:  runInvestigateList,
+}
+
+func init() {
+	rootCmd.AddCommand(investigateCmd)
+	investigateCmd.AddCommand(investigatePlanCmd)
+	investigateCmd.AddCommand(investigateRunCmd)
+	investigateCmd.AddCommand(investigateListCmd)
+
+	investigateCmd.PersistentFlags().StringVar(&investigateServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+	investigatePlanCmd.Flags().BoolVar(&investigateRun, "run", false, "Run the plan's read-only steps right away")
+	investigateListCmd.Flags().StringVar(&investigateCheck, "check", "", "Only list plans for this check")
+}
+
+// investigationClient creates an API client whose requests allow for every
+// step of a plan to run
+func investigationClient() (*client.Client, error) {
+	apiClient, err := client.NewClient(client.Config{BaseURL: investigateServer, Timeout: 5 * time.Minute})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return apiClient, nil
+}
+
+func runInvestigatePlan(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := investigationClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := apiClient.PlanInvestigation(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to plan investigation of %s: %w", args[0], err)
+	}
+	if investigateRun {
+		printer.Infof("Running read-only steps of %s...\n", plan.ID)
+		if plan, err = apiClient.RunInvestigation(context.Background(), plan.ID); err != nil {
+			return fmt.Errorf("failed to run investigation: %w", err)
+		}
+	}
+	return printer.Print(plan, func(w io.Writer) error {
+		return displayInvestigation(printer, w, *plan)
+	})
+}
+
+func runInvestigateRun(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := investigationClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := apiClient.RunInvestigation(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to run investigation %s: %w", args[0], err)
+	}
+	return printer.Print(plan, func(w io.Writer) error {
+		return displayInvestigation(printer, w, *plan)
+	})
+}
+
+func runInvestigateList(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := investigationClient()
+	if err != nil {
+		return err
+	}
+
+	plans, err := apiClient.Investigations(context.Background(), investigateCheck)
+	if err != nil {
+		return fmt.Errorf("failed to list investigations: %w", err)
+	}
+	return printer.Print(plans, func(w io.Writer) error {
+		if len(plans) == 0 {
+			_, err := fmt.Fprintln(w, "No investigation plans")
+			return err
+		}
+		table := output.NewTable("ID", "CHECK", "STEPS", "RUNNABLE", "CREATED", "LAST RUN")
+		for _, plan := range plans {
+			runnable := 0
+			for _, step := range plan.Steps {
+				if step.Runnable() {
+					runnable++
+				}
+			}
+			lastRun := "never"
+			if !plan.LastRun.IsZero() {
+				lastRun = plan.LastRun.Local().Format(time.DateTime)
+			}
+			table.AddRow(plan.ID, plan.Check, strconv.Itoa(len(plan.Steps)), strconv.Itoa(runnable),
+				plan.CreatedAt.Local().Format(time.DateTime), lastRun)
+		}
+		return table.Render(w)
+	})
+}
+
+// stepState describes whether a step ran, can run, or is left to a person
+func stepState(step ai.InvestigationStep) (string, output.Color) {
+	switch {
+	case !step.RanAt.IsZero() && step.Error != "":
+		return "failed", output.Red
+	case !step.RanAt.IsZero():
+		return "ran", output.Green
+	case step.Error != "":
+		return "not run", output.Yellow
+	case len(step.Unresolved) > 0:
+		return "needs " + strings.Join(step.Unresolved, ","), output.Yellow
+	case !step.ReadOnly:
+		return "manual", output.Yellow
+	}
+	return "read-only", output.Blue
+}
+
+// displayInvestigation prints a plan's steps, then the output of the steps
+// that ran
+func displayInvestigation(p *output.Printer, w io.Writer, plan ai.InvestigationPlan) error {
+	header := fmt.Sprintf("Investigation %s for %s", plan.ID, plan.Check)
+	if len(plan.Templates) > 0 {
+		header += " (" + strings.Join(plan.Templates, ", ") + ")"
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return err
+	}
+	if plan.Summary != "" {
+		if _, err := fmt.Fprintln(w, plan.Summary); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+
+	table := output.NewTable("#", "STATE", "SOURCE", "COMMAND")
+	for i, step := range plan.Steps {
+		state, color := stepState(step)
+		table.AddRow(strconv.Itoa(i+1), p.Colorize(color, state), step.Source, step.Command)
+	}
+	if err := table.Render(w); err != nil {
+		return err
+	}
+
+	for i, step := range plan.Steps {
+		if step.RanAt.IsZero() {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\n%s %d. %s\n", p.Symbol("▸", ">"), i+1, step.Command); err != nil {
+			return err
+		}
+		text := strings.TrimRight(step.Output, "\n")
+		if step.Error != "" && text == "" {
+			text = p.Colorize(output.Red, step.Error)
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,11 +3,14 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
 	"github.com/kubepulse/kubepulse/pkg/core"
 	"github.com/kubepulse/kubepulse/pkg/health"
 	"github.com/kubepulse/kubepulse/pkg/plugins"
@@ -17,10 +20,10 @@ import (
 
 var (
 	interval      time.Duration
-	outputFormat  string
 	watch         bool
 	namespace     string
 	enabledChecks []string
+	checkProfile  string
 )
 
 // monitorCmd represents the monitor command
@@ -37,13 +40,18 @@ func init() {
 	rootCmd.AddCommand(monitorCmd)
 
 	monitorCmd.Flags().DurationVarP(&interval, "interval", "i", 30*time.Second, "Check interval")
-	monitorCmd.Flags().StringVarP(&outputFormat, "output", "o", "summary", "Output format (summary, json, yaml)")
 	monitorCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch mode - continuous monitoring")
 	monitorCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to monitor (empty for all)")
 	monitorCmd.Flags().StringSliceVar(&enabledChecks, "checks", []string{"pod-health", "node-health"}, "Enabled health checks")
+	monitorCmd.Flags().StringVar(&checkProfile, "check-profile", "", "Run a built-in check profile (minimal, standard, deep) instead of --checks")
 }
 
 func runMonitor(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
 	client := GetK8sClient()
 	if client == nil {
 		return fmt.Errorf("kubernetes client not initialized")
@@ -83,7 +91,74 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to register node check: %w", err)
 	}
 
-	// Add enabled checks to the engine
+	// Event rate check (enable with --checks event-rates)
+	eventCheck := health.NewEventRateCheck()
+	if namespace != "" {
+		if err := eventCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure event rate check: %w", err)
+		}
+	}
+	if err := registry.Register(eventCheck); err != nil {
+		return fmt.Errorf("failed to register event rate check: %w", err)
+	}
+
+	// Ingress layer check (enable with --checks ingress-health)
+	ingressCheck := health.NewIngressHealthCheck(GetDynamicClient())
+	if namespace != "" {
+		if err := ingressCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure ingress check: %w", err)
+		}
+	}
+	if err := registry.Register(ingressCheck); err != nil {
+		return fmt.Errorf("failed to register ingress check: %w", err)
+	}
+
+	// Service mesh check (enable with --checks service-mesh)
+	meshCheck := health.NewServiceMeshHealthCheck(GetDynamicClient())
+	if namespace != "" {
+		if err := meshCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure service mesh check: %w", err)
+		}
+	}
+	if err := registry.Register(meshCheck); err != nil {
+		return fmt.Errorf("failed to register service mesh check: %w", err)
+	}
+
+	// Pod Security Standards check (enable with --checks pod-security)
+	podSecurityCheck := health.NewPodSecurityCheck()
+	if namespace != "" {
+		if err := podSecurityCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure pod security check: %w", err)
+		}
+	}
+	if err := registry.Register(podSecurityCheck); err != nil {
+		return fmt.Errorf("failed to register pod security check: %w", err)
+	}
+
+	// Node version skew check (enable with --checks node-versions)
+	if err := registry.Register(health.NewNodeVersionCheck()); err != nil {
+		return fmt.Errorf("failed to register node version check: %w", err)
+	}
+
+	// Add the profile's checks, or the enabled checks, to the engine
+	if checkProfile != "" {
+		checks, err := profileChecks(registry, config.MonitoringConfig{}, checkProfile, nil)
+		if err != nil {
+			return err
+		}
+		enabledChecks = make([]string, 0, len(checks))
+		for _, check := range checks {
+			enabledChecks = append(enabledChecks, check.Name())
+		}
+	}
 	for _, checkName := range enabledChecks {
 		check, err := registry.Get(checkName)
 		if err != nil {
@@ -118,27 +193,29 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 	if !watch {
 		// Wait for one check cycle
 		time.Sleep(2 * time.Second)
-		displayResults(engine)
-		return nil
+		return displayResults(printer, engine)
 	}
 
 	// Watch mode - continuous monitoring
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	fmt.Println("Starting continuous monitoring... (Press Ctrl+C to stop)")
-	fmt.Println()
+	printer.Infof("Starting continuous monitoring... (Press Ctrl+C to stop)\n")
 
 	// Initial display
 	time.Sleep(2 * time.Second)
-	displayResults(engine)
+	if err := displayResults(printer, engine); err != nil {
+		return err
+	}
 
 	for {
 		select {
 		case <-ticker.C:
-			displayResults(engine)
+			if err := displayResults(printer, engine); err != nil {
+				return err
+			}
 		case <-sigChan:
-			fmt.Println("\nShutting down...")
+			printer.Infof("\nShutting down...")
 			engine.Stop()
 			return nil
 		case <-ctx.Done():
@@ -147,55 +224,49 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func displayResults(engine *core.Engine) {
+// displayResults prints the engine's current cluster health
+func displayResults(p *output.Printer, engine *core.Engine) error {
 	health := engine.GetClusterHealth("default")
-
-	switch outputFormat {
-	case "json":
-		// TODO: Implement JSON output
-		fmt.Println("JSON output not yet implemented")
-	case "yaml":
-		// TODO: Implement YAML output
-		fmt.Println("YAML output not yet implemented")
-	default:
-		displaySummary(health)
-	}
+	return p.Print(health, func(w io.Writer) error {
+		return displaySummary(p, w, health)
+	})
 }
 
-func displaySummary(health core.ClusterHealth) {
-	// Clear screen in watch mode
-	if watch {
-		fmt.Print("\033[H\033[2J")
+// displaySummary writes a human-readable health report
+func displaySummary(p *output.Printer, w io.Writer, health core.ClusterHealth) error {
+	// Clear the screen between reports in watch mode
+	if watch && p.ColorEnabled() {
+		_, _ = fmt.Fprint(w, "\033[H\033[2J")
 	}
 
 	// Display header
-	fmt.Printf("=== KubePulse Health Report - %s ===\n", health.Timestamp.Format("2006-01-02 15:04:05"))
-	fmt.Println()
+	_, _ = fmt.Fprintf(w, "=== KubePulse Health Report - %s ===\n\n", health.Timestamp.Format("2006-01-02 15:04:05"))
 
 	// Display overall status with color
-	statusColor := getStatusColor(health.Status)
-	fmt.Printf("Overall Status: %s%s%s\n", statusColor, health.Status, resetColor)
-	fmt.Printf("Health Score: %.1f%% (weighted: %.1f%%)\n", health.Score.Raw, health.Score.Weighted)
-	fmt.Printf("Confidence: %.1f%%\n", health.Score.Confidence*100)
-	fmt.Println()
+	_, _ = fmt.Fprintf(w, "Overall Status: %s\n", p.Colorize(statusColor(health.Status), string(health.Status)))
+	_, _ = fmt.Fprintf(w, "Health Score: %.1f%% (weighted: %.1f%%)\n", health.Score.Raw, health.Score.Weighted)
+	_, _ = fmt.Fprintf(w, "Confidence: %.1f%%\n\n", health.Score.Confidence*100)
 
 	// Display individual checks
-	fmt.Println("Health Checks:")
-	fmt.Println("--------------")
+	_, _ = fmt.Fprintln(w, "Health Checks:")
+	_, _ = fmt.Fprintln(w, "--------------")
 	for _, check := range health.Checks {
-		checkColor := getStatusColor(check.Status)
-		symbol := getStatusSymbol(check.Status)
-		fmt.Printf("%s %s%-15s%s %s\n", symbol, checkColor, check.Name, resetColor, check.Message)
+		name := p.Colorize(statusColor(check.Status), output.PadRight(check.Name, 15))
+		_, _ = fmt.Fprintf(w, "%s %s %s\n", statusSymbol(p, check.Status), name, check.Message)
+		if window, ok := core.MaintenanceOf(check); ok {
+			_, _ = fmt.Fprintf(w, "      %s until %s by %s: %s\n", p.Colorize(output.Blue, "[maintenance]"),
+				window.Until.Local().Format("Jan 2 15:04"), window.Owner, window.Reason)
+		}
 
 		// Show important details
 		if check.Status != core.HealthStatusHealthy && len(check.Details) > 0 {
 			if issues, ok := check.Details["issues"].([]string); ok && len(issues) > 0 {
 				for i, issue := range issues {
 					if i >= 3 {
-						fmt.Printf("      ... and %d more issues\n", len(issues)-3)
+						_, _ = fmt.Fprintf(w, "      ... and %d more issues\n", len(issues)-3)
 						break
 					}
-					fmt.Printf("      - %s\n", issue)
+					_, _ = fmt.Fprintf(w, "      - %s\n", issue)
 				}
 			}
 		}
@@ -203,16 +274,17 @@ func displaySummary(health core.ClusterHealth) {
 
 	// Display active alerts
 	if len(health.Alerts) > 0 {
-		fmt.Println()
-		fmt.Println("Active Alerts:")
-		fmt.Println("--------------")
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "Active Alerts:")
+		_, _ = fmt.Fprintln(w, "--------------")
 		for _, alert := range health.Alerts {
-			severityColor := getSeverityColor(alert.Severity)
-			fmt.Printf("%s[%s]%s %s: %s\n", severityColor, alert.Severity, resetColor, alert.Name, alert.Message)
+			severity := p.Colorize(severityColor(alert.Severity), "["+string(alert.Severity)+"]")
+			_, _ = fmt.Fprintf(w, "%s %s: %s\n", severity, alert.Name, alert.Message)
 		}
 	}
 
-	fmt.Println()
+	_, err := fmt.Fprintln(w)
+	return err
 }
 
 func handleAlerts(alertChan <-chan core.Alert) {
@@ -228,49 +300,3 @@ func handleMetrics(metricsChan <-chan core.Metric) {
 		klog.V(3).Infof("Metric: %s = %f %s", metric.Name, metric.Value, metric.Unit)
 	}
 }
-
-// Color constants
-const (
-	resetColor  = "\033[0m"
-	redColor    = "\033[31m"
-	yellowColor = "\033[33m"
-	greenColor  = "\033[32m"
-	blueColor   = "\033[34m"
-)
-
-func getStatusColor(status core.HealthStatus) string {
-	switch status {
-	case core.HealthStatusHealthy:
-		return greenColor
-	case core.HealthStatusDegraded:
-		return yellowColor
-	case core.HealthStatusUnhealthy:
-		return redColor
-	default:
-		return blueColor
-	}
-}
-
-func getStatusSymbol(status core.HealthStatus) string {
-	switch status {
-	case core.HealthStatusHealthy:
-		return "✓"
-	case core.HealthStatusDegraded:
-		return "⚠"
-	case core.HealthStatusUnhealthy:
-		return "✗"
-	default:
-		return "?"
-	}
-}
-
-func getSeverityColor(severity core.AlertSeverity) string {
-	switch severity {
-	case core.AlertSeverityCritical:
-		return redColor
-	case core.AlertSeverityWarning:
-		return yellowColor
-	default:
-		return blueColor
-	}
-}
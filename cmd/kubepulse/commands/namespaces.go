@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	namespacesServer    string
+	namespacesUnhealthy bool
+)
+
+// namespacesCmd represents the ns command
+var namespacesCmd = &cobra.Command{
+	Use:     "ns",
+	Aliases: []string{"namespaces"},
+	Short:   "Show the health of each namespace",
+	Long: `Ns asks a running "kubepulse serve" for the health of each namespace: pod
+readiness, container restarts, pending and failed pods, persistent volume
+claims pending or lost, firing alerts and the SLOs labelled with the
+namespace. Namespaces are listed worst first.`,
+	Example: `  kubepulse ns
+  kubepulse ns --unhealthy
+  kubepulse ns -o json`,
+	Args: cobra.NoArgs,
+	RunE: runNamespaces,
+}
+
+func init() {
+	rootCmd.AddCommand(namespacesCmd)
+
+	namespacesCmd.Flags().StringVar(&namespacesServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+	namespacesCmd.Flags().BoolVar(&namespacesUnhealthy, "unhealthy", false, "Only show namespaces that aren't healthy")
+}
+
+func runNamespaces(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := client.NewClient(client.Config{BaseURL: namespacesServer})
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	report, err := apiClient.NamespaceHealth(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get namespace health: %w", err)
+	}
+	if namespacesUnhealthy {
+		namespaces := report.Namespaces[:0]
+		for _, health := range report.Namespaces {
+			if health.Status != core.HealthStatusHealthy {
+				namespaces = append(namespaces, health)
+			}
+		}
+		report.Namespaces = namespaces
+	}
+
+	return printer.Print(report, func(w io.Writer) error {
+		if !report.Synced {
+			printer.Infof("The server is still listing pods and claims; counts may be incomplete")
+		}
+		if len(report.Namespaces) == 0 {
+			_, err := fmt.Fprintln(w, "No namespaces")
+			return err
+		}
+		table := output.NewTable("NAMESPACE", "STATUS", "READY", "RESTARTS", "PENDING", "FAILED", "PVC ISSUES", "ALERTS", "SLOS")
+		for _, health := range report.Namespaces {
+			table.AddRow(health.Namespace, printer.Colorize(statusColor(health.Status), string(health.Status)),
+				fmt.Sprintf("%d/%d (%.0f%%)", health.ReadyPods, health.Pods, health.Readiness),
+				fmt.Sprint(health.Restarts), fmt.Sprint(health.PendingPods), fmt.Sprint(health.FailedPods),
+				fmt.Sprintf("%d/%d", health.PVCIssues, health.PVCs), namespaceAlerts(health), namespaceSLOs(health))
+		}
+		return table.Render(w)
+	})
+}
+
+// namespaceAlerts summarizes the firing alerts of a namespace, e.g. "3 (1 critical)"
+func namespaceAlerts(health core.NamespaceHealth) string {
+	if health.CriticalAlerts == 0 {
+		return fmt.Sprint(health.Alerts)
+	}
+	return fmt.Sprintf("%d (%d critical)", health.Alerts, health.CriticalAlerts)
+}
+
+// namespaceSLOs lists the SLOs of a namespace, marking those violated
+func namespaceSLOs(health core.NamespaceHealth) string {
+	if len(health.SLOs) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(health.SLOs))
+	for _, slo := range health.SLOs {
+		if slo.Violated {
+			names = append(names, slo.Name+" (violated)")
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s (%.0f%% budget)", slo.Name, slo.ErrorBudget))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+// newPrinter returns the renderer for a command's output, set up from the
+// global --output, --no-color and --quiet flags
+func newPrinter(cmd *cobra.Command) (*output.Printer, error) {
+	format, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return nil, err
+	}
+	return output.NewPrinter(cmd.OutOrStdout(), cmd.ErrOrStderr(), output.Options{
+		Format:  format,
+		NoColor: noColor,
+		Quiet:   quiet,
+	}), nil
+}
+
+// statusColor returns the color a health status is shown in
+func statusColor(status core.HealthStatus) output.Color {
+	switch status {
+	case core.HealthStatusHealthy:
+		return output.Green
+	case core.HealthStatusDegraded:
+		return output.Yellow
+	case core.HealthStatusUnhealthy:
+		return output.Red
+	default:
+		return output.Blue
+	}
+}
+
+// statusSymbol returns the symbol shown before a health status, falling back
+// to ASCII on terminals without UTF-8
+func statusSymbol(p *output.Printer, status core.HealthStatus) string {
+	switch status {
+	case core.HealthStatusHealthy:
+		return p.Symbol("✓", "[ok]")
+	case core.HealthStatusDegraded:
+		return p.Symbol("⚠", "[warn]")
+	case core.HealthStatusUnhealthy:
+		return p.Symbol("✗", "[fail]")
+	case core.HealthStatusRestricted:
+		return p.Symbol("⊘", "[restricted]")
+	default:
+		return p.Symbol("?", "[?]")
+	}
+}
+
+// severityColor returns the color an alert severity is shown in
+func severityColor(severity core.AlertSeverity) output.Color {
+	switch severity {
+	case core.AlertSeverityCritical:
+		return output.Red
+	case core.AlertSeverityWarning:
+		return output.Yellow
+	default:
+		return output.Blue
+	}
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestNewPrinter(t *testing.T) {
+	defer func() { outputFlag, quiet = "table", false }()
+
+	var out, errOut bytes.Buffer
+	checkCmd.SetOut(&out)
+	checkCmd.SetErr(&errOut)
+	defer func() {
+		checkCmd.SetOut(nil)
+		checkCmd.SetErr(nil)
+	}()
+
+	outputFlag, quiet = "yaml", true
+	printer, err := newPrinter(checkCmd)
+	if err != nil {
+		t.Fatalf("newPrinter() error = %v", err)
+	}
+	if printer.Format() != output.FormatYAML || !printer.Quiet() {
+		t.Errorf("expected a quiet YAML printer, got %s quiet=%v", printer.Format(), printer.Quiet())
+	}
+	printer.Infof("Running pod-health")
+	if errOut.Len() != 0 {
+		t.Errorf("expected no progress in quiet mode, got %q", errOut.String())
+	}
+
+	outputFlag = "xml"
+	if _, err := newPrinter(checkCmd); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestPrintCheckResult(t *testing.T) {
+	result := core.CheckResult{
+		Name:     "pod-health",
+		Status:   core.HealthStatusDegraded,
+		Message:  "1 pod restarting",
+		Duration: 1500 * time.Millisecond,
+		Error:    errors.New("partial list"),
+		Details:  map[string]interface{}{"restarting": 1, "failed": 0},
+	}
+
+	var buf bytes.Buffer
+	printer := output.NewPrinter(&buf, io.Discard, output.Options{})
+	if err := printCheckResult(printer, &buf, result); err != nil {
+		t.Fatalf("printCheckResult() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Check:        pod-health", "degraded", "Error:        partial list", "Duration:     1.5s", "failed:       0\nrestarting:   1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printer = output.NewPrinter(&buf, io.Discard, output.Options{Format: output.FormatJSON})
+	if err := printer.Print(result, func(io.Writer) error { return nil }); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded["error"] != "partial list" {
+		t.Errorf("expected the result as JSON, got %s (%v)", buf.String(), err)
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/kubepulse/kubepulse/pkg/plugins"
+	"github.com/spf13/cobra"
+)
+
+// pluginsCmd represents the plugins command
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Work with health check plugins",
+	Long: `Health check plugins are executables in the plugins.dir directory
+(~/.kubepulse/plugins by default). "kubepulse serve" runs each as a health
+check alongside the built-in ones, and "kubepulse check <name>" runs one
+on its own. See docs/check-plugins.md for the protocol plugins speak.`,
+}
+
+// pluginsListCmd represents the plugins list command
+var pluginsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the discovered health check plugins",
+	Long: `List the health check plugins in the plugin directory, and the
+executables there that failed to describe themselves.`,
+	Args: cobra.NoArgs,
+	RunE: runPluginsList,
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+	pluginsCmd.AddCommand(pluginsListCmd)
+}
+
+// discoverPlugins describes the plugins of the plugins section, to run
+// against a kubeconfig context
+func discoverPlugins(cfg config.PluginsConfig, kubeContext string) ([]*plugins.ExecCheck, []error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return plugins.Discover(context.Background(), backup.ExpandHome(cfg.Dir), plugins.ExecOptions{
+		Timeout:    cfg.Timeout,
+		Kubeconfig: kubeconfig,
+		Context:    kubeContext,
+		Config:     cfg.Config,
+	})
+}
+
+func runPluginsList(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.Plugins.Enabled {
+		return fmt.Errorf("plugins are disabled by plugins.enabled")
+	}
+
+	checks, errs := discoverPlugins(cfg.Plugins, contextName)
+	type listedPlugin struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Criticality string `json:"criticality"`
+		Interval    string `json:"interval,omitempty"`
+		Path        string `json:"path"`
+	}
+	listed := struct {
+		Dir     string         `json:"dir"`
+		Plugins []listedPlugin `json:"plugins"`
+		Errors  []string       `json:"errors,omitempty"`
+	}{Dir: backup.ExpandHome(cfg.Plugins.Dir), Plugins: []listedPlugin{}}
+	for _, check := range checks {
+		plugin := listedPlugin{
+			Name:        check.Name(),
+			Description: check.Description(),
+			Criticality: string(check.Criticality()),
+			Path:        check.Path(),
+		}
+		if check.Interval() > 0 {
+			plugin.Interval = check.Interval().String()
+		}
+		listed.Plugins = append(listed.Plugins, plugin)
+	}
+	for _, err := range errs {
+		listed.Errors = append(listed.Errors, err.Error())
+	}
+
+	return printer.Print(listed, func(w io.Writer) error {
+		if len(checks) == 0 {
+			_, _ = fmt.Fprintf(w, "No plugins in %s\n", listed.Dir)
+		} else {
+			table := output.NewTable("NAME", "CRITICALITY", "INTERVAL", "DESCRIPTION")
+			for _, plugin := range listed.Plugins {
+				interval := plugin.Interval
+				if interval == "" {
+					interval = "default"
+				}
+				table.AddRow(plugin.Name, plugin.Criticality, interval, plugin.Description)
+			}
+			if err := table.Render(w); err != nil {
+				return err
+			}
+		}
+		for _, message := range listed.Errors {
+			if _, err := fmt.Fprintf(w, "%s %s\n", printer.Colorize(output.Red, printer.Symbol("✗", "x")), message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rbacManifest bool
+	rbacRoleName string
+)
+
+// rbacCmd represents the rbac command
+var rbacCmd = &cobra.Command{
+	Use:   "rbac",
+	Short: "Inspect the Kubernetes permissions KubePulse needs",
+}
+
+// rbacAuditCmd represents the rbac audit command
+var rbacAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Compare KubePulse's permissions with what its enabled features need",
+	Long: `Audit lists the rules granted to the current identity, usually the KubePulse
+ServiceAccount, with a SelfSubjectRulesReview and compares them with the
+permissions needed by the checks of the selected check profile, the custom
+resource checks and features such as the cluster inventory, change feed and
+resource descriptions. It prints the permissions that are missing, so some
+feature will fail, and those granted that nothing needs.
+
+Rules are reviewed in the default namespace, which includes those bound
+cluster-wide. It exits with an error when a permission is missing.
+
+With --manifest it prints a minimal ClusterRole for the enabled features
+instead, plus a Role for each custom resource check limited to a namespace,
+without connecting to the cluster.`,
+	Example: `  kubepulse rbac audit
+  kubepulse rbac audit --check-profile deep -o json
+  kubepulse rbac audit --manifest > kubepulse-rbac.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runRBACAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(rbacCmd)
+	rbacCmd.AddCommand(rbacAuditCmd)
+
+	rbacAuditCmd.Flags().StringVar(&checkProfile, "check-profile", "", "Check profile to audit for: minimal, standard, deep or one from monitoring.check_profiles")
+	rbacAuditCmd.Flags().BoolVar(&rbacManifest, "manifest", false, "Print a minimal ClusterRole and Roles for the enabled features instead")
+	rbacAuditCmd.Flags().StringVar(&rbacRoleName, "name", "kubepulse", "Name of the ClusterRole and Roles printed by --manifest")
+}
+
+func runRBACAudit(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	profile := checkProfile
+	if !cmd.Flags().Changed("check-profile") {
+		currentContext := ""
+		if contextManager, err := newContextManager(); err == nil {
+			if ctx, err := contextManager.GetCurrentContext(); err == nil {
+				currentContext = ctx.Name
+			}
+		}
+		profile = cfg.Monitoring.CheckProfileFor(currentContext)
+	}
+	required, err := requiredPermissions(cfg, profile)
+	if err != nil {
+		return err
+	}
+
+	if rbacManifest {
+		return writeRBACManifest(cmd.OutOrStdout(), rbacRoleName, required)
+	}
+
+	client := GetK8sClient()
+	if client == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	audit, err := preflight.AuditRBAC(ctx, client, "", required)
+	if err != nil {
+		return err
+	}
+
+	err = printer.Print(audit, func(w io.Writer) error {
+		printRBACAudit(printer, w, audit, profile)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(audit.Missing) > 0 {
+		return fmt.Errorf("%d required permissions are missing", len(audit.Missing))
+	}
+	return nil
+}
+
+// requiredPermissions returns the permissions serve needs with the given
+// check profile and configuration
+func requiredPermissions(cfg *config.Config, profile string) ([]preflight.Permission, error) {
+	checks, err := cfg.Monitoring.ProfileChecks(profile)
+	if err != nil {
+		return nil, err
+	}
+	features := []string{preflight.FeatureInventory, preflight.FeatureChangeFeed, preflight.FeatureDescribe}
+	if cfg.Telemetry.Enabled {
+		features = append(features, preflight.FeatureTelemetry)
+	}
+	if cfg.Chaos.Enabled {
+		features = append(features, preflight.FeatureChaos)
+	}
+
+	custom := make([]preflight.Permission, 0, len(cfg.CustomResources)+len(cfg.CustomMetrics))
+	for _, resource := range cfg.CustomResources {
+		custom = append(custom, preflight.Permission{
+			Check:     resource.Name,
+			Verb:      "list",
+			Group:     resource.Group,
+			Resource:  resource.PluralResource(),
+			Namespace: resource.Namespace,
+		})
+	}
+	for _, metric := range cfg.CustomMetrics {
+		custom = append(custom, customMetricsPermission(metric))
+	}
+	return preflight.RequiredPermissions(checks, features, custom...), nil
+}
+
+// customMetricsPermission returns the access a custom metrics check needs.
+// The metrics APIs authorize a custom metric as a subresource of the objects
+// it describes, and an external metric as a resource of its own.
+func customMetricsPermission(metric config.CustomMetricConfig) preflight.Permission {
+	if metric.API == "external" {
+		namespace := metric.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		return preflight.Permission{
+			Check:     metric.Name,
+			Verb:      "list",
+			Group:     "external.metrics.k8s.io",
+			Resource:  metric.Metric,
+			Namespace: namespace,
+		}
+	}
+	return preflight.Permission{
+		Check:     metric.Name,
+		Verb:      "get",
+		Group:     "custom.metrics.k8s.io",
+		Resource:  metric.Resource + "/" + metric.Metric,
+		Namespace: metric.Namespace,
+	}
+}
+
+// writeRBACManifest writes the ClusterRole and Roles granting the required
+// permissions as YAML documents
+func writeRBACManifest(out io.Writer, name string, required []preflight.Permission) error {
+	clusterRole, roles := preflight.RBACManifest(name, required)
+	if err := output.WriteYAML(out, clusterRole); err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if _, err := fmt.Fprintln(out, "---"); err != nil {
+			return err
+		}
+		if err := output.WriteYAML(out, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// permissionName formats a permission the way kubectl auth can-i takes it,
+// such as "list cronjobs.batch"
+func permissionName(perm preflight.Permission) string {
+	name := perm.Verb + " " + perm.Resource
+	if perm.Group != "" {
+		name += "." + perm.Group
+	}
+	if perm.Namespace != "" {
+		name += " in " + perm.Namespace
+	}
+	return name
+}
+
+// printRBACAudit writes the missing and excessive permissions of an audit
+func printRBACAudit(p *output.Printer, out io.Writer, audit *preflight.RBACAudit, profile string) {
+	_, _ = fmt.Fprintf(out, "%d permissions required by the %s check profile and enabled features\n", len(audit.Required), profile)
+	if audit.Incomplete {
+		_, _ = fmt.Fprintf(out, "%s Rules are incomplete, so some permissions may be wrongly reported missing: %s\n",
+			p.Colorize(output.Yellow, p.Symbol("⚠️", "[warn]")), audit.EvaluationError)
+	}
+
+	_, _ = fmt.Fprintf(out, "\nmissing (%d)\n", len(audit.Missing))
+	for _, perm := range audit.Missing {
+		_, _ = fmt.Fprintf(out, "  %s %s %s\n", p.Colorize(output.Red, p.Symbol("❌", "[fail]")),
+			output.PadRight(permissionName(perm), 40), perm.Check+" will fail")
+	}
+
+	_, _ = fmt.Fprintf(out, "\nexcessive (%d)\n", len(audit.Excessive))
+	for _, perm := range audit.Excessive {
+		_, _ = fmt.Fprintf(out, "  %s %s\n", p.Colorize(output.Yellow, p.Symbol("⚠️", "[warn]")), permissionName(perm))
+	}
+
+	if len(audit.Missing) > 0 || len(audit.Excessive) > 0 {
+		_, _ = fmt.Fprintln(out, "\nRun kubepulse rbac audit --manifest for a ClusterRole granting exactly what is needed")
+	}
+}
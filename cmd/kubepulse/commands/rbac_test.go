@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+)
+
+func TestRequiredPermissions(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.CustomResources = []config.CustomResourceConfig{
+		{Name: "kafka", Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka", Namespace: "streaming"},
+	}
+	cfg.CustomMetrics = []config.CustomMetricConfig{
+		{Name: "checkout-rps", Metric: "http_requests_per_second", Namespace: "shop", Resource: "pods"},
+		{Name: "orders-queue", API: "external", Metric: "s0-rabbitmq-orders"},
+	}
+
+	required, err := requiredPermissions(cfg, config.CheckProfileMinimal)
+	if err != nil {
+		t.Fatalf("requiredPermissions() error = %v", err)
+	}
+	checks := make(map[string]bool)
+	for _, perm := range required {
+		checks[perm.Check] = true
+	}
+	for _, want := range []string{"node-health", preflight.FeatureInventory, preflight.FeatureDescribe, "kafka"} {
+		if !checks[want] {
+			t.Errorf("expected permissions for %s, got %+v", want, required)
+		}
+	}
+	wantMetrics := map[preflight.Permission]bool{
+		{Check: "checkout-rps", Verb: "get", Group: "custom.metrics.k8s.io", Resource: "pods/http_requests_per_second", Namespace: "shop"}: false,
+		{Check: "orders-queue", Verb: "list", Group: "external.metrics.k8s.io", Resource: "s0-rabbitmq-orders", Namespace: "default"}:      false,
+	}
+	for _, perm := range required {
+		if _, ok := wantMetrics[perm]; ok {
+			wantMetrics[perm] = true
+		}
+	}
+	for perm, found := range wantMetrics {
+		if !found {
+			t.Errorf("expected %s for %s, got %+v", permissionName(perm), perm.Check, required)
+		}
+	}
+	if checks["pod-health"] || checks[preflight.FeatureTelemetry] {
+		t.Errorf("expected only the minimal profile's checks and enabled features, got %+v", required)
+	}
+
+	if _, err := requiredPermissions(cfg, "huge"); err == nil {
+		t.Error("expected an error for an unknown check profile")
+	}
+}
+
+func TestWriteRBACManifest(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeRBACManifest(&buf, "kubepulse-minimal", []preflight.Permission{
+		{Check: "event-rates", Verb: "list", Resource: "events"},
+		{Check: "kafka", Verb: "list", Group: "kafka.strimzi.io", Resource: "kafkas", Namespace: "streaming"},
+	})
+	if err != nil {
+		t.Fatalf("writeRBACManifest() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"kind: ClusterRole\n", "name: kubepulse-minimal\n", "- events\n",
+		"---\n", "kind: Role\n", "namespace: streaming\n", "- kafka.strimzi.io\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in manifest:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintRBACAudit(t *testing.T) {
+	var buf bytes.Buffer
+	printRBACAudit(output.NewPrinter(&buf, io.Discard, output.Options{}), &buf, &preflight.RBACAudit{
+		Required:  make([]preflight.Permission, 12),
+		Missing:   []preflight.Permission{{Check: preflight.FeatureInventory, Verb: "list", Group: "batch", Resource: "cronjobs"}},
+		Excessive: []preflight.Permission{{Verb: "watch", Resource: "pods"}},
+	}, "standard")
+
+	out := buf.String()
+	for _, want := range []string{
+		"12 permissions required by the standard check profile",
+		"missing (1)", "list cronjobs.batch", "inventory will fail",
+		"excessive (1)", "watch pods",
+		"kubepulse rbac audit --manifest",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output %q", want, out)
+		}
+	}
+}
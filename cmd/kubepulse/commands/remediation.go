@@ -0,0 +1,295 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remediationServer  string
+	remediationToken   string
+	remediationCheck   string
+	remediationStatus  string
+	remediationComment string
+	remediationDryRun  bool
+)
+
+// remediationCmd represents the remediation command
+var remediationCmd = &cobra.Command{
+	Use:   "remediation",
+	Short: "Review, approve and run AI-suggested remediations",
+	Long: `Remediation works with the remediation actions a running "kubepulse serve"
+stored when it suggested them for failing checks.
+
+Actions wait for approval. An approved action runs once through the server's
+validated kubectl executor; if it fails it can be approved again. Dry runs
+need no approval. Every run records each command's output and the commands
+that would undo it.
+
+Approving and rejecting need an admin token when the server has API
+tokens; pass one with --token or KUBEPULSE_TOKEN.`,
+}
+
+// remediationListCmd represents the remediation list command
+var remediationListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List suggested remediation actions and their status",
+	Example: `  kubepulse remediation list --status pending`,
+	Args:    cobra.NoArgs,
+	RunE:    runRemediationList,
+}
+
+// remediationShowCmd represents the remediation show command
+var remediationShowCmd = &cobra.Command{
+	Use:   "show <action-id>",
+	Short: "Show a remediation action with its runs and rollback commands",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemediationShow,
+}
+
+// remediationApproveCmd represents the remediation approve command
+var remediationApproveCmd = &cobra.Command{
+	Use:     "approve <action-id>",
+	Short:   "Approve a remediation action to run once",
+	Example: `  kubepulse remediation approve action-1760680800000000000-0 --comment "matches the runbook"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runRemediationReview,
+}
+
+// remediationRejectCmd represents the remediation reject command
+var remediationRejectCmd = &cobra.Command{
+	Use:   "reject <action-id>",
+	Short: "Reject a remediation action so it can't run",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRemediationReview,
+}
+
+// remediationExecuteCmd represents the remediation execute command
+var remediationExecuteCmd = &cobra.Command{
+	Use:   "execute <action-id>",
+	Short: "Run an approved remediation action, or dry-run any action",
+	Example: `  kubepulse remediation execute action-1760680800000000000-0 --dry-run
+  kubepulse remediation execute action-1760680800000000000-0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemediationExecute,
+}
+
+func init() {
+	rootCmd.AddCommand(remediationCmd)
+	remediationCmd.AddCommand(remediationListCmd)
+	remediationCmd.AddCommand(remediationShowCmd)
+	remediationCmd.AddCommand(remediationApproveCmd)
+	remediationCmd.AddCommand(remediationRejectCmd)
+	remediationCmd.AddCommand(remediationExecuteCmd)
+
+	remediationCmd.PersistentFlags().StringVar(&remediationServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+	remediationCmd.PersistentFlags().StringVar(&remediationToken, "token", os.Getenv("KUBEPULSE_TOKEN"), "API token")
+	remediationListCmd.Flags().StringVar(&remediationCheck, "check", "", "Only list actions for this check")
+	remediationListCmd.Flags().StringVar(&remediationStatus, "status", "", "Only list actions with this status (pending, approved, rejected, running, executed, failed)")
+	remediationApproveCmd.Flags().StringVar(&remediationComment, "comment", "", "Why the action is approved")
+	remediationRejectCmd.Flags().StringVar(&remediationComment, "comment", "", "Why the action is rejected")
+	remediationExecuteCmd.Flags().BoolVar(&remediationDryRun, "dry-run", false, "Show what the commands would do without changing the cluster")
+}
+
+// remediationClient creates an API client whose requests allow for every
+// command of an action to run
+func remediationClient() (*client.Client, error) {
+	apiClient, err := client.NewClient(client.Config{BaseURL: remediationServer, Token: remediationToken, Timeout: 5 * time.Minute})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return apiClient, nil
+}
+
+func runRemediationList(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := remediationClient()
+	if err != nil {
+		return err
+	}
+
+	remediations, err := apiClient.Remediations(context.Background(), remediationCheck, core.RemediationStatus(remediationStatus))
+	if err != nil {
+		return fmt.Errorf("failed to list remediations: %w", err)
+	}
+	return printer.Print(remediations, func(w io.Writer) error {
+		if len(remediations) == 0 {
+			_, err := fmt.Fprintln(w, "No remediation actions")
+			return err
+		}
+		table := output.NewTable("ID", "CHECK", "STATUS", "RISK", "SUGGESTED", "DESCRIPTION")
+		for _, remediation := range remediations {
+			table.AddRow(remediation.Action.ID, remediation.Check,
+				printer.Colorize(remediationColor(remediation.Status), string(remediation.Status)),
+				string(remediation.Action.Risk), remediation.SuggestedAt.Local().Format(time.DateTime), remediation.Action.Description)
+		}
+		return table.Render(w)
+	})
+}
+
+func runRemediationShow(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := remediationClient()
+	if err != nil {
+		return err
+	}
+
+	remediation, records, err := apiClient.Remediation(context.Background(), args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get remediation %s: %w", args[0], err)
+	}
+	shown := struct {
+		*core.Remediation
+		Records []ai.RemediationRecord `json:"records"`
+	}{remediation, records}
+	return printer.Print(shown, func(w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "Remediation %s for %s: %s\n", remediation.Action.ID, remediation.Check,
+			printer.Colorize(remediationColor(remediation.Status), string(remediation.Status)))
+		_, _ = fmt.Fprintln(w, remediation.Action.Description)
+		if remediation.ReviewedBy != "" {
+			review := fmt.Sprintf("Reviewed by %s", remediation.ReviewedBy)
+			if remediation.Comment != "" {
+				review += ": " + remediation.Comment
+			}
+			_, _ = fmt.Fprintln(w, review)
+		}
+		_, _ = fmt.Fprintln(w)
+		for _, command := range remediation.Action.Commands {
+			_, _ = fmt.Fprintf(w, "  %s\n", command)
+		}
+		for _, record := range records {
+			if err := displayRemediationRecord(printer, w, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runRemediationReview(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := remediationClient()
+	if err != nil {
+		return err
+	}
+
+	review := apiClient.ApproveRemediation
+	if cmd.Name() == "reject" {
+		review = apiClient.RejectRemediation
+	}
+	remediation, err := review(context.Background(), args[0], remediationComment)
+	if err != nil {
+		return fmt.Errorf("failed to %s remediation %s: %w", cmd.Name(), args[0], err)
+	}
+	return printer.Print(remediation, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Remediation %s %s by %s\n", remediation.Action.ID, remediation.Status, remediation.ReviewedBy)
+		return err
+	})
+}
+
+func runRemediationExecute(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := remediationClient()
+	if err != nil {
+		return err
+	}
+
+	record, err := apiClient.ExecuteRemediation(context.Background(), args[0], remediationDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to execute remediation %s: %w", args[0], err)
+	}
+	return printer.Print(record, func(w io.Writer) error {
+		return displayRemediationRecord(printer, w, *record)
+	})
+}
+
+// remediationColor colors a remediation status
+func remediationColor(status core.RemediationStatus) output.Color {
+	switch status {
+	case core.RemediationExecuted:
+		return output.Green
+	case core.RemediationFailed, core.RemediationRejected:
+		return output.Red
+	case core.RemediationPending:
+		return output.Yellow
+	}
+	return output.Blue
+}
+
+// displayRemediationRecord prints a run's outcome, each command's output
+// and the commands undoing it, flagging those only an operator can undo
+func displayRemediationRecord(p *output.Printer, w io.Writer, record ai.RemediationRecord) error {
+	kind := "Run"
+	if record.DryRun {
+		kind = "Dry run"
+	}
+	outcome := p.Colorize(output.Green, "succeeded")
+	if !record.Success {
+		outcome = p.Colorize(output.Red, "failed")
+	}
+	header := fmt.Sprintf("\n%s %s at %s %s", kind, record.ID, record.Timestamp.Local().Format(time.DateTime), outcome)
+	if record.ApprovedBy != "" {
+		header += ", approved by " + record.ApprovedBy
+	}
+	if _, err := fmt.Fprintln(w, header); err != nil {
+		return err
+	}
+
+	for _, step := range record.Steps {
+		if _, err := fmt.Fprintf(w, "%s %s\n", p.Symbol("▸", ">"), step.Command); err != nil {
+			return err
+		}
+		text := strings.TrimRight(step.Output, "\n")
+		if step.Error != "" && text == "" {
+			text = p.Colorize(output.Red, step.Error)
+		}
+		if text != "" {
+			if _, err := fmt.Fprintln(w, text); err != nil {
+				return err
+			}
+		}
+		if step.ManualRollback && !record.DryRun {
+			if _, err := fmt.Fprintln(w, p.Colorize(output.Yellow, "  no automatic rollback, undo this command by hand if needed")); err != nil {
+				return err
+			}
+		}
+	}
+	if !record.Success && len(record.Steps) == 0 {
+		if _, err := fmt.Fprintln(w, record.Result); err != nil {
+			return err
+		}
+	}
+	if len(record.RollbackCommands) > 0 {
+		if _, err := fmt.Fprintln(w, "Rollback:"); err != nil {
+			return err
+		}
+		for _, command := range record.RollbackCommands {
+			if _, err := fmt.Fprintf(w, "  %s\n", command); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var replayNamespace string
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay [recording-file...]",
+	Short: "Re-run checks against recorded API responses",
+	Long: `Replay re-runs the built-in implementation of a recorded check against the
+exact API responses it read when it was recorded, and compares the status,
+message, error and details with the recorded result.
+
+Recordings come from "kubepulse check --record" or from the recordings API of
+a server started with --record-checks. Replay needs no cluster, so recordings
+can be kept as regression tests for check logic; the command fails when any
+replayed result differs.`,
+	Example: `  kubepulse check pod-health --record pod-health.json
+  kubepulse replay pod-health.json
+  curl localhost:8080/api/v1/recordings/pod-health-3 > pod-health-3.json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVarP(&replayNamespace, "namespace", "n", "", "Namespace the check was scoped to when recorded")
+}
+
+// replayResult is one replayed recording in the JSON and YAML output
+type replayResult struct {
+	File      string `json:"file"`
+	Truncated bool   `json:"truncated,omitempty"`
+	*core.ReplayReport
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	results := make([]replayResult, 0, len(args))
+	mismatches := 0
+	for _, path := range args {
+		recording, err := core.LoadRecording(path)
+		if err != nil {
+			return err
+		}
+		dynamicClient, err := recording.DynamicClient()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		check, err := findCheck(recording.Check, replayNamespace, dynamicClient)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		report, err := core.ReplayCheck(ctx, check, *recording)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		results = append(results, replayResult{File: path, Truncated: recording.Truncated, ReplayReport: report})
+		if !report.Matches {
+			mismatches++
+		}
+	}
+
+	err = printer.Print(results, func(w io.Writer) error {
+		for _, result := range results {
+			printReplayReport(printer, w, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if mismatches > 0 {
+		return fmt.Errorf("%d of %d replayed results differ from their recordings", mismatches, len(args))
+	}
+	return nil
+}
+
+// printReplayReport writes one line per recording and its differences
+func printReplayReport(p *output.Printer, out io.Writer, result replayResult) {
+	if result.Matches {
+		_, _ = fmt.Fprintf(out, "%s %s: %s replayed as %s\n", p.Colorize(output.Green, p.Symbol("✓", "[ok]")),
+			result.File, result.Check, result.Replayed.Status)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "%s %s: %s recorded %s, replayed %s\n", p.Colorize(output.Red, p.Symbol("✗", "[fail]")),
+		result.File, result.Check, result.Recorded.Status, result.Replayed.Status)
+	for _, difference := range result.Differences {
+		_, _ = fmt.Fprintf(out, "    %s\n", difference)
+	}
+	if result.Truncated {
+		_, _ = fmt.Fprintln(out, "    note: the recording is incomplete; some responses were too large or binary")
+	}
+}
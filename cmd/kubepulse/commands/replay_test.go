@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestRunReplay(t *testing.T) {
+	recorded := filepath.Join("..", "..", "..", "pkg", "health", "testdata", "pod-health-crashloop.json")
+	rec, err := core.LoadRecording(recorded)
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+
+	// A recording whose result no longer matches what the check computes
+	rec.Result.Status = core.HealthStatusHealthy
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatalf("failed to encode recording: %v", err)
+	}
+	stale := filepath.Join(t.TempDir(), "stale.json")
+	if err := os.WriteFile(stale, data, 0600); err != nil {
+		t.Fatalf("failed to write recording: %v", err)
+	}
+
+	var buf bytes.Buffer
+	replayCmd.SetOut(&buf)
+	defer replayCmd.SetOut(nil)
+
+	if err := runReplay(replayCmd, []string{recorded}); err != nil {
+		t.Fatalf("runReplay() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "✓") {
+		t.Errorf("expected a matching replay, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := runReplay(replayCmd, []string{recorded, stale}); err == nil {
+		t.Error("expected an error when a replay differs")
+	}
+	if !strings.Contains(buf.String(), "status: recorded healthy, replayed degraded") {
+		t.Errorf("expected the status difference in output %q", buf.String())
+	}
+}
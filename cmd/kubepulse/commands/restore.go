@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreFrom   string
+	restoreBackup string
+	restoreList   bool
+	restoreForce  bool
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore configuration and learned state from a backup",
+	Long: `Restore reads a backup written by a server with backup.enabled and puts it
+back in place: the config file is written to --config (default
+$HOME/.kubepulse.yaml) and the alert rules, silences, escalation policies,
+anomaly baselines and SLOs are saved to backup.state_file, which the next
+"kubepulse serve" applies on startup.
+
+--from is a backup file, a backup directory or an s3://bucket/prefix location.
+For directories and buckets the newest backup is restored unless --backup
+names another; --list shows what is available. S3 credentials come from
+backup.s3 in the current config or the AWS_* environment variables.
+
+Existing files are only replaced with --force. Restart a running server to
+pick up the restored configuration.`,
+	Example: `  kubepulse restore --from ~/.kubepulse/backups --list
+  kubepulse restore --from ~/.kubepulse/backups --force
+  kubepulse restore --from s3://ops-backups/kubepulse --backup kubepulse-backup-20261016T020000.000Z.json`,
+	Args: cobra.NoArgs,
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().StringVar(&restoreFrom, "from", "", "Backup file, directory or s3://bucket/prefix to restore from")
+	restoreCmd.Flags().StringVar(&restoreBackup, "backup", "", "Name of the backup to restore (default newest)")
+	restoreCmd.Flags().BoolVar(&restoreList, "list", false, "List the backups at --from instead of restoring")
+	restoreCmd.Flags().BoolVar(&restoreForce, "force", false, "Replace an existing config or state file")
+	_ = restoreCmd.MarkFlagRequired("from")
+}
+
+// restoreResult is the JSON and YAML output of a restore
+type restoreResult struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Version     string    `json:"version"`
+	ConfigFile  string    `json:"config_file,omitempty"` // Empty when the backup has no config
+	StateFile   string    `json:"state_file"`
+	AlertRules  int       `json:"alert_rules"`
+	Silences    int       `json:"silences"`
+	Escalations int       `json:"escalation_policies"`
+	Baselines   int       `json:"baselines"`
+	SLOs        int       `json:"slos"`
+}
+
+// backupList is the JSON and YAML output of restore --list
+type backupList struct {
+	Location string   `json:"location"`
+	Backups  []string `json:"backups"` // Newest first
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var snapshot *backup.Snapshot
+	if info, err := os.Stat(backup.ExpandHome(restoreFrom)); err == nil && info.Mode().IsRegular() {
+		if restoreList || restoreBackup != "" {
+			return fmt.Errorf("--list and --backup need a backup directory or bucket, not a file")
+		}
+		if snapshot, err = backup.ReadFile(backup.ExpandHome(restoreFrom)); err != nil {
+			return err
+		}
+	} else {
+		store, err := backup.Open(restoreFrom, restoreS3Options())
+		if err != nil {
+			return err
+		}
+		if restoreList {
+			names, err := backup.List(ctx, store)
+			if err != nil {
+				return err
+			}
+			list := backupList{Location: store.String(), Backups: append([]string{}, names...)}
+			return printer.Print(list, func(w io.Writer) error {
+				printBackupList(w, list)
+				return nil
+			})
+		}
+		if snapshot, err = backup.Read(ctx, store, restoreBackup); err != nil {
+			return err
+		}
+	}
+
+	result, err := restoreSnapshot(snapshot, restoreForce)
+	if err != nil {
+		return err
+	}
+	return printer.Print(result, func(w io.Writer) error {
+		printRestoreResult(w, result)
+		return nil
+	})
+}
+
+// restoreSnapshot writes a backup's config file and state file
+func restoreSnapshot(snapshot *backup.Snapshot, overwrite bool) (restoreResult, error) {
+	state := snapshot.State
+	result := restoreResult{
+		CreatedAt:   snapshot.CreatedAt,
+		Version:     snapshot.Version,
+		AlertRules:  len(state.AlertRules),
+		Silences:    len(state.Silences),
+		Escalations: len(state.EscalationPolicies),
+		Baselines:   len(state.Baselines),
+		SLOs:        len(state.SLOs),
+	}
+
+	path := configPath()
+	if snapshot.Config != "" {
+		if path == "" {
+			path = backup.ExpandHome(filepath.Join("~", ".kubepulse.yaml"))
+		}
+		if err := backup.WriteFile(path, []byte(snapshot.Config), overwrite); err != nil {
+			return result, err
+		}
+		result.ConfigFile = path
+	}
+
+	// The state file location may itself be set by the restored config
+	cfg, err := config.LoadConfigWithOptions(config.LoadOptions{Path: path, Profile: profileName})
+	if err != nil {
+		return result, fmt.Errorf("restored config is invalid: %w", err)
+	}
+	result.StateFile = backup.ExpandHome(cfg.Backup.StateFile)
+	if err := backup.WriteState(result.StateFile, snapshot.State, overwrite); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// printRestoreResult writes what a restore put back in place
+func printRestoreResult(out io.Writer, result restoreResult) {
+	_, _ = fmt.Fprintf(out, "Restored backup from %s (KubePulse %s)\n",
+		result.CreatedAt.Local().Format(time.RFC1123), result.Version)
+	if result.ConfigFile != "" {
+		_, _ = fmt.Fprintf(out, "  config:  %s\n", result.ConfigFile)
+	} else {
+		_, _ = fmt.Fprintf(out, "  config:  backup has none; keeping the current configuration\n")
+	}
+	_, _ = fmt.Fprintf(out, "  state:   %s\n", result.StateFile)
+	_, _ = fmt.Fprintf(out, "           %d alert rules, %d silences, %d escalation policies, %d baselines, %d SLOs\n",
+		result.AlertRules, result.Silences, result.Escalations, result.Baselines, result.SLOs)
+	_, _ = fmt.Fprintln(out, "Start or restart \"kubepulse serve\" to apply the restored state.")
+}
+
+// restoreS3Options returns S3 settings from the current configuration
+func restoreS3Options() backup.S3Options {
+	cfg, err := loadConfig()
+	if err != nil {
+		return backup.S3Options{}
+	}
+	return backup.S3Options{
+		Endpoint:        cfg.Backup.S3.Endpoint,
+		Region:          cfg.Backup.S3.Region,
+		AccessKeyID:     cfg.Backup.S3.AccessKeyID,
+		SecretAccessKey: cfg.Backup.S3.SecretAccessKey,
+	}
+}
+
+// printBackupList writes the backups at a location, newest first
+func printBackupList(out io.Writer, list backupList) {
+	if len(list.Backups) == 0 {
+		_, _ = fmt.Fprintf(out, "No backups in %s\n", list.Location)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Backups in %s (newest first):\n", list.Location)
+	for _, name := range list.Backups {
+		_, _ = fmt.Fprintf(out, "  %s\n", name)
+	}
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestRunRestore(t *testing.T) {
+	dir := t.TempDir()
+	backups := filepath.Join(dir, "backups")
+	statePath := filepath.Join(dir, "state.json")
+	configFile := filepath.Join(dir, "restored.yaml")
+
+	store := backup.NewDirStore(backups)
+	older := backup.Snapshot{Format: backup.FormatVersion, CreatedAt: time.Now().Add(-time.Hour), Version: "test"}
+	newest := backup.Snapshot{
+		Format:    backup.FormatVersion,
+		CreatedAt: time.Now(),
+		Version:   "test",
+		Config:    "backup:\n  state_file: " + statePath + "\n",
+		State: core.State{
+			Version:    core.StateVersion,
+			AlertRules: []alerts.RuleSpec{{Name: "pods", Check: "pod-health", Severity: alerts.AlertSeverityWarning}},
+		},
+	}
+	for _, snapshot := range []backup.Snapshot{older, newest} {
+		if _, err := backup.Write(context.Background(), store, snapshot); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	restoreCmd.SetOut(&buf)
+	defer func() {
+		cfgFile, restoreFrom, restoreBackup, restoreList, restoreForce = "", "", "", false, false
+		restoreCmd.SetOut(nil)
+	}()
+	cfgFile, restoreFrom = configFile, backups
+
+	restoreList = true
+	if err := runRestore(restoreCmd, nil); err != nil {
+		t.Fatalf("runRestore() --list error = %v", err)
+	}
+	if !strings.Contains(buf.String(), backup.Name(newest.CreatedAt)) || !strings.Contains(buf.String(), backup.Name(older.CreatedAt)) {
+		t.Errorf("expected both backups to be listed, got %q", buf.String())
+	}
+
+	restoreList = false
+	buf.Reset()
+	if err := runRestore(restoreCmd, nil); err != nil {
+		t.Fatalf("runRestore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(configFile); string(data) != newest.Config {
+		t.Errorf("expected the newest config to be restored, got %q", data)
+	}
+
+	// The state file goes where the restored config says
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("expected state file: %v", err)
+	}
+	var state core.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("invalid state file: %v", err)
+	}
+	if len(state.AlertRules) != 1 || state.AlertRules[0].Name != "pods" {
+		t.Errorf("unexpected restored state %+v", state)
+	}
+	if !strings.Contains(buf.String(), "1 alert rules") {
+		t.Errorf("expected a summary, got %q", buf.String())
+	}
+
+	// Restoring again needs --force
+	if err := runRestore(restoreCmd, nil); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected refusal to overwrite, got %v", err)
+	}
+	restoreForce = true
+	if err := runRestore(restoreCmd, nil); err != nil {
+		t.Errorf("runRestore() --force error = %v", err)
+	}
+}
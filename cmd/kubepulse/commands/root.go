@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"github.com/kubepulse/kubepulse/pkg/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 	"path/filepath"
@@ -16,7 +21,17 @@ var (
 	cfgFile     string
 	kubeconfig  string
 	contextName string
+	profileName string
+	readOnly    bool
+	outputFlag  string
+	noColor     bool
+	quiet       bool
 	k8sClient   kubernetes.Interface
+	k8sDynamic  dynamic.Interface // For custom resources such as Gateway API objects
+	k8sErr      error             // Why k8sClient could not be created
+
+	// checkRecorder wraps the client transport so check runs can be recorded
+	checkRecorder = core.NewCheckRecorder(0)
 )
 
 // rootCmd represents the base command
@@ -28,7 +43,7 @@ that combines traditional threshold-based monitoring with ML-powered anomaly det
 
 It provides instant "traffic light" health status for your clusters while
 eliminating alert fatigue through smart, context-aware monitoring.`,
-	Version: "0.1.0",
+	Version: version.Version,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately
@@ -43,6 +58,11 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.kubepulse.yaml)")
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "path to kubeconfig file")
 	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "kubernetes context to use")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "config profile to use (overrides KUBEPULSE_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "disable context switching, remediation and other changes (overrides read_only)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "output format: table, json or yaml")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also disabled by NO_COLOR or when not a terminal)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "print only results and errors, no progress or notices")
 
 	// Bind flags to viper
 	if err := viper.BindPFlag("kubeconfig", rootCmd.PersistentFlags().Lookup("kubeconfig")); err != nil {
@@ -75,7 +95,7 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in
-	if err := viper.ReadInConfig(); err == nil {
+	if err := viper.ReadInConfig(); err == nil && !quiet {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 
@@ -116,27 +136,51 @@ func initK8sClient() {
 		configOverrides,
 	)
 
-	config, err := clientConfig.ClientConfig()
+	var config *rest.Config
+	var err error
+	if endpoints := clusterEndpoints(); len(endpoints) > 0 {
+		// Clusters of the config file are contexts too, and need no kubeconfig
+		config, err = k8s.RESTConfig(kubeconfigPath, selectedContext, endpoints)
+	} else {
+		config, err = clientConfig.ClientConfig()
+	}
 	if err != nil {
 		// Try in-cluster config
 		config, err = clientcmd.BuildConfigFromFlags("", "")
 		if err != nil {
+			k8sErr = fmt.Errorf("error building kubeconfig: %w", err)
 			fmt.Fprintf(os.Stderr, "Error building kubeconfig: %v\n", err)
 			return
 		}
 	}
 
+	config.Wrap(checkRecorder.WrapTransport)
+
 	// Create the clientset
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
+		k8sErr = fmt.Errorf("error creating Kubernetes client: %w", err)
 		fmt.Fprintf(os.Stderr, "Error creating Kubernetes client: %v\n", err)
 		return
 	}
 
 	k8sClient = clientset
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		// Only custom resource checks need it; they skip those resources
+		fmt.Fprintf(os.Stderr, "Error creating dynamic Kubernetes client: %v\n", err)
+		return
+	}
+	k8sDynamic = dynamicClient
 }
 
 // GetK8sClient returns the initialized Kubernetes client
 func GetK8sClient() kubernetes.Interface {
 	return k8sClient
 }
+
+// GetDynamicClient returns the initialized dynamic client, or nil
+func GetDynamicClient() dynamic.Interface {
+	return k8sDynamic
+}
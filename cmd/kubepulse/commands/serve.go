@@ -3,28 +3,43 @@ package commands
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/kubepulse/kubepulse/internal/config"
 	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
 	"github.com/kubepulse/kubepulse/pkg/api"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/kubepulse/kubepulse/pkg/chaos"
 	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/diagnostics"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
 	"github.com/kubepulse/kubepulse/pkg/health"
-	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"github.com/kubepulse/kubepulse/pkg/loadshed"
 	"github.com/kubepulse/kubepulse/pkg/plugins"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"github.com/kubepulse/kubepulse/pkg/statuspage"
+	"github.com/kubepulse/kubepulse/pkg/telemetry"
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"github.com/kubepulse/kubepulse/pkg/webhooks"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
 )
 
 var (
-	port       int
-	apiOnly    bool
-	webEnabled bool
+	port         int
+	apiOnly      bool
+	webEnabled   bool
+	recordChecks bool
 )
 
 // serveCmd represents the serve command
@@ -47,6 +62,8 @@ func init() {
 	serveCmd.Flags().BoolVar(&webEnabled, "web", true, "Enable web dashboard")
 	serveCmd.Flags().DurationVarP(&interval, "interval", "i", 10*time.Second, "Health check interval")
 	serveCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to monitor (empty for all)")
+	serveCmd.Flags().StringVar(&checkProfile, "check-profile", "", "Check profile to run: minimal, standard, deep or one from monitoring.check_profiles")
+	serveCmd.Flags().BoolVar(&recordChecks, "record-checks", false, "Record the API responses each check reads so runs can be replayed")
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
@@ -56,8 +73,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create context manager
-	kubeconfigPath := viper.GetString("kubeconfig")
-	contextManager, err := k8s.NewContextManager(kubeconfigPath)
+	contextManager, err := newContextManager()
 	if err != nil {
 		return fmt.Errorf("failed to create context manager: %w", err)
 	}
@@ -68,29 +84,23 @@ func runServe(cmd *cobra.Command, args []string) error {
 		currentContext = ctx.Name
 	}
 
-	// Load configuration
-	var cfg *config.Config
-
-	// Check if config file is specified
-	configFile := viper.GetString("config")
-	if configFile != "" {
-		cfg, err = config.LoadConfig(configFile)
-	} else {
-		cfg, err = config.LoadConfig("")
-	}
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
-	}
-
-	// Override with command line flags if provided
+	// Load configuration, with command line flags as the final layer
+	var overrides []config.Override
 	if cmd.Flags().Changed("port") {
-		cfg.Server.Port = port
+		overrides = append(overrides, config.Override{Key: "server.port", Value: port, Flag: "--port"})
 	}
 	if cmd.Flags().Changed("web") {
-		cfg.Server.EnableWeb = webEnabled
+		overrides = append(overrides, config.Override{Key: "server.enable_web", Value: webEnabled, Flag: "--web"})
 	}
 	if cmd.Flags().Changed("interval") {
-		cfg.Monitoring.Interval = interval
+		overrides = append(overrides, config.Override{Key: "monitoring.interval", Value: interval, Flag: "--interval"})
+	}
+	if cmd.Flags().Changed("record-checks") {
+		overrides = append(overrides, config.Override{Key: "monitoring.record_checks", Value: recordChecks, Flag: "--record-checks"})
+	}
+	cfg, err := loadConfig(overrides...)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
 	// Create channels for alerts and metrics
@@ -98,9 +108,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 	metricsChan := make(chan core.Metric, 1000)
 
 	// Create monitoring engine with AI enabled
-	aiConfig := ai.Config{
-		ClaudePath: "claude", // Assume claude is in PATH
-		MaxTurns:   3,
+	aiConfig := aiClientConfig(cfg)
+	if aiConfig.TestMode {
+		klog.Warning("AI test mode is on: analyses are canned fixtures, not model output")
 	}
 
 	engineConfig := core.EngineConfig{
@@ -109,22 +119,80 @@ func runServe(cmd *cobra.Command, args []string) error {
 		Interval:    interval,
 		AlertChan:   alertChan,
 		MetricsChan: metricsChan,
+		MaxHistory:  cfg.Monitoring.MaxHistory,
 		EnableAI:    true,
 		AIConfig:    &aiConfig,
+
+		CheckTimeout:       cfg.Monitoring.Timeout,
+		WatchdogMultiplier: cfg.Monitoring.WatchdogMultiplier,
+		ReadOnly:           cfg.ReadOnly,
+
+		MaxConcurrentAnalyses: cfg.Server.MaxConcurrentAnalyses,
+	}
+	if cfg.AIEvaluation.Enabled {
+		engineConfig.EvaluationVariants = evaluationVariants(aiConfig, cfg.AIEvaluation.Variants)
+		engineConfig.EvaluationSampleRate = cfg.AIEvaluation.SampleRate
+	}
+	if cfg.Monitoring.RecordChecks {
+		engineConfig.Recorder = checkRecorder
+	}
+	engineConfig.Runbooks = cfg.Monitoring.Runbooks
+	engineConfig.ExpensiveChecks = cfg.Monitoring.ExpensiveChecks
+	engineConfig.ExpensiveInterval = cfg.Monitoring.ExpensiveInterval
+	engineConfig.InventoryInterval = cfg.Monitoring.InventoryInterval
+	engineConfig.LoadSheddingFactor = cfg.LoadShedding.IntervalFactor
+	engineConfig.ChaosAutoSilence = cfg.Chaos.AutoSilence
+	engineConfig.LatencyBudget = core.LatencyBudget{
+		Detection:  cfg.Monitoring.LatencyBudget.Detection,
+		Percentile: cfg.Monitoring.LatencyBudget.Percentile,
+	}
+	applyEnvironment(&engineConfig, cfg.Environments, currentContext)
+	engineConfig.PermissionRecheckInterval = cfg.Monitoring.PermissionRecheckInterval
+	engineConfig.CheckDiscovery = cfg.Monitoring.CheckDiscovery.Enabled
+	engineConfig.CheckOverrides = cfg.Monitoring.CheckDiscovery.Overrides
+	engineConfig.NativeTools = cfg.Monitoring.NativeTools
+	if adaptive := cfg.Monitoring.AdaptiveInterval; adaptive.Enabled {
+		engineConfig.Adaptive = core.AdaptiveConfig{
+			MinInterval:  adaptive.MinInterval,
+			MaxInterval:  adaptive.MaxInterval,
+			HealthyAfter: adaptive.HealthyAfter,
+		}
+	}
+	engineConfig.SLOs = sloDefinitions(cfg.SLOs)
+	engineConfig.MetricConditions = metricConditions(cfg.MetricConditions)
+	engineConfig.Cardinality = cardinalityConfig(cfg.Monitoring.Cardinality)
+	history, err := core.NewResultHistory(backup.ExpandHome(cfg.Monitoring.HistoryFile), cfg.Monitoring.HistoryRetention)
+	if err != nil {
+		return fmt.Errorf("failed to open health history: %w", err)
 	}
+	defer func() { _ = history.Close() }()
+	engineConfig.History = history
+	findingTracker, err := core.NewFindingTracker(backup.ExpandHome(cfg.Monitoring.FindingsFile), core.DefaultFindingRetention)
+	if err != nil {
+		return fmt.Errorf("failed to open findings: %w", err)
+	}
+	defer func() {
+		if err := findingTracker.Close(); err != nil {
+			klog.Errorf("Failed to save findings: %v", err)
+		}
+	}()
+	engineConfig.Findings = findingTracker
+	runbooks := runbookLinks(cfg.Monitoring.Runbooks)
+	verifyRunbooks(context.Background(), runbooks)
 	engine := core.NewEngine(engineConfig)
 
 	// Register health checks
 	registry := plugins.NewRegistry()
 
 	// Add pod health check
+	ownership := core.OwnershipRules{
+		Team: cfg.Monitoring.Ownership.Team,
+		App:  cfg.Monitoring.Ownership.App,
+		Tier: cfg.Monitoring.Ownership.Tier,
+	}
 	podCheck := health.NewPodHealthCheck()
-	if namespace != "" {
-		if err := podCheck.Configure(map[string]interface{}{
-			"namespace": namespace,
-		}); err != nil {
-			return fmt.Errorf("failed to configure pod check: %w", err)
-		}
+	if err := podCheck.Configure(workloadCheckConfig(namespace, ownership)); err != nil {
+		return fmt.Errorf("failed to configure pod check: %w", err)
 	}
 	if err := registry.Register(podCheck); err != nil {
 		return fmt.Errorf("failed to register pod check: %w", err)
@@ -138,21 +206,203 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	// Add service health check
 	serviceCheck := health.NewServiceHealthCheck()
+	if err := serviceCheck.Configure(workloadCheckConfig(namespace, ownership)); err != nil {
+		return fmt.Errorf("failed to configure service check: %w", err)
+	}
+	if err := registry.Register(serviceCheck); err != nil {
+		return fmt.Errorf("failed to register service check: %w", err)
+	}
+
+	// Add event rate check
+	eventCheck := health.NewEventRateCheck()
 	if namespace != "" {
-		if err := serviceCheck.Configure(map[string]interface{}{
+		if err := eventCheck.Configure(map[string]interface{}{
 			"namespace": namespace,
 		}); err != nil {
-			return fmt.Errorf("failed to configure service check: %w", err)
+			return fmt.Errorf("failed to configure event rate check: %w", err)
 		}
 	}
-	if err := registry.Register(serviceCheck); err != nil {
-		return fmt.Errorf("failed to register service check: %w", err)
+	if err := registry.Register(eventCheck); err != nil {
+		return fmt.Errorf("failed to register event rate check: %w", err)
+	}
+
+	// Add ingress health check
+	ingressCheck := health.NewIngressHealthCheck(GetDynamicClient())
+	if namespace != "" {
+		if err := ingressCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure ingress check: %w", err)
+		}
+	}
+	if err := registry.Register(ingressCheck); err != nil {
+		return fmt.Errorf("failed to register ingress check: %w", err)
+	}
+
+	// Add service mesh check; healthy when no mesh is installed
+	meshCheck := health.NewServiceMeshHealthCheck(GetDynamicClient())
+	if namespace != "" {
+		if err := meshCheck.Configure(map[string]interface{}{
+			"namespace": namespace,
+		}); err != nil {
+			return fmt.Errorf("failed to configure service mesh check: %w", err)
+		}
+	}
+	if err := registry.Register(meshCheck); err != nil {
+		return fmt.Errorf("failed to register service mesh check: %w", err)
+	}
+
+	// Add Pod Security Standards check
+	podSecurityCheck := health.NewPodSecurityCheck()
+	if err := podSecurityCheck.Configure(podSecurityConfig(cfg.Monitoring.PodSecurity, namespace)); err != nil {
+		return fmt.Errorf("failed to configure pod security check: %w", err)
+	}
+	if err := registry.Register(podSecurityCheck); err != nil {
+		return fmt.Errorf("failed to register pod security check: %w", err)
+	}
+
+	// Add node version skew check
+	if err := registry.Register(health.NewNodeVersionCheck()); err != nil {
+		return fmt.Errorf("failed to register node version check: %w", err)
+	}
+
+	// Add custom resource checks from the configuration
+	customChecks := make([]string, 0, len(cfg.CustomResources)+len(cfg.CustomMetrics))
+	for _, resource := range cfg.CustomResources {
+		check := customResourceCheck(resource, GetDynamicClient())
+		if err := registry.Register(check); err != nil {
+			return fmt.Errorf("failed to register custom resource check %s: %w", resource.Name, err)
+		}
+		customChecks = append(customChecks, check.Name())
 	}
 
-	// Add all checks to engine
-	for _, check := range registry.List() {
+	// Add checks on metrics served by custom and external metrics adapters
+	for _, metric := range cfg.CustomMetrics {
+		check := customMetricsCheck(metric)
+		if err := registry.Register(check); err != nil {
+			return fmt.Errorf("failed to register custom metrics check %s: %w", metric.Name, err)
+		}
+		customChecks = append(customChecks, check.Name())
+	}
+
+	// Add the health check plugins of the plugin directory. Each run execs
+	// the plugin afresh, so replaced binaries take effect without a restart.
+	pluginChecks, pluginErrs := discoverPlugins(cfg.Plugins, currentContext)
+	for _, err := range pluginErrs {
+		klog.Warningf("Skipping health check plugin: %v", err)
+	}
+	for _, check := range pluginChecks {
+		if err := registry.Register(check); err != nil {
+			klog.Warningf("Skipping health check plugin %s: %v", check.Path(), err)
+			continue
+		}
+		customChecks = append(customChecks, check.Name())
+	}
+
+	// Add the checks of the selected profile to the engine; the flag wins
+	// over the cluster's profile and monitoring.check_profile
+	profile := cfg.Monitoring.CheckProfileFor(currentContext)
+	if cmd.Flags().Changed("check-profile") {
+		profile = checkProfile
+	}
+	checks, err := profileChecks(registry, cfg.Monitoring, profile, customChecks)
+	if err != nil {
+		return fmt.Errorf("failed to select health checks: %w", err)
+	}
+	for _, check := range checks {
 		engine.AddCheck(check)
 	}
+	klog.Infof("Running %d health checks from the %s check profile", len(checks), profile)
+
+	slackSigningSecret, err := configureAlerting(engine, cfg.Alerts)
+	if err != nil {
+		return fmt.Errorf("failed to configure alerting: %w", err)
+	}
+
+	// Apply state left by `kubepulse restore`, then schedule backups
+	stateFile := backup.ExpandHome(cfg.Backup.StateFile)
+	if applied, err := backup.ApplyState(stateFile, engine); err != nil {
+		return err
+	} else if applied {
+		klog.Infof("Applied restored state from %s", stateFile)
+	}
+	backups, err := newBackupScheduler(cfg.Backup, engine)
+	if err != nil {
+		return fmt.Errorf("failed to configure backups: %w", err)
+	}
+
+	// Optional release update checks
+	var updateChecker *version.UpdateChecker
+	if cfg.Updates.Enabled {
+		updateChecker = version.NewUpdateChecker(version.UpdateConfig{
+			Repository: cfg.Updates.Repository,
+			Interval:   cfg.Updates.CheckInterval,
+			Features:   enabledFeatures(cfg),
+		})
+	}
+
+	// Usage reporting is opt-in; a disabled reporter still serves previews
+	telemetryEnabled := cfg.Telemetry.Enabled && !telemetry.OptedOut()
+	collector := telemetry.Collector{Client: client, Features: telemetryFeatures(cfg), Usage: engine.Usage}
+	reporter := telemetry.NewReporter(telemetryEnabled, cfg.Telemetry.Endpoint, cfg.Telemetry.Interval, collector.Collect)
+
+	// Check result webhooks for external automation; more can be
+	// registered through the API
+	dispatcher := webhooks.NewDispatcher(currentContext)
+	for _, webhook := range cfg.Webhooks {
+		err := dispatcher.Register(webhooks.Webhook{
+			Name:   webhook.Name,
+			URL:    webhook.URL,
+			Filter: webhook.Filter,
+			Secret: webhook.Secret,
+			Source: webhooks.SourceConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to configure webhooks: %w", err)
+		}
+	}
+	engine.OnCheckResult("webhooks", dispatcher.Observe)
+
+	// Self-diagnostics capture dumps when goroutines or check cycles jump
+	var selfDiagnostics *diagnostics.Monitor
+	if cfg.Diagnostics.Enabled {
+		selfDiagnostics, err = diagnostics.NewMonitor(diagnostics.Config{
+			Dir:           backup.ExpandHome(cfg.Diagnostics.DumpDir),
+			MaxDumps:      cfg.Diagnostics.MaxDumps,
+			Interval:      cfg.Monitoring.Interval,
+			GrowthFactor:  cfg.Diagnostics.GrowthFactor,
+			MinGoroutines: cfg.Diagnostics.MinGoroutines,
+			MinCycle:      cfg.Diagnostics.MinCycle,
+			Cooldown:      cfg.Diagnostics.Cooldown,
+			Warnings:      engine.CardinalityWarnings,
+		}, engine.CycleDuration)
+		if err != nil {
+			return fmt.Errorf("failed to configure self-diagnostics: %w", err)
+		}
+	}
+
+	// Shed load before KubePulse runs out of memory or CPU
+	var shedder *loadshed.Shedder
+	if cfg.LoadShedding.Enabled {
+		shedder = loadshed.NewShedder(loadshed.Config{
+			Interval:        cfg.Monitoring.Interval,
+			MemoryThreshold: cfg.LoadShedding.MemoryThreshold,
+			CPUThreshold:    cfg.LoadShedding.CPUThreshold,
+			OnChange:        func(state loadshed.State) { engine.SetLoadShedding(state.Shedding) },
+		})
+	}
+
+	// Tell failures caused by chaos experiments apart from real ones
+	var chaosDetector *chaos.Detector
+	if cfg.Chaos.Enabled && GetDynamicClient() != nil {
+		chaosDetector = chaos.NewDetector(GetDynamicClient(), cfg.Chaos.Interval, engine.SetChaosExperiments)
+	}
+
+	// Respect maintenance silences created in Alertmanager, and vice versa
+	var silenceSync *alerts.AlertmanagerSync
+	if cfg.Alerts.Alertmanager.URL != "" {
+		silenceSync = alerts.NewAlertmanagerSync(cfg.Alerts.Alertmanager.URL, cfg.Alerts.Alertmanager.Interval, engine)
+	}
 
 	// Create API server with configuration
 	serverConfig := api.Config{
@@ -164,10 +414,49 @@ func runServe(cmd *cobra.Command, args []string) error {
 		CORSOrigins:    cfg.Server.CORSOrigins,
 		ReadTimeout:    cfg.Server.ReadTimeout,
 		WriteTimeout:   cfg.Server.WriteTimeout,
+		AnalysisWait:   cfg.Server.AnalysisWait,
 		UIConfig:       cfg.UI,
+		UpdateChecker:  updateChecker,
+		Preflight: &preflight.Config{
+			Client:     client,
+			AIEnabled:  engineConfig.EnableAI,
+			ClaudePath: aiConfig.ClaudePath,
+			AIProvider: aiConfig.Provider,
+			AIEndpoint: aiConfig.Endpoint(),
+			ListenAddr: net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port)),
+			Serving:    true,
+			Runbooks:   runbooks,
+		},
+		Fleet:              fleet.NewProber(contextManager.NewClient),
+		Hub:                federation.NewHub(cfg.Federation.Instance, federationPeers(cfg.Federation.Spokes), cfg.Federation.Timeout),
+		Spoke:              federation.NewSpoke(cfg.Federation.Instance, federationPeers(cfg.Federation.Hubs)),
+		Backups:            backups,
+		Telemetry:          reporter,
+		Diagnostics:        selfDiagnostics,
+		Webhooks:           dispatcher,
+		LoadShedding:       shedder,
+		SilenceSync:        silenceSync,
+		SlackSigningSecret: slackSigningSecret,
+		ReadOnly:           cfg.ReadOnly,
+		Credentials:        apiCredentials(cfg.Server.Auth.Tokens),
+		TokensFile:         backup.ExpandHome(cfg.Server.Auth.TokensFile),
+		TokenRotation:      cfg.Server.Auth.RotationReminder,
 	}
 	apiServer := api.NewServer(serverConfig)
 
+	// Optional public status page on its own port
+	var statusPage *statuspage.Server
+	if cfg.StatusPage.Enabled {
+		statusPage = statuspage.NewServer(statuspage.Config{
+			Host:        cfg.StatusPage.Host,
+			Port:        cfg.StatusPage.Port,
+			Title:       cfg.StatusPage.Title,
+			Components:  statusPageComponents(cfg.StatusPage.Components),
+			HistoryDays: cfg.StatusPage.HistoryDays,
+			Source:      engine,
+		})
+	}
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -192,6 +481,16 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	if statusPage != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := statusPage.Start(); err != nil {
+				klog.Errorf("Status page error: %v", err)
+			}
+		}()
+	}
+
 	// Start broadcasting updates to WebSocket clients
 	wg.Add(1)
 	go func() {
@@ -203,13 +502,38 @@ func runServe(cmd *cobra.Command, args []string) error {
 			select {
 			case <-broadcastTicker.C:
 				health := engine.GetClusterHealth("default")
-				apiServer.BroadcastToClients(health)
+				apiServer.PublishHealth(health)
 			case <-ctx.Done():
 				return
 			}
 		}
 	}()
 
+	if updateChecker != nil {
+		go updateChecker.Run(ctx)
+	}
+	if backups != nil {
+		go backups.Run(ctx)
+	}
+	if selfDiagnostics != nil {
+		go selfDiagnostics.Run(ctx)
+	}
+	if shedder != nil {
+		go shedder.Run(ctx)
+	}
+	if chaosDetector != nil {
+		go chaosDetector.Run(ctx)
+	}
+	if silenceSync != nil {
+		go silenceSync.Run(ctx)
+	}
+	go dispatcher.Run(ctx)
+	if telemetryEnabled {
+		klog.Infof("Telemetry is on: sending anonymized usage to %s every %s; preview it with kubepulse telemetry preview",
+			cfg.Telemetry.Endpoint, cfg.Telemetry.Interval)
+		go reporter.Run(ctx)
+	}
+
 	// Handle alert and metrics channels
 	go handleAlerts(alertChan)
 	go handleMetrics(metricsChan)
@@ -234,6 +558,11 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if err := apiServer.Stop(shutdownCtx); err != nil {
 		klog.Errorf("Error stopping API server: %v", err)
 	}
+	if statusPage != nil {
+		if err := statusPage.Stop(shutdownCtx); err != nil {
+			klog.Errorf("Error stopping status page: %v", err)
+		}
+	}
 
 	// Stop monitoring engine
 	engine.Stop()
@@ -263,6 +592,8 @@ func displayStartupInfo(cfg *config.Config) {
 	fmt.Printf("│  Monitoring Interval: %s                │\n", cfg.Monitoring.Interval.String())
 	fmt.Printf("│  UI Refresh Interval: %s               │\n", cfg.UI.RefreshInterval.String())
 	fmt.Printf("│  CORS: %v                              │\n", cfg.Server.CORSEnabled)
+	fmt.Printf("│  Read-only: %v                         │\n", cfg.ReadOnly)
+	fmt.Printf("│  Telemetry: %v                         │\n", cfg.Telemetry.Enabled && !telemetry.OptedOut())
 	fmt.Printf("└─────────────────────────────────────────┘\n\n")
 
 	// Display feature flags
@@ -278,6 +609,10 @@ func displayStartupInfo(cfg *config.Config) {
 	fmt.Printf("   • Health Checks:     http://localhost:%d/api/v1/health/checks\n", cfg.Server.Port)
 	fmt.Printf("   • Prometheus Metrics: http://localhost:%d/api/v1/metrics\n", cfg.Server.Port)
 
+	if cfg.StatusPage.Enabled {
+		fmt.Printf("   • Status Page:       http://localhost:%d\n", cfg.StatusPage.Port)
+	}
+
 	if cfg.Server.EnableWeb {
 		fmt.Printf("\n🌐 Web Dashboard:\n")
 		fmt.Printf("   • Dashboard:         http://localhost:%d\n", cfg.Server.Port)
@@ -287,6 +622,190 @@ func displayStartupInfo(cfg *config.Config) {
 	fmt.Printf("\n💡 Press Ctrl+C to stop the server\n\n")
 }
 
+// statusPageComponents converts configured status page components
+func statusPageComponents(configured []config.StatusPageComponentConfig) []statuspage.Component {
+	components := make([]statuspage.Component, len(configured))
+	for i, c := range configured {
+		components[i] = statuspage.Component{
+			Name:        c.Name,
+			Description: c.Description,
+			Checks:      c.Checks,
+			SLOs:        c.SLOs,
+		}
+	}
+	return components
+}
+
+// apiCredentials converts configured API tokens
+func apiCredentials(configured []config.AuthTokenConfig) []api.Credential {
+	credentials := make([]api.Credential, len(configured))
+	for i, token := range configured {
+		credentials[i] = api.Credential{
+			Name:      token.Name,
+			Token:     token.Token,
+			Role:      token.Role,
+			Scope:     token.Scope,
+			Cluster:   token.Cluster,
+			ExpiresAt: token.ExpiresAt,
+		}
+	}
+	return credentials
+}
+
+// federationPeers converts configured federation hubs or spokes
+func federationPeers(configured []config.FederationPeerConfig) []federation.Peer {
+	peers := make([]federation.Peer, len(configured))
+	for i, peer := range configured {
+		peers[i] = federation.Peer{Name: peer.Name, URL: peer.URL, Secret: peer.Secret}
+	}
+	return peers
+}
+
+// evaluationVariants converts the configured evaluation models, each a copy
+// of the AI client configuration with its model and CLI
+func evaluationVariants(base ai.Config, configured []config.AIVariantConfig) []ai.EvaluationVariant {
+	variants := make([]ai.EvaluationVariant, len(configured))
+	for i, variant := range configured {
+		clientConfig := base
+		clientConfig.Model = variant.Model
+		if variant.ClaudePath != "" {
+			clientConfig.ClaudePath = variant.ClaudePath
+		}
+		if variant.Provider != "" {
+			provider := config.AIConfig{Provider: variant.Provider, APIKeyEnv: variant.APIKeyEnv}
+			clientConfig.Provider, clientConfig.BaseURL, clientConfig.APIKey = variant.Provider, variant.BaseURL, ""
+			if env := provider.KeyEnv(); env != "" {
+				clientConfig.APIKey = os.Getenv(env)
+			}
+		}
+		variants[i] = ai.EvaluationVariant{
+			Name:                 variant.Name,
+			Config:               clientConfig,
+			InputCostPerMTokens:  variant.InputCostPerMTokens,
+			OutputCostPerMTokens: variant.OutputCostPerMTokens,
+		}
+	}
+	return variants
+}
+
+// sloDefinitions converts configured SLOs, sorted by name
+func sloDefinitions(configured map[string]config.SLOConfig) []slo.SLO {
+	names := make([]string, 0, len(configured))
+	for name := range configured {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := make([]slo.SLO, 0, len(names))
+	for _, name := range names {
+		c := configured[name]
+		definition := slo.SLO{
+			Name:        name,
+			Description: c.Description,
+			SLI:         c.SLI,
+			Target:      c.Target,
+			Window:      c.Window,
+			Metrics:     c.Metrics,
+			Labels:      c.Labels,
+			Check:       c.Check,
+		}
+		if c.Check != "" && c.SLI == "" {
+			definition.SLI = "availability"
+		}
+		for _, alert := range c.BurnRateAlerts {
+			definition.BurnRateAlerts = append(definition.BurnRateAlerts, slo.BurnRateAlert{
+				LongWindow:  alert.LongWindow,
+				ShortWindow: alert.ShortWindow,
+				BurnRate:    alert.BurnRate,
+				Severity:    alert.Severity,
+			})
+		}
+		for _, rule := range c.BudgetPolicy {
+			definition.BudgetPolicy = append(definition.BudgetPolicy, slo.BudgetRule{Threshold: rule.Threshold, Action: rule.Action})
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions
+}
+
+// metricConditions converts configured conditions on ingested metrics
+func metricConditions(configured []config.MetricConditionConfig) []core.MetricCondition {
+	conditions := make([]core.MetricCondition, 0, len(configured))
+	for _, c := range configured {
+		conditions = append(conditions, core.MetricCondition{
+			Name:      c.Name,
+			Source:    c.Source,
+			Metric:    c.Metric,
+			Labels:    c.Labels,
+			Operator:  c.Operator,
+			Threshold: c.Threshold,
+			Status:    core.HealthStatus(c.Status),
+		})
+	}
+	return conditions
+}
+
+// cardinalityConfig converts the configured metric cardinality limits
+func cardinalityConfig(configured config.CardinalityConfig) core.CardinalityConfig {
+	limits := core.CardinalityConfig{
+		MaxSeries:      configured.MaxSeries,
+		MaxLabelValues: configured.MaxLabelValues,
+		Action:         core.CardinalityAction(configured.Action),
+		HashBuckets:    configured.HashBuckets,
+		Overrides:      make(map[string]core.CardinalityLimit, len(configured.Overrides)),
+	}
+	for metric, limit := range configured.Overrides {
+		limits.Overrides[metric] = core.CardinalityLimit{MaxSeries: limit.MaxSeries, MaxLabelValues: limit.MaxLabelValues}
+	}
+	return limits
+}
+
+// newBackupScheduler returns a scheduler for the configured backup location,
+// or nil when scheduled backups are disabled
+func newBackupScheduler(cfg config.BackupConfig, engine *core.Engine) (*backup.Scheduler, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	store, err := backup.Open(cfg.Location, backup.S3Options{
+		Endpoint:        cfg.S3.Endpoint,
+		Region:          cfg.S3.Region,
+		AccessKeyID:     cfg.S3.AccessKeyID,
+		SecretAccessKey: cfg.S3.SecretAccessKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	path := configPath()
+	return backup.NewScheduler(store, cfg.Interval, cfg.Retain, func() (backup.Snapshot, error) {
+		return backup.NewSnapshot(path, engine.ExportState())
+	}), nil
+}
+
+// enabledFeatures returns changelog keywords for the features this server runs,
+// used to pick release notes worth surfacing with an update
+func enabledFeatures(cfg *config.Config) []string {
+	features := []string{"health check", "api"}
+	if cfg.Alerts.Enabled {
+		features = append(features, "alert")
+	}
+	if cfg.ML.Enabled {
+		features = append(features, "anomaly", "ml")
+	}
+	if len(cfg.SLOs) > 0 {
+		features = append(features, "slo")
+	}
+	if cfg.UI.Features.AIInsights || cfg.UI.Features.PredictiveAnalytics || cfg.UI.Features.SmartAlerts {
+		features = append(features, "ai")
+	}
+	if cfg.Server.EnableWeb {
+		features = append(features, "dashboard", "ui")
+	}
+	if cfg.StatusPage.Enabled {
+		features = append(features, "status page")
+	}
+	return features
+}
+
 func getServerMode(apiOnly, webEnabled bool) string {
 	if apiOnly {
 		return "API Only          "
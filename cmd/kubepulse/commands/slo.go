@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/client"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/spf13/cobra"
+)
+
+var sloServer string
+
+// sloCmd represents the slo command
+var sloCmd = &cobra.Command{
+	Use:   "slo [name]",
+	Short: "Show SLO error budgets and burn rates",
+	Long: `Slo asks a running "kubepulse serve" for the SLOs defined under slos in
+its configuration: each SLI against its target and the error budget left.
+Given an SLO's name it shows the burn rates of its alerts; SLOs with an
+availability, error_rate or check SLI alert while their budget burns too
+fast over both of an alert's windows.`,
+	Example: `  kubepulse slo
+  kubepulse slo api-availability`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSLO,
+}
+
+func init() {
+	rootCmd.AddCommand(sloCmd)
+
+	sloCmd.Flags().StringVar(&sloServer, "server", "http://localhost:8080", "URL of the KubePulse server")
+}
+
+func runSLO(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	apiClient, err := client.NewClient(client.Config{BaseURL: sloServer})
+	if err != nil {
+		return fmt.Errorf("failed to create API client: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if len(args) == 1 {
+		status, err := apiClient.SLO(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get SLO %s: %w", args[0], err)
+		}
+		return printer.Print(status, func(w io.Writer) error {
+			return displaySLO(printer, w, status)
+		})
+	}
+
+	statuses, err := apiClient.SLOs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list SLOs: %w", err)
+	}
+	return printer.Print(statuses, func(w io.Writer) error {
+		if len(statuses) == 0 {
+			_, err := fmt.Fprintln(w, "No SLOs")
+			return err
+		}
+		table := output.NewTable("NAME", "SLI", "CURRENT", "TARGET", "BUDGET LEFT", "BURN RATE", "EXHAUSTS IN")
+		for _, status := range statuses {
+			exhausts := status.TimeToExhaust
+			if exhausts == "" {
+				exhausts = "-"
+			}
+			table.AddRow(status.SLO.Name, sloSource(status.SLO),
+				printer.Colorize(sloColor(status), fmt.Sprintf("%.3f", status.CurrentValue)),
+				fmt.Sprintf("%g", status.SLO.Target), fmt.Sprintf("%.0f%%", status.ErrorBudget),
+				fmt.Sprintf("%.1fx", status.BurnRate), exhausts)
+		}
+		return table.Render(w)
+	})
+}
+
+// displaySLO prints an SLO's status and the burn rates of its alerts
+func displaySLO(p *output.Printer, w io.Writer, status *core.SLOStatus) error {
+	_, _ = fmt.Fprintf(w, "SLO %s (%s): %s against a %g target, %.0f%% of the error budget left\n",
+		status.SLO.Name, sloSource(status.SLO), p.Colorize(sloColor(*status), fmt.Sprintf("%.3f", status.CurrentValue)),
+		status.SLO.Target, status.ErrorBudget)
+	if status.SLO.Description != "" {
+		_, _ = fmt.Fprintln(w, status.SLO.Description)
+	}
+	if status.TimeToExhaust != "" {
+		_, _ = fmt.Fprintf(w, "At the current burn rate the budget is exhausted in %s\n", status.TimeToExhaust)
+	}
+	if len(status.BurnRates) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w)
+	table := output.NewTable("SEVERITY", "THRESHOLD", "LONG WINDOW", "SHORT WINDOW", "FIRING")
+	for _, burn := range status.BurnRates {
+		firing := "no"
+		if burn.Firing {
+			firing = p.Colorize(output.Red, "yes")
+		}
+		table.AddRow(burn.Severity, fmt.Sprintf("%gx", burn.BurnRate),
+			fmt.Sprintf("%.1fx over %s", burn.LongBurnRate, burn.LongWindow),
+			fmt.Sprintf("%.1fx over %s", burn.ShortBurnRate, burn.ShortWindow), firing)
+	}
+	return table.Render(w)
+}
+
+// sloSource describes what an SLO's SLI is computed from
+func sloSource(slo core.SLO) string {
+	if slo.Check != "" {
+		return "check " + slo.Check
+	}
+	return slo.SLI
+}
+
+// sloColor colors an SLO's current value
+func sloColor(status core.SLOStatus) output.Color {
+	for _, burn := range status.BurnRates {
+		if burn.Firing {
+			return output.Red
+		}
+	}
+	if status.IsViolated {
+		return output.Yellow
+	}
+	return output.Green
+}
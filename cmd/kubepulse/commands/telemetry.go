@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/internal/output"
+	"github.com/kubepulse/kubepulse/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// telemetryCmd represents the telemetry command
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Show whether anonymized usage reporting is on",
+	Long: `KubePulse can report anonymized usage to help its maintainers decide what to
+work on. Reporting is off unless telemetry.enabled is true in the config file
+or KUBEPULSE_TELEMETRY=true, and DO_NOT_TRACK=1 turns it off regardless.
+
+A report holds the KubePulse version, operating system and architecture, the
+names of enabled features, bucketed node, namespace and pod counts, and
+bucketed counts and error rates of KubePulse's own checks. It never contains
+names, labels, messages, addresses or anything else read from the cluster, and
+carries no identifier for the installation.
+
+Use "kubepulse telemetry preview" to print exactly what would be sent.`,
+	Args: cobra.NoArgs,
+	RunE: runTelemetryStatus,
+}
+
+var telemetryPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Print the usage report that would be sent",
+	Long: `Preview prints the report built from the current configuration and cluster,
+exactly as it would be posted. Check counts and error rates are only known to
+a running server; "kubepulse serve" shows them at GET /api/v1/system/telemetry.
+Nothing is sent.`,
+	Args: cobra.NoArgs,
+	RunE: runTelemetryPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(telemetryCmd)
+	telemetryCmd.AddCommand(telemetryPreviewCmd)
+}
+
+// telemetryStatus is the JSON and YAML output of the telemetry command
+type telemetryStatus struct {
+	Sending  bool   `json:"sending"`
+	OptedOut bool   `json:"opted_out"` // DO_NOT_TRACK is set
+	Endpoint string `json:"endpoint,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
+func runTelemetryStatus(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	optedOut := telemetry.OptedOut()
+	status := telemetryStatus{Sending: cfg.Telemetry.Enabled && !optedOut, OptedOut: optedOut}
+	if status.Sending {
+		status.Endpoint, status.Interval = cfg.Telemetry.Endpoint, cfg.Telemetry.Interval.String()
+	}
+	return printer.Print(status, func(w io.Writer) error {
+		printTelemetryStatus(w, cfg.Telemetry, optedOut)
+		return nil
+	})
+}
+
+// printTelemetryStatus explains whether reports are sent and how to change it
+func printTelemetryStatus(out io.Writer, cfg config.TelemetryConfig, optedOut bool) {
+	switch {
+	case optedOut:
+		_, _ = fmt.Fprintln(out, "Telemetry: off (DO_NOT_TRACK is set)")
+	case cfg.Enabled:
+		_, _ = fmt.Fprintf(out, "Telemetry: on, sending to %s every %s\n", cfg.Endpoint, cfg.Interval)
+		_, _ = fmt.Fprintln(out, "Turn it off with telemetry.enabled: false, KUBEPULSE_TELEMETRY=false or DO_NOT_TRACK=1.")
+	default:
+		_, _ = fmt.Fprintln(out, "Telemetry: off")
+		_, _ = fmt.Fprintln(out, "Opt in with telemetry.enabled: true and telemetry.endpoint in the config file.")
+	}
+	_, _ = fmt.Fprintln(out, "Run \"kubepulse telemetry preview\" to see exactly what a report contains.")
+}
+
+func runTelemetryPreview(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The report is posted as JSON, so that is also how the table format shows it
+	collector := telemetry.Collector{Client: GetK8sClient(), Features: telemetryFeatures(cfg)}
+	report := collector.Collect(ctx)
+	return printer.Print(report, func(w io.Writer) error {
+		return output.WriteJSON(w, report)
+	})
+}
+
+// telemetryFeatures names the features a configuration enables. Only fixed
+// names are reported, never user-chosen ones such as channel or SLO names.
+func telemetryFeatures(cfg *config.Config) []string {
+	var features []string
+	add := func(enabled bool, name string) {
+		if enabled {
+			features = append(features, name)
+		}
+	}
+
+	add(len(cfg.Kubernetes.Clusters) > 0, "kubernetes.clusters")
+	add(cfg.Alerts.Enabled, "alerts")
+	add(cfg.AIEvaluation.Enabled, "ai_evaluation")
+	// Validated against config.AIProviders, so no free text is reported
+	add(cfg.AI.Provider != "" && cfg.AI.Provider != "claude-cli", "ai.provider."+cfg.AI.Provider)
+	add(cfg.AI.TestMode, "ai.test_mode")
+	channelTypes := map[string]bool{}
+	for _, channel := range cfg.Alerts.Channels {
+		switch channel.Type {
+		case "log", "slack", "pagerduty", "opsgenie", "splunk_oncall":
+			if channel.Enabled && !channelTypes[channel.Type] {
+				channelTypes[channel.Type] = true
+				features = append(features, "alerts."+channel.Type)
+			}
+		}
+	}
+	add(len(cfg.Alerts.Escalations) > 0, "alerts.escalations")
+	add(cfg.Alerts.Alertmanager.URL != "", "alerts.alertmanager")
+	add(len(cfg.Alerts.Routes) > 0, "alerts.routes")
+	add(len(cfg.Environments.Contexts) > 0 || cfg.Environments.Default != "", "environments")
+	add(cfg.ML.Enabled, "ml")
+	add(len(cfg.SLOs) > 0, "slos")
+	add(len(cfg.MetricConditions) > 0, "metric_conditions")
+	add(cfg.UI.Features.AIInsights || cfg.UI.Features.PredictiveAnalytics || cfg.UI.Features.SmartAlerts, "ai")
+	add(cfg.Server.EnableWeb, "web")
+	add(cfg.Monitoring.RecordChecks, "recordings")
+	add(len(cfg.Monitoring.Runbooks) > 0, "runbooks")
+	add(cfg.Monitoring.NativeTools, "native_tools")
+	add(cfg.Backup.Enabled && strings.HasPrefix(cfg.Backup.Location, "s3://"), "backup.s3")
+	add(cfg.Backup.Enabled && !strings.HasPrefix(cfg.Backup.Location, "s3://"), "backup.dir")
+	add(cfg.StatusPage.Enabled, "status_page")
+	add(len(cfg.Server.Auth.Tokens) > 0, "auth")
+	add(cfg.Updates.Enabled, "updates")
+	add(cfg.ReadOnly, "read_only")
+	return features
+}
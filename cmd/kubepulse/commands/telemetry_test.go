@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+)
+
+func TestTelemetryFeatures(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.Alerts.Enabled = true
+	cfg.Alerts.Channels = map[string]config.ChannelConfig{
+		"payments-oncall": {Type: "slack", Enabled: true},
+		"platform-oncall": {Type: "slack", Enabled: true},
+		"legacy-pager":    {Type: "pagerduty", Enabled: false},
+	}
+	cfg.ML.Enabled = false
+	cfg.Server.EnableWeb = false
+	cfg.UI.Features.AIInsights = false
+	cfg.UI.Features.PredictiveAnalytics = false
+	cfg.UI.Features.SmartAlerts = false
+	cfg.SLOs = map[string]config.SLOConfig{"checkout-availability": {SLI: "availability"}}
+	cfg.Backup.Enabled = true
+	cfg.Backup.Location = "s3://ops-backups/kubepulse"
+
+	want := []string{"alerts", "alerts.slack", "slos", "backup.s3"}
+	if got := telemetryFeatures(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("telemetryFeatures() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintTelemetryStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.TelemetryConfig
+		optedOut bool
+		want     string
+	}{
+		{"off", config.TelemetryConfig{}, false, "Telemetry: off\n"},
+		{"on", config.TelemetryConfig{Enabled: true, Endpoint: "https://telemetry.example.com", Interval: 24 * time.Hour}, false, "on, sending to https://telemetry.example.com every 24h0m0s"},
+		{"do not track", config.TelemetryConfig{Enabled: true}, true, "off (DO_NOT_TRACK is set)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			printTelemetryStatus(&out, tt.cfg, tt.optedOut)
+			if !strings.Contains(out.String(), tt.want) || !strings.Contains(out.String(), "kubepulse telemetry preview") {
+				t.Errorf("unexpected output:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+var versionCheck bool
+
+// versionReport is the JSON and YAML output of the version command
+type versionReport struct {
+	version.Info
+	Update *version.UpdateStatus `json:"update,omitempty"`
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print build version information",
+	Long: `Print the version, git commit, build date and platform of this binary.
+With --check, also look up the latest GitHub release and report whether a
+newer version is available along with notable changelog entries.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check GitHub for a newer release")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	report := versionReport{Info: version.Get()}
+	var checkErr error
+	if versionCheck {
+		report.Update, checkErr = checkForUpdate(cmd.Context())
+	}
+
+	err = printer.Print(report, func(w io.Writer) error {
+		if _, err := fmt.Fprintln(w, report.Info.String()); err != nil {
+			return err
+		}
+		if report.Update != nil {
+			printUpdateStatus(w, report.Update)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if checkErr != nil {
+		return fmt.Errorf("update check failed: %w", checkErr)
+	}
+	return nil
+}
+
+// checkForUpdate looks up the latest release using the configured repository
+func checkForUpdate(ctx context.Context) (*version.UpdateStatus, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	repository := version.DefaultRepository
+	if cfg, err := loadConfig(); err == nil && cfg.Updates.Repository != "" {
+		repository = cfg.Updates.Repository
+	}
+
+	return version.NewUpdateChecker(version.UpdateConfig{Repository: repository}).Check(ctx)
+}
+
+// printUpdateStatus writes a human-readable update notice
+func printUpdateStatus(out io.Writer, status *version.UpdateStatus) {
+	if !status.UpdateAvailable {
+		_, _ = fmt.Fprintf(out, "You are running the latest release (%s)\n", status.LatestVersion)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "New version available: %s (running %s)\n", status.LatestVersion, status.CurrentVersion)
+	if status.ReleaseURL != "" {
+		_, _ = fmt.Fprintf(out, "  %s\n", status.ReleaseURL)
+	}
+	for _, highlight := range status.Highlights {
+		_, _ = fmt.Fprintf(out, "  • %s\n", highlight)
+	}
+}
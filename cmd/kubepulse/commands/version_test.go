@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/version"
+)
+
+func TestRunVersion(t *testing.T) {
+	defer func() { outputFlag = "table" }()
+
+	tests := []struct {
+		output  string
+		wantErr bool
+		check   func(t *testing.T, out string)
+	}{
+		{
+			output: "text",
+			check: func(t *testing.T, out string) {
+				if !strings.HasPrefix(out, "kubepulse "+version.Version) {
+					t.Errorf("unexpected text output %q", out)
+				}
+			},
+		},
+		{
+			output: "json",
+			check: func(t *testing.T, out string) {
+				var info version.Info
+				if err := json.Unmarshal([]byte(out), &info); err != nil {
+					t.Fatalf("expected JSON output: %v", err)
+				}
+				if info.Version != version.Version || info.Platform == "" {
+					t.Errorf("unexpected build info %+v", info)
+				}
+			},
+		},
+		{
+			output: "yaml",
+			check: func(t *testing.T, out string) {
+				if !strings.Contains(out, "version: "+version.Version) || !strings.Contains(out, "platform: ") {
+					t.Errorf("unexpected YAML output %q", out)
+				}
+			},
+		},
+		{output: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			var buf bytes.Buffer
+			versionCmd.SetOut(&buf)
+			outputFlag = tt.output
+
+			err := runVersion(versionCmd, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestPrintUpdateStatus(t *testing.T) {
+	var buf bytes.Buffer
+	printUpdateStatus(&buf, &version.UpdateStatus{
+		CurrentVersion:  "1.0.0",
+		LatestVersion:   "1.1.0",
+		UpdateAvailable: true,
+		ReleaseURL:      "https://example.com/v1.1.0",
+		Highlights:      []string{"New alert rule suggestions"},
+		CheckedAt:       time.Now(),
+	})
+
+	out := buf.String()
+	for _, want := range []string{"New version available: 1.1.0 (running 1.0.0)", "https://example.com/v1.1.0", "New alert rule suggestions"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output %q", want, out)
+		}
+	}
+
+	buf.Reset()
+	printUpdateStatus(&buf, &version.UpdateStatus{LatestVersion: "1.0.0"})
+	if !strings.Contains(buf.String(), "latest release") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
@@ -7,6 +7,9 @@ import { AIInsights } from '@/components/dashboard/AIInsights'
 import { NodeDetailsPanel } from '@/components/dashboard/NodeDetailsPanel'
 import { PredictiveAnalytics } from '@/components/dashboard/PredictiveAnalytics'
 import { SmartAlerts } from '@/components/dashboard/SmartAlerts'
+import { SilenceForm } from '@/components/dashboard/SilenceForm'
+import { ClusterInventory } from '@/components/dashboard/ClusterInventory'
+import { NamespaceHeatmap } from '@/components/dashboard/NamespaceHeatmap'
 import { useWebSocket } from '@/hooks/useWebSocket'
 import { useAIInsights } from '@/hooks/useAIInsights'
 import { useSystemTheme } from '@/hooks/useSystemTheme'
@@ -40,7 +43,7 @@ function App() {
   
   const [currentContext, setCurrentContext] = useState<KubernetesContext | null>(null)
   const { data, connectionStatus } = useWebSocket()
-  const { insights, loading: aiLoading, error: aiError } = useAIInsights()
+  const { insights, loading: aiLoading, error: aiError, stale: aiStale } = useAIInsights(data?.state_revision)
   const [activeTab, setActiveTab] = useState('overview')
   useSystemTheme() // This hook handles applying dark class to document
 
@@ -92,6 +95,9 @@ function App() {
           </div>
         )}
 
+        {/* Silence opened from a notification's silence link */}
+        <SilenceForm />
+
         {/* Enhanced Status Cards */}
         <div className="grid grid-cols-1 md:grid-cols-4 gap-6 mb-8">
           <StatusCard
@@ -145,7 +151,10 @@ function App() {
                 status: check.status,
                 message: check.message,
                 timestamp: check.timestamp,
-                duration: check.duration
+                duration: check.duration,
+                maintenance: check.details?.maintenance,
+                annotations: check.details?.annotations,
+                predictions: check.predictions
               })) || []}
             />
 
@@ -154,6 +163,12 @@ function App() {
               metrics={allMetrics}
               clusterStats={clusterStats}
             />
+
+            {/* Health of each namespace */}
+            <NamespaceHeatmap />
+
+            {/* What the cluster runs */}
+            <ClusterInventory />
           </TabsContent>
 
           {config.features.nodeDetails && (
@@ -172,6 +187,7 @@ function App() {
                   insights={insights}
                   loading={aiLoading}
                   error={aiError || undefined}
+                  stale={aiStale}
                 />
                 {config.features.smartAlerts && <SmartAlerts />}
               </div>
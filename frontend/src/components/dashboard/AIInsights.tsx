@@ -14,15 +14,19 @@ export interface AIInsight {
     impact?: string
     effort?: string
   }>
+  // Revision of the cluster state the insights were computed from
+  state_revision?: number
 }
 
 interface AIInsightsProps {
   insights: AIInsight | null
   loading?: boolean
   error?: string
+  // The cluster state changed since the insights were computed
+  stale?: boolean
 }
 
-export function AIInsights({ insights, loading, error }: AIInsightsProps) {
+export function AIInsights({ insights, loading, error, stale }: AIInsightsProps) {
   if (loading) {
     return (
       <Card>
@@ -78,6 +82,11 @@ export function AIInsights({ insights, loading, error }: AIInsightsProps) {
         <CardTitle className="flex items-center gap-2">
           <span>🤖</span>
           AI Insights
+          {stale && (
+            <Badge variant="outline" className="ml-auto" title="Checks changed status or a critical alert fired since this analysis">
+              Outdated, refreshing…
+            </Badge>
+          )}
         </CardTitle>
       </CardHeader>
       <CardContent className="space-y-4">
@@ -0,0 +1,115 @@
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { Badge } from "@/components/ui/badge"
+import { Separator } from "@/components/ui/separator"
+import { useApi } from "@/hooks/useApi"
+import { body, healthApi } from "@/lib/api"
+
+interface Inventory {
+  collected_at: string
+  server_version?: string
+  nodes: {
+    total: number
+    instance_types: Record<string, number>
+    kubelet_versions: Record<string, number>
+  }
+  namespaces: number
+  workloads: Record<string, number>
+  custom_resources: Array<{ group: string; preferred_version: string }>
+  components: Array<{ name: string; namespace: string; version: string }>
+  errors?: string[]
+}
+
+// The server refreshes the inventory every 15 minutes by default
+const INVENTORY_REFRESH_INTERVAL = 5 * 60 * 1000
+
+const fetchInventory = () => healthApi.getInventoryRaw().then(body<Inventory>)
+
+const WORKLOAD_KINDS = ['Deployment', 'StatefulSet', 'DaemonSet', 'Job', 'CronJob', 'Pod', 'Service']
+
+// counts renders a count map as "m5.large ×2, p3.2xlarge ×1", largest first
+function counts(values: Record<string, number>) {
+  return Object.entries(values)
+    .sort(([, a], [, b]) => b - a)
+    .map(([name, count]) => `${name} ×${count}`)
+    .join(', ')
+}
+
+export function ClusterInventory() {
+  const { data: inventory } = useApi(fetchInventory, {
+    refreshInterval: INVENTORY_REFRESH_INTERVAL
+  })
+
+  // The overview works without the inventory, e.g. against older servers
+  if (!inventory) {
+    return null
+  }
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center justify-between">
+          <span>Cluster Inventory</span>
+          <span className="text-xs font-normal text-muted-foreground">
+            {inventory.server_version && `${inventory.server_version} • `}
+            Collected {new Date(inventory.collected_at).toLocaleTimeString()}
+          </span>
+        </CardTitle>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+          <div>
+            <div className="text-2xl font-bold">{inventory.nodes.total}</div>
+            <div className="text-sm text-muted-foreground">Nodes</div>
+          </div>
+          <div>
+            <div className="text-2xl font-bold">{inventory.namespaces}</div>
+            <div className="text-sm text-muted-foreground">Namespaces</div>
+          </div>
+          {WORKLOAD_KINDS.filter(kind => kind in inventory.workloads).slice(0, 2).map(kind => (
+            <div key={kind}>
+              <div className="text-2xl font-bold">{inventory.workloads[kind]}</div>
+              <div className="text-sm text-muted-foreground">{kind}s</div>
+            </div>
+          ))}
+        </div>
+
+        <div className="text-sm space-y-1">
+          <div><span className="font-medium">Node types:</span> {counts(inventory.nodes.instance_types) || 'none'}</div>
+          <div><span className="font-medium">Kubelet versions:</span> {counts(inventory.nodes.kubelet_versions) || 'none'}</div>
+          <div>
+            <span className="font-medium">Workloads:</span>{' '}
+            {WORKLOAD_KINDS.filter(kind => kind in inventory.workloads)
+              .map(kind => `${inventory.workloads[kind]} ${kind}s`)
+              .join(', ')}
+          </div>
+        </div>
+
+        {inventory.components.length > 0 && (
+          <>
+            <Separator />
+            <div className="flex flex-wrap gap-2">
+              {inventory.components.map(component => (
+                <Badge key={`${component.namespace}/${component.name}`} variant="secondary" title={component.namespace}>
+                  {component.name} {component.version}
+                </Badge>
+              ))}
+            </div>
+          </>
+        )}
+
+        {inventory.custom_resources.length > 0 && (
+          <div className="text-sm text-muted-foreground">
+            {inventory.custom_resources.length} custom API groups:{' '}
+            {inventory.custom_resources.map(api => `${api.group}/${api.preferred_version}`).join(', ')}
+          </div>
+        )}
+
+        {inventory.errors && inventory.errors.length > 0 && (
+          <div className="text-xs text-yellow-600">
+            Incomplete: {inventory.errors.join('; ')}
+          </div>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -11,6 +11,7 @@ import {
 } from '@/components/ui/select'
 import { Badge } from '@/components/ui/badge'
 import { config } from '@/config'
+import { body, contextsApi } from '@/lib/api'
 
 interface ContextInfo {
   name: string
@@ -38,9 +39,7 @@ export function ContextSelector({ onContextChange }: ContextSelectorProps) {
 
   const fetchContexts = async () => {
     try {
-      const response = await fetch(`${config.api.baseUrl}/api/v1/contexts`)
-      if (!response.ok) throw new Error('Failed to fetch contexts')
-      const data = await response.json()
+      const data = await contextsApi.listContextsRaw().then(body<{ contexts?: ContextInfo[] }>)
       setContexts(data.contexts || [])
       
       // Find current context
@@ -60,15 +59,9 @@ export function ContextSelector({ onContextChange }: ContextSelectorProps) {
     
     setSwitching(true)
     try {
-      const response = await fetch(`${config.api.baseUrl}/api/v1/contexts/switch`, {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ context_name: contextName })
-      })
-      
-      if (!response.ok) throw new Error('Failed to switch context')
-      
-      const data = await response.json()
+      const data = await contextsApi
+        .switchContextRaw({ switchContextRequest: { contextName } })
+        .then(body<{ context: ContextInfo }>)
       setCurrentContext(data.context)
       
       // Update contexts list to reflect new current
@@ -101,7 +94,7 @@ export function ContextSelector({ onContextChange }: ContextSelectorProps) {
     <Select
       value={currentContext?.name}
       onValueChange={handleContextSwitch}
-      disabled={switching}
+      disabled={switching || config.readOnly}
     >
       <SelectTrigger className="w-[240px] h-9">
         <div className="flex items-center gap-2">
@@ -4,16 +4,99 @@ import { cn } from "@/lib/utils"
 
 export interface HealthCheck {
   name: string
-  status: "healthy" | "degraded" | "unhealthy"
+  status: "healthy" | "degraded" | "unhealthy" | "restricted"
   message: string
   timestamp?: string
   duration?: number
+  maintenance?: CheckMaintenance
+  annotations?: Annotation[]
+  predictions?: Prediction[]
+}
+
+// Prediction from the anomaly detector; explanation says why a metric was flagged
+export interface Prediction {
+  status: string
+  probability: number
+  reason: string
+  explanation?: AnomalyExplanation
+}
+
+export interface AnomalyExplanation {
+  metric: string
+  labels?: Record<string, string>
+  observed: number
+  baseline: number
+  std_dev: number
+  z_score: number
+  threshold: number
+  window: number
+  history: number[]
+}
+
+// Maintenance window set through POST /api/v1/checks/{name}/maintenance;
+// failures during it don't affect the health score or fire alerts
+export interface CheckMaintenance {
+  reason: string
+  owner: string
+  until: string
+}
+
+// Operator note on a time range set through POST /api/v1/annotations;
+// anomalies aren't flagged for the checks it covers
+export interface Annotation {
+  id: string
+  note: string
+  author?: string
+  start: string
+  end: string
+}
+
+// AnomalyExplanationRow shows why a metric was flagged anomalous
+function AnomalyExplanationRow({ prediction }: { prediction: Prediction }) {
+  const explanation = prediction.explanation
+  if (!explanation) {
+    return null
+  }
+  return (
+    <div className="mt-2 flex items-center gap-3 text-xs text-muted-foreground">
+      <Sparkline explanation={explanation} />
+      <span title={prediction.reason}>
+        Anomaly: {explanation.metric} {explanation.observed.toPrecision(4)} vs baseline{' '}
+        {explanation.baseline.toPrecision(4)} (z {explanation.z_score.toFixed(1)}, {explanation.window} samples)
+      </span>
+    </div>
+  )
 }
 
 interface HealthChecksTableProps {
   checks: HealthCheck[]
 }
 
+// Sparkline plots an anomaly's recent samples with the observed value last
+function Sparkline({ explanation }: { explanation: AnomalyExplanation }) {
+  const points = [...explanation.history, explanation.observed]
+  const min = Math.min(...points, explanation.baseline)
+  const max = Math.max(...points, explanation.baseline)
+  const range = max - min || 1
+  const width = 120
+  const height = 28
+  const x = (i: number) => (points.length > 1 ? (i / (points.length - 1)) * width : width)
+  const y = (v: number) => height - ((v - min) / range) * height
+
+  return (
+    <svg width={width} height={height} className="shrink-0" aria-label={`${explanation.metric} history`}>
+      <line x1={0} x2={width} y1={y(explanation.baseline)} y2={y(explanation.baseline)} className="stroke-muted-foreground/40" strokeDasharray="2 2" />
+      <polyline
+        fill="none"
+        className="stroke-primary"
+        strokeWidth={1.5}
+        points={points.map((v, i) => `${x(i)},${y(v)}`).join(' ')}
+      />
+      <circle cx={x(points.length - 1)} cy={y(explanation.observed)} r={2.5} className="fill-red-500" />
+    </svg>
+  )
+}
+
 export function HealthChecksTable({ checks }: HealthChecksTableProps) {
   const getStatusVariant = (status: HealthCheck["status"]) => {
     switch (status) {
@@ -23,6 +106,8 @@ export function HealthChecksTable({ checks }: HealthChecksTableProps) {
         return "secondary"
       case "unhealthy":
         return "destructive"
+      case "restricted":
+        return "outline"
     }
   }
 
@@ -34,6 +119,8 @@ export function HealthChecksTable({ checks }: HealthChecksTableProps) {
         return "border-l-yellow-500"
       case "unhealthy":
         return "border-l-red-500"
+      case "restricted":
+        return "border-l-muted-foreground"
     }
   }
 
@@ -92,18 +179,40 @@ export function HealthChecksTable({ checks }: HealthChecksTableProps) {
                       <span>Duration: {Math.round(check.duration / 1000000)}ms</span>
                     )}
                   </div>
+                  {check.maintenance && (
+                    <p className="mt-2 text-xs text-muted-foreground">
+                      Maintenance by {check.maintenance.owner} until{' '}
+                      {new Date(check.maintenance.until).toLocaleString()}: {check.maintenance.reason}
+                    </p>
+                  )}
+                  {check.annotations?.map(annotation => (
+                    <p key={annotation.id} className="mt-2 text-xs text-muted-foreground">
+                      Note{annotation.author && ` by ${annotation.author}`} until{' '}
+                      {new Date(annotation.end).toLocaleString()}: {annotation.note}
+                    </p>
+                  ))}
+                  {check.predictions?.map((prediction, i) => (
+                    <AnomalyExplanationRow key={i} prediction={prediction} />
+                  ))}
                 </div>
-                <Badge 
-                  variant={getStatusVariant(check.status)} 
-                  className={cn(
-                    "ml-4 font-semibold",
-                    check.status === "healthy" && "bg-green-500/10 text-green-600 border-green-500/20",
-                    check.status === "degraded" && "bg-yellow-500/10 text-yellow-600 border-yellow-500/20",
-                    check.status === "unhealthy" && "bg-red-500/10 text-red-600 border-red-500/20"
+                <div className="ml-4 flex flex-col items-end gap-1">
+                  <Badge 
+                    variant={getStatusVariant(check.status)} 
+                    className={cn(
+                      "font-semibold",
+                      check.status === "healthy" && "bg-green-500/10 text-green-600 border-green-500/20",
+                      check.status === "degraded" && "bg-yellow-500/10 text-yellow-600 border-yellow-500/20",
+                      check.status === "unhealthy" && "bg-red-500/10 text-red-600 border-red-500/20"
+                    )}
+                  >
+                    {check.status}
+                  </Badge>
+                  {check.maintenance && (
+                    <Badge variant="outline" className="bg-blue-500/10 text-blue-600 border-blue-500/20">
+                      maintenance
+                    </Badge>
                   )}
-                >
-                  {check.status}
-                </Badge>
+                </div>
               </div>
             </div>
           ))}
@@ -0,0 +1,91 @@
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
+import { useApi } from "@/hooks/useApi"
+import { body, healthApi } from "@/lib/api"
+
+interface NamespaceHealth {
+  namespace: string
+  status: 'healthy' | 'degraded' | 'unhealthy'
+  pods: number
+  ready_pods: number
+  readiness: number
+  restarts: number
+  pending_pods: number
+  failed_pods: number
+  pvcs: number
+  pvc_issues: number
+  alerts: number
+  critical_alerts: number
+  slos?: Array<{ name: string; violated: boolean; error_budget: number }>
+}
+
+interface NamespaceHealthReport {
+  synced: boolean
+  namespaces: NamespaceHealth[]
+}
+
+// Rollups are kept current from pod and claim events; polling is cheap
+const NAMESPACE_REFRESH_INTERVAL = 15 * 1000
+
+const fetchNamespaceHealth = () => healthApi.getNamespaceHealthRaw({}).then(body<NamespaceHealthReport>)
+
+const STATUS_COLORS: Record<NamespaceHealth['status'], string> = {
+  healthy: 'bg-green-500',
+  degraded: 'bg-yellow-500',
+  unhealthy: 'bg-red-500'
+}
+
+// summary lists what a namespace's cell stands for, shown on hover
+function summary(ns: NamespaceHealth) {
+  const lines = [
+    `${ns.namespace}: ${ns.status}`,
+    `${ns.ready_pods}/${ns.pods} pods ready (${ns.readiness.toFixed(0)}%), ${ns.restarts} restarts`,
+  ]
+  if (ns.pending_pods || ns.failed_pods) lines.push(`${ns.pending_pods} pending, ${ns.failed_pods} failed`)
+  if (ns.pvc_issues) lines.push(`${ns.pvc_issues} of ${ns.pvcs} claims pending or lost`)
+  if (ns.alerts) lines.push(`${ns.alerts} alerts firing, ${ns.critical_alerts} critical`)
+  for (const slo of ns.slos ?? []) {
+    lines.push(`SLO ${slo.name}: ${slo.violated ? 'violated' : `${slo.error_budget.toFixed(0)}% budget left`}`)
+  }
+  return lines.join('\n')
+}
+
+export function NamespaceHeatmap() {
+  const { data: report } = useApi(fetchNamespaceHealth, {
+    refreshInterval: NAMESPACE_REFRESH_INTERVAL
+  })
+
+  // The overview works without the rollups, e.g. against older servers
+  if (!report || report.namespaces.length === 0) {
+    return null
+  }
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center justify-between">
+          <span>Namespaces</span>
+          {!report.synced && (
+            <span className="text-xs font-normal text-muted-foreground">Still listing pods…</span>
+          )}
+        </CardTitle>
+      </CardHeader>
+      <CardContent>
+        <div className="grid grid-cols-3 md:grid-cols-6 lg:grid-cols-8 gap-2">
+          {report.namespaces.map(ns => (
+            <div
+              key={ns.namespace}
+              title={summary(ns)}
+              className={`${STATUS_COLORS[ns.status]} rounded p-2 text-white`}
+            >
+              <div className="text-xs font-medium truncate">{ns.namespace}</div>
+              <div className="text-xs opacity-90">
+                {ns.readiness.toFixed(0)}% ready
+                {ns.alerts > 0 && ` • ${ns.alerts} alerts`}
+              </div>
+            </div>
+          ))}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
@@ -3,6 +3,7 @@ import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert"
 import { Badge } from "@/components/ui/badge"
 import { Progress } from "@/components/ui/progress"
 import { useState, useEffect } from "react"
+import { aiApi, body } from "@/lib/api"
 
 interface PredictionData {
   predictions: Array<{
@@ -52,13 +53,7 @@ export function PredictiveAnalytics({ clusterHealth }: PredictiveAnalyticsProps)
     const fetchPredictions = async () => {
       try {
         setLoading(true)
-        const response = await fetch('/api/v1/ai/predictions')
-        
-        if (!response.ok) {
-          throw new Error('Failed to fetch predictions')
-        }
-        
-        const data = await response.json()
+        const data = await aiApi.getPredictionsRaw().then(body<PredictionData>)
         setPredictions(data)
         // setError(null)
       } catch (err) {
@@ -0,0 +1,124 @@
+import { useState } from 'react'
+import { BellOff } from 'lucide-react'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import { config } from '@/config'
+import { alertsApi, errorMessage } from '@/lib/api'
+
+const SILENCE_PARAM_PREFIX = 'silence.'
+const DURATIONS = ['1h', '4h', '12h', '24h', '168h']
+
+// silenceMatchersFromURL reads the matchers a notification's silence link
+// pre-fills, e.g. ?silence.check=pod-health&silence.rule=pod-health-critical
+function silenceMatchersFromURL(search: string): Record<string, string> {
+  const matchers: Record<string, string> = {}
+  new URLSearchParams(search).forEach((value, key) => {
+    if (key.startsWith(SILENCE_PARAM_PREFIX) && value) {
+      matchers[key.slice(SILENCE_PARAM_PREFIX.length)] = value
+    }
+  })
+  return matchers
+}
+
+// clearSilenceParams drops the silence link's parameters from the address bar
+function clearSilenceParams() {
+  const url = new URL(window.location.href)
+  Array.from(url.searchParams.keys())
+    .filter(key => key.startsWith(SILENCE_PARAM_PREFIX))
+    .forEach(key => url.searchParams.delete(key))
+  window.history.replaceState(null, '', url.toString())
+}
+
+// SilenceForm confirms a silence opened from a notification's silence link
+export function SilenceForm() {
+  const [matchers] = useState(() => silenceMatchersFromURL(window.location.search))
+  const [duration, setDuration] = useState(DURATIONS[0])
+  const [comment, setComment] = useState('')
+  const [submitting, setSubmitting] = useState(false)
+  const [error, setError] = useState<string | null>(null)
+  const [done, setDone] = useState(false)
+
+  if (Object.keys(matchers).length === 0 || done) {
+    return null
+  }
+
+  const handleSubmit = async () => {
+    setSubmitting(true)
+    setError(null)
+    try {
+      await alertsApi.createSilenceRaw({
+        silenceRequest: { matchers, duration, comment: comment || 'Silenced from notification link' },
+      })
+      clearSilenceParams()
+      setDone(true)
+    } catch (err) {
+      setError(await errorMessage(err, 'Failed to create silence'))
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  const handleCancel = () => {
+    clearSilenceParams()
+    setDone(true)
+  }
+
+  return (
+    <Card className="mb-6">
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <BellOff className="h-5 w-5" />
+          Silence alerts
+        </CardTitle>
+      </CardHeader>
+      <CardContent className="space-y-4">
+        <div className="flex flex-wrap gap-2">
+          {Object.entries(matchers).map(([label, value]) => (
+            <Badge key={label} variant="secondary">
+              {label}={value}
+            </Badge>
+          ))}
+        </div>
+        <div className="flex flex-wrap items-center gap-3">
+          <Select value={duration} onValueChange={setDuration} disabled={submitting}>
+            <SelectTrigger className="w-[120px] h-9">
+              <SelectValue />
+            </SelectTrigger>
+            <SelectContent>
+              {DURATIONS.map(d => (
+                <SelectItem key={d} value={d}>
+                  {d}
+                </SelectItem>
+              ))}
+            </SelectContent>
+          </Select>
+          <input
+            className="h-9 flex-1 min-w-[200px] rounded-md border border-input bg-transparent px-3 text-sm"
+            placeholder="Comment"
+            value={comment}
+            onChange={e => setComment(e.target.value)}
+            disabled={submitting}
+          />
+          <Button onClick={handleSubmit} disabled={submitting || config.readOnly}>
+            {submitting ? 'Silencing...' : 'Silence'}
+          </Button>
+          <Button variant="outline" onClick={handleCancel} disabled={submitting}>
+            Cancel
+          </Button>
+        </div>
+        {config.readOnly && (
+          <p className="text-sm text-muted-foreground">KubePulse is read-only; silences can't be created.</p>
+        )}
+        {error && <p className="text-sm text-destructive">{error}</p>}
+      </CardContent>
+    </Card>
+  )
+}
@@ -3,6 +3,7 @@ import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert"
 import { Badge } from "@/components/ui/badge"
 import { Button } from "@/components/ui/button"
 import { useState, useEffect } from "react"
+import { aiApi, body } from "@/lib/api"
 
 interface SmartAlert {
   id: string
@@ -46,13 +47,9 @@ export function SmartAlerts() {
     const fetchAlerts = async () => {
       try {
         setLoading(true)
-        const response = await fetch('/api/v1/ai/alerts/insights')
-        
-        if (!response.ok) {
-          throw new Error('Failed to fetch smart alerts')
-        }
-        
-        const data = await response.json()
+        const data = await aiApi
+          .getSmartAlertInsightsRaw()
+          .then(body<{ alerts?: SmartAlert[]; insights?: AlertInsights }>)
         setAlerts(data.alerts || [])
         setInsights(data.insights || null)
         // setError(null)
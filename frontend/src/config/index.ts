@@ -4,12 +4,20 @@ export interface Config {
     baseUrl: string
     wsUrl: string
     timeout: number
+    // Bearer token sent as the first WebSocket message when the server requires authentication
+    token?: string
+    // Fixture the server's AI test mode answers with, from ?ai_scenario= on the page URL
+    aiScenario?: string
   }
   ui: {
     refreshInterval: number
     aiInsightsInterval: number
     maxReconnectAttempts: number
     reconnectDelay: number
+    // Exponential backoff for WebSocket reconnects, advertised by the server
+    reconnectMaxDelay: number
+    reconnectMultiplier: number
+    reconnectJitter: number
     theme: 'light' | 'dark' | 'system'
   }
   features: {
@@ -17,7 +25,17 @@ export interface Config {
     predictiveAnalytics: boolean
     smartAlerts: boolean
     nodeDetails: boolean
+    // Derived by the server; false until it reports it
+    remediation?: boolean
   }
+  // Set by the server in read-only mode; controls that change state are hidden or disabled
+  readOnly: boolean
+  // Server-side features that are available; unknown until the server reports them
+  capabilities: Record<string, boolean>
+  // WebSocket message types the server pushes
+  wsTopics: string[]
+  // Server clock minus local clock in milliseconds
+  serverTimeOffset: number
 }
 
 // Get configuration from environment variables or defaults
@@ -45,12 +63,17 @@ function getConfig(): Config {
       baseUrl: apiBaseUrl,
       wsUrl: wsUrl,
       timeout: Number(env.VITE_API_TIMEOUT) || 30000, // 30 seconds
+      token: runtimeConfig.api?.token || env.VITE_API_TOKEN || undefined,
+      aiScenario: new URLSearchParams(window.location.search).get('ai_scenario') || env.VITE_AI_SCENARIO || undefined,
     },
     ui: {
       refreshInterval: Number(env.VITE_REFRESH_INTERVAL) || 10000, // 10 seconds
       aiInsightsInterval: Number(env.VITE_AI_INSIGHTS_INTERVAL) || 30000, // 30 seconds
       maxReconnectAttempts: Number(env.VITE_MAX_RECONNECT_ATTEMPTS) || 5,
       reconnectDelay: Number(env.VITE_RECONNECT_DELAY) || 3000, // 3 seconds
+      reconnectMaxDelay: 60000, // 1 minute
+      reconnectMultiplier: 2,
+      reconnectJitter: 0.2,
       theme: (env.VITE_THEME as Config['ui']['theme']) || 'system',
     },
     features: {
@@ -59,6 +82,10 @@ function getConfig(): Config {
       smartAlerts: env.VITE_FEATURE_SMART_ALERTS !== 'false',
       nodeDetails: env.VITE_FEATURE_NODE_DETAILS !== 'false',
     },
+    readOnly: false,
+    capabilities: {},
+    wsTopics: [],
+    serverTimeOffset: 0,
   }
 }
 
@@ -69,6 +96,10 @@ export const config = getConfig()
 export function apiUrl(path: string): string {
   const base = config.api.baseUrl.replace(/\/$/, '')
   const cleanPath = path.startsWith('/') ? path : `/${path}`
+  if (config.api.aiScenario) {
+    const separator = cleanPath.includes('?') ? '&' : '?'
+    return `${base}${cleanPath}${separator}ai_scenario=${encodeURIComponent(config.api.aiScenario)}`
+  }
   return `${base}${cleanPath}`
 }
 
@@ -77,6 +108,15 @@ export function wsUrl(): string {
   return config.api.wsUrl
 }
 
+// reconnectDelay returns the backed-off delay before the given reconnect
+// attempt (1-based), randomized by the configured jitter
+export function reconnectDelay(attempt: number): number {
+  const { reconnectDelay: initial, reconnectMaxDelay, reconnectMultiplier, reconnectJitter } = config.ui
+  const delay = Math.min(initial * Math.pow(reconnectMultiplier, attempt - 1), reconnectMaxDelay)
+  const jitter = delay * reconnectJitter * (Math.random() * 2 - 1)
+  return Math.max(0, Math.round(delay + jitter))
+}
+
 // Allow dynamic config updates (useful for development)
 export function updateConfig(updates: Partial<Config>): void {
   Object.assign(config, updates)
@@ -1,43 +1,84 @@
-import { useEffect, useState } from 'react'
+import { useCallback, useEffect, useRef, useState } from 'react'
 import type { AIInsight } from '@/components/dashboard/AIInsights'
-import { config, apiUrl } from '@/config'
+import { config } from '@/config'
+import { aiApi, body } from '@/lib/api'
 
-export function useAIInsights() {
+// How often a queued or running analysis is polled
+const RUN_POLL_INTERVAL = 2000
+
+interface AnalysisRun {
+  id: string
+  state: 'queued' | 'running' | 'succeeded' | 'failed'
+  result?: AIInsight
+  error?: string
+}
+
+// waitForRun polls an analysis run the server accepted until it finishes
+async function waitForRun(id: string): Promise<AIInsight> {
+  for (;;) {
+    await new Promise(resolve => setTimeout(resolve, RUN_POLL_INTERVAL))
+    const run = await aiApi.getAnalysisRunRaw({ id }).then(body<AnalysisRun>)
+    if (run.state === 'succeeded' && run.result) {
+      return run.result
+    }
+    if (run.state === 'failed') {
+      throw new Error(run.error || 'AI analysis failed')
+    }
+  }
+}
+
+// useAIInsights fetches cluster AI insights on an interval, and again as
+// soon as stateRevision moves past the revision they were computed from
+export function useAIInsights(stateRevision?: number) {
   const [insights, setInsights] = useState<AIInsight | null>(null)
   const [loading, setLoading] = useState(true)
   const [error, setError] = useState<string | null>(null)
+  const fetching = useRef(false)
 
-  useEffect(() => {
-    const fetchInsights = async () => {
-      try {
-        setLoading(true)
-        const response = await fetch(apiUrl('/api/v1/ai/insights'), {
-          signal: AbortSignal.timeout(config.api.timeout)
-        })
-        
-        if (!response.ok) {
-          throw new Error('Failed to fetch AI insights')
-        }
-        
-        const data = await response.json()
-        setInsights(data)
-        setError(null)
-      } catch (err) {
-        console.error('Failed to load AI insights:', err)
-        setError(err instanceof Error ? err.message : 'Unknown error')
-        setInsights(null)
-      } finally {
-        setLoading(false)
-      }
+  const fetchInsights = useCallback(async () => {
+    if (fetching.current) {
+      return
     }
+    fetching.current = true
+    try {
+      setLoading(true)
+      const response = await aiApi.getAIInsightsRaw()
 
+      // The server answers 202 with the run when the analysis is queued
+      // or still running
+      const data = response.raw.status === 202
+        ? await waitForRun((await body<AnalysisRun>(response)).id)
+        : await body<AIInsight>(response)
+      setInsights(data)
+      setError(null)
+    } catch (err) {
+      console.error('Failed to load AI insights:', err)
+      setError(err instanceof Error ? err.message : 'Unknown error')
+      setInsights(null)
+    } finally {
+      fetching.current = false
+      setLoading(false)
+    }
+  }, [])
+
+  useEffect(() => {
     fetchInsights()
     
     // Refresh AI insights based on config
     const interval = setInterval(fetchInsights, config.ui.aiInsightsInterval)
     
     return () => clearInterval(interval)
-  }, [])
+  }, [fetchInsights])
+
+  const stale = insights !== null && stateRevision !== undefined &&
+    (insights.state_revision ?? 0) < stateRevision
+
+  // Don't wait for the interval once the cluster state has moved on
+  useEffect(() => {
+    if (stale) {
+      fetchInsights()
+    }
+  }, [stale, stateRevision, fetchInsights])
 
-  return { insights, loading, error }
-}
\ No newline at end of file
+  return { insights, loading, error, stale }
+}
@@ -1,5 +1,4 @@
 import { useState, useEffect, useCallback } from 'react'
-import { config, apiUrl } from '@/config'
 
 interface UseApiOptions {
   autoFetch?: boolean
@@ -7,16 +6,19 @@ interface UseApiOptions {
   onError?: (error: Error) => void
 }
 
+// useApi loads data with a request made through the generated API client,
+// e.g. () => healthApi.getInventoryRaw().then(body<Inventory>). Declare the
+// request outside the component so it stays the same between renders.
 export function useApi<T>(
-  endpoint: string, 
+  request: () => Promise<T>,
   options: UseApiOptions = {}
 ) {
-  const { 
-    autoFetch = true, 
+  const {
+    autoFetch = true,
     refreshInterval = 0,
-    onError 
+    onError
   } = options
-  
+
   const [data, setData] = useState<T | null>(null)
   const [loading, setLoading] = useState(autoFetch)
   const [error, setError] = useState<Error | null>(null)
@@ -25,20 +27,7 @@ export function useApi<T>(
     try {
       setLoading(true)
       setError(null)
-      
-      const response = await fetch(apiUrl(endpoint), {
-        signal: AbortSignal.timeout(config.api.timeout),
-        headers: {
-          'Content-Type': 'application/json',
-        },
-      })
-      
-      if (!response.ok) {
-        throw new Error(`HTTP error! status: ${response.status}`)
-      }
-      
-      const json = await response.json()
-      setData(json)
+      setData(await request())
     } catch (err) {
       const error = err instanceof Error ? err : new Error('Unknown error')
       setError(error)
@@ -46,7 +35,7 @@ export function useApi<T>(
     } finally {
       setLoading(false)
     }
-  }, [endpoint, onError])
+  }, [request, onError])
 
   useEffect(() => {
     if (autoFetch) {
@@ -61,24 +50,3 @@ export function useApi<T>(
 
   return { data, loading, error, refetch: fetchData }
 }
-
-// POST/PUT/DELETE operations
-export async function apiRequest<T>(
-  endpoint: string,
-  options: RequestInit = {}
-): Promise<T> {
-  const response = await fetch(apiUrl(endpoint), {
-    ...options,
-    signal: AbortSignal.timeout(config.api.timeout),
-    headers: {
-      'Content-Type': 'application/json',
-      ...options.headers,
-    },
-  })
-
-  if (!response.ok) {
-    throw new Error(`HTTP error! status: ${response.status}`)
-  }
-
-  return response.json()
-}
\ No newline at end of file
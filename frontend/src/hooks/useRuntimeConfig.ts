@@ -1,5 +1,27 @@
 import { useEffect } from 'react'
-import { config, updateConfig, apiUrl } from '@/config'
+import { config, updateConfig, type Config } from '@/config'
+import { body, configApi } from '@/lib/api'
+
+// RuntimeConfig is the part of /api/v1/config/ui the dashboard applies
+interface RuntimeConfig {
+  refreshInterval?: number
+  aiInsightsInterval?: number
+  maxReconnectAttempts?: number
+  reconnectDelay?: number
+  reconnect?: {
+    maxAttempts?: number
+    initialDelay?: number
+    maxDelay?: number
+    multiplier?: number
+    jitter?: number
+  }
+  theme?: Config['ui']['theme']
+  features?: Partial<Config['features']>
+  readOnly?: boolean
+  capabilities?: Record<string, boolean>
+  websocket?: { topics?: string[] }
+  serverTime?: string
+}
 
 // This hook fetches runtime configuration from the server
 // and updates the local config with server-provided values
@@ -7,29 +29,39 @@ export function useRuntimeConfig() {
   useEffect(() => {
     const fetchRuntimeConfig = async () => {
       try {
-        const response = await fetch(apiUrl('/api/v1/config/ui'))
-        
-        if (!response.ok) {
+        const runtimeConfig = await configApi
+          .getUIConfigRaw()
+          .then(body<RuntimeConfig>)
+          .catch(() => null)
+
+        if (!runtimeConfig) {
           console.warn('Failed to fetch runtime config, using defaults')
           return
         }
         
-        const runtimeConfig = await response.json()
-        
         // Update the config with server values
         updateConfig({
           ui: {
             ...config.ui,
             refreshInterval: runtimeConfig.refreshInterval || config.ui.refreshInterval,
             aiInsightsInterval: runtimeConfig.aiInsightsInterval || config.ui.aiInsightsInterval,
-            maxReconnectAttempts: runtimeConfig.maxReconnectAttempts || config.ui.maxReconnectAttempts,
-            reconnectDelay: runtimeConfig.reconnectDelay || config.ui.reconnectDelay,
+            maxReconnectAttempts: runtimeConfig.reconnect?.maxAttempts || runtimeConfig.maxReconnectAttempts || config.ui.maxReconnectAttempts,
+            reconnectDelay: runtimeConfig.reconnect?.initialDelay || runtimeConfig.reconnectDelay || config.ui.reconnectDelay,
+            reconnectMaxDelay: runtimeConfig.reconnect?.maxDelay || config.ui.reconnectMaxDelay,
+            reconnectMultiplier: runtimeConfig.reconnect?.multiplier || config.ui.reconnectMultiplier,
+            reconnectJitter: runtimeConfig.reconnect?.jitter ?? config.ui.reconnectJitter,
             theme: runtimeConfig.theme || config.ui.theme,
           },
           features: {
             ...config.features,
             ...runtimeConfig.features,
           },
+          readOnly: runtimeConfig.readOnly === true,
+          capabilities: runtimeConfig.capabilities || config.capabilities,
+          wsTopics: runtimeConfig.websocket?.topics || config.wsTopics,
+          serverTimeOffset: runtimeConfig.serverTime
+            ? new Date(runtimeConfig.serverTime).getTime() - Date.now()
+            : config.serverTimeOffset,
         })
         
         console.log('Runtime configuration loaded', runtimeConfig)
@@ -1,5 +1,6 @@
 import { useCallback, useEffect, useRef, useState } from 'react'
-import { config, wsUrl } from '@/config'
+import { config, reconnectDelay, updateConfig, wsUrl, type Config } from '@/config'
+import type { Prediction } from '@/components/dashboard/HealthChecksTable'
 
 export interface DashboardData {
   status: "healthy" | "degraded" | "unhealthy" | "unknown"
@@ -7,6 +8,8 @@ export interface DashboardData {
   score?: {
     weighted: number
   }
+  // Revision of the cluster state; AI insights of an older one are stale
+  state_revision?: number
   checks: Array<{
     name: string
     status: "healthy" | "degraded" | "unhealthy"
@@ -16,9 +19,64 @@ export interface DashboardData {
       value: number
       unit?: string
     }>
+    predictions?: Prediction[]
   }>
 }
 
+// WebSocket protocol version understood by this client; see docs/websocket-protocol.md
+export const WS_PROTOCOL_VERSION = 1
+
+export type WSMessageType =
+  | "health.updated"
+  | "alert.fired"
+  | "alert.resolved"
+  | "context.switched"
+  | "ai.insight"
+  | "remediation.status"
+  | "features.changed"
+  | "check.result"
+  | "auth.ok"
+  | "subscribed"
+  | "error"
+
+// Topics a connection can subscribe to; check:<name> adds one check's messages
+export type WSTopic =
+  | "health"
+  | "alerts"
+  | "ai-insights"
+  | "context-changes"
+  | "remediation"
+  | "features"
+  | `check:${string}`
+
+// Message a client sends to change the topics it receives
+export interface WSSubscriptionRequest {
+  type: "subscribe" | "unsubscribe"
+  topics: WSTopic[]
+}
+
+export interface WSMessage<T = unknown> {
+  v: number
+  type: WSMessageType | string
+  ts: string
+  data: T
+}
+
+export interface ContextSwitchedData {
+  context: {
+    name: string
+    cluster_name: string
+    namespace?: string
+    current: boolean
+  }
+  previous?: string
+}
+
+export interface FeaturesChangedData {
+  features: Config['features']
+  capabilities: Record<string, boolean>
+}
+
 export function useWebSocket() {
   const [data, setData] = useState<DashboardData | null>(null)
   const [connectionStatus, setConnectionStatus] = useState<"connecting" | "connected" | "disconnected">("connecting")
@@ -35,22 +93,39 @@ export function useWebSocket() {
 
       ws.onopen = () => {
         console.log('WebSocket connected')
+        // Browsers can't set headers on the upgrade, so authenticate first
+        if (config.api.token) {
+          ws.send(JSON.stringify({ type: 'auth', token: config.api.token }))
+        }
         setConnectionStatus('connected')
         reconnectAttemptsRef.current = 0
       }
 
       ws.onmessage = (event) => {
         try {
-          const parsedData = JSON.parse(event.data)
-          
-          // Handle context switch events
-          if (parsedData.type === 'context_switched') {
-            console.log('Context switched:', parsedData.context)
-            // Clear current data to show loading state
-            setData(null)
-          } else {
-            // Regular health data update
-            setData(parsedData)
+          const message: WSMessage = JSON.parse(event.data)
+          if (message.v !== WS_PROTOCOL_VERSION) {
+            console.warn('Unsupported WebSocket protocol version:', message.v)
+            return
+          }
+
+          switch (message.type) {
+            case 'health.updated':
+              setData(message.data as DashboardData)
+              break
+            case 'features.changed': {
+              const changed = message.data as FeaturesChangedData
+              updateConfig({ features: changed.features, capabilities: changed.capabilities })
+              break
+            }
+            case 'context.switched':
+              console.log('Context switched:', (message.data as ContextSwitchedData).context)
+              // Clear current data to show loading state
+              setData(null)
+              break
+            default:
+              // Other message types are consumed by dedicated views; ignore unknown ones
+              break
           }
         } catch (error) {
           console.error('Failed to parse WebSocket data:', error)
@@ -80,7 +155,7 @@ export function useWebSocket() {
       
       reconnectTimeoutRef.current = setTimeout(() => {
         connect()
-      }, config.ui.reconnectDelay)
+      }, reconnectDelay(reconnectAttemptsRef.current))
     }
   }, [connect])
 
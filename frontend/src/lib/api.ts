@@ -0,0 +1,78 @@
+import {
+  AiApi,
+  AlertsApi,
+  ConfigApi,
+  Configuration,
+  ContextsApi,
+  HealthApi,
+  ResponseError,
+  type ApiResponse,
+  type Middleware,
+} from '@kubepulse/client'
+import { config, apiUrl } from '@/config'
+
+// The generated client is built from api/openapi.yaml by
+// scripts/generate-clients.sh; `npm run client` generates it when missing.
+
+const warnedDeprecations = new Set<string>()
+
+// warnIfDeprecated logs once per endpoint when the server marks it deprecated,
+// so the dashboard's use of a route is noticed before the route is removed
+function warnIfDeprecated(endpoint: string, response: Response) {
+  if (!response.headers.get('Deprecation') || warnedDeprecations.has(endpoint)) {
+    return
+  }
+  warnedDeprecations.add(endpoint)
+  const sunset = response.headers.get('Sunset')
+  const successor = response.headers.get('Link')?.match(/<([^>]+)>;\s*rel="successor-version"/)?.[1]
+  console.warn(
+    `KubePulse API ${endpoint} is deprecated` +
+      (sunset ? ` and may be removed after ${sunset}` : '') +
+      (successor ? `; use ${successor}` : '')
+  )
+}
+
+// dashboardMiddleware resolves paths against the configured base URL at
+// request time, so runtime config and ?ai_scenario= apply, bounds every
+// request by the API timeout and reports deprecated routes
+const dashboardMiddleware: Middleware = {
+  async pre({ url, init }) {
+    return {
+      url: apiUrl(`/api/v1${url}`),
+      init: { ...init, signal: AbortSignal.timeout(config.api.timeout) },
+    }
+  },
+  async post({ url, response }) {
+    warnIfDeprecated(new URL(url, window.location.href).pathname, response)
+    return response
+  },
+}
+
+const configuration = new Configuration({
+  basePath: '',
+  middleware: [dashboardMiddleware],
+  accessToken: async () => config.api.token ?? '',
+})
+
+export const aiApi = new AiApi(configuration)
+export const alertsApi = new AlertsApi(configuration)
+export const configApi = new ConfigApi(configuration)
+export const contextsApi = new ContextsApi(configuration)
+export const healthApi = new HealthApi(configuration)
+
+// body returns a response's JSON as the server sent it. Components keep the
+// wire shapes (snake_case fields, timestamps as strings) rather than the
+// generated models' camelCase ones.
+export function body<T>(response: ApiResponse<unknown>): Promise<T> {
+  return response.raw.json()
+}
+
+// errorMessage returns the server's message for a failed request, falling
+// back to the HTTP status
+export async function errorMessage(err: unknown, fallback: string): Promise<string> {
+  if (err instanceof ResponseError) {
+    const payload = await err.response.json().catch(() => null)
+    return payload?.message || `HTTP error! status: ${err.response.status}`
+  }
+  return err instanceof Error ? err.message : fallback
+}
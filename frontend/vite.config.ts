@@ -5,12 +5,21 @@ import { fileURLToPath } from 'node:url'
 
 const __dirname = path.dirname(fileURLToPath(import.meta.url))
 
+// The API client generated from api/openapi.yaml (npm run client)
+const clientDir = path.resolve(__dirname, "../clients/typescript")
+
 // https://vite.dev/config/
 export default defineConfig({
   plugins: [react()],
   resolve: {
     alias: {
       "@": path.resolve(__dirname, "./src"),
+      "@kubepulse/client": path.join(clientDir, "index.ts"),
+    },
+  },
+  server: {
+    fs: {
+      allow: [__dirname, clientDir],
     },
   },
 })
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AIProviders are the model providers AI analysis can use
+var AIProviders = []string{"claude-cli", "anthropic", "openai", "ollama"}
+
+// AIScenarios are the fixtures AI test mode can answer with
+var AIScenarios = []string{"healthy", "crashloop", "node-pressure", "etcd-degraded"}
+
+// AIConfig selects the model provider AI analysis uses. The Claude CLI is
+// the default; the anthropic and openai providers call their HTTP APIs, and
+// ollama or an OpenAI-compatible server such as vLLM keep inference inside
+// air-gapped clusters.
+type AIConfig struct {
+	Provider   string        `yaml:"provider,omitempty" mapstructure:"provider"`       // One of AIProviders; claude-cli when unset
+	Model      string        `yaml:"model,omitempty" mapstructure:"model"`             // Required by the API providers; the CLI's default when unset
+	ClaudePath string        `yaml:"claude_path,omitempty" mapstructure:"claude_path"` // CLI binary of the claude-cli provider
+	BaseURL    string        `yaml:"base_url,omitempty" mapstructure:"base_url"`       // API address; the provider's public or local default when unset
+	APIKeyEnv  string        `yaml:"api_key_env,omitempty" mapstructure:"api_key_env"` // Environment variable holding the API key; ANTHROPIC_API_KEY or OPENAI_API_KEY when unset
+	Timeout    time.Duration `yaml:"timeout,omitempty" mapstructure:"timeout"`         // Per analysis; 2m when unset
+	MaxTokens  int           `yaml:"max_tokens,omitempty" mapstructure:"max_tokens"`   // Longest answer of the API providers; 4096 when unset
+
+	// TestMode answers with canned fixtures instead of calling a provider,
+	// for frontend work, demos and integration tests. Requests can pick a
+	// fixture with the X-KubePulse-AI-Scenario header.
+	TestMode     bool   `yaml:"test_mode,omitempty" mapstructure:"test_mode"`
+	TestScenario string `yaml:"test_scenario,omitempty" mapstructure:"test_scenario"` // One of AIScenarios; suggested by each prompt when unset
+}
+
+// KeyEnv returns the environment variable holding the API key: api_key_env,
+// or the provider's usual one, or nothing for providers without keys
+func (a AIConfig) KeyEnv() string {
+	switch {
+	case a.APIKeyEnv != "":
+		return a.APIKeyEnv
+	case a.Provider == "anthropic":
+		return "ANTHROPIC_API_KEY"
+	case a.Provider == "openai":
+		return "OPENAI_API_KEY"
+	}
+	return ""
+}
+
+// validateAI checks the provider is known and has what it needs to run
+func validateAI(a AIConfig) error {
+	if a.Provider != "" && !slices.Contains(AIProviders, a.Provider) {
+		return fmt.Errorf("ai.provider must be one of %s, got %q", strings.Join(AIProviders, ", "), a.Provider)
+	}
+	if a.Provider != "" && a.Provider != "claude-cli" && a.Model == "" {
+		return fmt.Errorf("ai.model must be set for the %s provider", a.Provider)
+	}
+	if a.BaseURL != "" {
+		if u, err := url.Parse(a.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("ai.base_url must be an http or https URL")
+		}
+	}
+	if a.Timeout < 0 {
+		return fmt.Errorf("ai.timeout must not be negative")
+	}
+	if a.MaxTokens < 0 {
+		return fmt.Errorf("ai.max_tokens must not be negative")
+	}
+	if a.TestScenario != "" && !slices.Contains(AIScenarios, a.TestScenario) {
+		return fmt.Errorf("ai.test_scenario must be one of %s, got %q", strings.Join(AIScenarios, ", "), a.TestScenario)
+	}
+	if a.TestScenario != "" && !a.TestMode {
+		return fmt.Errorf("ai.test_scenario requires ai.test_mode")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import "fmt"
+
+// AIEvaluationConfig answers automatic diagnoses with two models side by
+// side, so their accuracy, speed and cost can be compared
+type AIEvaluationConfig struct {
+	Enabled    bool              `yaml:"enabled" mapstructure:"enabled"`
+	SampleRate float64           `yaml:"sample_rate,omitempty" mapstructure:"sample_rate"` // Share of automatic diagnoses evaluated; all when unset
+	Variants   []AIVariantConfig `yaml:"variants,omitempty" mapstructure:"variants"`       // Exactly two
+}
+
+// AIVariantConfig is a model taking part in an evaluation, by default from
+// the provider in ai. Prices apply when the provider doesn't report what a
+// run cost.
+type AIVariantConfig struct {
+	Name                 string  `yaml:"name" mapstructure:"name"`
+	Provider             string  `yaml:"provider,omitempty" mapstructure:"provider"`       // Another provider than ai.provider, with its own base_url and api_key_env
+	Model                string  `yaml:"model,omitempty" mapstructure:"model"`             // Empty uses the CLI's default
+	ClaudePath           string  `yaml:"claude_path,omitempty" mapstructure:"claude_path"` // Another CLI install, such as one set up for Bedrock or Vertex
+	BaseURL              string  `yaml:"base_url,omitempty" mapstructure:"base_url"`
+	APIKeyEnv            string  `yaml:"api_key_env,omitempty" mapstructure:"api_key_env"`
+	InputCostPerMTokens  float64 `yaml:"input_cost_per_mtok,omitempty" mapstructure:"input_cost_per_mtok"`
+	OutputCostPerMTokens float64 `yaml:"output_cost_per_mtok,omitempty" mapstructure:"output_cost_per_mtok"`
+}
+
+// validateAIEvaluation checks an enabled evaluation compares two distinct
+// named variants at non-negative prices
+func validateAIEvaluation(e AIEvaluationConfig) error {
+	if !e.Enabled {
+		return nil
+	}
+	if e.SampleRate < 0 || e.SampleRate > 1 {
+		return fmt.Errorf("ai_evaluation.sample_rate must be between 0 and 1")
+	}
+	if len(e.Variants) != 2 {
+		return fmt.Errorf("ai_evaluation.variants must list exactly two models")
+	}
+	for i, variant := range e.Variants {
+		if variant.Name == "" {
+			return fmt.Errorf("ai_evaluation.variants[%d].name must not be empty", i)
+		}
+		if variant.InputCostPerMTokens < 0 || variant.OutputCostPerMTokens < 0 {
+			return fmt.Errorf("ai_evaluation.variants[%d] costs must not be negative", i)
+		}
+		if variant.Provider != "" {
+			provider := AIConfig{Provider: variant.Provider, Model: variant.Model, BaseURL: variant.BaseURL}
+			if err := validateAI(provider); err != nil {
+				return fmt.Errorf("ai_evaluation.variants[%d]: %w", i, err)
+			}
+		}
+	}
+	if e.Variants[0].Name == e.Variants[1].Name {
+		return fmt.Errorf("ai_evaluation.variants[1].name %q is already used", e.Variants[1].Name)
+	}
+	a, b := e.Variants[0], e.Variants[1]
+	if a.Provider == b.Provider && a.Model == b.Model && a.ClaudePath == b.ClaudePath && a.BaseURL == b.BaseURL {
+		return fmt.Errorf("ai_evaluation.variants must differ in provider, model, claude_path or base_url")
+	}
+	return nil
+}
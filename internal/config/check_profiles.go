@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Built-in check profiles, from the lightest scan to the most thorough
+const (
+	// CheckProfileMinimal watches the control plane through node health
+	CheckProfileMinimal = "minimal"
+	// CheckProfileStandard adds workloads and the network path to them
+	CheckProfileStandard = "standard"
+	// CheckProfileDeep runs every built-in check, including event rates,
+	// Pod Security Standards compliance and node version skew
+	CheckProfileDeep = "deep"
+)
+
+// builtinCheckProfiles lists the checks of each built-in profile. Custom
+// resource checks run under every profile since each is configured
+// explicitly.
+var builtinCheckProfiles = map[string][]string{
+	CheckProfileMinimal:  {"node-health"},
+	CheckProfileStandard: {"node-health", "pod-health", "service-health", "ingress-health", "service-mesh"},
+	CheckProfileDeep:     {"node-health", "pod-health", "service-health", "ingress-health", "service-mesh", "event-rates", "pod-security", "node-versions"},
+}
+
+// CheckDiscoveryConfig runs checks of things a cluster may not have, such
+// as service-mesh or custom resource checks, only where the cluster
+// inventory shows it has them. Decisions are made again on every inventory
+// refresh and can be overridden per check.
+type CheckDiscoveryConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Overrides force checks of the selected profile on (true) or off
+	// (false) by name, whatever discovery decides
+	Overrides map[string]bool `yaml:"overrides,omitempty" mapstructure:"overrides"`
+}
+
+// BuiltinCheckProfiles returns the names of the built-in check profiles
+func BuiltinCheckProfiles() []string {
+	return []string{CheckProfileMinimal, CheckProfileStandard, CheckProfileDeep}
+}
+
+// CheckProfileFor returns the check profile for a kubeconfig context: its
+// entry in ClusterCheckProfiles, else CheckProfile
+func (m MonitoringConfig) CheckProfileFor(context string) string {
+	if profile, ok := m.ClusterCheckProfiles[context]; ok && context != "" {
+		return profile
+	}
+	return m.CheckProfile
+}
+
+// ProfileChecks returns the check names of a profile. Custom profiles take
+// precedence over built-in profiles of the same name.
+func (m MonitoringConfig) ProfileChecks(name string) ([]string, error) {
+	checks, ok := m.CheckProfiles[name]
+	if !ok {
+		checks, ok = builtinCheckProfiles[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown check profile %q (available: %s)", name, strings.Join(m.checkProfileNames(), ", "))
+	}
+	return append([]string(nil), checks...), nil
+}
+
+// checkProfileNames returns the built-in profiles, then the custom ones
+func (m MonitoringConfig) checkProfileNames() []string {
+	names := BuiltinCheckProfiles()
+	custom := make([]string, 0, len(m.CheckProfiles))
+	for name := range m.CheckProfiles {
+		if _, ok := builtinCheckProfiles[name]; !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// validateCheckProfiles checks custom profiles are non-empty and every
+// selected profile exists
+func validateCheckProfiles(m *MonitoringConfig) error {
+	for name, checks := range m.CheckProfiles {
+		if len(checks) == 0 {
+			return fmt.Errorf("monitoring.check_profiles.%s must list at least one check", name)
+		}
+		for _, check := range checks {
+			if strings.TrimSpace(check) == "" {
+				return fmt.Errorf("monitoring.check_profiles.%s must not contain empty check names", name)
+			}
+		}
+	}
+	if m.CheckProfile == "" {
+		m.CheckProfile = CheckProfileDeep
+	}
+	if _, err := m.ProfileChecks(m.CheckProfile); err != nil {
+		return fmt.Errorf("monitoring.check_profile: %w", err)
+	}
+	for context, profile := range m.ClusterCheckProfiles {
+		if _, err := m.ProfileChecks(profile); err != nil {
+			return fmt.Errorf("monitoring.cluster_check_profiles.%s: %w", context, err)
+		}
+	}
+	for check := range m.CheckDiscovery.Overrides {
+		if strings.TrimSpace(check) == "" {
+			return fmt.Errorf("monitoring.check_discovery.overrides must not contain empty check names")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMonitoringConfig_ProfileChecks(t *testing.T) {
+	monitoring := MonitoringConfig{
+		CheckProfile: CheckProfileStandard,
+		CheckProfiles: map[string][]string{
+			"edge":    {"node-health", "pod-health"},
+			"minimal": {"node-health", "event-rates"},
+		},
+		ClusterCheckProfiles: map[string]string{"prod-eu": CheckProfileDeep, "kind-dev": "edge"},
+	}
+
+	tests := []struct {
+		context string
+		want    []string
+	}{
+		{"prod-eu", []string{"node-health", "pod-health", "service-health", "ingress-health", "service-mesh", "event-rates", "pod-security", "node-versions"}},
+		{"kind-dev", []string{"node-health", "pod-health"}},
+		{"staging", []string{"node-health", "pod-health", "service-health", "ingress-health", "service-mesh"}},
+	}
+	for _, tt := range tests {
+		checks, err := monitoring.ProfileChecks(monitoring.CheckProfileFor(tt.context))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.context, err)
+		}
+		if !reflect.DeepEqual(checks, tt.want) {
+			t.Errorf("%s: got checks %v, want %v", tt.context, checks, tt.want)
+		}
+	}
+
+	// A custom profile replaces the built-in one of the same name
+	if checks, _ := monitoring.ProfileChecks(CheckProfileMinimal); !reflect.DeepEqual(checks, []string{"node-health", "event-rates"}) {
+		t.Errorf("expected the custom minimal profile, got %v", checks)
+	}
+	_, err := monitoring.ProfileChecks("huge")
+	if err == nil || !strings.Contains(err.Error(), "minimal, standard, deep, edge") {
+		t.Errorf("expected the available profiles listed, got %v", err)
+	}
+}
+
+func TestConfigValidation_CheckProfiles(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.CheckProfile = ""
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Monitoring.CheckProfile != CheckProfileDeep {
+		t.Errorf("expected the deep profile by default, got %q", config.Monitoring.CheckProfile)
+	}
+	if !config.Monitoring.CheckDiscovery.Enabled {
+		t.Error("expected check discovery on by default")
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*MonitoringConfig)
+		key    string
+	}{
+		{"unknown profile", func(m *MonitoringConfig) { m.CheckProfile = "huge" }, "monitoring.check_profile"},
+		{"empty custom profile", func(m *MonitoringConfig) { m.CheckProfiles = map[string][]string{"edge": {}} }, "monitoring.check_profiles.edge"},
+		{"unknown cluster profile", func(m *MonitoringConfig) { m.ClusterCheckProfiles = map[string]string{"prod": "huge"} }, "monitoring.cluster_check_profiles.prod"},
+		{"empty discovery override", func(m *MonitoringConfig) { m.CheckDiscovery.Overrides = map[string]bool{" ": true} }, "monitoring.check_discovery.overrides"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Monitoring)
+			if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ClusterConfig connects to a cluster by API server URL and credentials,
+// without a kubeconfig, for containers where mounting one is awkward. The
+// cluster is available as a context of its name.
+type ClusterConfig struct {
+	Name      string `yaml:"name" mapstructure:"name"`
+	Server    string `yaml:"server" mapstructure:"server"` // API server URL
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace"`
+
+	// One of a bearer token, a file holding one, reread as it rotates, or a
+	// credential plugin such as aws eks get-token
+	Token     string          `yaml:"token,omitempty" mapstructure:"token"`
+	TokenFile string          `yaml:"token_file,omitempty" mapstructure:"token_file"`
+	Exec      *ExecAuthConfig `yaml:"exec,omitempty" mapstructure:"exec"`
+
+	CAFile                string `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
+	CAData                string `yaml:"ca_data,omitempty" mapstructure:"ca_data"` // PEM, or base64 of it
+	InsecureSkipTLSVerify bool   `yaml:"insecure_skip_tls_verify,omitempty" mapstructure:"insecure_skip_tls_verify"`
+}
+
+// ExecAuthConfig runs a credential plugin for a token
+type ExecAuthConfig struct {
+	Command    string            `yaml:"command" mapstructure:"command"`
+	Args       []string          `yaml:"args,omitempty" mapstructure:"args"`
+	Env        map[string]string `yaml:"env,omitempty" mapstructure:"env"`
+	APIVersion string            `yaml:"api_version,omitempty" mapstructure:"api_version"` // client.authentication.k8s.io/v1beta1 when empty
+}
+
+// validateClusters checks the clusters have unique names, an http(s)
+// server and one way to authenticate
+func validateClusters(clusters []ClusterConfig) error {
+	names := make(map[string]bool, len(clusters))
+	for i, cluster := range clusters {
+		if strings.TrimSpace(cluster.Name) == "" {
+			return fmt.Errorf("kubernetes.clusters[%d].name must not be empty", i)
+		}
+		if names[cluster.Name] {
+			return fmt.Errorf("kubernetes.clusters[%d].name %q is already used", i, cluster.Name)
+		}
+		names[cluster.Name] = true
+		if parsed, err := url.Parse(cluster.Server); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("kubernetes.clusters[%d].server must be an http(s) URL", i)
+		}
+		methods := 0
+		for _, set := range []bool{cluster.Token != "", cluster.TokenFile != "", cluster.Exec != nil} {
+			if set {
+				methods++
+			}
+		}
+		if methods != 1 {
+			return fmt.Errorf("kubernetes.clusters[%d] needs exactly one of token, token_file or exec", i)
+		}
+		if cluster.Exec != nil && cluster.Exec.Command == "" {
+			return fmt.Errorf("kubernetes.clusters[%d].exec.command must not be empty", i)
+		}
+		if data := strings.TrimSpace(cluster.CAData); data != "" && !strings.HasPrefix(data, "-----BEGIN") {
+			if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+				return fmt.Errorf("kubernetes.clusters[%d].ca_data must be PEM or base64-encoded PEM", i)
+			}
+		}
+	}
+	return nil
+}
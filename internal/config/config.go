@@ -2,14 +2,19 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
+// checkNamePattern matches names of health checks defined in configuration
+var checkNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
 // Config represents the application configuration
 type Config struct {
 	// Kubernetes settings
@@ -24,6 +29,18 @@ type Config struct {
 	// SLO definitions
 	SLOs map[string]SLOConfig `yaml:"slos" mapstructure:"slos"`
 
+	// Conditions on application metrics pushed to /api/v1/metrics/ingest
+	MetricConditions []MetricConditionConfig `yaml:"metric_conditions" mapstructure:"metric_conditions"`
+
+	// Health checks on the status conditions of operator-managed resources
+	CustomResources []CustomResourceConfig `yaml:"custom_resources" mapstructure:"custom_resources"`
+
+	// Health checks on metrics served by custom and external metrics adapters
+	CustomMetrics []CustomMetricConfig `yaml:"custom_metrics" mapstructure:"custom_metrics"`
+
+	// Health checks run by executables in a plugin directory
+	Plugins PluginsConfig `yaml:"plugins" mapstructure:"plugins"`
+
 	// ML settings
 	ML MLConfig `yaml:"ml" mapstructure:"ml"`
 
@@ -32,6 +49,52 @@ type Config struct {
 
 	// UI settings
 	UI UIConfig `yaml:"ui" mapstructure:"ui"`
+
+	// Release update checks
+	Updates UpdatesConfig `yaml:"updates" mapstructure:"updates"`
+
+	// Scheduled backups of configuration and learned state
+	Backup BackupConfig `yaml:"backup" mapstructure:"backup"`
+
+	// Opt-in anonymized usage reporting
+	Telemetry TelemetryConfig `yaml:"telemetry" mapstructure:"telemetry"`
+
+	// Public read-only status page served on its own port
+	StatusPage StatusPageConfig `yaml:"status_page" mapstructure:"status_page"`
+
+	// Goroutine and heap dumps captured when KubePulse itself misbehaves
+	Diagnostics DiagnosticsConfig `yaml:"diagnostics" mapstructure:"diagnostics"`
+
+	// Degraded operation when KubePulse nears its memory or CPU limits
+	LoadShedding LoadSheddingConfig `yaml:"load_shedding" mapstructure:"load_shedding"`
+
+	// Detection of running Chaos Mesh and LitmusChaos experiments
+	Chaos ChaosConfig `yaml:"chaos" mapstructure:"chaos"`
+
+	// Model provider of AI analysis
+	AI AIConfig `yaml:"ai,omitempty" mapstructure:"ai"`
+
+	// Side-by-side evaluation of two AI models on the same diagnoses
+	AIEvaluation AIEvaluationConfig `yaml:"ai_evaluation,omitempty" mapstructure:"ai_evaluation"`
+
+	// AI analyses requested from, or served to, other KubePulse instances
+	Federation FederationConfig `yaml:"federation" mapstructure:"federation"`
+
+	// Classification of kubeconfig contexts as prod, staging or dev, which
+	// shapes alert severity, routing, noise and AI analysis
+	Environments EnvironmentsConfig `yaml:"environments,omitempty" mapstructure:"environments"`
+
+	// Webhooks posting check results to external automation when a check's
+	// status changes
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty" mapstructure:"webhooks"`
+
+	// ReadOnly disables every capability that changes the cluster or
+	// KubePulse state, for observation-only deployments
+	ReadOnly bool `yaml:"read_only" mapstructure:"read_only"`
+
+	// Profile is the named profile in effect. In a file it selects the
+	// profile used when neither --profile nor KUBEPULSE_PROFILE is set.
+	Profile string `yaml:"profile,omitempty" mapstructure:"profile"`
 }
 
 // KubernetesConfig holds Kubernetes-related configuration
@@ -39,6 +102,9 @@ type KubernetesConfig struct {
 	Kubeconfig string   `yaml:"kubeconfig" mapstructure:"kubeconfig"`
 	Context    string   `yaml:"context" mapstructure:"context"`
 	Namespaces []string `yaml:"namespaces" mapstructure:"namespaces"`
+
+	// Clusters reached without a kubeconfig, as extra contexts
+	Clusters []ClusterConfig `yaml:"clusters,omitempty" mapstructure:"clusters"`
 }
 
 // MonitoringConfig holds monitoring-related configuration
@@ -47,6 +113,132 @@ type MonitoringConfig struct {
 	EnabledChecks []string      `yaml:"enabled_checks" mapstructure:"enabled_checks"`
 	MaxHistory    int           `yaml:"max_history" mapstructure:"max_history"`
 	Timeout       time.Duration `yaml:"timeout" mapstructure:"timeout"`
+
+	// WatchdogMultiplier abandons checks still running after this many timeouts
+	WatchdogMultiplier float64 `yaml:"watchdog_multiplier" mapstructure:"watchdog_multiplier"`
+
+	// RecordChecks keeps the API objects each check run read so it can be replayed
+	RecordChecks bool `yaml:"record_checks" mapstructure:"record_checks"`
+
+	// Runbooks maps check names to the runbook URL linked from their alerts
+	Runbooks map[string]string `yaml:"runbooks,omitempty" mapstructure:"runbooks"`
+
+	// HistoryFile persists check results for time-travel inspection across
+	// restarts; empty keeps them in memory only
+	HistoryFile string `yaml:"history_file,omitempty" mapstructure:"history_file"`
+	// HistoryRetention is how far back check results can be inspected
+	HistoryRetention time.Duration `yaml:"history_retention" mapstructure:"history_retention"`
+
+	// FindingsFile persists the lifecycle of findings across restarts;
+	// empty keeps them in memory only
+	FindingsFile string `yaml:"findings_file,omitempty" mapstructure:"findings_file"`
+
+	// CheckProfile selects the health checks serve runs: minimal, standard,
+	// deep or a profile from CheckProfiles
+	CheckProfile string `yaml:"check_profile" mapstructure:"check_profile"`
+	// CheckProfiles defines custom check profiles as lists of check names
+	CheckProfiles map[string][]string `yaml:"check_profiles,omitempty" mapstructure:"check_profiles"`
+	// ClusterCheckProfiles selects a check profile per kubeconfig context,
+	// taking precedence over CheckProfile
+	ClusterCheckProfiles map[string]string `yaml:"cluster_check_profiles,omitempty" mapstructure:"cluster_check_profiles"`
+	// CheckDiscovery runs checks only in clusters that have what they
+	// monitor
+	CheckDiscovery CheckDiscoveryConfig `yaml:"check_discovery" mapstructure:"check_discovery"`
+
+	// ExpensiveChecks run every ExpensiveInterval instead of every Interval
+	ExpensiveChecks   []string      `yaml:"expensive_checks,omitempty" mapstructure:"expensive_checks"`
+	ExpensiveInterval time.Duration `yaml:"expensive_interval" mapstructure:"expensive_interval"`
+
+	// InventoryInterval is how often the cluster inventory of nodes,
+	// workloads, custom resources and component versions is refreshed
+	InventoryInterval time.Duration `yaml:"inventory_interval" mapstructure:"inventory_interval"`
+
+	// PermissionRecheckInterval is how often checks disabled because the
+	// API server refused them access are retried
+	PermissionRecheckInterval time.Duration `yaml:"permission_recheck_interval" mapstructure:"permission_recheck_interval"`
+
+	// NativeTools answers investigation steps that get pods, nodes,
+	// deployments or events from informer caches instead of kubectl
+	NativeTools bool `yaml:"native_tools" mapstructure:"native_tools"`
+
+	// AdaptiveInterval shortens the interval of failing checks and lengthens
+	// it for long-healthy ones
+	AdaptiveInterval AdaptiveIntervalConfig `yaml:"adaptive_interval" mapstructure:"adaptive_interval"`
+
+	// PodSecurity sets the Pod Security Standards level the pod-security
+	// check holds each namespace to
+	PodSecurity PodSecurityConfig `yaml:"pod_security" mapstructure:"pod_security"`
+
+	// Cardinality bounds the series checks and ingested metrics can create
+	Cardinality CardinalityConfig `yaml:"cardinality" mapstructure:"cardinality"`
+
+	// LatencyBudget alerts when KubePulse takes too long to notice
+	// conditions in the cluster
+	LatencyBudget LatencyBudgetConfig `yaml:"latency_budget" mapstructure:"latency_budget"`
+
+	// Ownership maps a cluster's own label and annotation keys to the team,
+	// app and tier of workloads, before the common conventions
+	Ownership OwnershipConfig `yaml:"ownership,omitempty" mapstructure:"ownership"`
+}
+
+// OwnershipConfig lists, per ownership field, the label or annotation keys
+// to read it from, such as acme.com/squad for team
+type OwnershipConfig struct {
+	Team []string `yaml:"team,omitempty" mapstructure:"team"`
+	App  []string `yaml:"app,omitempty" mapstructure:"app"`
+	Tier []string `yaml:"tier,omitempty" mapstructure:"tier"`
+}
+
+// LatencyBudgetConfig bounds detection latency: the time from a condition
+// beginning in the cluster, per its event or status timestamps, to the
+// check result reporting it. When the percentile of recent detections
+// exceeds detection, the pipeline-latency check degrades and alerts.
+type LatencyBudgetConfig struct {
+	Detection  time.Duration `yaml:"detection" mapstructure:"detection"`   // Zero only measures latency
+	Percentile float64       `yaml:"percentile" mapstructure:"percentile"` // Of detection latency, 1-100
+}
+
+// AdaptiveIntervalConfig bounds adaptive check intervals. Failing checks,
+// and all checks while an SLO burns its error budget, run every
+// min_interval; checks healthy for healthy_after back off toward
+// max_interval.
+type AdaptiveIntervalConfig struct {
+	Enabled      bool          `yaml:"enabled" mapstructure:"enabled"`
+	MinInterval  time.Duration `yaml:"min_interval" mapstructure:"min_interval"`
+	MaxInterval  time.Duration `yaml:"max_interval" mapstructure:"max_interval"`
+	HealthyAfter time.Duration `yaml:"healthy_after" mapstructure:"healthy_after"`
+}
+
+// PodSecurityConfig sets Pod Security Standards levels: privileged,
+// baseline or restricted. Namespaces override a namespace's
+// pod-security.kubernetes.io/enforce label, which overrides DefaultLevel.
+type PodSecurityConfig struct {
+	DefaultLevel string            `yaml:"default_level" mapstructure:"default_level"`
+	Namespaces   map[string]string `yaml:"namespaces,omitempty" mapstructure:"namespaces"`
+}
+
+// podSecurityLevels are the Pod Security Standards levels
+var podSecurityLevels = map[string]bool{"privileged": true, "baseline": true, "restricted": true}
+
+// CardinalityConfig limits metric label cardinality. Each label of a metric
+// keeps its first max_label_values values; later values are hashed into
+// hash_buckets buckets or the label is dropped, per action. New series
+// beyond max_series per metric are dropped.
+type CardinalityConfig struct {
+	MaxSeries      int    `yaml:"max_series" mapstructure:"max_series"`
+	MaxLabelValues int    `yaml:"max_label_values" mapstructure:"max_label_values"`
+	Action         string `yaml:"action" mapstructure:"action"` // hash or drop
+	HashBuckets    int    `yaml:"hash_buckets" mapstructure:"hash_buckets"`
+
+	// Overrides sets different limits for individual metrics by name
+	Overrides map[string]CardinalityLimitConfig `yaml:"overrides,omitempty" mapstructure:"overrides"`
+}
+
+// CardinalityLimitConfig overrides the cardinality limits of one metric;
+// zero fields keep the global limit
+type CardinalityLimitConfig struct {
+	MaxSeries      int `yaml:"max_series" mapstructure:"max_series"`
+	MaxLabelValues int `yaml:"max_label_values" mapstructure:"max_label_values"`
 }
 
 // AlertsConfig holds alert-related configuration
@@ -54,22 +246,119 @@ type AlertsConfig struct {
 	Enabled  bool                       `yaml:"enabled" mapstructure:"enabled"`
 	Channels map[string]ChannelConfig   `yaml:"channels" mapstructure:"channels"`
 	Rules    map[string]AlertRuleConfig `yaml:"rules" mapstructure:"rules"`
+
+	// Escalations notify channels in turn until an alert is acknowledged
+	Escalations map[string]EscalationConfig `yaml:"escalations,omitempty" mapstructure:"escalations"`
+
+	// Delivery retries notifications channels fail to accept
+	Delivery DeliveryConfig `yaml:"delivery" mapstructure:"delivery"`
+
+	// ExternalURL is where responders reach the dashboard and API, such as
+	// https://kubepulse.example.com; notifications then link to silencing
+	// their alert. Empty omits the links.
+	ExternalURL string `yaml:"external_url,omitempty" mapstructure:"external_url"`
+
+	// Alertmanager syncs silences both ways with an Alertmanager instance
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager,omitempty" mapstructure:"alertmanager"`
+
+	// Routes send the alerts of a team, app or tier to a channel instead of
+	// their rule's; the first matching route wins
+	Routes []AlertRouteConfig `yaml:"routes,omitempty" mapstructure:"routes"`
+}
+
+// AlertRouteConfig routes alerts whose workloads have an owner with every
+// field set to channel
+type AlertRouteConfig struct {
+	Team    string `yaml:"team,omitempty" mapstructure:"team"`
+	App     string `yaml:"app,omitempty" mapstructure:"app"`
+	Tier    string `yaml:"tier,omitempty" mapstructure:"tier"`
+	Channel string `yaml:"channel" mapstructure:"channel"`
+}
+
+// AlertmanagerConfig syncs silences with Alertmanager, so maintenance
+// silences created in either are respected by both. An empty URL disables it.
+type AlertmanagerConfig struct {
+	URL      string        `yaml:"url,omitempty" mapstructure:"url"` // e.g. http://alertmanager:9093
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
+// DeliveryConfig queues notifications a channel rejects and retries them
+// with exponential backoff
+type DeliveryConfig struct {
+	// QueueFile keeps queued notifications across restarts; empty keeps
+	// them in memory only
+	QueueFile      string        `yaml:"queue_file,omitempty" mapstructure:"queue_file"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" mapstructure:"max_backoff"`
+	MaxAttempts    int           `yaml:"max_attempts" mapstructure:"max_attempts"` // Then dead-lettered
 }
 
 // ChannelConfig represents a notification channel configuration
 type ChannelConfig struct {
-	Type     string                 `yaml:"type" mapstructure:"type"`
-	Enabled  bool                   `yaml:"enabled" mapstructure:"enabled"`
-	Settings map[string]interface{} `yaml:"settings" mapstructure:"settings"`
+	Type       string                 `yaml:"type" mapstructure:"type"`
+	Enabled    bool                   `yaml:"enabled" mapstructure:"enabled"`
+	Settings   map[string]interface{} `yaml:"settings" mapstructure:"settings"`
+	QuietHours *QuietHoursConfig      `yaml:"quiet_hours,omitempty" mapstructure:"quiet_hours"`
+}
+
+// QuietHoursConfig holds back a channel's alerts during a daily window
+type QuietHoursConfig struct {
+	Start    string `yaml:"start" mapstructure:"start"` // "22:00"
+	End      string `yaml:"end" mapstructure:"end"`     // "07:00"
+	Timezone string `yaml:"timezone,omitempty" mapstructure:"timezone"`
+	Bypass   string `yaml:"bypass,omitempty" mapstructure:"bypass"` // Lowest severity still sent; critical by default
 }
 
-// AlertRuleConfig represents an alert rule configuration
+// EscalationConfig represents an escalation policy
+type EscalationConfig struct {
+	Severities []string               `yaml:"severities" mapstructure:"severities"` // Alert severities routed through the policy
+	Steps      []EscalationStepConfig `yaml:"steps" mapstructure:"steps"`
+}
+
+// EscalationStepConfig notifies a channel once an alert is unacknowledged for After
+type EscalationStepConfig struct {
+	Channel string        `yaml:"channel" mapstructure:"channel"`
+	After   time.Duration `yaml:"after" mapstructure:"after"`
+}
+
+// AlertRuleConfig represents an alert rule configuration. A rule with a
+// reason fires on that event's rate from the event-rates check; otherwise it
+// fires when check reports status (degraded or unhealthy when unset).
 type AlertRuleConfig struct {
-	Condition string        `yaml:"condition" mapstructure:"condition"`
+	Condition string        `yaml:"condition,omitempty" mapstructure:"condition"` // Free-text description, not evaluated
+	Check     string        `yaml:"check,omitempty" mapstructure:"check"`
+	Status    string        `yaml:"status,omitempty" mapstructure:"status"`
+	Reason    string        `yaml:"reason,omitempty" mapstructure:"reason"`
+	Threshold float64       `yaml:"threshold,omitempty" mapstructure:"threshold"`
 	Severity  string        `yaml:"severity" mapstructure:"severity"`
 	Cooldown  time.Duration `yaml:"cooldown" mapstructure:"cooldown"`
-	Channels  []string      `yaml:"channels" mapstructure:"channels"`
+	Channel   string        `yaml:"channel,omitempty" mapstructure:"channel"`
+	Channels  []string      `yaml:"channels,omitempty" mapstructure:"channels"` // The first is used when channel is unset
 	Template  string        `yaml:"template" mapstructure:"template"`
+
+	Escalation string `yaml:"escalation,omitempty" mapstructure:"escalation"`
+	Runbook    string `yaml:"runbook,omitempty" mapstructure:"runbook"`
+
+	// Tests are sample check results and whether the rule should fire on
+	// them; the server refuses to start when one fails
+	Tests []AlertRuleTestConfig `yaml:"tests,omitempty" mapstructure:"tests"`
+}
+
+// AlertRuleTestConfig is a sample check result an alert rule is tested
+// against and whether the rule should fire on it
+type AlertRuleTestConfig struct {
+	Name   string               `yaml:"name" mapstructure:"name"`
+	Result AlertRuleTestFixture `yaml:"result" mapstructure:"result"`
+	Fire   bool                 `yaml:"fire" mapstructure:"fire"`
+}
+
+// AlertRuleTestFixture is a check result in an alert rule test. The check
+// defaults to the rule's check.
+type AlertRuleTestFixture struct {
+	Check   string                 `yaml:"check,omitempty" mapstructure:"check"`
+	Status  string                 `yaml:"status" mapstructure:"status"`
+	Message string                 `yaml:"message,omitempty" mapstructure:"message"`
+	Details map[string]interface{} `yaml:"details,omitempty" mapstructure:"details"`
 }
 
 // SLOConfig represents an SLO configuration
@@ -79,6 +368,90 @@ type SLOConfig struct {
 	Target       float64              `yaml:"target" mapstructure:"target"`
 	Window       time.Duration        `yaml:"window" mapstructure:"window"`
 	BudgetPolicy []BudgetPolicyConfig `yaml:"budget_policy" mapstructure:"budget_policy"`
+
+	// Metrics and Labels select the metrics of checks and ingested ones the
+	// SLI is computed from; without Metrics the SLI's standard metric names
+	// are used
+	Metrics []string          `yaml:"metrics,omitempty" mapstructure:"metrics"`
+	Labels  map[string]string `yaml:"labels,omitempty" mapstructure:"labels"`
+
+	// Check makes the SLI the share of the check's runs that weren't
+	// unhealthy; sli must then be availability or unset
+	Check string `yaml:"check,omitempty" mapstructure:"check"`
+
+	// BurnRateAlerts of availability, error_rate and check SLIs; empty
+	// selects the multi-window alerts of the Google SRE workbook, scaled to
+	// the window
+	BurnRateAlerts []BurnRateAlertConfig `yaml:"burn_rate_alerts,omitempty" mapstructure:"burn_rate_alerts"`
+}
+
+// BurnRateAlertConfig fires an alert while an SLO's error budget burns more
+// than burn_rate times faster than its window allows, over both windows
+type BurnRateAlertConfig struct {
+	LongWindow  time.Duration `yaml:"long_window" mapstructure:"long_window"`
+	ShortWindow time.Duration `yaml:"short_window" mapstructure:"short_window"`
+	BurnRate    float64       `yaml:"burn_rate" mapstructure:"burn_rate"`
+	Severity    string        `yaml:"severity" mapstructure:"severity"` // critical or warning
+}
+
+// MetricConditionConfig sets the status of an ingest source while one of its
+// metrics crosses a threshold
+type MetricConditionConfig struct {
+	Name      string            `yaml:"name" mapstructure:"name"`
+	Source    string            `yaml:"source,omitempty" mapstructure:"source"` // Empty applies to every source
+	Metric    string            `yaml:"metric" mapstructure:"metric"`
+	Labels    map[string]string `yaml:"labels,omitempty" mapstructure:"labels"`
+	Operator  string            `yaml:"operator" mapstructure:"operator"` // >, >=, < or <=
+	Threshold float64           `yaml:"threshold" mapstructure:"threshold"`
+	Status    string            `yaml:"status" mapstructure:"status"` // degraded or unhealthy
+}
+
+// CustomResourceConfig adds a health check evaluating the status.conditions
+// of a custom resource kind
+type CustomResourceConfig struct {
+	Name      string `yaml:"name" mapstructure:"name"` // Check name
+	Group     string `yaml:"group" mapstructure:"group"`
+	Version   string `yaml:"version" mapstructure:"version"`
+	Kind      string `yaml:"kind" mapstructure:"kind"`
+	Resource  string `yaml:"resource,omitempty" mapstructure:"resource"`   // Plural; defaults to the lowercase kind plus "s"
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace"` // Empty checks every namespace
+
+	// Conditions default to Ready being True, unhealthy otherwise
+	Conditions []CustomResourceConditionConfig `yaml:"conditions,omitempty" mapstructure:"conditions"`
+}
+
+// CustomResourceConditionConfig maps a status condition to the health it
+// warrants when it doesn't have the expected status
+type CustomResourceConditionConfig struct {
+	Type   string `yaml:"type" mapstructure:"type"`
+	Status string `yaml:"status,omitempty" mapstructure:"status"` // Expected status; defaults to True
+	Health string `yaml:"health,omitempty" mapstructure:"health"` // degraded or unhealthy (default)
+}
+
+// PluralResource returns the configured resource, or the lowercase kind plus
+// "s" as most CRDs name it
+func (c CustomResourceConfig) PluralResource() string {
+	if c.Resource != "" {
+		return c.Resource
+	}
+	return strings.ToLower(c.Kind) + "s"
+}
+
+// CustomMetricConfig adds a health check reading a metric from the
+// custom.metrics.k8s.io or external.metrics.k8s.io API
+type CustomMetricConfig struct {
+	Name      string `yaml:"name" mapstructure:"name"`         // Check name
+	API       string `yaml:"api,omitempty" mapstructure:"api"` // custom (default) or external
+	Metric    string `yaml:"metric" mapstructure:"metric"`
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace"` // Empty reads cluster-scoped objects, or "default" for external metrics
+	Resource  string `yaml:"resource,omitempty" mapstructure:"resource"`   // Custom metrics: the described objects, e.g. pods
+	Object    string `yaml:"object,omitempty" mapstructure:"object"`       // Custom metrics: one object; empty reads them all
+	Selector  string `yaml:"selector,omitempty" mapstructure:"selector"`   // Label selector
+
+	// Optional threshold failing the check while a value crosses it
+	Operator  string  `yaml:"operator,omitempty" mapstructure:"operator"` // >, >=, < or <=
+	Threshold float64 `yaml:"threshold,omitempty" mapstructure:"threshold"`
+	Status    string  `yaml:"status,omitempty" mapstructure:"status"` // degraded or unhealthy (default)
 }
 
 // BudgetPolicyConfig represents error budget policy configuration
@@ -105,6 +478,39 @@ type ServerConfig struct {
 	CORSOrigins  []string      `yaml:"cors_origins" mapstructure:"cors_origins"`
 	ReadTimeout  time.Duration `yaml:"read_timeout" mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout" mapstructure:"write_timeout"`
+	Auth         AuthConfig    `yaml:"auth" mapstructure:"auth"`
+
+	// MaxConcurrentAnalyses bounds the on-demand cluster and batch AI
+	// analyses running at once; more are queued
+	MaxConcurrentAnalyses int `yaml:"max_concurrent_analyses" mapstructure:"max_concurrent_analyses"`
+
+	// AnalysisWait is how long analysis requests wait for their result
+	// before answering 202 Accepted; defaults to half of write_timeout
+	AnalysisWait time.Duration `yaml:"analysis_wait" mapstructure:"analysis_wait"`
+}
+
+// AuthConfig lists the tokens API clients authenticate with. Without tokens,
+// WebSocket clients connect anonymously and receive every message.
+type AuthConfig struct {
+	Tokens []AuthTokenConfig `yaml:"tokens" mapstructure:"tokens"`
+
+	// TokensFile persists the tokens admins create through the API; without
+	// it they last until the server restarts
+	TokensFile string `yaml:"tokens_file,omitempty" mapstructure:"tokens_file"`
+
+	// RotationReminder is the token age at which rotation is due; tokens are
+	// also due a week before they expire
+	RotationReminder time.Duration `yaml:"rotation_reminder" mapstructure:"rotation_reminder"`
+}
+
+// AuthTokenConfig is a bearer token and the identity it grants
+type AuthTokenConfig struct {
+	Name      string    `yaml:"name" mapstructure:"name"`
+	Token     string    `yaml:"token" mapstructure:"token"`
+	Role      string    `yaml:"role" mapstructure:"role"`                                 // viewer or admin
+	Scope     string    `yaml:"scope,omitempty" mapstructure:"scope"`                     // Empty for all routes, or alerts
+	Cluster   string    `yaml:"cluster,omitempty" mapstructure:"cluster"`                 // Limits the token to one cluster
+	ExpiresAt time.Time `yaml:"expires_at,omitempty" mapstructure:"expires_at,omitempty"` // Zero never expires
 }
 
 // UIConfig holds UI-related configuration
@@ -112,7 +518,10 @@ type UIConfig struct {
 	RefreshInterval      time.Duration `yaml:"refresh_interval" mapstructure:"refresh_interval"`
 	AIInsightsInterval   time.Duration `yaml:"ai_insights_interval" mapstructure:"ai_insights_interval"`
 	MaxReconnectAttempts int           `yaml:"max_reconnect_attempts" mapstructure:"max_reconnect_attempts"`
-	ReconnectDelay       time.Duration `yaml:"reconnect_delay" mapstructure:"reconnect_delay"`
+	ReconnectDelay       time.Duration `yaml:"reconnect_delay" mapstructure:"reconnect_delay"`         // First reconnect delay
+	ReconnectMaxDelay    time.Duration `yaml:"reconnect_max_delay" mapstructure:"reconnect_max_delay"` // Cap on the backed-off delay
+	ReconnectMultiplier  float64       `yaml:"reconnect_multiplier" mapstructure:"reconnect_multiplier"`
+	ReconnectJitter      float64       `yaml:"reconnect_jitter" mapstructure:"reconnect_jitter"` // Fraction of each delay randomized, 0-1
 	Theme                string        `yaml:"theme" mapstructure:"theme"`
 	Features             UIFeatures    `yaml:"features" mapstructure:"features"`
 }
@@ -125,10 +534,157 @@ type UIFeatures struct {
 	NodeDetails         bool `yaml:"node_details" mapstructure:"node_details"`
 }
 
+// UpdatesConfig controls checking GitHub for newer KubePulse releases
+type UpdatesConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled"`
+	Repository    string        `yaml:"repository" mapstructure:"repository"`
+	CheckInterval time.Duration `yaml:"check_interval" mapstructure:"check_interval"`
+}
+
+// TelemetryConfig controls opt-in reporting of anonymized KubePulse usage.
+// It is off unless enabled, and DO_NOT_TRACK=1 turns it off regardless.
+type TelemetryConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Endpoint string        `yaml:"endpoint" mapstructure:"endpoint"` // Where reports are posted
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
+// DiagnosticsConfig controls self-diagnostics. Goroutine counts and check
+// cycle durations are sampled every monitoring interval; a sample above
+// growth_factor times its running baseline, and above its floor, captures
+// a goroutine and heap dump.
+type DiagnosticsConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled"`
+	DumpDir       string        `yaml:"dump_dir" mapstructure:"dump_dir"`
+	MaxDumps      int           `yaml:"max_dumps" mapstructure:"max_dumps"` // Older dumps are deleted
+	GrowthFactor  float64       `yaml:"growth_factor" mapstructure:"growth_factor"`
+	MinGoroutines int           `yaml:"min_goroutines" mapstructure:"min_goroutines"`
+	MinCycle      time.Duration `yaml:"min_cycle" mapstructure:"min_cycle"`
+	Cooldown      time.Duration `yaml:"cooldown" mapstructure:"cooldown"` // Between automatic dumps
+}
+
+// LoadSheddingConfig controls load shedding. KubePulse's memory and CPU use
+// are sampled every monitoring interval against the limits of its cgroup;
+// when either reaches its threshold, checks run interval_factor times less
+// often, failures aren't queued for AI analysis and less history is kept
+// until both are back below 90% of their thresholds.
+type LoadSheddingConfig struct {
+	Enabled         bool    `yaml:"enabled" mapstructure:"enabled"`
+	MemoryThreshold float64 `yaml:"memory_threshold" mapstructure:"memory_threshold"` // Share of the memory limit
+	CPUThreshold    float64 `yaml:"cpu_threshold" mapstructure:"cpu_threshold"`       // Share of the CPU limit
+	IntervalFactor  int     `yaml:"interval_factor" mapstructure:"interval_factor"`
+}
+
+// ChaosConfig controls chaos experiment awareness. Running Chaos Mesh and
+// LitmusChaos experiments are listed every interval; failures in the
+// namespaces they target are tagged chaos-induced, and with auto_silence
+// their alerts are silenced until the experiment ends.
+type ChaosConfig struct {
+	Enabled     bool          `yaml:"enabled" mapstructure:"enabled"`
+	Interval    time.Duration `yaml:"interval" mapstructure:"interval"`
+	AutoSilence bool          `yaml:"auto_silence" mapstructure:"auto_silence"`
+}
+
+// FederationConfig connects KubePulse instances in a hub-and-spoke setup: a
+// hub requests AI analyses from its spokes, and a spoke serves them to the
+// hubs it lists. Each pair shares a secret both sides sign with.
+type FederationConfig struct {
+	Instance string        `yaml:"instance" mapstructure:"instance"` // Name this instance signs with
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`   // How long a hub waits for each spoke
+
+	Spokes []FederationPeerConfig `yaml:"spokes,omitempty" mapstructure:"spokes"` // Instances this hub requests analyses from
+	Hubs   []FederationPeerConfig `yaml:"hubs,omitempty" mapstructure:"hubs"`     // Instances allowed to request analyses
+}
+
+// FederationPeerConfig is another instance and the secret shared with it
+type FederationPeerConfig struct {
+	Name   string `yaml:"name" mapstructure:"name"`
+	URL    string `yaml:"url,omitempty" mapstructure:"url"` // Spokes only
+	Secret string `yaml:"secret" mapstructure:"secret"`
+}
+
+// WebhookConfig is an endpoint that receives the full check result whenever
+// a check's status changes in a way its filter selects
+type WebhookConfig struct {
+	Name   string `yaml:"name" mapstructure:"name"`
+	URL    string `yaml:"url" mapstructure:"url"`
+	Filter string `yaml:"filter,omitempty" mapstructure:"filter"` // Label selector over check, from, to and namespace
+	Secret string `yaml:"secret,omitempty" mapstructure:"secret"` // Signs deliveries with HMAC-SHA256
+}
+
+// StatusPageConfig controls the public status page. It listens on its own
+// port so it can be exposed without exposing the dashboard or API.
+type StatusPageConfig struct {
+	Enabled     bool   `yaml:"enabled" mapstructure:"enabled"`
+	Host        string `yaml:"host" mapstructure:"host"`
+	Port        int    `yaml:"port" mapstructure:"port"`
+	Title       string `yaml:"title" mapstructure:"title"`
+	HistoryDays int    `yaml:"history_days" mapstructure:"history_days"` // Days of availability shown
+
+	// Components group checks and SLOs; without any, every check is shown
+	Components []StatusPageComponentConfig `yaml:"components,omitempty" mapstructure:"components"`
+}
+
+// StatusPageComponentConfig is one line on the status page, down while any
+// of its checks is unhealthy and degraded while any of its SLOs is violated
+type StatusPageComponentConfig struct {
+	Name        string   `yaml:"name" mapstructure:"name"`
+	Description string   `yaml:"description,omitempty" mapstructure:"description"`
+	Checks      []string `yaml:"checks,omitempty" mapstructure:"checks"`
+	SLOs        []string `yaml:"slos,omitempty" mapstructure:"slos"`
+}
+
+// PluginsConfig controls health check plugins: executables in a directory
+// that speak the exec protocol described in docs/check-plugins.md
+type PluginsConfig struct {
+	Enabled bool          `yaml:"enabled" mapstructure:"enabled"`
+	Dir     string        `yaml:"dir" mapstructure:"dir"`         // Missing directories hold no plugins
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"` // Bounds each plugin run
+
+	// Config is passed to each plugin, by check name, when it runs
+	Config map[string]map[string]interface{} `yaml:"config,omitempty" mapstructure:"config"`
+}
+
+// BackupConfig controls periodic backups of the config file and the state
+// KubePulse learns at runtime
+type BackupConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+	Location string        `yaml:"location" mapstructure:"location"` // Directory, or s3://bucket/prefix
+	Retain   int           `yaml:"retain" mapstructure:"retain"`     // Backups kept; 0 keeps all
+	S3       S3Config      `yaml:"s3" mapstructure:"s3"`
+
+	// StateFile holds state restored by `kubepulse restore`; serve loads it
+	// at startup
+	StateFile string `yaml:"state_file" mapstructure:"state_file"`
+}
+
+// S3Config locates an S3-compatible object store. Credentials default to the
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" mapstructure:"endpoint"`
+	Region          string `yaml:"region" mapstructure:"region"`
+	AccessKeyID     string `yaml:"access_key_id,omitempty" mapstructure:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty" mapstructure:"secret_access_key"`
+}
+
 // LoadConfig loads configuration from file and environment
 func LoadConfig(configPath string) (*Config, error) {
-	// Set defaults
-	config := &Config{
+	return LoadConfigWithOptions(LoadOptions{Path: configPath})
+}
+
+// LoadConfigWithOptions loads configuration from all layers, see Resolve
+func LoadConfigWithOptions(opts LoadOptions) (*Config, error) {
+	resolved, err := Resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.Config, nil
+}
+
+// defaultConfig returns the built-in configuration every layer applies to
+func defaultConfig() *Config {
+	return &Config{
 		Kubernetes: KubernetesConfig{
 			Kubeconfig: "~/.kube/config",
 		},
@@ -137,6 +693,30 @@ func LoadConfig(configPath string) (*Config, error) {
 			EnabledChecks: []string{"pod-health", "node-health", "service-health"},
 			MaxHistory:    1000,
 			Timeout:       30 * time.Second,
+
+			WatchdogMultiplier: 2,
+			HistoryRetention:   7 * 24 * time.Hour,
+			CheckProfile:       CheckProfileDeep,
+			CheckDiscovery:     CheckDiscoveryConfig{Enabled: true},
+			ExpensiveInterval:  10 * time.Minute,
+			InventoryInterval:  15 * time.Minute,
+
+			PermissionRecheckInterval: 10 * time.Minute,
+			AdaptiveInterval: AdaptiveIntervalConfig{
+				MinInterval:  10 * time.Second,
+				MaxInterval:  5 * time.Minute,
+				HealthyAfter: 30 * time.Minute,
+			},
+			PodSecurity: PodSecurityConfig{
+				DefaultLevel: "baseline",
+			},
+			Cardinality: CardinalityConfig{
+				MaxSeries:      1000,
+				MaxLabelValues: 100,
+				Action:         "hash",
+				HashBuckets:    16,
+			},
+			LatencyBudget: LatencyBudgetConfig{Percentile: 90},
 		},
 		Alerts: AlertsConfig{
 			Enabled: true,
@@ -146,6 +726,15 @@ func LoadConfig(configPath string) (*Config, error) {
 					Enabled: true,
 				},
 			},
+			Delivery: DeliveryConfig{
+				QueueFile:      "~/.kubepulse/notifications.json",
+				InitialBackoff: 30 * time.Second,
+				MaxBackoff:     30 * time.Minute,
+				MaxAttempts:    12,
+			},
+			Alertmanager: AlertmanagerConfig{
+				Interval: time.Minute,
+			},
 		},
 		ML: MLConfig{
 			Enabled:         true,
@@ -162,12 +751,18 @@ func LoadConfig(configPath string) (*Config, error) {
 			CORSOrigins:  []string{"*"},
 			ReadTimeout:  15 * time.Second,
 			WriteTimeout: 15 * time.Second,
+			Auth:         AuthConfig{RotationReminder: 90 * 24 * time.Hour},
+
+			MaxConcurrentAnalyses: 2,
 		},
 		UI: UIConfig{
 			RefreshInterval:      10 * time.Second,
 			AIInsightsInterval:   30 * time.Second,
 			MaxReconnectAttempts: 5,
 			ReconnectDelay:       3 * time.Second,
+			ReconnectMaxDelay:    time.Minute,
+			ReconnectMultiplier:  2,
+			ReconnectJitter:      0.2,
 			Theme:                "system",
 			Features: UIFeatures{
 				AIInsights:          true,
@@ -176,102 +771,259 @@ func LoadConfig(configPath string) (*Config, error) {
 				NodeDetails:         true,
 			},
 		},
+		Updates: UpdatesConfig{
+			Enabled:       false,
+			Repository:    "charles-adedotun/kubepulse",
+			CheckInterval: 24 * time.Hour,
+		},
+		Telemetry: TelemetryConfig{
+			Enabled:  false,
+			Interval: 24 * time.Hour,
+		},
+		Diagnostics: DiagnosticsConfig{
+			Enabled:       true,
+			DumpDir:       "~/.kubepulse/dumps",
+			MaxDumps:      5,
+			GrowthFactor:  3,
+			MinGoroutines: 1000,
+			MinCycle:      time.Minute,
+			Cooldown:      30 * time.Minute,
+		},
+		LoadShedding: LoadSheddingConfig{
+			Enabled:         true,
+			MemoryThreshold: 0.85,
+			CPUThreshold:    0.9,
+			IntervalFactor:  3,
+		},
+		Chaos: ChaosConfig{
+			Enabled:  true,
+			Interval: 30 * time.Second,
+		},
+		Federation: FederationConfig{
+			Timeout: time.Minute,
+		},
+		StatusPage: StatusPageConfig{
+			Enabled:     false,
+			Port:        8081,
+			Title:       "Service Status",
+			HistoryDays: 7,
+		},
+		Plugins: PluginsConfig{
+			Enabled: true,
+			Dir:     "~/.kubepulse/plugins",
+			Timeout: 30 * time.Second,
+		},
+		Backup: BackupConfig{
+			Enabled:   false,
+			Interval:  24 * time.Hour,
+			Location:  "~/.kubepulse/backups",
+			Retain:    7,
+			StateFile: "~/.kubepulse/state.json",
+			S3: S3Config{
+				Endpoint: "https://s3.amazonaws.com",
+				Region:   "us-east-1",
+			},
+		},
 	}
-
-	// Load from file if specified
-	if configPath != "" {
-		if err := loadFromFile(config, configPath); err != nil {
-			return nil, fmt.Errorf("failed to load config from file: %w", err)
-		}
-	}
-
-	// Load from environment
-	if err := loadFromEnv(config); err != nil {
-		return nil, fmt.Errorf("failed to load config from environment: %w", err)
-	}
-
-	// Validate configuration
-	if err := validateConfig(config); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	return config, nil
 }
 
-// loadFromFile loads configuration from YAML file
-func loadFromFile(config *Config, path string) error {
+// loadFromFile reads the YAML configuration file
+func loadFromFile(path string) (map[string]interface{}, error) {
 	// Validate that the path doesn't contain directory traversal sequences
 	if strings.Contains(path, "..") {
-		return fmt.Errorf("invalid path: directory traversal not allowed")
+		return nil, fmt.Errorf("invalid path: directory traversal not allowed")
 	}
 
 	data, err := os.ReadFile(path) // #nosec G304 - path validation performed above
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return yaml.Unmarshal(data, config)
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
-// loadFromEnv loads configuration from environment variables
-func loadFromEnv(config *Config) error {
-	viper.SetEnvPrefix("KUBEPULSE")
-	viper.AutomaticEnv()
+// validateConfig validates the configuration
+func validateConfig(config *Config) error {
+	// Validate monitoring settings
+	if config.Monitoring.Interval <= 0 {
+		return fmt.Errorf("monitoring.interval must be positive")
+	}
+	if config.Monitoring.Timeout <= 0 {
+		return fmt.Errorf("monitoring.timeout must be positive")
+	}
+	if config.Monitoring.MaxHistory <= 0 {
+		config.Monitoring.MaxHistory = 1000
+	}
+	if config.Monitoring.WatchdogMultiplier == 0 {
+		config.Monitoring.WatchdogMultiplier = 2
+	}
+	if config.Monitoring.WatchdogMultiplier < 1 {
+		return fmt.Errorf("monitoring.watchdog_multiplier must be at least 1")
+	}
+	if config.Monitoring.HistoryRetention == 0 {
+		config.Monitoring.HistoryRetention = 7 * 24 * time.Hour
+	}
+	if config.Monitoring.HistoryRetention < time.Hour {
+		return fmt.Errorf("monitoring.history_retention must be at least 1h")
+	}
+	for check, runbook := range config.Monitoring.Runbooks {
+		if parsed, err := url.Parse(runbook); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("monitoring.runbooks.%s must be an absolute http or https URL", check)
+		}
+	}
+	if config.Monitoring.ExpensiveInterval == 0 {
+		config.Monitoring.ExpensiveInterval = 10 * time.Minute
+	}
+	if config.Monitoring.ExpensiveInterval < config.Monitoring.Interval {
+		return fmt.Errorf("monitoring.expensive_interval must be at least monitoring.interval")
+	}
+	if config.Monitoring.InventoryInterval == 0 {
+		config.Monitoring.InventoryInterval = 15 * time.Minute
+	}
+	if config.Monitoring.InventoryInterval < time.Minute {
+		return fmt.Errorf("monitoring.inventory_interval must be at least 1m")
+	}
+	if config.Monitoring.PermissionRecheckInterval == 0 {
+		config.Monitoring.PermissionRecheckInterval = 10 * time.Minute
+	}
+	if config.Monitoring.PermissionRecheckInterval < config.Monitoring.Interval {
+		return fmt.Errorf("monitoring.permission_recheck_interval must be at least monitoring.interval")
+	}
+	if err := validateAdaptiveInterval(&config.Monitoring); err != nil {
+		return err
+	}
+	if err := validatePodSecurity(&config.Monitoring.PodSecurity); err != nil {
+		return err
+	}
+	if err := validateCheckProfiles(&config.Monitoring); err != nil {
+		return err
+	}
+	if err := validateCardinality(&config.Monitoring.Cardinality); err != nil {
+		return err
+	}
+	if budget := &config.Monitoring.LatencyBudget; budget.Detection < 0 {
+		return fmt.Errorf("monitoring.latency_budget.detection must not be negative")
+	} else if budget.Percentile == 0 {
+		budget.Percentile = 90
+	} else if budget.Percentile < 1 || budget.Percentile > 100 {
+		return fmt.Errorf("monitoring.latency_budget.percentile must be between 1 and 100")
+	}
 
-	// Bind environment variables
-	_ = viper.BindEnv("kubernetes.kubeconfig", "KUBEPULSE_KUBECONFIG")
-	_ = viper.BindEnv("monitoring.interval", "KUBEPULSE_INTERVAL")
-	_ = viper.BindEnv("ml.enabled", "KUBEPULSE_ML_ENABLED")
-	_ = viper.BindEnv("server.port", "KUBEPULSE_PORT")
-	_ = viper.BindEnv("server.host", "KUBEPULSE_HOST")
-	_ = viper.BindEnv("server.enable_web", "KUBEPULSE_WEB_ENABLED")
-	_ = viper.BindEnv("server.cors_enabled", "KUBEPULSE_CORS_ENABLED")
-	_ = viper.BindEnv("ui.refresh_interval", "KUBEPULSE_UI_REFRESH")
-	_ = viper.BindEnv("ui.theme", "KUBEPULSE_UI_THEME")
+	// Validate API tokens
+	tokenNames := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for i, token := range config.Server.Auth.Tokens {
+		if token.Name == "" {
+			return fmt.Errorf("server.auth.tokens[%d] needs a name", i)
+		}
+		if tokenNames[token.Name] {
+			return fmt.Errorf("server.auth.tokens.%s is defined more than once", token.Name)
+		}
+		tokenNames[token.Name] = true
+		if len(token.Token) < 16 {
+			return fmt.Errorf("server.auth.tokens.%s.token must be at least 16 characters", token.Name)
+		}
+		if tokens[token.Token] {
+			return fmt.Errorf("server.auth.tokens.%s reuses another token's value", token.Name)
+		}
+		tokens[token.Token] = true
+		if token.Role != "viewer" && token.Role != "admin" {
+			return fmt.Errorf("server.auth.tokens.%s.role must be viewer or admin", token.Name)
+		}
+		if token.Scope != "" && token.Scope != "alerts" {
+			return fmt.Errorf("server.auth.tokens.%s.scope must be empty or alerts", token.Name)
+		}
+	}
+	if config.Server.Auth.RotationReminder < 0 {
+		return fmt.Errorf("server.auth.rotation_reminder must not be negative")
+	}
 
-	// Override with environment values if set
-	if viper.IsSet("kubernetes.kubeconfig") {
-		config.Kubernetes.Kubeconfig = viper.GetString("kubernetes.kubeconfig")
+	// Validate the AI analysis queue
+	if config.Server.MaxConcurrentAnalyses == 0 {
+		config.Server.MaxConcurrentAnalyses = 2
 	}
-	if viper.IsSet("monitoring.interval") {
-		config.Monitoring.Interval = viper.GetDuration("monitoring.interval")
+	if config.Server.MaxConcurrentAnalyses < 1 {
+		return fmt.Errorf("server.max_concurrent_analyses must be at least 1")
 	}
-	if viper.IsSet("ml.enabled") {
-		config.ML.Enabled = viper.GetBool("ml.enabled")
+	if config.Server.AnalysisWait < 0 {
+		return fmt.Errorf("server.analysis_wait must not be negative")
 	}
-	if viper.IsSet("server.port") {
-		config.Server.Port = viper.GetInt("server.port")
+	if config.Server.WriteTimeout > 0 && config.Server.AnalysisWait >= config.Server.WriteTimeout {
+		return fmt.Errorf("server.analysis_wait must be less than server.write_timeout")
 	}
-	if viper.IsSet("server.host") {
-		config.Server.Host = viper.GetString("server.host")
+
+	// Validate UI reconnect backoff
+	if config.UI.ReconnectMaxDelay == 0 {
+		config.UI.ReconnectMaxDelay = time.Minute
 	}
-	if viper.IsSet("server.enable_web") {
-		config.Server.EnableWeb = viper.GetBool("server.enable_web")
+	if config.UI.ReconnectMaxDelay < config.UI.ReconnectDelay {
+		config.UI.ReconnectMaxDelay = config.UI.ReconnectDelay
 	}
-	if viper.IsSet("server.cors_enabled") {
-		config.Server.CORSEnabled = viper.GetBool("server.cors_enabled")
+	if config.UI.ReconnectMultiplier == 0 {
+		config.UI.ReconnectMultiplier = 2
 	}
-	if viper.IsSet("ui.refresh_interval") {
-		config.UI.RefreshInterval = viper.GetDuration("ui.refresh_interval")
+	if config.UI.ReconnectMultiplier < 1 {
+		return fmt.Errorf("ui.reconnect_multiplier must be at least 1")
 	}
-	if viper.IsSet("ui.theme") {
-		config.UI.Theme = viper.GetString("ui.theme")
+	if config.UI.ReconnectJitter < 0 || config.UI.ReconnectJitter > 1 {
+		return fmt.Errorf("ui.reconnect_jitter must be between 0 and 1")
 	}
 
-	return nil
-}
-
-// validateConfig validates the configuration
-func validateConfig(config *Config) error {
-	// Validate monitoring settings
-	if config.Monitoring.Interval <= 0 {
-		return fmt.Errorf("monitoring.interval must be positive")
+	// Validate notification delivery
+	if config.Alerts.Delivery.InitialBackoff == 0 {
+		config.Alerts.Delivery.InitialBackoff = 30 * time.Second
 	}
-	if config.Monitoring.Timeout <= 0 {
-		return fmt.Errorf("monitoring.timeout must be positive")
+	if config.Alerts.Delivery.InitialBackoff < time.Second {
+		return fmt.Errorf("alerts.delivery.initial_backoff must be at least 1s")
 	}
-	if config.Monitoring.MaxHistory <= 0 {
-		config.Monitoring.MaxHistory = 1000
+	if config.Alerts.Delivery.MaxBackoff == 0 {
+		config.Alerts.Delivery.MaxBackoff = 30 * time.Minute
+	}
+	if config.Alerts.Delivery.MaxBackoff < config.Alerts.Delivery.InitialBackoff {
+		return fmt.Errorf("alerts.delivery.max_backoff must be at least alerts.delivery.initial_backoff")
+	}
+	if config.Alerts.Delivery.MaxAttempts == 0 {
+		config.Alerts.Delivery.MaxAttempts = 12
+	}
+	if config.Alerts.Delivery.MaxAttempts < 1 {
+		return fmt.Errorf("alerts.delivery.max_attempts must be at least 1")
+	}
+	if config.Alerts.ExternalURL != "" {
+		if parsed, err := url.Parse(config.Alerts.ExternalURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("alerts.external_url must be an http(s) URL")
+		}
+	}
+	if config.Alerts.Alertmanager.URL != "" {
+		if parsed, err := url.Parse(config.Alerts.Alertmanager.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("alerts.alertmanager.url must be an http(s) URL")
+		}
+		if config.Alerts.Alertmanager.Interval < 10*time.Second {
+			return fmt.Errorf("alerts.alertmanager.interval must be at least 10s")
+		}
+	}
+	if err := validateClusters(config.Kubernetes.Clusters); err != nil {
+		return err
+	}
+	if err := validateAI(config.AI); err != nil {
+		return err
+	}
+	if err := validateAIEvaluation(config.AIEvaluation); err != nil {
+		return err
+	}
+	if err := validateEnvironments(&config.Environments, config.Alerts.Channels); err != nil {
+		return err
+	}
+	for i, route := range config.Alerts.Routes {
+		if route.Team == "" && route.App == "" && route.Tier == "" {
+			return fmt.Errorf("alerts.routes[%d] needs a team, app or tier", i)
+		}
+		if _, ok := config.Alerts.Channels[route.Channel]; !ok {
+			return fmt.Errorf("alerts.routes[%d].channel must name a channel in alerts.channels", i)
+		}
 	}
 
 	// Validate ML settings
@@ -282,6 +1034,250 @@ func validateConfig(config *Config) error {
 		config.ML.PredictionHours = 24
 	}
 
+	// Validate update check settings
+	if config.Updates.CheckInterval == 0 {
+		config.Updates.CheckInterval = 24 * time.Hour
+	}
+	if config.Updates.Enabled && config.Updates.CheckInterval < time.Hour {
+		return fmt.Errorf("updates.check_interval must be at least 1h")
+	}
+	if config.Updates.Repository != "" && strings.Count(config.Updates.Repository, "/") != 1 {
+		return fmt.Errorf("updates.repository must be in owner/name form")
+	}
+
+	// Validate metric conditions
+	for i, condition := range config.MetricConditions {
+		if condition.Name == "" || condition.Metric == "" {
+			return fmt.Errorf("metric_conditions[%d] needs a name and a metric", i)
+		}
+		switch condition.Operator {
+		case ">", ">=", "<", "<=":
+		default:
+			return fmt.Errorf("metric_conditions.%s.operator must be >, >=, < or <=", condition.Name)
+		}
+		if condition.Status != "degraded" && condition.Status != "unhealthy" {
+			return fmt.Errorf("metric_conditions.%s.status must be degraded or unhealthy", condition.Name)
+		}
+	}
+
+	// Validate SLOs
+	sloNames := make([]string, 0, len(config.SLOs))
+	for name := range config.SLOs {
+		sloNames = append(sloNames, name)
+	}
+	sort.Strings(sloNames)
+	for _, name := range sloNames {
+		slo := config.SLOs[name]
+		if slo.Window < 0 {
+			return fmt.Errorf("slos.%s.window must not be negative", name)
+		}
+		if slo.Check != "" && slo.SLI != "" && slo.SLI != "availability" {
+			return fmt.Errorf("slos.%s.sli must be availability or unset with a check", name)
+		}
+		ratio := slo.Check != "" || slo.SLI == "availability" || slo.SLI == "error_rate"
+		if ratio && (slo.Target <= 0 || slo.Target >= 100) {
+			return fmt.Errorf("slos.%s.target must be a percentage between 0 and 100", name)
+		}
+		if len(slo.BurnRateAlerts) > 0 && !ratio {
+			return fmt.Errorf("slos.%s.burn_rate_alerts need an availability, error_rate or check SLI", name)
+		}
+		for i, alert := range slo.BurnRateAlerts {
+			if alert.ShortWindow <= 0 || alert.LongWindow <= alert.ShortWindow {
+				return fmt.Errorf("slos.%s.burn_rate_alerts[%d] needs a short_window shorter than its long_window", name, i)
+			}
+			if alert.BurnRate <= 0 {
+				return fmt.Errorf("slos.%s.burn_rate_alerts[%d].burn_rate must be positive", name, i)
+			}
+			if alert.Severity != "critical" && alert.Severity != "warning" {
+				return fmt.Errorf("slos.%s.burn_rate_alerts[%d].severity must be critical or warning", name, i)
+			}
+		}
+	}
+
+	// Validate custom resource checks
+	checkNames := make(map[string]bool)
+	for i, resource := range config.CustomResources {
+		if !checkNamePattern.MatchString(resource.Name) {
+			return fmt.Errorf("custom_resources[%d].name must be lowercase letters, digits and '-'", i)
+		}
+		if checkNames[resource.Name] {
+			return fmt.Errorf("custom_resources.%s is defined more than once", resource.Name)
+		}
+		checkNames[resource.Name] = true
+		if resource.Version == "" || resource.Kind == "" {
+			return fmt.Errorf("custom_resources.%s needs a version and a kind", resource.Name)
+		}
+		for _, condition := range resource.Conditions {
+			if condition.Type == "" {
+				return fmt.Errorf("custom_resources.%s.conditions need a type", resource.Name)
+			}
+			switch condition.Status {
+			case "", "True", "False", "Unknown":
+			default:
+				return fmt.Errorf("custom_resources.%s.conditions.%s.status must be True, False or Unknown", resource.Name, condition.Type)
+			}
+			if condition.Health != "" && condition.Health != "degraded" && condition.Health != "unhealthy" {
+				return fmt.Errorf("custom_resources.%s.conditions.%s.health must be degraded or unhealthy", resource.Name, condition.Type)
+			}
+		}
+	}
+
+	// Validate custom metrics checks; they share names with custom resource
+	// checks
+	for i, metric := range config.CustomMetrics {
+		if !checkNamePattern.MatchString(metric.Name) {
+			return fmt.Errorf("custom_metrics[%d].name must be lowercase letters, digits and '-'", i)
+		}
+		if checkNames[metric.Name] {
+			return fmt.Errorf("custom_metrics.%s is defined more than once", metric.Name)
+		}
+		checkNames[metric.Name] = true
+		if metric.Metric == "" {
+			return fmt.Errorf("custom_metrics.%s needs a metric", metric.Name)
+		}
+		switch metric.API {
+		case "", "custom":
+			if metric.Resource == "" {
+				return fmt.Errorf("custom_metrics.%s needs the resource the metric describes", metric.Name)
+			}
+		case "external":
+		default:
+			return fmt.Errorf("custom_metrics.%s.api must be custom or external", metric.Name)
+		}
+		switch metric.Operator {
+		case "", ">", ">=", "<", "<=":
+		default:
+			return fmt.Errorf("custom_metrics.%s.operator must be >, >=, < or <=", metric.Name)
+		}
+		if metric.Status != "" && metric.Status != "degraded" && metric.Status != "unhealthy" {
+			return fmt.Errorf("custom_metrics.%s.status must be degraded or unhealthy", metric.Name)
+		}
+	}
+
+	// Validate telemetry settings
+	if config.Telemetry.Enabled {
+		if parsed, err := url.Parse(config.Telemetry.Endpoint); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("telemetry.endpoint must be an absolute http or https URL when telemetry is enabled")
+		}
+		if config.Telemetry.Interval < time.Hour {
+			return fmt.Errorf("telemetry.interval must be at least 1h")
+		}
+	}
+
+	// Validate self-diagnostics settings
+	if config.Diagnostics.Enabled {
+		if config.Diagnostics.DumpDir == "" {
+			return fmt.Errorf("diagnostics.dump_dir must be set when diagnostics are enabled")
+		}
+		if config.Diagnostics.MaxDumps < 1 {
+			return fmt.Errorf("diagnostics.max_dumps must be at least 1")
+		}
+		if config.Diagnostics.GrowthFactor <= 1 {
+			return fmt.Errorf("diagnostics.growth_factor must be greater than 1")
+		}
+		if config.Diagnostics.Cooldown < 0 {
+			return fmt.Errorf("diagnostics.cooldown must not be negative")
+		}
+	}
+
+	// Validate load shedding settings
+	if config.LoadShedding.Enabled {
+		if config.LoadShedding.MemoryThreshold <= 0 || config.LoadShedding.MemoryThreshold > 1 {
+			return fmt.Errorf("load_shedding.memory_threshold must be greater than 0 and at most 1")
+		}
+		if config.LoadShedding.CPUThreshold <= 0 || config.LoadShedding.CPUThreshold > 1 {
+			return fmt.Errorf("load_shedding.cpu_threshold must be greater than 0 and at most 1")
+		}
+		if config.LoadShedding.IntervalFactor < 1 {
+			return fmt.Errorf("load_shedding.interval_factor must be at least 1")
+		}
+	}
+
+	// Validate chaos experiment detection
+	if config.Chaos.Enabled && config.Chaos.Interval < time.Second {
+		return fmt.Errorf("chaos.interval must be at least 1s")
+	}
+
+	// Validate federation peers
+	if err := validateFederation(config); err != nil {
+		return err
+	}
+
+	// Validate check result webhooks; filters are parsed when serve starts
+	webhooks := make(map[string]bool)
+	for i, webhook := range config.Webhooks {
+		if webhook.Name == "" {
+			return fmt.Errorf("webhooks[%d] needs a name", i)
+		}
+		if webhooks[webhook.Name] {
+			return fmt.Errorf("webhooks.%s is defined more than once", webhook.Name)
+		}
+		webhooks[webhook.Name] = true
+		if parsed, err := url.Parse(webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("webhooks.%s.url must be an absolute http or https URL", webhook.Name)
+		}
+	}
+
+	// Validate status page settings
+	if config.StatusPage.Enabled {
+		if config.StatusPage.Port <= 0 || config.StatusPage.Port > 65535 {
+			return fmt.Errorf("status_page.port must be between 1 and 65535")
+		}
+		if config.StatusPage.Port == config.Server.Port {
+			return fmt.Errorf("status_page.port must differ from server.port")
+		}
+		if config.StatusPage.HistoryDays < 1 {
+			return fmt.Errorf("status_page.history_days must be at least 1")
+		}
+		if time.Duration(config.StatusPage.HistoryDays)*24*time.Hour > config.Monitoring.HistoryRetention {
+			return fmt.Errorf("status_page.history_days must fit within monitoring.history_retention (%s)", config.Monitoring.HistoryRetention)
+		}
+		components := make(map[string]bool)
+		for i, component := range config.StatusPage.Components {
+			if component.Name == "" {
+				return fmt.Errorf("status_page.components[%d] needs a name", i)
+			}
+			if components[component.Name] {
+				return fmt.Errorf("status_page.components.%s is defined more than once", component.Name)
+			}
+			components[component.Name] = true
+			if len(component.Checks) == 0 && len(component.SLOs) == 0 {
+				return fmt.Errorf("status_page.components.%s needs at least one check or SLO", component.Name)
+			}
+			for _, name := range component.SLOs {
+				if _, ok := config.SLOs[name]; !ok {
+					return fmt.Errorf("status_page.components.%s refers to undefined SLO %q", component.Name, name)
+				}
+			}
+		}
+	}
+
+	// Validate plugin settings
+	if config.Plugins.Timeout <= 0 {
+		return fmt.Errorf("plugins.timeout must be positive")
+	}
+
+	// Validate backup settings
+	if config.Backup.Enabled {
+		if config.Backup.Interval < time.Minute {
+			return fmt.Errorf("backup.interval must be at least 1m")
+		}
+		if config.Backup.Location == "" {
+			return fmt.Errorf("backup.location is required when backups are enabled")
+		}
+	}
+	if config.Backup.Retain < 0 {
+		return fmt.Errorf("backup.retain must not be negative")
+	}
+	if strings.HasPrefix(config.Backup.Location, "s3://") {
+		if bucket, _, _ := strings.Cut(strings.TrimPrefix(config.Backup.Location, "s3://"), "/"); bucket == "" {
+			return fmt.Errorf("backup.location must name a bucket, as in s3://bucket/prefix")
+		}
+		if parsed, err := url.Parse(config.Backup.S3.Endpoint); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("backup.s3.endpoint must be an absolute http or https URL")
+		}
+	}
+
 	return nil
 }
 
@@ -300,3 +1296,134 @@ func GetDefaultConfig() *Config {
 	config, _ := LoadConfig("")
 	return config
 }
+
+// validateAdaptiveInterval fills in adaptive interval defaults and checks
+// the bounds surround monitoring.interval
+func validateAdaptiveInterval(monitoring *MonitoringConfig) error {
+	adaptive := &monitoring.AdaptiveInterval
+	if !adaptive.Enabled {
+		return nil
+	}
+	if adaptive.MinInterval == 0 {
+		adaptive.MinInterval = 10 * time.Second
+	}
+	if adaptive.MaxInterval == 0 {
+		adaptive.MaxInterval = 5 * time.Minute
+	}
+	if adaptive.HealthyAfter == 0 {
+		adaptive.HealthyAfter = 30 * time.Minute
+	}
+	if adaptive.MinInterval < time.Second {
+		return fmt.Errorf("monitoring.adaptive_interval.min_interval must be at least 1s")
+	}
+	if adaptive.MinInterval > monitoring.Interval {
+		return fmt.Errorf("monitoring.adaptive_interval.min_interval must be at most monitoring.interval")
+	}
+	if adaptive.MaxInterval < monitoring.Interval {
+		return fmt.Errorf("monitoring.adaptive_interval.max_interval must be at least monitoring.interval")
+	}
+	if adaptive.HealthyAfter < 0 {
+		return fmt.Errorf("monitoring.adaptive_interval.healthy_after must not be negative")
+	}
+	return nil
+}
+
+// validatePodSecurity defaults the Pod Security level to baseline and
+// checks every level is known
+func validatePodSecurity(podSecurity *PodSecurityConfig) error {
+	if podSecurity.DefaultLevel == "" {
+		podSecurity.DefaultLevel = "baseline"
+	}
+	if !podSecurityLevels[podSecurity.DefaultLevel] {
+		return fmt.Errorf("monitoring.pod_security.default_level must be privileged, baseline or restricted")
+	}
+	for namespace, level := range podSecurity.Namespaces {
+		if !podSecurityLevels[level] {
+			return fmt.Errorf("monitoring.pod_security.namespaces.%s must be privileged, baseline or restricted", namespace)
+		}
+	}
+	return nil
+}
+
+// validateCardinality fills unset cardinality limits with their defaults and
+// rejects negative ones
+func validateCardinality(cardinality *CardinalityConfig) error {
+	if cardinality.MaxSeries == 0 {
+		cardinality.MaxSeries = 1000
+	}
+	if cardinality.MaxLabelValues == 0 {
+		cardinality.MaxLabelValues = 100
+	}
+	if cardinality.Action == "" {
+		cardinality.Action = "hash"
+	}
+	if cardinality.HashBuckets == 0 {
+		cardinality.HashBuckets = 16
+	}
+	if cardinality.MaxSeries < 1 {
+		return fmt.Errorf("monitoring.cardinality.max_series must be at least 1")
+	}
+	if cardinality.MaxLabelValues < 1 {
+		return fmt.Errorf("monitoring.cardinality.max_label_values must be at least 1")
+	}
+	if cardinality.Action != "hash" && cardinality.Action != "drop" {
+		return fmt.Errorf("monitoring.cardinality.action must be hash or drop, got %q", cardinality.Action)
+	}
+	if cardinality.HashBuckets < 1 {
+		return fmt.Errorf("monitoring.cardinality.hash_buckets must be at least 1")
+	}
+	for metric, limit := range cardinality.Overrides {
+		if limit.MaxSeries < 0 || limit.MaxLabelValues < 0 {
+			return fmt.Errorf("monitoring.cardinality.overrides.%s limits must not be negative", metric)
+		}
+	}
+	return nil
+}
+
+// validateFederation checks the federation peers and defaults the timeout
+func validateFederation(config *Config) error {
+	federation := &config.Federation
+	if federation.Timeout == 0 {
+		federation.Timeout = time.Minute
+	}
+	if federation.Timeout < 0 {
+		return fmt.Errorf("federation.timeout must be positive")
+	}
+	if len(federation.Spokes) == 0 && len(federation.Hubs) == 0 {
+		return nil
+	}
+	if federation.Instance == "" {
+		return fmt.Errorf("federation.instance must be set when federation peers are configured")
+	}
+
+	for _, peers := range []struct {
+		key   string
+		list  []FederationPeerConfig
+		spoke bool
+	}{{"spokes", federation.Spokes, true}, {"hubs", federation.Hubs, false}} {
+		names := make(map[string]bool)
+		for i, peer := range peers.list {
+			if peer.Name == "" {
+				return fmt.Errorf("federation.%s[%d] needs a name", peers.key, i)
+			}
+			if names[peer.Name] {
+				return fmt.Errorf("federation.%s.%s is defined more than once", peers.key, peer.Name)
+			}
+			names[peer.Name] = true
+			if len(peer.Secret) < 32 {
+				return fmt.Errorf("federation.%s.%s.secret must be at least 32 characters", peers.key, peer.Name)
+			}
+			if !peers.spoke {
+				continue
+			}
+			if parsed, err := url.Parse(peer.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("federation.spokes.%s.url must be an absolute http or https URL", peer.Name)
+			}
+		}
+	}
+
+	if len(federation.Spokes) > 0 && config.Server.WriteTimeout > 0 && federation.Timeout >= config.Server.WriteTimeout {
+		return fmt.Errorf("federation.timeout must be less than server.write_timeout")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -450,6 +451,29 @@ func TestEnvironmentVariableOverrides(t *testing.T) {
 	}
 }
 
+func TestReadOnlyOverrides(t *testing.T) {
+	if GetDefaultConfig().ReadOnly {
+		t.Fatal("read-only mode should be off by default")
+	}
+
+	t.Setenv("KUBEPULSE_READ_ONLY", "true")
+	resolved, err := Resolve(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !resolved.Config.ReadOnly || resolved.Sources["read_only"] != "env:KUBEPULSE_READ_ONLY" {
+		t.Errorf("expected read-only mode from the environment, got %v from %q", resolved.Config.ReadOnly, resolved.Sources["read_only"])
+	}
+
+	resolved, err = Resolve(LoadOptions{Overrides: []Override{{Key: "read_only", Value: false, Flag: "--read-only"}}})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Config.ReadOnly {
+		t.Error("expected --read-only=false to win over the environment")
+	}
+}
+
 func TestConfigValidation_Defaults(t *testing.T) {
 	config := GetDefaultConfig()
 
@@ -479,6 +503,381 @@ func TestConfigValidation_Defaults(t *testing.T) {
 	}
 }
 
+func TestConfigValidation_Updates(t *testing.T) {
+	config := GetDefaultConfig()
+	if config.Updates.Enabled {
+		t.Error("update checks should be off by default")
+	}
+	if config.Updates.CheckInterval != 24*time.Hour {
+		t.Errorf("expected daily update checks, got %v", config.Updates.CheckInterval)
+	}
+
+	config.Updates.Enabled = true
+	config.Updates.CheckInterval = time.Minute
+	if err := validateConfig(config); err == nil {
+		t.Error("expected error for update interval under an hour")
+	}
+
+	config.Updates.CheckInterval = time.Hour
+	config.Updates.Repository = "kubepulse"
+	if err := validateConfig(config); err == nil {
+		t.Error("expected error for repository without an owner")
+	}
+}
+
+func TestConfigValidation_Runbooks(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.Runbooks = map[string]string{"pod-health": "https://runbooks.example.com/pods"}
+	if err := validateConfig(config); err != nil {
+		t.Errorf("unexpected error for a valid runbook: %v", err)
+	}
+
+	config.Monitoring.Runbooks["node-health"] = "wiki/nodes"
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.runbooks.node-health") {
+		t.Errorf("expected an error naming the invalid runbook, got %v", err)
+	}
+}
+
+func TestConfigValidation_HistoryRetention(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.HistoryRetention = 0
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Monitoring.HistoryRetention != 7*24*time.Hour {
+		t.Errorf("expected the default retention, got %s", config.Monitoring.HistoryRetention)
+	}
+
+	config.Monitoring.HistoryRetention = 30 * time.Minute
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.history_retention") {
+		t.Errorf("expected a short retention to be rejected, got %v", err)
+	}
+}
+
+func TestConfigValidation_Backup(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*BackupConfig)
+		wantErr string
+	}{
+		{"defaults", func(b *BackupConfig) {}, ""},
+		{"enabled", func(b *BackupConfig) { b.Enabled = true }, ""},
+		{"short interval", func(b *BackupConfig) { b.Enabled = true; b.Interval = time.Second }, "backup.interval"},
+		{"no location", func(b *BackupConfig) { b.Enabled = true; b.Location = "" }, "backup.location"},
+		{"negative retain", func(b *BackupConfig) { b.Retain = -1 }, "backup.retain"},
+		{"s3", func(b *BackupConfig) { b.Location = "s3://ops/kubepulse" }, ""},
+		{"s3 without bucket", func(b *BackupConfig) { b.Location = "s3:///kubepulse" }, "must name a bucket"},
+		{"bad s3 endpoint", func(b *BackupConfig) { b.Location = "s3://ops"; b.S3.Endpoint = "minio:9000" }, "backup.s3.endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Backup)
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Plugins(t *testing.T) {
+	config := GetDefaultConfig()
+	if !config.Plugins.Enabled || config.Plugins.Dir != "~/.kubepulse/plugins" || config.Plugins.Timeout != 30*time.Second {
+		t.Errorf("unexpected plugin defaults %+v", config.Plugins)
+	}
+
+	config.Plugins.Timeout = 0
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "plugins.timeout") {
+		t.Errorf("expected a plugins.timeout error, got %v", err)
+	}
+}
+
+func TestConfigValidation_SLOs(t *testing.T) {
+	fast := BurnRateAlertConfig{LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BurnRate: 14.4, Severity: "critical"}
+	tests := []struct {
+		name    string
+		slo     SLOConfig
+		wantErr string
+	}{
+		{"check", SLOConfig{Check: "web-health", Target: 99.5, BurnRateAlerts: []BurnRateAlertConfig{fast}}, ""},
+		{"custom", SLOConfig{SLI: "custom", Target: 150, Metrics: []string{"queue_ok"}}, ""},
+		{"check with latency sli", SLOConfig{Check: "web-health", SLI: "latency", Target: 99}, "slos.web.sli"},
+		{"ratio target", SLOConfig{SLI: "availability", Target: 100}, "slos.web.target"},
+		{"burn alerts on latency", SLOConfig{SLI: "latency", Target: 200, BurnRateAlerts: []BurnRateAlertConfig{fast}}, "slos.web.burn_rate_alerts"},
+		{"short window too long", SLOConfig{Check: "web-health", Target: 99, BurnRateAlerts: []BurnRateAlertConfig{
+			{LongWindow: time.Hour, ShortWindow: time.Hour, BurnRate: 1, Severity: "warning"},
+		}}, "burn_rate_alerts[0] needs a short_window"},
+		{"severity", SLOConfig{Check: "web-health", Target: 99, BurnRateAlerts: []BurnRateAlertConfig{
+			{LongWindow: time.Hour, ShortWindow: time.Minute, BurnRate: 1, Severity: "page"},
+		}}, "burn_rate_alerts[0].severity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.SLOs = map[string]SLOConfig{"web": tt.slo}
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Telemetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*TelemetryConfig)
+		wantErr string
+	}{
+		{"defaults", func(c *TelemetryConfig) {}, ""},
+		{"endpoint ignored while off", func(c *TelemetryConfig) { c.Endpoint = "reports" }, ""},
+		{"enabled", func(c *TelemetryConfig) { c.Enabled = true; c.Endpoint = "https://telemetry.example.com/v1/reports" }, ""},
+		{"no endpoint", func(c *TelemetryConfig) { c.Enabled = true }, "telemetry.endpoint"},
+		{"short interval", func(c *TelemetryConfig) {
+			c.Enabled = true
+			c.Endpoint = "https://telemetry.example.com/v1/reports"
+			c.Interval = time.Minute
+		}, "telemetry.interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Telemetry)
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Webhooks(t *testing.T) {
+	scaler := WebhookConfig{Name: "scaler", URL: "https://automation.example.com/kubepulse", Filter: "to=unhealthy"}
+	tests := []struct {
+		name     string
+		webhooks []WebhookConfig
+		wantErr  string
+	}{
+		{"none", nil, ""},
+		{"valid", []WebhookConfig{scaler}, ""},
+		{"no name", []WebhookConfig{{URL: scaler.URL}}, "webhooks[0] needs a name"},
+		{"duplicate", []WebhookConfig{scaler, scaler}, "webhooks.scaler is defined more than once"},
+		{"relative url", []WebhookConfig{{Name: "scaler", URL: "/kubepulse"}}, "webhooks.scaler.url"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Webhooks = tt.webhooks
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_StatusPage(t *testing.T) {
+	component := func(c *Config, component StatusPageComponentConfig) {
+		c.StatusPage.Enabled = true
+		c.StatusPage.Components = append(c.StatusPage.Components, component)
+	}
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"enabled", func(c *Config) { c.StatusPage.Enabled = true }, ""},
+		{"same port as server", func(c *Config) { c.StatusPage.Enabled = true; c.StatusPage.Port = c.Server.Port }, "must differ from server.port"},
+		{"history beyond retention", func(c *Config) { c.StatusPage.Enabled = true; c.StatusPage.HistoryDays = 30 }, "status_page.history_days"},
+		{"component", func(c *Config) {
+			component(c, StatusPageComponentConfig{Name: "Checkout", Checks: []string{"pod-health"}})
+		}, ""},
+		{"empty component", func(c *Config) { component(c, StatusPageComponentConfig{Name: "Checkout"}) }, "at least one check or SLO"},
+		{"duplicate component", func(c *Config) {
+			component(c, StatusPageComponentConfig{Name: "Checkout", Checks: []string{"pod-health"}})
+			component(c, StatusPageComponentConfig{Name: "Checkout", Checks: []string{"service-health"}})
+		}, "defined more than once"},
+		{"undefined SLO", func(c *Config) {
+			component(c, StatusPageComponentConfig{Name: "API", SLOs: []string{"api-availability"}})
+		}, "undefined SLO"},
+		{"defined SLO", func(c *Config) {
+			c.SLOs = map[string]SLOConfig{"api-availability": {SLI: "availability", Target: 99.9}}
+			component(c, StatusPageComponentConfig{Name: "API", SLOs: []string{"api-availability"}})
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(config)
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_MetricConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition MetricConditionConfig
+		wantErr   string
+	}{
+		{"valid", MetricConditionConfig{Name: "backlog", Metric: "queue_depth", Operator: ">", Threshold: 500, Status: "degraded"}, ""},
+		{"no metric", MetricConditionConfig{Name: "backlog", Operator: ">", Status: "degraded"}, "metric_conditions[0]"},
+		{"bad operator", MetricConditionConfig{Name: "backlog", Metric: "queue_depth", Operator: "==", Status: "degraded"}, "metric_conditions.backlog.operator"},
+		{"bad status", MetricConditionConfig{Name: "backlog", Metric: "queue_depth", Operator: ">", Status: "healthy"}, "metric_conditions.backlog.status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.MetricConditions = []MetricConditionConfig{tt.condition}
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_CustomResources(t *testing.T) {
+	kafka := CustomResourceConfig{Name: "kafka-clusters", Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"}
+	withConditions := func(conditions ...CustomResourceConditionConfig) CustomResourceConfig {
+		resource := kafka
+		resource.Conditions = conditions
+		return resource
+	}
+
+	tests := []struct {
+		name      string
+		resources []CustomResourceConfig
+		wantErr   string
+	}{
+		{"valid", []CustomResourceConfig{kafka}, ""},
+		{"valid conditions", []CustomResourceConfig{withConditions(
+			CustomResourceConditionConfig{Type: "Ready"},
+			CustomResourceConditionConfig{Type: "Degraded", Status: "False", Health: "degraded"},
+		)}, ""},
+		{"bad name", []CustomResourceConfig{{Name: "Kafka Clusters", Version: "v1", Kind: "Kafka"}}, "custom_resources[0].name"},
+		{"duplicate", []CustomResourceConfig{kafka, kafka}, "defined more than once"},
+		{"no kind", []CustomResourceConfig{{Name: "kafka", Version: "v1"}}, "custom_resources.kafka needs a version and a kind"},
+		{"no condition type", []CustomResourceConfig{withConditions(CustomResourceConditionConfig{Status: "True"})}, "need a type"},
+		{"bad status", []CustomResourceConfig{withConditions(CustomResourceConditionConfig{Type: "Ready", Status: "yes"})}, "conditions.Ready.status"},
+		{"bad health", []CustomResourceConfig{withConditions(CustomResourceConditionConfig{Type: "Ready", Health: "critical"})}, "conditions.Ready.health"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.CustomResources = tt.resources
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_CustomMetrics(t *testing.T) {
+	rps := CustomMetricConfig{Name: "checkout-rps", Metric: "http_requests_per_second", Namespace: "shop", Resource: "pods", Operator: "<", Threshold: 1}
+	queue := CustomMetricConfig{Name: "orders-queue", API: "external", Metric: "s0-rabbitmq-orders"}
+	with := func(modify func(*CustomMetricConfig)) CustomMetricConfig {
+		metric := rps
+		modify(&metric)
+		return metric
+	}
+
+	tests := []struct {
+		name    string
+		metrics []CustomMetricConfig
+		wantErr string
+	}{
+		{"valid", []CustomMetricConfig{rps, queue}, ""},
+		{"bad name", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.Name = "Checkout RPS" })}, "custom_metrics[0].name"},
+		{"duplicate", []CustomMetricConfig{rps, rps}, "defined more than once"},
+		{"no metric", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.Metric = "" })}, "needs a metric"},
+		{"no resource", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.Resource = "" })}, "needs the resource"},
+		{"bad api", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.API = "prometheus" })}, "api must be custom or external"},
+		{"bad operator", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.Operator = "==" })}, "operator"},
+		{"bad status", []CustomMetricConfig{with(func(m *CustomMetricConfig) { m.Status = "critical" })}, "status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.CustomMetrics = tt.metrics
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	config := GetDefaultConfig()
+	config.CustomResources = []CustomResourceConfig{{Name: "checkout-rps", Version: "v1", Kind: "Checkout"}}
+	config.CustomMetrics = []CustomMetricConfig{rps}
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "defined more than once") {
+		t.Errorf("expected a name shared with a custom resource check refused, got %v", err)
+	}
+}
+
+func TestCustomResourceConfig_PluralResource(t *testing.T) {
+	if got := (CustomResourceConfig{Kind: "Kafka"}).PluralResource(); got != "kafkas" {
+		t.Errorf("expected kafkas, got %s", got)
+	}
+	if got := (CustomResourceConfig{Kind: "Postgresql", Resource: "postgresqls"}).PluralResource(); got != "postgresqls" {
+		t.Errorf("expected the configured resource, got %s", got)
+	}
+}
+
 func TestYAMLTags(t *testing.T) {
 	// Test that struct tags are properly set for YAML marshaling
 	config := &Config{
@@ -522,3 +921,574 @@ func containsAtIndex(s, substr string) bool {
 	}
 	return false
 }
+
+func TestConfigValidation_AuthTokens(t *testing.T) {
+	token := func(name, value, role string) AuthTokenConfig {
+		return AuthTokenConfig{Name: name, Token: value, Role: role}
+	}
+	tests := []struct {
+		name    string
+		tokens  []AuthTokenConfig
+		wantErr string
+	}{
+		{"none", nil, ""},
+		{"valid", []AuthTokenConfig{token("dashboard", "viewer-token-0123456789", "viewer"), token("oncall", "admin-token-0123456789", "admin")}, ""},
+		{"no name", []AuthTokenConfig{token("", "viewer-token-0123456789", "viewer")}, "server.auth.tokens[0]"},
+		{"short token", []AuthTokenConfig{token("dashboard", "secret", "viewer")}, "at least 16 characters"},
+		{"bad role", []AuthTokenConfig{token("dashboard", "viewer-token-0123456789", "owner")}, "role must be viewer or admin"},
+		{"duplicate name", []AuthTokenConfig{token("dashboard", "viewer-token-0123456789", "viewer"), token("dashboard", "admin-token-0123456789", "admin")}, "defined more than once"},
+		{"duplicate token", []AuthTokenConfig{token("dashboard", "viewer-token-0123456789", "viewer"), token("oncall", "viewer-token-0123456789", "admin")}, "reuses another token"},
+		{"alerts scope", []AuthTokenConfig{{Name: "ci", Token: "alerts-token-0123456789", Role: "admin", Scope: "alerts", Cluster: "prod"}}, ""},
+		{"bad scope", []AuthTokenConfig{{Name: "ci", Token: "alerts-token-0123456789", Role: "admin", Scope: "metrics"}}, "scope must be empty or alerts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Server.Auth.Tokens = tt.tokens
+			err := validateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_ReconnectBackoff(t *testing.T) {
+	config := GetDefaultConfig()
+	config.UI.ReconnectDelay = 2 * time.Minute
+	config.UI.ReconnectMultiplier = 0
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.UI.ReconnectMaxDelay != 2*time.Minute || config.UI.ReconnectMultiplier != 2 {
+		t.Errorf("expected backoff defaults filled in, got max %s multiplier %v", config.UI.ReconnectMaxDelay, config.UI.ReconnectMultiplier)
+	}
+
+	config.UI.ReconnectJitter = 1.5
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "ui.reconnect_jitter") {
+		t.Errorf("expected a jitter error, got %v", err)
+	}
+}
+
+func TestConfigValidation_AnalysisQueue(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Server.MaxConcurrentAnalyses = 0
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Server.MaxConcurrentAnalyses != 2 {
+		t.Errorf("expected max_concurrent_analyses to default to 2, got %d", config.Server.MaxConcurrentAnalyses)
+	}
+
+	config.Server.MaxConcurrentAnalyses = -1
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "server.max_concurrent_analyses") {
+		t.Errorf("expected a max_concurrent_analyses error, got %v", err)
+	}
+	config.Server.MaxConcurrentAnalyses = 2
+	config.Server.AnalysisWait = config.Server.WriteTimeout
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "server.analysis_wait") {
+		t.Errorf("expected an analysis_wait error, got %v", err)
+	}
+}
+
+func TestConfigValidation_Delivery(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Alerts.Delivery = DeliveryConfig{}
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery := config.Alerts.Delivery; delivery.InitialBackoff != 30*time.Second || delivery.MaxBackoff != 30*time.Minute || delivery.MaxAttempts != 12 {
+		t.Errorf("expected delivery defaults filled in, got %+v", delivery)
+	}
+
+	config.Alerts.Delivery.MaxBackoff = 10 * time.Second
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "alerts.delivery.max_backoff") {
+		t.Errorf("expected a max_backoff error, got %v", err)
+	}
+	config.Alerts.Delivery.MaxBackoff = time.Minute
+	config.Alerts.Delivery.MaxAttempts = -1
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "alerts.delivery.max_attempts") {
+		t.Errorf("expected a max_attempts error, got %v", err)
+	}
+}
+
+func TestConfigValidation_ExternalURL(t *testing.T) {
+	for url, valid := range map[string]bool{
+		"":                              true,
+		"https://kubepulse.example.com": true,
+		"kubepulse.example.com":         false,
+		"ftp://kubepulse.example.com":   false,
+	} {
+		config := GetDefaultConfig()
+		config.Alerts.ExternalURL = url
+		if err := validateConfig(config); (err == nil) != valid {
+			t.Errorf("external_url %q: expected valid %v, got %v", url, valid, err)
+		}
+	}
+}
+
+func TestConfigValidation_ExpensiveInterval(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.ExpensiveInterval = 0
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Monitoring.ExpensiveInterval != 10*time.Minute {
+		t.Errorf("expected a 10m default, got %s", config.Monitoring.ExpensiveInterval)
+	}
+
+	config.Monitoring.ExpensiveInterval = 10 * time.Second
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.expensive_interval") {
+		t.Errorf("expected an expensive_interval error, got %v", err)
+	}
+}
+
+func TestConfigValidation_PermissionRecheckInterval(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.PermissionRecheckInterval = 0
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Monitoring.PermissionRecheckInterval != 10*time.Minute {
+		t.Errorf("expected a 10m default, got %s", config.Monitoring.PermissionRecheckInterval)
+	}
+
+	config.Monitoring.PermissionRecheckInterval = 10 * time.Second
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.permission_recheck_interval") {
+		t.Errorf("expected a permission_recheck_interval error, got %v", err)
+	}
+}
+
+func TestConfigValidation_AdaptiveInterval(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.AdaptiveInterval = AdaptiveIntervalConfig{Enabled: true}
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adaptive := config.Monitoring.AdaptiveInterval; adaptive.MinInterval != 10*time.Second || adaptive.MaxInterval != 5*time.Minute || adaptive.HealthyAfter != 30*time.Minute {
+		t.Errorf("expected defaults, got %+v", adaptive)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*AdaptiveIntervalConfig)
+		key    string
+	}{
+		{"min too small", func(a *AdaptiveIntervalConfig) { a.MinInterval = time.Millisecond }, "min_interval"},
+		{"min above interval", func(a *AdaptiveIntervalConfig) { a.MinInterval = time.Minute }, "min_interval"},
+		{"max below interval", func(a *AdaptiveIntervalConfig) { a.MaxInterval = 10 * time.Second }, "max_interval"},
+		{"negative healthy_after", func(a *AdaptiveIntervalConfig) { a.HealthyAfter = -time.Minute }, "healthy_after"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Monitoring.AdaptiveInterval.Enabled = true
+			tt.modify(&config.Monitoring.AdaptiveInterval)
+			if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.adaptive_interval."+tt.key) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_PodSecurity(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Monitoring.PodSecurity = PodSecurityConfig{}
+	if err := validateConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Monitoring.PodSecurity.DefaultLevel != "baseline" {
+		t.Errorf("expected a baseline default, got %q", config.Monitoring.PodSecurity.DefaultLevel)
+	}
+
+	config.Monitoring.PodSecurity.Namespaces = map[string]string{"payments": "strict"}
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "monitoring.pod_security.namespaces.payments") {
+		t.Errorf("expected a namespace level error, got %v", err)
+	}
+}
+
+func TestConfigValidation_Diagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*DiagnosticsConfig)
+		key    string
+	}{
+		{"defaults", func(d *DiagnosticsConfig) {}, ""},
+		{"disabled ignores settings", func(d *DiagnosticsConfig) { d.Enabled = false; d.MaxDumps = 0 }, ""},
+		{"no dump dir", func(d *DiagnosticsConfig) { d.DumpDir = "" }, "diagnostics.dump_dir"},
+		{"no dumps kept", func(d *DiagnosticsConfig) { d.MaxDumps = 0 }, "diagnostics.max_dumps"},
+		{"growth factor of 1", func(d *DiagnosticsConfig) { d.GrowthFactor = 1 }, "diagnostics.growth_factor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Diagnostics)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_LoadShedding(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*LoadSheddingConfig)
+		key    string
+	}{
+		{"defaults", func(l *LoadSheddingConfig) {}, ""},
+		{"disabled ignores settings", func(l *LoadSheddingConfig) { *l = LoadSheddingConfig{} }, ""},
+		{"no memory threshold", func(l *LoadSheddingConfig) { l.MemoryThreshold = 0 }, "load_shedding.memory_threshold"},
+		{"memory threshold above the limit", func(l *LoadSheddingConfig) { l.MemoryThreshold = 1.2 }, "load_shedding.memory_threshold"},
+		{"negative CPU threshold", func(l *LoadSheddingConfig) { l.CPUThreshold = -0.5 }, "load_shedding.cpu_threshold"},
+		{"no interval factor", func(l *LoadSheddingConfig) { l.IntervalFactor = 0 }, "load_shedding.interval_factor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.LoadShedding)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Chaos(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ChaosConfig)
+		key    string
+	}{
+		{"defaults", func(c *ChaosConfig) {}, ""},
+		{"disabled ignores settings", func(c *ChaosConfig) { *c = ChaosConfig{} }, ""},
+		{"auto-silence", func(c *ChaosConfig) { c.AutoSilence = true }, ""},
+		{"no interval", func(c *ChaosConfig) { c.Interval = 0 }, "chaos.interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Chaos)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Alertmanager(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*AlertmanagerConfig)
+		key    string
+	}{
+		{"defaults", func(c *AlertmanagerConfig) {}, ""},
+		{"sync", func(c *AlertmanagerConfig) { c.URL = "http://alertmanager:9093" }, ""},
+		{"disabled ignores interval", func(c *AlertmanagerConfig) { c.Interval = 0 }, ""},
+		{"relative URL", func(c *AlertmanagerConfig) { c.URL = "alertmanager:9093" }, "alerts.alertmanager.url"},
+		{"short interval", func(c *AlertmanagerConfig) {
+			c.URL, c.Interval = "https://alertmanager.example.com", time.Second
+		}, "alerts.alertmanager.interval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Alerts.Alertmanager)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_LatencyBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*LatencyBudgetConfig)
+		key    string
+	}{
+		{"defaults", func(b *LatencyBudgetConfig) {}, ""},
+		{"budget", func(b *LatencyBudgetConfig) { b.Detection = 2 * time.Minute }, ""},
+		{"unset percentile", func(b *LatencyBudgetConfig) { b.Percentile = 0 }, ""},
+		{"negative budget", func(b *LatencyBudgetConfig) { b.Detection = -time.Second }, "monitoring.latency_budget.detection"},
+		{"percentile over 100", func(b *LatencyBudgetConfig) { b.Percentile = 150 }, "monitoring.latency_budget.percentile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Monitoring.LatencyBudget)
+			err := validateConfig(config)
+			if tt.key == "" && (err != nil || config.Monitoring.LatencyBudget.Percentile != 90) {
+				t.Fatalf("expected the p90 default, got %+v, %v", config.Monitoring.LatencyBudget, err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_AlertRoutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		route AlertRouteConfig
+		key   string
+	}{
+		{"team", AlertRouteConfig{Team: "payments", Channel: "slack"}, ""},
+		{"app and tier", AlertRouteConfig{App: "checkout", Tier: "backend", Channel: "slack"}, ""},
+		{"no owner", AlertRouteConfig{Channel: "slack"}, "alerts.routes[0]"},
+		{"unknown channel", AlertRouteConfig{Team: "payments", Channel: "pager"}, "alerts.routes[0].channel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Alerts.Channels = map[string]ChannelConfig{"slack": {Type: "slack", Enabled: true}}
+			config.Alerts.Routes = []AlertRouteConfig{tt.route}
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Clusters(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster ClusterConfig
+		key     string
+	}{
+		{"token", ClusterConfig{Name: "prod", Server: "https://10.0.0.1:6443", Token: "secret"}, ""},
+		{"exec", ClusterConfig{Name: "eks", Server: "https://eks.example.com", Exec: &ExecAuthConfig{Command: "aws"}}, ""},
+		{"no name", ClusterConfig{Server: "https://10.0.0.1:6443", Token: "secret"}, "kubernetes.clusters[0].name"},
+		{"no server", ClusterConfig{Name: "prod", Token: "secret"}, "kubernetes.clusters[0].server"},
+		{"no credentials", ClusterConfig{Name: "prod", Server: "https://10.0.0.1:6443"}, "kubernetes.clusters[0]"},
+		{"two credentials", ClusterConfig{Name: "prod", Server: "https://10.0.0.1:6443", Token: "secret", TokenFile: "/var/run/token"}, "kubernetes.clusters[0]"},
+		{"exec without command", ClusterConfig{Name: "eks", Server: "https://eks.example.com", Exec: &ExecAuthConfig{}}, "kubernetes.clusters[0].exec.command"},
+		{"bad CA data", ClusterConfig{Name: "prod", Server: "https://10.0.0.1:6443", Token: "secret", CAData: "not base64!"}, "kubernetes.clusters[0].ca_data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.Kubernetes.Clusters = []ClusterConfig{tt.cluster}
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+
+	config := GetDefaultConfig()
+	config.Kubernetes.Clusters = []ClusterConfig{
+		{Name: "prod", Server: "https://a.example.com", Token: "a"},
+		{Name: "prod", Server: "https://b.example.com", Token: "b"},
+	}
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), "kubernetes.clusters[1].name") {
+		t.Errorf("expected a duplicate name error, got %v", err)
+	}
+}
+
+func TestConfigValidation_AIEvaluation(t *testing.T) {
+	variants := func() []AIVariantConfig {
+		return []AIVariantConfig{{Name: "sonnet", Model: "sonnet"}, {Name: "haiku", Model: "haiku"}}
+	}
+	tests := []struct {
+		name   string
+		modify func(*AIEvaluationConfig)
+		key    string
+	}{
+		{"disabled", func(e *AIEvaluationConfig) { e.Enabled = false; e.Variants = nil }, ""},
+		{"two models", func(e *AIEvaluationConfig) {}, ""},
+		{"one model", func(e *AIEvaluationConfig) { e.Variants = e.Variants[:1] }, "ai_evaluation.variants"},
+		{"sample rate", func(e *AIEvaluationConfig) { e.SampleRate = 1.5 }, "ai_evaluation.sample_rate"},
+		{"no name", func(e *AIEvaluationConfig) { e.Variants[0].Name = "" }, "ai_evaluation.variants[0].name"},
+		{"same name", func(e *AIEvaluationConfig) { e.Variants[1].Name = "sonnet" }, "ai_evaluation.variants[1].name"},
+		{"same model", func(e *AIEvaluationConfig) { e.Variants[1].Model = "sonnet" }, "ai_evaluation.variants"},
+		{"negative cost", func(e *AIEvaluationConfig) { e.Variants[1].InputCostPerMTokens = -1 }, "ai_evaluation.variants[1]"},
+		{"local model", func(e *AIEvaluationConfig) {
+			e.Variants[1] = AIVariantConfig{Name: "local", Provider: "ollama", Model: "sonnet"}
+		}, ""},
+		{"local without model", func(e *AIEvaluationConfig) { e.Variants[1] = AIVariantConfig{Name: "local", Provider: "ollama"} }, "ai_evaluation.variants[1]: ai.model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.AIEvaluation = AIEvaluationConfig{Enabled: true, Variants: variants()}
+			tt.modify(&config.AIEvaluation)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_AI(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*AIConfig)
+		key    string
+	}{
+		{"defaults", func(a *AIConfig) {}, ""},
+		{"claude cli", func(a *AIConfig) { a.Provider = "claude-cli" }, ""},
+		{"ollama", func(a *AIConfig) {
+			*a = AIConfig{Provider: "ollama", Model: "llama3.1:8b", BaseURL: "http://ollama:11434"}
+		}, ""},
+		{"unknown provider", func(a *AIConfig) { a.Provider = "bard" }, "ai.provider"},
+		{"api without model", func(a *AIConfig) { a.Provider = "openai" }, "ai.model"},
+		{"relative url", func(a *AIConfig) { *a = AIConfig{Provider: "openai", Model: "gpt-4o", BaseURL: "vllm:8000/v1"} }, "ai.base_url"},
+		{"negative timeout", func(a *AIConfig) { a.Timeout = -time.Second }, "ai.timeout"},
+		{"negative max tokens", func(a *AIConfig) { a.MaxTokens = -1 }, "ai.max_tokens"},
+		{"test scenario", func(a *AIConfig) { a.TestMode, a.TestScenario = true, "crashloop" }, ""},
+		{"unknown test scenario", func(a *AIConfig) { a.TestMode, a.TestScenario = true, "meltdown" }, "ai.test_scenario"},
+		{"test scenario without test mode", func(a *AIConfig) { a.TestScenario = "crashloop" }, "ai.test_mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.AI)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestAIConfig_KeyEnv(t *testing.T) {
+	tests := []struct {
+		config AIConfig
+		want   string
+	}{
+		{AIConfig{}, ""},
+		{AIConfig{Provider: "anthropic"}, "ANTHROPIC_API_KEY"},
+		{AIConfig{Provider: "openai"}, "OPENAI_API_KEY"},
+		{AIConfig{Provider: "openai", APIKeyEnv: "VLLM_TOKEN"}, "VLLM_TOKEN"},
+		{AIConfig{Provider: "ollama"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.config.KeyEnv(); got != tt.want {
+			t.Errorf("expected %q for %+v, got %q", tt.want, tt.config, got)
+		}
+	}
+}
+
+func TestConfigValidation_Cardinality(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*CardinalityConfig)
+		key    string
+	}{
+		{"defaults", func(c *CardinalityConfig) {}, ""},
+		{"unset limits", func(c *CardinalityConfig) { *c = CardinalityConfig{} }, ""},
+		{"negative series", func(c *CardinalityConfig) { c.MaxSeries = -1 }, "monitoring.cardinality.max_series"},
+		{"negative label values", func(c *CardinalityConfig) { c.MaxLabelValues = -1 }, "monitoring.cardinality.max_label_values"},
+		{"unknown action", func(c *CardinalityConfig) { c.Action = "truncate" }, "monitoring.cardinality.action"},
+		{"negative override", func(c *CardinalityConfig) {
+			c.Overrides = map[string]CardinalityLimitConfig{"latency": {MaxSeries: -5}}
+		}, "monitoring.cardinality.overrides.latency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Monitoring.Cardinality)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+			if tt.key == "" && (config.Monitoring.Cardinality.MaxSeries != 1000 || config.Monitoring.Cardinality.Action != "hash") {
+				t.Errorf("expected default limits, got %+v", config.Monitoring.Cardinality)
+			}
+		})
+	}
+}
+
+func TestConfigValidation_Federation(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	spoke := FederationPeerConfig{Name: "prod-us", URL: "https://kubepulse.prod-us.example.com", Secret: secret}
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		key    string
+	}{
+		{"none", func(c *Config) {}, ""},
+		{"hub", func(c *Config) {
+			c.Federation.Instance = "hub"
+			c.Federation.Spokes = []FederationPeerConfig{spoke}
+			c.Server.WriteTimeout = 2 * time.Minute
+		}, ""},
+		{"spoke", func(c *Config) {
+			c.Federation.Instance = "prod-us"
+			c.Federation.Hubs = []FederationPeerConfig{{Name: "hub", Secret: secret}}
+		}, ""},
+		{"no instance", func(c *Config) { c.Federation.Hubs = []FederationPeerConfig{{Name: "hub", Secret: secret}} }, "federation.instance"},
+		{"short secret", func(c *Config) {
+			c.Federation.Instance = "prod-us"
+			c.Federation.Hubs = []FederationPeerConfig{{Name: "hub", Secret: "secret"}}
+		}, "federation.hubs.hub.secret"},
+		{"spoke without url", func(c *Config) {
+			c.Federation.Instance = "hub"
+			c.Federation.Spokes = []FederationPeerConfig{{Name: "prod-us", Secret: secret}}
+			c.Server.WriteTimeout = 2 * time.Minute
+		}, "federation.spokes.prod-us.url"},
+		{"duplicate spoke", func(c *Config) {
+			c.Federation.Instance = "hub"
+			c.Federation.Spokes = []FederationPeerConfig{spoke, spoke}
+			c.Server.WriteTimeout = 2 * time.Minute
+		}, "defined more than once"},
+		{"timeout beyond write timeout", func(c *Config) {
+			c.Federation.Instance = "hub"
+			c.Federation.Spokes = []FederationPeerConfig{spoke}
+		}, "federation.timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(config)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Built-in cluster environments, from the one that matters most
+const (
+	EnvironmentProd    = "prod"
+	EnvironmentStaging = "staging"
+	EnvironmentDev     = "dev"
+)
+
+// EnvironmentsConfig classifies kubeconfig contexts as prod, staging, dev
+// or a custom environment, whose policy then shapes alerting and AI
+// analysis of the cluster
+type EnvironmentsConfig struct {
+	Contexts map[string]string `yaml:"contexts,omitempty" mapstructure:"contexts"` // Context name to environment
+	Default  string            `yaml:"default,omitempty" mapstructure:"default"`   // Environment of unlisted contexts; empty applies no policy
+
+	// Policies define custom environments or replace a built-in one's
+	Policies map[string]EnvironmentPolicyConfig `yaml:"policies,omitempty" mapstructure:"policies"`
+}
+
+// EnvironmentPolicyConfig is how alerting and AI analysis treat the
+// clusters of an environment
+type EnvironmentPolicyConfig struct {
+	MaxSeverity    string         `yaml:"max_severity,omitempty" mapstructure:"max_severity"`       // Alerts above it are lowered to it
+	Channel        string         `yaml:"channel,omitempty" mapstructure:"channel"`                 // Replaces rules' channels
+	CooldownFactor float64        `yaml:"cooldown_factor,omitempty" mapstructure:"cooldown_factor"` // Multiplies rules' cooldowns, to suppress noise
+	Escalate       bool           `yaml:"escalate" mapstructure:"escalate"`                         // Whether escalation policies page
+	AIMinSeverity  string         `yaml:"ai_min_severity,omitempty" mapstructure:"ai_min_severity"` // Failures below it get no automatic AI analysis
+	AIQueue        map[string]int `yaml:"ai_queue,omitempty" mapstructure:"ai_queue"`               // Pending AI analyses kept per severity
+}
+
+// builtinEnvironmentPolicies page for production only, and spend less of
+// the AI budget and tolerate more repeats the less a cluster matters
+var builtinEnvironmentPolicies = map[string]EnvironmentPolicyConfig{
+	EnvironmentProd: {MaxSeverity: "critical", CooldownFactor: 1, Escalate: true, AIMinSeverity: "info"},
+	EnvironmentStaging: {MaxSeverity: "warning", CooldownFactor: 2, AIMinSeverity: "warning",
+		AIQueue: map[string]int{"critical": 25, "warning": 10, "info": 5}},
+	EnvironmentDev: {MaxSeverity: "warning", CooldownFactor: 4, AIMinSeverity: "critical",
+		AIQueue: map[string]int{"critical": 10, "warning": 5, "info": 1}},
+}
+
+// EnvironmentFor returns the environment of a kubeconfig context, its entry
+// in Contexts else Default, and the environment's policy. It reports false
+// when the context has no environment.
+func (e EnvironmentsConfig) EnvironmentFor(context string) (string, EnvironmentPolicyConfig, bool) {
+	name, ok := e.Contexts[context]
+	if !ok || context == "" {
+		name = e.Default
+	}
+	if name == "" {
+		return "", EnvironmentPolicyConfig{}, false
+	}
+	policy, ok := e.policy(name)
+	return name, policy, ok
+}
+
+// policy returns an environment's policy; custom policies take precedence
+// over built-in ones of the same name
+func (e EnvironmentsConfig) policy(name string) (EnvironmentPolicyConfig, bool) {
+	if policy, ok := e.Policies[name]; ok {
+		return policy, true
+	}
+	policy, ok := builtinEnvironmentPolicies[name]
+	return policy, ok
+}
+
+// environmentNames returns the built-in environments, then the custom ones
+func (e EnvironmentsConfig) environmentNames() []string {
+	names := []string{EnvironmentProd, EnvironmentStaging, EnvironmentDev}
+	custom := make([]string, 0, len(e.Policies))
+	for name := range e.Policies {
+		if _, ok := builtinEnvironmentPolicies[name]; !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// validateEnvironments checks every environment used has a policy and
+// every policy's severities, factor and channel
+func validateEnvironments(e *EnvironmentsConfig, channels map[string]ChannelConfig) error {
+	known := func(name string) error {
+		if _, ok := e.policy(name); !ok {
+			return fmt.Errorf("unknown environment %q (available: %s)", name, strings.Join(e.environmentNames(), ", "))
+		}
+		return nil
+	}
+	for context, name := range e.Contexts {
+		if err := known(name); err != nil {
+			return fmt.Errorf("environments.contexts.%s: %w", context, err)
+		}
+	}
+	if e.Default != "" {
+		if err := known(e.Default); err != nil {
+			return fmt.Errorf("environments.default: %w", err)
+		}
+	}
+
+	severity := func(value string) bool {
+		return value == "" || value == "critical" || value == "warning" || value == "info"
+	}
+	for name, policy := range e.Policies {
+		if !severity(policy.MaxSeverity) {
+			return fmt.Errorf("environments.policies.%s.max_severity must be critical, warning or info", name)
+		}
+		if !severity(policy.AIMinSeverity) {
+			return fmt.Errorf("environments.policies.%s.ai_min_severity must be critical, warning or info", name)
+		}
+		if policy.CooldownFactor < 0 {
+			return fmt.Errorf("environments.policies.%s.cooldown_factor must not be negative", name)
+		}
+		if _, ok := channels[policy.Channel]; policy.Channel != "" && !ok {
+			return fmt.Errorf("environments.policies.%s.channel must name a channel in alerts.channels", name)
+		}
+		for level, capacity := range policy.AIQueue {
+			if level == "" || !severity(level) {
+				return fmt.Errorf("environments.policies.%s.ai_queue: unknown severity %q", name, level)
+			}
+			if capacity < 0 {
+				return fmt.Errorf("environments.policies.%s.ai_queue.%s must not be negative", name, level)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentsConfig_EnvironmentFor(t *testing.T) {
+	environments := EnvironmentsConfig{
+		Contexts: map[string]string{"prod-eu": EnvironmentProd, "kind-dev": EnvironmentDev, "perf": "load-test"},
+		Default:  EnvironmentStaging,
+		Policies: map[string]EnvironmentPolicyConfig{
+			EnvironmentDev: {MaxSeverity: "info", Channel: "slack"},
+			"load-test":    {MaxSeverity: "warning", CooldownFactor: 10},
+		},
+	}
+
+	tests := []struct {
+		context     string
+		environment string
+		severity    string
+		escalate    bool
+	}{
+		{"prod-eu", EnvironmentProd, "critical", true},
+		{"kind-dev", EnvironmentDev, "info", false},
+		{"perf", "load-test", "warning", false},
+		{"unlisted", EnvironmentStaging, "warning", false},
+	}
+	for _, tt := range tests {
+		name, policy, ok := environments.EnvironmentFor(tt.context)
+		if !ok || name != tt.environment || policy.MaxSeverity != tt.severity || policy.Escalate != tt.escalate {
+			t.Errorf("%s: got %s %+v, %v", tt.context, name, policy, ok)
+		}
+	}
+
+	environments.Default = ""
+	if _, _, ok := environments.EnvironmentFor("unlisted"); ok {
+		t.Error("expected no environment for an unlisted context without a default")
+	}
+}
+
+func TestConfigValidation_Environments(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*EnvironmentsConfig)
+		key    string
+	}{
+		{"none", func(e *EnvironmentsConfig) {}, ""},
+		{"built-in", func(e *EnvironmentsConfig) { e.Contexts = map[string]string{"prod-eu": EnvironmentProd} }, ""},
+		{"custom", func(e *EnvironmentsConfig) {
+			e.Default = "sandbox"
+			e.Policies = map[string]EnvironmentPolicyConfig{"sandbox": {MaxSeverity: "info", AIQueue: map[string]int{"critical": 2}}}
+		}, ""},
+		{"unknown environment", func(e *EnvironmentsConfig) { e.Contexts = map[string]string{"eu": "production"} }, "environments.contexts.eu"},
+		{"unknown default", func(e *EnvironmentsConfig) { e.Default = "qa" }, "environments.default"},
+		{"unknown severity", func(e *EnvironmentsConfig) {
+			e.Policies = map[string]EnvironmentPolicyConfig{EnvironmentDev: {MaxSeverity: "page"}}
+		}, "environments.policies.dev.max_severity"},
+		{"negative cooldown factor", func(e *EnvironmentsConfig) {
+			e.Policies = map[string]EnvironmentPolicyConfig{EnvironmentDev: {CooldownFactor: -1}}
+		}, "environments.policies.dev.cooldown_factor"},
+		{"unknown channel", func(e *EnvironmentsConfig) {
+			e.Policies = map[string]EnvironmentPolicyConfig{EnvironmentDev: {Channel: "slack"}}
+		}, "environments.policies.dev.channel"},
+		{"unknown AI queue severity", func(e *EnvironmentsConfig) {
+			e.Policies = map[string]EnvironmentPolicyConfig{EnvironmentDev: {AIQueue: map[string]int{"urgent": 1}}}
+		}, "environments.policies.dev.ai_queue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			tt.modify(&config.Environments)
+			err := validateConfig(config)
+			if tt.key == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.key != "" && (err == nil || !strings.Contains(err.Error(), tt.key)) {
+				t.Errorf("expected a %s error, got %v", tt.key, err)
+			}
+		})
+	}
+}
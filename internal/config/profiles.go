@@ -0,0 +1,424 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnvVar selects the configuration profile when --profile is not given
+const ProfileEnvVar = "KUBEPULSE_PROFILE"
+
+// Layer names recorded in Resolved.Sources. Profiles, environment variables
+// and flags are recorded as "profile:<name>", "env:<VAR>" and "flag:<--flag>".
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+)
+
+// envBindings maps environment variables to the keys they override
+var envBindings = []struct {
+	Env string
+	Key string
+}{
+	{"KUBEPULSE_KUBECONFIG", "kubernetes.kubeconfig"},
+	{"KUBEPULSE_INTERVAL", "monitoring.interval"},
+	{"KUBEPULSE_CHECK_PROFILE", "monitoring.check_profile"},
+	{"KUBEPULSE_ML_ENABLED", "ml.enabled"},
+	{"KUBEPULSE_PORT", "server.port"},
+	{"KUBEPULSE_HOST", "server.host"},
+	{"KUBEPULSE_WEB_ENABLED", "server.enable_web"},
+	{"KUBEPULSE_CORS_ENABLED", "server.cors_enabled"},
+	{"KUBEPULSE_UI_REFRESH", "ui.refresh_interval"},
+	{"KUBEPULSE_UI_THEME", "ui.theme"},
+	{"KUBEPULSE_UPDATE_CHECK", "updates.enabled"},
+	{"KUBEPULSE_BACKUP_LOCATION", "backup.location"},
+	{"KUBEPULSE_READ_ONLY", "read_only"},
+	{"KUBEPULSE_TELEMETRY", "telemetry.enabled"},
+}
+
+// Override sets a single key from a command-line flag
+type Override struct {
+	Key   string // Dotted YAML key, e.g. server.port
+	Value interface{}
+	Flag  string // Flag that set the value, e.g. --port
+}
+
+// LoadOptions selects the layers applied on top of the defaults
+type LoadOptions struct {
+	Path      string     // YAML config file; empty uses defaults and environment only
+	Profile   string     // From --profile; takes precedence over KUBEPULSE_PROFILE and the file
+	Overrides []Override // From command-line flags, applied last
+}
+
+// Resolved is a configuration with a record of where each value came from
+type Resolved struct {
+	Config  *Config
+	Profile string            // Selected profile, empty for none
+	Chain   []string          // Profiles applied, most general first
+	Sources map[string]string // Dotted key -> layer that set it
+}
+
+// Resolve builds the configuration from its layers, later layers winning:
+//
+//  1. built-in defaults
+//  2. top-level settings in the config file
+//  3. the selected profile, after the profiles it inherits from
+//  4. KUBEPULSE_* environment variables
+//  5. command-line flags
+//
+// Maps are merged key by key; scalars and lists replace earlier values.
+func Resolve(opts LoadOptions) (*Resolved, error) {
+	values, err := toValues(defaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	recordSources(values, "", SourceDefault, sources)
+
+	var profiles map[string]interface{}
+	fileProfile := ""
+	if opts.Path != "" {
+		file, err := loadFromFile(opts.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config from file: %w", err)
+		}
+		if profiles, err = profileDefinitions(file); err != nil {
+			return nil, fmt.Errorf("failed to load config from file: %w", err)
+		}
+		if name, ok := file["profile"].(string); ok {
+			fileProfile = name
+		}
+		delete(file, "profiles")
+		delete(file, "profile")
+		merge(values, file, "", SourceFile, sources)
+	}
+
+	resolved := &Resolved{Sources: sources}
+	switch {
+	case opts.Profile != "":
+		resolved.Profile = opts.Profile
+		sources["profile"] = "flag:--profile"
+	case os.Getenv(ProfileEnvVar) != "":
+		resolved.Profile = os.Getenv(ProfileEnvVar)
+		sources["profile"] = "env:" + ProfileEnvVar
+	case fileProfile != "":
+		resolved.Profile = fileProfile
+		sources["profile"] = SourceFile
+	}
+
+	if resolved.Profile != "" {
+		chain, err := profileChain(profiles, resolved.Profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load profile: %w", err)
+		}
+		for _, name := range chain {
+			overlay := profiles[name].(map[string]interface{})
+			merge(values, overlay, "", "profile:"+name, sources)
+		}
+		resolved.Chain = chain
+		values["profile"] = resolved.Profile
+	}
+
+	for _, binding := range envBindings {
+		raw, ok := os.LookupEnv(binding.Env)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setKey(values, binding.Key, parseScalar(raw), "env:"+binding.Env, sources); err != nil {
+			return nil, fmt.Errorf("failed to load config from environment: %s: %w", binding.Env, err)
+		}
+	}
+
+	for _, override := range opts.Overrides {
+		if err := setKey(values, override.Key, override.Value, "flag:"+override.Flag, sources); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", override.Flag, err)
+		}
+	}
+
+	config := &Config{}
+	if err := fromValues(values, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	resolved.Config = config
+	return resolved, nil
+}
+
+// Profiles returns the profile names defined in a config file
+func Profiles(path string) ([]string, error) {
+	file, err := loadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := profileDefinitions(file)
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(profiles), nil
+}
+
+// ProfileOverrides returns the settings a profile sets itself, as written in
+// the config file, and the profile it inherits from
+func ProfileOverrides(path, name string) (map[string]interface{}, string, error) {
+	file, err := loadFromFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	profiles, err := profileDefinitions(file)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, ok := profiles[name]; !ok {
+		return nil, "", unknownProfile(profiles, name)
+	}
+
+	overlay := profiles[name].(map[string]interface{})
+	parent, _ := overlay["inherits"].(string)
+	settings := make(map[string]interface{}, len(overlay))
+	for key, value := range overlay {
+		if key != "inherits" {
+			settings[key] = value
+		}
+	}
+	return settings, parent, nil
+}
+
+// AnnotatedYAML renders the configuration with a comment on every value
+// that did not come from the defaults, naming the layer that set it
+func (r *Resolved) AnnotatedYAML() ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(r.Config); err != nil {
+		return nil, err
+	}
+	annotate(&doc, "", r.Sources)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// annotate sets line comments on mapping values with a non-default source
+func annotate(node *yaml.Node, prefix string, sources map[string]string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := joinKey(prefix, key.Value)
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			annotate(value, path, sources)
+			continue
+		}
+		source, ok := sources[path]
+		if !ok || source == SourceDefault {
+			continue
+		}
+		if value.Kind == yaml.ScalarNode {
+			value.LineComment = source
+		} else {
+			key.LineComment = source
+		}
+	}
+}
+
+// profileDefinitions validates the profiles section of a config file
+func profileDefinitions(file map[string]interface{}) (map[string]interface{}, error) {
+	raw, ok := file["profiles"]
+	if !ok || raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	profiles, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("profiles must be a map of profile names to settings")
+	}
+
+	for name, definition := range profiles {
+		if definition == nil {
+			profiles[name] = map[string]interface{}{}
+			continue
+		}
+		overlay, ok := definition.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profile %q must be a map of settings", name)
+		}
+		if _, nested := overlay["profiles"]; nested {
+			return nil, fmt.Errorf("profile %q cannot define profiles", name)
+		}
+		if _, nested := overlay["profile"]; nested {
+			return nil, fmt.Errorf("profile %q cannot select a profile; use inherits", name)
+		}
+		if parent, ok := overlay["inherits"]; ok {
+			if _, ok := parent.(string); !ok {
+				return nil, fmt.Errorf("profile %q: inherits must be a profile name", name)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// profileChain returns the profile and its ancestors, most general first
+func profileChain(profiles map[string]interface{}, name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+	for current := name; current != ""; {
+		if seen[current] {
+			return nil, fmt.Errorf("profile %q inherits from itself via %s", name, strings.Join(append(chain, current), " -> "))
+		}
+		definition, ok := profiles[current]
+		if !ok {
+			return nil, unknownProfile(profiles, current)
+		}
+		seen[current] = true
+		chain = append(chain, current)
+		current, _ = definition.(map[string]interface{})["inherits"].(string)
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// unknownProfile reports a missing profile with the ones that exist
+func unknownProfile(profiles map[string]interface{}, name string) error {
+	if len(profiles) == 0 {
+		return fmt.Errorf("profile %q not found: no profiles are defined", name)
+	}
+	return fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(sortedKeys(profiles), ", "))
+}
+
+// merge deep-merges src into dst, recording the source of every value set
+func merge(dst, src map[string]interface{}, prefix, source string, sources map[string]string) {
+	for key, value := range src {
+		if key == "inherits" && strings.HasPrefix(source, "profile:") && prefix == "" {
+			continue
+		}
+		path := joinKey(prefix, key)
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			if dstMap, ok := dst[key].(map[string]interface{}); ok {
+				merge(dstMap, srcMap, path, source, sources)
+				continue
+			}
+		}
+
+		for existing := range sources {
+			if existing == path || strings.HasPrefix(existing, path+".") {
+				delete(sources, existing)
+			}
+		}
+		dst[key] = copyValue(value)
+		recordSources(value, path, source, sources)
+	}
+}
+
+// setKey sets a dotted key after checking the value decodes into Config
+func setKey(values map[string]interface{}, key string, value interface{}, source string, sources map[string]string) error {
+	overlay := value
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		overlay = map[string]interface{}{parts[i]: overlay}
+	}
+
+	if err := fromValues(overlay.(map[string]interface{}), &Config{}); err != nil {
+		return err
+	}
+	merge(values, overlay.(map[string]interface{}), "", source, sources)
+	return nil
+}
+
+// recordSources marks every leaf below path as coming from source
+func recordSources(value interface{}, path, source string, sources map[string]string) {
+	if m, ok := value.(map[string]interface{}); ok && (len(m) > 0 || path == "") {
+		for key, child := range m {
+			recordSources(child, joinKey(path, key), source, sources)
+		}
+		return
+	}
+	sources[path] = source
+}
+
+// parseScalar interprets an environment value as a YAML scalar so numbers,
+// booleans and durations decode like they do in the file
+func parseScalar(raw string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return raw
+	}
+	return value
+}
+
+// copyValue deep-copies maps and lists so layers never share state
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = copyValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = copyValue(child)
+		}
+		return copied
+	}
+	return value
+}
+
+// toValues converts a Config into generic YAML values
+func toValues(config *Config) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// fromValues decodes generic YAML values into a Config
+func fromValues(values map[string]interface{}, config *Config) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, config)
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const profilesYAML = `
+server:
+  port: 8080
+alerts:
+  channels:
+    slack:
+      type: slack
+      enabled: false
+      settings:
+        webhook: https://hooks.example.com/slack
+profile: dev
+profiles:
+  dev:
+    ui:
+      theme: dark
+  staging:
+    monitoring:
+      interval: 1m
+    kubernetes:
+      namespaces: [apps]
+  prod:
+    inherits: staging
+    server:
+      port: 9090
+    alerts:
+      channels:
+        slack:
+          enabled: true
+`
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kubepulse.yaml")
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestResolve_Profiles(t *testing.T) {
+	path := writeConfig(t, profilesYAML)
+
+	tests := []struct {
+		name      string
+		opts      LoadOptions
+		env       map[string]string
+		profile   string
+		chain     []string
+		port      int
+		theme     string
+		sources   map[string]string
+		slackHook bool
+	}{
+		{
+			name:    "file selects default profile",
+			opts:    LoadOptions{Path: path},
+			profile: "dev",
+			chain:   []string{"dev"},
+			port:    8080,
+			theme:   "dark",
+			sources: map[string]string{"server.port": SourceFile, "ui.theme": "profile:dev", "profile": SourceFile},
+		},
+		{
+			name:    "inherited profile merges maps",
+			opts:    LoadOptions{Path: path, Profile: "prod"},
+			profile: "prod",
+			chain:   []string{"staging", "prod"},
+			port:    9090,
+			theme:   "system",
+			sources: map[string]string{
+				"server.port":                   "profile:prod",
+				"monitoring.interval":           "profile:staging",
+				"alerts.channels.slack.enabled": "profile:prod",
+				"ui.theme":                      SourceDefault,
+				"profile":                       "flag:--profile",
+			},
+			slackHook: true,
+		},
+		{
+			name:    "environment selects profile and overrides it",
+			opts:    LoadOptions{Path: path},
+			env:     map[string]string{ProfileEnvVar: "prod", "KUBEPULSE_PORT": "9999"},
+			profile: "prod",
+			chain:   []string{"staging", "prod"},
+			port:    9999,
+			theme:   "system",
+			sources: map[string]string{"server.port": "env:KUBEPULSE_PORT", "profile": "env:" + ProfileEnvVar},
+		},
+		{
+			name: "flags override everything",
+			opts: LoadOptions{Path: path, Profile: "prod", Overrides: []Override{
+				{Key: "server.port", Value: 7000, Flag: "--port"},
+			}},
+			env:     map[string]string{"KUBEPULSE_PORT": "9999"},
+			profile: "prod",
+			chain:   []string{"staging", "prod"},
+			port:    7000,
+			theme:   "system",
+			sources: map[string]string{"server.port": "flag:--port"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			resolved, err := Resolve(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved.Profile != tt.profile || resolved.Config.Profile != tt.profile {
+				t.Errorf("expected profile %q, got %q / %q", tt.profile, resolved.Profile, resolved.Config.Profile)
+			}
+			if !reflect.DeepEqual(resolved.Chain, tt.chain) {
+				t.Errorf("expected chain %v, got %v", tt.chain, resolved.Chain)
+			}
+			if resolved.Config.Server.Port != tt.port {
+				t.Errorf("expected port %d, got %d", tt.port, resolved.Config.Server.Port)
+			}
+			if resolved.Config.UI.Theme != tt.theme {
+				t.Errorf("expected theme %q, got %q", tt.theme, resolved.Config.UI.Theme)
+			}
+			for key, source := range tt.sources {
+				if resolved.Sources[key] != source {
+					t.Errorf("expected %s from %s, got %s", key, source, resolved.Sources[key])
+				}
+			}
+			if tt.slackHook {
+				slack := resolved.Config.Alerts.Channels["slack"]
+				if !slack.Enabled || slack.Settings["webhook"] != "https://hooks.example.com/slack" {
+					t.Errorf("expected slack enabled with the file's webhook, got %+v", slack)
+				}
+				if resolved.Config.Monitoring.Interval != time.Minute {
+					t.Errorf("expected inherited interval, got %v", resolved.Config.Monitoring.Interval)
+				}
+			}
+		})
+	}
+}
+
+func TestResolve_ProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		want    string
+	}{
+		{
+			name:    "unknown profile",
+			config:  profilesYAML,
+			profile: "qa",
+			want:    "available: dev, prod, staging",
+		},
+		{
+			name:    "inheritance cycle",
+			config:  "profiles:\n  a:\n    inherits: b\n  b:\n    inherits: a\n",
+			profile: "a",
+			want:    "inherits from itself",
+		},
+		{
+			name:    "missing parent",
+			config:  "profiles:\n  a:\n    inherits: base\n",
+			profile: "a",
+			want:    `profile "base" not found`,
+		},
+		{
+			name:    "nested profiles",
+			config:  "profiles:\n  a:\n    profiles: {}\n",
+			profile: "a",
+			want:    "cannot define profiles",
+		},
+		{
+			name:    "invalid value",
+			config:  "profiles:\n  a:\n    server:\n      port: lots\n",
+			profile: "a",
+			want:    "invalid configuration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve(LoadOptions{Path: writeConfig(t, tt.config), Profile: tt.profile})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestResolve_InvalidEnvironment(t *testing.T) {
+	t.Setenv("KUBEPULSE_PORT", "not-a-port")
+	_, err := Resolve(LoadOptions{})
+	if err == nil || !strings.Contains(err.Error(), "KUBEPULSE_PORT") {
+		t.Errorf("expected error naming the variable, got %v", err)
+	}
+}
+
+func TestResolve_ProfileWithoutFile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "prod")
+	if _, err := Resolve(LoadOptions{}); err == nil {
+		t.Error("expected error for a profile without a config file")
+	}
+}
+
+func TestResolved_AnnotatedYAML(t *testing.T) {
+	t.Setenv("KUBEPULSE_UI_THEME", "light")
+	resolved, err := Resolve(LoadOptions{Path: writeConfig(t, profilesYAML), Profile: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := resolved.AnnotatedYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"port: 9090 # profile:prod",
+		"interval: 1m0s # profile:staging",
+		"namespaces: # profile:staging",
+		"theme: light # env:KUBEPULSE_UI_THEME",
+		"profile: prod # flag:--profile",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "# default") {
+		t.Errorf("expected default values to be unannotated:\n%s", out)
+	}
+}
+
+func TestProfileOverrides(t *testing.T) {
+	path := writeConfig(t, profilesYAML)
+
+	settings, parent, err := ProfileOverrides(path, "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parent != "staging" {
+		t.Errorf("expected parent staging, got %q", parent)
+	}
+	if _, ok := settings["inherits"]; ok {
+		t.Error("expected inherits to be reported separately")
+	}
+	if _, ok := settings["server"]; !ok {
+		t.Errorf("expected server overrides, got %v", settings)
+	}
+
+	profiles, err := Profiles(path)
+	if err != nil || !reflect.DeepEqual(profiles, []string{"dev", "prod", "staging"}) {
+		t.Errorf("expected sorted profiles, got %v (%v)", profiles, err)
+	}
+}
+
+func TestLoadConfig_Example(t *testing.T) {
+	path, err := filepath.Abs(filepath.Join("..", "..", ".kubepulse.yaml.example"))
+	if err != nil {
+		t.Fatalf("failed to resolve example path: %v", err)
+	}
+
+	for _, profile := range []string{"", "dev", "staging", "prod"} {
+		if _, err := LoadConfigWithOptions(LoadOptions{Path: path, Profile: profile}); err != nil {
+			t.Errorf("example config with profile %q failed to load: %v", profile, err)
+		}
+	}
+}
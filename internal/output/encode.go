@@ -0,0 +1,62 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteJSON writes a value as indented JSON
+func WriteJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode JSON output: %w", err)
+	}
+	return nil
+}
+
+// WriteYAML writes a value as YAML with two-space indentation. The value is
+// encoded through JSON first, so field names, omitted fields and custom
+// marshalers match the JSON output exactly.
+func WriteYAML(w io.Writer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+
+	// JSON is YAML; decoding into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quoting decoded from JSON, leaving the
+// encoder to quote only the strings that need it. Strings YAML 1.1 parsers
+// would read as booleans stay quoted.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && yaml11Bools[strings.ToLower(node.Value)] {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// yaml11Bools are the plain scalars YAML 1.1 resolves to booleans
+var yaml11Bools = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true,
+}
@@ -0,0 +1,181 @@
+// Package output renders command results for people, as aligned tables and
+// text, or for scripts, as JSON or YAML, and applies the color, quiet and
+// character set settings every command shares.
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Format is how a command renders its result
+type Format string
+
+// Supported formats
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+// ParseFormat parses an --output value. "text" and "summary", the names
+// commands used before formats were shared, are accepted for table.
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "table", "text", "summary":
+		return FormatTable, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported output format %q (use table, json or yaml)", value)
+}
+
+// Options configures a Printer
+type Options struct {
+	Format  Format
+	NoColor bool // Never color, even on a terminal
+	Quiet   bool // Drop progress and informational messages
+}
+
+// Printer writes a command's result to stdout and its progress messages to
+// stderr, so stdout stays parseable in the JSON and YAML formats
+type Printer struct {
+	out     io.Writer
+	errOut  io.Writer
+	format  Format
+	color   bool
+	unicode bool
+	quiet   bool
+
+	documents int // YAML documents written, to separate a stream of them
+}
+
+// NewPrinter creates a printer. Color is only used when out is a terminal
+// and neither NoColor nor the NO_COLOR environment variable disables it.
+func NewPrinter(out, errOut io.Writer, opts Options) *Printer {
+	if opts.Format == "" {
+		opts.Format = FormatTable
+	}
+	return &Printer{
+		out:     out,
+		errOut:  errOut,
+		format:  opts.Format,
+		color:   !opts.NoColor && ColorSupported(out),
+		unicode: UnicodeSupported(),
+		quiet:   opts.Quiet,
+	}
+}
+
+// Out returns the writer results go to
+func (p *Printer) Out() io.Writer {
+	return p.out
+}
+
+// Format returns the format results are rendered in
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+// Quiet reports whether informational messages are dropped
+func (p *Printer) Quiet() bool {
+	return p.quiet
+}
+
+// ColorEnabled reports whether output may contain ANSI escape sequences
+func (p *Printer) ColorEnabled() bool {
+	return p.color
+}
+
+// Print renders a result: value is encoded for JSON and YAML, and human
+// writes the table format
+func (p *Printer) Print(value interface{}, human func(w io.Writer) error) error {
+	switch p.format {
+	case FormatJSON:
+		return WriteJSON(p.out, value)
+	case FormatYAML:
+		if p.documents > 0 {
+			if _, err := fmt.Fprintln(p.out, "---"); err != nil {
+				return err
+			}
+		}
+		p.documents++
+		return WriteYAML(p.out, value)
+	default:
+		return human(p.out)
+	}
+}
+
+// Infof writes a progress or informational message to stderr unless quiet.
+// A trailing newline is added when missing.
+func (p *Printer) Infof(format string, args ...interface{}) {
+	if p.quiet {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+	_, _ = io.WriteString(p.errOut, message)
+}
+
+// Color is an ANSI foreground color
+type Color string
+
+// Colors used for statuses and severities
+const (
+	Red    Color = "\033[31m"
+	Yellow Color = "\033[33m"
+	Green  Color = "\033[32m"
+	Blue   Color = "\033[34m"
+
+	reset = "\033[0m"
+)
+
+// Colorize wraps text in a color when color is enabled
+func (p *Printer) Colorize(color Color, text string) string {
+	if !p.color || color == "" {
+		return text
+	}
+	return string(color) + text + reset
+}
+
+// Symbol returns the Unicode symbol, or its ASCII fallback when the
+// terminal's character set can't display it
+func (p *Printer) Symbol(unicode, ascii string) string {
+	if p.unicode {
+		return unicode
+	}
+	return ascii
+}
+
+// ColorSupported reports whether w is a terminal that should get color.
+// NO_COLOR (https://no-color.org) and TERM=dumb disable it.
+func ColorSupported(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// UnicodeSupported reports whether the locale's character set is UTF-8, so
+// symbols and emoji render instead of appearing as replacement characters.
+// Like setlocale, LC_ALL wins over LC_CTYPE, which wins over LANG; without
+// any of them UTF-8 is assumed.
+func UnicodeSupported() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		locale = strings.ToLower(locale)
+		return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	}
+	return true
+}
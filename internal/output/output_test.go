@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatTable, false},
+		{"table", FormatTable, false},
+		{"text", FormatTable, false},
+		{"summary", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{"yaml", FormatYAML, false},
+		{"yml", FormatYAML, false},
+		{"xml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+type report struct {
+	Name     string        `json:"name"`
+	Answer   string        `json:"answer"`
+	Version  string        `json:"version"`
+	Checks   []string      `json:"checks"`
+	Duration time.Duration `json:"duration"`
+	Empty    string        `json:"empty,omitempty"`
+}
+
+func TestPrinter_Print(t *testing.T) {
+	value := report{Name: "pod-health", Answer: "yes", Version: "1.0", Checks: []string{"a", "b"}, Duration: time.Second}
+	human := func(w io.Writer) error {
+		_, err := io.WriteString(w, "pod-health is fine\n")
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := NewPrinter(&out, io.Discard, Options{Format: FormatTable}).Print(value, human); err != nil {
+		t.Fatalf("table: %v", err)
+	}
+	if out.String() != "pod-health is fine\n" {
+		t.Errorf("unexpected table output %q", out.String())
+	}
+
+	out.Reset()
+	if err := NewPrinter(&out, io.Discard, Options{Format: FormatJSON}).Print(value, human); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded report
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded.Name != "pod-health" {
+		t.Errorf("expected JSON output, got %q (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	printer := NewPrinter(&out, io.Discard, Options{Format: FormatYAML})
+	if err := printer.Print(value, human); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	want := `name: pod-health
+answer: "yes"
+version: "1.0"
+checks:
+  - a
+  - b
+duration: 1000000000
+`
+	if out.String() != want {
+		t.Errorf("YAML output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// A stream of YAML results, e.g. from watch mode, is one document each
+	if err := printer.Print(value, human); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	if !strings.Contains(out.String(), "\n---\nname: pod-health") {
+		t.Errorf("expected documents separated by ---, got\n%s", out.String())
+	}
+}
+
+func TestPrinter_QuietAndColor(t *testing.T) {
+	var out, errOut bytes.Buffer
+	printer := NewPrinter(&out, &errOut, Options{Quiet: true})
+	printer.Infof("Running %s", "pod-health")
+	if errOut.Len() != 0 {
+		t.Errorf("expected no messages in quiet mode, got %q", errOut.String())
+	}
+
+	printer = NewPrinter(&out, &errOut, Options{})
+	printer.Infof("Running %s", "pod-health")
+	if errOut.String() != "Running pod-health\n" || out.Len() != 0 {
+		t.Errorf("expected the message on stderr only, got stdout %q stderr %q", out.String(), errOut.String())
+	}
+
+	// A buffer is not a terminal, so nothing is colored
+	if got := printer.Colorize(Red, "unhealthy"); got != "unhealthy" {
+		t.Errorf("expected no color outside a terminal, got %q", got)
+	}
+}
+
+func TestUnicodeSupported(t *testing.T) {
+	tests := []struct {
+		lcAll, lcCtype, lang string
+		want                 bool
+	}{
+		{"", "", "", true},
+		{"", "", "de_DE.UTF-8", true},
+		{"", "", "ja_JP.utf8", true},
+		{"", "", "C", false},
+		{"", "ru_RU.KOI8-R", "en_US.UTF-8", false},
+		{"en_US.UTF-8", "POSIX", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", tt.lcCtype)
+		t.Setenv("LANG", tt.lang)
+		if got := UnicodeSupported(); got != tt.want {
+			t.Errorf("UnicodeSupported() with LC_ALL=%q LC_CTYPE=%q LANG=%q = %v, want %v",
+				tt.lcAll, tt.lcCtype, tt.lang, got, tt.want)
+		}
+	}
+
+	t.Setenv("LC_ALL", "C")
+	printer := NewPrinter(io.Discard, io.Discard, Options{})
+	if got := printer.Symbol("✓", "OK"); got != "OK" {
+		t.Errorf("expected the ASCII fallback, got %q", got)
+	}
+}
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// columnGap separates table columns
+const columnGap = "   "
+
+// ansiEscape matches the color sequences Colorize adds
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// Table is a set of rows written with aligned columns. Unlike
+// text/tabwriter it aligns by display width, so columns holding CJK text or
+// emoji line up.
+type Table struct {
+	headers []string
+	rows    [][]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// AddRow appends a row; missing cells are left blank
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the headers and rows
+func (t *Table) Render(w io.Writer) error {
+	rows := t.rows
+	if len(t.headers) > 0 {
+		rows = append([][]string{t.headers}, rows...)
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if cellWidth := Width(cell); cellWidth > widths[i] {
+				widths[i] = cellWidth
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		line := make([]string, len(row))
+		for i, cell := range row {
+			if i == len(row)-1 {
+				line[i] = cell
+			} else {
+				line[i] = PadRight(cell, widths[i])
+			}
+		}
+		b.WriteString(strings.TrimRight(strings.Join(line, columnGap), " "))
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Width returns how many terminal columns text occupies: East Asian wide and
+// fullwidth characters take two, combining marks and format characters such
+// as emoji variation selectors take none, and color sequences are ignored
+func Width(text string) int {
+	text = ansiEscape.ReplaceAllString(text, "")
+	columns := 0
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		case width.LookupRune(r).Kind() == width.EastAsianWide, width.LookupRune(r).Kind() == width.EastAsianFullwidth:
+			columns += 2
+		default:
+			columns++
+		}
+	}
+	return columns
+}
+
+// PadRight pads text with spaces to the given display width
+func PadRight(text string, columns int) string {
+	if padding := columns - Width(text); padding > 0 {
+		return text + strings.Repeat(" ", padding)
+	}
+	return text
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"pod-health", 10},
+		{"ポッド", 6},
+		{"节点健康", 8},
+		{"Größe", 5},
+		{"✅", 2},
+		{"⚠️", 1},
+		{string(Red) + "red" + reset, 3},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.text); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTable_Render(t *testing.T) {
+	table := NewTable("CHECK", "STATUS", "MESSAGE")
+	table.AddRow("ポッド", "healthy", "すべて正常")
+	table.AddRow("node-health", "degraded", "1 node NotReady")
+	table.AddRow("svc")
+
+	var buf bytes.Buffer
+	if err := table.Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "CHECK         STATUS     MESSAGE\n" +
+		"ポッド        healthy    すべて正常\n" +
+		"node-health   degraded   1 node NotReady\n" +
+		"svc\n"
+	if buf.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", buf.String(), want)
+	}
+	if table.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", table.Len())
+	}
+}
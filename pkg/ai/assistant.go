@@ -42,12 +42,13 @@ type Pattern struct {
 
 // QueryResponse represents assistant's response to a query
 type QueryResponse struct {
-	Answer     string   `json:"answer"`
-	Confidence float64  `json:"confidence"`
-	Actions    []string `json:"suggested_actions,omitempty"`
-	Commands   []string `json:"commands,omitempty"`
-	References []string `json:"references,omitempty"`
-	Followup   []string `json:"followup_questions,omitempty"`
+	Answer     string     `json:"answer"`
+	Confidence float64    `json:"confidence"`
+	Actions    []string   `json:"suggested_actions,omitempty"`
+	Commands   []string   `json:"commands,omitempty"`
+	References []string   `json:"references,omitempty"`
+	Evidence   []Evidence `json:"evidence,omitempty"`
+	Followup   []string   `json:"followup_questions,omitempty"`
 }
 
 // NewAssistant creates a new AI assistant
@@ -67,9 +68,11 @@ func NewAssistant(client *Client) *Assistant {
 func (a *Assistant) Query(ctx context.Context, question string, clusterHealth *ClusterHealth) (*QueryResponse, error) {
 	klog.V(2).Infof("Processing natural language query: %s", question)
 
+	evidence := CollectEvidence(clusterHealth)
+
 	request := AnalysisRequest{
 		Type:    AnalysisTypeSummary,
-		Context: "Natural language query from user",
+		Context: "Natural language query from user" + evidencePrompt(evidence),
 		Data: map[string]interface{}{
 			"user_question":   question,
 			"cluster_context": a.knowledge.clusterContext,
@@ -81,15 +84,15 @@ func (a *Assistant) Query(ctx context.Context, question string, clusterHealth *C
 
 	// Special handling for common query types
 	if a.isPerformanceQuery(question) {
-		return a.handlePerformanceQuery(ctx, question, clusterHealth)
+		return a.handlePerformanceQuery(ctx, question, clusterHealth, evidence)
 	}
 
 	if a.isTroubleshootingQuery(question) {
-		return a.handleTroubleshootingQuery(ctx, question, clusterHealth)
+		return a.handleTroubleshootingQuery(ctx, question, clusterHealth, evidence)
 	}
 
 	if a.isOptimizationQuery(question) {
-		return a.handleOptimizationQuery(ctx, question, clusterHealth)
+		return a.handleOptimizationQuery(ctx, question, clusterHealth, evidence)
 	}
 
 	// General query handling
@@ -104,6 +107,7 @@ func (a *Assistant) Query(ctx context.Context, question string, clusterHealth *C
 		Actions:    a.extractActions(response),
 		Commands:   a.extractCommands(response),
 		References: a.extractReferences(response),
+		Evidence:   attachEvidence(response, evidence),
 		Followup:   a.generateFollowupQuestions(question, response),
 	}, nil
 }
@@ -133,7 +137,7 @@ func (a *Assistant) UpdateContext(key string, value interface{}) {
 }
 
 // handlePerformanceQuery handles performance-related questions
-func (a *Assistant) handlePerformanceQuery(ctx context.Context, question string, health *ClusterHealth) (*QueryResponse, error) {
+func (a *Assistant) handlePerformanceQuery(ctx context.Context, question string, health *ClusterHealth, evidence []Evidence) (*QueryResponse, error) {
 	prompt := fmt.Sprintf(`Performance Analysis Query:
 Question: %s
 
@@ -153,7 +157,7 @@ Focus on actionable insights.`,
 
 	request := AnalysisRequest{
 		Type:        AnalysisTypeOptimization,
-		Context:     prompt,
+		Context:     prompt + evidencePrompt(evidence),
 		ClusterInfo: health,
 		Timestamp:   time.Now(),
 	}
@@ -163,11 +167,11 @@ Focus on actionable insights.`,
 		return nil, err
 	}
 
-	return a.formatResponse(response), nil
+	return a.formatResponse(response, evidence), nil
 }
 
 // handleTroubleshootingQuery handles troubleshooting questions
-func (a *Assistant) handleTroubleshootingQuery(ctx context.Context, question string, health *ClusterHealth) (*QueryResponse, error) {
+func (a *Assistant) handleTroubleshootingQuery(ctx context.Context, question string, health *ClusterHealth, evidence []Evidence) (*QueryResponse, error) {
 	// Find relevant failing checks
 	failingChecks := []CheckResult{}
 	for _, check := range health.Checks {
@@ -194,7 +198,7 @@ Provide:
 
 	request := AnalysisRequest{
 		Type:        AnalysisTypeRootCause,
-		Context:     prompt,
+		Context:     prompt + evidencePrompt(evidence),
 		ClusterInfo: health,
 		Timestamp:   time.Now(),
 	}
@@ -204,13 +208,14 @@ Provide:
 		return nil, err
 	}
 
-	return a.formatResponse(response), nil
+	return a.formatResponse(response, evidence), nil
 }
 
 // handleOptimizationQuery handles optimization questions
-func (a *Assistant) handleOptimizationQuery(ctx context.Context, question string, health *ClusterHealth) (*QueryResponse, error) {
+func (a *Assistant) handleOptimizationQuery(ctx context.Context, question string, health *ClusterHealth, evidence []Evidence) (*QueryResponse, error) {
 	// Analyze resource usage patterns
 	predictions, _ := a.analyzer.AnalyzeTrends(ctx, a.extractMetrics(health))
+	upgrades := upgradeRecommendations(health)
 
 	prompt := fmt.Sprintf(`Optimization Query:
 Question: %s
@@ -224,13 +229,18 @@ Provide:
 3. Cost-saving suggestions
 4. Implementation commands`,
 		question, predictions)
+	if len(upgrades) > 0 {
+		prompt += "\n\nUpgrades recommended by health checks; include them, ordered so the cluster stays within supported version skew:\n- " +
+			strings.Join(upgrades, "\n- ")
+	}
 
 	request := AnalysisRequest{
 		Type:        AnalysisTypeOptimization,
-		Context:     prompt,
+		Context:     prompt + evidencePrompt(evidence),
 		ClusterInfo: health,
 		Data: map[string]interface{}{
-			"predictions": predictions,
+			"predictions":             predictions,
+			"upgrade_recommendations": upgrades,
 		},
 		Timestamp: time.Now(),
 	}
@@ -240,7 +250,7 @@ Provide:
 		return nil, err
 	}
 
-	return a.formatResponse(response), nil
+	return a.formatResponse(response, evidence), nil
 }
 
 // Query type detection helpers
@@ -267,7 +277,7 @@ func (a *Assistant) isTroubleshootingQuery(q string) bool {
 }
 
 func (a *Assistant) isOptimizationQuery(q string) bool {
-	keywords := []string{"optimize", "improve", "reduce", "cost", "efficient", "save", "scale"}
+	keywords := []string{"optimize", "improve", "reduce", "cost", "efficient", "save", "scale", "upgrade"}
 	q = strings.ToLower(q)
 	for _, kw := range keywords {
 		if strings.Contains(q, kw) {
@@ -344,17 +354,38 @@ func (a *Assistant) generateFollowupQuestions(original string, response *Analysi
 	return followups
 }
 
-func (a *Assistant) formatResponse(response *AnalysisResponse) *QueryResponse {
+func (a *Assistant) formatResponse(response *AnalysisResponse, evidence []Evidence) *QueryResponse {
 	return &QueryResponse{
 		Answer:     response.Summary,
 		Confidence: response.Confidence,
 		Actions:    a.extractActions(response),
 		Commands:   a.extractCommands(response),
 		References: a.extractReferences(response),
+		Evidence:   attachEvidence(response, evidence),
 		Followup:   []string{},
 	}
 }
 
+// upgradeRecommendations collects the upgrades checks recommend under
+// core.DetailUpgradeRecommendations, such as kubelets outside the supported
+// version skew or nodes waiting for a reboot
+func upgradeRecommendations(health *ClusterHealth) []string {
+	var upgrades []string
+	for _, check := range health.Checks {
+		switch list := check.Details["upgrade_recommendations"].(type) {
+		case []string:
+			upgrades = append(upgrades, list...)
+		case []interface{}:
+			for _, item := range list {
+				if upgrade, ok := item.(string); ok {
+					upgrades = append(upgrades, upgrade)
+				}
+			}
+		}
+	}
+	return upgrades
+}
+
 func (a *Assistant) extractMetrics(health *ClusterHealth) []Metric {
 	metrics := []Metric{}
 	for _, check := range health.Checks {
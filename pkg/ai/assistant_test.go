@@ -342,7 +342,7 @@ func TestFormatResponse(t *testing.T) {
 		},
 	}
 
-	queryResponse := assistant.formatResponse(response)
+	queryResponse := assistant.formatResponse(response, nil)
 
 	if queryResponse.Answer != "Test summary" {
 		t.Errorf("expected answer %q, got %q", "Test summary", queryResponse.Answer)
@@ -405,6 +405,25 @@ func TestExtractMetrics(t *testing.T) {
 	}
 }
 
+func TestUpgradeRecommendations(t *testing.T) {
+	health := &ClusterHealth{
+		Checks: []CheckResult{
+			{Name: "pod-health"},
+			{Name: "node-versions", Details: map[string]interface{}{
+				"upgrade_recommendations": []string{"Upgrade the kubelet on node worker-1 from v1.26 to within 3 minor versions of the control plane (v1.30)"},
+			}},
+			{Name: "remote:node-versions", Details: map[string]interface{}{
+				"upgrade_recommendations": []interface{}{"Drain and reboot node worker-2, one at a time, to apply pending updates"},
+			}},
+		},
+	}
+
+	upgrades := upgradeRecommendations(health)
+	if len(upgrades) != 2 || !strings.HasPrefix(upgrades[0], "Upgrade the kubelet") || !strings.HasPrefix(upgrades[1], "Drain and reboot") {
+		t.Errorf("expected both checks' recommendations, got %v", upgrades)
+	}
+}
+
 func TestLearnFromFeedback(t *testing.T) {
 	client := NewClient(Config{TestMode: true})
 	assistant := NewAssistant(client)
@@ -583,7 +602,7 @@ func TestHandlerStructures(t *testing.T) {
 		{
 			name: "performance query handler",
 			fn: func() (*QueryResponse, error) {
-				return assistant.handlePerformanceQuery(ctx, "Why is it slow?", health)
+				return assistant.handlePerformanceQuery(ctx, "Why is it slow?", health, nil)
 			},
 			validate: func(resp *QueryResponse) error {
 				if resp.Answer == "" {
@@ -598,7 +617,7 @@ func TestHandlerStructures(t *testing.T) {
 		{
 			name: "troubleshooting query handler",
 			fn: func() (*QueryResponse, error) {
-				return assistant.handleTroubleshootingQuery(ctx, "What's broken?", health)
+				return assistant.handleTroubleshootingQuery(ctx, "What's broken?", health, nil)
 			},
 			validate: func(resp *QueryResponse) error {
 				if resp.Answer == "" {
@@ -613,7 +632,7 @@ func TestHandlerStructures(t *testing.T) {
 		{
 			name: "optimization query handler",
 			fn: func() (*QueryResponse, error) {
-				return assistant.handleOptimizationQuery(ctx, "How to optimize?", health)
+				return assistant.handleOptimizationQuery(ctx, "How to optimize?", health, nil)
 			},
 			validate: func(resp *QueryResponse) error {
 				if resp.Answer == "" {
@@ -0,0 +1,170 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+// BatchAnalysis is one consolidated analysis of several failing checks: a
+// diagnosis per check plus how the failures relate to each other
+type BatchAnalysis struct {
+	ID          string           `json:"id"`
+	Checks      []string         `json:"checks"`
+	Diagnoses   []CheckDiagnosis `json:"diagnoses"`
+	Unanalyzed  []string         `json:"unanalyzed,omitempty"` // Checks the AI returned no diagnosis for
+	Correlation Correlation      `json:"correlation"`
+	Confidence  float64          `json:"confidence"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Duration    time.Duration    `json:"duration"`
+
+	// StateRevision is the revision of the cluster state the analysis was
+	// computed from
+	StateRevision uint64 `json:"state_revision"`
+}
+
+// CheckDiagnosis is the batch analysis' diagnosis of a single check
+type CheckDiagnosis struct {
+	Check           string             `json:"check"`
+	Summary         string             `json:"summary"`
+	Diagnosis       string             `json:"diagnosis"`
+	Confidence      float64            `json:"confidence"`
+	Severity        SeverityLevel      `json:"severity"`
+	Recommendations []Recommendation   `json:"recommendations,omitempty"`
+	Actions         []SuggestedAction  `json:"actions,omitempty"`
+	Findings        []findings.Finding `json:"findings,omitempty"`
+}
+
+// Correlation describes what the analyzed failures have in common
+type Correlation struct {
+	Summary         string             `json:"summary"`
+	RootCause       string             `json:"root_cause,omitempty"` // Shared root cause, if any
+	Groups          []CorrelationGroup `json:"groups,omitempty"`
+	Severity        SeverityLevel      `json:"severity"`
+	Recommendations []Recommendation   `json:"recommendations,omitempty"`
+}
+
+// CorrelationGroup is a set of checks failing for the same reason
+type CorrelationGroup struct {
+	Checks []string `json:"checks"`
+	Cause  string   `json:"cause"`
+}
+
+// AnalyzeBatch diagnoses several checks in a single AI call. The checks share
+// one diagnostic context, so the model sees all failures side by side and can
+// correlate them instead of diagnosing each in isolation.
+func (c *Client) AnalyzeBatch(ctx context.Context, checks []CheckResult, context DiagnosticContext) (*BatchAnalysis, error) {
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("no checks to analyze")
+	}
+
+	names := make([]string, len(checks))
+	for i, check := range checks {
+		names[i] = check.Name
+	}
+
+	request := AnalysisRequest{
+		Type: AnalysisTypeBatch,
+		Context: withAnnotations(withMaintenance(fmt.Sprintf("%d Kubernetes health checks are failing (%s); diagnose each and correlate them",
+			len(checks), strings.Join(names, ", ")), context.ExpectedDisruptions), context.Annotations),
+		Data: map[string]interface{}{
+			"checks":             checks,
+			"diagnostic_context": context,
+		},
+		Timestamp: time.Now(),
+	}
+
+	response, err := c.Analyze(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("AI batch analysis failed: %w", err)
+	}
+	return batchFromResponse(names, response), nil
+}
+
+// batchFromResponse splits a batch response into per-check diagnoses, in the
+// order the checks were requested, and the overall correlation
+func batchFromResponse(names []string, response *AnalysisResponse) *BatchAnalysis {
+	batch := &BatchAnalysis{
+		ID:         response.ID,
+		Checks:     names,
+		Diagnoses:  make([]CheckDiagnosis, 0, len(names)),
+		Confidence: response.Confidence,
+		Timestamp:  response.Timestamp,
+		Duration:   response.Duration,
+		Correlation: Correlation{
+			Summary:         response.Summary,
+			Severity:        response.Severity,
+			Recommendations: response.Recommendations,
+		},
+	}
+
+	var diagnoses []CheckDiagnosis
+	decodeContext(response.Context, "diagnoses", &diagnoses)
+	byCheck := make(map[string]CheckDiagnosis, len(diagnoses))
+	for _, diagnosis := range diagnoses {
+		byCheck[diagnosis.Check] = diagnosis
+	}
+	for _, name := range names {
+		diagnosis, ok := byCheck[name]
+		if !ok {
+			batch.Unanalyzed = append(batch.Unanalyzed, name)
+			continue
+		}
+		diagnosis.Findings = findings.Normalize(diagnosis.Findings)
+		diagnosis.Recommendations = normalizeRecommendationFindings(diagnosis.Recommendations)
+		batch.Diagnoses = append(batch.Diagnoses, diagnosis)
+	}
+
+	var correlation struct {
+		RootCause string             `json:"root_cause"`
+		Groups    []CorrelationGroup `json:"groups"`
+	}
+	decodeContext(response.Context, "correlation", &correlation)
+	batch.Correlation.RootCause = correlation.RootCause
+	batch.Correlation.Groups = correlation.Groups
+	if batch.Correlation.Summary == "" {
+		batch.Correlation.Summary = response.Diagnosis
+	}
+	return batch
+}
+
+// decodeContext decodes a structured value the AI returned in the response
+// context; malformed values are left zero
+func decodeContext(context map[string]interface{}, key string, out interface{}) {
+	value, ok := context[key]
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, out)
+}
+
+func getBatchInstructions() string {
+	return `
+BATCH ANALYSIS INSTRUCTIONS:
+1. Diagnose every check listed in "checks" using its data and the shared diagnostic context
+2. Look for failures with a common cause (same node, namespace, dependency, deployment or time window)
+3. Group checks failing for the same reason and name the shared root cause, if there is one
+4. Don't repeat the same fix per check when one fix resolves several
+
+Respond with JSON only:
+{
+  "summary": "How the failures relate to each other",
+  "diagnosis": "Overall analysis",
+  "confidence": 0.0-1.0,
+  "severity": "critical|high|medium|low",
+  "recommendations": [{"title": "", "description": "", "priority": 1, "finding": "Finding type ID it addresses, or empty"}],
+  "context": {
+    "diagnoses": [{"check": "check name", "summary": "", "diagnosis": "", "confidence": 0.0-1.0, "severity": "", "recommendations": [], "actions": [{"description": "", "command": ""}], "findings": [{"id": "KP-NODE-001", "subject": "node/worker-1"}]}],
+    "correlation": {"root_cause": "Shared root cause, or empty", "groups": [{"checks": ["check name"], "cause": ""}]}
+  }
+}
+`
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatchFromResponse(t *testing.T) {
+	response := &AnalysisResponse{
+		ID:         "batch-1",
+		Summary:    "Both failures follow node-3 running out of memory",
+		Confidence: 0.9,
+		Severity:   SeverityHigh,
+		Context: map[string]interface{}{
+			"diagnoses": []interface{}{
+				map[string]interface{}{"check": "service-health", "summary": "Endpoints missing", "confidence": 0.8},
+				map[string]interface{}{"check": "pod-health", "summary": "OOMKilled on node-3", "severity": "high",
+					"findings": []interface{}{
+						map[string]interface{}{"id": "kp-pod-003", "subject": "pod/shop/api-1"},
+						map[string]interface{}{"id": "KP-POD-999", "subject": "pod/shop/api-1"},
+					},
+					"recommendations": []interface{}{
+						map[string]interface{}{"title": "Raise the memory limit", "finding": "kp-pod-003"},
+						map[string]interface{}{"title": "Page someone", "finding": "KP-MADE-UP"},
+					},
+				},
+				map[string]interface{}{"check": "dns-health", "summary": "Not requested"},
+			},
+			"correlation": map[string]interface{}{
+				"root_cause": "node-3 memory pressure",
+				"groups": []interface{}{
+					map[string]interface{}{"checks": []interface{}{"pod-health", "service-health"}, "cause": "node-3 memory pressure"},
+				},
+			},
+		},
+	}
+
+	batch := batchFromResponse([]string{"node-health", "pod-health", "service-health"}, response)
+	if len(batch.Diagnoses) != 2 || batch.Diagnoses[0].Check != "pod-health" || batch.Diagnoses[1].Check != "service-health" {
+		t.Fatalf("expected diagnoses for the requested checks in order, got %+v", batch.Diagnoses)
+	}
+	if batch.Diagnoses[0].Severity != SeverityHigh || batch.Diagnoses[1].Confidence != 0.8 {
+		t.Errorf("unexpected diagnoses %+v", batch.Diagnoses)
+	}
+	pod := batch.Diagnoses[0]
+	if len(pod.Findings) != 1 || pod.Findings[0].ID != "KP-POD-003" || pod.Findings[0].Subject != "pod/shop/api-1" {
+		t.Errorf("expected the OOM finding with its ID normalized, got %+v", pod.Findings)
+	}
+	if len(pod.Recommendations) != 2 || pod.Recommendations[0].Finding != "KP-POD-003" || pod.Recommendations[1].Finding != "" {
+		t.Errorf("expected recommendation finding IDs normalized, got %+v", pod.Recommendations)
+	}
+	if len(batch.Unanalyzed) != 1 || batch.Unanalyzed[0] != "node-health" {
+		t.Errorf("expected node-health unanalyzed, got %v", batch.Unanalyzed)
+	}
+	correlation := batch.Correlation
+	if correlation.Summary != response.Summary || correlation.RootCause != "node-3 memory pressure" || len(correlation.Groups) != 1 {
+		t.Errorf("unexpected correlation %+v", correlation)
+	}
+}
+
+func TestAnalyzeBatch(t *testing.T) {
+	client := NewClient(Config{TestMode: true})
+	if _, err := client.AnalyzeBatch(context.Background(), nil, DiagnosticContext{}); err == nil {
+		t.Error("expected an error without checks")
+	}
+
+	checks := []CheckResult{{Name: "pod-health", Status: HealthStatusUnhealthy}, {Name: "node-health", Status: HealthStatusDegraded}}
+	batch, err := client.AnalyzeBatch(context.Background(), checks, DiagnosticContext{ClusterName: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Checks) != 2 || batch.Correlation.Summary == "" {
+		t.Errorf("unexpected batch %+v", batch)
+	}
+}
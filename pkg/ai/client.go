@@ -1,43 +1,87 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
 )
 
-// Client provides AI analysis capabilities using Claude Code CLI
+// Client provides AI analysis capabilities through a model provider, the
+// Claude CLI by default
 type Client struct {
-	claudePath     string
+	provider       Provider
 	maxTurns       int
 	timeout        time.Duration
 	systemPrompt   string
-	testMode       bool
 	circuitBreaker *CircuitBreaker
 	parser         *ResponseParser
+
+	callsMu sync.Mutex
+	calls   map[string]*CallStats // By call kind
+}
+
+// Kinds of provider calls
+const (
+	CallQuery   = "query"
+	CallStream  = "stream"
+	CallAnalyze = "analyze"
+)
+
+// CallStats counts the provider calls of one kind
+type CallStats struct {
+	Kind      string `json:"kind"`
+	Succeeded int64  `json:"succeeded"`
+	Failed    int64  `json:"failed"` // Including calls the open circuit breaker refused
 }
 
 // Config holds configuration for the AI client
 type Config struct {
+	Provider     string // One of Providers; empty uses the Claude CLI
 	ClaudePath   string
 	MaxTurns     int
 	Timeout      time.Duration
 	SystemPrompt string
-	TestMode     bool // When true, returns mock responses instead of executing Claude CLI
+	TestMode     bool   // When true, answers with fixtures from Scenarios instead of calling the provider
+	TestScenario string // Fixture test mode answers with; suggested by the prompt when unset
+	ReadOnly     bool   // When true, the CLI may analyze but not run commands
+	Model        string // Model the provider is asked for; empty uses the CLI's default, and is required by the API providers
+
+	BaseURL   string // API address of the anthropic, openai and ollama providers; empty uses the public or local default
+	APIKey    string // Key of the anthropic and openai providers; local servers may not need one
+	MaxTokens int    // Longest answer the API providers may write; 4096 when unset
 }
 
-// NewClient creates a new AI client
+// ModelInfo identifies the model behind AI output, for audit records
+type ModelInfo struct {
+	Provider string `json:"provider"` // Vendor or server of the model: anthropic, openai or ollama
+	Model    string `json:"model"`    // "default" when the CLI picks it
+	Version  string `json:"version"`  // Claude CLI version, or the model version the API reported; "unknown" when it can't be read
+}
+
+// NewClient creates a new AI client with the provider config selects. A
+// provider that can't be created fails every analysis with the reason.
 func NewClient(config Config) *Client {
-	if config.ClaudePath == "" {
-		config.ClaudePath = "claude" // Assume claude is in PATH
+	var provider Provider
+	if config.TestMode {
+		provider = newCLIProvider(config)
+	} else if p, err := NewProvider(config); err != nil {
+		klog.Errorf("AI provider unavailable: %v", err)
+		provider = failedProvider{err: err}
+	} else {
+		provider = p
 	}
+	return NewClientWithProvider(provider, config)
+}
+
+// NewClientWithProvider creates a new AI client answering with provider,
+// such as one not built in; config sets the rest
+func NewClientWithProvider(provider Provider, config Config) *Client {
 	if config.MaxTurns == 0 {
 		config.MaxTurns = 3
 	}
@@ -59,14 +103,79 @@ func NewClient(config Config) *Client {
 	})
 
 	return &Client{
-		claudePath:     config.ClaudePath,
+		provider:       provider,
 		maxTurns:       config.MaxTurns,
 		timeout:        config.Timeout,
 		systemPrompt:   config.SystemPrompt,
-		testMode:       config.TestMode,
 		circuitBreaker: circuitBreaker,
 		parser:         NewResponseParser(),
+		calls:          make(map[string]*CallStats),
+	}
+}
+
+// ModelInfo returns the provider, model and version the client uses
+func (c *Client) ModelInfo() ModelInfo {
+	return c.provider.ModelInfo()
+}
+
+// call runs a provider call of a kind within the client's timeout and
+// circuit breaker, counting its outcome
+func (c *Client) call(ctx context.Context, kind string, fn func(ctx context.Context) (string, Usage, error)) (string, Usage, error) {
+	var result string
+	var usage Usage
+	err := c.circuitBreaker.Execute(ctx, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+		var execErr error
+		result, usage, execErr = fn(ctx)
+		return execErr
+	})
+	c.countCall(kind, err)
+	return result, usage, err
+}
+
+// countCall counts the outcome of a provider call
+func (c *Client) countCall(kind string, err error) {
+	c.callsMu.Lock()
+	defer c.callsMu.Unlock()
+	stats, ok := c.calls[kind]
+	if !ok {
+		stats = &CallStats{Kind: kind}
+		c.calls[kind] = stats
+	}
+	if err != nil {
+		stats.Failed++
+	} else {
+		stats.Succeeded++
+	}
+}
+
+// CallStats counts the client's provider calls by kind, in kind order
+func (c *Client) CallStats() []CallStats {
+	c.callsMu.Lock()
+	stats := make([]CallStats, 0, len(c.calls))
+	for _, kind := range c.calls {
+		stats = append(stats, *kind)
 	}
+	c.callsMu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Kind < stats[j].Kind })
+	return stats
+}
+
+// Query answers a question in free text, outside any analysis
+func (c *Client) Query(ctx context.Context, question string) (string, Usage, error) {
+	return c.call(ctx, CallQuery, func(ctx context.Context) (string, Usage, error) {
+		return c.provider.Query(ctx, Prompt{System: c.systemPrompt, User: question})
+	})
+}
+
+// Stream answers a question like Query, passing the answer to onChunk as
+// the provider writes it
+func (c *Client) Stream(ctx context.Context, question string, onChunk func(string) error) (string, Usage, error) {
+	return c.call(ctx, CallStream, func(ctx context.Context) (string, Usage, error) {
+		return c.provider.Stream(ctx, Prompt{System: c.systemPrompt, User: question}, onChunk)
+	})
 }
 
 // Analyze performs AI analysis on the given request
@@ -78,7 +187,7 @@ func (c *Client) Analyze(ctx context.Context, request AnalysisRequest) (*Analysi
 		return nil, fmt.Errorf("failed to build prompt: %w", err)
 	}
 
-	klog.V(2).Infof("AI Analysis: Running Claude Code CLI analysis for type=%s", request.Type)
+	klog.V(2).Infof("AI Analysis: Running analysis for type=%s", request.Type)
 	klog.V(3).Infof("AI Analysis prompt preview (first 200 chars): %s", func() string {
 		if len(prompt) > 200 {
 			return prompt[:200] + "..."
@@ -86,15 +195,11 @@ func (c *Client) Analyze(ctx context.Context, request AnalysisRequest) (*Analysi
 		return prompt
 	}())
 
-	var result string
-	err = c.circuitBreaker.Execute(ctx, func(ctx context.Context) error {
-		var execErr error
-		result, execErr = c.runClaude(ctx, prompt)
-		return execErr
+	result, usage, err := c.call(ctx, CallAnalyze, func(ctx context.Context) (string, Usage, error) {
+		return c.provider.Analyze(ctx, Prompt{System: c.systemPrompt, User: prompt})
 	})
-
 	if err != nil {
-		return nil, fmt.Errorf("claude analysis failed: %w", err)
+		return nil, fmt.Errorf("AI analysis failed: %w", err)
 	}
 
 	response, err := c.parser.ParseResponse(result, request)
@@ -106,6 +211,7 @@ func (c *Client) Analyze(ctx context.Context, request AnalysisRequest) (*Analysi
 	response.Type = request.Type
 	response.Timestamp = time.Now()
 	response.Duration = time.Since(start)
+	response.Usage = &usage
 
 	klog.V(2).Infof("AI Analysis completed: type=%s, confidence=%.2f, duration=%v",
 		request.Type, response.Confidence, response.Duration)
@@ -115,24 +221,56 @@ func (c *Client) Analyze(ctx context.Context, request AnalysisRequest) (*Analysi
 
 // AnalyzeDiagnostic performs diagnostic analysis on health check failures
 func (c *Client) AnalyzeDiagnostic(ctx context.Context, checkResult *CheckResult, context DiagnosticContext) (*AnalysisResponse, error) {
-	request := AnalysisRequest{
+	return c.Analyze(ctx, DiagnosticRequest(checkResult, context))
+}
+
+// DiagnosticRequest builds the request diagnosing a health check failure
+func DiagnosticRequest(checkResult *CheckResult, context DiagnosticContext) AnalysisRequest {
+	return AnalysisRequest{
 		Type:        AnalysisTypeDiagnostic,
-		Context:     "Kubernetes health check failure requiring diagnostic analysis",
+		Context:     withAnnotations(withMaintenance(withRunbook("Kubernetes health check failure requiring diagnostic analysis", context.Runbook), context.ExpectedDisruptions), context.Annotations),
 		HealthCheck: checkResult,
 		Data: map[string]interface{}{
 			"diagnostic_context": context,
 		},
 		Timestamp: time.Now(),
 	}
+}
 
-	return c.Analyze(ctx, request)
+// withRunbook points the AI at the team's runbook so its steps follow and
+// cite the documented procedure
+func withRunbook(context, runbook string) string {
+	if runbook == "" {
+		return context
+	}
+	return fmt.Sprintf("%s. The team's runbook for this failure is %s; align the recommended steps with it and cite it as the next step for on-call.", context, runbook)
+}
+
+// withMaintenance tells the AI which disruption is planned, so cordoned or
+// draining nodes and maintenance windows aren't mistaken for a root cause
+func withMaintenance(context string, disruptions []string) string {
+	if len(disruptions) == 0 {
+		return context
+	}
+	return fmt.Sprintf("%s. Planned maintenance is in progress and these disruptions are expected: %s. Don't treat them as the root cause unless the evidence ties the failure to them, and don't recommend uncordoning nodes or ending the maintenance as a fix.",
+		context, strings.Join(disruptions, "; "))
+}
+
+// withAnnotations gives the AI the operators' notes on the period, so load
+// tests, migrations and similar planned activity explain what they cause
+func withAnnotations(context string, notes []string) string {
+	if len(notes) == 0 {
+		return context
+	}
+	return fmt.Sprintf("%s. Operators annotated this period: %s. Expect the load, restarts or errors these activities cause and say when a finding is explained by one, but still report problems they don't explain.",
+		context, strings.Join(notes, "; "))
 }
 
 // AnalyzeHealing suggests self-healing actions
 func (c *Client) AnalyzeHealing(ctx context.Context, checkResult *CheckResult, context DiagnosticContext) (*AnalysisResponse, error) {
 	request := AnalysisRequest{
 		Type:        AnalysisTypeHealing,
-		Context:     "Generate automated healing suggestions for Kubernetes issues",
+		Context:     withAnnotations(withMaintenance(withRunbook("Generate automated healing suggestions for Kubernetes issues", context.Runbook), context.ExpectedDisruptions), context.Annotations),
 		HealthCheck: checkResult,
 		Data: map[string]interface{}{
 			"diagnostic_context": context,
@@ -147,7 +285,7 @@ func (c *Client) AnalyzeHealing(ctx context.Context, checkResult *CheckResult, c
 func (c *Client) AnalyzeCluster(ctx context.Context, clusterHealth *ClusterHealth) (*InsightSummary, error) {
 	request := AnalysisRequest{
 		Type:        AnalysisTypeSummary,
-		Context:     "Comprehensive Kubernetes cluster health analysis and insights",
+		Context:     withAnnotations("Comprehensive Kubernetes cluster health analysis and insights", clusterHealth.Annotations),
 		ClusterInfo: clusterHealth,
 		Timestamp:   time.Now(),
 	}
@@ -176,110 +314,17 @@ func (c *Client) AnalyzeCluster(ctx context.Context, clusterHealth *ClusterHealt
 	return summary, nil
 }
 
-// runClaude executes the Claude Code CLI with the given prompt
-func (c *Client) runClaude(ctx context.Context, prompt string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	// Return mock response in test mode
-	if c.testMode {
-		klog.Infof("AI: Test mode enabled, returning mock response")
-		return `{
-			"summary": "Mock analysis response for testing",
-			"diagnosis": "Mock diagnosis for testing",
-			"confidence": 0.8,
-			"severity": "medium",
-			"recommendations": [
-				{
-					"title": "Mock recommendation 1",
-					"description": "Mock description 1",
-					"priority": "high",
-					"references": []
-				}
-			],
-			"actions": [
-				{
-					"description": "Mock action 1",
-					"command": "kubectl get pods",
-					"priority": "high"
-				}
-			],
-			"context": {}
-		}`, nil
-	}
-
-	// Validate Claude path for security
-	if err := c.validateClaudePath(); err != nil {
-		return "", fmt.Errorf("invalid claude path: %w", err)
-	}
-
-	if len(prompt) > 100000 { // Reasonable limit
-		return "", fmt.Errorf("prompt too long: %d characters", len(prompt))
-	}
-
-	args := []string{
-		"-p", prompt,
-		"--max-turns", "1",
-		"--system-prompt", c.systemPrompt,
-		"--permission-mode", "bypassPermissions",
-	}
-
-	// Use exec.Command with an argument slice so prompt content never reaches a shell parser.
-	// This is structurally injection-safe regardless of what kubectl output or K8s YAML the
-	// prompt contains (no sh -c, no shell quoting required).
-	cmd := exec.CommandContext(ctx, c.claudePath, args...) // #nosec G204 - claudePath is validated above
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Ensure proper environment inheritance for Node.js/Claude CLI
-	cmd.Env = os.Environ() // Inherit full environment from parent process
-
-	klog.Infof("AI: Executing Claude CLI analysis (prompt length: %d chars)", len(prompt))
-
-	if err := cmd.Run(); err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			klog.Errorf("Claude CLI timed out after %v", c.timeout)
-			return "", fmt.Errorf("claude CLI timed out after %v", c.timeout)
-		}
-		klog.Errorf("Claude command failed: %v, stderr: %s, stdout: %s", err, stderr.String(), stdout.String())
-		return "", fmt.Errorf("claude command failed: %w, stderr: %s", err, stderr.String())
-	}
-
-	klog.Infof("AI: Claude CLI completed successfully (output length: %d chars)", len(stdout.String()))
-
-	return stdout.String(), nil
-}
-
-// validateClaudePath ensures the Claude CLI path is safe to execute
-func (c *Client) validateClaudePath() error {
-	// Only allow known safe paths for Claude CLI
-	allowedPaths := []string{
-		"claude", // From PATH
-		"/usr/local/bin/claude",
-		"/opt/homebrew/bin/claude",
-	}
-
-	for _, allowed := range allowedPaths {
-		if c.claudePath == allowed {
-			return nil
-		}
-	}
-
-	// Check if it's an absolute path pointing to a 'claude' binary
-	if strings.HasPrefix(c.claudePath, "/") && strings.HasSuffix(c.claudePath, "/claude") {
-		return nil
-	}
-
-	return fmt.Errorf("claude path not in allowlist: %s", c.claudePath)
-}
-
 // GetCircuitBreakerStats returns circuit breaker statistics
 func (c *Client) GetCircuitBreakerStats() map[string]interface{} {
 	return c.circuitBreaker.GetStats()
 }
 
+// Available reports whether the client is taking requests, that is its
+// circuit breaker isn't open after repeated provider failures
+func (c *Client) Available() bool {
+	return c.circuitBreaker.GetState() != CircuitOpen
+}
+
 // ResetCircuitBreaker manually resets the circuit breaker
 func (c *Client) ResetCircuitBreaker() {
 	c.circuitBreaker.Reset()
@@ -327,6 +372,14 @@ func (c *Client) buildPrompt(request AnalysisRequest) (string, error) {
 		prompt.WriteString(getSummaryInstructions())
 	case AnalysisTypeRootCause:
 		prompt.WriteString(getRootCauseInstructions())
+	case AnalysisTypeBatch:
+		prompt.WriteString(getBatchInstructions())
+	}
+
+	// Classify problems by finding type so they can be tracked across analyses
+	switch request.Type {
+	case AnalysisTypeDiagnostic, AnalysisTypeRootCause, AnalysisTypeBatch:
+		prompt.WriteString(findingsPrompt())
 	}
 
 	return prompt.String(), nil
@@ -381,8 +434,9 @@ func getDiagnosticInstructions() string {
 	return `
 DIAGNOSTIC ANALYSIS INSTRUCTIONS:
 1. Analyze the health check failure and identify the root cause
-2. Examine error messages, logs, and metrics
+2. Examine error messages, extracted log patterns (log_patterns), and metrics
 3. Consider common Kubernetes issues (resource constraints, networking, configuration)
+   - For networking failures, use the service-mesh check data if present: control plane health, pods missing sidecars (pods_missing_sidecar), sidecar restarts and mTLS policy conflicts (mtls_modes)
 4. Provide a clear diagnosis with confidence level
 5. Include specific technical details and evidence
 6. Suggest investigation commands if more data is needed
@@ -393,6 +447,7 @@ Please structure your response with:
 - CONFIDENCE: Your confidence level in this diagnosis
 - EVIDENCE: Supporting data from the health check
 - NEXT_STEPS: Immediate actions to take
+- FINDINGS: One finding per line as "<finding type ID> <kind/namespace/name or kind/name>"
 `
 }
 
@@ -442,7 +497,7 @@ ROOT CAUSE ANALYSIS INSTRUCTIONS:
 1. Perform deep analysis to identify the fundamental cause
 2. Trace the chain of events leading to the issue
 3. Eliminate symptoms to focus on core problems
-4. Consider system interactions and dependencies
+4. Consider system interactions and dependencies, including service mesh sidecars and mTLS policy between callers and backends
 5. Provide evidence-based conclusions
 6. Suggest systemic fixes to prevent similar issues
 
@@ -453,5 +508,6 @@ Please structure your response with:
 - IMPACT: Scope and consequences of the root cause
 - SYSTEMIC_FIXES: Fundamental solutions
 - VALIDATION: How to verify the fix worked
+- FINDINGS: One finding per line as "<finding type ID> <kind/namespace/name or kind/name>"
 `
 }
@@ -17,8 +17,8 @@ func TestNewClient(t *testing.T) {
 			name:   "default config",
 			config: Config{TestMode: true},
 			verify: func(c *Client) error {
-				if c.claudePath != "claude" {
-					t.Errorf("expected default claudePath 'claude', got %s", c.claudePath)
+				if path := c.provider.(*cliProvider).path; path != "claude" {
+					t.Errorf("expected default claudePath 'claude', got %s", path)
 				}
 				if c.maxTurns != 3 {
 					t.Errorf("expected default maxTurns 3, got %d", c.maxTurns)
@@ -48,8 +48,8 @@ func TestNewClient(t *testing.T) {
 				TestMode:     true,
 			},
 			verify: func(c *Client) error {
-				if c.claudePath != "/custom/path/claude" {
-					t.Errorf("expected claudePath '/custom/path/claude', got %s", c.claudePath)
+				if path := c.provider.(*cliProvider).path; path != "/custom/path/claude" {
+					t.Errorf("expected claudePath '/custom/path/claude', got %s", path)
 				}
 				if c.maxTurns != 5 {
 					t.Errorf("expected maxTurns 5, got %d", c.maxTurns)
@@ -120,8 +120,8 @@ func TestValidateClaudePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client := &Client{claudePath: tt.path}
-			err := client.validateClaudePath()
+			provider := &cliProvider{path: tt.path}
+			err := provider.validatePath()
 
 			if tt.expectErr && err == nil {
 				t.Error("expected error but got none")
@@ -157,6 +157,8 @@ func TestBuildPrompt(t *testing.T) {
 				"pod-health",
 				"crashing",
 				"DIAGNOSTIC ANALYSIS INSTRUCTIONS:",
+				"FINDING TYPES",
+				"KP-NODE-001: Node memory pressure",
 			},
 		},
 		{
@@ -206,6 +208,7 @@ func TestBuildPrompt(t *testing.T) {
 			contains: []string{
 				"ANALYSIS REQUEST: root_cause",
 				"ROOT CAUSE ANALYSIS INSTRUCTIONS:",
+				"FINDING TYPES",
 			},
 		},
 	}
@@ -274,6 +277,62 @@ func TestAnalyzeDiagnostic(t *testing.T) {
 	}
 }
 
+func TestPermissionMode(t *testing.T) {
+	if mode := newCLIProvider(Config{}).permissionMode(); mode != "bypassPermissions" {
+		t.Errorf("expected bypassPermissions by default, got %q", mode)
+	}
+	if mode := newCLIProvider(Config{ReadOnly: true}).permissionMode(); mode != "plan" {
+		t.Errorf("expected plan mode in read-only mode, got %q", mode)
+	}
+}
+
+func TestClientAvailable(t *testing.T) {
+	client := NewClient(Config{TestMode: true})
+	if !client.Available() {
+		t.Error("expected a new client available")
+	}
+	client.circuitBreaker.setState(CircuitOpen)
+	if client.Available() {
+		t.Error("expected the client unavailable with its circuit breaker open")
+	}
+	if info := client.ModelInfo(); info.Provider != ProviderAnthropic || info.Model != "default" || info.Version != "test" {
+		t.Errorf("unexpected model info %+v", info)
+	}
+}
+
+func TestWithRunbook(t *testing.T) {
+	if got := withRunbook("Diagnose", ""); got != "Diagnose" {
+		t.Errorf("expected the context unchanged without a runbook, got %q", got)
+	}
+	got := withRunbook("Diagnose", "https://runbooks.example.com/pods")
+	if !strings.Contains(got, "https://runbooks.example.com/pods") || !strings.HasPrefix(got, "Diagnose") {
+		t.Errorf("expected the runbook appended to the context, got %q", got)
+	}
+}
+
+func TestWithMaintenance(t *testing.T) {
+	if got := withMaintenance("Diagnose", nil); got != "Diagnose" {
+		t.Errorf("expected the context unchanged without maintenance, got %q", got)
+	}
+	got := withMaintenance("Diagnose", []string{"node-2: NotReady (cordoned)", "shop/web-1: Failed (node node-2 draining)"})
+	if !strings.HasPrefix(got, "Diagnose") || !strings.Contains(got, "node-2: NotReady (cordoned); shop/web-1") {
+		t.Errorf("expected the disruptions appended to the context, got %q", got)
+	}
+	if !strings.Contains(got, "root cause") {
+		t.Errorf("expected guidance against blaming planned maintenance, got %q", got)
+	}
+}
+
+func TestWithAnnotations(t *testing.T) {
+	if got := withAnnotations("Diagnose", nil); got != "Diagnose" {
+		t.Errorf("expected the context unchanged without annotations, got %q", got)
+	}
+	got := withAnnotations("Diagnose", []string{`"load test running" by alice for all checks`, `"migration window" for pod-health`})
+	if !strings.HasPrefix(got, "Diagnose") || !strings.Contains(got, `"load test running" by alice for all checks; "migration window"`) {
+		t.Errorf("expected the annotations appended to the context, got %q", got)
+	}
+}
+
 func TestAnalyzeHealing(t *testing.T) {
 	client := NewClient(Config{TestMode: true})
 
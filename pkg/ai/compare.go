@@ -0,0 +1,258 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AnalysisSession is a snapshot of a cluster analysis kept for comparison
+type AnalysisSession struct {
+	ID              string             `json:"id"`
+	Cluster         string             `json:"cluster"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Status          HealthStatus       `json:"status"`
+	HealthScore     float64            `json:"health_score"`
+	Summary         string             `json:"summary"`
+	Findings        []Finding          `json:"findings"`
+	Recommendations []string           `json:"recommendations"`
+	Metrics         map[string]float64 `json:"metrics"` // Latest value per metric series
+}
+
+// Finding is a check that was not healthy when a session was taken
+type Finding struct {
+	Check   string       `json:"check"`
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message"`
+}
+
+// FindingChange is a finding present in both sessions with a different status
+type FindingChange struct {
+	Check string       `json:"check"`
+	From  HealthStatus `json:"from"`
+	To    HealthStatus `json:"to"`
+}
+
+// MetricDelta is the change in one metric series between sessions
+type MetricDelta struct {
+	Name          string  `json:"name"`
+	From          float64 `json:"from"`
+	To            float64 `json:"to"`
+	Delta         float64 `json:"delta"`
+	PercentChange float64 `json:"percent_change,omitempty"` // Omitted when From is zero
+}
+
+// SessionRef identifies a compared session
+type SessionRef struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AnalysisDiff is a structured comparison of two analysis sessions
+type AnalysisDiff struct {
+	Cluster                string          `json:"cluster"`
+	From                   SessionRef      `json:"from"`
+	To                     SessionRef      `json:"to"`
+	StatusFrom             HealthStatus    `json:"status_from"`
+	StatusTo               HealthStatus    `json:"status_to"`
+	HealthScoreDelta       float64         `json:"health_score_delta"`
+	AppearedFindings       []Finding       `json:"appeared_findings"`
+	ResolvedFindings       []Finding       `json:"resolved_findings"`
+	ChangedFindings        []FindingChange `json:"changed_findings"`
+	AddedRecommendations   []string        `json:"added_recommendations"`
+	RemovedRecommendations []string        `json:"removed_recommendations"`
+	MetricDeltas           []MetricDelta   `json:"metric_deltas"`
+	Improved               []string        `json:"improved"`
+	Regressed              []string        `json:"regressed"`
+	Narrative              string          `json:"narrative,omitempty"`
+	NarrativeError         string          `json:"narrative_error,omitempty"`
+}
+
+// NewAnalysisSession snapshots cluster health and its AI summary
+func NewAnalysisSession(id string, health *ClusterHealth, summary *InsightSummary) AnalysisSession {
+	session := AnalysisSession{
+		ID:              id,
+		CreatedAt:       time.Now(),
+		Findings:        []Finding{},
+		Recommendations: []string{},
+		Metrics:         make(map[string]float64),
+	}
+
+	if health != nil {
+		session.Cluster = health.ClusterName
+		session.Status = health.Status
+		session.HealthScore = health.Score.Weighted
+		for _, check := range health.Checks {
+			if check.Status != HealthStatusHealthy {
+				session.Findings = append(session.Findings, Finding{Check: check.Name, Status: check.Status, Message: check.Message})
+			}
+			for _, metric := range check.Metrics {
+				session.Metrics[metricKey(metric)] = metric.Value
+			}
+		}
+	}
+	if summary != nil {
+		session.Summary = summary.OverallHealth
+		for _, rec := range summary.Recommendations {
+			session.Recommendations = append(session.Recommendations, rec.Title)
+		}
+	}
+	return session
+}
+
+// metricKey identifies a metric series by name and sorted labels
+func metricKey(metric Metric) string {
+	if len(metric.Labels) == 0 {
+		return metric.Name
+	}
+	labels := make([]string, 0, len(metric.Labels))
+	for key, value := range metric.Labels {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	return metric.Name + "{" + strings.Join(labels, ",") + "}"
+}
+
+// CompareSessions diffs two sessions, from the earlier to the later
+func CompareSessions(from, to AnalysisSession) *AnalysisDiff {
+	diff := &AnalysisDiff{
+		Cluster:                to.Cluster,
+		From:                   SessionRef{ID: from.ID, CreatedAt: from.CreatedAt},
+		To:                     SessionRef{ID: to.ID, CreatedAt: to.CreatedAt},
+		StatusFrom:             from.Status,
+		StatusTo:               to.Status,
+		HealthScoreDelta:       to.HealthScore - from.HealthScore,
+		AppearedFindings:       []Finding{},
+		ResolvedFindings:       []Finding{},
+		ChangedFindings:        []FindingChange{},
+		AddedRecommendations:   []string{},
+		RemovedRecommendations: []string{},
+		MetricDeltas:           []MetricDelta{},
+		Improved:               []string{},
+		Regressed:              []string{},
+	}
+
+	before := make(map[string]Finding, len(from.Findings))
+	for _, finding := range from.Findings {
+		before[finding.Check] = finding
+	}
+	after := make(map[string]Finding, len(to.Findings))
+	for _, finding := range to.Findings {
+		after[finding.Check] = finding
+		previous, existed := before[finding.Check]
+		switch {
+		case !existed:
+			diff.AppearedFindings = append(diff.AppearedFindings, finding)
+			diff.Regressed = append(diff.Regressed, fmt.Sprintf("%s became %s: %s", finding.Check, finding.Status, finding.Message))
+		case previous.Status != finding.Status:
+			diff.ChangedFindings = append(diff.ChangedFindings, FindingChange{Check: finding.Check, From: previous.Status, To: finding.Status})
+			line := fmt.Sprintf("%s went from %s to %s", finding.Check, previous.Status, finding.Status)
+			if severityRank(finding.Status) > severityRank(previous.Status) {
+				diff.Regressed = append(diff.Regressed, line)
+			} else {
+				diff.Improved = append(diff.Improved, line)
+			}
+		}
+	}
+	for _, finding := range from.Findings {
+		if _, ok := after[finding.Check]; !ok {
+			diff.ResolvedFindings = append(diff.ResolvedFindings, finding)
+			diff.Improved = append(diff.Improved, fmt.Sprintf("%s recovered (was %s)", finding.Check, finding.Status))
+		}
+	}
+
+	diff.AddedRecommendations, diff.RemovedRecommendations = diffStrings(from.Recommendations, to.Recommendations)
+
+	names := make([]string, 0, len(to.Metrics))
+	for name := range to.Metrics {
+		if _, ok := from.Metrics[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		delta := MetricDelta{Name: name, From: from.Metrics[name], To: to.Metrics[name]}
+		delta.Delta = delta.To - delta.From
+		if delta.Delta == 0 {
+			continue
+		}
+		if delta.From != 0 {
+			delta.PercentChange = math.Round(delta.Delta/math.Abs(delta.From)*10000) / 100
+		}
+		diff.MetricDeltas = append(diff.MetricDeltas, delta)
+	}
+
+	switch {
+	case diff.HealthScoreDelta > 0:
+		diff.Improved = append(diff.Improved, fmt.Sprintf("health score rose by %.1f", diff.HealthScoreDelta))
+	case diff.HealthScoreDelta < 0:
+		diff.Regressed = append(diff.Regressed, fmt.Sprintf("health score fell by %.1f", -diff.HealthScoreDelta))
+	}
+
+	return diff
+}
+
+// severityRank orders health statuses from best to worst
+func severityRank(status HealthStatus) int {
+	switch status {
+	case HealthStatusHealthy:
+		return 0
+	case HealthStatusDegraded:
+		return 2
+	case HealthStatusUnhealthy:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// diffStrings returns values only in b and values only in a, in order
+func diffStrings(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, value := range a {
+		inA[value] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, value := range b {
+		inB[value] = true
+	}
+
+	added, removed = []string{}, []string{}
+	for _, value := range b {
+		if !inA[value] {
+			added = append(added, value)
+		}
+	}
+	for _, value := range a {
+		if !inB[value] {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+// NarrateComparison asks the AI for a short "what improved / what regressed"
+// narrative of a diff, suitable for change review meetings
+func (c *Client) NarrateComparison(ctx context.Context, diff *AnalysisDiff) (string, error) {
+	request := AnalysisRequest{
+		Type: AnalysisTypeSummary,
+		Context: fmt.Sprintf("Compare two analyses of cluster %s taken at %s and %s. "+
+			"Summarize what improved and what regressed in a few sentences for a change review meeting, "+
+			"citing the findings and metric deltas provided.",
+			diff.Cluster, diff.From.CreatedAt.Format(time.RFC3339), diff.To.CreatedAt.Format(time.RFC3339)),
+		Data:      map[string]interface{}{"diff": diff},
+		Timestamp: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	response, err := c.Analyze(ctx, request)
+	if err != nil {
+		return "", fmt.Errorf("AI comparison failed: %w", err)
+	}
+	return response.Summary, nil
+}
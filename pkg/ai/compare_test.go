@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompareSessions(t *testing.T) {
+	base := time.Now()
+	from := AnalysisSession{
+		ID:          "analysis-1",
+		CreatedAt:   base,
+		Status:      HealthStatusDegraded,
+		HealthScore: 70,
+		Findings: []Finding{
+			{Check: "pod-health", Status: HealthStatusDegraded, Message: "3 failed"},
+			{Check: "node-health", Status: HealthStatusDegraded, Message: "1 NotReady"},
+		},
+		Recommendations: []string{"Restart api", "Add node capacity"},
+		Metrics:         map[string]float64{"pod_failed": 3, "node_total": 3, "pod_total": 20},
+	}
+	to := AnalysisSession{
+		ID:          "analysis-2",
+		Cluster:     "prod",
+		CreatedAt:   base.Add(time.Hour),
+		Status:      HealthStatusUnhealthy,
+		HealthScore: 55,
+		Findings: []Finding{
+			{Check: "pod-health", Status: HealthStatusUnhealthy, Message: "12 failed"},
+			{Check: "service-health", Status: HealthStatusDegraded, Message: "1 without endpoints"},
+		},
+		Recommendations: []string{"Restart api", "Fix image tag"},
+		Metrics:         map[string]float64{"pod_failed": 12, "node_total": 3, "pod_total": 20},
+	}
+
+	diff := CompareSessions(from, to)
+
+	if len(diff.AppearedFindings) != 1 || diff.AppearedFindings[0].Check != "service-health" {
+		t.Errorf("expected service-health to appear, got %+v", diff.AppearedFindings)
+	}
+	if len(diff.ResolvedFindings) != 1 || diff.ResolvedFindings[0].Check != "node-health" {
+		t.Errorf("expected node-health to resolve, got %+v", diff.ResolvedFindings)
+	}
+	want := []FindingChange{{Check: "pod-health", From: HealthStatusDegraded, To: HealthStatusUnhealthy}}
+	if !reflect.DeepEqual(diff.ChangedFindings, want) {
+		t.Errorf("expected pod-health to worsen, got %+v", diff.ChangedFindings)
+	}
+	if !reflect.DeepEqual(diff.AddedRecommendations, []string{"Fix image tag"}) ||
+		!reflect.DeepEqual(diff.RemovedRecommendations, []string{"Add node capacity"}) {
+		t.Errorf("unexpected recommendation changes: +%v -%v", diff.AddedRecommendations, diff.RemovedRecommendations)
+	}
+	if len(diff.MetricDeltas) != 1 || diff.MetricDeltas[0].Name != "pod_failed" ||
+		diff.MetricDeltas[0].Delta != 9 || diff.MetricDeltas[0].PercentChange != 300 {
+		t.Errorf("expected only the changed metric, got %+v", diff.MetricDeltas)
+	}
+	if diff.HealthScoreDelta != -15 {
+		t.Errorf("expected score delta -15, got %v", diff.HealthScoreDelta)
+	}
+	if len(diff.Improved) != 1 || len(diff.Regressed) != 3 {
+		t.Errorf("expected 1 improvement and 3 regressions, got %v / %v", diff.Improved, diff.Regressed)
+	}
+}
+
+func TestNewAnalysisSession(t *testing.T) {
+	health := &ClusterHealth{
+		ClusterName: "prod",
+		Status:      HealthStatusDegraded,
+		Score:       HealthScore{Weighted: 80},
+		Checks: []CheckResult{
+			{Name: "node-health", Status: HealthStatusHealthy, Metrics: []Metric{
+				{Name: "node_cpu_usage_percent", Value: 40, Labels: map[string]string{"node": "a"}},
+			}},
+			{Name: "pod-health", Status: HealthStatusDegraded, Message: "2 failed"},
+		},
+	}
+	summary := &InsightSummary{OverallHealth: "Mostly fine", Recommendations: []Recommendation{{Title: "Restart api"}}}
+
+	session := NewAnalysisSession("analysis-1", health, summary)
+	if session.Cluster != "prod" || session.HealthScore != 80 || session.Summary != "Mostly fine" {
+		t.Errorf("unexpected session %+v", session)
+	}
+	if len(session.Findings) != 1 || session.Findings[0].Check != "pod-health" {
+		t.Errorf("expected only unhealthy checks as findings, got %+v", session.Findings)
+	}
+	if session.Metrics["node_cpu_usage_percent{node=a}"] != 40 {
+		t.Errorf("expected labelled metric series, got %v", session.Metrics)
+	}
+	if !reflect.DeepEqual(session.Recommendations, []string{"Restart api"}) {
+		t.Errorf("expected recommendation titles, got %v", session.Recommendations)
+	}
+}
+
+func TestNarrateComparison(t *testing.T) {
+	client := NewClient(Config{TestMode: true})
+	narrative, err := client.NarrateComparison(context.Background(), &AnalysisDiff{Cluster: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if narrative == "" {
+		t.Error("expected a narrative")
+	}
+}
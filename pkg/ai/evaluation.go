@@ -0,0 +1,299 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrEvaluationNotFound is returned for feedback on an unknown evaluation
+var ErrEvaluationNotFound = errors.New("evaluation not found")
+
+// defaultEvaluations is how many evaluations are kept
+const defaultEvaluations = 200
+
+// EvaluationVariant is one side of an A/B evaluation: a model, and what it
+// costs per million tokens when the CLI doesn't report cost
+type EvaluationVariant struct {
+	Name                 string
+	Config               Config
+	InputCostPerMTokens  float64
+	OutputCostPerMTokens float64
+}
+
+// evaluationArm is a variant with its client
+type evaluationArm struct {
+	EvaluationVariant
+	client *Client
+}
+
+// EvaluationResult is one variant's answer to an evaluated request
+type EvaluationResult struct {
+	Variant    string        `json:"variant"`
+	Model      ModelInfo     `json:"model"`
+	Summary    string        `json:"summary,omitempty"`
+	Diagnosis  string        `json:"diagnosis,omitempty"`
+	Confidence float64       `json:"confidence"`
+	Severity   SeverityLevel `json:"severity,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	Usage      Usage         `json:"usage"`
+	CostUSD    float64       `json:"cost_usd"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// EvaluationFeedback is an operator's judgement of an evaluation
+type EvaluationFeedback struct {
+	Correct   map[string]bool `json:"correct,omitempty"`   // Variant to whether its diagnosis was right
+	Preferred string          `json:"preferred,omitempty"` // Variant whose answer was more useful
+	Comment   string          `json:"comment,omitempty"`
+	By        string          `json:"by,omitempty"`
+	At        time.Time       `json:"at"`
+}
+
+// Evaluation is a request answered by both variants
+type Evaluation struct {
+	ID        string              `json:"id"`
+	Type      AnalysisType        `json:"type"`
+	Check     string              `json:"check,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	Results   []EvaluationResult  `json:"results"`
+	Feedback  *EvaluationFeedback `json:"feedback,omitempty"`
+}
+
+// VariantReport compares a variant's evaluations: accuracy from operator
+// feedback, speed, tokens and cost
+type VariantReport struct {
+	Variant         string        `json:"variant"`
+	Model           string        `json:"model"`
+	Runs            int           `json:"runs"`
+	Errors          int           `json:"errors"`
+	Rated           int           `json:"rated"` // Runs with feedback on their correctness
+	Correct         int           `json:"correct"`
+	Accuracy        float64       `json:"accuracy"` // Correct of rated, 0 until rated
+	Preferred       int           `json:"preferred"`
+	AvgDuration     time.Duration `json:"avg_duration"`
+	AvgInputTokens  float64       `json:"avg_input_tokens"`
+	AvgOutputTokens float64       `json:"avg_output_tokens"`
+	TotalCostUSD    float64       `json:"total_cost_usd"`
+	CostPerCorrect  float64       `json:"cost_per_correct,omitempty"` // Total cost over correct answers
+}
+
+// EvaluationReport compares the variants over the kept evaluations
+type EvaluationReport struct {
+	Evaluations  int             `json:"evaluations"`
+	WithFeedback int             `json:"with_feedback"`
+	Variants     []VariantReport `json:"variants"`
+}
+
+// Evaluator runs analysis requests against two models side by side and
+// keeps both answers, so teams can pick a model on evidence
+type Evaluator struct {
+	arms     []evaluationArm
+	capacity int
+
+	mu          sync.RWMutex
+	evaluations []Evaluation
+	nextID      int
+}
+
+// NewEvaluator creates an evaluator of two variants keeping up to capacity
+// evaluations
+func NewEvaluator(variants []EvaluationVariant, capacity int) (*Evaluator, error) {
+	if len(variants) != 2 {
+		return nil, fmt.Errorf("an evaluation needs two variants, got %d", len(variants))
+	}
+	if variants[0].Name == "" || variants[0].Name == variants[1].Name {
+		return nil, fmt.Errorf("evaluation variants need distinct names")
+	}
+	if capacity <= 0 {
+		capacity = defaultEvaluations
+	}
+	arms := make([]evaluationArm, len(variants))
+	for i, variant := range variants {
+		arms[i] = evaluationArm{EvaluationVariant: variant, client: NewClient(variant.Config)}
+	}
+	return &Evaluator{arms: arms, capacity: capacity}, nil
+}
+
+// Variants returns the names of the variants
+func (e *Evaluator) Variants() []string {
+	names := make([]string, len(e.arms))
+	for i, arm := range e.arms {
+		names[i] = arm.Name
+	}
+	return names
+}
+
+// Evaluate runs a request against both variants at once and records their
+// answers. It fails only when both do.
+func (e *Evaluator) Evaluate(ctx context.Context, check string, request AnalysisRequest) (Evaluation, error) {
+	results := make([]EvaluationResult, len(e.arms))
+	var wg sync.WaitGroup
+	for i, arm := range e.arms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = arm.run(ctx, request)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed == len(results) {
+		return Evaluation{}, fmt.Errorf("both variants failed: %s; %s", results[0].Error, results[1].Error)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextID++
+	evaluation := Evaluation{
+		ID:        fmt.Sprintf("eval-%d", e.nextID),
+		Type:      request.Type,
+		Check:     check,
+		CreatedAt: time.Now(),
+		Results:   results,
+	}
+	e.evaluations = append(e.evaluations, evaluation)
+	if len(e.evaluations) > e.capacity {
+		e.evaluations = e.evaluations[len(e.evaluations)-e.capacity:]
+	}
+	return evaluation, nil
+}
+
+// run answers a request with the arm's model
+func (a evaluationArm) run(ctx context.Context, request AnalysisRequest) EvaluationResult {
+	result := EvaluationResult{Variant: a.Name, Model: a.client.ModelInfo()}
+	start := time.Now()
+	response, err := a.client.Analyze(ctx, request)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Summary = response.Summary
+	result.Diagnosis = response.Diagnosis
+	result.Confidence = response.Confidence
+	result.Severity = response.Severity
+	if response.Usage != nil {
+		result.Usage = *response.Usage
+	}
+	result.CostUSD = a.cost(result.Usage)
+	return result
+}
+
+// cost is the cost the CLI reported, or else the tokens at the variant's
+// prices
+func (a evaluationArm) cost(usage Usage) float64 {
+	if usage.CostUSD > 0 {
+		return usage.CostUSD
+	}
+	return (float64(usage.InputTokens)*a.InputCostPerMTokens + float64(usage.OutputTokens)*a.OutputCostPerMTokens) / 1e6
+}
+
+// List returns the kept evaluations, newest first
+func (e *Evaluator) List() []Evaluation {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	evaluations := make([]Evaluation, 0, len(e.evaluations))
+	for i := len(e.evaluations) - 1; i >= 0; i-- {
+		evaluations = append(evaluations, e.evaluations[i])
+	}
+	return evaluations
+}
+
+// Feedback records an operator's judgement of an evaluation, replacing any
+// earlier one
+func (e *Evaluator) Feedback(id string, feedback EvaluationFeedback) (Evaluation, error) {
+	known := make(map[string]bool, len(e.arms))
+	for _, arm := range e.arms {
+		known[arm.Name] = true
+	}
+	for variant := range feedback.Correct {
+		if !known[variant] {
+			return Evaluation{}, fmt.Errorf("unknown variant %q", variant)
+		}
+	}
+	if feedback.Preferred != "" && !known[feedback.Preferred] {
+		return Evaluation{}, fmt.Errorf("unknown variant %q", feedback.Preferred)
+	}
+	if len(feedback.Correct) == 0 && feedback.Preferred == "" {
+		return Evaluation{}, fmt.Errorf("feedback needs a correct verdict or a preferred variant")
+	}
+	feedback.At = time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i := range e.evaluations {
+		if e.evaluations[i].ID == id {
+			e.evaluations[i].Feedback = &feedback
+			return e.evaluations[i], nil
+		}
+	}
+	return Evaluation{}, fmt.Errorf("%w: %s", ErrEvaluationNotFound, id)
+}
+
+// Report compares the variants over the kept evaluations
+func (e *Evaluator) Report() EvaluationReport {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	report := EvaluationReport{Evaluations: len(e.evaluations), Variants: make([]VariantReport, len(e.arms))}
+	for i, arm := range e.arms {
+		variant := VariantReport{Variant: arm.Name, Model: arm.Config.Model}
+		if variant.Model == "" {
+			variant.Model = "default"
+		}
+		var duration time.Duration
+		var input, output, answered int
+		for _, evaluation := range e.evaluations {
+			result := evaluation.Results[i]
+			variant.Runs++
+			if result.Error != "" {
+				variant.Errors++
+			} else {
+				answered++
+				duration += result.Duration
+				input += result.Usage.InputTokens
+				output += result.Usage.OutputTokens
+				variant.TotalCostUSD += result.CostUSD
+			}
+			if evaluation.Feedback == nil {
+				continue
+			}
+			if correct, ok := evaluation.Feedback.Correct[arm.Name]; ok {
+				variant.Rated++
+				if correct {
+					variant.Correct++
+				}
+			}
+			if evaluation.Feedback.Preferred == arm.Name {
+				variant.Preferred++
+			}
+		}
+		if answered > 0 {
+			variant.AvgDuration = duration / time.Duration(answered)
+			variant.AvgInputTokens = float64(input) / float64(answered)
+			variant.AvgOutputTokens = float64(output) / float64(answered)
+		}
+		if variant.Rated > 0 {
+			variant.Accuracy = float64(variant.Correct) / float64(variant.Rated)
+		}
+		if variant.Correct > 0 {
+			variant.CostPerCorrect = variant.TotalCostUSD / float64(variant.Correct)
+		}
+		report.Variants[i] = variant
+	}
+	for _, evaluation := range e.evaluations {
+		if evaluation.Feedback != nil {
+			report.WithFeedback++
+		}
+	}
+	return report
+}
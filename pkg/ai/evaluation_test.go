@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEvaluator(t *testing.T) {
+	if _, err := NewEvaluator([]EvaluationVariant{{Name: "a"}}, 0); err == nil {
+		t.Error("expected an error for a single variant")
+	}
+
+	evaluator, err := NewEvaluator([]EvaluationVariant{
+		{Name: "large", Config: Config{TestMode: true, Model: "large"}, InputCostPerMTokens: 3, OutputCostPerMTokens: 15},
+		{Name: "small", Config: Config{TestMode: true, Model: "small"}},
+	}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := AnalysisRequest{Type: AnalysisTypeDiagnostic, Context: "pods crash looping"}
+	var ids []string
+	for range 3 {
+		evaluation, err := evaluator.Evaluate(context.Background(), "pod-health", request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(evaluation.Results) != 2 || evaluation.Results[0].Model.Model != "large" || evaluation.Results[1].Summary == "" {
+			t.Fatalf("expected both variants' answers, got %+v", evaluation.Results)
+		}
+		ids = append(ids, evaluation.ID)
+	}
+	if list := evaluator.List(); len(list) != 2 || list[0].ID != ids[2] {
+		t.Fatalf("expected the 2 newest evaluations kept, newest first, got %+v", list)
+	}
+
+	if _, err := evaluator.Feedback(ids[0], EvaluationFeedback{Preferred: "large"}); !errors.Is(err, ErrEvaluationNotFound) {
+		t.Errorf("expected ErrEvaluationNotFound for a dropped evaluation, got %v", err)
+	}
+	if _, err := evaluator.Feedback(ids[1], EvaluationFeedback{Preferred: "medium"}); err == nil {
+		t.Error("expected an error for an unknown variant")
+	}
+	if _, err := evaluator.Feedback(ids[1], EvaluationFeedback{}); err == nil {
+		t.Error("expected an error for empty feedback")
+	}
+	if _, err := evaluator.Feedback(ids[1], EvaluationFeedback{Correct: map[string]bool{"large": true, "small": false}, Preferred: "large"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := evaluator.Feedback(ids[2], EvaluationFeedback{Correct: map[string]bool{"large": true, "small": true}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := evaluator.Report()
+	if report.Evaluations != 2 || report.WithFeedback != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	large, small := report.Variants[0], report.Variants[1]
+	if large.Accuracy != 1 || small.Accuracy != 0.5 || large.Preferred != 1 || small.Preferred != 0 {
+		t.Errorf("unexpected accuracy %+v and %+v", large, small)
+	}
+	if large.TotalCostUSD <= 0 || small.TotalCostUSD != 0 || large.CostPerCorrect != large.TotalCostUSD/2 {
+		t.Errorf("expected cost from large's prices only, got %+v and %+v", large, small)
+	}
+	if large.AvgInputTokens == 0 || large.AvgOutputTokens == 0 {
+		t.Errorf("expected token counts, got %+v", large)
+	}
+}
@@ -0,0 +1,221 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// EvidenceKindCheckResult is evidence taken from a health check result
+	EvidenceKindCheckResult = "check_result"
+
+	maxEvidenceLines    = 20
+	maxEvidenceLineSize = 200
+)
+
+// checkCommands maps built-in checks to the kubectl command that shows the
+// same data, so answers can say what they were based on in familiar terms
+var checkCommands = map[string]string{
+	"pod-health":     "kubectl get pods -A",
+	"node-health":    "kubectl get nodes",
+	"service-health": "kubectl get services,endpoints -A",
+	"event-rates":    "kubectl get events -A",
+	"service-mesh":   "kubectl get peerauthentications,destinationrules -A",
+	"pod-security":   "kubectl get namespaces -L pod-security.kubernetes.io/enforce",
+	"node-versions":  "kubectl get nodes -o wide",
+}
+
+// itemizedDetails are check details whose items each answer a question on
+// their own, such as why a pod is pending, so every item gets its own line
+var itemizedDetails = map[string]bool{
+	"pending_reasons": true,
+}
+
+var (
+	citationPattern = regexp.MustCompile(`\[(E\d+)\]`)
+	resourcePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?/[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+)
+
+// Evidence links an answer or finding to the data it was based on
+type Evidence struct {
+	ID        string       `json:"id"`                  // Citation ID used in answers, e.g. "E1"
+	Kind      string       `json:"kind"`                // Source type, e.g. check_result
+	Source    string       `json:"source"`              // Source name, e.g. check/pod-health
+	Command   string       `json:"command,omitempty"`   // Equivalent kubectl command, if any
+	Summary   string       `json:"summary"`             // One-line description of what the evidence shows
+	Status    HealthStatus `json:"status,omitempty"`    // Health of the source check
+	Resources []string     `json:"resources,omitempty"` // namespace/name references found in the data
+	Lines     []string     `json:"lines,omitempty"`     // Relevant excerpt
+	Raw       string       `json:"raw,omitempty"`       // Full source data for expandable views
+	Cited     bool         `json:"cited"`               // Whether the answer cited this evidence
+}
+
+// CollectEvidence builds citable evidence from cluster health, unhealthy
+// checks first
+func CollectEvidence(health *ClusterHealth) []Evidence {
+	if health == nil {
+		return []Evidence{}
+	}
+
+	checks := make([]CheckResult, len(health.Checks))
+	copy(checks, health.Checks)
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Status != HealthStatusHealthy && checks[j].Status == HealthStatusHealthy
+	})
+
+	evidence := make([]Evidence, 0, len(checks))
+	for i, check := range checks {
+		evidence = append(evidence, checkEvidence(fmt.Sprintf("E%d", i+1), check))
+	}
+	return evidence
+}
+
+// checkEvidence describes a single check result as evidence
+func checkEvidence(id string, check CheckResult) Evidence {
+	raw, _ := json.MarshalIndent(check, "", "  ")
+
+	evidence := Evidence{
+		ID:      id,
+		Kind:    EvidenceKindCheckResult,
+		Source:  "check/" + check.Name,
+		Command: checkCommands[check.Name],
+		Summary: fmt.Sprintf("%s is %s: %s", check.Name, check.Status, check.Message),
+		Status:  check.Status,
+		Raw:     string(raw),
+	}
+
+	keys := make([]string, 0, len(check.Details))
+	for key := range check.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		value := check.Details[key]
+		for _, ref := range resourceRefs(value) {
+			if !seen[ref] {
+				seen[ref] = true
+				evidence.Resources = append(evidence.Resources, ref)
+			}
+		}
+		if itemizedDetails[key] {
+			for _, item := range detailItems(value) {
+				if len(evidence.Lines) < maxEvidenceLines {
+					evidence.Lines = append(evidence.Lines, truncateLine(item))
+				}
+			}
+			continue
+		}
+		if len(evidence.Lines) < maxEvidenceLines {
+			evidence.Lines = append(evidence.Lines, truncateLine(fmt.Sprintf("%s: %v", key, value)))
+		}
+	}
+
+	return evidence
+}
+
+// resourceRefs returns namespace/name strings from a detail value
+func resourceRefs(value interface{}) []string {
+	var candidates []string
+	switch v := value.(type) {
+	case string:
+		candidates = []string{v}
+	case []string:
+		candidates = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				candidates = append(candidates, s)
+			}
+		}
+	}
+
+	refs := make([]string, 0)
+	for _, candidate := range candidates {
+		if resourcePattern.MatchString(candidate) {
+			refs = append(refs, candidate)
+		}
+	}
+	return refs
+}
+
+// detailItems returns the strings in a list detail
+func detailItems(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	}
+	return []string{fmt.Sprint(value)}
+}
+
+// truncateLine shortens an excerpt line to maxEvidenceLineSize
+func truncateLine(line string) string {
+	if len(line) <= maxEvidenceLineSize {
+		return line
+	}
+	return line[:maxEvidenceLineSize] + "..."
+}
+
+// evidencePrompt describes the available evidence and asks for citations
+func evidencePrompt(evidence []Evidence) string {
+	if len(evidence) == 0 {
+		return ""
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("\n\nEVIDENCE (cite the IDs your answer relies on inline, e.g. [E1]):\n")
+	for _, e := range evidence {
+		fmt.Fprintf(&prompt, "[%s] %s", e.ID, e.Summary)
+		if len(e.Resources) > 0 {
+			fmt.Fprintf(&prompt, " (resources: %s)", strings.Join(e.Resources, ", "))
+		}
+		prompt.WriteString("\n")
+	}
+	return prompt.String()
+}
+
+// attachEvidence marks the evidence cited by a response. When nothing was
+// cited the answer is assumed to rest on the unhealthy checks.
+func attachEvidence(response *AnalysisResponse, evidence []Evidence) []Evidence {
+	cited := make(map[string]bool)
+	texts := []string{response.Summary, response.Diagnosis}
+	for _, rec := range response.Recommendations {
+		texts = append(texts, rec.Title, rec.Description)
+		for _, id := range rec.Evidence {
+			cited[id] = true
+		}
+	}
+	for _, text := range texts {
+		for _, match := range citationPattern.FindAllStringSubmatch(text, -1) {
+			cited[match[1]] = true
+		}
+	}
+
+	attached := make([]Evidence, 0)
+	for _, e := range evidence {
+		if cited[e.ID] {
+			e.Cited = true
+			attached = append(attached, e)
+		}
+	}
+	if len(attached) > 0 {
+		return attached
+	}
+
+	for _, e := range evidence {
+		if e.Status != HealthStatusHealthy {
+			attached = append(attached, e)
+		}
+	}
+	return attached
+}
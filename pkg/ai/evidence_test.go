@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func evidenceTestHealth() *ClusterHealth {
+	return &ClusterHealth{
+		ClusterName: "prod",
+		Status:      HealthStatusDegraded,
+		Checks: []CheckResult{
+			{Name: "node-health", Status: HealthStatusHealthy, Message: "All 3 nodes ready"},
+			{
+				Name:    "pod-health",
+				Status:  HealthStatusDegraded,
+				Message: "Pod issues detected: 3 failed",
+				Details: map[string]interface{}{
+					"failed_pods":       3,
+					"high_restart_pods": []string{"payments/api-7d9f", "payments/worker-1", "not a resource"},
+				},
+			},
+		},
+	}
+}
+
+func TestCollectEvidence(t *testing.T) {
+	evidence := CollectEvidence(evidenceTestHealth())
+	if len(evidence) != 2 {
+		t.Fatalf("expected evidence per check, got %d", len(evidence))
+	}
+
+	pods := evidence[0]
+	if pods.ID != "E1" || pods.Source != "check/pod-health" {
+		t.Fatalf("expected unhealthy pod-health first as E1, got %s %s", pods.ID, pods.Source)
+	}
+	if pods.Command != "kubectl get pods -A" {
+		t.Errorf("expected equivalent kubectl command, got %q", pods.Command)
+	}
+	if len(pods.Resources) != 2 || pods.Resources[0] != "payments/api-7d9f" {
+		t.Errorf("expected namespace/name resources, got %v", pods.Resources)
+	}
+	if len(pods.Lines) != 2 || pods.Lines[0] != "failed_pods: 3" {
+		t.Errorf("expected sorted detail lines, got %v", pods.Lines)
+	}
+	if !strings.Contains(pods.Raw, `"high_restart_pods"`) {
+		t.Errorf("expected raw check data, got %s", pods.Raw)
+	}
+
+	if got := CollectEvidence(nil); len(got) != 0 {
+		t.Errorf("expected no evidence without health data, got %d", len(got))
+	}
+}
+
+func TestCollectEvidence_PendingReasons(t *testing.T) {
+	health := &ClusterHealth{Checks: []CheckResult{{
+		Name:   "pod-health",
+		Status: HealthStatusUnhealthy,
+		Details: map[string]interface{}{
+			"pending_reasons": []interface{}{
+				"pod ml/trainer-0 pending because no node has gpu=true + topology.kubernetes.io/zone=us-east-1a",
+				"pod ml/trainer-1 pending because 3 of 3 node(s) have untolerated taint dedicated=infra:NoSchedule",
+			},
+		},
+	}}}
+
+	lines := CollectEvidence(health)[0].Lines
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "pod ml/trainer-0 pending because no node has gpu=true") {
+		t.Errorf("expected one evidence line per pending pod, got %v", lines)
+	}
+}
+
+func TestAttachEvidence(t *testing.T) {
+	evidence := CollectEvidence(evidenceTestHealth())
+
+	tests := []struct {
+		name     string
+		response AnalysisResponse
+		wantIDs  []string
+		cited    bool
+	}{
+		{
+			name:     "inline citation",
+			response: AnalysisResponse{Summary: "Pods in payments are crash looping [E1]."},
+			wantIDs:  []string{"E1"},
+			cited:    true,
+		},
+		{
+			name: "recommendation evidence",
+			response: AnalysisResponse{Recommendations: []Recommendation{
+				{Title: "Check nodes", Evidence: []string{"E2"}},
+			}},
+			wantIDs: []string{"E2"},
+			cited:   true,
+		},
+		{
+			name:     "unknown citation falls back to unhealthy checks",
+			response: AnalysisResponse{Summary: "See [E9]."},
+			wantIDs:  []string{"E1"},
+		},
+		{
+			name:     "no citations falls back to unhealthy checks",
+			response: AnalysisResponse{Summary: "Pods are failing."},
+			wantIDs:  []string{"E1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attached := attachEvidence(&tt.response, evidence)
+			if len(attached) != len(tt.wantIDs) {
+				t.Fatalf("expected %v, got %+v", tt.wantIDs, attached)
+			}
+			for i, id := range tt.wantIDs {
+				if attached[i].ID != id {
+					t.Errorf("expected %s, got %s", id, attached[i].ID)
+				}
+				if attached[i].Cited != tt.cited {
+					t.Errorf("expected cited=%v, got %v", tt.cited, attached[i].Cited)
+				}
+			}
+		})
+	}
+}
+
+func TestAssistantQuery_Evidence(t *testing.T) {
+	assistant := NewAssistant(NewClient(Config{TestMode: true}))
+
+	response, err := assistant.Query(context.Background(), "What is going on?", evidenceTestHealth())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Evidence) != 1 || response.Evidence[0].Source != "check/pod-health" {
+		t.Errorf("expected pod-health evidence, got %+v", response.Evidence)
+	}
+}
@@ -15,6 +15,9 @@ type KubectlExecutor struct {
 	kubectlPath string
 	namespace   string
 	dryRunMode  bool
+	limiter     *ToolLimiter
+	cache       *ToolCache
+	cluster     string // Scopes cached results
 }
 
 // NewKubectlExecutor creates a new kubectl executor
@@ -23,10 +26,31 @@ func NewKubectlExecutor(namespace string) *KubectlExecutor {
 		kubectlPath: "kubectl",
 		namespace:   namespace,
 		dryRunMode:  false,
+		limiter:     SharedToolLimiter(),
+		cache:       SharedToolCache(),
 	}
 }
 
-// Execute runs a kubectl command
+// SetLimiter replaces the shared tool limiter, e.g. with one tuned for a
+// large cluster. A nil limiter disables rate limiting.
+func (k *KubectlExecutor) SetLimiter(limiter *ToolLimiter) {
+	k.limiter = limiter
+}
+
+// SetCache replaces the shared tool cache. A nil cache runs every command.
+func (k *KubectlExecutor) SetCache(cache *ToolCache) {
+	k.cache = cache
+}
+
+// SetCluster names the cluster commands run against, so cached results of
+// different clusters are kept apart
+func (k *KubectlExecutor) SetCluster(cluster string) {
+	k.cluster = cluster
+}
+
+// Execute runs a kubectl command. Read-only commands reuse a recent result
+// of the same command from the tool cache; other commands clear the
+// cluster's cached results.
 func (k *KubectlExecutor) Execute(ctx context.Context, command string) (string, error) {
 	if k.dryRunMode {
 		return k.DryRun(ctx, command)
@@ -38,6 +62,31 @@ func (k *KubectlExecutor) Execute(ctx context.Context, command string) (string,
 		return "", fmt.Errorf("command validation failed: %w", err)
 	}
 
+	if k.cache == nil {
+		return k.run(ctx, args)
+	}
+	if IsReadOnlyCommand(command) {
+		return k.cache.Do(ctx, k.cluster, strings.Join(args, " "), func(ctx context.Context) (string, error) {
+			return k.run(ctx, args)
+		})
+	}
+
+	// A command that may change the cluster makes its cached results stale
+	defer k.cache.Refresh(k.cluster)
+	return k.run(ctx, args)
+}
+
+// run executes validated kubectl arguments within the rate limit
+func (k *KubectlExecutor) run(ctx context.Context, args []string) (string, error) {
+	// Wait for the shared rate limit before touching the API server
+	if k.limiter != nil {
+		release, err := k.limiter.Acquire(ctx)
+		if err != nil {
+			return "", fmt.Errorf("waiting for rate limit: %w", err)
+		}
+		defer release()
+	}
+
 	// Execute with timeout
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -47,6 +96,9 @@ func (k *KubectlExecutor) Execute(ctx context.Context, command string) (string,
 	// Use exec.CommandContext with explicit args to prevent shell injection
 	cmd := exec.CommandContext(ctx, "kubectl", args...) // #nosec G204 - args are validated and sanitized above
 	output, err := cmd.CombinedOutput()
+	if k.limiter != nil {
+		k.limiter.Observe(string(output), err)
+	}
 
 	if err != nil {
 		return string(output), fmt.Errorf("command failed: %w, output: %s", err, output)
@@ -152,6 +204,34 @@ func (k *KubectlExecutor) DryRun(ctx context.Context, command string) (string, e
 	return fmt.Sprintf("Command would execute: %s", command), nil
 }
 
+// ServerDryRun runs a command with --dry-run=server, replacing any dry-run
+// and output flags, so the API server validates and admits the change
+// without persisting it. Objects are printed as JSON, except by delete,
+// which can only name them.
+func (k *KubectlExecutor) ServerDryRun(ctx context.Context, command string) (string, error) {
+	args, err := k.parseAndValidateCommand(command)
+	if err != nil {
+		return "", fmt.Errorf("command validation failed: %w", err)
+	}
+
+	dryRunArgs := make([]string, 0, len(args)+3)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" || arg == "--output":
+			i++ // Drop the value too
+		case strings.HasPrefix(arg, "--dry-run"), strings.HasPrefix(arg, "--output="), strings.HasPrefix(arg, "-o"):
+		default:
+			dryRunArgs = append(dryRunArgs, arg)
+		}
+	}
+	dryRunArgs = append(dryRunArgs, "--dry-run=server")
+	if len(args) == 0 || args[0] != "delete" {
+		dryRunArgs = append(dryRunArgs, "-o", "json")
+	}
+	return k.run(ctx, dryRunArgs)
+}
+
 // DefaultSafetyChecker implements SafetyChecker with safety rules
 type DefaultSafetyChecker struct {
 	allowedVerbs      []string
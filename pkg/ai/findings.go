@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+var (
+	// findingPattern matches a finding type ID and the resource after it,
+	// e.g. "KP-NODE-001 node/worker-1" or "KP-POD-001 on pod/shop/api-1"
+	findingPattern = regexp.MustCompile(`(?i)\b(KP-[A-Z]+-\d{3})\b(?:\s+(?:on\s+)?([a-z]+/[a-z0-9][a-z0-9./-]*[a-z0-9]))?`)
+	// sectionPattern matches the headings of a structured text response
+	sectionPattern = regexp.MustCompile(`^[A-Z][A-Z_ ]*:`)
+)
+
+// findingsPrompt lists the finding taxonomy so diagnoses classify problems
+// with stable IDs that can be tracked across analyses
+func findingsPrompt() string {
+	var prompt strings.Builder
+	prompt.WriteString("\nFINDING TYPES (classify each problem you find with the ID that fits it, naming the affected resource; leave out problems no type fits):\n")
+	for _, t := range findings.Catalog() {
+		fmt.Fprintf(&prompt, "%s: %s\n", t.ID, t.Title)
+	}
+	return prompt.String()
+}
+
+// extractFindings reads the FINDINGS section of a text response. Only IDs
+// in the taxonomy are kept, so IDs the model invents are dropped.
+func extractFindings(text string) []findings.Finding {
+	var list []findings.Finding
+	inSection := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, " \t-*#"))
+		switch {
+		case strings.HasPrefix(strings.ToUpper(line), "FINDINGS:"):
+			inSection = true
+			line = line[len("FINDINGS:"):]
+		case inSection && sectionPattern.MatchString(line):
+			inSection = false
+		}
+		if !inSection {
+			continue
+		}
+		for _, match := range findingPattern.FindAllStringSubmatch(line, -1) {
+			list = append(list, findings.Finding{ID: match[1], Subject: match[2]})
+		}
+	}
+	return findings.Normalize(list)
+}
+
+// normalizeRecommendationFindings canonicalizes the finding type IDs
+// recommendations address and clears unknown ones
+func normalizeRecommendationFindings(recommendations []Recommendation) []Recommendation {
+	for i, rec := range recommendations {
+		if rec.Finding == "" {
+			continue
+		}
+		t, ok := findings.Lookup(rec.Finding)
+		recommendations[i].Finding = ""
+		if ok {
+			recommendations[i].Finding = t.ID
+		}
+	}
+	return recommendations
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+func TestExtractFindings(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []findings.Finding
+	}{
+		{
+			name: "findings section",
+			text: `SUMMARY: worker-1 is out of memory and api-1 is OOM killed, see KP-NODE-006
+DIAGNOSIS: The node is overcommitted
+FINDINGS:
+- KP-NODE-001 node/worker-1
+- kp-pod-003 on pod/shop/api-1
+- KP-ZZZ-001 pod/shop/api-2
+NEXT_STEPS: Raise the limit`,
+			want: []findings.Finding{
+				{ID: findings.NodeMemoryPressure, Subject: "node/worker-1"},
+				{ID: findings.PodOOMKilled, Subject: "pod/shop/api-1"},
+			},
+		},
+		{
+			name: "inline and without subject",
+			text: "- **FINDINGS:** KP-SVC-001 service/shop/api, KP-NODE-004\n\n- EVIDENCE: none",
+			want: []findings.Finding{
+				{ID: findings.ServiceNoEndpoint, Subject: "service/shop/api"},
+				{ID: findings.NodeNotReady},
+			},
+		},
+		{
+			name: "IDs outside the section are ignored",
+			text: "SUMMARY: This resembles KP-NODE-001 but isn't",
+			want: []findings.Finding{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractFindings(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractFindings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResponse_Findings(t *testing.T) {
+	text := "```json\n" + `{"summary": "Disk full", "findings": [{"id": "kp-node-002", "subject": "node/worker-3"}, {"id": "KP-NODE-777"}],
+"recommendations": [{"title": "Prune images", "finding": "KP-NODE-002"}]}` + "\n```"
+	response, err := NewResponseParser().ParseResponse(text, AnalysisRequest{Type: AnalysisTypeDiagnostic})
+	if err != nil {
+		t.Fatalf("ParseResponse() error = %v", err)
+	}
+	want := []findings.Finding{{ID: findings.NodeDiskPressure, Subject: "node/worker-3"}}
+	if !reflect.DeepEqual(response.Findings, want) {
+		t.Errorf("Findings = %+v, want %+v", response.Findings, want)
+	}
+	if len(response.Recommendations) != 1 || response.Recommendations[0].Finding != findings.NodeDiskPressure {
+		t.Errorf("unexpected recommendations %+v", response.Recommendations)
+	}
+}
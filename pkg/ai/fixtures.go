@@ -0,0 +1,315 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+// Scenarios test mode can answer with
+const (
+	ScenarioHealthy      = "healthy"       // Nothing wrong beyond noise
+	ScenarioCrashLoop    = "crashloop"     // A bad rollout crash-looping pods across a namespace
+	ScenarioNodePressure = "node-pressure" // Workers under memory and disk pressure, evicting pods
+	ScenarioEtcdDegraded = "etcd-degraded" // Slow etcd disks causing leader elections and API latency
+)
+
+// Scenarios lists the fixtures test mode can answer with
+var Scenarios = []string{ScenarioHealthy, ScenarioCrashLoop, ScenarioNodePressure, ScenarioEtcdDegraded}
+
+// scenarioKey is the context key of the scenario a request asked for
+type scenarioKey struct{}
+
+// WithScenario returns a context asking test mode to answer with scenario,
+// overriding the configured one. Providers other than test mode ignore it.
+func WithScenario(ctx context.Context, scenario string) context.Context {
+	return context.WithValue(ctx, scenarioKey{}, scenario)
+}
+
+// ScenarioFrom returns the scenario ctx asks for, or nothing
+func ScenarioFrom(ctx context.Context) string {
+	scenario, _ := ctx.Value(scenarioKey{}).(string)
+	return scenario
+}
+
+// fixture is a scenario's canned analysis and the answer to free-text
+// questions
+type fixture struct {
+	analysis  StructuredResponse
+	rootCause string   // Correlation root cause of batch analyses
+	checks    []string // Checks the scenario's batch diagnoses fail with
+	answer    string
+}
+
+// fixtures are written like the analyses models return, with findings from
+// the catalog, so callers see realistic shapes
+var fixtures = map[string]fixture{
+	ScenarioHealthy: {
+		analysis: StructuredResponse{
+			Summary:    "The cluster is healthy; no action is needed",
+			Diagnosis:  "All nodes are Ready and workloads are running at their desired replicas. Two pod restarts in the last hour were liveness probe timeouts during a node image pull and recovered on their own.",
+			Confidence: 0.92,
+			Severity:   SeverityInfo,
+			Recommendations: []Recommendation{
+				{
+					Title:       "Raise the liveness probe timeout of slow-starting pods",
+					Description: "Probes with a 1s timeout failed while images were pulled. A 3s timeout avoids restarts under brief load.",
+					Priority:    3,
+					Category:    "reliability",
+					Impact:      "low",
+					Effort:      "low",
+				},
+			},
+			Actions: []SuggestedAction{
+				{
+					ID:          "review-restarts",
+					Type:        ActionTypeInvestigate,
+					Title:       "Review recent restarts",
+					Description: "Confirm the restarts were transient",
+					Command:     "kubectl get pods -A --sort-by=.status.containerStatuses[0].restartCount",
+				},
+			},
+			Context: map[string]interface{}{},
+		},
+		rootCause: "No shared cause; the failures are transient",
+		answer:    "The cluster is healthy. All nodes are Ready, workloads are at their desired replicas and no alerts are firing. The only recent events are two liveness probe restarts during an image pull, which recovered on their own.",
+	},
+	ScenarioCrashLoop: {
+		analysis: StructuredResponse{
+			Summary:    "Pods of the checkout deployment are crash-looping after the latest rollout",
+			Diagnosis:  "Revision 14 of deployment shop/checkout reads DATABASE_URL from secret checkout-db, which the rollout renamed to checkout-database. The container exits with status 1 at startup and 6 of 6 pods are in CrashLoopBackOff; the orders service has no ready endpoints as a result.",
+			Confidence: 0.88,
+			Severity:   SeverityCritical,
+			Recommendations: []Recommendation{
+				{
+					Title:       "Roll back deployment shop/checkout",
+					Description: "Revision 13 ran without errors. Rolling back restores service while the secret reference is fixed.",
+					Priority:    1,
+					Category:    "remediation",
+					Impact:      "high",
+					Effort:      "low",
+					Finding:     findings.PodCrashLoop,
+				},
+				{
+					Title:       "Fix the secret reference in the pod template",
+					Description: "Point the DATABASE_URL secretKeyRef at checkout-database, or restore the old secret name.",
+					Priority:    2,
+					Category:    "configuration",
+					Impact:      "high",
+					Effort:      "low",
+					Finding:     findings.PodCrashLoop,
+				},
+			},
+			Actions: []SuggestedAction{
+				{
+					ID:               "rollback-checkout",
+					Type:             ActionTypeKubectl,
+					Title:            "Roll back checkout",
+					Description:      "Return deployment shop/checkout to revision 13",
+					Command:          "kubectl rollout undo deployment/checkout -n shop --to-revision=13",
+					RequiresApproval: true,
+				},
+				{
+					ID:          "checkout-logs",
+					Type:        ActionTypeInvestigate,
+					Title:       "Read the crash logs",
+					Description: "Show the output of the last crashed container",
+					Command:     "kubectl logs deployment/checkout -n shop --previous",
+				},
+			},
+			Findings: []findings.Finding{
+				{ID: findings.PodCrashLoop, Subject: "pod/shop/checkout-7d9f8b6c5-x2k4p", Message: "Back-off restarting failed container checkout"},
+				{ID: findings.PodHighRestarts, Subject: "pod/shop/checkout-7d9f8b6c5-q8w1m", Message: "Restarted 23 times in 40 minutes"},
+				{ID: findings.ServiceNoEndpoint, Subject: "service/shop/orders", Message: "No ready endpoints"},
+			},
+			Context: map[string]interface{}{},
+		},
+		rootCause: "Deployment shop/checkout references a secret its latest rollout renamed",
+		checks:    []string{"pod-health", "service-health"},
+		answer:    "Deployment shop/checkout is crash-looping: all 6 pods of revision 14 exit at startup because they read DATABASE_URL from secret checkout-db, which was renamed to checkout-database. Roll back with `kubectl rollout undo deployment/checkout -n shop --to-revision=13`, then fix the secret reference.",
+	},
+	ScenarioNodePressure: {
+		analysis: StructuredResponse{
+			Summary:    "Two worker nodes are under memory and disk pressure and evicting pods",
+			Diagnosis:  "worker-2 and worker-3 report MemoryPressure after the analytics batch jobs were scheduled without memory limits. worker-3 also reports DiskPressure: its image filesystem is 94% full. The kubelet has evicted 11 pods in 20 minutes and several have been rescheduled onto the same nodes.",
+			Confidence: 0.85,
+			Severity:   SeverityHigh,
+			Recommendations: []Recommendation{
+				{
+					Title:       "Set memory limits on the analytics jobs",
+					Description: "The jobs request 512Mi but use up to 6Gi. Limits let the scheduler place them on nodes with room.",
+					Priority:    1,
+					Category:    "resources",
+					Impact:      "high",
+					Effort:      "low",
+					Finding:     findings.NodeMemoryPressure,
+				},
+				{
+					Title:       "Prune unused images on worker-3",
+					Description: "Image garbage collection is behind; lower imageGCHighThresholdPercent or prune unused images.",
+					Priority:    2,
+					Category:    "capacity",
+					Impact:      "medium",
+					Effort:      "low",
+					Finding:     findings.NodeDiskPressure,
+				},
+			},
+			Actions: []SuggestedAction{
+				{
+					ID:               "cordon-worker-3",
+					Type:             ActionTypeKubectl,
+					Title:            "Cordon worker-3",
+					Description:      "Stop new pods landing on the node under disk pressure",
+					Command:          "kubectl cordon worker-3",
+					RequiresApproval: true,
+				},
+				{
+					ID:          "top-nodes",
+					Type:        ActionTypeInvestigate,
+					Title:       "Show node usage",
+					Description: "Compare memory use across the workers",
+					Command:     "kubectl top nodes",
+				},
+			},
+			Findings: []findings.Finding{
+				{ID: findings.NodeMemoryPressure, Subject: "node/worker-2", Message: "MemoryPressure is True"},
+				{ID: findings.NodeMemoryPressure, Subject: "node/worker-3", Message: "MemoryPressure is True"},
+				{ID: findings.NodeDiskPressure, Subject: "node/worker-3", Message: "Image filesystem 94% full"},
+				{ID: findings.PodFailed, Subject: "pod/analytics/report-28411-6xk2d", Message: "Evicted: the node was low on memory"},
+			},
+			Context: map[string]interface{}{},
+		},
+		rootCause: "Analytics batch jobs without memory limits are exhausting worker-2 and worker-3",
+		checks:    []string{"node-health", "pod-health"},
+		answer:    "worker-2 and worker-3 are under memory pressure because the analytics batch jobs use up to 6Gi against 512Mi requests, and worker-3's image filesystem is 94% full. The kubelet has evicted 11 pods. Set memory limits on the jobs and prune images on worker-3; cordon it until disk usage drops.",
+	},
+	ScenarioEtcdDegraded: {
+		analysis: StructuredResponse{
+			Summary:    "etcd is degraded by slow disks, causing leader elections and API server latency",
+			Diagnosis:  "The etcd member on control-plane-2 reports WAL fsync durations above 500ms on a shared volume. Heartbeats time out, and the cluster has changed leader 7 times in 30 minutes. API server requests wait on etcd: p99 latency is 4.2s and controllers are falling behind.",
+			Confidence: 0.81,
+			Severity:   SeverityCritical,
+			Recommendations: []Recommendation{
+				{
+					Title:       "Move etcd data to dedicated low-latency disks",
+					Description: "etcd needs fsync under 10ms. The shared volume on control-plane-2 is contended by log shipping.",
+					Priority:    1,
+					Category:    "infrastructure",
+					Impact:      "high",
+					Effort:      "high",
+					References:  []string{"https://etcd.io/docs/v3.5/op-guide/hardware/"},
+				},
+				{
+					Title:       "Defragment the etcd database",
+					Description: "The database is 3.1GB with 1.4GB in use; defragmenting one member at a time shortens compaction pauses.",
+					Priority:    2,
+					Category:    "maintenance",
+					Impact:      "medium",
+					Effort:      "medium",
+				},
+			},
+			Actions: []SuggestedAction{
+				{
+					ID:          "etcd-status",
+					Type:        ActionTypeInvestigate,
+					Title:       "Check etcd member status",
+					Description: "Show leader, database size and raft index of every member",
+					Command:     "kubectl exec -n kube-system etcd-control-plane-1 -- etcdctl endpoint status --cluster -w table",
+				},
+				{
+					ID:          "apiserver-latency",
+					Type:        ActionTypeInvestigate,
+					Title:       "Check API server latency",
+					Description: "Confirm requests are waiting on etcd",
+					Command:     "kubectl get --raw /metrics | grep apiserver_request_duration_seconds",
+				},
+			},
+			Findings: []findings.Finding{
+				{ID: findings.NodeProblem, Subject: "node/control-plane-2", Message: "etcd WAL fsync p99 above 500ms"},
+				{ID: findings.PodNotReady, Subject: "pod/kube-system/etcd-control-plane-2", Message: "Readiness probe failed: leader changed"},
+			},
+			Context: map[string]interface{}{},
+		},
+		rootCause: "Slow disks on control-plane-2 are delaying etcd and every request through the API server",
+		checks:    []string{"etcd-health", "api-server-health"},
+		answer:    "etcd is degraded: the member on control-plane-2 takes over 500ms to fsync its WAL, so heartbeats time out and leadership has changed 7 times in 30 minutes. The API server's p99 latency is 4.2s as a result. Move etcd onto dedicated low-latency disks and defragment the members one at a time.",
+	},
+}
+
+// scenarioSignals are prompt text that suggests a scenario when none is
+// asked for, in order of precedence
+var scenarioSignals = []struct {
+	scenario string
+	signals  []string
+}{
+	{ScenarioEtcdDegraded, []string{"etcdserver", "etcd leader", "etcd-health", "wal fsync"}},
+	{ScenarioNodePressure, []string{"memorypressure", "diskpressure", "pidpressure", "evicted"}},
+	{ScenarioCrashLoop, []string{"crashloopbackoff", "back-off restarting"}},
+}
+
+// selectScenario picks the fixture to answer with: the one ctx asks for,
+// then the configured one, then one suggested by the prompt, healthy when
+// nothing suggests trouble
+func selectScenario(ctx context.Context, configured, prompt string) string {
+	for _, scenario := range []string{ScenarioFrom(ctx), configured} {
+		if _, ok := fixtures[scenario]; ok {
+			return scenario
+		}
+	}
+	lower := strings.ToLower(prompt)
+	for _, candidate := range scenarioSignals {
+		for _, signal := range candidate.signals {
+			if strings.Contains(lower, signal) {
+				return candidate.scenario
+			}
+		}
+	}
+	return ScenarioHealthy
+}
+
+// batchChecksPattern finds the checks a batch prompt names
+var batchChecksPattern = regexp.MustCompile(`health checks are failing \(([^)]*)\)`)
+
+// fixtureAnalysis returns the scenario's analysis as the JSON a model
+// writes. Batch prompts get a diagnosis per check they name and a
+// correlation, like batch analyses do.
+func fixtureAnalysis(scenario, prompt string) string {
+	f := fixtures[scenario]
+	analysis := f.analysis
+	if strings.Contains(prompt, "ANALYSIS REQUEST: "+string(AnalysisTypeBatch)) {
+		checks := f.checks
+		if match := batchChecksPattern.FindStringSubmatch(prompt); match != nil {
+			checks = strings.Split(match[1], ", ")
+		}
+		diagnoses := make([]CheckDiagnosis, len(checks))
+		for i, check := range checks {
+			diagnoses[i] = CheckDiagnosis{
+				Check:           check,
+				Summary:         analysis.Summary,
+				Diagnosis:       analysis.Diagnosis,
+				Confidence:      analysis.Confidence,
+				Severity:        analysis.Severity,
+				Recommendations: analysis.Recommendations,
+				Actions:         analysis.Actions,
+				Findings:        analysis.Findings,
+			}
+		}
+		analysis.Context = map[string]interface{}{
+			"diagnoses": diagnoses,
+			"correlation": map[string]interface{}{
+				"root_cause": f.rootCause,
+				"groups":     []CorrelationGroup{{Checks: checks, Cause: f.rootCause}},
+			},
+		}
+	}
+	data, _ := json.Marshal(analysis)
+	return string(data)
+}
+
+// fixtureAnswer returns the scenario's answer to a free-text question
+func fixtureAnswer(scenario string) string {
+	return fixtures[scenario].answer
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+func TestSelectScenario(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		configured string
+		prompt     string
+		want       string
+	}{
+		{"nothing suggested", context.Background(), "", "CONTEXT: cluster summary", ScenarioHealthy},
+		{"configured", context.Background(), ScenarioNodePressure, "", ScenarioNodePressure},
+		{"requested over configured", WithScenario(context.Background(), ScenarioEtcdDegraded), ScenarioNodePressure, "", ScenarioEtcdDegraded},
+		{"unknown requested", WithScenario(context.Background(), "meltdown"), "", "", ScenarioHealthy},
+		{"inferred crashloop", context.Background(), "", `"reason": "CrashLoopBackOff"`, ScenarioCrashLoop},
+		{"inferred node pressure", context.Background(), "", `"type": "MemoryPressure"`, ScenarioNodePressure},
+		{"inferred etcd", context.Background(), "", "etcdserver: leader changed", ScenarioEtcdDegraded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectScenario(tt.ctx, tt.configured, tt.prompt); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	for _, scenario := range Scenarios {
+		t.Run(scenario, func(t *testing.T) {
+			client := NewClient(Config{TestMode: true, TestScenario: scenario})
+			ctx := context.Background()
+
+			response, err := client.Analyze(ctx, AnalysisRequest{Type: AnalysisTypeDiagnostic})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if response.Summary != fixtures[scenario].analysis.Summary || len(response.Recommendations) == 0 || len(response.Actions) == 0 {
+				t.Errorf("expected the fixture's analysis, got %+v", response)
+			}
+			for _, finding := range response.Findings {
+				if _, ok := findings.Lookup(finding.ID); !ok {
+					t.Errorf("expected findings from the catalog, got %s", finding.ID)
+				}
+			}
+
+			checks := []CheckResult{{Name: "pod-health"}, {Name: "node-health"}}
+			batch, err := client.AnalyzeBatch(ctx, checks, DiagnosticContext{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(batch.Diagnoses) != 2 || len(batch.Unanalyzed) != 0 || batch.Correlation.RootCause == "" || len(batch.Correlation.Groups) != 1 {
+				t.Errorf("expected every check diagnosed and correlated, got %+v", batch)
+			}
+
+			var chunks []string
+			answer, usage, err := client.Stream(ctx, "what is wrong?", func(chunk string) error {
+				chunks = append(chunks, chunk)
+				return nil
+			})
+			if err != nil || answer != fixtures[scenario].answer || strings.Join(chunks, "") != answer || len(chunks) < 2 || !usage.Estimated {
+				t.Errorf("expected the fixture's answer streamed in sentences, got %q in %q, %v", answer, chunks, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sources of an investigation step
+const (
+	StepSourceAI       = "ai"
+	StepSourceTemplate = "template"
+)
+
+// maxStepOutput bounds the output captured from one investigation step
+const maxStepOutput = 64 << 10
+
+// placeholderPattern matches {pod}-style template placeholders and the
+// <pod-name>-style placeholders AI suggestions use
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z][A-Za-z_-]*)\}|<([A-Za-z][A-Za-z_-]*)>`)
+
+// placeholderAliases maps placeholder spellings to their canonical names
+var placeholderAliases = map[string]string{
+	"ns":         "namespace",
+	"pod_name":   "pod",
+	"podname":    "pod",
+	"node_name":  "node",
+	"nodename":   "node",
+	"svc":        "service",
+	"deploy":     "deployment",
+	"controller": "workload",
+}
+
+// InvestigationPlan is an ordered list of kubectl commands for looking into
+// a failing check, with placeholders resolved to the resources it implicates.
+// Running the plan executes its read-only steps and keeps their output.
+type InvestigationPlan struct {
+	ID        string              `json:"id"`
+	Check     string              `json:"check"`
+	Cluster   string              `json:"cluster,omitempty"`
+	Summary   string              `json:"summary,omitempty"`   // The AI diagnosis the plan follows up on
+	Templates []string            `json:"templates,omitempty"` // Templates the plan's standard steps came from
+	Steps     []InvestigationStep `json:"steps"`
+	CreatedAt time.Time           `json:"created_at"`
+	LastRun   time.Time           `json:"last_run,omitzero"`
+}
+
+// InvestigationStep is one command of an investigation plan
+type InvestigationStep struct {
+	Command    string    `json:"command"`
+	Purpose    string    `json:"purpose,omitempty"`
+	Source     string    `json:"source"`               // StepSourceAI or StepSourceTemplate
+	ReadOnly   bool      `json:"read_only"`            // Only read-only steps are run
+	Unresolved []string  `json:"unresolved,omitempty"` // Placeholders with no known value
+	Output     string    `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	RanAt      time.Time `json:"ran_at,omitzero"`
+}
+
+// Runnable reports whether running the plan executes the step
+func (s InvestigationStep) Runnable() bool {
+	return s.ReadOnly && len(s.Unresolved) == 0
+}
+
+// InvestigationTemplate is a standard set of commands for a kind of failure
+type InvestigationTemplate struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Keywords    []string                    `json:"keywords"` // Matched against the check's name, message and diagnosis
+	Steps       []InvestigationTemplateStep `json:"steps"`
+}
+
+// InvestigationTemplateStep is a template command; placeholders such as
+// {namespace}, {pod}, {node}, {service} and {workload} are resolved when a
+// plan is built
+type InvestigationTemplateStep struct {
+	Command string `json:"command"`
+	Purpose string `json:"purpose"`
+}
+
+// Matches reports whether the template applies to text describing a failure
+func (t InvestigationTemplate) Matches(text string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range t.Keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// genericTemplate is used when no other template matches
+var genericTemplate = InvestigationTemplate{
+	Name:        "generic",
+	Description: "Recent warnings across the cluster",
+	Steps: []InvestigationTemplateStep{
+		{Command: "kubectl get events -A --field-selector type=Warning --sort-by=.lastTimestamp", Purpose: "Recent warning events"},
+		{Command: "kubectl get nodes -o wide", Purpose: "Node status"},
+		{Command: "kubectl get pods -A --field-selector status.phase!=Running,status.phase!=Succeeded", Purpose: "Pods that aren't running"},
+	},
+}
+
+// DefaultInvestigationTemplates returns the built-in template library
+func DefaultInvestigationTemplates() []InvestigationTemplate {
+	return []InvestigationTemplate{
+		{
+			Name:        "crashloop",
+			Description: "Containers crashing or restarting",
+			Keywords:    []string{"crashloop", "backoff", "restart"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe pod {pod} -n {namespace}", Purpose: "Container states, exit codes and recent events"},
+				{Command: "kubectl logs {pod} -n {namespace} --all-containers --previous --tail=100", Purpose: "Logs from before the last crash"},
+				{Command: "kubectl get events -n {namespace} --field-selector involvedObject.name={pod}", Purpose: "Events for the pod"},
+			},
+		},
+		{
+			Name:        "oom",
+			Description: "Containers killed for exceeding memory limits",
+			Keywords:    []string{"oom", "memory"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe pod {pod} -n {namespace}", Purpose: "Memory limits and last termination reason"},
+				{Command: "kubectl top pod {pod} -n {namespace} --containers", Purpose: "Current memory use per container"},
+				{Command: "kubectl logs {pod} -n {namespace} --all-containers --previous --tail=50", Purpose: "Logs from before the kill"},
+			},
+		},
+		{
+			Name:        "image-pull",
+			Description: "Images that can't be pulled",
+			Keywords:    []string{"imagepull", "errimagepull", "image pull"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe pod {pod} -n {namespace}", Purpose: "Image names and pull errors"},
+				{Command: "kubectl get events -n {namespace} --field-selector involvedObject.name={pod}", Purpose: "Pull attempts for the pod"},
+			},
+		},
+		{
+			Name:        "scheduling",
+			Description: "Pods that can't be scheduled",
+			Keywords:    []string{"pending", "unschedulable", "failedscheduling", "insufficient"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe pod {pod} -n {namespace}", Purpose: "Scheduler messages for the pod"},
+				{Command: "kubectl top nodes", Purpose: "Node resource use"},
+				{Command: "kubectl describe resourcequota -n {namespace}", Purpose: "Quota left in the namespace"},
+			},
+		},
+		{
+			Name:        "node",
+			Description: "Nodes that are not ready or under pressure",
+			Keywords:    []string{"node", "notready", "pressure"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe node {node}", Purpose: "Node conditions, taints and allocated resources"},
+				{Command: "kubectl top node {node}", Purpose: "Node resource use"},
+				{Command: "kubectl get pods -A --field-selector spec.nodeName={node}", Purpose: "Pods on the node"},
+			},
+		},
+		{
+			Name:        "service",
+			Description: "Services without ready endpoints",
+			Keywords:    []string{"service", "endpoint"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe service {service} -n {namespace}", Purpose: "Selector and ports"},
+				{Command: "kubectl get endpoints {service} -n {namespace}", Purpose: "Ready and unready endpoints"},
+			},
+		},
+		{
+			Name:        "admission",
+			Description: "Pod creation rejected by a webhook or quota",
+			Keywords:    []string{"blocked", "webhook", "quota", "admission"},
+			Steps: []InvestigationTemplateStep{
+				{Command: "kubectl describe {workload} -n {namespace}", Purpose: "Controller events with the rejection"},
+				{Command: "kubectl describe resourcequota -n {namespace}", Purpose: "Quota used and left"},
+				{Command: "kubectl get validatingwebhookconfigurations,mutatingwebhookconfigurations", Purpose: "Admission webhooks"},
+			},
+		},
+	}
+}
+
+// BuildInvestigationPlan orders the AI's suggested kubectl commands ahead of
+// the steps of every matching template, dropping duplicates, and resolves
+// placeholders from values (namespace, pod, node, service, workload). The
+// generic template is used when no template matches.
+func BuildInvestigationPlan(check CheckResult, diagnosis *AnalysisResponse, suggestions []SuggestedAction, values map[string]string, templates []InvestigationTemplate) InvestigationPlan {
+	plan := InvestigationPlan{
+		Check:     check.Name,
+		Steps:     []InvestigationStep{},
+		CreatedAt: time.Now(),
+	}
+	text := check.Name + " " + check.Message
+	if diagnosis != nil {
+		plan.Summary = diagnosis.Summary
+		text += " " + diagnosis.Summary + " " + diagnosis.Diagnosis
+	}
+
+	seen := make(map[string]bool)
+	add := func(command, purpose, source string) {
+		step := resolveStep(command, values)
+		key := strings.Join(strings.Fields(step.Command), " ")
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		step.Purpose = purpose
+		step.Source = source
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	for _, suggestion := range suggestions {
+		if strings.HasPrefix(strings.TrimSpace(suggestion.Command), "kubectl ") {
+			purpose := suggestion.Title
+			if purpose == "" {
+				purpose = suggestion.Description
+			}
+			add(suggestion.Command, purpose, StepSourceAI)
+		}
+	}
+
+	var matched []InvestigationTemplate
+	for _, template := range templates {
+		if template.Matches(text) {
+			matched = append(matched, template)
+		}
+	}
+	if len(matched) == 0 {
+		matched = []InvestigationTemplate{genericTemplate}
+	}
+	for _, template := range matched {
+		plan.Templates = append(plan.Templates, template.Name)
+		for _, step := range template.Steps {
+			add(step.Command, step.Purpose, StepSourceTemplate)
+		}
+	}
+	return plan
+}
+
+// resolveStep substitutes known placeholder values into a command
+func resolveStep(command string, values map[string]string) InvestigationStep {
+	var unresolved []string
+	resolved := placeholderPattern.ReplaceAllStringFunc(strings.TrimSpace(command), func(match string) string {
+		name := canonicalPlaceholder(match[1 : len(match)-1])
+		if value := values[name]; value != "" {
+			return value
+		}
+		unresolved = append(unresolved, name)
+		return match
+	})
+	sort.Strings(unresolved)
+	return InvestigationStep{
+		Command:    resolved,
+		ReadOnly:   IsReadOnlyCommand(resolved),
+		Unresolved: compactStrings(unresolved),
+	}
+}
+
+// canonicalPlaceholder normalizes a placeholder name such as "pod-name" or
+// "NAMESPACE" to the key used for its value
+func canonicalPlaceholder(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	if alias, ok := placeholderAliases[name]; ok {
+		return alias
+	}
+	return strings.TrimSuffix(name, "_name")
+}
+
+// compactStrings removes adjacent duplicates from a sorted slice
+func compactStrings(values []string) []string {
+	var compacted []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			compacted = append(compacted, value)
+		}
+	}
+	return compacted
+}
+
+// readOnlyVerbs are kubectl verbs that only read cluster state
+var readOnlyVerbs = map[string]bool{
+	"get":           true,
+	"describe":      true,
+	"logs":          true,
+	"top":           true,
+	"explain":       true,
+	"events":        true,
+	"api-resources": true,
+	"api-versions":  true,
+	"version":       true,
+}
+
+// IsReadOnlyCommand reports whether a kubectl command only reads cluster
+// state and finishes on its own. Commands that watch or follow, and any
+// that touch secrets, are not read-only.
+func IsReadOnlyCommand(command string) bool {
+	parts := strings.Fields(command)
+	if len(parts) < 2 || parts[0] != "kubectl" {
+		return false
+	}
+	for _, arg := range parts[1:] {
+		switch {
+		case arg == "-w", arg == "--watch", arg == "--watch-only", arg == "-f", arg == "--follow",
+			strings.HasPrefix(arg, "--watch="), strings.HasPrefix(arg, "--follow="):
+			return false
+		case strings.Contains(strings.ToLower(arg), "secret"):
+			return false
+		}
+	}
+
+	verb := parts[1]
+	switch {
+	case readOnlyVerbs[verb]:
+		return true
+	case verb == "rollout" && len(parts) > 2:
+		return parts[2] == "status" || parts[2] == "history"
+	case verb == "auth" && len(parts) > 2:
+		return parts[2] == "can-i"
+	}
+	return false
+}
+
+// RunInvestigation executes a plan's runnable steps in order, recording
+// each step's output or error; the other steps are left untouched
+func RunInvestigation(ctx context.Context, plan *InvestigationPlan, executor CommandExecutor) {
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if !step.Runnable() {
+			continue
+		}
+		if ctx.Err() != nil {
+			step.Output = ""
+			step.Error = fmt.Sprintf("not run: %v", ctx.Err())
+			continue
+		}
+
+		output, err := executor.Execute(ctx, step.Command)
+		step.RanAt = time.Now()
+		step.Output = truncateOutput(output)
+		step.Error = ""
+		if err != nil {
+			step.Error = err.Error()
+		}
+	}
+	plan.LastRun = time.Now()
+}
+
+// truncateOutput bounds captured command output
+func truncateOutput(output string) string {
+	if len(output) <= maxStepOutput {
+		return output
+	}
+	return output[:maxStepOutput] + "\n... (truncated)"
+}
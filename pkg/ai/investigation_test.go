@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingExecutor records the commands it runs and fails those containing fail
+type recordingExecutor struct {
+	commands []string
+}
+
+func (r *recordingExecutor) Execute(ctx context.Context, command string) (string, error) {
+	r.commands = append(r.commands, command)
+	if strings.Contains(command, "fail") {
+		return "", errors.New("command failed: exit status 1")
+	}
+	return "output of " + command, nil
+}
+
+func (r *recordingExecutor) DryRun(ctx context.Context, command string) (string, error) {
+	return "", nil
+}
+
+func TestIsReadOnlyCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"kubectl get pods -n shop", true},
+		{"kubectl describe pod api-1 -n shop", true},
+		{"kubectl logs api-1 -n shop --previous", true},
+		{"kubectl top nodes", true},
+		{"kubectl rollout status deployment/api -n shop", true},
+		{"kubectl auth can-i list pods", true},
+		{"kubectl rollout restart deployment/api -n shop", false},
+		{"kubectl delete pod api-1 -n shop", false},
+		{"kubectl exec api-1 -- sh", false},
+		{"kubectl logs api-1 -f", false},
+		{"kubectl get pods -w", false},
+		{"kubectl get secret db -o yaml", false},
+		{"helm list", false},
+		{"kubectl", false},
+	}
+	for _, tt := range tests {
+		if got := IsReadOnlyCommand(tt.command); got != tt.want {
+			t.Errorf("IsReadOnlyCommand(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestBuildInvestigationPlan(t *testing.T) {
+	check := CheckResult{Name: "pod-health", Message: "1 pod in CrashLoopBackOff"}
+	diagnosis := &AnalysisResponse{Summary: "api crashes on startup"}
+	suggestions := []SuggestedAction{
+		{Title: "Previous logs", Command: "kubectl logs <pod-name> -n <namespace> --previous"},
+		{Title: "Restart", Command: "kubectl rollout restart deployment/api -n <namespace>"},
+		{Title: "Check the node", Command: "kubectl describe node <node-name>"},
+		{Title: "Duplicate of a template step", Command: "kubectl describe pod {pod}  -n {namespace}"},
+		{Title: "Not kubectl", Script: "./collect.sh"},
+	}
+	values := map[string]string{"namespace": "shop", "pod": "api-1"}
+
+	plan := BuildInvestigationPlan(check, diagnosis, suggestions, values, DefaultInvestigationTemplates())
+	if plan.Check != "pod-health" || plan.Summary != "api crashes on startup" {
+		t.Errorf("unexpected plan header %+v", plan)
+	}
+	if !reflect.DeepEqual(plan.Templates, []string{"crashloop"}) {
+		t.Errorf("expected the crashloop template, got %v", plan.Templates)
+	}
+
+	want := []struct {
+		command  string
+		source   string
+		runnable bool
+	}{
+		{"kubectl logs api-1 -n shop --previous", StepSourceAI, true},
+		{"kubectl rollout restart deployment/api -n shop", StepSourceAI, false},
+		{"kubectl describe node <node-name>", StepSourceAI, false},
+		{"kubectl describe pod api-1  -n shop", StepSourceAI, true},
+		{"kubectl logs api-1 -n shop --all-containers --previous --tail=100", StepSourceTemplate, true},
+		{"kubectl get events -n shop --field-selector involvedObject.name=api-1", StepSourceTemplate, true},
+	}
+	if len(plan.Steps) != len(want) {
+		t.Fatalf("expected %d steps, got %+v", len(want), plan.Steps)
+	}
+	for i, w := range want {
+		step := plan.Steps[i]
+		if step.Command != w.command || step.Source != w.source || step.Runnable() != w.runnable {
+			t.Errorf("step %d = %q from %s (runnable %v), want %q from %s (runnable %v)",
+				i+1, step.Command, step.Source, step.Runnable(), w.command, w.source, w.runnable)
+		}
+	}
+	if !reflect.DeepEqual(plan.Steps[2].Unresolved, []string{"node"}) {
+		t.Errorf("expected the node placeholder unresolved, got %v", plan.Steps[2].Unresolved)
+	}
+
+	generic := BuildInvestigationPlan(CheckResult{Name: "dns"}, nil, nil, nil, DefaultInvestigationTemplates())
+	if !reflect.DeepEqual(generic.Templates, []string{"generic"}) || len(generic.Steps) == 0 {
+		t.Errorf("expected the generic template when nothing matches, got %+v", generic)
+	}
+}
+
+func TestRunInvestigation(t *testing.T) {
+	plan := InvestigationPlan{Steps: []InvestigationStep{
+		{Command: "kubectl get pods -n shop", ReadOnly: true},
+		{Command: "kubectl rollout restart deployment/api", ReadOnly: false},
+		{Command: "kubectl describe node <node>", ReadOnly: true, Unresolved: []string{"node"}},
+		{Command: "kubectl get pods -n fail", ReadOnly: true},
+	}}
+	executor := &recordingExecutor{}
+
+	RunInvestigation(context.Background(), &plan, executor)
+
+	if !reflect.DeepEqual(executor.commands, []string{"kubectl get pods -n shop", "kubectl get pods -n fail"}) {
+		t.Errorf("expected only runnable steps executed, got %v", executor.commands)
+	}
+	if plan.Steps[0].Output != "output of kubectl get pods -n shop" || plan.Steps[0].RanAt.IsZero() {
+		t.Errorf("expected the output captured, got %+v", plan.Steps[0])
+	}
+	if !plan.Steps[1].RanAt.IsZero() || !plan.Steps[2].RanAt.IsZero() {
+		t.Error("expected manual and unresolved steps left untouched")
+	}
+	if plan.Steps[3].Error == "" {
+		t.Error("expected the failing step's error captured")
+	}
+	if plan.LastRun.IsZero() {
+		t.Error("expected the run time recorded")
+	}
+}
@@ -0,0 +1,280 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Providers a client can be configured with
+const (
+	ProviderClaudeCLI = "claude-cli" // The Claude CLI, the default
+	ProviderAnthropic = "anthropic"  // The Anthropic Messages API
+	ProviderOpenAI    = "openai"     // OpenAI and compatible chat completions APIs, such as vLLM or LM Studio
+	ProviderOllama    = "ollama"     // A local Ollama server, for air-gapped clusters
+)
+
+// Providers lists the providers a client can be configured with
+var Providers = []string{ProviderClaudeCLI, ProviderAnthropic, ProviderOpenAI, ProviderOllama}
+
+// Prompt is what a provider is asked
+type Prompt struct {
+	System string
+	User   string
+}
+
+// Provider answers prompts with a model. The client adds the prompts,
+// timeouts, circuit breaking and response parsing around it.
+type Provider interface {
+	// Analyze answers a prompt asking for an analysis as JSON
+	Analyze(ctx context.Context, prompt Prompt) (string, Usage, error)
+
+	// Query answers a prompt in free text
+	Query(ctx context.Context, prompt Prompt) (string, Usage, error)
+
+	// Stream answers like Query, passing the answer to onChunk as it is
+	// written. It stops when onChunk returns an error.
+	Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error)
+
+	// ModelInfo identifies the model answering
+	ModelInfo() ModelInfo
+}
+
+// NewProvider creates the provider config selects, the Claude CLI when
+// unset
+func NewProvider(config Config) (Provider, error) {
+	switch config.Provider {
+	case "", ProviderClaudeCLI:
+		return newCLIProvider(config), nil
+	case ProviderAnthropic:
+		return newAnthropicProvider(config)
+	case ProviderOpenAI:
+		return newOpenAIProvider(config)
+	case ProviderOllama:
+		return newOllamaProvider(config)
+	}
+	return nil, fmt.Errorf("unknown AI provider %q, expected one of %s", config.Provider, strings.Join(Providers, ", "))
+}
+
+// Endpoint returns the API address the configured provider calls, or
+// nothing for the CLI
+func (c Config) Endpoint() string {
+	defaults := map[string]string{
+		ProviderAnthropic: DefaultAnthropicURL,
+		ProviderOpenAI:    DefaultOpenAIURL,
+		ProviderOllama:    DefaultOllamaURL,
+	}
+	if _, ok := defaults[c.Provider]; !ok {
+		return ""
+	}
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return defaults[c.Provider]
+}
+
+// failedProvider stands in for a provider that couldn't be created, so
+// every call reports why
+type failedProvider struct {
+	err error
+}
+
+func (p failedProvider) Analyze(context.Context, Prompt) (string, Usage, error) {
+	return "", Usage{}, p.err
+}
+
+func (p failedProvider) Query(context.Context, Prompt) (string, Usage, error) {
+	return "", Usage{}, p.err
+}
+
+func (p failedProvider) Stream(context.Context, Prompt, func(string) error) (string, Usage, error) {
+	return "", Usage{}, p.err
+}
+
+func (p failedProvider) ModelInfo() ModelInfo {
+	return ModelInfo{Provider: "unknown", Model: "unknown", Version: "unknown"}
+}
+
+// cliProvider runs the Claude CLI
+type cliProvider struct {
+	path     string
+	model    string
+	readOnly bool
+	testMode bool
+	scenario string // Fixture test mode answers with unless a request asks for another
+
+	versionOnce sync.Once
+	version     string
+}
+
+func newCLIProvider(config Config) *cliProvider {
+	if config.ClaudePath == "" {
+		config.ClaudePath = "claude" // Assume claude is in PATH
+	}
+	return &cliProvider{
+		path:     config.ClaudePath,
+		model:    config.Model,
+		readOnly: config.ReadOnly,
+		testMode: config.TestMode,
+		scenario: config.TestScenario,
+	}
+}
+
+// ModelInfo returns the model and CLI version. The version is read once
+// from the CLI.
+func (p *cliProvider) ModelInfo() ModelInfo {
+	info := ModelInfo{Provider: ProviderAnthropic, Model: p.model}
+	if info.Model == "" {
+		info.Model = "default"
+	}
+	p.versionOnce.Do(func() { p.version = p.cliVersion() })
+	info.Version = p.version
+	return info
+}
+
+// cliVersion asks the Claude CLI for its version
+func (p *cliProvider) cliVersion() string {
+	if p.testMode {
+		return "test"
+	}
+	if err := p.validatePath(); err != nil {
+		return "unknown"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, p.path, "--version").Output() // #nosec G204 - path is validated above
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		klog.V(2).Infof("Failed to read the Claude CLI version: %v", err)
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Analyze runs the CLI, which is asked for JSON by the prompt itself
+func (p *cliProvider) Analyze(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	if p.testMode {
+		scenario := selectScenario(ctx, p.scenario, prompt.User)
+		klog.Infof("AI: Test mode enabled, returning the %s analysis fixture", scenario)
+		return parseCLIOutput(fixtureAnalysis(scenario, prompt.User), prompt.User)
+	}
+	return p.run(ctx, prompt)
+}
+
+// Query runs the CLI
+func (p *cliProvider) Query(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	if p.testMode {
+		scenario := selectScenario(ctx, p.scenario, prompt.User)
+		klog.Infof("AI: Test mode enabled, returning the %s answer fixture", scenario)
+		return parseCLIOutput(fixtureAnswer(scenario), prompt.User)
+	}
+	return p.run(ctx, prompt)
+}
+
+// Stream runs the CLI, which answers all at once. Test mode streams its
+// answer a sentence at a time, like the API providers do.
+func (p *cliProvider) Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error) {
+	if p.testMode {
+		answer, usage, _ := p.Query(ctx, prompt)
+		for _, chunk := range strings.SplitAfter(answer, ". ") {
+			if err := onChunk(chunk); err != nil {
+				return answer, usage, err
+			}
+		}
+		return answer, usage, nil
+	}
+	answer, usage, err := p.run(ctx, prompt)
+	if err != nil {
+		return "", usage, err
+	}
+	return answer, usage, onChunk(answer)
+}
+
+// permissionMode returns the CLI permission mode; read-only mode restricts
+// the CLI to planning so it cannot run commands against the cluster
+func (p *cliProvider) permissionMode() string {
+	if p.readOnly {
+		return "plan"
+	}
+	return "bypassPermissions"
+}
+
+// run executes the Claude Code CLI with the given prompt, returning its
+// answer and what it consumed
+func (p *cliProvider) run(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	// Validate Claude path for security
+	if err := p.validatePath(); err != nil {
+		return "", Usage{}, fmt.Errorf("invalid claude path: %w", err)
+	}
+
+	if len(prompt.User) > 100000 { // Reasonable limit
+		return "", Usage{}, fmt.Errorf("prompt too long: %d characters", len(prompt.User))
+	}
+
+	args := []string{
+		"-p", prompt.User,
+		"--max-turns", "1",
+		"--system-prompt", prompt.System,
+		"--permission-mode", p.permissionMode(),
+		"--output-format", "json",
+	}
+	if p.model != "" {
+		args = append(args, "--model", p.model)
+	}
+
+	// Use exec.Command with an argument slice so prompt content never reaches a shell parser.
+	// This is structurally injection-safe regardless of what kubectl output or K8s YAML the
+	// prompt contains (no sh -c, no shell quoting required).
+	cmd := exec.CommandContext(ctx, p.path, args...) // #nosec G204 - path is validated above
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// Ensure proper environment inheritance for Node.js/Claude CLI
+	cmd.Env = os.Environ() // Inherit full environment from parent process
+
+	klog.Infof("AI: Executing Claude CLI analysis (prompt length: %d chars)", len(prompt.User))
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			klog.Errorf("Claude CLI timed out")
+			return "", Usage{}, fmt.Errorf("claude CLI timed out: %w", ctx.Err())
+		}
+		klog.Errorf("Claude command failed: %v, stderr: %s, stdout: %s", err, stderr.String(), stdout.String())
+		return "", Usage{}, fmt.Errorf("claude command failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	klog.Infof("AI: Claude CLI completed successfully (output length: %d chars)", len(stdout.String()))
+
+	return parseCLIOutput(stdout.String(), prompt.User)
+}
+
+// validatePath ensures the Claude CLI path is safe to execute
+func (p *cliProvider) validatePath() error {
+	// Only allow known safe paths for Claude CLI
+	allowedPaths := []string{
+		"claude", // From PATH
+		"/usr/local/bin/claude",
+		"/opt/homebrew/bin/claude",
+	}
+
+	for _, allowed := range allowedPaths {
+		if p.path == allowed {
+			return nil
+		}
+	}
+
+	// Check if it's an absolute path pointing to a 'claude' binary
+	if strings.HasPrefix(p.path, "/") && strings.HasSuffix(p.path, "/claude") {
+		return nil
+	}
+
+	return fmt.Errorf("claude path not in allowlist: %s", p.path)
+}
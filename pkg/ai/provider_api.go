@@ -0,0 +1,516 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kubepulse/kubepulse/pkg/version"
+)
+
+// Default addresses of the API providers
+const (
+	DefaultAnthropicURL = "https://api.anthropic.com"
+	DefaultOpenAIURL    = "https://api.openai.com/v1"
+	DefaultOllamaURL    = "http://localhost:11434"
+)
+
+// defaultMaxTokens caps answers of the API providers when unset
+const defaultMaxTokens = 4096
+
+// anthropicVersion is the Messages API version requested
+const anthropicVersion = "2023-06-01"
+
+// apiProvider holds what the HTTP API providers share
+type apiProvider struct {
+	name      string // Reported as the ModelInfo provider
+	baseURL   string
+	apiKey    string
+	model     string
+	maxTokens int
+	client    *http.Client
+
+	mu      sync.Mutex
+	version string // Model version of the latest answer
+}
+
+func newAPIProvider(name, defaultURL string, config Config) (*apiProvider, error) {
+	if config.Model == "" {
+		return nil, fmt.Errorf("the %s AI provider needs a model", name)
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaultURL
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = defaultMaxTokens
+	}
+	// Requests end with the client's timeout through their context
+	return &apiProvider{
+		name:      name,
+		baseURL:   strings.TrimSuffix(config.BaseURL, "/"),
+		apiKey:    config.APIKey,
+		model:     config.Model,
+		maxTokens: config.MaxTokens,
+		client:    &http.Client{},
+	}, nil
+}
+
+// ModelInfo returns the configured model and the version the API last
+// answered with
+func (p *apiProvider) ModelInfo() ModelInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	info := ModelInfo{Provider: p.name, Model: p.model, Version: p.version}
+	if info.Version == "" {
+		info.Version = "unknown"
+	}
+	return info
+}
+
+// answeredBy records the model version the API reported answering with
+func (p *apiProvider) answeredBy(model string) {
+	if model == "" {
+		return
+	}
+	p.mu.Lock()
+	p.version = model
+	p.mu.Unlock()
+}
+
+// post sends a JSON request, failing on non-2xx answers
+func (p *apiProvider) post(ctx context.Context, path string, body interface{}, headers map[string]string) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kubepulse/"+version.Version)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", p.name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s: %s", p.name, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// decode reads a JSON answer
+func (p *apiProvider) decode(resp *http.Response, out interface{}) error {
+	defer func() { _ = resp.Body.Close() }()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s answer: %w", p.name, err)
+	}
+	return nil
+}
+
+// readLines passes each line of a streamed answer to fn: the data of
+// Server-Sent Events, or NDJSON lines when sse is false
+func readLines(body io.Reader, sse bool, fn func(line string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if sse {
+			data, ok := strings.CutPrefix(line, "data:")
+			if !ok {
+				continue
+			}
+			line = strings.TrimSpace(data)
+		}
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// apiUsage completes the tokens an API reported, estimating them from the
+// text when it reported none
+func apiUsage(inputTokens, outputTokens int, prompt Prompt, answer string) Usage {
+	if inputTokens == 0 && outputTokens == 0 {
+		return Usage{
+			InputTokens:  estimateTokens(prompt.System + prompt.User),
+			OutputTokens: estimateTokens(answer),
+			Estimated:    true,
+		}
+	}
+	return Usage{InputTokens: inputTokens, OutputTokens: outputTokens}
+}
+
+// chatMessage is a message of the chat APIs
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicProvider calls the Anthropic Messages API
+type anthropicProvider struct {
+	*apiProvider
+}
+
+func newAnthropicProvider(config Config) (*anthropicProvider, error) {
+	api, err := newAPIProvider(ProviderAnthropic, DefaultAnthropicURL, config)
+	if err != nil {
+		return nil, err
+	}
+	if api.apiKey == "" {
+		return nil, fmt.Errorf("the anthropic AI provider needs an API key")
+	}
+	return &anthropicProvider{apiProvider: api}, nil
+}
+
+// anthropicRequest is a Messages API request
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream,omitempty"`
+}
+
+// anthropicUsage is the usage the Messages API reports
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicResponse is a Messages API answer
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// anthropicEvent is an event of a streamed Messages API answer
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *anthropicProvider) send(ctx context.Context, prompt Prompt, stream bool) (*http.Response, error) {
+	return p.post(ctx, "/v1/messages", anthropicRequest{
+		Model:     p.model,
+		MaxTokens: p.maxTokens,
+		System:    prompt.System,
+		Messages:  []chatMessage{{Role: "user", Content: prompt.User}},
+		Stream:    stream,
+	}, map[string]string{"x-api-key": p.apiKey, "anthropic-version": anthropicVersion})
+}
+
+// Analyze asks the model; the prompt itself asks for JSON
+func (p *anthropicProvider) Analyze(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	return p.Query(ctx, prompt)
+}
+
+// Query asks the model
+func (p *anthropicProvider) Query(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, false)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	var answer anthropicResponse
+	if err := p.decode(resp, &answer); err != nil {
+		return "", Usage{}, err
+	}
+	var text strings.Builder
+	for _, block := range answer.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	p.answeredBy(answer.Model)
+	return text.String(), apiUsage(answer.Usage.InputTokens, answer.Usage.OutputTokens, prompt, text.String()), nil
+}
+
+// Stream asks the model, passing on text deltas as they arrive
+func (p *anthropicProvider) Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, true)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var text strings.Builder
+	var usage anthropicUsage
+	err = readLines(resp.Body, true, func(line string) error {
+		var event anthropicEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return fmt.Errorf("failed to decode anthropic event: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			p.answeredBy(event.Message.Model)
+			usage.InputTokens = event.Message.Usage.InputTokens
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				text.WriteString(event.Delta.Text)
+				return onChunk(event.Delta.Text)
+			}
+		case "message_delta":
+			usage.OutputTokens = event.Usage.OutputTokens
+		case "error":
+			return fmt.Errorf("anthropic stream failed: %s", event.Error.Message)
+		}
+		return nil
+	})
+	return text.String(), apiUsage(usage.InputTokens, usage.OutputTokens, prompt, text.String()), err
+}
+
+// openAIProvider calls an OpenAI-compatible chat completions API
+type openAIProvider struct {
+	*apiProvider
+}
+
+func newOpenAIProvider(config Config) (*openAIProvider, error) {
+	api, err := newAPIProvider(ProviderOpenAI, DefaultOpenAIURL, config)
+	if err != nil {
+		return nil, err
+	}
+	return &openAIProvider{apiProvider: api}, nil
+}
+
+// openAIRequest is a chat completions request
+type openAIRequest struct {
+	Model         string        `json:"model"`
+	MaxTokens     int           `json:"max_tokens"`
+	Messages      []chatMessage `json:"messages"`
+	Stream        bool          `json:"stream,omitempty"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options,omitempty"`
+}
+
+// openAIResponse is a chat completions answer, or a chunk of a streamed one
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+		Delta   chatMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (p *openAIProvider) send(ctx context.Context, prompt Prompt, stream bool) (*http.Response, error) {
+	request := openAIRequest{
+		Model:     p.model,
+		MaxTokens: p.maxTokens,
+		Messages:  chatMessages(prompt),
+		Stream:    stream,
+	}
+	if stream {
+		request.StreamOptions = &struct {
+			IncludeUsage bool `json:"include_usage"`
+		}{IncludeUsage: true}
+	}
+	headers := map[string]string{}
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+	return p.post(ctx, "/chat/completions", request, headers)
+}
+
+// Analyze asks the model; the prompt itself asks for JSON, as not every
+// compatible server supports a JSON response format
+func (p *openAIProvider) Analyze(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	return p.Query(ctx, prompt)
+}
+
+// Query asks the model
+func (p *openAIProvider) Query(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, false)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	var answer openAIResponse
+	if err := p.decode(resp, &answer); err != nil {
+		return "", Usage{}, err
+	}
+	if len(answer.Choices) == 0 {
+		return "", Usage{}, fmt.Errorf("openai answered without choices")
+	}
+	p.answeredBy(answer.Model)
+	text := answer.Choices[0].Message.Content
+	usage := apiUsage(0, 0, prompt, text)
+	if answer.Usage != nil {
+		usage = apiUsage(answer.Usage.PromptTokens, answer.Usage.CompletionTokens, prompt, text)
+	}
+	return text, usage, nil
+}
+
+// Stream asks the model, passing on content deltas as they arrive
+func (p *openAIProvider) Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, true)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var text strings.Builder
+	var inputTokens, outputTokens int
+	err = readLines(resp.Body, true, func(line string) error {
+		if line == "[DONE]" {
+			return nil
+		}
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("failed to decode openai chunk: %w", err)
+		}
+		p.answeredBy(chunk.Model)
+		if chunk.Usage != nil {
+			inputTokens, outputTokens = chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			return nil
+		}
+		text.WriteString(chunk.Choices[0].Delta.Content)
+		return onChunk(chunk.Choices[0].Delta.Content)
+	})
+	return text.String(), apiUsage(inputTokens, outputTokens, prompt, text.String()), err
+}
+
+// ollamaProvider calls the chat API of an Ollama server
+type ollamaProvider struct {
+	*apiProvider
+}
+
+func newOllamaProvider(config Config) (*ollamaProvider, error) {
+	api, err := newAPIProvider(ProviderOllama, DefaultOllamaURL, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ollamaProvider{apiProvider: api}, nil
+}
+
+// ollamaRequest is an Ollama chat request
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Format   string        `json:"format,omitempty"`
+	Options  struct {
+		NumPredict int `json:"num_predict"`
+	} `json:"options"`
+}
+
+// ollamaResponse is an Ollama chat answer, or a line of a streamed one
+type ollamaResponse struct {
+	Model           string      `json:"model"`
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
+
+func (p *ollamaProvider) send(ctx context.Context, prompt Prompt, stream bool, format string) (*http.Response, error) {
+	request := ollamaRequest{Model: p.model, Messages: chatMessages(prompt), Stream: stream, Format: format}
+	request.Options.NumPredict = p.maxTokens
+	headers := map[string]string{}
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+	return p.post(ctx, "/api/chat", request, headers)
+}
+
+// Analyze asks the model in JSON mode, which keeps small local models to
+// the answer format
+func (p *ollamaProvider) Analyze(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	return p.chat(ctx, prompt, "json")
+}
+
+// Query asks the model
+func (p *ollamaProvider) Query(ctx context.Context, prompt Prompt) (string, Usage, error) {
+	return p.chat(ctx, prompt, "")
+}
+
+func (p *ollamaProvider) chat(ctx context.Context, prompt Prompt, format string) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, false, format)
+	if err != nil {
+		return "", Usage{}, err
+	}
+	var answer ollamaResponse
+	if err := p.decode(resp, &answer); err != nil {
+		return "", Usage{}, err
+	}
+	if answer.Error != "" {
+		return "", Usage{}, fmt.Errorf("ollama failed: %s", answer.Error)
+	}
+	p.answeredBy(answer.Model)
+	return answer.Message.Content, apiUsage(answer.PromptEvalCount, answer.EvalCount, prompt, answer.Message.Content), nil
+}
+
+// Stream asks the model, passing on each line of the answer as it arrives
+func (p *ollamaProvider) Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error) {
+	resp, err := p.send(ctx, prompt, true, "")
+	if err != nil {
+		return "", Usage{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var text strings.Builder
+	var inputTokens, outputTokens int
+	err = readLines(resp.Body, false, func(line string) error {
+		var chunk ollamaResponse
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return fmt.Errorf("failed to decode ollama chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama failed: %s", chunk.Error)
+		}
+		p.answeredBy(chunk.Model)
+		if chunk.Done {
+			inputTokens, outputTokens = chunk.PromptEvalCount, chunk.EvalCount
+		}
+		if chunk.Message.Content == "" {
+			return nil
+		}
+		text.WriteString(chunk.Message.Content)
+		return onChunk(chunk.Message.Content)
+	})
+	return text.String(), apiUsage(inputTokens, outputTokens, prompt, text.String()), err
+}
+
+// chatMessages turns a prompt into system and user messages
+func chatMessages(prompt Prompt) []chatMessage {
+	messages := make([]chatMessage, 0, 2)
+	if prompt.System != "" {
+		messages = append(messages, chatMessage{Role: "system", Content: prompt.System})
+	}
+	return append(messages, chatMessage{Role: "user", Content: prompt.User})
+}
@@ -0,0 +1,238 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr string
+	}{
+		{"default", Config{}, ProviderAnthropic, ""},
+		{"claude cli", Config{Provider: ProviderClaudeCLI, Model: "opus"}, ProviderAnthropic, ""},
+		{"anthropic", Config{Provider: ProviderAnthropic, Model: "claude-sonnet-4-5", APIKey: "key"}, ProviderAnthropic, ""},
+		{"anthropic without key", Config{Provider: ProviderAnthropic, Model: "claude-sonnet-4-5"}, "", "needs an API key"},
+		{"openai without key", Config{Provider: ProviderOpenAI, Model: "qwen2.5", BaseURL: "http://vllm:8000/v1"}, ProviderOpenAI, ""},
+		{"ollama", Config{Provider: ProviderOllama, Model: "llama3.1"}, ProviderOllama, ""},
+		{"no model", Config{Provider: ProviderOllama}, "", "needs a model"},
+		{"unknown", Config{Provider: "bard"}, "", `unknown AI provider "bard"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider(tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, ok := provider.(*cliProvider); !ok {
+				if info := provider.ModelInfo(); info.Provider != tt.want || info.Model != tt.config.Model || info.Version != "unknown" {
+					t.Errorf("unexpected model info %+v", info)
+				}
+			}
+		})
+	}
+}
+
+// chatServer answers like a provider's API, recording the last request
+type chatServer struct {
+	path     string
+	answer   string // JSON answer
+	stream   []string
+	request  map[string]interface{}
+	headers  http.Header
+	streamed bool
+}
+
+func (s *chatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.path {
+		http.NotFound(w, r)
+		return
+	}
+	s.headers = r.Header.Clone()
+	s.request = nil
+	_ = json.NewDecoder(r.Body).Decode(&s.request)
+	if stream, _ := s.request["stream"].(bool); !stream {
+		_, _ = fmt.Fprint(w, s.answer)
+		return
+	}
+	s.streamed = true
+	for _, line := range s.stream {
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+func TestAPIProviders(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		server     chatServer
+		wantHeader [2]string
+		wantUsage  Usage
+		wantFormat string // Ollama format asked for by Analyze
+	}{
+		{
+			name:     "anthropic",
+			provider: ProviderAnthropic,
+			server: chatServer{
+				path:   "/v1/messages",
+				answer: `{"model":"claude-sonnet-4-5-20250929","content":[{"type":"text","text":"Disk "},{"type":"text","text":"full"}],"usage":{"input_tokens":12,"output_tokens":3}}`,
+				stream: []string{
+					`event: message_start`,
+					`data: {"type":"message_start","message":{"model":"claude-sonnet-4-5-20250929","usage":{"input_tokens":12}}}`,
+					`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"Disk "}}`,
+					`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"full"}}`,
+					`data: {"type":"message_delta","usage":{"output_tokens":3}}`,
+					`data: {"type":"message_stop"}`,
+				},
+			},
+			wantHeader: [2]string{"X-Api-Key", "secret"},
+			wantUsage:  Usage{InputTokens: 12, OutputTokens: 3},
+		},
+		{
+			name:     "openai",
+			provider: ProviderOpenAI,
+			server: chatServer{
+				path:   "/chat/completions",
+				answer: `{"model":"gpt-4o-2024-08-06","choices":[{"message":{"role":"assistant","content":"Disk full"}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`,
+				stream: []string{
+					`data: {"model":"gpt-4o-2024-08-06","choices":[{"delta":{"content":"Disk "}}]}`,
+					`data: {"model":"gpt-4o-2024-08-06","choices":[{"delta":{"content":"full"}}]}`,
+					`data: {"model":"gpt-4o-2024-08-06","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3}}`,
+					`data: [DONE]`,
+				},
+			},
+			wantHeader: [2]string{"Authorization", "Bearer secret"},
+			wantUsage:  Usage{InputTokens: 12, OutputTokens: 3},
+		},
+		{
+			name:     "ollama",
+			provider: ProviderOllama,
+			server: chatServer{
+				path:   "/api/chat",
+				answer: `{"model":"llama3.1:8b","message":{"role":"assistant","content":"Disk full"},"done":true}`,
+				stream: []string{
+					`{"model":"llama3.1:8b","message":{"role":"assistant","content":"Disk "},"done":false}`,
+					`{"model":"llama3.1:8b","message":{"role":"assistant","content":"full"},"done":false}`,
+					`{"model":"llama3.1:8b","message":{"role":"assistant","content":""},"done":true,"prompt_eval_count":12,"eval_count":3}`,
+				},
+			},
+			wantHeader: [2]string{"Authorization", "Bearer secret"},
+			wantFormat: "json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(&tt.server)
+			defer server.Close()
+			provider, err := NewProvider(Config{Provider: tt.provider, Model: "test-model", BaseURL: server.URL + "/", APIKey: "secret"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			prompt := Prompt{System: "You are an SRE", User: "Why is etcd down?"}
+
+			answer, usage, err := provider.Analyze(context.Background(), prompt)
+			if err != nil || answer != "Disk full" {
+				t.Fatalf("expected the answer, got %q, %v", answer, err)
+			}
+			if tt.wantUsage != (Usage{}) && usage != tt.wantUsage {
+				t.Errorf("expected usage %+v, got %+v", tt.wantUsage, usage)
+			}
+			if tt.wantUsage == (Usage{}) && !usage.Estimated {
+				t.Errorf("expected usage estimated when the API reports none, got %+v", usage)
+			}
+			if got := tt.server.headers.Get(tt.wantHeader[0]); got != tt.wantHeader[1] {
+				t.Errorf("expected %s %q, got %q", tt.wantHeader[0], tt.wantHeader[1], got)
+			}
+			if format, _ := tt.server.request["format"].(string); format != tt.wantFormat {
+				t.Errorf("expected format %q, got %q", tt.wantFormat, format)
+			}
+			if model, _ := tt.server.request["model"].(string); model != "test-model" {
+				t.Errorf("expected the configured model asked for, got %q", model)
+			}
+			if info := provider.ModelInfo(); info.Version == "unknown" || info.Version == "" {
+				t.Errorf("expected the version the API answered with, got %+v", info)
+			}
+
+			var chunks []string
+			answer, usage, err = provider.Stream(context.Background(), prompt, func(chunk string) error {
+				chunks = append(chunks, chunk)
+				return nil
+			})
+			if err != nil || answer != "Disk full" || len(chunks) != 2 || !tt.server.streamed {
+				t.Fatalf("expected the answer streamed in two chunks, got %q in %q, %v", answer, chunks, err)
+			}
+			if usage.InputTokens != 12 || usage.OutputTokens != 3 || usage.Estimated {
+				t.Errorf("expected the streamed usage, got %+v", usage)
+			}
+		})
+	}
+}
+
+func TestAPIProvider_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{Provider: ProviderOllama, Model: "missing", BaseURL: server.URL})
+	_, err := client.Analyze(context.Background(), AnalysisRequest{Type: AnalysisTypeDiagnostic})
+	if err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("expected the API's error, got %v", err)
+	}
+
+	broken := NewClient(Config{Provider: "bard"})
+	if _, _, err := broken.Query(context.Background(), "hello"); err == nil || !strings.Contains(err.Error(), "unknown AI provider") {
+		t.Errorf("expected every call to fail with the configuration error, got %v", err)
+	}
+}
+
+// echoProvider is a provider outside the package's own
+type echoProvider struct{}
+
+func (echoProvider) Analyze(_ context.Context, prompt Prompt) (string, Usage, error) {
+	return `{"summary":"echo","diagnosis":"echo","confidence":0.5,"severity":"low"}`, Usage{}, nil
+}
+
+func (echoProvider) Query(_ context.Context, prompt Prompt) (string, Usage, error) {
+	return prompt.User, Usage{}, nil
+}
+
+func (p echoProvider) Stream(ctx context.Context, prompt Prompt, onChunk func(string) error) (string, Usage, error) {
+	return prompt.User, Usage{}, onChunk(prompt.User)
+}
+
+func (echoProvider) ModelInfo() ModelInfo {
+	return ModelInfo{Provider: "echo", Model: "echo", Version: "1"}
+}
+
+func TestNewClientWithProvider(t *testing.T) {
+	client := NewClientWithProvider(echoProvider{}, Config{})
+	response, err := client.Analyze(context.Background(), AnalysisRequest{Type: AnalysisTypeDiagnostic})
+	if err != nil || response.Summary != "echo" {
+		t.Fatalf("expected the provider's analysis, got %+v, %v", response, err)
+	}
+	if answer, _, err := client.Query(context.Background(), "ping"); err != nil || answer != "ping" {
+		t.Errorf("expected the provider's answer, got %q, %v", answer, err)
+	}
+	stopped := fmt.Errorf("stop")
+	if _, _, err := client.Stream(context.Background(), "ping", func(string) error { return stopped }); err == nil {
+		t.Error("expected the stream stopped by its callback")
+	}
+	if info := client.ModelInfo(); info.Provider != "echo" {
+		t.Errorf("expected the provider's model info, got %+v", info)
+	}
+}
@@ -3,7 +3,9 @@ package ai
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -29,12 +31,47 @@ type SafetyChecker interface {
 	ValidateCommand(command string) error
 }
 
-// RemediationHistory tracks remediation actions
+// RemediationHistory tracks remediation actions, keeping the latest
+// maxHistory. Dry runs need no approval, so records are added concurrently.
 type RemediationHistory struct {
+	mu         sync.Mutex
 	actions    []RemediationRecord
 	maxHistory int
 }
 
+// add records a remediation attempt, dropping the oldest past maxHistory
+func (h *RemediationHistory) add(record RemediationRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.actions = append(h.actions, record)
+	if h.maxHistory > 0 && len(h.actions) > h.maxHistory {
+		h.actions = append([]RemediationRecord(nil), h.actions[len(h.actions)-h.maxHistory:]...)
+	}
+}
+
+// recent returns a copy of the latest limit records
+func (h *RemediationHistory) recent(limit int) []RemediationRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	start := len(h.actions) - limit
+	if start < 0 {
+		start = 0
+	}
+	return append([]RemediationRecord(nil), h.actions[start:]...)
+}
+
+// find returns a copy of the record with the ID, or nil
+func (h *RemediationHistory) find(id string) *RemediationRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, record := range h.actions {
+		if record.ID == id {
+			return &record
+		}
+	}
+	return nil
+}
+
 // RemediationRecord represents a remediation attempt
 type RemediationRecord struct {
 	ID          string
@@ -43,7 +80,25 @@ type RemediationRecord struct {
 	Action      RemediationAction
 	Result      string
 	Success     bool
-	RollbackCmd string
+	RollbackCmd string // Rollback the AI suggested with the action
+	DryRun      bool
+	ApprovedBy  string // Who approved the action before it ran
+
+	// Steps are the commands that ran, with their output
+	Steps []RemediationStep
+	// RollbackCommands undo the commands that ran, in the order to run
+	// them, from the state read just before each command
+	RollbackCommands []string
+}
+
+// RemediationStep is a remediation command and its output
+type RemediationStep struct {
+	Command string
+	Output  string
+	Error   string
+	// ManualRollback is set for commands with no safe automatic undo, such
+	// as kubectl apply, which may have changed objects that already existed
+	ManualRollback bool
 }
 
 // RemediationAction represents an AI-suggested remediation
@@ -57,6 +112,10 @@ type RemediationAction struct {
 	Impact           string    `json:"impact"`
 	Rollback         string    `json:"rollback_command"`
 	RequiresApproval bool      `json:"requires_approval"`
+
+	// Simulation is the predicted effect of a complex action, so approvers
+	// can see it before it runs
+	Simulation *RemediationSimulation `json:"simulation,omitempty"`
 }
 
 // RiskLevel represents the risk of a remediation action
@@ -134,10 +193,11 @@ Format as JSON array.`,
 	// Parse remediation actions
 	actions := r.parseRemediationActions(response)
 
-	// Validate safety of each action
+	// Validate safety of each action and simulate the complex ones
 	validatedActions := []RemediationAction{}
 	for _, action := range actions {
 		if safe, reason := r.safetyCheck.IsSafe(&action); safe {
+			r.simulate(ctx, &action)
 			validatedActions = append(validatedActions, action)
 		} else {
 			klog.Warningf("Rejected unsafe remediation: %s, reason: %s", action.Description, reason)
@@ -147,53 +207,77 @@ Format as JSON array.`,
 	return validatedActions, nil
 }
 
-// ExecuteRemediation executes a remediation action
+// ExecuteRemediation executes a remediation action, capturing each
+// command's output and a rollback command for it. When a command fails
+// the commands that already ran are rolled back.
 func (r *RemediationEngine) ExecuteRemediation(ctx context.Context, action RemediationAction, dryRun bool) (*RemediationRecord, error) {
 	record := RemediationRecord{
-		ID:        fmt.Sprintf("rem-%d", time.Now().Unix()),
-		Timestamp: time.Now(),
-		Action:    action,
+		ID:          fmt.Sprintf("rem-%d", time.Now().UnixNano()),
+		Timestamp:   time.Now(),
+		Action:      action,
+		RollbackCmd: action.Rollback,
+		DryRun:      dryRun,
 	}
 
 	// Safety validation
 	if err := r.validateAction(action); err != nil {
 		record.Success = false
 		record.Result = fmt.Sprintf("Validation failed: %v", err)
-		r.history.actions = append(r.history.actions, record)
+		r.history.add(record)
 		return &record, err
 	}
 
+	// Refuse complex actions the API server is predicted to reject
+	if !dryRun && r.simulate(ctx, &action) {
+		record.Action = action
+		if action.Simulation.Outcome == SimulationRejected {
+			record.Success = false
+			record.Result = "Simulation rejected: the API server refused the change in a dry run"
+			r.history.add(record)
+			return &record, fmt.Errorf("remediation %s rejected in simulation", action.ID)
+		}
+	}
+
 	// Execute commands
 	results := []string{}
 	for _, cmd := range action.Commands {
+		rollback := r.rollbackCommand(ctx, cmd)
+
 		var result string
 		var err error
-
 		if dryRun {
 			result, err = r.executor.DryRun(ctx, cmd)
 		} else {
 			result, err = r.executor.Execute(ctx, cmd)
 		}
+		step := RemediationStep{Command: cmd, Output: result, ManualRollback: needsManualRollback(cmd)}
 
 		if err != nil {
+			step.Error = err.Error()
+			record.Steps = append(record.Steps, step)
 			record.Success = false
 			record.Result = fmt.Sprintf("Command failed: %s, error: %v", cmd, err)
 
 			// Attempt rollback if not in dry-run
-			if !dryRun && record.RollbackCmd != "" {
-				r.attemptRollback(ctx, record.RollbackCmd)
+			if !dryRun {
+				r.attemptRollback(ctx, record.RollbackCommands)
 			}
 
-			r.history.actions = append(r.history.actions, record)
+			r.history.add(record)
 			return &record, err
 		}
 
+		record.Steps = append(record.Steps, step)
+		if rollback != "" {
+			// Later commands are undone first
+			record.RollbackCommands = append([]string{rollback}, record.RollbackCommands...)
+		}
 		results = append(results, result)
 	}
 
 	record.Success = true
 	record.Result = strings.Join(results, "\n")
-	r.history.actions = append(r.history.actions, record)
+	r.history.add(record)
 
 	klog.Infof("Successfully executed remediation: %s", action.Description)
 
@@ -290,7 +374,7 @@ func (r *RemediationEngine) parseRemediationActions(response *AnalysisResponse)
 
 	for i, suggestedAction := range response.Actions {
 		action := RemediationAction{
-			ID:               fmt.Sprintf("action-%d-%d", time.Now().Unix(), i),
+			ID:               fmt.Sprintf("action-%d-%d", time.Now().UnixNano(), i),
 			Type:             string(suggestedAction.Type),
 			Description:      suggestedAction.Description,
 			Commands:         r.extractCommands(suggestedAction),
@@ -357,13 +441,25 @@ func (r *RemediationEngine) generateRollback(action RemediationAction) string {
 		if strings.Contains(cmd, "set image") {
 			return strings.Replace(cmd, "=", "=<previous>", 1)
 		}
-		if strings.Contains(cmd, "apply") {
-			return strings.Replace(cmd, "apply", "delete", 1)
-		}
 	}
 	return ""
 }
 
+// simulate attaches a server-side dry-run simulation to a complex action
+// that has none, when the executor can dry-run on the server, and reports
+// whether the action has a simulation
+func (r *RemediationEngine) simulate(ctx context.Context, action *RemediationAction) bool {
+	if action.Simulation != nil {
+		return true
+	}
+	runner, ok := r.executor.(ServerDryRunner)
+	if !ok || !NeedsSimulation(*action) {
+		return false
+	}
+	action.Simulation = SimulateRemediation(ctx, *action, runner, r.executor)
+	return true
+}
+
 func (r *RemediationEngine) validateAction(action RemediationAction) error {
 	// Validate commands
 	for _, cmd := range action.Commands {
@@ -380,13 +476,84 @@ func (r *RemediationEngine) validateAction(action RemediationAction) error {
 	return nil
 }
 
-func (r *RemediationEngine) attemptRollback(ctx context.Context, rollbackCmd string) {
-	klog.Warningf("Attempting rollback: %s", rollbackCmd)
-	if _, err := r.executor.Execute(ctx, rollbackCmd); err != nil {
-		klog.Errorf("Rollback failed: %v", err)
+func (r *RemediationEngine) attemptRollback(ctx context.Context, rollbackCmds []string) {
+	for _, rollbackCmd := range rollbackCmds {
+		klog.Warningf("Attempting rollback: %s", rollbackCmd)
+		if _, err := r.executor.Execute(ctx, rollbackCmd); err != nil {
+			klog.Errorf("Rollback failed: %v", err)
+		}
 	}
 }
 
+// rollbackCommand returns a command undoing a remediation command, reading
+// the state the command changes from the cluster first. Commands it can't
+// undo, such as restarts, have none, and neither has kubectl apply: deleting
+// what was applied would also remove objects that existed before.
+func (r *RemediationEngine) rollbackCommand(ctx context.Context, command string) string {
+	fields := strings.Fields(command)
+	if len(fields) < 3 || fields[0] != "kubectl" {
+		return ""
+	}
+	targets, scope := commandTargets(fields[2:])
+
+	switch fields[1] {
+	case "scale":
+		if len(targets) == 0 {
+			return ""
+		}
+		target := strings.Join(targets, " ")
+		replicas, err := r.executor.Execute(WithToolRefresh(ctx), "kubectl get "+target+scope+" -o jsonpath={.spec.replicas}")
+		if err != nil {
+			klog.V(2).Infof("No rollback for %q: %v", command, err)
+			return ""
+		}
+		if _, err := strconv.Atoi(strings.TrimSpace(replicas)); err != nil {
+			return ""
+		}
+		return "kubectl scale " + target + " --replicas=" + strings.TrimSpace(replicas) + scope
+	case "set":
+		// The new image or resources roll out a revision, undone by going back
+		if len(targets) < 2 {
+			return ""
+		}
+		return "kubectl rollout undo " + strings.Join(targets[1:], " ") + scope
+	}
+	return ""
+}
+
+// needsManualRollback reports whether a command changes the cluster in a
+// way only an operator can undo
+func needsManualRollback(command string) bool {
+	fields := strings.Fields(command)
+	return len(fields) >= 2 && fields[0] == "kubectl" && fields[1] == "apply"
+}
+
+// commandTargets splits kubectl arguments after the verb into the resources
+// they name, leaving out assignments such as container=image, and the
+// namespace flags to repeat
+func commandTargets(args []string) ([]string, string) {
+	var targets []string
+	scope := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-n" || arg == "--namespace":
+			if i+1 < len(args) {
+				scope += " " + arg + " " + args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--namespace="), strings.HasPrefix(arg, "-n") && len(arg) > 2:
+			scope += " " + arg
+		case arg == "-l" || arg == "-c" || arg == "-f" || arg == "--selector" || arg == "--container" || arg == "--filename":
+			i++ // Drop the value too
+		case strings.HasPrefix(arg, "-"), strings.Contains(arg, "="):
+		default:
+			targets = append(targets, arg)
+		}
+	}
+	return targets, scope
+}
+
 func (r *RemediationEngine) analyzeFailurePatterns(health *ClusterHealth) map[string]int {
 	patterns := make(map[string]int)
 
@@ -411,18 +578,9 @@ func (r *RemediationEngine) analyzeFailurePatterns(health *ClusterHealth) map[st
 }
 
 func (r *RemediationEngine) getRecentHistory(limit int) []RemediationRecord {
-	start := len(r.history.actions) - limit
-	if start < 0 {
-		start = 0
-	}
-	return r.history.actions[start:]
+	return r.history.recent(limit)
 }
 
 func (r *RemediationEngine) findRecord(id string) *RemediationRecord {
-	for _, record := range r.history.actions {
-		if record.ID == id {
-			return &record
-		}
-	}
-	return nil
+	return r.history.find(id)
 }
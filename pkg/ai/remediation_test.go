@@ -0,0 +1,36 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRemediationEngine_History(t *testing.T) {
+	engine := NewRemediationEngine(nil, &recordingExecutor{}, NewDefaultSafetyChecker())
+	engine.history.maxHistory = 5
+
+	// Dry runs need no approval, so callers run them concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			action := RemediationAction{ID: fmt.Sprintf("restart-%d", i), Commands: []string{"kubectl rollout restart deployment/web -n shop"}}
+			if _, err := engine.ExecuteRemediation(context.Background(), action, true); err != nil {
+				t.Errorf("ExecuteRemediation() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	recent := engine.getRecentHistory(100)
+	if len(recent) != 5 {
+		t.Fatalf("expected the history trimmed to 5 records, got %d", len(recent))
+	}
+	recent[0].Result = "changed"
+	if record := engine.findRecord(recent[0].ID); record == nil || record.Result == "changed" {
+		t.Errorf("expected records returned as copies, got %+v", record)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
 )
 
 // StructuredResponse represents a more reliable AI response format
@@ -16,6 +18,7 @@ type StructuredResponse struct {
 	Severity        SeverityLevel          `json:"severity"`
 	Recommendations []Recommendation       `json:"recommendations"`
 	Actions         []SuggestedAction      `json:"actions"`
+	Findings        []findings.Finding     `json:"findings"`
 	Context         map[string]interface{} `json:"context"`
 }
 
@@ -55,6 +58,7 @@ func (p *ResponseParser) ParseResponse(text string, request AnalysisRequest) (*A
 		Severity:        p.extractSeverity(text),
 		Recommendations: p.extractRecommendations(text),
 		Actions:         p.extractActions(text),
+		Findings:        extractFindings(text),
 		Context:         make(map[string]interface{}),
 	}
 
@@ -98,8 +102,9 @@ func (p *ResponseParser) convertStructuredResponse(sr *StructuredResponse) *Anal
 		Diagnosis:       sr.Diagnosis,
 		Confidence:      sr.Confidence,
 		Severity:        sr.Severity,
-		Recommendations: sr.Recommendations,
+		Recommendations: normalizeRecommendationFindings(sr.Recommendations),
 		Actions:         sr.Actions,
+		Findings:        findings.Normalize(sr.Findings),
 		Context:         sr.Context,
 	}
 }
@@ -0,0 +1,245 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSimulatedChanges caps the field changes reported per object
+const maxSimulatedChanges = 50
+
+// ServerDryRunner runs a command with server-side dry-run, so validation,
+// defaulting and admission apply without anything being persisted
+type ServerDryRunner interface {
+	ServerDryRun(ctx context.Context, command string) (string, error)
+}
+
+// SimulationOutcome is how the API server answered a simulated command
+type SimulationOutcome string
+
+const (
+	SimulationAccepted SimulationOutcome = "accepted" // Validation and admission accepted the change
+	SimulationRejected SimulationOutcome = "rejected" // Validation, quota or an admission controller refused it
+	SimulationFailed   SimulationOutcome = "failed"   // It couldn't be simulated, e.g. an unknown resource
+	SimulationSkipped  SimulationOutcome = "skipped"  // It only reads, so there is nothing to simulate
+)
+
+// RemediationSimulation is the predicted effect of a remediation: each
+// command applied with server-side dry-run, in order. Commands are
+// simulated independently, so a later command doesn't see an earlier one's
+// change.
+type RemediationSimulation struct {
+	Outcome     SimulationOutcome `json:"outcome"` // Rejected if any command was, then failed, then accepted
+	Steps       []SimulationStep  `json:"steps"`
+	SimulatedAt time.Time         `json:"simulated_at"`
+}
+
+// SimulationStep is the simulated outcome of one command
+type SimulationStep struct {
+	Command string            `json:"command"`
+	Outcome SimulationOutcome `json:"outcome"`
+	Objects []SimulatedObject `json:"objects,omitempty"`
+	Message string            `json:"message,omitempty"` // The API server's answer when no objects were returned
+}
+
+// SimulatedObject is an object as the simulated command would leave it,
+// compared with its current state
+type SimulatedObject struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Created   bool          `json:"created,omitempty"` // It doesn't exist yet
+	Changes   []FieldChange `json:"changes,omitempty"`
+	Truncated bool          `json:"truncated,omitempty"` // More changes than are listed
+}
+
+// FieldChange is a spec, label or annotation field the command would change
+type FieldChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// NeedsSimulation reports whether an action is complex enough to simulate
+// before running: it changes the cluster and runs several commands, isn't
+// low risk or requires approval
+func NeedsSimulation(action RemediationAction) bool {
+	mutating := false
+	for _, command := range action.Commands {
+		if strings.TrimSpace(command) != "" && !IsReadOnlyCommand(command) {
+			mutating = true
+		}
+	}
+	return mutating && (len(action.Commands) > 1 || action.Risk != RiskLow || action.RequiresApproval)
+}
+
+// SimulateRemediation runs each command of an action with server-side
+// dry-run and compares the resulting objects with their current state,
+// which is read through reader
+func SimulateRemediation(ctx context.Context, action RemediationAction, runner ServerDryRunner, reader CommandExecutor) *RemediationSimulation {
+	simulation := &RemediationSimulation{Outcome: SimulationAccepted, SimulatedAt: time.Now()}
+	for _, command := range action.Commands {
+		if strings.TrimSpace(command) == "" {
+			continue
+		}
+		step := simulateCommand(ctx, command, runner, reader)
+		switch {
+		case step.Outcome == SimulationRejected:
+			simulation.Outcome = SimulationRejected
+		case step.Outcome == SimulationFailed && simulation.Outcome != SimulationRejected:
+			simulation.Outcome = SimulationFailed
+		}
+		simulation.Steps = append(simulation.Steps, step)
+	}
+	return simulation
+}
+
+// simulateCommand dry-runs one command
+func simulateCommand(ctx context.Context, command string, runner ServerDryRunner, reader CommandExecutor) SimulationStep {
+	step := SimulationStep{Command: command}
+	if IsReadOnlyCommand(command) {
+		step.Outcome = SimulationSkipped
+		return step
+	}
+
+	output, err := runner.ServerDryRun(ctx, command)
+	if err != nil {
+		step.Outcome = SimulationFailed
+		if isAdmissionRejection(output + " " + err.Error()) {
+			step.Outcome = SimulationRejected
+		}
+		step.Message = truncateOutput(strings.TrimSpace(output))
+		if step.Message == "" {
+			step.Message = err.Error()
+		}
+		return step
+	}
+
+	step.Outcome = SimulationAccepted
+	objects, ok := parseObjects(output)
+	if !ok {
+		step.Message = truncateOutput(strings.TrimSpace(output))
+		return step
+	}
+	for _, object := range objects {
+		step.Objects = append(step.Objects, compareObject(ctx, object, reader))
+	}
+	return step
+}
+
+// isAdmissionRejection reports whether the API server refused a change, as
+// opposed to the command failing to reach it
+func isAdmissionRejection(output string) bool {
+	output = strings.ToLower(output)
+	for _, marker := range []string{"admission webhook", "denied the request", "forbidden", "is invalid", "exceeded quota", "violates podsecurity"} {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseObjects decodes the objects of kubectl -o json output, unwrapping lists
+func parseObjects(output string) ([]map[string]interface{}, bool) {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &object); err != nil {
+		return nil, false
+	}
+	items, isList := object["items"].([]interface{})
+	if !isList {
+		return []map[string]interface{}{object}, true
+	}
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if object, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects, true
+}
+
+// compareObject lists the fields a simulated object changes from the
+// current one; an object that isn't found is reported as created
+func compareObject(ctx context.Context, simulated map[string]interface{}, reader CommandExecutor) SimulatedObject {
+	kind, _ := simulated["kind"].(string)
+	apiVersion, _ := simulated["apiVersion"].(string)
+	metadata, _ := simulated["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	namespace, _ := metadata["namespace"].(string)
+	object := SimulatedObject{Kind: kind, Namespace: namespace, Name: name}
+
+	resource := strings.ToLower(kind)
+	if group, _, found := strings.Cut(apiVersion, "/"); found {
+		resource += "." + group
+	}
+	get := fmt.Sprintf("kubectl get %s %s -o json", resource, name)
+	if namespace != "" {
+		get += " -n " + namespace
+	}
+	output, err := reader.Execute(ctx, get)
+	if err != nil {
+		object.Created = strings.Contains(strings.ToLower(output+" "+err.Error()), "not found")
+		return object
+	}
+	current, ok := parseObjects(output)
+	if !ok || len(current) != 1 {
+		return object
+	}
+
+	before, after := objectFields(current[0]), objectFields(simulated)
+	paths := make(map[string]bool, len(after))
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		if before[path] != after[path] {
+			sorted = append(sorted, path)
+		}
+	}
+	sort.Strings(sorted)
+	if len(sorted) > maxSimulatedChanges {
+		sorted, object.Truncated = sorted[:maxSimulatedChanges], true
+	}
+	for _, path := range sorted {
+		object.Changes = append(object.Changes, FieldChange{Path: path, Before: before[path], After: after[path]})
+	}
+	return object
+}
+
+// objectFields flattens the spec, labels and annotations of an object into
+// leaf values by path, leaving out kubectl's last-applied configuration
+func objectFields(object map[string]interface{}) map[string]string {
+	fields := make(map[string]string)
+	flattenFields("spec", object["spec"], fields)
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		flattenFields("metadata.labels", metadata["labels"], fields)
+		flattenFields("metadata.annotations", metadata["annotations"], fields)
+	}
+	delete(fields, "metadata.annotations.kubectl.kubernetes.io/last-applied-configuration")
+	return fields
+}
+
+// flattenFields records value's leaves under path
+func flattenFields(path string, value interface{}, fields map[string]string) {
+	switch value := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, child := range value {
+			flattenFields(path+"."+key, child, fields)
+		}
+	case []interface{}:
+		for i, child := range value {
+			flattenFields(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	default:
+		fields[path] = fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const currentDeployment = `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"shop","labels":{"app":"web"}},"spec":{"replicas":2,"template":{"spec":{"containers":[{"name":"web","image":"web:1.0"}]}}}}`
+
+// simulatingExecutor answers server dry-runs with canned responses keyed by
+// a substring of the command, and reads the current deployment
+type simulatingExecutor struct {
+	recordingExecutor
+	dryRuns   map[string]string // Output by command substring
+	rejected  string            // Commands containing this are refused by admission
+	simulated []string
+}
+
+func (s *simulatingExecutor) Execute(ctx context.Context, command string) (string, error) {
+	s.commands = append(s.commands, command)
+	if strings.HasPrefix(command, "kubectl get deployment.apps web") {
+		return currentDeployment, nil
+	}
+	return `Error from server (NotFound): not found`, errors.New("command failed: exit status 1")
+}
+
+func (s *simulatingExecutor) ServerDryRun(ctx context.Context, command string) (string, error) {
+	s.simulated = append(s.simulated, command)
+	if s.rejected != "" && strings.Contains(command, s.rejected) {
+		return `Error from server (Forbidden): admission webhook "policy.example.com" denied the request: replicas above 5`, errors.New("exit status 1")
+	}
+	for substring, output := range s.dryRuns {
+		if strings.Contains(command, substring) {
+			return output, nil
+		}
+	}
+	return "", errors.New(`error: unknown command "frobnicate"`)
+}
+
+func TestNeedsSimulation(t *testing.T) {
+	tests := []struct {
+		name   string
+		action RemediationAction
+		want   bool
+	}{
+		{"read-only", RemediationAction{Commands: []string{"kubectl get pods -n shop"}, Risk: RiskHigh}, false},
+		{"single low-risk change", RemediationAction{Commands: []string{"kubectl rollout restart deployment/web -n shop"}, Risk: RiskLow}, false},
+		{"medium risk", RemediationAction{Commands: []string{"kubectl scale deployment web --replicas=3 -n shop"}, Risk: RiskMedium}, true},
+		{"requires approval", RemediationAction{Commands: []string{"kubectl scale deployment web --replicas=3 -n shop"}, Risk: RiskLow, RequiresApproval: true}, true},
+		{"several commands", RemediationAction{Commands: []string{"kubectl get pods -n shop", "kubectl rollout restart deployment/web -n shop"}, Risk: RiskLow}, true},
+	}
+	for _, tt := range tests {
+		if got := NeedsSimulation(tt.action); got != tt.want {
+			t.Errorf("%s: NeedsSimulation() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSimulateRemediation(t *testing.T) {
+	executor := &simulatingExecutor{dryRuns: map[string]string{
+		"scale":     strings.Replace(currentDeployment, `"replicas":2`, `"replicas":4`, 1),
+		"configmap": `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web-config","namespace":"shop"},"data":{"mode":"safe"}}`,
+		"delete":    `pod "web-1" deleted (server dry run)`,
+	}}
+	action := RemediationAction{Commands: []string{
+		"kubectl get pods -n shop",
+		"kubectl scale deployment web --replicas=4 -n shop",
+		"kubectl create configmap web-config --from-literal=mode=safe -n shop",
+		"kubectl delete pod web-1 -n shop",
+	}}
+
+	simulation := SimulateRemediation(context.Background(), action, executor, executor)
+	if simulation.Outcome != SimulationAccepted || len(simulation.Steps) != 4 {
+		t.Fatalf("expected 4 accepted steps, got %+v", simulation)
+	}
+	if step := simulation.Steps[0]; step.Outcome != SimulationSkipped || len(executor.simulated) != 3 {
+		t.Errorf("expected the read-only command skipped, got %+v", step)
+	}
+	scaled := simulation.Steps[1].Objects
+	if len(scaled) != 1 || scaled[0].Kind != "Deployment" || scaled[0].Created {
+		t.Fatalf("expected the deployment compared, got %+v", scaled)
+	}
+	if changes := scaled[0].Changes; len(changes) != 1 || changes[0] != (FieldChange{Path: "spec.replicas", Before: "2", After: "4"}) {
+		t.Errorf("expected only the replicas changed, got %+v", changes)
+	}
+	if created := simulation.Steps[2].Objects; len(created) != 1 || !created[0].Created {
+		t.Errorf("expected the configmap reported as created, got %+v", created)
+	}
+	if step := simulation.Steps[3]; step.Outcome != SimulationAccepted || !strings.Contains(step.Message, "server dry run") {
+		t.Errorf("expected the deletion's answer kept, got %+v", step)
+	}
+
+	executor.rejected = "replicas=9"
+	action.Commands = []string{"kubectl frobnicate deployment web", "kubectl scale deployment web --replicas=9 -n shop"}
+	simulation = SimulateRemediation(context.Background(), action, executor, executor)
+	if simulation.Outcome != SimulationRejected {
+		t.Fatalf("expected the simulation rejected, got %+v", simulation)
+	}
+	if simulation.Steps[0].Outcome != SimulationFailed || !strings.Contains(simulation.Steps[1].Message, "denied the request") {
+		t.Errorf("expected a failed and a rejected step, got %+v", simulation.Steps)
+	}
+}
+
+func TestExecuteRemediation_RefusesRejectedSimulation(t *testing.T) {
+	executor := &simulatingExecutor{rejected: "replicas=9"}
+	engine := NewRemediationEngine(nil, executor, NewDefaultSafetyChecker())
+	action := RemediationAction{
+		ID:       "action-1",
+		Commands: []string{"kubectl scale deployment web --replicas=9 -n shop"},
+		Risk:     RiskMedium,
+	}
+
+	record, err := engine.ExecuteRemediation(context.Background(), action, false)
+	if err == nil || record.Success {
+		t.Fatalf("expected the remediation refused, got %+v", record)
+	}
+	if record.Action.Simulation == nil || record.Action.Simulation.Outcome != SimulationRejected {
+		t.Errorf("expected the simulation recorded, got %+v", record.Action.Simulation)
+	}
+	for _, command := range executor.commands {
+		if strings.Contains(command, "scale") {
+			t.Errorf("expected the command not run, got %s", command)
+		}
+	}
+}
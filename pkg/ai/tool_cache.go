@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ToolCacheConfig bounds how long the output of read-only kubectl commands
+// is reused, so AI endpoints asking about the same cluster within a short
+// window share one command instead of each hitting the API server
+type ToolCacheConfig struct {
+	TTL        time.Duration // How long a result is reused
+	MaxEntries int           // Results kept; the soonest to expire is evicted first
+}
+
+// ToolCacheStats reports cache activity
+type ToolCacheStats struct {
+	Hits      uint64 `json:"hits"`      // Commands answered from the cache or a command in flight
+	Misses    uint64 `json:"misses"`    // Commands that ran
+	Refreshes uint64 `json:"refreshes"` // Refreshes, explicit or after commands that change the cluster
+	Entries   int    `json:"entries"`
+}
+
+// toolCacheKey identifies a command run against a cluster
+type toolCacheKey struct {
+	cluster string
+	command string
+}
+
+// toolCacheEntry is a cached or in-flight command result. done is closed
+// once output and err are set.
+type toolCacheEntry struct {
+	done    chan struct{}
+	output  string
+	err     error
+	expires time.Time
+}
+
+// ToolCache is a short-lived cache of read-only kubectl output keyed by
+// cluster and command. Concurrent callers of the same command wait for a
+// single run. Failed commands are not cached.
+type ToolCache struct {
+	config ToolCacheConfig
+
+	mu      sync.Mutex
+	entries map[toolCacheKey]*toolCacheEntry
+	stats   ToolCacheStats
+
+	now func() time.Time
+}
+
+var (
+	sharedToolCache     *ToolCache
+	sharedToolCacheOnce sync.Once
+)
+
+// SharedToolCache returns the cache shared by all kubectl executors
+func SharedToolCache() *ToolCache {
+	sharedToolCacheOnce.Do(func() {
+		sharedToolCache = NewToolCache(ToolCacheConfig{})
+	})
+	return sharedToolCache
+}
+
+// NewToolCache creates a tool cache, filling in defaults
+func NewToolCache(config ToolCacheConfig) *ToolCache {
+	if config.TTL <= 0 {
+		config.TTL = 30 * time.Second
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 256
+	}
+	return &ToolCache{
+		config:  config,
+		entries: make(map[toolCacheKey]*toolCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// toolRefreshKey marks a context whose commands bypass cached results
+type toolRefreshKey struct{}
+
+// WithToolRefresh returns a context whose read-only commands run afresh
+// and replace any cached result, e.g. when a user explicitly asks for
+// current data
+func WithToolRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, toolRefreshKey{}, true)
+}
+
+// Do returns the cached output of a command on a cluster, or runs it and
+// caches a successful result for the TTL
+func (c *ToolCache) Do(ctx context.Context, cluster, command string, run func(context.Context) (string, error)) (string, error) {
+	key := toolCacheKey{cluster: cluster, command: strings.Join(strings.Fields(command), " ")}
+	refresh, _ := ctx.Value(toolRefreshKey{}).(bool)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !refresh && c.usable(entry) {
+		c.stats.Hits++
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.output, entry.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	c.stats.Misses++
+	entry = &toolCacheEntry{done: make(chan struct{})}
+	c.makeRoom()
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	output, err := run(ctx)
+
+	c.mu.Lock()
+	entry.output, entry.err = output, err
+	entry.expires = c.now().Add(c.config.TTL)
+	if err != nil && c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return output, err
+}
+
+// usable reports whether an entry is in flight or not yet expired; callers
+// hold c.mu
+func (c *ToolCache) usable(entry *toolCacheEntry) bool {
+	select {
+	case <-entry.done:
+		return c.now().Before(entry.expires)
+	default:
+		return true
+	}
+}
+
+// makeRoom drops expired results and, when the cache is still full, the
+// result closest to expiry; callers hold c.mu
+func (c *ToolCache) makeRoom() {
+	if len(c.entries) < c.config.MaxEntries {
+		return
+	}
+	now := c.now()
+	var oldest toolCacheKey
+	var oldestExpires time.Time
+	for key, entry := range c.entries {
+		select {
+		case <-entry.done:
+		default:
+			continue // In flight; its callers are waiting on it
+		}
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestExpires.IsZero() || entry.expires.Before(oldestExpires) {
+			oldest, oldestExpires = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.config.MaxEntries && !oldestExpires.IsZero() {
+		delete(c.entries, oldest)
+	}
+}
+
+// Refresh drops the cached results of a cluster, or of every cluster when
+// cluster is empty, and returns how many were dropped. Commands in flight
+// finish but are not reused.
+func (c *ToolCache) Refresh(cluster string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := 0
+	for key := range c.entries {
+		if cluster == "" || key.cluster == cluster {
+			delete(c.entries, key)
+			dropped++
+		}
+	}
+	c.stats.Refreshes++
+	klog.V(2).Infof("Refreshed tool cache for %q: dropped %d results", cluster, dropped)
+	return dropped
+}
+
+// Stats returns a snapshot of cache activity
+func (c *ToolCache) Stats() ToolCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	return stats
+}
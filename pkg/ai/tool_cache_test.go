@@ -0,0 +1,139 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestToolCache_Do(t *testing.T) {
+	now := time.Now()
+	cache := NewToolCache(ToolCacheConfig{TTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	runs := 0
+	run := func(output string, err error) func(context.Context) (string, error) {
+		return func(context.Context) (string, error) {
+			runs++
+			return output, err
+		}
+	}
+	ctx := context.Background()
+
+	if out, _ := cache.Do(ctx, "prod", "get pods", run("pods v1", nil)); out != "pods v1" {
+		t.Fatalf("expected the command output, got %q", out)
+	}
+	if out, _ := cache.Do(ctx, "prod", " get   pods ", run("pods v2", nil)); out != "pods v1" || runs != 1 {
+		t.Errorf("expected the same command to reuse its output, got %q after %d runs", out, runs)
+	}
+	if out, _ := cache.Do(ctx, "staging", "get pods", run("staging pods", nil)); out != "staging pods" || runs != 2 {
+		t.Errorf("expected clusters to be cached apart, got %q after %d runs", out, runs)
+	}
+	if out, _ := cache.Do(WithToolRefresh(ctx), "prod", "get pods", run("pods v3", nil)); out != "pods v3" || runs != 3 {
+		t.Errorf("expected a refresh to run the command, got %q after %d runs", out, runs)
+	}
+	if out, _ := cache.Do(ctx, "prod", "get pods", run("pods v4", nil)); out != "pods v3" {
+		t.Errorf("expected the refreshed output to be cached, got %q", out)
+	}
+
+	now = now.Add(time.Minute)
+	if out, _ := cache.Do(ctx, "prod", "get pods", run("pods v5", nil)); out != "pods v5" || runs != 4 {
+		t.Errorf("expected an expired result to run again, got %q after %d runs", out, runs)
+	}
+
+	failure := errors.New("connection refused")
+	if _, err := cache.Do(ctx, "prod", "get nodes", run("", failure)); !errors.Is(err, failure) {
+		t.Fatalf("expected the command error, got %v", err)
+	}
+	if out, err := cache.Do(ctx, "prod", "get nodes", run("nodes", nil)); err != nil || out != "nodes" || runs != 6 {
+		t.Errorf("expected a failure not to be cached, got %q, %v after %d runs", out, err, runs)
+	}
+
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 6 || stats.Entries != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestToolCache_Refresh(t *testing.T) {
+	cache := NewToolCache(ToolCacheConfig{})
+	ctx := context.Background()
+	output := func(context.Context) (string, error) { return "ok", nil }
+	for _, cluster := range []string{"prod", "prod", "staging"} {
+		for _, command := range []string{"get pods", "get nodes"} {
+			_, _ = cache.Do(ctx, cluster, command, output)
+		}
+	}
+
+	if dropped := cache.Refresh("prod"); dropped != 2 {
+		t.Errorf("expected prod's two results to be dropped, got %d", dropped)
+	}
+	if dropped := cache.Refresh(""); dropped != 2 {
+		t.Errorf("expected the remaining results to be dropped, got %d", dropped)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Refreshes != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestToolCache_ConcurrentCallersShareOneRun(t *testing.T) {
+	cache := NewToolCache(ToolCacheConfig{})
+	release := make(chan struct{})
+	var runs atomic.Int32
+
+	var wg sync.WaitGroup
+	outputs := make([]string, 5)
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outputs[i], _ = cache.Do(context.Background(), "prod", "top nodes", func(context.Context) (string, error) {
+				runs.Add(1)
+				<-release
+				return "node-1 50%", nil
+			})
+		}()
+	}
+	for cache.Stats().Hits+cache.Stats().Misses < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("expected one run for concurrent callers, got %d", runs.Load())
+	}
+	for i, output := range outputs {
+		if output != "node-1 50%" {
+			t.Errorf("caller %d got %q", i, output)
+		}
+	}
+}
+
+func TestToolCache_MaxEntries(t *testing.T) {
+	now := time.Now()
+	cache := NewToolCache(ToolCacheConfig{TTL: time.Minute, MaxEntries: 2})
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		command := fmt.Sprintf("get pod api-%d", i)
+		_, _ = cache.Do(ctx, "prod", command, func(context.Context) (string, error) { return command, nil })
+		now = now.Add(time.Second)
+	}
+
+	if entries := cache.Stats().Entries; entries != 2 {
+		t.Fatalf("expected the cache to stay at 2 results, got %d", entries)
+	}
+	reran := false
+	_, _ = cache.Do(ctx, "prod", "get pod api-0", func(context.Context) (string, error) {
+		reran = true
+		return "", nil
+	})
+	if !reran {
+		t.Error("expected the result closest to expiry to be evicted")
+	}
+}
@@ -0,0 +1,211 @@
+package ai
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ToolLimiterConfig bounds how fast AI-driven kubectl commands reach the API
+// server so a large analysis doesn't trip API priority and fairness limits
+type ToolLimiterConfig struct {
+	QPS           float64       // Sustained commands per second
+	Burst         int           // Commands allowed back-to-back
+	MaxConcurrent int           // Commands running at once
+	MinQPS        float64       // Floor for the adaptive slow-down
+	RecoveryAfter time.Duration // Throttle-free time before each speed-up step
+}
+
+// ToolLimiterStats reports limiter activity
+type ToolLimiterStats struct {
+	Executed   uint64  `json:"executed"`    // Commands that were allowed to run
+	Delayed    uint64  `json:"delayed"`     // Commands that waited for a token or slot
+	Throttled  uint64  `json:"throttled"`   // Commands the API server answered with 429
+	CurrentQPS float64 `json:"current_qps"` // Rate after adaptive slow-down
+	InFlight   int     `json:"in_flight"`
+}
+
+// ToolLimiter is a token bucket with a concurrency cap that halves its rate
+// whenever the API server throttles a command and recovers gradually
+type ToolLimiter struct {
+	config ToolLimiterConfig
+	slots  chan struct{}
+
+	mu           sync.Mutex
+	qps          float64
+	tokens       float64
+	last         time.Time
+	lastThrottle time.Time
+	stats        ToolLimiterStats
+
+	now func() time.Time
+}
+
+var (
+	sharedToolLimiter     *ToolLimiter
+	sharedToolLimiterOnce sync.Once
+)
+
+// SharedToolLimiter returns the limiter shared by all kubectl executors
+func SharedToolLimiter() *ToolLimiter {
+	sharedToolLimiterOnce.Do(func() {
+		sharedToolLimiter = NewToolLimiter(ToolLimiterConfig{})
+	})
+	return sharedToolLimiter
+}
+
+// NewToolLimiter creates a tool limiter, filling in defaults
+func NewToolLimiter(config ToolLimiterConfig) *ToolLimiter {
+	if config.QPS <= 0 {
+		config.QPS = 2
+	}
+	if config.Burst <= 0 {
+		config.Burst = 5
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 3
+	}
+	if config.MinQPS <= 0 || config.MinQPS > config.QPS {
+		config.MinQPS = config.QPS / 10
+	}
+	if config.RecoveryAfter <= 0 {
+		config.RecoveryAfter = 30 * time.Second
+	}
+
+	return &ToolLimiter{
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+		qps:    config.QPS,
+		tokens: float64(config.Burst),
+		now:    time.Now,
+	}
+}
+
+// Acquire waits for a rate token and a concurrency slot. The returned
+// function releases the slot and must be called once the command finishes.
+func (l *ToolLimiter) Acquire(ctx context.Context) (func(), error) {
+	delayed := false
+	for {
+		wait := l.reserve()
+		if wait == 0 {
+			break
+		}
+		delayed = true
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		delayed = true
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	l.stats.Executed++
+	l.stats.InFlight++
+	if delayed {
+		l.stats.Delayed++
+	}
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-l.slots
+			l.mu.Lock()
+			l.stats.InFlight--
+			l.mu.Unlock()
+		})
+	}, nil
+}
+
+// reserve takes a token if one is available, otherwise returns how long to
+// wait for the next one
+func (l *ToolLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.recover(now)
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.qps
+		if max := float64(l.config.Burst); l.tokens > max {
+			l.tokens = max
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.qps * float64(time.Second))
+}
+
+// recover steps the rate back up after a throttle-free period
+func (l *ToolLimiter) recover(now time.Time) {
+	if l.qps >= l.config.QPS || now.Sub(l.lastThrottle) < l.config.RecoveryAfter {
+		return
+	}
+	l.qps += l.config.QPS / 4
+	if l.qps > l.config.QPS {
+		l.qps = l.config.QPS
+	}
+	l.lastThrottle = now
+	klog.V(2).Infof("Tool limiter recovering: %.2f commands/s", l.qps)
+}
+
+// Observe records a command's outcome. Throttling by the API server halves
+// the rate, down to MinQPS, and drains the bucket so the burst stops.
+func (l *ToolLimiter) Observe(output string, err error) {
+	if !isThrottled(output, err) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Throttled++
+	l.qps /= 2
+	if l.qps < l.config.MinQPS {
+		l.qps = l.config.MinQPS
+	}
+	l.tokens = 0
+	l.lastThrottle = l.now()
+	klog.Warningf("API server throttled a kubectl command, slowing to %.2f commands/s", l.qps)
+}
+
+// Stats returns a snapshot of limiter activity
+func (l *ToolLimiter) Stats() ToolLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.CurrentQPS = l.qps
+	return stats
+}
+
+// isThrottled reports whether kubectl output shows an HTTP 429 response
+func isThrottled(output string, err error) bool {
+	text := strings.ToLower(output)
+	if err != nil {
+		text += " " + strings.ToLower(err.Error())
+	}
+	return strings.Contains(text, "toomanyrequests") ||
+		strings.Contains(text, "too many requests") ||
+		strings.Contains(text, "status code 429")
+}
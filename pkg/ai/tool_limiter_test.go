@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToolLimiter_Burst(t *testing.T) {
+	now := time.Now()
+	limiter := NewToolLimiter(ToolLimiterConfig{QPS: 2, Burst: 3})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if wait := limiter.reserve(); wait != 0 {
+			t.Fatalf("expected command %d to run within the burst, waited %v", i, wait)
+		}
+	}
+	if wait := limiter.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("expected to wait for the next token at 2/s, got %v", wait)
+	}
+
+	now = now.Add(time.Second)
+	if wait := limiter.reserve(); wait != 0 {
+		t.Errorf("expected a refilled token, waited %v", wait)
+	}
+}
+
+func TestToolLimiter_AdaptiveSlowDown(t *testing.T) {
+	now := time.Now()
+	limiter := NewToolLimiter(ToolLimiterConfig{QPS: 4, MinQPS: 1, RecoveryAfter: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Observe("pods is forbidden", errors.New("exit status 1"))
+	if stats := limiter.Stats(); stats.Throttled != 0 || stats.CurrentQPS != 4 {
+		t.Fatalf("expected ordinary failures not to slow down, got %+v", stats)
+	}
+
+	throttled := "Error from server (TooManyRequests): the server has received too many requests"
+	limiter.Observe(throttled, errors.New("exit status 1"))
+	limiter.Observe(throttled, errors.New("exit status 1"))
+	limiter.Observe(throttled, errors.New("exit status 1"))
+	stats := limiter.Stats()
+	if stats.Throttled != 3 || stats.CurrentQPS != 1 {
+		t.Fatalf("expected rate halved down to the floor, got %+v", stats)
+	}
+	if wait := limiter.reserve(); wait == 0 {
+		t.Error("expected throttling to drain the bucket")
+	}
+
+	now = now.Add(30 * time.Second)
+	limiter.reserve()
+	if qps := limiter.Stats().CurrentQPS; qps != 1 {
+		t.Errorf("expected no recovery before RecoveryAfter, got %v", qps)
+	}
+	now = now.Add(31 * time.Second)
+	limiter.reserve()
+	if qps := limiter.Stats().CurrentQPS; qps != 2 {
+		t.Errorf("expected one recovery step, got %v", qps)
+	}
+}
+
+func TestToolLimiter_Concurrency(t *testing.T) {
+	limiter := NewToolLimiter(ToolLimiterConfig{QPS: 100, Burst: 10, MaxConcurrent: 1})
+
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected second command to wait for a slot, got %v", err)
+	}
+
+	release()
+	release() // Releasing twice is harmless
+	second, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected a free slot after release, got %v", err)
+	}
+	second()
+
+	stats := limiter.Stats()
+	if stats.Executed != 2 || stats.InFlight != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		output string
+		err    error
+		want   bool
+	}{
+		{"Error from server (TooManyRequests): please try again later", nil, true},
+		{"", errors.New("the server responded with status code 429"), true},
+		{"Error from server (NotFound): pods \"x\" not found", errors.New("exit status 1"), false},
+		{"NAME READY STATUS", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isThrottled(tt.output, tt.err); got != tt.want {
+			t.Errorf("isThrottled(%q, %v) = %v, want %v", tt.output, tt.err, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package ai
 
 import (
 	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
 )
 
 // AnalysisRequest represents a request for AI analysis
@@ -24,6 +27,7 @@ const (
 	AnalysisTypeOptimization AnalysisType = "optimization"
 	AnalysisTypeSummary      AnalysisType = "summary"
 	AnalysisTypeRootCause    AnalysisType = "root_cause"
+	AnalysisTypeBatch        AnalysisType = "batch"
 )
 
 // AnalysisResponse represents the AI's analysis response
@@ -36,9 +40,11 @@ type AnalysisResponse struct {
 	Severity        SeverityLevel          `json:"severity"`
 	Recommendations []Recommendation       `json:"recommendations"`
 	Actions         []SuggestedAction      `json:"actions"`
+	Findings        []findings.Finding     `json:"findings,omitempty"` // Problems classified by finding type
 	Context         map[string]interface{} `json:"context"`
 	Timestamp       time.Time              `json:"timestamp"`
 	Duration        time.Duration          `json:"duration"`
+	Usage           *Usage                 `json:"usage,omitempty"` // Tokens and cost of the CLI run
 }
 
 // SeverityLevel represents the severity of an issue
@@ -61,6 +67,8 @@ type Recommendation struct {
 	Impact      string            `json:"impact"`
 	Effort      string            `json:"effort"`
 	References  []string          `json:"references,omitempty"`
+	Evidence    []string          `json:"evidence,omitempty"` // IDs of the Evidence this is based on
+	Finding     string            `json:"finding,omitempty"`  // ID of the finding type it addresses
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
@@ -101,6 +109,10 @@ type InsightSummary struct {
 	AIConfidence    float64                `json:"ai_confidence"`
 	LastAnalyzed    time.Time              `json:"last_analyzed"`
 	Context         map[string]interface{} `json:"context"`
+
+	// StateRevision is the revision of the cluster state the insights were
+	// computed from; a newer revision in the cluster health makes them stale
+	StateRevision uint64 `json:"state_revision"`
 }
 
 // DiagnosticContext provides context for AI analysis
@@ -110,11 +122,44 @@ type DiagnosticContext struct {
 	ResourceType   string                 `json:"resource_type,omitempty"`
 	ResourceName   string                 `json:"resource_name,omitempty"`
 	ErrorLogs      []string               `json:"error_logs,omitempty"`
+	LogPatterns    []LogPattern           `json:"log_patterns,omitempty"`
 	Events         []string               `json:"events,omitempty"`
 	Metrics        []Metric               `json:"metrics,omitempty"`
 	RelatedChecks  []CheckResult          `json:"related_checks,omitempty"`
 	HistoricalData []CheckResult          `json:"historical_data,omitempty"`
 	ClusterState   map[string]interface{} `json:"cluster_state,omitempty"`
+	Runbook        string                 `json:"runbook,omitempty"` // Team runbook for the failing check
+
+	// ExpectedDisruptions are issues caused by planned maintenance, such as
+	// cordoned or draining nodes, anywhere in the cluster
+	ExpectedDisruptions []string `json:"expected_disruptions,omitempty"`
+
+	// BlockedDeployments are workloads whose pods an admission webhook or a
+	// resource quota is refusing to create, anywhere in the cluster
+	BlockedDeployments []string `json:"blocked_deployments,omitempty"`
+
+	// SchedulingFailures explain each pending pod the scheduler can't
+	// place, e.g. "pod ml/trainer-0 pending because no node has gpu=true"
+	SchedulingFailures []string `json:"scheduling_failures,omitempty"`
+
+	// DescribedResources hold describe-equivalent data for the resources
+	// the failing check implicates
+	DescribedResources []ResourceDescription `json:"described_resources,omitempty"`
+
+	// Annotations are operators' notes on the analyzed period, such as a
+	// load test or migration window
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// ResourceDescription is what kubectl describe shows for a resource,
+// trimmed to the parts that matter for diagnosis
+type ResourceDescription struct {
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Details   []string `json:"details,omitempty"`
+	Events    []string `json:"events,omitempty"` // Latest last
+	Error     string   `json:"error,omitempty"`  // Why the resource couldn't be described
 }
 
 // Local type definitions to avoid import cycles
@@ -162,12 +207,36 @@ type CheckResult struct {
 	Predictions []Prediction           `json:"predictions,omitempty"`
 }
 
+// LogPattern represents a recurring pattern extracted from pod logs
+type LogPattern struct {
+	Pattern string   `json:"pattern"`
+	Kind    string   `json:"kind"`
+	Count   int      `json:"count"`
+	Example string   `json:"example"`
+	Pods    []string `json:"pods,omitempty"`
+}
+
 // Prediction represents an ML prediction
 type Prediction struct {
-	Timestamp   time.Time    `json:"timestamp"`
-	Status      HealthStatus `json:"status"`
-	Probability float64      `json:"probability"`
-	Reason      string       `json:"reason"`
+	Timestamp   time.Time           `json:"timestamp"`
+	Status      HealthStatus        `json:"status"`
+	Probability float64             `json:"probability"`
+	Reason      string              `json:"reason"`
+	Explanation *AnomalyExplanation `json:"explanation,omitempty"`
+}
+
+// AnomalyExplanation is the evidence behind an anomaly prediction (local
+// copy to avoid cycle)
+type AnomalyExplanation struct {
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Observed  float64           `json:"observed"`
+	Baseline  float64           `json:"baseline"`
+	StdDev    float64           `json:"std_dev"`
+	ZScore    float64           `json:"z_score"`
+	Threshold float64           `json:"threshold"`
+	Window    int               `json:"window"`
+	History   []float64         `json:"history"`
 }
 
 // HealthScore represents health scoring information
@@ -181,9 +250,11 @@ type HealthScore struct {
 
 // ClusterHealth represents overall cluster health
 type ClusterHealth struct {
-	ClusterName string        `json:"cluster_name"`
-	Status      HealthStatus  `json:"status"`
-	Score       HealthScore   `json:"score"`
-	Checks      []CheckResult `json:"checks"`
-	Timestamp   time.Time     `json:"timestamp"`
+	ClusterName string               `json:"cluster_name"`
+	Status      HealthStatus         `json:"status"`
+	Score       HealthScore          `json:"score"`
+	Checks      []CheckResult        `json:"checks"`
+	Inventory   *inventory.Inventory `json:"inventory,omitempty"`   // What the cluster runs, once collected
+	Annotations []string             `json:"annotations,omitempty"` // Operators' notes on the recent period
+	Timestamp   time.Time            `json:"timestamp"`
 }
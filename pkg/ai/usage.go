@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// charsPerToken approximates tokens from text when the CLI doesn't report
+// them
+const charsPerToken = 4
+
+// Usage is what an analysis consumed
+type Usage struct {
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`  // As reported by the CLI
+	Estimated    bool    `json:"estimated,omitempty"` // Tokens approximated from the prompt and output length
+}
+
+// cliResult is the JSON the Claude CLI prints with --output-format json
+type cliResult struct {
+	Type    string  `json:"type"`
+	Result  string  `json:"result"`
+	IsError bool    `json:"is_error"`
+	CostUSD float64 `json:"total_cost_usd"`
+	Usage   struct {
+		InputTokens         int `json:"input_tokens"`
+		CacheCreationTokens int `json:"cache_creation_input_tokens"`
+		CacheReadTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens        int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// parseCLIOutput unwraps the analysis text and usage from the CLI's JSON
+// output. Output that isn't the CLI's JSON, from older CLIs or test mode,
+// is the text itself, with usage estimated from its length.
+func parseCLIOutput(output, prompt string) (string, Usage, error) {
+	var result cliResult
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &result); err != nil || result.Type != "result" {
+		return output, Usage{
+			InputTokens:  estimateTokens(prompt),
+			OutputTokens: estimateTokens(output),
+			Estimated:    true,
+		}, nil
+	}
+	usage := Usage{
+		InputTokens:  result.Usage.InputTokens + result.Usage.CacheCreationTokens + result.Usage.CacheReadTokens,
+		OutputTokens: result.Usage.OutputTokens,
+		CostUSD:      result.CostUSD,
+	}
+	if result.IsError {
+		return "", usage, fmt.Errorf("claude CLI reported an error: %s", result.Result)
+	}
+	return result.Result, usage, nil
+}
+
+// estimateTokens approximates the tokens of text
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
@@ -0,0 +1,48 @@
+package ai
+
+import "testing"
+
+func TestParseCLIOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		text    string
+		usage   Usage
+		wantErr bool
+	}{
+		{
+			name:   "json result",
+			output: `{"type":"result","result":"all good","total_cost_usd":0.012,"usage":{"input_tokens":100,"cache_read_input_tokens":900,"output_tokens":50}}`,
+			text:   "all good",
+			usage:  Usage{InputTokens: 1000, OutputTokens: 50, CostUSD: 0.012},
+		},
+		{
+			name:   "plain text",
+			output: "SUMMARY: all good",
+			text:   "SUMMARY: all good",
+			usage:  Usage{InputTokens: 3, OutputTokens: 5, Estimated: true},
+		},
+		{
+			name:    "error result",
+			output:  `{"type":"result","is_error":true,"result":"rate limited"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, usage, err := parseCLIOutput(tt.output, "ten chars!")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if text != tt.text || usage != tt.usage {
+				t.Errorf("expected %q with %+v, got %q with %+v", tt.text, tt.usage, text, usage)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"k8s.io/klog/v2"
+)
+
+// DefaultAlertmanagerSyncInterval is how often silences are synced with
+// Alertmanager
+const DefaultAlertmanagerSyncInterval = time.Minute
+
+// alertmanagerSilencePrefix starts the IDs of silences pulled from
+// Alertmanager; the rest is the Alertmanager silence ID
+const alertmanagerSilencePrefix = "alertmanager-"
+
+// kubepulseMarker tags the comment of silences pushed to Alertmanager with
+// the KubePulse silence ID, so they aren't pulled back
+var kubepulseMarker = regexp.MustCompile(`\[kubepulse:([^\]]+)\]$`)
+
+// SilenceStore holds the silences that are synced, such as the engine
+type SilenceStore interface {
+	GetSilences() []Silence
+	AddSilence(silence Silence) (Silence, error)
+	ExpireSilence(id string) (Silence, error)
+}
+
+// AlertmanagerSyncStatus reports how the last sync went
+type AlertmanagerSyncStatus struct {
+	URL       string    `json:"url"`
+	LastSync  time.Time `json:"last_sync,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+	Pulled    int       `json:"pulled"`    // Alertmanager silences in effect in KubePulse
+	Pushed    int       `json:"pushed"`    // KubePulse silences in effect in Alertmanager
+	Unmatched int       `json:"unmatched"` // Alertmanager silences on labels KubePulse alerts don't carry
+}
+
+// AlertmanagerSync keeps KubePulse's silences and an Alertmanager's in
+// step, both ways: Alertmanager silences whose matchers are equalities on
+// labels KubePulse alerts carry are added to KubePulse, KubePulse silences
+// are created in Alertmanager, and a silence expired on one side is expired
+// on the other.
+type AlertmanagerSync struct {
+	url      string
+	client   *http.Client
+	interval time.Duration
+	store    SilenceStore
+
+	mu     sync.Mutex
+	status AlertmanagerSyncStatus
+}
+
+// NewAlertmanagerSync creates a sync with the Alertmanager at baseURL,
+// such as http://alertmanager:9093, every interval,
+// DefaultAlertmanagerSyncInterval when zero
+func NewAlertmanagerSync(baseURL string, interval time.Duration, store SilenceStore) *AlertmanagerSync {
+	if interval <= 0 {
+		interval = DefaultAlertmanagerSyncInterval
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return &AlertmanagerSync{
+		url:      baseURL,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+		store:    store,
+		status:   AlertmanagerSyncStatus{URL: baseURL},
+	}
+}
+
+// Run syncs silences every interval until ctx is done
+func (s *AlertmanagerSync) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(ctx); err != nil {
+			klog.Warningf("Failed to sync silences with Alertmanager: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns how the last sync went
+func (s *AlertmanagerSync) Status() AlertmanagerSyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// amMatcher is a matcher of the Alertmanager v2 API
+type amMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual *bool  `json:"isEqual,omitempty"` // Unset means true
+}
+
+// amSilence is a silence of the Alertmanager v2 API
+type amSilence struct {
+	ID        string      `json:"id,omitempty"`
+	Matchers  []amMatcher `json:"matchers"`
+	StartsAt  time.Time   `json:"startsAt"`
+	EndsAt    time.Time   `json:"endsAt"`
+	CreatedBy string      `json:"createdBy"`
+	Comment   string      `json:"comment"`
+	Status    *struct {
+		State string `json:"state"`
+	} `json:"status,omitempty"`
+}
+
+// matchers converts the silence's matchers, reporting false when one isn't
+// an equality on a label KubePulse alerts carry
+func (a amSilence) matchers() (map[string]string, bool) {
+	matchers := make(map[string]string, len(a.Matchers))
+	for _, matcher := range a.Matchers {
+		if matcher.IsRegex || (matcher.IsEqual != nil && !*matcher.IsEqual) {
+			return nil, false
+		}
+		matchers[matcher.Name] = matcher.Value
+	}
+	if validateMatchers(matchers) != nil {
+		return nil, false
+	}
+	return matchers, true
+}
+
+// Sync runs one round of syncing
+func (s *AlertmanagerSync) Sync(ctx context.Context) error {
+	remote, err := s.list(ctx)
+	if err != nil {
+		// Keep the counts of the last listing
+		s.mu.Lock()
+		s.status.LastSync, s.status.LastError = time.Now(), err.Error()
+		s.mu.Unlock()
+		return err
+	}
+
+	local := make(map[string]Silence)
+	for _, silence := range s.store.GetSilences() {
+		if silence.ID != "" {
+			local[silence.ID] = silence
+		}
+	}
+
+	var status AlertmanagerSyncStatus
+	var errs []string
+	pulled := make(map[string]bool)
+	pushed := make(map[string]amSilence)
+	for _, silence := range remote {
+		if silence.Status == nil || silence.Status.State != "active" {
+			continue
+		}
+		if marker := kubepulseMarker.FindStringSubmatch(silence.Comment); marker != nil {
+			pushed[marker[1]] = silence
+			continue
+		}
+		matchers, ok := silence.matchers()
+		if !ok {
+			status.Unmatched++
+			continue
+		}
+
+		id := alertmanagerSilencePrefix + silence.ID
+		pulled[id] = true
+		if existing, ok := local[id]; ok && sameEnd(existing.Until, silence.EndsAt) {
+			continue
+		} else if ok {
+			// Extended or shortened in Alertmanager
+			_, _ = s.store.ExpireSilence(id)
+		}
+		if _, err := s.store.AddSilence(Silence{
+			ID:        id,
+			Matchers:  matchers,
+			Until:     silence.EndsAt,
+			CreatedBy: "alertmanager/" + silence.CreatedBy,
+			Comment:   silence.Comment,
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("pull %s: %v", silence.ID, err))
+		}
+	}
+	status.Pulled = len(pulled)
+
+	for id, silence := range local {
+		if strings.HasPrefix(id, alertmanagerSilencePrefix) {
+			if !pulled[id] {
+				_, _ = s.store.ExpireSilence(id)
+			}
+			continue
+		}
+		if len(silence.Matchers) == 0 {
+			continue
+		}
+		if existing, ok := pushed[id]; ok && sameEnd(existing.EndsAt, silence.Until) {
+			status.Pushed++
+			continue
+		}
+		push := toAlertmanager(silence)
+		if existing, ok := pushed[id]; ok {
+			push.ID = existing.ID
+		}
+		if err := s.post(ctx, push); err != nil {
+			errs = append(errs, fmt.Sprintf("push %s: %v", id, err))
+			continue
+		}
+		status.Pushed++
+	}
+	for id, silence := range pushed {
+		if _, ok := local[id]; ok {
+			continue
+		}
+		if err := s.expire(ctx, silence.ID); err != nil {
+			errs = append(errs, fmt.Sprintf("expire %s: %v", silence.ID, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	s.record(status, err)
+	return err
+}
+
+// record stores the outcome of a sync
+func (s *AlertmanagerSync) record(status AlertmanagerSyncStatus, err error) {
+	status.URL = s.url
+	status.LastSync = time.Now()
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+// sameEnd reports whether two end times are the same, ignoring the
+// precision Alertmanager drops
+func sameEnd(a, b time.Time) bool {
+	return a.Sub(b).Abs() < time.Second
+}
+
+// toAlertmanager converts a KubePulse silence, marking its comment with
+// the KubePulse ID
+func toAlertmanager(silence Silence) amSilence {
+	push := amSilence{
+		StartsAt:  silence.CreatedAt,
+		EndsAt:    silence.Until,
+		CreatedBy: silence.CreatedBy,
+		Comment:   strings.TrimSpace(silence.Comment + " [kubepulse:" + silence.ID + "]"),
+	}
+	if push.StartsAt.IsZero() || push.StartsAt.After(time.Now()) {
+		push.StartsAt = time.Now()
+	}
+	if push.CreatedBy == "" {
+		push.CreatedBy = "kubepulse"
+	}
+	for _, label := range SilenceLabels {
+		if value, ok := silence.Matchers[label]; ok {
+			push.Matchers = append(push.Matchers, amMatcher{Name: label, Value: value})
+		}
+	}
+	return push
+}
+
+// list returns the silences of the Alertmanager
+func (s *AlertmanagerSync) list(ctx context.Context) ([]amSilence, error) {
+	resp, err := s.do(ctx, http.MethodGet, "/api/v2/silences", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var silences []amSilence
+	if err := json.NewDecoder(resp.Body).Decode(&silences); err != nil {
+		return nil, fmt.Errorf("failed to decode Alertmanager silences: %w", err)
+	}
+	return silences, nil
+}
+
+// post creates, or updates when it has an ID, an Alertmanager silence
+func (s *AlertmanagerSync) post(ctx context.Context, silence amSilence) error {
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPost, "/api/v2/silences", body)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.Body.Close()
+}
+
+// expire ends an Alertmanager silence
+func (s *AlertmanagerSync) expire(ctx context.Context, id string) error {
+	resp, err := s.do(ctx, http.MethodDelete, "/api/v2/silence/"+id, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do sends a request to the Alertmanager API, failing on non-2xx answers
+func (s *AlertmanagerSync) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "kubepulse/"+version.Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// managerStore syncs a manager's silences
+type managerStore struct{ *Manager }
+
+func (s managerStore) GetSilences() []Silence { return s.Silences() }
+
+// fakeAlertmanager serves the silences API of Alertmanager
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	silences map[string]amSilence
+	next     int
+}
+
+func (f *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+		silences := make([]amSilence, 0, len(f.silences))
+		for _, silence := range f.silences {
+			silences = append(silences, silence)
+		}
+		_ = json.NewEncoder(w).Encode(silences)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+		var silence amSilence
+		if err := json.NewDecoder(r.Body).Decode(&silence); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if silence.ID == "" {
+			f.next++
+			silence.ID = fmt.Sprintf("am-%d", f.next)
+		}
+		f.add(silence)
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": silence.ID})
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+		delete(f.silences, strings.TrimPrefix(r.URL.Path, "/api/v2/silence/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeAlertmanager) add(silence amSilence) {
+	silence.Status = &struct {
+		State string `json:"state"`
+	}{State: "active"}
+	f.silences[silence.ID] = silence
+}
+
+func TestAlertmanagerSync(t *testing.T) {
+	until := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	am := &fakeAlertmanager{silences: make(map[string]amSilence)}
+	am.add(amSilence{ID: "maint", Matchers: []amMatcher{{Name: "check", Value: "node-health"}}, EndsAt: until, CreatedBy: "alice", Comment: "node upgrades"})
+	am.add(amSilence{ID: "other", Matchers: []amMatcher{{Name: "alertname", Value: "Watchdog"}}, EndsAt: until})
+	am.add(amSilence{ID: "regex", Matchers: []amMatcher{{Name: "check", Value: "pod-.*", IsRegex: true}}, EndsAt: until})
+	server := httptest.NewServer(am)
+	defer server.Close()
+
+	manager := NewManager()
+	local, err := manager.AddSilence(Silence{Matchers: map[string]string{"check": "pod-health"}, Until: until, CreatedBy: "bob", Comment: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncer := NewAlertmanagerSync(server.URL+"/", 0, managerStore{manager})
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pulled := false
+	for _, silence := range manager.Silences() {
+		if silence.ID == "alertmanager-maint" {
+			pulled = silence.Matchers["check"] == "node-health" && silence.CreatedBy == "alertmanager/alice"
+		}
+	}
+	if !pulled || len(manager.Silences()) != 2 {
+		t.Errorf("expected only the equality silence on a KubePulse label pulled, got %+v", manager.Silences())
+	}
+	if len(am.silences) != 4 {
+		t.Fatalf("expected the KubePulse silence pushed, got %+v", am.silences)
+	}
+	status := syncer.Status()
+	if status.Pulled != 1 || status.Pushed != 1 || status.Unmatched != 2 || status.LastError != "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// A second round changes nothing
+	if err := syncer.Sync(context.Background()); err != nil || len(am.silences) != 4 || len(manager.Silences()) != 2 {
+		t.Fatalf("expected the sync idempotent, got %v, %+v, %+v", err, am.silences, manager.Silences())
+	}
+
+	// Expiring on either side expires on the other
+	if _, err := manager.ExpireSilence(local.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delete(am.silences, "maint")
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manager.Silences()) != 0 || len(am.silences) != 2 {
+		t.Errorf("expected both expired silences gone, got %+v and %+v", manager.Silences(), am.silences)
+	}
+}
@@ -0,0 +1,602 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Action IDs of the buttons on Slack alert messages
+const (
+	SlackAckActionID     = "kubepulse_ack"
+	SlackSilenceActionID = "kubepulse_silence"
+)
+
+// defaultPagerDutyURL is the PagerDuty Events API v2 endpoint
+const defaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// defaultOpsGenieURL is the OpsGenie API; EU accounts use api.eu.opsgenie.com
+const defaultOpsGenieURL = "https://api.opsgenie.com"
+
+// defaultSplunkOnCallURL is the Splunk On-Call (VictorOps) REST endpoint
+// integration; the API key and routing key are appended to it
+const defaultSplunkOnCallURL = "https://alert.victorops.com/integrations/generic/20131114/alert"
+
+// defaultHeartbeatInterval applies when a heartbeat is configured without an interval
+const defaultHeartbeatInterval = time.Minute
+
+// opsGenieMessageLimit is the longest alert message OpsGenie accepts
+const opsGenieMessageLimit = 130
+
+// slackSignatureMaxAge rejects replayed Slack interaction requests
+const slackSignatureMaxAge = 5 * time.Minute
+
+// NewChannel builds a notification channel from its configured type and settings
+func NewChannel(name, channelType string, settings map[string]interface{}) (NotificationChannel, error) {
+	switch channelType {
+	case "log":
+		return NewLogChannel(), nil
+	case "slack":
+		webhook := stringSetting(settings, "webhook")
+		if webhook == "" {
+			return nil, fmt.Errorf("slack channel %s needs a webhook setting", name)
+		}
+		return NewSlackChannel(name, webhook), nil
+	case "pagerduty":
+		routingKey := stringSetting(settings, "routing_key")
+		if routingKey == "" {
+			return nil, fmt.Errorf("pagerduty channel %s needs a routing_key setting", name)
+		}
+		channel := NewPagerDutyChannel(name, routingKey)
+		if endpoint := stringSetting(settings, "url"); endpoint != "" {
+			channel.url = endpoint
+		}
+		return channel, nil
+	case "opsgenie":
+		return newOpsGenieChannel(name, settings)
+	case "splunk_oncall", "victorops":
+		return newSplunkOnCallChannel(name, settings)
+	default:
+		return nil, fmt.Errorf("channel %s has unsupported type %q", name, channelType)
+	}
+}
+
+// stringSetting reads a string channel setting
+func stringSetting(settings map[string]interface{}, key string) string {
+	value, _ := settings[key].(string)
+	return value
+}
+
+// heartbeatSetting reads the heartbeat_interval setting, defaulting to
+// defaultHeartbeatInterval when a heartbeat is configured
+func heartbeatSetting(name string, settings map[string]interface{}, configured bool) (time.Duration, error) {
+	if !configured {
+		return 0, nil
+	}
+	raw := stringSetting(settings, "heartbeat_interval")
+	if raw == "" {
+		return defaultHeartbeatInterval, nil
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < 10*time.Second {
+		return 0, fmt.Errorf("channel %s has invalid heartbeat_interval %q; use a duration of at least 10s", name, raw)
+	}
+	return interval, nil
+}
+
+// SlackChannel posts alerts to a Slack incoming webhook with Acknowledge and
+// Silence buttons; button clicks reach KubePulse through the Slack app's
+// interactivity URL
+type SlackChannel struct {
+	name    string
+	webhook string
+	client  *http.Client
+}
+
+// NewSlackChannel creates a Slack channel
+func NewSlackChannel(name, webhook string) *SlackChannel {
+	return &SlackChannel{name: name, webhook: webhook, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name returns the channel name
+func (s *SlackChannel) Name() string {
+	return s.name
+}
+
+// Send posts the alert
+func (s *SlackChannel) Send(ctx context.Context, alert Alert) error {
+	text := fmt.Sprintf("[%s] %s: %s", strings.ToUpper(string(alert.Severity)), alert.Name, alert.Message)
+	body := fmt.Sprintf("*%s*\n%s", text, alert.Timestamp.Format(time.RFC3339))
+	if alert.Runbook != "" {
+		body += fmt.Sprintf("\n<%s|Runbook>", alert.Runbook)
+	}
+	if alert.SilenceURL != "" {
+		body += fmt.Sprintf("\n<%s|Silence with other options>", alert.SilenceURL)
+	}
+	buttons := []map[string]interface{}{
+		{
+			"type":      "button",
+			"action_id": SlackAckActionID,
+			"value":     alert.ID,
+			"style":     "primary",
+			"text":      map[string]string{"type": "plain_text", "text": "Acknowledge"},
+		},
+	}
+	if matchers := SilenceMatchers(alert); len(matchers) > 0 {
+		buttons = append(buttons, map[string]interface{}{
+			"type":      "button",
+			"action_id": SlackSilenceActionID,
+			"value":     encodeMatchers(matchers),
+			"text":      map[string]string{"type": "plain_text", "text": "Silence " + shortDuration(DefaultSilenceDuration)},
+		})
+	}
+	payload := map[string]interface{}{
+		"text": text,
+		"blocks": []map[string]interface{}{
+			{
+				"type": "section",
+				"text": map[string]string{"type": "mrkdwn", "text": body},
+			},
+			{
+				"type":     "actions",
+				"elements": buttons,
+			},
+		},
+	}
+	return postJSON(ctx, s.client, s.webhook, payload)
+}
+
+// Post sends a message in Slack mrkdwn that isn't an alert, such as a shift
+// handoff summary
+func (s *SlackChannel) Post(ctx context.Context, text string) error {
+	return postJSON(ctx, s.client, s.webhook, map[string]interface{}{"text": text, "mrkdwn": true})
+}
+
+// PagerDutyChannel triggers PagerDuty incidents through the Events API v2
+type PagerDutyChannel struct {
+	name       string
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+// NewPagerDutyChannel creates a PagerDuty channel for an integration routing key
+func NewPagerDutyChannel(name, routingKey string) *PagerDutyChannel {
+	return &PagerDutyChannel{
+		name:       name,
+		routingKey: routingKey,
+		url:        defaultPagerDutyURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (p *PagerDutyChannel) Name() string {
+	return p.name
+}
+
+// Send triggers an incident, deduplicated by the alert fingerprint
+func (p *PagerDutyChannel) Send(ctx context.Context, alert Alert) error {
+	payload := map[string]interface{}{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    alert.Fingerprint,
+		"payload": map[string]interface{}{
+			"summary":        fmt.Sprintf("%s: %s", alert.Name, alert.Message),
+			"source":         alert.Source,
+			"severity":       string(alert.Severity),
+			"timestamp":      alert.Timestamp.Format(time.RFC3339),
+			"custom_details": withSilenceDetails(alert),
+		},
+	}
+	var links []map[string]string
+	if alert.Runbook != "" {
+		links = append(links, map[string]string{"href": alert.Runbook, "text": "Runbook"})
+	}
+	if alert.SilenceURL != "" {
+		links = append(links, map[string]string{"href": alert.SilenceURL, "text": "Silence"})
+	}
+	if len(links) > 0 {
+		payload["links"] = links
+	}
+	return postJSON(ctx, p.client, p.url, payload)
+}
+
+// Resolve resolves the incident opened for the alert
+func (p *PagerDutyChannel) Resolve(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, p.client, p.url, map[string]interface{}{
+		"routing_key":  p.routingKey,
+		"event_action": "resolve",
+		"dedup_key":    alert.Fingerprint,
+	})
+}
+
+// OpsGenieChannel creates OpsGenie alerts through the Alert API. Alerts are
+// deduplicated by alias, closed when they resolve, and an optional heartbeat
+// lets OpsGenie page when KubePulse stops reporting.
+type OpsGenieChannel struct {
+	name       string
+	apiKey     string
+	url        string
+	priorities map[AlertSeverity]string
+	heartbeat  string
+	interval   time.Duration
+	client     *http.Client
+}
+
+// newOpsGenieChannel builds an OpsGenie channel from its settings
+func newOpsGenieChannel(name string, settings map[string]interface{}) (*OpsGenieChannel, error) {
+	apiKey := stringSetting(settings, "api_key")
+	if apiKey == "" {
+		return nil, fmt.Errorf("opsgenie channel %s needs an api_key setting", name)
+	}
+	channel := NewOpsGenieChannel(name, apiKey)
+	if endpoint := stringSetting(settings, "url"); endpoint != "" {
+		channel.url = strings.TrimSuffix(endpoint, "/")
+	}
+
+	priorities, _ := settings["priorities"].(map[string]interface{})
+	for severity, value := range priorities {
+		priority, _ := value.(string)
+		switch AlertSeverity(severity) {
+		case AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo:
+		default:
+			return nil, fmt.Errorf("opsgenie channel %s maps unknown severity %q", name, severity)
+		}
+		switch priority {
+		case "P1", "P2", "P3", "P4", "P5":
+		default:
+			return nil, fmt.Errorf("opsgenie channel %s maps %s to %q; use P1 to P5", name, severity, value)
+		}
+		channel.priorities[AlertSeverity(severity)] = priority
+	}
+
+	channel.heartbeat = stringSetting(settings, "heartbeat")
+	interval, err := heartbeatSetting(name, settings, channel.heartbeat != "")
+	if err != nil {
+		return nil, err
+	}
+	channel.interval = interval
+	return channel, nil
+}
+
+// NewOpsGenieChannel creates an OpsGenie channel for an API integration key
+func NewOpsGenieChannel(name, apiKey string) *OpsGenieChannel {
+	return &OpsGenieChannel{
+		name:   name,
+		apiKey: apiKey,
+		url:    defaultOpsGenieURL,
+		priorities: map[AlertSeverity]string{
+			AlertSeverityCritical: "P1",
+			AlertSeverityWarning:  "P3",
+			AlertSeverityInfo:     "P5",
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (o *OpsGenieChannel) Name() string {
+	return o.name
+}
+
+// Send creates an alert aliased by the alert fingerprint, so repeats update
+// the open OpsGenie alert instead of creating another
+func (o *OpsGenieChannel) Send(ctx context.Context, alert Alert) error {
+	message := fmt.Sprintf("%s: %s", alert.Name, alert.Message)
+	if len(message) > opsGenieMessageLimit {
+		message = message[:opsGenieMessageLimit-3] + "..."
+	}
+	description := fmt.Sprintf("%s\n\nFired at %s", alert.Message, alert.Timestamp.Format(time.RFC3339))
+	details := withSilenceDetails(alert)
+	if alert.Runbook != "" {
+		description += "\nRunbook: " + alert.Runbook
+		details["runbook"] = alert.Runbook
+	}
+	if alert.SilenceURL != "" {
+		description += "\nSilence: " + alert.SilenceURL
+	}
+
+	payload := map[string]interface{}{
+		"message":     message,
+		"alias":       alert.Fingerprint,
+		"description": description,
+		"priority":    o.priority(alert.Severity),
+		"source":      alert.Source,
+		"entity":      alert.Labels["check"],
+		"tags":        []string{"kubepulse", string(alert.Severity)},
+		"details":     details,
+	}
+	return sendJSON(ctx, o.client, http.MethodPost, o.url+"/v2/alerts", o.headers(), payload)
+}
+
+// Resolve closes the OpsGenie alert with the alert's alias
+func (o *OpsGenieChannel) Resolve(ctx context.Context, alert Alert) error {
+	endpoint := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", o.url, url.PathEscape(alert.Fingerprint))
+	payload := map[string]interface{}{
+		"source": alert.Source,
+		"note":   "Resolved: the condition cleared",
+	}
+	return sendJSON(ctx, o.client, http.MethodPost, endpoint, o.headers(), payload)
+}
+
+// Heartbeat pings the configured OpsGenie heartbeat
+func (o *OpsGenieChannel) Heartbeat(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/v2/heartbeats/%s/ping", o.url, url.PathEscape(o.heartbeat))
+	return sendJSON(ctx, o.client, http.MethodPost, endpoint, o.headers(), nil)
+}
+
+// HeartbeatInterval returns how often the heartbeat is pinged; zero without one
+func (o *OpsGenieChannel) HeartbeatInterval() time.Duration {
+	return o.interval
+}
+
+// priority maps an alert severity to an OpsGenie priority
+func (o *OpsGenieChannel) priority(severity AlertSeverity) string {
+	if priority, ok := o.priorities[severity]; ok {
+		return priority
+	}
+	return "P3"
+}
+
+// headers authenticate requests with the integration key
+func (o *OpsGenieChannel) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + o.apiKey}
+}
+
+// SplunkOnCallChannel sends incidents to Splunk On-Call (formerly VictorOps)
+// through the REST endpoint integration. Incidents are keyed by entity ID,
+// so repeats update the open incident and a RECOVERY message resolves it.
+// Splunk On-Call has no heartbeat API; an optional heartbeat_url, such as a
+// dead man's switch, is requested instead.
+type SplunkOnCallChannel struct {
+	name      string
+	url       string // Endpoint including the API key and routing key
+	heartbeat string
+	interval  time.Duration
+	client    *http.Client
+}
+
+// newSplunkOnCallChannel builds a Splunk On-Call channel from its settings
+func newSplunkOnCallChannel(name string, settings map[string]interface{}) (*SplunkOnCallChannel, error) {
+	apiKey := stringSetting(settings, "api_key")
+	routingKey := stringSetting(settings, "routing_key")
+	if apiKey == "" || routingKey == "" {
+		return nil, fmt.Errorf("splunk_oncall channel %s needs api_key and routing_key settings", name)
+	}
+	channel := NewSplunkOnCallChannel(name, apiKey, routingKey)
+	if endpoint := stringSetting(settings, "url"); endpoint != "" {
+		channel.url = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(endpoint, "/"), url.PathEscape(apiKey), url.PathEscape(routingKey))
+	}
+
+	channel.heartbeat = stringSetting(settings, "heartbeat_url")
+	interval, err := heartbeatSetting(name, settings, channel.heartbeat != "")
+	if err != nil {
+		return nil, err
+	}
+	channel.interval = interval
+	return channel, nil
+}
+
+// NewSplunkOnCallChannel creates a Splunk On-Call channel for a REST
+// integration API key and a routing key
+func NewSplunkOnCallChannel(name, apiKey, routingKey string) *SplunkOnCallChannel {
+	return &SplunkOnCallChannel{
+		name:   name,
+		url:    fmt.Sprintf("%s/%s/%s", defaultSplunkOnCallURL, url.PathEscape(apiKey), url.PathEscape(routingKey)),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (s *SplunkOnCallChannel) Name() string {
+	return s.name
+}
+
+// Send opens or updates the incident for the alert fingerprint
+func (s *SplunkOnCallChannel) Send(ctx context.Context, alert Alert) error {
+	payload := map[string]interface{}{
+		"message_type":        splunkMessageType(alert.Severity),
+		"entity_id":           alert.Fingerprint,
+		"entity_display_name": fmt.Sprintf("%s: %s", alert.Name, alert.Message),
+		"state_message":       alert.Message,
+		"state_start_time":    alert.Timestamp.Unix(),
+		"monitoring_tool":     "kubepulse",
+	}
+	for key, value := range alert.Labels {
+		payload["kubepulse_"+key] = value
+	}
+	if alert.Runbook != "" {
+		payload["vo_annotate.u.Runbook"] = alert.Runbook
+	}
+	if alert.SilenceURL != "" {
+		payload["vo_annotate.u.Silence"] = alert.SilenceURL
+		payload["kubepulse_silence_command"] = alert.SilenceCommand
+	}
+	return postJSON(ctx, s.client, s.url, payload)
+}
+
+// Resolve sends a RECOVERY message for the alert fingerprint
+func (s *SplunkOnCallChannel) Resolve(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, s.client, s.url, map[string]interface{}{
+		"message_type":    "RECOVERY",
+		"entity_id":       alert.Fingerprint,
+		"state_message":   "Resolved: the condition cleared",
+		"monitoring_tool": "kubepulse",
+	})
+}
+
+// Heartbeat requests the heartbeat URL
+func (s *SplunkOnCallChannel) Heartbeat(ctx context.Context) error {
+	return sendJSON(ctx, s.client, http.MethodGet, s.heartbeat, nil, nil)
+}
+
+// HeartbeatInterval returns how often the heartbeat URL is requested; zero without one
+func (s *SplunkOnCallChannel) HeartbeatInterval() time.Duration {
+	return s.interval
+}
+
+// withSilenceDetails copies an alert's labels, adding its silence link and
+// command when it has them
+func withSilenceDetails(alert Alert) map[string]string {
+	details := make(map[string]string, len(alert.Labels)+3)
+	for key, value := range alert.Labels {
+		details[key] = value
+	}
+	if alert.SilenceURL != "" {
+		details["silence_url"] = alert.SilenceURL
+		details["silence_command"] = alert.SilenceCommand
+	}
+	return details
+}
+
+// encodeMatchers packs matchers into a Slack button value
+func encodeMatchers(matchers map[string]string) string {
+	values := url.Values{}
+	for label, value := range matchers {
+		values.Set(label, value)
+	}
+	return values.Encode()
+}
+
+// splunkMessageType maps an alert severity to a Splunk On-Call message type
+func splunkMessageType(severity AlertSeverity) string {
+	switch severity {
+	case AlertSeverityCritical:
+		return "CRITICAL"
+	case AlertSeverityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
+
+// postJSON posts a JSON payload and treats any non-2xx response as an error
+func postJSON(ctx context.Context, client *http.Client, endpoint string, payload interface{}) error {
+	return sendJSON(ctx, client, http.MethodPost, endpoint, nil, payload)
+}
+
+// sendJSON sends a request with an optional JSON payload and extra headers,
+// treating any non-2xx response as an error
+func sendJSON(ctx context.Context, client *http.Client, method, endpoint string, headers map[string]string, payload interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode notification: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// VerifySlackSignature checks the X-Slack-Signature of an interaction request
+// against the Slack app's signing secret
+func VerifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid Slack request timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("stale Slack request")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":"))
+	_, _ = mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid Slack signature")
+	}
+	return nil
+}
+
+// SlackAction is an Acknowledge or Silence button click from a Slack alert
+// message
+type SlackAction struct {
+	ActionID string            // SlackAckActionID or SlackSilenceActionID
+	AlertID  string            // Alert to acknowledge
+	Matchers map[string]string // Matchers to silence
+	User     string
+}
+
+// ParseSlackAction extracts an Acknowledge or Silence click from a
+// form-encoded Slack interaction payload; ok is false for any other
+// interaction
+func ParseSlackAction(body []byte) (action SlackAction, ok bool, err error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return SlackAction{}, false, fmt.Errorf("invalid Slack interaction: %w", err)
+	}
+
+	var payload struct {
+		User struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+		Actions []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return SlackAction{}, false, fmt.Errorf("invalid Slack interaction payload: %w", err)
+	}
+
+	user := payload.User.Username
+	if user == "" {
+		user = payload.User.ID
+	}
+	for _, clicked := range payload.Actions {
+		if clicked.Value == "" {
+			continue
+		}
+		switch clicked.ActionID {
+		case SlackAckActionID:
+			return SlackAction{ActionID: SlackAckActionID, AlertID: clicked.Value, User: "slack:" + user}, true, nil
+		case SlackSilenceActionID:
+			values, err := url.ParseQuery(clicked.Value)
+			if err != nil {
+				return SlackAction{}, false, fmt.Errorf("invalid Slack silence matchers: %w", err)
+			}
+			matchers := make(map[string]string, len(values))
+			for label := range values {
+				matchers[label] = values.Get(label)
+			}
+			return SlackAction{ActionID: SlackSilenceActionID, Matchers: matchers, User: "slack:" + user}, true, nil
+		}
+	}
+	return SlackAction{}, false, nil
+}
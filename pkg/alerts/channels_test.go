@@ -0,0 +1,364 @@
+package alerts
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureServer records the JSON body of the last request it receives
+func captureServer(t *testing.T, status int) (*httptest.Server, *map[string]interface{}) {
+	t.Helper()
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+func TestSlackChannel_Send(t *testing.T) {
+	server, body := captureServer(t, http.StatusOK)
+	channel := NewSlackChannel("slack", server.URL)
+
+	alert := Alert{ID: "pod-health-critical-1", Name: "pod-health-critical", Severity: AlertSeverityCritical, Message: "5 failed",
+		Runbook: "https://runbooks.example.com/pods"}
+	if err := channel.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := json.Marshal(*body)
+	if !strings.Contains(string(data), `"action_id":"kubepulse_ack"`) || !strings.Contains(string(data), `"value":"pod-health-critical-1"`) {
+		t.Errorf("expected an Acknowledge button for the alert, got %s", data)
+	}
+	if !strings.Contains(string(data), "https://runbooks.example.com/pods|Runbook") {
+		t.Errorf("expected a runbook link in the message, got %s", data)
+	}
+	if strings.Contains(string(data), SlackSilenceActionID) {
+		t.Errorf("expected no Silence button for an alert without labels, got %s", data)
+	}
+
+	alert.Labels = map[string]string{"check": "pod-health", "rule": "pod-health-critical", "severity": "critical"}
+	alert.SilenceURL = "https://kubepulse.example.com/?silence.check=pod-health&silence.rule=pod-health-critical"
+	if err := channel.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ = json.Marshal(*body)
+	if !strings.Contains(string(data), `"action_id":"kubepulse_silence"`) || !strings.Contains(string(data), `"value":"check=pod-health\u0026rule=pod-health-critical"`) {
+		t.Errorf("expected a Silence button with the alert's matchers, got %s", data)
+	}
+	if !strings.Contains(string(data), "|Silence with other options") {
+		t.Errorf("expected a silence link in the message, got %s", data)
+	}
+}
+
+func TestSlackChannel_Post(t *testing.T) {
+	server, body := captureServer(t, http.StatusOK)
+	channel := NewSlackChannel("slack", server.URL)
+
+	if err := channel.Post(context.Background(), "*Handoff*\n• all quiet"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*body)["text"] != "*Handoff*\n• all quiet" || (*body)["mrkdwn"] != true {
+		t.Errorf("expected the text posted as mrkdwn, got %v", *body)
+	}
+
+	failing, _ := captureServer(t, http.StatusInternalServerError)
+	if err := NewSlackChannel("slack", failing.URL).Post(context.Background(), "text"); err == nil {
+		t.Error("expected an error when Slack rejects the message")
+	}
+}
+
+func TestPagerDutyChannel_Send(t *testing.T) {
+	server, body := captureServer(t, http.StatusAccepted)
+	channel, err := NewChannel("pager", "pagerduty", map[string]interface{}{"routing_key": "key", "url": server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	alert := Alert{Name: "node-health-critical", Severity: AlertSeverityCritical, Fingerprint: "node-health-critical-node-health"}
+	if err := channel.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*body)["routing_key"] != "key" || (*body)["dedup_key"] != alert.Fingerprint || (*body)["event_action"] != "trigger" {
+		t.Errorf("unexpected event %v", *body)
+	}
+	if _, ok := (*body)["links"]; ok {
+		t.Errorf("expected no links for an alert without a runbook, got %v", (*body)["links"])
+	}
+
+	alert.Runbook = "https://runbooks.example.com/nodes"
+	if err := channel.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	links, _ := (*body)["links"].([]interface{})
+	if len(links) != 1 || links[0].(map[string]interface{})["href"] != alert.Runbook {
+		t.Errorf("expected the runbook in the event links, got %v", (*body)["links"])
+	}
+
+	alert.SilenceURL = "https://kubepulse.example.com/?silence.rule=node-health-critical"
+	alert.SilenceCommand = "curl -X POST https://kubepulse.example.com/api/v1/alerts/silences"
+	if err := channel.Send(context.Background(), alert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	links, _ = (*body)["links"].([]interface{})
+	if len(links) != 2 || links[1].(map[string]interface{})["href"] != alert.SilenceURL {
+		t.Errorf("expected the silence link in the event links, got %v", (*body)["links"])
+	}
+	details := (*body)["payload"].(map[string]interface{})["custom_details"].(map[string]interface{})
+	if details["silence_command"] != alert.SilenceCommand {
+		t.Errorf("expected the silence command in the custom details, got %v", details)
+	}
+
+	failing, _ := captureServer(t, http.StatusBadRequest)
+	rejected := NewPagerDutyChannel("pager", "key")
+	rejected.url = failing.URL
+	if err := rejected.Send(context.Background(), alert); err == nil {
+		t.Error("expected an error for a rejected event")
+	}
+}
+
+func TestPagerDutyChannel_Resolve(t *testing.T) {
+	server, body := captureServer(t, http.StatusAccepted)
+	channel := NewPagerDutyChannel("pager", "key")
+	channel.url = server.URL
+
+	if err := channel.Resolve(context.Background(), Alert{Fingerprint: "node-health-critical-node-health"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*body)["event_action"] != "resolve" || (*body)["dedup_key"] != "node-health-critical-node-health" {
+		t.Errorf("unexpected event %v", *body)
+	}
+}
+
+// request is an HTTP request received by recordServer
+type request struct {
+	method string
+	path   string
+	query  string
+	header http.Header
+	body   map[string]interface{}
+}
+
+// recordServer records every request it receives
+func recordServer(t *testing.T) (*httptest.Server, *[]request) {
+	t.Helper()
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, header: r.Header}
+		_ = json.NewDecoder(r.Body).Decode(&req.body)
+		requests = append(requests, req)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestOpsGenieChannel(t *testing.T) {
+	server, requests := recordServer(t)
+	channel, err := NewChannel("opsgenie", "opsgenie", map[string]interface{}{
+		"api_key":    "genie",
+		"url":        server.URL,
+		"priorities": map[string]interface{}{"warning": "P2"},
+		"heartbeat":  "kubepulse prod",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opsgenie := channel.(*OpsGenieChannel)
+
+	alert := Alert{
+		Name:        "pod-health-warning",
+		Severity:    AlertSeverityWarning,
+		Message:     strings.Repeat("pods pending ", 20),
+		Source:      "kubepulse",
+		Fingerprint: "pod-health-warning-pod-health",
+		Labels:      map[string]string{"check": "pod-health"},
+		Runbook:     "https://runbooks.example.com/pods",
+	}
+	if err := opsgenie.Send(context.Background(), alert); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := opsgenie.Resolve(context.Background(), alert); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if err := opsgenie.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if len(*requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(*requests))
+	}
+
+	create := (*requests)[0]
+	if create.path != "/v2/alerts" || create.header.Get("Authorization") != "GenieKey genie" {
+		t.Errorf("unexpected create request %s %s", create.path, create.header.Get("Authorization"))
+	}
+	if create.body["alias"] != alert.Fingerprint || create.body["priority"] != "P2" || create.body["entity"] != "pod-health" {
+		t.Errorf("unexpected alert %v", create.body)
+	}
+	if message, _ := create.body["message"].(string); len(message) > opsGenieMessageLimit {
+		t.Errorf("expected the message truncated to %d characters, got %d", opsGenieMessageLimit, len(message))
+	}
+	if details, _ := create.body["details"].(map[string]interface{}); details["runbook"] != alert.Runbook {
+		t.Errorf("expected the runbook in the details, got %v", create.body["details"])
+	}
+
+	closeReq := (*requests)[1]
+	if closeReq.path != "/v2/alerts/pod-health-warning-pod-health/close" || closeReq.query != "identifierType=alias" {
+		t.Errorf("unexpected close request %s?%s", closeReq.path, closeReq.query)
+	}
+	if ping := (*requests)[2]; ping.path != "/v2/heartbeats/kubepulse%20prod/ping" {
+		t.Errorf("unexpected heartbeat request %s", ping.path)
+	}
+	if opsgenie.HeartbeatInterval() != defaultHeartbeatInterval {
+		t.Errorf("HeartbeatInterval() = %s, want %s", opsgenie.HeartbeatInterval(), defaultHeartbeatInterval)
+	}
+	if opsgenie.priority(AlertSeverityCritical) != "P1" {
+		t.Errorf("expected critical alerts to stay P1, got %s", opsgenie.priority(AlertSeverityCritical))
+	}
+}
+
+func TestSplunkOnCallChannel(t *testing.T) {
+	server, requests := recordServer(t)
+	channel, err := NewChannel("oncall", "splunk_oncall", map[string]interface{}{
+		"api_key":            "api",
+		"routing_key":        "platform",
+		"url":                server.URL + "/alert",
+		"heartbeat_url":      server.URL + "/ping",
+		"heartbeat_interval": "5m",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	oncall := channel.(*SplunkOnCallChannel)
+
+	alert := Alert{
+		Name:        "node-health-critical",
+		Severity:    AlertSeverityCritical,
+		Message:     "2 nodes not ready",
+		Timestamp:   time.Unix(1760000000, 0),
+		Fingerprint: "node-health-critical-node-health",
+		Runbook:     "https://runbooks.example.com/nodes",
+	}
+	if err := oncall.Send(context.Background(), alert); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := oncall.Resolve(context.Background(), alert); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if err := oncall.Heartbeat(context.Background()); err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if len(*requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(*requests))
+	}
+
+	incident := (*requests)[0]
+	if incident.path != "/alert/api/platform" {
+		t.Errorf("unexpected incident path %s", incident.path)
+	}
+	if incident.body["message_type"] != "CRITICAL" || incident.body["entity_id"] != alert.Fingerprint ||
+		incident.body["vo_annotate.u.Runbook"] != alert.Runbook || incident.body["state_start_time"] != float64(1760000000) {
+		t.Errorf("unexpected incident %v", incident.body)
+	}
+	if recovery := (*requests)[1]; recovery.body["message_type"] != "RECOVERY" || recovery.body["entity_id"] != alert.Fingerprint {
+		t.Errorf("unexpected recovery %v", recovery.body)
+	}
+	if ping := (*requests)[2]; ping.method != http.MethodGet || ping.path != "/ping" {
+		t.Errorf("unexpected heartbeat request %s %s", ping.method, ping.path)
+	}
+	if oncall.HeartbeatInterval() != 5*time.Minute {
+		t.Errorf("HeartbeatInterval() = %s, want 5m", oncall.HeartbeatInterval())
+	}
+}
+
+func TestNewChannel_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		channelType string
+		settings    map[string]interface{}
+	}{
+		{"slack without webhook", "slack", nil},
+		{"pagerduty without routing key", "pagerduty", map[string]interface{}{}},
+		{"opsgenie without api key", "opsgenie", map[string]interface{}{}},
+		{"opsgenie with bad priority", "opsgenie", map[string]interface{}{"api_key": "k", "priorities": map[string]interface{}{"critical": "urgent"}}},
+		{"opsgenie with unknown severity", "opsgenie", map[string]interface{}{"api_key": "k", "priorities": map[string]interface{}{"fatal": "P1"}}},
+		{"opsgenie with short heartbeat", "opsgenie", map[string]interface{}{"api_key": "k", "heartbeat": "hb", "heartbeat_interval": "1s"}},
+		{"splunk on-call without routing key", "splunk_oncall", map[string]interface{}{"api_key": "k"}},
+		{"unsupported type", "email", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewChannel("test", tt.channelType, tt.settings); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// signSlack signs a Slack interaction body the way Slack does
+func signSlack(secret string, body []byte, at time.Time) http.Header {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":" + string(body)))
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	body := []byte("payload=%7B%7D")
+	now := time.Now()
+
+	if err := VerifySlackSignature("secret", signSlack("secret", body, now), body, now); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+	if err := VerifySlackSignature("other", signSlack("secret", body, now), body, now); err == nil {
+		t.Error("expected a signature from another secret to fail")
+	}
+	if err := VerifySlackSignature("secret", signSlack("secret", body, now.Add(-10*time.Minute)), body, now); err == nil {
+		t.Error("expected a stale request to fail")
+	}
+}
+
+func TestParseSlackAction(t *testing.T) {
+	ackPayload := `{"user":{"id":"U1","username":"alice"},"actions":[{"action_id":"kubepulse_ack","value":"alert-1"}]}`
+	ack, ok, err := ParseSlackAction([]byte("payload=" + url.QueryEscape(ackPayload)))
+	if err != nil || !ok {
+		t.Fatalf("expected an acknowledgement, got ok=%v err=%v", ok, err)
+	}
+	if ack.ActionID != SlackAckActionID || ack.AlertID != "alert-1" || ack.User != "slack:alice" {
+		t.Errorf("unexpected acknowledgement %+v", ack)
+	}
+
+	silencePayload := `{"user":{"id":"U2"},"actions":[{"action_id":"kubepulse_silence","value":"check=pod-health&rule=pod-health-critical"}]}`
+	silence, ok, err := ParseSlackAction([]byte("payload=" + url.QueryEscape(silencePayload)))
+	if err != nil || !ok {
+		t.Fatalf("expected a silence, got ok=%v err=%v", ok, err)
+	}
+	if silence.ActionID != SlackSilenceActionID || silence.User != "slack:U2" ||
+		silence.Matchers["check"] != "pod-health" || silence.Matchers["rule"] != "pod-health-critical" {
+		t.Errorf("unexpected silence %+v", silence)
+	}
+
+	otherPayload := `{"user":{"id":"U1"},"actions":[{"action_id":"something_else","value":"x"}]}`
+	if _, ok, err := ParseSlackAction([]byte("payload=" + url.QueryEscape(otherPayload))); ok || err != nil {
+		t.Errorf("expected other actions to be ignored, got ok=%v err=%v", ok, err)
+	}
+	if _, _, err := ParseSlackAction([]byte("payload=not-json")); err == nil {
+		t.Error("expected an error for an invalid payload")
+	}
+}
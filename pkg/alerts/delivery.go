@@ -0,0 +1,484 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Delivery states
+const (
+	DeliveryPending   = "pending"   // Waiting for its next attempt
+	DeliveryDead      = "dead"      // Out of attempts; only manual redelivery retries it
+	DeliveryDelivered = "delivered" // Accepted by the channel, and dropped from the queue
+)
+
+// What a delivery asks the channel to do
+const (
+	DeliveryKindSend    = "send"
+	DeliveryKindResolve = "resolve"
+)
+
+// Delivery queue defaults
+const (
+	DefaultDeliveryInitialBackoff = 30 * time.Second
+	DefaultDeliveryMaxBackoff     = 30 * time.Minute
+	DefaultDeliveryMaxAttempts    = 12
+)
+
+// maxDeadDeliveries bounds the dead letters kept for inspection; the oldest
+// are dropped first
+const maxDeadDeliveries = 1000
+
+var (
+	// ErrDeliveryNotFound is returned when redelivering an unknown delivery
+	ErrDeliveryNotFound = errors.New("delivery not found")
+
+	// ErrDeliveryFailed is returned when a manual redelivery is rejected
+	// again; the delivery goes back to being retried automatically
+	ErrDeliveryFailed = errors.New("redelivery failed")
+)
+
+// Delivery is a notification a channel failed to accept, kept until the
+// channel accepts it or it runs out of attempts
+type Delivery struct {
+	ID          string    `json:"id"`
+	Channel     string    `json:"channel"`
+	Kind        string    `json:"kind"` // DeliveryKindSend or DeliveryKindResolve
+	Alert       Alert     `json:"alert"`
+	State       string    `json:"state"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastAttempt time.Time `json:"last_attempt,omitzero"`
+	NextAttempt time.Time `json:"next_attempt,omitzero"` // Zero once dead
+}
+
+// DeliveryConfig controls how failed notifications are retried
+type DeliveryConfig struct {
+	Path           string // Empty keeps the queue in memory only
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxAttempts    int // Including the attempt that first failed
+}
+
+// DeliveryQueue holds notifications channels failed to accept, retrying each
+// with exponential backoff until it is delivered or dead-lettered. With a
+// path the queue is written to a JSON file after every change and reloaded
+// on restart, so notifications survive KubePulse restarting too.
+type DeliveryQueue struct {
+	config DeliveryConfig
+
+	mu         sync.Mutex
+	deliveries []*Delivery // Oldest first
+	sequence   int
+	now        func() time.Time
+}
+
+// NewDeliveryQueue creates a delivery queue, loading the file at the
+// configured path when one is given
+func NewDeliveryQueue(config DeliveryConfig) (*DeliveryQueue, error) {
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultDeliveryInitialBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = max(DefaultDeliveryMaxBackoff, config.InitialBackoff)
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultDeliveryMaxAttempts
+	}
+	q := &DeliveryQueue{config: config, now: time.Now}
+	if config.Path == "" {
+		return q, nil
+	}
+
+	data, err := os.ReadFile(config.Path) // #nosec G304 - path comes from configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification queue: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &q.deliveries); err != nil {
+			return nil, fmt.Errorf("failed to parse notification queue %s: %w", config.Path, err)
+		}
+	}
+	q.sequence = len(q.deliveries)
+	return q, nil
+}
+
+// Enqueue adds a notification whose first attempt failed with err
+func (q *DeliveryQueue) Enqueue(channel, kind string, alert Alert, err error) (Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var id string
+	for id == "" || q.find(id) != nil {
+		q.sequence++
+		id = fmt.Sprintf("%s-%s-%d-%d", channel, kind, now.Unix(), q.sequence)
+	}
+	delivery := &Delivery{
+		ID:        id,
+		Channel:   channel,
+		Kind:      kind,
+		Alert:     alert,
+		CreatedAt: now,
+	}
+	q.deliveries = append(q.deliveries, delivery)
+	q.fail(delivery, err, now)
+	return *delivery, q.save()
+}
+
+// Pending reports whether a notification about an alert is still waiting to
+// reach a channel, so later notifications about it wait their turn
+func (q *DeliveryQueue) Pending(channel, fingerprint string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, delivery := range q.deliveries {
+		if delivery.State == DeliveryPending && delivery.Channel == channel && delivery.Alert.Fingerprint == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// Succeeded drops a delivery the channel accepted
+func (q *DeliveryQueue) Succeeded(id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, delivery := range q.deliveries {
+		if delivery.ID == id {
+			q.deliveries = append(q.deliveries[:i], q.deliveries[i+1:]...)
+			return q.save()
+		}
+	}
+	return nil
+}
+
+// Failed records a failed attempt, scheduling the next one or dead-lettering
+// the delivery once it is out of attempts
+func (q *DeliveryQueue) Failed(id string, err error) (Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivery := q.find(id)
+	if delivery == nil {
+		return Delivery{}, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	q.fail(delivery, err, q.now())
+	return *delivery, q.save()
+}
+
+// Retry returns a delivery to pending with its attempts reset, for a manual
+// redelivery that failed
+func (q *DeliveryQueue) Retry(id string, err error) (Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivery := q.find(id)
+	if delivery == nil {
+		return Delivery{}, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	delivery.Attempts = 0
+	q.fail(delivery, err, q.now())
+	return *delivery, q.save()
+}
+
+// Get returns a delivery by ID
+func (q *DeliveryQueue) Get(id string) (Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivery := q.find(id)
+	if delivery == nil {
+		return Delivery{}, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	return *delivery, nil
+}
+
+// List returns the deliveries in a state, or all of them when state is
+// empty, oldest first
+func (q *DeliveryQueue) List(state string) []Delivery {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deliveries := make([]Delivery, 0, len(q.deliveries))
+	for _, delivery := range q.deliveries {
+		if state == "" || delivery.State == state {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	sort.SliceStable(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries
+}
+
+// find returns the delivery with an ID; callers hold mu
+func (q *DeliveryQueue) find(id string) *Delivery {
+	for _, delivery := range q.deliveries {
+		if delivery.ID == id {
+			return delivery
+		}
+	}
+	return nil
+}
+
+// fail records a failed attempt at now; callers hold mu
+func (q *DeliveryQueue) fail(delivery *Delivery, err error, now time.Time) {
+	delivery.Attempts++
+	delivery.LastAttempt = now
+	if err != nil {
+		delivery.LastError = err.Error()
+	}
+	if delivery.Attempts >= q.config.MaxAttempts {
+		delivery.State = DeliveryDead
+		delivery.NextAttempt = time.Time{}
+		q.pruneDead()
+		return
+	}
+	delivery.State = DeliveryPending
+	delivery.NextAttempt = now.Add(q.backoff(delivery.Attempts))
+}
+
+// backoff is the wait after a delivery's nth failed attempt, doubling from
+// the initial backoff up to the maximum
+func (q *DeliveryQueue) backoff(attempts int) time.Duration {
+	backoff := q.config.InitialBackoff
+	for i := 1; i < attempts && backoff < q.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, q.config.MaxBackoff)
+}
+
+// pruneDead drops the oldest dead letters beyond maxDeadDeliveries; callers
+// hold mu
+func (q *DeliveryQueue) pruneDead() {
+	dead := 0
+	for _, delivery := range q.deliveries {
+		if delivery.State == DeliveryDead {
+			dead++
+		}
+	}
+	if dead <= maxDeadDeliveries {
+		return
+	}
+	kept := q.deliveries[:0]
+	for _, delivery := range q.deliveries {
+		if delivery.State == DeliveryDead && dead > maxDeadDeliveries {
+			dead--
+			continue
+		}
+		kept = append(kept, delivery)
+	}
+	q.deliveries = kept
+}
+
+// save atomically rewrites the queue file; callers hold mu
+func (q *DeliveryQueue) save() error {
+	if q.config.Path == "" {
+		return nil
+	}
+	dir := filepath.Dir(q.config.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory for notification queue: %w", err)
+	}
+	data, err := json.MarshalIndent(q.deliveries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".notifications-*")
+	if err != nil {
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), q.config.Path); err != nil {
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	return nil
+}
+
+// deliveryInterval is how often the queue is checked for deliveries due
+// another attempt
+const deliveryInterval = 5 * time.Second
+
+// errQueuedBehind is recorded for a notification queued without an attempt
+// because an earlier one about the same alert is still waiting
+var errQueuedBehind = errors.New("waiting for an earlier notification about the alert")
+
+// SetDeliveryQueue keeps notifications channels fail to accept for
+// redelivery instead of reporting them as errors
+func (m *Manager) SetDeliveryQueue(queue *DeliveryQueue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = queue
+}
+
+// Deliveries lists queued notifications in a state, or all of them when
+// state is empty, oldest first
+func (m *Manager) Deliveries(state string) []Delivery {
+	queue := m.deliveryQueue()
+	if queue == nil {
+		return []Delivery{}
+	}
+	return queue.List(state)
+}
+
+// queueDelivery queues a notification a channel rejected with err, or
+// returns err when there is no queue; callers hold mu
+func (m *Manager) queueDelivery(channel, kind string, alert Alert, err error) error {
+	if m.deliveries == nil {
+		return err
+	}
+	delivery, saveErr := m.deliveries.Enqueue(channel, kind, alert, err)
+	if saveErr != nil {
+		klog.Errorf("Failed to persist notification queue: %v", saveErr)
+	}
+	klog.Warningf("Notification %s of alert %s to channel %s failed, queued for redelivery: %v", kind, alert.Name, channel, err)
+	if delivery.State == DeliveryDead {
+		klog.Errorf("Notification %s of alert %s to channel %s is dead-lettered", kind, alert.Name, channel)
+	}
+	return nil
+}
+
+// RunDeliveries retries queued notifications as they come due until ctx is
+// cancelled
+func (m *Manager) RunDeliveries(ctx context.Context) {
+	if m.deliveryQueue() == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(deliveryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.ProcessDeliveries(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// ProcessDeliveries retries the queued notifications that are due, oldest
+// first. A notification waits while an earlier one about the same alert is
+// still pending for its channel, so an incident is never resolved before
+// it is opened.
+func (m *Manager) ProcessDeliveries(ctx context.Context) {
+	queue := m.deliveryQueue()
+	if queue == nil {
+		return
+	}
+
+	now := m.now()
+	blocked := make(map[string]bool)
+	for _, delivery := range queue.List(DeliveryPending) {
+		key := delivery.Channel + "/" + delivery.Alert.Fingerprint
+		if blocked[key] {
+			continue
+		}
+		if delivery.NextAttempt.After(now) {
+			blocked[key] = true
+			continue
+		}
+
+		if err := m.deliver(ctx, delivery); err != nil {
+			blocked[key] = true
+			updated, saveErr := queue.Failed(delivery.ID, err)
+			if saveErr != nil {
+				klog.Errorf("Failed to persist notification queue: %v", saveErr)
+			}
+			if updated.State == DeliveryDead {
+				klog.Errorf("Giving up on notification %s to channel %s after %d attempts: %v", delivery.ID, delivery.Channel, updated.Attempts, err)
+			} else {
+				klog.V(2).Infof("Redelivery of notification %s to channel %s failed, retrying at %s: %v", delivery.ID, delivery.Channel, updated.NextAttempt.Format(time.RFC3339), err)
+			}
+			continue
+		}
+		if err := queue.Succeeded(delivery.ID); err != nil {
+			klog.Errorf("Failed to persist notification queue: %v", err)
+		}
+		klog.Infof("Redelivered notification %s to channel %s", delivery.ID, delivery.Channel)
+	}
+}
+
+// Redeliver immediately retries a queued notification, including a dead
+// one. If the channel rejects it again it goes back to being retried with
+// its attempts reset, and the error wraps ErrDeliveryFailed.
+func (m *Manager) Redeliver(ctx context.Context, id string) (Delivery, error) {
+	queue := m.deliveryQueue()
+	if queue == nil {
+		return Delivery{}, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+	}
+	delivery, err := queue.Get(id)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	if err := m.deliver(ctx, delivery); err != nil {
+		updated, saveErr := queue.Retry(id, err)
+		if saveErr != nil {
+			klog.Errorf("Failed to persist notification queue: %v", saveErr)
+		}
+		return updated, fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	if err := queue.Succeeded(id); err != nil {
+		klog.Errorf("Failed to persist notification queue: %v", err)
+	}
+	delivery.State = DeliveryDelivered
+	delivery.Attempts++
+	delivery.LastAttempt = m.now()
+	delivery.NextAttempt = time.Time{}
+	return delivery, nil
+}
+
+// deliver sends or resolves a queued notification through its channel
+func (m *Manager) deliver(ctx context.Context, delivery Delivery) error {
+	m.mu.RLock()
+	channel, ok := m.channels[delivery.Channel]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("channel %s not found", delivery.Channel)
+	}
+
+	if delivery.Kind == DeliveryKindResolve {
+		resolving, ok := channel.(ResolvingChannel)
+		if !ok {
+			return nil
+		}
+		return resolving.Resolve(ctx, delivery.Alert)
+	}
+	if err := channel.Send(ctx, delivery.Alert); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	onDeliver := m.onDeliver
+	m.mu.RUnlock()
+	delivered(onDeliver, delivery.Channel, delivery.Alert, m.now())
+	return nil
+}
+
+// deliveryQueue returns the manager's delivery queue, or nil
+func (m *Manager) deliveryQueue() *DeliveryQueue {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.deliveries
+}
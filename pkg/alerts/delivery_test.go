@@ -0,0 +1,189 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// flakyChannel fails every send and resolve while down
+type flakyChannel struct {
+	resolvingChannel
+	down bool
+}
+
+func (f *flakyChannel) Send(ctx context.Context, alert Alert) error {
+	if f.down {
+		return errors.New("503 service unavailable")
+	}
+	return f.resolvingChannel.Send(ctx, alert)
+}
+
+func (f *flakyChannel) Resolve(ctx context.Context, alert Alert) error {
+	if f.down {
+		return errors.New("503 service unavailable")
+	}
+	return f.resolvingChannel.Resolve(ctx, alert)
+}
+
+func TestDeliveryQueue_Backoff(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	queue, err := NewDeliveryQueue(DeliveryConfig{InitialBackoff: 30 * time.Second, MaxBackoff: 2 * time.Minute, MaxAttempts: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	queue.now = func() time.Time { return now }
+
+	delivery, err := queue.Enqueue("pagerduty", DeliveryKindSend, Alert{Name: "pod-health-critical"}, errors.New("timeout"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.State != DeliveryPending || delivery.Attempts != 1 || delivery.LastError != "timeout" {
+		t.Fatalf("unexpected delivery %+v", delivery)
+	}
+
+	// 30s, 1m, then capped at 2m, and dead after the fifth attempt
+	for _, want := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute} {
+		if got := delivery.NextAttempt.Sub(now); got != want {
+			t.Errorf("after attempt %d expected a %s backoff, got %s", delivery.Attempts, want, got)
+		}
+		if delivery, err = queue.Failed(delivery.ID, errors.New("timeout")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if delivery.State != DeliveryDead || delivery.Attempts != 5 || !delivery.NextAttempt.IsZero() {
+		t.Fatalf("expected the delivery dead-lettered, got %+v", delivery)
+	}
+	if dead := queue.List(DeliveryDead); len(dead) != 1 || len(queue.List(DeliveryPending)) != 0 {
+		t.Errorf("expected one dead letter, got %+v", queue.List(""))
+	}
+
+	if _, err := queue.Failed("missing", nil); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestDeliveryQueue_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue", "notifications.json")
+	queue, err := NewDeliveryQueue(DeliveryConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := queue.Enqueue("slack", DeliveryKindSend, Alert{Name: "node-health-critical", Fingerprint: "node"}, errors.New("timeout"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := queue.Enqueue("slack", DeliveryKindSend, Alert{Name: "pod-health-critical", Fingerprint: "pod"}, errors.New("timeout"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := queue.Succeeded(first.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := NewDeliveryQueue(DeliveryConfig{Path: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deliveries := reloaded.List("")
+	if len(deliveries) != 1 || deliveries[0].ID != second.ID || deliveries[0].Alert.Name != "pod-health-critical" {
+		t.Fatalf("expected the undelivered notification to survive a restart, got %+v", deliveries)
+	}
+	if !reloaded.Pending("slack", "pod") || reloaded.Pending("slack", "node") {
+		t.Error("expected only the pod alert pending")
+	}
+	third, err := reloaded.Enqueue("slack", DeliveryKindSend, Alert{Name: "pod-health-critical"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if third.ID == second.ID {
+		t.Errorf("expected a new ID after reloading, got %s twice", third.ID)
+	}
+}
+
+func TestManager_QueuesFailedNotifications(t *testing.T) {
+	manager := NewManager()
+	oncall := &flakyChannel{resolvingChannel: resolvingChannel{name: "oncall"}, down: true}
+	manager.RegisterChannel(oncall)
+	queue, err := NewDeliveryQueue(DeliveryConfig{InitialBackoff: time.Minute, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.SetDeliveryQueue(queue)
+	manager.AddRule(AlertRule{
+		Name:      "pod-health-critical",
+		Severity:  AlertSeverityCritical,
+		Channel:   "oncall",
+		Condition: func(result CheckResult) bool { return result.Status == HealthStatusUnhealthy },
+	})
+
+	ctx := context.Background()
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("expected a failed send to be queued rather than returned, got %v", err)
+	}
+	// The resolution waits behind the undelivered alert
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pending := manager.Deliveries(DeliveryPending)
+	if len(pending) != 2 || pending[0].Kind != DeliveryKindSend || pending[1].Kind != DeliveryKindResolve {
+		t.Fatalf("expected a queued send then resolve, got %+v", pending)
+	}
+
+	// Nothing is due yet
+	manager.ProcessDeliveries(ctx)
+	if len(oncall.sent) != 0 {
+		t.Fatal("expected no redelivery before the backoff elapsed")
+	}
+
+	oncall.down = false
+	manager.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	manager.ProcessDeliveries(ctx)
+	if len(oncall.sent) != 1 || len(oncall.resolved) != 1 || len(manager.Deliveries("")) != 0 {
+		t.Fatalf("expected the alert and its resolution redelivered, got %d sent, %d resolved and %+v queued",
+			len(oncall.sent), len(oncall.resolved), manager.Deliveries(""))
+	}
+}
+
+func TestManager_Redeliver(t *testing.T) {
+	manager := NewManager()
+	oncall := &flakyChannel{resolvingChannel: resolvingChannel{name: "oncall"}, down: true}
+	manager.RegisterChannel(oncall)
+	queue, err := NewDeliveryQueue(DeliveryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.SetDeliveryQueue(queue)
+
+	ctx := context.Background()
+	if err := manager.sendAlert(ctx, Alert{Name: "pod-health-critical", Fingerprint: "pod"}, "oncall"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dead := manager.Deliveries(DeliveryDead)
+	if len(dead) != 1 {
+		t.Fatalf("expected the notification dead-lettered, got %+v", manager.Deliveries(""))
+	}
+
+	if _, err := manager.Redeliver(ctx, "missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+	if _, err := manager.Redeliver(ctx, dead[0].ID); !errors.Is(err, ErrDeliveryFailed) {
+		t.Errorf("expected ErrDeliveryFailed while the channel is down, got %v", err)
+	}
+
+	oncall.down = false
+	delivery, err := manager.Redeliver(ctx, dead[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.State != DeliveryDelivered || len(oncall.sent) != 1 || len(manager.Deliveries("")) != 0 {
+		t.Errorf("expected the notification delivered and dropped, got %+v", delivery)
+	}
+
+	// Without a queue there is nothing to redeliver
+	if _, err := NewManager().Redeliver(ctx, dead[0].ID); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound without a queue, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Environment adjusts alerting to how much the monitored cluster matters,
+// so the same failing check pages for production but only posts for
+// development. The zero value changes nothing.
+type Environment struct {
+	Name           string        `json:"name"`                      // prod, staging, dev or a custom class; labels alerts
+	MaxSeverity    AlertSeverity `json:"max_severity,omitempty"`    // Alerts above it are lowered to it
+	Channel        string        `json:"channel,omitempty"`         // Replaces rules' channels; routes by owner still win
+	CooldownFactor float64       `json:"cooldown_factor,omitempty"` // Multiplies rules' cooldowns; 1 when zero
+	Escalate       bool          `json:"escalate"`                  // Whether escalation policies apply; ignored without a name
+}
+
+// Validate checks the severity cap and cooldown factor
+func (e Environment) Validate() error {
+	switch e.MaxSeverity {
+	case "", AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo:
+	default:
+		return fmt.Errorf("environment %s: invalid max severity %q", e.Name, e.MaxSeverity)
+	}
+	if e.CooldownFactor < 0 {
+		return fmt.Errorf("environment %s: cooldown factor must not be negative", e.Name)
+	}
+	return nil
+}
+
+// inEnvironment returns a rule as it runs in the environment; the channel
+// is only replaced by a registered one. Callers hold mu.
+func (m *Manager) inEnvironment(rule AlertRule) AlertRule {
+	env := m.environment
+	if env.MaxSeverity != "" && severityRank(rule.Severity) > severityRank(env.MaxSeverity) {
+		rule.Severity = env.MaxSeverity
+	}
+	if _, ok := m.channels[env.Channel]; ok {
+		rule.Channel = env.Channel
+	}
+	if env.CooldownFactor > 0 {
+		rule.Cooldown = time.Duration(float64(rule.Cooldown) * env.CooldownFactor)
+	}
+	return rule
+}
+
+// escalates reports whether escalation policies apply
+func (e Environment) escalates() bool {
+	return e.Name == "" || e.Escalate
+}
+
+// SetEnvironment sets the environment of the monitored cluster
+func (m *Manager) SetEnvironment(env Environment) error {
+	if err := env.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.environment = env
+	return nil
+}
+
+// Environment returns the environment of the monitored cluster
+func (m *Manager) Environment() Environment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.environment
+}
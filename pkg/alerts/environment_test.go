@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManager_Environment(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      Environment
+		channel  string
+		severity AlertSeverity
+		policy   string
+		cooldown time.Duration
+	}{
+		{"unclassified", Environment{}, "slack", AlertSeverityCritical, "critical-pages", 5 * time.Minute},
+		{"prod pages", Environment{Name: "prod", MaxSeverity: AlertSeverityCritical, CooldownFactor: 1, Escalate: true}, "slack", AlertSeverityCritical, "critical-pages", 5 * time.Minute},
+		{"dev only posts", Environment{Name: "dev", MaxSeverity: AlertSeverityWarning, Channel: "slack", CooldownFactor: 4}, "slack", AlertSeverityWarning, "", 20 * time.Minute},
+		{"unregistered channel", Environment{Name: "dev", Channel: "email", CooldownFactor: 4, Escalate: true}, "", AlertSeverityCritical, "critical-pages", 20 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, slack, pager, _ := newEscalationManager(t)
+			if err := manager.SetEnvironment(tt.env); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ctx := context.Background()
+			if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sent := slack.sentAlert
+			if sent == nil || pager.sentAlert != nil {
+				t.Fatalf("expected the alert sent to slack only, got %+v and %+v", slack.sentAlert, pager.sentAlert)
+			}
+			if sent.Severity != tt.severity || sent.Labels["severity"] != string(tt.severity) || sent.Escalation != tt.policy {
+				t.Errorf("expected a %s alert escalated by %q, got %+v", tt.severity, tt.policy, sent)
+			}
+			if sent.Labels["environment"] != tt.env.Name {
+				t.Errorf("expected environment label %q, got %q", tt.env.Name, sent.Labels["environment"])
+			}
+
+			// Repeats are held back for the environment's cooldown
+			for _, elapsed := range []time.Duration{tt.cooldown - time.Minute, tt.cooldown} {
+				slack.sentAlert = nil
+				manager.rules[0].LastFired = time.Now().Add(-elapsed)
+				if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if fired := slack.sentAlert != nil; fired != (elapsed >= tt.cooldown) {
+					t.Errorf("after %s: expected fired %v, got %v", elapsed, elapsed >= tt.cooldown, fired)
+				}
+			}
+		})
+	}
+
+	if err := NewManager().SetEnvironment(Environment{Name: "dev", MaxSeverity: "page"}); err == nil {
+		t.Error("expected an unknown severity refused")
+	}
+}
@@ -0,0 +1,357 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrAlertNotFound is returned when acknowledging an unknown alert
+var ErrAlertNotFound = errors.New("alert not found")
+
+// escalationRetention is how long an alert that ran through every step stays
+// listed while waiting to be acknowledged
+const escalationRetention = 24 * time.Hour
+
+// quietHoursBypassNone lets no alert through during quiet hours
+const quietHoursBypassNone AlertSeverity = "none"
+
+// QuietHours suppresses a channel's notifications during a daily window.
+// Alerts at or above the Bypass severity, critical by default, still go out;
+// a Bypass of "none" holds back every alert.
+type QuietHours struct {
+	Start    string        `json:"start"`              // "22:00"
+	End      string        `json:"end"`                // "07:00"; may wrap past midnight
+	Timezone string        `json:"timezone,omitempty"` // IANA name; defaults to local time
+	Bypass   AlertSeverity `json:"bypass,omitempty"`
+}
+
+// Validate checks the window bounds, timezone and bypass severity
+func (q QuietHours) Validate() error {
+	if _, err := parseClock(q.Start); err != nil {
+		return fmt.Errorf("invalid quiet hours start: %w", err)
+	}
+	if _, err := parseClock(q.End); err != nil {
+		return fmt.Errorf("invalid quiet hours end: %w", err)
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("invalid quiet hours timezone %q: %w", q.Timezone, err)
+	}
+	switch q.Bypass {
+	case "", quietHoursBypassNone, AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo:
+	default:
+		return fmt.Errorf("invalid quiet hours bypass severity %q", q.Bypass)
+	}
+	return nil
+}
+
+// Active reports whether now falls inside the window
+func (q QuietHours) Active(now time.Time) bool {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	if location, err := time.LoadLocation(q.Timezone); err == nil {
+		now = now.In(location)
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Suppresses reports whether the window holds back an alert at now
+func (q QuietHours) Suppresses(alert Alert, now time.Time) bool {
+	if !q.Active(now) {
+		return false
+	}
+	switch q.Bypass {
+	case quietHoursBypassNone:
+		return true
+	case "":
+		return alert.Severity != AlertSeverityCritical
+	default:
+		return severityRank(alert.Severity) < severityRank(q.Bypass)
+	}
+}
+
+// parseClock converts "HH:MM" to minutes after midnight
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// severityRank orders severities from least to most urgent
+func severityRank(severity AlertSeverity) int {
+	switch severity {
+	case AlertSeverityCritical:
+		return 2
+	case AlertSeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EscalationStep notifies a channel once an alert has gone unacknowledged
+// for After, measured from when it fired
+type EscalationStep struct {
+	Channel string        `json:"channel"`
+	After   time.Duration `json:"after"`
+}
+
+// EscalationPolicy is a chain of channels tried until an alert is
+// acknowledged. Rules select a policy by name; rules without one use the
+// first policy, by name, that lists the alert's severity.
+type EscalationPolicy struct {
+	Name       string           `json:"name"`
+	Severities []AlertSeverity  `json:"severities,omitempty"`
+	Steps      []EscalationStep `json:"steps"`
+}
+
+// Validate checks that the policy starts immediately and its steps are ordered
+func (p EscalationPolicy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("escalation policy name is required")
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("escalation policy %s has no steps", p.Name)
+	}
+	for i, step := range p.Steps {
+		if step.Channel == "" {
+			return fmt.Errorf("escalation policy %s step %d has no channel", p.Name, i+1)
+		}
+		if i == 0 && step.After != 0 {
+			return fmt.Errorf("escalation policy %s must notify its first channel immediately", p.Name)
+		}
+		if i > 0 && step.After <= p.Steps[i-1].After {
+			return fmt.Errorf("escalation policy %s step %d must come after step %d", p.Name, i+1, i)
+		}
+	}
+	for _, severity := range p.Severities {
+		switch severity {
+		case AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo:
+		default:
+			return fmt.Errorf("invalid severity %q in escalation policy %s", severity, p.Name)
+		}
+	}
+	return nil
+}
+
+// covers reports whether the policy applies to alerts of a severity by default
+func (p EscalationPolicy) covers(severity AlertSeverity) bool {
+	for _, s := range p.Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification records one escalation step being delivered or held back
+type Notification struct {
+	Channel    string    `json:"channel"`
+	At         time.Time `json:"at"`
+	Suppressed bool      `json:"suppressed,omitempty"` // Held back by quiet hours
+}
+
+// EscalationState describes an unacknowledged alert moving through a policy
+type EscalationState struct {
+	AlertID       string         `json:"alert_id"`
+	Policy        string         `json:"policy"`
+	Started       time.Time      `json:"started"`
+	Notifications []Notification `json:"notifications"`
+	NextChannel   string         `json:"next_channel,omitempty"` // Empty once every step has run
+	NextAt        *time.Time     `json:"next_at,omitempty"`
+}
+
+// escalation tracks the progress of one alert through its policy
+type escalation struct {
+	alert         Alert
+	policy        EscalationPolicy
+	started       time.Time
+	next          int
+	notifications []Notification
+}
+
+// SetQuietHours sets or replaces a channel's quiet hours
+func (m *Manager) SetQuietHours(channel string, quiet QuietHours) error {
+	if err := quiet.Validate(); err != nil {
+		return fmt.Errorf("channel %s: %w", channel, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quietHours[channel] = quiet
+	return nil
+}
+
+// AddEscalationPolicy adds a policy or replaces the one with the same name
+func (m *Manager) AddEscalationPolicy(policy EscalationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[policy.Name] = policy
+	return nil
+}
+
+// EscalationPolicies lists the configured policies by name
+func (m *Manager) EscalationPolicies() []EscalationPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	policies := make([]EscalationPolicy, 0, len(m.policies))
+	for _, policy := range m.policies {
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// policyFor returns the escalation policy for a rule's alerts, if any.
+// Environments that don't escalate send alerts to the rule's channel, when
+// it has one.
+func (m *Manager) policyFor(rule AlertRule) (EscalationPolicy, bool, error) {
+	if !m.environment.escalates() && rule.Channel != "" {
+		return EscalationPolicy{}, false, nil
+	}
+	if rule.Escalation != "" {
+		policy, ok := m.policies[rule.Escalation]
+		if !ok {
+			return EscalationPolicy{}, false, fmt.Errorf("escalation policy %s not found", rule.Escalation)
+		}
+		return policy, true, nil
+	}
+
+	names := make([]string, 0, len(m.policies))
+	for name := range m.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if m.policies[name].covers(rule.Severity) {
+			return m.policies[name], true, nil
+		}
+	}
+	return EscalationPolicy{}, false, nil
+}
+
+// startEscalation notifies a policy's first step and tracks the alert until
+// it is acknowledged or every step has run
+func (m *Manager) startEscalation(ctx context.Context, alert Alert, policy EscalationPolicy) error {
+	esc := &escalation{alert: alert, policy: policy, started: m.now()}
+	m.escalations[alert.ID] = esc
+	return m.advance(ctx, esc)
+}
+
+// advance sends every step of an escalation that is due
+func (m *Manager) advance(ctx context.Context, esc *escalation) error {
+	now := m.now()
+	for esc.next < len(esc.policy.Steps) {
+		step := esc.policy.Steps[esc.next]
+		if now.Sub(esc.started) < step.After {
+			return nil
+		}
+		esc.next++
+
+		suppressed, err := m.notify(ctx, esc.alert, step.Channel)
+		if err != nil {
+			return fmt.Errorf("escalation %s step %d: %w", esc.policy.Name, esc.next, err)
+		}
+		esc.notifications = append(esc.notifications, Notification{Channel: step.Channel, At: now, Suppressed: suppressed})
+		if !suppressed {
+			m.trackOpen(esc.alert, step.Channel)
+		}
+	}
+	return nil
+}
+
+// ProcessEscalations notifies the next channel of every unacknowledged alert
+// whose escalation step is due; the engine calls it after each check run
+func (m *Manager) ProcessEscalations(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []string
+	for id, esc := range m.escalations {
+		if esc.next >= len(esc.policy.Steps) && m.now().Sub(esc.started) > escalationRetention {
+			delete(m.escalations, id)
+			continue
+		}
+		if err := m.advance(ctx, esc); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to escalate alerts: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Escalations lists alerts still being escalated, oldest first
+func (m *Manager) Escalations() []EscalationState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make([]EscalationState, 0, len(m.escalations))
+	for _, esc := range m.escalations {
+		state := EscalationState{
+			AlertID:       esc.alert.ID,
+			Policy:        esc.policy.Name,
+			Started:       esc.started,
+			Notifications: append([]Notification{}, esc.notifications...),
+		}
+		if esc.next < len(esc.policy.Steps) {
+			step := esc.policy.Steps[esc.next]
+			nextAt := esc.started.Add(step.After)
+			state.NextChannel = step.Channel
+			state.NextAt = &nextAt
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Started.Before(states[j].Started)
+	})
+	return states
+}
+
+// Acknowledge marks an alert as handled and stops its escalation.
+// Acknowledging an already acknowledged alert returns it unchanged.
+func (m *Manager) Acknowledge(id, by string) (Alert, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.history) - 1; i >= 0; i-- {
+		alert := &m.history[i]
+		if alert.ID != id {
+			continue
+		}
+		if alert.AcknowledgedAt == nil {
+			now := m.now()
+			alert.Status = AlertStatusAcknowledged
+			alert.AcknowledgedAt = &now
+			alert.AcknowledgedBy = by
+		}
+		delete(m.escalations, id)
+		return *alert, nil
+	}
+	return Alert{}, fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+}
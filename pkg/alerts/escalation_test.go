@@ -0,0 +1,243 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuietHours_Suppresses(t *testing.T) {
+	night := QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}
+	day := QuietHours{Start: "09:00", End: "17:00", Timezone: "America/New_York", Bypass: AlertSeverityWarning}
+	silent := QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC", Bypass: "none"}
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		quiet    QuietHours
+		now      time.Time
+		severity AlertSeverity
+		want     bool
+	}{
+		{"before midnight", night, at(23, 30), AlertSeverityWarning, true},
+		{"after midnight", night, at(6, 59), AlertSeverityInfo, true},
+		{"window end is exclusive", night, at(7, 0), AlertSeverityWarning, false},
+		{"daytime", night, at(12, 0), AlertSeverityWarning, false},
+		{"critical bypasses by default", night, at(2, 0), AlertSeverityCritical, false},
+		{"none holds back critical", silent, at(2, 0), AlertSeverityCritical, true},
+		{"timezone is applied", day, at(14, 0), AlertSeverityInfo, true}, // 10:00 in New York
+		{"outside window in timezone", day, at(22, 0), AlertSeverityInfo, false},
+		{"bypass severity goes out", day, at(14, 0), AlertSeverityWarning, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.Suppresses(Alert{Severity: tt.severity}, tt.now); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		quiet QuietHours
+	}{
+		{"bad start", QuietHours{Start: "10pm", End: "07:00"}},
+		{"bad end", QuietHours{Start: "22:00", End: "25:00"}},
+		{"bad timezone", QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}},
+		{"bad bypass", QuietHours{Start: "22:00", End: "07:00", Bypass: "urgent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.quiet.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestEscalationPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  EscalationPolicy
+		wantErr bool
+	}{
+		{"valid", EscalationPolicy{Name: "pages", Steps: []EscalationStep{{Channel: "slack"}, {Channel: "pagerduty", After: 15 * time.Minute}}}, false},
+		{"no steps", EscalationPolicy{Name: "pages"}, true},
+		{"delayed first step", EscalationPolicy{Name: "pages", Steps: []EscalationStep{{Channel: "slack", After: time.Minute}}}, true},
+		{"unordered steps", EscalationPolicy{Name: "pages", Steps: []EscalationStep{{Channel: "slack"}, {Channel: "pagerduty"}}}, true},
+		{"bad severity", EscalationPolicy{Name: "pages", Severities: []AlertSeverity{"page"}, Steps: []EscalationStep{{Channel: "slack"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// newEscalationManager returns a manager paging slack then pagerduty for
+// critical alerts, with a controllable clock
+func newEscalationManager(t *testing.T) (*Manager, *mockNotificationChannel, *mockNotificationChannel, *time.Time) {
+	t.Helper()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	manager := NewManager()
+	manager.now = func() time.Time { return now }
+
+	slack := &mockNotificationChannel{name: "slack"}
+	pager := &mockNotificationChannel{name: "pagerduty"}
+	manager.RegisterChannel(slack)
+	manager.RegisterChannel(pager)
+	if err := manager.AddEscalationPolicy(EscalationPolicy{
+		Name:       "critical-pages",
+		Severities: []AlertSeverity{AlertSeverityCritical},
+		Steps:      []EscalationStep{{Channel: "slack"}, {Channel: "pagerduty", After: 15 * time.Minute}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, rule := range CreateDefaultRules() {
+		manager.AddRule(rule)
+	}
+	return manager, slack, pager, &now
+}
+
+func TestManager_Escalation(t *testing.T) {
+	manager, slack, pager, now := newEscalationManager(t)
+	ctx := context.Background()
+
+	err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "5 failed"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slack.sentAlert == nil || pager.sentAlert != nil {
+		t.Fatal("expected only slack to be notified when the alert fires")
+	}
+	if slack.sentAlert.Escalation != "critical-pages" {
+		t.Errorf("expected alert to carry its policy, got %q", slack.sentAlert.Escalation)
+	}
+
+	*now = now.Add(10 * time.Minute)
+	if err := manager.ProcessEscalations(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pager.sentAlert != nil {
+		t.Fatal("expected no page before the step delay")
+	}
+
+	*now = now.Add(6 * time.Minute)
+	if err := manager.ProcessEscalations(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pager.sentAlert == nil {
+		t.Fatal("expected pagerduty to be paged after 15 minutes")
+	}
+
+	states := manager.Escalations()
+	if len(states) != 1 || len(states[0].Notifications) != 2 || states[0].NextAt != nil {
+		t.Errorf("expected a fully escalated alert, got %+v", states)
+	}
+
+	alert, err := manager.Acknowledge(slack.sentAlert.ID, "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alert.Status != AlertStatusAcknowledged || alert.AcknowledgedBy != "alice" || alert.AcknowledgedAt == nil {
+		t.Errorf("expected acknowledged alert, got %+v", alert)
+	}
+	if len(manager.Escalations()) != 0 {
+		t.Error("expected acknowledgement to stop the escalation")
+	}
+	if history := manager.GetHistory(1); history[0].Status != AlertStatusAcknowledged {
+		t.Errorf("expected history to record the acknowledgement, got %s", history[0].Status)
+	}
+
+	again, err := manager.Acknowledge(alert.ID, "bob")
+	if err != nil || again.AcknowledgedBy != "alice" {
+		t.Errorf("expected the first acknowledgement to stick, got %+v (%v)", again, err)
+	}
+	if _, err := manager.Acknowledge("missing", "bob"); !errors.Is(err, ErrAlertNotFound) {
+		t.Errorf("expected ErrAlertNotFound, got %v", err)
+	}
+}
+
+func TestManager_EscalationAcknowledgedEarly(t *testing.T) {
+	manager, slack, pager, now := newEscalationManager(t)
+	ctx := context.Background()
+
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "node-health", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.Acknowledge(slack.sentAlert.ID, "api"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	*now = now.Add(time.Hour)
+	if err := manager.ProcessEscalations(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pager.sentAlert != nil {
+		t.Error("expected no page for an acknowledged alert")
+	}
+}
+
+func TestManager_QuietHours(t *testing.T) {
+	manager, slack, pager, now := newEscalationManager(t)
+	*now = time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	if err := manager.SetQuietHours("slack", QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC", Bypass: "none"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.SetQuietHours("log", QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	log := &mockNotificationChannel{name: "log"}
+	manager.RegisterChannel(log)
+	ctx := context.Background()
+
+	// Warnings route to the rule's channel, which is quiet at night
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusDegraded}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.sentAlert != nil {
+		t.Error("expected the warning to be held back")
+	}
+
+	// Critical alerts skip quiet slack but still page
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slack.sentAlert != nil {
+		t.Error("expected slack to stay quiet")
+	}
+	states := manager.Escalations()
+	if len(states) != 1 || !states[0].Notifications[0].Suppressed {
+		t.Fatalf("expected a suppressed first step, got %+v", states)
+	}
+
+	*now = now.Add(15 * time.Minute)
+	if err := manager.ProcessEscalations(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pager.sentAlert == nil {
+		t.Error("expected the page to go out during quiet hours")
+	}
+}
+
+func TestManager_UnknownEscalationPolicy(t *testing.T) {
+	manager := NewManager()
+	manager.AddRule(AlertRule{
+		Name:       "custom",
+		Condition:  func(CheckResult) bool { return true },
+		Severity:   AlertSeverityWarning,
+		Escalation: "missing",
+	})
+	if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: "any"}); err == nil {
+		t.Error("expected an error for an unknown escalation policy")
+	}
+}
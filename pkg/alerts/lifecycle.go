@@ -0,0 +1,137 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ResolvingChannel is a channel that closes the incidents it opened once
+// the alert's condition clears
+type ResolvingChannel interface {
+	NotificationChannel
+	Resolve(ctx context.Context, alert Alert) error
+}
+
+// HeartbeatChannel is a channel that tells the on-call service KubePulse is
+// alive, so the service can page when KubePulse itself goes quiet
+type HeartbeatChannel interface {
+	NotificationChannel
+	Heartbeat(ctx context.Context) error
+	HeartbeatInterval() time.Duration // Zero disables heartbeats
+}
+
+// openAlert is an alert delivered to channels and not yet resolved
+type openAlert struct {
+	alert    Alert
+	channels map[string]bool
+}
+
+// trackOpen remembers that an alert reached a channel, so the channel can
+// be told when the alert resolves; callers hold mu
+func (m *Manager) trackOpen(alert Alert, channel string) {
+	entry, ok := m.open[alert.Fingerprint]
+	if !ok {
+		entry = &openAlert{channels: make(map[string]bool)}
+		m.open[alert.Fingerprint] = entry
+	}
+	entry.alert = alert
+	entry.channels[channel] = true
+}
+
+// resolve closes the open alert with a fingerprint: it is marked resolved
+// in history, its escalation stops, and every channel it reached that can
+// resolve incidents is told; callers hold mu
+func (m *Manager) resolve(ctx context.Context, fingerprint string) error {
+	entry, ok := m.open[fingerprint]
+	if !ok {
+		return nil
+	}
+	delete(m.open, fingerprint)
+
+	now := m.now()
+	for i := range m.history {
+		alert := &m.history[i]
+		if alert.Fingerprint == fingerprint && alert.Status != AlertStatusResolved {
+			alert.Status = AlertStatusResolved
+			alert.ResolvedAt = &now
+		}
+	}
+	for id, esc := range m.escalations {
+		if esc.alert.Fingerprint == fingerprint {
+			delete(m.escalations, id)
+		}
+	}
+
+	resolved := entry.alert
+	resolved.Status = AlertStatusResolved
+	resolved.ResolvedAt = &now
+
+	names := make([]string, 0, len(entry.channels))
+	for name := range entry.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, name := range names {
+		channel, ok := m.channels[name].(ResolvingChannel)
+		if !ok {
+			continue
+		}
+		// Don't overtake a notification about the alert still queued for the channel
+		if m.deliveries != nil && m.deliveries.Pending(name, fingerprint) {
+			if err := m.queueDelivery(name, DeliveryKindResolve, resolved, errQueuedBehind); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			}
+			continue
+		}
+		if err := channel.Resolve(ctx, resolved); err != nil {
+			if err := m.queueDelivery(name, DeliveryKindResolve, resolved, err); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to resolve alert %s: %s", fingerprint, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// RunHeartbeats sends every heartbeat channel's heartbeat on its interval
+// until ctx is cancelled; the first goes out immediately
+func (m *Manager) RunHeartbeats(ctx context.Context) {
+	m.mu.RLock()
+	var channels []HeartbeatChannel
+	for _, channel := range m.channels {
+		if heartbeat, ok := channel.(HeartbeatChannel); ok && heartbeat.HeartbeatInterval() > 0 {
+			channels = append(channels, heartbeat)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, channel := range channels {
+		go runHeartbeat(ctx, channel)
+	}
+}
+
+// runHeartbeat sends a channel's heartbeat on its interval
+func runHeartbeat(ctx context.Context, channel HeartbeatChannel) {
+	ticker := time.NewTicker(channel.HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		if err := channel.Heartbeat(ctx); err != nil {
+			klog.Warningf("Heartbeat to alert channel %s failed: %v", channel.Name(), err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
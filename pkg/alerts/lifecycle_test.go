@@ -0,0 +1,155 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resolvingChannel records the alerts it sends and resolves
+type resolvingChannel struct {
+	name     string
+	sent     []Alert
+	resolved []Alert
+}
+
+func (r *resolvingChannel) Name() string { return r.name }
+
+func (r *resolvingChannel) Send(ctx context.Context, alert Alert) error {
+	r.sent = append(r.sent, alert)
+	return nil
+}
+
+func (r *resolvingChannel) Resolve(ctx context.Context, alert Alert) error {
+	r.resolved = append(r.resolved, alert)
+	return nil
+}
+
+func TestManager_ResolvesAlerts(t *testing.T) {
+	manager := NewManager()
+	oncall := &resolvingChannel{name: "oncall"}
+	manager.RegisterChannel(oncall)
+	manager.RegisterChannel(&mockNotificationChannel{name: "chat"})
+	manager.AddRule(AlertRule{
+		Name:      "pod-health-critical",
+		Severity:  AlertSeverityCritical,
+		Channel:   "oncall",
+		Condition: func(result CheckResult) bool { return result.Status == HealthStatusUnhealthy },
+	})
+
+	ctx := context.Background()
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(oncall.resolved) != 0 {
+		t.Fatalf("expected nothing to resolve before an alert fired, got %v", oncall.resolved)
+	}
+
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Another check recovering doesn't resolve pod-health's alert
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "node-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(oncall.sent) != 1 || len(oncall.resolved) != 0 {
+		t.Fatalf("expected one open alert, got %d sent and %d resolved", len(oncall.sent), len(oncall.resolved))
+	}
+
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(oncall.resolved) != 1 || oncall.resolved[0].Fingerprint != oncall.sent[0].Fingerprint {
+		t.Fatalf("expected the alert to be resolved, got %+v", oncall.resolved)
+	}
+	if oncall.resolved[0].Status != AlertStatusResolved || oncall.resolved[0].ResolvedAt == nil {
+		t.Errorf("expected a resolved alert, got %+v", oncall.resolved[0])
+	}
+	if history := manager.GetHistory(1); history[0].Status != AlertStatusResolved {
+		t.Errorf("expected the alert resolved in history, got %s", history[0].Status)
+	}
+
+	// Resolution happens once
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(oncall.resolved) != 1 {
+		t.Errorf("expected a single resolution, got %d", len(oncall.resolved))
+	}
+}
+
+func TestManager_ResolveStopsEscalation(t *testing.T) {
+	manager := NewManager()
+	now := time.Now()
+	manager.now = func() time.Time { return now }
+	first := &resolvingChannel{name: "first"}
+	manager.RegisterChannel(first)
+	manager.RegisterChannel(&resolvingChannel{name: "second"})
+	if err := manager.AddEscalationPolicy(EscalationPolicy{
+		Name:  "critical",
+		Steps: []EscalationStep{{Channel: "first"}, {Channel: "second", After: 10 * time.Minute}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.AddRule(AlertRule{
+		Name:       "pod-health-critical",
+		Severity:   AlertSeverityCritical,
+		Escalation: "critical",
+		Condition:  func(result CheckResult) bool { return result.Status == HealthStatusUnhealthy },
+	})
+
+	ctx := context.Background()
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.ProcessCheckResult(ctx, CheckResult{Name: "pod-health", Status: HealthStatusHealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.resolved) != 1 {
+		t.Errorf("expected the first step's channel to be told, got %d resolutions", len(first.resolved))
+	}
+	if escalations := manager.Escalations(); len(escalations) != 0 {
+		t.Errorf("expected the escalation to stop, got %+v", escalations)
+	}
+}
+
+// heartbeatChannel counts its heartbeats
+type heartbeatChannel struct {
+	mockNotificationChannel
+	mu    sync.Mutex
+	beats int
+}
+
+func (h *heartbeatChannel) Heartbeat(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beats++
+	return nil
+}
+
+func (h *heartbeatChannel) HeartbeatInterval() time.Duration { return time.Hour }
+
+func (h *heartbeatChannel) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.beats
+}
+
+func TestManager_RunHeartbeats(t *testing.T) {
+	manager := NewManager()
+	channel := &heartbeatChannel{mockNotificationChannel: mockNotificationChannel{name: "opsgenie"}}
+	manager.RegisterChannel(channel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager.RunHeartbeats(ctx)
+
+	deadline := time.Now().Add(time.Second)
+	for channel.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if channel.count() != 1 {
+		t.Errorf("expected an immediate heartbeat, got %d", channel.count())
+	}
+}
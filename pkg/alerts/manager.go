@@ -3,18 +3,37 @@ package alerts
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	"k8s.io/klog/v2"
 )
 
 // Manager handles alert routing, deduplication, and persistence
 type Manager struct {
 	channels   map[string]NotificationChannel
 	rules      []AlertRule
-	silences   map[string]time.Time
+	silences   map[string]time.Time // Keyed by alert fingerprint
 	history    []Alert
 	mu         sync.RWMutex
 	maxHistory int
+
+	quietHours  map[string]QuietHours // Keyed by channel name
+	policies    map[string]EscalationPolicy
+	escalations map[string]*escalation // Unacknowledged alerts, keyed by alert ID
+	open        map[string]*openAlert  // Delivered, unresolved alerts, keyed by fingerprint
+	deliveries  *DeliveryQueue         // Notifications channels failed to accept; nil drops them
+
+	matcherSilences map[string]Silence // Keyed by silence ID
+	silenceSequence int
+	externalURL     string // Base of silence links in notifications; empty omits them
+	onFire          func(Alert)
+	onDeliver       func(channel string, alert Alert, at time.Time)
+	routes          []Route // Tried in order before a rule's channel
+	environment     Environment
+
+	now func() time.Time
 }
 
 // NotificationChannel interface for alert delivery
@@ -32,6 +51,22 @@ type AlertRule struct {
 	LastFired time.Time
 	Channel   string
 	Template  string
+
+	// Escalation names the policy that notifies channels until the alert
+	// is acknowledged; when set it is used instead of Channel
+	Escalation string
+
+	// Runbook links the rule's alerts to response instructions, overriding
+	// the runbook of the check that triggered them
+	Runbook string
+
+	// Declarative description of what the rule matches, used to list,
+	// tune and rebuild rules; see RuleSpec
+	Check     string
+	Status    HealthStatus
+	Reason    string
+	Threshold float64
+	Added     time.Time
 }
 
 // NewManager creates a new alert manager
@@ -42,6 +77,38 @@ func NewManager() *Manager {
 		silences:   make(map[string]time.Time),
 		history:    make([]Alert, 0),
 		maxHistory: 1000,
+
+		quietHours:  make(map[string]QuietHours),
+		policies:    make(map[string]EscalationPolicy),
+		escalations: make(map[string]*escalation),
+		open:        make(map[string]*openAlert),
+		now:         time.Now,
+
+		matcherSilences: make(map[string]Silence),
+	}
+}
+
+// OnFire sets a function called with every alert a rule fires. It runs
+// with the manager locked, so it must not call back into the manager.
+func (m *Manager) OnFire(fn func(Alert)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onFire = fn
+}
+
+// OnDeliver sets a function called whenever a channel accepts an alert,
+// on its first attempt or a redelivery. It may run with the manager locked,
+// so it must not call back into the manager.
+func (m *Manager) OnDeliver(fn func(channel string, alert Alert, at time.Time)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onDeliver = fn
+}
+
+// delivered reports an alert a channel accepted to the OnDeliver function
+func delivered(fn func(string, Alert, time.Time), channel string, alert Alert, at time.Time) {
+	if fn != nil {
+		fn(channel, alert, at)
 	}
 }
 
@@ -56,37 +123,84 @@ func (m *Manager) RegisterChannel(channel NotificationChannel) {
 func (m *Manager) AddRule(rule AlertRule) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if rule.Added.IsZero() {
+		rule.Added = time.Now()
+	}
 	m.rules = append(m.rules, rule)
 }
 
-// ProcessCheckResult processes a check result and generates alerts
+// ProcessCheckResult processes a check result, generating alerts for rules
+// it matches and resolving open alerts of rules it no longer matches
 func (m *Manager) ProcessCheckResult(ctx context.Context, result CheckResult) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for i, rule := range m.rules {
-		if rule.Condition(result) && m.shouldFire(rule) {
+		rule = m.inEnvironment(rule)
+		if !rule.Condition(result) {
+			if err := m.resolve(ctx, m.generateFingerprint(rule.Name, result)); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.shouldFire(rule) {
 			alert := Alert{
 				ID:          fmt.Sprintf("%s-%d", rule.Name, time.Now().Unix()),
 				Name:        rule.Name,
 				Severity:    rule.Severity,
-				Message:     m.formatMessage(rule.Template, result),
 				Source:      "kubepulse",
 				Timestamp:   time.Now(),
 				Fingerprint: m.generateFingerprint(rule.Name, result),
 				Status:      AlertStatusFiring,
+				Runbook:     rule.Runbook,
+				ObservedAt:  result.ObservedAt,
+				DetectedAt:  result.Timestamp,
+				Owners:      result.Owners,
 				Labels: map[string]string{
 					"check":    result.Name,
 					"rule":     rule.Name,
 					"severity": string(rule.Severity),
 				},
 			}
+			if alert.Runbook == "" {
+				alert.Runbook = result.Runbook
+			}
+			if result.Chaos != "" {
+				alert.Labels["chaos"] = result.Chaos
+			}
+			if m.environment.Name != "" {
+				alert.Labels["environment"] = m.environment.Name
+			}
+			addOwnerLabels(&alert)
+			m.addSilenceLinks(&alert)
+			alert.Message = m.formatMessage(rule, result, alert)
 
 			// Check if silenced
-			if !m.isSilenced(alert.Fingerprint) {
-				if err := m.sendAlert(ctx, alert, rule.Channel); err != nil {
+			if !m.isSilenced(alert) {
+				policy, escalate, err := m.policyFor(rule)
+				if err != nil {
 					return fmt.Errorf("failed to send alert: %w", err)
 				}
+				if escalate {
+					alert.Escalation = policy.Name
+					// Keep the alert in history even if the first step fails,
+					// so it can still be acknowledged
+					m.addToHistory(alert)
+					m.rules[i].LastFired = time.Now()
+					m.fired(alert)
+					if err := m.startEscalation(ctx, alert, policy); err != nil {
+						return fmt.Errorf("failed to send alert: %w", err)
+					}
+					continue
+				}
+				channel := m.route(alert, rule.Channel)
+				suppressed, err := m.notify(ctx, alert, channel)
+				if err != nil {
+					return fmt.Errorf("failed to send alert: %w", err)
+				}
+				if !suppressed {
+					m.trackOpen(alert, channel)
+				}
 			}
 
 			// Update rule
@@ -94,12 +208,20 @@ func (m *Manager) ProcessCheckResult(ctx context.Context, result CheckResult) er
 
 			// Store in history
 			m.addToHistory(alert)
+			m.fired(alert)
 		}
 	}
 
 	return nil
 }
 
+// fired reports a fired alert to the OnFire function; callers hold mu
+func (m *Manager) fired(alert Alert) {
+	if m.onFire != nil {
+		m.onFire(alert)
+	}
+}
+
 // SilenceAlert silences an alert for a duration
 func (m *Manager) SilenceAlert(fingerprint string, duration time.Duration) {
 	m.mu.Lock()
@@ -107,6 +229,40 @@ func (m *Manager) SilenceAlert(fingerprint string, duration time.Duration) {
 	m.silences[fingerprint] = time.Now().Add(duration)
 }
 
+// Silence holds back alerts until a time. It matches either one alert
+// fingerprint or, when it has an ID, every alert whose labels equal all of
+// its matchers.
+type Silence struct {
+	ID          string            `json:"id,omitempty" yaml:"id,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+	Matchers    map[string]string `json:"matchers,omitempty" yaml:"matchers,omitempty"` // Alert label to value, e.g. check: pod-health
+	Until       time.Time         `json:"until" yaml:"until"`
+	CreatedAt   time.Time         `json:"created_at,omitzero" yaml:"created_at,omitempty"`
+	CreatedBy   string            `json:"created_by,omitempty" yaml:"created_by,omitempty"`
+	Comment     string            `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Silences lists the silences still in effect, soonest to expire first
+func (m *Manager) Silences() []Silence {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	silences := make([]Silence, 0, len(m.silences)+len(m.matcherSilences))
+	for fingerprint, until := range m.silences {
+		if until.After(now) {
+			silences = append(silences, Silence{Fingerprint: fingerprint, Until: until})
+		}
+	}
+	for _, silence := range m.matcherSilences {
+		if silence.Until.After(now) {
+			silences = append(silences, silence)
+		}
+	}
+	sortSilences(silences)
+	return silences
+}
+
 // GetHistory returns alert history
 func (m *Manager) GetHistory(limit int) []Alert {
 	m.mu.RLock()
@@ -126,19 +282,35 @@ func (m *Manager) shouldFire(rule AlertRule) bool {
 	return time.Since(rule.LastFired) >= rule.Cooldown
 }
 
-// isSilenced checks if an alert is currently silenced
-func (m *Manager) isSilenced(fingerprint string) bool {
-	silencedUntil, exists := m.silences[fingerprint]
-	if !exists {
-		return false
+// isSilenced checks if an alert is currently silenced by its fingerprint
+// or by matchers, dropping expired silences it comes across
+func (m *Manager) isSilenced(alert Alert) bool {
+	now := time.Now()
+	if silencedUntil, exists := m.silences[alert.Fingerprint]; exists {
+		if now.Before(silencedUntil) {
+			return true
+		}
+		delete(m.silences, alert.Fingerprint)
 	}
 
-	if time.Now().After(silencedUntil) {
-		delete(m.silences, fingerprint)
-		return false
+	silenced := false
+	for id, silence := range m.matcherSilences {
+		if !now.Before(silence.Until) {
+			delete(m.matcherSilences, id)
+			continue
+		}
+		silenced = silenced || silence.matches(alert)
 	}
+	return silenced
+}
 
-	return true
+// notify sends an alert through a channel unless the channel's quiet hours
+// hold it back, reporting whether it was suppressed
+func (m *Manager) notify(ctx context.Context, alert Alert, channelName string) (bool, error) {
+	if quiet, ok := m.quietHours[channelName]; ok && quiet.Suppresses(alert, m.now()) {
+		return true, nil
+	}
+	return false, m.sendAlert(ctx, alert, channelName)
 }
 
 // sendAlert sends an alert through the specified channel
@@ -148,7 +320,11 @@ func (m *Manager) sendAlert(ctx context.Context, alert Alert, channelName string
 		return fmt.Errorf("channel %s not found", channelName)
 	}
 
-	return channel.Send(ctx, alert)
+	if err := channel.Send(ctx, alert); err != nil {
+		return m.queueDelivery(channelName, DeliveryKindSend, alert, err)
+	}
+	delivered(m.onDeliver, channelName, alert, m.now())
+	return nil
 }
 
 // addToHistory adds an alert to history with size limit
@@ -160,15 +336,14 @@ func (m *Manager) addToHistory(alert Alert) {
 	}
 }
 
-// formatMessage formats alert message using template
-func (m *Manager) formatMessage(template string, result CheckResult) string {
-	if template == "" {
+// formatMessage renders a rule's template for an alert, falling back to
+// the check's message when the template fails to render
+func (m *Manager) formatMessage(rule AlertRule, result CheckResult, alert Alert) string {
+	message, err := RenderTemplate(rule.Template, m.templateData(rule, result, alert))
+	if err != nil {
+		klog.Warningf("Failed to render template of alert rule %s: %v", rule.Name, err)
 		return result.Message
 	}
-
-	// Simple template replacement (would use proper templating in production)
-	message := template
-	message = fmt.Sprintf(message, result.Name, result.Status, result.Message)
 	return message
 }
 
@@ -192,10 +367,86 @@ func (l *LogChannel) Name() string {
 
 // Send logs the alert
 func (l *LogChannel) Send(ctx context.Context, alert Alert) error {
+	if alert.Runbook != "" {
+		fmt.Printf("[ALERT] %s: %s - %s (runbook: %s)\n", alert.Severity, alert.Name, alert.Message, alert.Runbook)
+		return nil
+	}
 	fmt.Printf("[ALERT] %s: %s - %s\n", alert.Severity, alert.Name, alert.Message)
 	return nil
 }
 
+// NewEventRateRule creates a rule that fires when a cluster-wide event rate
+// reported by the event-rates check exceeds the threshold
+func NewEventRateRule(reason string, threshold float64, severity AlertSeverity) AlertRule {
+	return AlertRule{
+		Name: fmt.Sprintf("event-rate-%s", strings.ToLower(reason)),
+		Condition: func(result CheckResult) bool {
+			if result.Name != "event-rates" {
+				return false
+			}
+			rate, ok := eventRate(result.Details, reason)
+			return ok && rate > threshold
+		},
+		Severity:  severity,
+		Cooldown:  10 * time.Minute,
+		Channel:   "log",
+		Template:  "Elevated " + reason + " event rate: {{.Check.Message}}",
+		Check:     "event-rates",
+		Reason:    reason,
+		Threshold: threshold,
+	}
+}
+
+// CreateEventRateRules creates default alert rules for Kubernetes event rates
+func CreateEventRateRules() []AlertRule {
+	return []AlertRule{
+		NewEventRateRule("Evicted", 1, AlertSeverityWarning),
+		NewEventRateRule("OOMKilling", 5, AlertSeverityWarning),
+		NewEventRateRule("FailedScheduling", 2, AlertSeverityWarning),
+	}
+}
+
+// NewLatencyBudgetRule creates a rule that fires when the named check
+// reports detection latency over its budget
+func NewLatencyBudgetRule(check string) AlertRule {
+	return AlertRule{
+		Name: "detection-latency-budget",
+		Condition: func(result CheckResult) bool {
+			return result.Name == check && result.Status == HealthStatusDegraded
+		},
+		Severity: AlertSeverityWarning,
+		Cooldown: 30 * time.Minute,
+		Channel:  "log",
+		Template: "Detection latency over budget: {{.Check.Message}}",
+		Check:    check,
+		Status:   HealthStatusDegraded,
+	}
+}
+
+// NewSLOBurnRateRules creates the rules alerting on an SLO's check: a
+// critical alert while it's unhealthy, when a critical burn-rate alert
+// fires, and a warning while it's degraded
+func NewSLOBurnRateRules(slo, check string) []AlertRule {
+	rule := func(severity AlertSeverity, status HealthStatus) AlertRule {
+		return AlertRule{
+			Name: fmt.Sprintf("slo-%s-burn-%s", slo, severity),
+			Condition: func(result CheckResult) bool {
+				return result.Name == check && result.Status == status
+			},
+			Severity: severity,
+			Cooldown: 30 * time.Minute,
+			Channel:  "log",
+			Template: "SLO " + slo + " is burning its error budget: {{.Check.Message}}",
+			Check:    check,
+			Status:   status,
+		}
+	}
+	return []AlertRule{
+		rule(AlertSeverityCritical, HealthStatusUnhealthy),
+		rule(AlertSeverityWarning, HealthStatusDegraded),
+	}
+}
+
 // CreateDefaultRules creates default alert rules
 func CreateDefaultRules() []AlertRule {
 	return []AlertRule{
@@ -207,7 +458,9 @@ func CreateDefaultRules() []AlertRule {
 			Severity: AlertSeverityCritical,
 			Cooldown: 5 * time.Minute,
 			Channel:  "log",
-			Template: "Critical pod health issue: %s",
+			Template: "Critical pod health issue: {{.Check.Message}}",
+			Check:    "pod-health",
+			Status:   HealthStatusUnhealthy,
 		},
 		{
 			Name: "node-health-critical",
@@ -217,7 +470,9 @@ func CreateDefaultRules() []AlertRule {
 			Severity: AlertSeverityCritical,
 			Cooldown: 5 * time.Minute,
 			Channel:  "log",
-			Template: "Critical node health issue: %s",
+			Template: "Critical node health issue: {{.Check.Message}}",
+			Check:    "node-health",
+			Status:   HealthStatusUnhealthy,
 		},
 		{
 			Name: "pod-health-warning",
@@ -227,7 +482,9 @@ func CreateDefaultRules() []AlertRule {
 			Severity: AlertSeverityWarning,
 			Cooldown: 10 * time.Minute,
 			Channel:  "log",
-			Template: "Pod health degraded: %s",
+			Template: "Pod health degraded: {{.Check.Message}}",
+			Check:    "pod-health",
+			Status:   HealthStatusDegraded,
 		},
 	}
 }
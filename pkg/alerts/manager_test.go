@@ -401,18 +401,25 @@ func TestManager_FormatMessage(t *testing.T) {
 		Message: "Test failure message",
 	}
 
-	// Test empty template
-	message := manager.formatMessage("", result)
-	if message != "Test failure message" {
-		t.Errorf("expected original message, got %s", message)
-	}
-
-	// Test template formatting
-	template := "Alert for %s with status %s: %s"
-	message = manager.formatMessage(template, result)
-	expected := "Alert for test-check with status unhealthy: Test failure message"
-	if message != expected {
-		t.Errorf("expected '%s', got '%s'", expected, message)
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "empty template", want: "Test failure message"},
+		{name: "format string", template: "Alert for %s with status %s: %s", want: "Alert for test-check with status unhealthy: Test failure message"},
+		{name: "single verb format string", template: "Pod health degraded: %s", want: "Pod health degraded: Test failure message"},
+		{name: "go template", template: "{{.Rule}} {{.Severity}}: {{.Check.Message}}", want: "test-rule critical: Test failure message"},
+		{name: "failed render falls back to the message", template: "{{.Check.Name.Missing}}", want: "Test failure message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := AlertRule{Name: "test-rule", Severity: AlertSeverityCritical, Template: tt.template}
+			if message := manager.formatMessage(rule, result, Alert{}); message != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, message)
+			}
+		})
 	}
 }
 
@@ -431,20 +438,20 @@ func TestManager_IsSilenced(t *testing.T) {
 	manager := NewManager()
 
 	// Test non-existent silence
-	if manager.isSilenced("non-existent") {
+	if manager.isSilenced(Alert{Fingerprint: "non-existent"}) {
 		t.Error("expected non-existent fingerprint to not be silenced")
 	}
 
 	// Test active silence
 	fingerprint := "test-fingerprint"
 	manager.silences[fingerprint] = time.Now().Add(1 * time.Hour)
-	if !manager.isSilenced(fingerprint) {
+	if !manager.isSilenced(Alert{Fingerprint: fingerprint}) {
 		t.Error("expected active silence to be detected")
 	}
 
 	// Test expired silence
 	manager.silences[fingerprint] = time.Now().Add(-1 * time.Hour)
-	if manager.isSilenced(fingerprint) {
+	if manager.isSilenced(Alert{Fingerprint: fingerprint}) {
 		t.Error("expected expired silence to be removed")
 	}
 
@@ -509,3 +516,39 @@ func TestCreateDefaultRules(t *testing.T) {
 		t.Error("expected pod-health-critical condition to not match healthy pod-health")
 	}
 }
+
+func TestNewEventRateRule(t *testing.T) {
+	rule := NewEventRateRule("Evicted", 1, AlertSeverityWarning)
+
+	if rule.Name != "event-rate-evicted" {
+		t.Errorf("expected rule name 'event-rate-evicted', got %s", rule.Name)
+	}
+
+	above := CheckResult{
+		Name:    "event-rates",
+		Details: map[string]interface{}{"rates": map[string]float64{"Evicted": 2.5}},
+	}
+	if !rule.Condition(above) {
+		t.Error("expected condition to match rate above threshold")
+	}
+
+	below := CheckResult{
+		Name:    "event-rates",
+		Details: map[string]interface{}{"rates": map[string]float64{"Evicted": 0.5}},
+	}
+	if rule.Condition(below) {
+		t.Error("expected condition to not match rate below threshold")
+	}
+
+	otherCheck := CheckResult{
+		Name:    "pod-health",
+		Details: map[string]interface{}{"rates": map[string]float64{"Evicted": 5}},
+	}
+	if rule.Condition(otherCheck) {
+		t.Error("expected condition to only match event-rates results")
+	}
+
+	if len(CreateEventRateRules()) != 3 {
+		t.Errorf("expected 3 event rate rules, got %d", len(CreateEventRateRules()))
+	}
+}
@@ -0,0 +1,90 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Route sends the alerts of a team, app or tier to a channel instead of
+// their rule's. Each field set must equal the same owner of the alert's
+// workloads; alerts going through an escalation policy aren't routed.
+type Route struct {
+	Team    string `json:"team,omitempty"`
+	App     string `json:"app,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Channel string `json:"channel"`
+}
+
+// Validate checks that the route matches on an owner and names a channel
+func (r Route) Validate() error {
+	if r.Team == "" && r.App == "" && r.Tier == "" {
+		return fmt.Errorf("route to %q needs a team, app or tier", r.Channel)
+	}
+	if r.Channel == "" {
+		return fmt.Errorf("route needs a channel")
+	}
+	return nil
+}
+
+// matches reports whether one of the alert's owners has every field the
+// route sets
+func (r Route) matches(alert Alert) bool {
+	for _, owner := range alert.Owners {
+		if (r.Team == "" || r.Team == owner.Team) &&
+			(r.App == "" || r.App == owner.App) &&
+			(r.Tier == "" || r.Tier == owner.Tier) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRoutes replaces the routes, tried in order before a rule's channel
+func (m *Manager) SetRoutes(routes []Route) error {
+	for _, route := range routes {
+		if err := route.Validate(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes = append([]Route(nil), routes...)
+	return nil
+}
+
+// route returns the channel of the first route matching the alert, or
+// fallback; callers hold mu
+func (m *Manager) route(alert Alert, fallback string) string {
+	for _, route := range m.routes {
+		if _, ok := m.channels[route.Channel]; ok && route.matches(alert) {
+			return route.Channel
+		}
+	}
+	return fallback
+}
+
+// addOwnerLabels labels an alert with the team, app and tier its owners
+// agree on, so silences and Alertmanager can match them
+func addOwnerLabels(alert *Alert) {
+	if len(alert.Owners) == 0 {
+		return
+	}
+	fields := map[string]func(Ownership) string{
+		"team": func(o Ownership) string { return o.Team },
+		"app":  func(o Ownership) string { return o.App },
+		"tier": func(o Ownership) string { return o.Tier },
+	}
+	for label, field := range fields {
+		value := field(alert.Owners[0])
+		for _, owner := range alert.Owners[1:] {
+			if field(owner) != value {
+				value = ""
+				break
+			}
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			alert.Labels[label] = value
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+)
+
+func TestManager_Routes(t *testing.T) {
+	manager := NewManager()
+	fallback := &mockNotificationChannel{name: "log"}
+	payments := &mockNotificationChannel{name: "payments"}
+	manager.RegisterChannel(fallback)
+	manager.RegisterChannel(payments)
+	manager.AddRule(AlertRule{
+		Name:      "pods-failing",
+		Condition: func(result CheckResult) bool { return result.Status == HealthStatusUnhealthy },
+		Severity:  AlertSeverityCritical,
+		Channel:   "log",
+	})
+	if err := manager.SetRoutes([]Route{{Tier: "frontend"}}); err == nil {
+		t.Fatal("expected a route without a channel refused")
+	}
+	if err := manager.SetRoutes([]Route{{Team: "payments", Tier: "backend", Channel: "payments"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		owners  []Ownership
+		channel *mockNotificationChannel
+		labels  map[string]string
+	}{
+		{"no owners", nil, fallback, map[string]string{}},
+		{"matching owner", []Ownership{{Team: "payments", App: "checkout", Tier: "backend"}}, payments,
+			map[string]string{"team": "payments", "app": "checkout", "tier": "backend"}},
+		{"one of several owners", []Ownership{{Team: "payments", App: "ledger", Tier: "backend"}, {Team: "search", App: "web", Tier: "backend"}}, payments,
+			map[string]string{"tier": "backend"}},
+		{"other tier", []Ownership{{Team: "payments", Tier: "frontend"}}, fallback, map[string]string{"team": "payments", "tier": "frontend"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback.sentAlert, payments.sentAlert = nil, nil
+			manager.rules[0].LastFired = manager.rules[0].Added.AddDate(-1, 0, 0)
+			if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Owners: tt.owners}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sent := tt.channel.sentAlert
+			if sent == nil {
+				t.Fatalf("expected the alert sent to %s", tt.channel.name)
+			}
+			for _, label := range []string{"team", "app", "tier"} {
+				if sent.Labels[label] != tt.labels[label] {
+					t.Errorf("expected %s label %q, got %q", label, tt.labels[label], sent.Labels[label])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleTest is a sample check result and whether a rule should fire on it.
+// A result without a name is taken to come from the rule's check.
+type RuleTest struct {
+	Name   string      `json:"name"`
+	Result CheckResult `json:"result"`
+	Fire   bool        `json:"fire"`
+}
+
+// RuleTestResult is the outcome of one rule test
+type RuleTestResult struct {
+	Rule   string `json:"rule"`
+	Test   string `json:"test"`
+	Want   bool   `json:"want"`
+	Fired  bool   `json:"fired"`
+	Passed bool   `json:"passed"`
+}
+
+// String describes the outcome, e.g. "pods-down: unhealthy pods: fired, want fire"
+func (r RuleTestResult) String() string {
+	outcome := func(fire bool) string {
+		if fire {
+			return "fire"
+		}
+		return "no fire"
+	}
+	fired := "did not fire"
+	if r.Fired {
+		fired = "fired"
+	}
+	return fmt.Sprintf("%s: %s: %s, want %s", r.Rule, r.Test, fired, outcome(r.Want))
+}
+
+// RunRuleTests evaluates the rule's condition against each test's result.
+// Cooldowns, silences and channels are not involved; only whether the
+// condition matches is checked.
+func RunRuleTests(rule AlertRule, tests []RuleTest) []RuleTestResult {
+	results := make([]RuleTestResult, 0, len(tests))
+	for i, test := range tests {
+		result := test.Result
+		if result.Name == "" {
+			result.Name = rule.Check
+		}
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", i+1)
+		}
+
+		fired := rule.Condition != nil && rule.Condition(result)
+		results = append(results, RuleTestResult{
+			Rule:   rule.Name,
+			Test:   name,
+			Want:   test.Fire,
+			Fired:  fired,
+			Passed: fired == test.Fire,
+		})
+	}
+	return results
+}
+
+// RuleTestFailures returns an error listing the failed tests, or nil
+func RuleTestFailures(results []RuleTestResult) error {
+	var failed []string
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result.String())
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d alert rule tests failed: %s", len(failed), strings.Join(failed, "; "))
+}
+
+// eventRate reads a reason's rate from the event-rates check details. Rates
+// decoded from JSON or YAML arrive as map[string]interface{}, whose keys may
+// have been lowercased by the config loader, so those match case-insensitively.
+func eventRate(details map[string]interface{}, reason string) (float64, bool) {
+	switch rates := details["rates"].(type) {
+	case map[string]float64:
+		rate, ok := rates[reason]
+		return rate, ok
+	case map[string]interface{}:
+		value, ok := rates[reason]
+		if !ok {
+			for key, v := range rates {
+				if strings.EqualFold(key, reason) {
+					value, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return 0, false
+		}
+		return toFloat(value)
+	}
+	return 0, false
+}
+
+// toFloat converts a decoded number to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
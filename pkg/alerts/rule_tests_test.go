@@ -0,0 +1,86 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunRuleTests(t *testing.T) {
+	checkRule, err := RuleSpec{Name: "pods-down", Check: "pod-health", Status: HealthStatusUnhealthy, Severity: AlertSeverityCritical}.Build()
+	if err != nil {
+		t.Fatalf("failed to build rule: %v", err)
+	}
+	rateRule, err := RuleSpec{Name: "evictions", Reason: "Evicted", Threshold: 2, Severity: AlertSeverityWarning}.Build()
+	if err != nil {
+		t.Fatalf("failed to build rule: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		rule       AlertRule
+		test       RuleTest
+		wantFired  bool
+		wantPassed bool
+	}{
+		{
+			name:       "check defaults to the rule's",
+			rule:       checkRule,
+			test:       RuleTest{Result: CheckResult{Status: HealthStatusUnhealthy}, Fire: true},
+			wantFired:  true,
+			wantPassed: true,
+		},
+		{
+			name:       "other check",
+			rule:       checkRule,
+			test:       RuleTest{Result: CheckResult{Name: "node-health", Status: HealthStatusUnhealthy}, Fire: true},
+			wantPassed: false,
+		},
+		{
+			name:       "expected no fire",
+			rule:       checkRule,
+			test:       RuleTest{Result: CheckResult{Status: HealthStatusDegraded}},
+			wantPassed: true,
+		},
+		{
+			name:       "decoded rates",
+			rule:       rateRule,
+			test:       RuleTest{Result: CheckResult{Details: map[string]interface{}{"rates": map[string]interface{}{"evicted": 3}}}, Fire: true},
+			wantFired:  true,
+			wantPassed: true,
+		},
+		{
+			name:       "typed rates below threshold",
+			rule:       rateRule,
+			test:       RuleTest{Result: CheckResult{Details: map[string]interface{}{"rates": map[string]float64{"Evicted": 1.5}}}, Fire: true},
+			wantPassed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := RunRuleTests(tt.rule, []RuleTest{tt.test})
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			if results[0].Fired != tt.wantFired || results[0].Passed != tt.wantPassed {
+				t.Errorf("expected fired=%v passed=%v, got %+v", tt.wantFired, tt.wantPassed, results[0])
+			}
+			if results[0].Test != "test 1" {
+				t.Errorf("expected unnamed test to be numbered, got %q", results[0].Test)
+			}
+		})
+	}
+}
+
+func TestRuleTestFailures(t *testing.T) {
+	if err := RuleTestFailures([]RuleTestResult{{Rule: "a", Test: "ok", Passed: true}}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	err := RuleTestFailures([]RuleTestResult{
+		{Rule: "a", Test: "ok", Passed: true},
+		{Rule: "b", Test: "quiet", Fired: true},
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 alert rule tests failed: b: quiet: fired, want no fire") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
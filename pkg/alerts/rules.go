@@ -0,0 +1,197 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RuleSpec is the serializable form of an AlertRule used by the rules API.
+// A spec with a Reason builds an event-rate rule; otherwise it builds a rule
+// that fires when Check reports Status (unhealthy or degraded when unset).
+type RuleSpec struct {
+	Name      string        `json:"name"`
+	Check     string        `json:"check"`
+	Status    HealthStatus  `json:"status,omitempty"`
+	Reason    string        `json:"reason,omitempty"`
+	Threshold float64       `json:"threshold,omitempty"`
+	Severity  AlertSeverity `json:"severity"`
+	Cooldown  string        `json:"cooldown,omitempty"`
+	Channel   string        `json:"channel,omitempty"`
+	Template  string        `json:"template,omitempty"`
+
+	Escalation string `json:"escalation,omitempty"` // Escalation policy used instead of channel
+	Runbook    string `json:"runbook,omitempty"`    // Runbook URL attached to the rule's alerts
+}
+
+// RuleInfo describes a configured rule and how often it has fired
+type RuleInfo struct {
+	RuleSpec
+	Added     time.Time `json:"added"`
+	LastFired time.Time `json:"last_fired"`
+	FireCount int       `json:"fire_count"`
+}
+
+// defaultRuleCooldown applies when a spec omits the cooldown
+const defaultRuleCooldown = 10 * time.Minute
+
+// Validate checks that the spec describes a buildable rule
+func (s RuleSpec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if s.Reason == "" && s.Check == "" {
+		return fmt.Errorf("rule %s must set a check or an event reason", s.Name)
+	}
+	if s.Reason != "" && s.Threshold <= 0 {
+		return fmt.Errorf("event-rate rule %s needs a positive threshold", s.Name)
+	}
+	switch s.Severity {
+	case AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo:
+	default:
+		return fmt.Errorf("invalid severity %q for rule %s", s.Severity, s.Name)
+	}
+	switch s.Status {
+	case "", HealthStatusDegraded, HealthStatusUnhealthy, HealthStatusUnknown:
+	default:
+		return fmt.Errorf("invalid status %q for rule %s", s.Status, s.Name)
+	}
+	if s.Cooldown != "" {
+		cooldown, err := time.ParseDuration(s.Cooldown)
+		if err != nil || cooldown < 0 {
+			return fmt.Errorf("invalid cooldown %q for rule %s", s.Cooldown, s.Name)
+		}
+	}
+	if err := ValidateTemplate(s.Template); err != nil {
+		return fmt.Errorf("rule %s: %w", s.Name, err)
+	}
+	if s.Runbook != "" {
+		if err := ValidateRunbookURL(s.Runbook); err != nil {
+			return fmt.Errorf("rule %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// Build converts the spec into an AlertRule
+func (s RuleSpec) Build() (AlertRule, error) {
+	if err := s.Validate(); err != nil {
+		return AlertRule{}, err
+	}
+
+	cooldown := defaultRuleCooldown
+	if s.Cooldown != "" {
+		cooldown, _ = time.ParseDuration(s.Cooldown)
+	}
+	channel := s.Channel
+	if channel == "" {
+		channel = "log"
+	}
+
+	var rule AlertRule
+	if s.Reason != "" {
+		rule = NewEventRateRule(s.Reason, s.Threshold, s.Severity)
+	} else {
+		check, status := s.Check, s.Status
+		rule = AlertRule{
+			Condition: func(result CheckResult) bool {
+				if result.Name != check {
+					return false
+				}
+				if status == "" {
+					return result.Status == HealthStatusUnhealthy || result.Status == HealthStatusDegraded
+				}
+				return result.Status == status
+			},
+			Severity: s.Severity,
+			Template: check + " alert: {{.Check.Message}}",
+			Check:    check,
+			Status:   status,
+		}
+	}
+
+	rule.Name = s.Name
+	rule.Cooldown = cooldown
+	rule.Channel = channel
+	rule.Escalation = s.Escalation
+	rule.Runbook = s.Runbook
+	if s.Template != "" {
+		rule.Template = s.Template
+	}
+	return rule, nil
+}
+
+// Spec returns the declarative form of the rule
+func (r AlertRule) Spec() RuleSpec {
+	return RuleSpec{
+		Name:      r.Name,
+		Check:     r.Check,
+		Status:    r.Status,
+		Reason:    r.Reason,
+		Threshold: r.Threshold,
+		Severity:  r.Severity,
+		Cooldown:  r.Cooldown.String(),
+		Channel:   r.Channel,
+		Template:  r.Template,
+
+		Escalation: r.Escalation,
+		Runbook:    r.Runbook,
+	}
+}
+
+// Rules lists configured rules with their fire counts from alert history
+func (m *Manager) Rules() []RuleInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fired := make(map[string]int)
+	for _, alert := range m.history {
+		fired[alert.Labels["rule"]]++
+	}
+
+	rules := make([]RuleInfo, 0, len(m.rules))
+	for _, rule := range m.rules {
+		rules = append(rules, RuleInfo{
+			RuleSpec:  rule.Spec(),
+			Added:     rule.Added,
+			LastFired: rule.LastFired,
+			FireCount: fired[rule.Name],
+		})
+	}
+	return rules
+}
+
+// UpsertRule adds a rule or replaces the rule with the same name, keeping its
+// cooldown state so a tuned rule doesn't immediately re-fire
+func (m *Manager) UpsertRule(rule AlertRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.rules {
+		if strings.EqualFold(existing.Name, rule.Name) {
+			rule.Added = existing.Added
+			rule.LastFired = existing.LastFired
+			m.rules[i] = rule
+			return
+		}
+	}
+
+	if rule.Added.IsZero() {
+		rule.Added = time.Now()
+	}
+	m.rules = append(m.rules, rule)
+}
+
+// RemoveRule deletes a rule by name
+func (m *Manager) RemoveRule(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, rule := range m.rules {
+		if strings.EqualFold(rule.Name, name) {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("rule %s not found", name)
+}
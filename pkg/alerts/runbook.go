@@ -0,0 +1,57 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ValidateRunbookURL checks that a runbook link is an absolute http(s) URL
+func ValidateRunbookURL(link string) error {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid runbook URL %q: %w", link, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("runbook URL %q must be an absolute http or https URL", link)
+	}
+	return nil
+}
+
+// CheckRunbook verifies that a runbook link is reachable. It sends a HEAD
+// request, falling back to GET for servers that do not support HEAD, and
+// treats any 4xx or 5xx response as unreachable.
+func CheckRunbook(ctx context.Context, client *http.Client, link string) error {
+	if err := ValidateRunbookURL(link); err != nil {
+		return err
+	}
+
+	status, err := requestStatus(ctx, client, http.MethodHead, link)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = requestStatus(ctx, client, http.MethodGet, link)
+	}
+	if err != nil {
+		return fmt.Errorf("runbook %s is unreachable: %w", link, err)
+	}
+	if status >= 400 {
+		return fmt.Errorf("runbook %s returned %d", link, status)
+	}
+	return nil
+}
+
+// requestStatus returns the status code of a request, discarding the body
+func requestStatus(ctx context.Context, client *http.Client, method, link string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
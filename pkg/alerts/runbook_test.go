@@ -0,0 +1,103 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateRunbookURL(t *testing.T) {
+	tests := []struct {
+		link    string
+		wantErr bool
+	}{
+		{"https://runbooks.example.com/pods", false},
+		{"http://wiki.internal/runbooks/nodes#not-ready", false},
+		{"runbooks.example.com/pods", true},
+		{"ftp://runbooks.example.com/pods", true},
+		{"https://", true},
+		{"://bad", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateRunbookURL(tt.link); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRunbookURL(%q) error = %v, wantErr %v", tt.link, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckRunbook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"/ok", false},
+		{"/get-only", false},
+		{"/missing", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := CheckRunbook(context.Background(), server.Client(), server.URL+tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRunbook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := CheckRunbook(context.Background(), server.Client(), "http://127.0.0.1:1/down"); err == nil {
+		t.Error("expected an error for an unreachable runbook")
+	}
+}
+
+func TestManager_AlertRunbook(t *testing.T) {
+	manager := NewManager()
+	manager.RegisterChannel(NewLogChannel())
+	for _, spec := range []RuleSpec{
+		{Name: "pods", Check: "pod-health", Severity: AlertSeverityWarning, Runbook: "https://runbooks.example.com/pods"},
+		{Name: "nodes", Check: "node-health", Severity: AlertSeverityWarning},
+	} {
+		rule, err := spec.Build()
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		manager.AddRule(rule)
+	}
+
+	results := []CheckResult{
+		{Name: "pod-health", Status: HealthStatusUnhealthy, Runbook: "https://runbooks.example.com/annotated"},
+		{Name: "node-health", Status: HealthStatusUnhealthy, Runbook: "https://runbooks.example.com/nodes"},
+	}
+	for _, result := range results {
+		if err := manager.ProcessCheckResult(context.Background(), result); err != nil {
+			t.Fatalf("ProcessCheckResult() error = %v", err)
+		}
+	}
+
+	runbooks := make(map[string]string)
+	for _, alert := range manager.GetHistory(0) {
+		runbooks[alert.Name] = alert.Runbook
+	}
+	if runbooks["pods"] != "https://runbooks.example.com/pods" {
+		t.Errorf("expected the rule's runbook to win, got %q", runbooks["pods"])
+	}
+	if runbooks["nodes"] != "https://runbooks.example.com/nodes" {
+		t.Errorf("expected the check's runbook as fallback, got %q", runbooks["nodes"])
+	}
+
+	if _, err := (RuleSpec{Name: "bad", Check: "pod-health", Severity: AlertSeverityInfo, Runbook: "wiki/pods"}).Build(); err == nil {
+		t.Error("expected an error for a relative runbook URL")
+	}
+}
@@ -0,0 +1,164 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrSilenceNotFound is returned for silence IDs that don't exist or have expired
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// SilenceLabels are the alert labels silences can match on
+var SilenceLabels = []string{"check", "rule", "severity", "chaos", "team", "app", "tier", "environment"}
+
+// DefaultSilenceDuration is how long silences created from notification
+// links and buttons last unless the responder picks another duration
+const DefaultSilenceDuration = time.Hour
+
+// SilenceMatchers returns the matchers that select an alert and its
+// repeats: the rule that fired and the check it fired on
+func SilenceMatchers(alert Alert) map[string]string {
+	matchers := make(map[string]string, 2)
+	for _, label := range []string{"check", "rule"} {
+		if value := alert.Labels[label]; value != "" {
+			matchers[label] = value
+		}
+	}
+	return matchers
+}
+
+// validateMatchers checks that matchers only name labels alerts carry
+func validateMatchers(matchers map[string]string) error {
+	if len(matchers) == 0 {
+		return fmt.Errorf("a silence needs a fingerprint or at least one matcher")
+	}
+	for label, value := range matchers {
+		known := false
+		for _, name := range SilenceLabels {
+			known = known || name == label
+		}
+		if !known {
+			return fmt.Errorf("unknown matcher label %q; use %s", label, strings.Join(SilenceLabels, ", "))
+		}
+		if value == "" {
+			return fmt.Errorf("matcher %s needs a value", label)
+		}
+	}
+	return nil
+}
+
+// matches reports whether every matcher equals the alert's label
+func (s Silence) matches(alert Alert) bool {
+	for label, value := range s.Matchers {
+		if alert.Labels[label] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// AddSilence holds back alerts whose labels equal all of the silence's
+// matchers until it expires, returning the silence with its ID
+func (m *Manager) AddSilence(silence Silence) (Silence, error) {
+	if err := validateMatchers(silence.Matchers); err != nil {
+		return Silence{}, err
+	}
+	now := m.now()
+	if !silence.Until.After(now) {
+		return Silence{}, fmt.Errorf("silence must end in the future")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	matchers := make(map[string]string, len(silence.Matchers))
+	for label, value := range silence.Matchers {
+		matchers[label] = value
+	}
+	silence.Matchers = matchers
+	silence.Fingerprint = ""
+	if silence.CreatedAt.IsZero() {
+		silence.CreatedAt = now
+	}
+	for silence.ID == "" || m.matcherSilences[silence.ID].ID != "" {
+		m.silenceSequence++
+		silence.ID = fmt.Sprintf("silence-%d-%d", now.Unix(), m.silenceSequence)
+	}
+	m.matcherSilences[silence.ID] = silence
+	return silence, nil
+}
+
+// ExpireSilence ends a matcher silence before it expires
+func (m *Manager) ExpireSilence(id string) (Silence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	silence, ok := m.matcherSilences[id]
+	if !ok || !silence.Until.After(m.now()) {
+		return Silence{}, fmt.Errorf("%w: %s", ErrSilenceNotFound, id)
+	}
+	delete(m.matcherSilences, id)
+	silence.Until = m.now()
+	return silence, nil
+}
+
+// SetExternalURL sets the address responders reach KubePulse at, such as
+// https://kubepulse.example.com. Alerts then carry a dashboard link and an
+// API command that silence them, which channels include in notifications.
+func (m *Manager) SetExternalURL(externalURL string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.externalURL = strings.TrimSuffix(externalURL, "/")
+}
+
+// addSilenceLinks sets an alert's silence link and command from its
+// matchers; alerts get neither without an external URL
+func (m *Manager) addSilenceLinks(alert *Alert) {
+	matchers := SilenceMatchers(*alert)
+	if m.externalURL == "" || len(matchers) == 0 {
+		return
+	}
+
+	query := url.Values{}
+	for label, value := range matchers {
+		query.Set("silence."+label, value)
+	}
+	alert.SilenceURL = m.externalURL + "/?" + query.Encode()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"matchers": matchers,
+		"duration": shortDuration(DefaultSilenceDuration),
+		"comment":  "Silenced from notification",
+	})
+	if err != nil {
+		return
+	}
+	alert.SilenceCommand = fmt.Sprintf("curl -X POST %s/api/v1/alerts/silences -H 'Content-Type: application/json' -d '%s'",
+		m.externalURL, strings.ReplaceAll(string(body), "'", `'\''`))
+}
+
+// shortDuration formats whole hours and minutes without trailing zero units
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// sortSilences orders silences soonest to expire first
+func sortSilences(silences []Silence) {
+	sort.Slice(silences, func(i, j int) bool {
+		if !silences[i].Until.Equal(silences[j].Until) {
+			return silences[i].Until.Before(silences[j].Until)
+		}
+		if silences[i].Fingerprint != silences[j].Fingerprint {
+			return silences[i].Fingerprint < silences[j].Fingerprint
+		}
+		return silences[i].ID < silences[j].ID
+	})
+}
@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_AddSilence(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		silence Silence
+		wantErr bool
+	}{
+		{name: "check and rule", silence: Silence{Matchers: map[string]string{"check": "pod-health", "rule": "pod-health-critical"}, Until: until}},
+		{name: "severity", silence: Silence{Matchers: map[string]string{"severity": "warning"}, Until: until}},
+		{name: "no matchers", silence: Silence{Until: until}, wantErr: true},
+		{name: "unknown label", silence: Silence{Matchers: map[string]string{"namespace": "default"}, Until: until}, wantErr: true},
+		{name: "empty value", silence: Silence{Matchers: map[string]string{"check": ""}, Until: until}, wantErr: true},
+		{name: "already ended", silence: Silence{Matchers: map[string]string{"check": "pod-health"}, Until: time.Now().Add(-time.Minute)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silence, err := NewManager().AddSilence(tt.silence)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && (silence.ID == "" || silence.CreatedAt.IsZero()) {
+				t.Errorf("expected an ID and creation time, got %+v", silence)
+			}
+		})
+	}
+}
+
+func TestManager_MatcherSilence(t *testing.T) {
+	manager := NewManager()
+	channel := &mockNotificationChannel{name: "test-channel"}
+	manager.RegisterChannel(channel)
+	manager.AddRule(AlertRule{
+		Name:      "test-rule",
+		Severity:  AlertSeverityWarning,
+		Channel:   "test-channel",
+		Condition: func(result CheckResult) bool { return result.Status == HealthStatusUnhealthy },
+	})
+
+	silence, err := manager.AddSilence(Silence{Matchers: map[string]string{"check": "silenced-check"}, Until: time.Now().Add(time.Hour), Comment: "noisy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, check := range []string{"silenced-check", "other-check"} {
+		if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: check, Status: HealthStatusUnhealthy}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if channel.sentAlert == nil || channel.sentAlert.Labels["check"] != "other-check" {
+		t.Errorf("expected only the unmatched check's alert to be sent, got %+v", channel.sentAlert)
+	}
+
+	silences := manager.Silences()
+	if len(silences) != 1 || silences[0].ID != silence.ID || silences[0].Comment != "noisy" {
+		t.Errorf("expected the matcher silence to be listed, got %+v", silences)
+	}
+
+	if _, err := manager.ExpireSilence(silence.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.ExpireSilence(silence.ID); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("expected ErrSilenceNotFound for an expired silence, got %v", err)
+	}
+	channel.sentAlert = nil
+	if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: "silenced-check", Status: HealthStatusUnhealthy}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channel.sentAlert == nil {
+		t.Error("expected the alert to be sent once its silence expired")
+	}
+}
+
+func TestManager_SilenceLinks(t *testing.T) {
+	manager := NewManager()
+	channel := &mockNotificationChannel{name: "test-channel"}
+	manager.RegisterChannel(channel)
+	manager.AddRule(AlertRule{
+		Name:      "test-rule",
+		Severity:  AlertSeverityCritical,
+		Channel:   "test-channel",
+		Condition: func(result CheckResult) bool { return true },
+	})
+
+	if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: "test-check"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channel.sentAlert.SilenceURL != "" || channel.sentAlert.SilenceCommand != "" {
+		t.Errorf("expected no silence links without an external URL, got %+v", channel.sentAlert)
+	}
+
+	manager.SetExternalURL("https://kubepulse.example.com/")
+	if err := manager.ProcessCheckResult(context.Background(), CheckResult{Name: "test-check"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alert := channel.sentAlert
+	if alert.SilenceURL != "https://kubepulse.example.com/?silence.check=test-check&silence.rule=test-rule" {
+		t.Errorf("unexpected silence URL %s", alert.SilenceURL)
+	}
+	want := `curl -X POST https://kubepulse.example.com/api/v1/alerts/silences -H 'Content-Type: application/json' ` +
+		`-d '{"comment":"Silenced from notification","duration":"1h","matchers":{"check":"test-check","rule":"test-rule"}}'`
+	if alert.SilenceCommand != want {
+		t.Errorf("unexpected silence command:\n got %s\nwant %s", alert.SilenceCommand, want)
+	}
+	if !strings.HasPrefix(manager.GetHistory(1)[0].SilenceURL, "https://kubepulse.example.com/") {
+		t.Error("expected the alert history to keep the silence link")
+	}
+}
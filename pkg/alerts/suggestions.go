@@ -0,0 +1,169 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SuggestionAction is the kind of change a rule suggestion proposes
+type SuggestionAction string
+
+const (
+	SuggestionAdjustThreshold SuggestionAction = "adjust_threshold"
+	SuggestionAdjustCooldown  SuggestionAction = "adjust_cooldown"
+	SuggestionAddRule         SuggestionAction = "add_rule"
+	SuggestionRetireRule      SuggestionAction = "retire_rule"
+)
+
+// RuleSuggestion proposes a change to the alert rule set. Proposed is the
+// rule to upsert; it is nil when the suggestion retires Rule.
+type RuleSuggestion struct {
+	ID         string           `json:"id"`
+	Action     SuggestionAction `json:"action"`
+	Rule       string           `json:"rule"`
+	Reason     string           `json:"reason"`
+	Confidence float64          `json:"confidence"`
+	Source     string           `json:"source"`
+	Current    *RuleSpec        `json:"current,omitempty"`
+	Proposed   *RuleSpec        `json:"proposed,omitempty"`
+}
+
+// SuggestionInput is the history the suggestion heuristics work from
+type SuggestionInput struct {
+	Rules    []RuleInfo
+	Alerts   []Alert        // Alert history, oldest first
+	Failures map[string]int // Times each check started failing in the window
+	Checks   []string       // Currently registered health checks
+	Window   time.Duration
+	Now      time.Time
+}
+
+const (
+	// noisyFiresPerDay is the fire rate above which a rule is considered noisy
+	noisyFiresPerDay = 12
+	// recurringFailures is how often an uncovered check must fail to warrant a rule
+	recurringFailures = 3
+)
+
+// SuggestRules proposes rule changes from alert and failure history: noisy
+// rules get a higher threshold or longer cooldown, checks that keep failing
+// without a matching rule get one, and rules for checks that are no longer
+// registered are retired.
+func SuggestRules(in SuggestionInput) []RuleSuggestion {
+	if in.Now.IsZero() {
+		in.Now = time.Now()
+	}
+	if in.Window <= 0 {
+		in.Window = 24 * time.Hour
+	}
+	since := in.Now.Add(-in.Window)
+
+	fired := make(map[string]int)
+	for _, alert := range in.Alerts {
+		if alert.Timestamp.Before(since) {
+			continue
+		}
+		fired[alert.Labels["rule"]]++
+	}
+
+	registered := make(map[string]bool, len(in.Checks))
+	for _, check := range in.Checks {
+		registered[check] = true
+	}
+	covered := make(map[string]bool)
+	for _, rule := range in.Rules {
+		covered[rule.Check] = true
+	}
+
+	noisyLimit := int(math.Ceil(noisyFiresPerDay * in.Window.Hours() / 24))
+	if noisyLimit < 3 {
+		noisyLimit = 3
+	}
+
+	suggestions := make([]RuleSuggestion, 0)
+	for _, rule := range in.Rules {
+		current := rule.RuleSpec
+
+		if rule.Check != "" && !registered[rule.Check] {
+			suggestions = append(suggestions, RuleSuggestion{
+				ID:         SuggestionID(SuggestionRetireRule, rule.Name),
+				Action:     SuggestionRetireRule,
+				Rule:       rule.Name,
+				Reason:     fmt.Sprintf("check %s is not registered, so this rule can never fire", rule.Check),
+				Confidence: 0.9,
+				Current:    &current,
+			})
+			continue
+		}
+
+		count := fired[rule.Name]
+		if count < noisyLimit {
+			continue
+		}
+
+		proposed := current
+		suggestion := RuleSuggestion{
+			Rule:       rule.Name,
+			Confidence: math.Min(0.5+float64(count-noisyLimit)/float64(4*noisyLimit), 0.95),
+			Current:    &current,
+			Proposed:   &proposed,
+		}
+		if rule.Reason != "" {
+			proposed.Threshold = math.Round(rule.Threshold*1.5*100) / 100
+			suggestion.Action = SuggestionAdjustThreshold
+			suggestion.Reason = fmt.Sprintf("fired %d times in %s; raising the threshold from %g to %g should cut noise",
+				count, in.Window, rule.Threshold, proposed.Threshold)
+		} else {
+			cooldown, _ := time.ParseDuration(rule.Cooldown)
+			if cooldown <= 0 {
+				cooldown = defaultRuleCooldown
+			}
+			proposed.Cooldown = (2 * cooldown).String()
+			suggestion.Action = SuggestionAdjustCooldown
+			suggestion.Reason = fmt.Sprintf("fired %d times in %s; doubling the cooldown to %s should cut repeat notifications",
+				count, in.Window, proposed.Cooldown)
+		}
+		suggestion.ID = SuggestionID(suggestion.Action, rule.Name)
+		suggestions = append(suggestions, suggestion)
+	}
+
+	for check, failures := range in.Failures {
+		if failures < recurringFailures || covered[check] || !registered[check] {
+			continue
+		}
+		name := strings.ToLower(check) + "-failing"
+		suggestions = append(suggestions, RuleSuggestion{
+			ID:         SuggestionID(SuggestionAddRule, name),
+			Action:     SuggestionAddRule,
+			Rule:       name,
+			Reason:     fmt.Sprintf("%s failed %d times in %s with no alert rule covering it", check, failures, in.Window),
+			Confidence: math.Min(0.5+0.1*float64(failures-recurringFailures), 0.9),
+			Proposed: &RuleSpec{
+				Name:     name,
+				Check:    check,
+				Severity: AlertSeverityWarning,
+				Cooldown: defaultRuleCooldown.String(),
+				Channel:  "log",
+			},
+		})
+	}
+
+	for i := range suggestions {
+		suggestions[i].Source = "heuristic"
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].ID < suggestions[j].ID
+	})
+	return suggestions
+}
+
+// SuggestionID builds the stable identifier used to apply a suggestion
+func SuggestionID(action SuggestionAction, rule string) string {
+	return fmt.Sprintf("%s:%s", action, strings.ToLower(rule))
+}
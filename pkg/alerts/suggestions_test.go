@@ -0,0 +1,165 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRuleSpec_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    RuleSpec
+		result  CheckResult
+		fires   bool
+		wantErr bool
+	}{
+		{
+			name:   "status rule defaults to unhealthy or degraded",
+			spec:   RuleSpec{Name: "dns", Check: "dns-health", Severity: AlertSeverityWarning},
+			result: CheckResult{Name: "dns-health", Status: HealthStatusDegraded},
+			fires:  true,
+		},
+		{
+			name:   "status rule ignores other checks",
+			spec:   RuleSpec{Name: "dns", Check: "dns-health", Status: HealthStatusUnhealthy, Severity: AlertSeverityWarning},
+			result: CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy},
+		},
+		{
+			name: "event rate rule",
+			spec: RuleSpec{Name: "evictions", Reason: "Evicted", Threshold: 2, Severity: AlertSeverityWarning},
+			result: CheckResult{
+				Name:    "event-rates",
+				Details: map[string]interface{}{"rates": map[string]float64{"Evicted": 3}},
+			},
+			fires: true,
+		},
+		{
+			name:    "missing check and reason",
+			spec:    RuleSpec{Name: "broken", Severity: AlertSeverityWarning},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cooldown",
+			spec:    RuleSpec{Name: "broken", Check: "pod-health", Severity: AlertSeverityWarning, Cooldown: "soon"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := tt.spec.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if rule.Name != tt.spec.Name {
+				t.Errorf("expected name %s, got %s", tt.spec.Name, rule.Name)
+			}
+			if rule.Cooldown != defaultRuleCooldown {
+				t.Errorf("expected default cooldown, got %v", rule.Cooldown)
+			}
+			if got := rule.Condition(tt.result); got != tt.fires {
+				t.Errorf("Condition() = %v, want %v", got, tt.fires)
+			}
+		})
+	}
+}
+
+func TestManager_UpsertAndRemoveRule(t *testing.T) {
+	manager := NewManager()
+	for _, rule := range CreateDefaultRules() {
+		manager.AddRule(rule)
+	}
+
+	fired := time.Now().Add(-time.Minute)
+	manager.rules[0].LastFired = fired
+
+	tuned, err := RuleSpec{Name: "pod-health-critical", Check: "pod-health", Status: HealthStatusUnhealthy,
+		Severity: AlertSeverityCritical, Cooldown: "20m"}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.UpsertRule(tuned)
+
+	rules := manager.Rules()
+	if len(rules) != 3 {
+		t.Fatalf("expected upsert to replace in place, got %d rules", len(rules))
+	}
+	if rules[0].Cooldown != "20m0s" || !rules[0].LastFired.Equal(fired) {
+		t.Errorf("expected tuned cooldown with preserved state, got %+v", rules[0])
+	}
+
+	if err := manager.RemoveRule("node-health-critical"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.RemoveRule("node-health-critical"); err == nil {
+		t.Error("expected error removing a missing rule")
+	}
+	if len(manager.Rules()) != 2 {
+		t.Errorf("expected 2 rules after removal, got %d", len(manager.Rules()))
+	}
+}
+
+func TestSuggestRules(t *testing.T) {
+	now := time.Now()
+	rules := make([]RuleInfo, 0)
+	for _, rule := range append(CreateDefaultRules(), NewEventRateRule("Evicted", 1, AlertSeverityWarning)) {
+		rules = append(rules, RuleInfo{RuleSpec: rule.Spec()})
+	}
+
+	history := make([]Alert, 0)
+	for i := 0; i < 20; i++ {
+		ts := now.Add(-time.Duration(i) * time.Hour)
+		history = append(history,
+			Alert{Timestamp: ts, Labels: map[string]string{"rule": "event-rate-evicted"}},
+			Alert{Timestamp: ts, Labels: map[string]string{"rule": "pod-health-warning"}},
+		)
+	}
+	// Old alerts outside the window don't count
+	history = append(history, Alert{Timestamp: now.Add(-48 * time.Hour), Labels: map[string]string{"rule": "pod-health-critical"}})
+
+	suggestions := SuggestRules(SuggestionInput{
+		Rules:    rules,
+		Alerts:   history,
+		Failures: map[string]int{"dns-health": 4, "service-health": 1, "pod-health": 9},
+		Checks:   []string{"pod-health", "event-rates", "dns-health", "service-health"},
+		Window:   24 * time.Hour,
+		Now:      now,
+	})
+
+	byID := make(map[string]RuleSuggestion)
+	for _, suggestion := range suggestions {
+		byID[suggestion.ID] = suggestion
+		if suggestion.Source != "heuristic" {
+			t.Errorf("expected heuristic source, got %s", suggestion.Source)
+		}
+	}
+	if len(byID) != 4 {
+		t.Fatalf("expected 4 suggestions, got %d: %+v", len(byID), suggestions)
+	}
+
+	threshold := byID[SuggestionID(SuggestionAdjustThreshold, "event-rate-evicted")]
+	if threshold.Proposed == nil || threshold.Proposed.Threshold != 1.5 {
+		t.Errorf("expected threshold raised to 1.5, got %+v", threshold.Proposed)
+	}
+
+	cooldown := byID[SuggestionID(SuggestionAdjustCooldown, "pod-health-warning")]
+	if cooldown.Proposed == nil || cooldown.Proposed.Cooldown != "20m0s" {
+		t.Errorf("expected cooldown doubled to 20m, got %+v", cooldown.Proposed)
+	}
+
+	retire := byID[SuggestionID(SuggestionRetireRule, "node-health-critical")]
+	if retire.Proposed != nil || retire.Current == nil {
+		t.Errorf("expected retirement of unregistered node-health rule, got %+v", retire)
+	}
+
+	add := byID[SuggestionID(SuggestionAddRule, "dns-health-failing")]
+	if add.Proposed == nil || add.Proposed.Check != "dns-health" {
+		t.Errorf("expected new rule for dns-health, got %+v", add.Proposed)
+	}
+	if _, err := add.Proposed.Build(); err != nil {
+		t.Errorf("expected proposed rule to build: %v", err)
+	}
+}
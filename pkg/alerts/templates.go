@@ -0,0 +1,156 @@
+package alerts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is what an alert rule's template renders. Templates are Go
+// text/template, e.g.
+//
+//	{{.Check.Name}} on {{.Cluster}}: {{.Check.Message}}{{with .Diagnosis}} ({{.Summary}}){{end}}
+type TemplateData struct {
+	Rule         string
+	Severity     AlertSeverity
+	Check        CheckResult
+	Cluster      string
+	Diagnosis    *Diagnosis // Nil until the check's failure has been diagnosed
+	Runbook      string
+	DashboardURL string // Empty without an external URL
+	SilenceURL   string
+}
+
+// Diagnosis is the latest AI diagnosis of a failing check (local copy to avoid cycle)
+type Diagnosis struct {
+	Summary         string   `json:"summary"`
+	Diagnosis       string   `json:"diagnosis,omitempty"`
+	Confidence      float64  `json:"confidence"`
+	Recommendations []string `json:"recommendations,omitempty"` // Recommendation titles
+}
+
+// templateFuncs are available to alert rule templates besides the
+// text/template builtins
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+	"truncate": func(n int, s string) string {
+		if n < 0 || len([]rune(s)) <= n {
+			return s
+		}
+		return string([]rune(s)[:n]) + "…"
+	},
+	"percent": func(f float64) string {
+		return fmt.Sprintf("%.0f%%", f*100)
+	},
+}
+
+// legacyTemplate reports whether a template is an older format string.
+// Its verbs take the check's name, status and message in that order, or
+// just the message when there is one verb.
+func legacyTemplate(text string) bool {
+	return !strings.Contains(text, "{{") && strings.Contains(text, "%")
+}
+
+// ParseTemplate parses an alert rule template
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("alert").Funcs(templateFuncs).Parse(text)
+}
+
+// ValidateTemplate parses a template and renders it against sample data
+// with every field set, so misspelled fields are caught when rules load
+// rather than when an alert fires
+func ValidateTemplate(text string) error {
+	if text == "" || legacyTemplate(text) {
+		return nil
+	}
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, sampleTemplateData()); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
+
+// RenderTemplate renders an alert message; an empty template renders the
+// check's message
+func RenderTemplate(text string, data TemplateData) (string, error) {
+	if text == "" {
+		return data.Check.Message, nil
+	}
+	if legacyTemplate(text) {
+		verbs := strings.Count(text, "%") - 2*strings.Count(text, "%%")
+		args := []interface{}{data.Check.Name, data.Check.Status, data.Check.Message}
+		if verbs == 1 {
+			args = args[2:]
+		}
+		return fmt.Sprintf(text, args[:min(max(verbs, 0), len(args))]...), nil
+	}
+
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// templateData gathers what a rule's template can reference for a result
+func (m *Manager) templateData(rule AlertRule, result CheckResult, alert Alert) TemplateData {
+	return TemplateData{
+		Rule:         rule.Name,
+		Severity:     rule.Severity,
+		Check:        result,
+		Cluster:      result.Cluster,
+		Diagnosis:    result.Diagnosis,
+		Runbook:      alert.Runbook,
+		DashboardURL: m.externalURL,
+		SilenceURL:   alert.SilenceURL,
+	}
+}
+
+// RenderRule renders the message a rule's alert would carry for a result,
+// as ProcessCheckResult does, without sending anything
+func (m *Manager) RenderRule(rule AlertRule, result CheckResult) (string, error) {
+	alert := Alert{Runbook: rule.Runbook, Labels: map[string]string{"check": result.Name, "rule": rule.Name}}
+	if alert.Runbook == "" {
+		alert.Runbook = result.Runbook
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.addSilenceLinks(&alert)
+	return RenderTemplate(rule.Template, m.templateData(rule, result, alert))
+}
+
+// sampleTemplateData is TemplateData with every field set, used to
+// validate templates
+func sampleTemplateData() TemplateData {
+	return TemplateData{
+		Rule:     "sample-rule",
+		Severity: AlertSeverityWarning,
+		Check: CheckResult{
+			Name:      "pod-health",
+			Status:    HealthStatusUnhealthy,
+			Message:   "3 pods are failing",
+			Details:   map[string]interface{}{},
+			Timestamp: time.Unix(0, 0),
+			Runbook:   "https://runbooks.example.com/pods",
+			Cluster:   "sample-cluster",
+		},
+		Cluster:      "sample-cluster",
+		Diagnosis:    &Diagnosis{Summary: "sample", Diagnosis: "sample", Confidence: 0.9, Recommendations: []string{"sample"}},
+		Runbook:      "https://runbooks.example.com/pods",
+		DashboardURL: "https://kubepulse.example.com",
+		SilenceURL:   "https://kubepulse.example.com/?silence.rule=sample-rule",
+	}
+}
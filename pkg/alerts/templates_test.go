@@ -0,0 +1,106 @@
+package alerts
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  string
+	}{
+		{template: ""},
+		{template: "Critical pod health issue: %s"},
+		{template: "{{.Check.Name}} on {{.Cluster}}: {{.Check.Message}}"},
+		{template: "{{with .Diagnosis}}{{.Summary}} ({{percent .Confidence}}){{end}}"},
+		{template: "{{.Check.Details.restarts}} restarts, see {{.Runbook}}"},
+		{template: "{{.Check.Nmae}}", wantErr: "can't evaluate field Nmae"},
+		{template: "{{.Check.Message", wantErr: "unclosed action"},
+		{template: "{{shout .Check.Message}}", wantErr: `function "shout" not defined`},
+	}
+	for _, tt := range tests {
+		err := ValidateTemplate(tt.template)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("ValidateTemplate(%q) unexpected error: %v", tt.template, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("ValidateTemplate(%q) error = %v, want %q", tt.template, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRuleSpec_ValidateTemplate(t *testing.T) {
+	spec := RuleSpec{Name: "pods", Check: "pod-health", Severity: AlertSeverityWarning, Template: "{{.Check.Nmae}}"}
+	if _, err := spec.Build(); err == nil || !strings.Contains(err.Error(), "rule pods: invalid template") {
+		t.Errorf("expected invalid template error, got %v", err)
+	}
+
+	spec.Template = ""
+	rule, err := spec.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	message, err := RenderTemplate(rule.Template, TemplateData{Check: CheckResult{Name: "pod-health", Message: "3 pods are failing"}})
+	if err != nil || message != "pod-health alert: 3 pods are failing" {
+		t.Errorf("expected default template message, got %q (%v)", message, err)
+	}
+}
+
+func TestRenderTemplate_Context(t *testing.T) {
+	manager := NewManager()
+	manager.SetExternalURL("https://kubepulse.example.com")
+	channel := &mockNotificationChannel{name: "log"}
+	manager.RegisterChannel(channel)
+
+	rule, err := RuleSpec{
+		Name:     "pods",
+		Check:    "pod-health",
+		Severity: AlertSeverityCritical,
+		Runbook:  "https://runbooks.example.com/pods",
+		Template: `[{{.Cluster}}] {{upper (printf "%s" .Severity)}} {{.Check.Name}}: {{.Check.Message}}
+{{- with .Diagnosis}}
+AI: {{.Summary}} ({{percent .Confidence}}) - {{join "; " .Recommendations}}{{end}}
+Runbook: {{.Runbook}}
+Dashboard: {{.DashboardURL}}`,
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.AddRule(rule)
+
+	result := CheckResult{
+		Name:      "pod-health",
+		Status:    HealthStatusUnhealthy,
+		Message:   "3 pods are failing",
+		Cluster:   "prod-eu",
+		Diagnosis: &Diagnosis{Summary: "Image pull failures", Confidence: 0.85, Recommendations: []string{"Fix the image tag", "Check registry credentials"}},
+	}
+	want := `[prod-eu] CRITICAL pod-health: 3 pods are failing
+AI: Image pull failures (85%) - Fix the image tag; Check registry credentials
+Runbook: https://runbooks.example.com/pods
+Dashboard: https://kubepulse.example.com`
+
+	preview, err := manager.RenderRule(rule, result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if preview != want {
+		t.Errorf("unexpected preview:\n%s\nwant:\n%s", preview, want)
+	}
+
+	if err := manager.ProcessCheckResult(context.Background(), result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channel.sentAlert == nil || channel.sentAlert.Message != want {
+		t.Fatalf("expected the alert to carry the rendered message, got %+v", channel.sentAlert)
+	}
+
+	// Without a diagnosis the section is left out
+	result.Diagnosis = nil
+	preview, _ = manager.RenderRule(rule, result)
+	if strings.Contains(preview, "AI:") {
+		t.Errorf("expected no diagnosis line, got:\n%s", preview)
+	}
+}
@@ -14,6 +14,36 @@ type Alert struct {
 	Fingerprint string                 `json:"fingerprint"`
 	Labels      map[string]string      `json:"labels,omitempty"`
 	Status      AlertStatus            `json:"status"`
+
+	Escalation     string     `json:"escalation,omitempty"` // Policy notifying channels until acknowledged
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	Runbook        string     `json:"runbook,omitempty"` // Response instructions for on-call
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+
+	// Set when an external URL is configured, so responders can silence
+	// the alert from the notification itself
+	SilenceURL     string `json:"silence_url,omitempty"`     // Dashboard link pre-filled with the alert's matchers
+	SilenceCommand string `json:"silence_command,omitempty"` // curl command creating the silence through the API
+
+	// When the condition began in the cluster, if known, and when the check
+	// result that fired the alert was produced, for pipeline latency
+	ObservedAt time.Time `json:"observed_at,omitzero"`
+	DetectedAt time.Time `json:"detected_at,omitzero"`
+
+	Owners []Ownership `json:"owners,omitempty"` // Owners of the affected workloads
+}
+
+// Ownership is who owns a workload, parsed from its labels and annotations
+type Ownership struct {
+	Team string `json:"team,omitempty"`
+	App  string `json:"app,omitempty"`
+	Tier string `json:"tier,omitempty"`
+}
+
+// IsZero reports whether no owner is known
+func (o Ownership) IsZero() bool {
+	return o == Ownership{}
 }
 
 // AlertSeverity defines the severity levels for alerts
@@ -32,6 +62,8 @@ const (
 	AlertStatusFiring   AlertStatus = "firing"
 	AlertStatusResolved AlertStatus = "resolved"
 	AlertStatusSilenced AlertStatus = "silenced"
+
+	AlertStatusAcknowledged AlertStatus = "acknowledged"
 )
 
 // CheckResult represents the result of a health check (local copy to avoid cycle)
@@ -41,6 +73,15 @@ type CheckResult struct {
 	Message   string                 `json:"message"`
 	Details   map[string]interface{} `json:"details,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
+	Runbook   string                 `json:"runbook,omitempty"` // Runbook of the check or affected resources
+
+	Cluster   string     `json:"cluster,omitempty"`
+	Diagnosis *Diagnosis `json:"diagnosis,omitempty"` // Latest AI diagnosis of the failure, if any
+	Chaos     string     `json:"chaos,omitempty"`     // Chaos experiment the failure is attributed to, if any
+
+	ObservedAt time.Time `json:"observed_at,omitzero"` // When the condition began in the cluster; zero when unknown
+
+	Owners []Ownership `json:"owners,omitempty"` // Owners of the failing workloads
 }
 
 // HealthStatus represents the health state of a component
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+)
+
+// maxAgentReportBody bounds the size of an agent report
+const maxAgentReportBody = 1 << 20
+
+// handleAgentReport stores the check summaries an agent pushes
+func (s *Server) handleAgentReport(w http.ResponseWriter, r *http.Request) {
+	var report fleet.AgentReport
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAgentReportBody)).Decode(&report); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := s.agents.Record(report); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, fleet.ErrTooManyAgents) {
+			status = http.StatusTooManyRequests
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListAgents lists the agents reporting to this server
+func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
+	agents := s.agents.Summaries()
+	s.writeJSON(w, map[string]interface{}{
+		"agents": agents,
+		"total":  len(agents),
+	})
+}
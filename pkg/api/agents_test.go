@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+)
+
+func TestServer_AgentReports(t *testing.T) {
+	server := NewServer(Config{
+		Credentials: []Credential{
+			{Name: "edge", Token: "agent-token-0123456789", Role: RoleAdmin},
+			{Name: "dashboard", Token: "viewer-token-0123456789", Role: RoleViewer},
+		},
+	})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	report := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/agents/report", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+		return rr
+	}
+	valid := `{"agent":"edge-1","interval":30000000000,"checks":[{"name":"node-health","status":"degraded"}]}`
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{"no token", "", valid, http.StatusUnauthorized},
+		{"wrong token", "not-a-token", valid, http.StatusUnauthorized},
+		{"viewer token", "viewer-token-0123456789", valid, http.StatusForbidden},
+		{"malformed body", "agent-token-0123456789", "{", http.StatusBadRequest},
+		{"invalid report", "agent-token-0123456789", `{"agent":"edge 1"}`, http.StatusBadRequest},
+		{"valid report", "agent-token-0123456789", valid, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := report(tt.token, tt.body); rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/agents", nil))
+	var list struct {
+		Agents []fleet.Summary `json:"agents"`
+		Total  int             `json:"total"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode agents: %v", err)
+	}
+	if list.Total != 1 || !list.Agents[0].Agent || list.Agents[0].Context != "edge-1" || list.Agents[0].Status != core.HealthStatusDegraded {
+		t.Errorf("expected the reported agent, got %+v", list)
+	}
+
+	// Agents appear as clusters in the fleet view, which needs no contexts
+	// and no kubeconfig when agents report
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/multi", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var multi fleet.Report
+	if err := json.Unmarshal(rr.Body.Bytes(), &multi); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if len(multi.Contexts) != 1 || multi.Contexts[0].Context != "edge-1" || multi.Status != core.HealthStatusDegraded {
+		t.Errorf("expected the agent in the fleet view, got %+v", multi)
+	}
+}
+
+func TestServer_AgentReportsWithoutTokens(t *testing.T) {
+	server := NewServer(Config{})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	body := `{"agent":"edge-1","interval":30000000000,"checks":[{"name":"node-health","status":"healthy"}]}`
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/agents/report", strings.NewReader(body)))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without configured tokens, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
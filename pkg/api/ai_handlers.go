@@ -2,11 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
 	"k8s.io/klog/v2"
 )
 
@@ -46,7 +48,7 @@ func (s *Server) HandleAssistantQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := s.engine.QueryAssistant(req.Query)
+	response, err := s.engine.QueryAssistant(r.Context(), req.Query)
 	if err != nil {
 		klog.Errorf("Assistant query failed: %v", err)
 		http.Error(w, "Query processing failed", http.StatusInternalServerError)
@@ -108,6 +110,8 @@ func (s *Server) HandleRemediationSuggestions(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	s.engine.RecordSuggestionsShown(checkName, s.requestIdentity(r).Name, suggestions)
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
 		"check":       checkName,
@@ -117,7 +121,8 @@ func (s *Server) HandleRemediationSuggestions(w http.ResponseWriter, r *http.Req
 	}
 }
 
-// HandleExecuteRemediation executes a remediation action
+// HandleExecuteRemediation executes an approved remediation action, or
+// dry-runs any suggested one
 func (s *Server) HandleExecuteRemediation(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -135,11 +140,52 @@ func (s *Server) HandleExecuteRemediation(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	actor := s.requestIdentity(r).Name
+	if s.readOnly && !req.DryRun {
+		s.engine.RecordExecution(req.ActionID, actor, req.DryRun, nil, core.ErrReadOnly)
+		s.writeReadOnly(w, "remediation execution")
+		return
+	}
+
 	record, err := s.engine.ExecuteRemediation(req.ActionID, req.DryRun)
+	s.engine.RecordExecution(req.ActionID, actor, req.DryRun, record, err)
+	if errors.Is(err, core.ErrReadOnly) {
+		s.writeReadOnly(w, "remediation execution")
+		return
+	}
 	if err != nil {
 		klog.Errorf("Remediation execution failed: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		s.Publish(WSMessageRemediationStatus, RemediationStatusData{
+			ActionID: req.ActionID,
+			DryRun:   req.DryRun,
+			Status:   RemediationStatusFailed,
+			Message:  err.Error(),
+			Record:   record,
+		})
+		http.Error(w, err.Error(), remediationErrorStatus(err))
+		return
+	}
+
+	status := RemediationStatusSucceeded
+	if !record.Success {
+		status = RemediationStatusFailed
+	}
+	s.Publish(WSMessageRemediationStatus, RemediationStatusData{
+		ActionID: req.ActionID,
+		DryRun:   req.DryRun,
+		Status:   status,
+		Message:  record.Result,
+		Record:   record,
+	})
+
+	if !req.DryRun {
+		s.engine.RecordChange(core.Change{
+			Kind:     core.ChangeKindRemediation,
+			Resource: "remediation/" + req.ActionID,
+			Message:  record.Action.Description,
+			To:       record.Result,
+			Details:  map[string]interface{}{"success": record.Success},
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
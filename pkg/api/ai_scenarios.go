@@ -0,0 +1,36 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+)
+
+// AIScenarioHeader asks AI test mode to answer a request with one of the
+// ai.Scenarios fixtures; the ai_scenario query parameter does the same
+const AIScenarioHeader = "X-KubePulse-AI-Scenario"
+
+// aiScenarioMiddleware carries the test mode scenario a request asks for
+// into its context, so frontend work and integration tests can exercise
+// each fixture without reconfiguring the server. Unknown scenarios are
+// refused; real providers ignore the scenario.
+func (s *Server) aiScenarioMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scenario := r.Header.Get(AIScenarioHeader)
+		if scenario == "" {
+			scenario = r.URL.Query().Get("ai_scenario")
+		}
+		if scenario == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !slices.Contains(ai.Scenarios, scenario) {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown AI scenario %q, expected one of %s", scenario, strings.Join(ai.Scenarios, ", ")))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ai.WithScenario(r.Context(), scenario)))
+	})
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAIScenarioMiddleware(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		status int
+		want   string
+	}{
+		{"no scenario", "/api/v1/ai/assistant/query", "", http.StatusOK, "healthy"},
+		{"header", "/api/v1/ai/assistant/query", ai.ScenarioCrashLoop, http.StatusOK, "crash-looping"},
+		{"query parameter", "/api/v1/ai/assistant/query?ai_scenario=etcd-degraded", "", http.StatusOK, "etcd"},
+		{"unknown", "/api/v1/ai/assistant/query", "meltdown", http.StatusBadRequest, "unknown AI scenario"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"query": "summarize the cluster"}`))
+			if tt.header != "" {
+				req.Header.Set(AIScenarioHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				if !strings.Contains(w.Body.String(), tt.want) {
+					t.Errorf("expected %q in the error, got %s", tt.want, w.Body.String())
+				}
+				return
+			}
+			var response ai.QueryResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !strings.Contains(response.Answer, tt.want) {
+				t.Errorf("expected the %s fixture answering, got %q", tt.want, response.Answer)
+			}
+		})
+	}
+}
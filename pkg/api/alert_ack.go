@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// maxSlackActionBody bounds the size of a Slack interaction request
+const maxSlackActionBody = 64 << 10
+
+// AlertAckRequest records who acknowledged an alert
+type AlertAckRequest struct {
+	By string `json:"by,omitempty"` // Defaults to "api"
+}
+
+// handleAckAlert acknowledges an alert, stopping its escalation
+func (s *Server) handleAckAlert(w http.ResponseWriter, r *http.Request) {
+	var req AlertAckRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			s.writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.By == "" {
+		req.By = "api"
+	}
+
+	alert, err := s.engine.AcknowledgeAlert(mux.Vars(r)["id"], req.By)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, alerts.ErrAlertNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+
+	s.writeJSON(w, alert)
+}
+
+// handleListEscalations lists unacknowledged alerts moving through escalation policies
+func (s *Server) handleListEscalations(w http.ResponseWriter, r *http.Request) {
+	escalations := s.engine.GetEscalations()
+	s.writeJSON(w, map[string]interface{}{
+		"escalations": escalations,
+		"total":       len(escalations),
+	})
+}
+
+// handleListDeliveries lists notifications channels failed to accept,
+// optionally only those in one state
+func (s *Server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	state := r.URL.Query().Get("state")
+	switch state {
+	case "", alerts.DeliveryPending, alerts.DeliveryDead:
+	default:
+		s.writeError(w, http.StatusBadRequest, "state must be pending or dead")
+		return
+	}
+
+	deliveries := s.engine.GetDeliveries(state)
+	s.writeJSON(w, map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
+
+// handleRedeliver immediately retries a queued or dead-lettered notification
+func (s *Server) handleRedeliver(w http.ResponseWriter, r *http.Request) {
+	delivery, err := s.engine.Redeliver(r.Context(), mux.Vars(r)["id"])
+	switch {
+	case errors.Is(err, alerts.ErrDeliveryNotFound):
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, alerts.ErrDeliveryFailed):
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	case err != nil:
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, delivery)
+}
+
+// handleSlackActions receives Slack interactivity callbacks, acknowledging
+// the alert whose Acknowledge button was clicked or silencing the alerts
+// matching the one whose Silence button was clicked
+func (s *Server) handleSlackActions(w http.ResponseWriter, r *http.Request) {
+	if s.slackSigningSecret == "" {
+		s.writeError(w, http.StatusServiceUnavailable, "Slack interactivity is not configured")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackActionBody))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := alerts.VerifySlackSignature(s.slackSigningSecret, r.Header, body, time.Now()); err != nil {
+		s.writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	action, ok, err := alerts.ParseSlackAction(body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Slack expects a quick 200 whatever the outcome; failures are only logged
+	switch action.ActionID {
+	case alerts.SlackAckActionID:
+		if _, err := s.engine.AcknowledgeAlert(action.AlertID, action.User); err != nil {
+			klog.Warningf("Slack acknowledgement failed: %v", err)
+		}
+	case alerts.SlackSilenceActionID:
+		if _, err := s.engine.AddSilence(alerts.Silence{
+			Matchers:  action.Matchers,
+			Until:     time.Now().Add(alerts.DefaultSilenceDuration),
+			CreatedBy: action.User,
+			Comment:   "Silenced from Slack",
+		}); err != nil {
+			klog.Warningf("Slack silence failed: %v", err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
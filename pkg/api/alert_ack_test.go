@@ -0,0 +1,205 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// escalatingEngine runs an always-unhealthy pod check whose critical alerts
+// escalate through the log channel, and returns the first alert's ID
+func escalatingEngine(t *testing.T) (*core.Engine, string) {
+	t.Helper()
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   20 * time.Millisecond,
+	})
+	if err := engine.AddEscalationPolicy(alerts.EscalationPolicy{
+		Name:       "pages",
+		Severities: []alerts.AlertSeverity{alerts.AlertSeverityCritical},
+		Steps:      []alerts.EscalationStep{{Channel: "log"}, {Channel: "log", After: time.Hour}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusUnhealthy})
+	go func() { _ = engine.Start() }()
+	t.Cleanup(engine.Stop)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if escalations := engine.GetEscalations(); len(escalations) > 0 {
+			return engine, escalations[0].AlertID
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected an escalation to start")
+	return nil, ""
+}
+
+func TestHandleAckAlert(t *testing.T) {
+	engine, alertID := escalatingEngine(t)
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts/escalations", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), alertID) {
+		t.Fatalf("expected the escalation to be listed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/alerts/missing/ack", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/alerts/"+alertID+"/ack", strings.NewReader(`{"by":"alice"}`))
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var alert alerts.Alert
+	if err := json.Unmarshal(w.Body.Bytes(), &alert); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if alert.Status != alerts.AlertStatusAcknowledged || alert.AcknowledgedBy != "alice" {
+		t.Errorf("expected alert acknowledged by alice, got %+v", alert)
+	}
+	for _, escalation := range engine.GetEscalations() {
+		if escalation.AlertID == alertID {
+			t.Error("expected acknowledgement to stop the escalation")
+		}
+	}
+}
+
+// signSlackBody signs a Slack interaction body as Slack would
+func signSlackBody(secret, body string) http.Header {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":" + body))
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func TestHandleSlackActions(t *testing.T) {
+	engine, alertID := escalatingEngine(t)
+	payload := `{"user":{"username":"bob"},"actions":[{"action_id":"kubepulse_ack","value":"` + alertID + `"}]}`
+	body := "payload=" + url.QueryEscape(payload)
+
+	tests := []struct {
+		name   string
+		secret string
+		signer string
+		status int
+	}{
+		{"not configured", "", "secret", http.StatusServiceUnavailable},
+		{"bad signature", "secret", "other", http.StatusUnauthorized},
+		{"acknowledged", "secret", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(Config{Engine: engine, SlackSigningSecret: tt.secret})
+			defer func() { _ = server.Shutdown(context.Background()) }()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/alerts/slack/actions", strings.NewReader(body))
+			req.Header = signSlackBody(tt.signer, body)
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	alert, err := engine.AcknowledgeAlert(alertID, "later")
+	if err != nil || alert.AcknowledgedBy != "slack:bob" {
+		t.Errorf("expected the Slack click to acknowledge the alert, got %+v (%v)", alert, err)
+	}
+}
+
+// downChannel rejects notifications while down
+type downChannel struct{ down bool }
+
+func (c *downChannel) Name() string { return "pager" }
+
+func (c *downChannel) Send(ctx context.Context, alert alerts.Alert) error {
+	if c.down {
+		return errors.New("503 service unavailable")
+	}
+	return nil
+}
+
+func TestHandleDeliveries(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	channel := &downChannel{down: true}
+	engine.RegisterAlertChannel(channel)
+	queue, err := alerts.NewDeliveryQueue(alerts.DeliveryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine.SetDeliveryQueue(queue)
+	delivery, err := queue.Enqueue("pager", alerts.DeliveryKindSend, alerts.Alert{Name: "pod-health-critical"}, errors.New("timeout"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/v1/alerts/deliveries?state=dead")
+	var list struct {
+		Deliveries []alerts.Delivery `json:"deliveries"`
+		Total      int               `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || list.Total != 1 || list.Deliveries[0].ID != delivery.ID {
+		t.Fatalf("expected the dead letter listed, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodGet, "/api/v1/alerts/deliveries?state=lost"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown state, got %d", w.Code)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		down   bool
+		status int
+	}{
+		{"unknown", "missing", false, http.StatusNotFound},
+		{"channel still down", delivery.ID, true, http.StatusBadGateway},
+		{"delivered", delivery.ID, false, http.StatusOK},
+		{"already delivered", delivery.ID, false, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		channel.down = tt.down
+		if w := serve(http.MethodPost, "/api/v1/alerts/deliveries/"+tt.id+"/redeliver"); w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// defaultSuggestionWindow is the history analyzed when window is omitted
+const defaultSuggestionWindow = 24 * time.Hour
+
+// handleListAlertRules returns the configured alert rules and their fire counts
+func (s *Server) handleListAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules := s.engine.GetAlertRules()
+	s.writeJSON(w, map[string]interface{}{
+		"rules": rules,
+		"total": len(rules),
+	})
+}
+
+// handleUpsertAlertRule adds a rule or replaces the rule with the same name
+func (s *Server) handleUpsertAlertRule(w http.ResponseWriter, r *http.Request) {
+	var spec alerts.RuleSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := s.engine.UpsertAlertRule(spec); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.writeJSON(w, spec)
+}
+
+// handleDeleteAlertRule removes an alert rule
+func (s *Server) handleDeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := s.engine.RemoveAlertRule(name); err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRuleSuggestions proposes alert rule changes from recent alert and
+// failure history; window is a duration and defaults to 24h
+func (s *Server) handleRuleSuggestions(w http.ResponseWriter, r *http.Request) {
+	window := defaultSuggestionWindow
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid window %q: use a duration like 24h", raw))
+			return
+		}
+		window = parsed
+	}
+
+	suggestions, err := s.engine.SuggestAlertRules(r.Context(), window)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, map[string]interface{}{
+		"window":       window.String(),
+		"generated_at": time.Now(),
+		"suggestions":  suggestions,
+	})
+}
+
+// handleApplyRuleSuggestion applies a suggestion from the latest run
+func (s *Server) handleApplyRuleSuggestion(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	suggestion, err := s.engine.ApplyRuleSuggestion(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrRuleSuggestionNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+
+	s.writeJSON(w, map[string]interface{}{
+		"applied": suggestion,
+		"rules":   s.engine.GetAlertRules(),
+	})
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRuleSuggestions_Apply(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusHealthy})
+	engine.AddCheck(&staticCheck{name: "dns-health", status: core.HealthStatusHealthy})
+	for i := 0; i < 3; i++ {
+		engine.RecordChange(core.Change{Kind: core.ChangeKindAlertFiring, Resource: "check/dns-health"})
+	}
+
+	server := &Server{engine: engine, router: mux.NewRouter()}
+	server.setupRoutes()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/alerts/rule-suggestions?window=1h", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var response struct {
+		Window      string                  `json:"window"`
+		Suggestions []alerts.RuleSuggestion `json:"suggestions"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Window != "1h0m0s" {
+		t.Errorf("expected 1h window, got %s", response.Window)
+	}
+
+	var addID string
+	for _, suggestion := range response.Suggestions {
+		if suggestion.Action == alerts.SuggestionAddRule && suggestion.Rule == "dns-health-failing" {
+			addID = suggestion.ID
+		}
+	}
+	if addID == "" {
+		t.Fatalf("expected add_rule suggestion for dns-health, got %+v", response.Suggestions)
+	}
+
+	apply := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/alerts/rule-suggestions/"+addID+"/apply", nil))
+		return rr
+	}
+
+	rr = apply()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 applying suggestion, got %d: %s", rr.Code, rr.Body.String())
+	}
+	found := false
+	for _, rule := range engine.GetAlertRules() {
+		if rule.Name == "dns-health-failing" && rule.Check == "dns-health" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected applied rule in the rule set")
+	}
+
+	changes, err := engine.GetChanges(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last := changes[len(changes)-1]; last.Kind != core.ChangeKindAlertRule || last.Resource != "alert-rule/dns-health-failing" {
+		t.Errorf("expected applied suggestion in the change feed, got %+v", last)
+	}
+
+	if rr = apply(); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 re-applying a suggestion, got %d", rr.Code)
+	}
+}
+
+func TestAlertRulesAPI(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := &Server{engine: engine, router: mux.NewRouter()}
+	server.setupRoutes()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"list", http.MethodGet, "/api/v1/alerts/rules", "", http.StatusOK},
+		{"add", http.MethodPost, "/api/v1/alerts/rules", `{"name":"dns","check":"dns-health","severity":"warning","cooldown":"5m"}`, http.StatusOK},
+		{"invalid severity", http.MethodPost, "/api/v1/alerts/rules", `{"name":"dns","check":"dns-health","severity":"loud"}`, http.StatusBadRequest},
+		{"delete", http.MethodDelete, "/api/v1/alerts/rules/dns", "", http.StatusNoContent},
+		{"delete missing", http.MethodDelete, "/api/v1/alerts/rules/dns", "", http.StatusNotFound},
+		{"bad window", http.MethodGet, "/api/v1/alerts/rule-suggestions?window=-1h", "", http.StatusBadRequest},
+		{"unknown suggestion", http.MethodPost, "/api/v1/alerts/rule-suggestions/nope/apply", "", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rr.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BatchAnalyzeRequest selects the checks to analyze together. Either name
+// the checks or set AllFailing to analyze every check that isn't healthy.
+type BatchAnalyzeRequest struct {
+	Checks     []string `json:"checks,omitempty"`
+	AllFailing bool     `json:"all_failing,omitempty"`
+}
+
+// handleAIAnalyzeBatch diagnoses several checks in one consolidated analysis
+func (s *Server) handleAIAnalyzeBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchAnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Checks) == 0 && !req.AllFailing {
+		s.writeError(w, http.StatusBadRequest, "checks or all_failing is required")
+		return
+	}
+	if len(req.Checks) > 0 && req.AllFailing {
+		s.writeError(w, http.StatusBadRequest, "checks and all_failing are mutually exclusive")
+		return
+	}
+
+	run, err := s.engine.SubmitBatchAnalysis(r.Context(), req.Checks)
+	if err != nil {
+		s.writeAnalysisError(w, err)
+		return
+	}
+	s.respondWithRun(w, r, run)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleAIAnalyzeBatch(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	disabled := NewServer(Config{Engine: core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})})
+	defer func() { _ = disabled.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name   string
+		server *Server
+		body   string
+		status int
+	}{
+		{"invalid body", server, `{`, http.StatusBadRequest},
+		{"nothing selected", server, `{}`, http.StatusBadRequest},
+		{"both selected", server, `{"checks": ["pod-health"], "all_failing": true}`, http.StatusBadRequest},
+		{"unknown check", server, `{"checks": ["pod-health"]}`, http.StatusNotFound},
+		{"AI disabled", disabled, `{"all_failing": true}`, http.StatusServiceUnavailable},
+		{"nothing failing", server, `{"all_failing": true}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/analyze/batch", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			tt.server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var batch ai.BatchAnalysis
+			if err := json.Unmarshal(w.Body.Bytes(), &batch); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(batch.Checks) != 0 || batch.Diagnoses == nil {
+				t.Errorf("expected an empty batch, got %+v", batch)
+			}
+		})
+	}
+}
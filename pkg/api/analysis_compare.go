@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// AnalysisCompareRequest selects two analyses of a cluster to compare. From
+// and To are session IDs or RFC 3339 timestamps; a timestamp selects the
+// latest analysis taken at or before it.
+type AnalysisCompareRequest struct {
+	Cluster   string `json:"cluster,omitempty"` // Defaults to the current context
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Narrative *bool  `json:"narrative,omitempty"` // Ask the AI for a narrative; defaults to true
+}
+
+// handleAnalysisSessions lists recorded cluster analyses that can be compared
+func (s *Server) handleAnalysisSessions(w http.ResponseWriter, r *http.Request) {
+	cluster := r.URL.Query().Get("cluster")
+	s.writeJSON(w, map[string]interface{}{
+		"cluster":  cluster,
+		"sessions": s.engine.GetAnalysisSessions(cluster),
+	})
+}
+
+// handleCompareAnalyses diffs two recorded analyses of a cluster
+func (s *Server) handleCompareAnalyses(w http.ResponseWriter, r *http.Request) {
+	var req AnalysisCompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.From == "" || req.To == "" {
+		s.writeError(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+
+	narrate := req.Narrative == nil || *req.Narrative
+	diff, err := s.engine.CompareAnalyses(r.Context(), req.Cluster, req.From, req.To, narrate)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrAnalysisSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+
+	s.writeJSON(w, diff)
+}
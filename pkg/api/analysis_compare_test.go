@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleCompareAnalyses(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient:  fake.NewSimpleClientset(),
+		ContextName: "prod",
+		EnableAI:    true,
+		AIConfig:    &ai.Config{TestMode: true},
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := engine.GetAIInsights(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ai/analysis/sessions?cluster=prod", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var sessions struct {
+		Sessions []ai.AnalysisSession `json:"sessions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(sessions.Sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions.Sessions))
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing from", `{"to": "analysis-2"}`, http.StatusBadRequest},
+		{"unknown session", `{"from": "analysis-9", "to": "analysis-2"}`, http.StatusNotFound},
+		{"compare", `{"from": "analysis-1", "to": "analysis-2", "narrative": false}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/analysis/compare", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var diff ai.AnalysisDiff
+			if err := json.Unmarshal(w.Body.Bytes(), &diff); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if diff.From.ID != "analysis-1" || diff.To.ID != "analysis-2" || diff.Narrative != "" {
+				t.Errorf("unexpected diff %+v", diff)
+			}
+		})
+	}
+}
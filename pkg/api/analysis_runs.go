@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// respondWithRun answers an analysis request with the run's result once it
+// finishes. Runs that are queued, that outlast the server's analysis wait,
+// or that the client asked for with "Prefer: respond-async" are answered
+// with 202 Accepted and the run; poll its Location or listen for
+// analysis.completed on the WebSocket for the result.
+func (s *Server) respondWithRun(w http.ResponseWriter, r *http.Request, run core.AnalysisRun) {
+	async := strings.Contains(strings.ToLower(r.Header.Get("Prefer")), "respond-async")
+	if !async && run.State != core.AnalysisRunQueued {
+		ctx, cancel := context.WithTimeout(r.Context(), s.analysisWait)
+		defer cancel()
+		waited, err := s.engine.WaitAnalysis(ctx, run.ID)
+		if err == nil {
+			run = waited
+		}
+	}
+
+	switch run.State {
+	case core.AnalysisRunSucceeded:
+		s.writeJSON(w, run.Result)
+	case core.AnalysisRunFailed:
+		s.writeError(w, http.StatusInternalServerError, run.Error)
+	default:
+		run.Result = nil
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/"+APIVersionV1+"/ai/analysis/runs/"+run.ID)
+		w.WriteHeader(http.StatusAccepted)
+		s.writeJSON(w, run)
+	}
+}
+
+// writeAnalysisError reports why an analysis couldn't be submitted
+func (s *Server) writeAnalysisError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, core.ErrCheckNotFound), errors.Is(err, core.ErrAnalysisRunNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, core.ErrBatchTooLarge):
+		status = http.StatusBadRequest
+	case errors.Is(err, core.ErrAIDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, core.ErrAnalysisQueueFull):
+		status = http.StatusTooManyRequests
+	}
+	s.writeError(w, status, err.Error())
+}
+
+// handleListAnalysisRuns lists the current cluster's on-demand analyses,
+// newest first, without their results
+func (s *Server) handleListAnalysisRuns(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.engine.ListAnalysisRuns())
+}
+
+// handleGetAnalysisRun returns an on-demand analysis and, once it has
+// succeeded, its result
+func (s *Server) handleGetAnalysisRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.engine.GetAnalysisRun(mux.Vars(r)["id"])
+	if err != nil {
+		s.writeAnalysisError(w, err)
+		return
+	}
+	s.writeJSON(w, run)
+}
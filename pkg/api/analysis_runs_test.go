@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnalysisRuns(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/ai/insights", nil)
+	req.Header.Set("Prefer", "respond-async")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var accepted core.AnalysisRun
+	if err := json.Unmarshal(w.Body.Bytes(), &accepted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	location := w.Header().Get("Location")
+	if accepted.ID == "" || location != "/api/v1/ai/analysis/runs/"+accepted.ID {
+		t.Fatalf("expected a run and its Location, got %+v at %q", accepted, location)
+	}
+	if _, err := engine.WaitAnalysis(context.Background(), accepted.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		verify func(t *testing.T, body []byte)
+	}{
+		{
+			name:   "finished run",
+			path:   location,
+			status: http.StatusOK,
+			verify: func(t *testing.T, body []byte) {
+				var run struct {
+					core.AnalysisRun
+					Result *ai.InsightSummary `json:"result"`
+				}
+				if err := json.Unmarshal(body, &run); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if run.State != core.AnalysisRunSucceeded || run.Kind != core.AnalysisKindCluster || run.Result == nil {
+					t.Errorf("expected a succeeded cluster analysis with its result, got %s", body)
+				}
+			},
+		},
+		{
+			name:   "list",
+			path:   "/api/v1/ai/analysis/runs",
+			status: http.StatusOK,
+			verify: func(t *testing.T, body []byte) {
+				var runs []core.AnalysisRun
+				if err := json.Unmarshal(body, &runs); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(runs) != 1 || runs[0].ID != accepted.ID || runs[0].Result != nil {
+					t.Errorf("expected the run without its result, got %s", body)
+				}
+			},
+		},
+		{
+			name:   "unknown run",
+			path:   "/api/v1/ai/analysis/runs/run-0-0",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.verify != nil {
+				tt.verify(t, w.Body.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// AnnotationRequest attaches an operator's note to a time range of a cluster
+type AnnotationRequest struct {
+	Cluster  string    `json:"cluster,omitempty"` // Defaults to the monitored cluster
+	Checks   []string  `json:"checks,omitempty"`  // Defaults to every check
+	Note     string    `json:"note"`
+	Author   string    `json:"author,omitempty"`
+	Start    time.Time `json:"start,omitzero"` // Defaults to now
+	End      time.Time `json:"end,omitzero"`
+	Duration string    `json:"duration,omitempty"` // e.g. "2h" after start; used when end is unset
+}
+
+// handleListAnnotations lists annotations, optionally those of one cluster
+// or check overlapping the from and to parameters, for health timelines
+func (s *Server) handleListAnnotations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var bounds [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		raw := strings.TrimSpace(query.Get(name))
+		if raw == "" {
+			continue
+		}
+		at, err := core.ParseHistoryTime(raw, time.Local)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, name+": "+err.Error())
+			return
+		}
+		bounds[i] = at
+	}
+
+	annotations := s.engine.GetAnnotations(query.Get("cluster"), query.Get("check"), bounds[0], bounds[1])
+	s.writeJSON(w, map[string]interface{}{
+		"annotations": annotations,
+		"total":       len(annotations),
+	})
+}
+
+// handleCreateAnnotation annotates a time range
+func (s *Server) handleCreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	var req AnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	start, end := req.Start, req.End
+	if start.IsZero() {
+		start = time.Now()
+	}
+	switch {
+	case !end.IsZero() && req.Duration != "":
+		s.writeError(w, http.StatusBadRequest, "set end or duration, not both")
+		return
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			s.writeError(w, http.StatusBadRequest, "duration must be a positive duration such as 2h")
+			return
+		}
+		end = start.Add(duration)
+	}
+
+	annotation, err := s.engine.AddAnnotation(core.Annotation{
+		Cluster: req.Cluster,
+		Checks:  req.Checks,
+		Note:    req.Note,
+		Author:  req.Author,
+		Start:   start,
+		End:     end,
+	})
+	switch {
+	case errors.Is(err, core.ErrCheckNotFound):
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, annotation)
+}
+
+// handleDeleteAnnotation removes an annotation
+func (s *Server) handleDeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	annotation, err := s.engine.DeleteAnnotation(mux.Vars(r)["id"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrAnnotationNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	s.writeJSON(w, annotation)
+}
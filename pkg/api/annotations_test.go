@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_Annotations(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod-eu"})
+	if _, err := engine.IngestMetrics("checkout", []core.Metric{{Name: "queue_depth", Value: 1}}); err != nil {
+		t.Fatalf("IngestMetrics() error = %v", err)
+	}
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rr
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "{", http.StatusBadRequest},
+		{"no note", `{"duration": "1h"}`, http.StatusBadRequest},
+		{"no end", `{"note": "load test running"}`, http.StatusBadRequest},
+		{"both ends", `{"note": "load test running", "duration": "1h", "end": "2099-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"bad duration", `{"note": "load test running", "duration": "-1h"}`, http.StatusBadRequest},
+		{"unknown check", `{"note": "load test running", "checks": ["dns-health"], "duration": "1h"}`, http.StatusNotFound},
+		{"created", `{"note": "load test running", "author": "alice", "checks": ["app:checkout"], "duration": "1h"}`, http.StatusCreated},
+		{"past range", `{"note": "migration window", "start": "2026-01-02T10:00:00Z", "end": "2026-01-02T12:00:00Z"}`, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := do(http.MethodPost, "/api/v1/annotations", tt.body); rr.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	var response struct {
+		Annotations []core.Annotation `json:"annotations"`
+		Total       int               `json:"total"`
+	}
+	rr := do(http.MethodGet, "/api/v1/annotations?cluster=prod-eu&from=2026-01-02T11:00&to=2026-01-02T11:30", "")
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Total != 1 || response.Annotations[0].Note != "migration window" || response.Annotations[0].Cluster != "prod-eu" {
+		t.Errorf("expected the migration window, got %+v", response)
+	}
+	if rr := do(http.MethodGet, "/api/v1/annotations?from=yesterday", ""); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid time, got %d", rr.Code)
+	}
+
+	id := response.Annotations[0].ID
+	if rr := do(http.MethodDelete, "/api/v1/annotations/"+id, ""); rr.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := do(http.MethodDelete, "/api/v1/annotations/"+id, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+}
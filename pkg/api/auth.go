@@ -0,0 +1,272 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Roles a token can grant, from least to most privileged
+const (
+	RoleViewer = "viewer"
+	RoleAdmin  = "admin"
+)
+
+// Scopes narrow what a token may reach beyond its role
+const (
+	ScopeAll    = ""       // Every route
+	ScopeAlerts = "alerts" // Alert routes and WebSocket alert messages only
+)
+
+var (
+	// ErrInvalidToken is returned for a missing or unknown token
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrTokenExpired is returned for a known token past its expiry
+	ErrTokenExpired = errors.New("token expired")
+
+	// ErrOutOfScope is returned for a request outside a token's scope
+	ErrOutOfScope = errors.New("request is outside the token's scope")
+)
+
+// Credential is a bearer token and the identity it grants
+type Credential struct {
+	Name      string
+	Token     string
+	Role      string
+	Scope     string    // ScopeAll or ScopeAlerts
+	Cluster   string    // Restricts the token to one cluster; empty allows all
+	ExpiresAt time.Time // Zero never expires
+}
+
+// Identity is who an authenticated client is and what it may receive
+type Identity struct {
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Scope     string    `json:"scope,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// anonymous is the identity of every client when no tokens are configured
+var anonymous = Identity{Name: "anonymous", Role: RoleAdmin}
+
+// topicRoles is the least privileged role allowed to receive each WebSocket
+// message type; types not listed are sent to every role
+var topicRoles = map[string]string{
+	WSMessageRemediationStatus: RoleAdmin,
+}
+
+// Authenticator checks bearer tokens against the configured credentials and
+// the tokens managed through the API
+type Authenticator struct {
+	credentials []Credential
+	now         func() time.Time
+
+	// Tokens created through the API, persisted to path when set
+	mu          sync.Mutex
+	managed     []managedToken
+	lastUsed    map[string]time.Time
+	used        bool // lastUsed changed since the tokens were saved
+	path        string
+	rotateAfter time.Duration
+	reminded    map[string]time.Time
+}
+
+// NewAuthenticator creates an authenticator, or returns nil when there are no
+// credentials and authentication is disabled
+func NewAuthenticator(credentials []Credential) *Authenticator {
+	if len(credentials) == 0 {
+		return nil
+	}
+	return newAuthenticator(credentials)
+}
+
+// newAuthenticator creates an authenticator even without credentials
+func newAuthenticator(credentials []Credential) *Authenticator {
+	return &Authenticator{
+		credentials: credentials,
+		now:         time.Now,
+		lastUsed:    make(map[string]time.Time),
+		rotateAfter: DefaultTokenRotation,
+		reminded:    make(map[string]time.Time),
+	}
+}
+
+// Authenticate returns the identity a token grants, recording its use
+func (a *Authenticator) Authenticate(token string) (Identity, error) {
+	if token == "" {
+		return Identity{}, ErrInvalidToken
+	}
+	for _, credential := range a.credentials {
+		if subtle.ConstantTimeCompare([]byte(credential.Token), []byte(token)) != 1 {
+			continue
+		}
+		return a.use(credential.Name, credential.Role, credential.Scope, credential.Cluster, credential.ExpiresAt)
+	}
+
+	// Copy the token while locked: Rotate and Revoke change entries in place
+	hash := hashToken(token)
+	a.mu.Lock()
+	var found managedToken
+	ok := false
+	for i := range a.managed {
+		if subtle.ConstantTimeCompare([]byte(a.managed[i].Hash), []byte(hash)) == 1 {
+			found, ok = a.managed[i], true
+			break
+		}
+	}
+	a.mu.Unlock()
+	if !ok {
+		return Identity{}, ErrInvalidToken
+	}
+	return a.use(found.Name, found.Role, found.Scope, found.Cluster, found.ExpiresAt)
+}
+
+// use returns the identity of an unexpired token and records its use
+func (a *Authenticator) use(name, role, scope, cluster string, expiresAt time.Time) (Identity, error) {
+	now := a.now()
+	if !expiresAt.IsZero() && !now.Before(expiresAt) {
+		return Identity{}, ErrTokenExpired
+	}
+	a.mu.Lock()
+	a.lastUsed[name] = now
+	a.used = true
+	a.mu.Unlock()
+	return Identity{Name: name, Role: role, Scope: scope, Cluster: cluster, ExpiresAt: expiresAt}, nil
+}
+
+// CanReceive reports whether the identity may receive a WebSocket message type
+func (i Identity) CanReceive(messageType string) bool {
+	if i.Scope == ScopeAlerts && !strings.HasPrefix(messageType, "alert.") {
+		return false
+	}
+	required, ok := topicRoles[messageType]
+	return !ok || roleRank(i.Role) >= roleRank(required)
+}
+
+// Scoped reports whether the identity is narrower than its role
+func (i Identity) Scoped() bool {
+	return i.Scope != ScopeAll || i.Cluster != ""
+}
+
+// allows checks a REST request against the identity's scope: alerts tokens
+// reach only alert routes, and single-cluster tokens only their cluster
+func (i Identity) allows(r *http.Request, cluster string) error {
+	if i.Scope == ScopeAlerts && !strings.HasPrefix(r.URL.Path, "/api/"+APIVersionV1+"/alerts") {
+		return fmt.Errorf("%w: %s tokens only reach /api/%s/alerts", ErrOutOfScope, ScopeAlerts, APIVersionV1)
+	}
+	if i.Cluster == "" {
+		return nil
+	}
+	if strings.HasSuffix(r.URL.Path, "/contexts/switch") {
+		return fmt.Errorf("%w: single-cluster tokens cannot switch contexts", ErrOutOfScope)
+	}
+	if cluster != "" && cluster != i.Cluster {
+		return fmt.Errorf("%w: token is limited to cluster %s", ErrOutOfScope, i.Cluster)
+	}
+	for _, name := range strings.Split(r.URL.Query().Get("contexts"), ",") {
+		if name = strings.TrimSpace(name); name != "" && name != i.Cluster {
+			return fmt.Errorf("%w: token is limited to cluster %s", ErrOutOfScope, i.Cluster)
+		}
+	}
+	return nil
+}
+
+// scopeMiddleware holds requests carrying a bearer token to its scope.
+// Requests without one are left to the route's own authentication, which
+// mutating and admin routes require once tokens are configured.
+func (s *Server) scopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if s.auth == nil || token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		identity, err := s.auth.Authenticate(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err := identity.allows(r, s.resolveClusterName(r)); err != nil {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// roleRank orders roles by privilege
+func roleRank(role string) int {
+	switch role {
+	case RoleViewer:
+		return 1
+	case RoleAdmin:
+		return 2
+	}
+	return 0
+}
+
+// adminOnly requires a bearer token granting the admin role. Without
+// configured tokens the request is refused rather than served anonymously.
+func (s *Server) adminOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.auth == nil {
+			s.writeError(w, http.StatusForbidden, "This endpoint requires API tokens; configure server.auth.tokens")
+			return
+		}
+		identity, err := s.auth.Authenticate(bearerToken(r))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			s.writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if roleRank(identity.Role) < roleRank(RoleAdmin) {
+			s.writeError(w, http.StatusForbidden, "This endpoint requires an admin token")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// authenticated requires a valid bearer token of any role when API tokens
+// are configured, and serves everyone otherwise
+func (s *Server) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.auth != nil {
+			if _, err := s.auth.Authenticate(bearerToken(r)); err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				s.writeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
+// requestIdentity returns who made a request: the identity its bearer token
+// grants, anonymous without configured tokens, or "unauthenticated"
+func (s *Server) requestIdentity(r *http.Request) Identity {
+	if s.auth == nil {
+		return anonymous
+	}
+	identity, err := s.auth.Authenticate(bearerToken(r))
+	if err != nil {
+		return Identity{Name: "unauthenticated"}
+	}
+	return identity
+}
+
+// bearerToken returns the token from a request's Authorization header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestAuthenticator(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	auth := NewAuthenticator([]Credential{
+		{Name: "dashboard", Token: "viewer-token-0123456789", Role: RoleViewer},
+		{Name: "oncall", Token: "admin-token-0123456789", Role: RoleAdmin, ExpiresAt: now.Add(time.Hour)},
+		{Name: "old", Token: "expired-token-0123456789", Role: RoleAdmin, ExpiresAt: now},
+	})
+	auth.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{"viewer", "viewer-token-0123456789", "dashboard", nil},
+		{"admin", "admin-token-0123456789", "oncall", nil},
+		{"expired", "expired-token-0123456789", "", ErrTokenExpired},
+		{"unknown", "guess", "", ErrInvalidToken},
+		{"missing", "", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := auth.Authenticate(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if identity.Name != tt.want {
+				t.Errorf("identity = %q, want %q", identity.Name, tt.want)
+			}
+		})
+	}
+
+	if NewAuthenticator(nil) != nil {
+		t.Error("expected authentication disabled without credentials")
+	}
+}
+
+func TestIdentity_CanReceive(t *testing.T) {
+	viewer := Identity{Name: "dashboard", Role: RoleViewer}
+	if !viewer.CanReceive(WSMessageHealthUpdated) || viewer.CanReceive(WSMessageRemediationStatus) {
+		t.Error("expected viewers to receive health but not remediation events")
+	}
+	admin := Identity{Name: "oncall", Role: RoleAdmin}
+	if !admin.CanReceive(WSMessageRemediationStatus) {
+		t.Error("expected admins to receive remediation events")
+	}
+	alerts := Identity{Name: "pager", Role: RoleAdmin, Scope: ScopeAlerts}
+	if !alerts.CanReceive(WSMessageAlertFired) || alerts.CanReceive(WSMessageHealthUpdated) {
+		t.Error("expected alerts tokens to receive alert events only")
+	}
+}
+
+// newAuthServer starts a server requiring a viewer or admin token
+func newAuthServer(t *testing.T, credentials ...Credential) (*Server, *httptest.Server) {
+	t.Helper()
+	if len(credentials) == 0 {
+		credentials = []Credential{
+			{Name: "dashboard", Token: "viewer-token-0123456789", Role: RoleViewer},
+			{Name: "oncall", Token: "admin-token-0123456789", Role: RoleAdmin},
+		}
+	}
+	server := NewServer(Config{CORSEnabled: true, Credentials: credentials})
+	ts := httptest.NewServer(server.router)
+	t.Cleanup(func() {
+		ts.Close()
+		_ = server.Shutdown(context.Background())
+	})
+	return server, ts
+}
+
+// dialWithToken connects to /ws passing token in the Authorization header
+func dialWithToken(ts *httptest.Server, token string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws?v=1", header)
+}
+
+func TestWebSocket_Authentication(t *testing.T) {
+	server, ts := newAuthServer(t)
+
+	_, resp, err := dialWithToken(ts, "guess")
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown token, got %v", err)
+	}
+
+	// Without a header token the first message must authenticate
+	conn, _, err := dialWithToken(ts, "")
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := conn.WriteJSON(WSAuthRequest{Type: WSMessageAuth, Token: "guess"}); err != nil {
+		t.Fatalf("failed to send auth: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+		t.Errorf("expected the connection closed for a bad token, got %v", err)
+	}
+	_ = conn.Close()
+
+	conn, _, err = dialWithToken(ts, "")
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.WriteJSON(WSAuthRequest{Type: WSMessageAuth, Token: "viewer-token-0123456789"}); err != nil {
+		t.Fatalf("failed to send auth: %v", err)
+	}
+	message := readEnvelope(t, conn)
+	var identity Identity
+	if err := json.Unmarshal(message.Data, &identity); err != nil {
+		t.Fatalf("failed to decode identity: %v", err)
+	}
+	if message.Type != WSMessageAuthenticated || identity.Name != "dashboard" || identity.Role != RoleViewer {
+		t.Errorf("unexpected auth response %s: %+v", message.Type, identity)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.clientsMu.RLock()
+		connected := len(server.clients)
+		server.clientsMu.RUnlock()
+		if connected == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("authenticated client was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocket_TopicAuthorization(t *testing.T) {
+	server, ts := newAuthServer(t)
+
+	viewer, _, err := dialWithToken(ts, "viewer-token-0123456789")
+	if err != nil {
+		t.Fatalf("failed to connect viewer: %v", err)
+	}
+	defer func() { _ = viewer.Close() }()
+	admin, _, err := dialWithToken(ts, "admin-token-0123456789")
+	if err != nil {
+		t.Fatalf("failed to connect admin: %v", err)
+	}
+	defer func() { _ = admin.Close() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.clientsMu.RLock()
+		connected := len(server.clients)
+		server.clientsMu.RUnlock()
+		if connected == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("clients were not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	server.Publish(WSMessageRemediationStatus, RemediationStatusData{ActionID: "restart-api", Status: RemediationStatusSucceeded})
+	server.Publish(WSMessageAlertResolved, AlertResolvedData{Name: "pod-health"})
+
+	if message := readEnvelope(t, admin); message.Type != WSMessageRemediationStatus {
+		t.Errorf("expected the admin to receive remediation status first, got %s", message.Type)
+	}
+	if message := readEnvelope(t, viewer); message.Type != WSMessageAlertResolved {
+		t.Errorf("expected the viewer to skip remediation status, got %s", message.Type)
+	}
+}
+
+func TestWebSocket_ClosesOnTokenExpiry(t *testing.T) {
+	_, ts := newAuthServer(t, Credential{
+		Name:      "ci",
+		Token:     "short-lived-token-0123",
+		Role:      RoleViewer,
+		ExpiresAt: time.Now().Add(200 * time.Millisecond),
+	})
+
+	conn, _, err := dialWithToken(ts, "short-lived-token-0123")
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != ErrTokenExpired.Error() {
+		t.Errorf("expected the connection closed on token expiry, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleListBackups reports the last scheduled backup and the backups kept
+func (s *Server) handleListBackups(w http.ResponseWriter, r *http.Request) {
+	if s.backups == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Backups are not enabled")
+		return
+	}
+	names, err := s.backups.Backups(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"status":  s.backups.Status(),
+		"backups": names,
+		"total":   len(names),
+	})
+}
+
+// handleCreateBackup takes a backup immediately
+func (s *Server) handleCreateBackup(w http.ResponseWriter, r *http.Request) {
+	if s.backups == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Backups are not enabled")
+		return
+	}
+	name, err := s.backups.BackupNow(r.Context())
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, map[string]interface{}{
+		"backup": name,
+		"status": s.backups.Status(),
+	})
+}
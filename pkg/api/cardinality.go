@@ -0,0 +1,14 @@
+package api
+
+import "net/http"
+
+// handleMetricCardinality reports the series and label values kept per
+// metric and the metrics that hit their cardinality limits
+func (s *Server) handleMetricCardinality(w http.ResponseWriter, r *http.Request) {
+	metrics := s.engine.GetCardinality()
+	s.writeJSON(w, map[string]interface{}{
+		"metrics":  metrics,
+		"total":    len(metrics),
+		"warnings": s.engine.CardinalityWarnings(),
+	})
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_MetricCardinality(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient:  fake.NewSimpleClientset(),
+		Cardinality: core.CardinalityConfig{MaxLabelValues: 1},
+	})
+	if _, err := engine.IngestMetrics("checkout", []core.Metric{
+		{Name: "request_total", Value: 1, Labels: map[string]string{"path": "/a"}},
+		{Name: "request_total", Value: 1, Labels: map[string]string{"path": "/b"}},
+	}); err != nil {
+		t.Fatalf("IngestMetrics() error = %v", err)
+	}
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/metrics/cardinality", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var response struct {
+		Metrics  []core.MetricCardinality `json:"metrics"`
+		Total    int                      `json:"total"`
+		Warnings []string                 `json:"warnings"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode cardinality: %v", err)
+	}
+	if response.Total != 1 || response.Metrics[0].Metric != "request_total" || response.Metrics[0].Rewritten != 1 {
+		t.Errorf("unexpected cardinality: %+v", response)
+	}
+	if len(response.Warnings) != 1 {
+		t.Errorf("expected a warning, got %v", response.Warnings)
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	if !strings.Contains(rr.Body.String(), `kubepulse_metric_points_rewritten_total{metric="request_total"} 1`+"\n") {
+		t.Errorf("expected cardinality self-metrics, got:\n%s", rr.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// defaultChangesWindow is used when the since parameter is omitted
+const defaultChangesWindow = 30 * time.Minute
+
+// handleChanges returns a consolidated feed of what changed in a time window.
+// since accepts a duration (30m, 2h) or an RFC3339 timestamp; kind optionally
+// filters to a comma-separated list of change kinds.
+func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	since, err := parseSince(r.URL.Query().Get("since"), now)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	changes, err := s.engine.GetChanges(r.Context(), since)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if kinds := parseCommaSet(r.URL.Query().Get("kind")); len(kinds) > 0 {
+		filtered := make([]core.Change, 0, len(changes))
+		for _, change := range changes {
+			if kinds[string(change.Kind)] {
+				filtered = append(filtered, change)
+			}
+		}
+		changes = filtered
+	}
+
+	counts := make(map[core.ChangeKind]int)
+	for _, change := range changes {
+		counts[change.Kind]++
+	}
+
+	s.writeJSON(w, map[string]interface{}{
+		"since":   since,
+		"until":   now,
+		"total":   len(changes),
+		"counts":  counts,
+		"changes": changes,
+	})
+}
+
+// parseSince converts a relative duration or absolute timestamp into a start time
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return now.Add(-defaultChangesWindow), nil
+	}
+
+	if window, err := time.ParseDuration(raw); err == nil {
+		if window <= 0 {
+			return time.Time{}, fmt.Errorf("since must be a positive duration")
+		}
+		return now.Add(-window), nil
+	}
+
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		return ts, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since %q: use a duration like 30m or an RFC3339 timestamp", raw)
+}
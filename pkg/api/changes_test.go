@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "default window", raw: "", want: now.Add(-30 * time.Minute)},
+		{name: "duration", raw: "2h", want: now.Add(-2 * time.Hour)},
+		{name: "timestamp", raw: "2024-05-01T11:15:00Z", want: time.Date(2024, 5, 1, 11, 15, 0, 0, time.UTC)},
+		{name: "negative duration", raw: "-5m", wantErr: true},
+		{name: "garbage", raw: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSince(tt.raw, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleChanges(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.RecordChange(core.Change{Kind: core.ChangeKindContextSwitch, Resource: "context/prod"})
+	engine.RecordChange(core.Change{Kind: core.ChangeKindRemediation, Resource: "remediation/restart-api"})
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/changes?since=10m&kind=remediation", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Total   int            `json:"total"`
+		Counts  map[string]int `json:"counts"`
+		Changes []core.Change  `json:"changes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Total != 1 || response.Changes[0].Resource != "remediation/restart-api" {
+		t.Errorf("expected only the remediation change, got %+v", response.Changes)
+	}
+	if response.Counts["remediation"] != 1 {
+		t.Errorf("expected remediation count 1, got %v", response.Counts)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/changes?since=soon", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid since, got %d", w.Code)
+	}
+}
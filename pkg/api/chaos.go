@@ -0,0 +1,21 @@
+package api
+
+import "net/http"
+
+// handleListChaosExperiments returns the resilience reports of running and
+// recently ended chaos experiments: the failures attributed to each and
+// when the checks recovered
+func (s *Server) handleListChaosExperiments(w http.ResponseWriter, r *http.Request) {
+	reports := s.engine.ChaosReports()
+	active := 0
+	for _, report := range reports {
+		if report.Active {
+			active++
+		}
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"experiments": reports,
+		"active":      active,
+		"total":       len(reports),
+	})
+}
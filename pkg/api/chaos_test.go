@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_ChaosExperiments(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.SetChaosExperiments([]core.ChaosExperiment{
+		{Tool: "litmus", Kind: "ChaosEngine", Namespace: "litmus", Name: "cart-delete", Namespaces: []string{"cart"}, Started: time.Now()},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/chaos/experiments", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var body struct {
+		Experiments []core.ChaosReport `json:"experiments"`
+		Active      int                `json:"active"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Active != 1 || len(body.Experiments) != 1 || body.Experiments[0].Experiment.ID() != "litmus/litmus/cart-delete" {
+		t.Errorf("expected the running experiment, got %+v", body)
+	}
+}
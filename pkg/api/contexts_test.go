@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: kubepulse
+users:
+- name: kubepulse
+  user:
+    token: test
+`
+
+func TestServer_CurrentContextRestrictedChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	contextManager, err := k8s.NewContextManager(path)
+	if err != nil {
+		t.Fatalf("NewContextManager() error = %v", err)
+	}
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod"})
+	server := NewServer(Config{Engine: engine, ContextManager: contextManager})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/contexts/current", nil))
+	var response struct {
+		Name             string                 `json:"name"`
+		Server           string                 `json:"server"`
+		RestrictedChecks []core.RestrictedCheck `json:"restricted_checks"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rr.Code != http.StatusOK || response.Name != "prod" || response.Server != "https://prod.example.com" || response.RestrictedChecks == nil {
+		t.Errorf("expected the context with its restricted checks, got %d: %s", rr.Code, rr.Body)
+	}
+}
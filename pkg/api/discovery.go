@@ -0,0 +1,11 @@
+package api
+
+import "net/http"
+
+// handleCheckDiscovery returns which checks run in the cluster and why:
+// checks of things the cluster doesn't have, such as a service mesh, are
+// disabled when discovery is on, and configuration can force any check on
+// or off
+func (s *Server) handleCheckDiscovery(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.engine.CheckDiscoveries())
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleCheckDiscovery(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), CheckDiscovery: true})
+	engine.AddCheck(health.NewServiceMeshHealthCheck(nil))
+	engine.AddCheck(health.NewNodeHealthCheck())
+	engine.RefreshInventory(context.Background())
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/checks/discovery", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var decisions []core.CheckDiscovery
+	if err := json.Unmarshal(w.Body.Bytes(), &decisions); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]bool{"node-health": true, "service-mesh": false}
+	if len(decisions) != len(want) {
+		t.Fatalf("expected a decision per check, got %+v", decisions)
+	}
+	for _, decision := range decisions {
+		if decision.Enabled != want[decision.Check] {
+			t.Errorf("unexpected decision %+v", decision)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/diagnostics"
+	"k8s.io/klog/v2"
+)
+
+// handleListDumps reports the self-diagnostics samples and the dumps kept
+func (s *Server) handleListDumps(w http.ResponseWriter, r *http.Request) {
+	if s.diagnostics == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Diagnostic dumps are not enabled")
+		return
+	}
+	dumps, err := s.diagnostics.Dumps()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"status": s.diagnostics.Status(),
+		"dumps":  dumps,
+		"total":  len(dumps),
+	})
+}
+
+// handleCreateDump captures a goroutine and heap dump immediately
+func (s *Server) handleCreateDump(w http.ResponseWriter, r *http.Request) {
+	if s.diagnostics == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Diagnostic dumps are not enabled")
+		return
+	}
+	dump, err := s.diagnostics.Capture("requested through the API")
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, dump)
+}
+
+// handleDownloadDump streams one file of a dump
+func (s *Server) handleDownloadDump(w http.ResponseWriter, r *http.Request) {
+	if s.diagnostics == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Diagnostic dumps are not enabled")
+		return
+	}
+	vars := mux.Vars(r)
+	f, err := s.diagnostics.Open(vars["id"], vars["file"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, diagnostics.ErrDumpNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	contentType := "application/octet-stream"
+	if vars["file"] == diagnostics.FileGoroutines {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+vars["id"]+"-"+vars["file"]+`"`)
+	if _, err := io.Copy(w, f); err != nil {
+		klog.Errorf("Failed to send dump %s: %v", vars["id"], err)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/diagnostics"
+)
+
+func TestHandleDumps(t *testing.T) {
+	monitor, err := diagnostics.NewMonitor(diagnostics.Config{Dir: t.TempDir()}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := NewServer(Config{
+		Diagnostics: monitor,
+		Credentials: []Credential{
+			{Name: "dashboard", Token: "viewer-token", Role: RoleViewer},
+			{Name: "oncall", Token: "admin-token", Role: RoleAdmin},
+		},
+	})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"no token", "", http.StatusUnauthorized},
+		{"unknown token", "guess", http.StatusUnauthorized},
+		{"viewer", "viewer-token", http.StatusForbidden},
+	} {
+		if w := do(http.MethodGet, "/api/v1/system/dumps", tt.token); w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+		}
+	}
+
+	w := do(http.MethodPost, "/api/v1/system/dumps", "admin-token")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var dump diagnostics.Dump
+	if err := json.Unmarshal(w.Body.Bytes(), &dump); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	w = do(http.MethodGet, "/api/v1/system/dumps", "admin-token")
+	var list struct {
+		Dumps []diagnostics.Dump `json:"dumps"`
+		Total int                `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || list.Total != 1 || list.Dumps[0].ID != dump.ID {
+		t.Fatalf("expected the dump listed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/api/v1/system/dumps/"+dump.ID+"/goroutines.txt", "admin-token")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine ") {
+		t.Errorf("expected goroutine stacks, got %d", w.Code)
+	}
+	if w = do(http.MethodGet, "/api/v1/system/dumps/"+dump.ID+"/dump.json", "admin-token"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a file outside the dump, got %d", w.Code)
+	}
+
+	// Without API tokens dumps are never served anonymously
+	open := NewServer(Config{Diagnostics: monitor})
+	defer func() { _ = open.Shutdown(context.Background()) }()
+	w = httptest.NewRecorder()
+	open.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/dumps", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without API tokens, got %d", w.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// EvaluationRequest asks for a check's latest result to be diagnosed by
+// both evaluated models
+type EvaluationRequest struct {
+	Check string `json:"check"`
+}
+
+// handleListEvaluations lists the kept A/B evaluations, newest first
+func (s *Server) handleListEvaluations(w http.ResponseWriter, r *http.Request) {
+	evaluations := s.engine.GetEvaluations()
+	s.writeJSON(w, map[string]interface{}{
+		"evaluations": evaluations,
+		"total":       len(evaluations),
+	})
+}
+
+// handleCreateEvaluation diagnoses a check with both evaluated models now
+func (s *Server) handleCreateEvaluation(w http.ResponseWriter, r *http.Request) {
+	var req EvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Check == "" {
+		s.writeError(w, http.StatusBadRequest, "check is required")
+		return
+	}
+
+	evaluation, err := s.engine.EvaluateCheck(r.Context(), req.Check)
+	if err != nil {
+		s.writeEvaluationError(w, err, http.StatusBadGateway)
+		return
+	}
+	s.writeJSON(w, evaluation)
+}
+
+// handleEvaluationFeedback records whether each model's diagnosis was
+// right and which was more useful
+func (s *Server) handleEvaluationFeedback(w http.ResponseWriter, r *http.Request) {
+	var feedback ai.EvaluationFeedback
+	if err := json.NewDecoder(r.Body).Decode(&feedback); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if feedback.By == "" {
+		feedback.By = s.requestIdentity(r).Name
+	}
+
+	evaluation, err := s.engine.RecordEvaluationFeedback(mux.Vars(r)["id"], feedback)
+	if err != nil {
+		s.writeEvaluationError(w, err, http.StatusBadRequest)
+		return
+	}
+	s.writeJSON(w, evaluation)
+}
+
+// handleEvaluationReport compares the evaluated models' accuracy from
+// feedback, speed, tokens and cost
+func (s *Server) handleEvaluationReport(w http.ResponseWriter, r *http.Request) {
+	report, err := s.engine.EvaluationReport()
+	if err != nil {
+		s.writeEvaluationError(w, err, http.StatusInternalServerError)
+		return
+	}
+	s.writeJSON(w, report)
+}
+
+// writeEvaluationError maps evaluation errors to HTTP statuses, status
+// for the rest
+func (s *Server) writeEvaluationError(w http.ResponseWriter, err error, status int) {
+	switch {
+	case errors.Is(err, core.ErrEvaluationDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, core.ErrCheckNotFound), errors.Is(err, ai.ErrEvaluationNotFound):
+		status = http.StatusNotFound
+	}
+	s.writeError(w, status, err.Error())
+}
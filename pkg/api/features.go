@@ -0,0 +1,84 @@
+package api
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// featuresInterval is how often feature flags are re-derived from the
+// backend, so the UI follows capability changes at runtime
+const featuresInterval = 15 * time.Second
+
+// UIFeatureFlags are the UI features to show: those enabled in ui.features
+// that the backend can serve right now. Configuration can only turn a
+// feature off; one the backend can't serve is off whatever the config says.
+type UIFeatureFlags struct {
+	AIInsights          bool `json:"aiInsights"`
+	PredictiveAnalytics bool `json:"predictiveAnalytics"`
+	SmartAlerts         bool `json:"smartAlerts"`
+	NodeDetails         bool `json:"nodeDetails"`
+	Remediation         bool `json:"remediation"`
+}
+
+// FeaturesChangedData is sent when feature flags or capabilities change
+type FeaturesChangedData struct {
+	Features     UIFeatureFlags  `json:"features"`
+	Capabilities map[string]bool `json:"capabilities"`
+}
+
+// featureState is the last feature flags and capabilities sent to clients
+type featureState struct {
+	mu           sync.Mutex
+	known        bool
+	flags        UIFeatureFlags
+	capabilities map[string]bool
+}
+
+// featureFlags derives the UI feature flags from the configured ones and
+// the backend's state: AI enabled with a healthy provider, the predictive
+// and smart alert modules running, and remediation permitted
+func (s *Server) featureFlags() UIFeatureFlags {
+	configured := s.uiConfig.Features
+	aiAvailable := s.engine != nil && s.engine.AIAvailable()
+	return UIFeatureFlags{
+		AIInsights:          configured.AIInsights && aiAvailable,
+		PredictiveAnalytics: configured.PredictiveAnalytics && aiAvailable && s.engine.PredictiveEnabled(),
+		SmartAlerts:         configured.SmartAlerts && aiAvailable && s.engine.SmartAlertsEnabled(),
+		NodeDetails:         configured.NodeDetails && s.engine != nil,
+		Remediation:         aiAvailable && s.engine.RemediationEnabled() && !s.readOnly,
+	}
+}
+
+// refreshFeatures re-derives the feature flags and capabilities and sends
+// them to clients when they changed since last time. It reports whether
+// they changed; the first call only records them.
+func (s *Server) refreshFeatures() bool {
+	flags, capabilities := s.featureFlags(), s.capabilities()
+
+	s.features.mu.Lock()
+	changed := s.features.known && (flags != s.features.flags || !maps.Equal(capabilities, s.features.capabilities))
+	s.features.known, s.features.flags, s.features.capabilities = true, flags, capabilities
+	s.features.mu.Unlock()
+
+	if changed {
+		s.Publish(WSMessageFeaturesChanged, FeaturesChangedData{Features: flags, Capabilities: capabilities})
+	}
+	return changed
+}
+
+// watchFeatures refreshes the feature flags until the server shuts down
+func (s *Server) watchFeatures() {
+	ticker := time.NewTicker(featuresInterval)
+	defer ticker.Stop()
+
+	s.refreshFeatures()
+	for {
+		select {
+		case <-ticker.C:
+			s.refreshFeatures()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_FeatureFlags(t *testing.T) {
+	allOn := config.UIFeatures{AIInsights: true, PredictiveAnalytics: true, SmartAlerts: true, NodeDetails: true}
+	withAI := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), EnableAI: true, AIConfig: &ai.Config{TestMode: true}})
+	withoutAI := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	tests := []struct {
+		name     string
+		engine   *core.Engine
+		features config.UIFeatures
+		readOnly bool
+		want     UIFeatureFlags
+	}{
+		{"AI enabled", withAI, allOn, false, UIFeatureFlags{AIInsights: true, PredictiveAnalytics: true, SmartAlerts: true, NodeDetails: true, Remediation: true}},
+		{"AI disabled overrides config", withoutAI, allOn, false, UIFeatureFlags{NodeDetails: true}},
+		{"config turns features off", withAI, config.UIFeatures{NodeDetails: true}, false, UIFeatureFlags{NodeDetails: true, Remediation: true}},
+		{"read-only forbids remediation", withAI, allOn, true, UIFeatureFlags{AIInsights: true, PredictiveAnalytics: true, SmartAlerts: true, NodeDetails: true}},
+		{"no engine", nil, allOn, false, UIFeatureFlags{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{engine: tt.engine, uiConfig: config.UIConfig{Features: tt.features}, readOnly: tt.readOnly}
+			if got := server.featureFlags(); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServer_RefreshFeatures(t *testing.T) {
+	server := &Server{uiConfig: config.UIConfig{Features: config.UIFeatures{NodeDetails: true}}}
+	if server.refreshFeatures() {
+		t.Error("expected the first refresh only to record the flags")
+	}
+	if server.refreshFeatures() {
+		t.Error("expected no change reported when nothing changed")
+	}
+	server.slackSigningSecret = "secret"
+	if !server.refreshFeatures() {
+		t.Error("expected a capability change reported")
+	}
+}
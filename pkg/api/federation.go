@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+)
+
+// maxFederationRequestSize bounds the body of a spoke analysis request
+const maxFederationRequestSize = 1 << 20
+
+// FederatedAnalyzeRequest selects the spokes a hub asks for analyses and the
+// checks they analyze. Without spokes every spoke is asked; without checks
+// each analyzes every check that isn't healthy.
+type FederatedAnalyzeRequest struct {
+	Spokes []string `json:"spokes,omitempty"`
+	Checks []string `json:"checks,omitempty"`
+}
+
+// handleFederatedAnalyze requests analyses from the configured spokes in
+// parallel and combines them
+func (s *Server) handleFederatedAnalyze(w http.ResponseWriter, r *http.Request) {
+	if s.hub == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Federation requires federation.spokes")
+		return
+	}
+	var req FederatedAnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	report, err := s.hub.Analyze(r.Context(), req.Spokes, federation.AnalysisRequest{Checks: req.Checks})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.writeJSON(w, report)
+}
+
+// handleSpokeAnalyze analyzes this instance's checks for a hub. Requests must
+// be signed by a configured hub, and every response after authentication is
+// signed so the hub can tell it came from this instance.
+func (s *Server) handleSpokeAnalyze(w http.ResponseWriter, r *http.Request) {
+	if s.spoke == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Federation requires federation.hubs")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFederationRequestSize))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	hub, signature, err := s.spoke.Authenticate(r, body)
+	if err != nil {
+		s.writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req federation.AnalysisRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		s.writeSignedError(w, hub, signature, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var analysis *ai.BatchAnalysis
+	aiError := ""
+	if s.engine.AIEnabled() {
+		// Hubs wait for the analysis; it still counts against the
+		// concurrency limit and is shared with identical requests
+		run, err := s.engine.SubmitBatchAnalysis(r.Context(), req.Checks)
+		if err == nil {
+			run, err = s.engine.WaitAnalysis(r.Context(), run.ID)
+		}
+		switch {
+		case errors.Is(err, core.ErrCheckNotFound):
+			s.writeSignedError(w, hub, signature, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, core.ErrBatchTooLarge):
+			s.writeSignedError(w, hub, signature, http.StatusBadRequest, err.Error())
+			return
+		case err != nil:
+			aiError = err.Error()
+		case run.State == core.AnalysisRunFailed:
+			aiError = run.Error
+		case run.State == core.AnalysisRunSucceeded:
+			analysis, _ = run.Result.(*ai.BatchAnalysis)
+		default:
+			aiError = "AI analysis did not finish before the request ended"
+		}
+	} else {
+		aiError = "AI analysis is disabled on this instance"
+	}
+
+	report := federation.NewSpokeReport(s.spoke.Instance(), s.engine.GetClusterHealth(s.resolveClusterName(r)), analysis)
+	report.AIError = aiError
+	s.writeSigned(w, hub, signature, http.StatusOK, report)
+}
+
+// writeSignedError writes an error response signed for a hub
+func (s *Server) writeSignedError(w http.ResponseWriter, hub, signature string, statusCode int, message string) {
+	s.writeSigned(w, hub, signature, statusCode, map[string]interface{}{
+		"error":   true,
+		"message": message,
+		"status":  statusCode,
+	})
+}
+
+// writeSigned writes a JSON response signed for the hub whose request,
+// carrying signature, it answers
+func (s *Server) writeSigned(w http.ResponseWriter, hub, signature string, statusCode int, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		return
+	}
+	s.spoke.SignResponse(w.Header(), hub, signature, statusCode, body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_FederatedAnalyze(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	spoke := NewServer(Config{
+		Engine: engine,
+		Spoke:  federation.NewSpoke("prod-us", []federation.Peer{{Name: "hub", Secret: secret}}),
+	})
+	defer func() { _ = spoke.Shutdown(context.Background()) }()
+	spokeHTTP := httptest.NewServer(spoke.router)
+	defer spokeHTTP.Close()
+
+	hub := NewServer(Config{
+		Hub: federation.NewHub("hub", []federation.Peer{{Name: "prod-us", URL: spokeHTTP.URL, Secret: secret}}, 5*time.Second),
+	})
+	defer func() { _ = hub.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "{", http.StatusBadRequest},
+		{"unknown spoke", `{"spokes":["prod-eu"]}`, http.StatusBadRequest},
+		{"every spoke", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			hub.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/federation/analyze", strings.NewReader(tt.body)))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var report federation.Report
+			if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if len(report.Spokes) != 1 || report.Spokes[0].Report == nil {
+				t.Fatalf("expected a report from prod-us, got %+v", report.Spokes)
+			}
+			got := report.Spokes[0].Report
+			if got.Instance != "prod-us" || !strings.Contains(got.AIError, "disabled") {
+				t.Errorf("expected a report without analysis from prod-us, got %+v", got)
+			}
+		})
+	}
+
+	// Spokes only answer signed requests
+	rr := httptest.NewRecorder()
+	spoke.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, federation.SpokeAnalyzePath, bytes.NewReader([]byte("{}"))))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected an unsigned request to be rejected, got %d", rr.Code)
+	}
+
+	// A hub doesn't serve analyses and a spoke doesn't request them
+	for _, tt := range []struct {
+		server *Server
+		path   string
+	}{{hub, federation.SpokeAnalyzePath}, {spoke, "/api/v1/federation/analyze"}} {
+		rr := httptest.NewRecorder()
+		tt.server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader("{}")))
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected %s to be unavailable, got %d", tt.path, rr.Code)
+		}
+	}
+}
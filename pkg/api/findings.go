@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+// handleListFindings lists tracked findings, optionally filtered by status,
+// check or finding type
+func (s *Server) handleListFindings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := core.FindingFilter{
+		Status: core.FindingStatus(query.Get("status")),
+		Check:  query.Get("check"),
+	}
+	switch filter.Status {
+	case "", core.FindingOpen, core.FindingResolved:
+	default:
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status %q: must be open or resolved", filter.Status))
+		return
+	}
+	if id := query.Get("id"); id != "" {
+		findingType, ok := findings.Lookup(id)
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown finding type %q", id))
+			return
+		}
+		filter.ID = findingType.ID
+	}
+
+	list := s.engine.GetFindings(filter)
+	open := 0
+	for _, finding := range list {
+		if finding.Status == core.FindingOpen {
+			open++
+		}
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"findings": list,
+		"total":    len(list),
+		"open":     open,
+	})
+}
+
+// handleFindingTypes lists the finding taxonomy
+func (s *Server) handleFindingTypes(w http.ResponseWriter, r *http.Request) {
+	types := findings.Catalog()
+	s.writeJSON(w, map[string]interface{}{
+		"types": types,
+		"total": len(types),
+	})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_Findings(t *testing.T) {
+	tracker, err := core.NewFindingTracker("", 0)
+	if err != nil {
+		t.Fatalf("NewFindingTracker() error = %v", err)
+	}
+	tracker.ObserveResult(core.CheckResult{Name: "node-health", Status: core.HealthStatusDegraded, Details: map[string]interface{}{
+		core.DetailFindings: []findings.Finding{
+			{ID: findings.NodeMemoryPressure, Subject: "node/worker-1"},
+			{ID: findings.NodeDiskPressure, Subject: "node/worker-2"},
+		},
+	}})
+	tracker.ObserveResult(core.CheckResult{Name: "node-health", Status: core.HealthStatusDegraded, Details: map[string]interface{}{
+		core.DetailFindings: []findings.Finding{{ID: findings.NodeMemoryPressure, Subject: "node/worker-1"}},
+	}})
+
+	server := NewServer(Config{Engine: core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), Findings: tracker})})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{"all", "", http.StatusOK, []string{findings.NodeMemoryPressure, findings.NodeDiskPressure}},
+		{"open", "?status=open", http.StatusOK, []string{findings.NodeMemoryPressure}},
+		{"by type", "?id=kp-node-002", http.StatusOK, []string{findings.NodeDiskPressure}},
+		{"other check", "?check=pod-health", http.StatusOK, []string{}},
+		{"invalid status", "?status=closed", http.StatusBadRequest, nil},
+		{"unknown type", "?id=KP-NODE-999", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/findings"+tt.query, nil))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Findings []core.FindingRecord `json:"findings"`
+				Total    int                  `json:"total"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode findings: %v", err)
+			}
+			ids := make([]string, 0, len(response.Findings))
+			for _, finding := range response.Findings {
+				ids = append(ids, finding.ID)
+			}
+			if len(ids) != len(tt.wantIDs) || response.Total != len(tt.wantIDs) {
+				t.Fatalf("expected %v, got %v", tt.wantIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("expected %v, got %v", tt.wantIDs, ids)
+				}
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/findings/types", nil))
+	var types struct {
+		Types []findings.Type `json:"types"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &types); err != nil || len(types.Types) != len(findings.Catalog()) {
+		t.Errorf("expected the finding catalog, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
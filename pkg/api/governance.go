@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/klog/v2"
+)
+
+// governanceColumns are the columns of the CSV governance report
+var governanceColumns = []string{
+	"id", "timestamp", "event", "check", "action_id", "action_type", "description", "commands", "risk",
+	"requires_approval", "actor", "dry_run", "outcome", "message", "provider", "model", "model_version",
+}
+
+// GovernanceSummary counts the records of a governance report
+type GovernanceSummary struct {
+	Suggested int            `json:"suggested"`
+	Executed  int            `json:"executed"`
+	DryRuns   int            `json:"dry_runs"`
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Refused   int            `json:"refused"`
+	ByActor   map[string]int `json:"by_actor"` // Executions per actor
+}
+
+// handleAIGovernance returns the audit report of AI remediation suggestions
+// shown and executed, with who executed them, the outcome and the model
+// behind each. since accepts a duration or RFC3339 timestamp and defaults to
+// everything retained; until is an RFC3339 timestamp. format=csv exports it
+// as CSV instead of JSON.
+func (s *Server) handleAIGovernance(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	query := r.URL.Query()
+	var since, until time.Time
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := parseSince(raw, now)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		since = parsed
+	}
+	if raw := query.Get("until"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid until %q: use an RFC3339 timestamp", raw))
+			return
+		}
+		until = parsed
+	}
+
+	records := s.engine.GovernanceRecords(since, until)
+	switch format := query.Get("format"); format {
+	case "", "json":
+		s.writeJSON(w, map[string]interface{}{
+			"since":   since,
+			"until":   until,
+			"summary": summarizeGovernance(records),
+			"records": records,
+			"total":   len(records),
+		})
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="kubepulse-ai-governance-`+now.UTC().Format("20060102T150405Z")+`.csv"`)
+		if err := writeGovernanceCSV(w, records); err != nil {
+			klog.Errorf("Failed to write governance report: %v", err)
+		}
+	default:
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid format %q: use json or csv", format))
+	}
+}
+
+// summarizeGovernance counts suggestions, executions and their outcomes
+func summarizeGovernance(records []core.GovernanceRecord) GovernanceSummary {
+	summary := GovernanceSummary{ByActor: make(map[string]int)}
+	for _, record := range records {
+		if record.Event == core.GovernanceSuggested {
+			summary.Suggested++
+			continue
+		}
+		summary.Executed++
+		summary.ByActor[record.Actor]++
+		if record.DryRun {
+			summary.DryRuns++
+		}
+		switch record.Outcome {
+		case core.GovernanceOutcomeSucceeded:
+			summary.Succeeded++
+		case core.GovernanceOutcomeFailed:
+			summary.Failed++
+		case core.GovernanceOutcomeRefused:
+			summary.Refused++
+		}
+	}
+	return summary
+}
+
+// writeGovernanceCSV writes records as CSV with a header row
+func writeGovernanceCSV(w http.ResponseWriter, records []core.GovernanceRecord) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(governanceColumns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := []string{
+			record.ID,
+			record.Timestamp.UTC().Format(time.RFC3339),
+			string(record.Event),
+			record.Check,
+			record.ActionID,
+			record.ActionType,
+			record.Description,
+			strings.Join(record.Commands, "; "),
+			string(record.Risk),
+			strconv.FormatBool(record.RequiresApproval),
+			record.Actor,
+			strconv.FormatBool(record.DryRun),
+			record.Outcome,
+			record.Message,
+			record.Provider,
+			record.Model,
+			record.ModelVersion,
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// handleHandoff summarizes the on-call shift since a duration or timestamp,
+// the last 8 hours by default
+func (s *Server) handleHandoff(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	since := now.Add(-core.DefaultHandoffWindow)
+	if raw := strings.TrimSpace(r.URL.Query().Get("since")); raw != "" {
+		var err error
+		if since, err = parseSince(raw, now); err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	s.writeJSON(w, s.engine.Handoff(r.URL.Query().Get("cluster"), since))
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_Handoff(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod"})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"default window", "", http.StatusOK},
+		{"duration", "?since=2h", http.StatusOK},
+		{"timestamp", "?since=2026-01-02T15:04:05Z", http.StatusOK},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/handoff"+tt.query, nil))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var handoff core.Handoff
+			if err := json.Unmarshal(rr.Body.Bytes(), &handoff); err != nil {
+				t.Fatalf("failed to decode handoff: %v", err)
+			}
+			if handoff.ClusterName != "prod" || handoff.Incidents == nil || handoff.FollowUps == nil {
+				t.Errorf("unexpected handoff: %+v", handoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// handleHealthAt returns the cluster health and check results as they were
+// at the time given by the timestamp parameter, for postmortems. Zoneless
+// timestamps are read in the server's local time.
+func (s *Server) handleHealthAt(w http.ResponseWriter, r *http.Request) {
+	raw := strings.TrimSpace(r.URL.Query().Get("timestamp"))
+	if raw == "" {
+		s.writeError(w, http.StatusBadRequest, "timestamp is required")
+		return
+	}
+	at, err := core.ParseHistoryTime(raw, time.Local)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if at.After(time.Now()) {
+		s.writeError(w, http.StatusBadRequest, "timestamp is in the future")
+		return
+	}
+
+	health, err := s.engine.HealthAt(s.resolveClusterName(r), at)
+	if errors.Is(err, core.ErrNoHistory) {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, health)
+}
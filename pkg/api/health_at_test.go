@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleHealthAt(t *testing.T) {
+	history, err := core.NewResultHistory("", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	incident := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	history.Record(core.CheckResult{Name: "pod-health", Status: core.HealthStatusHealthy, Timestamp: incident.Add(-10 * time.Minute)})
+	history.Record(core.CheckResult{Name: "pod-health", Status: core.HealthStatusUnhealthy, Message: "2 pods crashlooping", Timestamp: incident})
+
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), History: history})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"during the incident", "timestamp=" + url.QueryEscape(incident.Add(time.Minute).Format(time.RFC3339)) + "&cluster=prod", http.StatusOK},
+		{"missing timestamp", "", http.StatusBadRequest},
+		{"invalid timestamp", "timestamp=yesterday", http.StatusBadRequest},
+		{"future", "timestamp=" + url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339)), http.StatusBadRequest},
+		{"before any result", "timestamp=" + url.QueryEscape(incident.Add(-30*time.Minute).Format(time.RFC3339)), http.StatusNotFound},
+		{"outside retention", "timestamp=2024-06-01T14:00", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/health/at?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var health core.ClusterHealth
+			if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if health.ClusterName != "prod" || health.Status != core.HealthStatusUnhealthy {
+				t.Errorf("unexpected health %s: %s", health.ClusterName, health.Status)
+			}
+			if len(health.Checks) != 1 || health.Checks[0].Message != "2 pods crashlooping" {
+				t.Errorf("expected the check result at the time, got %+v", health.Checks)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// fullSnapshotEvery is how often delta clients get a full health.updated
+// anyway, so a client that misapplied a delta recovers within a few cycles
+const fullSnapshotEvery = 10
+
+// healthStream numbers health updates and remembers the last one, so clients
+// connected with deltas=1 can be sent only what changed
+type healthStream struct {
+	mu        sync.Mutex
+	seq       uint64
+	last      core.ClusterHealth
+	sinceFull int
+}
+
+// wantsDeltas reports whether a WebSocket client asked for health deltas
+func wantsDeltas(r *http.Request) bool {
+	deltas, _ := strconv.ParseBool(r.URL.Query().Get("deltas"))
+	return deltas
+}
+
+// PublishHealth pushes a cluster health update to WebSocket clients. Clients
+// connected with deltas=1 that received the previous update get a
+// health.delta with only the changed checks; the rest get the full health.
+func (s *Server) PublishHealth(health core.ClusterHealth) {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+
+	s.health.seq++
+	seq := s.health.seq
+	full := NewWSMessage(WSMessageHealthUpdated, health)
+	full.Seq = seq
+
+	var delta *WSMessage
+	s.health.sinceFull++
+	if seq > 1 && s.health.sinceFull < fullSnapshotEvery {
+		diff := core.DiffHealth(s.health.last, health)
+		diff.Base = seq - 1
+		message := NewWSMessage(WSMessageHealthDelta, diff)
+		message.Seq = seq
+		delta = &message
+	} else {
+		s.health.sinceFull = 0
+	}
+	s.health.last = health
+	s.health.last.Checks = append([]core.CheckResult(nil), health.Checks...) // Callers may reuse the slice
+
+	s.broadcastEach(func(client *wsClient) (interface{}, bool) {
+		if !client.identity.CanReceive(WSMessageHealthUpdated) || !client.subscribed(WSMessageHealthUpdated, nil) {
+			return nil, false
+		}
+		if delta != nil && client.deltas && client.healthSeq == seq-1 {
+			return *delta, true
+		}
+		return full, true
+	}, func(client *wsClient) {
+		client.healthSeq = seq
+	})
+}
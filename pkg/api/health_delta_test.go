@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestPublishHealth_Deltas(t *testing.T) {
+	server := NewServer(Config{CORSEnabled: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	plain := dialWebSocket(t, server, ts)
+	defer func() { _ = plain.Close() }()
+	deltas, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws?v=1&deltas=1", nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = deltas.Close() }()
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(5 * time.Millisecond) {
+		server.clientsMu.RLock()
+		connected := len(server.clients)
+		server.clientsMu.RUnlock()
+		if connected == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("delta client was not registered")
+		}
+	}
+
+	health := core.ClusterHealth{
+		ClusterName: "prod",
+		Status:      core.HealthStatusHealthy,
+		Checks: []core.CheckResult{
+			{Name: "pod-health", Status: core.HealthStatusHealthy},
+			{Name: "node-health", Status: core.HealthStatusHealthy},
+		},
+	}
+	server.PublishHealth(health)
+	health.Checks[0].Status = core.HealthStatusDegraded
+	server.PublishHealth(health)
+
+	if first := readEnvelope(t, plain); first.Type != WSMessageHealthUpdated {
+		t.Fatalf("expected health.updated, got %s", first.Type)
+	}
+	if second := readEnvelope(t, plain); second.Type != WSMessageHealthUpdated {
+		t.Errorf("expected full health for a client without deltas, got %s", second.Type)
+	}
+
+	if first := readEnvelope(t, deltas); first.Type != WSMessageHealthUpdated {
+		t.Fatalf("expected a full snapshot first, got %s", first.Type)
+	}
+	second := readEnvelope(t, deltas)
+	if second.Type != WSMessageHealthDelta {
+		t.Fatalf("expected health.delta, got %s", second.Type)
+	}
+	var delta core.HealthDelta
+	if err := json.Unmarshal(second.Data, &delta); err != nil {
+		t.Fatalf("failed to decode delta: %v", err)
+	}
+	if delta.Base != 1 || len(delta.Changed) != 1 || delta.Changed[0].Name != "pod-health" {
+		t.Errorf("expected a delta on seq 1 with only pod-health, got %+v", delta)
+	}
+
+	// Every fullSnapshotEvery updates a delta client gets the full health
+	for i := 2; i < fullSnapshotEvery; i++ {
+		server.PublishHealth(health)
+		if message := readEnvelope(t, deltas); message.Type != WSMessageHealthDelta {
+			t.Fatalf("update %d: expected health.delta, got %s", i+1, message.Type)
+		}
+	}
+	server.PublishHealth(health)
+	if message := readEnvelope(t, deltas); message.Type != WSMessageHealthUpdated {
+		t.Errorf("expected a periodic full snapshot, got %s", message.Type)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+)
+
+// handleHealthMulti probes the contexts listed in the contexts parameter in
+// parallel and returns a health summary for each, followed by the clusters
+// of agents reporting to this server. Each context is given the timeout
+// parameter, 10s by default, which must fit within the server's write
+// timeout. Without contexts only the agents are listed.
+func (s *Server) handleHealthMulti(w http.ResponseWriter, r *http.Request) {
+	var contexts []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(r.URL.Query().Get("contexts"), ",") {
+		if name = strings.TrimSpace(name); name != "" && !seen[name] {
+			seen[name] = true
+			contexts = append(contexts, name)
+		}
+	}
+	agents := s.agents.Summaries()
+	if len(contexts) == 0 && len(agents) == 0 {
+		s.writeError(w, http.StatusBadRequest, "contexts is required when no agents report")
+		return
+	}
+	if len(contexts) > 0 && s.fleet == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Multi-context health requires a kubeconfig")
+		return
+	}
+	if len(contexts) > fleet.MaxContexts {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("at most %d contexts can be probed at once", fleet.MaxContexts))
+		return
+	}
+
+	timeout := fleet.DefaultTimeout
+	if raw := r.URL.Query().Get("timeout"); raw != "" {
+		var err error
+		if timeout, err = time.ParseDuration(raw); err != nil || timeout <= 0 {
+			s.writeError(w, http.StatusBadRequest, "timeout must be a positive duration, e.g. 5s")
+			return
+		}
+	}
+	if writeTimeout := s.server.WriteTimeout; writeTimeout > 0 && timeout >= writeTimeout {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("timeout must be less than the server's write timeout of %s", writeTimeout))
+		return
+	}
+
+	report := fleet.Report{Contexts: []fleet.Summary{}, CheckedAt: time.Now()}
+	if len(contexts) > 0 {
+		report = s.fleet.Probe(r.Context(), contexts, timeout)
+	}
+	report.Contexts = append(report.Contexts, agents...)
+	statuses := make([]core.HealthStatus, len(report.Contexts))
+	for i, summary := range report.Contexts {
+		statuses[i] = summary.Status
+	}
+	report.Status = core.WorstStatus(statuses...)
+	s.writeJSON(w, report)
+}
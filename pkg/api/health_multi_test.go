@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_HealthMulti(t *testing.T) {
+	var probed []string
+	prober := fleet.NewProber(func(name string, timeout time.Duration) (kubernetes.Interface, error) {
+		if name == "missing" {
+			return nil, errors.New("context not found: missing")
+		}
+		probed = append(probed, name)
+		return fake.NewSimpleClientset(), nil
+	})
+	server := NewServer(Config{Fleet: prober})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{"no contexts", "", http.StatusBadRequest},
+		{"invalid timeout", "?contexts=prod&timeout=soon", http.StatusBadRequest},
+		{"timeout beyond the write timeout", "?contexts=prod&timeout=1m", http.StatusBadRequest},
+		{"too many contexts", "?contexts=a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u", http.StatusBadRequest},
+		{"probes each context once", "?contexts=prod,%20missing,prod&timeout=2s", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/multi"+tt.query, nil))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var report fleet.Report
+			if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if len(report.Contexts) != 2 || report.Contexts[0].Context != "prod" || report.Contexts[1].Error == "" {
+				t.Errorf("expected prod and an error for missing, got %+v", report.Contexts)
+			}
+			if len(probed) != 1 {
+				t.Errorf("expected prod to be probed once, got %v", probed)
+			}
+		})
+	}
+}
+
+func TestServer_HealthMultiUnavailable(t *testing.T) {
+	server := NewServer(Config{})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/multi?contexts=prod", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a kubeconfig, got %d", rr.Code)
+	}
+}
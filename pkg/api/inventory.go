@@ -0,0 +1,11 @@
+package api
+
+import "net/http"
+
+// handleInventory returns the cached cluster inventory: node counts by type
+// and version, namespace and workload counts, installed custom resources
+// and the versions of key components. It's refreshed on a slow cadence and
+// reports when it was collected.
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.engine.Inventory(r.Context()))
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleInventory(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)
+	server := NewServer(Config{Engine: core.NewEngine(core.EngineConfig{KubeClient: client})})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/inventory", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var inv inventory.Inventory
+	if err := json.Unmarshal(w.Body.Bytes(), &inv); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if inv.Nodes.Total != 1 || inv.Namespaces != 1 || inv.CollectedAt.IsZero() {
+		t.Errorf("unexpected inventory: %+v", inv)
+	}
+}
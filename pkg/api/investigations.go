@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// InvestigationRequest asks for an investigation plan for a check
+type InvestigationRequest struct {
+	Check string `json:"check"`
+}
+
+// handleListInvestigations lists saved investigation plans, optionally for one check
+func (s *Server) handleListInvestigations(w http.ResponseWriter, r *http.Request) {
+	investigations := s.engine.GetInvestigations(r.URL.Query().Get("check"))
+	s.writeJSON(w, map[string]interface{}{
+		"investigations": investigations,
+		"total":          len(investigations),
+	})
+}
+
+// handleCreateInvestigation builds and saves an investigation plan for a check
+func (s *Server) handleCreateInvestigation(w http.ResponseWriter, r *http.Request) {
+	var req InvestigationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Check == "" {
+		s.writeError(w, http.StatusBadRequest, "check is required")
+		return
+	}
+
+	plan, err := s.engine.PlanInvestigation(req.Check)
+	if err != nil {
+		s.writeInvestigationError(w, err)
+		return
+	}
+	s.writeJSON(w, plan)
+}
+
+// handleGetInvestigation returns a saved investigation plan with the output
+// of its last run
+func (s *Server) handleGetInvestigation(w http.ResponseWriter, r *http.Request) {
+	plan, err := s.engine.GetInvestigation(mux.Vars(r)["id"])
+	if err != nil {
+		s.writeInvestigationError(w, err)
+		return
+	}
+	s.writeJSON(w, plan)
+}
+
+// handleRunInvestigation runs the read-only steps of a saved investigation
+// plan; with refresh=true they ignore cached command output
+func (s *Server) handleRunInvestigation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh")); refresh {
+		ctx = ai.WithToolRefresh(ctx)
+	}
+	plan, err := s.engine.RunInvestigation(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		s.writeInvestigationError(w, err)
+		return
+	}
+	s.writeJSON(w, plan)
+}
+
+// writeInvestigationError maps investigation errors to statuses
+func (s *Server) writeInvestigationError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, core.ErrInvestigationNotFound) || errors.Is(err, core.ErrCheckNotFound) {
+		status = http.StatusNotFound
+	}
+	s.writeError(w, status, err.Error())
+}
+
+// handleRefreshToolResults drops cached kubectl output so the next AI
+// request reads current cluster state
+func (s *Server) handleRefreshToolResults(w http.ResponseWriter, r *http.Request) {
+	dropped := s.engine.RefreshToolResults()
+	s.writeJSON(w, map[string]interface{}{
+		"dropped": dropped,
+		"cache":   s.engine.GetToolCacheStats(),
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleInvestigations(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   20 * time.Millisecond,
+	})
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusUnhealthy})
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for _, ok := engine.GetResult("pod-health"); !ok && time.Now().Before(deadline); _, ok = engine.GetResult("pod-health") {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing check", `{}`, http.StatusBadRequest},
+		{"unknown check", `{"check": "dns-health"}`, http.StatusNotFound},
+		{"plan", `{"check": "pod-health"}`, http.StatusOK},
+	}
+	var plan ai.InvestigationPlan
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/investigations", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusOK {
+				if err := json.Unmarshal(w.Body.Bytes(), &plan); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+			}
+		})
+	}
+	if plan.ID == "" || plan.Check != "pod-health" || len(plan.Steps) == 0 {
+		t.Fatalf("expected a saved plan with steps, got %+v", plan)
+	}
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ai/investigations?check=pod-health", nil))
+	var list struct {
+		Investigations []ai.InvestigationPlan `json:"investigations"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(list.Investigations) != 1 || list.Investigations[0].ID != plan.ID {
+		t.Fatalf("expected the saved plan listed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/ai/investigations/" + plan.ID, http.StatusOK},
+		{http.MethodGet, "/api/v1/ai/investigations/investigation-99", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/ai/investigations/investigation-99/run", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.status, w.Code, w.Body.String())
+		}
+	}
+}
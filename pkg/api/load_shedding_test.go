@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/loadshed"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_LoadShedding(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	disabled := NewServer(Config{Engine: engine})
+	defer func() { _ = disabled.Shutdown(context.Background()) }()
+	w := httptest.NewRecorder()
+	disabled.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/load-shedding", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+
+	// A cgroup v2 working set of 900 of 1000 bytes
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"cgroup.controllers": "cpu memory",
+		"memory.current":     "950",
+		"memory.stat":        "inactive_file 50",
+		"memory.max":         "1000",
+		"cpu.stat":           "usage_usec 0",
+		"cpu.max":            "max 100000",
+	} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shedder := loadshed.NewShedder(loadshed.Config{Root: root, MemoryThreshold: 0.85})
+	shedder.Sample()
+
+	server := NewServer(Config{Engine: engine, LoadShedding: shedder})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/load-shedding", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var state loadshed.State
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !state.Shedding || state.MemoryBytes != 900 || state.Reason == "" {
+		t.Errorf("expected load shed at 90%% memory, got %+v", state)
+	}
+
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	var health struct {
+		Status       string          `json:"status"`
+		LoadShedding *loadshed.State `json:"load_shedding"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if health.Status != "degraded" || health.LoadShedding == nil {
+		t.Errorf("expected /health to report the degradation, got %+v", health)
+	}
+}
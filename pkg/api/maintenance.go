@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// CheckMaintenanceRequest puts a check under maintenance until a time or
+// for a duration
+type CheckMaintenanceRequest struct {
+	Reason   string    `json:"reason"`
+	Owner    string    `json:"owner"`
+	Until    time.Time `json:"until,omitzero"`
+	Duration string    `json:"duration,omitempty"` // e.g. "2h"; used when until is unset
+}
+
+// handleSetCheckMaintenance puts a check under maintenance
+func (s *Server) handleSetCheckMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req CheckMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	until := req.Until
+	switch {
+	case !until.IsZero() && req.Duration != "":
+		s.writeError(w, http.StatusBadRequest, "set until or duration, not both")
+		return
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			s.writeError(w, http.StatusBadRequest, "duration must be a positive duration such as 2h")
+			return
+		}
+		until = time.Now().Add(duration)
+	case until.IsZero():
+		s.writeError(w, http.StatusBadRequest, "maintenance needs an until time or a duration")
+		return
+	}
+
+	window, err := s.engine.SetCheckMaintenance(core.CheckMaintenance{
+		Check:  mux.Vars(r)["name"],
+		Reason: req.Reason,
+		Owner:  req.Owner,
+		Until:  until,
+	})
+	switch {
+	case errors.Is(err, core.ErrCheckNotFound):
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.writeJSON(w, window)
+}
+
+// handleEndCheckMaintenance ends a check's maintenance before it expires
+func (s *Server) handleEndCheckMaintenance(w http.ResponseWriter, r *http.Request) {
+	window, err := s.engine.EndCheckMaintenance(mux.Vars(r)["name"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrMaintenanceNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	s.writeJSON(w, window)
+}
+
+// handleListCheckMaintenance lists the checks under maintenance
+func (s *Server) handleListCheckMaintenance(w http.ResponseWriter, r *http.Request) {
+	windows := s.engine.GetCheckMaintenance()
+	s.writeJSON(w, map[string]interface{}{
+		"maintenance": windows,
+		"total":       len(windows),
+	})
+}
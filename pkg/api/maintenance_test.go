@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_CheckMaintenance(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if _, err := engine.IngestMetrics("checkout", []core.Metric{{Name: "queue_depth", Value: 1}}); err != nil {
+		t.Fatalf("IngestMetrics() error = %v", err)
+	}
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", http.MethodPost, "/api/v1/checks/app:checkout/maintenance", "{", http.StatusBadRequest},
+		{"no expiry", http.MethodPost, "/api/v1/checks/app:checkout/maintenance", `{"reason": "migration", "owner": "payments"}`, http.StatusBadRequest},
+		{"both expiries", http.MethodPost, "/api/v1/checks/app:checkout/maintenance",
+			`{"reason": "migration", "owner": "payments", "duration": "1h", "until": "2099-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"bad duration", http.MethodPost, "/api/v1/checks/app:checkout/maintenance", `{"reason": "migration", "owner": "payments", "duration": "-1h"}`, http.StatusBadRequest},
+		{"no owner", http.MethodPost, "/api/v1/checks/app:checkout/maintenance", `{"reason": "migration", "duration": "1h"}`, http.StatusBadRequest},
+		{"unknown check", http.MethodPost, "/api/v1/checks/dns-health/maintenance", `{"reason": "migration", "owner": "payments", "duration": "1h"}`, http.StatusNotFound},
+		{"set", http.MethodPost, "/api/v1/checks/app:checkout/maintenance", `{"reason": "migration", "owner": "payments", "duration": "1h"}`, http.StatusOK},
+		{"end", http.MethodDelete, "/api/v1/checks/app:checkout/maintenance", "", http.StatusOK},
+		{"end again", http.MethodDelete, "/api/v1/checks/app:checkout/maintenance", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rr.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	if _, err := engine.SetCheckMaintenance(core.CheckMaintenance{Check: "app:checkout", Reason: "migration", Owner: "payments",
+		Until: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("SetCheckMaintenance() error = %v", err)
+	}
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/checks/maintenance", nil))
+	var response struct {
+		Maintenance []core.CheckMaintenance `json:"maintenance"`
+		Total       int                     `json:"total"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode maintenance: %v", err)
+	}
+	if response.Total != 1 || response.Maintenance[0].Check != "app:checkout" {
+		t.Errorf("unexpected maintenance: %+v", response)
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/checks/app:checkout", nil))
+	if !strings.Contains(rr.Body.String(), `"maintenance":{"check":"app:checkout"`) {
+		t.Errorf("expected the result marked with its maintenance, got %s", rr.Body.String())
+	}
+}
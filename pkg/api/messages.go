@@ -0,0 +1,188 @@
+package api
+
+import (
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"k8s.io/klog/v2"
+)
+
+// WSProtocolVersion is the version of the WebSocket message envelope. It is
+// bumped only for breaking changes to an existing message; new message types
+// and new fields are added without a bump, so clients should ignore types
+// they don't recognize.
+const WSProtocolVersion = 1
+
+// WebSocket message types. The data payload for each type is noted alongside.
+const (
+	WSMessageHealthUpdated     = "health.updated"     // core.ClusterHealth
+	WSMessageHealthDelta       = "health.delta"       // core.HealthDelta, to clients connected with deltas=1
+	WSMessageAlertFired        = "alert.fired"        // core.Alert
+	WSMessageAlertResolved     = "alert.resolved"     // AlertResolvedData
+	WSMessageContextSwitched   = "context.switched"   // ContextSwitchedData
+	WSMessageAIInsight         = "ai.insight"         // core.AIInsightEvent
+	WSMessageAnalysisCompleted = "analysis.completed" // core.AnalysisRun, with its result when it succeeded
+	WSMessageRemediationStatus = "remediation.status" // RemediationStatusData, admins only
+	WSMessageFeaturesChanged   = "features.changed"   // FeaturesChangedData
+	WSMessageCheckResult       = "check.result"       // core.CheckResult, to clients subscribed to the check's topic
+	WSMessageAuthenticated     = "auth.ok"            // Identity
+	WSMessageSubscribed        = "subscribed"         // SubscriptionData, answering subscribe and unsubscribe
+	WSMessageError             = "error"              // WSErrorData, answering a message the server rejected
+)
+
+// wsTopics lists the message types the server pushes, as advertised in
+// /api/v1/config/ui
+var wsTopics = []string{
+	WSMessageHealthUpdated,
+	WSMessageHealthDelta,
+	WSMessageAlertFired,
+	WSMessageAlertResolved,
+	WSMessageContextSwitched,
+	WSMessageAIInsight,
+	WSMessageAnalysisCompleted,
+	WSMessageRemediationStatus,
+	WSMessageFeaturesChanged,
+	WSMessageCheckResult,
+}
+
+// WSMessageAuth is the message a client sends first to authenticate when it
+// couldn't pass a bearer token during the upgrade
+const WSMessageAuth = "auth"
+
+// wsAuthTimeout is how long a client has to send its auth message
+const wsAuthTimeout = 10 * time.Second
+
+// Remediation statuses reported in RemediationStatusData
+const (
+	RemediationStatusApproved  = "approved"
+	RemediationStatusRejected  = "rejected"
+	RemediationStatusSucceeded = "succeeded"
+	RemediationStatusFailed    = "failed"
+)
+
+// WSMessage is the envelope for every message pushed over /ws
+type WSMessage struct {
+	Version   int         `json:"v"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"ts"`
+	Seq       uint64      `json:"seq,omitempty"` // Health sequence number, on health messages
+	Data      interface{} `json:"data"`
+}
+
+// AlertResolvedData is sent when a check that was alerting becomes healthy
+type AlertResolvedData struct {
+	Name       string    `json:"name"`
+	Message    string    `json:"message,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// ContextSwitchedData is sent after the server switches Kubernetes context
+type ContextSwitchedData struct {
+	Context  k8s.ContextInfo `json:"context"`
+	Previous string          `json:"previous,omitempty"`
+}
+
+// RemediationStatusData reports the outcome of a remediation execution
+type RemediationStatusData struct {
+	ActionID string                `json:"action_id"`
+	DryRun   bool                  `json:"dry_run"`
+	Status   string                `json:"status"`
+	Message  string                `json:"message,omitempty"`
+	Record   *ai.RemediationRecord `json:"record,omitempty"`
+}
+
+// WSAuthRequest is the auth message a client sends first
+type WSAuthRequest struct {
+	Type  string `json:"type"`
+	Token string `json:"token"`
+}
+
+// NewWSMessage wraps a payload in a versioned envelope
+func NewWSMessage(messageType string, data interface{}) WSMessage {
+	return WSMessage{
+		Version:   WSProtocolVersion,
+		Type:      messageType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
+// Publish sends a typed message to the connected WebSocket clients allowed
+// to receive it and subscribed to its topic, or to a check it is about
+func (s *Server) Publish(messageType string, data interface{}) {
+	checks := messageChecks(data)
+	s.broadcast(NewWSMessage(messageType, data), func(client *wsClient) bool {
+		return client.identity.CanReceive(messageType) && client.subscribed(messageType, checks)
+	})
+}
+
+// relayEngineEvents forwards alerts, AI insights and finished analyses from the engine's event
+// journal to WebSocket clients until the server shuts down
+func (s *Server) relayEngineEvents() {
+	token := ""
+	alerting := make(map[string]bool)
+
+	for {
+		backlog, _, events, cancel := s.engine.SubscribeStream(token)
+		for _, event := range backlog {
+			token = event.ID
+			s.relayEvent(event, alerting)
+		}
+
+		for open := true; open; {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					// Fell behind; resubscribe from the last relayed event
+					open = false
+					break
+				}
+				token = event.ID
+				s.relayEvent(event, alerting)
+			case <-s.ctx.Done():
+				cancel()
+				return
+			}
+		}
+		cancel()
+		klog.V(2).Info("WebSocket relay fell behind the event journal, resubscribing")
+	}
+}
+
+// relayEvent converts a journal event into a WebSocket message. Alerts are
+// sent once when a check starts alerting and resolved when it recovers.
+func (s *Server) relayEvent(event core.StreamEvent, alerting map[string]bool) {
+	switch event.Type {
+	case core.StreamEventAlert:
+		alert, ok := event.Data.(core.Alert)
+		if !ok || alerting[alert.Name] {
+			return
+		}
+		alerting[alert.Name] = true
+		s.Publish(WSMessageAlertFired, alert)
+
+	case core.StreamEventCheckResult:
+		result, ok := event.Data.(core.CheckResult)
+		if !ok {
+			return
+		}
+		s.Publish(WSMessageCheckResult, result)
+		if !alerting[result.Name] || result.Status != core.HealthStatusHealthy {
+			return
+		}
+		delete(alerting, result.Name)
+		s.Publish(WSMessageAlertResolved, AlertResolvedData{
+			Name:       result.Name,
+			Message:    result.Message,
+			ResolvedAt: result.Timestamp,
+		})
+
+	case core.StreamEventAIInsight:
+		s.Publish(WSMessageAIInsight, event.Data)
+
+	case core.StreamEventAnalysisRun:
+		s.Publish(WSMessageAnalysisCompleted, event.Data)
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// wsEnvelope is a received WebSocket message with its payload left raw
+type wsEnvelope struct {
+	Version   int             `json:"v"`
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"ts"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// dialWebSocket connects to the server's /ws endpoint and waits until the
+// server has registered the client
+func dialWebSocket(t *testing.T, server *Server, ts *httptest.Server) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws?v=1"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.clientsMu.RLock()
+		connected := len(server.clients)
+		server.clientsMu.RUnlock()
+		if connected > 0 {
+			return conn
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("client was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readEnvelope(t *testing.T, conn *websocket.Conn) wsEnvelope {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message wsEnvelope
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+	if message.Version != WSProtocolVersion {
+		t.Errorf("expected protocol version %d, got %d", WSProtocolVersion, message.Version)
+	}
+	if message.Timestamp.IsZero() {
+		t.Error("expected message timestamp")
+	}
+	return message
+}
+
+func TestNewWSMessage(t *testing.T) {
+	data, err := json.Marshal(NewWSMessage(WSMessageAlertResolved, AlertResolvedData{Name: "pod-health"}))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	for _, key := range []string{"v", "type", "ts", "data"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected envelope field %q in %s", key, data)
+		}
+	}
+	if decoded["type"] != "alert.resolved" {
+		t.Errorf("expected alert.resolved, got %v", decoded["type"])
+	}
+}
+
+func TestWebSocket_RejectsUnsupportedVersion(t *testing.T) {
+	server := NewServer(Config{CORSEnabled: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ws?v=2", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unsupported version, got %d", rr.Code)
+	}
+}
+
+func TestRelayEvent(t *testing.T) {
+	server := NewServer(Config{CORSEnabled: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	conn := dialWebSocket(t, server, ts)
+	defer func() { _ = conn.Close() }()
+
+	alert := core.Alert{Name: "pod-health", Severity: core.AlertSeverityWarning}
+	alerting := make(map[string]bool)
+	events := []core.StreamEvent{
+		{Type: core.StreamEventAlert, Data: alert},
+		// Repeated alerts for an active check are not re-sent
+		{Type: core.StreamEventAlert, Data: alert},
+		{Type: core.StreamEventCheckResult, Data: core.CheckResult{Name: "pod-health", Status: core.HealthStatusDegraded}},
+		{Type: core.StreamEventCheckResult, Data: core.CheckResult{Name: "pod-health", Status: core.HealthStatusHealthy, Message: "all pods ready"}},
+		// Healthy results for checks that weren't alerting are ignored
+		{Type: core.StreamEventCheckResult, Data: core.CheckResult{Name: "node-health", Status: core.HealthStatusHealthy}},
+		{Type: core.StreamEventAIInsight, Data: core.AIInsightEvent{Check: "pod-health"}},
+	}
+	for _, event := range events {
+		server.relayEvent(event, alerting)
+	}
+
+	wantTypes := []string{WSMessageAlertFired, WSMessageAlertResolved, WSMessageAIInsight}
+	for _, want := range wantTypes {
+		message := readEnvelope(t, conn)
+		if message.Type != want {
+			t.Fatalf("expected %s, got %s", want, message.Type)
+		}
+		if message.Type == WSMessageAlertResolved {
+			var resolved AlertResolvedData
+			if err := json.Unmarshal(message.Data, &resolved); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			if resolved.Name != "pod-health" || resolved.Message != "all pods ready" {
+				t.Errorf("unexpected resolved payload: %+v", resolved)
+			}
+		}
+	}
+	if len(alerting) != 0 {
+		t.Errorf("expected no active alerts after resolution, got %v", alerting)
+	}
+}
+
+func TestWebSocket_EngineAlerts(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   20 * time.Millisecond,
+	})
+	engine.AddCheck(&staticCheck{name: "flaky", status: core.HealthStatusDegraded})
+
+	server := NewServer(Config{Engine: engine, CORSEnabled: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	conn := dialWebSocket(t, server, ts)
+	defer func() { _ = conn.Close() }()
+
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+
+	message := readEnvelope(t, conn)
+	if message.Type != WSMessageAlertFired {
+		t.Fatalf("expected alert.fired, got %s", message.Type)
+	}
+	var alert core.Alert
+	if err := json.Unmarshal(message.Data, &alert); err != nil {
+		t.Fatalf("failed to decode alert: %v", err)
+	}
+	if alert.Name != "flaky" {
+		t.Errorf("expected alert for the flaky check, got %+v", alert)
+	}
+}
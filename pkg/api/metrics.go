@@ -0,0 +1,260 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+// checkStatuses are the values of the status label of kubepulse_check_status
+var checkStatuses = []core.HealthStatus{
+	core.HealthStatusHealthy,
+	core.HealthStatusDegraded,
+	core.HealthStatusUnhealthy,
+	core.HealthStatusRestricted,
+	core.HealthStatusUnknown,
+}
+
+var (
+	healthScoreDesc = prometheus.NewDesc("kubepulse_health_score",
+		"Cluster health score from 0 to 100, raw or weighted by severity and blast radius.",
+		[]string{"score"}, nil)
+	checkStatusDesc = prometheus.NewDesc("kubepulse_check_status",
+		"Whether a health check's latest result has the status, 1 for its current status and 0 for the others.",
+		[]string{"check", "status"}, nil)
+	aiCallsDesc = prometheus.NewDesc("kubepulse_ai_calls_total",
+		"AI provider calls by kind and outcome.",
+		[]string{"kind", "outcome"}, nil)
+)
+
+// metricsExporter serves KubePulse's metrics to Prometheus from a registry
+// of its own: check durations and alerts counted as they happen, health,
+// AI calls and WebSocket clients read on scrape, and the metrics checks and
+// the engine report, converted as they are
+type metricsExporter struct {
+	registry      *prometheus.Registry
+	handler       http.Handler
+	checkDuration *prometheus.HistogramVec
+	alerts        *prometheus.CounterVec
+}
+
+func newMetricsExporter(s *Server) *metricsExporter {
+	m := &metricsExporter{
+		registry: prometheus.NewRegistry(),
+		checkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kubepulse_check_duration_seconds",
+			Help:    "How long health check runs took.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"check"}),
+		alerts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kubepulse_alerts_total",
+			Help: "Alerts raised for failing health checks, by severity.",
+		}, []string{"severity"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.checkDuration,
+		m.alerts,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kubepulse_websocket_clients",
+			Help: "Connected WebSocket clients.",
+		}, func() float64 {
+			s.clientsMu.RLock()
+			defer s.clientsMu.RUnlock()
+			return float64(len(s.clients))
+		}),
+	)
+	if s.engine != nil {
+		m.registry.MustRegister(engineCollector{engine: s.engine}, reportedMetrics{server: s})
+		removeDurations := s.engine.OnCheckResult("prometheus", func(result core.CheckResult) {
+			m.checkDuration.WithLabelValues(result.Name).Observe(result.Duration.Seconds())
+		})
+		removeAlerts := s.engine.OnAlert("prometheus", func(alert core.Alert) {
+			m.alerts.WithLabelValues(string(alert.Severity)).Inc()
+		})
+		go func() {
+			<-s.ctx.Done()
+			removeDurations()
+			removeAlerts()
+		}()
+	}
+
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorLog:          promLogger{},
+		ErrorHandling:     promhttp.ContinueOnError,
+		EnableOpenMetrics: true,
+	})
+	return m
+}
+
+// promLogger logs metric collection errors
+type promLogger struct{}
+
+func (promLogger) Println(v ...interface{}) {
+	klog.Warning(v...)
+}
+
+// engineCollector reads cluster health and AI call counts on scrape
+type engineCollector struct {
+	engine *core.Engine
+}
+
+func (c engineCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- healthScoreDesc
+	ch <- checkStatusDesc
+	ch <- aiCallsDesc
+}
+
+func (c engineCollector) Collect(ch chan<- prometheus.Metric) {
+	health := c.engine.GetClusterHealth("")
+	ch <- prometheus.MustNewConstMetric(healthScoreDesc, prometheus.GaugeValue, health.Score.Raw, "raw")
+	ch <- prometheus.MustNewConstMetric(healthScoreDesc, prometheus.GaugeValue, health.Score.Weighted, "weighted")
+	for _, check := range health.Checks {
+		for _, status := range checkStatuses {
+			value := 0.0
+			if check.Status == status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(checkStatusDesc, prometheus.GaugeValue, value, check.Name, string(status))
+		}
+	}
+	for _, stats := range c.engine.GetAICallStats() {
+		ch <- prometheus.MustNewConstMetric(aiCallsDesc, prometheus.CounterValue, float64(stats.Succeeded), stats.Kind, "success")
+		ch <- prometheus.MustNewConstMetric(aiCallsDesc, prometheus.CounterValue, float64(stats.Failed), stats.Kind, "error")
+	}
+}
+
+// exportedNames are the metric names the exporter's own collectors own;
+// reported metrics of the same name are dropped
+var exportedNames = map[string]bool{
+	"kubepulse_check_duration_seconds": true,
+	"kubepulse_alerts_total":           true,
+	"kubepulse_websocket_clients":      true,
+	"kubepulse_health_score":           true,
+	"kubepulse_check_status":           true,
+	"kubepulse_ai_calls_total":         true,
+}
+
+// reportedMetrics converts the metrics checks and the engine report into
+// Prometheus metrics on scrape. Their names and label sets aren't known
+// up front, so the collector is unchecked.
+type reportedMetrics struct {
+	server *Server
+}
+
+func (c reportedMetrics) Describe(chan<- *prometheus.Desc) {}
+
+func (c reportedMetrics) Collect(ch chan<- prometheus.Metric) {
+	engine := c.server.engine
+	var metrics []core.Metric
+	for _, result := range engine.GetResults() {
+		metrics = append(metrics, result.Metrics...)
+	}
+	metrics = append(metrics, engine.GetWatchdogMetrics()...)
+	metrics = append(metrics, engine.GetCardinalityMetrics()...)
+	metrics = append(metrics, engine.GetLatencyMetrics()...)
+	metrics = append(metrics, c.server.deprecationMetrics()...)
+
+	for _, metric := range promMetrics(metrics) {
+		ch <- metric
+	}
+}
+
+var (
+	invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+	invalidLabelChars  = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// promName makes a metric or label name valid for Prometheus
+func promName(name string, invalid *regexp.Regexp) string {
+	name = invalid.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// promMetrics converts reported metrics into Prometheus metrics. Series of a
+// name share the union of their label names, missing labels being empty,
+// and a series reported twice keeps its last value.
+func promMetrics(metrics []core.Metric) []prometheus.Metric {
+	type family struct {
+		metricType core.MetricType
+		unit       string
+		labels     map[string]bool
+		series     []core.Metric
+	}
+	families := make(map[string]*family)
+	for _, metric := range metrics {
+		name := promName(metric.Name, invalidMetricChars)
+		if exportedNames[name] {
+			continue
+		}
+		f, ok := families[name]
+		if !ok {
+			f = &family{metricType: metric.Type, unit: metric.Unit, labels: make(map[string]bool)}
+			families[name] = f
+		}
+		labels := make(map[string]string, len(metric.Labels))
+		for key, value := range metric.Labels {
+			key = promName(key, invalidLabelChars)
+			labels[key] = value
+			f.labels[key] = true
+		}
+		metric.Labels = labels
+		f.series = append(f.series, metric)
+	}
+
+	var converted []prometheus.Metric
+	for name, f := range families {
+		labelNames := make([]string, 0, len(f.labels))
+		for label := range f.labels {
+			labelNames = append(labelNames, label)
+		}
+		sort.Strings(labelNames)
+
+		help := "Reported by KubePulse."
+		if f.unit != "" {
+			help = "Reported by KubePulse, in " + f.unit + "."
+		}
+		desc := prometheus.NewDesc(name, help, labelNames, nil)
+		valueType := prometheus.UntypedValue
+		switch f.metricType {
+		case core.MetricTypeCounter:
+			valueType = prometheus.CounterValue
+		case core.MetricTypeGauge:
+			valueType = prometheus.GaugeValue
+		}
+
+		latest := make(map[string]int)
+		var series []prometheus.Metric
+		for _, metric := range f.series {
+			values := make([]string, len(labelNames))
+			for i, label := range labelNames {
+				values[i] = metric.Labels[label]
+			}
+			m, err := prometheus.NewConstMetric(desc, valueType, metric.Value, values...)
+			if err != nil {
+				klog.V(2).Infof("Skipping metric %s: %v", name, err)
+				continue
+			}
+			key := strings.Join(values, "\xff")
+			if i, ok := latest[key]; ok {
+				series[i] = m
+				continue
+			}
+			latest[key] = len(series)
+			series = append(series, m)
+		}
+		converted = append(converted, series...)
+	}
+	return converted
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// maxIngestBody bounds the size of a metric ingestion request
+const maxIngestBody = 1 << 20
+
+// ingestRequest is a batch of application metrics from one source
+type ingestRequest struct {
+	Source  string        `json:"source"`
+	Metrics []core.Metric `json:"metrics"`
+}
+
+// handleIngestMetrics accepts application metrics and reports the resulting
+// check status for the source
+func (s *Server) handleIngestMetrics(w http.ResponseWriter, r *http.Request) {
+	var req ingestRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestBody)).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := s.engine.IngestMetrics(req.Source, req.Metrics)
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidMetrics) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, map[string]interface{}{
+		"check":    result.Name,
+		"status":   result.Status,
+		"message":  result.Message,
+		"accepted": len(result.Metrics),
+	})
+}
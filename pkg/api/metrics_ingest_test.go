@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_IngestMetrics(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		MetricConditions: []core.MetricCondition{
+			{Name: "backlog", Metric: "queue_depth", Operator: ">", Threshold: 500, Status: core.HealthStatusDegraded},
+		},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantHealth core.HealthStatus
+	}{
+		{"healthy", `{"source": "checkout", "metrics": [{"name": "queue_depth", "value": 10}]}`, http.StatusOK, core.HealthStatusHealthy},
+		{"breached", `{"source": "checkout", "metrics": [{"name": "queue_depth", "value": 900}]}`, http.StatusOK, core.HealthStatusDegraded},
+		{"invalid metrics", `{"source": "checkout", "metrics": []}`, http.StatusBadRequest, ""},
+		{"invalid body", `{"source": `, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/metrics/ingest", strings.NewReader(tt.body))
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Check    string            `json:"check"`
+				Status   core.HealthStatus `json:"status"`
+				Accepted int               `json:"accepted"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Check != "app:checkout" || response.Status != tt.wantHealth || response.Accepted != 1 {
+				t.Errorf("unexpected response %+v", response)
+			}
+		})
+	}
+}
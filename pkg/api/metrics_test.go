@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_PrometheusMetrics(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusUnhealthy})
+	engine.AddCheck(&staticCheck{name: "pod-security", status: core.HealthStatusRestricted})
+	// Label values string formatting used to break, and series of a name
+	// with different label sets
+	if _, err := engine.IngestMetrics("payments", []core.Metric{
+		{Name: "queue_depth", Value: 3, Unit: "messages", Type: core.MetricTypeGauge, Labels: map[string]string{"queue": "say \"hi\"\nback\\slash"}},
+		{Name: "queue_depth", Value: 5, Unit: "messages", Type: core.MetricTypeGauge},
+	}); err != nil {
+		t.Fatalf("IngestMetrics() error = %v", err)
+	}
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+	deadline := time.Now().Add(2 * time.Second)
+	for _, name := range []string{"pod-health", "pod-security"} {
+		for _, ok := engine.GetResult(name); !ok && time.Now().Before(deadline); _, ok = engine.GetResult(name) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	for _, path := range []string{"/metrics", "/api/v1/metrics"} {
+		t.Run(path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if contentType := rr.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+				t.Errorf("Content-Type = %q, want the text format", contentType)
+			}
+
+			parser := expfmt.NewTextParser(model.UTF8Validation)
+			families, err := parser.TextToMetricFamilies(strings.NewReader(rr.Body.String()))
+			if err != nil {
+				t.Fatalf("invalid exposition: %v\n%s", err, rr.Body.String())
+			}
+			for _, name := range []string{"kubepulse_check_duration_seconds", "kubepulse_alerts_total", "kubepulse_health_score", "kubepulse_check_status", "kubepulse_websocket_clients", "queue_depth"} {
+				family, ok := families[name]
+				if !ok {
+					t.Errorf("missing %s", name)
+					continue
+				}
+				if family.GetHelp() == "" {
+					t.Errorf("%s has no HELP", name)
+				}
+			}
+
+			queue := families["queue_depth"]
+			if queue == nil || len(queue.Metric) != 2 {
+				t.Fatalf("expected two queue_depth series, got %v", queue)
+			}
+			if got := labelValue(queue.Metric, "queue", 3); got != "say \"hi\"\nback\\slash" {
+				t.Errorf("queue label = %q, want it intact", got)
+			}
+			if got := labelValue(queue.Metric, "queue", 5); got != "" {
+				t.Errorf("series without the label has queue=%q, want empty", got)
+			}
+			if got := checkStatus(families["kubepulse_check_status"], "pod-security"); got != "restricted" {
+				t.Errorf("pod-security status = %q, want restricted", got)
+			}
+			if duration := families["kubepulse_check_duration_seconds"]; duration != nil && duration.GetType() != dto.MetricType_HISTOGRAM {
+				t.Errorf("check durations are a %s, want a histogram", duration.GetType())
+			}
+		})
+	}
+
+	// Scrapers asking for OpenMetrics get it
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, req)
+	if contentType := rr.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/openmetrics-text") {
+		t.Errorf("Content-Type = %q, want OpenMetrics", contentType)
+	}
+	if !strings.HasSuffix(rr.Body.String(), "# EOF\n") {
+		t.Errorf("OpenMetrics exposition doesn't end with # EOF")
+	}
+}
+
+// labelValue returns the value of a label of the series with a value
+func labelValue(series []*dto.Metric, label string, value float64) string {
+	for _, metric := range series {
+		if metric.GetGauge().GetValue() != value {
+			continue
+		}
+		for _, pair := range metric.Label {
+			if pair.GetName() == label {
+				return pair.GetValue()
+			}
+		}
+	}
+	return ""
+}
+
+// checkStatus returns the status a check's kubepulse_check_status series
+// set to 1
+func checkStatus(family *dto.MetricFamily, check string) string {
+	if family == nil {
+		return ""
+	}
+	for _, metric := range family.Metric {
+		labels := map[string]string{}
+		for _, pair := range metric.Label {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if labels["check"] == check && metric.GetGauge().GetValue() == 1 {
+			return labels["status"]
+		}
+	}
+	return ""
+}
+
+func TestPromName(t *testing.T) {
+	tests := []struct {
+		name    string
+		invalid string
+		want    string
+	}{
+		{"kubepulse_check_runs_total", "metric", "kubepulse_check_runs_total"},
+		{"http.requests-total", "metric", "http_requests_total"},
+		{"node:cpu", "metric", "node:cpu"},
+		{"node:cpu", "label", "node_cpu"},
+		{"5xx_rate", "metric", "_5xx_rate"},
+		{"", "label", "_"},
+	}
+	for _, tt := range tests {
+		invalid := invalidMetricChars
+		if tt.invalid == "label" {
+			invalid = invalidLabelChars
+		}
+		if got := promName(tt.name, invalid); got != tt.want {
+			t.Errorf("promName(%q, %s) = %q, want %q", tt.name, tt.invalid, got, tt.want)
+		}
+	}
+}
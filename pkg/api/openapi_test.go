@@ -0,0 +1,106 @@
+package api
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+const openAPISpecPath = "../../api/openapi.yaml"
+
+// openAPISpecs maps each API group to the spec that documents it
+var openAPISpecs = map[string]string{
+	"/api/" + APIVersionV1:     openAPISpecPath,
+	"/api/" + APIVersionV2Beta: "../../api/openapi-v2beta.yaml",
+}
+
+// TestOpenAPISpec_CoversRoutes keeps the OpenAPI specs in sync with the router
+// so generated Python and TypeScript clients never miss an endpoint
+func TestOpenAPISpec_CoversRoutes(t *testing.T) {
+	server := &Server{router: mux.NewRouter()}
+	server.setupRoutes()
+
+	for prefix, specPath := range openAPISpecs {
+		t.Run(prefix, func(t *testing.T) {
+			data, err := os.ReadFile(specPath)
+			if err != nil {
+				t.Fatalf("failed to read OpenAPI spec: %v", err)
+			}
+
+			var spec struct {
+				Paths map[string]map[string]interface{} `yaml:"paths"`
+			}
+			if err := yaml.Unmarshal(data, &spec); err != nil {
+				t.Fatalf("failed to parse OpenAPI spec: %v", err)
+			}
+
+			routed := make(map[string]bool)
+			err = server.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+				path, err := route.GetPathTemplate()
+				if err != nil || !strings.HasPrefix(path, prefix+"/") {
+					return nil
+				}
+				methods, err := route.GetMethods()
+				if err != nil {
+					return nil
+				}
+
+				path = strings.TrimPrefix(path, prefix)
+				for _, method := range methods {
+					method = strings.ToLower(method)
+					if method == "options" {
+						continue
+					}
+					routed[method+" "+path] = true
+
+					if _, documented := spec.Paths[path][method]; !documented {
+						t.Errorf("route %s %s is not documented in %s", strings.ToUpper(method), path, specPath)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("failed to walk routes: %v", err)
+			}
+
+			for path, operations := range spec.Paths {
+				for method := range operations {
+					if !routed[method+" "+path] {
+						t.Errorf("spec documents %s %s but no such route is registered", strings.ToUpper(method), path)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestOpenAPISpec_CoversDeprecations keeps deprecated routes marked as
+// deprecated in the specs
+func TestOpenAPISpec_CoversDeprecations(t *testing.T) {
+	for _, deprecation := range deprecatedRoutes {
+		for prefix, specPath := range openAPISpecs {
+			if !strings.HasPrefix(deprecation.Path, prefix+"/") {
+				continue
+			}
+			data, err := os.ReadFile(specPath)
+			if err != nil {
+				t.Fatalf("failed to read OpenAPI spec: %v", err)
+			}
+			var spec struct {
+				Paths map[string]map[string]struct {
+					Deprecated bool `yaml:"deprecated"`
+				} `yaml:"paths"`
+			}
+			if err := yaml.Unmarshal(data, &spec); err != nil {
+				t.Fatalf("failed to parse OpenAPI spec: %v", err)
+			}
+			operation := spec.Paths[strings.TrimPrefix(deprecation.Path, prefix)][strings.ToLower(deprecation.Method)]
+			if !operation.Deprecated {
+				t.Errorf("%s %s is deprecated but not marked deprecated in %s", deprecation.Method, deprecation.Path, specPath)
+			}
+		}
+	}
+}
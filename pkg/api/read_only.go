@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxDryRunPeek bounds the request body read to tell a dry run apart
+const maxDryRunPeek = 64 << 10
+
+// mutating wraps a handler that changes cluster or KubePulse state so it is
+// rejected in read-only mode and, when API tokens are configured, for
+// requests without an admin token
+func (s *Server) mutating(action string, handler http.HandlerFunc) http.HandlerFunc {
+	return s.writable(action, func(w http.ResponseWriter, r *http.Request) {
+		if s.auth != nil {
+			identity, err := s.auth.Authenticate(bearerToken(r))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				s.writeError(w, http.StatusUnauthorized, fmt.Sprintf("%s requires an admin token: %v", action, err))
+				return
+			}
+			if roleRank(identity.Role) < roleRank(RoleAdmin) {
+				s.writeError(w, http.StatusForbidden, fmt.Sprintf("Token %s is read-only: %s requires an admin token", identity.Name, action))
+				return
+			}
+		}
+		handler(w, r)
+	})
+}
+
+// dryRunnable wraps a mutating handler whose requests may set dry_run: dry
+// runs reach every caller, and other requests must pass mutating. In
+// read-only mode the handler refuses executions itself, so it can audit
+// the refused attempt.
+func (s *Server) dryRunnable(action string, handler http.HandlerFunc) http.HandlerFunc {
+	guarded := s.mutating(action, handler)
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxDryRunPeek))
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var request struct {
+			DryRun bool `json:"dry_run"`
+		}
+		if (json.Unmarshal(body, &request) == nil && request.DryRun) || s.readOnly {
+			handler(w, r)
+			return
+		}
+		guarded(w, r)
+	}
+}
+
+// writable wraps a handler that changes state so it is rejected in read-only
+// mode, for routes that authenticate callers themselves, such as Slack's
+// signed interaction requests
+func (s *Server) writable(action string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.readOnly {
+			s.writeReadOnly(w, action)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// writeReadOnly rejects a request with 403, naming the disabled action
+func (s *Server) writeReadOnly(w http.ResponseWriter, action string) {
+	s.writeError(w, http.StatusForbidden,
+		fmt.Sprintf("KubePulse is running in read-only mode: %s is disabled", action))
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_ReadOnly(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), ReadOnly: true})
+	server := NewServer(Config{Engine: engine, ReadOnly: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		reason string
+	}{
+		{http.MethodPost, "/api/v1/contexts/switch", `{"context_name":"prod"}`, "context switching"},
+		{http.MethodPost, "/api/v1/ai/remediation/execute", `{"action_id":"restart-api"}`, "remediation execution"},
+		{http.MethodPost, "/api/v1/alerts/rules", `{"name":"pods","check":"pod-health","severity":"warning"}`, "changing alert rules"},
+		{http.MethodDelete, "/api/v1/alerts/rules/pods", "", "changing alert rules"},
+		{http.MethodPost, "/api/v1/alerts/rule-suggestions/1/apply", "", "changing alert rules"},
+		{http.MethodPost, "/api/v1/alerts/pods-1/ack", "", "acknowledging alerts"},
+		{http.MethodPost, "/api/v1/alerts/slack/actions", "", "acknowledging and silencing alerts"},
+		{http.MethodPost, "/api/v1/alerts/silences", `{"matchers":{"check":"pod-health"}}`, "silencing alerts"},
+		{http.MethodDelete, "/api/v1/alerts/silences/silence-1", "", "silencing alerts"},
+		{http.MethodPost, "/api/v1/system/backups", "", "taking backups"},
+		{http.MethodPost, "/api/v1/metrics/ingest", `{"source":"checkout","metrics":[{"name":"queue_depth","value":10}]}`, "ingesting metrics"},
+		{http.MethodPost, "/api/v1/agents/report", `{"agent":"edge-1"}`, "accepting agent reports"},
+		{http.MethodPost, "/api/v1/ai/investigations", `{"check":"pod-health"}`, "planning investigations"},
+		{http.MethodPost, "/api/v1/ai/investigations/investigation-1/run", "", "running investigations"},
+		{http.MethodPost, "/api/v1/ai/evaluations", `{"check":"pod-health"}`, "creating AI evaluations"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("expected status 403, got %d: %s", w.Code, w.Body.String())
+			}
+			var response map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			message, _ := response["message"].(string)
+			if !strings.Contains(message, "read-only mode") || !strings.Contains(message, tt.reason) {
+				t.Errorf("expected the read-only reason, got %q", message)
+			}
+		})
+	}
+
+	// Dry runs and reads still work
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/remediation/execute", strings.NewReader(`{"action_id":"restart-api","dry_run":true}`))
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code == http.StatusForbidden {
+		t.Errorf("expected a dry run to be allowed, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"read_only":true`) {
+		t.Errorf("expected health to report read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestServer_ReadOnlyDisabled(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/alerts/rules",
+		strings.NewReader(`{"name":"pods","check":"pod-health","severity":"warning"}`))
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code == http.StatusForbidden {
+		t.Errorf("expected changes to be allowed outside read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "read_only") {
+		t.Errorf("expected no read-only flag in health, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// handleListRecordings summarizes recorded check runs; ?check= filters by check
+func (s *Server) handleListRecordings(w http.ResponseWriter, r *http.Request) {
+	recordings := s.engine.GetRecordings(r.URL.Query().Get("check"))
+	s.writeJSON(w, map[string]interface{}{
+		"enabled":    s.engine.RecordingEnabled(),
+		"recordings": recordings,
+		"total":      len(recordings),
+	})
+}
+
+// handleGetRecording returns a recorded check run with its API responses,
+// suitable for saving and replaying with `kubepulse replay`
+func (s *Server) handleGetRecording(w http.ResponseWriter, r *http.Request) {
+	recording, err := s.engine.GetRecording(mux.Vars(r)["id"])
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	s.writeJSON(w, recording)
+}
+
+// handleReplayRecording re-runs a recorded check against its recorded inputs
+func (s *Server) handleReplayRecording(w http.ResponseWriter, r *http.Request) {
+	report, err := s.engine.ReplayRecording(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, core.ErrRecordingNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	s.writeJSON(w, report)
+}
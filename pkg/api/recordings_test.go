@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandleRecordings(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   time.Hour,
+		Recorder:   core.NewCheckRecorder(0),
+	})
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusDegraded})
+	go func() { _ = engine.Start() }()
+	t.Cleanup(engine.Stop)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(engine.GetRecordings("")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/recordings?check=pod-health", nil)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Enabled    bool                    `json:"enabled"`
+		Recordings []core.RecordingSummary `json:"recordings"`
+		Total      int                     `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !list.Enabled || list.Total != 1 || list.Recordings[0].Status != core.HealthStatusDegraded {
+		t.Fatalf("unexpected recordings response: %s", w.Body.String())
+	}
+	id := list.Recordings[0].ID
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"get", http.MethodGet, "/api/v1/recordings/" + id, http.StatusOK},
+		{"get unknown", http.MethodGet, "/api/v1/recordings/pod-health-99", http.StatusNotFound},
+		{"replay", http.MethodPost, "/api/v1/recordings/" + id + "/replay", http.StatusOK},
+		{"replay unknown", http.MethodPost, "/api/v1/recordings/pod-health-99/replay", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/recordings/"+id+"/replay", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	var report core.ReplayReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !report.Matches || report.RecordingID != id {
+		t.Errorf("expected replay of %s to match, got %+v", id, report)
+	}
+}
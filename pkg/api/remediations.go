@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// RemediationReview approves or rejects a suggested remediation action
+type RemediationReview struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// handleListRemediations lists suggested remediation actions, optionally
+// for one check or with one status
+func (s *Server) handleListRemediations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	remediations := s.engine.GetRemediations(query.Get("check"), core.RemediationStatus(query.Get("status")))
+	s.writeJSON(w, map[string]interface{}{
+		"remediations": remediations,
+		"total":        len(remediations),
+	})
+}
+
+// handleGetRemediation returns a suggested remediation action with its
+// execution records
+func (s *Server) handleGetRemediation(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	remediation, err := s.engine.GetRemediation(id)
+	if err != nil {
+		s.writeRemediationError(w, err)
+		return
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"remediation": remediation,
+		"records":     s.engine.GetRemediationRecords(id),
+	})
+}
+
+// handleApproveRemediation approves a remediation action to run once
+func (s *Server) handleApproveRemediation(w http.ResponseWriter, r *http.Request) {
+	s.reviewRemediation(w, r, s.engine.ApproveRemediation, RemediationStatusApproved)
+}
+
+// handleRejectRemediation rejects a remediation action
+func (s *Server) handleRejectRemediation(w http.ResponseWriter, r *http.Request) {
+	s.reviewRemediation(w, r, s.engine.RejectRemediation, RemediationStatusRejected)
+}
+
+// reviewRemediation records a review by the request's identity and tells
+// WebSocket clients
+func (s *Server) reviewRemediation(w http.ResponseWriter, r *http.Request, review func(id, reviewer, comment string) (core.Remediation, error), status string) {
+	var req RemediationReview
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	remediation, err := review(mux.Vars(r)["id"], s.requestIdentity(r).Name, req.Comment)
+	if err != nil {
+		s.writeRemediationError(w, err)
+		return
+	}
+	s.Publish(WSMessageRemediationStatus, RemediationStatusData{
+		ActionID: remediation.Action.ID,
+		Status:   status,
+		Message:  remediation.Comment,
+	})
+	s.writeJSON(w, remediation)
+}
+
+// handleListRemediationRecords lists remediation runs and dry runs, oldest
+// first, optionally of one action
+func (s *Server) handleListRemediationRecords(w http.ResponseWriter, r *http.Request) {
+	records := s.engine.GetRemediationRecords(r.URL.Query().Get("action"))
+	s.writeJSON(w, map[string]interface{}{
+		"records": records,
+		"total":   len(records),
+	})
+}
+
+// remediationErrorStatus maps remediation workflow errors to statuses
+func remediationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, core.ErrRemediationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, core.ErrRemediationNotApproved), errors.Is(err, core.ErrRemediationStatus):
+		return http.StatusConflict
+	case errors.Is(err, core.ErrReadOnly):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// writeRemediationError writes a remediation workflow error
+func (s *Server) writeRemediationError(w http.ResponseWriter, err error) {
+	s.writeError(w, remediationErrorStatus(err), err.Error())
+}
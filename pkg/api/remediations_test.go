@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_RemediationApproval(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset(), EnableAI: true, AIConfig: &ai.Config{TestMode: true, TestScenario: ai.ScenarioCrashLoop}})
+	engine.AddCheck(&staticCheck{name: "pod-health", status: core.HealthStatusUnhealthy})
+	server := NewServer(Config{
+		Engine: engine,
+		Credentials: []Credential{
+			{Name: "alice", Token: "alice-token-0123456789", Role: RoleAdmin},
+			{Name: "victor", Token: "victor-token-0123456789", Role: RoleViewer},
+		},
+	})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+	deadline := time.Now().Add(2 * time.Second)
+	for _, ok := engine.GetResult("pod-health"); !ok && time.Now().Before(deadline); _, ok = engine.GetResult("pod-health") {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	request := func(token, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, req)
+		return rr
+	}
+	admin := "alice-token-0123456789"
+
+	rr := request(admin, http.MethodGet, "/api/v1/ai/remediation/pod-health/suggestions", "")
+	var suggestions struct {
+		Suggestions []ai.RemediationAction `json:"suggestions"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &suggestions); err != nil || len(suggestions.Suggestions) == 0 {
+		t.Fatalf("expected suggestions, got %d: %s", rr.Code, rr.Body.String())
+	}
+	id := suggestions.Suggestions[0].ID
+
+	rr = request(admin, http.MethodGet, "/api/v1/ai/remediation/actions?status=pending&check=pod-health", "")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"id":"`+id+`"`) {
+		t.Fatalf("expected the suggestion pending approval, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Running needs approval, dry runs don't
+	execute := `{"action_id":"` + id + `"}`
+	if rr := request(admin, http.MethodPost, "/api/v1/ai/remediation/execute", execute); rr.Code != http.StatusConflict {
+		t.Errorf("expected 409 before approval, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := request(admin, http.MethodPost, "/api/v1/ai/remediation/execute", `{"action_id":"`+id+`","dry_run":true}`); rr.Code != http.StatusOK {
+		t.Errorf("expected a dry run to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := request(admin, http.MethodPost, "/api/v1/ai/remediation/execute", `{"action_id":"nope"}`); rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown action, got %d", rr.Code)
+	}
+
+	// Viewers and anonymous callers can only dry-run
+	viewer := "victor-token-0123456789"
+	if rr := request(viewer, http.MethodPost, "/api/v1/ai/remediation/execute", execute); rr.Code != http.StatusForbidden {
+		t.Errorf("expected a viewer's execution to be refused, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := request("", http.MethodPost, "/api/v1/ai/remediation/execute", execute); rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected an anonymous execution to be refused, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := request(viewer, http.MethodPost, "/api/v1/ai/remediation/execute", `{"action_id":"`+id+`","dry_run":true}`); rr.Code != http.StatusOK {
+		t.Errorf("expected a viewer's dry run to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Viewers can't approve
+	approve := "/api/v1/ai/remediation/actions/" + id + "/approve"
+	if rr := request(viewer, http.MethodPost, approve, ""); rr.Code != http.StatusForbidden {
+		t.Errorf("expected a viewer to be refused, got %d", rr.Code)
+	}
+	rr = request(admin, http.MethodPost, approve, `{"comment":"matches the runbook"}`)
+	var approved core.Remediation
+	if err := json.Unmarshal(rr.Body.Bytes(), &approved); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected approval, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if approved.Status != core.RemediationApproved || approved.ReviewedBy != "alice" || approved.Comment != "matches the runbook" {
+		t.Errorf("unexpected approved remediation %+v", approved)
+	}
+	if rr := request(admin, http.MethodPost, approve, ""); rr.Code != http.StatusConflict {
+		t.Errorf("expected approving twice to conflict, got %d", rr.Code)
+	}
+
+	rr = request(admin, http.MethodPost, "/api/v1/ai/remediation/actions/"+id+"/reject", "")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"status":"rejected"`) {
+		t.Errorf("expected the rejection, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = request(admin, http.MethodGet, "/api/v1/ai/remediation/actions/"+id, "")
+	var detail struct {
+		Remediation core.Remediation       `json:"remediation"`
+		Records     []ai.RemediationRecord `json:"records"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if detail.Remediation.Status != core.RemediationRejected || len(detail.Records) != 2 || !detail.Records[0].DryRun || !detail.Records[1].DryRun {
+		t.Errorf("expected the rejected action with its dry runs, got %+v", detail)
+	}
+	if rr := request(admin, http.MethodGet, "/api/v1/ai/remediation/records?action="+id, ""); !strings.Contains(rr.Body.String(), `"total":2`) {
+		t.Errorf("expected the two dry-run records, got %s", rr.Body.String())
+	}
+}
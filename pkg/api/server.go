@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,8 +15,18 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/backup"
 	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/diagnostics"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
 	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"github.com/kubepulse/kubepulse/pkg/loadshed"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/kubepulse/kubepulse/pkg/telemetry"
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"github.com/kubepulse/kubepulse/pkg/webhooks"
 	"k8s.io/klog/v2"
 )
 
@@ -26,7 +37,7 @@ type Server struct {
 	router         *mux.Router
 	server         *http.Server
 	upgrader       websocket.Upgrader
-	clients        map[*websocket.Conn]bool
+	clients        map[*websocket.Conn]*wsClient
 	clientsMu      sync.RWMutex
 	shutdown       chan struct{}
 	ctx            context.Context
@@ -34,6 +45,35 @@ type Server struct {
 	corsEnabled    bool
 	corsOrigins    []string
 	uiConfig       config.UIConfig
+	updates        *version.UpdateChecker
+	preflight      *preflight.Config
+	fleet          *fleet.Prober
+	agents         *fleet.AgentRegistry
+	deprecations   deprecations
+	hub            *federation.Hub
+	spoke          *federation.Spoke
+	backups        *backup.Scheduler
+	telemetry      *telemetry.Reporter
+	diagnostics    *diagnostics.Monitor
+	loadShedding   *loadshed.Shedder
+	webhooks       *webhooks.Dispatcher
+	silenceSync    *alerts.AlertmanagerSync
+	readOnly       bool
+	auth           *Authenticator
+	health         healthStream
+	features       featureState
+	analysisWait   time.Duration // How long AI analysis requests wait before answering 202 Accepted
+	metrics        *metricsExporter
+
+	slackSigningSecret string
+}
+
+// wsClient is a connected WebSocket client
+type wsClient struct {
+	identity  Identity
+	deltas    bool            // Receives health.delta messages between full snapshots
+	topics    map[string]bool // Subscribed topics
+	healthSeq uint64          // Sequence number of the last health message sent
 }
 
 // spaHandler implements a single-page application handler
@@ -53,6 +93,29 @@ type Config struct {
 	ReadTimeout    time.Duration
 	WriteTimeout   time.Duration
 	UIConfig       config.UIConfig
+	UpdateChecker  *version.UpdateChecker   // Optional; reports new releases in /health
+	Preflight      *preflight.Config        // Optional; enables /system/preflight
+	Fleet          *fleet.Prober            // Optional; enables /health/multi
+	Hub            *federation.Hub          // Optional; enables /federation/analyze
+	Spoke          *federation.Spoke        // Optional; enables /federation/spoke/analyze
+	Backups        *backup.Scheduler        // Optional; enables /system/backups
+	Telemetry      *telemetry.Reporter      // Optional; enables /system/telemetry
+	Diagnostics    *diagnostics.Monitor     // Optional; enables /system/dumps for admins
+	Webhooks       *webhooks.Dispatcher     // Optional; enables /webhooks
+	LoadShedding   *loadshed.Shedder        // Optional; enables /system/load-shedding
+	SilenceSync    *alerts.AlertmanagerSync // Optional; reported with the silences
+
+	// AnalysisWait is how long cluster and batch AI analysis requests wait
+	// for their run before answering 202 Accepted; defaults to half the
+	// write timeout
+	AnalysisWait time.Duration
+
+	SlackSigningSecret string       // Optional; enables Slack Acknowledge buttons
+	ReadOnly           bool         // Rejects requests that change cluster or KubePulse state with 403
+	Credentials        []Credential // Optional; requires WebSocket clients and agents to authenticate
+
+	TokensFile    string        // Optional; persists tokens created through the API
+	TokenRotation time.Duration // Age at which tokens are due for rotation; DefaultTokenRotation when zero
 }
 
 // NewServer creates a new API server
@@ -69,6 +132,10 @@ func NewServer(config Config) *Server {
 	if writeTimeout == 0 {
 		writeTimeout = 15 * time.Second
 	}
+	analysisWait := config.AnalysisWait
+	if analysisWait <= 0 || analysisWait >= writeTimeout {
+		analysisWait = writeTimeout / 2
+	}
 
 	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
 	if config.Host == "" {
@@ -78,6 +145,18 @@ func NewServer(config Config) *Server {
 	server := &Server{
 		engine:         config.Engine,
 		contextManager: config.ContextManager,
+		updates:        config.UpdateChecker,
+		preflight:      config.Preflight,
+		fleet:          config.Fleet,
+		agents:         fleet.NewAgentRegistry(),
+		hub:            config.Hub,
+		spoke:          config.Spoke,
+		backups:        config.Backups,
+		telemetry:      config.Telemetry,
+		diagnostics:    config.Diagnostics,
+		loadShedding:   config.LoadShedding,
+		webhooks:       config.Webhooks,
+		silenceSync:    config.SilenceSync,
 		router:         router,
 		server: &http.Server{
 			Addr:         addr,
@@ -87,6 +166,7 @@ func NewServer(config Config) *Server {
 			IdleTimeout:  60 * time.Second,
 		},
 		upgrader: websocket.Upgrader{
+			EnableCompression: true,
 			CheckOrigin: func(r *http.Request) bool {
 				if !config.CORSEnabled {
 					return false
@@ -104,20 +184,40 @@ func NewServer(config Config) *Server {
 				return false
 			},
 		},
-		clients:     make(map[*websocket.Conn]bool),
+		clients:     make(map[*websocket.Conn]*wsClient),
 		shutdown:    make(chan struct{}),
 		ctx:         ctx,
 		cancel:      cancel,
 		corsEnabled: config.CORSEnabled,
 		corsOrigins: config.CORSOrigins,
 		uiConfig:    config.UIConfig,
+		readOnly:    config.ReadOnly,
+		auth:        authenticatorFor(config.Credentials, config.TokensFile, config.TokenRotation),
+
+		analysisWait: analysisWait,
+
+		slackSigningSecret: config.SlackSigningSecret,
 	}
 
+	server.metrics = newMetricsExporter(server)
 	server.setupRoutes()
 
 	// Start WebSocket client cleanup routine
 	go server.cleanupClients()
 
+	// Push feature flag changes to WebSocket clients
+	go server.watchFeatures()
+
+	// Remind of API tokens due for rotation
+	if server.auth != nil {
+		go server.remindRotations(ctx)
+	}
+
+	// Push engine alerts and AI insights to WebSocket clients
+	if server.engine != nil {
+		go server.relayEngineEvents()
+	}
+
 	return server
 }
 
@@ -137,26 +237,97 @@ func (s *Server) Stop(ctx context.Context) error {
 func (s *Server) setupRoutes() {
 	// Add CORS middleware first
 	s.router.Use(s.corsMiddleware)
+	s.router.Use(s.deprecationMiddleware)
+	s.router.Use(s.aiScenarioMiddleware)
+	for _, deprecation := range deprecatedRoutes {
+		s.deprecate(deprecation)
+	}
 
 	klog.Info("Setting up API routes")
 
 	// API v1 routes
-	api := s.router.PathPrefix("/api/v1").Subrouter()
+	api := s.router.PathPrefix("/api/" + APIVersionV1).Subrouter()
+	api.Use(s.scopeMiddleware)
 	api.HandleFunc("/health", s.handleHealth).Methods("GET")
+	api.HandleFunc("/version", s.handleVersion).Methods("GET")
+	api.HandleFunc("/deprecations", s.handleListDeprecations).Methods("GET")
+	api.HandleFunc("/system/preflight", s.handlePreflight).Methods("GET")
+	api.HandleFunc("/system/backups", s.handleListBackups).Methods("GET")
+	api.HandleFunc("/system/backups", s.mutating("taking backups", s.handleCreateBackup)).Methods("POST")
+	api.HandleFunc("/system/telemetry", s.handleTelemetry).Methods("GET")
+	api.HandleFunc("/system/load-shedding", s.handleLoadShedding).Methods("GET")
+	api.HandleFunc("/system/latency", s.handlePipelineLatency).Methods("GET")
+	api.HandleFunc("/system/dumps", s.adminOnly(s.handleListDumps)).Methods("GET")
+	api.HandleFunc("/system/dumps", s.adminOnly(s.handleCreateDump)).Methods("POST")
+	api.HandleFunc("/system/dumps/{id}/{file}", s.adminOnly(s.handleDownloadDump)).Methods("GET")
+	api.HandleFunc("/auth/tokens", s.adminOnly(s.handleListTokens)).Methods("GET")
+	api.HandleFunc("/auth/tokens", s.mutating("managing API tokens", s.adminOnly(s.handleCreateToken))).Methods("POST")
+	api.HandleFunc("/auth/tokens/{name}/rotate", s.mutating("managing API tokens", s.adminOnly(s.handleRotateToken))).Methods("POST")
+	api.HandleFunc("/auth/tokens/{name}", s.mutating("managing API tokens", s.adminOnly(s.handleRevokeToken))).Methods("DELETE")
 	api.HandleFunc("/health/cluster", s.handleClusterHealth).Methods("GET")
+	api.HandleFunc("/health/at", s.handleHealthAt).Methods("GET")
+	api.HandleFunc("/health/multi", s.handleHealthMulti).Methods("GET")
+	api.HandleFunc("/health/apps", s.handleAppHealth).Methods("GET")
+	api.HandleFunc("/health/namespaces", s.handleNamespaceHealth).Methods("GET")
+	api.HandleFunc("/agents", s.handleListAgents).Methods("GET")
+	api.HandleFunc("/agents/report", s.writable("accepting agent reports", s.adminOnly(s.handleAgentReport))).Methods("POST")
 	api.HandleFunc("/health/checks", s.handleHealthChecks).Methods("GET")
 	api.HandleFunc("/health/checks/{name}", s.handleHealthCheck).Methods("GET")
+	api.HandleFunc("/checks/maintenance", s.handleListCheckMaintenance).Methods("GET")
+	api.HandleFunc("/checks/discovery", s.handleCheckDiscovery).Methods("GET")
+	api.HandleFunc("/checks/{name}/maintenance", s.mutating("changing check maintenance", s.handleSetCheckMaintenance)).Methods("POST")
+	api.HandleFunc("/checks/{name}/maintenance", s.mutating("changing check maintenance", s.handleEndCheckMaintenance)).Methods("DELETE")
+	api.HandleFunc("/annotations", s.handleListAnnotations).Methods("GET")
+	api.HandleFunc("/annotations", s.mutating("annotating health", s.handleCreateAnnotation)).Methods("POST")
+	api.HandleFunc("/annotations/{id}", s.mutating("annotating health", s.handleDeleteAnnotation)).Methods("DELETE")
+	api.HandleFunc("/chaos/experiments", s.handleListChaosExperiments).Methods("GET")
 	api.HandleFunc("/alerts", s.handleAlerts).Methods("GET")
+	api.HandleFunc("/alerts/rules", s.handleListAlertRules).Methods("GET")
+	api.HandleFunc("/alerts/rules", s.mutating("changing alert rules", s.handleUpsertAlertRule)).Methods("POST")
+	api.HandleFunc("/alerts/rules/{name}", s.mutating("changing alert rules", s.handleDeleteAlertRule)).Methods("DELETE")
+	api.HandleFunc("/alerts/rule-suggestions", s.handleRuleSuggestions).Methods("GET")
+	api.HandleFunc("/alerts/rule-suggestions/{id}/apply", s.mutating("changing alert rules", s.handleApplyRuleSuggestion)).Methods("POST")
+	api.HandleFunc("/alerts/escalations", s.handleListEscalations).Methods("GET")
+	api.HandleFunc("/alerts/deliveries", s.handleListDeliveries).Methods("GET")
+	api.HandleFunc("/alerts/deliveries/{id}/redeliver", s.mutating("redelivering notifications", s.handleRedeliver)).Methods("POST")
+	api.HandleFunc("/alerts/silences", s.handleListSilences).Methods("GET")
+	api.HandleFunc("/alerts/silences", s.mutating("silencing alerts", s.handleCreateSilence)).Methods("POST")
+	api.HandleFunc("/alerts/silences/export", s.handleExportSilences).Methods("GET")
+	api.HandleFunc("/alerts/silences/import", s.mutating("silencing alerts", s.handleImportSilences)).Methods("POST")
+	api.HandleFunc("/alerts/silences/{id}", s.mutating("silencing alerts", s.handleExpireSilence)).Methods("DELETE")
+	api.HandleFunc("/alerts/slack/actions", s.writable("acknowledging and silencing alerts", s.handleSlackActions)).Methods("POST")
+	api.HandleFunc("/alerts/{id}/ack", s.mutating("acknowledging alerts", s.handleAckAlert)).Methods("POST")
 	api.HandleFunc("/metrics", s.handleMetrics).Methods("GET")
+	api.HandleFunc("/metrics/ingest", s.mutating("ingesting metrics", s.handleIngestMetrics)).Methods("POST")
+	api.HandleFunc("/metrics/history/{name}", s.handleMetricHistory).Methods("GET")
+	api.HandleFunc("/metrics/cardinality", s.handleMetricCardinality).Methods("GET")
+	api.HandleFunc("/slo", s.handleListSLOs).Methods("GET")
+	api.HandleFunc("/slo/{name}", s.handleGetSLO).Methods("GET")
+	api.HandleFunc("/stream/results", s.handleStreamResults).Methods("GET")
+	api.HandleFunc("/dashboard/summary", s.handleDashboardSummary).Methods("GET")
+	api.HandleFunc("/changes", s.handleChanges).Methods("GET")
+	api.HandleFunc("/inventory", s.handleInventory).Methods("GET")
+	api.HandleFunc("/webhooks", s.authenticated(s.handleListWebhooks)).Methods("GET")
+	api.HandleFunc("/webhooks", s.mutating("registering webhooks", s.adminOnly(s.handleRegisterWebhook))).Methods("POST")
+	api.HandleFunc("/webhooks/{name}", s.mutating("registering webhooks", s.adminOnly(s.handleUnregisterWebhook))).Methods("DELETE")
+	api.HandleFunc("/handoff", s.handleHandoff).Methods("GET")
+	api.HandleFunc("/findings", s.handleListFindings).Methods("GET")
+	api.HandleFunc("/findings/types", s.handleFindingTypes).Methods("GET")
+	api.HandleFunc("/recordings", s.handleListRecordings).Methods("GET")
+	api.HandleFunc("/recordings/{id}", s.handleGetRecording).Methods("GET")
+	api.HandleFunc("/recordings/{id}/replay", s.handleReplayRecording).Methods("POST")
 	api.HandleFunc("/ai/insights", s.handleAIInsights).Methods("GET")
+	api.HandleFunc("/ai/analyze/batch", s.handleAIAnalyzeBatch).Methods("POST")
 	api.HandleFunc("/ai/analyze/{check}", s.handleAIAnalyze).Methods("POST")
 	api.HandleFunc("/ai/heal/{check}", s.handleAIHeal).Methods("POST")
+	api.HandleFunc("/federation/analyze", s.handleFederatedAnalyze).Methods("POST")
+	api.HandleFunc("/federation/spoke/analyze", s.handleSpokeAnalyze).Methods("POST")
 	api.HandleFunc("/config/ui", s.handleUIConfig).Methods("GET")
 
 	// Context management endpoints
 	api.HandleFunc("/contexts", s.handleListContexts).Methods("GET")
 	api.HandleFunc("/contexts/current", s.handleGetCurrentContext).Methods("GET")
-	api.HandleFunc("/contexts/switch", s.handleSwitchContext).Methods("POST")
+	api.HandleFunc("/contexts/switch", s.mutating("context switching", s.handleSwitchContext)).Methods("POST")
 
 	// Register new AI routes on the api subrouter
 	aiApi := api.PathPrefix("/ai").Subrouter()
@@ -166,15 +337,47 @@ func (s *Server) setupRoutes() {
 	aiApi.HandleFunc("/predictions", s.HandlePredictiveInsights).Methods("GET")
 	// Remediation
 	aiApi.HandleFunc("/remediation/{check}/suggestions", s.HandleRemediationSuggestions).Methods("GET")
-	aiApi.HandleFunc("/remediation/execute", s.HandleExecuteRemediation).Methods("POST")
+	aiApi.HandleFunc("/remediation/execute", s.dryRunnable("remediation execution", s.HandleExecuteRemediation)).Methods("POST")
+	aiApi.HandleFunc("/remediation/actions", s.handleListRemediations).Methods("GET")
+	aiApi.HandleFunc("/remediation/actions/{id}", s.handleGetRemediation).Methods("GET")
+	aiApi.HandleFunc("/remediation/actions/{id}/approve", s.mutating("remediation approval", s.handleApproveRemediation)).Methods("POST")
+	aiApi.HandleFunc("/remediation/actions/{id}/reject", s.mutating("remediation review", s.handleRejectRemediation)).Methods("POST")
+	aiApi.HandleFunc("/remediation/records", s.handleListRemediationRecords).Methods("GET")
+	aiApi.HandleFunc("/governance", s.authenticated(s.handleAIGovernance)).Methods("GET")
 	// Smart alerts
 	aiApi.HandleFunc("/alerts/insights", s.HandleSmartAlerts).Methods("GET")
+	// Analysis history and comparison
+	aiApi.HandleFunc("/analysis/sessions", s.handleAnalysisSessions).Methods("GET")
+	aiApi.HandleFunc("/analysis/compare", s.handleCompareAnalyses).Methods("POST")
+	aiApi.HandleFunc("/analysis/runs", s.handleListAnalysisRuns).Methods("GET")
+	aiApi.HandleFunc("/analysis/runs/{id}", s.handleGetAnalysisRun).Methods("GET")
+	// A/B evaluation of AI models
+	aiApi.HandleFunc("/evaluations", s.handleListEvaluations).Methods("GET")
+	aiApi.HandleFunc("/evaluations", s.mutating("creating AI evaluations", s.handleCreateEvaluation)).Methods("POST")
+	aiApi.HandleFunc("/evaluations/report", s.handleEvaluationReport).Methods("GET")
+	aiApi.HandleFunc("/evaluations/{id}/feedback", s.mutating("rating AI evaluations", s.handleEvaluationFeedback)).Methods("POST")
+	// Investigation plans
+	aiApi.HandleFunc("/investigations", s.handleListInvestigations).Methods("GET")
+	aiApi.HandleFunc("/investigations", s.mutating("planning investigations", s.handleCreateInvestigation)).Methods("POST")
+	aiApi.HandleFunc("/investigations/{id}", s.handleGetInvestigation).Methods("GET")
+	aiApi.HandleFunc("/investigations/{id}/run", s.mutating("running investigations", s.handleRunInvestigation)).Methods("POST")
+	// Cached kubectl output shared by AI endpoints
+	aiApi.HandleFunc("/tools/refresh", s.handleRefreshToolResults).Methods("POST")
 
 	klog.Info("AI API routes registered at /api/v1/ai/*")
 
+	// API v2beta routes: successors to v1 routes whose shape is still settling
+	v2beta := s.router.PathPrefix("/api/" + APIVersionV2Beta).Subrouter()
+	v2beta.Use(s.scopeMiddleware)
+	v2beta.HandleFunc("/checks", s.handleListChecksV2).Methods("GET")
+	v2beta.HandleFunc("/checks/{name}", s.handleGetCheckV2).Methods("GET")
+
 	// WebSocket endpoint
 	s.router.HandleFunc("/ws", s.handleWebSocket)
 
+	// Metrics at the path Prometheus scrapes by default
+	s.router.Handle("/metrics", s.scopeMiddleware(http.HandlerFunc(s.handleMetrics))).Methods("GET")
+
 	// Static files for web dashboard - MUST BE LAST
 	// First check if frontend build exists
 	frontendBuildPath := "./frontend/dist"
@@ -193,28 +396,80 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"status":    "healthy",
 		"timestamp": time.Now(),
-		"version":   "0.1.0",
+		"version":   version.Version,
+	}
+	if s.readOnly {
+		response["read_only"] = true
+	}
+	if s.updates != nil {
+		if status := s.updates.Status(); status != nil {
+			response["update"] = status
+		}
+	}
+	if s.loadShedding != nil {
+		if state := s.loadShedding.State(); state.Shedding {
+			response["status"] = "degraded"
+			response["load_shedding"] = state
+		}
 	}
 	s.writeJSON(w, response)
 }
 
+// handleVersion returns build information for the running server
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, version.Get())
+}
+
+// handleLoadShedding reports KubePulse's resource use against its limits
+// and whether it is shedding load
+func (s *Server) handleLoadShedding(w http.ResponseWriter, r *http.Request) {
+	if s.loadShedding == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Load shedding is not configured")
+		return
+	}
+	s.writeJSON(w, s.loadShedding.State())
+}
+
+// handlePipelineLatency reports how long each stage of the monitoring
+// pipeline takes, from a condition beginning in the cluster to its alert
+// being delivered
+func (s *Server) handlePipelineLatency(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, s.engine.PipelineLatency())
+}
+
+// handlePreflight runs the same environment checks as `kubepulse doctor`
+func (s *Server) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	if s.preflight == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Pre-flight checks are not configured")
+		return
+	}
+	s.writeJSON(w, preflight.Run(r.Context(), *s.preflight))
+}
+
 // handleClusterHealth returns full cluster health
 func (s *Server) handleClusterHealth(w http.ResponseWriter, r *http.Request) {
-	// Get current context name from context manager
-	contextName := ""
+	health := s.engine.GetClusterHealth(s.resolveClusterName(r))
+	s.writeJSON(w, health)
+}
+
+// handleDashboardSummary returns the precomputed dashboard overview
+func (s *Server) handleDashboardSummary(w http.ResponseWriter, r *http.Request) {
+	summary := s.engine.GetDashboardSummary(s.resolveClusterName(r))
+	s.writeJSON(w, summary)
+}
+
+// resolveClusterName returns the cluster query parameter, defaulting to the current context
+func (s *Server) resolveClusterName(r *http.Request) string {
+	if clusterName := r.URL.Query().Get("cluster"); clusterName != "" {
+		return clusterName
+	}
+
 	if s.contextManager != nil {
 		if ctx, err := s.contextManager.GetCurrentContext(); err == nil {
-			contextName = ctx.Name
+			return ctx.Name
 		}
 	}
-
-	clusterName := r.URL.Query().Get("cluster")
-	if clusterName == "" {
-		clusterName = contextName
-	}
-
-	health := s.engine.GetClusterHealth(clusterName)
-	s.writeJSON(w, health)
+	return ""
 }
 
 // handleHealthChecks returns all health check results
@@ -223,6 +478,37 @@ func (s *Server) handleHealthChecks(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, results)
 }
 
+// handleAppHealth returns the health of workloads grouped by owner,
+// optionally only those of a team, app or tier
+func (s *Server) handleAppHealth(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	apps := make([]core.AppHealth, 0)
+	for _, app := range s.engine.AppHealth() {
+		if (query.Get("team") == "" || query.Get("team") == app.Team) &&
+			(query.Get("app") == "" || query.Get("app") == app.App) &&
+			(query.Get("tier") == "" || query.Get("tier") == app.Tier) {
+			apps = append(apps, app)
+		}
+	}
+	s.writeJSON(w, apps)
+}
+
+// handleNamespaceHealth returns the health of each namespace, optionally
+// only those with a status
+func (s *Server) handleNamespaceHealth(w http.ResponseWriter, r *http.Request) {
+	report := s.engine.NamespaceHealth()
+	if status := r.URL.Query().Get("status"); status != "" {
+		namespaces := make([]core.NamespaceHealth, 0, len(report.Namespaces))
+		for _, health := range report.Namespaces {
+			if string(health.Status) == status {
+				namespaces = append(namespaces, health)
+			}
+		}
+		report.Namespaces = namespaces
+	}
+	s.writeJSON(w, report)
+}
+
 // handleHealthCheck returns a specific health check result
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -251,34 +537,21 @@ func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, alerts)
 }
 
-// handleMetrics returns Prometheus-compatible metrics
+// handleMetrics serves metrics in the Prometheus text or OpenMetrics
+// format, as the scraper asks
 func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain")
-
-	results := s.engine.GetResults()
+	s.metrics.handler.ServeHTTP(w, r)
+}
 
-	for _, result := range results {
-		for _, metric := range result.Metrics {
-			// Convert to Prometheus format
-			labels := ""
-			for k, v := range metric.Labels {
-				if labels != "" {
-					labels += ","
-				}
-				labels += fmt.Sprintf(`%s="%s"`, k, v)
-			}
-			if labels != "" {
-				labels = "{" + labels + "}"
-			}
+// handleMetricHistory returns recorded data points for a metric, grouped by series
+func (s *Server) handleMetricHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
 
-			_, _ = fmt.Fprintf(w, "# TYPE %s %s\n", metric.Name, string(metric.Type))
-			_, _ = fmt.Fprintf(w, "%s%s %f %d\n",
-				metric.Name,
-				labels,
-				metric.Value,
-				metric.Timestamp.Unix()*1000)
-		}
-	}
+	s.writeJSON(w, map[string]interface{}{
+		"metric": name,
+		"series": s.engine.GetMetricHistory(name),
+	})
 }
 
 // Old WebSocket handler removed - replaced with improved version with proper cleanup
@@ -305,8 +578,9 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+AIScenarioHeader)
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Expose-Headers", "Deprecation, Sunset, Link")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -319,12 +593,12 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 
 // handleAIInsights returns AI-generated cluster insights
 func (s *Server) handleAIInsights(w http.ResponseWriter, r *http.Request) {
-	insights, err := s.engine.GetAIInsights()
+	run, err := s.engine.SubmitClusterAnalysis(r.Context())
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get AI insights: %v", err), http.StatusInternalServerError)
+		s.writeAnalysisError(w, err)
 		return
 	}
-	s.writeJSON(w, insights)
+	s.respondWithRun(w, r, run)
 }
 
 // handleAIAnalyze performs AI analysis on a specific health check
@@ -391,16 +665,61 @@ func (s *Server) handleUIConfig(w http.ResponseWriter, r *http.Request) {
 		"maxReconnectAttempts": s.uiConfig.MaxReconnectAttempts,
 		"reconnectDelay":       s.uiConfig.ReconnectDelay.Milliseconds(),
 		"theme":                s.uiConfig.Theme,
-		"features": map[string]bool{
-			"aiInsights":          s.uiConfig.Features.AIInsights,
-			"predictiveAnalytics": s.uiConfig.Features.PredictiveAnalytics,
-			"smartAlerts":         s.uiConfig.Features.SmartAlerts,
-			"nodeDetails":         s.uiConfig.Features.NodeDetails,
+		"readOnly":             s.readOnly,
+		"features":             s.featureFlags(),
+		"reconnect": map[string]interface{}{
+			"initialDelay": s.uiConfig.ReconnectDelay.Milliseconds(),
+			"maxDelay":     s.uiConfig.ReconnectMaxDelay.Milliseconds(),
+			"multiplier":   s.uiConfig.ReconnectMultiplier,
+			"jitter":       s.uiConfig.ReconnectJitter,
+			"maxAttempts":  s.uiConfig.MaxReconnectAttempts,
 		},
+		"websocket":    s.websocketInfo(),
+		"capabilities": s.capabilities(),
+		"serverTime":   time.Now().UTC(),
 	}
 	s.writeJSON(w, config)
 }
 
+// websocketInfo advertises the WebSocket protocol the server speaks
+func (s *Server) websocketInfo() map[string]interface{} {
+	info := map[string]interface{}{
+		"protocolVersion": WSProtocolVersion,
+		"topics":          wsTopics,
+		"subscriptions":   wsDefaultTopics,
+		"authRequired":    s.auth != nil,
+	}
+	if s.auth != nil {
+		info["topicRoles"] = topicRoles
+	}
+	return info
+}
+
+// capabilities reports which server-side features are available, so the UI
+// can hide what the server can't do instead of failing on use
+func (s *Server) capabilities() map[string]bool {
+	aiEnabled := s.engine != nil && s.engine.AIEnabled()
+	return map[string]bool{
+		"ai":              aiEnabled,
+		"remediation":     aiEnabled && !s.readOnly,
+		"contextSwitch":   s.contextManager != nil && !s.readOnly,
+		"recordings":      s.engine != nil && s.engine.RecordingEnabled(),
+		"backups":         s.backups != nil,
+		"preflight":       s.preflight != nil,
+		"multiContext":    s.fleet != nil,
+		"federationHub":   s.hub != nil,
+		"federationSpoke": s.spoke != nil,
+		"telemetry":       s.telemetry != nil,
+		"webhooks":        s.webhooks != nil,
+		"diagnosticDumps": s.diagnostics != nil && s.auth != nil,
+		"loadShedding":    s.loadShedding != nil,
+		"silenceSync":     s.silenceSync != nil,
+		"slackActions":    s.slackSigningSecret != "",
+		"websocketAuth":   s.auth != nil,
+		"alertRuleChange": !s.readOnly,
+	}
+}
+
 // writeJSON writes JSON response
 func (s *Server) writeJSON(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -469,21 +788,56 @@ func (h spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
-// handleWebSocket handles WebSocket connections with proper cleanup
+// handleWebSocket handles WebSocket connections with proper cleanup. When
+// tokens are configured, clients authenticate with a bearer token during the
+// upgrade or in an auth message sent first, and are disconnected when the
+// token expires.
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// Clients may pin the protocol version they understand
+	if v := r.URL.Query().Get("v"); v != "" && v != strconv.Itoa(WSProtocolVersion) {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported WebSocket protocol version %q: server speaks v%d", v, WSProtocolVersion))
+		return
+	}
+
+	topics, err := requestedTopics(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, authenticated := anonymous, s.auth == nil
+	if token := bearerToken(r); !authenticated && token != "" {
+		var err error
+		if identity, err = s.auth.Authenticate(token); err != nil {
+			s.writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		authenticated = true
+	}
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		klog.Errorf("WebSocket upgrade failed: %v", err)
 		return
 	}
 
+	if !authenticated {
+		if identity, err = s.authenticateFirstMessage(conn); err != nil {
+			klog.V(2).Infof("WebSocket client failed to authenticate: %v", err)
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()), time.Now().Add(time.Second))
+			_ = conn.Close()
+			return
+		}
+	}
+
 	// Add client with thread safety
 	s.clientsMu.Lock()
-	s.clients[conn] = true
+	s.clients[conn] = &wsClient{identity: identity, deltas: wantsDeltas(r), topics: topics}
 	clientCount := len(s.clients)
 	s.clientsMu.Unlock()
 
-	klog.V(2).Infof("WebSocket client connected. Total clients: %d", clientCount)
+	klog.V(2).Infof("WebSocket client %s connected. Total clients: %d", identity.Name, clientCount)
 
 	// Set up connection cleanup
 	defer func() {
@@ -491,6 +845,17 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		_ = conn.Close()
 	}()
 
+	if !identity.ExpiresAt.IsZero() {
+		expiry := time.AfterFunc(time.Until(identity.ExpiresAt), func() {
+			klog.V(2).Infof("Closing WebSocket client %s: token expired", identity.Name)
+			s.removeClient(conn)
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ErrTokenExpired.Error()), time.Now().Add(time.Second))
+			_ = conn.Close()
+		})
+		defer expiry.Stop()
+	}
+
 	// Set up ping/pong to detect dead connections
 	_ = conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	conn.SetPongHandler(func(string) error {
@@ -501,23 +866,44 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Start ping routine
 	go s.pingClient(conn)
 
-	// Read messages from client (mainly for keeping connection alive)
+	// Read subscription changes from the client
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 		default:
-			_, _, err := conn.ReadMessage()
+			_, payload, err := conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					klog.Errorf("WebSocket error: %v", err)
 				}
 				return
 			}
+			s.handleClientMessage(conn, payload)
 		}
 	}
 }
 
+// authenticateFirstMessage waits for a client's auth message and confirms it
+// with an auth.ok message carrying the identity
+func (s *Server) authenticateFirstMessage(conn *websocket.Conn) (Identity, error) {
+	_ = conn.SetReadDeadline(time.Now().Add(wsAuthTimeout))
+	var request WSAuthRequest
+	if err := conn.ReadJSON(&request); err != nil || request.Type != WSMessageAuth {
+		return Identity{}, fmt.Errorf("expected an %s message: %w", WSMessageAuth, ErrInvalidToken)
+	}
+	identity, err := s.auth.Authenticate(request.Token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(NewWSMessage(WSMessageAuthenticated, identity)); err != nil {
+		return Identity{}, err
+	}
+	return identity, nil
+}
+
 // removeClient safely removes a client from the map
 func (s *Server) removeClient(conn *websocket.Conn) {
 	s.clientsMu.Lock()
@@ -537,8 +923,8 @@ func (s *Server) pingClient(conn *websocket.Conn) {
 	for {
 		select {
 		case <-ticker.C:
-			_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			// WriteControl is safe to call concurrently with broadcasts
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
 				return
 			}
 		case <-s.ctx.Done():
@@ -586,8 +972,23 @@ func (s *Server) cleanupClients() {
 
 // BroadcastToClients sends data to all connected WebSocket clients
 func (s *Server) BroadcastToClients(data interface{}) {
-	s.clientsMu.RLock()
-	defer s.clientsMu.RUnlock()
+	s.broadcast(data, func(*wsClient) bool { return true })
+}
+
+// broadcast sends data to the connected WebSocket clients that allow it
+func (s *Server) broadcast(data interface{}, allow func(*wsClient) bool) {
+	s.broadcastEach(func(client *wsClient) (interface{}, bool) {
+		return data, allow(client)
+	})
+}
+
+// broadcastEach sends each connected WebSocket client the message chosen for
+// it, skipping clients for which choose returns false; sent is called after
+// each successful write. The exclusive lock serializes writers, since a
+// connection supports only one concurrent writer.
+func (s *Server) broadcastEach(choose func(*wsClient) (interface{}, bool), sent ...func(*wsClient)) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
 
 	if len(s.clients) == 0 {
 		return
@@ -595,15 +996,23 @@ func (s *Server) BroadcastToClients(data interface{}) {
 
 	var deadConnections []*websocket.Conn
 
-	for conn := range s.clients {
+	for conn, client := range s.clients {
+		data, ok := choose(client)
+		if !ok {
+			continue
+		}
 		_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 		if err := conn.WriteJSON(data); err != nil {
 			klog.V(3).Infof("Failed to send to WebSocket client: %v", err)
 			deadConnections = append(deadConnections, conn)
+			continue
+		}
+		for _, callback := range sent {
+			callback(client)
 		}
 	}
 
-	// Clean up dead connections (but don't modify map during read lock)
+	// Clean up dead connections after releasing the lock
 	if len(deadConnections) > 0 {
 		go func() {
 			for _, conn := range deadConnections {
@@ -632,6 +1041,13 @@ func (s *Server) handleListContexts(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// currentContextStatus is the current context with the checks its
+// credentials aren't allowed to run
+type currentContextStatus struct {
+	k8s.ContextInfo
+	RestrictedChecks []core.RestrictedCheck `json:"restricted_checks"`
+}
+
 // handleGetCurrentContext returns the current context information
 func (s *Server) handleGetCurrentContext(w http.ResponseWriter, r *http.Request) {
 	if s.contextManager == nil {
@@ -645,7 +1061,11 @@ func (s *Server) handleGetCurrentContext(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	s.writeJSON(w, context)
+	status := currentContextStatus{ContextInfo: context, RestrictedChecks: []core.RestrictedCheck{}}
+	if s.engine != nil {
+		status.RestrictedChecks = s.engine.RestrictedChecks()
+	}
+	s.writeJSON(w, status)
 }
 
 // handleSwitchContext switches to a different Kubernetes context
@@ -669,6 +1089,11 @@ func (s *Server) handleSwitchContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	previousContext := ""
+	if current, err := s.contextManager.GetCurrentContext(); err == nil {
+		previousContext = current.Name
+	}
+
 	// Switch context
 	if err := s.contextManager.SwitchContext(req.ContextName); err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
@@ -694,10 +1119,20 @@ func (s *Server) handleSwitchContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.engine != nil {
+		s.engine.RecordChange(core.Change{
+			Kind:     core.ChangeKindContextSwitch,
+			Resource: "context/" + context.Name,
+			Message:  fmt.Sprintf("Switched context from %s to %s", previousContext, context.Name),
+			From:     previousContext,
+			To:       context.Name,
+		})
+	}
+
 	// Broadcast context change to WebSocket clients
-	s.BroadcastToClients(map[string]interface{}{
-		"type":    "context_switched",
-		"context": context,
+	s.Publish(WSMessageContextSwitched, ContextSwitchedData{
+		Context:  context,
+		Previous: previousContext,
 	})
 
 	s.writeJSON(w, map[string]interface{}{
@@ -720,7 +1155,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 			websocket.FormatCloseMessage(websocket.CloseGoingAway, "Server shutting down"))
 		_ = conn.Close()
 	}
-	s.clients = make(map[*websocket.Conn]bool)
+	s.clients = make(map[*websocket.Conn]*wsClient)
 	s.clientsMu.Unlock()
 
 	// Shutdown HTTP server
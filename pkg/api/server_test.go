@@ -12,6 +12,8 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/kubepulse/kubepulse/internal/config"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/kubepulse/kubepulse/pkg/version"
 )
 
 func TestConfig_Validation(t *testing.T) {
@@ -398,9 +400,137 @@ func TestServer_UIConfigResponse(t *testing.T) {
 	}
 }
 
+func TestServer_UIConfigAdvertisesServer(t *testing.T) {
+	server := &Server{
+		uiConfig: config.UIConfig{
+			ReconnectDelay:      time.Second,
+			ReconnectMaxDelay:   time.Minute,
+			ReconnectMultiplier: 2,
+			ReconnectJitter:     0.2,
+		},
+		readOnly: true,
+		auth:     NewAuthenticator([]Credential{{Name: "dashboard", Token: "viewer-token-0123456789", Role: RoleViewer}}),
+	}
+
+	w := httptest.NewRecorder()
+	server.handleUIConfig(w, httptest.NewRequest(http.MethodGet, "/api/v1/config/ui", nil))
+	var response struct {
+		Reconnect struct {
+			InitialDelay int64   `json:"initialDelay"`
+			MaxDelay     int64   `json:"maxDelay"`
+			Multiplier   float64 `json:"multiplier"`
+			Jitter       float64 `json:"jitter"`
+		} `json:"reconnect"`
+		WebSocket struct {
+			ProtocolVersion int               `json:"protocolVersion"`
+			Topics          []string          `json:"topics"`
+			AuthRequired    bool              `json:"authRequired"`
+			TopicRoles      map[string]string `json:"topicRoles"`
+		} `json:"websocket"`
+		Capabilities map[string]bool `json:"capabilities"`
+		ServerTime   time.Time       `json:"serverTime"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+
+	if r := response.Reconnect; r.InitialDelay != 1000 || r.MaxDelay != 60000 || r.Multiplier != 2 || r.Jitter != 0.2 {
+		t.Errorf("unexpected reconnect policy %+v", r)
+	}
+	ws := response.WebSocket
+	if ws.ProtocolVersion != WSProtocolVersion || len(ws.Topics) != len(wsTopics) || !ws.AuthRequired || ws.TopicRoles[WSMessageRemediationStatus] != RoleAdmin {
+		t.Errorf("unexpected websocket info %+v", ws)
+	}
+	if response.Capabilities["ai"] || response.Capabilities["remediation"] || !response.Capabilities["websocketAuth"] || response.Capabilities["alertRuleChange"] {
+		t.Errorf("unexpected capabilities %v", response.Capabilities)
+	}
+	if time.Since(response.ServerTime) > time.Minute {
+		t.Errorf("unexpected server time %s", response.ServerTime)
+	}
+}
+
+func TestServer_VersionInfo(t *testing.T) {
+	server := &Server{}
+
+	rr := httptest.NewRecorder()
+	server.handleVersion(rr, httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+	var info version.Info
+	if err := json.NewDecoder(rr.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode version: %v", err)
+	}
+	if info.Version != version.Version || info.GoVersion == "" || info.Platform == "" {
+		t.Errorf("unexpected build info %+v", info)
+	}
+
+	rr = httptest.NewRecorder()
+	server.handleHealth(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	var health map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if health["version"] != version.Version {
+		t.Errorf("expected health to report version %s, got %v", version.Version, health["version"])
+	}
+	if _, ok := health["update"]; ok {
+		t.Error("expected no update field without an update checker")
+	}
+}
+
+func TestServer_Preflight(t *testing.T) {
+	server := &Server{}
+
+	rr := httptest.NewRecorder()
+	server.handlePreflight(rr, httptest.NewRequest(http.MethodGet, "/api/v1/system/preflight", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without preflight config, got %d", rr.Code)
+	}
+
+	server.preflight = &preflight.Config{ListenAddr: "127.0.0.1:8080", Serving: true}
+	rr = httptest.NewRecorder()
+	server.handlePreflight(rr, httptest.NewRequest(http.MethodGet, "/api/v1/system/preflight", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var report preflight.Report
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	// No Kubernetes client was configured
+	if report.Passed || len(report.Results) == 0 || report.Results[0].Name != "kubeconfig" {
+		t.Errorf("expected failed kubeconfig check, got %+v", report)
+	}
+}
+
+func TestServer_HealthReportsUpdate(t *testing.T) {
+	releases := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name": "v99.0.0", "html_url": "https://example.com/v99.0.0"}`))
+	}))
+	defer releases.Close()
+
+	checker := version.NewUpdateChecker(version.UpdateConfig{APIURL: releases.URL})
+	if _, err := checker.Check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := &Server{updates: checker}
+
+	rr := httptest.NewRecorder()
+	server.handleHealth(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+
+	var health struct {
+		Update *version.UpdateStatus `json:"update"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&health); err != nil {
+		t.Fatalf("failed to decode health: %v", err)
+	}
+	if health.Update == nil || !health.Update.UpdateAvailable || health.Update.LatestVersion != "99.0.0" {
+		t.Errorf("expected update notice in health, got %+v", health.Update)
+	}
+}
+
 func TestServer_ClientManagement(t *testing.T) {
 	server := &Server{
-		clients: make(map[*websocket.Conn]bool),
+		clients: make(map[*websocket.Conn]*wsClient),
 	}
 
 	if server.clients == nil {
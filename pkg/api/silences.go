@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"gopkg.in/yaml.v3"
+)
+
+// SilenceRequest silences alerts whose labels equal all of its matchers,
+// until a time or for a duration
+type SilenceRequest struct {
+	Matchers  map[string]string `json:"matchers"` // Alert label to value: check, rule, severity or chaos
+	Until     time.Time         `json:"until,omitzero"`
+	Duration  string            `json:"duration,omitempty"` // e.g. "1h"; used when until is unset
+	Comment   string            `json:"comment,omitempty"`
+	CreatedBy string            `json:"created_by,omitempty"` // Defaults to "api"
+}
+
+// maxSilenceImport bounds the size of a silence import body
+const maxSilenceImport = 1 << 20
+
+// SilenceFile is the document silences are exported as and imported from
+type SilenceFile struct {
+	Silences []alerts.Silence `json:"silences" yaml:"silences"`
+}
+
+// SilenceImportError is a silence of an import that was rejected
+type SilenceImportError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// handleListSilences lists the silences still in effect, with how the
+// Alertmanager sync went when one is configured
+func (s *Server) handleListSilences(w http.ResponseWriter, r *http.Request) {
+	silences := s.engine.GetSilences()
+	response := map[string]interface{}{
+		"silences": silences,
+		"total":    len(silences),
+	}
+	if s.silenceSync != nil {
+		response["alertmanager"] = s.silenceSync.Status()
+	}
+	s.writeJSON(w, response)
+}
+
+// handleExportSilences returns the matcher silences still in effect as a
+// SilenceFile, in YAML with format=yaml and JSON otherwise. Silences of a
+// single alert fingerprint are left out, since they can't be imported.
+func (s *Server) handleExportSilences(w http.ResponseWriter, r *http.Request) {
+	file := SilenceFile{Silences: []alerts.Silence{}}
+	for _, silence := range s.engine.GetSilences() {
+		if len(silence.Matchers) > 0 {
+			file.Silences = append(file.Silences, silence)
+		}
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		w.Header().Set("Content-Disposition", `attachment; filename="kubepulse-silences.json"`)
+		s.writeJSON(w, file)
+	case "yaml":
+		data, err := yaml.Marshal(file)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("Content-Disposition", `attachment; filename="kubepulse-silences.yaml"`)
+		_, _ = w.Write(data)
+	default:
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid format %q: use json or yaml", format))
+	}
+}
+
+// handleImportSilences adds the silences of a SilenceFile, or of a bare
+// list, in YAML or JSON. Silences that have ended, or whose ID is already
+// in effect, are skipped, so importing the same file twice is harmless.
+func (s *Server) handleImportSilences(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSilenceImport))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	var file SilenceFile
+	if err := decodeSilences(body, &file); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid silences: %v", err))
+		return
+	}
+
+	existing := make(map[string]bool)
+	for _, silence := range s.engine.GetSilences() {
+		existing[silence.ID] = true
+	}
+	imported := []alerts.Silence{}
+	errs := []SilenceImportError{}
+	skipped := 0
+	now := time.Now()
+	for i, silence := range file.Silences {
+		if !silence.Until.After(now) || (silence.ID != "" && existing[silence.ID]) {
+			skipped++
+			continue
+		}
+		if silence.CreatedBy == "" {
+			silence.CreatedBy = "import"
+		}
+		added, err := s.engine.AddSilence(silence)
+		if err != nil {
+			errs = append(errs, SilenceImportError{Index: i, Error: err.Error()})
+			continue
+		}
+		existing[added.ID] = true
+		imported = append(imported, added)
+	}
+
+	s.writeJSON(w, map[string]interface{}{
+		"imported": imported,
+		"skipped":  skipped,
+		"errors":   errs,
+	})
+}
+
+// handleCreateSilence silences alerts matching a set of labels
+func (s *Server) handleCreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req SilenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	until := req.Until
+	switch {
+	case !until.IsZero() && req.Duration != "":
+		s.writeError(w, http.StatusBadRequest, "set until or duration, not both")
+		return
+	case req.Duration != "":
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			s.writeError(w, http.StatusBadRequest, "duration must be a positive duration such as 1h")
+			return
+		}
+		until = time.Now().Add(duration)
+	case until.IsZero():
+		until = time.Now().Add(alerts.DefaultSilenceDuration)
+	}
+	if req.CreatedBy == "" {
+		req.CreatedBy = "api"
+	}
+
+	silence, err := s.engine.AddSilence(alerts.Silence{
+		Matchers:  req.Matchers,
+		Until:     until,
+		Comment:   req.Comment,
+		CreatedBy: req.CreatedBy,
+	})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, silence)
+}
+
+// handleExpireSilence ends a silence before it expires
+func (s *Server) handleExpireSilence(w http.ResponseWriter, r *http.Request) {
+	silence, err := s.engine.ExpireSilence(mux.Vars(r)["id"])
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, alerts.ErrSilenceNotFound) {
+			status = http.StatusNotFound
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	s.writeJSON(w, silence)
+}
+
+// decodeSilences reads a SilenceFile or a bare list of silences, as JSON
+// when the body starts like JSON and as YAML otherwise
+func decodeSilences(body []byte, file *SilenceFile) error {
+	unmarshal := yaml.Unmarshal
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(body, file); err != nil {
+		if listErr := unmarshal(body, &file.Silences); listErr != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_Silences(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"invalid body", "{", http.StatusBadRequest},
+		{"no matchers", `{"duration": "1h"}`, http.StatusBadRequest},
+		{"unknown label", `{"matchers": {"pod": "web"}, "duration": "1h"}`, http.StatusBadRequest},
+		{"both expiries", `{"matchers": {"check": "pod-health"}, "duration": "1h", "until": "2099-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"bad duration", `{"matchers": {"check": "pod-health"}, "duration": "soon"}`, http.StatusBadRequest},
+		{"past until", `{"matchers": {"check": "pod-health"}, "until": "2000-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"default duration", `{"matchers": {"check": "pod-health", "rule": "pod-health-critical"}}`, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/alerts/silences", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alerts/silences", nil))
+	var list struct {
+		Silences []alerts.Silence `json:"silences"`
+		Total    int              `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Total != 1 || list.Silences[0].CreatedBy != "api" || list.Silences[0].Matchers["rule"] != "pod-health-critical" {
+		t.Fatalf("expected the created silence listed, got %s", w.Body.String())
+	}
+
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/alerts/silences/"+list.Silences[0].ID, nil))
+		if w.Code != want {
+			t.Errorf("expected status %d expiring the silence, got %d: %s", want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestServer_SilenceImportExport(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	type importResult struct {
+		Imported []alerts.Silence     `json:"imported"`
+		Skipped  int                  `json:"skipped"`
+		Errors   []SilenceImportError `json:"errors"`
+	}
+
+	yamlFile := `silences:
+  - id: maint-nodes
+    matchers:
+      check: node-health
+    until: 2099-01-01T00:00:00Z
+    comment: node upgrades
+  - matchers:
+      check: pod-health
+    until: 2000-01-01T00:00:00Z
+  - matchers:
+      pod: web
+    until: 2099-01-01T00:00:00Z
+`
+	tests := []struct {
+		name         string
+		body         string
+		wantImported int
+		wantSkipped  int
+		wantErrors   int
+	}{
+		{"yaml", yamlFile, 1, 1, 1},
+		{"again", yamlFile, 0, 2, 1},
+		{"json list", `[{"matchers": {"severity": "warning"}, "until": "2099-01-01T00:00:00Z"}]`, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := request(http.MethodPost, "/api/v1/alerts/silences/import", tt.body)
+			var result importResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to decode response %s: %v", w.Body.String(), err)
+			}
+			if len(result.Imported) != tt.wantImported || result.Skipped != tt.wantSkipped || len(result.Errors) != tt.wantErrors {
+				t.Errorf("expected %d imported, %d skipped and %d errors, got %+v", tt.wantImported, tt.wantSkipped, tt.wantErrors, result)
+			}
+		})
+	}
+	if w := request(http.MethodPost, "/api/v1/alerts/silences/import", "silences: ["); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed YAML, got %d", w.Code)
+	}
+
+	w := request(http.MethodGet, "/api/v1/alerts/silences/export?format=yaml", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("expected a YAML export, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var file SilenceFile
+	if err := decodeSilences(w.Body.Bytes(), &file); err != nil || len(file.Silences) != 2 {
+		t.Fatalf("expected the 2 imported silences exported, got %+v, %v", file, err)
+	}
+
+	// Re-importing an export into another server restores the silences
+	other := NewServer(Config{Engine: core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})})
+	defer func() { _ = other.Shutdown(context.Background()) }()
+	exported := request(http.MethodGet, "/api/v1/alerts/silences/export", "")
+	w = httptest.NewRecorder()
+	other.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/alerts/silences/import", exported.Body))
+	if silences := other.engine.GetSilences(); len(silences) != 2 || silences[0].ID != file.Silences[0].ID {
+		t.Errorf("expected the export restored with its IDs, got %+v", silences)
+	}
+}
+
+func TestHandleSlackActions_Silence(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine, SlackSigningSecret: "secret"})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	payload := `{"user":{"username":"bob"},"actions":[{"action_id":"kubepulse_silence","value":"check=pod-health&rule=pod-health-critical"}]}`
+	body := "payload=" + url.QueryEscape(payload)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/alerts/slack/actions", strings.NewReader(body))
+	req.Header = signSlackBody("secret", body)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	silences := engine.GetSilences()
+	if len(silences) != 1 || silences[0].CreatedBy != "slack:bob" || silences[0].Matchers["check"] != "pod-health" {
+		t.Errorf("expected the Slack click to silence the alert, got %+v", silences)
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// handleListSLOs lists the status of every SLO by name: its SLI, the error
+// budget left and, for ratio SLIs, the burn rates of its alerts
+func (s *Server) handleListSLOs(w http.ResponseWriter, r *http.Request) {
+	statuses := s.engine.GetSLOStatuses()
+	slos := make([]*core.SLOStatus, 0, len(statuses))
+	for _, status := range statuses {
+		slos = append(slos, status)
+	}
+	sort.Slice(slos, func(i, j int) bool { return slos[i].SLO.Name < slos[j].SLO.Name })
+	s.writeJSON(w, map[string]interface{}{
+		"slos":  slos,
+		"total": len(slos),
+	})
+}
+
+// handleGetSLO returns the status of one SLO
+func (s *Server) handleGetSLO(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	status, ok := s.engine.GetSLOStatus(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "SLO not found: "+name)
+		return
+	}
+	s.writeJSON(w, status)
+}
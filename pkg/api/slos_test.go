@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_SLOs(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		SLOs: []slo.SLO{
+			{Name: "web", SLI: "availability", Target: 99, Check: "web-health"},
+			{Name: "api", SLI: "error_rate", Target: 99.9},
+		},
+	})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/slo", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var response struct {
+		SLOs  []core.SLOStatus `json:"slos"`
+		Total int              `json:"total"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode SLOs: %v", err)
+	}
+	if response.Total != 2 || response.SLOs[0].SLO.Name != "api" || response.SLOs[1].SLO.Check != "web-health" {
+		t.Errorf("expected both SLOs sorted by name, got %+v", response)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/slo/web", http.StatusOK},
+		{"/api/v1/slo/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d: %s", tt.path, tt.want, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/slo/web", nil))
+	var status core.SLOStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode SLO: %v", err)
+	}
+	if status.SLO.Name != "web" || len(status.BurnRates) != 3 || status.ErrorBudget != 100 {
+		t.Errorf("expected the web SLO with its default burn-rate alerts, got %+v", status)
+	}
+}
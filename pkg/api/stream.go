@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/klog/v2"
+)
+
+// streamHeartbeatInterval keeps idle SSE connections open through proxies
+const streamHeartbeatInterval = 15 * time.Second
+
+// handleStreamResults streams check results and alerts as Server-Sent Events.
+// Clients resume after a disconnect by sending the last event ID they received
+// in the Last-Event-ID header (or last_event_id query parameter). When the ID
+// can't be resumed a "reset" event is sent and the client should re-sync.
+func (s *Server) handleStreamResults(w http.ResponseWriter, r *http.Request) {
+	if s.engine == nil {
+		s.writeError(w, http.StatusInternalServerError, "Engine not initialized")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	token := r.Header.Get("Last-Event-ID")
+	if token == "" {
+		token = r.URL.Query().Get("last_event_id")
+	}
+	types := parseCommaSet(r.URL.Query().Get("types"))
+
+	// Streams outlive the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		klog.V(2).Infof("Unable to clear write deadline for stream: %v", err)
+	}
+
+	backlog, complete, events, cancel := s.engine.SubscribeStream(token)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !complete {
+		_, _ = fmt.Fprintf(w, "event: reset\ndata: {\"reason\":\"resume token expired or unknown\"}\n\n")
+	}
+	for _, event := range backlog {
+		if err := writeStreamEvent(w, event, types); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// Fell behind; the client reconnects with its last event ID
+				return
+			}
+			if err := writeStreamEvent(w, event, types); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// writeStreamEvent writes one SSE frame, skipping types the client didn't ask for
+func writeStreamEvent(w http.ResponseWriter, event core.StreamEvent, types map[string]bool) error {
+	if len(types) > 0 && !types[event.Type] {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("Failed to encode stream event %s: %v", event.ID, err)
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// parseCommaSet parses a comma-separated filter such as event types or change kinds
+func parseCommaSet(raw string) map[string]bool {
+	if raw == "" {
+		return nil
+	}
+	types := make(map[string]bool)
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[t] = true
+		}
+	}
+	return types
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// staticCheck always reports the same status
+type staticCheck struct {
+	name   string
+	status core.HealthStatus
+}
+
+func (c *staticCheck) Name() string                                  { return c.name }
+func (c *staticCheck) Description() string                           { return "static check" }
+func (c *staticCheck) Configure(config map[string]interface{}) error { return nil }
+func (c *staticCheck) Interval() time.Duration                       { return time.Second }
+func (c *staticCheck) Criticality() core.Criticality                 { return core.CriticalityLow }
+
+func (c *staticCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	return core.CheckResult{Name: c.name, Status: c.status, Timestamp: time.Now()}, nil
+}
+
+// sseFrame is a parsed Server-Sent Events frame
+type sseFrame struct {
+	id    string
+	event string
+	data  string
+}
+
+// readFrames reads up to n SSE frames, ignoring comments
+func readFrames(t *testing.T, reader *bufio.Reader, n int) []sseFrame {
+	t.Helper()
+	frames := make([]sseFrame, 0, n)
+	var frame sseFrame
+	for len(frames) < n {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if frame.event != "" {
+				frames = append(frames, frame)
+			}
+			frame = sseFrame{}
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	return frames
+}
+
+func openStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open stream: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %s", ct)
+	}
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestHandleStreamResults_Resume(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   20 * time.Millisecond,
+	})
+	engine.AddCheck(&staticCheck{name: "flaky", status: core.HealthStatusDegraded})
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	url := ts.URL + "/api/v1/stream/results"
+
+	resp, reader := openStream(t, url+"?types=check_result", "")
+	frames := readFrames(t, reader, 2)
+	_ = resp.Body.Close()
+
+	for _, frame := range frames {
+		if frame.event != core.StreamEventCheckResult {
+			t.Errorf("expected only check_result events, got %s", frame.event)
+		}
+		if !strings.Contains(frame.data, `"name":"flaky"`) {
+			t.Errorf("expected check result payload, got %s", frame.data)
+		}
+	}
+
+	// Resuming from the first event replays everything after it, alerts included
+	resp, reader = openStream(t, url, frames[0].id)
+	resumed := readFrames(t, reader, 2)
+	_ = resp.Body.Close()
+
+	if resumed[0].id == frames[0].id {
+		t.Error("expected events after the resume token, got the token itself")
+	}
+	sawAlert := false
+	for _, frame := range resumed {
+		if frame.event == core.StreamEventAlert {
+			sawAlert = true
+		}
+	}
+	if !sawAlert {
+		t.Error("expected alert events for a degraded check")
+	}
+}
+
+func TestHandleStreamResults_UnknownToken(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	resp, reader := openStream(t, ts.URL+"/api/v1/stream/results", "stale-42")
+	defer func() { _ = resp.Body.Close() }()
+
+	frames := readFrames(t, reader, 1)
+	if frames[0].event != "reset" {
+		t.Errorf("expected reset event for unknown token, got %s", frames[0].event)
+	}
+}
+
+func TestParseCommaSet(t *testing.T) {
+	if parseCommaSet("") != nil {
+		t.Error("expected nil filter for empty input")
+	}
+	types := parseCommaSet("alert, check_result,")
+	if len(types) != 2 || !types["alert"] || !types["check_result"] {
+		t.Errorf("unexpected filter %v", types)
+	}
+}
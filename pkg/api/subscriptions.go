@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/klog/v2"
+)
+
+// WebSocket subscription topics. A client receives the message types of the
+// topics it is subscribed to; a check topic, check:<name>, adds the messages
+// about that check.
+const (
+	WSTopicHealth         = "health"          // health.updated, health.delta
+	WSTopicAlerts         = "alerts"          // alert.fired, alert.resolved
+	WSTopicAIInsights     = "ai-insights"     // ai.insight, analysis.completed
+	WSTopicContextChanges = "context-changes" // context.switched
+	WSTopicRemediation    = "remediation"     // remediation.status
+	WSTopicFeatures       = "features"        // features.changed
+	WSTopicCheckPrefix    = "check:"          // check.result, and the alerts and AI insights of the check
+)
+
+// wsDefaultTopics are the topics a client is subscribed to unless it picks
+// its own when connecting
+var wsDefaultTopics = []string{
+	WSTopicHealth,
+	WSTopicAlerts,
+	WSTopicAIInsights,
+	WSTopicContextChanges,
+	WSTopicRemediation,
+	WSTopicFeatures,
+}
+
+// wsMessageTopics is the topic of each pushed message type; types without a
+// topic, such as replies to the client, are always sent
+var wsMessageTopics = map[string]string{
+	WSMessageHealthUpdated:     WSTopicHealth,
+	WSMessageHealthDelta:       WSTopicHealth,
+	WSMessageAlertFired:        WSTopicAlerts,
+	WSMessageAlertResolved:     WSTopicAlerts,
+	WSMessageAIInsight:         WSTopicAIInsights,
+	WSMessageAnalysisCompleted: WSTopicAIInsights,
+	WSMessageContextSwitched:   WSTopicContextChanges,
+	WSMessageRemediationStatus: WSTopicRemediation,
+	WSMessageFeaturesChanged:   WSTopicFeatures,
+	WSMessageCheckResult:       "", // Only through check topics
+}
+
+// Messages a client sends to change its subscription
+const (
+	WSMessageSubscribe   = "subscribe"
+	WSMessageUnsubscribe = "unsubscribe"
+)
+
+// WSSubscriptionRequest is a subscribe or unsubscribe message a client
+// sends to change its topics
+type WSSubscriptionRequest struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics"`
+}
+
+// SubscriptionData lists the topics a client is subscribed to
+type SubscriptionData struct {
+	Topics []string `json:"topics"`
+}
+
+// WSErrorData reports a client message the server rejected
+type WSErrorData struct {
+	Request string `json:"request,omitempty"` // Type of the rejected message
+	Message string `json:"message"`
+}
+
+// validTopic reports whether a client may subscribe to a topic
+func validTopic(topic string) bool {
+	if check, ok := strings.CutPrefix(topic, WSTopicCheckPrefix); ok {
+		return check != ""
+	}
+	for _, known := range wsDefaultTopics {
+		if topic == known {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTopics validates a list of topics
+func parseTopics(topics []string) ([]string, error) {
+	var parsed []string
+	for _, topic := range topics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		if !validTopic(topic) {
+			return nil, fmt.Errorf("unknown topic %q: want one of %s, or %s<name>", topic, strings.Join(wsDefaultTopics, ", "), WSTopicCheckPrefix)
+		}
+		parsed = append(parsed, topic)
+	}
+	return parsed, nil
+}
+
+// requestedTopics returns the topics a client asked for when connecting,
+// comma-separated in the topics query parameter, or the default topics
+func requestedTopics(r *http.Request) (map[string]bool, error) {
+	topics := wsDefaultTopics
+	if values, ok := r.URL.Query()["topics"]; ok {
+		var err error
+		if topics, err = parseTopics(strings.Split(strings.Join(values, ","), ",")); err != nil {
+			return nil, err
+		}
+	}
+	subscribed := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		subscribed[topic] = true
+	}
+	return subscribed, nil
+}
+
+// subscribed reports whether a client is subscribed to a message type,
+// either through its topic or a check topic of a check the message is
+// about. Callers hold clientsMu.
+func (c *wsClient) subscribed(messageType string, checks []string) bool {
+	topic, ok := wsMessageTopics[messageType]
+	if !ok {
+		return true
+	}
+	if topic != "" && c.topics[topic] {
+		return true
+	}
+	for _, check := range checks {
+		if c.topics[WSTopicCheckPrefix+check] {
+			return true
+		}
+	}
+	return false
+}
+
+// topicList lists a client's topics in order; callers hold clientsMu
+func (c *wsClient) topicList() []string {
+	topics := make([]string, 0, len(c.topics))
+	for topic := range c.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// messageChecks returns the checks a pushed message is about
+func messageChecks(data interface{}) []string {
+	switch data := data.(type) {
+	case core.CheckResult:
+		return []string{data.Name}
+	case core.Alert:
+		return []string{data.Name}
+	case AlertResolvedData:
+		return []string{data.Name}
+	case core.AIInsightEvent:
+		return []string{data.Check}
+	case core.AnalysisRun:
+		return data.Checks
+	}
+	return nil
+}
+
+// handleClientMessage applies a message a connected client sent, answering
+// with its subscription or an error
+func (s *Server) handleClientMessage(conn *websocket.Conn, payload []byte) {
+	var message WSSubscriptionRequest
+	if err := json.Unmarshal(payload, &message); err != nil {
+		s.sendTo(conn, NewWSMessage(WSMessageError, WSErrorData{Message: "messages must be JSON objects with a type"}))
+		return
+	}
+
+	switch message.Type {
+	case WSMessageSubscribe, WSMessageUnsubscribe:
+		topics, err := parseTopics(message.Topics)
+		if err != nil {
+			s.sendTo(conn, NewWSMessage(WSMessageError, WSErrorData{Request: message.Type, Message: err.Error()}))
+			return
+		}
+		s.clientsMu.Lock()
+		defer s.clientsMu.Unlock()
+		client, ok := s.clients[conn]
+		if !ok {
+			return
+		}
+		for _, topic := range topics {
+			if message.Type == WSMessageSubscribe {
+				client.topics[topic] = true
+			} else {
+				delete(client.topics, topic)
+			}
+		}
+		s.write(conn, NewWSMessage(WSMessageSubscribed, SubscriptionData{Topics: client.topicList()}))
+	case WSMessageAuth:
+		s.sendTo(conn, NewWSMessage(WSMessageError, WSErrorData{Request: message.Type, Message: "already authenticated"}))
+	default:
+		s.sendTo(conn, NewWSMessage(WSMessageError, WSErrorData{Request: message.Type, Message: fmt.Sprintf("unknown message type %q", message.Type)}))
+	}
+}
+
+// sendTo sends a message to one connected client
+func (s *Server) sendTo(conn *websocket.Conn, message WSMessage) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	if _, ok := s.clients[conn]; ok {
+		s.write(conn, message)
+	}
+}
+
+// write sends a message to a client; callers hold clientsMu, which
+// serializes writers
+func (s *Server) write(conn *websocket.Conn, message WSMessage) {
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteJSON(message); err != nil {
+		klog.V(3).Infof("Failed to send to WebSocket client: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func TestRequestedTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "defaults", query: "", want: wsDefaultTopics},
+		{name: "picked", query: "topics=alerts,check:pod-health", want: []string{"alerts", "check:pod-health"}},
+		{name: "repeated parameter", query: "topics=alerts&topics=health", want: []string{"alerts", "health"}},
+		{name: "none", query: "topics=", want: nil},
+		{name: "unknown topic", query: "topics=alerts,metrics", wantErr: true},
+		{name: "check without a name", query: "topics=check:", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topics, err := requestedTopics(httptest.NewRequest(http.MethodGet, "/ws?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requestedTopics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			client := &wsClient{topics: topics}
+			if got := client.topicList(); !sameTopics(got, tt.want) {
+				t.Errorf("topics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sameTopics compares topic lists regardless of order
+func sameTopics(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, topic := range a {
+		set[topic] = true
+	}
+	for _, topic := range b {
+		if !set[topic] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+func TestWSClientSubscribed(t *testing.T) {
+	client := &wsClient{topics: map[string]bool{WSTopicAlerts: true, "check:pod-health": true}}
+	tests := []struct {
+		messageType string
+		checks      []string
+		want        bool
+	}{
+		{WSMessageAlertFired, []string{"dns-health"}, true},
+		{WSMessageHealthUpdated, nil, false},
+		{WSMessageAIInsight, []string{"dns-health"}, false},
+		{WSMessageAIInsight, []string{"pod-health"}, true},
+		{WSMessageCheckResult, []string{"pod-health"}, true},
+		{WSMessageCheckResult, []string{"dns-health"}, false},
+		{WSMessageAnalysisCompleted, []string{"dns-health", "pod-health"}, true},
+		{WSMessageAuthenticated, nil, true}, // Replies have no topic
+	}
+	for _, tt := range tests {
+		if got := client.subscribed(tt.messageType, tt.checks); got != tt.want {
+			t.Errorf("subscribed(%s, %v) = %v, want %v", tt.messageType, tt.checks, got, tt.want)
+		}
+	}
+}
+
+func TestWebSocket_Subscriptions(t *testing.T) {
+	server := NewServer(Config{CORSEnabled: true})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/ws?v=1&topics=nope", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown topic, got %d", rr.Code)
+	}
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws?v=1&topics=alerts"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.clientsMu.RLock()
+		connected := len(server.clients)
+		server.clientsMu.RUnlock()
+		if connected > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("client was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	send := func(request WSSubscriptionRequest) {
+		t.Helper()
+		if err := conn.WriteJSON(request); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+	expectTopics := func(want ...string) {
+		t.Helper()
+		message := readEnvelope(t, conn)
+		if message.Type != WSMessageSubscribed {
+			t.Fatalf("expected %s, got %s: %s", WSMessageSubscribed, message.Type, message.Data)
+		}
+		var data SubscriptionData
+		if err := json.Unmarshal(message.Data, &data); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if !reflect.DeepEqual(data.Topics, want) {
+			t.Errorf("topics = %v, want %v", data.Topics, want)
+		}
+	}
+	// expectNext checks the next message the client receives, so a message
+	// that should have been filtered out shows up in its place
+	expectNext := func(wantType, wantCheck string) {
+		t.Helper()
+		message := readEnvelope(t, conn)
+		var payload struct {
+			Name  string `json:"name"`
+			Check string `json:"check"`
+		}
+		_ = json.Unmarshal(message.Data, &payload)
+		if message.Type != wantType || payload.Name+payload.Check != wantCheck {
+			t.Fatalf("received %s about %q, want %s about %q", message.Type, payload.Name+payload.Check, wantType, wantCheck)
+		}
+	}
+
+	// Only the subscribed topic is delivered
+	server.Publish(WSMessageFeaturesChanged, FeaturesChangedData{})
+	server.Publish(WSMessageAIInsight, core.AIInsightEvent{Check: "pod-health"})
+	server.Publish(WSMessageAlertFired, core.Alert{Name: "dns-health"})
+	expectNext(WSMessageAlertFired, "dns-health")
+
+	// A check topic adds that check's results and insights
+	send(WSSubscriptionRequest{Type: WSMessageSubscribe, Topics: []string{"check:pod-health"}})
+	expectTopics("alerts", "check:pod-health")
+	server.Publish(WSMessageCheckResult, core.CheckResult{Name: "dns-health"})
+	server.Publish(WSMessageCheckResult, core.CheckResult{Name: "pod-health"})
+	expectNext(WSMessageCheckResult, "pod-health")
+	server.Publish(WSMessageAIInsight, core.AIInsightEvent{Check: "dns-health"})
+	server.Publish(WSMessageAIInsight, core.AIInsightEvent{Check: "pod-health"})
+	expectNext(WSMessageAIInsight, "pod-health")
+
+	// Unsubscribing from alerts keeps those of the subscribed check
+	send(WSSubscriptionRequest{Type: WSMessageUnsubscribe, Topics: []string{"alerts"}})
+	expectTopics("check:pod-health")
+	server.Publish(WSMessageAlertFired, core.Alert{Name: "dns-health"})
+	server.Publish(WSMessageAlertFired, core.Alert{Name: "pod-health"})
+	expectNext(WSMessageAlertFired, "pod-health")
+
+	// Health updates follow the health topic
+	server.PublishHealth(core.ClusterHealth{})
+	send(WSSubscriptionRequest{Type: WSMessageSubscribe, Topics: []string{"health"}})
+	expectTopics("check:pod-health", "health")
+	server.PublishHealth(core.ClusterHealth{})
+	if message := readEnvelope(t, conn); message.Type != WSMessageHealthUpdated {
+		t.Fatalf("expected %s, got %s", WSMessageHealthUpdated, message.Type)
+	}
+
+	// Bad requests are answered with an error and leave the subscription
+	for _, request := range []interface{}{
+		WSSubscriptionRequest{Type: WSMessageSubscribe, Topics: []string{"everything"}},
+		map[string]string{"type": "ping"},
+	} {
+		if err := conn.WriteJSON(request); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+		if message := readEnvelope(t, conn); message.Type != WSMessageError {
+			t.Errorf("expected %s for %v, got %s", WSMessageError, request, message.Type)
+		}
+	}
+	send(WSSubscriptionRequest{Type: WSMessageSubscribe})
+	expectTopics("check:pod-health", "health")
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleTelemetry reports whether usage reporting is on and previews the
+// next report exactly as it would be sent
+func (s *Server) handleTelemetry(w http.ResponseWriter, r *http.Request) {
+	if s.telemetry == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Telemetry is not configured")
+		return
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"status":  s.telemetry.Status(),
+		"preview": s.telemetry.Preview(r.Context()),
+	})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/telemetry"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_Telemetry(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	disabled := NewServer(Config{Engine: engine})
+	defer func() { _ = disabled.Shutdown(context.Background()) }()
+	w := httptest.NewRecorder()
+	disabled.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/telemetry", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+
+	collector := telemetry.Collector{Features: []string{"web"}, Usage: engine.Usage}
+	reporter := telemetry.NewReporter(false, "", time.Hour, collector.Collect)
+	server := NewServer(Config{Engine: engine, Telemetry: reporter})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/telemetry", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var response struct {
+		Status  telemetry.Status `json:"status"`
+		Preview telemetry.Report `json:"preview"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Status.Enabled {
+		t.Error("expected telemetry to be reported as off")
+	}
+	// The engine hasn't run yet, so there is no error rate to report
+	if response.Preview.Checks.Runs != "0" || response.Preview.Checks.ErrorRate != telemetry.NoData {
+		t.Errorf("unexpected check usage %+v", response.Preview.Checks)
+	}
+}
@@ -0,0 +1,513 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultTokenRotation is how old a token grows before a rotation
+	// reminder, unless configured otherwise
+	DefaultTokenRotation = 90 * 24 * time.Hour
+
+	// tokenExpiryWarning is how close to its expiry a token gets a rotation
+	// reminder
+	tokenExpiryWarning = 7 * 24 * time.Hour
+
+	// tokenReminderInterval is how often rotation reminders are logged for
+	// each token due
+	tokenReminderInterval = 24 * time.Hour
+)
+
+// Sources of API tokens
+const (
+	TokenSourceConfig = "config" // server.auth.tokens; revoked by editing the config
+	TokenSourceAPI    = "api"    // Created through the API
+)
+
+var (
+	// ErrTokenExists is returned when creating a token with a taken name
+	ErrTokenExists = errors.New("token already exists")
+
+	// ErrTokenNotFound is returned for an unknown token name
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenConfigured is returned when revoking or rotating a token from
+	// the config file
+	ErrTokenConfigured = errors.New("token is defined in the config file")
+)
+
+// managedToken is a token created through the API. Only the SHA-256 hash of
+// its secret is kept.
+type managedToken struct {
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash"`
+	Role      string    `json:"role"`
+	Scope     string    `json:"scope,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+	LastUsed  time.Time `json:"last_used,omitzero"`
+}
+
+// TokenInfo describes an API token without its secret
+type TokenInfo struct {
+	Name           string    `json:"name"`
+	Role           string    `json:"role"`
+	Scope          string    `json:"scope,omitempty"`
+	Cluster        string    `json:"cluster,omitempty"`
+	Source         string    `json:"source"`
+	CreatedBy      string    `json:"created_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"` // Unknown for configured tokens
+	ExpiresAt      time.Time `json:"expires_at,omitzero"`
+	LastUsed       time.Time `json:"last_used,omitzero"`
+	RotationDue    bool      `json:"rotation_due"`
+	RotationReason string    `json:"rotation_reason,omitempty"`
+}
+
+// TokenRequest creates an API token
+type TokenRequest struct {
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Scope     string    `json:"scope,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	ExpiresIn string    `json:"expires_in,omitempty"` // Duration such as 720h; or set expires_at
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// CreatedToken is a new token and its secret, which is only shown once
+type CreatedToken struct {
+	TokenInfo
+	Token string `json:"token"`
+}
+
+// hashToken returns the hex SHA-256 hash of a token secret
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken returns a new random token secret
+func generateToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return "kp_" + hex.EncodeToString(secret), nil
+}
+
+// authenticatorFor creates the server's authenticator from configured
+// credentials and the tokens saved in tokensFile, or returns nil when there
+// are neither and authentication is disabled
+func authenticatorFor(credentials []Credential, tokensFile string, rotateAfter time.Duration) *Authenticator {
+	auth := newAuthenticator(credentials)
+	auth.path = tokensFile
+	if rotateAfter > 0 {
+		auth.rotateAfter = rotateAfter
+	}
+	if err := auth.load(); err != nil {
+		klog.Errorf("Failed to load API tokens: %v", err)
+	}
+	if len(auth.credentials) == 0 && len(auth.managed) == 0 {
+		return nil
+	}
+	return auth
+}
+
+// load reads the tokens file, if any
+func (a *Authenticator) load() error {
+	if a.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var managed []managedToken
+	if err := json.Unmarshal(data, &managed); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", a.path, err)
+	}
+	for _, token := range managed {
+		if !token.LastUsed.IsZero() {
+			a.lastUsed[token.Name] = token.LastUsed
+		}
+	}
+	a.managed = managed
+	return nil
+}
+
+// save writes the managed tokens to the tokens file; callers hold a.mu
+func (a *Authenticator) save() error {
+	if a.path == "" {
+		return nil
+	}
+	a.used = false
+	managed := make([]managedToken, len(a.managed))
+	for i, token := range a.managed {
+		token.LastUsed = a.lastUsed[token.Name]
+		managed[i] = token
+	}
+	data, err := json.MarshalIndent(managed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+
+	dir := filepath.Dir(a.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory for API tokens: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tokens-*")
+	if err != nil {
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), a.path); err != nil {
+		return fmt.Errorf("failed to save API tokens: %w", err)
+	}
+	return nil
+}
+
+// flush saves the last use of tokens when it changed since the last save
+func (a *Authenticator) flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.used || len(a.managed) == 0 {
+		return
+	}
+	if err := a.save(); err != nil {
+		klog.Errorf("Failed to save API token use: %v", err)
+	}
+}
+
+// configured reports whether a token with a name is in the config file
+func (a *Authenticator) configured(name string) bool {
+	for _, credential := range a.credentials {
+		if credential.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Create issues a token, returning it with its secret
+func (a *Authenticator) Create(req TokenRequest, createdBy string) (CreatedToken, error) {
+	if req.Name == "" {
+		return CreatedToken{}, fmt.Errorf("name is required")
+	}
+	if req.Role != RoleViewer && req.Role != RoleAdmin {
+		return CreatedToken{}, fmt.Errorf("role must be %s or %s", RoleViewer, RoleAdmin)
+	}
+	if req.Scope != ScopeAll && req.Scope != ScopeAlerts {
+		return CreatedToken{}, fmt.Errorf("scope must be empty or %s", ScopeAlerts)
+	}
+	now := a.now()
+	expiresAt := req.ExpiresAt
+	if req.ExpiresIn != "" {
+		ttl, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return CreatedToken{}, fmt.Errorf("expires_in must be a positive duration, e.g. 720h")
+		}
+		expiresAt = now.Add(ttl)
+	}
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return CreatedToken{}, fmt.Errorf("expires_at must be in the future")
+	}
+	secret, err := generateToken()
+	if err != nil {
+		return CreatedToken{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.configured(req.Name) || a.find(req.Name) >= 0 {
+		return CreatedToken{}, fmt.Errorf("%w: %s", ErrTokenExists, req.Name)
+	}
+	token := managedToken{
+		Name:      req.Name,
+		Hash:      hashToken(secret),
+		Role:      req.Role,
+		Scope:     req.Scope,
+		Cluster:   req.Cluster,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	a.managed = append(a.managed, token)
+	if err := a.save(); err != nil {
+		a.managed = a.managed[:len(a.managed)-1]
+		return CreatedToken{}, err
+	}
+	return CreatedToken{TokenInfo: a.info(token, now), Token: secret}, nil
+}
+
+// Rotate replaces a managed token's secret, keeping its name and scope and
+// extending its expiry by its original lifetime
+func (a *Authenticator) Rotate(name string) (CreatedToken, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return CreatedToken{}, err
+	}
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	i := a.find(name)
+	if i < 0 {
+		if a.configured(name) {
+			return CreatedToken{}, fmt.Errorf("%w: %s", ErrTokenConfigured, name)
+		}
+		return CreatedToken{}, fmt.Errorf("%w: %s", ErrTokenNotFound, name)
+	}
+	previous := a.managed[i]
+	token := previous
+	token.Hash = hashToken(secret)
+	token.CreatedAt = now
+	if !previous.ExpiresAt.IsZero() {
+		token.ExpiresAt = now.Add(previous.ExpiresAt.Sub(previous.CreatedAt))
+	}
+	a.managed[i] = token
+	delete(a.reminded, name)
+	if err := a.save(); err != nil {
+		a.managed[i] = previous
+		return CreatedToken{}, err
+	}
+	return CreatedToken{TokenInfo: a.info(token, now), Token: secret}, nil
+}
+
+// Revoke deletes a managed token
+func (a *Authenticator) Revoke(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	i := a.find(name)
+	if i < 0 {
+		if a.configured(name) {
+			return fmt.Errorf("%w: %s", ErrTokenConfigured, name)
+		}
+		return fmt.Errorf("%w: %s", ErrTokenNotFound, name)
+	}
+	previous := a.managed
+	a.managed = append(a.managed[:i:i], a.managed[i+1:]...)
+	if err := a.save(); err != nil {
+		a.managed = previous
+		return err
+	}
+	delete(a.lastUsed, name)
+	delete(a.reminded, name)
+	return nil
+}
+
+// Tokens lists the configured and managed tokens by name
+func (a *Authenticator) Tokens() []TokenInfo {
+	now := a.now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tokens := make([]TokenInfo, 0, len(a.credentials)+len(a.managed))
+	for _, credential := range a.credentials {
+		tokens = append(tokens, a.rotation(TokenInfo{
+			Name:      credential.Name,
+			Role:      credential.Role,
+			Scope:     credential.Scope,
+			Cluster:   credential.Cluster,
+			Source:    TokenSourceConfig,
+			ExpiresAt: credential.ExpiresAt,
+			LastUsed:  a.lastUsed[credential.Name],
+		}, now))
+	}
+	for _, token := range a.managed {
+		tokens = append(tokens, a.info(token, now))
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Name < tokens[j].Name })
+	return tokens
+}
+
+// find returns the index of a managed token, or -1; callers hold a.mu
+func (a *Authenticator) find(name string) int {
+	for i, token := range a.managed {
+		if token.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// info describes a managed token; callers hold a.mu
+func (a *Authenticator) info(token managedToken, now time.Time) TokenInfo {
+	return a.rotation(TokenInfo{
+		Name:      token.Name,
+		Role:      token.Role,
+		Scope:     token.Scope,
+		Cluster:   token.Cluster,
+		Source:    TokenSourceAPI,
+		CreatedBy: token.CreatedBy,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		LastUsed:  a.lastUsed[token.Name],
+	}, now)
+}
+
+// rotation flags tokens that expired, expire within a week, or are older
+// than the rotation age
+func (a *Authenticator) rotation(info TokenInfo, now time.Time) TokenInfo {
+	switch {
+	case !info.ExpiresAt.IsZero() && !now.Before(info.ExpiresAt):
+		info.RotationReason = "expired"
+	case !info.ExpiresAt.IsZero() && info.ExpiresAt.Sub(now) < tokenExpiryWarning:
+		info.RotationReason = fmt.Sprintf("expires in %s", info.ExpiresAt.Sub(now).Round(time.Hour))
+	case !info.CreatedAt.IsZero() && now.Sub(info.CreatedAt) >= a.rotateAfter:
+		info.RotationReason = fmt.Sprintf("older than %s", a.rotateAfter)
+	}
+	info.RotationDue = info.RotationReason != ""
+	return info
+}
+
+// Reminders returns the tokens due for rotation that weren't reminded of in
+// the last day, marking them reminded
+func (a *Authenticator) Reminders() []TokenInfo {
+	now := a.now()
+	var due []TokenInfo
+	for _, token := range a.Tokens() {
+		if !token.RotationDue {
+			continue
+		}
+		a.mu.Lock()
+		if last, ok := a.reminded[token.Name]; !ok || now.Sub(last) >= tokenReminderInterval {
+			a.reminded[token.Name] = now
+			due = append(due, token)
+		}
+		a.mu.Unlock()
+	}
+	return due
+}
+
+// remindRotations logs the tokens due for rotation and saves their last use
+// every hour until the server shuts down
+func (s *Server) remindRotations(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		for _, token := range s.auth.Reminders() {
+			klog.Warningf("API token %s should be rotated: %s", token.Name, token.RotationReason)
+		}
+		select {
+		case <-ctx.Done():
+			s.auth.flush()
+			return
+		case <-ticker.C:
+			s.auth.flush()
+		}
+	}
+}
+
+// fullAdmin refuses scoped tokens, which must not mint or revoke others
+func (s *Server) fullAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if identity := s.requestIdentity(r); identity.Scoped() {
+		s.writeError(w, http.StatusForbidden, "Managing tokens requires an unscoped admin token")
+		return false
+	}
+	return true
+}
+
+// handleListTokens lists the API tokens, their last use and whether they
+// are due for rotation
+func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	if !s.fullAdmin(w, r) {
+		return
+	}
+	tokens := s.auth.Tokens()
+	due := 0
+	for _, token := range tokens {
+		if token.RotationDue {
+			due++
+		}
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"tokens":       tokens,
+		"total":        len(tokens),
+		"rotation_due": due,
+	})
+}
+
+// handleCreateToken issues a scoped token, answering with its secret once
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request) {
+	if !s.fullAdmin(w, r) {
+		return
+	}
+	var req TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	token, err := s.auth.Create(req, s.requestIdentity(r).Name)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrTokenExists) {
+			status = http.StatusConflict
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, token)
+}
+
+// handleRotateToken replaces a token's secret, answering with the new one
+func (s *Server) handleRotateToken(w http.ResponseWriter, r *http.Request) {
+	if !s.fullAdmin(w, r) {
+		return
+	}
+	token, err := s.auth.Rotate(mux.Vars(r)["name"])
+	if err != nil {
+		s.writeError(w, tokenErrorStatus(err), err.Error())
+		return
+	}
+	s.writeJSON(w, token)
+}
+
+// handleRevokeToken deletes a token created through the API
+func (s *Server) handleRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if !s.fullAdmin(w, r) {
+		return
+	}
+	if err := s.auth.Revoke(mux.Vars(r)["name"]); err != nil {
+		s.writeError(w, tokenErrorStatus(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tokenErrorStatus maps token management errors to HTTP statuses
+func tokenErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrTokenNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTokenConfigured):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAuthenticator_ManagedTokens(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	auth := authenticatorFor([]Credential{{Name: "oncall", Token: "admin-token-0123456789", Role: RoleAdmin}}, path, 30*24*time.Hour)
+	auth.now = func() time.Time { return now }
+
+	created, err := auth.Create(TokenRequest{Name: "ci", Role: RoleViewer, Cluster: "prod", ExpiresIn: "240h"}, "oncall")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := auth.Create(TokenRequest{Name: "oncall", Role: RoleViewer}, "oncall"); !errors.Is(err, ErrTokenExists) {
+		t.Errorf("expected a configured name refused, got %v", err)
+	}
+	identity, err := auth.Authenticate(created.Token)
+	if err != nil || identity.Name != "ci" || identity.Cluster != "prod" || identity.Role != RoleViewer {
+		t.Fatalf("expected the created token to authenticate, got %+v, %v", identity, err)
+	}
+
+	// Tokens survive a restart, keeping their last use but not their secret
+	auth.flush()
+	restarted := authenticatorFor(nil, path, 0)
+	restarted.now = auth.now
+	tokens := restarted.Tokens()
+	if len(tokens) != 1 || tokens[0].Source != TokenSourceAPI || !tokens[0].LastUsed.Equal(now) || tokens[0].CreatedBy != "oncall" {
+		t.Fatalf("expected the created token reloaded, got %+v", tokens)
+	}
+	if _, err := restarted.Authenticate(created.Token); err != nil {
+		t.Errorf("expected the reloaded token to authenticate, got %v", err)
+	}
+
+	// A week before expiry rotation is due, and reminded of once a day
+	now = now.Add(4 * 24 * time.Hour)
+	if due := auth.Reminders(); len(due) != 1 || due[0].Name != "ci" || due[0].RotationReason != "expires in 144h0m0s" {
+		t.Fatalf("expected a rotation reminder for ci, got %+v", due)
+	}
+	if due := auth.Reminders(); len(due) != 0 {
+		t.Errorf("expected no second reminder the same day, got %+v", due)
+	}
+
+	rotated, err := auth.Rotate("ci")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := auth.Authenticate(created.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the old secret invalid after rotation, got %v", err)
+	}
+	if !rotated.ExpiresAt.Equal(now.Add(240*time.Hour)) || rotated.RotationDue {
+		t.Errorf("expected the expiry extended by the token's lifetime, got %+v", rotated.TokenInfo)
+	}
+
+	if err := auth.Revoke("oncall"); !errors.Is(err, ErrTokenConfigured) {
+		t.Errorf("expected configured tokens not revocable, got %v", err)
+	}
+	if err := auth.Revoke("ci"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := auth.Authenticate(rotated.Token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the revoked token refused, got %v", err)
+	}
+	if authenticatorFor(nil, path, 0) != nil {
+		t.Error("expected authentication disabled once every token is revoked")
+	}
+}
+
+func TestAuthenticator_ConcurrentRotation(t *testing.T) {
+	auth := authenticatorFor([]Credential{{Name: "oncall", Token: "admin-token-0123456789", Role: RoleAdmin}}, filepath.Join(t.TempDir(), "tokens.json"), 0)
+	created, err := auth.Create(TokenRequest{Name: "ci", Role: RoleViewer}, "oncall")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var secret atomic.Value
+	secret.Store(created.Token)
+
+	// Requests authenticate with the latest secret while it is rotated
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if identity, err := auth.Authenticate(secret.Load().(string)); err == nil && (identity.Name != "ci" || identity.Role != RoleViewer) {
+					t.Errorf("expected the ci viewer, got %+v", identity)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 500; i++ {
+		rotated, err := auth.Rotate("ci")
+		if err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+		secret.Store(rotated.Token)
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestServer_ScopedTokens(t *testing.T) {
+	server := NewServer(Config{
+		Engine: core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()}),
+		Credentials: []Credential{
+			{Name: "dashboard", Token: "viewer-token-0123456789", Role: RoleViewer},
+			{Name: "oncall", Token: "admin-token-0123456789", Role: RoleAdmin},
+		},
+	})
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+	defer func() { _ = server.Shutdown(context.Background()) }()
+	request := func(method, path, token string, body interface{}) *http.Response {
+		t.Helper()
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, ts.URL+path, bytes.NewReader(data))
+		req.RequestURI = ""
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+	create := func(token string, req TokenRequest) CreatedToken {
+		t.Helper()
+		resp := request(http.MethodPost, "/api/v1/auth/tokens", token, req)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201 creating %s, got %d", req.Name, resp.StatusCode)
+		}
+		var created CreatedToken
+		if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode token: %v", err)
+		}
+		return created
+	}
+
+	alerts := create("admin-token-0123456789", TokenRequest{Name: "pager", Role: RoleAdmin, Scope: ScopeAlerts})
+	prod := create("admin-token-0123456789", TokenRequest{Name: "prod-dashboard", Role: RoleViewer, Cluster: "prod"})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"alerts token on alerts", http.MethodGet, "/api/v1/alerts/silences", alerts.Token, http.StatusOK},
+		{"alerts token elsewhere", http.MethodGet, "/api/v1/inventory", alerts.Token, http.StatusForbidden},
+		{"scoped token managing tokens", http.MethodGet, "/api/v1/auth/tokens", alerts.Token, http.StatusForbidden},
+		{"cluster token on another cluster", http.MethodGet, "/api/v1/handoff?cluster=staging", prod.Token, http.StatusForbidden},
+		{"cluster token probing other contexts", http.MethodGet, "/api/v1/health/multi?contexts=prod,staging", prod.Token, http.StatusForbidden},
+		{"cluster token switching context", http.MethodPost, "/api/v1/contexts/switch", prod.Token, http.StatusForbidden},
+		{"viewer token mutating", http.MethodPost, "/api/v1/annotations", prod.Token, http.StatusForbidden},
+		{"viewer token managing tokens", http.MethodGet, "/api/v1/auth/tokens", "viewer-token-0123456789", http.StatusForbidden},
+		{"unknown token", http.MethodGet, "/api/v1/inventory", "guess", http.StatusUnauthorized},
+		{"no token reading", http.MethodGet, "/api/v1/inventory", "", http.StatusOK},
+		{"no token silencing", http.MethodPost, "/api/v1/alerts/silences", "", http.StatusUnauthorized},
+		{"no token switching context", http.MethodPost, "/api/v1/contexts/switch", "", http.StatusUnauthorized},
+		{"no token setting maintenance", http.MethodPost, "/api/v1/checks/pod-health/maintenance", "", http.StatusUnauthorized},
+		{"no token ingesting metrics", http.MethodPost, "/api/v1/metrics/ingest", "", http.StatusUnauthorized},
+		{"no token planning investigations", http.MethodPost, "/api/v1/ai/investigations", "", http.StatusUnauthorized},
+		{"viewer token running investigations", http.MethodPost, "/api/v1/ai/investigations/investigation-1/run", "viewer-token-0123456789", http.StatusForbidden},
+		{"viewer token creating AI evaluations", http.MethodPost, "/api/v1/ai/evaluations", "viewer-token-0123456789", http.StatusForbidden},
+		{"revoking a configured token", http.MethodDelete, "/api/v1/auth/tokens/oncall", "admin-token-0123456789", http.StatusConflict},
+		{"revoking a created token", http.MethodDelete, "/api/v1/auth/tokens/pager", "admin-token-0123456789", http.StatusNoContent},
+		{"revoked token", http.MethodGet, "/api/v1/alerts/silences", alerts.Token, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := request(tt.method, tt.path, tt.token, nil); resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+
+	resp := request(http.MethodGet, "/api/v1/auth/tokens", "admin-token-0123456789", nil)
+	var list struct {
+		Tokens []TokenInfo `json:"tokens"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode tokens: %v", err)
+	}
+	if len(list.Tokens) != 3 || list.Tokens[0].Name != "dashboard" || list.Tokens[1].LastUsed.IsZero() {
+		t.Errorf("expected the configured tokens and prod-dashboard with last use, got %+v", list.Tokens)
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/klog/v2"
+)
+
+// API groups served under /api/<version>. v1 is stable; routes are only
+// removed from it after a deprecation period. v2beta holds their successors
+// while their shape may still change.
+const (
+	APIVersionV1     = "v1"
+	APIVersionV2Beta = "v2beta"
+)
+
+// Deprecation describes a route scheduled for removal. Responses from the
+// route carry Deprecation and, when set, Sunset and Link headers.
+type Deprecation struct {
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`                  // Route template, e.g. /api/v1/health/checks
+	Since       time.Time `json:"since"`                 // When the route was deprecated
+	Sunset      time.Time `json:"sunset,omitzero"`       // Earliest time the route may be removed
+	Replacement string    `json:"replacement,omitempty"` // Route to use instead
+	Note        string    `json:"note,omitempty"`
+
+	Requests uint64    `json:"requests"`           // Calls since the server started
+	LastUsed time.Time `json:"last_used,omitzero"` // Last call since the server started
+}
+
+// deprecatedRoutes lists the deprecated routes; add an entry here, with
+// its successor and a sunset at least two minor releases away, before
+// changing or removing a route.
+var deprecatedRoutes = []Deprecation{}
+
+// deprecatedRoute is a deprecation with its usage counters
+type deprecatedRoute struct {
+	Deprecation
+	requests atomic.Uint64
+	lastUsed atomic.Int64 // Unix nanoseconds
+}
+
+// deprecations holds the deprecated routes by method and route template
+type deprecations struct {
+	mu     sync.RWMutex
+	routes map[string]*deprecatedRoute
+}
+
+// deprecationKey identifies a route
+func deprecationKey(method, path string) string {
+	return method + " " + path
+}
+
+// deprecate marks a route as deprecated
+func (s *Server) deprecate(deprecation Deprecation) {
+	s.deprecations.mu.Lock()
+	defer s.deprecations.mu.Unlock()
+	if s.deprecations.routes == nil {
+		s.deprecations.routes = make(map[string]*deprecatedRoute)
+	}
+	s.deprecations.routes[deprecationKey(deprecation.Method, deprecation.Path)] = &deprecatedRoute{Deprecation: deprecation}
+}
+
+// deprecationMiddleware adds deprecation headers to responses from
+// deprecated routes and counts their use, so operators can see who still
+// calls them before they are removed
+func (s *Server) deprecationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := s.deprecatedRoute(r); route != nil {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", route.Since.Unix()))
+			if !route.Sunset.IsZero() {
+				w.Header().Set("Sunset", route.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if route.Replacement != "" {
+				w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", route.Replacement))
+			}
+			if route.requests.Add(1) == 1 {
+				klog.Warningf("Deprecated route %s %s called by %q; see /api/v1/deprecations", route.Method, route.Path, r.UserAgent())
+			}
+			route.lastUsed.Store(time.Now().UnixNano())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// deprecatedRoute returns the deprecation of the route a request matched
+func (s *Server) deprecatedRoute(r *http.Request) *deprecatedRoute {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return nil
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return nil
+	}
+
+	s.deprecations.mu.RLock()
+	defer s.deprecations.mu.RUnlock()
+	return s.deprecations.routes[deprecationKey(r.Method, path)]
+}
+
+// Deprecations lists the deprecated routes with their use since the server
+// started, soonest sunset first
+func (s *Server) Deprecations() []Deprecation {
+	s.deprecations.mu.RLock()
+	list := make([]Deprecation, 0, len(s.deprecations.routes))
+	for _, route := range s.deprecations.routes {
+		deprecation := route.Deprecation
+		deprecation.Requests = route.requests.Load()
+		if used := route.lastUsed.Load(); used > 0 {
+			deprecation.LastUsed = time.Unix(0, used)
+		}
+		list = append(list, deprecation)
+	}
+	s.deprecations.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if !a.Sunset.Equal(b.Sunset) {
+			return !a.Sunset.IsZero() && (b.Sunset.IsZero() || a.Sunset.Before(b.Sunset))
+		}
+		return deprecationKey(a.Method, a.Path) < deprecationKey(b.Method, b.Path)
+	})
+	return list
+}
+
+// deprecationMetrics reports calls to each deprecated route
+func (s *Server) deprecationMetrics() []core.Metric {
+	now := time.Now()
+	deprecations := s.Deprecations()
+	metrics := make([]core.Metric, 0, len(deprecations))
+	for _, deprecation := range deprecations {
+		metrics = append(metrics, core.Metric{
+			Name:      "kubepulse_api_deprecated_requests_total",
+			Value:     float64(deprecation.Requests),
+			Unit:      "requests",
+			Labels:    map[string]string{"method": deprecation.Method, "path": deprecation.Path},
+			Timestamp: now,
+			Type:      core.MetricTypeCounter,
+		})
+	}
+	return metrics
+}
+
+// handleListDeprecations lists the deprecated routes and their use
+func (s *Server) handleListDeprecations(w http.ResponseWriter, r *http.Request) {
+	deprecations := s.Deprecations()
+	s.writeJSON(w, map[string]interface{}{
+		"deprecations": deprecations,
+		"total":        len(deprecations),
+	})
+}
+
+// handleListChecksV2 returns the latest check results as a list sorted by
+// name, unlike the v1 map keyed by name
+func (s *Server) handleListChecksV2(w http.ResponseWriter, r *http.Request) {
+	results := s.engine.GetResults()
+	items := make([]core.CheckResult, 0, len(results))
+	for _, result := range results {
+		items = append(items, result)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	s.writeJSON(w, map[string]interface{}{
+		"items": items,
+		"total": len(items),
+	})
+}
+
+// handleGetCheckV2 returns one check result, with a JSON error when the
+// check is unknown
+func (s *Server) handleGetCheckV2(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	result, ok := s.engine.GetResult(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("%v: %s", core.ErrCheckNotFound, name))
+		return
+	}
+	s.writeJSON(w, result)
+}
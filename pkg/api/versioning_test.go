@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServer_DeprecatedRoutes(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	server.deprecate(Deprecation{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/health/checks/{name}",
+		Since:       since,
+		Sunset:      sunset,
+		Replacement: "/api/v2beta/checks/{name}",
+	})
+
+	for i := 0; i < 2; i++ {
+		rr := httptest.NewRecorder()
+		server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/checks/pod-health", nil))
+		if got := rr.Header().Get("Deprecation"); got != "@1767225600" {
+			t.Errorf("expected a Deprecation header, got %q", got)
+		}
+		if got := rr.Header().Get("Sunset"); got != "Wed, 01 Jul 2026 00:00:00 GMT" {
+			t.Errorf("expected a Sunset header, got %q", got)
+		}
+		if got := rr.Header().Get("Link"); got != `</api/v2beta/checks/{name}>; rel="successor-version"` {
+			t.Errorf("expected a successor Link header, got %q", got)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/health/checks", nil))
+	if got := rr.Header().Get("Deprecation"); got != "" {
+		t.Errorf("expected no Deprecation header on a current route, got %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/deprecations", nil))
+	var list struct {
+		Deprecations []Deprecation `json:"deprecations"`
+		Total        int           `json:"total"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode deprecations: %v", err)
+	}
+	if list.Total != 1 || list.Deprecations[0].Requests != 2 || list.Deprecations[0].LastUsed.IsZero() {
+		t.Errorf("expected the deprecated route with two calls, got %+v", list)
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil))
+	if !strings.Contains(rr.Body.String(), `kubepulse_api_deprecated_requests_total{`) {
+		t.Errorf("expected deprecated route calls in the metrics, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestServer_ChecksV2Beta(t *testing.T) {
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	for _, source := range []string{"payments", "checkout"} {
+		if _, err := engine.IngestMetrics(source, []core.Metric{{Name: "queue_depth", Value: 1}}); err != nil {
+			t.Fatalf("IngestMetrics() error = %v", err)
+		}
+	}
+	server := NewServer(Config{Engine: engine})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	rr := httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2beta/checks", nil))
+	var list struct {
+		Items []core.CheckResult `json:"items"`
+		Total int                `json:"total"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode checks: %v", err)
+	}
+	if list.Total != 2 || list.Items[0].Name != "app:checkout" || list.Items[1].Name != "app:payments" {
+		t.Errorf("expected checks sorted by name, got %+v", list)
+	}
+
+	rr = httptest.NewRecorder()
+	server.router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v2beta/checks/dns-health", nil))
+	if rr.Code != http.StatusNotFound || rr.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected a JSON 404, got %d %q: %s", rr.Code, rr.Header().Get("Content-Type"), rr.Body.String())
+	}
+}
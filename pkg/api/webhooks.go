@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kubepulse/kubepulse/pkg/webhooks"
+)
+
+// WebhookRequest registers a webhook notified of check transitions
+type WebhookRequest struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Filter string `json:"filter,omitempty"` // Label selector over check, from, to and namespace
+	Secret string `json:"secret,omitempty"` // Signs deliveries with HMAC-SHA256
+}
+
+// handleListWebhooks lists the check result webhooks and their deliveries
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Webhooks are not configured")
+		return
+	}
+	list := s.webhooks.List()
+	s.writeJSON(w, map[string]interface{}{
+		"webhooks": list,
+		"total":    len(list),
+	})
+}
+
+// handleRegisterWebhook registers a webhook until the server restarts
+func (s *Server) handleRegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Webhooks are not configured")
+		return
+	}
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	webhook := webhooks.Webhook{Name: req.Name, URL: req.URL, Filter: req.Filter, Secret: req.Secret, Source: webhooks.SourceAPI}
+	if err := s.webhooks.Register(webhook); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, webhooks.ErrWebhookExists) {
+			status = http.StatusConflict
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	webhook.Secret = ""
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	s.writeJSON(w, webhook)
+}
+
+// handleUnregisterWebhook removes a webhook registered through the API
+func (s *Server) handleUnregisterWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Webhooks are not configured")
+		return
+	}
+	if err := s.webhooks.Unregister(mux.Vars(r)["name"]); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, webhooks.ErrWebhookNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, webhooks.ErrConfigured):
+			status = http.StatusConflict
+		}
+		s.writeError(w, status, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/webhooks"
+)
+
+func TestServer_Webhooks(t *testing.T) {
+	dispatcher := webhooks.NewDispatcher("prod-eu")
+	if err := dispatcher.Register(webhooks.Webhook{Name: "scaler", URL: "https://automation.example.com", Source: webhooks.SourceConfig}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	server := NewServer(Config{
+		Webhooks: dispatcher,
+		Credentials: []Credential{
+			{Name: "dashboard", Token: "viewer-token", Role: RoleViewer},
+			{Name: "oncall", Token: "admin-token", Role: RoleAdmin},
+		},
+	})
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	register := `{"name": "tickets", "url": "https://bot.example.com/hook", "filter": "to=unhealthy,namespace=payments", "secret": "s3cret"}`
+	if w := do(http.MethodPost, "/api/v1/webhooks", "viewer-token", register); w.Code != http.StatusForbidden {
+		t.Errorf("expected viewers to be refused, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/api/v1/webhooks", "admin-token", register); w.Code != http.StatusCreated || strings.Contains(w.Body.String(), "s3cret") {
+		t.Fatalf("expected the webhook to be registered without echoing its secret, got %d: %s", w.Code, w.Body)
+	}
+	if w := do(http.MethodPost, "/api/v1/webhooks", "admin-token", register); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a duplicate, got %d", w.Code)
+	}
+	if w := do(http.MethodPost, "/api/v1/webhooks", "admin-token", `{"name": "bad", "url": "https://x.example.com", "filter": "severity=critical"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid filter, got %d", w.Code)
+	}
+
+	w := do(http.MethodGet, "/api/v1/webhooks", "viewer-token", "")
+	var list struct {
+		Webhooks []webhooks.Status `json:"webhooks"`
+		Total    int               `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Total != 2 || list.Webhooks[1].Name != "tickets" || list.Webhooks[1].Secret != "" {
+		t.Errorf("unexpected webhooks %+v", list)
+	}
+
+	if w := do(http.MethodDelete, "/api/v1/webhooks/scaler", "admin-token", ""); w.Code != http.StatusConflict {
+		t.Errorf("expected 409 for a configured webhook, got %d", w.Code)
+	}
+	if w := do(http.MethodDelete, "/api/v1/webhooks/tickets", "admin-token", ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+	if w := do(http.MethodDelete, "/api/v1/webhooks/tickets", "admin-token", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
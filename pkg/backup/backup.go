@@ -0,0 +1,195 @@
+// Package backup exports KubePulse's configuration and learned state to a
+// local directory or an S3-compatible bucket, and reads it back for restore.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// FormatVersion is the version of the snapshot format written by this build
+const FormatVersion = 1
+
+// Backup object names are namePrefix + UTC timestamp + nameSuffix, so sorting
+// names sorts backups by age
+const (
+	namePrefix = "kubepulse-backup-"
+	nameSuffix = ".json"
+	nameTime   = "20060102T150405.000Z"
+)
+
+// ErrBackupNotFound is returned when a location holds no backup, or not the
+// requested one
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Snapshot is one backup: the config file as written and the engine state
+type Snapshot struct {
+	Format     int        `json:"format"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Version    string     `json:"version"`               // KubePulse version that wrote the backup
+	ConfigPath string     `json:"config_path,omitempty"` // Where the config file was read from
+	Config     string     `json:"config,omitempty"`      // Config file contents; empty when running on defaults
+	State      core.State `json:"state"`
+}
+
+// Store reads and writes backup objects by name
+type Store interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error) // ErrBackupNotFound when missing
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+	String() string // Location, for messages
+}
+
+// S3Options configures an S3-compatible store
+type S3Options struct {
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Open returns the store for a location: s3://bucket/prefix for an
+// S3-compatible bucket, otherwise a local directory
+func Open(location string, s3 S3Options) (Store, error) {
+	if strings.HasPrefix(location, "s3://") {
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+		return NewS3Store(bucket, prefix, s3)
+	}
+	if location == "" {
+		return nil, fmt.Errorf("backup location is required")
+	}
+	return NewDirStore(ExpandHome(location)), nil
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// Name returns the object name for a backup taken at t
+func Name(t time.Time) string {
+	return namePrefix + t.UTC().Format(nameTime) + nameSuffix
+}
+
+// Write stores a snapshot and returns its name
+func Write(ctx context.Context, store Store, snapshot Snapshot) (string, error) {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode backup: %w", err)
+	}
+	name := Name(snapshot.CreatedAt)
+	if err := store.Put(ctx, name, data); err != nil {
+		return "", fmt.Errorf("failed to write backup %s to %s: %w", name, store, err)
+	}
+	return name, nil
+}
+
+// List returns the names of the backups in a store, newest first
+func List(ctx context.Context, store Store) ([]string, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups in %s: %w", store, err)
+	}
+	backups := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, namePrefix) && strings.HasSuffix(name, nameSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// Read loads a backup by name, or the newest backup when name is empty
+func Read(ctx context.Context, store Store, name string) (*Snapshot, error) {
+	if name == "" {
+		names, err := List(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no backups in %s: %w", store, ErrBackupNotFound)
+		}
+		name = names[0]
+	}
+
+	data, err := store.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s from %s: %w", name, store, err)
+	}
+	snapshot, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s: %w", name, err)
+	}
+	return snapshot, nil
+}
+
+// ReadFile loads a backup from a local file
+func ReadFile(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is chosen by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	snapshot, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// Decode parses a snapshot, rejecting formats newer than this build reads
+func Decode(data []byte) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	if snapshot.Format == 0 || snapshot.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("invalid backup: missing format or creation time")
+	}
+	if snapshot.Format > FormatVersion {
+		return nil, fmt.Errorf("backup format %d is newer than supported format %d", snapshot.Format, FormatVersion)
+	}
+	return &snapshot, nil
+}
+
+// Prune deletes all but the newest retain backups and returns their names.
+// A retain of 0 keeps every backup.
+func Prune(ctx context.Context, store Store, retain int) ([]string, error) {
+	if retain <= 0 {
+		return nil, nil
+	}
+	names, err := List(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) <= retain {
+		return nil, nil
+	}
+
+	var deleted []string
+	for _, name := range names[retain:] {
+		if err := store.Delete(ctx, name); err != nil {
+			return deleted, fmt.Errorf("failed to delete backup %s from %s: %w", name, store, err)
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, nil
+}
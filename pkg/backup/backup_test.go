@@ -0,0 +1,219 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+func testSnapshot(at time.Time) Snapshot {
+	return Snapshot{
+		Format:    FormatVersion,
+		CreatedAt: at,
+		Version:   "test",
+		Config:    "read_only: true\n",
+		State: core.State{
+			Version:    core.StateVersion,
+			AlertRules: []alerts.RuleSpec{{Name: "pods", Check: "pod-health", Severity: alerts.AlertSeverityWarning}},
+		},
+	}
+}
+
+func TestDirStore_WriteListRead(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "backups")
+	store := NewDirStore(dir)
+
+	// A missing directory has no backups yet
+	if _, err := Read(ctx, store, ""); !errors.Is(err, ErrBackupNotFound) {
+		t.Fatalf("expected ErrBackupNotFound, got %v", err)
+	}
+
+	start := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, err := Write(ctx, store, testSnapshot(start.Add(time.Duration(i)*time.Hour)))
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		names = append(names, name)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a backup"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, names[0]))
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected backup mode 0600, got %v", info.Mode().Perm())
+	}
+
+	listed, err := List(ctx, store)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 3 || listed[0] != names[2] || listed[2] != names[0] {
+		t.Errorf("expected backups newest first, got %v", listed)
+	}
+
+	latest, err := Read(ctx, store, "")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !latest.CreatedAt.Equal(start.Add(2 * time.Hour)) {
+		t.Errorf("expected the newest backup, got %v", latest.CreatedAt)
+	}
+	if latest.Config != "read_only: true\n" || len(latest.State.AlertRules) != 1 {
+		t.Errorf("unexpected snapshot %+v", latest)
+	}
+
+	oldest, err := Read(ctx, store, names[0])
+	if err != nil || !oldest.CreatedAt.Equal(start) {
+		t.Errorf("Read(%s) = %v, %v", names[0], oldest, err)
+	}
+	if _, err := Read(ctx, store, "kubepulse-backup-missing.json"); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("expected ErrBackupNotFound, got %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name        string
+		retain      int
+		wantDeleted int
+	}{
+		{"keep all", 0, 0},
+		{"under limit", 5, 0},
+		{"over limit", 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewDirStore(t.TempDir())
+			start := time.Now()
+			for i := 0; i < 4; i++ {
+				if _, err := Write(ctx, store, testSnapshot(start.Add(time.Duration(i)*time.Minute))); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			newest := Name(start.Add(3 * time.Minute))
+
+			deleted, err := Prune(ctx, store, tt.retain)
+			if err != nil {
+				t.Fatalf("Prune() error = %v", err)
+			}
+			if len(deleted) != tt.wantDeleted {
+				t.Errorf("expected %d deleted, got %v", tt.wantDeleted, deleted)
+			}
+			remaining, _ := List(ctx, store)
+			if len(remaining) != 4-tt.wantDeleted || remaining[0] != newest {
+				t.Errorf("unexpected remaining backups %v", remaining)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"valid", `{"format":1,"created_at":"2026-10-16T02:00:00Z","state":{"version":1}}`, ""},
+		{"not json", `backup`, "invalid backup"},
+		{"missing format", `{"created_at":"2026-10-16T02:00:00Z"}`, "missing format"},
+		{"newer format", `{"format":99,"created_at":"2026-10-16T02:00:00Z"}`, "newer than supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	store, err := Open("~/.kubepulse/backups", S3Options{})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if store.String() != filepath.Join(home, ".kubepulse", "backups") {
+		t.Errorf("expected home to be expanded, got %s", store)
+	}
+
+	store, err = Open("s3://ops/kubepulse", S3Options{AccessKeyID: "id", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if store.String() != "s3://ops/kubepulse/" {
+		t.Errorf("unexpected S3 location %s", store)
+	}
+
+	if _, err := Open("", S3Options{}); err == nil {
+		t.Error("expected an error for an empty location")
+	}
+}
+
+func TestScheduler_BackupNow(t *testing.T) {
+	ctx := context.Background()
+	store := NewDirStore(t.TempDir())
+	captures := 0
+	capture := func() (Snapshot, error) {
+		captures++
+		if captures == 2 {
+			return Snapshot{}, errors.New("config unreadable")
+		}
+		return testSnapshot(time.Now().Add(time.Duration(captures) * time.Second)), nil
+	}
+	scheduler := NewScheduler(store, time.Hour, 1, capture)
+
+	name, err := scheduler.BackupNow(ctx)
+	if err != nil {
+		t.Fatalf("BackupNow() error = %v", err)
+	}
+	if status := scheduler.Status(); status.Last != name || status.LastError != "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if _, err := scheduler.BackupNow(ctx); err == nil {
+		t.Fatal("expected capture error")
+	}
+	if status := scheduler.Status(); status.Last != name || !strings.Contains(status.LastError, "config unreadable") {
+		t.Errorf("expected the failure to be reported, got %+v", status)
+	}
+
+	// The third backup pushes the first out of retention
+	latest, err := scheduler.BackupNow(ctx)
+	if err != nil {
+		t.Fatalf("BackupNow() error = %v", err)
+	}
+	backups, err := scheduler.Backups(ctx)
+	if err != nil {
+		t.Fatalf("Backups() error = %v", err)
+	}
+	if len(backups) != 1 || backups[0] != latest {
+		t.Errorf("expected only %s to be retained, got %v", latest, backups)
+	}
+}
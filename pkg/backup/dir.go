@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// DirStore keeps backups as files in a local directory
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore returns a store for a directory, created on first write
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Put writes a backup atomically, readable only by the owner since it may
+// contain credentials from the config file
+func (d *DirStore) Put(ctx context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, ".tmp-"+name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(name))
+}
+
+// Get reads a backup
+func (d *DirStore) Get(ctx context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(d.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBackupNotFound
+	}
+	return data, err
+}
+
+// List returns the names of the files in the directory
+func (d *DirStore) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes a backup
+func (d *DirStore) Delete(ctx context.Context, name string) error {
+	return os.Remove(d.path(name))
+}
+
+// String returns the directory
+func (d *DirStore) String() string {
+	return d.dir
+}
+
+// path returns the file for a name, which must not leave the directory
+func (d *DirStore) path(name string) string {
+	return filepath.Join(d.dir, filepath.Base(name))
+}
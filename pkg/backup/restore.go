@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/version"
+)
+
+// appliedSuffix is appended to a state file once serve has applied it
+const appliedSuffix = ".applied"
+
+// NewSnapshot captures the config file at configPath, if any, and the state
+func NewSnapshot(configPath string, state core.State) (Snapshot, error) {
+	snapshot := Snapshot{
+		Format:    FormatVersion,
+		CreatedAt: time.Now(),
+		Version:   version.Version,
+		State:     state,
+	}
+	if configPath != "" {
+		data, err := os.ReadFile(configPath) // #nosec G304 - the operator's own config file
+		if err != nil {
+			return snapshot, fmt.Errorf("failed to read config file: %w", err)
+		}
+		snapshot.ConfigPath = configPath
+		snapshot.Config = string(data)
+	}
+	return snapshot, nil
+}
+
+// WriteFile writes data to path for the owner only, creating parent
+// directories. An existing file is only replaced when overwrite is set.
+func WriteFile(path string, data []byte, overwrite bool) error {
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists; pass --force to replace it", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteState saves restored state for serve to apply on its next start
+func WriteState(path string, state core.State, overwrite bool) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	return WriteFile(path, data, overwrite)
+}
+
+// ApplyState imports restored state from path into the engine and renames
+// the file so it is applied only once. It reports false when there is no
+// state file.
+func ApplyState(path string, engine *core.Engine) (bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path comes from configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read restored state: %w", err)
+	}
+
+	var state core.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return false, fmt.Errorf("invalid restored state in %s: %w", path, err)
+	}
+	if err := engine.ImportState(state); err != nil {
+		return false, fmt.Errorf("failed to apply restored state from %s: %w", path, err)
+	}
+	if err := os.Rename(path, path+appliedSuffix); err != nil {
+		return true, fmt.Errorf("applied restored state but failed to rename %s: %w", path, err)
+	}
+	return true, nil
+}
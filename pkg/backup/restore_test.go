@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubepulse.yaml")
+	if err := os.WriteFile(path, []byte("read_only: true\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	snapshot, err := NewSnapshot(path, core.State{Version: core.StateVersion})
+	if err != nil {
+		t.Fatalf("NewSnapshot() error = %v", err)
+	}
+	if snapshot.Format != FormatVersion || snapshot.ConfigPath != path || snapshot.Config != "read_only: true\n" {
+		t.Errorf("unexpected snapshot %+v", snapshot)
+	}
+
+	// Running on defaults leaves the config empty
+	snapshot, err = NewSnapshot("", core.State{})
+	if err != nil || snapshot.Config != "" {
+		t.Errorf("NewSnapshot(\"\") = %+v, %v", snapshot, err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "kubepulse.yaml")
+	if err := WriteFile(path, []byte("a"), false); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	err := WriteFile(path, []byte("b"), false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected refusal to overwrite, got %v", err)
+	}
+	if err := WriteFile(path, []byte("b"), true); err != nil {
+		t.Fatalf("WriteFile() with overwrite error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "b" {
+		t.Errorf("expected file to be replaced, got %q", data)
+	}
+}
+
+func TestApplyState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	engine := core.NewEngine(core.EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	applied, err := ApplyState(path, engine)
+	if err != nil || applied {
+		t.Fatalf("expected nothing to apply, got %v, %v", applied, err)
+	}
+
+	state := core.State{
+		Version:    core.StateVersion,
+		AlertRules: []alerts.RuleSpec{{Name: "restored", Check: "pod-health", Severity: alerts.AlertSeverityWarning}},
+		Silences:   []alerts.Silence{{Fingerprint: "pod-health:Warning", Until: time.Now().Add(time.Hour)}},
+	}
+	if err := WriteState(path, state, false); err != nil {
+		t.Fatalf("WriteState() error = %v", err)
+	}
+
+	applied, err = ApplyState(path, engine)
+	if err != nil || !applied {
+		t.Fatalf("expected state to be applied, got %v, %v", applied, err)
+	}
+	exported := engine.ExportState()
+	found := false
+	for _, rule := range exported.AlertRules {
+		found = found || rule.Name == "restored"
+	}
+	if !found || len(exported.Silences) != 1 {
+		t.Errorf("restored state not applied: %+v", exported)
+	}
+
+	// The file is renamed so the next start doesn't apply it again
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be moved aside, got %v", err)
+	}
+	if _, err := os.Stat(path + appliedSuffix); err != nil {
+		t.Errorf("expected applied state file, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	if _, err := ApplyState(path, engine); err == nil {
+		t.Error("expected an error for invalid state")
+	}
+}
@@ -0,0 +1,315 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxS3ErrorBody bounds how much of an error response is read
+const maxS3ErrorBody = 4 << 10
+
+// S3Store keeps backups as objects in an S3-compatible bucket, addressed
+// path-style so it works with AWS, MinIO, Ceph and similar stores. Requests
+// are signed with AWS Signature Version 4.
+type S3Store struct {
+	endpoint     *url.URL
+	bucket       string
+	prefix       string // Empty, or ends with "/"
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewS3Store returns a store for objects under prefix in bucket. Missing
+// credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN.
+func NewS3Store(bucket, prefix string, opts S3Options) (*S3Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("S3 backup location must name a bucket")
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3.amazonaws.com"
+	}
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", opts.Endpoint)
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.AccessKeyID == "" && opts.SecretAccessKey == "" {
+		opts.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		opts.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		opts.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials are required; set backup.s3 keys or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Store{
+		endpoint:     endpoint,
+		bucket:       bucket,
+		prefix:       prefix,
+		region:       opts.Region,
+		accessKey:    opts.AccessKeyID,
+		secretKey:    opts.SecretAccessKey,
+		sessionToken: opts.SessionToken,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+	}, nil
+}
+
+// Put uploads a backup
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.prefix+name, nil, data)
+	if err != nil {
+		return err
+	}
+	return closeBody(resp)
+}
+
+// Get downloads a backup
+func (s *S3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.prefix+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
+
+// List returns the names of the objects under the prefix
+func (s *S3Store) List(ctx context.Context) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid list response: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			name := strings.TrimPrefix(object.Key, s.prefix)
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Delete removes a backup
+func (s *S3Store) Delete(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.prefix+name, nil, nil)
+	if err != nil {
+		return err
+	}
+	return closeBody(resp)
+}
+
+// String returns the s3:// location
+func (s *S3Store) String() string {
+	return "s3://" + s.bucket + "/" + s.prefix
+}
+
+// do sends a signed request for a key in the bucket, or the bucket itself
+// when key is empty, and turns error responses into errors
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	target := *s.endpoint
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + s.bucket
+	if key != "" {
+		target.Path += "/" + key
+	}
+	target.RawPath = ""
+	target.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet && key != "" {
+		_ = resp.Body.Close()
+		return nil, ErrBackupNotFound
+	}
+	if resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, s3Error(resp)
+	}
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers covering the host, the payload
+// hash and every header already set on the request
+func (s *S3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	req.Header.Set("Authorization", signatureV4(req, payloadHash, now, s.region, s.accessKey, s.secretKey))
+}
+
+// signatureV4 returns the Authorization header value for a request
+func signatureV4(req *http.Request, payloadHash string, now time.Time, region, accessKey, secretKey string) string {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and
+// slashes too unless they are kept as path separators
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+// s3Error describes an S3 error response
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxS3ErrorBody))
+	var result struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &result) == nil && result.Code != "" {
+		return fmt.Errorf("S3 returned %d %s: %s", resp.StatusCode, result.Code, result.Message)
+	}
+	return fmt.Errorf("S3 returned %d", resp.StatusCode)
+}
+
+// closeBody drains and closes a response body
+func closeBody(resp *http.Response) error {
+	defer func() { _ = resp.Body.Close() }()
+	_, err := io.Copy(io.Discard, resp.Body)
+	return err
+}
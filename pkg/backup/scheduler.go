@@ -0,0 +1,110 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Status reports the outcome of the most recent scheduled backup
+type Status struct {
+	Location  string    `json:"location"`
+	Last      string    `json:"last,omitempty"` // Name of the last backup written
+	LastAt    time.Time `json:"last_at,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Scheduler takes a backup on every interval and prunes old ones
+type Scheduler struct {
+	store    Store
+	interval time.Duration
+	retain   int
+	capture  func() (Snapshot, error)
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewScheduler returns a scheduler writing snapshots from capture to store,
+// keeping the newest retain backups (all when retain is 0)
+func NewScheduler(store Store, interval time.Duration, retain int, capture func() (Snapshot, error)) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		interval: interval,
+		retain:   retain,
+		capture:  capture,
+		status:   Status{Location: store.String()},
+	}
+}
+
+// Run backs up on every interval until ctx is cancelled. The first backup
+// waits a full interval so a freshly started, still empty server doesn't
+// push older backups out of retention.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.BackupNow(ctx); err != nil {
+				klog.Errorf("Scheduled backup failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// BackupNow captures and writes a backup, then prunes old backups
+func (s *Scheduler) BackupNow(ctx context.Context) (string, error) {
+	name, err := s.backup(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.status.LastError = err.Error()
+		return "", err
+	}
+	s.status.Last = name
+	s.status.LastAt = time.Now()
+	s.status.LastError = ""
+	return name, nil
+}
+
+func (s *Scheduler) backup(ctx context.Context) (string, error) {
+	snapshot, err := s.capture()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture backup: %w", err)
+	}
+	name, err := Write(ctx, s.store, snapshot)
+	if err != nil {
+		return "", err
+	}
+	klog.Infof("Wrote backup %s to %s", name, s.store)
+
+	deleted, err := Prune(ctx, s.store, s.retain)
+	if err != nil {
+		// The new backup is safe; pruning is retried on the next run
+		klog.Warningf("Failed to prune old backups: %v", err)
+	}
+	for _, old := range deleted {
+		klog.V(2).Infof("Pruned backup %s", old)
+	}
+	return name, nil
+}
+
+// Backups lists the backups in the scheduler's store, newest first
+func (s *Scheduler) Backups(ctx context.Context) ([]string, error) {
+	return List(ctx, s.store)
+}
+
+// Status returns the outcome of the most recent backup
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
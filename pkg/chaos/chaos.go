@@ -0,0 +1,206 @@
+// Package chaos detects running chaos experiments of Chaos Mesh and
+// LitmusChaos, so the failures they cause are told apart from real ones and
+// recorded for resilience reports.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// Chaos tools the detector knows
+const (
+	ToolChaosMesh = "chaos-mesh"
+	ToolLitmus    = "litmus"
+)
+
+// DefaultInterval is how often running experiments are listed
+const DefaultInterval = 30 * time.Second
+
+// chaosMeshPause is the annotation Chaos Mesh pauses an experiment with
+const chaosMeshPause = "experiment.chaos-mesh.org/pause"
+
+// ChaosMeshResources are the Chaos Mesh experiment kinds listed
+var ChaosMeshResources = []schema.GroupVersionResource{
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "podchaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "networkchaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "stresschaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "iochaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "timechaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "dnschaos"},
+	{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "httpchaos"},
+}
+
+// LitmusResource is the LitmusChaos ChaosEngine resource
+var LitmusResource = schema.GroupVersionResource{Group: "litmuschaos.io", Version: "v1alpha1", Resource: "chaosengines"}
+
+// Detector lists the running chaos experiments of a cluster
+type Detector struct {
+	dynamic  dynamic.Interface
+	interval time.Duration
+	onChange func([]core.ChaosExperiment)
+}
+
+// NewDetector creates a detector passing the running experiments to
+// onChange every interval, DefaultInterval when zero
+func NewDetector(dynamicClient dynamic.Interface, interval time.Duration, onChange func([]core.ChaosExperiment)) *Detector {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Detector{dynamic: dynamicClient, interval: interval, onChange: onChange}
+}
+
+// Run lists the running experiments until ctx is done. A failed listing
+// keeps the previous experiments, so their failures stay attributed.
+func (d *Detector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		experiments, err := d.Active(ctx)
+		if err != nil {
+			klog.Warningf("Failed to list chaos experiments: %v", err)
+		} else {
+			d.onChange(experiments)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Active returns the running experiments of every installed chaos tool.
+// Tools that aren't installed, or whose experiments KubePulse may not list,
+// are skipped.
+func (d *Detector) Active(ctx context.Context) ([]core.ChaosExperiment, error) {
+	var experiments []core.ChaosExperiment
+	for _, resource := range ChaosMeshResources {
+		items, err := d.list(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if experiment, ok := chaosMeshExperiment(&items[i]); ok {
+				experiments = append(experiments, experiment)
+			}
+		}
+	}
+	items, err := d.list(ctx, LitmusResource)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if experiment, ok := litmusExperiment(&items[i]); ok {
+			experiments = append(experiments, experiment)
+		}
+	}
+
+	sort.Slice(experiments, func(i, j int) bool { return experiments[i].ID() < experiments[j].ID() })
+	return experiments, nil
+}
+
+// list returns the objects of a resource in every namespace, or none when
+// the resource isn't served or may not be listed
+func (d *Detector) list(ctx context.Context, resource schema.GroupVersionResource) ([]unstructured.Unstructured, error) {
+	list, err := d.dynamic.Resource(resource).List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case apierrors.IsForbidden(err):
+		klog.V(2).Infof("Not allowed to list %s.%s; its chaos experiments aren't detected", resource.Resource, resource.Group)
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to list %s.%s: %w", resource.Resource, resource.Group, err)
+	}
+	return list.Items, nil
+}
+
+// chaosMeshExperiment returns a Chaos Mesh experiment that is running:
+// its desired phase is Run and it isn't paused
+func chaosMeshExperiment(object *unstructured.Unstructured) (core.ChaosExperiment, bool) {
+	phase, _, _ := unstructured.NestedString(object.Object, "status", "experiment", "desiredPhase")
+	if phase != "Run" || object.GetAnnotations()[chaosMeshPause] == "true" {
+		return core.ChaosExperiment{}, false
+	}
+
+	experiment := core.ChaosExperiment{
+		Tool:      ToolChaosMesh,
+		Kind:      object.GetKind(),
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+		Started:   object.GetCreationTimestamp().Time,
+	}
+	experiment.Action, _, _ = unstructured.NestedString(object.Object, "spec", "action")
+	experiment.Namespaces, _, _ = unstructured.NestedStringSlice(object.Object, "spec", "selector", "namespaces")
+	if labels, _, _ := unstructured.NestedStringMap(object.Object, "spec", "selector", "labelSelectors"); len(labels) > 0 {
+		experiment.Selector = selectorString(labels)
+	}
+	if value, _, _ := unstructured.NestedString(object.Object, "spec", "duration"); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			experiment.Until = experiment.Started.Add(duration)
+		}
+	}
+	if len(experiment.Namespaces) == 0 {
+		experiment.Namespaces = []string{experiment.Namespace}
+	}
+	return experiment, true
+}
+
+// litmusExperiment returns a LitmusChaos engine that is running: active and
+// not yet completed or stopped
+func litmusExperiment(object *unstructured.Unstructured) (core.ChaosExperiment, bool) {
+	state, _, _ := unstructured.NestedString(object.Object, "spec", "engineState")
+	status, _, _ := unstructured.NestedString(object.Object, "status", "engineStatus")
+	if state != "active" || status == "completed" || status == "stopped" {
+		return core.ChaosExperiment{}, false
+	}
+
+	experiment := core.ChaosExperiment{
+		Tool:      ToolLitmus,
+		Kind:      "ChaosEngine",
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+		Started:   object.GetCreationTimestamp().Time,
+	}
+	if namespace, _, _ := unstructured.NestedString(object.Object, "spec", "appinfo", "appns"); namespace != "" {
+		experiment.Namespaces = []string{namespace}
+	} else {
+		experiment.Namespaces = []string{experiment.Namespace}
+	}
+	experiment.Selector, _, _ = unstructured.NestedString(object.Object, "spec", "appinfo", "applabel")
+
+	experiments, _, _ := unstructured.NestedSlice(object.Object, "spec", "experiments")
+	names := make([]string, 0, len(experiments))
+	for _, item := range experiments {
+		if spec, ok := item.(map[string]interface{}); ok {
+			if name, ok := spec["name"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	experiment.Action = strings.Join(names, ", ")
+	return experiment, true
+}
+
+// selectorString formats labels as a label selector
+func selectorString(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
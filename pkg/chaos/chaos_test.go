@@ -0,0 +1,114 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var created = time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+func chaosObject(apiVersion, kind, namespace, name string, annotations map[string]interface{}, spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         namespace,
+			"annotations":       annotations,
+			"creationTimestamp": created.Format(time.RFC3339),
+		},
+		"spec":   spec,
+		"status": status,
+	}}
+}
+
+// newChaosClient serves the objects under their resource, since the fake
+// client would guess podchaoses from PodChaos
+func newChaosClient(t *testing.T, objects map[schema.GroupVersionResource][]*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{LitmusResource: "ChaosEngineList"}
+	for _, resource := range ChaosMeshResources {
+		listKinds[resource] = "ChaosList"
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for resource, items := range objects {
+		for _, item := range items {
+			if err := client.Tracker().Create(resource, item, item.GetNamespace()); err != nil {
+				t.Fatalf("failed to add %s: %v", item.GetName(), err)
+			}
+		}
+	}
+	return client
+}
+
+func TestDetector_Active(t *testing.T) {
+	running := map[string]interface{}{"experiment": map[string]interface{}{"desiredPhase": "Run"}}
+	client := newChaosClient(t, map[schema.GroupVersionResource][]*unstructured.Unstructured{ChaosMeshResources[0]: {
+		chaosObject("chaos-mesh.org/v1alpha1", "PodChaos", "chaos", "kill-web", nil, map[string]interface{}{
+			"action":   "pod-kill",
+			"duration": "5m",
+			"selector": map[string]interface{}{
+				"namespaces":     []interface{}{"shop"},
+				"labelSelectors": map[string]interface{}{"app": "web"},
+			},
+		}, running),
+	}, ChaosMeshResources[1]: {
+		chaosObject("chaos-mesh.org/v1alpha1", "NetworkChaos", "shop", "paused", map[string]interface{}{chaosMeshPause: "true"},
+			map[string]interface{}{"action": "delay"}, running),
+	}, ChaosMeshResources[2]: {
+		chaosObject("chaos-mesh.org/v1alpha1", "StressChaos", "shop", "finished", nil,
+			map[string]interface{}{}, map[string]interface{}{"experiment": map[string]interface{}{"desiredPhase": "Stop"}}),
+	}, LitmusResource: {
+		chaosObject("litmuschaos.io/v1alpha1", "ChaosEngine", "litmus", "cart-delete", nil, map[string]interface{}{
+			"engineState": "active",
+			"appinfo":     map[string]interface{}{"appns": "cart", "applabel": "app=cart"},
+			"experiments": []interface{}{map[string]interface{}{"name": "pod-delete"}},
+		}, map[string]interface{}{"engineStatus": "initialized"}),
+		chaosObject("litmuschaos.io/v1alpha1", "ChaosEngine", "litmus", "done", nil,
+			map[string]interface{}{"engineState": "active"}, map[string]interface{}{"engineStatus": "completed"}),
+	}})
+
+	experiments, err := NewDetector(client, 0, nil).Active(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(experiments) != 2 {
+		t.Fatalf("expected the 2 running experiments, got %+v", experiments)
+	}
+
+	mesh := experiments[0]
+	if mesh.ID() != "chaos-mesh/chaos/kill-web" || mesh.Action != "pod-kill" || mesh.Selector != "app=web" {
+		t.Errorf("unexpected Chaos Mesh experiment %+v", mesh)
+	}
+	if len(mesh.Namespaces) != 1 || mesh.Namespaces[0] != "shop" || !mesh.Until.Equal(created.Add(5*time.Minute)) {
+		t.Errorf("expected shop targeted for 5m, got %+v", mesh)
+	}
+
+	litmus := experiments[1]
+	if litmus.ID() != "litmus/litmus/cart-delete" || litmus.Action != "pod-delete" || litmus.Namespaces[0] != "cart" || !litmus.Until.IsZero() {
+		t.Errorf("unexpected Litmus experiment %+v", litmus)
+	}
+}
+
+func TestDetector_NoChaosTools(t *testing.T) {
+	client := newChaosClient(t, nil)
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		resource := action.GetResource()
+		if resource.Group == "chaos-mesh.org" {
+			return true, nil, apierrors.NewNotFound(resource.GroupResource(), "")
+		}
+		return true, nil, apierrors.NewForbidden(resource.GroupResource(), "", nil)
+	})
+
+	experiments, err := NewDetector(client, 0, nil).Active(context.Background())
+	if err != nil || len(experiments) != 0 {
+		t.Errorf("expected missing and forbidden tools skipped, got %+v, %v", experiments, err)
+	}
+}
@@ -0,0 +1,1313 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/backup"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	"github.com/kubepulse/kubepulse/pkg/k8s"
+	"github.com/kubepulse/kubepulse/pkg/preflight"
+	"github.com/kubepulse/kubepulse/pkg/telemetry"
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"github.com/kubepulse/kubepulse/pkg/webhooks"
+)
+
+// ErrNotAnalyzed is returned when the server has no AI analysis for a check yet
+var ErrNotAnalyzed = errors.New("AI analysis not available for this health check")
+
+// analysisPollInterval is how often a run the server accepted but hadn't
+// finished is polled
+var analysisPollInterval = 2 * time.Second
+
+// acceptedRunError is returned by attempt for a 202 Accepted analysis run
+type acceptedRunError struct {
+	run core.AnalysisRun
+}
+
+// Error implements the error interface
+func (e *acceptedRunError) Error() string {
+	return fmt.Sprintf("analysis run %s is %s", e.run.ID, e.run.State)
+}
+
+// Client is a typed client for the KubePulse HTTP API
+type Client struct {
+	baseURL      *url.URL
+	token        string
+	userAgent    string
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+	onDeprecated func(DeprecationNotice)
+}
+
+// Config holds configuration for the API client
+type Config struct {
+	BaseURL      string        // e.g. http://localhost:8080
+	Token        string        // Sent as a bearer token when set
+	UserAgent    string        // Defaults to kubepulse-go-client
+	HTTPClient   *http.Client  // Defaults to a client with Timeout
+	Timeout      time.Duration // Per-request timeout for the default HTTP client
+	MaxRetries   int           // Retries for idempotent requests; negative disables
+	RetryBackoff time.Duration // Initial backoff, doubled after each retry
+
+	// OnDeprecated, when set, is called for every response from a route the
+	// server has deprecated, so programs can warn before the route is removed
+	OnDeprecated func(DeprecationNotice)
+}
+
+// DeprecationNotice describes a call to a deprecated route
+type DeprecationNotice struct {
+	Method    string
+	Path      string
+	Sunset    time.Time // Earliest removal; zero when not announced
+	Successor string    // Route to use instead, when the server names one
+}
+
+// Deprecation is a route the server has deprecated, with its use since the
+// server started
+type Deprecation struct {
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Since       time.Time `json:"since"`
+	Sunset      time.Time `json:"sunset,omitzero"`
+	Replacement string    `json:"replacement,omitempty"`
+	Note        string    `json:"note,omitempty"`
+	Requests    uint64    `json:"requests"`
+	LastUsed    time.Time `json:"last_used,omitzero"`
+}
+
+// APIError represents a non-2xx response from the server
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("kubepulse API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// ServerStatus is the response of the basic health endpoint
+type ServerStatus struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
+
+	// Update is set when the server checks for new releases
+	Update *version.UpdateStatus `json:"update,omitempty"`
+}
+
+// IngestResult reports how the engine scored a batch of pushed metrics
+type IngestResult struct {
+	Check    string            `json:"check"`
+	Status   core.HealthStatus `json:"status"`
+	Message  string            `json:"message"`
+	Accepted int               `json:"accepted"`
+}
+
+// MetricHistory holds recorded data points for a metric, keyed by series
+type MetricHistory struct {
+	Metric string                   `json:"metric"`
+	Series map[string][]core.Metric `json:"series"`
+}
+
+// CardinalityReport holds the series and label values kept per metric
+type CardinalityReport struct {
+	Metrics  []core.MetricCardinality `json:"metrics"`
+	Total    int                      `json:"total"`
+	Warnings []string                 `json:"warnings"`
+}
+
+// BackupList reports the last scheduled backup and the backups kept
+type BackupList struct {
+	Status  backup.Status `json:"status"`
+	Backups []string      `json:"backups"` // Newest first
+	Total   int           `json:"total"`
+}
+
+// TelemetryPreview reports the usage reporting settings and the next report
+type TelemetryPreview struct {
+	Status  telemetry.Status `json:"status"`
+	Preview telemetry.Report `json:"preview"`
+}
+
+// ChangeFeed lists what changed in a time window, oldest first
+type ChangeFeed struct {
+	Since   time.Time               `json:"since"`
+	Until   time.Time               `json:"until"`
+	Total   int                     `json:"total"`
+	Counts  map[core.ChangeKind]int `json:"counts"`
+	Changes []core.Change           `json:"changes"`
+}
+
+// Predictions is the response of the predictive insights endpoint
+type Predictions struct {
+	Predictions []ai.PredictiveInsight `json:"predictions"`
+	GeneratedAt time.Time              `json:"generated_at"`
+}
+
+// NewClient creates a new API client
+func NewClient(config Config) (*Client, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+
+	baseURL, err := url.Parse(strings.TrimRight(config.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme != "http" && baseURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme: %s", baseURL.Scheme)
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = "kubepulse-go-client"
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = 500 * time.Millisecond
+	}
+
+	return &Client{
+		baseURL:      baseURL,
+		token:        config.Token,
+		userAgent:    config.UserAgent,
+		httpClient:   config.HTTPClient,
+		maxRetries:   config.MaxRetries,
+		retryBackoff: config.RetryBackoff,
+		onDeprecated: config.OnDeprecated,
+	}, nil
+}
+
+// Health returns the basic server health status
+func (c *Client) Health(ctx context.Context) (*ServerStatus, error) {
+	var status ServerStatus
+	if err := c.get(ctx, "/api/v1/health", nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Version returns build information for the server
+func (c *Client) Version(ctx context.Context) (*version.Info, error) {
+	var info version.Info
+	if err := c.get(ctx, "/api/v1/version", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Deprecations lists the routes the server has deprecated, soonest sunset first
+func (c *Client) Deprecations(ctx context.Context) ([]Deprecation, error) {
+	var response struct {
+		Deprecations []Deprecation `json:"deprecations"`
+	}
+	if err := c.get(ctx, "/api/v1/deprecations", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Deprecations, nil
+}
+
+// Preflight runs the server's environment pre-flight checks
+func (c *Client) Preflight(ctx context.Context) (*preflight.Report, error) {
+	var report preflight.Report
+	if err := c.get(ctx, "/api/v1/system/preflight", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Backups lists the server's configuration and state backups
+func (c *Client) Backups(ctx context.Context) (*BackupList, error) {
+	var list BackupList
+	if err := c.get(ctx, "/api/v1/system/backups", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// CreateBackup takes a backup now and returns its name
+func (c *Client) CreateBackup(ctx context.Context) (string, error) {
+	var response struct {
+		Backup string `json:"backup"`
+	}
+	if err := c.post(ctx, "/api/v1/system/backups", nil, &response); err != nil {
+		return "", err
+	}
+	return response.Backup, nil
+}
+
+// Telemetry returns whether usage reporting is on and the report a running
+// server would send next
+func (c *Client) Telemetry(ctx context.Context) (*TelemetryPreview, error) {
+	var preview TelemetryPreview
+	if err := c.get(ctx, "/api/v1/system/telemetry", nil, &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// ClusterHealth returns the overall cluster health; cluster may be empty
+func (c *Client) ClusterHealth(ctx context.Context, cluster string) (*core.ClusterHealth, error) {
+	query := url.Values{}
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+
+	var health core.ClusterHealth
+	if err := c.get(ctx, "/api/v1/health/cluster", query, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// MultiHealth probes several kubeconfig contexts in parallel and returns a
+// summary of each; a zero timeout uses the server's default
+func (c *Client) MultiHealth(ctx context.Context, contexts []string, timeout time.Duration) (*fleet.Report, error) {
+	query := url.Values{}
+	query.Set("contexts", strings.Join(contexts, ","))
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+
+	var report fleet.Report
+	if err := c.get(ctx, "/api/v1/health/multi", query, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ReportAgent pushes an agent's check results to the server
+func (c *Client) ReportAgent(ctx context.Context, report fleet.AgentReport) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	_, err = c.do(ctx, http.MethodPost, "/api/v1/agents/report", nil, payload)
+	return err
+}
+
+// Agents returns the health of every agent reporting to the server
+func (c *Client) Agents(ctx context.Context) ([]fleet.Summary, error) {
+	var response struct {
+		Agents []fleet.Summary `json:"agents"`
+	}
+	if err := c.get(ctx, "/api/v1/agents", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Agents, nil
+}
+
+// HealthAt returns the cluster health as it was at the given time; an empty cluster uses the server's current context
+func (c *Client) HealthAt(ctx context.Context, cluster string, at time.Time) (*core.ClusterHealth, error) {
+	query := url.Values{}
+	query.Set("timestamp", at.Format(time.RFC3339))
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+
+	var health core.ClusterHealth
+	if err := c.get(ctx, "/api/v1/health/at", query, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// DashboardSummary returns the precomputed dashboard overview; an empty cluster uses the server's current context
+func (c *Client) DashboardSummary(ctx context.Context, cluster string) (*core.DashboardSummary, error) {
+	query := url.Values{}
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+
+	var summary core.DashboardSummary
+	if err := c.get(ctx, "/api/v1/dashboard/summary", query, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Checks returns the latest result of every health check keyed by name
+func (c *Client) Checks(ctx context.Context) (map[string]core.CheckResult, error) {
+	var results map[string]core.CheckResult
+	if err := c.get(ctx, "/api/v1/health/checks", nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Check returns the latest result of a single health check
+func (c *Client) Check(ctx context.Context, name string) (*core.CheckResult, error) {
+	var result core.CheckResult
+	if err := c.get(ctx, "/api/v1/health/checks/"+url.PathEscape(name), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CheckMaintenance lists the checks under maintenance, soonest to end first
+func (c *Client) CheckMaintenance(ctx context.Context) ([]core.CheckMaintenance, error) {
+	var response struct {
+		Maintenance []core.CheckMaintenance `json:"maintenance"`
+	}
+	if err := c.get(ctx, "/api/v1/checks/maintenance", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Maintenance, nil
+}
+
+// SetCheckMaintenance puts a check under maintenance for a duration, during
+// which its failures don't affect the health score or fire alerts
+func (c *Client) SetCheckMaintenance(ctx context.Context, name, reason, owner string, duration time.Duration) (*core.CheckMaintenance, error) {
+	var window core.CheckMaintenance
+	path := fmt.Sprintf("/api/v1/checks/%s/maintenance", url.PathEscape(name))
+	request := map[string]string{"reason": reason, "owner": owner, "duration": duration.String()}
+	if err := c.post(ctx, path, request, &window); err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// EndCheckMaintenance ends a check's maintenance before it expires
+func (c *Client) EndCheckMaintenance(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/api/v1/checks/%s/maintenance", url.PathEscape(name)), nil, nil)
+	return err
+}
+
+// Annotations lists a cluster's annotations for a check overlapping from
+// and to; empty or zero arguments don't filter
+func (c *Client) Annotations(ctx context.Context, cluster, check string, from, to time.Time) ([]core.Annotation, error) {
+	query := url.Values{}
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+	if check != "" {
+		query.Set("check", check)
+	}
+	if !from.IsZero() {
+		query.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		query.Set("to", to.Format(time.RFC3339))
+	}
+	var response struct {
+		Annotations []core.Annotation `json:"annotations"`
+	}
+	if err := c.get(ctx, "/api/v1/annotations", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Annotations, nil
+}
+
+// Annotate attaches a note to a time range; the server defaults the
+// cluster to the one it monitors and the start to now
+func (c *Client) Annotate(ctx context.Context, annotation core.Annotation) (*core.Annotation, error) {
+	var created core.Annotation
+	if err := c.post(ctx, "/api/v1/annotations", annotation, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteAnnotation removes an annotation
+func (c *Client) DeleteAnnotation(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/annotations/"+url.PathEscape(id), nil, nil)
+	return err
+}
+
+// Alerts returns active alerts
+func (c *Client) Alerts(ctx context.Context) ([]core.Alert, error) {
+	var alerts []core.Alert
+	if err := c.get(ctx, "/api/v1/alerts", nil, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// Metrics returns metrics in Prometheus text exposition format
+func (c *Client) Metrics(ctx context.Context) (string, error) {
+	body, err := c.do(ctx, http.MethodGet, "/api/v1/metrics", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// MetricHistory returns recorded data points for a metric
+func (c *Client) MetricHistory(ctx context.Context, name string) (*MetricHistory, error) {
+	var history MetricHistory
+	if err := c.get(ctx, "/api/v1/metrics/history/"+url.PathEscape(name), nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// MetricCardinality returns the series and label values kept per metric and
+// the metrics that hit their cardinality limits
+func (c *Client) MetricCardinality(ctx context.Context) (*CardinalityReport, error) {
+	var report CardinalityReport
+	if err := c.get(ctx, "/api/v1/metrics/cardinality", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// IngestMetrics pushes application metrics reported by source
+func (c *Client) IngestMetrics(ctx context.Context, source string, metrics []core.Metric) (*IngestResult, error) {
+	body := map[string]interface{}{
+		"source":  source,
+		"metrics": metrics,
+	}
+	var result IngestResult
+	if err := c.post(ctx, "/api/v1/metrics/ingest", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Changes returns what changed since a duration (e.g. "30m") or RFC3339 timestamp,
+// optionally limited to the given change kinds
+func (c *Client) Changes(ctx context.Context, since string, kinds ...core.ChangeKind) (*ChangeFeed, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if len(kinds) > 0 {
+		names := make([]string, len(kinds))
+		for i, kind := range kinds {
+			names[i] = string(kind)
+		}
+		query.Set("kind", strings.Join(names, ","))
+	}
+
+	var feed ChangeFeed
+	if err := c.get(ctx, "/api/v1/changes", query, &feed); err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// NamespaceHealth returns the health of each namespace, worst first
+func (c *Client) NamespaceHealth(ctx context.Context) (*core.NamespaceHealthReport, error) {
+	var report core.NamespaceHealthReport
+	if err := c.get(ctx, "/api/v1/health/namespaces", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Inventory returns the server's cached inventory of the cluster's nodes,
+// workloads, custom resources and component versions
+func (c *Client) Inventory(ctx context.Context) (*inventory.Inventory, error) {
+	var inv inventory.Inventory
+	if err := c.get(ctx, "/api/v1/inventory", nil, &inv); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// Handoff summarizes the on-call shift since a duration (e.g. "8h") or
+// RFC3339 timestamp; empty values use the last 8 hours and the server's
+// current context
+func (c *Client) Handoff(ctx context.Context, cluster, since string) (*core.Handoff, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+
+	var handoff core.Handoff
+	if err := c.get(ctx, "/api/v1/handoff", query, &handoff); err != nil {
+		return nil, err
+	}
+	return &handoff, nil
+}
+
+// Findings lists tracked findings matching filter, open ones first
+func (c *Client) Findings(ctx context.Context, filter core.FindingFilter) ([]core.FindingRecord, error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	if filter.Check != "" {
+		query.Set("check", filter.Check)
+	}
+	if filter.ID != "" {
+		query.Set("id", filter.ID)
+	}
+
+	var response struct {
+		Findings []core.FindingRecord `json:"findings"`
+	}
+	if err := c.get(ctx, "/api/v1/findings", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Findings, nil
+}
+
+// FindingTypes returns the finding taxonomy the server classifies
+// problems with
+func (c *Client) FindingTypes(ctx context.Context) ([]findings.Type, error) {
+	var response struct {
+		Types []findings.Type `json:"types"`
+	}
+	if err := c.get(ctx, "/api/v1/findings/types", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Types, nil
+}
+
+// Recordings lists recorded check runs, newest first, optionally for one check
+func (c *Client) Recordings(ctx context.Context, check string) ([]core.RecordingSummary, error) {
+	query := url.Values{}
+	if check != "" {
+		query.Set("check", check)
+	}
+
+	var response struct {
+		Recordings []core.RecordingSummary `json:"recordings"`
+	}
+	if err := c.get(ctx, "/api/v1/recordings", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Recordings, nil
+}
+
+// Recording returns a recorded check run with its API responses
+func (c *Client) Recording(ctx context.Context, id string) (*core.Recording, error) {
+	var recording core.Recording
+	if err := c.get(ctx, "/api/v1/recordings/"+url.PathEscape(id), nil, &recording); err != nil {
+		return nil, err
+	}
+	return &recording, nil
+}
+
+// ReplayRecording re-runs a recorded check on the server against its
+// recorded API responses
+func (c *Client) ReplayRecording(ctx context.Context, id string) (*core.ReplayReport, error) {
+	var report core.ReplayReport
+	path := fmt.Sprintf("/api/v1/recordings/%s/replay", url.PathEscape(id))
+	if err := c.post(ctx, path, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// AlertRules returns the configured alert rules and their fire counts
+func (c *Client) AlertRules(ctx context.Context) ([]alerts.RuleInfo, error) {
+	var response struct {
+		Rules []alerts.RuleInfo `json:"rules"`
+	}
+	if err := c.get(ctx, "/api/v1/alerts/rules", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Rules, nil
+}
+
+// UpsertAlertRule adds an alert rule or replaces the rule with the same name
+func (c *Client) UpsertAlertRule(ctx context.Context, spec alerts.RuleSpec) error {
+	var saved alerts.RuleSpec
+	return c.post(ctx, "/api/v1/alerts/rules", spec, &saved)
+}
+
+// DeleteAlertRule removes an alert rule
+func (c *Client) DeleteAlertRule(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/alerts/rules/"+url.PathEscape(name), nil, nil)
+	return err
+}
+
+// RuleSuggestions returns suggested alert rule changes for the given history
+// window (e.g. "24h"); an empty window uses the server default
+func (c *Client) RuleSuggestions(ctx context.Context, window string) ([]alerts.RuleSuggestion, error) {
+	query := url.Values{}
+	if window != "" {
+		query.Set("window", window)
+	}
+
+	var response struct {
+		Suggestions []alerts.RuleSuggestion `json:"suggestions"`
+	}
+	if err := c.get(ctx, "/api/v1/alerts/rule-suggestions", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Suggestions, nil
+}
+
+// ApplyRuleSuggestion applies a suggestion from the latest RuleSuggestions call
+func (c *Client) ApplyRuleSuggestion(ctx context.Context, id string) (*alerts.RuleSuggestion, error) {
+	var response struct {
+		Applied alerts.RuleSuggestion `json:"applied"`
+	}
+	path := fmt.Sprintf("/api/v1/alerts/rule-suggestions/%s/apply", url.PathEscape(id))
+	if err := c.post(ctx, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Applied, nil
+}
+
+// Escalations returns unacknowledged alerts moving through escalation policies
+func (c *Client) Escalations(ctx context.Context) ([]alerts.EscalationState, error) {
+	var response struct {
+		Escalations []alerts.EscalationState `json:"escalations"`
+	}
+	if err := c.get(ctx, "/api/v1/alerts/escalations", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Escalations, nil
+}
+
+// AcknowledgeAlert acknowledges an alert, stopping its escalation; by
+// records who acknowledged it and defaults to "api" on the server
+func (c *Client) AcknowledgeAlert(ctx context.Context, id, by string) (*alerts.Alert, error) {
+	var alert alerts.Alert
+	path := fmt.Sprintf("/api/v1/alerts/%s/ack", url.PathEscape(id))
+	if err := c.post(ctx, path, map[string]string{"by": by}, &alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Silences returns the silences still in effect, soonest to expire first
+func (c *Client) Silences(ctx context.Context) ([]alerts.Silence, error) {
+	var response struct {
+		Silences []alerts.Silence `json:"silences"`
+	}
+	if err := c.get(ctx, "/api/v1/alerts/silences", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Silences, nil
+}
+
+// CreateSilence silences alerts whose labels (check, rule, severity or chaos) equal
+// all of the matchers for a duration
+func (c *Client) CreateSilence(ctx context.Context, matchers map[string]string, duration time.Duration, comment string) (*alerts.Silence, error) {
+	var silence alerts.Silence
+	request := map[string]interface{}{"matchers": matchers, "duration": duration.String(), "comment": comment}
+	if err := c.post(ctx, "/api/v1/alerts/silences", request, &silence); err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// ExpireSilence ends a silence before it expires
+func (c *Client) ExpireSilence(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/alerts/silences/"+url.PathEscape(id), nil, nil)
+	return err
+}
+
+// Webhooks returns the check result webhooks and how their deliveries went
+func (c *Client) Webhooks(ctx context.Context) ([]webhooks.Status, error) {
+	var response struct {
+		Webhooks []webhooks.Status `json:"webhooks"`
+	}
+	if err := c.get(ctx, "/api/v1/webhooks", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Webhooks, nil
+}
+
+// RegisterWebhook registers a webhook notified of the check transitions its
+// filter selects, until the server restarts
+func (c *Client) RegisterWebhook(ctx context.Context, webhook webhooks.Webhook) (*webhooks.Webhook, error) {
+	var registered webhooks.Webhook
+	request := map[string]string{"name": webhook.Name, "url": webhook.URL, "filter": webhook.Filter, "secret": webhook.Secret}
+	if err := c.post(ctx, "/api/v1/webhooks", request, &registered); err != nil {
+		return nil, err
+	}
+	return &registered, nil
+}
+
+// UnregisterWebhook removes a webhook registered through the API
+func (c *Client) UnregisterWebhook(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(name), nil, nil)
+	return err
+}
+
+// Deliveries returns notifications channels failed to accept, oldest first;
+// state filters to "pending" or "dead" ones
+func (c *Client) Deliveries(ctx context.Context, state string) ([]alerts.Delivery, error) {
+	query := url.Values{}
+	if state != "" {
+		query.Set("state", state)
+	}
+
+	var response struct {
+		Deliveries []alerts.Delivery `json:"deliveries"`
+	}
+	if err := c.get(ctx, "/api/v1/alerts/deliveries", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Deliveries, nil
+}
+
+// Redeliver retries a queued or dead-lettered notification now
+func (c *Client) Redeliver(ctx context.Context, id string) (*alerts.Delivery, error) {
+	var delivery alerts.Delivery
+	path := fmt.Sprintf("/api/v1/alerts/deliveries/%s/redeliver", url.PathEscape(id))
+	if err := c.post(ctx, path, nil, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UIConfig returns the dashboard configuration
+func (c *Client) UIConfig(ctx context.Context) (map[string]interface{}, error) {
+	var config map[string]interface{}
+	if err := c.get(ctx, "/api/v1/config/ui", nil, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// AIInsights returns AI-generated cluster insights. When the server queues
+// the analysis, AIInsights polls the run until it finishes or ctx is done.
+func (c *Client) AIInsights(ctx context.Context) (*ai.InsightSummary, error) {
+	var insights ai.InsightSummary
+	if err := c.awaitAnalysis(ctx, c.get(ctx, "/api/v1/ai/insights", nil, &insights), &insights); err != nil {
+		return nil, err
+	}
+	return &insights, nil
+}
+
+// AnalysisRuns returns the server's on-demand AI analyses, newest first
+func (c *Client) AnalysisRuns(ctx context.Context) ([]core.AnalysisRun, error) {
+	var runs []core.AnalysisRun
+	if err := c.get(ctx, "/api/v1/ai/analysis/runs", nil, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// AnalysisRun returns an on-demand AI analysis and, once it has succeeded,
+// its result
+func (c *Client) AnalysisRun(ctx context.Context, id string) (*core.AnalysisRun, error) {
+	var run core.AnalysisRun
+	if err := c.get(ctx, "/api/v1/ai/analysis/runs/"+url.PathEscape(id), nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// WaitAnalysisRun polls an analysis run until it finishes or ctx is done
+func (c *Client) WaitAnalysisRun(ctx context.Context, id string) (*core.AnalysisRun, error) {
+	for {
+		run, err := c.AnalysisRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Finished() {
+			return run, nil
+		}
+		select {
+		case <-time.After(analysisPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// awaitAnalysis passes through err unless the server accepted an analysis
+// run without finishing it, in which case it waits for the run and decodes
+// its result into out
+func (c *Client) awaitAnalysis(ctx context.Context, err error, out interface{}) error {
+	var accepted *acceptedRunError
+	if !errors.As(err, &accepted) {
+		return err
+	}
+	run, err := c.WaitAnalysisRun(ctx, accepted.run.ID)
+	if err != nil {
+		return err
+	}
+	if run.State == core.AnalysisRunFailed {
+		return &APIError{StatusCode: http.StatusInternalServerError, Message: run.Error}
+	}
+	data, err := json.Marshal(run.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis result: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// AIDiagnosis returns the stored AI diagnosis for a check, or ErrNotAnalyzed
+func (c *Client) AIDiagnosis(ctx context.Context, check string) (*ai.AnalysisResponse, error) {
+	return c.analysis(ctx, "/api/v1/ai/analyze/"+url.PathEscape(check))
+}
+
+// AIHealing returns the stored AI healing suggestions for a check, or ErrNotAnalyzed
+func (c *Client) AIHealing(ctx context.Context, check string) (*ai.AnalysisResponse, error) {
+	return c.analysis(ctx, "/api/v1/ai/heal/"+url.PathEscape(check))
+}
+
+// FederatedAnalysis asks a hub to request analyses of checks from its
+// spokes and combine them. Without spokes every spoke is asked; without
+// checks each analyzes every check that isn't healthy.
+func (c *Client) FederatedAnalysis(ctx context.Context, spokes, checks []string) (*federation.Report, error) {
+	var report federation.Report
+	request := map[string][]string{"spokes": spokes, "checks": checks}
+	if err := c.post(ctx, "/api/v1/federation/analyze", request, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Query asks the AI assistant a natural language question
+func (c *Client) Query(ctx context.Context, query string) (*ai.QueryResponse, error) {
+	var response ai.QueryResponse
+	request := map[string]string{"query": query}
+	if err := c.post(ctx, "/api/v1/ai/assistant/query", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Predictions returns AI predictions about future issues
+func (c *Client) Predictions(ctx context.Context) (*Predictions, error) {
+	var predictions Predictions
+	if err := c.get(ctx, "/api/v1/ai/predictions", nil, &predictions); err != nil {
+		return nil, err
+	}
+	return &predictions, nil
+}
+
+// RemediationSuggestions returns AI remediation suggestions for a check
+func (c *Client) RemediationSuggestions(ctx context.Context, check string) ([]ai.RemediationAction, error) {
+	var response struct {
+		Check       string                 `json:"check"`
+		Suggestions []ai.RemediationAction `json:"suggestions"`
+	}
+	path := fmt.Sprintf("/api/v1/ai/remediation/%s/suggestions", url.PathEscape(check))
+	if err := c.get(ctx, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Suggestions, nil
+}
+
+// ExecuteRemediation executes a remediation action; requests are never retried
+func (c *Client) ExecuteRemediation(ctx context.Context, actionID string, dryRun bool) (*ai.RemediationRecord, error) {
+	var record ai.RemediationRecord
+	request := map[string]interface{}{
+		"action_id": actionID,
+		"dry_run":   dryRun,
+	}
+	if err := c.post(ctx, "/api/v1/ai/remediation/execute", request, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Remediations returns suggested remediation actions and their approval
+// status, oldest first; empty filters return every action
+func (c *Client) Remediations(ctx context.Context, check string, status core.RemediationStatus) ([]core.Remediation, error) {
+	query := url.Values{}
+	if check != "" {
+		query.Set("check", check)
+	}
+	if status != "" {
+		query.Set("status", string(status))
+	}
+
+	var response struct {
+		Remediations []core.Remediation `json:"remediations"`
+	}
+	if err := c.get(ctx, "/api/v1/ai/remediation/actions", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Remediations, nil
+}
+
+// Remediation returns a suggested remediation action with its runs and dry runs
+func (c *Client) Remediation(ctx context.Context, id string) (*core.Remediation, []ai.RemediationRecord, error) {
+	var response struct {
+		Remediation core.Remediation       `json:"remediation"`
+		Records     []ai.RemediationRecord `json:"records"`
+	}
+	path := fmt.Sprintf("/api/v1/ai/remediation/actions/%s", url.PathEscape(id))
+	if err := c.get(ctx, path, nil, &response); err != nil {
+		return nil, nil, err
+	}
+	return &response.Remediation, response.Records, nil
+}
+
+// ApproveRemediation approves a remediation action to run once
+func (c *Client) ApproveRemediation(ctx context.Context, id, comment string) (*core.Remediation, error) {
+	return c.reviewRemediation(ctx, id, "approve", comment)
+}
+
+// RejectRemediation rejects a remediation action so it can't run
+func (c *Client) RejectRemediation(ctx context.Context, id, comment string) (*core.Remediation, error) {
+	return c.reviewRemediation(ctx, id, "reject", comment)
+}
+
+func (c *Client) reviewRemediation(ctx context.Context, id, review, comment string) (*core.Remediation, error) {
+	var remediation core.Remediation
+	path := fmt.Sprintf("/api/v1/ai/remediation/actions/%s/%s", url.PathEscape(id), review)
+	if err := c.post(ctx, path, map[string]string{"comment": comment}, &remediation); err != nil {
+		return nil, err
+	}
+	return &remediation, nil
+}
+
+// RemediationRecords returns remediation runs and dry runs, oldest first;
+// an empty action returns the records of every action
+func (c *Client) RemediationRecords(ctx context.Context, action string) ([]ai.RemediationRecord, error) {
+	query := url.Values{}
+	if action != "" {
+		query.Set("action", action)
+	}
+
+	var response struct {
+		Records []ai.RemediationRecord `json:"records"`
+	}
+	if err := c.get(ctx, "/api/v1/ai/remediation/records", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Records, nil
+}
+
+// SLOs returns the status of every SLO, ordered by name
+func (c *Client) SLOs(ctx context.Context) ([]core.SLOStatus, error) {
+	var response struct {
+		SLOs []core.SLOStatus `json:"slos"`
+	}
+	if err := c.get(ctx, "/api/v1/slo", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.SLOs, nil
+}
+
+// SLO returns the status of one SLO with the burn rates of its alerts
+func (c *Client) SLO(ctx context.Context, name string) (*core.SLOStatus, error) {
+	var status core.SLOStatus
+	if err := c.get(ctx, "/api/v1/slo/"+url.PathEscape(name), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// AnalysisSessions returns recorded cluster analyses, oldest first; an empty
+// cluster returns sessions for all clusters
+func (c *Client) AnalysisSessions(ctx context.Context, cluster string) ([]ai.AnalysisSession, error) {
+	query := url.Values{}
+	if cluster != "" {
+		query.Set("cluster", cluster)
+	}
+
+	var response struct {
+		Sessions []ai.AnalysisSession `json:"sessions"`
+	}
+	if err := c.get(ctx, "/api/v1/ai/analysis/sessions", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Sessions, nil
+}
+
+// CompareAnalyses diffs two analyses identified by session ID or RFC 3339
+// timestamp; narrative asks the AI to describe what improved and regressed
+func (c *Client) CompareAnalyses(ctx context.Context, cluster, from, to string, narrative bool) (*ai.AnalysisDiff, error) {
+	var diff ai.AnalysisDiff
+	request := map[string]interface{}{
+		"cluster":   cluster,
+		"from":      from,
+		"to":        to,
+		"narrative": narrative,
+	}
+	if err := c.post(ctx, "/api/v1/ai/analysis/compare", request, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// Investigations returns saved investigation plans, oldest first,
+// optionally only those for one check
+func (c *Client) Investigations(ctx context.Context, check string) ([]ai.InvestigationPlan, error) {
+	query := url.Values{}
+	if check != "" {
+		query.Set("check", check)
+	}
+
+	var response struct {
+		Investigations []ai.InvestigationPlan `json:"investigations"`
+	}
+	if err := c.get(ctx, "/api/v1/ai/investigations", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Investigations, nil
+}
+
+// PlanInvestigation builds and saves an investigation plan for a check
+func (c *Client) PlanInvestigation(ctx context.Context, check string) (*ai.InvestigationPlan, error) {
+	var plan ai.InvestigationPlan
+	if err := c.post(ctx, "/api/v1/ai/investigations", map[string]string{"check": check}, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Investigation returns a saved investigation plan with its last run's output
+func (c *Client) Investigation(ctx context.Context, id string) (*ai.InvestigationPlan, error) {
+	var plan ai.InvestigationPlan
+	if err := c.get(ctx, "/api/v1/ai/investigations/"+url.PathEscape(id), nil, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// RunInvestigation runs the read-only steps of a saved investigation plan on
+// the server and returns the plan with their output
+func (c *Client) RunInvestigation(ctx context.Context, id string) (*ai.InvestigationPlan, error) {
+	var plan ai.InvestigationPlan
+	path := fmt.Sprintf("/api/v1/ai/investigations/%s/run", url.PathEscape(id))
+	if err := c.post(ctx, path, nil, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// RefreshToolResults drops the server's cached kubectl output so the next AI
+// request reads current cluster state, and returns how many results were dropped
+func (c *Client) RefreshToolResults(ctx context.Context) (int, error) {
+	var response struct {
+		Dropped int `json:"dropped"`
+	}
+	if err := c.post(ctx, "/api/v1/ai/tools/refresh", nil, &response); err != nil {
+		return 0, err
+	}
+	return response.Dropped, nil
+}
+
+// SmartAlertInsights returns AI alert pattern insights
+func (c *Client) SmartAlertInsights(ctx context.Context) (*ai.AlertInsights, error) {
+	var insights ai.AlertInsights
+	if err := c.get(ctx, "/api/v1/ai/alerts/insights", nil, &insights); err != nil {
+		return nil, err
+	}
+	return &insights, nil
+}
+
+// Contexts returns all available Kubernetes contexts
+func (c *Client) Contexts(ctx context.Context) ([]k8s.ContextInfo, error) {
+	var response struct {
+		Contexts []k8s.ContextInfo `json:"contexts"`
+	}
+	if err := c.get(ctx, "/api/v1/contexts", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Contexts, nil
+}
+
+// CurrentContext returns the current Kubernetes context
+func (c *Client) CurrentContext(ctx context.Context) (*k8s.ContextInfo, error) {
+	var info k8s.ContextInfo
+	if err := c.get(ctx, "/api/v1/contexts/current", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SwitchContext switches the server to a different Kubernetes context
+func (c *Client) SwitchContext(ctx context.Context, name string) (*k8s.ContextInfo, error) {
+	var response struct {
+		Success bool            `json:"success"`
+		Context k8s.ContextInfo `json:"context"`
+	}
+	request := map[string]string{"context_name": name}
+	if err := c.post(ctx, "/api/v1/contexts/switch", request, &response); err != nil {
+		return nil, err
+	}
+	return &response.Context, nil
+}
+
+// analysis fetches a stored AI analysis, mapping the placeholder response to ErrNotAnalyzed
+func (c *Client) analysis(ctx context.Context, path string) (*ai.AnalysisResponse, error) {
+	body, err := c.do(ctx, http.MethodPost, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &status); err == nil && status.Status == "not_analyzed" {
+		return nil, ErrNotAnalyzed
+	}
+
+	var response ai.AnalysisResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &response, nil
+}
+
+// get performs a GET request and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	body, err := c.do(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// post performs a POST request with a JSON body and decodes the JSON response into out
+func (c *Client) post(ctx context.Context, path string, in interface{}, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := c.do(ctx, http.MethodPost, path, nil, payload)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// do executes a request, retrying idempotent requests on transient failures
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, payload []byte) ([]byte, error) {
+	endpoint := *c.baseURL
+	endpoint.Path = c.baseURL.Path + path
+	endpoint.RawQuery = query.Encode()
+
+	retries := 0
+	if method == http.MethodGet {
+		retries = c.maxRetries
+	}
+	backoff := c.retryBackoff
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		body, retryable, err := c.attempt(ctx, method, endpoint.String(), payload)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attempt performs a single HTTP round trip and reports whether a failure is retryable
+func (c *Client) attempt(ctx context.Context, method, endpoint string, payload []byte) ([]byte, bool, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Network errors are retryable unless the caller gave up
+		return nil, ctx.Err() == nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if c.onDeprecated != nil && resp.Header.Get("Deprecation") != "" {
+		c.onDeprecated(deprecationNotice(req, resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retryable, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    errorMessage(body),
+		}
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		var run core.AnalysisRun
+		if err := json.Unmarshal(body, &run); err == nil && run.ID != "" {
+			return nil, false, &acceptedRunError{run: run}
+		}
+	}
+
+	return body, false, nil
+}
+
+// deprecationNotice describes a response from a deprecated route
+func deprecationNotice(req *http.Request, resp *http.Response) DeprecationNotice {
+	notice := DeprecationNotice{Method: req.Method, Path: req.URL.Path}
+	if sunset, err := http.ParseTime(resp.Header.Get("Sunset")); err == nil {
+		notice.Sunset = sunset
+	}
+	for _, link := range resp.Header.Values("Link") {
+		target, params, ok := strings.Cut(link, ";")
+		if ok && strings.Contains(params, `rel="successor-version"`) {
+			notice.Successor = strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return notice
+}
+
+// errorMessage extracts the message from either a JSON or plain text error body
+func errorMessage(body []byte) string {
+	var structured struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &structured); err == nil && structured.Message != "" {
+		return structured.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,532 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/federation"
+	"github.com/kubepulse/kubepulse/pkg/fleet"
+)
+
+func newTestClient(t *testing.T, server *httptest.Server, token string) *Client {
+	t.Helper()
+	client, err := NewClient(Config{
+		BaseURL:      server.URL,
+		Token:        token,
+		RetryBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	return client
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "empty URL", baseURL: "", wantErr: true},
+		{name: "unsupported scheme", baseURL: "ftp://example.com", wantErr: true},
+		{name: "http URL", baseURL: "http://localhost:8080", wantErr: false},
+		{name: "trailing slash", baseURL: "https://kubepulse.example.com/", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(Config{BaseURL: tt.baseURL})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestClient_ClusterHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/health/cluster" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("cluster"); got != "prod" {
+			t.Errorf("expected cluster query 'prod', got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(core.ClusterHealth{
+			ClusterName: "prod",
+			Status:      core.HealthStatusDegraded,
+			Checks: []core.CheckResult{
+				{Name: "pod-health", Status: core.HealthStatusUnknown, Error: errors.New("timeout")},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "secret")
+	health, err := client.ClusterHealth(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if health.ClusterName != "prod" || health.Status != core.HealthStatusDegraded {
+		t.Errorf("unexpected cluster health: %+v", health)
+	}
+	if len(health.Checks) != 1 || health.Checks[0].Error == nil {
+		t.Fatalf("expected check error to be decoded, got %+v", health.Checks)
+	}
+	if health.Checks[0].Error.Error() != "timeout" {
+		t.Errorf("expected check error 'timeout', got %q", health.Checks[0].Error.Error())
+	}
+}
+
+func TestClient_HealthAt(t *testing.T) {
+	at := time.Date(2024, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/health/at" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("timestamp"); got != "2024-06-01T14:00:00+02:00" {
+			t.Errorf("expected an RFC 3339 timestamp, got %q", got)
+		}
+		if _, ok := r.URL.Query()["cluster"]; ok {
+			t.Error("expected no cluster query for the current context")
+		}
+		_ = json.NewEncoder(w).Encode(core.ClusterHealth{Status: core.HealthStatusUnhealthy, Timestamp: at})
+	}))
+	defer server.Close()
+
+	health, err := newTestClient(t, server, "").HealthAt(context.Background(), "", at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.Status != core.HealthStatusUnhealthy || !health.Timestamp.Equal(at) {
+		t.Errorf("unexpected cluster health: %+v", health)
+	}
+}
+
+func TestClient_MultiHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/health/multi" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query(); got.Get("contexts") != "prod,staging" || got.Get("timeout") != "5s" {
+			t.Errorf("unexpected query %v", got)
+		}
+		_ = json.NewEncoder(w).Encode(fleet.Report{
+			Status:   core.HealthStatusDegraded,
+			Contexts: []fleet.Summary{{Context: "prod"}, {Context: "staging", Status: core.HealthStatusDegraded}},
+		})
+	}))
+	defer server.Close()
+
+	report, err := newTestClient(t, server, "").MultiHealth(context.Background(), []string{"prod", "staging"}, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != core.HealthStatusDegraded || len(report.Contexts) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestClient_Findings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/findings" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query(); got.Get("status") != "open" || got.Get("check") != "node-health" || got.Has("id") {
+			t.Errorf("unexpected query %v", got)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"findings": []core.FindingRecord{{ID: "KP-NODE-001", Subject: "node/worker-1", Status: core.FindingOpen, Occurrences: 2}},
+			"total":    1,
+			"open":     1,
+		})
+	}))
+	defer server.Close()
+
+	list, err := newTestClient(t, server, "").Findings(context.Background(), core.FindingFilter{Status: core.FindingOpen, Check: "node-health"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Subject != "node/worker-1" || list[0].Occurrences != 2 {
+		t.Errorf("unexpected findings: %+v", list)
+	}
+}
+
+func TestClient_FederatedAnalysis(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/federation/analyze" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var request struct {
+			Spokes []string `json:"spokes"`
+			Checks []string `json:"checks"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Spokes) != 1 || request.Spokes[0] != "prod-us" {
+			t.Errorf("unexpected request body %+v (%v)", request, err)
+		}
+		_ = json.NewEncoder(w).Encode(federation.Report{
+			Status: core.HealthStatusUnhealthy,
+			Spokes: []federation.SpokeResult{{Spoke: "prod-us", Report: &federation.SpokeReport{Cluster: "prod", Status: core.HealthStatusUnhealthy}}},
+		})
+	}))
+	defer server.Close()
+
+	report, err := newTestClient(t, server, "").FederatedAnalysis(context.Background(), []string{"prod-us"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != core.HealthStatusUnhealthy || len(report.Spokes) != 1 || report.Spokes[0].Report.Cluster != "prod" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ServerStatus{Status: "healthy", Version: "0.1.0"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+	status, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != "healthy" {
+		t.Errorf("expected healthy status, got %s", status.Status)
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestClient_DoesNotRetryPost(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "remediation engine not enabled", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+	_, err := client.ExecuteRemediation(context.Background(), "action-1", true)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Message != "remediation engine not enabled" {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected POST to be attempted once, got %d", calls)
+	}
+}
+
+func TestClient_JSONErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":   true,
+			"message": "context_name is required",
+			"status":  http.StatusBadRequest,
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+	_, err := client.SwitchContext(context.Background(), "")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Message != "context_name is required" {
+		t.Errorf("expected JSON error message, got %q", apiErr.Message)
+	}
+}
+
+func TestClient_AIDiagnosisNotAnalyzed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": "AI analysis not available for this health check",
+			"check":   "pod-health",
+			"status":  "not_analyzed",
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+	if _, err := client.AIDiagnosis(context.Background(), "pod-health"); !errors.Is(err, ErrNotAnalyzed) {
+		t.Errorf("expected ErrNotAnalyzed, got %v", err)
+	}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_ = conn.WriteJSON(core.ClusterHealth{ClusterName: "prod", Status: core.HealthStatusHealthy})
+		_ = conn.WriteJSON(map[string]interface{}{"type": "context_switched", "context": map[string]string{"name": "dev"}})
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+
+	var received []StreamMessage
+	stop := errors.New("stop")
+	err := client.Subscribe(context.Background(), SubscribeOptions{}, func(msg StreamMessage) error {
+		received = append(received, msg)
+		if len(received) == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+	if received[0].Type != StreamMessageClusterHealth || received[0].ClusterHealth == nil {
+		t.Fatalf("expected cluster health message, got %+v", received[0])
+	}
+	if received[0].ClusterHealth.ClusterName != "prod" {
+		t.Errorf("expected cluster 'prod', got %s", received[0].ClusterHealth.ClusterName)
+	}
+	if received[1].Type != "context_switched" {
+		t.Errorf("expected context_switched message, got %s", received[1].Type)
+	}
+}
+
+func TestClient_SubscribeHealthDeltas(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("deltas") != "1" {
+			t.Errorf("expected deltas=1, got query %q", r.URL.RawQuery)
+		}
+		if topics := r.URL.Query().Get("topics"); topics != "health,check:pod-health" {
+			t.Errorf("expected the health and pod-health topics, got %q", topics)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		full := core.ClusterHealth{
+			ClusterName: "prod",
+			Status:      core.HealthStatusHealthy,
+			Checks: []core.CheckResult{
+				{Name: "pod-health", Status: core.HealthStatusHealthy},
+				{Name: "node-health", Status: core.HealthStatusHealthy},
+			},
+		}
+		_ = conn.WriteJSON(map[string]interface{}{"v": 1, "type": "health.updated", "seq": 1, "data": full})
+		// A delta against a health the client never saw is dropped
+		_ = conn.WriteJSON(map[string]interface{}{"v": 1, "type": "health.delta", "seq": 7, "data": core.HealthDelta{Base: 6}})
+		_ = conn.WriteJSON(map[string]interface{}{"v": 1, "type": "health.delta", "seq": 2, "data": core.HealthDelta{
+			Base:        1,
+			ClusterName: "prod",
+			Status:      core.HealthStatusDegraded,
+			Changed:     []core.CheckResult{{Name: "pod-health", Status: core.HealthStatusDegraded}},
+		}})
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, "")
+
+	var received []StreamMessage
+	stop := errors.New("stop")
+	err := client.Subscribe(context.Background(), SubscribeOptions{HealthDeltas: true, Topics: []string{"health", "check:pod-health"}}, func(msg StreamMessage) error {
+		received = append(received, msg)
+		if len(received) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handler error to be returned, got %v", err)
+	}
+
+	delta := received[1]
+	if delta.Type != StreamMessageHealthDelta || delta.Seq != 2 || delta.ClusterHealth == nil {
+		t.Fatalf("expected applied health delta with seq 2, got %+v", delta)
+	}
+	if delta.ClusterHealth.Status != core.HealthStatusDegraded || len(delta.ClusterHealth.Checks) != 2 {
+		t.Fatalf("expected degraded health with both checks, got %+v", delta.ClusterHealth)
+	}
+	if delta.ClusterHealth.Checks[0].Status != core.HealthStatusDegraded || delta.ClusterHealth.Checks[1].Status != core.HealthStatusHealthy {
+		t.Errorf("expected only pod-health to change, got %+v", delta.ClusterHealth.Checks)
+	}
+}
+
+func TestDecodeStreamMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantType   string
+		wantHealth bool
+		wantErr    bool
+	}{
+		{"health envelope", `{"v":1,"type":"health.updated","ts":"2024-01-01T00:00:00Z","data":{"cluster_name":"prod","status":"healthy"}}`, StreamMessageClusterHealth, true, false},
+		{"alert envelope", `{"v":1,"type":"alert.fired","data":{"name":"pod-health"}}`, StreamMessageAlertFired, false, false},
+		{"unknown type is passed through", `{"v":1,"type":"cluster.renamed","data":{}}`, "cluster.renamed", false, false},
+		{"legacy health", `{"cluster_name":"prod","status":"healthy"}`, StreamMessageClusterHealth, true, false},
+		{"future version", `{"v":2,"type":"health.updated","data":{}}`, "", false, true},
+		{"invalid JSON", `{`, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := decodeStreamMessage([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeStreamMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if message.Type != tt.wantType {
+				t.Errorf("expected type %s, got %s", tt.wantType, message.Type)
+			}
+			if (message.ClusterHealth != nil) != tt.wantHealth {
+				t.Fatalf("expected cluster health %v, got %+v", tt.wantHealth, message.ClusterHealth)
+			}
+			if tt.wantHealth && message.ClusterHealth.ClusterName != "prod" {
+				t.Errorf("expected cluster 'prod', got %s", message.ClusterHealth.ClusterName)
+			}
+		})
+	}
+
+	message, _ := decodeStreamMessage([]byte(`{"v":1,"type":"alert.fired","data":{"name":"pod-health"}}`))
+	var alert core.Alert
+	if err := message.Decode(&alert); err != nil || alert.Name != "pod-health" {
+		t.Errorf("expected decoded alert payload, got %+v (%v)", alert, err)
+	}
+}
+
+func TestClient_OnDeprecated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/health/checks" {
+			w.Header().Set("Deprecation", "@1767225600")
+			w.Header().Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+			w.Header().Add("Link", `</api/v2beta/checks>; rel="successor-version"`)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]core.CheckResult{})
+	}))
+	defer server.Close()
+
+	var notices []DeprecationNotice
+	client, err := NewClient(Config{
+		BaseURL:      server.URL,
+		OnDeprecated: func(notice DeprecationNotice) { notices = append(notices, notice) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	if _, err := client.Checks(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Check(context.Background(), "pod-health"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := DeprecationNotice{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/health/checks",
+		Sunset:    time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2beta/checks",
+	}
+	if len(notices) != 1 || notices[0] != want {
+		t.Errorf("expected one notice %+v, got %+v", want, notices)
+	}
+}
+
+func TestClient_AIInsightsPollsAcceptedRun(t *testing.T) {
+	defer func(interval time.Duration) { analysisPollInterval = interval }(analysisPollInterval)
+	analysisPollInterval = time.Millisecond
+
+	tests := []struct {
+		name    string
+		final   core.AnalysisRun
+		wantErr string
+	}{
+		{
+			name:  "succeeded",
+			final: core.AnalysisRun{ID: "run-1", State: core.AnalysisRunSucceeded, Result: ai.InsightSummary{OverallHealth: "Healthy"}},
+		},
+		{
+			name:    "failed",
+			final:   core.AnalysisRun{ID: "run-1", State: core.AnalysisRunFailed, Error: "model unavailable"},
+			wantErr: "model unavailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1/ai/insights":
+					w.WriteHeader(http.StatusAccepted)
+					_ = json.NewEncoder(w).Encode(core.AnalysisRun{ID: "run-1", State: core.AnalysisRunQueued})
+				case "/api/v1/ai/analysis/runs/run-1":
+					polls++
+					if polls < 2 {
+						_ = json.NewEncoder(w).Encode(core.AnalysisRun{ID: "run-1", State: core.AnalysisRunRunning})
+						return
+					}
+					_ = json.NewEncoder(w).Encode(tt.final)
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			insights, err := newTestClient(t, server, "").AIInsights(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if insights.OverallHealth != "Healthy" || polls != 2 {
+				t.Errorf("expected the run's result after 2 polls, got %+v after %d", insights, polls)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// Stream message types, matching the server's WebSocket protocol v1
+const (
+	StreamMessageClusterHealth     = "health.updated"
+	StreamMessageHealthDelta       = "health.delta"
+	StreamMessageAlertFired        = "alert.fired"
+	StreamMessageAlertResolved     = "alert.resolved"
+	StreamMessageContextSwitched   = "context.switched"
+	StreamMessageAIInsight         = "ai.insight"
+	StreamMessageRemediationStatus = "remediation.status"
+	StreamMessageCheckResult       = "check.result"
+)
+
+// StreamProtocolVersion is the WebSocket envelope version this client understands
+const StreamProtocolVersion = 1
+
+// StreamMessage is a message received from the WebSocket stream
+type StreamMessage struct {
+	Version       int
+	Type          string
+	Timestamp     time.Time
+	Seq           uint64              // Health sequence number, on health messages
+	ClusterHealth *core.ClusterHealth // Set for health.updated and health.delta messages
+	Data          json.RawMessage     // The envelope's data payload
+	Raw           json.RawMessage
+}
+
+// Decode unmarshals the message payload into v
+func (m StreamMessage) Decode(v interface{}) error {
+	if len(m.Data) == 0 {
+		return fmt.Errorf("stream message %q has no data", m.Type)
+	}
+	return json.Unmarshal(m.Data, v)
+}
+
+// StreamHandler processes stream messages; returning an error stops the subscription
+type StreamHandler func(StreamMessage) error
+
+// SubscribeOptions configures a stream subscription
+type SubscribeOptions struct {
+	Reconnect     bool          // Reconnect after connection loss until ctx is done
+	ReconnectWait time.Duration // Delay between reconnect attempts
+
+	// HealthDeltas asks the server to send only the checks that changed
+	// between full health snapshots. Deltas are applied before the handler
+	// is called, so ClusterHealth is always the complete health.
+	HealthDeltas bool
+
+	// Topics picks the topics to receive, such as alerts or
+	// check:pod-health; empty receives the server's default topics
+	Topics []string
+}
+
+// Subscribe connects to the WebSocket stream and invokes handler for each message.
+// It blocks until ctx is cancelled, the handler returns an error, or the connection
+// fails and reconnects are disabled.
+func (c *Client) Subscribe(ctx context.Context, opts SubscribeOptions, handler StreamHandler) error {
+	if opts.ReconnectWait == 0 {
+		opts.ReconnectWait = 3 * time.Second
+	}
+
+	for {
+		err := c.subscribeOnce(ctx, opts, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var hErr *handlerError
+		if errors.As(err, &hErr) {
+			return hErr.err
+		}
+		if !opts.Reconnect {
+			return err
+		}
+
+		select {
+		case <-time.After(opts.ReconnectWait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// handlerError wraps errors returned by a StreamHandler so they are not retried
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string {
+	return e.err.Error()
+}
+
+// subscribeOnce reads messages from a single WebSocket connection
+func (c *Client) subscribeOnce(ctx context.Context, opts SubscribeOptions, handler StreamHandler) error {
+	endpoint := *c.baseURL
+	endpoint.Path = c.baseURL.Path + "/ws"
+	endpoint.RawQuery = fmt.Sprintf("v=%d", StreamProtocolVersion)
+	if opts.HealthDeltas {
+		endpoint.RawQuery += "&deltas=1"
+	}
+	if len(opts.Topics) > 0 {
+		endpoint.RawQuery += "&topics=" + url.QueryEscape(strings.Join(opts.Topics, ","))
+	}
+	if endpoint.Scheme == "https" {
+		endpoint.Scheme = "wss"
+	} else {
+		endpoint.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	header.Set("User-Agent", c.userAgent)
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = true
+	conn, resp, err := dialer.DialContext(ctx, endpoint.String(), header)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to stream: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Unblock ReadMessage when the caller cancels
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	var health healthTracker
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("stream read failed: %w", err)
+		}
+
+		message, err := decodeStreamMessage(data)
+		if err != nil {
+			return fmt.Errorf("failed to decode stream message: %w", err)
+		}
+		if !health.track(&message) {
+			continue
+		}
+
+		if err := handler(message); err != nil {
+			return &handlerError{err: err}
+		}
+	}
+}
+
+// healthTracker keeps the last health received on a connection so health
+// deltas can be applied to it
+type healthTracker struct {
+	last *core.ClusterHealth
+	seq  uint64
+}
+
+// track records a full health message or applies a delta to the last one,
+// setting the message's ClusterHealth. It returns false for a delta that
+// can't be applied, which is dropped; the server's next full snapshot
+// resynchronizes the client.
+func (t *healthTracker) track(message *StreamMessage) bool {
+	switch message.Type {
+	case StreamMessageClusterHealth:
+		t.last, t.seq = message.ClusterHealth, message.Seq
+	case StreamMessageHealthDelta:
+		var delta core.HealthDelta
+		if err := message.Decode(&delta); err != nil || t.last == nil || delta.Base != t.seq {
+			return false
+		}
+		health := t.last.ApplyDelta(delta)
+		t.last, t.seq = &health, message.Seq
+		message.ClusterHealth = &health
+	}
+	return true
+}
+
+// decodeStreamMessage unwraps a versioned envelope. Messages from servers
+// that predate the envelope are passed through: untyped messages are health
+// broadcasts and typed ones keep their type with the whole message as data.
+func decodeStreamMessage(data []byte) (StreamMessage, error) {
+	var envelope struct {
+		Version   int             `json:"v"`
+		Type      string          `json:"type"`
+		Timestamp time.Time       `json:"ts"`
+		Seq       uint64          `json:"seq"`
+		Data      json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return StreamMessage{}, err
+	}
+
+	message := StreamMessage{
+		Version:   envelope.Version,
+		Type:      envelope.Type,
+		Timestamp: envelope.Timestamp,
+		Seq:       envelope.Seq,
+		Data:      envelope.Data,
+		Raw:       json.RawMessage(data),
+	}
+
+	if message.Version == 0 {
+		message.Data = json.RawMessage(data)
+		if message.Type == "" {
+			message.Type = StreamMessageClusterHealth
+		}
+	} else if message.Version > StreamProtocolVersion {
+		return StreamMessage{}, fmt.Errorf("unsupported stream protocol version %d", message.Version)
+	}
+
+	if message.Type == StreamMessageClusterHealth {
+		var health core.ClusterHealth
+		if err := message.Decode(&health); err != nil {
+			return StreamMessage{}, err
+		}
+		message.ClusterHealth = &health
+	}
+
+	return message, nil
+}
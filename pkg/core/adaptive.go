@@ -0,0 +1,162 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// AdaptiveConfig lets each check's interval follow its health, within
+// bounds. Failing checks, and every check while an SLO burns its error
+// budget, run every MinInterval to catch recovery quickly. Checks healthy
+// for HealthyAfter double their interval each run up to MaxInterval,
+// reducing steady-state load on large clusters. Zero values disable it.
+type AdaptiveConfig struct {
+	MinInterval  time.Duration
+	MaxInterval  time.Duration
+	HealthyAfter time.Duration
+}
+
+// Reasons a check's interval changed
+const (
+	adaptFailing = "failing"
+	adaptBurning = "an SLO is burning its error budget"
+	adaptNormal  = "recently changed"
+	adaptHealthy = "healthy for a long time"
+)
+
+// adaptiveScheduler tracks when each check is next due under an
+// AdaptiveConfig
+type adaptiveScheduler struct {
+	config AdaptiveConfig
+	base   time.Duration // The engine's interval, used until a check settles
+
+	mu     sync.Mutex
+	checks map[string]*adaptiveState
+}
+
+// adaptiveState is a check's current interval and next run
+type adaptiveState struct {
+	interval     time.Duration
+	nextRun      time.Time
+	healthySince time.Time
+}
+
+// newAdaptiveScheduler returns a scheduler, or nil when adaptive intervals
+// are disabled
+func newAdaptiveScheduler(config AdaptiveConfig, base time.Duration) *adaptiveScheduler {
+	if config == (AdaptiveConfig{}) || base <= 0 {
+		return nil
+	}
+	if config.MinInterval <= 0 || config.MinInterval > base {
+		config.MinInterval = base
+	}
+	if config.MaxInterval < base {
+		config.MaxInterval = base
+	}
+	return &adaptiveScheduler{config: config, base: base, checks: make(map[string]*adaptiveState)}
+}
+
+// due returns the checks whose next run has come; checks not seen before
+// are due at once
+func (s *adaptiveScheduler) due(checks []HealthCheck, now time.Time) []HealthCheck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []HealthCheck
+	for _, check := range checks {
+		state, ok := s.checks[check.Name()]
+		// Allow for ticker jitter so a check isn't pushed back a whole tick
+		if !ok || !state.nextRun.After(now.Add(s.config.MinInterval/10)) {
+			due = append(due, check)
+		}
+	}
+	return due
+}
+
+// observe sets a check's next run from its latest result
+func (s *adaptiveScheduler) observe(result CheckResult, burning bool, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.checks[result.Name]
+	if !ok {
+		state = &adaptiveState{interval: s.base}
+		s.checks[result.Name] = state
+	}
+
+	healthy := result.Status == HealthStatusHealthy || InMaintenance(result)
+	if !healthy {
+		state.healthySince = time.Time{}
+	} else if state.healthySince.IsZero() {
+		state.healthySince = now
+	}
+
+	interval, reason := s.base, adaptNormal
+	switch {
+	case !healthy:
+		interval, reason = s.config.MinInterval, adaptFailing
+	case burning:
+		interval, reason = s.config.MinInterval, adaptBurning
+	case s.config.HealthyAfter > 0 && now.Sub(state.healthySince) >= s.config.HealthyAfter:
+		interval, reason = min(max(state.interval*2, s.base), s.config.MaxInterval), adaptHealthy
+	}
+	if interval != state.interval {
+		klog.V(1).Infof("Check %s now runs every %s: %s", result.Name, interval, reason)
+	}
+	state.interval = interval
+	state.nextRun = now.Add(interval)
+}
+
+// interval returns a check's current interval
+func (s *adaptiveScheduler) interval(name string) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.checks[name]
+	if !ok {
+		return 0, false
+	}
+	return state.interval, true
+}
+
+// metrics reports each check's current interval
+func (s *adaptiveScheduler) metrics(now time.Time) []Metric {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	metrics := make([]Metric, 0, len(s.checks))
+	for name, state := range s.checks {
+		metrics = append(metrics, Metric{
+			Name:      "kubepulse_check_interval_seconds",
+			Value:     state.interval.Seconds(),
+			Unit:      "seconds",
+			Labels:    map[string]string{"check": name},
+			Timestamp: now,
+			Type:      MetricTypeGauge,
+		})
+	}
+	return metrics
+}
+
+// adaptIntervals reschedules checks that just ran from their results
+func (e *Engine) adaptIntervals(checks []HealthCheck) {
+	now := time.Now()
+	burning := e.sloBurning()
+	for _, check := range checks {
+		if result, ok := e.GetResult(check.Name()); ok {
+			e.adaptive.observe(result, burning, now)
+		}
+	}
+	e.recordMetrics(e.adaptive.metrics(now))
+}
+
+// sloBurning reports whether any SLO is violated or on course to exhaust
+// its error budget within a week
+func (e *Engine) sloBurning() bool {
+	for _, status := range e.sloTracker.GetAllSLOs() {
+		if status.IsViolated || status.TimeToExhaust != "" {
+			return true
+		}
+	}
+	return false
+}
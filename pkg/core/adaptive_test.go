@@ -0,0 +1,81 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdaptiveScheduler_Observe(t *testing.T) {
+	scheduler := newAdaptiveScheduler(AdaptiveConfig{
+		MinInterval:  10 * time.Second,
+		MaxInterval:  2 * time.Minute,
+		HealthyAfter: time.Minute,
+	}, 30*time.Second)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	healthy := CheckResult{Name: "pod-health", Status: HealthStatusHealthy}
+	failing := CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy}
+
+	steps := []struct {
+		name    string
+		result  CheckResult
+		burning bool
+		after   time.Duration
+		want    time.Duration
+	}{
+		{"first healthy run", healthy, false, 0, 30 * time.Second},
+		{"not healthy long enough", healthy, false, 30 * time.Second, 30 * time.Second},
+		{"healthy long enough", healthy, false, 30 * time.Second, time.Minute},
+		{"still healthy", healthy, false, time.Minute, 2 * time.Minute},
+		{"capped at max", healthy, false, 2 * time.Minute, 2 * time.Minute},
+		{"failing", failing, false, 2 * time.Minute, 10 * time.Second},
+		{"recovered", healthy, false, 10 * time.Second, 30 * time.Second},
+		{"SLO burning", healthy, true, 30 * time.Second, 10 * time.Second},
+		{"SLO recovered", healthy, false, 10 * time.Second, 30 * time.Second},
+	}
+	for _, step := range steps {
+		now = now.Add(step.after)
+		scheduler.observe(step.result, step.burning, now)
+		if got, _ := scheduler.interval("pod-health"); got != step.want {
+			t.Errorf("%s: expected interval %s, got %s", step.name, step.want, got)
+		}
+	}
+}
+
+func TestAdaptiveScheduler_Disabled(t *testing.T) {
+	if scheduler := newAdaptiveScheduler(AdaptiveConfig{}, 30*time.Second); scheduler != nil {
+		t.Error("expected no scheduler for a zero config")
+	}
+}
+
+func TestEngine_AdaptiveIntervals(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Interval:   time.Hour,
+		Adaptive:   AdaptiveConfig{MinInterval: time.Minute, MaxInterval: 2 * time.Hour, HealthyAfter: time.Hour},
+	})
+	pods := &countingCheck{mockHealthCheck: mockHealthCheck{name: "pod-health"}}
+	engine.AddCheck(pods)
+
+	engine.runChecks()
+	engine.runChecks()
+	if runs := pods.runs.Load(); runs != 1 {
+		t.Errorf("expected a healthy check to wait for its interval, got %d runs", runs)
+	}
+	if cadence := engine.cadence("pod-health"); cadence != time.Hour {
+		t.Errorf("expected the base interval as cadence, got %s", cadence)
+	}
+
+	found := false
+	for _, series := range engine.GetMetricHistory("kubepulse_check_interval_seconds") {
+		for _, metric := range series {
+			if metric.Labels["check"] == "pod-health" && metric.Value == time.Hour.Seconds() {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("expected the check interval to be recorded")
+	}
+}
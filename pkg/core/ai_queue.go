@@ -0,0 +1,248 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// aiSeverityOrder lists severities from highest to lowest priority
+var aiSeverityOrder = []AlertSeverity{AlertSeverityCritical, AlertSeverityWarning, AlertSeverityInfo}
+
+// DefaultAIQueueCapacity is the number of pending AI events kept per severity
+var DefaultAIQueueCapacity = map[AlertSeverity]int{
+	AlertSeverityCritical: 50,
+	AlertSeverityWarning:  25,
+	AlertSeverityInfo:     10,
+}
+
+// aiSeverityAtLeast reports whether severity ranks at or above floor; an
+// empty floor admits every severity
+func aiSeverityAtLeast(severity, floor AlertSeverity) bool {
+	for _, s := range aiSeverityOrder {
+		if s == severity {
+			return true
+		}
+		if s == floor {
+			return false
+		}
+	}
+	return true
+}
+
+// AIQueue holds check results awaiting AI analysis. Higher severities are
+// always dequeued first, each severity has its own capacity so a flood of
+// low-severity events can't crowd out critical ones, and a result for a check
+// that is already queued replaces the queued one instead of adding another.
+type AIQueue struct {
+	mu        sync.Mutex
+	capacity  map[AlertSeverity]int
+	buckets   map[AlertSeverity][]string // FIFO of check names per severity
+	pending   map[string]aiEvent         // Latest queued event per check
+	ready     chan struct{}
+	enqueued  map[AlertSeverity]int64
+	coalesced map[AlertSeverity]int64
+	dropped   map[AlertSeverity]int64
+	processed int64
+}
+
+// aiEvent is a queued check result
+type aiEvent struct {
+	result   CheckResult
+	severity AlertSeverity
+	queuedAt time.Time
+}
+
+// AIQueueStats reports queue depth and event counters per severity
+type AIQueueStats struct {
+	Depth     map[AlertSeverity]int   `json:"depth"`
+	Capacity  map[AlertSeverity]int   `json:"capacity"`
+	Enqueued  map[AlertSeverity]int64 `json:"enqueued"`
+	Coalesced map[AlertSeverity]int64 `json:"coalesced"`
+	Dropped   map[AlertSeverity]int64 `json:"dropped"`
+	Processed int64                   `json:"processed"`
+}
+
+// NewAIQueue creates a queue; severities missing from capacity use
+// DefaultAIQueueCapacity
+func NewAIQueue(capacity map[AlertSeverity]int) *AIQueue {
+	q := &AIQueue{
+		capacity:  make(map[AlertSeverity]int),
+		buckets:   make(map[AlertSeverity][]string),
+		pending:   make(map[string]aiEvent),
+		ready:     make(chan struct{}, 1),
+		enqueued:  make(map[AlertSeverity]int64),
+		coalesced: make(map[AlertSeverity]int64),
+		dropped:   make(map[AlertSeverity]int64),
+	}
+	for _, severity := range aiSeverityOrder {
+		q.capacity[severity] = DefaultAIQueueCapacity[severity]
+		if c, ok := capacity[severity]; ok && c > 0 {
+			q.capacity[severity] = c
+		}
+	}
+	return q
+}
+
+// Push queues a result for analysis. A queued result for the same check is
+// replaced, moving to the higher of the two severities. When the severity's
+// queue is full its oldest event is dropped.
+func (q *AIQueue) Push(result CheckResult, severity AlertSeverity) {
+	if _, known := q.capacity[severity]; !known {
+		severity = AlertSeverityInfo
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.enqueued[severity]++
+	event := aiEvent{result: result, severity: severity, queuedAt: time.Now()}
+
+	if existing, queued := q.pending[result.Name]; queued {
+		q.coalesced[severity]++
+		if aiPriority(existing.severity) <= aiPriority(severity) {
+			// Keep the queue position and higher severity; refresh the result
+			event.severity = existing.severity
+			event.queuedAt = existing.queuedAt
+			q.pending[result.Name] = event
+			return
+		}
+		q.removeLocked(existing.severity, result.Name)
+	}
+
+	if len(q.buckets[severity]) >= q.capacity[severity] {
+		oldest := q.buckets[severity][0]
+		q.buckets[severity] = q.buckets[severity][1:]
+		delete(q.pending, oldest)
+		q.dropped[severity]++
+		klog.V(2).Infof("AI queue full for %s events, dropped analysis of %s", severity, oldest)
+	}
+
+	q.buckets[severity] = append(q.buckets[severity], result.Name)
+	q.pending[result.Name] = event
+	q.signal()
+}
+
+// Pop blocks until an event is available and returns the highest-severity,
+// oldest one. It returns false when ctx is done.
+func (q *AIQueue) Pop(ctx context.Context) (CheckResult, bool) {
+	for {
+		q.mu.Lock()
+		for _, severity := range aiSeverityOrder {
+			bucket := q.buckets[severity]
+			if len(bucket) == 0 {
+				continue
+			}
+			name := bucket[0]
+			q.buckets[severity] = bucket[1:]
+			event := q.pending[name]
+			delete(q.pending, name)
+			q.processed++
+			if len(q.pending) > 0 {
+				// Wake another worker for the remaining events
+				q.signal()
+			}
+			q.mu.Unlock()
+			return event.result, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return CheckResult{}, false
+		}
+	}
+}
+
+// Stats returns a snapshot of queue depth and counters
+func (q *AIQueue) Stats() AIQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := AIQueueStats{
+		Depth:     make(map[AlertSeverity]int),
+		Capacity:  make(map[AlertSeverity]int),
+		Enqueued:  make(map[AlertSeverity]int64),
+		Coalesced: make(map[AlertSeverity]int64),
+		Dropped:   make(map[AlertSeverity]int64),
+		Processed: q.processed,
+	}
+	for _, severity := range aiSeverityOrder {
+		stats.Depth[severity] = len(q.buckets[severity])
+		stats.Capacity[severity] = q.capacity[severity]
+		stats.Enqueued[severity] = q.enqueued[severity]
+		stats.Coalesced[severity] = q.coalesced[severity]
+		stats.Dropped[severity] = q.dropped[severity]
+	}
+	return stats
+}
+
+// Metrics reports queue depth and dropped and coalesced event counters
+func (q *AIQueue) Metrics() []Metric {
+	stats := q.Stats()
+	now := time.Now()
+
+	metrics := make([]Metric, 0, len(aiSeverityOrder)*3)
+	for _, severity := range aiSeverityOrder {
+		labels := map[string]string{"severity": string(severity)}
+		metrics = append(metrics,
+			Metric{
+				Name:      "kubepulse_ai_queue_depth",
+				Value:     float64(stats.Depth[severity]),
+				Unit:      "events",
+				Labels:    labels,
+				Timestamp: now,
+				Type:      MetricTypeGauge,
+			},
+			Metric{
+				Name:      "kubepulse_ai_events_dropped_total",
+				Value:     float64(stats.Dropped[severity]),
+				Unit:      "events",
+				Labels:    labels,
+				Timestamp: now,
+				Type:      MetricTypeCounter,
+			},
+			Metric{
+				Name:      "kubepulse_ai_events_coalesced_total",
+				Value:     float64(stats.Coalesced[severity]),
+				Unit:      "events",
+				Labels:    labels,
+				Timestamp: now,
+				Type:      MetricTypeCounter,
+			},
+		)
+	}
+	return metrics
+}
+
+// removeLocked removes a check from a severity's FIFO; q.mu must be held
+func (q *AIQueue) removeLocked(severity AlertSeverity, name string) {
+	bucket := q.buckets[severity]
+	for i, queued := range bucket {
+		if queued == name {
+			q.buckets[severity] = append(bucket[:i:i], bucket[i+1:]...)
+			return
+		}
+	}
+}
+
+// signal wakes a waiting worker without blocking
+func (q *AIQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// aiPriority ranks severities, lower is more urgent
+func aiPriority(severity AlertSeverity) int {
+	for i, s := range aiSeverityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(aiSeverityOrder)
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func popAll(t *testing.T, q *AIQueue) []CheckResult {
+	t.Helper()
+	results := make([]CheckResult, 0)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		result, ok := q.Pop(ctx)
+		cancel()
+		if !ok {
+			return results
+		}
+		results = append(results, result)
+	}
+}
+
+func TestAIQueue_PriorityOrder(t *testing.T) {
+	q := NewAIQueue(nil)
+	q.Push(CheckResult{Name: "info-1"}, AlertSeverityInfo)
+	q.Push(CheckResult{Name: "warn-1"}, AlertSeverityWarning)
+	q.Push(CheckResult{Name: "crit-1"}, AlertSeverityCritical)
+	q.Push(CheckResult{Name: "warn-2"}, AlertSeverityWarning)
+	q.Push(CheckResult{Name: "crit-2"}, AlertSeverityCritical)
+
+	want := []string{"crit-1", "crit-2", "warn-1", "warn-2", "info-1"}
+	got := popAll(t, q)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(got))
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, got[i].Name)
+		}
+	}
+	if stats := q.Stats(); stats.Processed != 5 {
+		t.Errorf("expected 5 processed, got %d", stats.Processed)
+	}
+}
+
+func TestAIQueue_Coalescing(t *testing.T) {
+	q := NewAIQueue(nil)
+	q.Push(CheckResult{Name: "pod-health", Message: "first"}, AlertSeverityWarning)
+	q.Push(CheckResult{Name: "node-health"}, AlertSeverityWarning)
+	q.Push(CheckResult{Name: "pod-health", Message: "second"}, AlertSeverityWarning)
+	// Escalation moves the check ahead of other warnings
+	q.Push(CheckResult{Name: "node-health", Message: "escalated"}, AlertSeverityCritical)
+	// A lower severity doesn't demote the queued event
+	q.Push(CheckResult{Name: "node-health", Message: "latest"}, AlertSeverityInfo)
+
+	got := popAll(t, q)
+	if len(got) != 2 {
+		t.Fatalf("expected duplicates to coalesce into 2 events, got %d", len(got))
+	}
+	if got[0].Name != "node-health" || got[0].Message != "latest" {
+		t.Errorf("expected escalated node-health first with the latest result, got %+v", got[0])
+	}
+	if got[1].Name != "pod-health" || got[1].Message != "second" {
+		t.Errorf("expected latest pod-health result, got %+v", got[1])
+	}
+
+	stats := q.Stats()
+	if stats.Coalesced[AlertSeverityWarning] != 1 || stats.Coalesced[AlertSeverityCritical] != 1 || stats.Coalesced[AlertSeverityInfo] != 1 {
+		t.Errorf("unexpected coalesced counters: %+v", stats.Coalesced)
+	}
+}
+
+func TestAIQueue_PerSeverityCapacity(t *testing.T) {
+	q := NewAIQueue(map[AlertSeverity]int{AlertSeverityInfo: 2})
+	for _, name := range []string{"info-1", "info-2", "info-3", "info-4"} {
+		q.Push(CheckResult{Name: name}, AlertSeverityInfo)
+	}
+	// A full info queue doesn't block critical events
+	q.Push(CheckResult{Name: "crit-1"}, AlertSeverityCritical)
+
+	stats := q.Stats()
+	if stats.Dropped[AlertSeverityInfo] != 2 {
+		t.Errorf("expected 2 dropped info events, got %d", stats.Dropped[AlertSeverityInfo])
+	}
+	if stats.Depth[AlertSeverityInfo] != 2 || stats.Depth[AlertSeverityCritical] != 1 {
+		t.Errorf("unexpected depth: %+v", stats.Depth)
+	}
+	if stats.Capacity[AlertSeverityCritical] != DefaultAIQueueCapacity[AlertSeverityCritical] {
+		t.Errorf("expected default critical capacity, got %d", stats.Capacity[AlertSeverityCritical])
+	}
+
+	got := popAll(t, q)
+	names := make([]string, len(got))
+	for i, result := range got {
+		names[i] = result.Name
+	}
+	if len(names) != 3 || names[0] != "crit-1" || names[1] != "info-3" || names[2] != "info-4" {
+		t.Errorf("expected oldest info events dropped, got %v", names)
+	}
+
+	dropped := 0.0
+	for _, metric := range q.Metrics() {
+		if metric.Name == "kubepulse_ai_events_dropped_total" && metric.Labels["severity"] == "info" {
+			dropped = metric.Value
+		}
+	}
+	if dropped != 2 {
+		t.Errorf("expected dropped metric of 2, got %v", dropped)
+	}
+}
+
+func TestAIQueue_PopWaitsForEvents(t *testing.T) {
+	q := NewAIQueue(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	done := make(chan CheckResult, 1)
+	go func() {
+		result, _ := q.Pop(ctx)
+		done <- result
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Push(CheckResult{Name: "pod-health"}, AlertSeverityCritical)
+
+	select {
+	case result := <-done:
+		if result.Name != "pod-health" {
+			t.Errorf("expected pod-health, got %s", result.Name)
+		}
+	case <-ctx.Done():
+		t.Fatal("Pop did not wake up for a pushed event")
+	}
+}
+
+func TestAISeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity, floor AlertSeverity
+		want            bool
+	}{
+		{AlertSeverityInfo, "", true},
+		{AlertSeverityCritical, AlertSeverityCritical, true},
+		{AlertSeverityWarning, AlertSeverityCritical, false},
+		{AlertSeverityWarning, AlertSeverityWarning, true},
+		{AlertSeverityInfo, AlertSeverityWarning, false},
+		{AlertSeverityCritical, AlertSeverityInfo, true},
+	}
+	for _, tt := range tests {
+		if got := aiSeverityAtLeast(tt.severity, tt.floor); got != tt.want {
+			t.Errorf("aiSeverityAtLeast(%s, %s) = %v, want %v", tt.severity, tt.floor, got, tt.want)
+		}
+	}
+}
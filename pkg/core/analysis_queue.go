@@ -0,0 +1,362 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// ErrAnalysisQueueFull is returned when a cluster already has
+	// MaxQueuedAnalyses analyses waiting for a slot
+	ErrAnalysisQueueFull = errors.New("too many AI analyses queued")
+
+	// ErrAnalysisRunNotFound is returned for analysis run IDs that don't
+	// exist or have been forgotten
+	ErrAnalysisRunNotFound = errors.New("analysis run not found")
+)
+
+// DefaultMaxConcurrentAnalyses is how many on-demand AI analyses run at
+// once per cluster unless configured otherwise
+const DefaultMaxConcurrentAnalyses = 2
+
+// MaxQueuedAnalyses bounds the analyses waiting for a slot per cluster
+const MaxQueuedAnalyses = 20
+
+// analysisRunRetention is how long finished runs can still be polled
+const analysisRunRetention = time.Hour
+
+// Analysis run states
+const (
+	AnalysisRunQueued    = "queued"
+	AnalysisRunRunning   = "running"
+	AnalysisRunSucceeded = "succeeded"
+	AnalysisRunFailed    = "failed"
+)
+
+// Kinds of on-demand analysis
+const (
+	AnalysisKindCluster = "cluster" // Cluster-wide insights
+	AnalysisKindBatch   = "batch"   // Consolidated diagnosis of several checks
+)
+
+// AnalysisRun is an on-demand AI analysis. Identical requests made while a
+// run is queued or running share it instead of starting another.
+type AnalysisRun struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Cluster    string      `json:"cluster"`
+	Checks     []string    `json:"checks,omitempty"` // Checks a batch analysis covers
+	State      string      `json:"state"`
+	Requests   int         `json:"requests"` // Requests sharing the run
+	QueuedAt   time.Time   `json:"queued_at"`
+	StartedAt  time.Time   `json:"started_at,omitzero"`
+	FinishedAt time.Time   `json:"finished_at,omitzero"`
+	Result     interface{} `json:"result,omitempty"` // *ai.InsightSummary or *ai.BatchAnalysis once succeeded
+	Error      string      `json:"error,omitempty"`
+
+	// StateRevision is the cluster state revision the run was requested
+	// at; Stale is set once the state has moved on
+	StateRevision uint64 `json:"state_revision"`
+	Stale         bool   `json:"stale,omitempty"`
+}
+
+// Finished reports whether the run succeeded or failed
+func (r AnalysisRun) Finished() bool {
+	return r.State == AnalysisRunSucceeded || r.State == AnalysisRunFailed
+}
+
+// analysisRun is a run with what the queue needs to share and finish it
+type analysisRun struct {
+	AnalysisRun
+	key  string
+	done chan struct{}
+}
+
+// AnalysisQueue runs on-demand AI analyses with at most maxConcurrent
+// running per cluster, queueing the rest in order
+type AnalysisQueue struct {
+	ctx           context.Context
+	maxConcurrent int
+	onFinish      func(AnalysisRun)
+
+	mu       sync.Mutex
+	runs     map[string]*analysisRun  // Keyed by ID
+	inflight map[string]*analysisRun  // Queued or running, keyed by cluster, kind and parameters
+	slots    map[string]chan struct{} // Running analyses per cluster
+	queued   map[string]int           // Waiting analyses per cluster
+	nextID   int
+	now      func() time.Time
+}
+
+// NewAnalysisQueue creates a queue whose runs use ctx. onFinish, when set,
+// is called with every run that finishes.
+func NewAnalysisQueue(ctx context.Context, maxConcurrent int, onFinish func(AnalysisRun)) *AnalysisQueue {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentAnalyses
+	}
+	return &AnalysisQueue{
+		ctx:           ctx,
+		maxConcurrent: maxConcurrent,
+		onFinish:      onFinish,
+		runs:          make(map[string]*analysisRun),
+		inflight:      make(map[string]*analysisRun),
+		slots:         make(map[string]chan struct{}),
+		queued:        make(map[string]int),
+		now:           time.Now,
+	}
+}
+
+// Submit starts an analysis, or joins the queued or running one for the
+// same cluster, kind, checks, state revision and variant, so requests made
+// after the state changed don't share a run of the older state. The variant
+// tells apart requests asking for different answers, such as test mode
+// scenarios. The run starts at once when the cluster has a free slot and is
+// queued otherwise.
+func (q *AnalysisQueue) Submit(cluster, kind string, checks []string, revision uint64, variant string, analyze func(context.Context) (interface{}, error)) (AnalysisRun, error) {
+	key := strings.Join([]string{cluster, kind, strings.Join(checks, ","), strconv.FormatUint(revision, 10), variant}, "\x00")
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prune()
+
+	if run, ok := q.inflight[key]; ok {
+		run.Requests++
+		return run.AnalysisRun, nil
+	}
+
+	slots, ok := q.slots[cluster]
+	if !ok {
+		slots = make(chan struct{}, q.maxConcurrent)
+		q.slots[cluster] = slots
+	}
+	state := AnalysisRunRunning
+	select {
+	case slots <- struct{}{}:
+	default:
+		if q.queued[cluster] >= MaxQueuedAnalyses {
+			return AnalysisRun{}, fmt.Errorf("%w: %d waiting for cluster %s", ErrAnalysisQueueFull, q.queued[cluster], cluster)
+		}
+		q.queued[cluster]++
+		state = AnalysisRunQueued
+	}
+
+	q.nextID++
+	now := q.now()
+	run := &analysisRun{
+		AnalysisRun: AnalysisRun{
+			ID:       fmt.Sprintf("run-%d-%d", now.Unix(), q.nextID),
+			Kind:     kind,
+			Cluster:  cluster,
+			Checks:   checks,
+			State:    state,
+			Requests: 1,
+			QueuedAt: now,
+
+			StateRevision: revision,
+		},
+		key:  key,
+		done: make(chan struct{}),
+	}
+	if state == AnalysisRunRunning {
+		run.StartedAt = now
+	}
+	q.runs[run.ID] = run
+	q.inflight[key] = run
+
+	go q.execute(run, slots, analyze)
+	return run.AnalysisRun, nil
+}
+
+// execute waits for a slot if the run is queued, runs the analysis and
+// records its outcome
+func (q *AnalysisQueue) execute(run *analysisRun, slots chan struct{}, analyze func(context.Context) (interface{}, error)) {
+	q.mu.Lock()
+	queued := run.State == AnalysisRunQueued
+	q.mu.Unlock()
+
+	var result interface{}
+	var err error
+	if queued {
+		select {
+		case slots <- struct{}{}:
+			q.mu.Lock()
+			q.queued[run.Cluster]--
+			run.State = AnalysisRunRunning
+			run.StartedAt = q.now()
+			q.mu.Unlock()
+		case <-q.ctx.Done():
+			q.mu.Lock()
+			q.queued[run.Cluster]--
+			q.mu.Unlock()
+			err = q.ctx.Err()
+		}
+	}
+	if err == nil {
+		klog.V(2).Infof("Running %s analysis %s for cluster %s", run.Kind, run.ID, run.Cluster)
+		result, err = analyze(q.ctx)
+		<-slots
+	}
+
+	q.mu.Lock()
+	run.FinishedAt = q.now()
+	if err != nil {
+		run.State = AnalysisRunFailed
+		run.Error = err.Error()
+	} else {
+		run.State = AnalysisRunSucceeded
+		run.Result = result
+	}
+	delete(q.inflight, run.key)
+	finished := run.AnalysisRun
+	close(run.done)
+	q.mu.Unlock()
+
+	if q.onFinish != nil {
+		q.onFinish(finished)
+	}
+}
+
+// Wait blocks until a run finishes or ctx is done, returning the run as it
+// then stands
+func (q *AnalysisQueue) Wait(ctx context.Context, id string) (AnalysisRun, error) {
+	q.mu.Lock()
+	run, ok := q.runs[id]
+	q.mu.Unlock()
+	if !ok {
+		return AnalysisRun{}, fmt.Errorf("%w: %s", ErrAnalysisRunNotFound, id)
+	}
+
+	select {
+	case <-run.done:
+	case <-ctx.Done():
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return run.AnalysisRun, nil
+}
+
+// Get returns a run by ID
+func (q *AnalysisQueue) Get(id string) (AnalysisRun, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	run, ok := q.runs[id]
+	if !ok {
+		return AnalysisRun{}, fmt.Errorf("%w: %s", ErrAnalysisRunNotFound, id)
+	}
+	return run.AnalysisRun, nil
+}
+
+// List returns the runs of a cluster, newest first, without their results;
+// an empty cluster returns every run
+func (q *AnalysisQueue) List(cluster string) []AnalysisRun {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.prune()
+
+	runs := make([]AnalysisRun, 0, len(q.runs))
+	for _, run := range q.runs {
+		if cluster == "" || run.Cluster == cluster {
+			summary := run.AnalysisRun
+			summary.Result = nil
+			runs = append(runs, summary)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].QueuedAt.Equal(runs[j].QueuedAt) {
+			return runs[i].QueuedAt.After(runs[j].QueuedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	return runs
+}
+
+// prune forgets runs finished longer than analysisRunRetention ago
+func (q *AnalysisQueue) prune() {
+	cutoff := q.now().Add(-analysisRunRetention)
+	for id, run := range q.runs {
+		if run.Finished() && run.FinishedAt.Before(cutoff) {
+			delete(q.runs, id)
+		}
+	}
+}
+
+// SubmitClusterAnalysis queues AI insights for the whole cluster, sharing
+// an analysis already queued or running. A test mode scenario ctx asks for
+// is kept for the run.
+func (e *Engine) SubmitClusterAnalysis(ctx context.Context) (AnalysisRun, error) {
+	if e.aiClient == nil {
+		return AnalysisRun{}, ErrAIDisabled
+	}
+	scenario := ai.ScenarioFrom(ctx)
+	run, err := e.analysisQueue.Submit(e.currentContext, AnalysisKindCluster, nil, e.StateRevision(), scenario, func(ctx context.Context) (interface{}, error) {
+		return e.analyzeCluster(withScenario(ctx, scenario))
+	})
+	return e.markStale(run), err
+}
+
+// SubmitBatchAnalysis queues a consolidated analysis of the named checks,
+// or of every failing check without names. Requests resolving to the same
+// checks share one run. Unknown checks and oversized batches are refused
+// before anything is queued. A test mode scenario ctx asks for is kept for
+// the run.
+func (e *Engine) SubmitBatchAnalysis(ctx context.Context, names []string) (AnalysisRun, error) {
+	if e.aiClient == nil {
+		return AnalysisRun{}, ErrAIDisabled
+	}
+	results, err := e.batchResults(names)
+	if err != nil {
+		return AnalysisRun{}, err
+	}
+	checks := make([]string, len(results))
+	for i, result := range results {
+		checks[i] = result.Name
+	}
+	revision := e.StateRevision()
+	scenario := ai.ScenarioFrom(ctx)
+	run, err := e.analysisQueue.Submit(e.currentContext, AnalysisKindBatch, checks, revision, scenario, func(ctx context.Context) (interface{}, error) {
+		analysis, err := e.analyzeBatchResults(withScenario(ctx, scenario), results)
+		if err == nil {
+			analysis.StateRevision = revision
+		}
+		return analysis, err
+	})
+	return e.markStale(run), err
+}
+
+// withScenario carries a test mode scenario into a run's context
+func withScenario(ctx context.Context, scenario string) context.Context {
+	if scenario == "" {
+		return ctx
+	}
+	return ai.WithScenario(ctx, scenario)
+}
+
+// WaitAnalysis blocks until an analysis run finishes or ctx is done
+func (e *Engine) WaitAnalysis(ctx context.Context, id string) (AnalysisRun, error) {
+	run, err := e.analysisQueue.Wait(ctx, id)
+	return e.markStale(run), err
+}
+
+// GetAnalysisRun returns an on-demand analysis run by ID
+func (e *Engine) GetAnalysisRun(id string) (AnalysisRun, error) {
+	run, err := e.analysisQueue.Get(id)
+	return e.markStale(run), err
+}
+
+// ListAnalysisRuns returns the current cluster's analysis runs, newest first
+func (e *Engine) ListAnalysisRuns() []AnalysisRun {
+	runs := e.analysisQueue.List(e.currentContext)
+	for i, run := range runs {
+		runs[i] = e.markStale(run)
+	}
+	return runs
+}
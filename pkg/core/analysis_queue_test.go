@@ -0,0 +1,157 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAnalysisQueue_SharesInflightRuns(t *testing.T) {
+	queue := NewAnalysisQueue(context.Background(), 2, nil)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	analyze := func(context.Context) (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "insights", nil
+	}
+
+	first, err := queue.Submit("prod", AnalysisKindCluster, nil, 0, "", analyze)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := queue.Submit("prod", AnalysisKindCluster, nil, 0, "", analyze)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ID != first.ID || second.Requests != 2 {
+		t.Fatalf("expected the second request to share run %s, got %+v", first.ID, second)
+	}
+	other, _ := queue.Submit("staging", AnalysisKindCluster, nil, 0, "", analyze)
+	if other.ID == first.ID {
+		t.Fatal("expected another cluster to get its own run")
+	}
+	newer, _ := queue.Submit("prod", AnalysisKindCluster, nil, 1, "", analyze)
+	if newer.ID == first.ID || newer.StateRevision != 1 {
+		t.Fatalf("expected a request at a newer state revision to get its own run, got %+v", newer)
+	}
+
+	close(release)
+	run, err := queue.Wait(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = queue.Wait(context.Background(), other.ID)
+	_, _ = queue.Wait(context.Background(), newer.ID)
+	if run.State != AnalysisRunSucceeded || run.Result != "insights" || calls.Load() != 3 {
+		t.Errorf("expected one succeeded analysis per cluster and revision, got %+v after %d calls", run, calls.Load())
+	}
+
+	// A finished run isn't shared with later requests
+	next, _ := queue.Submit("prod", AnalysisKindCluster, nil, 0, "", analyze)
+	if next.ID == first.ID {
+		t.Error("expected a new run once the previous one finished")
+	}
+}
+
+func TestAnalysisQueue_BoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var finished []AnalysisRun
+	done := make(chan struct{}, MaxQueuedAnalyses+2)
+	queue := NewAnalysisQueue(context.Background(), 1, func(run AnalysisRun) {
+		mu.Lock()
+		finished = append(finished, run)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	release := make(chan struct{})
+	analyze := func(context.Context) (interface{}, error) {
+		<-release
+		return nil, errors.New("model unavailable")
+	}
+
+	running, _ := queue.Submit("prod", AnalysisKindBatch, []string{"pod-health"}, 0, "", analyze)
+	if running.State != AnalysisRunRunning {
+		t.Fatalf("expected the first run to start, got %s", running.State)
+	}
+	queued, _ := queue.Submit("prod", AnalysisKindBatch, []string{"node-health"}, 0, "", analyze)
+	if queued.State != AnalysisRunQueued {
+		t.Fatalf("expected the second run to queue, got %s", queued.State)
+	}
+	for i := 1; i < MaxQueuedAnalyses; i++ {
+		if _, err := queue.Submit("prod", AnalysisKindBatch, []string{string(rune('a' + i))}, 0, "", analyze); err != nil {
+			t.Fatalf("unexpected error queueing run %d: %v", i, err)
+		}
+	}
+	if _, err := queue.Submit("prod", AnalysisKindBatch, []string{"full"}, 0, "", analyze); !errors.Is(err, ErrAnalysisQueueFull) {
+		t.Fatalf("expected ErrAnalysisQueueFull, got %v", err)
+	}
+
+	close(release)
+	for i := 0; i < MaxQueuedAnalyses+1; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for runs to finish")
+		}
+	}
+	run, err := queue.Get(queued.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.State != AnalysisRunFailed || run.Error != "model unavailable" || run.StartedAt.IsZero() {
+		t.Errorf("expected the queued run to start and fail, got %+v", run)
+	}
+	if len(finished) != MaxQueuedAnalyses+1 {
+		t.Errorf("expected every run to be reported, got %d", len(finished))
+	}
+}
+
+func TestAnalysisQueue_ListAndPrune(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	queue := NewAnalysisQueue(context.Background(), 2, nil)
+	queue.now = func() time.Time { return now }
+	analyze := func(context.Context) (interface{}, error) { return "insights", nil }
+
+	old, _ := queue.Submit("prod", AnalysisKindCluster, nil, 0, "", analyze)
+	if _, err := queue.Wait(context.Background(), old.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(time.Minute)
+	recent, _ := queue.Submit("prod", AnalysisKindCluster, nil, 0, "", analyze)
+	_, _ = queue.Wait(context.Background(), recent.ID)
+	staging, _ := queue.Submit("staging", AnalysisKindCluster, nil, 0, "", analyze)
+	_, _ = queue.Wait(context.Background(), staging.ID)
+
+	runs := queue.List("prod")
+	if len(runs) != 2 || runs[0].ID != recent.ID || runs[1].ID != old.ID {
+		t.Fatalf("expected prod runs newest first, got %+v", runs)
+	}
+	if runs[0].Result != nil {
+		t.Error("expected listed runs without results")
+	}
+	if len(queue.List("")) != 3 {
+		t.Error("expected every run without a cluster")
+	}
+
+	now = now.Add(analysisRunRetention)
+	if runs := queue.List("prod"); len(runs) != 1 || runs[0].ID != recent.ID {
+		t.Errorf("expected runs finished over an hour ago to be forgotten, got %+v", runs)
+	}
+	if _, err := queue.Get(old.ID); !errors.Is(err, ErrAnalysisRunNotFound) {
+		t.Errorf("expected ErrAnalysisRunNotFound, got %v", err)
+	}
+}
+
+func TestEngine_SubmitAnalysisWithoutAI(t *testing.T) {
+	engine := NewEngine(EngineConfig{ContextName: "prod"})
+	if _, err := engine.SubmitClusterAnalysis(context.Background()); !errors.Is(err, ErrAIDisabled) {
+		t.Errorf("expected ErrAIDisabled, got %v", err)
+	}
+	if _, err := engine.SubmitBatchAnalysis(context.Background(), []string{"pod-health"}); !errors.Is(err, ErrAIDisabled) {
+		t.Errorf("expected ErrAIDisabled, got %v", err)
+	}
+}
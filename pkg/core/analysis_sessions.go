@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+// ErrAnalysisSessionNotFound is returned when a compared session ID or
+// timestamp doesn't match a recorded analysis
+var ErrAnalysisSessionNotFound = errors.New("analysis session not found")
+
+// defaultAnalysisSessions is how many cluster analyses are kept for comparison
+const defaultAnalysisSessions = 100
+
+// AnalysisLog is a bounded record of cluster analyses, oldest first
+type AnalysisLog struct {
+	capacity int
+	mu       sync.RWMutex
+	sessions []ai.AnalysisSession
+	nextID   int
+}
+
+// NewAnalysisLog creates an analysis log retaining up to capacity sessions
+func NewAnalysisLog(capacity int) *AnalysisLog {
+	if capacity <= 0 {
+		capacity = defaultAnalysisSessions
+	}
+	return &AnalysisLog{
+		capacity: capacity,
+		sessions: make([]ai.AnalysisSession, 0),
+	}
+}
+
+// Record stores a snapshot of a cluster analysis and returns its session
+func (l *AnalysisLog) Record(health *ai.ClusterHealth, summary *ai.InsightSummary) ai.AnalysisSession {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	session := ai.NewAnalysisSession(fmt.Sprintf("analysis-%d", l.nextID), health, summary)
+	l.sessions = append(l.sessions, session)
+	if len(l.sessions) > l.capacity {
+		l.sessions = l.sessions[len(l.sessions)-l.capacity:]
+	}
+	return session
+}
+
+// List returns the sessions for a cluster, oldest first; an empty cluster
+// returns all sessions
+func (l *AnalysisLog) List(cluster string) []ai.AnalysisSession {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	sessions := make([]ai.AnalysisSession, 0)
+	for _, session := range l.sessions {
+		if cluster == "" || session.Cluster == cluster {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// Find resolves a session ID, or an RFC 3339 timestamp to the latest
+// session taken at or before it
+func (l *AnalysisLog) Find(cluster, ref string) (ai.AnalysisSession, error) {
+	sessions := l.List(cluster)
+	for _, session := range sessions {
+		if session.ID == ref {
+			return session, nil
+		}
+	}
+
+	at, err := time.Parse(time.RFC3339, ref)
+	if err != nil {
+		return ai.AnalysisSession{}, fmt.Errorf("%w: %s", ErrAnalysisSessionNotFound, ref)
+	}
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if !sessions[i].CreatedAt.After(at) {
+			return sessions[i], nil
+		}
+	}
+	return ai.AnalysisSession{}, fmt.Errorf("%w: no analysis at or before %s", ErrAnalysisSessionNotFound, ref)
+}
+
+// GetAnalysisSessions returns recorded cluster analyses, oldest first
+func (e *Engine) GetAnalysisSessions(cluster string) []ai.AnalysisSession {
+	return e.analyses.List(cluster)
+}
+
+// CompareAnalyses diffs two recorded analyses of a cluster, identified by
+// session ID or timestamp, optionally with an AI-written narrative
+func (e *Engine) CompareAnalyses(ctx context.Context, cluster, from, to string, narrate bool) (*ai.AnalysisDiff, error) {
+	if cluster == "" {
+		cluster = e.currentContext
+	}
+
+	fromSession, err := e.analyses.Find(cluster, from)
+	if err != nil {
+		return nil, err
+	}
+	toSession, err := e.analyses.Find(cluster, to)
+	if err != nil {
+		return nil, err
+	}
+	if toSession.CreatedAt.Before(fromSession.CreatedAt) {
+		fromSession, toSession = toSession, fromSession
+	}
+
+	diff := ai.CompareSessions(fromSession, toSession)
+	if narrate && e.aiClient != nil {
+		narrative, err := e.aiClient.NarrateComparison(ctx, diff)
+		if err != nil {
+			klog.Warningf("AI comparison narrative failed: %v", err)
+			diff.NarrativeError = err.Error()
+		} else {
+			diff.Narrative = narrative
+		}
+	}
+	return diff, nil
+}
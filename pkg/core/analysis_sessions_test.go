@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAnalysisLog_Find(t *testing.T) {
+	log := NewAnalysisLog(2)
+	first := log.Record(&ai.ClusterHealth{ClusterName: "prod"}, nil)
+	second := log.Record(&ai.ClusterHealth{ClusterName: "staging"}, nil)
+	third := log.Record(&ai.ClusterHealth{ClusterName: "prod"}, nil)
+
+	if _, err := log.Find("", first.ID); !errors.Is(err, ErrAnalysisSessionNotFound) {
+		t.Errorf("expected the oldest session to be evicted, got %v", err)
+	}
+	if got := log.List("prod"); len(got) != 1 || got[0].ID != third.ID {
+		t.Errorf("expected one prod session, got %+v", got)
+	}
+	if _, err := log.Find("prod", second.ID); !errors.Is(err, ErrAnalysisSessionNotFound) {
+		t.Error("expected sessions of other clusters not to match")
+	}
+
+	at := third.CreatedAt.Add(time.Minute).Format(time.RFC3339)
+	if got, err := log.Find("prod", at); err != nil || got.ID != third.ID {
+		t.Errorf("expected timestamp to select the latest earlier session, got %+v (%v)", got, err)
+	}
+	before := third.CreatedAt.Add(-time.Hour).Format(time.RFC3339)
+	if _, err := log.Find("prod", before); !errors.Is(err, ErrAnalysisSessionNotFound) {
+		t.Errorf("expected no session before the first analysis, got %v", err)
+	}
+}
+
+func TestEngine_CompareAnalyses(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient:  fake.NewSimpleClientset(),
+		ContextName: "prod",
+		EnableAI:    true,
+		AIConfig:    &ai.Config{TestMode: true},
+	})
+	defer engine.Stop()
+
+	for i := 0; i < 2; i++ {
+		if _, err := engine.GetAIInsights(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	sessions := engine.GetAnalysisSessions("prod")
+	if len(sessions) != 2 {
+		t.Fatalf("expected a session per analysis, got %d", len(sessions))
+	}
+
+	diff, err := engine.CompareAnalyses(context.Background(), "", sessions[1].ID, sessions[0].ID, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.From.ID != sessions[0].ID || diff.To.ID != sessions[1].ID {
+		t.Errorf("expected sessions ordered oldest first, got %s -> %s", diff.From.ID, diff.To.ID)
+	}
+	if diff.Narrative == "" {
+		t.Error("expected an AI narrative")
+	}
+
+	if _, err := engine.CompareAnalyses(context.Background(), "", "analysis-99", sessions[0].ID, false); !errors.Is(err, ErrAnalysisSessionNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DetailAnnotations holds the annotations in effect for a result's check
+// when the result was produced
+const DetailAnnotations = "annotations"
+
+const (
+	// maxAnnotations bounds the annotations kept; those that ended longest
+	// ago are dropped first
+	maxAnnotations = 1000
+
+	// annotationContextWindow is how long after an annotation ends it is
+	// still given to AI analyses, since its effects can outlast it
+	annotationContextWindow = time.Hour
+)
+
+// ErrAnnotationNotFound is returned when deleting an unknown annotation
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// Annotation is an operator's note on a time range of a cluster, such as
+// "load test running" or "migration window", for some or all of its checks.
+// Results produced during it carry the note, their metrics don't flag
+// anomalies, and AI analyses of the period are told about it.
+type Annotation struct {
+	ID      string    `json:"id"`
+	Cluster string    `json:"cluster"`
+	Checks  []string  `json:"checks,omitempty"` // Empty means every check
+	Note    string    `json:"note"`
+	Author  string    `json:"author,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Created time.Time `json:"created"`
+}
+
+// Covers reports whether the annotation applies to a check at a time
+func (a Annotation) Covers(check string, at time.Time) bool {
+	return !at.Before(a.Start) && at.Before(a.End) && a.appliesTo(check)
+}
+
+// appliesTo reports whether the annotation applies to a check; an empty
+// check matches any annotation
+func (a Annotation) appliesTo(check string) bool {
+	if check == "" || len(a.Checks) == 0 {
+		return true
+	}
+	for _, name := range a.Checks {
+		if name == check {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the annotation for AI prompts
+func (a Annotation) String() string {
+	scope := "all checks"
+	if len(a.Checks) > 0 {
+		scope = strings.Join(a.Checks, ", ")
+	}
+	by := ""
+	if a.Author != "" {
+		by = " by " + a.Author
+	}
+	return fmt.Sprintf("%q%s for %s from %s to %s", a.Note, by, scope,
+		a.Start.UTC().Format(time.RFC3339), a.End.UTC().Format(time.RFC3339))
+}
+
+// annotationLog holds the operator annotations, oldest start first
+type annotationLog struct {
+	mu          sync.Mutex
+	annotations []Annotation
+	sequence    int
+}
+
+// AddAnnotation records an annotation. The cluster defaults to the current
+// context and the start to now; the end is required.
+func (e *Engine) AddAnnotation(annotation Annotation) (Annotation, error) {
+	now := time.Now()
+	annotation.Note = strings.TrimSpace(annotation.Note)
+	annotation.Author = strings.TrimSpace(annotation.Author)
+	if annotation.Cluster == "" {
+		annotation.Cluster = e.currentContext
+	}
+	if annotation.Start.IsZero() {
+		annotation.Start = now
+	}
+	switch {
+	case annotation.Note == "":
+		return Annotation{}, fmt.Errorf("annotation needs a note")
+	case annotation.End.IsZero():
+		return Annotation{}, fmt.Errorf("annotation needs an end time")
+	case !annotation.End.After(annotation.Start):
+		return Annotation{}, fmt.Errorf("annotation must end after it starts")
+	}
+	checks := make([]string, 0, len(annotation.Checks))
+	seen := make(map[string]bool)
+	for _, check := range annotation.Checks {
+		if check = strings.TrimSpace(check); check == "" || seen[check] {
+			continue
+		}
+		// Checks of other clusters aren't known here
+		if annotation.Cluster == e.currentContext && !e.knownCheck(check) {
+			return Annotation{}, fmt.Errorf("%w: %s", ErrCheckNotFound, check)
+		}
+		seen[check] = true
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	annotation.Checks = checks
+	annotation.Created = now
+
+	e.annotations.mu.Lock()
+	e.annotations.sequence++
+	annotation.ID = fmt.Sprintf("annotation-%d-%d", now.Unix(), e.annotations.sequence)
+	e.annotations.annotations = append(e.annotations.annotations, annotation)
+	sort.SliceStable(e.annotations.annotations, func(i, j int) bool {
+		return e.annotations.annotations[i].Start.Before(e.annotations.annotations[j].Start)
+	})
+	e.pruneAnnotations()
+	e.annotations.mu.Unlock()
+
+	if annotation.Cluster == e.currentContext {
+		e.changes.Record(Change{
+			Timestamp: now,
+			Kind:      ChangeKindAnnotation,
+			Resource:  "annotation/" + annotation.ID,
+			Message:   fmt.Sprintf("Annotated %s", annotation),
+		})
+	}
+	klog.Infof("Annotation %s added for cluster %s: %s", annotation.ID, annotation.Cluster, annotation.Note)
+	return annotation, nil
+}
+
+// pruneAnnotations drops the annotations that ended longest ago beyond
+// maxAnnotations; the caller holds the lock
+func (e *Engine) pruneAnnotations() {
+	excess := len(e.annotations.annotations) - maxAnnotations
+	if excess <= 0 {
+		return
+	}
+	byEnd := make([]Annotation, len(e.annotations.annotations))
+	copy(byEnd, e.annotations.annotations)
+	sort.SliceStable(byEnd, func(i, j int) bool { return byEnd[i].End.Before(byEnd[j].End) })
+	dropped := make(map[string]bool, excess)
+	for _, annotation := range byEnd[:excess] {
+		dropped[annotation.ID] = true
+	}
+	kept := e.annotations.annotations[:0]
+	for _, annotation := range e.annotations.annotations {
+		if !dropped[annotation.ID] {
+			kept = append(kept, annotation)
+		}
+	}
+	e.annotations.annotations = kept
+}
+
+// DeleteAnnotation removes an annotation
+func (e *Engine) DeleteAnnotation(id string) (Annotation, error) {
+	e.annotations.mu.Lock()
+	defer e.annotations.mu.Unlock()
+	for i, annotation := range e.annotations.annotations {
+		if annotation.ID == id {
+			e.annotations.annotations = append(e.annotations.annotations[:i], e.annotations.annotations[i+1:]...)
+			return annotation, nil
+		}
+	}
+	return Annotation{}, fmt.Errorf("%w: %s", ErrAnnotationNotFound, id)
+}
+
+// GetAnnotations lists a cluster's annotations overlapping from and to that
+// apply to a check, oldest start first. An empty cluster means every
+// cluster, an empty check every check and a zero time an open range.
+func (e *Engine) GetAnnotations(cluster, check string, from, to time.Time) []Annotation {
+	e.annotations.mu.Lock()
+	defer e.annotations.mu.Unlock()
+
+	annotations := make([]Annotation, 0)
+	for _, annotation := range e.annotations.annotations {
+		switch {
+		case cluster != "" && annotation.Cluster != cluster,
+			!from.IsZero() && !annotation.End.After(from),
+			!to.IsZero() && annotation.Start.After(to),
+			!annotation.appliesTo(check):
+			continue
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
+
+// withAnnotations returns the result marked with the current cluster's
+// annotations covering it. Details are copied, so the check's own details
+// are not modified.
+func (e *Engine) withAnnotations(result CheckResult) CheckResult {
+	at := result.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var covering []Annotation
+	for _, annotation := range e.GetAnnotations(e.currentContext, result.Name, at, at) {
+		if annotation.Covers(result.Name, at) {
+			covering = append(covering, annotation)
+		}
+	}
+	if len(covering) == 0 {
+		return result
+	}
+
+	details := make(map[string]interface{}, len(result.Details)+1)
+	for key, value := range result.Details {
+		details[key] = value
+	}
+	details[DetailAnnotations] = covering
+	result.Details = details
+	return result
+}
+
+// annotationNotes describes the current cluster's annotations for a check
+// that overlap the period leading up to at, for AI prompts
+func (e *Engine) annotationNotes(check string, at time.Time) []string {
+	annotations := e.GetAnnotations(e.currentContext, check, at.Add(-annotationContextWindow), at)
+	if len(annotations) == 0 {
+		return nil
+	}
+	notes := make([]string, len(annotations))
+	for i, annotation := range annotations {
+		notes[i] = annotation.String()
+	}
+	return notes
+}
+
+// AnnotationsOf returns the annotations a result was marked with, including
+// results decoded from JSON
+func AnnotationsOf(result CheckResult) []Annotation {
+	switch annotations := result.Details[DetailAnnotations].(type) {
+	case []Annotation:
+		return annotations
+	case []interface{}:
+		data, err := json.Marshal(annotations)
+		if err != nil {
+			return nil
+		}
+		var decoded []Annotation
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_AddAnnotationValidation(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod-eu"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		annotation Annotation
+		wantErr    error
+	}{
+		{"valid", Annotation{Note: "load test running", Checks: []string{"pod-health"}, End: now.Add(time.Hour)}, nil},
+		{"past range", Annotation{Note: "migration window", Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}, nil},
+		{"other cluster's check", Annotation{Cluster: "prod-us", Note: "load test", Checks: []string{"dns-health"}, End: now.Add(time.Hour)}, nil},
+		{"unknown check", Annotation{Note: "load test", Checks: []string{"dns-health"}, End: now.Add(time.Hour)}, ErrCheckNotFound},
+		{"no note", Annotation{Note: "  ", End: now.Add(time.Hour)}, errors.New("annotation needs a note")},
+		{"no end", Annotation{Note: "load test"}, errors.New("annotation needs an end time")},
+		{"ends before start", Annotation{Note: "load test", Start: now, End: now.Add(-time.Minute)}, errors.New("annotation must end after it starts")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotation, err := engine.AddAnnotation(tt.annotation)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr == nil && (annotation.ID == "" || annotation.Cluster == "" || annotation.Start.IsZero()):
+				t.Errorf("expected the ID, cluster and start set, got %+v", annotation)
+			case tt.wantErr != nil && (err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error())):
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEngine_GetAnnotations(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod-eu"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+	now := time.Now()
+
+	add := func(annotation Annotation) Annotation {
+		t.Helper()
+		added, err := engine.AddAnnotation(annotation)
+		if err != nil {
+			t.Fatalf("AddAnnotation() error = %v", err)
+		}
+		return added
+	}
+	loadTest := add(Annotation{Note: "load test running", Checks: []string{"pod-health"}, Start: now.Add(-time.Hour), End: now.Add(time.Hour)})
+	migration := add(Annotation{Note: "migration window", Start: now.Add(-3 * time.Hour), End: now.Add(-2 * time.Hour)})
+	add(Annotation{Cluster: "prod-us", Note: "load test", Start: now.Add(-time.Hour), End: now.Add(time.Hour)})
+
+	tests := []struct {
+		name     string
+		cluster  string
+		check    string
+		from, to time.Time
+		want     []string
+	}{
+		{"all", "", "", time.Time{}, time.Time{}, []string{migration.ID, loadTest.ID, ""}},
+		{"cluster", "prod-eu", "", time.Time{}, time.Time{}, []string{migration.ID, loadTest.ID}},
+		{"check", "prod-eu", "node-health", time.Time{}, time.Time{}, []string{migration.ID}},
+		{"range", "prod-eu", "", now.Add(-90 * time.Minute), now, []string{loadTest.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := engine.GetAnnotations(tt.cluster, tt.check, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d annotations, got %+v", len(tt.want), got)
+			}
+			for i, id := range tt.want {
+				if id != "" && got[i].ID != id {
+					t.Errorf("expected %s at %d, got %s", id, i, got[i].ID)
+				}
+			}
+		})
+	}
+
+	if _, err := engine.DeleteAnnotation(migration.ID); err != nil {
+		t.Fatalf("DeleteAnnotation() error = %v", err)
+	}
+	if _, err := engine.DeleteAnnotation(migration.ID); !errors.Is(err, ErrAnnotationNotFound) {
+		t.Errorf("expected ErrAnnotationNotFound, got %v", err)
+	}
+}
+
+func TestEngine_AnnotatedResults(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod-eu"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+
+	var seen []CheckResult
+	engine.OnCheckResult("test", func(result CheckResult) { seen = append(seen, result) })
+
+	metric := func(value float64) []Metric {
+		return []Metric{{Name: "restarts", Value: value, Timestamp: time.Now()}}
+	}
+	for i := 0; i < 20; i++ {
+		engine.processResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: time.Now(), Metrics: metric(float64(10 + i%2))})
+	}
+	if _, err := engine.AddAnnotation(Annotation{Note: "load test running", Author: "alice", Checks: []string{"pod-health"}, End: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("AddAnnotation() error = %v", err)
+	}
+
+	spike := engine.withAnnotations(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: time.Now(), Metrics: metric(500)})
+	if annotations := AnnotationsOf(spike); len(annotations) != 1 || annotations[0].Note != "load test running" {
+		t.Fatalf("expected the result marked with the annotation, got %+v", spike.Details)
+	}
+	engine.processResult(spike)
+	if last := seen[len(seen)-1]; len(last.Predictions) != 0 {
+		t.Errorf("expected no anomaly during an annotated period, got %+v", last.Predictions)
+	}
+
+	if other := engine.withAnnotations(CheckResult{Name: "node-health", Timestamp: time.Now()}); len(AnnotationsOf(other)) != 0 {
+		t.Errorf("expected other checks left unmarked, got %+v", other.Details)
+	}
+	notes := engine.buildDiagnosticContext(spike).Annotations
+	if len(notes) != 1 || !strings.HasPrefix(notes[0], `"load test running" by alice for pod-health`) {
+		t.Errorf("expected the annotation in the AI context, got %q", notes)
+	}
+}
+
+func TestAnnotationsOf_DecodedJSON(t *testing.T) {
+	result := CheckResult{Details: map[string]interface{}{DetailAnnotations: []interface{}{
+		map[string]interface{}{"id": "annotation-1-1", "note": "migration window", "end": "2026-01-02T15:04:05Z"},
+	}}}
+	annotations := AnnotationsOf(result)
+	if len(annotations) != 1 || annotations[0].Note != "migration window" || annotations[0].End.IsZero() {
+		t.Errorf("expected the decoded annotation, got %+v", annotations)
+	}
+	if len(AnnotationsOf(CheckResult{})) != 0 {
+		t.Error("expected no annotations without the detail")
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// ErrCheckNotFound is returned when a named health check has no result
+	ErrCheckNotFound = errors.New("health check not found")
+
+	// ErrAIDisabled is returned by AI operations when AI is not enabled
+	ErrAIDisabled = errors.New("AI client not enabled")
+
+	// ErrBatchTooLarge is returned when a batch analysis would cover more
+	// than MaxBatchChecks checks
+	ErrBatchTooLarge = errors.New("too many checks for one batch analysis")
+)
+
+// MaxBatchChecks bounds how many checks one batch analysis covers, keeping
+// the consolidated prompt within what the model can reason about
+const MaxBatchChecks = 20
+
+// batchAnalysisTimeout bounds a batch analysis, which runs longer than a
+// single check's diagnosis
+const batchAnalysisTimeout = 2 * time.Minute
+
+// AnalyzeBatch diagnoses several checks with one AI call and correlates their
+// failures. Without names, every check that isn't healthy is analyzed.
+func (e *Engine) AnalyzeBatch(ctx context.Context, names []string) (*ai.BatchAnalysis, error) {
+	if e.aiClient == nil {
+		return nil, ErrAIDisabled
+	}
+
+	results, err := e.batchResults(names)
+	if err != nil {
+		return nil, err
+	}
+	return e.analyzeBatchResults(ctx, results)
+}
+
+// analyzeBatchResults diagnoses resolved check results with one AI call
+func (e *Engine) analyzeBatchResults(ctx context.Context, results []CheckResult) (*ai.BatchAnalysis, error) {
+	if len(results) == 0 {
+		return &ai.BatchAnalysis{Checks: []string{}, Diagnoses: []ai.CheckDiagnosis{}, Timestamp: time.Now()}, nil
+	}
+
+	checks := make([]ai.CheckResult, len(results))
+	for i, result := range results {
+		checks[i] = e.convertToAICheckResult(result)
+	}
+
+	klog.V(2).Infof("Running AI batch analysis for %d checks", len(results))
+	ctx, cancel := context.WithTimeout(ctx, batchAnalysisTimeout)
+	defer cancel()
+	analysis, err := e.aiClient.AnalyzeBatch(ctx, checks, e.buildBatchContext(ctx, results))
+	if err != nil {
+		return nil, err
+	}
+	for _, diagnosis := range analysis.Diagnoses {
+		e.findings.ObserveDiagnosis(diagnosis.Check, diagnosis.Findings)
+	}
+	return analysis, nil
+}
+
+// batchResults returns the results of the named checks, or of every failing
+// check when no names are given, sorted by name
+func (e *Engine) batchResults(names []string) ([]CheckResult, error) {
+	e.resultsMu.RLock()
+	var results []CheckResult
+	var missing []string
+	if len(names) == 0 {
+		for _, result := range e.results {
+			if result.Status != HealthStatusHealthy {
+				results = append(results, result)
+			}
+		}
+	} else {
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			result, ok := e.results[name]
+			if !ok {
+				missing = append(missing, name)
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+	e.resultsMu.RUnlock()
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrCheckNotFound, missing)
+	}
+	if len(results) > MaxBatchChecks {
+		return nil, fmt.Errorf("%w: %d checks, at most %d", ErrBatchTooLarge, len(results), MaxBatchChecks)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
+
+// buildBatchContext creates the diagnostic context shared by every check in a
+// batch: their logs and events together, the checks outside the batch, and
+// the resources the batch implicates
+func (e *Engine) buildBatchContext(ctx context.Context, results []CheckResult) ai.DiagnosticContext {
+	inBatch := make(map[string]bool, len(results))
+	shared := ai.DiagnosticContext{ClusterName: e.currentContext}
+	var refs []ResourceRef
+	for _, result := range results {
+		inBatch[result.Name] = true
+		shared.ErrorLogs = append(shared.ErrorLogs, prefixed(result.Name, extractErrorLogs(result))...)
+		shared.Events = append(shared.Events, prefixed(result.Name, extractEvents(result))...)
+		shared.LogPatterns = append(shared.LogPatterns, extractLogPatterns(result)...)
+		shared.ExpectedDisruptions = append(shared.ExpectedDisruptions, ExpectedDisruptions(result)...)
+		refs = append(refs, ImplicatedResources(result)...)
+	}
+
+	e.resultsMu.RLock()
+	for _, result := range e.results {
+		shared.BlockedDeployments = append(shared.BlockedDeployments, blockedDeploymentLines(result)...)
+		shared.SchedulingFailures = append(shared.SchedulingFailures, schedulingFailureLines(result)...)
+		if !inBatch[result.Name] {
+			shared.RelatedChecks = append(shared.RelatedChecks, e.convertToAICheckResult(result))
+			shared.ExpectedDisruptions = append(shared.ExpectedDisruptions, ExpectedDisruptions(result)...)
+		}
+	}
+	e.resultsMu.RUnlock()
+	sort.Slice(shared.RelatedChecks, func(i, j int) bool { return shared.RelatedChecks[i].Name < shared.RelatedChecks[j].Name })
+	sort.Strings(shared.BlockedDeployments)
+	sort.Strings(shared.SchedulingFailures)
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, note := range e.annotationNotes(result.Name, now) {
+			if !seen[note] {
+				seen[note] = true
+				shared.Annotations = append(shared.Annotations, note)
+			}
+		}
+	}
+
+	shared.DescribedResources = e.describer.Describe(ctx, refs)
+	return shared
+}
+
+// prefixed labels each line with the check it came from
+func prefixed(check string, lines []string) []string {
+	labeled := make([]string, len(lines))
+	for i, line := range lines {
+		labeled[i] = fmt.Sprintf("[%s] %s", check, line)
+	}
+	return labeled
+}
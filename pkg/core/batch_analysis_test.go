@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_AnalyzeBatch(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if _, err := engine.AnalyzeBatch(context.Background(), nil); !errors.Is(err, ErrAIDisabled) {
+		t.Errorf("expected ErrAIDisabled without AI, got %v", err)
+	}
+
+	engine = NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+	})
+	engine.storeResult(CheckResult{Name: "service-health", Status: HealthStatusDegraded, Message: "2 services without endpoints"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "checkout crashlooping"})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+
+	batch, err := engine.AnalyzeBatch(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(batch.Checks, ","); got != "pod-health,service-health" {
+		t.Errorf("expected every failing check, got %s", got)
+	}
+
+	batch, err = engine.AnalyzeBatch(context.Background(), []string{"node-health", "node-health"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batch.Checks) != 1 || batch.Checks[0] != "node-health" {
+		t.Errorf("expected named checks once each, got %v", batch.Checks)
+	}
+
+	if _, err := engine.AnalyzeBatch(context.Background(), []string{"pod-health", "dns-health"}); !errors.Is(err, ErrCheckNotFound) {
+		t.Errorf("expected ErrCheckNotFound, got %v", err)
+	}
+
+	for i := 0; i <= MaxBatchChecks; i++ {
+		engine.storeResult(CheckResult{Name: fmt.Sprintf("custom-%d", i), Status: HealthStatusUnhealthy})
+	}
+	if _, err := engine.AnalyzeBatch(context.Background(), nil); !errors.Is(err, ErrBatchTooLarge) {
+		t.Errorf("expected ErrBatchTooLarge, got %v", err)
+	}
+}
+
+func TestEngine_BuildBatchContext(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod"})
+	pods := CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "checkout crashlooping"}
+	services := CheckResult{Name: "service-health", Status: HealthStatusDegraded, Message: "checkout has no endpoints"}
+	engine.storeResult(pods)
+	engine.storeResult(services)
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+
+	shared := engine.buildBatchContext(context.Background(), []CheckResult{pods, services})
+	if shared.ClusterName != "prod" {
+		t.Errorf("cluster = %s, want prod", shared.ClusterName)
+	}
+	want := []string{"[pod-health] checkout crashlooping", "[service-health] checkout has no endpoints"}
+	if strings.Join(shared.ErrorLogs, "|") != strings.Join(want, "|") {
+		t.Errorf("expected logs labeled by check, got %v", shared.ErrorLogs)
+	}
+	if len(shared.RelatedChecks) != 1 || shared.RelatedChecks[0].Name != "node-health" {
+		t.Errorf("expected only checks outside the batch as related, got %+v", shared.RelatedChecks)
+	}
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DetailBlockedDeployments lists workloads whose controllers can't create
+// pods because an admission webhook or a resource quota rejects them
+const DetailBlockedDeployments = "blocked_deployments"
+
+// Causes of a blocked deployment
+const (
+	BlockedByWebhook = "admission_webhook"
+	BlockedByQuota   = "resource_quota"
+)
+
+// DeploymentBlock is a workload whose pod creation is being rejected
+type DeploymentBlock struct {
+	Namespace string    `json:"namespace"`
+	Workload  string    `json:"workload"` // Kind/name of the controller, e.g. ReplicaSet/checkout-5c8b
+	Cause     string    `json:"cause"`    // BlockedByWebhook or BlockedByQuota
+	Blocker   string    `json:"blocker"`  // Webhook or quota name
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// String describes the block for people and AI prompts, e.g. "deployment
+// blocked by admission webhook policy.example.com: shop ReplicaSet/checkout-5c8b"
+func (b DeploymentBlock) String() string {
+	blocker := "resource quota " + b.Blocker
+	if b.Cause == BlockedByWebhook {
+		blocker = "admission webhook " + b.Blocker
+	}
+	return fmt.Sprintf("deployment blocked by %s: %s %s", blocker, b.Namespace, b.Workload)
+}
+
+// BlockedDeployments returns the blocked deployments a result reports,
+// including results decoded from JSON
+func BlockedDeployments(result CheckResult) []DeploymentBlock {
+	switch blocks := result.Details[DetailBlockedDeployments].(type) {
+	case []DeploymentBlock:
+		return blocks
+	case []interface{}:
+		data, err := json.Marshal(blocks)
+		if err != nil {
+			return nil
+		}
+		var decoded []DeploymentBlock
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
+
+// blockedDeploymentLines describes a result's blocked deployments for AI
+// context, with the admission error that explains each one
+func blockedDeploymentLines(result CheckResult) []string {
+	blocks := BlockedDeployments(result)
+	lines := make([]string, len(blocks))
+	for i, block := range blocks {
+		lines[i] = fmt.Sprintf("%s: %s", block, block.Message)
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlockedDeployments(t *testing.T) {
+	want := []DeploymentBlock{{
+		Namespace: "shop",
+		Workload:  "ReplicaSet/checkout-5c8b",
+		Cause:     BlockedByQuota,
+		Blocker:   "compute-resources",
+		Message:   "exceeded quota: compute-resources",
+		Count:     3,
+		LastSeen:  time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}}
+	result := CheckResult{
+		Name:    "event-rates",
+		Details: map[string]interface{}{DetailBlockedDeployments: want},
+	}
+	if got := BlockedDeployments(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("BlockedDeployments() = %v, want %v", got, want)
+	}
+
+	// Results read back from JSON, e.g. recordings, hold []interface{}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if got := BlockedDeployments(decoded); !reflect.DeepEqual(got, want) {
+		t.Errorf("BlockedDeployments() after JSON = %v, want %v", got, want)
+	}
+
+	if got := want[0].String(); got != "deployment blocked by resource quota compute-resources: shop ReplicaSet/checkout-5c8b" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := BlockedDeployments(CheckResult{}); got != nil {
+		t.Errorf("expected nil without blocked deployments, got %v", got)
+	}
+}
+
+func TestSchedulingFailures(t *testing.T) {
+	want := []SchedulingFailure{{
+		Namespace: "ml",
+		Pod:       "trainer-0",
+		Reasons: []SchedulingReason{
+			{Kind: SchedulingNodeSelector, Nodes: 3, Total: 3, Detail: "gpu=true + topology.kubernetes.io/zone=us-east-1a"},
+		},
+	}}
+	result := CheckResult{Name: "pod-health", Details: map[string]interface{}{DetailSchedulingFailures: want}}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if got := SchedulingFailures(decoded); !reflect.DeepEqual(got, want) {
+		t.Errorf("SchedulingFailures() after JSON = %v, want %v", got, want)
+	}
+
+	lines := schedulingFailureLines(result)
+	if len(lines) != 1 || lines[0] != "pod ml/trainer-0 pending because no node has gpu=true + topology.kubernetes.io/zone=us-east-1a" {
+		t.Errorf("unexpected scheduling failure lines %v", lines)
+	}
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// CardinalityAction is what happens to a label value beyond its limit
+type CardinalityAction string
+
+const (
+	// CardinalityHash replaces new values with one of a fixed set of
+	// hash buckets, keeping the series but bounding the label
+	CardinalityHash CardinalityAction = "hash"
+	// CardinalityDrop removes the label from the metric
+	CardinalityDrop CardinalityAction = "drop"
+)
+
+// Default cardinality limits
+const (
+	DefaultMaxSeries      = 1000 // Series kept per metric name
+	DefaultMaxLabelValues = 100  // Distinct values kept per label of a metric
+	DefaultHashBuckets    = 16   // Buckets values beyond the limit are hashed into
+)
+
+// hashedValuePrefix marks label values replaced by their hash bucket
+const hashedValuePrefix = "hashed-"
+
+// CardinalityLimit bounds the series of one metric; zero fields fall back to
+// the limiter's defaults
+type CardinalityLimit struct {
+	MaxSeries      int
+	MaxLabelValues int
+}
+
+// CardinalityConfig configures a CardinalityLimiter
+type CardinalityConfig struct {
+	MaxSeries      int               // Series per metric; new series beyond it are dropped
+	MaxLabelValues int               // Distinct values per label before Action applies
+	Action         CardinalityAction // hash (default) or drop
+	HashBuckets    int               // Buckets hashed values fall into
+	Overrides      map[string]CardinalityLimit
+}
+
+// MetricCardinality is the cardinality seen for one metric
+type MetricCardinality struct {
+	Metric         string         `json:"metric"`
+	Series         int            `json:"series"`
+	MaxSeries      int            `json:"max_series"`
+	LabelValues    map[string]int `json:"label_values"` // Distinct values kept per label
+	MaxLabelValues int            `json:"max_label_values"`
+	Rewritten      int64          `json:"rewritten"` // Points whose labels were hashed or dropped
+	Dropped        int64          `json:"dropped"`   // Points dropped as new series over the limit
+	LimitedLabels  []string       `json:"limited_labels,omitempty"`
+	LastLimitedAt  time.Time      `json:"last_limited_at,omitzero"`
+}
+
+// Limited reports whether the metric hit one of its limits
+func (c MetricCardinality) Limited() bool {
+	return c.Rewritten > 0 || c.Dropped > 0
+}
+
+// metricCardinality tracks the series and label values kept for a metric
+type metricCardinality struct {
+	limit     CardinalityLimit
+	series    map[string]bool
+	values    map[string]map[string]bool // Label to its kept values
+	limited   map[string]bool            // Labels that hit the value limit
+	rewritten int64
+	dropped   int64
+	limitedAt time.Time
+	warned    bool // Whether dropping series has been logged
+}
+
+// CardinalityLimiter bounds the series each metric name can create, so
+// checks and ingested metrics with unbounded label values, such as request
+// IDs, can't exhaust memory or blow up Prometheus scrapes. Each label keeps
+// its first MaxLabelValues values; later values are hashed into buckets or
+// the label is dropped. Series beyond MaxSeries are dropped entirely.
+type CardinalityLimiter struct {
+	config CardinalityConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	metrics map[string]*metricCardinality
+}
+
+// NewCardinalityLimiter creates a limiter, filling unset limits with defaults
+func NewCardinalityLimiter(config CardinalityConfig) *CardinalityLimiter {
+	if config.MaxSeries <= 0 {
+		config.MaxSeries = DefaultMaxSeries
+	}
+	if config.MaxLabelValues <= 0 {
+		config.MaxLabelValues = DefaultMaxLabelValues
+	}
+	if config.Action == "" {
+		config.Action = CardinalityHash
+	}
+	if config.HashBuckets <= 0 {
+		config.HashBuckets = DefaultHashBuckets
+	}
+	return &CardinalityLimiter{
+		config:  config,
+		now:     time.Now,
+		metrics: make(map[string]*metricCardinality),
+	}
+}
+
+// Apply returns metrics with labels beyond their value limits rewritten and
+// series beyond their metric's limit removed. The input is not modified.
+func (l *CardinalityLimiter) Apply(metrics []Metric) []Metric {
+	if len(metrics) == 0 {
+		return metrics
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	kept := make([]Metric, 0, len(metrics))
+	for _, metric := range metrics {
+		tracked := l.tracked(metric.Name)
+		rewritten := false
+		if len(metric.Labels) > 0 {
+			labels := make(map[string]string, len(metric.Labels))
+			for _, label := range sortedLabelNames(metric.Labels) {
+				value, keep := l.limitValue(metric.Name, tracked, label, metric.Labels[label])
+				if !keep || value != metric.Labels[label] {
+					rewritten = true
+				}
+				if keep {
+					labels[label] = value
+				}
+			}
+			metric.Labels = labels
+		}
+
+		key := metricSeriesKey(metric)
+		if !tracked.series[key] {
+			if len(tracked.series) >= tracked.limit.MaxSeries {
+				tracked.dropped++
+				tracked.limitedAt = l.now()
+				if !tracked.warned {
+					tracked.warned = true
+					klog.Warningf("Metric %s reached its limit of %d series; new series are dropped", metric.Name, tracked.limit.MaxSeries)
+				}
+				continue
+			}
+			tracked.series[key] = true
+		}
+		if rewritten {
+			tracked.rewritten++
+			tracked.limitedAt = l.now()
+		}
+		kept = append(kept, metric)
+	}
+	return kept
+}
+
+// tracked returns the tracking state of a metric; callers hold l.mu
+func (l *CardinalityLimiter) tracked(name string) *metricCardinality {
+	tracked, ok := l.metrics[name]
+	if ok {
+		return tracked
+	}
+	limit := CardinalityLimit{MaxSeries: l.config.MaxSeries, MaxLabelValues: l.config.MaxLabelValues}
+	if override, ok := l.config.Overrides[name]; ok {
+		if override.MaxSeries > 0 {
+			limit.MaxSeries = override.MaxSeries
+		}
+		if override.MaxLabelValues > 0 {
+			limit.MaxLabelValues = override.MaxLabelValues
+		}
+	}
+	tracked = &metricCardinality{
+		limit:   limit,
+		series:  make(map[string]bool),
+		values:  make(map[string]map[string]bool),
+		limited: make(map[string]bool),
+	}
+	l.metrics[name] = tracked
+	return tracked
+}
+
+// limitValue returns the value a label keeps and whether the label is kept
+// at all; callers hold l.mu
+func (l *CardinalityLimiter) limitValue(metric string, tracked *metricCardinality, label, value string) (string, bool) {
+	values, ok := tracked.values[label]
+	if !ok {
+		values = make(map[string]bool)
+		tracked.values[label] = values
+	}
+	if values[value] {
+		return value, true
+	}
+	if len(values) < tracked.limit.MaxLabelValues {
+		values[value] = true
+		return value, true
+	}
+
+	drop := l.config.Action == CardinalityDrop
+	if !tracked.limited[label] {
+		tracked.limited[label] = true
+		outcome := "hashed"
+		if drop {
+			outcome = "dropped"
+		}
+		klog.Warningf("Label %s of metric %s reached its limit of %d values; new values are %s",
+			label, metric, tracked.limit.MaxLabelValues, outcome)
+	}
+	if drop {
+		return "", false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return fmt.Sprintf("%s%02d", hashedValuePrefix, h.Sum32()%uint32(l.config.HashBuckets)), true
+}
+
+// Stats returns the cardinality of every metric seen, highest series count
+// first
+func (l *CardinalityLimiter) Stats() []MetricCardinality {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make([]MetricCardinality, 0, len(l.metrics))
+	for name, tracked := range l.metrics {
+		stat := MetricCardinality{
+			Metric:         name,
+			Series:         len(tracked.series),
+			MaxSeries:      tracked.limit.MaxSeries,
+			LabelValues:    make(map[string]int, len(tracked.values)),
+			MaxLabelValues: tracked.limit.MaxLabelValues,
+			Rewritten:      tracked.rewritten,
+			Dropped:        tracked.dropped,
+			LastLimitedAt:  tracked.limitedAt,
+		}
+		for label, values := range tracked.values {
+			stat.LabelValues[label] = len(values)
+		}
+		for label := range tracked.limited {
+			stat.LimitedLabels = append(stat.LimitedLabels, label)
+		}
+		sort.Strings(stat.LimitedLabels)
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Series != stats[j].Series {
+			return stats[i].Series > stats[j].Series
+		}
+		return stats[i].Metric < stats[j].Metric
+	})
+	return stats
+}
+
+// Warnings describes each metric that hit a limit, for self-diagnostics
+func (l *CardinalityLimiter) Warnings() []string {
+	var warnings []string
+	for _, stat := range l.Stats() {
+		if !stat.Limited() {
+			continue
+		}
+		var parts []string
+		if len(stat.LimitedLabels) > 0 {
+			parts = append(parts, fmt.Sprintf("labels %s over %d values (%d points rewritten)",
+				strings.Join(stat.LimitedLabels, ", "), stat.MaxLabelValues, stat.Rewritten))
+		}
+		if stat.Dropped > 0 {
+			parts = append(parts, fmt.Sprintf("%d points dropped over %d series", stat.Dropped, stat.MaxSeries))
+		}
+		warnings = append(warnings, fmt.Sprintf("metric %s hit its cardinality limits: %s", stat.Metric, strings.Join(parts, "; ")))
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// Metrics exports the series count and limited points of each metric
+func (l *CardinalityLimiter) Metrics() []Metric {
+	now := l.now()
+	stats := l.Stats()
+	metrics := make([]Metric, 0, len(stats)*3)
+	for _, stat := range stats {
+		labels := map[string]string{"metric": stat.Metric}
+		metrics = append(metrics,
+			Metric{Name: "kubepulse_metric_series", Value: float64(stat.Series), Unit: "series", Labels: labels, Timestamp: now, Type: MetricTypeGauge},
+			Metric{Name: "kubepulse_metric_points_rewritten_total", Value: float64(stat.Rewritten), Unit: "points", Labels: labels, Timestamp: now, Type: MetricTypeCounter},
+			Metric{Name: "kubepulse_metric_points_dropped_total", Value: float64(stat.Dropped), Unit: "points", Labels: labels, Timestamp: now, Type: MetricTypeCounter},
+		)
+	}
+	return metrics
+}
+
+// sortedLabelNames returns label names in a stable order, so which values
+// fit under a limit doesn't depend on map iteration
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
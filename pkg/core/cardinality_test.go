@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// requestMetrics returns one request_total point per path
+func requestMetrics(paths ...string) []Metric {
+	metrics := make([]Metric, len(paths))
+	for i, path := range paths {
+		metrics[i] = Metric{Name: "request_total", Value: 1, Labels: map[string]string{"path": path, "method": "GET"}}
+	}
+	return metrics
+}
+
+func TestCardinalityLimiter_LabelValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		action CardinalityAction
+		check  func(t *testing.T, label string, kept bool)
+	}{
+		{"hash", CardinalityHash, func(t *testing.T, label string, kept bool) {
+			if !kept || !strings.HasPrefix(label, hashedValuePrefix) {
+				t.Errorf("expected a hashed path, got %q (kept %v)", label, kept)
+			}
+		}},
+		{"drop", CardinalityDrop, func(t *testing.T, label string, kept bool) {
+			if kept {
+				t.Errorf("expected the path label to be dropped, got %q", label)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewCardinalityLimiter(CardinalityConfig{MaxLabelValues: 2, Action: tt.action})
+
+			input := requestMetrics("/a", "/b", "/c", "/a")
+			metrics := limiter.Apply(input)
+			if len(metrics) != 4 {
+				t.Fatalf("expected every point kept, got %d", len(metrics))
+			}
+			if input[2].Labels["path"] != "/c" {
+				t.Error("expected the input to be left unmodified")
+			}
+			for i, want := range []string{"/a", "/b", "", "/a"} {
+				if want != "" && metrics[i].Labels["path"] != want {
+					t.Errorf("point %d: expected path %s, got %q", i, want, metrics[i].Labels["path"])
+				}
+				if metrics[i].Labels["method"] != "GET" {
+					t.Errorf("point %d: expected the method label untouched, got %v", i, metrics[i].Labels)
+				}
+			}
+			label, kept := metrics[2].Labels["path"]
+			tt.check(t, label, kept)
+
+			stats := limiter.Stats()
+			if len(stats) != 1 || stats[0].Rewritten != 1 || stats[0].LabelValues["path"] != 2 ||
+				len(stats[0].LimitedLabels) != 1 || stats[0].LimitedLabels[0] != "path" {
+				t.Errorf("unexpected stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestCardinalityLimiter_HashIsStable(t *testing.T) {
+	limiter := NewCardinalityLimiter(CardinalityConfig{MaxLabelValues: 1, HashBuckets: 4})
+	limiter.Apply(requestMetrics("/a"))
+
+	first := limiter.Apply(requestMetrics("/b"))[0].Labels["path"]
+	second := limiter.Apply(requestMetrics("/b"))[0].Labels["path"]
+	if first != second {
+		t.Errorf("expected the same bucket for the same value, got %s and %s", first, second)
+	}
+
+	buckets := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		buckets[limiter.Apply(requestMetrics(fmt.Sprintf("/user/%d", i)))[0].Labels["path"]] = true
+	}
+	if len(buckets) > 4 {
+		t.Errorf("expected at most 4 buckets, got %d", len(buckets))
+	}
+}
+
+func TestCardinalityLimiter_MaxSeries(t *testing.T) {
+	limiter := NewCardinalityLimiter(CardinalityConfig{
+		MaxSeries: 2,
+		Overrides: map[string]CardinalityLimit{"queue_depth": {MaxSeries: 3}},
+	})
+
+	metrics := limiter.Apply(requestMetrics("/a", "/b", "/c", "/a"))
+	if len(metrics) != 3 || metrics[2].Labels["path"] != "/a" {
+		t.Fatalf("expected the third series dropped and known series kept, got %+v", metrics)
+	}
+
+	queues := make([]Metric, 4)
+	for i := range queues {
+		queues[i] = Metric{Name: "queue_depth", Labels: map[string]string{"queue": fmt.Sprint(i)}}
+	}
+	if kept := limiter.Apply(queues); len(kept) != 3 {
+		t.Errorf("expected the override to keep 3 series, got %d", len(kept))
+	}
+
+	stats := limiter.Stats()
+	if len(stats) != 2 || stats[0].Metric != "queue_depth" || stats[0].MaxSeries != 3 || stats[1].Dropped != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	warnings := limiter.Warnings()
+	if len(warnings) != 2 || !strings.Contains(warnings[1], "request_total") || !strings.Contains(warnings[1], "1 points dropped over 2 series") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	if got := len(limiter.Metrics()); got != 6 {
+		t.Errorf("expected 3 self-metrics per metric, got %d", got)
+	}
+}
+
+func TestCardinalityLimiter_NoWarningsUnderLimits(t *testing.T) {
+	limiter := NewCardinalityLimiter(CardinalityConfig{})
+	limiter.Apply(requestMetrics("/a", "/b"))
+	if warnings := limiter.Warnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestIngestMetrics_CardinalityLimits(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient:  fake.NewSimpleClientset(),
+		Cardinality: CardinalityConfig{MaxSeries: 2},
+	})
+
+	result, err := engine.IngestMetrics("checkout", requestMetrics("/a", "/b", "/c"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Metrics) != 2 {
+		t.Errorf("expected 2 accepted metrics, got %d", len(result.Metrics))
+	}
+	if got := len(engine.GetMetricHistory("request_total")); got != 2 {
+		t.Errorf("expected 2 series in history, got %d", got)
+	}
+	if warnings := engine.CardinalityWarnings(); len(warnings) != 1 {
+		t.Errorf("expected a cardinality warning, got %v", warnings)
+	}
+}
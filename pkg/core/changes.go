@@ -0,0 +1,257 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// ChangeKind classifies an entry in the change feed
+type ChangeKind string
+
+const (
+	ChangeKindCheckStatus   ChangeKind = "check_status"
+	ChangeKindAlertFiring   ChangeKind = "alert_firing"
+	ChangeKindAlertResolved ChangeKind = "alert_resolved"
+	ChangeKindRollout       ChangeKind = "deployment_rollout"
+	ChangeKindNodeAdded     ChangeKind = "node_added"
+	ChangeKindNodeRemoved   ChangeKind = "node_removed"
+	ChangeKindContextSwitch ChangeKind = "context_switch"
+	ChangeKindRemediation   ChangeKind = "remediation"
+	ChangeKindAlertRule     ChangeKind = "alert_rule"
+	ChangeKindMaintenance   ChangeKind = "check_maintenance"
+	ChangeKindAnnotation    ChangeKind = "annotation"
+	ChangeKindChaos         ChangeKind = "chaos_experiment"
+)
+
+// Change is a single entry in the "what changed" feed
+type Change struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Kind      ChangeKind             `json:"kind"`
+	Resource  string                 `json:"resource"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Message   string                 `json:"message"`
+	From      string                 `json:"from,omitempty"`
+	To        string                 `json:"to,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// ChangeLog is a bounded, time-ordered record of changes observed by the engine
+type ChangeLog struct {
+	capacity int
+	mu       sync.RWMutex
+	changes  []Change
+	nodes    map[string]bool // Node names seen in the last inventory
+}
+
+// NewChangeLog creates a change log retaining up to capacity entries
+func NewChangeLog(capacity int) *ChangeLog {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &ChangeLog{
+		capacity: capacity,
+		changes:  make([]Change, 0),
+	}
+}
+
+// Record appends a change, stamping it with the current time if unset
+func (l *ChangeLog) Record(change Change) {
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.changes = append(l.changes, change)
+	if len(l.changes) > l.capacity {
+		l.changes = l.changes[len(l.changes)-l.capacity:]
+	}
+}
+
+// Since returns recorded changes at or after the given time, oldest first
+func (l *ChangeLog) Since(since time.Time) []Change {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	changes := make([]Change, 0)
+	for _, change := range l.changes {
+		if !change.Timestamp.Before(since) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// UpdateNodes diffs the node inventory against the previous one and records
+// additions and removals. The first inventory only establishes a baseline.
+func (l *ChangeLog) UpdateNodes(names []string) {
+	current := make(map[string]bool, len(names))
+	for _, name := range names {
+		current[name] = true
+	}
+
+	l.mu.Lock()
+	previous := l.nodes
+	l.nodes = current
+	l.mu.Unlock()
+
+	if previous == nil {
+		return
+	}
+
+	now := time.Now()
+	for name := range current {
+		if !previous[name] {
+			l.Record(Change{Timestamp: now, Kind: ChangeKindNodeAdded, Resource: "node/" + name, Message: fmt.Sprintf("Node %s joined the cluster", name)})
+		}
+	}
+	for name := range previous {
+		if !current[name] {
+			l.Record(Change{Timestamp: now, Kind: ChangeKindNodeRemoved, Resource: "node/" + name, Message: fmt.Sprintf("Node %s left the cluster", name)})
+		}
+	}
+}
+
+// RecordChange adds an externally observed change, such as a context switch, to the feed
+func (e *Engine) RecordChange(change Change) {
+	e.changes.Record(change)
+}
+
+// GetChanges returns everything that changed since the given time, oldest first.
+// Deployment rollouts are read from the cluster; everything else comes from the engine's change log.
+func (e *Engine) GetChanges(ctx context.Context, since time.Time) ([]Change, error) {
+	changes := e.changes.Since(since)
+
+	rollouts, err := e.deploymentRollouts(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment rollouts: %w", err)
+	}
+	changes = append(changes, rollouts...)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Timestamp.Before(changes[j].Timestamp)
+	})
+	return changes, nil
+}
+
+// recordStatusChange records check status transitions and the alerts they open or resolve
+func (e *Engine) recordStatusChange(previous CheckResult, existed bool, result CheckResult) {
+	if !existed || previous.Status == result.Status {
+		return
+	}
+	e.invalidateInsights(fmt.Sprintf("%s changed from %s to %s", result.Name, previous.Status, result.Status))
+
+	now := time.Now()
+	resource := "check/" + result.Name
+	e.changes.Record(Change{
+		Timestamp: now,
+		Kind:      ChangeKindCheckStatus,
+		Resource:  resource,
+		Message:   fmt.Sprintf("%s changed from %s to %s", result.Name, previous.Status, result.Status),
+		From:      string(previous.Status),
+		To:        string(result.Status),
+	})
+
+	// Failures while under maintenance neither fire nor resolve alerts
+	wasAlerting := isAlerting(previous.Status) && !InMaintenance(previous)
+	switch {
+	case !wasAlerting && isAlerting(result.Status) && !InMaintenance(result):
+		e.changes.Record(Change{
+			Timestamp: now,
+			Kind:      ChangeKindAlertFiring,
+			Resource:  resource,
+			Message:   result.Message,
+			To:        string(e.getSeverity(result)),
+		})
+	case wasAlerting && result.Status == HealthStatusHealthy:
+		e.changes.Record(Change{
+			Timestamp: now,
+			Kind:      ChangeKindAlertResolved,
+			Resource:  resource,
+			Message:   fmt.Sprintf("%s recovered", result.Name),
+			From:      string(e.getSeverity(previous)),
+		})
+	}
+}
+
+// isAlerting reports whether a status raises an alert
+func isAlerting(status HealthStatus) bool {
+	return status == HealthStatusUnhealthy || status == HealthStatusDegraded
+}
+
+// trackNodes refreshes the node inventory used for node added/removed changes
+func (e *Engine) trackNodes() {
+	if e.client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, e.checkTimeout)
+	defer cancel()
+
+	nodes, err := e.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.V(2).Infof("Failed to list nodes for change tracking: %v", err)
+		return
+	}
+
+	names := make([]string, len(nodes.Items))
+	for i, node := range nodes.Items {
+		names[i] = node.Name
+	}
+	e.changes.UpdateNodes(names)
+}
+
+// deploymentRollouts finds ReplicaSets created for deployments since the given time
+func (e *Engine) deploymentRollouts(ctx context.Context, since time.Time) ([]Change, error) {
+	if e.client == nil {
+		return nil, nil
+	}
+
+	replicaSets, err := e.client.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+	for _, rs := range replicaSets.Items {
+		if rs.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		deployment := deploymentOwner(rs)
+		if deployment == "" {
+			continue
+		}
+
+		change := Change{
+			Timestamp: rs.CreationTimestamp.Time,
+			Kind:      ChangeKindRollout,
+			Resource:  "deployment/" + deployment,
+			Namespace: rs.Namespace,
+			Message:   fmt.Sprintf("Deployment %s/%s rolled out ReplicaSet %s", rs.Namespace, deployment, rs.Name),
+			To:        rs.Name,
+		}
+		if revision := rs.Annotations["deployment.kubernetes.io/revision"]; revision != "" {
+			change.Details = map[string]interface{}{"revision": revision}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// deploymentOwner returns the name of the deployment owning a ReplicaSet
+func deploymentOwner(rs appsv1.ReplicaSet) string {
+	for _, owner := range rs.OwnerReferences {
+		if owner.Kind == "Deployment" {
+			return owner.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRecordStatusChange(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusDegraded})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+
+	changes := engine.changes.Since(time.Time{})
+
+	want := []ChangeKind{
+		ChangeKindCheckStatus, ChangeKindAlertFiring, // healthy -> unhealthy
+		ChangeKindCheckStatus,                          // unhealthy -> degraded keeps the alert open
+		ChangeKindCheckStatus, ChangeKindAlertResolved, // degraded -> healthy
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for i, kind := range want {
+		if changes[i].Kind != kind {
+			t.Errorf("expected %s at %d, got %s", kind, i, changes[i].Kind)
+		}
+	}
+
+	if changes[0].From != "healthy" || changes[0].To != "unhealthy" {
+		t.Errorf("unexpected transition %s -> %s", changes[0].From, changes[0].To)
+	}
+	if changes[1].To != string(AlertSeverityCritical) || changes[1].Message != "2 pods crashlooping" {
+		t.Errorf("unexpected alert change: %+v", changes[1])
+	}
+}
+
+func TestChangeLog_UpdateNodes(t *testing.T) {
+	log := NewChangeLog(10)
+
+	log.UpdateNodes([]string{"node-a", "node-b"})
+	if len(log.Since(time.Time{})) != 0 {
+		t.Fatal("expected first inventory to only set a baseline")
+	}
+
+	log.UpdateNodes([]string{"node-b", "node-c"})
+	changes := log.Since(time.Time{})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 node changes, got %d", len(changes))
+	}
+
+	kinds := map[ChangeKind]string{}
+	for _, change := range changes {
+		kinds[change.Kind] = change.Resource
+	}
+	if kinds[ChangeKindNodeAdded] != "node/node-c" || kinds[ChangeKindNodeRemoved] != "node/node-a" {
+		t.Errorf("unexpected node changes: %v", kinds)
+	}
+}
+
+func TestChangeLog_Capacity(t *testing.T) {
+	log := NewChangeLog(2)
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		log.Record(Change{Timestamp: base.Add(time.Duration(i) * time.Second), Kind: ChangeKindContextSwitch})
+	}
+
+	changes := log.Since(time.Time{})
+	if len(changes) != 2 || !changes[0].Timestamp.Equal(base.Add(time.Second)) {
+		t.Errorf("expected the 2 most recent changes, got %+v", changes)
+	}
+	if len(log.Since(base.Add(2*time.Second))) != 1 {
+		t.Error("expected since to be inclusive")
+	}
+}
+
+func TestGetChanges(t *testing.T) {
+	now := time.Now()
+	replicaSet := func(name string, created time.Time, owner string) *appsv1.ReplicaSet {
+		rs := &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       map[string]string{"deployment.kubernetes.io/revision": "3"},
+			},
+		}
+		if owner != "" {
+			rs.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: owner}}
+		}
+		return rs
+	}
+
+	client := fake.NewSimpleClientset(
+		replicaSet("api-7d9f", now.Add(-10*time.Minute), "api"),
+		replicaSet("api-5c2a", now.Add(-2*time.Hour), "api"),
+		replicaSet("orphan-1", now.Add(-5*time.Minute), ""),
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+	)
+	engine := NewEngine(EngineConfig{KubeClient: client})
+	engine.RecordChange(Change{Timestamp: now.Add(-20 * time.Minute), Kind: ChangeKindContextSwitch, Resource: "context/prod"})
+	engine.RecordChange(Change{Timestamp: now.Add(-time.Hour), Kind: ChangeKindRemediation, Resource: "remediation/old"})
+
+	changes, err := engine.GetChanges(context.Background(), now.Add(-30*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Kind != ChangeKindContextSwitch {
+		t.Errorf("expected context switch first, got %s", changes[0].Kind)
+	}
+	rollout := changes[1]
+	if rollout.Kind != ChangeKindRollout || rollout.Resource != "deployment/api" || rollout.To != "api-7d9f" {
+		t.Errorf("unexpected rollout change: %+v", rollout)
+	}
+	if rollout.Details["revision"] != "3" {
+		t.Errorf("expected revision 3, got %v", rollout.Details["revision"])
+	}
+
+	// Node tracking picks up the inventory on each check cycle
+	engine.trackNodes()
+	if err := client.CoreV1().Nodes().Delete(context.Background(), "node-a", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete node: %v", err)
+	}
+	engine.trackNodes()
+
+	changes, _ = engine.GetChanges(context.Background(), now)
+	if len(changes) != 1 || changes[0].Kind != ChangeKindNodeRemoved {
+		t.Errorf("expected node removal, got %+v", changes)
+	}
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// DetailChaos lists the IDs of the chaos experiments a failing result is
+// attributed to
+const DetailChaos = "chaos"
+
+const (
+	// maxChaosReports bounds the experiments whose impact is kept; the
+	// oldest ended ones are dropped first
+	maxChaosReports = 100
+
+	// maxChaosSilence caps the auto-silence of an experiment that runs until
+	// stopped; it is lifted as soon as the experiment ends anyway
+	maxChaosSilence = 24 * time.Hour
+)
+
+// ChaosExperiment is a running chaos experiment, such as a Chaos Mesh
+// PodChaos or a LitmusChaos ChaosEngine
+type ChaosExperiment struct {
+	Tool       string    `json:"tool"` // chaos-mesh or litmus
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Action     string    `json:"action,omitempty"` // e.g. pod-kill, or the Litmus experiments run
+	Namespaces []string  `json:"namespaces"`       // Namespaces of the targets
+	Selector   string    `json:"selector,omitempty"`
+	Started    time.Time `json:"started"`
+	Until      time.Time `json:"until,omitzero"` // Zero when it runs until stopped
+}
+
+// ID identifies the experiment as tool/namespace/name
+func (x ChaosExperiment) ID() string {
+	return x.Tool + "/" + x.Namespace + "/" + x.Name
+}
+
+// targets reports whether the experiment targets a namespace; without
+// target namespaces it targets its own
+func (x ChaosExperiment) targets(namespace string) bool {
+	if len(x.Namespaces) == 0 {
+		return namespace == x.Namespace
+	}
+	for _, target := range x.Namespaces {
+		if target == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ChaosImpact is how a check fared during a chaos experiment
+type ChaosImpact struct {
+	Check        string       `json:"check"`
+	WorstStatus  HealthStatus `json:"worst_status"`
+	FirstFailure time.Time    `json:"first_failure"`
+	LastFailure  time.Time    `json:"last_failure"`
+	RecoveredAt  *time.Time   `json:"recovered_at,omitempty"` // First healthy result after the last failure
+}
+
+// ChaosReport is the resilience report of a chaos experiment: the failures
+// attributed to it and when the checks recovered
+type ChaosReport struct {
+	Experiment ChaosExperiment `json:"experiment"`
+	Active     bool            `json:"active"`
+	EndedAt    *time.Time      `json:"ended_at,omitempty"`
+	SilenceID  string          `json:"silence_id,omitempty"` // Silence of the alerts it causes
+	Impacts    []ChaosImpact   `json:"impacts"`
+}
+
+// chaosState holds the reports of running and recently ended experiments,
+// oldest first
+type chaosState struct {
+	mu          sync.Mutex
+	autoSilence bool
+	reports     []*ChaosReport
+}
+
+// SetChaosExperiments replaces the running chaos experiments. Experiments
+// that appear are recorded in the change feed and, with auto-silence on,
+// get a silence of the alerts attributed to them; those that disappear have
+// ended, and their silence is lifted.
+func (e *Engine) SetChaosExperiments(experiments []ChaosExperiment) {
+	now := time.Now()
+	running := make(map[string]ChaosExperiment, len(experiments))
+	for _, experiment := range experiments {
+		running[experiment.ID()] = experiment
+	}
+
+	e.chaos.mu.Lock()
+	var started, ended []*ChaosReport
+	for _, report := range e.chaos.reports {
+		if !report.Active {
+			continue
+		}
+		experiment, ok := running[report.Experiment.ID()]
+		if ok {
+			report.Experiment = experiment
+			delete(running, experiment.ID())
+			continue
+		}
+		report.Active = false
+		endedAt := now
+		report.EndedAt = &endedAt
+		ended = append(ended, report)
+	}
+	for _, experiment := range experiments {
+		if _, ok := running[experiment.ID()]; ok {
+			report := &ChaosReport{Experiment: experiment, Active: true, Impacts: []ChaosImpact{}}
+			e.chaos.reports = append(e.chaos.reports, report)
+			started = append(started, report)
+		}
+	}
+	e.trimChaosReports()
+	autoSilence := e.chaos.autoSilence
+	e.chaos.mu.Unlock()
+
+	for _, report := range started {
+		id := report.Experiment.ID()
+		message := fmt.Sprintf("Chaos experiment %s started, targeting %s", id, strings.Join(report.Experiment.Namespaces, ", "))
+		if autoSilence {
+			if silence, err := e.silenceChaos(report.Experiment, now); err != nil {
+				klog.Warningf("Failed to silence alerts of chaos experiment %s: %v", id, err)
+			} else {
+				e.chaos.mu.Lock()
+				report.SilenceID = silence.ID
+				e.chaos.mu.Unlock()
+				message += "; its alerts are silenced"
+			}
+		}
+		e.changes.Record(Change{Timestamp: now, Kind: ChangeKindChaos, Resource: "chaos/" + id, Message: message, To: "running"})
+		klog.Infof("%s", message)
+	}
+	for _, report := range ended {
+		id := report.Experiment.ID()
+		e.chaos.mu.Lock()
+		silenceID := report.SilenceID
+		e.chaos.mu.Unlock()
+		if silenceID != "" {
+			if _, err := e.alertManager.ExpireSilence(silenceID); err != nil {
+				klog.V(2).Infof("Silence of chaos experiment %s already ended: %v", id, err)
+			}
+		}
+		e.changes.Record(Change{Timestamp: now, Kind: ChangeKindChaos, Resource: "chaos/" + id, Message: fmt.Sprintf("Chaos experiment %s ended", id), From: "running"})
+		klog.Infof("Chaos experiment %s ended", id)
+	}
+}
+
+// silenceChaos silences the alerts attributed to an experiment until it is
+// due to end
+func (e *Engine) silenceChaos(experiment ChaosExperiment, now time.Time) (alerts.Silence, error) {
+	until := experiment.Until
+	if !until.After(now) || until.Sub(now) > maxChaosSilence {
+		until = now.Add(maxChaosSilence)
+	}
+	return e.alertManager.AddSilence(alerts.Silence{
+		Matchers:  map[string]string{"chaos": experiment.ID()},
+		Until:     until,
+		CreatedBy: "kubepulse",
+		Comment:   "Chaos experiment " + experiment.ID(),
+	})
+}
+
+// trimChaosReports drops the oldest ended reports beyond maxChaosReports;
+// callers hold chaos.mu
+func (e *Engine) trimChaosReports() {
+	excess := len(e.chaos.reports) - maxChaosReports
+	if excess <= 0 {
+		return
+	}
+	kept := e.chaos.reports[:0]
+	for _, report := range e.chaos.reports {
+		if excess > 0 && !report.Active {
+			excess--
+			continue
+		}
+		kept = append(kept, report)
+	}
+	e.chaos.reports = kept
+}
+
+// ChaosReports returns the reports of running and recently ended chaos
+// experiments, most recently started first
+func (e *Engine) ChaosReports() []ChaosReport {
+	e.chaos.mu.Lock()
+	defer e.chaos.mu.Unlock()
+
+	reports := make([]ChaosReport, len(e.chaos.reports))
+	for i, report := range e.chaos.reports {
+		copied := *report
+		copied.Impacts = append([]ChaosImpact{}, report.Impacts...)
+		reports[len(reports)-1-i] = copied
+	}
+	return reports
+}
+
+// withChaos returns a failing result marked with the running experiments
+// it is attributed to, and records the result in their reports. A failure
+// is attributed when every resource it implicates is in a namespace an
+// experiment targets. Details are copied, so the check's own details are
+// not modified.
+func (e *Engine) withChaos(result CheckResult) CheckResult {
+	e.chaos.mu.Lock()
+	defer e.chaos.mu.Unlock()
+	if len(e.chaos.reports) == 0 {
+		return result
+	}
+
+	at := result.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if !isAlerting(result.Status) {
+		e.recordChaosRecovery(result.Name, at)
+		return result
+	}
+
+	refs := ImplicatedResources(result)
+	var ids []string
+	for _, report := range e.chaos.reports {
+		if report.Active && attributable(report.Experiment, refs) {
+			ids = append(ids, report.Experiment.ID())
+			report.recordFailure(result, at)
+		}
+	}
+	if len(ids) == 0 {
+		return result
+	}
+
+	sort.Strings(ids)
+	details := make(map[string]interface{}, len(result.Details)+1)
+	for key, value := range result.Details {
+		details[key] = value
+	}
+	details[DetailChaos] = ids
+	result.Details = details
+	return result
+}
+
+// attributable reports whether every implicated resource is in a namespace
+// the experiment targets
+func attributable(experiment ChaosExperiment, refs []ResourceRef) bool {
+	if len(refs) == 0 {
+		return false
+	}
+	for _, ref := range refs {
+		if ref.Namespace == "" || !experiment.targets(ref.Namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordFailure adds a failing result to the report's impact on its check
+func (r *ChaosReport) recordFailure(result CheckResult, at time.Time) {
+	for i := range r.Impacts {
+		impact := &r.Impacts[i]
+		if impact.Check != result.Name {
+			continue
+		}
+		if statusRank(result.Status) > statusRank(impact.WorstStatus) {
+			impact.WorstStatus = result.Status
+		}
+		impact.LastFailure = at
+		impact.RecoveredAt = nil
+		return
+	}
+	r.Impacts = append(r.Impacts, ChaosImpact{Check: result.Name, WorstStatus: result.Status, FirstFailure: at, LastFailure: at})
+}
+
+// recordChaosRecovery marks when a check impacted by an experiment is
+// healthy again; callers hold chaos.mu
+func (e *Engine) recordChaosRecovery(check string, at time.Time) {
+	for _, report := range e.chaos.reports {
+		for i := range report.Impacts {
+			impact := &report.Impacts[i]
+			if impact.Check == check && impact.RecoveredAt == nil && at.After(impact.LastFailure) {
+				recovered := at
+				impact.RecoveredAt = &recovered
+			}
+		}
+	}
+}
+
+// ChaosExperimentsOf returns the IDs of the experiments a result is
+// attributed to, including results decoded from JSON
+func ChaosExperimentsOf(result CheckResult) []string {
+	switch ids := result.Details[DetailChaos].(type) {
+	case []string:
+		return ids
+	case []interface{}:
+		data, err := json.Marshal(ids)
+		if err != nil {
+			return nil
+		}
+		var decoded []string
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
+
+// ChaosInduced reports whether a result's failure is attributed to a chaos
+// experiment
+func ChaosInduced(result CheckResult) bool {
+	return len(ChaosExperimentsOf(result)) > 0
+}
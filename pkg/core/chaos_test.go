@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_ChaosExperiments(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ChaosAutoSilence: true})
+	killWeb := ChaosExperiment{Tool: "chaos-mesh", Kind: "PodChaos", Namespace: "chaos", Name: "kill-web",
+		Namespaces: []string{"shop"}, Started: time.Now(), Until: time.Now().Add(5 * time.Minute)}
+	engine.SetChaosExperiments([]ChaosExperiment{killWeb})
+
+	silences := engine.alertManager.Silences()
+	if len(silences) != 1 || silences[0].Matchers["chaos"] != killWeb.ID() {
+		t.Fatalf("expected the experiment's alerts silenced, got %+v", silences)
+	}
+
+	failing := func(pods ...string) CheckResult {
+		return CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: time.Now(),
+			Details: map[string]interface{}{DetailImplicatedResources: pods}}
+	}
+	if result := engine.withChaos(failing("pod/shop/web-1", "pod/payments/api-1")); ChaosInduced(result) {
+		t.Errorf("expected a failure outside the targeted namespace not attributed, got %+v", result.Details)
+	}
+	result := engine.withChaos(failing("pod/shop/web-1"))
+	if ids := ChaosExperimentsOf(result); len(ids) != 1 || ids[0] != killWeb.ID() {
+		t.Fatalf("expected the failure attributed to the experiment, got %+v", result.Details)
+	}
+	engine.storeResult(result)
+	engine.processResult(result)
+
+	history := engine.alertManager.GetHistory(10)
+	if len(history) == 0 || history[0].Labels["chaos"] != killWeb.ID() {
+		t.Errorf("expected the alert labelled with the experiment, got %+v", history)
+	}
+	health := engine.GetClusterHealth("test")
+	if len(health.Score.Breakdown) != 1 || !health.Score.Breakdown[0].ChaosInduced || health.Score.Weighted == 100 {
+		t.Errorf("expected the failure to count and be tagged chaos-induced, got %+v", health.Score)
+	}
+
+	engine.withChaos(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: time.Now().Add(time.Second)})
+	engine.SetChaosExperiments(nil)
+	reports := engine.ChaosReports()
+	if len(reports) != 1 || reports[0].Active || reports[0].EndedAt == nil {
+		t.Fatalf("expected one ended experiment, got %+v", reports)
+	}
+	impacts := reports[0].Impacts
+	if len(impacts) != 1 || impacts[0].Check != "pod-health" || impacts[0].WorstStatus != HealthStatusUnhealthy || impacts[0].RecoveredAt == nil {
+		t.Errorf("expected the recovered pod-health impact, got %+v", impacts)
+	}
+	if silences := engine.alertManager.Silences(); len(silences) != 0 {
+		t.Errorf("expected the silence lifted when the experiment ended, got %+v", silences)
+	}
+	if result := engine.withChaos(failing("pod/shop/web-1")); ChaosInduced(result) {
+		t.Error("expected no attribution once the experiment ended")
+	}
+}
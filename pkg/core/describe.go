@@ -0,0 +1,521 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// DetailImplicatedResources lists the resources a failing check blames, as
+// kind/namespace/name or kind/name for cluster-scoped resources
+const DetailImplicatedResources = "implicated_resources"
+
+const (
+	// DefaultDescribeTTL is how long a resource description is reused
+	DefaultDescribeTTL = 2 * time.Minute
+
+	// maxDescribedResources caps the descriptions attached to one analysis
+	maxDescribedResources = 3
+	// maxDescribedEvents caps the events kept per description
+	maxDescribedEvents = 10
+	// describeTimeout bounds fetching a single description
+	describeTimeout = 10 * time.Second
+)
+
+// ResourceRef identifies a resource implicated by a check
+type ResourceRef struct {
+	Kind      string `json:"kind"` // pod, node or service
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// String returns the reference as kind/namespace/name or kind/name
+func (r ResourceRef) String() string {
+	if r.Namespace == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// ParseResourceRef parses kind/namespace/name or kind/name
+func ParseResourceRef(value string) (ResourceRef, bool) {
+	parts := strings.Split(value, "/")
+	for _, part := range parts {
+		if part == "" {
+			return ResourceRef{}, false
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return ResourceRef{Kind: parts[0], Name: parts[1]}, true
+	case 3:
+		return ResourceRef{Kind: parts[0], Namespace: parts[1], Name: parts[2]}, true
+	}
+	return ResourceRef{}, false
+}
+
+// ImplicatedResources returns the resources a result blames, including
+// results decoded from JSON
+func ImplicatedResources(result CheckResult) []ResourceRef {
+	var values []string
+	switch resources := result.Details[DetailImplicatedResources].(type) {
+	case []string:
+		values = resources
+	case []interface{}:
+		for _, value := range resources {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	refs := make([]ResourceRef, 0, len(values))
+	for _, value := range values {
+		if ref, ok := ParseResourceRef(value); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ResourceDescriber fetches describe-equivalent data for implicated
+// resources on demand. Descriptions are cached briefly and concurrent
+// requests for the same resource share one fetch, so repeated analyses of
+// a failure don't query the API server again.
+type ResourceDescriber struct {
+	client kubernetes.Interface
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[ResourceRef]*describeEntry
+}
+
+// describeEntry is a cached description; ready is closed once it's fetched
+type describeEntry struct {
+	ready       chan struct{}
+	description ai.ResourceDescription
+	fetchedAt   time.Time
+}
+
+// NewResourceDescriber creates a describer that reuses descriptions for ttl
+func NewResourceDescriber(client kubernetes.Interface, ttl time.Duration) *ResourceDescriber {
+	if ttl <= 0 {
+		ttl = DefaultDescribeTTL
+	}
+	return &ResourceDescriber{
+		client: client,
+		ttl:    ttl,
+		cache:  make(map[ResourceRef]*describeEntry),
+	}
+}
+
+// Describe returns descriptions of the first few supported resources
+func (d *ResourceDescriber) Describe(ctx context.Context, refs []ResourceRef) []ai.ResourceDescription {
+	if d == nil || d.client == nil {
+		return nil
+	}
+
+	var descriptions []ai.ResourceDescription
+	seen := make(map[ResourceRef]bool)
+	for _, ref := range refs {
+		if len(descriptions) == maxDescribedResources {
+			break
+		}
+		if seen[ref] || !describable(ref) {
+			continue
+		}
+		seen[ref] = true
+		descriptions = append(descriptions, d.describe(ctx, ref))
+	}
+	return descriptions
+}
+
+// describable reports whether the describer knows the resource's kind
+func describable(ref ResourceRef) bool {
+	switch ref.Kind {
+	case "pod", "service":
+		return ref.Namespace != ""
+	case "node":
+		return ref.Namespace == ""
+	}
+	return false
+}
+
+// describe returns the cached description of ref, fetching it when missing
+// or expired
+func (d *ResourceDescriber) describe(ctx context.Context, ref ResourceRef) ai.ResourceDescription {
+	now := time.Now()
+
+	d.mu.Lock()
+	entry, ok := d.cache[ref]
+	if ok {
+		select {
+		case <-entry.ready:
+			if now.Sub(entry.fetchedAt) >= d.ttl {
+				ok = false
+			}
+		default:
+			// Another analysis is fetching it
+		}
+	}
+	if ok {
+		d.mu.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.description
+		case <-ctx.Done():
+			return ai.ResourceDescription{Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name, Error: ctx.Err().Error()}
+		}
+	}
+
+	d.prune(now)
+	entry = &describeEntry{ready: make(chan struct{})}
+	d.cache[ref] = entry
+	d.mu.Unlock()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+	entry.description = d.fetch(fetchCtx, ref)
+	entry.fetchedAt = time.Now()
+	close(entry.ready)
+	return entry.description
+}
+
+// prune drops expired descriptions; callers hold mu
+func (d *ResourceDescriber) prune(now time.Time) {
+	for ref, entry := range d.cache {
+		select {
+		case <-entry.ready:
+			if now.Sub(entry.fetchedAt) >= d.ttl {
+				delete(d.cache, ref)
+			}
+		default:
+		}
+	}
+}
+
+// fetch reads the resource and its events from the API server
+func (d *ResourceDescriber) fetch(ctx context.Context, ref ResourceRef) ai.ResourceDescription {
+	description := ai.ResourceDescription{Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name}
+
+	var kind string
+	var err error
+	switch ref.Kind {
+	case "pod":
+		kind = "Pod"
+		description.Details, err = d.describePod(ctx, ref)
+	case "node":
+		kind = "Node"
+		description.Details, err = d.describeNode(ctx, ref)
+	case "service":
+		kind = "Service"
+		description.Details, err = d.describeService(ctx, ref)
+	case "deployment":
+		kind = "Deployment"
+		description.Details, err = d.describeDeployment(ctx, ref)
+	}
+	if apierrors.IsNotFound(err) {
+		description.Error = "not found; it may have been deleted or replaced"
+		return description
+	}
+	if err != nil {
+		klog.V(2).Infof("Failed to describe %s: %v", ref, err)
+		description.Error = err.Error()
+		return description
+	}
+
+	events, err := d.events(ctx, ref, kind)
+	if err != nil {
+		klog.V(2).Infof("Failed to list events for %s: %v", ref, err)
+	}
+	description.Events = events
+	return description
+}
+
+// describePod summarizes a pod's phase, conditions and containers
+func (d *ResourceDescriber) describePod(ctx context.Context, ref ResourceRef) ([]string, error) {
+	pod, err := d.client.CoreV1().Pods(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	details := []string{"Status: " + withReason(string(pod.Status.Phase), pod.Status.Reason, pod.Status.Message)}
+	if pod.Spec.NodeName != "" {
+		details = append(details, "Node: "+pod.Spec.NodeName)
+	}
+	for _, owner := range pod.OwnerReferences {
+		details = append(details, fmt.Sprintf("Controlled by: %s/%s", owner.Kind, owner.Name))
+	}
+	if pod.Status.QOSClass != "" {
+		details = append(details, "QoS class: "+string(pod.Status.QOSClass))
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			details = append(details, fmt.Sprintf("Condition %s=%s", condition.Type, withReason(string(condition.Status), condition.Reason, condition.Message)))
+		}
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		statuses[status.Name] = status
+	}
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		line := fmt.Sprintf("Container %s: image %s", container.Name, container.Image)
+		if status, ok := statuses[container.Name]; ok {
+			line += fmt.Sprintf(", %s, ready=%t, restarts=%d", containerState(status.State), status.Ready, status.RestartCount)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				line += fmt.Sprintf(", last terminated %s (exit %d) at %s", terminated.Reason, terminated.ExitCode, terminated.FinishedAt.Format(time.RFC3339))
+			}
+		}
+		if resources := formatResources(container.Resources); resources != "" {
+			line += ", " + resources
+		}
+		details = append(details, line)
+	}
+	return details, nil
+}
+
+// describeNode summarizes a node's conditions, taints and allocated resources
+func (d *ResourceDescriber) describeNode(ctx context.Context, ref ResourceRef) ([]string, error) {
+	node, err := d.client.CoreV1().Nodes().Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var details []string
+	for _, condition := range node.Status.Conditions {
+		healthy := condition.Status == corev1.ConditionFalse
+		if condition.Type == corev1.NodeReady {
+			healthy = condition.Status == corev1.ConditionTrue
+		}
+		if !healthy {
+			details = append(details, fmt.Sprintf("Condition %s=%s", condition.Type, withReason(string(condition.Status), condition.Reason, condition.Message)))
+		}
+	}
+	if node.Spec.Unschedulable {
+		details = append(details, "Unschedulable: true")
+	}
+	for _, taint := range node.Spec.Taints {
+		details = append(details, "Taint: "+taint.ToString())
+	}
+	details = append(details, fmt.Sprintf("Allocatable: cpu=%s memory=%s pods=%s",
+		node.Status.Allocatable.Cpu(), node.Status.Allocatable.Memory(), node.Status.Allocatable.Pods()))
+	if version := node.Status.NodeInfo.KubeletVersion; version != "" {
+		details = append(details, "Kubelet: "+version)
+	}
+
+	// Allocated resources, as describe shows them, from the node's pods
+	pods, err := d.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+	})
+	if err != nil {
+		klog.V(2).Infof("Failed to list pods on node %s: %v", node.Name, err)
+		return details, nil
+	}
+	cpu, memory := resource.Quantity{}, resource.Quantity{}
+	count := 0
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != node.Name || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		count++
+		for _, container := range pod.Spec.Containers {
+			cpu.Add(*container.Resources.Requests.Cpu())
+			memory.Add(*container.Resources.Requests.Memory())
+		}
+	}
+	details = append(details, fmt.Sprintf("Allocated: %d pods, cpu requests %s (%s), memory requests %s (%s)",
+		count, cpu.String(), percentOf(cpu, *node.Status.Allocatable.Cpu()), memory.String(), percentOf(memory, *node.Status.Allocatable.Memory())))
+	return details, nil
+}
+
+// describeService summarizes a service's selector and endpoints
+func (d *ResourceDescriber) describeService(ctx context.Context, ref ResourceRef) ([]string, error) {
+	service, err := d.client.CoreV1().Services(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	details := []string{fmt.Sprintf("Type: %s", service.Spec.Type)}
+	if len(service.Spec.Selector) == 0 {
+		details = append(details, "Selector: none")
+	} else {
+		details = append(details, "Selector: "+metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector}))
+	}
+	var ports []string
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%d->%s/%s", port.Port, port.TargetPort.String(), port.Protocol))
+	}
+	if len(ports) > 0 {
+		details = append(details, "Ports: "+strings.Join(ports, ", "))
+	}
+
+	endpoints, err := d.client.CoreV1().Endpoints(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		details = append(details, "Endpoints: none")
+	case err != nil:
+		klog.V(2).Infof("Failed to get endpoints of %s: %v", ref, err)
+	default:
+		ready, notReady := 0, 0
+		for _, subset := range endpoints.Subsets {
+			ready += len(subset.Addresses)
+			notReady += len(subset.NotReadyAddresses)
+		}
+		details = append(details, fmt.Sprintf("Endpoints: %d ready, %d not ready", ready, notReady))
+	}
+
+	if len(service.Spec.Selector) > 0 {
+		pods, err := d.client.CoreV1().Pods(ref.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector}),
+		})
+		if err == nil {
+			details = append(details, fmt.Sprintf("Pods matching selector: %d", len(pods.Items)))
+		}
+	}
+	return details, nil
+}
+
+// describeDeployment summarizes a deployment's replicas, strategy and
+// conditions
+func (d *ResourceDescriber) describeDeployment(ctx context.Context, ref ResourceRef) ([]string, error) {
+	deployment, err := d.client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	details := []string{
+		fmt.Sprintf("Replicas: %d desired, %d updated, %d ready, %d available, %d unavailable",
+			desiredReplicas(deployment), deployment.Status.UpdatedReplicas, deployment.Status.ReadyReplicas,
+			deployment.Status.AvailableReplicas, deployment.Status.UnavailableReplicas),
+		fmt.Sprintf("Strategy: %s", deployment.Spec.Strategy.Type),
+	}
+	if deployment.Spec.Selector != nil {
+		details = append(details, "Selector: "+metav1.FormatLabelSelector(deployment.Spec.Selector))
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		details = append(details, fmt.Sprintf("Container %s: image %s", container.Name, container.Image))
+	}
+	for _, condition := range deployment.Status.Conditions {
+		details = append(details, fmt.Sprintf("Condition %s=%s", condition.Type, withReason(string(condition.Status), condition.Reason, condition.Message)))
+	}
+	return details, nil
+}
+
+// events returns the latest events about the resource, oldest first
+func (d *ResourceDescriber) events(ctx context.Context, ref ResourceRef, kind string) ([]string, error) {
+	selector := fields.Set{"involvedObject.kind": kind, "involvedObject.name": ref.Name}.AsSelector().String()
+	list, err := d.client.CoreV1().Events(ref.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []corev1.Event
+	for _, event := range list.Items {
+		// Field selectors aren't honoured everywhere, so match again
+		if event.InvolvedObject.Kind == kind && event.InvolvedObject.Name == ref.Name {
+			items = append(items, event)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(items[i]).Before(eventTime(items[j]))
+	})
+	if len(items) > maxDescribedEvents {
+		items = items[len(items)-maxDescribedEvents:]
+	}
+
+	events := make([]string, 0, len(items))
+	for _, event := range items {
+		line := fmt.Sprintf("%s %s %s: %s", eventTime(event).Format(time.RFC3339), event.Type, event.Reason, event.Message)
+		if event.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		events = append(events, line)
+	}
+	return events, nil
+}
+
+// eventTime returns when an event last occurred
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// containerState describes a container's current state
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		return "waiting: " + withReason(state.Waiting.Reason, "", state.Waiting.Message)
+	case state.Terminated != nil:
+		return fmt.Sprintf("terminated: %s (exit %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	case state.Running != nil:
+		return "running since " + state.Running.StartedAt.Format(time.RFC3339)
+	}
+	return "state unknown"
+}
+
+// formatResources renders a container's requests and limits
+func formatResources(requirements corev1.ResourceRequirements) string {
+	format := func(label string, list corev1.ResourceList) string {
+		if len(list) == 0 {
+			return ""
+		}
+		names := make([]string, 0, len(list))
+		for name := range list {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			quantity := list[corev1.ResourceName(name)]
+			parts = append(parts, name+"="+quantity.String())
+		}
+		return label + " " + strings.Join(parts, " ")
+	}
+
+	var parts []string
+	for _, part := range []string{format("requests", requirements.Requests), format("limits", requirements.Limits)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// withReason appends a reason and message to a status when present
+func withReason(status, reason, message string) string {
+	switch {
+	case reason != "" && message != "":
+		return fmt.Sprintf("%s (%s: %s)", status, reason, message)
+	case reason != "":
+		return fmt.Sprintf("%s (%s)", status, reason)
+	case message != "":
+		return fmt.Sprintf("%s (%s)", status, message)
+	}
+	return status
+}
+
+// percentOf formats used as a percentage of total
+func percentOf(used, total resource.Quantity) string {
+	if total.IsZero() {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", float64(used.MilliValue())/float64(total.MilliValue())*100)
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestImplicatedResources(t *testing.T) {
+	result := CheckResult{
+		Details: map[string]interface{}{
+			DetailImplicatedResources: []interface{}{"pod/default/web-1", "node/node-1", "bad", "pod//x"},
+		},
+	}
+	want := []ResourceRef{
+		{Kind: "pod", Namespace: "default", Name: "web-1"},
+		{Kind: "node", Name: "node-1"},
+	}
+	if got := ImplicatedResources(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImplicatedResources() = %v, want %v", got, want)
+	}
+
+	for _, ref := range want {
+		if parsed, ok := ParseResourceRef(ref.String()); !ok || parsed != ref {
+			t.Errorf("ParseResourceRef(%q) = %v, %v", ref.String(), parsed, ok)
+		}
+	}
+}
+
+func TestResourceDescriberCachesDescriptions(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "web", Image: "nginx:1.25"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "web",
+				RestartCount: 4,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+			}},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Count:          3,
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	client := fake.NewSimpleClientset(pod, event)
+
+	gets := 0
+	client.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+
+	describer := NewResourceDescriber(client, time.Minute)
+	refs := []ResourceRef{
+		{Kind: "pod", Namespace: "default", Name: "web-1"},
+		{Kind: "pod", Namespace: "default", Name: "web-1"},
+		{Kind: "deployment", Namespace: "default", Name: "web"},
+	}
+
+	descriptions := describer.Describe(context.Background(), refs)
+	if len(descriptions) != 1 {
+		t.Fatalf("expected 1 description, got %d: %+v", len(descriptions), descriptions)
+	}
+	details := strings.Join(descriptions[0].Details, "\n")
+	for _, want := range []string{"Node: node-1", "CrashLoopBackOff", "restarts=4"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("expected details to mention %q, got:\n%s", want, details)
+		}
+	}
+	if len(descriptions[0].Events) != 1 || !strings.Contains(descriptions[0].Events[0], "BackOff") {
+		t.Errorf("expected the BackOff event, got %v", descriptions[0].Events)
+	}
+
+	describer.Describe(context.Background(), refs)
+	if gets != 1 {
+		t.Errorf("expected the cached description to be reused, got %d fetches", gets)
+	}
+}
+
+func TestResourceDescriberMissingResource(t *testing.T) {
+	describer := NewResourceDescriber(fake.NewSimpleClientset(), time.Minute)
+
+	descriptions := describer.Describe(context.Background(), []ResourceRef{{Kind: "node", Name: "gone"}})
+	if len(descriptions) != 1 || !strings.Contains(descriptions[0].Error, "not found") {
+		t.Errorf("expected a not found description, got %+v", descriptions)
+	}
+}
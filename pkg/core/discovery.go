@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	"k8s.io/klog/v2"
+)
+
+// Discoverable is implemented by checks only relevant to clusters running
+// something, such as a service mesh or an operator's custom resources. With
+// check discovery on, the engine runs them only where it finds what they
+// monitor.
+type Discoverable interface {
+	// Relevant reports whether the cluster the inventory describes runs
+	// what the check monitors, and why
+	Relevant(inv *inventory.Inventory) (bool, string)
+}
+
+// CheckDiscovery is the decision whether a check runs in the cluster
+type CheckDiscovery struct {
+	Check    string    `json:"check"`
+	Enabled  bool      `json:"enabled"`
+	Reason   string    `json:"reason"`
+	Override bool      `json:"override,omitempty"` // Decided by configuration rather than discovered
+	Decided  time.Time `json:"decided"`
+}
+
+// checkDiscovery holds the discovery decisions by check name
+type checkDiscovery struct {
+	mu        sync.Mutex
+	enabled   bool
+	overrides map[string]bool
+	decisions map[string]CheckDiscovery
+}
+
+// discoverChecks decides from the inventory which checks run. Overrides
+// win; discoverable checks run when the cluster has what they monitor, or
+// when the inventory is incomplete and can't tell; other checks always run.
+// Checks that stop running have their latest result forgotten.
+func (e *Engine) discoverChecks(inv *inventory.Inventory) {
+	e.discovery.mu.Lock()
+	if !e.discovery.enabled && len(e.discovery.overrides) == 0 {
+		e.discovery.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	decisions := make(map[string]CheckDiscovery, len(e.checks))
+	var disabled []string
+	for _, check := range e.checks {
+		decision := decideCheck(check, inv, e.discovery.enabled, e.discovery.overrides)
+		decision.Decided = now
+		if previous, ok := e.discovery.decisions[decision.Check]; ok && previous.Enabled == decision.Enabled && previous.Reason == decision.Reason {
+			decision.Decided = previous.Decided
+		} else if ok || !decision.Enabled {
+			state := "enabled"
+			if !decision.Enabled {
+				state = "disabled"
+			}
+			klog.Infof("Check %s %s: %s", decision.Check, state, decision.Reason)
+		}
+		if !decision.Enabled {
+			disabled = append(disabled, decision.Check)
+		}
+		decisions[decision.Check] = decision
+	}
+	e.discovery.decisions = decisions
+	e.discovery.mu.Unlock()
+
+	if len(disabled) > 0 {
+		e.resultsMu.Lock()
+		for _, name := range disabled {
+			delete(e.results, name)
+		}
+		e.resultsMu.Unlock()
+	}
+}
+
+// decideCheck decides whether a check runs
+func decideCheck(check HealthCheck, inv *inventory.Inventory, discover bool, overrides map[string]bool) CheckDiscovery {
+	decision := CheckDiscovery{Check: check.Name(), Enabled: true}
+	if enabled, ok := overrides[decision.Check]; ok {
+		decision.Enabled, decision.Override = enabled, true
+		decision.Reason = "Disabled in configuration"
+		if enabled {
+			decision.Reason = "Enabled in configuration"
+		}
+		return decision
+	}
+	discoverable, ok := check.(Discoverable)
+	if !discover || !ok {
+		decision.Reason = "Relevant to every cluster"
+		return decision
+	}
+	decision.Enabled, decision.Reason = discoverable.Relevant(inv)
+	if !decision.Enabled && len(inv.Errors) > 0 {
+		decision.Enabled = true
+		decision.Reason = fmt.Sprintf("%s, but kept enabled because the inventory is incomplete: %s", decision.Reason, strings.Join(inv.Errors, "; "))
+	}
+	return decision
+}
+
+// discoveredOff reports whether discovery or an override disabled a check
+func (e *Engine) discoveredOff(name string) bool {
+	e.discovery.mu.Lock()
+	defer e.discovery.mu.Unlock()
+	decision, ok := e.discovery.decisions[name]
+	return ok && !decision.Enabled
+}
+
+// CheckDiscoveries lists the discovery decisions by check name; empty until
+// the inventory is first collected, or when discovery is off and nothing
+// is overridden
+func (e *Engine) CheckDiscoveries() []CheckDiscovery {
+	e.discovery.mu.Lock()
+	decisions := make([]CheckDiscovery, 0, len(e.discovery.decisions))
+	for _, decision := range e.discovery.decisions {
+		decisions = append(decisions, decision)
+	}
+	e.discovery.mu.Unlock()
+
+	sort.Slice(decisions, func(i, j int) bool { return decisions[i].Check < decisions[j].Check })
+	return decisions
+}
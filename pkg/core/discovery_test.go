@@ -0,0 +1,90 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// groupCheck is relevant where its API group is served
+type groupCheck struct {
+	countingCheck
+	group string
+}
+
+func (c *groupCheck) Relevant(inv *inventory.Inventory) (bool, string) {
+	for _, api := range inv.CustomResources {
+		if api.Group == c.group {
+			return true, c.group + " is served"
+		}
+	}
+	return false, c.group + " is not served"
+}
+
+func TestEngine_DiscoverChecks(t *testing.T) {
+	withVelero := &inventory.Inventory{CustomResources: []inventory.CustomResourceAPI{{Group: "velero.io"}}}
+	tests := []struct {
+		name      string
+		discovery bool
+		overrides map[string]bool
+		inv       *inventory.Inventory
+		want      map[string]bool // Enabled by check
+	}{
+		{"off", false, nil, &inventory.Inventory{}, map[string]bool{}},
+		{"not installed", true, nil, &inventory.Inventory{}, map[string]bool{"pod-health": true, "velero": false, "service-mesh": false}},
+		{"installed", true, nil, withVelero, map[string]bool{"pod-health": true, "velero": true, "service-mesh": false}},
+		{"incomplete inventory", true, nil, &inventory.Inventory{Errors: []string{"custom resources: forbidden"}}, map[string]bool{"pod-health": true, "velero": true, "service-mesh": true}},
+		{"overridden", true, map[string]bool{"service-mesh": true, "pod-health": false}, withVelero, map[string]bool{"pod-health": false, "velero": true, "service-mesh": true}},
+		{"overridden without discovery", false, map[string]bool{"velero": false}, withVelero, map[string]bool{"pod-health": true, "velero": false, "service-mesh": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), CheckDiscovery: tt.discovery, CheckOverrides: tt.overrides})
+			checks := map[string]*countingCheck{}
+			for _, check := range []HealthCheck{
+				&countingCheck{mockHealthCheck: mockHealthCheck{name: "pod-health"}},
+				&groupCheck{countingCheck: countingCheck{mockHealthCheck: mockHealthCheck{name: "velero"}}, group: "velero.io"},
+				&groupCheck{countingCheck: countingCheck{mockHealthCheck: mockHealthCheck{name: "service-mesh"}}, group: "networking.istio.io"},
+			} {
+				engine.AddCheck(check)
+				switch c := check.(type) {
+				case *countingCheck:
+					checks[c.name] = c
+				case *groupCheck:
+					checks[c.name] = &c.countingCheck
+				}
+			}
+			engine.runChecks()
+			engine.discoverChecks(tt.inv)
+
+			decisions := engine.CheckDiscoveries()
+			if len(decisions) != len(tt.want) {
+				t.Fatalf("expected %d decisions, got %+v", len(tt.want), decisions)
+			}
+			for _, decision := range decisions {
+				if decision.Enabled != tt.want[decision.Check] || decision.Reason == "" || decision.Decided.IsZero() {
+					t.Errorf("unexpected decision %+v", decision)
+				}
+				if _, ok := tt.overrides[decision.Check]; ok != decision.Override {
+					t.Errorf("expected override %v, got %+v", ok, decision)
+				}
+				if _, ok := engine.GetResult(decision.Check); ok != decision.Enabled {
+					t.Errorf("expected the result of disabled checks forgotten, %s has one: %v", decision.Check, ok)
+				}
+			}
+
+			// Disabled checks no longer run
+			engine.runChecks()
+			for name, check := range checks {
+				wantRuns := int32(2)
+				if enabled, ok := tt.want[name]; ok && !enabled {
+					wantRuns = 1
+				}
+				if runs := check.runs.Load(); runs != wantRuns {
+					t.Errorf("expected %s run %d times, got %d", name, wantRuns, runs)
+				}
+			}
+		})
+	}
+}
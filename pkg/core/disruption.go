@@ -0,0 +1,33 @@
+package core
+
+// Check result details describing disruption from planned maintenance, such
+// as cordoned or draining nodes and announced maintenance windows
+const (
+	// DetailExpectedDisruptions lists issues caused by planned maintenance;
+	// they are reported but don't count against health
+	DetailExpectedDisruptions = "expected_disruptions"
+	// DetailImpact classifies a result's impact when it isn't a plain failure
+	DetailImpact = "impact"
+)
+
+// ImpactExpectedDisruption marks a result whose only issues come from
+// planned maintenance
+const ImpactExpectedDisruption = "expected_disruption"
+
+// ExpectedDisruptions returns the disruptions a result attributes to planned
+// maintenance, including results decoded from JSON
+func ExpectedDisruptions(result CheckResult) []string {
+	switch disruptions := result.Details[DetailExpectedDisruptions].(type) {
+	case []string:
+		return disruptions
+	case []interface{}:
+		values := make([]string, 0, len(disruptions))
+		for _, disruption := range disruptions {
+			if value, ok := disruption.(string); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExpectedDisruptions(t *testing.T) {
+	result := CheckResult{
+		Name:    "node-health",
+		Details: map[string]interface{}{DetailExpectedDisruptions: []string{"node-2: NotReady (cordoned)"}},
+	}
+	want := []string{"node-2: NotReady (cordoned)"}
+	if got := ExpectedDisruptions(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpectedDisruptions() = %v, want %v", got, want)
+	}
+
+	// Results read back from JSON, e.g. recordings, hold []interface{}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if got := ExpectedDisruptions(decoded); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpectedDisruptions() after JSON = %v, want %v", got, want)
+	}
+
+	if got := ExpectedDisruptions(CheckResult{}); got != nil {
+		t.Errorf("expected nil without disruptions, got %v", got)
+	}
+}
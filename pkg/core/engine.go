@@ -2,9 +2,12 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/ai"
@@ -22,7 +25,12 @@ type Engine struct {
 	checks         []HealthCheck
 	interval       time.Duration
 	results        map[string]CheckResult
+	failingSince   map[string]time.Time            // When each failing check started failing
+	diagnoses      map[string]*ai.AnalysisResponse // Latest AI diagnosis of each failing check
 	resultsMu      sync.RWMutex
+	metricHistory  map[string][]Metric
+	maxHistory     int
+	historyMu      sync.RWMutex
 	ctx            context.Context
 	cancel         context.CancelFunc
 	alertChan      chan Alert
@@ -31,7 +39,55 @@ type Engine struct {
 	anomalyEngine  *ml.AnomalyDetector
 	sloTracker     *slo.Tracker
 	aiClient       *ai.Client
+	aiQueue        *AIQueue
+	aiMinSeverity  AlertSeverity
+	toolLimiter    *ai.ToolLimiter
+	toolCache      *ai.ToolCache
+	nativeTools    *nativeTools
 	errorHandler   *ErrorHandler
+	checkTimeout   time.Duration
+	watchdog       *Watchdog
+	journal        *Journal
+	changes        *ChangeLog
+	analyses       *AnalysisLog
+	analysisQueue  *AnalysisQueue
+	investigations *InvestigationLog
+	kubectl        ai.CommandExecutor // Runs the read-only steps of investigation plans
+	recorder       *CheckRecorder
+	history        *ResultHistory
+	findings       *FindingTracker
+	cardinality    *CardinalityLimiter
+	describer      *ResourceDescriber
+	runbooks       map[string]string
+	readOnly       bool
+
+	expensive         map[string]bool // Checks run on the expensive cadence
+	expensiveInterval time.Duration
+	adaptive          *adaptiveScheduler // Per-check intervals; nil when disabled
+	inventory         clusterInventory
+
+	metricConditions []MetricCondition
+	generation       atomic.Uint64 // Bumped whenever results change
+	stateRevision    atomic.Uint64 // Bumped when AI insights go stale; see StateRevision
+	checkRuns        atomic.Int64  // Check executions since start
+	checkErrors      atomic.Int64  // Executions that returned an error
+	cycleStarted     atomic.Int64  // Unix nanoseconds the running cycle started, 0 between cycles
+	lastCycle        atomic.Int64  // Duration of the last completed cycle
+	summary          summaryCache
+	summaryMu        sync.Mutex
+	ruleSuggestions  ruleSuggestions
+	maintenance      checkMaintenance
+	annotations      annotationLog
+	restrictions     checkRestrictions
+	discovery        checkDiscovery
+	shedding         loadShedding
+	chaos            chaosState
+	governance       governanceLog
+	remediations     remediationLog
+	latency          pipelineLatency
+	evaluation       modelEvaluation
+	namespaces       *namespaceTracker
+	hooks            hooks
 
 	// New AI components
 	predictiveAnalyzer *ai.PredictiveAnalyzer
@@ -47,10 +103,121 @@ type EngineConfig struct {
 	Interval    time.Duration
 	AlertChan   chan Alert
 	MetricsChan chan Metric
+	MaxHistory  int // Data points retained per metric series
 	EnableAI    bool
 	AIConfig    *ai.Config
+
+	CheckTimeout       time.Duration // Context timeout for a single check run
+	WatchdogMultiplier float64       // Abandon checks running longer than this many timeouts
+
+	AIQueueCapacity map[AlertSeverity]int // Pending AI events kept per severity
+	AIWorkers       int                   // Concurrent AI analyses; defaults to 2
+
+	// MaxConcurrentAnalyses bounds the on-demand cluster and batch analyses
+	// running at once per cluster; more are queued. Defaults to
+	// DefaultMaxConcurrentAnalyses.
+	MaxConcurrentAnalyses int
+
+	// ToolLimits rate-limits AI-driven kubectl commands; zero values share
+	// the process-wide limiter with its defaults
+	ToolLimits ai.ToolLimiterConfig
+
+	// ToolCache bounds how long read-only kubectl output is reused across
+	// AI endpoints; zero values share the process-wide cache
+	ToolCache ai.ToolCacheConfig
+
+	// NativeTools answers investigation steps that get pods, nodes,
+	// deployments or events from informer caches rather than kubectl
+	NativeTools bool
+
+	// EvaluationVariants, when two are set, also answer sampled automatic
+	// diagnoses with both models so they can be compared
+	EvaluationVariants []ai.EvaluationVariant
+
+	// EvaluationSampleRate is the share of automatic diagnoses evaluated;
+	// all of them when zero
+	EvaluationSampleRate float64
+
+	// Recorder, when set, records the API responses every check run reads so
+	// the run can be replayed; its transport must wrap KubeClient's
+	Recorder *CheckRecorder
+
+	// Runbooks maps check names to runbook URLs attached to their alerts
+	// and AI diagnoses
+	Runbooks map[string]string
+
+	// ReadOnly refuses remediation and keeps the AI CLI from running
+	// commands, so the engine never modifies the cluster
+	ReadOnly bool
+
+	// SLOs are tracked from the metrics of checks and ingested application
+	// metrics
+	SLOs []slo.SLO
+
+	// MetricConditions set the status of ingested application metrics;
+	// invalid conditions are skipped with an error logged
+	MetricConditions []MetricCondition
+
+	// History keeps past check results for time-travel inspection; an
+	// in-memory history is used when nil
+	History *ResultHistory
+
+	// Findings tracks the lifecycle of findings checks and AI diagnoses
+	// report; an in-memory tracker is used when nil
+	Findings *FindingTracker
+
+	// Cardinality bounds the series each metric name can create; zero
+	// values use the default limits
+	Cardinality CardinalityConfig
+
+	// ExpensiveChecks names checks too costly for every cycle; they run
+	// every ExpensiveInterval (default 10m) instead of every Interval
+	ExpensiveChecks   []string
+	ExpensiveInterval time.Duration
+
+	// Adaptive shortens the interval of failing checks and lengthens it
+	// for long-healthy ones; zero values keep every check on Interval
+	Adaptive AdaptiveConfig
+
+	// InventoryInterval is how often the cluster inventory is refreshed;
+	// DefaultInventoryInterval when zero
+	InventoryInterval time.Duration
+
+	// PermissionRecheckInterval is how often checks disabled for lack of
+	// permissions are retried; DefaultPermissionRecheckInterval when zero
+	PermissionRecheckInterval time.Duration
+
+	// CheckDiscovery runs Discoverable checks only in clusters the inventory
+	// shows have what they monitor, deciding again on every inventory
+	// refresh. CheckOverrides force checks on or off by name either way.
+	CheckDiscovery bool
+	CheckOverrides map[string]bool
+
+	// LoadSheddingFactor is how many intervals apart checks run while load
+	// is shed; DefaultLoadSheddingFactor when zero
+	LoadSheddingFactor int
+
+	// ChaosAutoSilence silences the alerts attributed to a chaos experiment
+	// while it runs; see SetChaosExperiments
+	ChaosAutoSilence bool
+
+	// LatencyBudget alerts through the pipeline-latency check when
+	// detection latency exceeds it; a zero budget only measures latency
+	LatencyBudget LatencyBudget
+
+	// Environment adjusts alert severities, cooldowns, channels and
+	// escalation to the class of the monitored cluster, such as prod or dev
+	Environment alerts.Environment
+
+	// AIMinSeverity leaves failures below it out of automatic AI analysis;
+	// empty analyzes every failure
+	AIMinSeverity AlertSeverity
 }
 
+// ErrReadOnly is returned when an action that modifies the cluster is
+// requested in read-only mode
+var ErrReadOnly = errors.New("read-only mode")
+
 // NewEngine creates a new monitoring engine
 func NewEngine(config EngineConfig) *Engine {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -58,6 +225,33 @@ func NewEngine(config EngineConfig) *Engine {
 	if config.Interval == 0 {
 		config.Interval = 30 * time.Second
 	}
+	if config.MaxHistory <= 0 {
+		config.MaxHistory = 1000
+	}
+	if config.CheckTimeout <= 0 {
+		config.CheckTimeout = 30 * time.Second
+	}
+	if config.History == nil {
+		config.History, _ = NewResultHistory("", DefaultHistoryRetention)
+	}
+	if config.ExpensiveInterval <= 0 {
+		config.ExpensiveInterval = DefaultExpensiveInterval
+	}
+	if config.InventoryInterval <= 0 {
+		config.InventoryInterval = DefaultInventoryInterval
+	}
+	if config.PermissionRecheckInterval <= 0 {
+		config.PermissionRecheckInterval = DefaultPermissionRecheckInterval
+	}
+	if config.LoadSheddingFactor <= 0 {
+		config.LoadSheddingFactor = DefaultLoadSheddingFactor
+	}
+	if config.Findings == nil {
+		config.Findings, _ = NewFindingTracker("", DefaultFindingRetention)
+	}
+	if config.LatencyBudget.Percentile <= 0 {
+		config.LatencyBudget.Percentile = DefaultLatencyPercentile
+	}
 
 	// Initialize alert manager with default rules
 	alertManager := alerts.NewManager()
@@ -65,6 +259,15 @@ func NewEngine(config EngineConfig) *Engine {
 	for _, rule := range alerts.CreateDefaultRules() {
 		alertManager.AddRule(rule)
 	}
+	for _, rule := range alerts.CreateEventRateRules() {
+		alertManager.AddRule(rule)
+	}
+	if config.LatencyBudget.Detection > 0 {
+		alertManager.AddRule(alerts.NewLatencyBudgetRule(LatencyBudgetCheck))
+	}
+	if err := alertManager.SetEnvironment(config.Environment); err != nil {
+		klog.Errorf("Ignoring cluster environment: %v", err)
+	}
 
 	// Initialize error handler with callback for critical errors
 	errorHandler := NewErrorHandler(1000, func(err EngineError) {
@@ -79,6 +282,10 @@ func NewEngine(config EngineConfig) *Engine {
 		checks:         make([]HealthCheck, 0),
 		interval:       config.Interval,
 		results:        make(map[string]CheckResult),
+		failingSince:   make(map[string]time.Time),
+		diagnoses:      make(map[string]*ai.AnalysisResponse),
+		metricHistory:  make(map[string][]Metric),
+		maxHistory:     config.MaxHistory,
 		ctx:            ctx,
 		cancel:         cancel,
 		alertChan:      config.AlertChan,
@@ -87,6 +294,50 @@ func NewEngine(config EngineConfig) *Engine {
 		anomalyEngine:  ml.NewAnomalyDetector(),
 		sloTracker:     slo.NewTracker(),
 		errorHandler:   errorHandler,
+		checkTimeout:   config.CheckTimeout,
+		watchdog:       NewWatchdog(config.WatchdogMultiplier),
+		journal:        NewJournal(config.MaxHistory),
+		changes:        NewChangeLog(config.MaxHistory),
+		analyses:       NewAnalysisLog(defaultAnalysisSessions),
+		investigations: NewInvestigationLog(defaultInvestigations),
+		recorder:       config.Recorder,
+		history:        config.History,
+		findings:       config.Findings,
+		cardinality:    NewCardinalityLimiter(config.Cardinality),
+		describer:      NewResourceDescriber(config.KubeClient, DefaultDescribeTTL),
+		runbooks:       config.Runbooks,
+		readOnly:       config.ReadOnly,
+
+		expensive:         make(map[string]bool, len(config.ExpensiveChecks)),
+		expensiveInterval: config.ExpensiveInterval,
+		adaptive:          newAdaptiveScheduler(config.Adaptive, config.Interval),
+		inventory:         clusterInventory{interval: config.InventoryInterval},
+		restrictions:      checkRestrictions{interval: config.PermissionRecheckInterval},
+		discovery:         checkDiscovery{enabled: config.CheckDiscovery, overrides: config.CheckOverrides},
+		shedding:          loadShedding{factor: config.LoadSheddingFactor},
+		chaos:             chaosState{autoSilence: config.ChaosAutoSilence},
+		latency:           pipelineLatency{budget: config.LatencyBudget},
+		namespaces:        newNamespaceTracker(),
+	}
+	for _, name := range config.ExpensiveChecks {
+		engine.expensive[name] = true
+	}
+	engine.analysisQueue = NewAnalysisQueue(ctx, config.MaxConcurrentAnalyses, func(run AnalysisRun) {
+		engine.journal.Append(StreamEventAnalysisRun, run)
+	})
+
+	alertManager.OnFire(engine.observeFiredAlert)
+	alertManager.OnDeliver(engine.observeDelivery)
+
+	for _, definition := range config.SLOs {
+		engine.addSLO(definition)
+	}
+	for _, condition := range config.MetricConditions {
+		if err := condition.Validate(); err != nil {
+			klog.Errorf("Skipping metric condition: %v", err)
+			continue
+		}
+		engine.metricConditions = append(engine.metricConditions, condition)
 	}
 
 	// Initialize AI client if enabled
@@ -95,7 +346,26 @@ func NewEngine(config EngineConfig) *Engine {
 		if aiConfig == nil {
 			aiConfig = &ai.Config{} // Use defaults
 		}
-		engine.aiClient = ai.NewClient(*aiConfig)
+		clientConfig := *aiConfig
+		clientConfig.ReadOnly = clientConfig.ReadOnly || config.ReadOnly
+		engine.aiClient = ai.NewClient(clientConfig)
+
+		if len(config.EvaluationVariants) > 0 {
+			variants := make([]ai.EvaluationVariant, len(config.EvaluationVariants))
+			for i, variant := range config.EvaluationVariants {
+				variant.Config.ReadOnly = variant.Config.ReadOnly || config.ReadOnly
+				variants[i] = variant
+			}
+			evaluator, err := ai.NewEvaluator(variants, 0)
+			if err != nil {
+				klog.Errorf("AI model evaluation disabled: %v", err)
+			}
+			engine.evaluation.evaluator = evaluator
+			engine.evaluation.rate = config.EvaluationSampleRate
+			if engine.evaluation.rate <= 0 {
+				engine.evaluation.rate = 1
+			}
+		}
 
 		// Initialize AI components
 		engine.predictiveAnalyzer = ai.NewPredictiveAnalyzer(engine.aiClient)
@@ -104,15 +374,84 @@ func NewEngine(config EngineConfig) *Engine {
 
 		// Initialize remediation engine with safety checks
 		executor := ai.NewKubectlExecutor("")
+		engine.toolLimiter = ai.SharedToolLimiter()
+		if config.ToolLimits != (ai.ToolLimiterConfig{}) {
+			engine.toolLimiter = ai.NewToolLimiter(config.ToolLimits)
+			executor.SetLimiter(engine.toolLimiter)
+		}
+		engine.toolCache = ai.SharedToolCache()
+		if config.ToolCache != (ai.ToolCacheConfig{}) {
+			engine.toolCache = ai.NewToolCache(config.ToolCache)
+			executor.SetCache(engine.toolCache)
+		}
+		executor.SetCluster(config.ContextName)
 		safetyChecker := ai.NewDefaultSafetyChecker()
 		engine.remediationEngine = ai.NewRemediationEngine(engine.aiClient, executor, safetyChecker)
+		engine.kubectl = executor
+
+		// Analyze failures from a priority queue so critical events go first
+		engine.aiQueue = NewAIQueue(config.AIQueueCapacity)
+		engine.aiMinSeverity = config.AIMinSeverity
+		workers := config.AIWorkers
+		if workers <= 0 {
+			workers = 2
+		}
+		for i := 0; i < workers; i++ {
+			go engine.runAIWorker()
+		}
 
 		klog.Info("AI-powered diagnostics enabled with predictive analytics, assistant, and auto-remediation")
 	}
+	if engine.kubectl == nil {
+		executor := ai.NewKubectlExecutor("")
+		executor.SetCluster(config.ContextName)
+		engine.toolCache = ai.SharedToolCache()
+		if config.ToolCache != (ai.ToolCacheConfig{}) {
+			engine.toolCache = ai.NewToolCache(config.ToolCache)
+			executor.SetCache(engine.toolCache)
+		}
+		engine.kubectl = executor
+	}
+	if config.NativeTools && config.KubeClient != nil {
+		engine.nativeTools = newNativeTools(engine.kubectl)
+		engine.kubectl = engine.nativeTools
+	}
 
 	return engine
 }
 
+// ReadOnly reports whether the engine runs in read-only mode
+func (e *Engine) ReadOnly() bool {
+	return e.readOnly
+}
+
+// AIEnabled reports whether AI diagnosis, the assistant and remediation are
+// available
+func (e *Engine) AIEnabled() bool {
+	return e.aiClient != nil
+}
+
+// AIAvailable reports whether AI is enabled and its provider is healthy,
+// rather than backing off after repeated failures
+func (e *Engine) AIAvailable() bool {
+	return e.aiClient != nil && e.aiClient.Available()
+}
+
+// PredictiveEnabled reports whether predictive insights can be generated
+func (e *Engine) PredictiveEnabled() bool {
+	return e.predictiveAnalyzer != nil
+}
+
+// SmartAlertsEnabled reports whether smart alert insights can be generated
+func (e *Engine) SmartAlertsEnabled() bool {
+	return e.smartAlertManager != nil
+}
+
+// RemediationEnabled reports whether remediations can be suggested
+func (e *Engine) RemediationEnabled() bool {
+	return e.remediationEngine != nil
+}
+
 // AddCheck adds a health check to the engine
 func (e *Engine) AddCheck(check HealthCheck) {
 	e.checks = append(e.checks, check)
@@ -133,11 +472,39 @@ func (e *Engine) RemoveCheck(name string) error {
 func (e *Engine) Start() error {
 	klog.Info("Starting monitoring engine")
 
+	// Tell on-call services KubePulse is alive
+	e.alertManager.RunHeartbeats(e.ctx)
+
+	// Retry notifications channels failed to accept
+	e.alertManager.RunDeliveries(e.ctx)
+
+	// Run expensive checks on their own cadence
+	go e.runExpensiveChecks()
+
+	// Take stock of the cluster on a slow cadence
+	go e.runInventory()
+
+	// Keep namespace rollups current from pod and claim events
+	if e.client != nil {
+		go e.runNamespaceTracker()
+	}
+
+	// Fill the caches investigation steps are answered from
+	if e.nativeTools != nil {
+		go e.nativeTools.run(e.ctx, e.client)
+	}
+
 	// Run initial checks
 	e.runChecks()
 
-	// Start periodic monitoring
-	ticker := time.NewTicker(e.interval)
+	// Start periodic monitoring; with adaptive intervals, tick as often as
+	// the shortest interval and run whichever checks are due
+	tick := e.interval
+	if e.adaptive != nil {
+		tick = e.adaptive.config.MinInterval
+		klog.Infof("Adapting check intervals between %s and %s", tick, e.adaptive.config.MaxInterval)
+	}
+	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
 	for {
@@ -157,24 +524,100 @@ func (e *Engine) Stop() {
 	e.cancel()
 }
 
-// runChecks executes all health checks in parallel
+// runChecks executes all but the expensive health checks in parallel
 func (e *Engine) runChecks() {
+	if e.skipShedCycle() {
+		return
+	}
+	regular, _ := e.checksByCadence()
+	if e.adaptive != nil {
+		regular = e.adaptive.due(regular, time.Now())
+		if len(regular) == 0 {
+			e.processEscalations()
+			return
+		}
+	}
+
+	start := time.Now()
+	e.cycleStarted.Store(start.UnixNano())
+	defer func() {
+		e.lastCycle.Store(int64(time.Since(start)))
+		e.cycleStarted.Store(0)
+	}()
+
+	e.executeChecks(regular)
+	if e.adaptive != nil {
+		e.adaptIntervals(regular)
+	}
+	e.processEscalations()
+	e.checkLatencyBudget()
+	e.checkSLOs()
+
+	e.recordMetrics(e.watchdog.Metrics())
+	if e.aiQueue != nil {
+		e.recordMetrics(e.aiQueue.Metrics())
+	}
+	if e.toolLimiter != nil {
+		e.recordMetrics(toolLimiterMetrics(e.toolLimiter.Stats()))
+	}
+	e.recordMetrics(toolCacheMetrics(e.toolCache.Stats()))
+	e.recordMetrics(e.loadSheddingMetrics())
+	e.trackNodes()
+	e.generation.Add(1)
+}
+
+// CycleDuration returns how long the running check cycle has taken so far,
+// or the last cycle's duration between cycles, so a stuck cycle shows up
+// before it completes
+func (e *Engine) CycleDuration() time.Duration {
+	if started := e.cycleStarted.Load(); started != 0 {
+		return time.Since(time.Unix(0, started))
+	}
+	return time.Duration(e.lastCycle.Load())
+}
+
+// executeChecks runs checks in parallel, then stores and processes their
+// results
+func (e *Engine) executeChecks(checks []HealthCheck) {
 	var wg sync.WaitGroup
-	resultsChan := make(chan CheckResult, len(e.checks))
+	resultsChan := make(chan CheckResult, len(checks))
 
-	for _, check := range e.checks {
+	for _, check := range checks {
 		wg.Add(1)
 		go func(hc HealthCheck) {
 			defer wg.Done()
 
-			start := time.Now()
-			ctx, cancel := context.WithTimeout(e.ctx, 30*time.Second)
-			defer cancel()
+			// Don't pile up goroutines behind a check the watchdog already gave up on
+			if e.watchdog.InFlight(hc.Name()) {
+				resultsChan <- CheckResult{
+					Name:      hc.Name(),
+					Status:    HealthStatusUnknown,
+					Message:   "Check skipped: previous execution is still stuck",
+					Timestamp: time.Now(),
+				}
+				return
+			}
 
-			result, err := hc.Check(ctx, e.client)
-			result.Duration = time.Since(start)
+			// Checks refused access aren't run again until their recheck
+			if result, skip := e.restrictedResult(hc.Name(), time.Now()); skip {
+				resultsChan <- result
+				return
+			}
 
-			if err != nil {
+			start := time.Now()
+			result, err := e.executeCheck(hc)
+			result.Duration = time.Since(start)
+			e.checkRuns.Add(1)
+
+			switch {
+			case IsPermissionError(err):
+				// Refusals repeat every run until permissions change, so
+				// the check is disabled rather than failing each cycle
+				now := time.Now()
+				result = e.restrict(hc.Name(), err, now).result(now)
+				result.Duration = time.Since(start)
+			case err != nil:
+				e.checkErrors.Add(1)
 				result.Status = HealthStatusUnknown
 				result.Error = err
 				result.Message = fmt.Sprintf("Check failed: %v", err)
@@ -190,6 +633,8 @@ func (e *Engine) runChecks() {
 				if handleErr := e.errorHandler.Handle(engineErr); handleErr != nil {
 					klog.Errorf("Critical health check failure: %v", handleErr)
 				}
+			default:
+				e.unrestrict(hc.Name())
 			}
 
 			resultsChan <- result
@@ -203,41 +648,154 @@ func (e *Engine) runChecks() {
 
 	// Collect results
 	for result := range resultsChan {
+		result.Metrics = e.cardinality.Apply(result.Metrics)
+		result = e.withMaintenance(result, time.Now())
+		result = e.withAnnotations(result)
+		result = e.withChaos(result)
 		e.storeResult(result)
 		e.processResult(result)
 	}
+	e.notifyHealthChange()
+}
+
+// executeCheck runs a single check under the watchdog, returning early if the
+// check is abandoned; an abandoned check's goroutine exits whenever its call returns
+func (e *Engine) executeCheck(hc HealthCheck) (CheckResult, error) {
+	type outcome struct {
+		result CheckResult
+		err    error
+	}
+
+	exec := e.watchdog.Begin(hc.Name(), e.checkTimeout)
+	done := make(chan outcome, 1)
+
+	go func() {
+		defer e.watchdog.Finish(exec)
+
+		ctx, cancel := context.WithTimeout(e.ctx, e.checkTimeout)
+		defer cancel()
+
+		if e.recorder != nil {
+			_, result, err := e.recorder.Run(ctx, hc, e.client, e.currentContext)
+			done <- outcome{result: result, err: err}
+			return
+		}
+		result, err := hc.Check(ctx, e.client)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-exec.Abandoned():
+		return CheckResult{Name: hc.Name(), Timestamp: time.Now()},
+			fmt.Errorf("check abandoned by watchdog after %v (timeout %v)",
+				time.Since(exec.start).Round(time.Second), e.checkTimeout)
+	}
+}
+
+// GetStuckChecks returns check executions that exceeded their watchdog deadline
+func (e *Engine) GetStuckChecks() []StuckCheck {
+	return e.watchdog.Stuck()
+}
+
+// GetWatchdogMetrics returns stuck and abandoned check metrics
+func (e *Engine) GetWatchdogMetrics() []Metric {
+	return e.watchdog.Metrics()
+}
+
+// GetCardinality returns the series and label values seen per metric and
+// how often their limits were hit
+func (e *Engine) GetCardinality() []MetricCardinality {
+	return e.cardinality.Stats()
+}
+
+// GetCardinalityMetrics exports the series count and limited points per
+// metric for Prometheus
+func (e *Engine) GetCardinalityMetrics() []Metric {
+	return e.cardinality.Metrics()
+}
+
+// CardinalityWarnings describes the metrics that hit a cardinality limit
+func (e *Engine) CardinalityWarnings() []string {
+	return e.cardinality.Warnings()
 }
 
 // storeResult saves a check result
 func (e *Engine) storeResult(result CheckResult) {
 	e.resultsMu.Lock()
-	defer e.resultsMu.Unlock()
+	previous, existed := e.results[result.Name]
 	e.results[result.Name] = result
+	e.trackFailure(result)
+	if existed && previous.Status != result.Status {
+		// A diagnosis of the previous status no longer applies
+		delete(e.diagnoses, result.Name)
+	}
+	e.resultsMu.Unlock()
+
+	e.history.Record(result)
+	e.findings.ObserveResult(result)
+	e.recordStatusChange(previous, existed, result)
+	e.observeDetection(previous, existed, result)
+}
+
+// runbookFor returns the runbook for a check result: one annotated on the
+// affected resources, otherwise the runbook configured for the check
+func (e *Engine) runbookFor(result CheckResult) string {
+	if runbook, ok := result.Details["runbook"].(string); ok && runbook != "" {
+		return runbook
+	}
+	return e.runbooks[result.Name]
 }
 
 // processResult handles alerts and metrics from a check result
 func (e *Engine) processResult(result CheckResult) {
-	// Run AI analysis for failed health checks
-	if e.aiClient != nil && (result.Status == HealthStatusUnhealthy || result.Status == HealthStatusDegraded) {
-		go e.runAIAnalysis(result)
+	// Run AI analysis for failed health checks unless load is shed
+	if e.aiQueue != nil && !e.LoadShedding() && (result.Status == HealthStatusUnhealthy || result.Status == HealthStatusDegraded) {
+		if severity := e.getSeverity(result); aiSeverityAtLeast(severity, e.aiMinSeverity) {
+			e.aiQueue.Push(result, severity)
+		}
 	}
 
-	// Convert to alerts.CheckResult to avoid import cycle
-	alertResult := alerts.CheckResult{
-		Name:      result.Name,
-		Status:    alerts.HealthStatus(result.Status),
-		Message:   result.Message,
-		Details:   result.Details,
-		Timestamp: result.Timestamp,
+	// Failures of checks under maintenance don't alert; healthy results
+	// still go through so alerts opened before the maintenance resolve
+	alerting := isAlerting(result.Status) && !InMaintenance(result)
+	if alerting || !isAlerting(result.Status) {
+		// Convert to alerts.CheckResult to avoid import cycle
+		alertResult := alerts.CheckResult{
+			Name:      result.Name,
+			Status:    alerts.HealthStatus(result.Status),
+			Message:   result.Message,
+			Details:   result.Details,
+			Timestamp: result.Timestamp,
+			Runbook:   e.runbookFor(result),
+			Cluster:   e.currentContext,
+			Diagnosis: e.alertDiagnosis(result.Name),
+			Owners:    Owners(result),
+		}
+		alertResult.ObservedAt, _ = ObservedAt(result)
+		if experiments := ChaosExperimentsOf(result); len(experiments) > 0 {
+			alertResult.Chaos = experiments[0]
+		}
+
+		// Process through alert manager
+		if err := e.alertManager.ProcessCheckResult(e.ctx, alertResult); err != nil {
+			klog.Errorf("Failed to process alert: %v", err)
+		}
 	}
 
-	// Process through alert manager
-	if err := e.alertManager.ProcessCheckResult(e.ctx, alertResult); err != nil {
-		klog.Errorf("Failed to process alert: %v", err)
+	// Keep metric history for trend and rate queries, and feed the SLOs
+	// selecting any of the metrics
+	e.recordMetrics(result.Metrics)
+	e.sloTracker.Observe(sloMetrics(result.Metrics))
+	if !InMaintenance(result) {
+		e.sloTracker.ObserveCheck(result.Name, result.Status != HealthStatusUnhealthy, result.Timestamp)
 	}
 
-	// Run anomaly detection on metrics
-	if len(result.Metrics) > 0 {
+	// Run anomaly detection on metrics. Annotated periods such as load
+	// tests are expected to look unusual, so they neither flag anomalies
+	// nor skew the baselines.
+	if len(result.Metrics) > 0 && len(AnnotationsOf(result)) == 0 {
 		// Convert metrics to ML format
 		mlMetrics := make([]ml.Metric, len(result.Metrics))
 		for i, metric := range result.Metrics {
@@ -260,25 +818,28 @@ func (e *Engine) processResult(result CheckResult) {
 				Status:      HealthStatus(pred.Status),
 				Probability: pred.Probability,
 				Reason:      pred.Reason,
+				Explanation: pred.Explanation,
 			}
 		}
 		result.Predictions = corePredictions
 	}
 
 	// Send to channels for backward compatibility
-	if result.Status == HealthStatusUnhealthy || result.Status == HealthStatusDegraded {
-		if e.alertChan != nil {
-			alert := Alert{
-				ID:        fmt.Sprintf("%s-%d", result.Name, time.Now().Unix()),
-				Name:      result.Name,
-				Severity:  e.getSeverity(result),
-				Message:   result.Message,
-				Details:   result.Details,
-				Source:    "kubepulse",
-				Timestamp: result.Timestamp,
-				Status:    AlertStatusFiring,
-			}
+	if alerting {
+		alert := Alert{
+			ID:        fmt.Sprintf("%s-%d", result.Name, time.Now().Unix()),
+			Name:      result.Name,
+			Severity:  e.getSeverity(result),
+			Message:   result.Message,
+			Details:   result.Details,
+			Source:    "kubepulse",
+			Timestamp: result.Timestamp,
+			Status:    AlertStatusFiring,
+		}
+		e.journal.Append(StreamEventAlert, alert)
+		runHooks(&e.hooks, &e.hooks.alert, "OnAlert", alert)
 
+		if e.alertChan != nil {
 			select {
 			case e.alertChan <- alert:
 			case <-time.After(time.Second):
@@ -297,6 +858,15 @@ func (e *Engine) processResult(result CheckResult) {
 			}
 		}
 	}
+
+	e.journal.Append(StreamEventCheckResult, result)
+	runHooks(&e.hooks, &e.hooks.checkResult, "OnCheckResult", result)
+}
+
+// SubscribeStream returns journaled events after the resume token and a channel
+// of subsequent events; complete is false when the token can't be resumed
+func (e *Engine) SubscribeStream(token string) (backlog []StreamEvent, complete bool, events <-chan StreamEvent, cancel func()) {
+	return e.journal.Subscribe(token, 0)
 }
 
 // getSeverity determines alert severity based on check result
@@ -316,9 +886,10 @@ func (e *Engine) GetResults() map[string]CheckResult {
 	e.resultsMu.RLock()
 	defer e.resultsMu.RUnlock()
 
+	now := time.Now()
 	results := make(map[string]CheckResult)
 	for k, v := range e.results {
-		results[k] = v
+		results[k] = e.withMaintenance(v, now)
 	}
 	return results
 }
@@ -326,10 +897,68 @@ func (e *Engine) GetResults() map[string]CheckResult {
 // GetResult returns a specific check result by name
 func (e *Engine) GetResult(name string) (CheckResult, bool) {
 	e.resultsMu.RLock()
-	defer e.resultsMu.RUnlock()
-
 	result, exists := e.results[name]
-	return result, exists
+	e.resultsMu.RUnlock()
+	if !exists {
+		return result, false
+	}
+	return e.withMaintenance(result, time.Now()), true
+}
+
+// recordMetrics appends metrics to their series history with a size limit
+func (e *Engine) recordMetrics(metrics []Metric) {
+	if len(metrics) == 0 {
+		return
+	}
+
+	limit := e.metricHistoryLimit()
+	e.historyMu.Lock()
+	defer e.historyMu.Unlock()
+
+	for _, metric := range metrics {
+		key := metricSeriesKey(metric)
+		history := append(e.metricHistory[key], metric)
+		if len(history) > limit {
+			history = history[len(history)-limit:]
+		}
+		e.metricHistory[key] = history
+	}
+}
+
+// GetMetricHistory returns recorded data points for all series of a metric
+func (e *Engine) GetMetricHistory(name string) map[string][]Metric {
+	e.historyMu.RLock()
+	defer e.historyMu.RUnlock()
+
+	history := make(map[string][]Metric)
+	for key, series := range e.metricHistory {
+		if len(series) == 0 || series[0].Name != name {
+			continue
+		}
+		points := make([]Metric, len(series))
+		copy(points, series)
+		history[key] = points
+	}
+	return history
+}
+
+// metricSeriesKey identifies a metric series by name and sorted labels
+func metricSeriesKey(metric Metric) string {
+	if len(metric.Labels) == 0 {
+		return metric.Name
+	}
+
+	keys := make([]string, 0, len(metric.Labels))
+	for k := range metric.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, metric.Labels[k])
+	}
+	return fmt.Sprintf("%s{%s}", metric.Name, strings.Join(pairs, ","))
 }
 
 // GetClusterHealth returns the overall cluster health
@@ -337,24 +966,57 @@ func (e *Engine) GetClusterHealth(clusterName string) ClusterHealth {
 	e.resultsMu.RLock()
 	defer e.resultsMu.RUnlock()
 
+	now := time.Now()
 	checks := make([]CheckResult, 0, len(e.results))
-	var totalScore float64
-	var weightedScore float64
-	var totalWeight float64
-	healthyCount := 0
+	freshness := make(map[string]CheckFreshness, len(e.results))
+	for name, result := range e.results {
+		checks = append(checks, e.withMaintenance(result, now))
+		freshness[name] = e.freshness(result, now)
+	}
+	health := e.clusterHealth(clusterName, checks, e.failingSince, now)
+	health.Freshness = freshness
+	health.Restricted = e.RestrictedChecks()
+	health.StateRevision = e.StateRevision()
+	health.Environment = e.alertManager.Environment().Name
+	return health
+}
 
-	for _, result := range e.results {
-		checks = append(checks, result)
+// GetFindings returns the tracked findings matching filter, open ones first
+func (e *Engine) GetFindings(filter FindingFilter) []FindingRecord {
+	return e.findings.List(filter)
+}
 
-		// Calculate scores
-		score := e.calculateScore(result)
-		weight := e.getWeight(result)
+// HealthAt returns the cluster health as it was at the given time, from
+// the result history
+func (e *Engine) HealthAt(clusterName string, at time.Time) (ClusterHealth, error) {
+	checks, failingSince, err := e.history.At(at)
+	if err != nil {
+		return ClusterHealth{}, err
+	}
+	return e.clusterHealth(clusterName, checks, failingSince, at), nil
+}
 
-		totalScore += score
-		weightedScore += score * weight
-		totalWeight += weight
+// CheckTimeline returns a check's recorded results between from and to,
+// starting with the result in effect at from
+func (e *Engine) CheckTimeline(name string, from, to time.Time) []CheckResult {
+	return e.history.Timeline(name, from, to)
+}
 
-		if result.Status == HealthStatusHealthy {
+// clusterHealth aggregates check results into the cluster health at now
+func (e *Engine) clusterHealth(clusterName string, checks []CheckResult, failingSince map[string]time.Time, now time.Time) ClusterHealth {
+	// Restricted checks can't run, so they say nothing about the cluster
+	scored := make([]CheckResult, 0, len(checks))
+	for _, result := range checks {
+		if result.Status != HealthStatusRestricted {
+			scored = append(scored, result)
+		}
+	}
+
+	var totalScore float64
+	healthyCount := 0
+	for _, result := range scored {
+		totalScore += e.calculateScore(result)
+		if result.Status == HealthStatusHealthy || InMaintenance(result) {
 			healthyCount++
 		}
 	}
@@ -363,20 +1025,16 @@ func (e *Engine) GetClusterHealth(clusterName string) ClusterHealth {
 	overallStatus := HealthStatusHealthy
 	if healthyCount == 0 {
 		overallStatus = HealthStatusUnhealthy
-	} else if healthyCount < len(checks) {
+	} else if healthyCount < len(scored) {
 		overallStatus = HealthStatusDegraded
 	}
 
 	// Calculate health score
 	rawScore := 0.0
-	if len(checks) > 0 {
-		rawScore = (totalScore / float64(len(checks))) * 100
-	}
-
-	weighted := 0.0
-	if totalWeight > 0 {
-		weighted = (weightedScore / totalWeight) * 100
+	if len(scored) > 0 {
+		rawScore = (totalScore / float64(len(scored))) * 100
 	}
+	weighted, breakdown := e.weightedScore(scored, failingSince, now)
 
 	return ClusterHealth{
 		ClusterName: clusterName,
@@ -384,17 +1042,22 @@ func (e *Engine) GetClusterHealth(clusterName string) ClusterHealth {
 		Score: HealthScore{
 			Raw:        rawScore,
 			Weighted:   weighted,
+			Breakdown:  breakdown,
 			Trend:      "stable", // TODO: Implement trend calculation
 			Confidence: 0.95,     // TODO: Implement ML confidence
 			Forecast:   "stable", // TODO: Implement forecasting
 		},
 		Checks:    checks,
-		Timestamp: time.Now(),
+		Timestamp: now,
 	}
 }
 
-// calculateScore converts health status to numeric score
+// calculateScore converts health status to numeric score; checks under
+// maintenance score as healthy
 func (e *Engine) calculateScore(result CheckResult) float64 {
+	if InMaintenance(result) {
+		return 1.0
+	}
 	switch result.Status {
 	case HealthStatusHealthy:
 		return 1.0
@@ -409,9 +1072,84 @@ func (e *Engine) calculateScore(result CheckResult) float64 {
 
 // getWeight returns the weight based on check criticality
 func (e *Engine) getWeight(result CheckResult) float64 {
-	// TODO: Get criticality from check
-	// For now, return default weight
-	return 1.0
+	return criticalityWeight(e.criticalityOf(result.Name))
+}
+
+// runAIWorker analyzes queued failures until the engine stops
+func (e *Engine) runAIWorker() {
+	for {
+		result, ok := e.aiQueue.Pop(e.ctx)
+		if !ok {
+			return
+		}
+		e.runAIAnalysis(result)
+	}
+}
+
+// GetAIQueueStats returns AI event queue depth and counters, or nil when AI is disabled
+func (e *Engine) GetAIQueueStats() *AIQueueStats {
+	if e.aiQueue == nil {
+		return nil
+	}
+	stats := e.aiQueue.Stats()
+	return &stats
+}
+
+// GetToolLimiterStats returns kubectl rate limiter activity, or nil when AI is disabled
+func (e *Engine) GetToolLimiterStats() *ai.ToolLimiterStats {
+	if e.toolLimiter == nil {
+		return nil
+	}
+	stats := e.toolLimiter.Stats()
+	return &stats
+}
+
+// toolLimiterMetrics reports kubectl rate limiter counters and current rate
+func toolLimiterMetrics(stats ai.ToolLimiterStats) []Metric {
+	now := time.Now()
+	metric := func(name string, value float64, unit string, metricType MetricType) Metric {
+		return Metric{Name: name, Value: value, Unit: unit, Timestamp: now, Type: metricType}
+	}
+	return []Metric{
+		metric("kubepulse_ai_tool_commands_total", float64(stats.Executed), "commands", MetricTypeCounter),
+		metric("kubepulse_ai_tool_commands_delayed_total", float64(stats.Delayed), "commands", MetricTypeCounter),
+		metric("kubepulse_ai_tool_commands_throttled_total", float64(stats.Throttled), "commands", MetricTypeCounter),
+		metric("kubepulse_ai_tool_rate_limit", stats.CurrentQPS, "commands/s", MetricTypeGauge),
+		metric("kubepulse_ai_tool_commands_in_flight", float64(stats.InFlight), "commands", MetricTypeGauge),
+	}
+}
+
+// GetAICallStats counts AI provider calls by kind; nil when AI is disabled
+func (e *Engine) GetAICallStats() []ai.CallStats {
+	if e.aiClient == nil {
+		return nil
+	}
+	return e.aiClient.CallStats()
+}
+
+// GetToolCacheStats returns kubectl result cache activity
+func (e *Engine) GetToolCacheStats() ai.ToolCacheStats {
+	return e.toolCache.Stats()
+}
+
+// RefreshToolResults drops the cached kubectl results of the engine's
+// cluster so the next AI request reads current state. It returns how many
+// results were dropped.
+func (e *Engine) RefreshToolResults() int {
+	return e.toolCache.Refresh(e.currentContext)
+}
+
+// toolCacheMetrics reports kubectl result cache counters and size
+func toolCacheMetrics(stats ai.ToolCacheStats) []Metric {
+	now := time.Now()
+	metric := func(name string, value float64, unit string, metricType MetricType) Metric {
+		return Metric{Name: name, Value: value, Unit: unit, Timestamp: now, Type: metricType}
+	}
+	return []Metric{
+		metric("kubepulse_ai_tool_cache_hits_total", float64(stats.Hits), "commands", MetricTypeCounter),
+		metric("kubepulse_ai_tool_cache_misses_total", float64(stats.Misses), "commands", MetricTypeCounter),
+		metric("kubepulse_ai_tool_cache_entries", float64(stats.Entries), "results", MetricTypeGauge),
+	}
 }
 
 // runAIAnalysis performs AI-powered analysis on health check failures
@@ -427,6 +1165,14 @@ func (e *Engine) runAIAnalysis(result CheckResult) {
 
 	// Convert to AI types and run diagnostic analysis
 	aiResult := e.convertToAICheckResult(result)
+	if e.evaluation.sample() {
+		// Compare the models once the diagnosis in use is done
+		defer func() {
+			if _, err := e.evaluateDiagnosis(e.ctx, result.Name, ai.DiagnosticRequest(&aiResult, context)); err != nil {
+				klog.Warningf("AI evaluation of %s failed: %v", result.Name, err)
+			}
+		}()
+	}
 	diagnosisResp, err := e.aiClient.AnalyzeDiagnostic(e.ctx, &aiResult, context)
 	if err != nil {
 		klog.Errorf("AI diagnostic analysis failed for %s: %v", result.Name, err)
@@ -435,6 +1181,7 @@ func (e *Engine) runAIAnalysis(result CheckResult) {
 
 	klog.Infof("AI Diagnosis for %s: %s (confidence: %.2f)",
 		result.Name, diagnosisResp.Summary, diagnosisResp.Confidence)
+	e.findings.ObserveDiagnosis(result.Name, diagnosisResp.Findings)
 
 	// Run healing analysis if diagnosis confidence is high
 	if diagnosisResp.Confidence > 0.7 {
@@ -449,21 +1196,33 @@ func (e *Engine) runAIAnalysis(result CheckResult) {
 
 		// Store AI insights in the result
 		e.storeAIInsights(result.Name, diagnosisResp, healingResp)
+
+		// Save the suggested follow-up commands as an investigation plan
+		e.planSuggestedInvestigation(result, diagnosisResp, healingResp)
 	}
 }
 
-// buildDiagnosticContext creates context for AI analysis
+// buildDiagnosticContext creates context for AI analysis. Resources the
+// check implicates are described on demand rather than up front.
 func (e *Engine) buildDiagnosticContext(result CheckResult) ai.DiagnosticContext {
-	e.resultsMu.RLock()
-	defer e.resultsMu.RUnlock()
-
-	// Get related checks and convert them
+	// Get related checks and convert them. Planned maintenance seen by any
+	// check explains disruption seen by the others.
 	relatedChecks := make([]ai.CheckResult, 0)
+	var relatedDisruptions, blocked, unschedulable []string
+	e.resultsMu.RLock()
 	for _, checkResult := range e.results {
+		blocked = append(blocked, blockedDeploymentLines(checkResult)...)
+		unschedulable = append(unschedulable, schedulingFailureLines(checkResult)...)
 		if checkResult.Name != result.Name {
 			relatedChecks = append(relatedChecks, e.convertToAICheckResult(checkResult))
+			relatedDisruptions = append(relatedDisruptions, ExpectedDisruptions(checkResult)...)
 		}
 	}
+	e.resultsMu.RUnlock()
+	sort.Strings(relatedDisruptions)
+	sort.Strings(blocked)
+	sort.Strings(unschedulable)
+	disruptions := append(append([]string{}, ExpectedDisruptions(result)...), relatedDisruptions...)
 
 	// Convert metrics
 	aiMetrics := make([]ai.Metric, len(result.Metrics))
@@ -484,9 +1243,17 @@ func (e *Engine) buildDiagnosticContext(result CheckResult) ai.DiagnosticContext
 		ResourceType:  extractResourceType(result.Name),
 		ResourceName:  extractResourceName(result.Name),
 		ErrorLogs:     extractErrorLogs(result),
+		LogPatterns:   extractLogPatterns(result),
 		Events:        extractEvents(result),
 		Metrics:       aiMetrics,
 		RelatedChecks: relatedChecks,
+		Runbook:       e.runbookFor(result),
+
+		ExpectedDisruptions: disruptions,
+		BlockedDeployments:  blocked,
+		SchedulingFailures:  unschedulable,
+		DescribedResources:  e.describer.Describe(e.ctx, ImplicatedResources(result)),
+		Annotations:         e.annotationNotes(result.Name, time.Now()),
 	}
 
 	return context
@@ -495,42 +1262,75 @@ func (e *Engine) buildDiagnosticContext(result CheckResult) ai.DiagnosticContext
 // storeAIInsights stores AI analysis results
 func (e *Engine) storeAIInsights(checkName string, diagnosis *ai.AnalysisResponse, healing *ai.AnalysisResponse) {
 	e.resultsMu.Lock()
-	defer e.resultsMu.Unlock()
+	result, exists := e.results[checkName]
+	if !exists {
+		e.resultsMu.Unlock()
+		return
+	}
 
-	if result, exists := e.results[checkName]; exists {
-		// Add AI insights to the result
-		if result.Details == nil {
-			result.Details = make(map[string]interface{})
+	// Add AI insights to the result
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	analyzedAt := time.Now()
+	result.Details["ai_diagnosis"] = diagnosis
+	result.Details["ai_healing"] = healing
+	result.Details["ai_analyzed_at"] = analyzedAt
+	e.results[checkName] = result
+	if diagnosis != nil {
+		if e.diagnoses == nil {
+			e.diagnoses = make(map[string]*ai.AnalysisResponse)
 		}
-
-		result.Details["ai_diagnosis"] = diagnosis
-		result.Details["ai_healing"] = healing
-		result.Details["ai_analyzed_at"] = time.Now()
-
-		e.results[checkName] = result
+		e.diagnoses[checkName] = diagnosis
+	}
+	e.generation.Add(1)
+
+	insight := AIInsightEvent{
+		Check:         checkName,
+		Diagnosis:     diagnosis,
+		Healing:       healing,
+		AnalyzedAt:    analyzedAt,
+		StateRevision: e.StateRevision(),
 	}
+	e.journal.Append(StreamEventAIInsight, insight)
+	e.resultsMu.Unlock()
+
+	runHooks(&e.hooks, &e.hooks.aiInsight, "OnAIInsight", insight)
 }
 
 // GetAIInsights returns AI insights for cluster health
 func (e *Engine) GetAIInsights() (*ai.InsightSummary, error) {
+	return e.analyzeCluster(e.ctx)
+}
+
+// analyzeCluster analyzes the current cluster's health with ctx
+func (e *Engine) analyzeCluster(ctx context.Context) (*ai.InsightSummary, error) {
 	if e.aiClient == nil {
 		return nil, fmt.Errorf("AI client not enabled")
 	}
 
 	clusterHealth := e.GetClusterHealth(e.currentContext)
 	aiClusterHealth := e.convertToAIClusterHealth(clusterHealth)
-	return e.aiClient.AnalyzeCluster(e.ctx, &aiClusterHealth)
+	summary, err := e.aiClient.AnalyzeCluster(ctx, &aiClusterHealth)
+	if err != nil {
+		return nil, err
+	}
+	summary.StateRevision = clusterHealth.StateRevision
+
+	// Keep the analysis so it can be compared with later ones
+	e.analyses.Record(&aiClusterHealth, summary)
+	return summary, nil
 }
 
 // QueryAssistant processes natural language queries
-func (e *Engine) QueryAssistant(query string) (*ai.QueryResponse, error) {
+func (e *Engine) QueryAssistant(ctx context.Context, query string) (*ai.QueryResponse, error) {
 	if e.assistant == nil {
 		return nil, fmt.Errorf("AI assistant not enabled")
 	}
 
 	clusterHealth := e.GetClusterHealth(e.currentContext)
 	aiClusterHealth := e.convertToAIClusterHealth(clusterHealth)
-	return e.assistant.Query(e.ctx, query, &aiClusterHealth)
+	return e.assistant.Query(ctx, query, &aiClusterHealth)
 }
 
 // GetPredictiveInsights returns AI predictions about future issues
@@ -578,18 +1378,12 @@ func (e *Engine) GetRemediationSuggestions(checkName string) ([]ai.RemediationAc
 	context := e.buildDiagnosticContext(result)
 	aiResult := e.convertToAICheckResult(result)
 
-	return e.remediationEngine.GenerateRemediation(e.ctx, aiResult, context)
-}
-
-// ExecuteRemediation executes an AI-suggested remediation
-func (e *Engine) ExecuteRemediation(actionID string, dryRun bool) (*ai.RemediationRecord, error) {
-	if e.remediationEngine == nil {
-		return nil, fmt.Errorf("remediation engine not enabled")
+	actions, err := e.remediationEngine.GenerateRemediation(e.ctx, aiResult, context)
+	if err != nil {
+		return nil, err
 	}
-
-	// This would need to store and retrieve actions properly
-	// For now, return error
-	return nil, fmt.Errorf("action retrieval not implemented")
+	e.storeRemediations(checkName, actions)
+	return actions, nil
 }
 
 // GetSmartAlertInsights returns intelligent alert insights
@@ -635,6 +1429,26 @@ func extractErrorLogs(result CheckResult) []string {
 	return logs
 }
 
+// extractLogPatterns converts log patterns attached by health checks to AI format
+func extractLogPatterns(result CheckResult) []ai.LogPattern {
+	patterns, ok := result.Details["log_patterns"].([]LogPattern)
+	if !ok {
+		return nil
+	}
+
+	aiPatterns := make([]ai.LogPattern, len(patterns))
+	for i, pattern := range patterns {
+		aiPatterns[i] = ai.LogPattern{
+			Pattern: pattern.Pattern,
+			Kind:    pattern.Kind,
+			Count:   pattern.Count,
+			Example: pattern.Example,
+			Pods:    pattern.Pods,
+		}
+	}
+	return aiPatterns
+}
+
 func extractEvents(result CheckResult) []string {
 	events := []string{}
 	if result.Details != nil {
@@ -671,6 +1485,10 @@ func (e *Engine) convertToAICheckResult(result CheckResult) ai.CheckResult {
 			Probability: pred.Probability,
 			Reason:      pred.Reason,
 		}
+		if pred.Explanation != nil {
+			explanation := ai.AnomalyExplanation(*pred.Explanation)
+			aiPredictions[i].Explanation = &explanation
+		}
 	}
 
 	return ai.CheckResult{
@@ -703,7 +1521,9 @@ func (e *Engine) convertToAIClusterHealth(clusterHealth ClusterHealth) ai.Cluste
 			Confidence: clusterHealth.Score.Confidence,
 			Forecast:   clusterHealth.Score.Forecast,
 		},
-		Checks:    aiChecks,
-		Timestamp: clusterHealth.Timestamp,
+		Checks:      aiChecks,
+		Inventory:   e.cachedInventory().Compact(),
+		Annotations: e.annotationNotes("", time.Now()),
+		Timestamp:   clusterHealth.Timestamp,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/ml"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -609,6 +610,72 @@ func TestExtractEvents(t *testing.T) {
 	}
 }
 
+func TestExtractLogPatterns(t *testing.T) {
+	result := CheckResult{
+		Name: "pod-health",
+		Details: map[string]interface{}{
+			"log_patterns": []LogPattern{
+				{Pattern: "error: <n> retries", Kind: "error", Count: 4, Example: "error: 3 retries", Pods: []string{"default/api"}},
+			},
+		},
+	}
+
+	patterns := extractLogPatterns(result)
+	if len(patterns) != 1 {
+		t.Fatalf("expected 1 pattern, got %d", len(patterns))
+	}
+	if patterns[0].Pattern != "error: <n> retries" || patterns[0].Count != 4 {
+		t.Errorf("unexpected pattern conversion: %+v", patterns[0])
+	}
+
+	if patterns := extractLogPatterns(CheckResult{Name: "node-health"}); patterns != nil {
+		t.Errorf("expected nil patterns when none attached, got %v", patterns)
+	}
+}
+
+func TestExecuteRemediation_ReadOnly(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+		ReadOnly:   true,
+	})
+	if !engine.ReadOnly() {
+		t.Fatal("expected the engine to be read-only")
+	}
+	if _, err := engine.ExecuteRemediation("restart-api", false); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if _, err := engine.ExecuteRemediation("restart-api", true); errors.Is(err, ErrReadOnly) {
+		t.Error("expected dry runs to be allowed in read-only mode")
+	}
+}
+
+func TestRunbookFor(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Runbooks:   map[string]string{"pod-health": "https://runbooks.example.com/pods"},
+	})
+
+	tests := []struct {
+		name   string
+		result CheckResult
+		want   string
+	}{
+		{"configured", CheckResult{Name: "pod-health"}, "https://runbooks.example.com/pods"},
+		{"annotated wins", CheckResult{Name: "pod-health", Details: map[string]interface{}{"runbook": "https://runbooks.example.com/payments"}},
+			"https://runbooks.example.com/payments"},
+		{"none", CheckResult{Name: "node-health"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.runbookFor(tt.result); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestConvertToAICheckResult(t *testing.T) {
 	client := fake.NewSimpleClientset()
 	config := EngineConfig{
@@ -640,6 +707,7 @@ func TestConvertToAICheckResult(t *testing.T) {
 				Status:      HealthStatusHealthy,
 				Probability: 0.95,
 				Reason:      "stable metrics",
+				Explanation: &ml.AnomalyExplanation{Metric: "cpu", Observed: 80.5, Baseline: 40, StdDev: 5, ZScore: 8.1, History: []float64{39, 41}},
 			},
 		},
 	}
@@ -690,6 +758,10 @@ func TestConvertToAICheckResult(t *testing.T) {
 	if aiPrediction.Probability != corePrediction.Probability {
 		t.Errorf("expected prediction probability %f, got %f", corePrediction.Probability, aiPrediction.Probability)
 	}
+
+	if explanation := aiPrediction.Explanation; explanation == nil || explanation.Baseline != 40 || explanation.ZScore != 8.1 || len(explanation.History) != 2 {
+		t.Errorf("expected the anomaly explanation to be kept, got %+v", explanation)
+	}
 }
 
 func TestConvertToAIClusterHealth(t *testing.T) {
@@ -780,3 +852,61 @@ func (m *mockHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 func (m *mockHealthCheck) Configure(config map[string]interface{}) error {
 	return nil
 }
+
+func TestMetricHistory(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		MaxHistory: 2,
+	})
+
+	for i := 0; i < 3; i++ {
+		engine.recordMetrics([]Metric{
+			{Name: "event_evictions_per_minute", Value: float64(i), Labels: map[string]string{"namespace": "default", "reason": "Evicted"}},
+			{Name: "event_evictions_per_minute", Value: float64(i * 10), Labels: map[string]string{"reason": "Evicted"}},
+			{Name: "pod_total", Value: 5},
+		})
+	}
+
+	history := engine.GetMetricHistory("event_evictions_per_minute")
+	if len(history) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(history))
+	}
+
+	series := history[`event_evictions_per_minute{namespace="default",reason="Evicted"}`]
+	if len(series) != 2 {
+		t.Fatalf("expected history to be capped at 2 points, got %d", len(series))
+	}
+	if series[0].Value != 1 || series[1].Value != 2 {
+		t.Errorf("expected most recent points to be retained, got %v and %v", series[0].Value, series[1].Value)
+	}
+
+	if len(engine.GetMetricHistory("missing")) != 0 {
+		t.Error("expected no history for unknown metric")
+	}
+}
+
+func TestEngine_AlertDiagnosis(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod"})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy})
+	if engine.alertDiagnosis("pod-health") != nil {
+		t.Fatal("expected no diagnosis before AI analysis")
+	}
+
+	engine.storeAIInsights("pod-health", &ai.AnalysisResponse{
+		Summary:         "Missing DATABASE_URL",
+		Confidence:      0.9,
+		Recommendations: []ai.Recommendation{{Title: "Set DATABASE_URL"}, {Title: "Roll back the deployment"}},
+	}, nil)
+	// The next failing result replaces the stored one but keeps the diagnosis
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy})
+	diagnosis := engine.alertDiagnosis("pod-health")
+	if diagnosis == nil || diagnosis.Summary != "Missing DATABASE_URL" || len(diagnosis.Recommendations) != 2 ||
+		diagnosis.Recommendations[0] != "Set DATABASE_URL" {
+		t.Fatalf("unexpected diagnosis %+v", diagnosis)
+	}
+
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	if engine.alertDiagnosis("pod-health") != nil {
+		t.Error("expected the diagnosis to be dropped once the check recovers")
+	}
+}
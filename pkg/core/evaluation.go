@@ -0,0 +1,88 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+// ErrEvaluationDisabled is returned by A/B evaluation operations when no
+// variants are configured
+var ErrEvaluationDisabled = errors.New("AI model evaluation not enabled")
+
+// modelEvaluation samples automatic diagnoses for A/B evaluation
+type modelEvaluation struct {
+	evaluator *ai.Evaluator // nil when no variants are configured
+	rate      float64       // Share of diagnoses evaluated
+
+	mu     sync.Mutex
+	credit float64 // Accumulated rate; a diagnosis is evaluated once it reaches 1
+}
+
+// sample reports whether the next diagnosis is evaluated, spreading the
+// evaluated ones evenly rather than at random
+func (m *modelEvaluation) sample() bool {
+	if m.evaluator == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credit += m.rate
+	if m.credit < 1 {
+		return false
+	}
+	m.credit--
+	return true
+}
+
+// evaluateDiagnosis answers a diagnosis request with both variants
+func (e *Engine) evaluateDiagnosis(ctx context.Context, check string, request ai.AnalysisRequest) (ai.Evaluation, error) {
+	evaluation, err := e.evaluation.evaluator.Evaluate(ctx, check, request)
+	if err != nil {
+		return ai.Evaluation{}, err
+	}
+	klog.V(2).Infof("AI evaluation %s of %s recorded", evaluation.ID, check)
+	return evaluation, nil
+}
+
+// EvaluateCheck diagnoses a check's latest result with both evaluated
+// models now, whether or not it's failing
+func (e *Engine) EvaluateCheck(ctx context.Context, check string) (ai.Evaluation, error) {
+	if e.evaluation.evaluator == nil {
+		return ai.Evaluation{}, ErrEvaluationDisabled
+	}
+	result, ok := e.GetResult(check)
+	if !ok {
+		return ai.Evaluation{}, fmt.Errorf("%w: %s", ErrCheckNotFound, check)
+	}
+	aiResult := e.convertToAICheckResult(result)
+	return e.evaluateDiagnosis(ctx, check, ai.DiagnosticRequest(&aiResult, e.buildDiagnosticContext(result)))
+}
+
+// GetEvaluations returns the kept A/B evaluations, newest first
+func (e *Engine) GetEvaluations() []ai.Evaluation {
+	if e.evaluation.evaluator == nil {
+		return []ai.Evaluation{}
+	}
+	return e.evaluation.evaluator.List()
+}
+
+// RecordEvaluationFeedback stores an operator's judgement of an evaluation
+func (e *Engine) RecordEvaluationFeedback(id string, feedback ai.EvaluationFeedback) (ai.Evaluation, error) {
+	if e.evaluation.evaluator == nil {
+		return ai.Evaluation{}, ErrEvaluationDisabled
+	}
+	return e.evaluation.evaluator.Feedback(id, feedback)
+}
+
+// EvaluationReport compares the evaluated models' accuracy, speed and cost
+func (e *Engine) EvaluationReport() (ai.EvaluationReport, error) {
+	if e.evaluation.evaluator == nil {
+		return ai.EvaluationReport{}, ErrEvaluationDisabled
+	}
+	return e.evaluation.evaluator.Report(), nil
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestModelEvaluation_Sample(t *testing.T) {
+	var disabled modelEvaluation
+	if disabled.sample() {
+		t.Error("expected no sampling without an evaluator")
+	}
+
+	evaluator, err := ai.NewEvaluator([]ai.EvaluationVariant{{Name: "a"}, {Name: "b"}}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	evaluation := modelEvaluation{evaluator: evaluator, rate: 0.25}
+	sampled := 0
+	for range 8 {
+		if evaluation.sample() {
+			sampled++
+		}
+	}
+	if sampled != 2 {
+		t.Errorf("expected 2 of 8 diagnoses sampled at 0.25, got %d", sampled)
+	}
+}
+
+func TestEngine_Evaluations(t *testing.T) {
+	disabled := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	defer disabled.Stop()
+	if _, err := disabled.EvaluateCheck(context.Background(), "pod-health"); !errors.Is(err, ErrEvaluationDisabled) {
+		t.Errorf("expected ErrEvaluationDisabled, got %v", err)
+	}
+
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		EnableAI:   true,
+		AIConfig:   &ai.Config{TestMode: true},
+		EvaluationVariants: []ai.EvaluationVariant{
+			{Name: "large", Config: ai.Config{TestMode: true, Model: "large"}},
+			{Name: "small", Config: ai.Config{TestMode: true, Model: "small"}},
+		},
+	})
+	defer engine.Stop()
+
+	if _, err := engine.EvaluateCheck(context.Background(), "pod-health"); !errors.Is(err, ErrCheckNotFound) {
+		t.Fatalf("expected ErrCheckNotFound before the check ran, got %v", err)
+	}
+	engine.results["pod-health"] = CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "api-1 is in CrashLoopBackOff"}
+
+	// Automatic diagnoses are evaluated too
+	engine.runAIAnalysis(engine.results["pod-health"])
+	evaluation, err := engine.EvaluateCheck(context.Background(), "pod-health")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if evaluations := engine.GetEvaluations(); len(evaluations) != 2 || evaluations[0].ID != evaluation.ID || evaluation.Check != "pod-health" {
+		t.Fatalf("expected the automatic and on-demand evaluations, got %+v", evaluations)
+	}
+
+	if _, err := engine.RecordEvaluationFeedback(evaluation.ID, ai.EvaluationFeedback{Preferred: "small"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := engine.EvaluationReport()
+	if err != nil || report.WithFeedback != 1 || report.Variants[1].Preferred != 1 {
+		t.Errorf("expected small preferred once, got %+v, %v", report, err)
+	}
+}
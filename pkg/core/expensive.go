@@ -0,0 +1,99 @@
+package core
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultExpensiveInterval is how often expensive checks run by default
+const DefaultExpensiveInterval = 10 * time.Minute
+
+// staleCadences is how many missed runs make a result stale
+const staleCadences = 2
+
+// CheckFreshness describes how current a check's latest result is
+type CheckFreshness struct {
+	Expensive bool          `json:"expensive"`         // Runs on the expensive cadence
+	Cadence   time.Duration `json:"cadence"`           // How often the check runs
+	LastRun   time.Time     `json:"last_run"`          // When the result was produced
+	Age       time.Duration `json:"age"`               // Time since the last run
+	NextRun   time.Time     `json:"next_run,omitzero"` // When the next run is due
+	Stale     bool          `json:"stale"`             // The check missed two runs in a row
+}
+
+// IsExpensive reports whether a check runs on the expensive cadence
+func (e *Engine) IsExpensive(name string) bool {
+	return e.expensive[name]
+}
+
+// cadence returns how often a check runs
+func (e *Engine) cadence(name string) time.Duration {
+	if e.IsExpensive(name) {
+		return e.expensiveInterval
+	}
+	if e.adaptive != nil {
+		if interval, ok := e.adaptive.interval(name); ok {
+			return interval
+		}
+	}
+	return e.interval
+}
+
+// checksByCadence splits the checks into those run every cycle and the
+// expensive ones, leaving out checks discovery disabled
+func (e *Engine) checksByCadence() (regular, expensive []HealthCheck) {
+	for _, check := range e.checks {
+		if e.discoveredOff(check.Name()) {
+			continue
+		}
+		if e.IsExpensive(check.Name()) {
+			expensive = append(expensive, check)
+		} else {
+			regular = append(regular, check)
+		}
+	}
+	return regular, expensive
+}
+
+// runExpensiveChecks runs the expensive checks now and then every expensive
+// interval until the engine stops
+func (e *Engine) runExpensiveChecks() {
+	if _, expensive := e.checksByCadence(); len(expensive) == 0 {
+		return
+	}
+	klog.Infof("Running expensive checks every %s", e.expensiveInterval)
+
+	ticker := time.NewTicker(e.expensiveInterval)
+	defer ticker.Stop()
+	for {
+		_, expensive := e.checksByCadence()
+		e.executeChecks(expensive)
+		e.generation.Add(1)
+
+		select {
+		case <-ticker.C:
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
+
+// freshness describes how current a result is at now. A result is stale
+// once its check has missed two runs, which for a check that runs every
+// cycle usually means it is stuck or failing to report.
+func (e *Engine) freshness(result CheckResult, now time.Time) CheckFreshness {
+	cadence := e.cadence(result.Name)
+	freshness := CheckFreshness{
+		Expensive: e.IsExpensive(result.Name),
+		Cadence:   cadence,
+		LastRun:   result.Timestamp,
+	}
+	if result.Timestamp.IsZero() {
+		return freshness
+	}
+	freshness.Age = now.Sub(result.Timestamp)
+	freshness.NextRun = result.Timestamp.Add(cadence)
+	freshness.Stale = freshness.Age > staleCadences*cadence+e.checkTimeout
+	return freshness
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// countingCheck counts its runs
+type countingCheck struct {
+	mockHealthCheck
+	runs atomic.Int32
+}
+
+func (c *countingCheck) Check(ctx context.Context, client kubernetes.Interface) (CheckResult, error) {
+	c.runs.Add(1)
+	return CheckResult{Name: c.name, Status: HealthStatusHealthy, Timestamp: time.Now()}, nil
+}
+
+func TestEngine_ExpensiveChecks(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient:        fake.NewSimpleClientset(),
+		Interval:          time.Hour,
+		ExpensiveChecks:   []string{"security-scan"},
+		ExpensiveInterval: 20 * time.Millisecond,
+	})
+	pods := &countingCheck{mockHealthCheck: mockHealthCheck{name: "pod-health"}}
+	scan := &countingCheck{mockHealthCheck: mockHealthCheck{name: "security-scan"}}
+	engine.AddCheck(pods)
+	engine.AddCheck(scan)
+
+	// A regular cycle leaves the expensive check alone
+	engine.runChecks()
+	if pods.runs.Load() != 1 || scan.runs.Load() != 0 {
+		t.Fatalf("expected only the regular check run, got %d and %d runs", pods.runs.Load(), scan.runs.Load())
+	}
+
+	go func() { _ = engine.Start() }()
+	defer engine.Stop()
+	deadline := time.Now().Add(2 * time.Second)
+	for scan.runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if scan.runs.Load() < 3 {
+		t.Fatalf("expected the expensive check run on its own cadence, got %d runs", scan.runs.Load())
+	}
+	if runs := pods.runs.Load(); runs != 2 {
+		t.Errorf("expected the regular check run once more on start, got %d runs", runs)
+	}
+
+	health := engine.GetClusterHealth("test")
+	if freshness := health.Freshness["security-scan"]; !freshness.Expensive || freshness.Cadence != 20*time.Millisecond {
+		t.Errorf("expected expensive freshness metadata, got %+v", freshness)
+	}
+	if freshness := health.Freshness["pod-health"]; freshness.Expensive || freshness.Cadence != time.Hour || freshness.Stale {
+		t.Errorf("expected regular freshness metadata, got %+v", freshness)
+	}
+}
+
+func TestEngine_Freshness(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient:      fake.NewSimpleClientset(),
+		Interval:        30 * time.Second,
+		CheckTimeout:    10 * time.Second,
+		ExpensiveChecks: []string{"security-scan"},
+	})
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		check     string
+		age       time.Duration
+		wantStale bool
+	}{
+		{"fresh regular", "pod-health", 20 * time.Second, false},
+		{"one missed run", "pod-health", 65 * time.Second, false},
+		{"two missed runs", "pod-health", 80 * time.Second, true},
+		{"expensive within cadence", "security-scan", 15 * time.Minute, false},
+		{"expensive two runs late", "security-scan", 21 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckResult{Name: tt.check, Timestamp: now.Add(-tt.age)}
+			freshness := engine.freshness(result, now)
+			if freshness.Age != tt.age || freshness.Stale != tt.wantStale {
+				t.Errorf("got age %s stale %v, want age %s stale %v", freshness.Age, freshness.Stale, tt.age, tt.wantStale)
+			}
+			if !freshness.NextRun.Equal(result.Timestamp.Add(engine.cadence(tt.check))) {
+				t.Errorf("expected the next run one cadence after the last, got %s", freshness.NextRun)
+			}
+		})
+	}
+
+	if freshness := engine.freshness(CheckResult{Name: "pod-health"}, now); freshness.Stale || !freshness.NextRun.IsZero() {
+		t.Errorf("expected no staleness for a result without a timestamp, got %+v", freshness)
+	}
+}
@@ -0,0 +1,347 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	"k8s.io/klog/v2"
+)
+
+// DetailFindings lists the problems a check found, classified by finding
+// type so they can be tracked across runs
+const DetailFindings = "findings"
+
+// Sources a finding can be reported by
+const (
+	FindingSourceCheck = "check" // A health check's heuristics
+	FindingSourceAI    = "ai"    // An AI diagnosis
+)
+
+// FindingStatus is where a finding is in its lifecycle
+type FindingStatus string
+
+const (
+	FindingOpen     FindingStatus = "open"
+	FindingResolved FindingStatus = "resolved"
+)
+
+const (
+	// DefaultFindingRetention is how long resolved findings are kept
+	DefaultFindingRetention = 30 * 24 * time.Hour
+
+	// findingSaveInterval is how often findings that were only seen again,
+	// without opening or resolving, are written to the findings file
+	findingSaveInterval = time.Minute
+)
+
+// Findings returns the findings a result reports, including results decoded
+// from JSON
+func Findings(result CheckResult) []findings.Finding {
+	switch list := result.Details[DetailFindings].(type) {
+	case []findings.Finding:
+		return list
+	case []interface{}:
+		data, err := json.Marshal(list)
+		if err != nil {
+			return nil
+		}
+		var decoded []findings.Finding
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
+
+// FindingRecord is the lifecycle of one finding type on one subject
+type FindingRecord struct {
+	ID           string            `json:"id"`
+	Title        string            `json:"title"`
+	Category     findings.Category `json:"category"`
+	Severity     string            `json:"severity"`
+	Subject      string            `json:"subject,omitempty"`
+	Check        string            `json:"check"` // Check the finding belongs to
+	Message      string            `json:"message,omitempty"`
+	Sources      []string          `json:"sources"` // What reported the current occurrence
+	Status       FindingStatus     `json:"status"`
+	FirstSeen    time.Time         `json:"first_seen"`
+	LastSeen     time.Time         `json:"last_seen"`
+	ResolvedAt   time.Time         `json:"resolved_at,omitzero"`
+	Occurrences  int               `json:"occurrences"`  // Once, plus once per recurrence after being resolved
+	Observations int               `json:"observations"` // Check runs and analyses that reported it
+}
+
+// key identifies the record's finding across analyses
+func (r *FindingRecord) key() string {
+	return findings.Finding{ID: r.ID, Subject: r.Subject}.Key()
+}
+
+// hasSource reports whether the current occurrence was reported by source
+func (r *FindingRecord) hasSource(source string) bool {
+	for _, s := range r.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// FindingFilter selects findings; empty fields match every finding
+type FindingFilter struct {
+	Status FindingStatus
+	Check  string
+	ID     string
+}
+
+// FindingTracker follows each finding from when it was first seen until it
+// is resolved. Findings are identified by type and subject, so a problem
+// reported by a check's heuristics and by AI diagnoses is one finding, and
+// one that comes back after resolving is reopened rather than duplicated.
+// With a path the findings are kept in a JSON file and reloaded on restart.
+type FindingTracker struct {
+	path      string
+	retention time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	records map[string]*FindingRecord // By finding key
+	dirty   bool
+	savedAt time.Time
+}
+
+// NewFindingTracker creates a finding tracker, loading the file at path
+// when one is given. Resolved findings are dropped after retention.
+func NewFindingTracker(path string, retention time.Duration) (*FindingTracker, error) {
+	if retention <= 0 {
+		retention = DefaultFindingRetention
+	}
+	t := &FindingTracker{
+		path:      path,
+		retention: retention,
+		now:       time.Now,
+		records:   make(map[string]*FindingRecord),
+	}
+	if path == "" {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path comes from configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	if len(data) > 0 {
+		var records []*FindingRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse findings %s: %w", path, err)
+		}
+		for _, record := range records {
+			t.records[record.key()] = record
+		}
+	}
+	return t, nil
+}
+
+// ObserveResult records the findings a check result reports and resolves
+// the check's open findings it no longer reports. Findings only an AI
+// diagnosis reported stay open until the check is healthy, since the
+// check's heuristics can't tell whether they're gone. Results of checks
+// that couldn't run or are restricted resolve nothing.
+func (t *FindingTracker) ObserveResult(result CheckResult) {
+	if result.Status == HealthStatusUnknown || result.Status == HealthStatusRestricted || result.Error != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	changed := false
+	reported := make(map[string]bool)
+	for _, finding := range findings.Normalize(Findings(result)) {
+		reported[finding.Key()] = true
+		changed = t.observe(finding, result.Name, FindingSourceCheck, now) || changed
+	}
+	for key, record := range t.records {
+		if record.Status != FindingOpen || record.Check != result.Name || reported[key] {
+			continue
+		}
+		if record.hasSource(FindingSourceCheck) || result.Status == HealthStatusHealthy {
+			record.Status = FindingResolved
+			record.ResolvedAt = now
+			changed = true
+		}
+	}
+	t.persist(changed, now)
+}
+
+// ObserveDiagnosis records the findings an AI diagnosis of a check reports.
+// Findings of unknown types are ignored.
+func (t *FindingTracker) ObserveDiagnosis(check string, list []findings.Finding) {
+	list = findings.Normalize(list)
+	if len(list) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	changed := false
+	for _, finding := range list {
+		changed = t.observe(finding, check, FindingSourceAI, now) || changed
+	}
+	t.persist(changed, now)
+}
+
+// observe records one sighting of a finding and reports whether it opened
+// or reopened; callers hold t.mu
+func (t *FindingTracker) observe(finding findings.Finding, check, source string, now time.Time) bool {
+	record, exists := t.records[finding.Key()]
+	opened := false
+	switch {
+	case !exists:
+		findingType, _ := findings.Lookup(finding.ID)
+		record = &FindingRecord{
+			ID:          findingType.ID,
+			Title:       findingType.Title,
+			Category:    findingType.Category,
+			Severity:    findingType.Severity,
+			Subject:     finding.Subject,
+			Check:       check,
+			Status:      FindingOpen,
+			FirstSeen:   now,
+			Occurrences: 1,
+		}
+		t.records[finding.Key()] = record
+		opened = true
+	case record.Status == FindingResolved:
+		record.Status = FindingOpen
+		record.ResolvedAt = time.Time{}
+		record.Check = check
+		record.Sources = nil
+		record.Occurrences++
+		opened = true
+	case source == FindingSourceCheck:
+		// A check's heuristics decide when the finding resolves
+		record.Check = check
+	}
+
+	record.LastSeen = now
+	record.Observations++
+	if finding.Message != "" {
+		record.Message = finding.Message
+	}
+	if !record.hasSource(source) {
+		record.Sources = append(record.Sources, source)
+		sort.Strings(record.Sources)
+	}
+	return opened
+}
+
+// persist saves the findings right away when one opened or resolved, and
+// otherwise at most once per findingSaveInterval; callers hold t.mu
+func (t *FindingTracker) persist(changed bool, now time.Time) {
+	t.dirty = true
+	if !changed && now.Sub(t.savedAt) < findingSaveInterval {
+		return
+	}
+	if err := t.save(now); err != nil {
+		klog.Errorf("Failed to save findings: %v", err)
+	}
+}
+
+// save drops resolved findings past retention and writes the rest to the
+// findings file; callers hold t.mu
+func (t *FindingTracker) save(now time.Time) error {
+	for key, record := range t.records {
+		if record.Status == FindingResolved && now.Sub(record.ResolvedAt) > t.retention {
+			delete(t.records, key)
+		}
+	}
+	t.savedAt = now
+	t.dirty = false
+	if t.path == "" {
+		return nil
+	}
+
+	records := make([]*FindingRecord, 0, len(t.records))
+	for _, record := range t.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].key() < records[j].key() })
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+
+	dir := filepath.Dir(t.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory for findings: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".findings-*")
+	if err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), t.path); err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	return nil
+}
+
+// List returns the findings matching filter, open ones first, most
+// recently seen first
+func (t *FindingTracker) List(filter FindingFilter) []FindingRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list := make([]FindingRecord, 0, len(t.records))
+	for _, record := range t.records {
+		if (filter.Status != "" && record.Status != filter.Status) ||
+			(filter.Check != "" && record.Check != filter.Check) ||
+			(filter.ID != "" && record.ID != filter.ID) {
+			continue
+		}
+		copied := *record
+		copied.Sources = append([]string(nil), record.Sources...)
+		list = append(list, copied)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Status != list[j].Status {
+			return list[i].Status == FindingOpen
+		}
+		if !list[i].LastSeen.Equal(list[j].LastSeen) {
+			return list[i].LastSeen.After(list[j].LastSeen)
+		}
+		return list[i].key() < list[j].key()
+	})
+	return list
+}
+
+// Close writes findings seen since the last save to the findings file
+func (t *FindingTracker) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.dirty {
+		return nil
+	}
+	return t.save(t.now())
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+func TestFindings(t *testing.T) {
+	want := []findings.Finding{{ID: findings.NodeDiskPressure, Subject: "node/worker-2", Message: "worker-2: DiskPressure"}}
+	result := CheckResult{Name: "node-health", Details: map[string]interface{}{DetailFindings: want}}
+	if got := Findings(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("Findings() = %v, want %v", got, want)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+	if got := Findings(decoded); !reflect.DeepEqual(got, want) {
+		t.Errorf("Findings() after JSON = %v, want %v", got, want)
+	}
+	if got := Findings(CheckResult{}); got != nil {
+		t.Errorf("expected nil without findings, got %v", got)
+	}
+}
+
+// findingResult is a node-health result reporting findings
+func findingResult(status HealthStatus, list ...findings.Finding) CheckResult {
+	result := CheckResult{Name: "node-health", Status: status, Details: map[string]interface{}{}}
+	if len(list) > 0 {
+		result.Details[DetailFindings] = list
+	}
+	return result
+}
+
+func TestFindingTracker_Lifecycle(t *testing.T) {
+	tracker, err := NewFindingTracker("", 0)
+	if err != nil {
+		t.Fatalf("NewFindingTracker() error = %v", err)
+	}
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	pressure := findings.Finding{ID: findings.NodeMemoryPressure, Subject: "node/worker-1", Message: "worker-1: MemoryPressure"}
+
+	tracker.ObserveResult(findingResult(HealthStatusDegraded, pressure))
+	now = now.Add(time.Minute)
+	tracker.ObserveResult(findingResult(HealthStatusDegraded, pressure))
+	// The AI names the same problem, which stays one finding
+	tracker.ObserveDiagnosis("node-health", []findings.Finding{{ID: "kp-node-001", Subject: "node/worker-1"}})
+
+	list := tracker.List(FindingFilter{})
+	if len(list) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", list)
+	}
+	got := list[0]
+	if got.Status != FindingOpen || got.Occurrences != 1 || got.Observations != 3 || got.Title != "Node memory pressure" {
+		t.Errorf("unexpected open finding: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Sources, []string{FindingSourceAI, FindingSourceCheck}) {
+		t.Errorf("Sources = %v", got.Sources)
+	}
+	if !got.FirstSeen.Equal(now.Add(-time.Minute)) || !got.LastSeen.Equal(now) {
+		t.Errorf("FirstSeen = %v, LastSeen = %v", got.FirstSeen, got.LastSeen)
+	}
+
+	// Results of checks that couldn't run resolve nothing
+	tracker.ObserveResult(CheckResult{Name: "node-health", Status: HealthStatusUnknown, Error: errors.New("timeout")})
+	if list := tracker.List(FindingFilter{Status: FindingOpen}); len(list) != 1 {
+		t.Fatalf("expected the finding to stay open, got %+v", list)
+	}
+
+	now = now.Add(time.Minute)
+	tracker.ObserveResult(findingResult(HealthStatusHealthy))
+	resolved := tracker.List(FindingFilter{Status: FindingResolved})
+	if len(resolved) != 1 || !resolved[0].ResolvedAt.Equal(now) {
+		t.Fatalf("expected the finding to be resolved, got %+v", resolved)
+	}
+
+	now = now.Add(time.Minute)
+	tracker.ObserveResult(findingResult(HealthStatusDegraded, pressure))
+	list = tracker.List(FindingFilter{ID: findings.NodeMemoryPressure})
+	if len(list) != 1 || list[0].Status != FindingOpen || list[0].Occurrences != 2 || !list[0].ResolvedAt.IsZero() {
+		t.Errorf("expected the finding to be reopened, got %+v", list)
+	}
+}
+
+func TestFindingTracker_AIFindingsResolveWhenHealthy(t *testing.T) {
+	tracker, _ := NewFindingTracker("", 0)
+	tracker.ObserveDiagnosis("pod-health", []findings.Finding{
+		{ID: findings.PodOOMKilled, Subject: "pod/shop/api-1"},
+		{ID: "KP-NOT-A-TYPE", Subject: "pod/shop/api-1"},
+	})
+	if list := tracker.List(FindingFilter{}); len(list) != 1 || list[0].Check != "pod-health" {
+		t.Fatalf("expected one AI finding, got %+v", list)
+	}
+
+	// The check's heuristics don't report it, but the check still fails
+	tracker.ObserveResult(CheckResult{Name: "pod-health", Status: HealthStatusDegraded})
+	if list := tracker.List(FindingFilter{Status: FindingOpen}); len(list) != 1 {
+		t.Fatalf("expected the AI finding to stay open while the check fails, got %+v", list)
+	}
+	tracker.ObserveResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+	if list := tracker.List(FindingFilter{Status: FindingOpen}); len(list) != 1 {
+		t.Fatalf("expected another check's result not to resolve it, got %+v", list)
+	}
+	tracker.ObserveResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	if list := tracker.List(FindingFilter{Status: FindingResolved, Check: "pod-health"}); len(list) != 1 {
+		t.Fatalf("expected the AI finding to resolve once the check is healthy, got %+v", list)
+	}
+}
+
+func TestFindingTracker_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "findings.json")
+	tracker, err := NewFindingTracker(path, time.Hour)
+	if err != nil {
+		t.Fatalf("NewFindingTracker() error = %v", err)
+	}
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	tracker.ObserveResult(findingResult(HealthStatusDegraded,
+		findings.Finding{ID: findings.NodeNotReady, Subject: "node/worker-1"},
+		findings.Finding{ID: findings.NodePIDPressure, Subject: "node/worker-2"},
+	))
+	now = now.Add(time.Minute)
+	tracker.ObserveResult(findingResult(HealthStatusDegraded, findings.Finding{ID: findings.NodeNotReady, Subject: "node/worker-1"}))
+	now = now.Add(10 * time.Second)
+	// Only seen again, so saved by Close rather than right away
+	tracker.ObserveResult(findingResult(HealthStatusDegraded, findings.Finding{ID: findings.NodeNotReady, Subject: "node/worker-1"}))
+	if err := tracker.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reloaded, err := NewFindingTracker(path, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to reload findings: %v", err)
+	}
+	if got, want := reloaded.List(FindingFilter{}), tracker.List(FindingFilter{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("reloaded findings = %+v, want %+v", got, want)
+	}
+	if list := reloaded.List(FindingFilter{Status: FindingOpen}); len(list) != 1 || list[0].Observations != 3 {
+		t.Errorf("expected worker-1 open with 3 observations, got %+v", list)
+	}
+
+	// Resolved findings past retention are dropped on the next save
+	reloaded.now = func() time.Time { return now.Add(2 * time.Hour) }
+	reloaded.ObserveResult(findingResult(HealthStatusHealthy))
+	if list := reloaded.List(FindingFilter{}); len(list) != 1 || list[0].Status != FindingResolved || list[0].ID != findings.NodeNotReady {
+		t.Errorf("expected only the just-resolved finding, got %+v", list)
+	}
+}
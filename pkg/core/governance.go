@@ -0,0 +1,158 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+)
+
+// maxGovernanceRecords bounds the AI governance log; the oldest records are
+// dropped first
+const maxGovernanceRecords = 10000
+
+// GovernanceEvent is what happened to an AI suggestion
+type GovernanceEvent string
+
+const (
+	GovernanceSuggested GovernanceEvent = "suggested" // Shown to someone
+	GovernanceExecuted  GovernanceEvent = "executed"  // Run, or dry-run, by someone
+)
+
+// Outcomes of governance records
+const (
+	GovernanceOutcomeShown     = "shown"
+	GovernanceOutcomeSucceeded = "succeeded"
+	GovernanceOutcomeFailed    = "failed"
+	GovernanceOutcomeRefused   = "refused" // Not run because KubePulse is read-only
+)
+
+// GovernanceRecord is an audit entry of an AI suggestion shown or executed:
+// which action, for whom, with what outcome, and the model behind it
+type GovernanceRecord struct {
+	ID               string          `json:"id"`
+	Timestamp        time.Time       `json:"timestamp"`
+	Event            GovernanceEvent `json:"event"`
+	Check            string          `json:"check,omitempty"`
+	ActionID         string          `json:"action_id"`
+	ActionType       string          `json:"action_type,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	Commands         []string        `json:"commands,omitempty"`
+	Risk             ai.RiskLevel    `json:"risk,omitempty"`
+	RequiresApproval bool            `json:"requires_approval"`
+	Actor            string          `json:"actor"` // API identity that was shown or ran it
+	DryRun           bool            `json:"dry_run"`
+	Outcome          string          `json:"outcome"`
+	Message          string          `json:"message,omitempty"`
+	Provider         string          `json:"provider"`
+	Model            string          `json:"model"`
+	ModelVersion     string          `json:"model_version"`
+}
+
+// governanceLog is the bounded log of governance records, oldest first
+type governanceLog struct {
+	mu      sync.Mutex
+	next    int
+	records []GovernanceRecord
+}
+
+// RecordGovernance adds an entry to the AI governance log, stamping its ID,
+// time and, when unset, the model of the engine's AI client
+func (e *Engine) RecordGovernance(record GovernanceRecord) GovernanceRecord {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	if record.Provider == "" && e.aiClient != nil {
+		info := e.aiClient.ModelInfo()
+		record.Provider, record.Model, record.ModelVersion = info.Provider, info.Model, info.Version
+	}
+
+	e.governance.mu.Lock()
+	defer e.governance.mu.Unlock()
+	e.governance.next++
+	record.ID = fmt.Sprintf("gov-%d-%d", record.Timestamp.Unix(), e.governance.next)
+	e.governance.records = append(e.governance.records, record)
+	if excess := len(e.governance.records) - maxGovernanceRecords; excess > 0 {
+		e.governance.records = e.governance.records[excess:]
+	}
+	return record
+}
+
+// RecordSuggestionsShown records the remediation actions suggested for a
+// check as shown to an actor
+func (e *Engine) RecordSuggestionsShown(check, actor string, actions []ai.RemediationAction) {
+	for _, action := range actions {
+		e.RecordGovernance(GovernanceRecord{
+			Event:            GovernanceSuggested,
+			Check:            check,
+			ActionID:         action.ID,
+			ActionType:       action.Type,
+			Description:      action.Description,
+			Commands:         action.Commands,
+			Risk:             action.Risk,
+			RequiresApproval: action.RequiresApproval,
+			Actor:            actor,
+			Outcome:          GovernanceOutcomeShown,
+		})
+	}
+}
+
+// GovernanceRecords returns the governance records in [since, until], oldest
+// first; a zero until is open-ended
+func (e *Engine) GovernanceRecords(since, until time.Time) []GovernanceRecord {
+	e.governance.mu.Lock()
+	defer e.governance.mu.Unlock()
+
+	records := make([]GovernanceRecord, 0)
+	for _, record := range e.governance.records {
+		if record.Timestamp.Before(since) || (!until.IsZero() && record.Timestamp.After(until)) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// suggestedAction returns the most recently suggested action with an ID,
+// so an execution record can carry what was suggested
+func (e *Engine) suggestedAction(actionID string) (GovernanceRecord, bool) {
+	e.governance.mu.Lock()
+	defer e.governance.mu.Unlock()
+	for i := len(e.governance.records) - 1; i >= 0; i-- {
+		record := e.governance.records[i]
+		if record.Event == GovernanceSuggested && record.ActionID == actionID {
+			return record, true
+		}
+	}
+	return GovernanceRecord{}, false
+}
+
+// RecordExecution records a remediation run, or dry run, by an actor. The
+// action's details and model are taken from when it was suggested.
+func (e *Engine) RecordExecution(actionID, actor string, dryRun bool, record *ai.RemediationRecord, err error) GovernanceRecord {
+	entry := GovernanceRecord{ActionID: actionID}
+	if suggested, ok := e.suggestedAction(actionID); ok {
+		entry = suggested
+	}
+	entry.Event = GovernanceExecuted
+	entry.Actor = actor
+	entry.DryRun = dryRun
+	entry.Timestamp = time.Time{}
+
+	switch {
+	case errors.Is(err, ErrReadOnly):
+		entry.Outcome, entry.Message = GovernanceOutcomeRefused, err.Error()
+	case err != nil:
+		entry.Outcome, entry.Message = GovernanceOutcomeFailed, err.Error()
+	case record != nil && !record.Success:
+		entry.Outcome, entry.Message = GovernanceOutcomeFailed, record.Result
+	default:
+		entry.Outcome = GovernanceOutcomeSucceeded
+		if record != nil {
+			entry.Message = record.Result
+		}
+	}
+	return e.RecordGovernance(entry)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_RecordExecution(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), EnableAI: true, AIConfig: &ai.Config{TestMode: true}})
+	engine.RecordSuggestionsShown("pod-health", "alice", []ai.RemediationAction{{ID: "restart-web", Description: "Restart web", Risk: ai.RiskMedium}})
+
+	tests := []struct {
+		name        string
+		record      *ai.RemediationRecord
+		err         error
+		wantOutcome string
+	}{
+		{"succeeded", &ai.RemediationRecord{Success: true, Result: "restarted"}, nil, GovernanceOutcomeSucceeded},
+		{"command failed", &ai.RemediationRecord{Success: false, Result: "forbidden"}, nil, GovernanceOutcomeFailed},
+		{"error", nil, errors.New("timed out"), GovernanceOutcomeFailed},
+		{"read-only", nil, fmt.Errorf("cannot execute: %w", ErrReadOnly), GovernanceOutcomeRefused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := engine.RecordExecution("restart-web", "bob", false, tt.record, tt.err)
+			if entry.Outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %s, got %s", tt.wantOutcome, entry.Outcome)
+			}
+			if entry.Event != GovernanceExecuted || entry.Actor != "bob" || entry.Check != "pod-health" || entry.Risk != ai.RiskMedium {
+				t.Errorf("expected bob's execution of the suggested action, got %+v", entry)
+			}
+			if entry.Provider != ai.ProviderAnthropic || entry.Model != "default" || entry.ModelVersion != "test" {
+				t.Errorf("expected the model of the suggestion, got %+v", entry)
+			}
+		})
+	}
+
+	if records := engine.GovernanceRecords(time.Now().Add(time.Hour), time.Time{}); len(records) != 0 {
+		t.Errorf("expected no records after since, got %d", len(records))
+	}
+	if records := engine.GovernanceRecords(time.Time{}, time.Time{}); len(records) != 1+len(tests) {
+		t.Errorf("expected every record, got %d", len(records))
+	}
+}
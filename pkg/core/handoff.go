@@ -0,0 +1,217 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+)
+
+// DefaultHandoffWindow is the shift a handoff covers when none is given
+const DefaultHandoffWindow = 8 * time.Hour
+
+// Kinds of follow-up a handoff asks the next shift to take care of
+const (
+	FollowUpUnacknowledged    = "unacknowledged_alert" // Alert still escalating
+	FollowUpFailedRemediation = "failed_remediation"
+	FollowUpSLOViolated       = "slo_violated"
+	FollowUpStuckCheck        = "stuck_check"
+	FollowUpFailedDelivery    = "failed_delivery" // Notification out of delivery attempts
+	FollowUpOpenFinding       = "open_finding"    // Critical or high finding opened during the shift
+)
+
+// Handoff summarizes a shift for the next on-call engineer: what is broken
+// now, what fired and resolved, how SLOs burned, what was remediated and
+// analyzed, and what still needs someone's attention
+type Handoff struct {
+	ClusterName    string           `json:"cluster_name"`
+	Since          time.Time        `json:"since"`
+	Until          time.Time        `json:"until"`
+	Status         HealthStatus     `json:"status"`
+	Score          float64          `json:"score"`
+	Incidents      []Incident       `json:"incidents"` // Checks failing now, worst first
+	AlertsFired    []Change         `json:"alerts_fired"`
+	AlertsResolved []Change         `json:"alerts_resolved"`
+	SLOs           []SLOStatus      `json:"slos"` // Fastest burning first
+	Remediations   []Change         `json:"remediations"`
+	Insights       []HandoffInsight `json:"insights"`
+	FollowUps      []FollowUp       `json:"follow_ups"`
+}
+
+// Incident is a check that is failing at handoff
+type Incident struct {
+	Check        string        `json:"check"`
+	Status       HealthStatus  `json:"status"`
+	Severity     AlertSeverity `json:"severity"`
+	Message      string        `json:"message"`
+	FailingSince time.Time     `json:"failing_since,omitzero"`
+	Diagnosis    string        `json:"diagnosis,omitempty"` // Summary of the latest AI diagnosis
+}
+
+// HandoffInsight is an AI analysis completed during the shift
+type HandoffInsight struct {
+	Check      string    `json:"check"` // Empty for cluster-wide analyses
+	Summary    string    `json:"summary"`
+	Severity   string    `json:"severity,omitempty"`
+	AnalyzedAt time.Time `json:"analyzed_at"`
+}
+
+// FollowUp is something left for the next shift
+type FollowUp struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+// Handoff summarizes the shift since the given time
+func (e *Engine) Handoff(clusterName string, since time.Time) Handoff {
+	now := time.Now()
+	if clusterName == "" {
+		clusterName = e.currentContext
+	}
+	health := e.GetClusterHealth(clusterName)
+	handoff := Handoff{
+		ClusterName:    health.ClusterName,
+		Since:          since,
+		Until:          now,
+		Status:         health.Status,
+		Score:          health.Score.Weighted,
+		Incidents:      []Incident{},
+		AlertsFired:    []Change{},
+		AlertsResolved: []Change{},
+		SLOs:           []SLOStatus{},
+		Remediations:   []Change{},
+		Insights:       []HandoffInsight{},
+		FollowUps:      []FollowUp{},
+	}
+
+	e.resultsMu.RLock()
+	failingSince := make(map[string]time.Time, len(e.failingSince))
+	for name, at := range e.failingSince {
+		failingSince[name] = at
+	}
+	e.resultsMu.RUnlock()
+	for _, result := range health.Checks {
+		if result.Status == HealthStatusHealthy {
+			continue
+		}
+		incident := Incident{
+			Check:        result.Name,
+			Status:       result.Status,
+			Severity:     e.getSeverity(result),
+			Message:      result.Message,
+			FailingSince: failingSince[result.Name],
+		}
+		if headline := aiHeadline(result); headline != nil {
+			incident.Diagnosis = headline.Summary
+		}
+		handoff.Incidents = append(handoff.Incidents, incident)
+	}
+	sort.Slice(handoff.Incidents, func(i, j int) bool {
+		a, b := handoff.Incidents[i], handoff.Incidents[j]
+		if statusRank(a.Status) != statusRank(b.Status) {
+			return statusRank(a.Status) > statusRank(b.Status)
+		}
+		return a.Check < b.Check
+	})
+
+	for _, change := range e.changes.Since(since) {
+		switch change.Kind {
+		case ChangeKindAlertFiring:
+			handoff.AlertsFired = append(handoff.AlertsFired, change)
+		case ChangeKindAlertResolved:
+			handoff.AlertsResolved = append(handoff.AlertsResolved, change)
+		case ChangeKindRemediation:
+			handoff.Remediations = append(handoff.Remediations, change)
+			if success, ok := change.Details["success"].(bool); ok && !success {
+				handoff.FollowUps = append(handoff.FollowUps, FollowUp{
+					Kind:    FollowUpFailedRemediation,
+					Subject: change.Resource,
+					Message: fmt.Sprintf("%s failed: %s", change.Message, change.To),
+				})
+			}
+		}
+	}
+
+	for _, status := range e.GetSLOStatuses() {
+		handoff.SLOs = append(handoff.SLOs, *status)
+		if status.IsViolated {
+			handoff.FollowUps = append(handoff.FollowUps, FollowUp{
+				Kind:    FollowUpSLOViolated,
+				Subject: "slo/" + status.SLO.Name,
+				Message: fmt.Sprintf("at %.2f against a target of %.2f, burning budget at %.1fx", status.CurrentValue, status.SLO.Target, status.BurnRate),
+			})
+		}
+	}
+	sort.Slice(handoff.SLOs, func(i, j int) bool {
+		if handoff.SLOs[i].BurnRate != handoff.SLOs[j].BurnRate {
+			return handoff.SLOs[i].BurnRate > handoff.SLOs[j].BurnRate
+		}
+		return handoff.SLOs[i].SLO.Name < handoff.SLOs[j].SLO.Name
+	})
+
+	for _, event := range e.journal.EventsSince(StreamEventAIInsight, since) {
+		insight, ok := event.Data.(AIInsightEvent)
+		if !ok || insight.Diagnosis == nil {
+			continue
+		}
+		handoff.Insights = append(handoff.Insights, HandoffInsight{
+			Check:      insight.Check,
+			Summary:    insight.Diagnosis.Summary,
+			Severity:   string(insight.Diagnosis.Severity),
+			AnalyzedAt: insight.AnalyzedAt,
+		})
+	}
+	for _, session := range e.analyses.List(handoff.ClusterName) {
+		if session.CreatedAt.Before(since) {
+			continue
+		}
+		handoff.Insights = append(handoff.Insights, HandoffInsight{Summary: session.Summary, AnalyzedAt: session.CreatedAt})
+	}
+	sort.SliceStable(handoff.Insights, func(i, j int) bool {
+		return handoff.Insights[i].AnalyzedAt.Before(handoff.Insights[j].AnalyzedAt)
+	})
+
+	handoff.FollowUps = append(handoff.FollowUps, e.handoffFollowUps(since)...)
+	return handoff
+}
+
+// handoffFollowUps lists the alerts, checks, notifications and findings
+// that need attention after the shift
+func (e *Engine) handoffFollowUps(since time.Time) []FollowUp {
+	var followUps []FollowUp
+	for _, escalation := range e.GetEscalations() {
+		message := fmt.Sprintf("unacknowledged since %s", escalation.Started.Format(time.RFC3339))
+		if escalation.NextChannel != "" {
+			message += fmt.Sprintf("; escalates to %s next", escalation.NextChannel)
+		}
+		followUps = append(followUps, FollowUp{Kind: FollowUpUnacknowledged, Subject: "alert/" + escalation.AlertID, Message: message})
+	}
+	for _, stuck := range e.GetStuckChecks() {
+		followUps = append(followUps, FollowUp{Kind: FollowUpStuckCheck, Subject: "check/" + stuck.Name, Message: fmt.Sprintf("execution stuck for %s", stuck.Running.Round(time.Second))})
+	}
+	for _, delivery := range e.GetDeliveries(alerts.DeliveryDead) {
+		followUps = append(followUps, FollowUp{
+			Kind:    FollowUpFailedDelivery,
+			Subject: "notification/" + delivery.ID,
+			Message: fmt.Sprintf("%s to %s not delivered: %s", delivery.Alert.Name, delivery.Channel, delivery.LastError),
+		})
+	}
+	for _, finding := range e.GetFindings(FindingFilter{Status: FindingOpen}) {
+		if finding.FirstSeen.Before(since) || (finding.Severity != "critical" && finding.Severity != "high") {
+			continue
+		}
+		subject := finding.ID
+		if finding.Subject != "" {
+			subject += " " + finding.Subject
+		}
+		message := finding.Title
+		if finding.Message != "" {
+			message += ": " + finding.Message
+		}
+		followUps = append(followUps, FollowUp{Kind: FollowUpOpenFinding, Subject: strings.TrimSpace(subject), Message: message})
+	}
+	return followUps
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_Handoff(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ContextName: "prod"})
+	start := time.Now().Add(-time.Minute)
+
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusDegraded, Message: "1 node under memory pressure"})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "checkout crashlooping",
+		Details: map[string]interface{}{DetailFindings: []findings.Finding{{ID: findings.PodCrashLoop, Subject: "pod/shop/checkout-1"}}}})
+	engine.storeResult(CheckResult{Name: "service-health", Status: HealthStatusDegraded, Message: "1 service without endpoints",
+		Details: map[string]interface{}{DetailFindings: []findings.Finding{{ID: findings.PodNotReady, Subject: "pod/shop/web-1"}}}})
+	engine.storeAIInsights("pod-health", &ai.AnalysisResponse{Summary: "Missing DATABASE_URL", Severity: ai.SeverityHigh}, nil)
+	engine.RecordChange(Change{Kind: ChangeKindRemediation, Resource: "remediation/restart-checkout", Message: "Restart checkout",
+		To: "timed out", Details: map[string]interface{}{"success": false}})
+	engine.RecordChange(Change{Timestamp: start.Add(-time.Hour), Kind: ChangeKindRemediation, Resource: "remediation/old"})
+
+	handoff := engine.Handoff("", start)
+	if handoff.ClusterName != "prod" || handoff.Status == HealthStatusHealthy {
+		t.Errorf("unexpected cluster status %s %s", handoff.ClusterName, handoff.Status)
+	}
+	if len(handoff.Incidents) != 2 || handoff.Incidents[0].Check != "pod-health" || handoff.Incidents[0].Diagnosis != "Missing DATABASE_URL" ||
+		handoff.Incidents[0].FailingSince.IsZero() {
+		t.Errorf("expected pod-health first with its diagnosis, got %+v", handoff.Incidents)
+	}
+	// A check's first result is not a transition, so service-health fires no alert
+	if len(handoff.AlertsFired) != 2 || len(handoff.AlertsResolved) != 1 {
+		t.Errorf("expected 2 alerts fired and 1 resolved, got %d and %d", len(handoff.AlertsFired), len(handoff.AlertsResolved))
+	}
+	if len(handoff.Remediations) != 1 {
+		t.Errorf("expected only the remediation within the shift, got %+v", handoff.Remediations)
+	}
+	if len(handoff.Insights) != 1 || handoff.Insights[0].Check != "pod-health" || handoff.Insights[0].Severity != "high" {
+		t.Errorf("unexpected insights: %+v", handoff.Insights)
+	}
+
+	kinds := make(map[string]string)
+	for _, followUp := range handoff.FollowUps {
+		kinds[followUp.Kind] = followUp.Subject
+	}
+	if kinds[FollowUpFailedRemediation] != "remediation/restart-checkout" {
+		t.Errorf("expected the failed remediation as a follow-up, got %+v", handoff.FollowUps)
+	}
+	// Only high and critical findings need following up
+	if kinds[FollowUpOpenFinding] != findings.PodCrashLoop+" pod/shop/checkout-1" || len(handoff.FollowUps) != 2 {
+		t.Errorf("expected the crash loop finding as a follow-up, got %+v", handoff.FollowUps)
+	}
+
+	if later := engine.Handoff("", time.Now().Add(time.Minute)); len(later.AlertsFired) != 0 || len(later.Insights) != 0 || len(later.Incidents) != 2 {
+		t.Errorf("expected only current incidents in an empty window, got %+v", later)
+	}
+}
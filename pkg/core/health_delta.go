@@ -0,0 +1,135 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// HealthDelta is the difference between two cluster health snapshots: the
+// overall status and score, and only the checks whose outcome changed.
+// Checks are compared by status, message, error, details and staleness, so
+// a check that only ran again is not sent.
+type HealthDelta struct {
+	Base        uint64                    `json:"base"` // Sequence number of the health the delta applies to
+	ClusterName string                    `json:"cluster_name"`
+	Status      HealthStatus              `json:"status"`
+	Score       HealthScore               `json:"score"`
+	Timestamp   time.Time                 `json:"timestamp"`
+	Changed     []CheckResult             `json:"changed,omitempty"`
+	Removed     []string                  `json:"removed,omitempty"`
+	Freshness   map[string]CheckFreshness `json:"freshness,omitempty"` // For changed checks
+
+	StateRevision uint64 `json:"state_revision,omitempty"`
+}
+
+// DiffHealth returns the delta that turns previous into next
+func DiffHealth(previous, next ClusterHealth) HealthDelta {
+	delta := HealthDelta{
+		ClusterName: next.ClusterName,
+		Status:      next.Status,
+		Score:       next.Score,
+		Timestamp:   next.Timestamp,
+
+		StateRevision: next.StateRevision,
+	}
+
+	before := make(map[string]string, len(previous.Checks))
+	for _, result := range previous.Checks {
+		before[result.Name] = checkFingerprint(result, previous.Freshness[result.Name].Stale)
+	}
+	for _, result := range next.Checks {
+		fingerprint, existed := before[result.Name]
+		delete(before, result.Name)
+		if existed && fingerprint != "" && fingerprint == checkFingerprint(result, next.Freshness[result.Name].Stale) {
+			continue
+		}
+		delta.Changed = append(delta.Changed, result)
+		if freshness, ok := next.Freshness[result.Name]; ok {
+			if delta.Freshness == nil {
+				delta.Freshness = make(map[string]CheckFreshness)
+			}
+			delta.Freshness[result.Name] = freshness
+		}
+	}
+	for name := range before {
+		delta.Removed = append(delta.Removed, name)
+	}
+	sort.Strings(delta.Removed)
+	return delta
+}
+
+// ApplyDelta returns the health with a delta applied. Unchanged checks keep
+// the results, and freshness, of the health the delta is applied to.
+func (h ClusterHealth) ApplyDelta(delta HealthDelta) ClusterHealth {
+	applied := h
+	applied.ClusterName = delta.ClusterName
+	applied.Status = delta.Status
+	applied.Score = delta.Score
+	applied.Timestamp = delta.Timestamp
+	applied.StateRevision = delta.StateRevision
+
+	changed := make(map[string]CheckResult, len(delta.Changed))
+	for _, result := range delta.Changed {
+		changed[result.Name] = result
+	}
+	removed := make(map[string]bool, len(delta.Removed))
+	for _, name := range delta.Removed {
+		removed[name] = true
+	}
+
+	applied.Checks = make([]CheckResult, 0, len(h.Checks)+len(delta.Changed))
+	for _, result := range h.Checks {
+		if removed[result.Name] {
+			continue
+		}
+		if update, ok := changed[result.Name]; ok {
+			result = update
+			delete(changed, result.Name)
+		}
+		applied.Checks = append(applied.Checks, result)
+	}
+	for _, result := range delta.Changed {
+		if _, added := changed[result.Name]; added {
+			applied.Checks = append(applied.Checks, result)
+		}
+	}
+
+	if len(h.Freshness) > 0 || len(delta.Freshness) > 0 {
+		applied.Freshness = make(map[string]CheckFreshness, len(h.Freshness)+len(delta.Freshness))
+		for name, freshness := range h.Freshness {
+			if !removed[name] {
+				applied.Freshness[name] = freshness
+			}
+		}
+		for name, freshness := range delta.Freshness {
+			applied.Freshness[name] = freshness
+		}
+	}
+	return applied
+}
+
+// checkFingerprint hashes the parts of a result that make it news to a
+// client, since timestamps, durations and metrics change on every run. It is
+// empty for results whose details can't be encoded, which always count as
+// changed.
+func checkFingerprint(result CheckResult, stale bool) string {
+	errorMessage := ""
+	if result.Error != nil {
+		errorMessage = result.Error.Error()
+	}
+	data, err := json.Marshal(struct {
+		Status            HealthStatus
+		Message           string
+		Error             string
+		Details           map[string]interface{}
+		AffectedResources int
+		Stale             bool
+	}{result.Status, result.Message, errorMessage, result.Details, result.AffectedResources, stale})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return string(sum[:])
+}
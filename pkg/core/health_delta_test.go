@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiffHealth(t *testing.T) {
+	previous := ClusterHealth{
+		ClusterName: "prod",
+		Status:      HealthStatusHealthy,
+		Checks: []CheckResult{
+			{Name: "pod-health", Status: HealthStatusHealthy, Message: "all pods ready", Metrics: []Metric{{Name: "pods", Value: 10}}},
+			{Name: "node-health", Status: HealthStatusHealthy},
+			{Name: "event-rates", Status: HealthStatusHealthy},
+			{Name: "service-health", Status: HealthStatusHealthy},
+		},
+		Freshness: map[string]CheckFreshness{"event-rates": {Expensive: true}},
+	}
+	next := ClusterHealth{
+		ClusterName: "prod",
+		Status:      HealthStatusDegraded,
+		Checks: []CheckResult{
+			// Only the metrics changed
+			{Name: "pod-health", Status: HealthStatusHealthy, Message: "all pods ready", Metrics: []Metric{{Name: "pods", Value: 11}}},
+			{Name: "node-health", Status: HealthStatusDegraded, Error: errors.New("node not ready")},
+			{Name: "event-rates", Status: HealthStatusHealthy},
+			{Name: "ingress-health", Status: HealthStatusHealthy},
+		},
+		Freshness: map[string]CheckFreshness{"event-rates": {Expensive: true, Stale: true}},
+	}
+
+	delta := DiffHealth(previous, next)
+
+	var changed []string
+	for _, result := range delta.Changed {
+		changed = append(changed, result.Name)
+	}
+	if want := []string{"node-health", "event-rates", "ingress-health"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("expected changed checks %v, got %v", want, changed)
+	}
+	if want := []string{"service-health"}; !reflect.DeepEqual(delta.Removed, want) {
+		t.Errorf("expected removed checks %v, got %v", want, delta.Removed)
+	}
+	if delta.Status != HealthStatusDegraded || !delta.Freshness["event-rates"].Stale {
+		t.Errorf("expected degraded status and stale event-rates, got %+v", delta)
+	}
+
+	applied := previous.ApplyDelta(delta)
+	if len(applied.Checks) != len(next.Checks) {
+		t.Fatalf("expected %d checks after applying, got %+v", len(next.Checks), applied.Checks)
+	}
+	for i, result := range applied.Checks {
+		if result.Name != next.Checks[i].Name || result.Status != next.Checks[i].Status {
+			t.Errorf("check %d: expected %s %s, got %s %s", i, next.Checks[i].Name, next.Checks[i].Status, result.Name, result.Status)
+		}
+	}
+	if applied.Status != next.Status || !reflect.DeepEqual(applied.Freshness, next.Freshness) {
+		t.Errorf("expected status and freshness of next, got %s %+v", applied.Status, applied.Freshness)
+	}
+}
+
+func TestDiffHealth_Unchanged(t *testing.T) {
+	health := ClusterHealth{Checks: []CheckResult{{Name: "pod-health", Status: HealthStatusHealthy}}}
+	if delta := DiffHealth(health, health); len(delta.Changed) != 0 || len(delta.Removed) != 0 {
+		t.Errorf("expected an empty delta, got %+v", delta)
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"k8s.io/klog/v2"
+)
+
+// HealthChange is a change of the overall cluster status between check
+// cycles
+type HealthChange struct {
+	Previous HealthStatus
+	Current  HealthStatus
+	Health   ClusterHealth // Cluster health after the change
+}
+
+// Hooks let programs embedding the engine attach behavior to its events,
+// such as exporting results to another system, without forking it.
+//
+// Hooks of one kind run synchronously in the order they were registered, on
+// the goroutine that produced the event, after the engine has stored and
+// journaled it, so engine getters called from a hook already reflect it.
+// A hook that panics is logged and skipped; the remaining hooks and the
+// engine carry on. Hooks delay the engine while they run, so slow work
+// should be handed off to another goroutine. OnHealthChange hooks must not
+// ingest metrics, which would wait on the notification in progress.
+type hooks struct {
+	mu           sync.RWMutex
+	nextID       uint64
+	checkResult  []hook[CheckResult]
+	healthChange []hook[HealthChange]
+	alert        []hook[Alert]
+	aiInsight    []hook[AIInsightEvent]
+
+	healthMu   sync.Mutex   // Serializes health change notifications
+	lastStatus HealthStatus // Cluster status OnHealthChange hooks last saw; guarded by healthMu
+	panics     atomic.Int64
+}
+
+// hook is a registered callback
+type hook[T any] struct {
+	id   uint64
+	name string
+	fn   func(T)
+}
+
+// OnCheckResult registers a hook called with every check result, after the
+// result is stored and its alerts are processed. It returns a function that
+// removes the hook.
+func (e *Engine) OnCheckResult(name string, fn func(CheckResult)) (remove func()) {
+	return addHook(&e.hooks, &e.hooks.checkResult, name, fn)
+}
+
+// OnHealthChange registers a hook called when the overall cluster status
+// changes after a check cycle or a batch of ingested metrics. It returns a
+// function that removes the hook.
+func (e *Engine) OnHealthChange(name string, fn func(HealthChange)) (remove func()) {
+	return addHook(&e.hooks, &e.hooks.healthChange, name, fn)
+}
+
+// OnAlert registers a hook called with the alert raised for every failing
+// check result, the same alerts sent on EngineConfig.AlertChan. It returns
+// a function that removes the hook.
+func (e *Engine) OnAlert(name string, fn func(Alert)) (remove func()) {
+	return addHook(&e.hooks, &e.hooks.alert, name, fn)
+}
+
+// OnAIInsight registers a hook called when an AI analysis of a failing
+// check completes. It returns a function that removes the hook.
+func (e *Engine) OnAIInsight(name string, fn func(AIInsightEvent)) (remove func()) {
+	return addHook(&e.hooks, &e.hooks.aiInsight, name, fn)
+}
+
+// HookPanics returns how many times hooks have panicked
+func (e *Engine) HookPanics() int64 {
+	return e.hooks.panics.Load()
+}
+
+// addHook appends a hook to a list and returns a function removing it
+func addHook[T any](h *hooks, list *[]hook[T], name string, fn func(T)) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	id := h.nextID
+	*list = append(*list, hook[T]{id: id, name: name, fn: fn})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			kept := make([]hook[T], 0, len(*list))
+			for _, registered := range *list {
+				if registered.id != id {
+					kept = append(kept, registered)
+				}
+			}
+			*list = kept
+		})
+	}
+}
+
+// runHooks calls each hook in a list with an event, isolating panics
+func runHooks[T any](h *hooks, list *[]hook[T], kind string, event T) {
+	h.mu.RLock()
+	registered := *list
+	h.mu.RUnlock()
+
+	for _, hk := range registered {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					h.panics.Add(1)
+					klog.Errorf("%s hook %s panicked: %v\n%s", kind, hk.name, r, debug.Stack())
+				}
+			}()
+			hk.fn(event)
+		}()
+	}
+}
+
+// notifyHealthChange calls OnHealthChange hooks when the cluster status
+// differs from the one they last saw. The first status seen only sets the
+// baseline.
+func (e *Engine) notifyHealthChange() {
+	e.hooks.mu.RLock()
+	watched := len(e.hooks.healthChange) > 0
+	e.hooks.mu.RUnlock()
+	if !watched {
+		return
+	}
+
+	e.hooks.healthMu.Lock()
+	defer e.hooks.healthMu.Unlock()
+	health := e.GetClusterHealth(e.currentContext)
+	previous := e.hooks.lastStatus
+	e.hooks.lastStatus = health.Status
+	if previous == "" || previous == health.Status {
+		return
+	}
+	runHooks(&e.hooks, &e.hooks.healthChange, "OnHealthChange", HealthChange{Previous: previous, Current: health.Status, Health: health})
+}
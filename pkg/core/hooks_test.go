@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_HookOrderingAndPanics(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	var calls []string
+	engine.OnCheckResult("first", func(result CheckResult) { calls = append(calls, "first:"+result.Name) })
+	engine.OnCheckResult("broken", func(CheckResult) { panic("boom") })
+	remove := engine.OnCheckResult("removed", func(CheckResult) { calls = append(calls, "removed") })
+	engine.OnCheckResult("last", func(result CheckResult) {
+		// Results are stored before hooks run
+		if _, ok := engine.GetResult(result.Name); !ok {
+			t.Error("expected the result stored before the hook ran")
+		}
+		calls = append(calls, "last:"+result.Name)
+	})
+	remove()
+	remove()
+
+	var alerts []Alert
+	engine.OnAlert("alerts", func(alert Alert) { alerts = append(alerts, alert) })
+
+	result := CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "3 pods crashlooping"}
+	engine.storeResult(result)
+	engine.processResult(result)
+
+	if want := []string{"first:pod-health", "last:pod-health"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected hooks in registration order without the removed one, got %v", calls)
+	}
+	if engine.HookPanics() != 1 {
+		t.Errorf("expected 1 panic counted, got %d", engine.HookPanics())
+	}
+	if len(alerts) != 1 || alerts[0].Name != "pod-health" || alerts[0].Severity != AlertSeverityCritical {
+		t.Errorf("expected an alert for the failing check, got %+v", alerts)
+	}
+}
+
+func TestEngine_OnHealthChange(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	var changes []HealthChange
+	engine.OnHealthChange("changes", func(change HealthChange) { changes = append(changes, change) })
+
+	for _, status := range []HealthStatus{HealthStatusHealthy, HealthStatusHealthy, HealthStatusUnhealthy, HealthStatusHealthy} {
+		engine.storeResult(CheckResult{Name: "pod-health", Status: status})
+		engine.notifyHealthChange()
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes after the baseline, got %+v", changes)
+	}
+	if changes[0].Previous != HealthStatusHealthy || changes[0].Current != HealthStatusUnhealthy || changes[0].Health.Status != HealthStatusUnhealthy {
+		t.Errorf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1].Current != HealthStatusHealthy {
+		t.Errorf("unexpected second change: %+v", changes[1])
+	}
+}
+
+func TestEngine_OnAIInsight(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy})
+
+	var insights []AIInsightEvent
+	engine.OnAIInsight("insights", func(insight AIInsightEvent) {
+		// The engine's locks are released, so hooks can read results
+		if result, _ := engine.GetResult(insight.Check); result.Details["ai_diagnosis"] == nil {
+			t.Error("expected the diagnosis stored before the hook ran")
+		}
+		insights = append(insights, insight)
+	})
+	engine.storeAIInsights("pod-health", &ai.AnalysisResponse{Summary: "Missing DATABASE_URL"}, nil)
+	engine.storeAIInsights("unknown", &ai.AnalysisResponse{Summary: "ignored"}, nil)
+
+	if len(insights) != 1 || insights[0].Diagnosis.Summary != "Missing DATABASE_URL" {
+		t.Errorf("expected one insight for the stored check, got %+v", insights)
+	}
+}
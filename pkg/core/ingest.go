@@ -0,0 +1,243 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/slo"
+)
+
+// Application metrics pushed to the engine are reported as a check result
+// named IngestCheckPrefix + source, so they join the health score, alert
+// rules and AI diagnosis like any built-in check
+const (
+	IngestCheckPrefix = "app:"
+	// IngestSourceLabel is added to every ingested metric
+	IngestSourceLabel = "source"
+	// MaxIngestBatch bounds the metrics accepted in one request
+	MaxIngestBatch = 1000
+
+	// Timestamps further ahead than this are rejected as clock errors
+	maxIngestClockSkew = 5 * time.Minute
+)
+
+// ErrInvalidMetrics is returned when an ingest batch fails validation
+var ErrInvalidMetrics = errors.New("invalid metrics")
+
+var (
+	sourcePattern     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9_.]{0,61}[a-z0-9])?$`)
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// MetricCondition sets the status of an ingest source's check result while
+// an ingested metric crosses a threshold
+type MetricCondition struct {
+	Name      string            `json:"name"`
+	Source    string            `json:"source,omitempty"` // Empty applies to every source
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels,omitempty"` // All must match
+	Operator  string            `json:"operator"`         // >, >=, < or <=
+	Threshold float64           `json:"threshold"`
+	Status    HealthStatus      `json:"status"` // degraded or unhealthy
+}
+
+// Validate checks that the condition can be evaluated
+func (c MetricCondition) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("metric condition name is required")
+	}
+	if !metricNamePattern.MatchString(c.Metric) {
+		return fmt.Errorf("metric condition %s: invalid metric name %q", c.Name, c.Metric)
+	}
+	switch c.Operator {
+	case ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf("metric condition %s: operator must be >, >=, < or <=", c.Name)
+	}
+	switch c.Status {
+	case HealthStatusDegraded, HealthStatusUnhealthy:
+	default:
+		return fmt.Errorf("metric condition %s: status must be degraded or unhealthy", c.Name)
+	}
+	return nil
+}
+
+// breached reports whether a value crosses the condition's threshold
+func (c MetricCondition) breached(value float64) bool {
+	switch c.Operator {
+	case ">":
+		return value > c.Threshold
+	case ">=":
+		return value >= c.Threshold
+	case "<":
+		return value < c.Threshold
+	case "<=":
+		return value <= c.Threshold
+	}
+	return false
+}
+
+// matches reports whether a metric from source is evaluated by the condition
+func (c MetricCondition) matches(source string, metric Metric) bool {
+	if metric.Name != c.Metric || (c.Source != "" && c.Source != source) {
+		return false
+	}
+	for key, value := range c.Labels {
+		if metric.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// IngestMetrics accepts application metrics from source. They are kept in
+// the metric history, run through anomaly detection, fed to the SLOs that
+// select them and evaluated against the metric conditions; the outcome is
+// stored and returned as the source's check result.
+func (e *Engine) IngestMetrics(source string, metrics []Metric) (CheckResult, error) {
+	now := time.Now()
+	if err := validateIngest(source, metrics, now); err != nil {
+		return CheckResult{}, err
+	}
+
+	batch := make([]Metric, len(metrics))
+	for i, metric := range metrics {
+		labels := make(map[string]string, len(metric.Labels)+1)
+		for key, value := range metric.Labels {
+			labels[key] = value
+		}
+		labels[IngestSourceLabel] = source
+		metric.Labels = labels
+		if metric.Timestamp.IsZero() {
+			metric.Timestamp = now
+		}
+		if metric.Type == "" {
+			metric.Type = MetricTypeGauge
+		}
+		batch[i] = metric
+	}
+	batch = e.cardinality.Apply(batch)
+
+	result := CheckResult{
+		Name:       IngestCheckPrefix + source,
+		Status:     HealthStatusHealthy,
+		Timestamp:  now,
+		Metrics:    batch,
+		Confidence: 1.0,
+		Details: map[string]interface{}{
+			"source":   source,
+			"received": len(metrics),
+		},
+	}
+
+	var breaches []string
+	for _, condition := range e.metricConditions {
+		for _, metric := range e.latestIngested(source, condition, batch) {
+			if !condition.breached(metric.Value) {
+				continue
+			}
+			breaches = append(breaches, fmt.Sprintf("%s: %s = %g %s %g",
+				condition.Name, metricSeriesKey(metric), metric.Value, condition.Operator, condition.Threshold))
+			if statusRank(condition.Status) > statusRank(result.Status) {
+				result.Status = condition.Status
+			}
+		}
+	}
+	if len(breaches) > 0 {
+		result.Message = fmt.Sprintf("%d metric conditions breached: %s", len(breaches), strings.Join(breaches, "; "))
+		result.Details["breached_conditions"] = breaches
+		result.AffectedResources = len(breaches)
+	} else {
+		result.Message = fmt.Sprintf("Received %d metrics from %s", len(metrics), source)
+	}
+
+	result = e.withMaintenance(result, time.Now())
+	e.storeResult(result)
+	e.processResult(result)
+	e.generation.Add(1)
+	e.notifyHealthChange()
+	return result, nil
+}
+
+// latestIngested returns the newest value of every series from source the
+// condition evaluates, taking the batch over earlier history
+func (e *Engine) latestIngested(source string, condition MetricCondition, batch []Metric) []Metric {
+	latest := make(map[string]Metric)
+	for key, series := range e.GetMetricHistory(condition.Metric) {
+		last := series[len(series)-1]
+		if last.Labels[IngestSourceLabel] == source && condition.matches(source, last) {
+			latest[key] = last
+		}
+	}
+	for _, metric := range batch {
+		if condition.matches(source, metric) {
+			latest[metricSeriesKey(metric)] = metric
+		}
+	}
+
+	keys := make([]string, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]Metric, len(keys))
+	for i, key := range keys {
+		values[i] = latest[key]
+	}
+	return values
+}
+
+// validateIngest rejects batches that would corrupt metric history
+func validateIngest(source string, metrics []Metric, now time.Time) error {
+	if !sourcePattern.MatchString(source) {
+		return fmt.Errorf("%w: source must be 1-63 lowercase letters, digits, '-', '_' or '.'", ErrInvalidMetrics)
+	}
+	if len(metrics) == 0 {
+		return fmt.Errorf("%w: no metrics", ErrInvalidMetrics)
+	}
+	if len(metrics) > MaxIngestBatch {
+		return fmt.Errorf("%w: %d metrics exceeds the limit of %d per request", ErrInvalidMetrics, len(metrics), MaxIngestBatch)
+	}
+	for i, metric := range metrics {
+		if !metricNamePattern.MatchString(metric.Name) {
+			return fmt.Errorf("%w: metric %d has invalid name %q", ErrInvalidMetrics, i, metric.Name)
+		}
+		if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+			return fmt.Errorf("%w: %s has a non-finite value", ErrInvalidMetrics, metric.Name)
+		}
+		for key := range metric.Labels {
+			if !labelNamePattern.MatchString(key) {
+				return fmt.Errorf("%w: %s has invalid label name %q", ErrInvalidMetrics, metric.Name, key)
+			}
+		}
+		switch metric.Type {
+		case "", MetricTypeGauge, MetricTypeCounter, MetricTypeHistogram, MetricTypeSummary:
+		default:
+			return fmt.Errorf("%w: %s has unknown type %q", ErrInvalidMetrics, metric.Name, metric.Type)
+		}
+		if metric.Timestamp.After(now.Add(maxIngestClockSkew)) {
+			return fmt.Errorf("%w: %s is timestamped in the future", ErrInvalidMetrics, metric.Name)
+		}
+	}
+	return nil
+}
+
+// sloMetrics converts metrics for the SLO tracker
+func sloMetrics(metrics []Metric) []slo.Metric {
+	converted := make([]slo.Metric, len(metrics))
+	for i, metric := range metrics {
+		converted[i] = slo.Metric{
+			Name:      metric.Name,
+			Value:     metric.Value,
+			Labels:    metric.Labels,
+			Timestamp: metric.Timestamp,
+		}
+	}
+	return converted
+}
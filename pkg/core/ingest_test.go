@@ -0,0 +1,165 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIngestMetrics_Validation(t *testing.T) {
+	tooMany := make([]Metric, MaxIngestBatch+1)
+	for i := range tooMany {
+		tooMany[i] = Metric{Name: "queue_depth", Value: 1}
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		metrics []Metric
+	}{
+		{"invalid source", "Checkout Service", []Metric{{Name: "queue_depth", Value: 1}}},
+		{"no metrics", "checkout", nil},
+		{"too many metrics", "checkout", tooMany},
+		{"invalid name", "checkout", []Metric{{Name: "queue depth", Value: 1}}},
+		{"non-finite value", "checkout", []Metric{{Name: "queue_depth", Value: math.NaN()}}},
+		{"invalid label", "checkout", []Metric{{Name: "queue_depth", Value: 1, Labels: map[string]string{"queue-name": "orders"}}}},
+		{"unknown type", "checkout", []Metric{{Name: "queue_depth", Value: 1, Type: "rate"}}},
+		{"future timestamp", "checkout", []Metric{{Name: "queue_depth", Value: 1, Timestamp: time.Now().Add(time.Hour)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+			if _, err := engine.IngestMetrics(tt.source, tt.metrics); !errors.Is(err, ErrInvalidMetrics) {
+				t.Errorf("expected ErrInvalidMetrics, got %v", err)
+			}
+			if len(engine.GetResults()) != 0 {
+				t.Error("expected a rejected batch to store no result")
+			}
+		})
+	}
+}
+
+func TestIngestMetrics_Conditions(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		MetricConditions: []MetricCondition{
+			{Name: "backlog", Source: "checkout", Metric: "queue_depth", Operator: ">", Threshold: 500, Status: HealthStatusDegraded},
+			{Name: "errors", Metric: "error_rate", Labels: map[string]string{"route": "/pay"}, Operator: ">=", Threshold: 0.05, Status: HealthStatusUnhealthy},
+			{Name: "invalid", Metric: "error_rate", Operator: "!=", Status: HealthStatusUnhealthy},
+		},
+	})
+	if len(engine.metricConditions) != 2 {
+		t.Fatalf("expected the invalid condition to be skipped, got %d conditions", len(engine.metricConditions))
+	}
+
+	result, err := engine.IngestMetrics("checkout", []Metric{
+		{Name: "queue_depth", Value: 42},
+		{Name: "error_rate", Value: 0.2, Labels: map[string]string{"route": "/health"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Name != "app:checkout" || result.Status != HealthStatusHealthy {
+		t.Errorf("expected healthy app:checkout, got %s %s: %s", result.Name, result.Status, result.Message)
+	}
+
+	// A later batch without the error rate is still judged on its last value
+	if _, err := engine.IngestMetrics("checkout", []Metric{
+		{Name: "error_rate", Value: 0.1, Labels: map[string]string{"route": "/pay"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err = engine.IngestMetrics("checkout", []Metric{{Name: "queue_depth", Value: 900}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != HealthStatusUnhealthy {
+		t.Errorf("expected the worst breached condition to win, got %s", result.Status)
+	}
+	if result.AffectedResources != 2 || !strings.Contains(result.Message, "backlog") || !strings.Contains(result.Message, "errors") {
+		t.Errorf("expected both breaches to be reported, got %d: %s", result.AffectedResources, result.Message)
+	}
+
+	// Conditions scoped to a source don't apply to others
+	result, err = engine.IngestMetrics("billing", []Metric{{Name: "queue_depth", Value: 900}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != HealthStatusHealthy {
+		t.Errorf("expected billing to be healthy, got %s: %s", result.Status, result.Message)
+	}
+
+	stored, ok := engine.GetResult("app:checkout")
+	if !ok || stored.Status != HealthStatusUnhealthy {
+		t.Errorf("expected the stored result to be unhealthy, got %+v", stored)
+	}
+	health := engine.GetClusterHealth("test")
+	if health.Score.Raw >= 100 {
+		t.Errorf("expected the unhealthy application to lower the score, got %v", health.Score.Raw)
+	}
+}
+
+func TestIngestMetrics_HistoryAndSLOs(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		SLOs: []slo.SLO{
+			{Name: "checkout-availability", SLI: "availability", Target: 99, Window: time.Hour, Labels: map[string]string{"source": "checkout"}},
+		},
+	})
+
+	metrics := []Metric{
+		{Name: "request_total", Value: 100},
+		{Name: "request_success", Value: 90},
+	}
+	if _, err := engine.IngestMetrics("checkout", metrics); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := engine.IngestMetrics("billing", []Metric{{Name: "request_total", Value: 100}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metrics[0].Labels != nil {
+		t.Error("expected the caller's metrics to be left unchanged")
+	}
+
+	history := engine.GetMetricHistory("request_total")
+	series, ok := history[`request_total{source="checkout"}`]
+	if !ok || len(series) != 1 || series[0].Type != MetricTypeGauge || series[0].Timestamp.IsZero() {
+		t.Errorf("expected a defaulted checkout series, got %+v", history)
+	}
+	if len(history) != 2 {
+		t.Errorf("expected a series per source, got %d", len(history))
+	}
+
+	status, ok := engine.sloTracker.GetSLOStatus("checkout-availability")
+	if !ok {
+		t.Fatal("expected the configured SLO to be tracked")
+	}
+	if status.CurrentValue != 90 || !status.IsViolated {
+		t.Errorf("expected 90%% availability from checkout only, got %+v", status)
+	}
+}
+
+func TestProcessResult_FeedsSLOs(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		SLOs: []slo.SLO{
+			{Name: "checkout-throughput", SLI: "throughput", Target: 10, Window: time.Hour, Metrics: []string{"http_requests_per_second"}},
+		},
+	})
+
+	// Metrics a check reads, e.g. from the custom metrics API, feed SLOs
+	// like ingested ones
+	engine.processResult(CheckResult{Name: "checkout-rps", Status: HealthStatusHealthy, Metrics: []Metric{
+		{Name: "http_requests_per_second", Value: 4, Labels: map[string]string{"check": "checkout-rps"}},
+		{Name: "custom_resources", Value: 3},
+	}})
+	status, ok := engine.sloTracker.GetSLOStatus("checkout-throughput")
+	if !ok || status.CurrentValue != 4 || !status.IsViolated {
+		t.Errorf("expected the check's metric to violate the SLO, got %+v", status)
+	}
+}
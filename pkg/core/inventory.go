@@ -0,0 +1,83 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	"k8s.io/klog/v2"
+)
+
+// DefaultInventoryInterval is how often the cluster inventory is refreshed
+// by default
+const DefaultInventoryInterval = 15 * time.Minute
+
+// clusterInventory caches the latest cluster inventory
+type clusterInventory struct {
+	mu       sync.Mutex
+	snapshot *inventory.Inventory
+	interval time.Duration
+}
+
+// Inventory returns the cached cluster inventory, collecting it first if it
+// hasn't been yet
+func (e *Engine) Inventory(ctx context.Context) *inventory.Inventory {
+	e.inventory.mu.Lock()
+	defer e.inventory.mu.Unlock()
+	if e.inventory.snapshot == nil {
+		e.inventory.snapshot = e.collectInventory(ctx)
+	}
+	return e.inventory.snapshot
+}
+
+// cachedInventory returns the cluster inventory if it has been collected,
+// without collecting it
+func (e *Engine) cachedInventory() *inventory.Inventory {
+	e.inventory.mu.Lock()
+	defer e.inventory.mu.Unlock()
+	return e.inventory.snapshot
+}
+
+// RefreshInventory collects the cluster inventory now, caches it and
+// decides again which checks run
+func (e *Engine) RefreshInventory(ctx context.Context) *inventory.Inventory {
+	snapshot := e.collectInventory(ctx)
+	e.inventory.mu.Lock()
+	e.inventory.snapshot = snapshot
+	e.inventory.mu.Unlock()
+	e.discoverChecks(snapshot)
+	return snapshot
+}
+
+// collectInventory takes stock of the cluster within the check timeout
+func (e *Engine) collectInventory(ctx context.Context) *inventory.Inventory {
+	if e.client == nil {
+		return &inventory.Inventory{CollectedAt: time.Now(), Errors: []string{"no Kubernetes client"}}
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.checkTimeout)
+	defer cancel()
+
+	snapshot := inventory.Collect(ctx, e.client)
+	for _, err := range snapshot.Errors {
+		klog.V(2).Infof("Incomplete cluster inventory: %s", err)
+	}
+	klog.V(2).Infof("Collected cluster inventory in %s: %s", snapshot.Duration, snapshot.Summary())
+	return snapshot
+}
+
+// runInventory refreshes the cluster inventory now and then every inventory
+// interval until the engine stops
+func (e *Engine) runInventory() {
+	ticker := time.NewTicker(e.inventory.interval)
+	defer ticker.Stop()
+	for {
+		e.RefreshInventory(e.ctx)
+
+		select {
+		case <-ticker.C:
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_Inventory(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+	engine := NewEngine(EngineConfig{KubeClient: client, ContextName: "prod"})
+	if engine.inventory.interval != DefaultInventoryInterval {
+		t.Errorf("expected the default inventory interval, got %s", engine.inventory.interval)
+	}
+
+	// AI prompts only carry an inventory once one has been collected
+	if health := engine.convertToAIClusterHealth(ClusterHealth{ClusterName: "prod"}); health.Inventory != nil {
+		t.Error("expected no inventory before it is collected")
+	}
+
+	first := engine.Inventory(context.Background())
+	if first.Nodes.Total != 1 {
+		t.Fatalf("expected 1 node, got %+v", first.Nodes)
+	}
+	if err := client.CoreV1().Nodes().Delete(context.Background(), "worker-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete node: %v", err)
+	}
+	if cached := engine.Inventory(context.Background()); cached != first {
+		t.Error("expected the cached inventory until it is refreshed")
+	}
+	if refreshed := engine.RefreshInventory(context.Background()); refreshed.Nodes.Total != 0 {
+		t.Errorf("expected the refreshed inventory to have no nodes, got %+v", refreshed.Nodes)
+	}
+
+	health := engine.convertToAIClusterHealth(ClusterHealth{ClusterName: "prod"})
+	if health.Inventory == nil || health.Inventory.Nodes.Total != 0 {
+		t.Errorf("expected AI cluster context to include the inventory, got %+v", health.Inventory)
+	}
+}
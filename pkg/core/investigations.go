@@ -0,0 +1,202 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+// ErrInvestigationNotFound is returned for an unknown investigation plan ID
+var ErrInvestigationNotFound = errors.New("investigation plan not found")
+
+// defaultInvestigations is how many investigation plans are kept
+const defaultInvestigations = 50
+
+// investigationTimeout bounds running every step of a plan
+const investigationTimeout = 5 * time.Minute
+
+// InvestigationLog is a bounded record of investigation plans, oldest first
+type InvestigationLog struct {
+	capacity int
+	mu       sync.RWMutex
+	plans    []ai.InvestigationPlan
+	nextID   int
+}
+
+// NewInvestigationLog creates an investigation log retaining up to capacity plans
+func NewInvestigationLog(capacity int) *InvestigationLog {
+	if capacity <= 0 {
+		capacity = defaultInvestigations
+	}
+	return &InvestigationLog{
+		capacity: capacity,
+		plans:    make([]ai.InvestigationPlan, 0),
+	}
+}
+
+// Add stores a plan under a new ID and returns it
+func (l *InvestigationLog) Add(plan ai.InvestigationPlan) ai.InvestigationPlan {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	plan.ID = fmt.Sprintf("investigation-%d", l.nextID)
+	l.plans = append(l.plans, plan)
+	if len(l.plans) > l.capacity {
+		l.plans = l.plans[len(l.plans)-l.capacity:]
+	}
+	return plan
+}
+
+// Update replaces a stored plan with the same ID
+func (l *InvestigationLog) Update(plan ai.InvestigationPlan) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.plans {
+		if l.plans[i].ID == plan.ID {
+			l.plans[i] = plan
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvestigationNotFound, plan.ID)
+}
+
+// Get returns a plan by ID
+func (l *InvestigationLog) Get(id string) (ai.InvestigationPlan, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, plan := range l.plans {
+		if plan.ID == id {
+			return plan, nil
+		}
+	}
+	return ai.InvestigationPlan{}, fmt.Errorf("%w: %s", ErrInvestigationNotFound, id)
+}
+
+// List returns the plans for a check, oldest first; an empty check returns
+// all plans
+func (l *InvestigationLog) List(check string) []ai.InvestigationPlan {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	plans := make([]ai.InvestigationPlan, 0)
+	for _, plan := range l.plans {
+		if check == "" || plan.Check == check {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// PlanInvestigation builds and saves an investigation plan for a check from
+// the AI's latest suggested commands and the matching templates
+func (e *Engine) PlanInvestigation(check string) (ai.InvestigationPlan, error) {
+	result, ok := e.GetResult(check)
+	if !ok {
+		return ai.InvestigationPlan{}, fmt.Errorf("%w: %s", ErrCheckNotFound, check)
+	}
+	diagnosis, _ := result.Details["ai_diagnosis"].(*ai.AnalysisResponse)
+	healing, _ := result.Details["ai_healing"].(*ai.AnalysisResponse)
+	return e.savePlan(result, diagnosis, healing), nil
+}
+
+// savePlan builds a plan from a result and its AI analyses and stores it
+func (e *Engine) savePlan(result CheckResult, diagnosis, healing *ai.AnalysisResponse) ai.InvestigationPlan {
+	var suggestions []ai.SuggestedAction
+	for _, response := range []*ai.AnalysisResponse{diagnosis, healing} {
+		if response != nil {
+			suggestions = append(suggestions, response.Actions...)
+		}
+	}
+
+	plan := ai.BuildInvestigationPlan(e.convertToAICheckResult(result), diagnosis, suggestions,
+		investigationValues(result), ai.DefaultInvestigationTemplates())
+	plan.Cluster = e.currentContext
+	return e.investigations.Add(plan)
+}
+
+// planSuggestedInvestigation saves a plan when the AI suggested follow-up
+// kubectl commands for a failing check
+func (e *Engine) planSuggestedInvestigation(result CheckResult, diagnosis, healing *ai.AnalysisResponse) {
+	for _, response := range []*ai.AnalysisResponse{diagnosis, healing} {
+		if response == nil {
+			continue
+		}
+		for _, action := range response.Actions {
+			if strings.HasPrefix(strings.TrimSpace(action.Command), "kubectl ") {
+				plan := e.savePlan(result, diagnosis, healing)
+				klog.V(2).Infof("Saved investigation plan %s for %s with %d steps", plan.ID, result.Name, len(plan.Steps))
+				return
+			}
+		}
+	}
+}
+
+// GetInvestigations returns saved investigation plans for a check, or all
+// of them, oldest first
+func (e *Engine) GetInvestigations(check string) []ai.InvestigationPlan {
+	return e.investigations.List(check)
+}
+
+// GetInvestigation returns a saved investigation plan
+func (e *Engine) GetInvestigation(id string) (ai.InvestigationPlan, error) {
+	return e.investigations.Get(id)
+}
+
+// RunInvestigation executes the read-only steps of a saved plan through the
+// kubectl executor and saves their output with the plan
+func (e *Engine) RunInvestigation(ctx context.Context, id string) (ai.InvestigationPlan, error) {
+	plan, err := e.investigations.Get(id)
+	if err != nil {
+		return ai.InvestigationPlan{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, investigationTimeout)
+	defer cancel()
+	klog.Infof("Running read-only steps of investigation plan %s for %s", id, plan.Check)
+	ai.RunInvestigation(ctx, &plan, e.kubectl)
+
+	if err := e.investigations.Update(plan); err != nil {
+		return ai.InvestigationPlan{}, err
+	}
+	return plan, nil
+}
+
+// investigationValues returns the placeholder values for a result's plan:
+// the first pod, node and service it implicates and the first workload
+// blocked from creating pods
+func investigationValues(result CheckResult) map[string]string {
+	values := map[string]string{"check": result.Name}
+	set := func(key, value string) {
+		if values[key] == "" && value != "" {
+			values[key] = value
+		}
+	}
+
+	for _, ref := range ImplicatedResources(result) {
+		switch ref.Kind {
+		case "pod", "service":
+			if values["namespace"] == "" || values["namespace"] == ref.Namespace {
+				set(ref.Kind, ref.Name)
+				set("namespace", ref.Namespace)
+			}
+		case "node":
+			set("node", ref.Name)
+		}
+	}
+	for _, block := range BlockedDeployments(result) {
+		if values["namespace"] == "" || values["namespace"] == block.Namespace {
+			set("workload", block.Workload)
+			set("namespace", block.Namespace)
+		}
+	}
+	return values
+}
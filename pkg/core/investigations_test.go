@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeKubectl returns canned output for every command
+type fakeKubectl struct {
+	commands []string
+}
+
+func (f *fakeKubectl) Execute(ctx context.Context, command string) (string, error) {
+	f.commands = append(f.commands, command)
+	return "ok", nil
+}
+
+func (f *fakeKubectl) DryRun(ctx context.Context, command string) (string, error) {
+	return "", nil
+}
+
+func TestInvestigationValues(t *testing.T) {
+	result := CheckResult{
+		Name: "pod-health",
+		Details: map[string]interface{}{
+			DetailImplicatedResources: []string{"node/worker-2", "pod/shop/api-1", "pod/payments/ledger-0", "service/shop/api"},
+			DetailBlockedDeployments:  []DeploymentBlock{{Namespace: "shop", Workload: "ReplicaSet/api-5c8b"}},
+		},
+	}
+	want := map[string]string{
+		"check":     "pod-health",
+		"namespace": "shop",
+		"pod":       "api-1",
+		"node":      "worker-2",
+		"service":   "api",
+		"workload":  "ReplicaSet/api-5c8b",
+	}
+	if got := investigationValues(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("investigationValues() = %v, want %v", got, want)
+	}
+}
+
+func TestEngine_Investigations(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	defer engine.Stop()
+	kubectl := &fakeKubectl{}
+	engine.kubectl = kubectl
+
+	if _, err := engine.PlanInvestigation("pod-health"); !errors.Is(err, ErrCheckNotFound) {
+		t.Fatalf("expected ErrCheckNotFound before the check ran, got %v", err)
+	}
+
+	engine.results["pod-health"] = CheckResult{
+		Name:    "pod-health",
+		Status:  HealthStatusUnhealthy,
+		Message: "api-1 is in CrashLoopBackOff",
+		Details: map[string]interface{}{
+			DetailImplicatedResources: []string{"pod/shop/api-1"},
+			"ai_healing": &ai.AnalysisResponse{Actions: []ai.SuggestedAction{
+				{Title: "Roll back", Command: "kubectl rollout undo deployment/api -n <namespace>"},
+			}},
+		},
+	}
+	plan, err := engine.PlanInvestigation("pod-health")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.ID == "" || plan.Steps[0].Command != "kubectl rollout undo deployment/api -n shop" || plan.Steps[0].ReadOnly {
+		t.Fatalf("expected the AI suggestion first and not read-only, got %+v", plan.Steps)
+	}
+
+	ran, err := engine.RunInvestigation(context.Background(), plan.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kubectl.commands) != len(plan.Steps)-1 {
+		t.Errorf("expected every step but the rollback run, got %v", kubectl.commands)
+	}
+	saved, err := engine.GetInvestigation(plan.ID)
+	if err != nil || saved.LastRun.IsZero() || saved.Steps[1].Output != "ok" || !reflect.DeepEqual(saved, ran) {
+		t.Errorf("expected the run's output saved with the plan, got %+v (%v)", saved, err)
+	}
+	if plans := engine.GetInvestigations("node-health"); len(plans) != 0 {
+		t.Errorf("expected no plans for another check, got %d", len(plans))
+	}
+
+	if _, err := engine.RunInvestigation(context.Background(), "investigation-99"); !errors.Is(err, ErrInvestigationNotFound) {
+		t.Errorf("expected ErrInvestigationNotFound, got %v", err)
+	}
+}
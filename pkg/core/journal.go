@@ -0,0 +1,182 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+)
+
+// Stream event types recorded in the journal
+const (
+	StreamEventCheckResult = "check_result"
+	StreamEventAlert       = "alert"
+	StreamEventAIInsight   = "ai_insight"
+	StreamEventAnalysisRun = "analysis_run" // An on-demand AnalysisRun finished
+)
+
+// AIInsightEvent is journaled when AI analysis completes for a check
+type AIInsightEvent struct {
+	Check      string               `json:"check"`
+	Diagnosis  *ai.AnalysisResponse `json:"diagnosis,omitempty"`
+	Healing    *ai.AnalysisResponse `json:"healing,omitempty"`
+	AnalyzedAt time.Time            `json:"analyzed_at"`
+
+	// StateRevision is the cluster state revision when the analysis was
+	// stored; see Engine.StateRevision
+	StateRevision uint64 `json:"state_revision"`
+}
+
+// StreamEvent is a journaled check result, alert, AI insight or finished
+// analysis run with a resumable ID
+type StreamEvent struct {
+	ID        string      `json:"id"`
+	Seq       uint64      `json:"seq"`
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Journal keeps a bounded, ordered log of stream events so that consumers can
+// resume from the last event they saw. Event IDs are prefixed with an epoch
+// that changes on every restart, which lets stale resume tokens be detected.
+type Journal struct {
+	epoch       string
+	capacity    int
+	mu          sync.RWMutex
+	events      []StreamEvent
+	nextSeq     uint64
+	subscribers map[chan StreamEvent]struct{}
+}
+
+// NewJournal creates a journal retaining up to capacity events
+func NewJournal(capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &Journal{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		capacity:    capacity,
+		events:      make([]StreamEvent, 0, capacity),
+		nextSeq:     1,
+		subscribers: make(map[chan StreamEvent]struct{}),
+	}
+}
+
+// Append records an event and fans it out to live subscribers
+func (j *Journal) Append(eventType string, data interface{}) StreamEvent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	event := StreamEvent{
+		ID:        fmt.Sprintf("%s-%d", j.epoch, j.nextSeq),
+		Seq:       j.nextSeq,
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+	j.nextSeq++
+
+	j.events = append(j.events, event)
+	if len(j.events) > j.capacity {
+		j.events = j.events[len(j.events)-j.capacity:]
+	}
+
+	for ch := range j.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Slow consumer: disconnect it so it resumes from its last ID
+			delete(j.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return event
+}
+
+// Since returns the events recorded after the given resume token. The second
+// return value is false when the token cannot be honoured (unknown epoch or
+// events already evicted) and the consumer needs a full re-sync. An empty
+// token returns no events.
+func (j *Journal) Since(token string) ([]StreamEvent, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.sinceLocked(token)
+}
+
+// EventsSince returns the retained events of a type recorded at or after
+// the given time, oldest first
+func (j *Journal) EventsSince(eventType string, since time.Time) []StreamEvent {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	events := make([]StreamEvent, 0)
+	for _, event := range j.events {
+		if event.Type == eventType && !event.Timestamp.Before(since) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Subscribe atomically returns the events after token and a channel that
+// receives every event appended afterwards. The channel is closed when the
+// subscriber falls behind or cancel is called.
+func (j *Journal) Subscribe(token string, buffer int) (backlog []StreamEvent, complete bool, events <-chan StreamEvent, cancel func()) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	ch := make(chan StreamEvent, buffer)
+
+	j.mu.Lock()
+	backlog, complete = j.sinceLocked(token)
+	j.subscribers[ch] = struct{}{}
+	j.mu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			if _, exists := j.subscribers[ch]; exists {
+				delete(j.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+
+	return backlog, complete, ch, cancel
+}
+
+// sinceLocked implements Since; callers must hold j.mu
+func (j *Journal) sinceLocked(token string) ([]StreamEvent, bool) {
+	if token == "" {
+		return nil, true
+	}
+
+	epoch, seqStr, found := strings.Cut(token, "-")
+	if !found || epoch != j.epoch {
+		return nil, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil || seq >= j.nextSeq {
+		return nil, false
+	}
+
+	// The event after seq must still be retained
+	if len(j.events) > 0 && j.events[0].Seq > seq+1 {
+		return nil, false
+	}
+
+	backlog := make([]StreamEvent, 0)
+	for _, event := range j.events {
+		if event.Seq > seq {
+			backlog = append(backlog, event)
+		}
+	}
+	return backlog, true
+}
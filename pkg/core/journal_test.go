@@ -0,0 +1,96 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJournal_Since(t *testing.T) {
+	journal := NewJournal(3)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, journal.Append(StreamEventCheckResult, i).ID)
+	}
+
+	tests := []struct {
+		name         string
+		token        string
+		wantComplete bool
+		wantSeqs     []uint64
+	}{
+		{name: "empty token", token: "", wantComplete: true},
+		{name: "latest event", token: ids[4], wantComplete: true},
+		{name: "oldest resumable", token: ids[1], wantComplete: true, wantSeqs: []uint64{3, 4, 5}},
+		{name: "evicted", token: ids[0], wantComplete: false},
+		{name: "other epoch", token: "otherepoch-2", wantComplete: false},
+		{name: "future sequence", token: journal.epoch + "-99", wantComplete: false},
+		{name: "malformed", token: "garbage", wantComplete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, complete := journal.Since(tt.token)
+			if complete != tt.wantComplete {
+				t.Fatalf("expected complete=%v, got %v", tt.wantComplete, complete)
+			}
+			if len(events) != len(tt.wantSeqs) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantSeqs), len(events))
+			}
+			for i, seq := range tt.wantSeqs {
+				if events[i].Seq != seq {
+					t.Errorf("expected seq %d at %d, got %d", seq, i, events[i].Seq)
+				}
+			}
+		})
+	}
+}
+
+func TestJournal_Subscribe(t *testing.T) {
+	journal := NewJournal(10)
+	first := journal.Append(StreamEventAlert, "a")
+
+	backlog, complete, events, cancel := journal.Subscribe(first.ID, 1)
+	defer cancel()
+
+	if !complete || len(backlog) != 0 {
+		t.Fatalf("expected empty complete backlog, got %d events (complete=%v)", len(backlog), complete)
+	}
+
+	second := journal.Append(StreamEventCheckResult, "b")
+	select {
+	case event := <-events:
+		if event.ID != second.ID {
+			t.Errorf("expected event %s, got %s", second.ID, event.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected live event")
+	}
+
+	// Overflowing the buffer disconnects the subscriber
+	journal.Append(StreamEventCheckResult, "c")
+	journal.Append(StreamEventCheckResult, "d")
+	<-events
+	if _, ok := <-events; ok {
+		t.Error("expected slow subscriber channel to be closed")
+	}
+
+	// Cancelling after disconnection is safe
+	cancel()
+}
+
+func TestJournal_EventsSince(t *testing.T) {
+	journal := NewJournal(10)
+	journal.Append(StreamEventAIInsight, "old")
+	cutoff := time.Now()
+	journal.Append(StreamEventCheckResult, "result")
+	journal.Append(StreamEventAIInsight, "new")
+
+	events := journal.EventsSince(StreamEventAIInsight, cutoff)
+	if len(events) != 1 || events[0].Data != "new" {
+		t.Errorf("expected only the insight after the cutoff, got %+v", events)
+	}
+	if events := journal.EventsSince(StreamEventAIInsight, cutoff.Add(time.Hour)); len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+}
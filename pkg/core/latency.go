@@ -0,0 +1,377 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+)
+
+// DetailObservedAt is the detail holding when the condition a failing
+// result reports began in the cluster, taken from event and status
+// timestamps, so detection latency can be measured
+const DetailObservedAt = "observed_at"
+
+// Stages of the monitoring pipeline whose latency is tracked
+const (
+	LatencyStageDetection  = "detection"  // Condition began in the cluster → check result reporting it
+	LatencyStageEvaluation = "evaluation" // Check result → alert fired
+	LatencyStageDelivery   = "delivery"   // Alert fired → a channel accepted it
+	LatencyStageEndToEnd   = "end_to_end" // Condition began, or was detected when unknown → first delivery
+)
+
+// LatencyBudgetCheck is the name of the check result reporting whether
+// detection latency is within its budget
+const LatencyBudgetCheck = "pipeline-latency"
+
+const (
+	// maxLatencySamples bounds the samples kept per stage and channel; the
+	// oldest are dropped first
+	maxLatencySamples = 1000
+
+	// minBudgetSamples is how many detections are measured before the
+	// budget is judged
+	minBudgetSamples = 5
+
+	// DefaultLatencyPercentile is the percentile of detection latency held
+	// to the budget
+	DefaultLatencyPercentile = 90.0
+)
+
+// LatencyBudget bounds how long KubePulse may take to notice a condition
+type LatencyBudget struct {
+	Detection  time.Duration // Zero disables the budget
+	Percentile float64       // DefaultLatencyPercentile when zero
+}
+
+// LatencyStats summarizes the latency of a pipeline stage
+type LatencyStats struct {
+	Stage   string  `json:"stage"`
+	Channel string  `json:"channel,omitempty"` // Delivery stage only
+	Count   int     `json:"count"`
+	P50     float64 `json:"p50_seconds"`
+	P90     float64 `json:"p90_seconds"`
+	P99     float64 `json:"p99_seconds"`
+	Max     float64 `json:"max_seconds"`
+}
+
+// LatencyBudgetStatus reports detection latency against its budget
+type LatencyBudgetStatus struct {
+	Detection  float64 `json:"detection_seconds"`
+	Percentile float64 `json:"percentile"`
+	Observed   float64 `json:"observed_seconds"` // Detection latency at the percentile
+	Samples    int     `json:"samples"`
+	Exceeded   bool    `json:"exceeded"`
+}
+
+// LatencyReport is the latency of every pipeline stage measured so far
+type LatencyReport struct {
+	Stages []LatencyStats       `json:"stages"`
+	Budget *LatencyBudgetStatus `json:"budget,omitempty"`
+}
+
+// latencyKey identifies the samples of a stage, per channel for delivery
+type latencyKey struct {
+	stage   string
+	channel string
+}
+
+// pipelineLatency holds recent latency samples of each pipeline stage
+type pipelineLatency struct {
+	mu        sync.Mutex
+	budget    LatencyBudget
+	samples   map[latencyKey][]time.Duration
+	counts    map[latencyKey]int64            // Samples taken since start
+	delivered map[string]map[string]time.Time // Alert ID → channels that accepted it, and when
+}
+
+// SetObservedAt records the earliest of the given times as when a result's
+// condition began in the cluster; zero times are ignored
+func SetObservedAt(result *CheckResult, times ...time.Time) {
+	earliest, _ := ObservedAt(*result)
+	for _, at := range times {
+		if !at.IsZero() && (earliest.IsZero() || at.Before(earliest)) {
+			earliest = at
+		}
+	}
+	if earliest.IsZero() {
+		return
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details[DetailObservedAt] = earliest
+}
+
+// ObservedAt returns when a result's condition began in the cluster, if
+// the check reported it
+func ObservedAt(result CheckResult) (time.Time, bool) {
+	switch value := result.Details[DetailObservedAt].(type) {
+	case time.Time:
+		return value, !value.IsZero()
+	case string:
+		// Results read back from the history file
+		at, err := time.Parse(time.RFC3339Nano, value)
+		return at, err == nil
+	}
+	return time.Time{}, false
+}
+
+// observeLatency records a latency sample of a stage
+func (e *Engine) observeLatency(stage, channel string, latency time.Duration) {
+	if latency < 0 {
+		// Clock skew between the cluster and KubePulse
+		latency = 0
+	}
+	key := latencyKey{stage: stage, channel: channel}
+	e.latency.mu.Lock()
+	defer e.latency.mu.Unlock()
+	if e.latency.samples == nil {
+		e.latency.samples = make(map[latencyKey][]time.Duration)
+		e.latency.counts = make(map[latencyKey]int64)
+	}
+	samples := append(e.latency.samples[key], latency)
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	e.latency.samples[key] = samples
+	e.latency.counts[key]++
+}
+
+// observeDetection measures detection latency when a check starts failing
+// and reported when its condition began
+func (e *Engine) observeDetection(previous CheckResult, existed bool, result CheckResult) {
+	if !isAlerting(result.Status) || (existed && isAlerting(previous.Status)) {
+		return
+	}
+	if at, ok := ObservedAt(result); ok {
+		e.observeLatency(LatencyStageDetection, "", result.Timestamp.Sub(at))
+	}
+}
+
+// observeEvaluation measures how long a check result took to fire an alert
+func (e *Engine) observeEvaluation(alert alerts.Alert) {
+	if !alert.DetectedAt.IsZero() {
+		e.observeLatency(LatencyStageEvaluation, "", alert.Timestamp.Sub(alert.DetectedAt))
+	}
+}
+
+// observeDelivery measures how long an alert took to reach a channel, and
+// on its first delivery the whole pipeline. Escalation steps notifying a
+// channel again aren't measured; their delay is on purpose.
+func (e *Engine) observeDelivery(channel string, alert alerts.Alert, at time.Time) {
+	e.latency.mu.Lock()
+	if e.latency.delivered == nil {
+		e.latency.delivered = make(map[string]map[string]time.Time)
+	}
+	channels, seen := e.latency.delivered[alert.ID]
+	if !seen {
+		e.pruneDelivered(at)
+		channels = make(map[string]time.Time)
+		e.latency.delivered[alert.ID] = channels
+	}
+	_, repeated := channels[channel]
+	channels[channel] = at
+	e.latency.mu.Unlock()
+
+	if repeated {
+		return
+	}
+	e.observeLatency(LatencyStageDelivery, channel, at.Sub(alert.Timestamp))
+	if seen {
+		return
+	}
+	start := alert.DetectedAt
+	if !alert.ObservedAt.IsZero() {
+		start = alert.ObservedAt
+	}
+	if !start.IsZero() {
+		e.observeLatency(LatencyStageEndToEnd, "", at.Sub(start))
+	}
+}
+
+// pruneDelivered forgets alerts first delivered over a day ago; callers
+// hold e.latency.mu
+func (e *Engine) pruneDelivered(now time.Time) {
+	if len(e.latency.delivered) < maxLatencySamples {
+		return
+	}
+	for id, channels := range e.latency.delivered {
+		latest := time.Time{}
+		for _, at := range channels {
+			if at.After(latest) {
+				latest = at
+			}
+		}
+		if now.Sub(latest) > 24*time.Hour {
+			delete(e.latency.delivered, id)
+		}
+	}
+}
+
+// PipelineLatency returns the latency percentiles of each pipeline stage
+// and, when a budget is set, detection latency against it
+func (e *Engine) PipelineLatency() LatencyReport {
+	e.latency.mu.Lock()
+	defer e.latency.mu.Unlock()
+
+	report := LatencyReport{Stages: make([]LatencyStats, 0, len(e.latency.samples))}
+	for key, samples := range e.latency.samples {
+		report.Stages = append(report.Stages, LatencyStats{
+			Stage:   key.stage,
+			Channel: key.channel,
+			Count:   len(samples),
+			P50:     percentile(samples, 50).Seconds(),
+			P90:     percentile(samples, 90).Seconds(),
+			P99:     percentile(samples, 99).Seconds(),
+			Max:     percentile(samples, 100).Seconds(),
+		})
+	}
+	sort.Slice(report.Stages, func(i, j int) bool {
+		a, b := report.Stages[i], report.Stages[j]
+		if a.Stage != b.Stage {
+			return stageOrder(a.Stage) < stageOrder(b.Stage)
+		}
+		return a.Channel < b.Channel
+	})
+	if status, ok := e.budgetStatus(); ok {
+		report.Budget = &status
+	}
+	return report
+}
+
+// budgetStatus judges detection latency against the budget; callers hold
+// e.latency.mu
+func (e *Engine) budgetStatus() (LatencyBudgetStatus, bool) {
+	budget := e.latency.budget
+	if budget.Detection <= 0 {
+		return LatencyBudgetStatus{}, false
+	}
+	samples := e.latency.samples[latencyKey{stage: LatencyStageDetection}]
+	observed := percentile(samples, budget.Percentile)
+	return LatencyBudgetStatus{
+		Detection:  budget.Detection.Seconds(),
+		Percentile: budget.Percentile,
+		Observed:   observed.Seconds(),
+		Samples:    len(samples),
+		Exceeded:   len(samples) >= minBudgetSamples && observed > budget.Detection,
+	}, true
+}
+
+// checkLatencyBudget reports detection latency against the budget as the
+// pipeline-latency check, so regressions alert like any failing check
+func (e *Engine) checkLatencyBudget() {
+	e.latency.mu.Lock()
+	status, ok := e.budgetStatus()
+	e.latency.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	budget := time.Duration(status.Detection * float64(time.Second))
+	observed := time.Duration(status.Observed * float64(time.Second)).Round(time.Second)
+	result := CheckResult{
+		Name:      LatencyBudgetCheck,
+		Status:    HealthStatusHealthy,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			"budget":     budget.String(),
+			"percentile": status.Percentile,
+			"observed":   observed.String(),
+			"samples":    status.Samples,
+		},
+		Metrics: []Metric{{
+			Name:      "kubepulse_detection_latency_budget_exceeded",
+			Value:     boolValue(status.Exceeded),
+			Timestamp: time.Now(),
+			Type:      MetricTypeGauge,
+		}},
+		Confidence: 1,
+	}
+	switch {
+	case status.Exceeded:
+		result.Status = HealthStatusDegraded
+		result.Message = fmt.Sprintf("p%g detection latency %s exceeds the %s budget", status.Percentile, observed, budget)
+	case status.Samples < minBudgetSamples:
+		result.Message = fmt.Sprintf("%d of %d detections measured before judging the %s budget", status.Samples, minBudgetSamples, budget)
+	default:
+		result.Message = fmt.Sprintf("p%g detection latency %s is within the %s budget", status.Percentile, observed, budget)
+	}
+	e.storeResult(result)
+	e.processResult(result)
+}
+
+// GetLatencyMetrics exports the latency percentiles of each pipeline stage
+// for Prometheus
+func (e *Engine) GetLatencyMetrics() []Metric {
+	e.latency.mu.Lock()
+	defer e.latency.mu.Unlock()
+
+	now := time.Now()
+	metrics := make([]Metric, 0, len(e.latency.samples)*4)
+	for key, samples := range e.latency.samples {
+		labels := func(extra ...string) map[string]string {
+			labels := map[string]string{"stage": key.stage}
+			if key.channel != "" {
+				labels["channel"] = key.channel
+			}
+			for i := 0; i+1 < len(extra); i += 2 {
+				labels[extra[i]] = extra[i+1]
+			}
+			return labels
+		}
+		for _, q := range []float64{50, 90, 99} {
+			metrics = append(metrics, Metric{
+				Name:      "kubepulse_pipeline_latency_seconds",
+				Value:     percentile(samples, q).Seconds(),
+				Unit:      "seconds",
+				Labels:    labels("quantile", fmt.Sprintf("%g", q/100)),
+				Timestamp: now,
+				Type:      MetricTypeGauge,
+			})
+		}
+		metrics = append(metrics, Metric{
+			Name:      "kubepulse_pipeline_latency_samples_total",
+			Value:     float64(e.latency.counts[key]),
+			Labels:    labels(),
+			Timestamp: now,
+			Type:      MetricTypeCounter,
+		})
+	}
+	return metrics
+}
+
+// percentile returns the nearest-rank percentile of samples, 0 when empty
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// stageOrder orders stages as they happen in the pipeline
+func stageOrder(stage string) int {
+	switch stage {
+	case LatencyStageDetection:
+		return 0
+	case LatencyStageEvaluation:
+		return 1
+	case LatencyStageDelivery:
+		return 2
+	}
+	return 3
+}
+
+// boolValue converts a flag to a gauge value
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
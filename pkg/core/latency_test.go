@@ -0,0 +1,61 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_PipelineLatency(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), LatencyBudget: LatencyBudget{Detection: time.Minute}})
+
+	// A condition noticed two minutes after it began, alerted and delivered
+	now := time.Now()
+	failing := CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: now}
+	SetObservedAt(&failing, now.Add(-time.Minute), now.Add(-2*time.Minute), time.Time{})
+	engine.storeResult(failing)
+	engine.processResult(failing)
+
+	report := engine.PipelineLatency()
+	if len(report.Stages) != 4 {
+		t.Fatalf("expected all four stages measured, got %+v", report.Stages)
+	}
+	if detection := report.Stages[0]; detection.Stage != LatencyStageDetection || detection.P50 != 120 {
+		t.Errorf("expected 120s detection latency, got %+v", detection)
+	}
+	if delivery := report.Stages[2]; delivery.Stage != LatencyStageDelivery || delivery.Channel != "log" {
+		t.Errorf("expected delivery to the log channel measured, got %+v", delivery)
+	}
+	if endToEnd := report.Stages[3]; endToEnd.Stage != LatencyStageEndToEnd || endToEnd.P50 < 120 {
+		t.Errorf("expected end-to-end latency from when the condition began, got %+v", endToEnd)
+	}
+
+	// Still failing isn't a new detection
+	engine.storeResult(failing)
+	if report := engine.PipelineLatency(); report.Stages[0].Count != 1 || report.Budget.Exceeded {
+		t.Fatalf("expected one detection and the budget not yet judged, got %+v", report)
+	}
+
+	for i := 0; i < minBudgetSamples; i++ {
+		engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: now})
+		engine.storeResult(failing)
+	}
+	engine.checkLatencyBudget()
+	budget, ok := engine.GetResult(LatencyBudgetCheck)
+	if !ok || budget.Status != HealthStatusDegraded {
+		t.Fatalf("expected the pipeline-latency check degraded, got %+v", budget)
+	}
+	fired := false
+	for _, alert := range engine.alertManager.GetHistory(10) {
+		fired = fired || alert.Name == "detection-latency-budget"
+	}
+	if !fired {
+		t.Error("expected the latency budget alert fired")
+	}
+
+	metrics := engine.GetLatencyMetrics()
+	if len(metrics) != 16 {
+		t.Errorf("expected 3 quantiles and a count for each of 4 stages, got %d", len(metrics))
+	}
+}
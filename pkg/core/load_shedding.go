@@ -0,0 +1,102 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultLoadSheddingFactor is how many intervals apart checks run while
+// load is shed
+const DefaultLoadSheddingFactor = 3
+
+// loadSheddingRetentionDivisor cuts health history retention and metric
+// history while load is shed
+const loadSheddingRetentionDivisor = 4
+
+// loadShedding degrades the engine while KubePulse nears its memory or CPU
+// limits: checks run less often, failures aren't queued for AI analysis and
+// less history is kept
+type loadShedding struct {
+	mu      sync.Mutex
+	active  bool
+	factor  int
+	skipped int // Cycles skipped since checks last ran
+}
+
+// SetLoadShedding starts or stops shedding load. Starting cuts the health
+// history retention and metric history to a quarter right away; stopping
+// lets them grow back.
+func (e *Engine) SetLoadShedding(active bool) {
+	e.shedding.mu.Lock()
+	changed := e.shedding.active != active
+	e.shedding.active = active
+	e.shedding.skipped = 0
+	e.shedding.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	e.history.shedRetention(active)
+	if active {
+		limit := e.metricHistoryLimit()
+		e.historyMu.Lock()
+		for key, series := range e.metricHistory {
+			if len(series) > limit {
+				e.metricHistory[key] = append([]Metric(nil), series[len(series)-limit:]...)
+			}
+		}
+		e.historyMu.Unlock()
+		klog.Warningf("Load shedding: running checks every %s, pausing AI analysis of failures and keeping less history",
+			time.Duration(e.shedding.factor)*e.interval)
+		return
+	}
+	klog.Info("Load shedding stopped: checks, AI analysis and history are back to normal")
+}
+
+// LoadShedding reports whether load is being shed
+func (e *Engine) LoadShedding() bool {
+	e.shedding.mu.Lock()
+	defer e.shedding.mu.Unlock()
+	return e.shedding.active
+}
+
+// skipShedCycle reports whether a check cycle should be skipped so checks
+// only run every factor cycles while load is shed
+func (e *Engine) skipShedCycle() bool {
+	e.shedding.mu.Lock()
+	defer e.shedding.mu.Unlock()
+	if !e.shedding.active {
+		return false
+	}
+	e.shedding.skipped++
+	if e.shedding.skipped < e.shedding.factor {
+		return true
+	}
+	e.shedding.skipped = 0
+	return false
+}
+
+// metricHistoryLimit is the data points kept per metric series
+func (e *Engine) metricHistoryLimit() int {
+	if e.LoadShedding() {
+		return max(e.maxHistory/loadSheddingRetentionDivisor, 1)
+	}
+	return e.maxHistory
+}
+
+// loadSheddingMetrics reports whether load is being shed
+func (e *Engine) loadSheddingMetrics() []Metric {
+	value := 0.0
+	if e.LoadShedding() {
+		value = 1
+	}
+	return []Metric{{
+		Name:      "kubepulse_load_shedding",
+		Value:     value,
+		Unit:      "state",
+		Timestamp: time.Now(),
+		Type:      MetricTypeGauge,
+	}}
+}
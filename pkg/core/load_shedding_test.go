@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_LoadShedding(t *testing.T) {
+	history, _ := NewResultHistory("", 4*time.Hour)
+	engine := NewEngine(EngineConfig{
+		KubeClient:         fake.NewSimpleClientset(),
+		MaxHistory:         8,
+		History:            history,
+		LoadSheddingFactor: 3,
+	})
+	engine.aiQueue = NewAIQueue(nil)
+	check := &countingCheck{mockHealthCheck: mockHealthCheck{name: "pod-health"}}
+	engine.AddCheck(check)
+
+	now := time.Now()
+	history.Record(CheckResult{Name: "node-health", Status: HealthStatusUnhealthy, Timestamp: now.Add(-3 * time.Hour)})
+	history.Record(CheckResult{Name: "node-health", Status: HealthStatusHealthy, Timestamp: now.Add(-2 * time.Hour)})
+	history.Record(CheckResult{Name: "node-health", Status: HealthStatusDegraded, Timestamp: now.Add(-time.Minute)})
+	for i := 0; i < 8; i++ {
+		engine.recordMetrics([]Metric{{Name: "pod_restarts", Value: float64(i), Timestamp: now}})
+	}
+
+	engine.SetLoadShedding(true)
+	if !engine.LoadShedding() {
+		t.Fatal("expected load to be shed")
+	}
+
+	// Checks run every third cycle
+	for i := 0; i < 6; i++ {
+		engine.runChecks()
+	}
+	if runs := check.runs.Load(); runs != 2 {
+		t.Errorf("expected 2 runs in 6 cycles, got %d", runs)
+	}
+
+	// Failures aren't queued for AI analysis
+	engine.processResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: now})
+	if depth := engine.aiQueue.Stats().Depth[AlertSeverityCritical]; depth != 0 {
+		t.Errorf("expected AI analysis paused, got %d queued", depth)
+	}
+
+	// History is cut to a quarter
+	if series := engine.GetMetricHistory("pod_restarts")["pod_restarts"]; len(series) != 2 || series[1].Value != 7 {
+		t.Errorf("expected the last 2 data points kept, got %+v", series)
+	}
+	if _, _, err := history.At(now.Add(-90 * time.Minute)); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("expected history older than an hour dropped, got %v", err)
+	}
+	if gauge := engine.loadSheddingMetrics()[0]; gauge.Name != "kubepulse_load_shedding" || gauge.Value != 1 {
+		t.Errorf("expected the shedding gauge set, got %+v", gauge)
+	}
+
+	engine.SetLoadShedding(false)
+	for i := 0; i < 2; i++ {
+		engine.runChecks()
+	}
+	if runs := check.runs.Load(); runs != 4 {
+		t.Errorf("expected every cycle to run once shedding stops, got %d runs", runs)
+	}
+	engine.processResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: now})
+	if depth := engine.aiQueue.Stats().Depth[AlertSeverityCritical]; depth != 1 {
+		t.Errorf("expected AI analysis resumed, got %d queued", depth)
+	}
+	if _, _, err := history.At(now.Add(-90 * time.Minute)); err != nil {
+		t.Errorf("expected the full retention window back, got %v", err)
+	}
+}
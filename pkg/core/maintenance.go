@@ -0,0 +1,200 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DetailMaintenance holds the CheckMaintenance of a result whose check is
+// under maintenance
+const DetailMaintenance = "maintenance"
+
+// ErrMaintenanceNotFound is returned when ending maintenance on a check that
+// isn't under maintenance
+var ErrMaintenanceNotFound = errors.New("check is not under maintenance")
+
+// CheckMaintenance marks a check as under maintenance until a time. While it
+// is active, the check's failures don't count against the health score or
+// fire alerts, but its results are still reported.
+type CheckMaintenance struct {
+	Check   string    `json:"check"`
+	Reason  string    `json:"reason"`
+	Owner   string    `json:"owner"`
+	Started time.Time `json:"started"`
+	Until   time.Time `json:"until"`
+}
+
+// checkMaintenance holds the maintenance windows of checks by name
+type checkMaintenance struct {
+	mu      sync.Mutex
+	windows map[string]CheckMaintenance
+}
+
+// SetCheckMaintenance puts a check under maintenance, replacing any window
+// it already has
+func (e *Engine) SetCheckMaintenance(window CheckMaintenance) (CheckMaintenance, error) {
+	now := time.Now()
+	window.Reason = strings.TrimSpace(window.Reason)
+	window.Owner = strings.TrimSpace(window.Owner)
+	switch {
+	case window.Reason == "":
+		return CheckMaintenance{}, fmt.Errorf("maintenance needs a reason")
+	case window.Owner == "":
+		return CheckMaintenance{}, fmt.Errorf("maintenance needs an owner")
+	case !window.Until.After(now):
+		return CheckMaintenance{}, fmt.Errorf("maintenance must end in the future")
+	}
+	if !e.knownCheck(window.Check) {
+		return CheckMaintenance{}, fmt.Errorf("%w: %s", ErrCheckNotFound, window.Check)
+	}
+	window.Started = now
+
+	e.maintenance.mu.Lock()
+	if e.maintenance.windows == nil {
+		e.maintenance.windows = make(map[string]CheckMaintenance)
+	}
+	e.maintenance.windows[window.Check] = window
+	e.maintenance.mu.Unlock()
+
+	e.changes.Record(Change{
+		Timestamp: now,
+		Kind:      ChangeKindMaintenance,
+		Resource:  "check/" + window.Check,
+		Message:   fmt.Sprintf("%s under maintenance by %s until %s: %s", window.Check, window.Owner, window.Until.UTC().Format(time.RFC3339), window.Reason),
+		To:        "maintenance",
+	})
+	klog.Infof("Check %s under maintenance by %s until %s", window.Check, window.Owner, window.Until.Format(time.RFC3339))
+	return window, nil
+}
+
+// EndCheckMaintenance ends a check's maintenance before it expires
+func (e *Engine) EndCheckMaintenance(name string) (CheckMaintenance, error) {
+	e.maintenance.mu.Lock()
+	window, ok := e.maintenance.windows[name]
+	delete(e.maintenance.windows, name)
+	e.maintenance.mu.Unlock()
+	if !ok || !window.Until.After(time.Now()) {
+		return CheckMaintenance{}, fmt.Errorf("%w: %s", ErrMaintenanceNotFound, name)
+	}
+	e.recordMaintenanceEnd(window, "ended early")
+	return window, nil
+}
+
+// GetCheckMaintenance lists the active maintenance windows, soonest to end
+// first
+func (e *Engine) GetCheckMaintenance() []CheckMaintenance {
+	now := time.Now()
+	e.maintenance.mu.Lock()
+	windows := make([]CheckMaintenance, 0, len(e.maintenance.windows))
+	for _, window := range e.maintenance.windows {
+		if window.Until.After(now) {
+			windows = append(windows, window)
+		}
+	}
+	e.maintenance.mu.Unlock()
+
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].Until.Equal(windows[j].Until) {
+			return windows[i].Until.Before(windows[j].Until)
+		}
+		return windows[i].Check < windows[j].Check
+	})
+	return windows
+}
+
+// activeMaintenance returns the maintenance window a check is under at now,
+// removing it once it has expired so the check is treated normally again
+func (e *Engine) activeMaintenance(name string, now time.Time) (CheckMaintenance, bool) {
+	e.maintenance.mu.Lock()
+	window, ok := e.maintenance.windows[name]
+	expired := ok && !window.Until.After(now)
+	if expired {
+		delete(e.maintenance.windows, name)
+	}
+	e.maintenance.mu.Unlock()
+
+	if expired {
+		e.recordMaintenanceEnd(window, "expired")
+		return CheckMaintenance{}, false
+	}
+	return window, ok
+}
+
+// recordMaintenanceEnd records a check leaving maintenance
+func (e *Engine) recordMaintenanceEnd(window CheckMaintenance, how string) {
+	e.changes.Record(Change{
+		Timestamp: time.Now(),
+		Kind:      ChangeKindMaintenance,
+		Resource:  "check/" + window.Check,
+		Message:   fmt.Sprintf("Maintenance of %s %s", window.Check, how),
+		From:      "maintenance",
+	})
+	klog.Infof("Maintenance of check %s %s", window.Check, how)
+}
+
+// withMaintenance returns the result marked with its check's active
+// maintenance window, or with a stale mark removed. Details are copied, so
+// the stored result is not modified.
+func (e *Engine) withMaintenance(result CheckResult, now time.Time) CheckResult {
+	window, active := e.activeMaintenance(result.Name, now)
+	if _, marked := result.Details[DetailMaintenance]; !active && !marked {
+		return result
+	}
+
+	details := make(map[string]interface{}, len(result.Details)+1)
+	for key, value := range result.Details {
+		details[key] = value
+	}
+	if active {
+		details[DetailMaintenance] = window
+	} else {
+		delete(details, DetailMaintenance)
+	}
+	result.Details = details
+	return result
+}
+
+// knownCheck reports whether a check is registered or has reported a result
+func (e *Engine) knownCheck(name string) bool {
+	for _, check := range e.checks {
+		if check.Name() == name {
+			return true
+		}
+	}
+	_, ok := e.GetResult(name)
+	return ok
+}
+
+// MaintenanceOf returns the maintenance window a result was marked with,
+// including results decoded from JSON
+func MaintenanceOf(result CheckResult) (CheckMaintenance, bool) {
+	switch window := result.Details[DetailMaintenance].(type) {
+	case CheckMaintenance:
+		return window, true
+	case map[string]interface{}:
+		data, err := json.Marshal(window)
+		if err != nil {
+			return CheckMaintenance{}, false
+		}
+		var decoded CheckMaintenance
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return CheckMaintenance{}, false
+		}
+		return decoded, true
+	}
+	return CheckMaintenance{}, false
+}
+
+// InMaintenance reports whether a result was produced while its check was
+// under maintenance
+func InMaintenance(result CheckResult) bool {
+	_, ok := MaintenanceOf(result)
+	return ok
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_SetCheckMaintenanceValidation(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	until := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		window  CheckMaintenance
+		wantErr error
+	}{
+		{"valid", CheckMaintenance{Check: "pod-health", Reason: "node upgrade", Owner: "platform", Until: until}, nil},
+		{"unknown check", CheckMaintenance{Check: "dns-health", Reason: "node upgrade", Owner: "platform", Until: until}, ErrCheckNotFound},
+		{"no reason", CheckMaintenance{Check: "pod-health", Owner: "platform", Until: until}, errors.New("maintenance needs a reason")},
+		{"no owner", CheckMaintenance{Check: "pod-health", Reason: "node upgrade", Until: until}, errors.New("maintenance needs an owner")},
+		{"in the past", CheckMaintenance{Check: "pod-health", Reason: "node upgrade", Owner: "platform", Until: time.Now().Add(-time.Minute)},
+			errors.New("maintenance must end in the future")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := engine.SetCheckMaintenance(tt.window)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr == nil && window.Started.IsZero():
+				t.Errorf("expected the start time set, got %+v", window)
+			case tt.wantErr != nil && (err == nil || (!errors.Is(err, tt.wantErr) && err.Error() != tt.wantErr.Error())):
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEngine_CheckMaintenance(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusHealthy})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+
+	if _, err := engine.SetCheckMaintenance(CheckMaintenance{Check: "pod-health", Reason: "node upgrade", Owner: "platform",
+		Until: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failing := engine.withMaintenance(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "3 pods pending"}, time.Now())
+	engine.storeResult(failing)
+
+	health := engine.GetClusterHealth("test")
+	if health.Status != HealthStatusHealthy || health.Score.Weighted != 100 {
+		t.Errorf("expected maintenance failures not to count, got %s with score %.1f", health.Status, health.Score.Weighted)
+	}
+	result, _ := engine.GetResult("pod-health")
+	if window, ok := MaintenanceOf(result); result.Status != HealthStatusUnhealthy || !ok || window.Owner != "platform" {
+		t.Errorf("expected the failure reported with its maintenance, got %+v", result)
+	}
+	for _, change := range engine.changes.Since(time.Time{}) {
+		if change.Kind == ChangeKindAlertFiring {
+			t.Errorf("expected no alert for a check under maintenance, got %+v", change)
+		}
+	}
+
+	// Expiry restores normal scoring without waiting for the next run
+	engine.maintenance.mu.Lock()
+	window := engine.maintenance.windows["pod-health"]
+	window.Until = time.Now().Add(-time.Second)
+	engine.maintenance.windows["pod-health"] = window
+	engine.maintenance.mu.Unlock()
+
+	health = engine.GetClusterHealth("test")
+	if health.Status != HealthStatusDegraded || health.Score.Weighted == 100 {
+		t.Errorf("expected the failure to count after expiry, got %s with score %.1f", health.Status, health.Score.Weighted)
+	}
+	if result, _ := engine.GetResult("pod-health"); InMaintenance(result) {
+		t.Errorf("expected the maintenance mark removed after expiry, got %+v", result.Details)
+	}
+	if windows := engine.GetCheckMaintenance(); len(windows) != 0 {
+		t.Errorf("expected no active maintenance, got %+v", windows)
+	}
+	changes := engine.changes.Since(time.Time{})
+	if last := changes[len(changes)-1]; last.Kind != ChangeKindMaintenance || last.Message != "Maintenance of pod-health expired" {
+		t.Errorf("expected the expiry recorded, got %+v", last)
+	}
+
+	if _, err := engine.EndCheckMaintenance("pod-health"); !errors.Is(err, ErrMaintenanceNotFound) {
+		t.Errorf("expected ErrMaintenanceNotFound, got %v", err)
+	}
+}
+
+func TestMaintenanceOf_DecodedJSON(t *testing.T) {
+	result := CheckResult{Details: map[string]interface{}{DetailMaintenance: map[string]interface{}{
+		"check": "pod-health", "reason": "node upgrade", "owner": "platform", "until": "2026-01-02T15:04:05Z",
+	}}}
+	window, ok := MaintenanceOf(result)
+	if !ok || window.Reason != "node upgrade" || window.Until.IsZero() {
+		t.Errorf("expected the decoded window, got %+v", window)
+	}
+	if InMaintenance(CheckResult{}) {
+		t.Error("expected a result without the detail not to be in maintenance")
+	}
+}
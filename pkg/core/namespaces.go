@@ -0,0 +1,397 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// namespaceResync is how often the namespace informers replay their cache,
+// correcting any drift in the rollups
+const namespaceResync = 30 * time.Minute
+
+// lowReadiness is the pod readiness percentage below which a namespace is
+// unhealthy rather than degraded
+const lowReadiness = 50
+
+// NamespaceHealth rolls up the health of a namespace for heatmaps
+type NamespaceHealth struct {
+	Namespace      string         `json:"namespace"`
+	Status         HealthStatus   `json:"status"`
+	Pods           int            `json:"pods"` // Pods not yet succeeded
+	ReadyPods      int            `json:"ready_pods"`
+	Readiness      float64        `json:"readiness"` // Percent of pods ready, 100 without pods
+	Restarts       int            `json:"restarts"`  // Container restarts of current pods
+	PendingPods    int            `json:"pending_pods"`
+	FailedPods     int            `json:"failed_pods"`
+	PVCs           int            `json:"pvcs"`
+	PVCIssues      int            `json:"pvc_issues"` // Claims pending or lost
+	Alerts         int            `json:"alerts"`     // Firing alerts of checks implicating the namespace
+	CriticalAlerts int            `json:"critical_alerts"`
+	SLOs           []NamespaceSLO `json:"slos,omitempty"` // SLOs whose labels select the namespace
+}
+
+// NamespaceHealthReport is the health of every namespace, worst first
+type NamespaceHealthReport struct {
+	Synced     bool              `json:"synced"` // Whether the pod and claim caches have listed everything once
+	Namespaces []NamespaceHealth `json:"namespaces"`
+}
+
+// NamespaceSLO is the state of an SLO of a namespace
+type NamespaceSLO struct {
+	Name        string  `json:"name"`
+	Violated    bool    `json:"violated"`
+	ErrorBudget float64 `json:"error_budget"`
+}
+
+// podSummary is what the rollups count of a pod
+type podSummary struct {
+	namespace string
+	counted   bool // Not succeeded
+	ready     bool
+	pending   bool
+	failed    bool
+	restarts  int
+}
+
+// pvcSummary is what the rollups count of a claim
+type pvcSummary struct {
+	namespace string
+	issue     bool
+}
+
+// namespaceCounts are the running totals of a namespace
+type namespaceCounts struct {
+	objects                                int // Pods of any phase and claims
+	pods, ready, pending, failed, restarts int
+	pvcs, pvcIssues                        int
+}
+
+// namespaceTracker keeps per-namespace totals up to date from pod and
+// claim events, so rollups cost nothing to read
+type namespaceTracker struct {
+	mu     sync.RWMutex
+	pods   map[string]podSummary
+	pvcs   map[string]pvcSummary
+	totals map[string]*namespaceCounts
+	synced func() bool // Whether the informers have listed everything once
+}
+
+func newNamespaceTracker() *namespaceTracker {
+	return &namespaceTracker{
+		pods:   make(map[string]podSummary),
+		pvcs:   make(map[string]pvcSummary),
+		totals: make(map[string]*namespaceCounts),
+	}
+}
+
+// counts returns the totals of a namespace, creating them; callers hold mu
+func (t *namespaceTracker) counts(namespace string) *namespaceCounts {
+	counts, ok := t.totals[namespace]
+	if !ok {
+		counts = &namespaceCounts{}
+		t.totals[namespace] = counts
+	}
+	return counts
+}
+
+// prune drops a namespace whose last pod and claim are gone; callers hold mu
+func (t *namespaceTracker) prune(namespace string) {
+	if counts, ok := t.totals[namespace]; ok && *counts == (namespaceCounts{}) {
+		delete(t.totals, namespace)
+	}
+}
+
+// addPod counts a pod, sign 1, or uncounts it, sign -1; callers hold mu
+func (t *namespaceTracker) addPod(pod podSummary, sign int) {
+	counts := t.counts(pod.namespace)
+	counts.objects += sign
+	if pod.counted {
+		counts.pods += sign
+		counts.restarts += sign * pod.restarts
+	}
+	if pod.ready {
+		counts.ready += sign
+	}
+	if pod.pending {
+		counts.pending += sign
+	}
+	if pod.failed {
+		counts.failed += sign
+	}
+	t.prune(pod.namespace)
+}
+
+// setPod replaces what is counted of a pod
+func (t *namespaceTracker) setPod(pod *corev1.Pod) {
+	key := pod.Namespace + "/" + pod.Name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.pods[key]; ok {
+		t.addPod(old, -1)
+	}
+	summary := summarizePod(pod)
+	t.pods[key] = summary
+	t.addPod(summary, 1)
+}
+
+// deletePod stops counting a pod
+func (t *namespaceTracker) deletePod(namespace, name string) {
+	key := namespace + "/" + name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.pods[key]; ok {
+		t.addPod(old, -1)
+		delete(t.pods, key)
+	}
+}
+
+// addPVC counts a claim, sign 1, or uncounts it, sign -1; callers hold mu
+func (t *namespaceTracker) addPVC(pvc pvcSummary, sign int) {
+	counts := t.counts(pvc.namespace)
+	counts.objects += sign
+	counts.pvcs += sign
+	if pvc.issue {
+		counts.pvcIssues += sign
+	}
+	t.prune(pvc.namespace)
+}
+
+// setPVC replaces what is counted of a claim
+func (t *namespaceTracker) setPVC(pvc *corev1.PersistentVolumeClaim) {
+	key := pvc.Namespace + "/" + pvc.Name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.pvcs[key]; ok {
+		t.addPVC(old, -1)
+	}
+	summary := pvcSummary{namespace: pvc.Namespace, issue: pvc.Status.Phase != corev1.ClaimBound}
+	t.pvcs[key] = summary
+	t.addPVC(summary, 1)
+}
+
+// deletePVC stops counting a claim
+func (t *namespaceTracker) deletePVC(namespace, name string) {
+	key := namespace + "/" + name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.pvcs[key]; ok {
+		t.addPVC(old, -1)
+		delete(t.pvcs, key)
+	}
+}
+
+// summarizePod reduces a pod to what the rollups count
+func summarizePod(pod *corev1.Pod) podSummary {
+	summary := podSummary{
+		namespace: pod.Namespace,
+		counted:   pod.Status.Phase != corev1.PodSucceeded,
+		pending:   pod.Status.Phase == corev1.PodPending,
+		failed:    pod.Status.Phase == corev1.PodFailed,
+	}
+	if !summary.counted {
+		return summary
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			summary.ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		summary.restarts += int(status.RestartCount)
+	}
+	return summary
+}
+
+// slimPod keeps only what summarizePod reads, so the informer cache holds
+// little of each pod
+func slimPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	slim := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			UID:             pod.UID,
+			ResourceVersion: pod.ResourceVersion,
+		},
+		Status: corev1.PodStatus{Phase: pod.Status.Phase, Conditions: pod.Status.Conditions},
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		slim.Status.ContainerStatuses = append(slim.Status.ContainerStatuses, corev1.ContainerStatus{Name: status.Name, RestartCount: status.RestartCount})
+	}
+	return slim, nil
+}
+
+// eventHandler applies informer events to the tracker
+func (t *namespaceTracker) eventHandler(set func(obj interface{}), remove func(namespace, name string)) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    set,
+		UpdateFunc: func(_, obj interface{}) { set(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if meta, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+				namespace, name, _ := cache.SplitMetaNamespaceKey(meta)
+				remove(namespace, name)
+			}
+		},
+	}
+}
+
+// runNamespaceTracker watches pods and claims until the engine stops
+func (e *Engine) runNamespaceTracker() {
+	factory := informers.NewSharedInformerFactory(e.client, namespaceResync)
+	pods := factory.Core().V1().Pods().Informer()
+	if err := pods.SetTransform(slimPod); err != nil {
+		klog.Warningf("Failed to slim cached pods: %v", err)
+	}
+	pvcs := factory.Core().V1().PersistentVolumeClaims().Informer()
+
+	tracker := e.namespaces
+	if _, err := pods.AddEventHandler(tracker.eventHandler(func(obj interface{}) {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			tracker.setPod(pod)
+		}
+	}, tracker.deletePod)); err != nil {
+		klog.Errorf("Namespace health disabled: %v", err)
+		return
+	}
+	if _, err := pvcs.AddEventHandler(tracker.eventHandler(func(obj interface{}) {
+		if pvc, ok := obj.(*corev1.PersistentVolumeClaim); ok {
+			tracker.setPVC(pvc)
+		}
+	}, tracker.deletePVC)); err != nil {
+		klog.Errorf("Namespace health disabled: %v", err)
+		return
+	}
+
+	tracker.mu.Lock()
+	tracker.synced = func() bool { return pods.HasSynced() && pvcs.HasSynced() }
+	tracker.mu.Unlock()
+	factory.Start(e.ctx.Done())
+	<-e.ctx.Done()
+	factory.Shutdown()
+}
+
+// NamespaceHealth rolls up the health of each namespace with pods, claims,
+// alerts or SLOs
+func (e *Engine) NamespaceHealth() NamespaceHealthReport {
+	tracker := e.namespaces
+	tracker.mu.RLock()
+	synced := tracker.synced != nil && tracker.synced()
+	rollups := make(map[string]*NamespaceHealth, len(tracker.totals))
+	for namespace, counts := range tracker.totals {
+		rollups[namespace] = &NamespaceHealth{
+			Namespace:   namespace,
+			Pods:        counts.pods,
+			ReadyPods:   counts.ready,
+			Restarts:    counts.restarts,
+			PendingPods: counts.pending,
+			FailedPods:  counts.failed,
+			PVCs:        counts.pvcs,
+			PVCIssues:   counts.pvcIssues,
+		}
+	}
+	tracker.mu.RUnlock()
+
+	rollup := func(namespace string) *NamespaceHealth {
+		if _, ok := rollups[namespace]; !ok {
+			rollups[namespace] = &NamespaceHealth{Namespace: namespace}
+		}
+		return rollups[namespace]
+	}
+	for _, alert := range e.firingAlerts() {
+		for namespace := range e.checkNamespaces(alert.Labels["check"]) {
+			health := rollup(namespace)
+			health.Alerts++
+			if alert.Severity == alerts.AlertSeverityCritical {
+				health.CriticalAlerts++
+			}
+		}
+	}
+	for name, status := range e.sloTracker.GetAllSLOs() {
+		if namespace := status.SLO.Labels["namespace"]; namespace != "" {
+			health := rollup(namespace)
+			health.SLOs = append(health.SLOs, NamespaceSLO{Name: name, Violated: status.IsViolated, ErrorBudget: status.ErrorBudget})
+		}
+	}
+
+	namespaces := make([]NamespaceHealth, 0, len(rollups))
+	for _, health := range rollups {
+		health.Readiness = 100
+		if health.Pods > 0 {
+			health.Readiness = float64(health.ReadyPods) / float64(health.Pods) * 100
+		}
+		sort.Slice(health.SLOs, func(i, j int) bool { return health.SLOs[i].Name < health.SLOs[j].Name })
+		health.Status = health.status()
+		namespaces = append(namespaces, *health)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		a, b := namespaces[i], namespaces[j]
+		if statusRank(a.Status) != statusRank(b.Status) {
+			return statusRank(a.Status) > statusRank(b.Status)
+		}
+		return a.Namespace < b.Namespace
+	})
+	return NamespaceHealthReport{Synced: synced, Namespaces: namespaces}
+}
+
+// status grades a namespace: unhealthy with critical alerts, violated SLOs
+// or most pods unready; degraded with any unready, pending or failed pod,
+// claim issue or alert
+func (h NamespaceHealth) status() HealthStatus {
+	violated := false
+	for _, slo := range h.SLOs {
+		violated = violated || slo.Violated
+	}
+	switch {
+	case h.CriticalAlerts > 0 || violated || h.Readiness < lowReadiness:
+		return HealthStatusUnhealthy
+	case h.ReadyPods < h.Pods || h.PendingPods > 0 || h.FailedPods > 0 || h.PVCIssues > 0 || h.Alerts > 0:
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
+
+// firingAlerts returns the alerts still firing or acknowledged, the latest
+// of each fingerprint
+func (e *Engine) firingAlerts() []alerts.Alert {
+	latest := make(map[string]alerts.Alert)
+	for _, alert := range e.alertManager.GetHistory(0) {
+		latest[alert.Fingerprint] = alert
+	}
+	firing := make([]alerts.Alert, 0, len(latest))
+	for _, alert := range latest {
+		if alert.ResolvedAt == nil && (alert.Status == alerts.AlertStatusFiring || alert.Status == alerts.AlertStatusAcknowledged) {
+			firing = append(firing, alert)
+		}
+	}
+	return firing
+}
+
+// checkNamespaces returns the namespaces of the resources a check's latest
+// result implicates
+func (e *Engine) checkNamespaces(check string) map[string]bool {
+	namespaces := make(map[string]bool)
+	result, ok := e.GetResult(check)
+	if !ok {
+		return namespaces
+	}
+	for _, ref := range ImplicatedResources(result) {
+		if ref.Namespace != "" {
+			namespaces[ref.Namespace] = true
+		}
+	}
+	return namespaces
+}
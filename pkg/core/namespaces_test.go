@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testPod(namespace, name string, phase corev1.PodPhase, ready bool, restarts int32) *corev1.Pod {
+	condition := corev1.ConditionFalse
+	if ready {
+		condition = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: condition}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+		},
+	}
+}
+
+func TestEngine_NamespaceHealth(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		testPod("shop", "checkout", corev1.PodRunning, true, 3),
+		testPod("shop", "cart", corev1.PodPending, false, 0),
+		testPod("web", "frontend", corev1.PodRunning, true, 0),
+		testPod("batch", "report", corev1.PodSucceeded, false, 1),
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "shop"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+	)
+	engine := NewEngine(EngineConfig{KubeClient: client, ContextName: "prod"})
+	engine.sloTracker.AddSLO(slo.SLO{Name: "web-availability", SLI: "availability", Target: 99.9, Window: time.Hour, Labels: map[string]string{"namespace": "web"}})
+	go engine.runNamespaceTracker()
+	defer engine.Stop()
+
+	byNamespace := func() map[string]NamespaceHealth {
+		report := engine.NamespaceHealth()
+		namespaces := make(map[string]NamespaceHealth, len(report.Namespaces))
+		for _, health := range report.Namespaces {
+			namespaces[health.Namespace] = health
+		}
+		return namespaces
+	}
+	waitFor := func(description string, ready func(map[string]NamespaceHealth) bool) map[string]NamespaceHealth {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			namespaces := byNamespace()
+			if ready(namespaces) {
+				return namespaces
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s, got %+v", description, namespaces)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	namespaces := waitFor("the caches to sync", func(map[string]NamespaceHealth) bool {
+		report := engine.NamespaceHealth()
+		return report.Synced && len(report.Namespaces) == 3
+	})
+	shop := namespaces["shop"]
+	if shop.Pods != 2 || shop.ReadyPods != 1 || shop.Readiness != 50 || shop.Restarts != 3 || shop.PendingPods != 1 ||
+		shop.PVCs != 1 || shop.PVCIssues != 1 || shop.Status != HealthStatusDegraded {
+		t.Errorf("unexpected shop rollup %+v", shop)
+	}
+	if web := namespaces["web"]; web.Status != HealthStatusHealthy || len(web.SLOs) != 1 || web.SLOs[0].Name != "web-availability" {
+		t.Errorf("unexpected web rollup %+v", web)
+	}
+	if batch := namespaces["batch"]; batch.Pods != 0 || batch.Restarts != 0 || batch.Readiness != 100 {
+		t.Errorf("expected succeeded pods not counted, got %+v", batch)
+	}
+	if first := engine.NamespaceHealth().Namespaces[0]; first.Namespace != "shop" {
+		t.Errorf("expected the worst namespace first, got %s", first.Namespace)
+	}
+
+	// Events update the rollups in place
+	ctx := context.Background()
+	if err := client.CoreV1().Pods("shop").Delete(ctx, "cart", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.CoreV1().Pods("web").UpdateStatus(ctx, testPod("web", "frontend", corev1.PodRunning, false, 2), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.CoreV1().Pods("batch").Delete(ctx, "report", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	namespaces = waitFor("the events", func(namespaces map[string]NamespaceHealth) bool {
+		_, batch := namespaces["batch"]
+		return namespaces["shop"].Pods == 1 && namespaces["web"].Restarts == 2 && !batch
+	})
+	if web := namespaces["web"]; web.ReadyPods != 0 || web.Readiness != 0 || web.Status != HealthStatusUnhealthy {
+		t.Errorf("expected web unhealthy with no pod ready, got %+v", web)
+	}
+}
+
+func TestNamespaceHealth_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		health NamespaceHealth
+		want   HealthStatus
+	}{
+		{"all ready", NamespaceHealth{Pods: 4, ReadyPods: 4, Readiness: 100}, HealthStatusHealthy},
+		{"no pods", NamespaceHealth{Readiness: 100}, HealthStatusHealthy},
+		{"one unready", NamespaceHealth{Pods: 4, ReadyPods: 3, Readiness: 75}, HealthStatusDegraded},
+		{"claim lost", NamespaceHealth{Readiness: 100, PVCs: 1, PVCIssues: 1}, HealthStatusDegraded},
+		{"warning alert", NamespaceHealth{Readiness: 100, Alerts: 1}, HealthStatusDegraded},
+		{"critical alert", NamespaceHealth{Readiness: 100, Alerts: 1, CriticalAlerts: 1}, HealthStatusUnhealthy},
+		{"violated SLO", NamespaceHealth{Readiness: 100, SLOs: []NamespaceSLO{{Name: "latency", Violated: true}}}, HealthStatusUnhealthy},
+		{"most unready", NamespaceHealth{Pods: 4, ReadyPods: 1, Readiness: 25}, HealthStatusUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.health.status(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
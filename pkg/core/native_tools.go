@@ -0,0 +1,511 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// nativeResync is how often the native tool informers replay their cache
+const nativeResync = 30 * time.Minute
+
+// nativeResources maps the names kubectl accepts for the resources native
+// tools answer from informer caches to their canonical name
+var nativeResources = map[string]string{
+	"pods": "pods", "pod": "pods", "po": "pods",
+	"nodes": "nodes", "node": "nodes", "no": "nodes",
+	"deployments": "deployments", "deployment": "deployments", "deploy": "deployments",
+	"events": "events", "event": "events", "ev": "events",
+}
+
+// nativeFields are the field selector keys native tools evaluate per
+// resource; selectors on other fields go to kubectl
+var nativeFields = map[string][]string{
+	"pods":        {"metadata.name", "metadata.namespace", "status.phase", "spec.nodeName"},
+	"nodes":       {"metadata.name"},
+	"deployments": {"metadata.name", "metadata.namespace"},
+	"events":      {"metadata.name", "metadata.namespace", "type", "reason", "involvedObject.name", "involvedObject.kind", "involvedObject.namespace"},
+}
+
+// nativeQuery is a read-only kubectl get native tools can answer
+type nativeQuery struct {
+	resource      string
+	name          string
+	namespace     string // Empty for all namespaces and cluster-scoped resources
+	allNamespaces bool
+	fields        fields.Selector
+	labels        labels.Selector
+	wide          bool
+}
+
+// nativeTools answers read-only kubectl gets of pods, nodes, deployments
+// and events from informer caches instead of running kubectl, with typed
+// objects and no external binary. Every other command, and every command
+// before the caches have synced, goes to the fallback executor.
+type nativeTools struct {
+	fallback ai.CommandExecutor
+	now      func() time.Time
+
+	mu          sync.RWMutex
+	pods        corelisters.PodLister
+	nodes       corelisters.NodeLister
+	deployments appslisters.DeploymentLister
+	events      corelisters.EventLister
+	synced      func() bool
+}
+
+func newNativeTools(fallback ai.CommandExecutor) *nativeTools {
+	return &nativeTools{fallback: fallback, now: time.Now}
+}
+
+// run fills the informer caches until ctx ends
+func (n *nativeTools) run(ctx context.Context, client kubernetes.Interface) {
+	factory := informers.NewSharedInformerFactory(client, nativeResync)
+	pods := factory.Core().V1().Pods()
+	nodes := factory.Core().V1().Nodes()
+	deployments := factory.Apps().V1().Deployments()
+	events := factory.Core().V1().Events()
+
+	n.mu.Lock()
+	n.pods, n.nodes, n.deployments, n.events = pods.Lister(), nodes.Lister(), deployments.Lister(), events.Lister()
+	syncs := []func() bool{pods.Informer().HasSynced, nodes.Informer().HasSynced, deployments.Informer().HasSynced, events.Informer().HasSynced}
+	n.synced = func() bool {
+		for _, synced := range syncs {
+			if !synced() {
+				return false
+			}
+		}
+		return true
+	}
+	n.mu.Unlock()
+
+	klog.Info("Answering kubectl gets of pods, nodes, deployments and events from informer caches")
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+// Execute answers a kubectl get from the caches, or runs the command with
+// the fallback executor
+func (n *nativeTools) Execute(ctx context.Context, command string) (string, error) {
+	query, ok := parseNativeQuery(command)
+	if !ok || !n.ready() {
+		return n.fallback.Execute(ctx, command)
+	}
+	return n.get(query)
+}
+
+// DryRun shows what a command would do with the fallback executor
+func (n *nativeTools) DryRun(ctx context.Context, command string) (string, error) {
+	return n.fallback.DryRun(ctx, command)
+}
+
+// ready reports whether the caches have listed everything once
+func (n *nativeTools) ready() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.synced != nil && n.synced()
+}
+
+// parseNativeQuery parses a kubectl get of a single native resource kind
+// with flags native tools understand
+func parseNativeQuery(command string) (nativeQuery, bool) {
+	parts := strings.Fields(command)
+	if len(parts) < 3 || parts[0] != "kubectl" || parts[1] != "get" {
+		return nativeQuery{}, false
+	}
+	resource, ok := nativeResources[parts[2]]
+	if !ok {
+		return nativeQuery{}, false
+	}
+	query := nativeQuery{resource: resource, labels: labels.Everything(), fields: fields.Everything()}
+
+	namespaced := resource != "nodes"
+	namespaceSet := false
+	args := parts[3:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		flag, value, inline := strings.Cut(arg, "=")
+		if strings.HasPrefix(arg, "-") && !inline && i+1 < len(args) && nativeFlagTakesValue(flag) {
+			i++
+			value = args[i]
+		}
+		switch {
+		case flag == "-A" || flag == "--all-namespaces":
+			query.allNamespaces = true
+		case flag == "-n" || flag == "--namespace":
+			query.namespace, namespaceSet = value, true
+		case flag == "-o" || flag == "--output":
+			if value != "wide" {
+				return nativeQuery{}, false
+			}
+			query.wide = true
+		case flag == "--field-selector":
+			selector, err := fields.ParseSelector(value)
+			if err != nil || !nativeSelectable(resource, selector) {
+				return nativeQuery{}, false
+			}
+			query.fields = selector
+		case flag == "-l" || flag == "--selector":
+			selector, err := labels.Parse(value)
+			if err != nil {
+				return nativeQuery{}, false
+			}
+			query.labels = selector
+		case flag == "--sort-by":
+			// Events are listed oldest first, as sorting by last timestamp
+			// does; other orders go to kubectl
+			if resource != "events" || value != ".lastTimestamp" {
+				return nativeQuery{}, false
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nativeQuery{}, false
+		case query.name == "":
+			query.name = arg
+		default:
+			return nativeQuery{}, false
+		}
+	}
+
+	if !namespaced {
+		query.namespace, query.allNamespaces = "", false
+		return query, true
+	}
+	if query.allNamespaces {
+		query.namespace = ""
+		return query, query.name == ""
+	}
+	// Without a namespace kubectl uses the kubeconfig context's, which only
+	// kubectl knows
+	return query, namespaceSet && query.namespace != ""
+}
+
+// nativeFlagTakesValue reports whether a flag reads the next argument as its
+// value
+func nativeFlagTakesValue(flag string) bool {
+	switch flag {
+	case "-n", "--namespace", "-o", "--output", "--field-selector", "-l", "--selector", "--sort-by":
+		return true
+	}
+	return false
+}
+
+// nativeSelectable reports whether native tools can evaluate every field
+// of a selector on a resource
+func nativeSelectable(resource string, selector fields.Selector) bool {
+	for _, requirement := range selector.Requirements() {
+		supported := false
+		for _, field := range nativeFields[resource] {
+			if requirement.Field == field {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return false
+		}
+	}
+	return true
+}
+
+// get lists the objects a query selects as kubectl prints them
+func (n *nativeTools) get(query nativeQuery) (string, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var (
+		rows [][]string
+		err  error
+	)
+	switch query.resource {
+	case "pods":
+		rows, err = n.getPods(query)
+	case "nodes":
+		rows, err = n.getNodes(query)
+	case "deployments":
+		rows, err = n.getDeployments(query)
+	case "events":
+		rows, err = n.getEvents(query)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(rows) <= 1 {
+		if query.namespace != "" {
+			return fmt.Sprintf("No resources found in %s namespace.\n", query.namespace), nil
+		}
+		return "No resources found\n", nil
+	}
+	return formatTable(rows), nil
+}
+
+// notFound is the error kubectl reports for a named object that doesn't exist
+func notFound(resource, name string) error {
+	return fmt.Errorf("Error from server (NotFound): %s %q not found", resource, name)
+}
+
+func (n *nativeTools) getPods(query nativeQuery) ([][]string, error) {
+	pods, err := n.pods.Pods(query.namespace).List(query.labels)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE"}
+	if query.wide {
+		header = append(header, "IP", "NODE")
+	}
+	rows := [][]string{withNamespaceColumn(query, header, "NAMESPACE")}
+	sort.Slice(pods, func(i, j int) bool {
+		return objectKey(pods[i].Namespace, pods[i].Name) < objectKey(pods[j].Namespace, pods[j].Name)
+	})
+	for _, pod := range pods {
+		if query.name != "" && pod.Name != query.name {
+			continue
+		}
+		set := fields.Set{"metadata.name": pod.Name, "metadata.namespace": pod.Namespace, "status.phase": string(pod.Status.Phase), "spec.nodeName": pod.Spec.NodeName}
+		if !query.fields.Matches(set) {
+			continue
+		}
+		ready, restarts := 0, int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Ready {
+				ready++
+			}
+			restarts += status.RestartCount
+		}
+		row := []string{pod.Name, fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)), podStatus(pod), fmt.Sprint(restarts), n.age(pod.CreationTimestamp.Time)}
+		if query.wide {
+			row = append(row, orNone(pod.Status.PodIP), orNone(pod.Spec.NodeName))
+		}
+		rows = append(rows, withNamespaceColumn(query, row, pod.Namespace))
+	}
+	if query.name != "" && len(rows) == 1 {
+		return nil, notFound("pods", query.name)
+	}
+	return rows, nil
+}
+
+func (n *nativeTools) getNodes(query nativeQuery) ([][]string, error) {
+	nodes, err := n.nodes.List(query.labels)
+	if err != nil {
+		return nil, err
+	}
+	header := []string{"NAME", "STATUS", "ROLES", "AGE", "VERSION"}
+	if query.wide {
+		header = append(header, "INTERNAL-IP", "OS-IMAGE", "CONTAINER-RUNTIME")
+	}
+	rows := [][]string{header}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for _, node := range nodes {
+		if query.name != "" && node.Name != query.name {
+			continue
+		}
+		if !query.fields.Matches(fields.Set{"metadata.name": node.Name}) {
+			continue
+		}
+		row := []string{node.Name, nodeStatus(node), nodeRoles(node), n.age(node.CreationTimestamp.Time), node.Status.NodeInfo.KubeletVersion}
+		if query.wide {
+			internalIP := ""
+			for _, address := range node.Status.Addresses {
+				if address.Type == corev1.NodeInternalIP {
+					internalIP = address.Address
+					break
+				}
+			}
+			row = append(row, orNone(internalIP), node.Status.NodeInfo.OSImage, node.Status.NodeInfo.ContainerRuntimeVersion)
+		}
+		rows = append(rows, row)
+	}
+	if query.name != "" && len(rows) == 1 {
+		return nil, notFound("nodes", query.name)
+	}
+	return rows, nil
+}
+
+func (n *nativeTools) getDeployments(query nativeQuery) ([][]string, error) {
+	deployments, err := n.deployments.Deployments(query.namespace).List(query.labels)
+	if err != nil {
+		return nil, err
+	}
+	rows := [][]string{withNamespaceColumn(query, []string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"}, "NAMESPACE")}
+	sort.Slice(deployments, func(i, j int) bool {
+		return objectKey(deployments[i].Namespace, deployments[i].Name) < objectKey(deployments[j].Namespace, deployments[j].Name)
+	})
+	for _, deployment := range deployments {
+		if query.name != "" && deployment.Name != query.name {
+			continue
+		}
+		if !query.fields.Matches(fields.Set{"metadata.name": deployment.Name, "metadata.namespace": deployment.Namespace}) {
+			continue
+		}
+		row := []string{
+			deployment.Name,
+			fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, desiredReplicas(deployment)),
+			fmt.Sprint(deployment.Status.UpdatedReplicas),
+			fmt.Sprint(deployment.Status.AvailableReplicas),
+			n.age(deployment.CreationTimestamp.Time),
+		}
+		rows = append(rows, withNamespaceColumn(query, row, deployment.Namespace))
+	}
+	if query.name != "" && len(rows) == 1 {
+		return nil, notFound("deployments.apps", query.name)
+	}
+	return rows, nil
+}
+
+func (n *nativeTools) getEvents(query nativeQuery) ([][]string, error) {
+	events, err := n.events.Events(query.namespace).List(query.labels)
+	if err != nil {
+		return nil, err
+	}
+	rows := [][]string{withNamespaceColumn(query, []string{"LAST SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"}, "NAMESPACE")}
+	sort.SliceStable(events, func(i, j int) bool { return eventTime(*events[i]).Before(eventTime(*events[j])) })
+	for _, event := range events {
+		if query.name != "" && event.Name != query.name {
+			continue
+		}
+		set := fields.Set{
+			"metadata.name":            event.Name,
+			"metadata.namespace":       event.Namespace,
+			"type":                     event.Type,
+			"reason":                   event.Reason,
+			"involvedObject.name":      event.InvolvedObject.Name,
+			"involvedObject.kind":      event.InvolvedObject.Kind,
+			"involvedObject.namespace": event.InvolvedObject.Namespace,
+		}
+		if !query.fields.Matches(set) {
+			continue
+		}
+		object := strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name
+		row := []string{n.age(eventTime(*event)), event.Type, event.Reason, object, strings.TrimSpace(event.Message)}
+		rows = append(rows, withNamespaceColumn(query, row, event.Namespace))
+	}
+	if query.name != "" && len(rows) == 1 {
+		return nil, notFound("events", query.name)
+	}
+	return rows, nil
+}
+
+// withNamespaceColumn prefixes a row with its namespace when listing all
+// namespaces, as kubectl does
+func withNamespaceColumn(query nativeQuery, row []string, namespace string) []string {
+	if !query.allNamespaces {
+		return row
+	}
+	return append([]string{namespace}, row...)
+}
+
+func objectKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// age formats how long ago t was as kubectl does
+func (n *nativeTools) age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(n.now().Sub(t))
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// podStatus is the status kubectl shows for a pod: the reason a container
+// isn't running, if any, else the pod's phase or reason
+func podStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing" {
+			return "Init:" + status.State.Waiting.Reason
+		}
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return "Init:Error"
+		}
+	}
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		switch {
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+			return container.State.Waiting.Reason
+		case container.State.Terminated != nil && container.State.Terminated.Reason != "":
+			status = container.State.Terminated.Reason
+		}
+	}
+	return status
+}
+
+// nodeStatus is the status kubectl shows for a node
+func nodeStatus(node *corev1.Node) string {
+	status := "Unknown"
+	for _, condition := range node.Status.Conditions {
+		if condition.Type != corev1.NodeReady {
+			continue
+		}
+		status = "NotReady"
+		if condition.Status == corev1.ConditionTrue {
+			status = "Ready"
+		}
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// nodeRoles lists a node's roles from its node-role.kubernetes.io labels
+func nodeRoles(node *corev1.Node) string {
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return "<none>"
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+// formatTable aligns rows into columns as kubectl prints them
+func formatTable(rows [][]string) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 3, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return buf.String()
+}
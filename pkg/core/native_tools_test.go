@@ -0,0 +1,252 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// testNativeTools returns native tools answering from fixed objects, with
+// the clock an hour after they were created
+func testNativeTools(t *testing.T, fallback *fakeKubectl) *nativeTools {
+	t.Helper()
+	created := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	index := func(objects ...interface{}) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		for _, obj := range objects {
+			if err := indexer.Add(obj); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return indexer
+	}
+	replicas := int32(3)
+
+	tools := newNativeTools(fallback)
+	tools.now = func() time.Time { return created.Add(time.Hour) }
+	tools.pods = corelisters.NewPodLister(index(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", CreationTimestamp: created, Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Name: "api"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.5", ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "api", RestartCount: 7, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", CreationTimestamp: created, Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeName: "node-b", Containers: []corev1.Container{{Name: "web"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-1", Namespace: "batch", CreationTimestamp: created},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "job"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	))
+	tools.nodes = corelisters.NewNodeLister(index(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a", CreationTimestamp: created, Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""}},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.36.1"},
+			},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-b", CreationTimestamp: created},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+				NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.36.1"},
+			},
+		},
+	))
+	tools.deployments = appslisters.NewDeploymentLister(index(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", CreationTimestamp: created},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, UpdatedReplicas: 3, AvailableReplicas: 2},
+		},
+	))
+	tools.events = corelisters.NewEventLister(index(
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "api-1.b", Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-1", Namespace: "shop"},
+			Type:           corev1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container",
+			LastTimestamp: metav1.NewTime(created.Add(50 * time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "api-1.a", Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "api-1", Namespace: "shop"},
+			Type:           corev1.EventTypeNormal, Reason: "Pulled", Message: "Container image pulled",
+			LastTimestamp: metav1.NewTime(created.Add(40 * time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "node-b.a", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-b"},
+			Type:           corev1.EventTypeWarning, Reason: "NodeNotReady", Message: "Node node-b status is now: NodeNotReady",
+			LastTimestamp: metav1.NewTime(created.Add(55 * time.Minute)),
+		},
+	))
+	tools.synced = func() bool { return true }
+	return tools
+}
+
+// tableRows splits kubectl-style output into rows of columns
+func tableRows(output string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	return rows
+}
+
+func TestNativeToolsGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:    "pods in a namespace",
+			command: "kubectl get pods -n shop",
+			want: [][]string{
+				{"NAME", "READY", "STATUS", "RESTARTS", "AGE"},
+				{"api-1", "0/1", "CrashLoopBackOff", "7", "60m"},
+				{"web-1", "1/1", "Running", "0", "60m"},
+			},
+		},
+		{
+			name:    "pods not running in all namespaces",
+			command: "kubectl get pods -A --field-selector status.phase!=Running,status.phase!=Succeeded",
+			want: [][]string{
+				{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE"},
+				{"batch", "job-1", "0/1", "Pending", "0", "60m"},
+			},
+		},
+		{
+			name:    "pods on a node, wide",
+			command: "kubectl get pods -A --field-selector spec.nodeName=node-a -o wide",
+			want: [][]string{
+				{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE"},
+				{"shop", "api-1", "0/1", "CrashLoopBackOff", "7", "60m", "10.0.0.5", "node-a"},
+			},
+		},
+		{
+			name:    "pods by label",
+			command: "kubectl get po -n shop -l app=web",
+			want: [][]string{
+				{"NAME", "READY", "STATUS", "RESTARTS", "AGE"},
+				{"web-1", "1/1", "Running", "0", "60m"},
+			},
+		},
+		{
+			name:    "nodes",
+			command: "kubectl get nodes",
+			want: [][]string{
+				{"NAME", "STATUS", "ROLES", "AGE", "VERSION"},
+				{"node-a", "Ready", "control-plane", "60m", "v1.36.1"},
+				{"node-b", "NotReady,SchedulingDisabled", "<none>", "60m", "v1.36.1"},
+			},
+		},
+		{
+			name:    "deployment by name",
+			command: "kubectl get deployment api --namespace=shop",
+			want: [][]string{
+				{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE"},
+				{"api", "2/3", "3", "2", "60m"},
+			},
+		},
+		{
+			name:    "warning events, oldest first",
+			command: "kubectl get events -A --field-selector type=Warning --sort-by=.lastTimestamp",
+			want: [][]string{
+				{"NAMESPACE", "LAST", "SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"},
+				{"shop", "10m", "Warning", "BackOff", "pod/api-1", "Back-off", "restarting", "failed", "container"},
+				{"default", "5m", "Warning", "NodeNotReady", "node/node-b", "Node", "node-b", "status", "is", "now:", "NodeNotReady"},
+			},
+		},
+		{
+			name:    "events of a pod",
+			command: "kubectl get events -n shop --field-selector involvedObject.name=api-1",
+			want: [][]string{
+				{"LAST", "SEEN", "TYPE", "REASON", "OBJECT", "MESSAGE"},
+				{"20m", "Normal", "Pulled", "pod/api-1", "Container", "image", "pulled"},
+				{"10m", "Warning", "BackOff", "pod/api-1", "Back-off", "restarting", "failed", "container"},
+			},
+		},
+		{
+			name:    "nothing selected",
+			command: "kubectl get deployments -n batch",
+			want:    [][]string{{"No", "resources", "found", "in", "batch", "namespace."}},
+		},
+		{
+			name:    "missing pod",
+			command: "kubectl get pod api-9 -n shop",
+			wantErr: `pods "api-9" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback := &fakeKubectl{}
+			output, err := testNativeTools(t, fallback).Execute(context.Background(), tt.command)
+			if len(fallback.commands) > 0 {
+				t.Fatalf("ran kubectl %v, want the caches to answer", fallback.commands)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := tableRows(output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("output:\n%s\nrows = %v, want %v", output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNativeToolsFallback(t *testing.T) {
+	commands := []string{
+		"kubectl describe pod api-1 -n shop",
+		"kubectl logs api-1 -n shop --previous",
+		"kubectl get pods",                   // The context's namespace
+		"kubectl get pods -n shop -o yaml",   // Unsupported output
+		"kubectl get services -n shop",       // Unsupported resource
+		"kubectl get pods,services -n shop",  // Several resources
+		"kubectl get pod api-1 -A",           // Named across namespaces
+		"kubectl get events -n shop --watch", // Unknown flag
+		"kubectl get pods -A --field-selector status.podIP=10.0.0.5",
+		"kubectl get pods -A --sort-by=.metadata.name",
+	}
+	for _, command := range commands {
+		t.Run(command, func(t *testing.T) {
+			fallback := &fakeKubectl{}
+			output, err := testNativeTools(t, fallback).Execute(context.Background(), command)
+			if err != nil || output != "ok" || !reflect.DeepEqual(fallback.commands, []string{command}) {
+				t.Errorf("Execute() = %q, %v; kubectl ran %v, want the command passed to kubectl", output, err, fallback.commands)
+			}
+		})
+	}
+
+	// Until the caches sync, kubectl answers
+	fallback := &fakeKubectl{}
+	tools := testNativeTools(t, fallback)
+	tools.synced = func() bool { return false }
+	if _, err := tools.Execute(context.Background(), "kubectl get nodes"); err != nil || len(fallback.commands) != 1 {
+		t.Errorf("before sync kubectl ran %v, want the command passed to kubectl", fallback.commands)
+	}
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// RegisterAlertChannel adds a notification channel alert rules and
+// escalation policies can deliver to
+func (e *Engine) RegisterAlertChannel(channel alerts.NotificationChannel) {
+	e.alertManager.RegisterChannel(channel)
+}
+
+// SetChannelQuietHours holds back a channel's non-urgent alerts during a daily window
+func (e *Engine) SetChannelQuietHours(channel string, quiet alerts.QuietHours) error {
+	return e.alertManager.SetQuietHours(channel, quiet)
+}
+
+// SetAlertRoutes sends the alerts of a team, app or tier to a channel
+// instead of their rule's
+func (e *Engine) SetAlertRoutes(routes []alerts.Route) error {
+	return e.alertManager.SetRoutes(routes)
+}
+
+// AddEscalationPolicy adds a chain of channels notified until an alert is acknowledged
+func (e *Engine) AddEscalationPolicy(policy alerts.EscalationPolicy) error {
+	return e.alertManager.AddEscalationPolicy(policy)
+}
+
+// GetEscalations lists alerts still being escalated
+func (e *Engine) GetEscalations() []alerts.EscalationState {
+	return e.alertManager.Escalations()
+}
+
+// AcknowledgeAlert marks an alert as handled, stopping its escalation
+func (e *Engine) AcknowledgeAlert(id, by string) (alerts.Alert, error) {
+	alert, err := e.alertManager.Acknowledge(id, by)
+	if err != nil {
+		return alerts.Alert{}, err
+	}
+	klog.Infof("Alert %s acknowledged by %s", id, by)
+	return alert, nil
+}
+
+// alertDiagnosis returns the latest AI diagnosis of a failing check for
+// alert templates, or nil when it hasn't been diagnosed
+func (e *Engine) alertDiagnosis(check string) *alerts.Diagnosis {
+	e.resultsMu.RLock()
+	diagnosis := e.diagnoses[check]
+	e.resultsMu.RUnlock()
+	if diagnosis == nil {
+		return nil
+	}
+
+	recommendations := make([]string, 0, len(diagnosis.Recommendations))
+	for _, recommendation := range diagnosis.Recommendations {
+		recommendations = append(recommendations, recommendation.Title)
+	}
+	return &alerts.Diagnosis{
+		Summary:         diagnosis.Summary,
+		Diagnosis:       diagnosis.Diagnosis,
+		Confidence:      diagnosis.Confidence,
+		Recommendations: recommendations,
+	}
+}
+
+// SetAlertExternalURL sets the address responders reach KubePulse at, so
+// notifications carry links and commands that silence their alert
+func (e *Engine) SetAlertExternalURL(externalURL string) {
+	e.alertManager.SetExternalURL(externalURL)
+}
+
+// GetSilences lists the silences still in effect, soonest to expire first
+func (e *Engine) GetSilences() []alerts.Silence {
+	return e.alertManager.Silences()
+}
+
+// AddSilence holds back alerts matching the silence's matchers until it expires
+func (e *Engine) AddSilence(silence alerts.Silence) (alerts.Silence, error) {
+	silence, err := e.alertManager.AddSilence(silence)
+	if err != nil {
+		return alerts.Silence{}, err
+	}
+	klog.Infof("Silence %s of %v added by %s until %s", silence.ID, silence.Matchers, silence.CreatedBy, silence.Until.Format(time.RFC3339))
+	return silence, nil
+}
+
+// ExpireSilence ends a silence before it expires
+func (e *Engine) ExpireSilence(id string) (alerts.Silence, error) {
+	silence, err := e.alertManager.ExpireSilence(id)
+	if err != nil {
+		return alerts.Silence{}, err
+	}
+	klog.Infof("Silence %s expired", id)
+	return silence, nil
+}
+
+// SetDeliveryQueue keeps notifications channels fail to accept and retries
+// them with backoff
+func (e *Engine) SetDeliveryQueue(queue *alerts.DeliveryQueue) {
+	e.alertManager.SetDeliveryQueue(queue)
+}
+
+// GetDeliveries lists queued notifications in a state, or all of them when
+// state is empty
+func (e *Engine) GetDeliveries(state string) []alerts.Delivery {
+	return e.alertManager.Deliveries(state)
+}
+
+// Redeliver immediately retries a queued or dead-lettered notification
+func (e *Engine) Redeliver(ctx context.Context, id string) (alerts.Delivery, error) {
+	delivery, err := e.alertManager.Redeliver(ctx, id)
+	if err != nil {
+		return delivery, err
+	}
+	klog.Infof("Notification %s redelivered to channel %s", id, delivery.Channel)
+	return delivery, nil
+}
+
+// processEscalations notifies the next channel of overdue unacknowledged alerts
+func (e *Engine) processEscalations() {
+	if err := e.alertManager.ProcessEscalations(e.ctx); err != nil {
+		klog.Errorf("Failed to process escalations: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+)
+
+const (
+	// DetailOwners lists the owners of the resources a failing check blames
+	DetailOwners = "owners"
+	// DetailApps lists the owners of every resource a check looked at, so
+	// healthy apps are known too
+	DetailApps = "apps"
+)
+
+// maxOwners caps the owners a result lists
+const maxOwners = 200
+
+// Ownership is who owns a workload: its team, application and tier
+type Ownership = alerts.Ownership
+
+// Common ownership conventions, tried after configured keys in order
+var (
+	defaultTeamKeys = []string{"kubepulse.io/team", "team", "owner"}
+	defaultAppKeys  = []string{"app.kubernetes.io/part-of", "app.kubernetes.io/name", "app", "k8s-app"}
+	defaultTierKeys = []string{"app.kubernetes.io/component", "tier"}
+)
+
+// OwnershipRules maps the label or annotation keys of a cluster's own
+// ownership conventions, such as acme.com/squad, to ownership fields.
+// They're tried before the common conventions.
+type OwnershipRules struct {
+	Team []string `json:"team,omitempty"`
+	App  []string `json:"app,omitempty"`
+	Tier []string `json:"tier,omitempty"`
+}
+
+// Resolve returns the ownership from sets of labels and annotations, most
+// specific first, such as a pod's labels and annotations then its
+// namespace's. Each field comes from the first set that has one.
+func (r OwnershipRules) Resolve(metadata ...map[string]string) Ownership {
+	return Ownership{
+		Team: lookupOwner(metadata, r.Team, defaultTeamKeys),
+		App:  lookupOwner(metadata, r.App, defaultAppKeys),
+		Tier: lookupOwner(metadata, r.Tier, defaultTierKeys),
+	}
+}
+
+// lookupOwner returns the first value set for one of the keys
+func lookupOwner(metadata []map[string]string, keys ...[]string) string {
+	for _, values := range metadata {
+		for _, set := range keys {
+			for _, key := range set {
+				if value := strings.TrimSpace(values[key]); value != "" {
+					return value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// SetOwners records distinct, known owners under a detail, DetailOwners
+// or DetailApps
+func SetOwners(result *CheckResult, detail string, owners []Ownership) {
+	seen := make(map[Ownership]bool, len(owners))
+	distinct := make([]Ownership, 0, len(owners))
+	for _, owner := range owners {
+		if owner.IsZero() || seen[owner] {
+			continue
+		}
+		seen[owner] = true
+		distinct = append(distinct, owner)
+	}
+	if len(distinct) == 0 {
+		return
+	}
+	sortOwners(distinct)
+	if len(distinct) > maxOwners {
+		distinct = distinct[:maxOwners]
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details[detail] = distinct
+}
+
+// Owners returns the owners of the resources a result blames, including
+// results decoded from JSON
+func Owners(result CheckResult) []Ownership {
+	return ownersDetail(result, DetailOwners)
+}
+
+// ownersDetail decodes a detail set by SetOwners
+func ownersDetail(result CheckResult, detail string) []Ownership {
+	switch owners := result.Details[detail].(type) {
+	case []Ownership:
+		return owners
+	case []interface{}:
+		data, err := json.Marshal(owners)
+		if err != nil {
+			return nil
+		}
+		var decoded []Ownership
+		if json.Unmarshal(data, &decoded) != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
+
+// sortOwners orders owners by team, app and tier
+func sortOwners(owners []Ownership) {
+	sort.Slice(owners, func(i, j int) bool {
+		a, b := owners[i], owners[j]
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		return a.Tier < b.Tier
+	})
+}
+
+// AppHealth is the health of one owner's workloads: the worst status of
+// the checks failing on them
+type AppHealth struct {
+	Ownership
+	Status HealthStatus `json:"status"`
+	Checks []AppCheck   `json:"checks,omitempty"` // Checks failing on the app's workloads
+}
+
+// AppCheck is a check failing on an app's workloads
+type AppCheck struct {
+	Name    string       `json:"name"`
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message"`
+}
+
+// GroupByApp groups check results by the owners of the resources they
+// looked at, worst first. Resources without a known owner are left out.
+func GroupByApp(results map[string]CheckResult) []AppHealth {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	apps := make(map[Ownership]*AppHealth)
+	app := func(owner Ownership) *AppHealth {
+		if apps[owner] == nil {
+			apps[owner] = &AppHealth{Ownership: owner, Status: HealthStatusHealthy}
+		}
+		return apps[owner]
+	}
+	for _, name := range names {
+		result := results[name]
+		for _, owner := range ownersDetail(result, DetailApps) {
+			app(owner)
+		}
+		if !isAlerting(result.Status) {
+			continue
+		}
+		for _, owner := range Owners(result) {
+			health := app(owner)
+			health.Checks = append(health.Checks, AppCheck{Name: result.Name, Status: result.Status, Message: result.Message})
+			if statusRank(result.Status) > statusRank(health.Status) {
+				health.Status = result.Status
+			}
+		}
+	}
+
+	owners := make([]Ownership, 0, len(apps))
+	for owner := range apps {
+		owners = append(owners, owner)
+	}
+	sortOwners(owners)
+	grouped := make([]AppHealth, 0, len(owners))
+	for _, owner := range owners {
+		grouped = append(grouped, *apps[owner])
+	}
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return statusRank(grouped[i].Status) > statusRank(grouped[j].Status)
+	})
+	return grouped
+}
+
+// AppHealth groups the latest check results by the apps they looked at
+func (e *Engine) AppHealth() []AppHealth {
+	return GroupByApp(e.GetResults())
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOwnershipRules_Resolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    OwnershipRules
+		metadata []map[string]string
+		want     Ownership
+	}{
+		{
+			name:     "recommended labels",
+			metadata: []map[string]string{{"app.kubernetes.io/name": "checkout", "app.kubernetes.io/component": "backend", "team": "payments"}},
+			want:     Ownership{Team: "payments", App: "checkout", Tier: "backend"},
+		},
+		{
+			name:     "part-of names the app",
+			metadata: []map[string]string{{"app.kubernetes.io/part-of": "shop", "app.kubernetes.io/name": "cart"}},
+			want:     Ownership{App: "shop"},
+		},
+		{
+			name:     "namespace fills in the team",
+			metadata: []map[string]string{{"app": "web"}, nil, {"owner": "search"}},
+			want:     Ownership{Team: "search", App: "web"},
+		},
+		{
+			name:     "pod wins over its namespace",
+			metadata: []map[string]string{{"team": "payments"}, {"team": "platform"}},
+			want:     Ownership{Team: "payments"},
+		},
+		{
+			name:     "configured keys first",
+			rules:    OwnershipRules{Team: []string{"acme.com/squad"}},
+			metadata: []map[string]string{{"team": "payments", "acme.com/squad": "billing"}},
+			want:     Ownership{Team: "billing"},
+		},
+		{name: "no conventions", metadata: []map[string]string{{"version": "v1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Resolve(tt.metadata...); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGroupByApp(t *testing.T) {
+	checkout := Ownership{Team: "payments", App: "checkout"}
+	search := Ownership{Team: "search", App: "web"}
+	pods := CheckResult{Name: "pod-health", Status: HealthStatusDegraded, Message: "1 failed", Details: map[string]interface{}{}}
+	SetOwners(&pods, DetailOwners, []Ownership{checkout, checkout, {}})
+	SetOwners(&pods, DetailApps, []Ownership{search, checkout})
+	services := CheckResult{Name: "service-health", Status: HealthStatusHealthy, Details: map[string]interface{}{}}
+	SetOwners(&services, DetailApps, []Ownership{search})
+
+	// Results read back from history carry owners as decoded JSON
+	data, err := json.Marshal(pods)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owners := Owners(decoded); len(owners) != 1 || owners[0] != checkout {
+		t.Fatalf("expected the checkout owner decoded once, got %+v", owners)
+	}
+
+	apps := GroupByApp(map[string]CheckResult{"pod-health": decoded, "service-health": services})
+	if len(apps) != 2 {
+		t.Fatalf("expected two apps, got %+v", apps)
+	}
+	if apps[0].Ownership != checkout || apps[0].Status != HealthStatusDegraded || len(apps[0].Checks) != 1 || apps[0].Checks[0].Name != "pod-health" {
+		t.Errorf("expected checkout degraded by pod-health first, got %+v", apps[0])
+	}
+	if apps[1].Ownership != search || apps[1].Status != HealthStatusHealthy || len(apps[1].Checks) != 0 {
+		t.Errorf("expected the search app healthy, got %+v", apps[1])
+	}
+}
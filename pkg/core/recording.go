@@ -0,0 +1,444 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ErrRecordingNotFound is returned for an unknown recording ID
+var ErrRecordingNotFound = errors.New("recording not found")
+
+const (
+	defaultRecordingsPerCheck = 5
+	maxRecordedResponse       = 16 << 20 // Larger responses mark the recording truncated
+)
+
+// RecordedResponse is an API server response a check read while it ran.
+// JSON responses are kept in Body and plain text ones, such as pod logs, in Text.
+type RecordedResponse struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// Recording is a check result together with the raw API responses the check
+// saw, so the check can be replayed against exactly the same inputs
+type Recording struct {
+	ID         string             `json:"id"`
+	Check      string             `json:"check"`
+	Cluster    string             `json:"cluster,omitempty"`
+	RecordedAt time.Time          `json:"recorded_at"`
+	Result     CheckResult        `json:"result"`
+	Error      string             `json:"error,omitempty"` // Error returned by the check, if any
+	Responses  []RecordedResponse `json:"responses"`
+	Truncated  bool               `json:"truncated,omitempty"` // Some responses were too large or binary
+}
+
+// RecordingSummary describes a recording without its API responses
+type RecordingSummary struct {
+	ID         string       `json:"id"`
+	Check      string       `json:"check"`
+	Cluster    string       `json:"cluster,omitempty"`
+	RecordedAt time.Time    `json:"recorded_at"`
+	Status     HealthStatus `json:"status"`
+	Responses  int          `json:"responses"`
+	Truncated  bool         `json:"truncated,omitempty"`
+}
+
+// ReplayReport compares a recorded result with the result of re-running the
+// check against the recorded API responses
+type ReplayReport struct {
+	RecordingID string      `json:"recording_id"`
+	Check       string      `json:"check"`
+	Recorded    CheckResult `json:"recorded"`
+	Replayed    CheckResult `json:"replayed"`
+	Matches     bool        `json:"matches"`
+	Differences []string    `json:"differences,omitempty"`
+}
+
+// CheckRecorder captures the API responses each check reads. Its transport
+// wrapper must be installed on the Kubernetes client's rest.Config; only
+// requests made under Run are recorded.
+type CheckRecorder struct {
+	mu         sync.Mutex
+	recordings map[string][]Recording // Newest last, keyed by check
+	perCheck   int
+	seq        uint64
+}
+
+// NewCheckRecorder creates a recorder keeping the latest perCheck recordings
+// of every check
+func NewCheckRecorder(perCheck int) *CheckRecorder {
+	if perCheck <= 0 {
+		perCheck = defaultRecordingsPerCheck
+	}
+	return &CheckRecorder{
+		recordings: make(map[string][]Recording),
+		perCheck:   perCheck,
+	}
+}
+
+// recordingSession collects the responses of one check run
+type recordingSession struct {
+	mu        sync.Mutex
+	responses []RecordedResponse
+	truncated bool
+}
+
+type recordingSessionKey struct{}
+
+// WrapTransport wraps a client transport to capture responses for recorded
+// check runs; it matches rest.Config.Wrap
+func (r *CheckRecorder) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{next: rt}
+}
+
+// recordingTransport tees GET responses into the request's recording session
+type recordingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	session, ok := req.Context().Value(recordingSessionKey{}).(*recordingSession)
+	if !ok || err != nil || req.Method != http.MethodGet {
+		return resp, err
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return resp, readErr
+	}
+
+	recorded := RecordedResponse{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Status: resp.StatusCode,
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case len(body) > maxRecordedResponse:
+		mediaType = ""
+	case mediaType == "application/json" && json.Valid(body):
+		recorded.Body = json.RawMessage(body)
+	case strings.HasPrefix(mediaType, "text/") && utf8.Valid(body):
+		recorded.Text = string(body)
+	default:
+		mediaType = ""
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if mediaType == "" {
+		session.truncated = true
+		return resp, nil
+	}
+	session.responses = append(session.responses, recorded)
+	return resp, nil
+}
+
+// Run executes a check while recording the API responses it reads, and
+// stores the recording
+func (r *CheckRecorder) Run(ctx context.Context, check HealthCheck, client kubernetes.Interface, cluster string) (Recording, CheckResult, error) {
+	session := &recordingSession{}
+	result, err := check.Check(context.WithValue(ctx, recordingSessionKey{}, session), client)
+
+	session.mu.Lock()
+	recording := Recording{
+		Check:      check.Name(),
+		Cluster:    cluster,
+		RecordedAt: time.Now(),
+		Result:     result,
+		Responses:  session.responses,
+		Truncated:  session.truncated,
+	}
+	session.mu.Unlock()
+	if err != nil {
+		recording.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.seq++
+	recording.ID = fmt.Sprintf("%s-%d", recording.Check, r.seq)
+	kept := append(r.recordings[recording.Check], recording)
+	if len(kept) > r.perCheck {
+		kept = kept[len(kept)-r.perCheck:]
+	}
+	r.recordings[recording.Check] = kept
+	r.mu.Unlock()
+
+	return recording, result, err
+}
+
+// List summarizes stored recordings, newest first, optionally for one check
+func (r *CheckRecorder) List(check string) []RecordingSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := make([]RecordingSummary, 0)
+	for name, recordings := range r.recordings {
+		if check != "" && name != check {
+			continue
+		}
+		for _, rec := range recordings {
+			summaries = append(summaries, RecordingSummary{
+				ID:         rec.ID,
+				Check:      rec.Check,
+				Cluster:    rec.Cluster,
+				RecordedAt: rec.RecordedAt,
+				Status:     rec.Result.Status,
+				Responses:  len(rec.Responses),
+				Truncated:  rec.Truncated,
+			})
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].RecordedAt.After(summaries[j].RecordedAt)
+	})
+	return summaries
+}
+
+// Get returns a stored recording by ID
+func (r *CheckRecorder) Get(id string) (*Recording, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, recordings := range r.recordings {
+		for _, rec := range recordings {
+			if rec.ID == id {
+				rec := rec
+				return &rec, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRecordingNotFound, id)
+}
+
+// LoadRecording reads a recording saved as JSON, e.g. from the recordings API
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - user-selected recording file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode recording %s: %w", path, err)
+	}
+	if rec.Check == "" {
+		return nil, fmt.Errorf("recording %s does not name a check", path)
+	}
+	return &rec, nil
+}
+
+// Client returns a clientset answering requests from the recorded
+// responses. A request is matched on its path and query, falling back to its
+// path alone; anything else gets a NotFound status.
+func (rec Recording) Client() (kubernetes.Interface, error) {
+	client, err := kubernetes.NewForConfig(rec.replayConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay client: %w", err)
+	}
+	return client, nil
+}
+
+// DynamicClient returns a dynamic client answering from the recorded
+// responses, for checks that read custom resources
+func (rec Recording) DynamicClient() (dynamic.Interface, error) {
+	client, err := dynamic.NewForConfig(rec.replayConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay dynamic client: %w", err)
+	}
+	return client, nil
+}
+
+// replayConfig returns a client config served by the recorded responses
+func (rec Recording) replayConfig() *rest.Config {
+	return &rest.Config{
+		Host:      "http://recording.invalid",
+		Transport: &replayTransport{responses: rec.Responses},
+	}
+}
+
+// replayTransport serves recorded responses instead of calling a cluster
+type replayTransport struct {
+	responses []RecordedResponse
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var match *RecordedResponse
+	for i := range t.responses {
+		resp := &t.responses[i]
+		if resp.Method != req.Method || resp.Path != req.URL.Path {
+			continue
+		}
+		if resp.Query == req.URL.RawQuery {
+			match = resp
+			break
+		}
+		if match == nil {
+			match = resp
+		}
+	}
+
+	if match == nil {
+		body := fmt.Sprintf(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,"message":"%s %s was not recorded"}`,
+			req.Method, req.URL.Path)
+		return replayResponse(req, http.StatusNotFound, "application/json", []byte(body)), nil
+	}
+	if match.Text != "" || len(match.Body) == 0 {
+		return replayResponse(req, match.Status, "text/plain", []byte(match.Text)), nil
+	}
+	return replayResponse(req, match.Status, "application/json", match.Body), nil
+}
+
+// replayResponse builds an HTTP response for a replayed request
+func replayResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{contentType}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// ReplayCheck re-runs a check implementation against a recording's API
+// responses and reports how the result differs from the recorded one.
+// Timestamps, durations and metrics are not compared; checks that keep state
+// between runs replay as if it were their first run.
+func ReplayCheck(ctx context.Context, check HealthCheck, rec Recording) (*ReplayReport, error) {
+	if check.Name() != rec.Check {
+		return nil, fmt.Errorf("recording %s is for check %s, not %s", rec.ID, rec.Check, check.Name())
+	}
+	client, err := rec.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := check.Check(ctx, client)
+	report := &ReplayReport{
+		RecordingID: rec.ID,
+		Check:       rec.Check,
+		Recorded:    rec.Result,
+		Replayed:    replayed,
+	}
+	replayErr := ""
+	if err != nil {
+		replayErr = err.Error()
+	}
+	report.Differences = compareResults(rec.Result, rec.Error, replayed, replayErr)
+	report.Matches = len(report.Differences) == 0
+	return report, nil
+}
+
+// compareResults lists the differences between two check outcomes
+func compareResults(recorded CheckResult, recordedErr string, replayed CheckResult, replayedErr string) []string {
+	var differences []string
+	if recorded.Status != replayed.Status {
+		differences = append(differences, fmt.Sprintf("status: recorded %s, replayed %s", recorded.Status, replayed.Status))
+	}
+	if recorded.Message != replayed.Message {
+		differences = append(differences, fmt.Sprintf("message: recorded %q, replayed %q", recorded.Message, replayed.Message))
+	}
+	if recorded.AffectedResources != replayed.AffectedResources {
+		differences = append(differences, fmt.Sprintf("affected_resources: recorded %d, replayed %d", recorded.AffectedResources, replayed.AffectedResources))
+	}
+	if recordedErr != replayedErr {
+		differences = append(differences, fmt.Sprintf("error: recorded %q, replayed %q", recordedErr, replayedErr))
+	}
+
+	// Compare details in their JSON form, as recordings loaded from disk hold
+	// decoded JSON rather than the check's own types
+	keys := make(map[string]bool)
+	for key := range recorded.Details {
+		keys[key] = true
+	}
+	for key := range replayed.Details {
+		keys[key] = true
+	}
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		before, _ := json.Marshal(recorded.Details[key])
+		after, _ := json.Marshal(replayed.Details[key])
+		if !jsonEqual(before, after) {
+			differences = append(differences, fmt.Sprintf("details.%s: recorded %s, replayed %s",
+				key, strings.TrimSpace(string(before)), strings.TrimSpace(string(after))))
+		}
+	}
+	return differences
+}
+
+// jsonEqual compares two JSON documents structurally
+func jsonEqual(a, b []byte) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// RecordingEnabled reports whether check runs are being recorded
+func (e *Engine) RecordingEnabled() bool {
+	return e.recorder != nil
+}
+
+// GetRecordings summarizes recorded check runs, optionally for one check
+func (e *Engine) GetRecordings(check string) []RecordingSummary {
+	if e.recorder == nil {
+		return []RecordingSummary{}
+	}
+	return e.recorder.List(check)
+}
+
+// GetRecording returns a recorded check run by ID
+func (e *Engine) GetRecording(id string) (*Recording, error) {
+	if e.recorder == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRecordingNotFound, id)
+	}
+	return e.recorder.Get(id)
+}
+
+// ReplayRecording re-runs the registered implementation of a recorded check
+// against the recorded API responses
+func (e *Engine) ReplayRecording(ctx context.Context, id string) (*ReplayReport, error) {
+	rec, err := e.GetRecording(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, check := range e.checks {
+		if check.Name() == rec.Check {
+			ctx, cancel := context.WithTimeout(ctx, e.checkTimeout)
+			defer cancel()
+			return ReplayCheck(ctx, check, *rec)
+		}
+	}
+	return nil, fmt.Errorf("check %s is no longer registered", rec.Check)
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// podLogCheck lists pods and reads the logs of those that are not running,
+// exercising both JSON and plain text responses
+type podLogCheck struct {
+	mockHealthCheck
+	threshold int
+}
+
+func (c *podLogCheck) Check(ctx context.Context, client kubernetes.Interface) (CheckResult, error) {
+	result := CheckResult{Name: c.name, Status: HealthStatusHealthy, Details: map[string]interface{}{}}
+	pods, err := client.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var failing []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			continue
+		}
+		logs, err := client.CoreV1().Pods("default").GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to read logs: %w", err)
+		}
+		failing = append(failing, pod.Name+": "+strings.TrimSpace(string(logs)))
+	}
+	result.Details["failing"] = failing
+	if len(failing) > c.threshold {
+		result.Status = HealthStatusUnhealthy
+		result.Message = fmt.Sprintf("%d pods failing", len(failing))
+	}
+	return result, nil
+}
+
+// recordingAPIServer serves a pod list and pod logs like an API server
+func recordingAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/default/pods":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"PodList","apiVersion":"v1","items":[
+				{"metadata":{"name":"web","namespace":"default"},"status":{"phase":"Running"}},
+				{"metadata":{"name":"worker","namespace":"default"},"status":{"phase":"Failed"}}]}`))
+		case "/api/v1/namespaces/default/pods/worker/log":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("panic: out of memory\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func recordedClient(t *testing.T, recorder *CheckRecorder) kubernetes.Interface {
+	t.Helper()
+	config := &rest.Config{Host: recordingAPIServer(t).URL}
+	config.Wrap(recorder.WrapTransport)
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestCheckRecorder_RecordAndReplay(t *testing.T) {
+	recorder := NewCheckRecorder(0)
+	client := recordedClient(t, recorder)
+	check := &podLogCheck{mockHealthCheck: mockHealthCheck{name: "pods"}}
+
+	rec, result, err := recorder.Run(context.Background(), check, client, "prod")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Status != HealthStatusUnhealthy {
+		t.Fatalf("expected unhealthy result, got %s", result.Status)
+	}
+	if rec.ID != "pods-1" || rec.Cluster != "prod" || rec.Truncated {
+		t.Errorf("unexpected recording %s cluster=%q truncated=%v", rec.ID, rec.Cluster, rec.Truncated)
+	}
+	if len(rec.Responses) != 2 {
+		t.Fatalf("expected 2 recorded responses, got %d", len(rec.Responses))
+	}
+	if rec.Responses[1].Text != "panic: out of memory\n" {
+		t.Errorf("expected pod logs recorded as text, got %+v", rec.Responses[1])
+	}
+
+	// Requests outside Run are not recorded
+	if _, err := client.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if stored, _ := recorder.Get(rec.ID); len(stored.Responses) != 2 {
+		t.Errorf("expected unrelated requests to be ignored, got %d responses", len(stored.Responses))
+	}
+
+	report, err := ReplayCheck(context.Background(), check, rec)
+	if err != nil {
+		t.Fatalf("ReplayCheck() error = %v", err)
+	}
+	if !report.Matches {
+		t.Errorf("expected replay to match, got differences %v", report.Differences)
+	}
+
+	lenient := &podLogCheck{mockHealthCheck: mockHealthCheck{name: "pods"}, threshold: 1}
+	report, err = ReplayCheck(context.Background(), lenient, rec)
+	if err != nil {
+		t.Fatalf("ReplayCheck() error = %v", err)
+	}
+	if report.Matches || len(report.Differences) != 2 {
+		t.Fatalf("expected status and message differences, got %v", report.Differences)
+	}
+	if !strings.HasPrefix(report.Differences[0], "status: recorded unhealthy, replayed healthy") {
+		t.Errorf("unexpected difference %q", report.Differences[0])
+	}
+
+	if _, err := ReplayCheck(context.Background(), &podLogCheck{mockHealthCheck: mockHealthCheck{name: "other"}}, rec); err == nil {
+		t.Error("expected an error replaying a recording of another check")
+	}
+}
+
+func TestCheckRecorder_ReplayFromFile(t *testing.T) {
+	recorder := NewCheckRecorder(0)
+	check := &podLogCheck{mockHealthCheck: mockHealthCheck{name: "pods"}}
+	rec, _, err := recorder.Run(context.Background(), check, recordedClient(t, recorder), "")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatalf("failed to encode recording: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "pods.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write recording: %v", err)
+	}
+
+	loaded, err := LoadRecording(path)
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+	report, err := ReplayCheck(context.Background(), check, *loaded)
+	if err != nil {
+		t.Fatalf("ReplayCheck() error = %v", err)
+	}
+	if !report.Matches {
+		t.Errorf("expected a loaded recording to replay identically, got %v", report.Differences)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"id":"x"}`), 0600); err != nil {
+		t.Fatalf("failed to write recording: %v", err)
+	}
+	if _, err := LoadRecording(path); err == nil {
+		t.Error("expected an error for a recording without a check")
+	}
+}
+
+func TestRecordingClient_UnrecordedRequest(t *testing.T) {
+	client, err := Recording{Check: "pods"}.Client()
+	if err != nil {
+		t.Fatalf("Client() error = %v", err)
+	}
+	_, err = client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "was not recorded") {
+		t.Errorf("expected a not recorded error, got %v", err)
+	}
+}
+
+func TestCheckRecorder_ListGet(t *testing.T) {
+	recorder := NewCheckRecorder(2)
+	client := fake.NewSimpleClientset()
+	for _, name := range []string{"a", "a", "a", "b"} {
+		if _, _, err := recorder.Run(context.Background(), &mockHealthCheck{name: name}, client, ""); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	all := recorder.List("")
+	if len(all) != 3 {
+		t.Fatalf("expected 3 recordings after trimming, got %d", len(all))
+	}
+	if all[0].ID != "b-4" || all[1].ID != "a-3" || all[2].ID != "a-2" {
+		t.Errorf("expected newest first, got %s, %s, %s", all[0].ID, all[1].ID, all[2].ID)
+	}
+	if got := recorder.List("a"); len(got) != 2 {
+		t.Errorf("expected 2 recordings of check a, got %d", len(got))
+	}
+
+	if _, err := recorder.Get("a-1"); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("expected trimmed recording to be gone, got %v", err)
+	}
+	if rec, err := recorder.Get("a-3"); err != nil || rec.Check != "a" {
+		t.Errorf("Get(a-3) = %v, %v", rec, err)
+	}
+}
+
+func TestEngine_Recordings(t *testing.T) {
+	plain := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if plain.RecordingEnabled() || len(plain.GetRecordings("")) != 0 {
+		t.Error("expected recording to be disabled without a recorder")
+	}
+	if _, err := plain.ReplayRecording(context.Background(), "x-1"); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("expected ErrRecordingNotFound, got %v", err)
+	}
+
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		Recorder:   NewCheckRecorder(0),
+	})
+	engine.AddCheck(&mockHealthCheck{name: "mock"})
+	engine.runChecks()
+
+	recordings := engine.GetRecordings("mock")
+	if len(recordings) != 1 || recordings[0].Status != HealthStatusHealthy {
+		t.Fatalf("expected one healthy recording, got %+v", recordings)
+	}
+	report, err := engine.ReplayRecording(context.Background(), recordings[0].ID)
+	if err != nil {
+		t.Fatalf("ReplayRecording() error = %v", err)
+	}
+	if !report.Matches {
+		t.Errorf("expected replay to match, got %v", report.Differences)
+	}
+
+	if err := engine.RemoveCheck("mock"); err != nil {
+		t.Fatalf("RemoveCheck() error = %v", err)
+	}
+	if _, err := engine.ReplayRecording(context.Background(), recordings[0].ID); err == nil {
+		t.Error("expected an error replaying a check that is no longer registered")
+	}
+}
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// ErrRemediationNotFound is returned for an unknown remediation action ID
+	ErrRemediationNotFound = errors.New("remediation action not found")
+	// ErrRemediationNotApproved is returned when running an action nobody
+	// approved
+	ErrRemediationNotApproved = errors.New("remediation action not approved")
+	// ErrRemediationStatus is returned when an action's status doesn't allow
+	// the approval, rejection or run asked for
+	ErrRemediationStatus = errors.New("remediation action status does not allow this")
+)
+
+const (
+	// maxRemediations bounds the stored remediation actions; the oldest are
+	// dropped first
+	maxRemediations = 500
+	// maxRemediationRecords bounds the stored execution records
+	maxRemediationRecords = 1000
+	// remediationTimeout bounds running every command of an action
+	remediationTimeout = 5 * time.Minute
+)
+
+// RemediationStatus is where a remediation action stands in the approval
+// workflow
+type RemediationStatus string
+
+const (
+	RemediationPending  RemediationStatus = "pending"  // Suggested, waiting for approval
+	RemediationApproved RemediationStatus = "approved" // Approved to run once
+	RemediationRejected RemediationStatus = "rejected"
+	RemediationRunning  RemediationStatus = "running"
+	RemediationExecuted RemediationStatus = "executed" // Ran and succeeded
+	RemediationFailed   RemediationStatus = "failed"   // Ran and failed; can be approved again
+)
+
+// Remediation is a suggested remediation action and its review
+type Remediation struct {
+	Check       string               `json:"check"`
+	Action      ai.RemediationAction `json:"action"`
+	Status      RemediationStatus    `json:"status"`
+	SuggestedAt time.Time            `json:"suggested_at"`
+	ReviewedBy  string               `json:"reviewed_by,omitempty"` // Who approved or rejected it
+	ReviewedAt  *time.Time           `json:"reviewed_at,omitempty"`
+	Comment     string               `json:"comment,omitempty"`
+	LastRecord  string               `json:"last_record,omitempty"` // ID of the latest run or dry run
+}
+
+// remediationLog holds suggested actions and execution records, oldest first
+type remediationLog struct {
+	mu      sync.Mutex
+	actions []Remediation
+	records []ai.RemediationRecord
+}
+
+// find returns the index of an action; callers hold mu
+func (l *remediationLog) find(actionID string) int {
+	for i := len(l.actions) - 1; i >= 0; i-- {
+		if l.actions[i].Action.ID == actionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// addRecord stores an execution record; callers hold mu
+func (l *remediationLog) addRecord(record ai.RemediationRecord) {
+	l.records = append(l.records, record)
+	if excess := len(l.records) - maxRemediationRecords; excess > 0 {
+		l.records = l.records[excess:]
+	}
+}
+
+// storeRemediations saves actions suggested for a check, pending approval
+func (e *Engine) storeRemediations(check string, actions []ai.RemediationAction) {
+	now := time.Now()
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+	for _, action := range actions {
+		if e.remediations.find(action.ID) >= 0 {
+			continue
+		}
+		e.remediations.actions = append(e.remediations.actions, Remediation{
+			Check:       check,
+			Action:      action,
+			Status:      RemediationPending,
+			SuggestedAt: now,
+		})
+	}
+	if excess := len(e.remediations.actions) - maxRemediations; excess > 0 {
+		e.remediations.actions = e.remediations.actions[excess:]
+	}
+}
+
+// GetRemediations returns stored remediation actions for a check with a
+// status, oldest first; empty filters match every action
+func (e *Engine) GetRemediations(check string, status RemediationStatus) []Remediation {
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+
+	remediations := make([]Remediation, 0)
+	for _, remediation := range e.remediations.actions {
+		if (check == "" || remediation.Check == check) && (status == "" || remediation.Status == status) {
+			remediations = append(remediations, remediation)
+		}
+	}
+	return remediations
+}
+
+// GetRemediation returns a stored remediation action
+func (e *Engine) GetRemediation(actionID string) (Remediation, error) {
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+	i := e.remediations.find(actionID)
+	if i < 0 {
+		return Remediation{}, fmt.Errorf("%w: %s", ErrRemediationNotFound, actionID)
+	}
+	return e.remediations.actions[i], nil
+}
+
+// ApproveRemediation approves a pending or failed action to run once
+func (e *Engine) ApproveRemediation(actionID, reviewer, comment string) (Remediation, error) {
+	if e.readOnly {
+		return Remediation{}, fmt.Errorf("cannot approve remediation %s: %w", actionID, ErrReadOnly)
+	}
+	return e.reviewRemediation(actionID, reviewer, comment, RemediationApproved, RemediationPending, RemediationFailed)
+}
+
+// RejectRemediation rejects an action that hasn't run, or failed
+func (e *Engine) RejectRemediation(actionID, reviewer, comment string) (Remediation, error) {
+	return e.reviewRemediation(actionID, reviewer, comment, RemediationRejected, RemediationPending, RemediationApproved, RemediationFailed)
+}
+
+// reviewRemediation moves an action in one of the from statuses to a
+// reviewed status
+func (e *Engine) reviewRemediation(actionID, reviewer, comment string, to RemediationStatus, from ...RemediationStatus) (Remediation, error) {
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+
+	i := e.remediations.find(actionID)
+	if i < 0 {
+		return Remediation{}, fmt.Errorf("%w: %s", ErrRemediationNotFound, actionID)
+	}
+	remediation := &e.remediations.actions[i]
+	allowed := false
+	for _, status := range from {
+		allowed = allowed || remediation.Status == status
+	}
+	if !allowed {
+		return Remediation{}, fmt.Errorf("%w: %s is %s", ErrRemediationStatus, actionID, remediation.Status)
+	}
+
+	now := time.Now()
+	remediation.Status = to
+	remediation.ReviewedBy = reviewer
+	remediation.ReviewedAt = &now
+	remediation.Comment = comment
+	klog.Infof("Remediation %s for %s %s by %s", actionID, remediation.Check, to, reviewer)
+	return *remediation, nil
+}
+
+// GetRemediationRecords returns the execution records of an action, or of
+// every action, oldest first
+func (e *Engine) GetRemediationRecords(actionID string) []ai.RemediationRecord {
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+
+	records := make([]ai.RemediationRecord, 0)
+	for _, record := range e.remediations.records {
+		if actionID == "" || record.Action.ID == actionID {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// restoreRemediationRecords adds execution records from exported state,
+// skipping those already stored
+func (e *Engine) restoreRemediationRecords(records []ai.RemediationRecord) {
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+
+	stored := make(map[string]bool, len(e.remediations.records))
+	for _, record := range e.remediations.records {
+		stored[record.ID] = true
+	}
+	for _, record := range records {
+		if !stored[record.ID] {
+			e.remediations.addRecord(record)
+		}
+	}
+}
+
+// ExecuteRemediation runs a stored remediation action through the kubectl
+// executor, or dry-runs it. Only approved actions run for real, once per
+// approval; dry runs need no approval. Every run is recorded with its
+// output and rollback commands.
+func (e *Engine) ExecuteRemediation(actionID string, dryRun bool) (*ai.RemediationRecord, error) {
+	if e.readOnly && !dryRun {
+		return nil, fmt.Errorf("cannot execute remediation %s: %w", actionID, ErrReadOnly)
+	}
+	if e.remediationEngine == nil {
+		return nil, fmt.Errorf("remediation engine not enabled")
+	}
+
+	e.remediations.mu.Lock()
+	i := e.remediations.find(actionID)
+	if i < 0 {
+		e.remediations.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrRemediationNotFound, actionID)
+	}
+	remediation := e.remediations.actions[i]
+	switch {
+	case remediation.Status == RemediationRejected || remediation.Status == RemediationRunning:
+		e.remediations.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is %s", ErrRemediationStatus, actionID, remediation.Status)
+	case !dryRun && remediation.Status != RemediationApproved:
+		e.remediations.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s is %s", ErrRemediationNotApproved, actionID, remediation.Status)
+	case !dryRun:
+		// Take the approval so the action can't run twice concurrently
+		e.remediations.actions[i].Status = RemediationRunning
+	}
+	e.remediations.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(e.ctx, remediationTimeout)
+	defer cancel()
+	if dryRun {
+		klog.Infof("Dry-running remediation %s for %s", actionID, remediation.Check)
+	} else {
+		klog.Infof("Running remediation %s for %s, approved by %s", actionID, remediation.Check, remediation.ReviewedBy)
+	}
+	record, err := e.remediationEngine.ExecuteRemediation(ctx, remediation.Action, dryRun)
+
+	e.remediations.mu.Lock()
+	defer e.remediations.mu.Unlock()
+	if record != nil {
+		record.Problem = remediation.Check
+		if !dryRun {
+			record.ApprovedBy = remediation.ReviewedBy
+		}
+		e.remediations.addRecord(*record)
+	}
+	if i = e.remediations.find(actionID); i >= 0 {
+		if record != nil {
+			e.remediations.actions[i].LastRecord = record.ID
+		}
+		if !dryRun {
+			e.remediations.actions[i].Status = RemediationExecuted
+			if err != nil || record == nil || !record.Success {
+				e.remediations.actions[i].Status = RemediationFailed
+			}
+		}
+	}
+	return record, err
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// remediationKubectl answers replica reads with 3 and fails commands
+// containing fail
+type remediationKubectl struct {
+	commands []string
+}
+
+func (k *remediationKubectl) Execute(ctx context.Context, command string) (string, error) {
+	k.commands = append(k.commands, command)
+	switch {
+	case strings.Contains(command, "fail"):
+		return "error: not found", errors.New("command failed")
+	case strings.Contains(command, "jsonpath={.spec.replicas}"):
+		return "3", nil
+	}
+	return "done: " + command, nil
+}
+
+func (k *remediationKubectl) DryRun(ctx context.Context, command string) (string, error) {
+	return "dry run: " + command, nil
+}
+
+func TestEngine_RemediationWorkflow(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	kubectl := &remediationKubectl{}
+	engine.remediationEngine = ai.NewRemediationEngine(nil, kubectl, ai.NewDefaultSafetyChecker())
+	engine.storeRemediations("web-health", []ai.RemediationAction{
+		{ID: "scale-web", Type: "scale", Commands: []string{
+			"kubectl scale deployment/web --replicas=5 -n shop",
+			"kubectl rollout restart deployment/web -n shop",
+		}, Risk: ai.RiskMedium, Confidence: 0.9},
+		{ID: "scale-api", Type: "scale", Commands: []string{
+			"kubectl scale deployment api --replicas=4 --namespace=shop",
+			"kubectl rollout restart deployment/fail -n shop",
+		}, Risk: ai.RiskMedium, Confidence: 0.9},
+	})
+
+	if _, err := engine.ExecuteRemediation("scale-db", true); !errors.Is(err, ErrRemediationNotFound) {
+		t.Fatalf("expected ErrRemediationNotFound, got %v", err)
+	}
+	if _, err := engine.ExecuteRemediation("scale-web", false); !errors.Is(err, ErrRemediationNotApproved) {
+		t.Fatalf("expected an unapproved run to be refused, got %v", err)
+	}
+
+	// Dry runs need no approval and leave the action pending
+	record, err := engine.ExecuteRemediation("scale-web", true)
+	if err != nil || !record.Success || !record.DryRun || record.Steps[0].Output != "dry run: kubectl scale deployment/web --replicas=5 -n shop" {
+		t.Fatalf("expected a successful dry run, got %+v, %v", record, err)
+	}
+	if remediation, _ := engine.GetRemediation("scale-web"); remediation.Status != RemediationPending {
+		t.Errorf("status after a dry run = %s, want pending", remediation.Status)
+	}
+
+	if _, err := engine.ApproveRemediation("scale-web", "alice", "during the incident"); err != nil {
+		t.Fatalf("ApproveRemediation() error = %v", err)
+	}
+	kubectl.commands = nil
+	record, err = engine.ExecuteRemediation("scale-web", false)
+	if err != nil || !record.Success {
+		t.Fatalf("expected the approved action to run, got %+v, %v", record, err)
+	}
+	wantCommands := []string{
+		"kubectl get deployment/web -n shop -o jsonpath={.spec.replicas}",
+		"kubectl scale deployment/web --replicas=5 -n shop",
+		"kubectl rollout restart deployment/web -n shop",
+	}
+	if !reflect.DeepEqual(kubectl.commands, wantCommands) {
+		t.Errorf("ran %v, want %v", kubectl.commands, wantCommands)
+	}
+	if record.ApprovedBy != "alice" || record.Problem != "web-health" || len(record.Steps) != 2 || record.DryRun {
+		t.Errorf("unexpected record %+v", record)
+	}
+	if want := []string{"kubectl scale deployment/web --replicas=3 -n shop"}; !reflect.DeepEqual(record.RollbackCommands, want) {
+		t.Errorf("rollback commands = %v, want %v", record.RollbackCommands, want)
+	}
+
+	// The approval is used up
+	remediation, _ := engine.GetRemediation("scale-web")
+	if remediation.Status != RemediationExecuted || remediation.LastRecord != record.ID {
+		t.Errorf("unexpected remediation after the run %+v", remediation)
+	}
+	if _, err := engine.ExecuteRemediation("scale-web", false); !errors.Is(err, ErrRemediationNotApproved) {
+		t.Errorf("expected a second run to need approval, got %v", err)
+	}
+	if _, err := engine.RejectRemediation("scale-web", "bob", ""); !errors.Is(err, ErrRemediationStatus) {
+		t.Errorf("expected an executed action not to be rejected, got %v", err)
+	}
+
+	// A failed command rolls back those that ran, and the action can be
+	// approved again
+	if _, err := engine.ApproveRemediation("scale-api", "alice", ""); err != nil {
+		t.Fatalf("ApproveRemediation() error = %v", err)
+	}
+	kubectl.commands = nil
+	record, err = engine.ExecuteRemediation("scale-api", false)
+	if err == nil || record == nil || record.Success || record.Steps[1].Error == "" {
+		t.Fatalf("expected the second command to fail, got %+v, %v", record, err)
+	}
+	if last := kubectl.commands[len(kubectl.commands)-1]; last != "kubectl scale deployment api --replicas=3 --namespace=shop" {
+		t.Errorf("last command = %q, want the scale rolled back", last)
+	}
+	if remediation, _ := engine.GetRemediation("scale-api"); remediation.Status != RemediationFailed {
+		t.Errorf("status after the failure = %s, want failed", remediation.Status)
+	}
+	if _, err := engine.RejectRemediation("scale-api", "bob", "needs a different fix"); err != nil {
+		t.Errorf("RejectRemediation() error = %v", err)
+	}
+	if _, err := engine.ExecuteRemediation("scale-api", true); !errors.Is(err, ErrRemediationStatus) {
+		t.Errorf("expected a rejected action not to run, got %v", err)
+	}
+
+	if got := engine.GetRemediations("", RemediationRejected); len(got) != 1 || got[0].ReviewedBy != "bob" {
+		t.Errorf("rejected remediations = %+v", got)
+	}
+	if got := len(engine.GetRemediationRecords("scale-web")); got != 2 {
+		t.Errorf("scale-web has %d records, want 2", got)
+	}
+
+	// Records survive through exported state
+	restored := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if err := restored.ImportState(engine.ExportState()); err != nil {
+		t.Fatalf("ImportState() error = %v", err)
+	}
+	if err := restored.ImportState(engine.ExportState()); err != nil {
+		t.Fatalf("ImportState() error = %v", err)
+	}
+	if got, want := restored.GetRemediationRecords(""), engine.GetRemediationRecords(""); len(got) != len(want) || got[2].RollbackCommands[0] != want[2].RollbackCommands[0] {
+		t.Errorf("restored records %+v, want %+v", got, want)
+	}
+}
+
+func TestEngine_RemediationReadOnly(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), ReadOnly: true})
+	engine.storeRemediations("web-health", []ai.RemediationAction{{ID: "restart-web"}})
+	if _, err := engine.ApproveRemediation("restart-web", "alice", ""); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected approval to be refused, got %v", err)
+	}
+}
+
+// applyingSafety allows kubectl apply, which the default checker refuses
+type applyingSafety struct{}
+
+func (applyingSafety) IsSafe(action *ai.RemediationAction) (bool, string) { return true, "" }
+func (applyingSafety) ValidateCommand(command string) error               { return nil }
+
+func TestEngine_RemediationManualRollback(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	kubectl := &remediationKubectl{}
+	engine.remediationEngine = ai.NewRemediationEngine(nil, kubectl, applyingSafety{})
+	engine.storeRemediations("web-health", []ai.RemediationAction{
+		{ID: "apply-web", Type: "apply", Commands: []string{
+			"kubectl apply -f web.yaml -n shop",
+			"kubectl rollout restart deployment/fail -n shop",
+		}, Risk: ai.RiskMedium, Confidence: 0.9},
+	})
+	if _, err := engine.ApproveRemediation("apply-web", "alice", ""); err != nil {
+		t.Fatalf("ApproveRemediation() error = %v", err)
+	}
+
+	// The failure rolls nothing back: deleting what was applied could remove
+	// objects that existed before
+	record, err := engine.ExecuteRemediation("apply-web", false)
+	if err == nil || record == nil || record.Success {
+		t.Fatalf("expected the restart to fail, got %+v, %v", record, err)
+	}
+	want := []string{"kubectl apply -f web.yaml -n shop", "kubectl rollout restart deployment/fail -n shop"}
+	if !reflect.DeepEqual(kubectl.commands, want) {
+		t.Errorf("ran %v, want %v", kubectl.commands, want)
+	}
+	if len(record.RollbackCommands) != 0 || !record.Steps[0].ManualRollback || record.Steps[1].ManualRollback {
+		t.Errorf("expected the apply marked for manual rollback, got %+v", record)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// DetailRestriction holds the RestrictedCheck of a result whose check is
+// disabled for lack of permissions
+const DetailRestriction = "restriction"
+
+// DefaultPermissionRecheckInterval is how often a restricted check is run
+// again to see whether it has been granted the permissions it lacked
+const DefaultPermissionRecheckInterval = 10 * time.Minute
+
+// RestrictedCheck is a check disabled because the API server refused it
+// access, e.g. a ServiceAccount without access to nodes. It isn't run again
+// until NextRecheck, and is re-enabled once it runs without being refused.
+type RestrictedCheck struct {
+	Check       string    `json:"check"`
+	Reason      string    `json:"reason"` // The API server's refusal
+	Since       time.Time `json:"since"`
+	LastChecked time.Time `json:"last_checked"`
+	NextRecheck time.Time `json:"next_recheck"`
+}
+
+// checkRestrictions holds the restricted checks by name
+type checkRestrictions struct {
+	mu       sync.Mutex
+	interval time.Duration
+	checks   map[string]RestrictedCheck
+}
+
+// IsPermissionError reports whether a check failed because the API server
+// refused KubePulse access to something it reads
+func IsPermissionError(err error) bool {
+	return apierrors.IsForbidden(err)
+}
+
+// restrictedResult returns the result reported instead of running a
+// restricted check, and whether the check should be skipped because its
+// recheck isn't due
+func (e *Engine) restrictedResult(name string, now time.Time) (CheckResult, bool) {
+	e.restrictions.mu.Lock()
+	restriction, ok := e.restrictions.checks[name]
+	e.restrictions.mu.Unlock()
+	if !ok || !now.Before(restriction.NextRecheck) {
+		return CheckResult{}, false
+	}
+	return restriction.result(now), true
+}
+
+// restrict disables a check the API server refused, or pushes back the
+// recheck of one already restricted
+func (e *Engine) restrict(name string, err error, now time.Time) RestrictedCheck {
+	e.restrictions.mu.Lock()
+	defer e.restrictions.mu.Unlock()
+
+	restriction, existed := e.restrictions.checks[name]
+	if !existed {
+		restriction = RestrictedCheck{Check: name, Since: now}
+		klog.Warningf("Check %s disabled: KubePulse lacks permissions it needs, rechecking every %v: %v", name, e.restrictions.interval, err)
+	}
+	restriction.Reason = err.Error()
+	restriction.LastChecked = now
+	restriction.NextRecheck = now.Add(e.restrictions.interval)
+	if e.restrictions.checks == nil {
+		e.restrictions.checks = make(map[string]RestrictedCheck)
+	}
+	e.restrictions.checks[name] = restriction
+	return restriction
+}
+
+// unrestrict re-enables a restricted check that ran without being refused
+func (e *Engine) unrestrict(name string) {
+	e.restrictions.mu.Lock()
+	_, ok := e.restrictions.checks[name]
+	delete(e.restrictions.checks, name)
+	e.restrictions.mu.Unlock()
+	if ok {
+		klog.Infof("Check %s re-enabled: its permissions were granted", name)
+	}
+}
+
+// RestrictedChecks lists the checks disabled for lack of permissions, by name
+func (e *Engine) RestrictedChecks() []RestrictedCheck {
+	e.restrictions.mu.Lock()
+	restricted := make([]RestrictedCheck, 0, len(e.restrictions.checks))
+	for _, restriction := range e.restrictions.checks {
+		restricted = append(restricted, restriction)
+	}
+	e.restrictions.mu.Unlock()
+
+	sort.Slice(restricted, func(i, j int) bool { return restricted[i].Check < restricted[j].Check })
+	return restricted
+}
+
+// result is the result a restricted check reports
+func (r RestrictedCheck) result(now time.Time) CheckResult {
+	return CheckResult{
+		Name:      r.Check,
+		Status:    HealthStatusRestricted,
+		Message:   fmt.Sprintf("Check disabled: KubePulse lacks the permissions it needs, rechecking at %s", r.NextRecheck.Format(time.RFC3339)),
+		Details:   map[string]interface{}{DetailRestriction: r},
+		Timestamp: now,
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// forbiddenCheck is refused access to nodes until allowed
+type forbiddenCheck struct {
+	mockHealthCheck
+	allowed atomic.Bool
+	runs    atomic.Int32
+}
+
+func (c *forbiddenCheck) Check(ctx context.Context, client kubernetes.Interface) (CheckResult, error) {
+	c.runs.Add(1)
+	if !c.allowed.Load() {
+		err := apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("cannot list resource"))
+		return CheckResult{Name: c.name}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return CheckResult{Name: c.name, Status: HealthStatusHealthy, Timestamp: time.Now()}, nil
+}
+
+func TestIsPermissionError(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("denied"))
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"forbidden", forbidden, true},
+		{"wrapped", fmt.Errorf("failed to list nodes: %w", forbidden), true},
+		{"not found", apierrors.NewNotFound(schema.GroupResource{Resource: "nodes"}, "node-1"), false},
+		{"other", errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		if got := IsPermissionError(tt.err); got != tt.want {
+			t.Errorf("%s: IsPermissionError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_RestrictsForbiddenChecks(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset(), PermissionRecheckInterval: time.Hour})
+	nodes := &forbiddenCheck{mockHealthCheck: mockHealthCheck{name: "node-health"}}
+	pods := &countingCheck{mockHealthCheck: mockHealthCheck{name: "pod-health"}}
+	engine.AddCheck(nodes)
+	engine.AddCheck(pods)
+
+	engine.runChecks()
+	result, _ := engine.GetResult("node-health")
+	if result.Status != HealthStatusRestricted || result.Error != nil {
+		t.Fatalf("expected the check restricted, got %+v", result)
+	}
+	if _, ok := result.Details[DetailRestriction].(RestrictedCheck); !ok {
+		t.Errorf("expected the restriction in the details, got %+v", result.Details)
+	}
+	health := engine.GetClusterHealth("test")
+	if health.Status != HealthStatusHealthy || health.Score.Raw != 100 || len(health.Checks) != 2 {
+		t.Errorf("expected the restricted check left out of the score, got %s with %.1f", health.Status, health.Score.Raw)
+	}
+	if len(health.Restricted) != 1 || health.Restricted[0].Check != "node-health" || health.Restricted[0].Reason == "" {
+		t.Errorf("expected the restriction surfaced, got %+v", health.Restricted)
+	}
+	if errs := engine.checkErrors.Load(); errs != 0 {
+		t.Errorf("expected no check errors for a refusal, got %d", errs)
+	}
+
+	// Not run again until the recheck is due
+	engine.runChecks()
+	if runs := nodes.runs.Load(); runs != 1 {
+		t.Errorf("expected the restricted check skipped, got %d runs", runs)
+	}
+
+	// Granted permissions re-enable the check at its recheck
+	nodes.allowed.Store(true)
+	engine.restrictions.mu.Lock()
+	restriction := engine.restrictions.checks["node-health"]
+	restriction.NextRecheck = time.Now().Add(-time.Second)
+	engine.restrictions.checks["node-health"] = restriction
+	engine.restrictions.mu.Unlock()
+
+	engine.runChecks()
+	if result, _ := engine.GetResult("node-health"); result.Status != HealthStatusHealthy {
+		t.Errorf("expected the check re-enabled, got %+v", result)
+	}
+	if restricted := engine.RestrictedChecks(); len(restricted) != 0 {
+		t.Errorf("expected no restricted checks, got %+v", restricted)
+	}
+}
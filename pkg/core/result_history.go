@@ -0,0 +1,384 @@
+package core
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultHistoryRetention is how long check results are kept for time-travel
+// inspection when no retention is configured
+const DefaultHistoryRetention = 7 * 24 * time.Hour
+
+// ErrNoHistory is returned when no check results were recorded at or before
+// the requested time
+var ErrNoHistory = errors.New("no health history")
+
+// ResultHistory keeps every check result that changed a check's status or
+// message, so the cluster's health can be inspected as it was at any moment
+// within the retention window. With a path the history is appended to a
+// JSON lines file and reloaded on restart; a path ending in .gz is written
+// gzip-compressed.
+type ResultHistory struct {
+	path      string
+	retention time.Duration
+	shedding  bool // Retention is cut while load is shed
+
+	mu      sync.RWMutex
+	results map[string][]CheckResult // Per check, oldest first
+	file    *os.File
+	gz      *gzip.Writer // Set for compressed histories
+}
+
+// NewResultHistory creates a result history, loading and compacting the
+// file at path when one is given
+func NewResultHistory(path string, retention time.Duration) (*ResultHistory, error) {
+	if retention <= 0 {
+		retention = DefaultHistoryRetention
+	}
+	h := &ResultHistory{
+		path:      path,
+		retention: retention,
+		results:   make(map[string][]CheckResult),
+	}
+	if path == "" {
+		return h, nil
+	}
+
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	h.prune(time.Now())
+	if err := h.compact(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 - path comes from configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to open health history: %w", err)
+	}
+	h.file = file
+	if h.compressed() {
+		// Each run appends a new gzip member, which readers concatenate
+		h.gz = gzip.NewWriter(file)
+	}
+	return h, nil
+}
+
+// compressed reports whether the history file is gzip-compressed
+func (h *ResultHistory) compressed() bool {
+	return strings.HasSuffix(h.path, ".gz")
+}
+
+// load reads recorded results from the history file, skipping lines it
+// can't decode
+func (h *ResultHistory) load() error {
+	file, err := os.Open(h.path) // #nosec G304 - path comes from configuration
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read health history: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var reader io.Reader = file
+	if h.compressed() {
+		gz, err := gzip.NewReader(file)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read health history: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+
+	skipped := 0
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var result CheckResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil || result.Name == "" {
+			skipped++
+			continue
+		}
+		h.results[result.Name] = append(h.results[result.Name], result)
+	}
+	if err := scanner.Err(); errors.Is(err, io.ErrUnexpectedEOF) {
+		// The last gzip member is unterminated when KubePulse didn't exit
+		// cleanly; every record flushed before that is still read
+		klog.Warningf("Health history %s ends in an unterminated gzip stream", h.path)
+	} else if err != nil {
+		return fmt.Errorf("failed to read health history: %w", err)
+	}
+	if skipped > 0 {
+		klog.Warningf("Skipped %d unreadable entries in health history %s", skipped, h.path)
+	}
+
+	for name := range h.results {
+		results := h.results[name]
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Timestamp.Before(results[j].Timestamp)
+		})
+	}
+	return nil
+}
+
+// compact rewrites the history file with only the retained results
+func (h *ResultHistory) compact() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for health history: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), ".history-*")
+	if err != nil {
+		return fmt.Errorf("failed to compact health history: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	var gz *gzip.Writer
+	var writer *bufio.Writer
+	if h.compressed() {
+		gz = gzip.NewWriter(tmp)
+		writer = bufio.NewWriter(gz)
+	} else {
+		writer = bufio.NewWriter(tmp)
+	}
+	encoder := json.NewEncoder(writer)
+	for _, name := range h.checkNames() {
+		for _, result := range h.results[name] {
+			if err := encoder.Encode(result); err != nil {
+				_ = tmp.Close()
+				return fmt.Errorf("failed to compact health history: %w", err)
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to compact health history: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to compact health history: %w", err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to compact health history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), h.path); err != nil {
+		return fmt.Errorf("failed to compact health history: %w", err)
+	}
+	return nil
+}
+
+// Record adds a result when it changes the check's status or message;
+// repeats of the latest result are already represented by it
+func (h *ResultHistory) Record(result CheckResult) {
+	if result.Timestamp.IsZero() {
+		result.Timestamp = time.Now()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	results := h.results[result.Name]
+	if n := len(results); n > 0 {
+		last := results[n-1]
+		if last.Status == result.Status && last.Message == result.Message {
+			return
+		}
+		if result.Timestamp.Before(last.Timestamp) {
+			return
+		}
+	}
+	h.results[result.Name] = append(results, result)
+	h.prune(result.Timestamp)
+
+	if h.file != nil {
+		data, err := json.Marshal(result)
+		if err == nil && h.gz != nil {
+			// Flush so each record survives a crash
+			if _, err = h.gz.Write(append(data, '\n')); err == nil {
+				err = h.gz.Flush()
+			}
+		} else if err == nil {
+			_, err = h.file.Write(append(data, '\n'))
+		}
+		if err != nil {
+			klog.Warningf("Failed to persist %s result to health history: %v", result.Name, err)
+		}
+	}
+}
+
+// prune drops results older than the retention window, keeping the last
+// one before it so each check's state at the start of the window is known;
+// callers hold mu or own the history
+func (h *ResultHistory) prune(now time.Time) {
+	cutoff := now.Add(-h.window())
+	for name, results := range h.results {
+		keep := 0
+		for keep+1 < len(results) && results[keep+1].Timestamp.Before(cutoff) {
+			keep++
+		}
+		if keep > 0 {
+			h.results[name] = append([]CheckResult(nil), results[keep:]...)
+		}
+	}
+}
+
+// window is the retention in effect; callers hold mu or own the history
+func (h *ResultHistory) window() time.Duration {
+	if h.shedding {
+		return h.retention / loadSheddingRetentionDivisor
+	}
+	return h.retention
+}
+
+// shedRetention cuts the retention window while load is shed, dropping the
+// results outside it from memory; they stay in the file and are reloaded
+// on restart
+func (h *ResultHistory) shedRetention(active bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shedding = active
+	if active {
+		h.prune(time.Now())
+	}
+}
+
+// At returns the latest result of every check at or before at, sorted by
+// name, with the time each failing check started failing
+func (h *ResultHistory) At(at time.Time) ([]CheckResult, map[string]time.Time, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if oldest := time.Now().Add(-h.window()); at.Before(oldest) {
+		return nil, nil, fmt.Errorf("%w before %s: history is kept for %s", ErrNoHistory, oldest.Format(time.RFC3339), h.window())
+	}
+
+	results := make([]CheckResult, 0, len(h.results))
+	failingSince := make(map[string]time.Time)
+	for _, name := range h.checkNames() {
+		history := h.results[name]
+		i := sort.Search(len(history), func(i int) bool {
+			return history[i].Timestamp.After(at)
+		}) - 1
+		if i < 0 {
+			continue
+		}
+		results = append(results, history[i])
+
+		if history[i].Status != HealthStatusHealthy {
+			start := i
+			for start > 0 && history[start-1].Status != HealthStatusHealthy {
+				start--
+			}
+			failingSince[name] = history[start].Timestamp
+		}
+	}
+	if len(results) == 0 {
+		return nil, nil, fmt.Errorf("%w at %s", ErrNoHistory, at.Format(time.RFC3339))
+	}
+	return results, failingSince, nil
+}
+
+// Timeline returns a check's results from the one in effect at from up to
+// to, oldest first
+func (h *ResultHistory) Timeline(name string, from, to time.Time) []CheckResult {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	history := h.results[name]
+	start := sort.Search(len(history), func(i int) bool {
+		return history[i].Timestamp.After(from)
+	}) - 1
+	if start < 0 {
+		start = 0
+	}
+	end := sort.Search(len(history), func(i int) bool {
+		return history[i].Timestamp.After(to)
+	})
+	if start >= end {
+		return nil
+	}
+	return append([]CheckResult(nil), history[start:end]...)
+}
+
+// Oldest returns the timestamp of the oldest recorded result, or zero
+func (h *ResultHistory) Oldest() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var oldest time.Time
+	for _, results := range h.results {
+		if len(results) > 0 && (oldest.IsZero() || results[0].Timestamp.Before(oldest)) {
+			oldest = results[0].Timestamp
+		}
+	}
+	return oldest
+}
+
+// checkNames returns the recorded check names in order; callers hold mu
+func (h *ResultHistory) checkNames() []string {
+	names := make([]string, 0, len(h.results))
+	for name := range h.results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes the history file
+func (h *ResultHistory) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+	var err error
+	if h.gz != nil {
+		err = h.gz.Close()
+		h.gz = nil
+	}
+	if closeErr := h.file.Close(); err == nil {
+		err = closeErr
+	}
+	h.file = nil
+	return err
+}
+
+// historyTimeLayouts are the accepted timestamp forms besides RFC 3339; they
+// carry no zone and are read in the caller's location
+var historyTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseHistoryTime parses a timestamp for time-travel inspection, accepting
+// RFC 3339 or a zoneless date and time in loc
+func ParseHistoryTime(value string, loc *time.Location) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	for _, layout := range historyTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q: use RFC 3339 (2006-01-02T15:04:05Z07:00) or 2006-01-02T15:04", value)
+}
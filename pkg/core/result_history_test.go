@@ -0,0 +1,283 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResultHistory_At(t *testing.T) {
+	history, err := NewResultHistory("", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now().Add(-30 * time.Minute)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: at(0)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: at(1)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusDegraded, Message: "1 pod pending", Timestamp: at(5)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping", Timestamp: at(10)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: at(20)})
+	history.Record(CheckResult{Name: "node-health", Status: HealthStatusHealthy, Timestamp: at(8)})
+	history.Record(CheckResult{Name: "node-health", Status: HealthStatusUnhealthy, Timestamp: at(7)}) // Out of order
+
+	if got := len(history.results["pod-health"]); got != 4 {
+		t.Errorf("expected repeated results to be skipped, got %d entries", got)
+	}
+
+	tests := []struct {
+		name         string
+		at           time.Time
+		wantStatuses map[string]HealthStatus
+		wantSince    map[string]time.Time
+	}{
+		{
+			name:         "before node-health ran",
+			at:           at(2),
+			wantStatuses: map[string]HealthStatus{"pod-health": HealthStatusHealthy},
+			wantSince:    map[string]time.Time{},
+		},
+		{
+			name:         "during the incident",
+			at:           at(12),
+			wantStatuses: map[string]HealthStatus{"node-health": HealthStatusHealthy, "pod-health": HealthStatusUnhealthy},
+			wantSince:    map[string]time.Time{"pod-health": at(5)},
+		},
+		{
+			name:         "exactly at a change",
+			at:           at(20),
+			wantStatuses: map[string]HealthStatus{"node-health": HealthStatusHealthy, "pod-health": HealthStatusHealthy},
+			wantSince:    map[string]time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, failingSince, err := history.At(tt.at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantStatuses) {
+				t.Fatalf("expected %d results, got %+v", len(tt.wantStatuses), results)
+			}
+			for i, result := range results {
+				if i > 0 && results[i-1].Name > result.Name {
+					t.Errorf("expected results sorted by name, got %s before %s", results[i-1].Name, result.Name)
+				}
+				if result.Status != tt.wantStatuses[result.Name] {
+					t.Errorf("expected %s to be %s, got %s", result.Name, tt.wantStatuses[result.Name], result.Status)
+				}
+			}
+			if len(failingSince) != len(tt.wantSince) {
+				t.Errorf("expected failing since %v, got %v", tt.wantSince, failingSince)
+			}
+			for name, since := range tt.wantSince {
+				if !failingSince[name].Equal(since) {
+					t.Errorf("expected %s failing since %v, got %v", name, since, failingSince[name])
+				}
+			}
+		})
+	}
+
+	if _, _, err := history.At(start.Add(-time.Minute)); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory before the first result, got %v", err)
+	}
+	if _, _, err := history.At(time.Now().Add(-2 * time.Hour)); !errors.Is(err, ErrNoHistory) || !strings.Contains(err.Error(), "history is kept for 1h0m0s") {
+		t.Errorf("expected ErrNoHistory outside retention, got %v", err)
+	}
+}
+
+func TestResultHistory_Prune(t *testing.T) {
+	history, err := NewResultHistory("", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: now.Add(-3 * time.Hour)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: now.Add(-2 * time.Hour)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: now.Add(-10 * time.Minute)})
+
+	if got := history.results["pod-health"]; len(got) != 2 || got[0].Status != HealthStatusUnhealthy {
+		t.Fatalf("expected the last result before the window to be kept, got %+v", got)
+	}
+
+	// The state at the start of the window comes from the kept result
+	results, failingSince, err := history.At(now.Add(-30 * time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Status != HealthStatusUnhealthy || !failingSince["pod-health"].Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("unexpected state %+v since %v", results[0], failingSince)
+	}
+	if !history.Oldest().Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("unexpected oldest result %v", history.Oldest())
+	}
+}
+
+func TestResultHistory_Timeline(t *testing.T) {
+	history, err := NewResultHistory("", time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now().Add(-30 * time.Minute)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: at(0)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Timestamp: at(10)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: at(20)})
+
+	timeline := history.Timeline("pod-health", at(5), at(15))
+	if len(timeline) != 2 || !timeline[0].Timestamp.Equal(at(0)) || timeline[1].Status != HealthStatusUnhealthy {
+		t.Errorf("expected the result in effect at the start and the change within, got %+v", timeline)
+	}
+	if got := history.Timeline("pod-health", at(-10), at(-5)); got != nil {
+		t.Errorf("expected nothing before the first result, got %+v", got)
+	}
+	if got := history.Timeline("node-health", at(0), at(30)); got != nil {
+		t.Errorf("expected nothing for an unknown check, got %+v", got)
+	}
+}
+
+func TestResultHistory_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "results.jsonl")
+	now := time.Now().Truncate(time.Second)
+
+	history, err := NewResultHistory(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: now.Add(-3 * time.Hour)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusDegraded, Timestamp: now.Add(-2 * time.Hour)})
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping", Timestamp: now.Add(-5 * time.Minute)})
+	if err := history.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = file.WriteString("not json\n")
+	_ = file.Close()
+
+	reloaded, err := NewResultHistory(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = reloaded.Close() }()
+
+	results, _, err := reloaded.At(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Message != "2 pods crashlooping" {
+		t.Errorf("expected the persisted result, got %+v", results)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("expected the file to be compacted to 2 results, got %d lines:\n%s", lines, data)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("expected the history to be private, got %v", info.Mode().Perm())
+	}
+}
+
+func TestResultHistory_Compressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl.gz")
+	now := time.Now().Truncate(time.Second)
+
+	history, err := NewResultHistory(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: now.Add(-10 * time.Minute)})
+	if err := history.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A second run appends a gzip member and exits without closing it
+	history, err = NewResultHistory(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = history.Close() }()
+	history.Record(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping", Timestamp: now.Add(-5 * time.Minute)})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Fatalf("expected a gzip file, got %q", data)
+	}
+
+	reloaded, err := NewResultHistory(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = reloaded.Close() }()
+	if timeline := reloaded.Timeline("pod-health", now.Add(-time.Hour), now); len(timeline) != 2 || timeline[1].Message != "2 pods crashlooping" {
+		t.Errorf("expected both results from the compressed history, got %+v", timeline)
+	}
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-06-01T14:00:00Z", want: time.Date(2024, 6, 1, 14, 0, 0, 0, time.UTC)},
+		{value: "2024-06-01T14:00:00+01:00", want: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)},
+		{value: "2024-06-01T14:00", want: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{value: "2024-06-01 14:00:30", want: time.Date(2024, 6, 1, 12, 0, 30, 0, time.UTC)},
+		{value: "yesterday", wantErr: true},
+		{value: "2024-06-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseHistoryTime(tt.value, loc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHistoryTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("ParseHistoryTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_HealthAt(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: time.Now().Add(-20 * time.Minute)})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping", Timestamp: time.Now().Add(-10 * time.Minute)})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy, Timestamp: time.Now()})
+
+	at := time.Now().Add(-5 * time.Minute)
+	health, err := engine.HealthAt("prod", at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.ClusterName != "prod" || !health.Timestamp.Equal(at) {
+		t.Errorf("unexpected cluster health header %s at %v", health.ClusterName, health.Timestamp)
+	}
+	if health.Status != HealthStatusUnhealthy || len(health.Checks) != 1 || health.Checks[0].Message != "2 pods crashlooping" {
+		t.Errorf("expected the incident state, got %s with %+v", health.Status, health.Checks)
+	}
+	if current := engine.GetClusterHealth("prod"); current.Status != HealthStatusHealthy {
+		t.Errorf("expected the current health to be unaffected, got %s", current.Status)
+	}
+
+	if _, err := engine.HealthAt("prod", time.Now().Add(-time.Hour)); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory before any result, got %v", err)
+	}
+}
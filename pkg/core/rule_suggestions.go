@@ -0,0 +1,217 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// ErrRuleSuggestionNotFound is returned when applying a suggestion that was
+// never generated or has been superseded by a newer run
+var ErrRuleSuggestionNotFound = errors.New("rule suggestion not found")
+
+// ruleSuggestions holds the most recent suggestions so they can be applied by ID
+type ruleSuggestions struct {
+	mu          sync.Mutex
+	suggestions map[string]alerts.RuleSuggestion
+}
+
+// GetAlertRules lists the configured alert rules
+func (e *Engine) GetAlertRules() []alerts.RuleInfo {
+	return e.alertManager.Rules()
+}
+
+// UpsertAlertRule adds or replaces an alert rule from its spec
+func (e *Engine) UpsertAlertRule(spec alerts.RuleSpec) error {
+	rule, err := spec.Build()
+	if err != nil {
+		return err
+	}
+	e.alertManager.UpsertRule(rule)
+	klog.Infof("Alert rule %s updated", spec.Name)
+	return nil
+}
+
+// RemoveAlertRule deletes an alert rule by name
+func (e *Engine) RemoveAlertRule(name string) error {
+	if err := e.alertManager.RemoveRule(name); err != nil {
+		return err
+	}
+	klog.Infof("Alert rule %s removed", name)
+	return nil
+}
+
+// SuggestAlertRules proposes alert rule changes from the alert and failure
+// history in the window. Heuristics always run; when AI is enabled the
+// candidates and history are sent for review and the AI's suggestions take
+// precedence.
+func (e *Engine) SuggestAlertRules(ctx context.Context, window time.Duration) ([]alerts.RuleSuggestion, error) {
+	now := time.Now()
+	input := alerts.SuggestionInput{
+		Rules:    e.alertManager.Rules(),
+		Alerts:   e.alertManager.GetHistory(0),
+		Failures: make(map[string]int),
+		Window:   window,
+		Now:      now,
+	}
+	for _, check := range e.checks {
+		input.Checks = append(input.Checks, check.Name())
+	}
+	for _, change := range e.changes.Since(now.Add(-window)) {
+		if change.Kind == ChangeKindAlertFiring {
+			input.Failures[strings.TrimPrefix(change.Resource, "check/")]++
+		}
+	}
+
+	suggestions := alerts.SuggestRules(input)
+	if e.aiClient != nil {
+		reviewed, err := e.reviewRuleSuggestions(ctx, input, suggestions)
+		if err != nil {
+			klog.Warningf("AI rule suggestion review failed, using heuristics: %v", err)
+		} else {
+			suggestions = reviewed
+		}
+	}
+
+	e.ruleSuggestions.mu.Lock()
+	e.ruleSuggestions.suggestions = make(map[string]alerts.RuleSuggestion, len(suggestions))
+	for _, suggestion := range suggestions {
+		e.ruleSuggestions.suggestions[suggestion.ID] = suggestion
+	}
+	e.ruleSuggestions.mu.Unlock()
+
+	return suggestions, nil
+}
+
+// ApplyRuleSuggestion applies a previously generated suggestion through the
+// same path as the rules API
+func (e *Engine) ApplyRuleSuggestion(id string) (*alerts.RuleSuggestion, error) {
+	e.ruleSuggestions.mu.Lock()
+	suggestion, ok := e.ruleSuggestions.suggestions[id]
+	if ok {
+		delete(e.ruleSuggestions.suggestions, id)
+	}
+	e.ruleSuggestions.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRuleSuggestionNotFound, id)
+	}
+
+	var err error
+	if suggestion.Action == alerts.SuggestionRetireRule {
+		err = e.RemoveAlertRule(suggestion.Rule)
+	} else {
+		err = e.UpsertAlertRule(*suggestion.Proposed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply suggestion %s: %w", id, err)
+	}
+
+	e.RecordChange(Change{
+		Kind:     ChangeKindAlertRule,
+		Resource: "alert-rule/" + suggestion.Rule,
+		Message:  fmt.Sprintf("Applied %s suggestion: %s", suggestion.Action, suggestion.Reason),
+		To:       string(suggestion.Action),
+	})
+	return &suggestion, nil
+}
+
+// reviewRuleSuggestions asks the AI to refine the heuristic candidates. AI
+// suggestions that don't describe a valid change are dropped.
+func (e *Engine) reviewRuleSuggestions(ctx context.Context, input alerts.SuggestionInput, candidates []alerts.RuleSuggestion) ([]alerts.RuleSuggestion, error) {
+	fireCounts := make(map[string]int)
+	for _, alert := range input.Alerts {
+		if !alert.Timestamp.Before(input.Now.Add(-input.Window)) {
+			fireCounts[alert.Labels["rule"]]++
+		}
+	}
+
+	response, err := e.aiClient.Analyze(ctx, ai.AnalysisRequest{
+		Type: ai.AnalysisTypeOptimization,
+		Context: fmt.Sprintf(`Alert rule tuning for the last %s.
+Review the configured rules, how often each fired, checks that repeatedly failed, and the candidate suggestions.
+Propose threshold or cooldown adjustments for noisy rules, new rules for recurring failures with no rule, and rules to retire.
+Respond with JSON whose "context" object has a "rule_suggestions" array. Each entry has "action"
+(adjust_threshold, adjust_cooldown, add_rule or retire_rule), "rule", "reason", "confidence" (0-1) and,
+unless retiring, a "proposed" rule with name, check, status, reason, threshold, severity and cooldown.`, input.Window),
+		Data: map[string]interface{}{
+			"rules":       input.Rules,
+			"fire_counts": fireCounts,
+			"failures":    input.Failures,
+			"checks":      input.Checks,
+			"candidates":  candidates,
+		},
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := response.Context["rule_suggestions"]
+	if !ok {
+		return candidates, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode AI rule suggestions: %w", err)
+	}
+	var proposed []alerts.RuleSuggestion
+	if err := json.Unmarshal(data, &proposed); err != nil {
+		return nil, fmt.Errorf("failed to decode AI rule suggestions: %w", err)
+	}
+
+	existing := make(map[string]alerts.RuleSpec, len(input.Rules))
+	for _, rule := range input.Rules {
+		existing[strings.ToLower(rule.Name)] = rule.RuleSpec
+	}
+
+	merged := make(map[string]alerts.RuleSuggestion)
+	order := make([]string, 0)
+	add := func(suggestion alerts.RuleSuggestion) {
+		if _, seen := merged[suggestion.ID]; !seen {
+			order = append(order, suggestion.ID)
+		}
+		merged[suggestion.ID] = suggestion
+	}
+
+	for _, suggestion := range candidates {
+		add(suggestion)
+	}
+	for _, suggestion := range proposed {
+		current, exists := existing[strings.ToLower(suggestion.Rule)]
+		switch suggestion.Action {
+		case alerts.SuggestionRetireRule:
+			if !exists {
+				continue
+			}
+			suggestion.Proposed = nil
+		case alerts.SuggestionAdjustThreshold, alerts.SuggestionAdjustCooldown, alerts.SuggestionAddRule:
+			if suggestion.Proposed == nil || suggestion.Proposed.Validate() != nil {
+				continue
+			}
+			suggestion.Rule = suggestion.Proposed.Name
+			current, exists = existing[strings.ToLower(suggestion.Rule)]
+		default:
+			continue
+		}
+		if exists {
+			suggestion.Current = &current
+		}
+		suggestion.ID = alerts.SuggestionID(suggestion.Action, suggestion.Rule)
+		suggestion.Source = "ai"
+		add(suggestion)
+	}
+
+	result := make([]alerts.RuleSuggestion, 0, len(order))
+	for _, id := range order {
+		result = append(result, merged[id])
+	}
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DetailSchedulingFailures lists pending pods the scheduler can't place,
+// with the reasons each node was ruled out
+const DetailSchedulingFailures = "scheduling_failures"
+
+// DetailPendingReasons holds one sentence per unschedulable pod, such as
+// "pod ml/trainer-0 pending because no node has gpu=true"
+const DetailPendingReasons = "pending_reasons"
+
+// Reasons a node can't take a pending pod
+const (
+	SchedulingNodeSelector   = "node_selector"          // Node selector or required node affinity
+	SchedulingTaint          = "untolerated_taint"      // NoSchedule or NoExecute taint without a toleration
+	SchedulingInsufficient   = "insufficient_resources" // Requests exceed the node's free allocatable
+	SchedulingTopologySpread = "topology_spread"        // Reported by the scheduler
+	SchedulingNoNodes        = "no_nodes"               // The cluster has no schedulable nodes
+	SchedulingUnschedulable  = "node_unschedulable"     // Cordoned nodes
+	SchedulingOther          = "other"                  // Anything else the scheduler reported
+)
+
+// SchedulingFailure explains why a pending pod can't be scheduled
+type SchedulingFailure struct {
+	Namespace        string             `json:"namespace"`
+	Pod              string             `json:"pod"`
+	PendingSince     time.Time          `json:"pending_since"`
+	Reasons          []SchedulingReason `json:"reasons"`
+	SchedulerMessage string             `json:"scheduler_message,omitempty"` // The PodScheduled condition message
+}
+
+// SchedulingReason is one reason nodes were ruled out for a pod
+type SchedulingReason struct {
+	Kind   string `json:"kind"`             // One of the Scheduling* constants
+	Nodes  int    `json:"nodes"`            // Nodes ruled out for this reason
+	Total  int    `json:"total"`            // Nodes considered
+	Pool   string `json:"pool,omitempty"`   // Node pool, for insufficient resources
+	Detail string `json:"detail,omitempty"` // What was missing, e.g. gpu=true + zone=us-east-1a
+}
+
+// String describes the reason, e.g. "no node has gpu=true + zone=us-east-1a"
+func (r SchedulingReason) String() string {
+	switch r.Kind {
+	case SchedulingNodeSelector:
+		if r.Nodes == r.Total {
+			return "no node has " + r.Detail
+		}
+		return fmt.Sprintf("%d of %d node(s) lack %s", r.Nodes, r.Total, r.Detail)
+	case SchedulingTaint:
+		return fmt.Sprintf("%d of %d node(s) have untolerated taint %s", r.Nodes, r.Total, r.Detail)
+	case SchedulingInsufficient:
+		return fmt.Sprintf("%d node(s) in pool %s have insufficient %s", r.Nodes, r.Pool, r.Detail)
+	case SchedulingTopologySpread:
+		return "topology spread constraints can't be met: " + r.Detail
+	case SchedulingNoNodes:
+		return "the cluster has no nodes"
+	case SchedulingUnschedulable:
+		return fmt.Sprintf("%d of %d node(s) are cordoned", r.Nodes, r.Total)
+	}
+	return r.Detail
+}
+
+// String describes the failure for people and AI prompts, e.g. "pod ml/trainer-0
+// pending because no node has gpu=true + zone=us-east-1a"
+func (f SchedulingFailure) String() string {
+	reasons := make([]string, len(f.Reasons))
+	for i, reason := range f.Reasons {
+		reasons[i] = reason.String()
+	}
+	if len(reasons) == 0 {
+		return fmt.Sprintf("pod %s/%s pending: %s", f.Namespace, f.Pod, f.SchedulerMessage)
+	}
+	return fmt.Sprintf("pod %s/%s pending because %s", f.Namespace, f.Pod, strings.Join(reasons, "; "))
+}
+
+// SchedulingFailures returns the scheduling failures a result reports,
+// including results decoded from JSON
+func SchedulingFailures(result CheckResult) []SchedulingFailure {
+	switch failures := result.Details[DetailSchedulingFailures].(type) {
+	case []SchedulingFailure:
+		return failures
+	case []interface{}:
+		data, err := json.Marshal(failures)
+		if err != nil {
+			return nil
+		}
+		var decoded []SchedulingFailure
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return nil
+}
+
+// schedulingFailureLines describes a result's scheduling failures for AI
+// context
+func schedulingFailureLines(result CheckResult) []string {
+	failures := SchedulingFailures(result)
+	lines := make([]string, len(failures))
+	for i, failure := range failures {
+		lines[i] = failure.String()
+	}
+	return lines
+}
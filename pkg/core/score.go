@@ -0,0 +1,160 @@
+package core
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// A failure's blast radius factor starts at blastRadiusBase and grows by
+	// blastRadiusPerResource for each affected resource, up to 1
+	blastRadiusBase        = 0.5
+	blastRadiusPerResource = 0.05
+
+	// A brand new failure counts durationFactorBase; the factor grows
+	// linearly to 1 over durationFactorRamp
+	durationFactorBase = 0.75
+	durationFactorRamp = time.Hour
+)
+
+// ScoreContribution explains how one check affected the weighted health score
+type ScoreContribution struct {
+	Check             string       `json:"check"`
+	Status            HealthStatus `json:"status"`
+	Criticality       Criticality  `json:"criticality"`
+	Weight            float64      `json:"weight"`             // Criticality weight
+	Severity          float64      `json:"severity"`           // 0 when healthy, 1 when unhealthy
+	AffectedResources int          `json:"affected_resources"` // Resources the failure touches; at least 1 when failing
+	BlastRadius       float64      `json:"blast_radius"`       // 0.55 for one resource, 1 for ten or more
+	FailingSince      *time.Time   `json:"failing_since,omitempty"`
+	DurationFactor    float64      `json:"duration_factor"`         // 0.75 for a new failure, 1 after an hour
+	Penalty           float64      `json:"penalty"`                 // Points deducted from the weighted score
+	ChaosInduced      bool         `json:"chaos_induced,omitempty"` // The failure is attributed to a chaos experiment
+}
+
+// criticalityWeight returns a check's share of the weighted score
+func criticalityWeight(criticality Criticality) float64 {
+	switch criticality {
+	case CriticalityCritical:
+		return 4.0
+	case CriticalityHigh:
+		return 2.0
+	case CriticalityLow:
+		return 0.5
+	default:
+		return 1.0
+	}
+}
+
+// blastRadius scales a failure by how many resources it affects
+func blastRadius(affected int) float64 {
+	return math.Min(1, blastRadiusBase+blastRadiusPerResource*float64(affected))
+}
+
+// durationFactor scales a failure by how long it has lasted
+func durationFactor(failingFor time.Duration) float64 {
+	if failingFor <= 0 {
+		return durationFactorBase
+	}
+	ramp := math.Min(1, float64(failingFor)/float64(durationFactorRamp))
+	return durationFactorBase + (1-durationFactorBase)*ramp
+}
+
+// weightedScore computes the severity-aware health score:
+//
+//	penalty(check) = weight × severity × blast radius × duration factor
+//	score          = 100 × (1 − Σ penalty / Σ weight)
+//
+// weight comes from the check's criticality (critical 4, high 2, medium 1,
+// low 0.5), so one failing critical check costs as much as eight failing low
+// ones. failingSince holds when each failing check started failing. The
+// returned contributions report each check's penalty in score points,
+// largest first.
+func (e *Engine) weightedScore(results []CheckResult, failingSince map[string]time.Time, now time.Time) (float64, []ScoreContribution) {
+	contributions := make([]ScoreContribution, 0, len(results))
+	var totalWeight, totalPenalty float64
+
+	for _, result := range results {
+		weight := e.getWeight(result)
+		contribution := ScoreContribution{
+			Check:       result.Name,
+			Status:      result.Status,
+			Criticality: e.criticalityOf(result.Name),
+			Weight:      weight,
+			Severity:    1 - e.calculateScore(result),
+		}
+		if contribution.Severity > 0 {
+			contribution.AffectedResources = result.AffectedResources
+			if contribution.AffectedResources < 1 {
+				contribution.AffectedResources = 1
+			}
+			contribution.BlastRadius = blastRadius(contribution.AffectedResources)
+
+			var failingFor time.Duration
+			if since, ok := failingSince[result.Name]; ok {
+				since := since
+				contribution.FailingSince = &since
+				failingFor = now.Sub(since)
+			}
+			contribution.DurationFactor = durationFactor(failingFor)
+			contribution.Penalty = weight * contribution.Severity * contribution.BlastRadius * contribution.DurationFactor
+			contribution.ChaosInduced = ChaosInduced(result)
+		}
+
+		totalWeight += weight
+		totalPenalty += contribution.Penalty
+		contributions = append(contributions, contribution)
+	}
+
+	if totalWeight == 0 {
+		return 0, contributions
+	}
+	for i := range contributions {
+		contributions[i].Penalty = roundScore(contributions[i].Penalty / totalWeight * 100)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].Penalty != contributions[j].Penalty {
+			return contributions[i].Penalty > contributions[j].Penalty
+		}
+		return contributions[i].Check < contributions[j].Check
+	})
+	return (1 - totalPenalty/totalWeight) * 100, contributions
+}
+
+// roundScore rounds score points to two decimals for display
+func roundScore(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// criticalityOf returns the criticality of a registered check, medium when
+// the check is unknown
+func (e *Engine) criticalityOf(name string) Criticality {
+	for _, check := range e.checks {
+		if check.Name() == name {
+			return check.Criticality()
+		}
+	}
+	return CriticalityMedium
+}
+
+// trackFailure records when a check started failing and forgets it once the
+// check is healthy again; callers hold resultsMu
+func (e *Engine) trackFailure(result CheckResult) {
+	if result.Status == HealthStatusHealthy {
+		delete(e.failingSince, result.Name)
+		delete(e.diagnoses, result.Name)
+		return
+	}
+	if _, ok := e.failingSince[result.Name]; ok {
+		return
+	}
+	if e.failingSince == nil {
+		e.failingSince = make(map[string]time.Time)
+	}
+	since := result.Timestamp
+	if since.IsZero() {
+		since = time.Now()
+	}
+	e.failingSince[result.Name] = since
+}
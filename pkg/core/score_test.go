@@ -0,0 +1,143 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// criticalCheck is a mock check with a configurable criticality
+type criticalCheck struct {
+	mockHealthCheck
+	criticality Criticality
+}
+
+func (c *criticalCheck) Criticality() Criticality {
+	return c.criticality
+}
+
+func TestWeightedScore_Criticality(t *testing.T) {
+	newEngine := func() *Engine {
+		engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+		engine.AddCheck(&criticalCheck{mockHealthCheck{name: "nodes"}, CriticalityCritical})
+		for _, name := range []string{"low-1", "low-2", "low-3", "low-4", "low-5"} {
+			engine.AddCheck(&criticalCheck{mockHealthCheck{name: name}, CriticalityLow})
+		}
+		return engine
+	}
+
+	// One failing critical check
+	critical := newEngine()
+	critical.storeResult(CheckResult{Name: "nodes", Status: HealthStatusUnhealthy})
+	for _, name := range []string{"low-1", "low-2", "low-3", "low-4", "low-5"} {
+		critical.storeResult(CheckResult{Name: name, Status: HealthStatusHealthy})
+	}
+
+	// Five failing low-priority checks
+	low := newEngine()
+	low.storeResult(CheckResult{Name: "nodes", Status: HealthStatusHealthy})
+	for _, name := range []string{"low-1", "low-2", "low-3", "low-4", "low-5"} {
+		low.storeResult(CheckResult{Name: name, Status: HealthStatusUnhealthy})
+	}
+
+	criticalScore := critical.GetClusterHealth("a").Score
+	lowScore := low.GetClusterHealth("b").Score
+	// The raw score only counts failing checks, so it ranks the critical
+	// failure as the healthier cluster
+	if criticalScore.Raw <= lowScore.Raw {
+		t.Fatalf("expected the raw score to ignore criticality, got %.1f and %.1f", criticalScore.Raw, lowScore.Raw)
+	}
+	if criticalScore.Weighted >= lowScore.Weighted {
+		t.Errorf("expected one failing critical check (%.1f) to score below five failing low ones (%.1f)",
+			criticalScore.Weighted, lowScore.Weighted)
+	}
+
+	// weight 4 × severity 1 × blast radius 0.55 × duration 0.75 out of 6.5
+	want := 100 * (1 - 4*0.55*0.75/6.5)
+	if math.Abs(criticalScore.Weighted-want) > 0.01 {
+		t.Errorf("expected weighted score %.2f, got %.2f", want, criticalScore.Weighted)
+	}
+
+	breakdown := criticalScore.Breakdown
+	if len(breakdown) != 6 {
+		t.Fatalf("expected a contribution per check, got %d", len(breakdown))
+	}
+	top := breakdown[0]
+	if top.Check != "nodes" || top.Criticality != CriticalityCritical || top.Weight != 4 || top.AffectedResources != 1 {
+		t.Errorf("unexpected top contribution %+v", top)
+	}
+	if math.Abs(top.Penalty-(100-want)) > 0.01 {
+		t.Errorf("expected penalty %.2f, got %.2f", 100-want, top.Penalty)
+	}
+	if top.FailingSince == nil {
+		t.Error("expected the failure start to be reported")
+	}
+	for _, contribution := range breakdown[1:] {
+		if contribution.Penalty != 0 || contribution.FailingSince != nil {
+			t.Errorf("expected healthy check %s to cost nothing, got %+v", contribution.Check, contribution)
+		}
+	}
+}
+
+func TestWeightedScore_BlastRadiusAndDuration(t *testing.T) {
+	now := time.Now()
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "narrow", Status: HealthStatusDegraded, AffectedResources: 1, Timestamp: now})
+	engine.storeResult(CheckResult{Name: "wide", Status: HealthStatusDegraded, AffectedResources: 30, Timestamp: now})
+	engine.storeResult(CheckResult{Name: "old", Status: HealthStatusDegraded, AffectedResources: 1, Timestamp: now.Add(-2 * time.Hour)})
+
+	_, breakdown := engine.weightedScore([]CheckResult{
+		{Name: "narrow", Status: HealthStatusDegraded, AffectedResources: 1},
+		{Name: "wide", Status: HealthStatusDegraded, AffectedResources: 30},
+		{Name: "old", Status: HealthStatusDegraded, AffectedResources: 1},
+	}, engine.failingSince, now)
+
+	byCheck := make(map[string]ScoreContribution)
+	for _, contribution := range breakdown {
+		byCheck[contribution.Check] = contribution
+	}
+	if byCheck["wide"].BlastRadius != 1 || byCheck["narrow"].BlastRadius != 0.55 {
+		t.Errorf("unexpected blast radius: wide %.2f, narrow %.2f", byCheck["wide"].BlastRadius, byCheck["narrow"].BlastRadius)
+	}
+	if byCheck["old"].DurationFactor != 1 || byCheck["narrow"].DurationFactor != durationFactorBase {
+		t.Errorf("unexpected duration factor: old %.2f, narrow %.2f", byCheck["old"].DurationFactor, byCheck["narrow"].DurationFactor)
+	}
+	if breakdown[0].Check != "wide" || breakdown[2].Check != "narrow" {
+		t.Errorf("expected contributions ordered by penalty, got %s, %s, %s", breakdown[0].Check, breakdown[1].Check, breakdown[2].Check)
+	}
+}
+
+func TestTrackFailure(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	started := time.Now().Add(-10 * time.Minute)
+
+	engine.storeResult(CheckResult{Name: "pods", Status: HealthStatusDegraded, Timestamp: started})
+	engine.storeResult(CheckResult{Name: "pods", Status: HealthStatusUnhealthy, Timestamp: time.Now()})
+	if since := engine.failingSince["pods"]; !since.Equal(started) {
+		t.Errorf("expected the failure to date from its first failing result, got %v", since)
+	}
+
+	engine.storeResult(CheckResult{Name: "pods", Status: HealthStatusHealthy, Timestamp: time.Now()})
+	if _, ok := engine.failingSince["pods"]; ok {
+		t.Error("expected recovery to clear the failure start")
+	}
+}
+
+func TestDurationFactor(t *testing.T) {
+	tests := []struct {
+		failingFor time.Duration
+		expected   float64
+	}{
+		{0, 0.75},
+		{30 * time.Minute, 0.875},
+		{time.Hour, 1},
+		{24 * time.Hour, 1},
+	}
+	for _, tt := range tests {
+		if got := durationFactor(tt.failingFor); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("durationFactor(%v) = %f, want %f", tt.failingFor, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+)
+
+// The error budget of each SLO with a ratio SLI is reported as a check
+// result named SLOCheckPrefix + SLO name: unhealthy while a critical
+// burn-rate alert fires and degraded while a warning one does. Rules on it
+// alert through the alert manager like any failing check.
+const SLOCheckPrefix = "slo:"
+
+// addSLO tracks an SLO and adds the rules alerting on its burn rates
+func (e *Engine) addSLO(definition slo.SLO) {
+	e.sloTracker.AddSLO(definition)
+	if definition.Ratio() {
+		for _, rule := range alerts.NewSLOBurnRateRules(definition.Name, SLOCheckPrefix+definition.Name) {
+			e.alertManager.UpsertRule(rule)
+		}
+	}
+}
+
+// checkSLOs evaluates the burn rates of every SLO with a ratio SLI and
+// reports each as its check
+func (e *Engine) checkSLOs() {
+	statuses := e.sloTracker.Evaluate()
+	names := make([]string, 0, len(statuses))
+	for name, status := range statuses {
+		if status.SLO.Ratio() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		result := sloResult(statuses[name], time.Now())
+		e.storeResult(result)
+		e.processResult(result)
+	}
+}
+
+// sloResult reports an SLO's error budget and burn rates as a check result
+func sloResult(status *slo.SLOStatus, now time.Time) CheckResult {
+	result := CheckResult{
+		Name:      SLOCheckPrefix + status.SLO.Name,
+		Status:    HealthStatusHealthy,
+		Timestamp: now,
+		Details: map[string]interface{}{
+			"slo":          status.SLO.Name,
+			"target":       status.SLO.Target,
+			"sli":          status.CurrentValue,
+			"error_budget": status.ErrorBudget,
+			"events":       status.Events,
+		},
+		Metrics: []Metric{
+			{Name: "kubepulse_slo_sli", Value: status.CurrentValue, Unit: "percent"},
+			{Name: "kubepulse_slo_error_budget_remaining", Value: status.ErrorBudget, Unit: "percent"},
+		},
+		Confidence: 1,
+	}
+
+	windows := make(map[time.Duration]float64)
+	var firing []string
+	for _, burn := range status.BurnRates {
+		windows[burn.LongWindow] = burn.LongBurnRate
+		windows[burn.ShortWindow] = burn.ShortBurnRate
+		if !burn.Firing {
+			continue
+		}
+		firing = append(firing, fmt.Sprintf("%.1fx over %s and %.1fx over %s (alerting above %gx)",
+			burn.LongBurnRate, burn.LongWindow, burn.ShortBurnRate, burn.ShortWindow, burn.BurnRate))
+		switch {
+		case burn.Severity == slo.SeverityCritical:
+			result.Status = HealthStatusUnhealthy
+		case result.Status == HealthStatusHealthy:
+			result.Status = HealthStatusDegraded
+		}
+	}
+	for window, rate := range windows {
+		result.Metrics = append(result.Metrics, Metric{
+			Name:   "kubepulse_slo_burn_rate",
+			Value:  rate,
+			Labels: map[string]string{"window": window.String()},
+		})
+	}
+	sort.Slice(result.Metrics, func(i, j int) bool {
+		if result.Metrics[i].Name != result.Metrics[j].Name {
+			return result.Metrics[i].Name < result.Metrics[j].Name
+		}
+		return result.Metrics[i].Labels["window"] < result.Metrics[j].Labels["window"]
+	})
+	for i := range result.Metrics {
+		metric := &result.Metrics[i]
+		if metric.Labels == nil {
+			metric.Labels = map[string]string{}
+		}
+		metric.Labels["slo"] = status.SLO.Name
+		metric.Timestamp = now
+		metric.Type = MetricTypeGauge
+	}
+
+	switch {
+	case len(firing) > 0:
+		result.Message = fmt.Sprintf("Error budget burning at %s; %.0f%% left", strings.Join(firing, ", "), status.ErrorBudget)
+	case status.Events == 0:
+		result.Status = HealthStatusUnknown
+		result.Message = "No SLI events in the window yet"
+	default:
+		result.Message = fmt.Sprintf("SLI %.3f%% against a %g%% target; %.0f%% of the error budget left", status.CurrentValue, status.SLO.Target, status.ErrorBudget)
+	}
+	return result
+}
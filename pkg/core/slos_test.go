@@ -0,0 +1,65 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_SLOBurnRateAlerts(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient: fake.NewSimpleClientset(),
+		SLOs: []slo.SLO{
+			{Name: "web", SLI: "availability", Target: 99, Check: "web-health"},
+			{Name: "queue", SLI: "custom", Target: 50, Metrics: []string{"queue_ok"}},
+		},
+	})
+
+	engine.checkSLOs()
+	if result, ok := engine.GetResult(SLOCheckPrefix + "web"); !ok || result.Status != HealthStatusUnknown {
+		t.Fatalf("expected the SLO unknown before any runs, got %+v", result)
+	}
+	if _, ok := engine.GetResult(SLOCheckPrefix + "queue"); ok {
+		t.Error("expected no burn-rate check for a custom SLI")
+	}
+
+	// Every run of the check failing burns the budget 100x
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		failing := CheckResult{Name: "web-health", Status: HealthStatusUnhealthy, Timestamp: now.Add(-time.Duration(i) * time.Second)}
+		engine.storeResult(failing)
+		engine.processResult(failing)
+	}
+	engine.checkSLOs()
+
+	result, ok := engine.GetResult(SLOCheckPrefix + "web")
+	if !ok || result.Status != HealthStatusUnhealthy {
+		t.Fatalf("expected the SLO unhealthy while its budget burns, got %+v", result)
+	}
+	burnRates := 0
+	for _, metric := range result.Metrics {
+		if metric.Labels["slo"] != "web" {
+			t.Errorf("expected %s labelled with the SLO, got %v", metric.Name, metric.Labels)
+		}
+		if metric.Name == "kubepulse_slo_burn_rate" {
+			burnRates++
+		}
+	}
+	if burnRates != 5 {
+		t.Errorf("expected a burn rate for each of 5 distinct windows, got %d", burnRates)
+	}
+	fired := false
+	for _, alert := range engine.alertManager.GetHistory(10) {
+		fired = fired || alert.Name == "slo-web-burn-critical"
+	}
+	if !fired {
+		t.Error("expected the critical burn-rate alert fired")
+	}
+
+	status, ok := engine.GetSLOStatus("web")
+	if !ok || status.Events != 5 || status.ErrorBudget != 0 || len(status.BurnRates) != 3 {
+		t.Errorf("expected the budget spent by 5 failing runs, got %+v", status)
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/ml"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"k8s.io/klog/v2"
+)
+
+// StateVersion is the format version of exported engine state
+const StateVersion = 1
+
+// State is what the engine learns or is told at runtime and would lose on
+// restart: alert rules, silences, escalation policies, learned metric
+// baselines, SLO definitions and remediation execution records
+type State struct {
+	Version            int                       `json:"version"`
+	AlertRules         []alerts.RuleSpec         `json:"alert_rules,omitempty"`
+	Silences           []alerts.Silence          `json:"silences,omitempty"`
+	EscalationPolicies []alerts.EscalationPolicy `json:"escalation_policies,omitempty"`
+	Baselines          map[string]ml.Baseline    `json:"baselines,omitempty"`
+	SLOs               []slo.SLO                 `json:"slos,omitempty"`
+	CheckMaintenance   []CheckMaintenance        `json:"check_maintenance,omitempty"`
+	Remediations       []ai.RemediationRecord    `json:"remediations,omitempty"`
+}
+
+// ExportState captures the engine's runtime state
+func (e *Engine) ExportState() State {
+	state := State{
+		Version:            StateVersion,
+		Silences:           e.alertManager.Silences(),
+		EscalationPolicies: e.alertManager.EscalationPolicies(),
+		Baselines:          e.anomalyEngine.Baselines(),
+		SLOs:               e.sloTracker.SLOs(),
+		CheckMaintenance:   e.GetCheckMaintenance(),
+		Remediations:       e.GetRemediationRecords(""),
+	}
+	for _, rule := range e.alertManager.Rules() {
+		state.AlertRules = append(state.AlertRules, rule.RuleSpec)
+	}
+	return state
+}
+
+// ImportState applies exported state on top of the engine's current state.
+// Rules and policies replace those with the same name; silences and
+// maintenance windows that have already expired are skipped.
+func (e *Engine) ImportState(state State) error {
+	if state.Version > StateVersion {
+		return fmt.Errorf("state version %d is newer than supported version %d", state.Version, StateVersion)
+	}
+
+	// Policies first, since rules may name them
+	for _, policy := range state.EscalationPolicies {
+		if err := e.alertManager.AddEscalationPolicy(policy); err != nil {
+			return fmt.Errorf("failed to restore escalation policy: %w", err)
+		}
+	}
+	for _, spec := range state.AlertRules {
+		rule, err := spec.Build()
+		if err != nil {
+			return fmt.Errorf("failed to restore alert rule: %w", err)
+		}
+		e.alertManager.UpsertRule(rule)
+	}
+	for _, silence := range state.Silences {
+		remaining := time.Until(silence.Until)
+		switch {
+		case remaining <= 0:
+		case len(silence.Matchers) > 0:
+			if _, err := e.alertManager.AddSilence(silence); err != nil {
+				return fmt.Errorf("failed to restore silence %s: %w", silence.ID, err)
+			}
+		default:
+			e.alertManager.SilenceAlert(silence.Fingerprint, remaining)
+		}
+	}
+	now := time.Now()
+	e.maintenance.mu.Lock()
+	for _, window := range state.CheckMaintenance {
+		if !window.Until.After(now) {
+			continue
+		}
+		if e.maintenance.windows == nil {
+			e.maintenance.windows = make(map[string]CheckMaintenance)
+		}
+		e.maintenance.windows[window.Check] = window
+	}
+	e.maintenance.mu.Unlock()
+	e.anomalyEngine.RestoreBaselines(state.Baselines)
+	for _, definition := range state.SLOs {
+		e.addSLO(definition)
+	}
+	e.restoreRemediationRecords(state.Remediations)
+
+	klog.Infof("Restored %d alert rules, %d silences, %d escalation policies, %d baselines, %d SLOs, %d maintenance windows and %d remediation records",
+		len(state.AlertRules), len(state.Silences), len(state.EscalationPolicies), len(state.Baselines), len(state.SLOs), len(state.CheckMaintenance), len(state.Remediations))
+	return nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// StateRevision returns the revision of the cluster state, which changes
+// when a check changes status or a critical alert fires. AI insights carry
+// the revision they were computed from; older ones are stale.
+func (e *Engine) StateRevision() uint64 {
+	return e.stateRevision.Load()
+}
+
+// invalidateInsights moves the state revision on and drops the cached
+// kubectl output AI insights are built from, so analyses requested from
+// now on read the changed cluster instead of sharing a stale run
+func (e *Engine) invalidateInsights(reason string) {
+	revision := e.stateRevision.Add(1)
+	if e.toolCache != nil {
+		e.toolCache.Refresh(e.currentContext)
+	}
+	klog.V(2).Infof("AI insights invalidated at state revision %d: %s", revision, reason)
+}
+
+// observeFiredAlert measures evaluation latency and invalidates AI insights
+// when a critical alert fires
+func (e *Engine) observeFiredAlert(alert alerts.Alert) {
+	e.observeEvaluation(alert)
+	if alert.Severity == alerts.AlertSeverityCritical {
+		e.invalidateInsights("critical alert " + alert.Name + " fired")
+	}
+}
+
+// markStale flags a run computed from an older state revision
+func (e *Engine) markStale(run AnalysisRun) AnalysisRun {
+	run.Stale = run.StateRevision < e.StateRevision()
+	return run
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_StateRevision(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusDegraded})
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusDegraded})
+	if revision := engine.StateRevision(); revision != 0 {
+		t.Fatalf("expected no revision before a status change, got %d", revision)
+	}
+	engine.storeAIInsights("node-health", &ai.AnalysisResponse{Summary: "disk pressure"}, nil)
+
+	// A status transition makes insights stale and drops the old diagnosis
+	run := AnalysisRun{ID: "run-1", StateRevision: engine.StateRevision()}
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusUnhealthy})
+	if revision := engine.StateRevision(); revision != 1 {
+		t.Errorf("expected revision 1 after a status change, got %d", revision)
+	}
+	if diagnosis := engine.alertDiagnosis("node-health"); diagnosis != nil {
+		t.Errorf("expected the diagnosis of the degraded status dropped, got %+v", diagnosis)
+	}
+	if !engine.markStale(run).Stale {
+		t.Error("expected a run of the previous revision to be stale")
+	}
+	if health := engine.GetClusterHealth("test"); health.StateRevision != 1 {
+		t.Errorf("expected the health stamped with revision 1, got %d", health.StateRevision)
+	}
+
+	// So does a critical alert firing
+	engine.processResult(CheckResult{Name: "pod-health", Status: HealthStatusUnhealthy, Message: "2 pods crashlooping"})
+	if revision := engine.StateRevision(); revision != 2 {
+		t.Errorf("expected revision 2 after a critical alert, got %d", revision)
+	}
+	if engine.markStale(AnalysisRun{StateRevision: 2}).Stale {
+		t.Error("expected a run of the current revision to be fresh")
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"github.com/kubepulse/kubepulse/pkg/ml"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEngine_ExportImportState(t *testing.T) {
+	source := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if err := source.AddEscalationPolicy(alerts.EscalationPolicy{
+		Name:  "pages",
+		Steps: []alerts.EscalationStep{{Channel: "slack"}, {Channel: "pagerduty", After: 15 * time.Minute}},
+	}); err != nil {
+		t.Fatalf("AddEscalationPolicy() error = %v", err)
+	}
+	if err := source.UpsertAlertRule(alerts.RuleSpec{
+		Name:       "payments-down",
+		Check:      "service-health",
+		Severity:   alerts.AlertSeverityCritical,
+		Escalation: "pages",
+	}); err != nil {
+		t.Fatalf("UpsertAlertRule() error = %v", err)
+	}
+	source.alertManager.SilenceAlert("pod-health:Warning", time.Hour)
+	source.anomalyEngine.RestoreBaselines(map[string]ml.Baseline{
+		"pod_restarts": {Mean: 2, StdDev: 0.5, Count: 3, Window: []float64{1.5, 2, 2.5}},
+	})
+	source.addSLO(slo.SLO{Name: "api-availability", SLI: "availability", Target: 99.9, Window: 30 * 24 * time.Hour})
+	source.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+	if _, err := source.SetCheckMaintenance(CheckMaintenance{Check: "pod-health", Reason: "node upgrade", Owner: "platform",
+		Until: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("SetCheckMaintenance() error = %v", err)
+	}
+
+	// State survives a round trip through JSON, as it does in a backup
+	data, err := json.Marshal(source.ExportState())
+	if err != nil {
+		t.Fatalf("failed to encode state: %v", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("failed to decode state: %v", err)
+	}
+
+	if state.Version != StateVersion {
+		t.Errorf("expected version %d, got %d", StateVersion, state.Version)
+	}
+	if len(state.Silences) != 1 || len(state.EscalationPolicies) != 1 || len(state.Baselines) != 1 || len(state.SLOs) != 1 ||
+		len(state.CheckMaintenance) != 1 {
+		t.Fatalf("unexpected exported state: %+v", state)
+	}
+
+	target := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	if err := target.ImportState(state); err != nil {
+		t.Fatalf("ImportState() error = %v", err)
+	}
+	restored := target.ExportState()
+
+	if !hasRule(restored.AlertRules, "payments-down") {
+		t.Errorf("expected restored rule payments-down, got %+v", restored.AlertRules)
+	}
+	if len(restored.AlertRules) != len(state.AlertRules) {
+		t.Errorf("expected %d rules, got %d", len(state.AlertRules), len(restored.AlertRules))
+	}
+	if !reflect.DeepEqual(restored.EscalationPolicies, state.EscalationPolicies) {
+		t.Errorf("escalation policies differ: %+v", restored.EscalationPolicies)
+	}
+	if !reflect.DeepEqual(restored.Baselines, state.Baselines) {
+		t.Errorf("baselines differ: %+v", restored.Baselines)
+	}
+	if !reflect.DeepEqual(restored.SLOs, state.SLOs) {
+		t.Errorf("SLOs differ: %+v", restored.SLOs)
+	}
+	if len(restored.Silences) != 1 || restored.Silences[0].Fingerprint != "pod-health:Warning" {
+		t.Errorf("expected restored silence, got %+v", restored.Silences)
+	}
+	if len(restored.CheckMaintenance) != 1 || restored.CheckMaintenance[0].Owner != "platform" {
+		t.Errorf("expected restored maintenance, got %+v", restored.CheckMaintenance)
+	}
+}
+
+func TestEngine_ImportState(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        State
+		wantErr      bool
+		wantSilences int
+	}{
+		{"newer version", State{Version: StateVersion + 1}, true, 0},
+		{"invalid rule", State{Version: StateVersion, AlertRules: []alerts.RuleSpec{{Name: "broken"}}}, true, 0},
+		{"expired silence skipped", State{Version: StateVersion, Silences: []alerts.Silence{
+			{Fingerprint: "old", Until: time.Now().Add(-time.Minute)},
+			{Fingerprint: "current", Until: time.Now().Add(time.Hour)},
+		}}, false, 1},
+		{"matcher silence restored", State{Version: StateVersion, Silences: []alerts.Silence{
+			{ID: "silence-1", Matchers: map[string]string{"check": "pod-health"}, Until: time.Now().Add(time.Hour)},
+			{ID: "silence-2", Matchers: map[string]string{"check": "node-health"}, Until: time.Now().Add(-time.Minute)},
+		}}, false, 1},
+		{"invalid matcher silence", State{Version: StateVersion, Silences: []alerts.Silence{
+			{ID: "silence-1", Matchers: map[string]string{"pod": "web"}, Until: time.Now().Add(time.Hour)},
+		}}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+			err := engine.ImportState(tt.state)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := len(engine.ExportState().Silences); got != tt.wantSilences {
+				t.Errorf("expected %d silences, got %d", tt.wantSilences, got)
+			}
+		})
+	}
+}
+
+func hasRule(specs []alerts.RuleSpec, name string) bool {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return true
+		}
+	}
+	return false
+}
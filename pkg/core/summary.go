@@ -0,0 +1,259 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/slo"
+)
+
+// maxFailingChecks caps the number of failing checks in a dashboard summary
+const maxFailingChecks = 5
+
+// DashboardSummary is a single precomputed payload for the dashboard overview
+type DashboardSummary struct {
+	ClusterName  string                `json:"cluster_name"`
+	Status       HealthStatus          `json:"status"`
+	Score        HealthScore           `json:"score"`
+	TotalChecks  int                   `json:"total_checks"`
+	CheckCounts  map[HealthStatus]int  `json:"check_counts"`
+	TopFailing   []FailingCheck        `json:"top_failing"`
+	ActiveAlerts AlertCounts           `json:"active_alerts"`
+	AIHeadline   *AIHeadline           `json:"ai_headline,omitempty"`
+	SLOs         map[string]*SLOStatus `json:"slos,omitempty"`
+	StuckChecks  int                   `json:"stuck_checks"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+}
+
+// summaryCache holds the last computed summary and the state it was built from
+type summaryCache struct {
+	summary    DashboardSummary
+	generation uint64
+	cluster    string
+	valid      bool
+}
+
+// FailingCheck is a non-healthy check shown on the dashboard
+type FailingCheck struct {
+	Name        string       `json:"name"`
+	Status      HealthStatus `json:"status"`
+	Message     string       `json:"message"`
+	Criticality Criticality  `json:"criticality,omitempty"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// AlertCounts counts currently firing alerts by severity
+type AlertCounts struct {
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Total    int `json:"total"`
+}
+
+// AIHeadline is the most recent AI diagnosis across all checks
+type AIHeadline struct {
+	Check      string    `json:"check"`
+	Summary    string    `json:"summary"`
+	Severity   string    `json:"severity"`
+	Confidence float64   `json:"confidence"`
+	AnalyzedAt time.Time `json:"analyzed_at"`
+}
+
+// GetDashboardSummary returns the dashboard summary, recomputing it only when
+// check results or AI insights changed since the last call
+func (e *Engine) GetDashboardSummary(clusterName string) DashboardSummary {
+	generation := e.generation.Load()
+
+	e.summaryMu.Lock()
+	defer e.summaryMu.Unlock()
+
+	if e.summary.valid && e.summary.generation == generation && e.summary.cluster == clusterName {
+		return e.summary.summary
+	}
+
+	summary := e.buildDashboardSummary(clusterName)
+	e.summary = summaryCache{
+		summary:    summary,
+		generation: generation,
+		cluster:    clusterName,
+		valid:      true,
+	}
+	return summary
+}
+
+// buildDashboardSummary computes the dashboard summary from current results
+func (e *Engine) buildDashboardSummary(clusterName string) DashboardSummary {
+	health := e.GetClusterHealth(clusterName)
+
+	criticalities := make(map[string]Criticality, len(e.checks))
+	for _, check := range e.checks {
+		criticalities[check.Name()] = check.Criticality()
+	}
+
+	summary := DashboardSummary{
+		ClusterName: health.ClusterName,
+		Status:      health.Status,
+		Score:       health.Score,
+		TotalChecks: len(health.Checks),
+		CheckCounts: make(map[HealthStatus]int),
+		TopFailing:  make([]FailingCheck, 0),
+		StuckChecks: len(e.watchdog.Stuck()),
+		GeneratedAt: time.Now(),
+	}
+
+	for _, result := range health.Checks {
+		summary.CheckCounts[result.Status]++
+
+		switch e.getSeverity(result) {
+		case AlertSeverityCritical:
+			summary.ActiveAlerts.Critical++
+			summary.ActiveAlerts.Total++
+		case AlertSeverityWarning:
+			summary.ActiveAlerts.Warning++
+			summary.ActiveAlerts.Total++
+		}
+
+		if result.Status != HealthStatusHealthy {
+			summary.TopFailing = append(summary.TopFailing, FailingCheck{
+				Name:        result.Name,
+				Status:      result.Status,
+				Message:     result.Message,
+				Criticality: criticalities[result.Name],
+				Timestamp:   result.Timestamp,
+			})
+		}
+
+		if headline := aiHeadline(result); headline != nil {
+			if summary.AIHeadline == nil || headline.AnalyzedAt.After(summary.AIHeadline.AnalyzedAt) {
+				summary.AIHeadline = headline
+			}
+		}
+	}
+
+	sort.Slice(summary.TopFailing, func(i, j int) bool {
+		a, b := summary.TopFailing[i], summary.TopFailing[j]
+		if statusRank(a.Status) != statusRank(b.Status) {
+			return statusRank(a.Status) > statusRank(b.Status)
+		}
+		if criticalityRank(a.Criticality) != criticalityRank(b.Criticality) {
+			return criticalityRank(a.Criticality) > criticalityRank(b.Criticality)
+		}
+		return a.Name < b.Name
+	})
+	if len(summary.TopFailing) > maxFailingChecks {
+		summary.TopFailing = summary.TopFailing[:maxFailingChecks]
+	}
+
+	if slos := e.GetSLOStatuses(); len(slos) > 0 {
+		summary.SLOs = slos
+	}
+
+	return summary
+}
+
+// aiHeadline extracts the stored AI diagnosis from a result, if any
+func aiHeadline(result CheckResult) *AIHeadline {
+	if result.Details == nil {
+		return nil
+	}
+	diagnosis, ok := result.Details["ai_diagnosis"].(*ai.AnalysisResponse)
+	if !ok || diagnosis == nil {
+		return nil
+	}
+
+	analyzedAt, _ := result.Details["ai_analyzed_at"].(time.Time)
+	return &AIHeadline{
+		Check:      result.Name,
+		Summary:    diagnosis.Summary,
+		Severity:   string(diagnosis.Severity),
+		Confidence: diagnosis.Confidence,
+		AnalyzedAt: analyzedAt,
+	}
+}
+
+// WorstStatus returns the least healthy of the given statuses, or healthy
+// when there are none
+func WorstStatus(statuses ...HealthStatus) HealthStatus {
+	worst := HealthStatusHealthy
+	for _, status := range statuses {
+		if statusRank(status) > statusRank(worst) {
+			worst = status
+		}
+	}
+	return worst
+}
+
+// statusRank orders statuses from healthy to unhealthy
+func statusRank(status HealthStatus) int {
+	switch status {
+	case HealthStatusUnhealthy:
+		return 3
+	case HealthStatusDegraded:
+		return 2
+	case HealthStatusUnknown:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// criticalityRank orders criticalities from low to critical
+func criticalityRank(criticality Criticality) int {
+	switch criticality {
+	case CriticalityCritical:
+		return 4
+	case CriticalityHigh:
+		return 3
+	case CriticalityMedium:
+		return 2
+	case CriticalityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GetSLOStatuses returns the current status of every tracked SLO by name
+func (e *Engine) GetSLOStatuses() map[string]*SLOStatus {
+	slos := e.sloTracker.GetAllSLOs()
+	statuses := make(map[string]*SLOStatus, len(slos))
+	for name, status := range slos {
+		statuses[name] = sloStatus(status)
+	}
+	return statuses
+}
+
+// GetSLOStatus returns the current status of a tracked SLO
+func (e *Engine) GetSLOStatus(name string) (*SLOStatus, bool) {
+	status, ok := e.sloTracker.GetSLOStatus(name)
+	if !ok {
+		return nil, false
+	}
+	return sloStatus(status), true
+}
+
+// sloStatus converts an SLO tracker status
+func sloStatus(status *slo.SLOStatus) *SLOStatus {
+	budgetPolicy := make([]BudgetRule, len(status.SLO.BudgetPolicy))
+	for i, rule := range status.SLO.BudgetPolicy {
+		budgetPolicy[i] = BudgetRule{Threshold: rule.Threshold, Action: rule.Action}
+	}
+	return &SLOStatus{
+		SLO: SLO{
+			Name:         status.SLO.Name,
+			Description:  status.SLO.Description,
+			SLI:          status.SLO.SLI,
+			Target:       status.SLO.Target,
+			Window:       status.SLO.Window,
+			BudgetPolicy: budgetPolicy,
+			Check:        status.SLO.Check,
+		},
+		CurrentValue:  status.CurrentValue,
+		ErrorBudget:   status.ErrorBudget,
+		BurnRate:      status.BurnRate,
+		IsViolated:    status.IsViolated,
+		TimeToExhaust: status.TimeToExhaust,
+		Events:        status.Events,
+		BurnRates:     status.BurnRates,
+	}
+}
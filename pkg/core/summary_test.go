@@ -0,0 +1,104 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetDashboardSummary(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+
+	analyzedAt := time.Now()
+	results := []CheckResult{
+		{Name: "pod-health", Status: HealthStatusHealthy},
+		{Name: "node-health", Status: HealthStatusDegraded, Message: "1 node not ready"},
+		{Name: "service-health", Status: HealthStatusUnhealthy, Message: "no endpoints"},
+		{Name: "event-rates", Status: HealthStatusUnknown, Message: "Check failed"},
+		{
+			Name:   "pvc-health",
+			Status: HealthStatusDegraded,
+			Details: map[string]interface{}{
+				"ai_diagnosis":   &ai.AnalysisResponse{Summary: "Volume provisioning stalled", Severity: ai.SeverityHigh, Confidence: 0.8},
+				"ai_analyzed_at": analyzedAt,
+			},
+		},
+	}
+	for _, result := range results {
+		engine.storeResult(result)
+	}
+	engine.AddCheck(&mockHealthCheck{name: "node-health"})
+
+	summary := engine.GetDashboardSummary("prod")
+
+	if summary.ClusterName != "prod" || summary.TotalChecks != 5 {
+		t.Errorf("unexpected summary header: %s with %d checks", summary.ClusterName, summary.TotalChecks)
+	}
+	if summary.CheckCounts[HealthStatusDegraded] != 2 {
+		t.Errorf("expected 2 degraded checks, got %d", summary.CheckCounts[HealthStatusDegraded])
+	}
+	if summary.ActiveAlerts.Critical != 1 || summary.ActiveAlerts.Warning != 2 || summary.ActiveAlerts.Total != 3 {
+		t.Errorf("unexpected alert counts: %+v", summary.ActiveAlerts)
+	}
+
+	wantOrder := []string{"service-health", "node-health", "pvc-health", "event-rates"}
+	if len(summary.TopFailing) != len(wantOrder) {
+		t.Fatalf("expected %d failing checks, got %d", len(wantOrder), len(summary.TopFailing))
+	}
+	for i, name := range wantOrder {
+		if summary.TopFailing[i].Name != name {
+			t.Errorf("expected %s at position %d, got %s", name, i, summary.TopFailing[i].Name)
+		}
+	}
+	if summary.TopFailing[1].Criticality != CriticalityMedium {
+		t.Errorf("expected criticality from registered check, got %q", summary.TopFailing[1].Criticality)
+	}
+
+	if summary.AIHeadline == nil || summary.AIHeadline.Check != "pvc-health" {
+		t.Fatalf("expected AI headline from pvc-health, got %+v", summary.AIHeadline)
+	}
+	if summary.AIHeadline.Summary != "Volume provisioning stalled" {
+		t.Errorf("unexpected headline summary %q", summary.AIHeadline.Summary)
+	}
+}
+
+func TestGetDashboardSummary_Cache(t *testing.T) {
+	engine := NewEngine(EngineConfig{KubeClient: fake.NewSimpleClientset()})
+	engine.storeResult(CheckResult{Name: "pod-health", Status: HealthStatusHealthy})
+
+	first := engine.GetDashboardSummary("prod")
+
+	// Results changed but no check cycle completed: cached summary is served
+	engine.storeResult(CheckResult{Name: "node-health", Status: HealthStatusUnhealthy})
+	if cached := engine.GetDashboardSummary("prod"); !cached.GeneratedAt.Equal(first.GeneratedAt) || cached.TotalChecks != 1 {
+		t.Error("expected cached summary within the same check cycle")
+	}
+
+	if other := engine.GetDashboardSummary("staging"); other.ClusterName != "staging" {
+		t.Errorf("expected summary for staging, got %s", other.ClusterName)
+	}
+
+	engine.generation.Add(1)
+	if refreshed := engine.GetDashboardSummary("prod"); refreshed.TotalChecks != 2 {
+		t.Errorf("expected recomputed summary after a check cycle, got %d checks", refreshed.TotalChecks)
+	}
+}
+
+func TestWorstStatus(t *testing.T) {
+	tests := []struct {
+		statuses []HealthStatus
+		want     HealthStatus
+	}{
+		{nil, HealthStatusHealthy},
+		{[]HealthStatus{HealthStatusHealthy, HealthStatusUnknown}, HealthStatusUnknown},
+		{[]HealthStatus{HealthStatusDegraded, HealthStatusUnknown, HealthStatusHealthy}, HealthStatusDegraded},
+		{[]HealthStatus{HealthStatusDegraded, HealthStatusUnhealthy}, HealthStatusUnhealthy},
+	}
+	for _, tt := range tests {
+		if got := WorstStatus(tt.statuses...); got != tt.want {
+			t.Errorf("WorstStatus(%v) = %s, want %s", tt.statuses, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxTriagedPods caps the pods of a deployment looked at for a root cause
+const maxTriagedPods = 20
+
+// Triage is a diagnosis of one resource made from its state and events
+// alone, without an AI provider: the most likely root cause, the facts it
+// rests on, and kubectl commands to dig further
+type Triage struct {
+	Resource    ResourceRef            `json:"resource"`
+	Status      HealthStatus           `json:"status"`
+	RootCause   string                 `json:"root_cause"`
+	Evidence    []string               `json:"evidence,omitempty"`
+	Commands    []string               `json:"commands,omitempty"`
+	Description ai.ResourceDescription `json:"description"`
+}
+
+// TriageResource diagnoses a pod, node or deployment
+func TriageResource(ctx context.Context, client kubernetes.Interface, ref ResourceRef) (Triage, error) {
+	triage := Triage{Resource: ref, Status: HealthStatusHealthy}
+
+	var err error
+	switch ref.Kind {
+	case "pod":
+		var pod *corev1.Pod
+		pod, err = client.CoreV1().Pods(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			triagePod(&triage, pod)
+		}
+	case "node":
+		var node *corev1.Node
+		node, err = client.CoreV1().Nodes().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			triageNode(&triage, node)
+		}
+	case "deployment":
+		var deployment *appsv1.Deployment
+		deployment, err = client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			err = triageDeployment(ctx, client, &triage, deployment)
+		}
+	default:
+		return Triage{}, fmt.Errorf("can't diagnose %s resources; use pod, node or deployment", ref.Kind)
+	}
+	if apierrors.IsNotFound(err) {
+		return Triage{}, fmt.Errorf("%s not found", ref)
+	}
+	if err != nil {
+		return Triage{}, fmt.Errorf("failed to get %s: %w", ref, err)
+	}
+
+	describer := NewResourceDescriber(client, DefaultDescribeTTL)
+	triage.Description = describer.fetch(ctx, ref)
+	for _, event := range triage.Description.Events {
+		if strings.Contains(event, " "+corev1.EventTypeWarning+" ") {
+			triage.Evidence = append(triage.Evidence, "Event: "+event)
+		}
+	}
+	return triage, nil
+}
+
+// triagePod finds why a pod isn't running and ready
+func triagePod(triage *Triage, pod *corev1.Pod) {
+	triage.Commands = []string{
+		fmt.Sprintf("kubectl describe pod %s -n %s", pod.Name, pod.Namespace),
+		fmt.Sprintf("kubectl get events -n %s --field-selector involvedObject.name=%s", pod.Namespace, pod.Name),
+	}
+	status, cause, evidence := podRootCause(pod)
+	triage.Status, triage.RootCause = status, cause
+	triage.Evidence = append(triage.Evidence, evidence...)
+
+	for _, container := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		if container.RestartCount > 0 {
+			triage.Commands = append(triage.Commands, fmt.Sprintf("kubectl logs %s -n %s -c %s --previous", pod.Name, pod.Namespace, container.Name))
+		} else if !container.Ready && container.State.Waiting == nil {
+			triage.Commands = append(triage.Commands, fmt.Sprintf("kubectl logs %s -n %s -c %s", pod.Name, pod.Namespace, container.Name))
+		}
+	}
+	if pod.Spec.NodeName != "" && status != HealthStatusHealthy {
+		triage.Commands = append(triage.Commands, fmt.Sprintf("kubepulse diagnose node/%s", pod.Spec.NodeName))
+	}
+}
+
+// podRootCause returns a pod's status, the most likely reason it isn't
+// running and ready, and the facts behind it
+func podRootCause(pod *corev1.Pod) (HealthStatus, string, []string) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return HealthStatusUnhealthy, "Pod can't be scheduled: " + withReason(condition.Reason, "", condition.Message),
+				[]string{fmt.Sprintf("Condition PodScheduled=False (%s)", condition.Reason)}
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, container := range statuses {
+		waiting := container.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		evidence := []string{fmt.Sprintf("Container %s %s, restarts=%d", container.Name, containerState(container.State), container.RestartCount)}
+		terminated := container.LastTerminationState.Terminated
+		if terminated != nil {
+			evidence = append(evidence, fmt.Sprintf("Container %s last terminated %s (exit %d)", container.Name, terminated.Reason, terminated.ExitCode))
+		}
+		switch waiting.Reason {
+		case "CrashLoopBackOff":
+			if terminated != nil && terminated.Reason == "OOMKilled" {
+				return HealthStatusUnhealthy, fmt.Sprintf("Container %s is killed for exceeding its memory limit (OOMKilled) and crash looping", container.Name), evidence
+			}
+			if terminated != nil {
+				return HealthStatusUnhealthy, fmt.Sprintf("Container %s is crash looping, exiting with code %d (%s)", container.Name, terminated.ExitCode, terminated.Reason), evidence
+			}
+			return HealthStatusUnhealthy, fmt.Sprintf("Container %s is crash looping", container.Name), evidence
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+			return HealthStatusUnhealthy, fmt.Sprintf("Image %s of container %s can't be pulled: %s", container.Image, container.Name, withReason(waiting.Reason, "", waiting.Message)), evidence
+		case "CreateContainerConfigError", "CreateContainerError", "RunContainerError":
+			return HealthStatusUnhealthy, fmt.Sprintf("Container %s can't be started: %s", container.Name, withReason(waiting.Reason, "", waiting.Message)), evidence
+		case "ContainerCreating", "PodInitializing":
+			// Starting; a later container may show why the pod is stuck
+		default:
+			return HealthStatusUnhealthy, fmt.Sprintf("Container %s is waiting: %s", container.Name, withReason(waiting.Reason, "", waiting.Message)), evidence
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodFailed {
+		return HealthStatusUnhealthy, "Pod failed: " + withReason(string(pod.Status.Phase), pod.Status.Reason, pod.Status.Message), nil
+	}
+	if pod.Status.Phase == corev1.PodPending {
+		return HealthStatusDegraded, "Pod is pending while its containers are created", nil
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return HealthStatusHealthy, "Pod ran to completion", nil
+	}
+
+	for _, container := range pod.Status.ContainerStatuses {
+		if !container.Ready && container.State.Running != nil {
+			return HealthStatusDegraded, fmt.Sprintf("Container %s is running but not ready; its readiness probe is failing", container.Name),
+				[]string{fmt.Sprintf("Container %s %s, ready=false", container.Name, containerState(container.State))}
+		}
+	}
+	for _, container := range statuses {
+		if terminated := container.LastTerminationState.Terminated; terminated != nil && container.RestartCount > 0 {
+			return HealthStatusDegraded, fmt.Sprintf("Container %s restarted %d times, last %s (exit %d)", container.Name, container.RestartCount, terminated.Reason, terminated.ExitCode),
+				[]string{fmt.Sprintf("Container %s last terminated %s at %s", container.Name, terminated.Reason, terminated.FinishedAt.Format(time.RFC3339))}
+		}
+	}
+	return HealthStatusHealthy, "Pod is running and ready", nil
+}
+
+// triageNode finds why a node isn't ready or schedulable
+func triageNode(triage *Triage, node *corev1.Node) {
+	triage.Commands = []string{
+		fmt.Sprintf("kubectl describe node %s", node.Name),
+		fmt.Sprintf("kubectl get pods -A --field-selector spec.nodeName=%s", node.Name),
+		fmt.Sprintf("kubectl top node %s", node.Name),
+	}
+	triage.RootCause = "Node is ready"
+
+	var pressure []string
+	for _, condition := range node.Status.Conditions {
+		switch {
+		case condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue:
+			triage.Status = HealthStatusUnhealthy
+			triage.RootCause = "Node is not ready: " + withReason(string(condition.Status), condition.Reason, condition.Message)
+			triage.Evidence = append(triage.Evidence, fmt.Sprintf("Condition Ready=%s since %s", condition.Status, condition.LastTransitionTime.Format(time.RFC3339)))
+		case condition.Type != corev1.NodeReady && condition.Status == corev1.ConditionTrue:
+			pressure = append(pressure, string(condition.Type))
+			triage.Evidence = append(triage.Evidence, fmt.Sprintf("Condition %s=%s", condition.Type, withReason("True", condition.Reason, condition.Message)))
+		}
+	}
+	if triage.Status == HealthStatusHealthy && len(pressure) > 0 {
+		triage.Status = HealthStatusDegraded
+		triage.RootCause = "Node reports " + strings.Join(pressure, ", ")
+	}
+	if node.Spec.Unschedulable {
+		triage.Evidence = append(triage.Evidence, "Unschedulable: true")
+		if triage.Status == HealthStatusHealthy {
+			triage.Status = HealthStatusDegraded
+			triage.RootCause = "Node is cordoned, so no new pods are scheduled on it"
+		}
+		triage.Commands = append(triage.Commands, fmt.Sprintf("kubectl uncordon %s", node.Name))
+	}
+}
+
+// triageDeployment finds why a deployment's replicas aren't available,
+// looking at its pods for the root cause
+func triageDeployment(ctx context.Context, client kubernetes.Interface, triage *Triage, deployment *appsv1.Deployment) error {
+	desired := desiredReplicas(deployment)
+	triage.Commands = []string{
+		fmt.Sprintf("kubectl rollout status deployment/%s -n %s", deployment.Name, deployment.Namespace),
+		fmt.Sprintf("kubectl describe deployment %s -n %s", deployment.Name, deployment.Namespace),
+	}
+	triage.Evidence = append(triage.Evidence, fmt.Sprintf("Replicas: %d desired, %d updated, %d available",
+		desired, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas))
+	triage.RootCause = fmt.Sprintf("All %d replicas are available", desired)
+	if desired == 0 {
+		triage.RootCause = "Deployment is scaled to zero"
+	}
+
+	var stalled, replicaFailure *appsv1.DeploymentCondition
+	for i, condition := range deployment.Status.Conditions {
+		switch {
+		case condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded":
+			stalled = &deployment.Status.Conditions[i]
+		case condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue:
+			replicaFailure = &deployment.Status.Conditions[i]
+		}
+	}
+	if replicaFailure != nil {
+		triage.Status = HealthStatusUnhealthy
+		triage.RootCause = "Pods can't be created: " + withReason(replicaFailure.Reason, "", replicaFailure.Message)
+		triage.Evidence = append(triage.Evidence, "Condition ReplicaFailure="+withReason("True", replicaFailure.Reason, replicaFailure.Message))
+		return nil
+	}
+	if deployment.Status.AvailableReplicas >= desired && stalled == nil {
+		return nil
+	}
+
+	triage.Status = HealthStatusDegraded
+	if deployment.Status.AvailableReplicas == 0 && desired > 0 {
+		triage.Status = HealthStatusUnhealthy
+	}
+	unavailable := fmt.Sprintf("%d of %d replicas unavailable", desired-min(deployment.Status.AvailableReplicas, desired), desired)
+	triage.RootCause = unavailable
+	if stalled != nil {
+		triage.Status = HealthStatusUnhealthy
+		triage.RootCause = "Rollout stalled, " + unavailable
+		triage.Evidence = append(triage.Evidence, "Condition Progressing="+withReason("False", stalled.Reason, stalled.Message))
+		triage.Commands = append(triage.Commands, fmt.Sprintf("kubectl rollout undo deployment/%s -n %s", deployment.Name, deployment.Namespace))
+	}
+
+	if deployment.Spec.Selector == nil {
+		return nil
+	}
+	selector := metav1.FormatLabelSelector(deployment.Spec.Selector)
+	triage.Commands = append(triage.Commands, fmt.Sprintf("kubectl get pods -n %s -l %s", deployment.Namespace, selector))
+	pods, err := client.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector, Limit: maxTriagedPods})
+	if err != nil {
+		triage.Evidence = append(triage.Evidence, "Pods couldn't be listed: "+err.Error())
+		return nil
+	}
+
+	// The root cause is that of the least healthy pod
+	var worst *corev1.Pod
+	worstStatus, worstCause, worstEvidence := HealthStatusHealthy, "", []string(nil)
+	for i := range pods.Items {
+		status, cause, evidence := podRootCause(&pods.Items[i])
+		if statusRank(status) > statusRank(worstStatus) {
+			worst, worstStatus, worstCause, worstEvidence = &pods.Items[i], status, cause, evidence
+		}
+	}
+	if worst != nil {
+		triage.RootCause = fmt.Sprintf("%s: pod %s: %s", triage.RootCause, worst.Name, worstCause)
+		triage.Evidence = append(triage.Evidence, worstEvidence...)
+		triage.Commands = append(triage.Commands, fmt.Sprintf("kubepulse diagnose pod/%s -n %s", worst.Name, worst.Namespace))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTriageResource(t *testing.T) {
+	replicas := int32(2)
+	labels := map[string]string{"app": "web"}
+	crashing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "web", Image: "web:2"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "web",
+				Image:                "web:2",
+				RestartCount:         6,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}},
+		},
+	}
+	pulling := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "api",
+				Image: "registry.example.com/api:missing",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "manifest unknown"}},
+			}},
+		},
+	}
+	healthy := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", Labels: labels},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "web", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Reason: "NodeStatusUnknown", Message: "Kubelet stopped posting node status."},
+			{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+		}},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+		},
+		Status: appsv1.DeploymentStatus{
+			UpdatedReplicas:   2,
+			AvailableReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+			}},
+		},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web-1.backoff", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	client := fake.NewSimpleClientset([]runtime.Object{crashing, pulling, healthy, node, deployment, event}...)
+
+	tests := []struct {
+		ref       ResourceRef
+		status    HealthStatus
+		rootCause string
+		evidence  string
+		command   string
+	}{
+		{ResourceRef{Kind: "pod", Namespace: "default", Name: "web-1"}, HealthStatusUnhealthy,
+			"Container web is killed for exceeding its memory limit", "Event: ", "kubectl logs web-1 -n default -c web --previous"},
+		{ResourceRef{Kind: "pod", Namespace: "default", Name: "api-1"}, HealthStatusUnhealthy,
+			"Image registry.example.com/api:missing of container api can't be pulled: ImagePullBackOff (manifest unknown)", "waiting: ImagePullBackOff", "kubectl describe pod api-1 -n default"},
+		{ResourceRef{Kind: "pod", Namespace: "default", Name: "web-0"}, HealthStatusHealthy,
+			"Pod is running and ready", "", "kubectl describe pod web-0 -n default"},
+		{ResourceRef{Kind: "node", Name: "node-1"}, HealthStatusUnhealthy,
+			"Node is not ready: Unknown (NodeStatusUnknown: Kubelet stopped posting node status.)", "Condition MemoryPressure=True", "kubectl uncordon node-1"},
+		{ResourceRef{Kind: "deployment", Namespace: "default", Name: "web"}, HealthStatusUnhealthy,
+			"Rollout stalled, 1 of 2 replicas unavailable: pod web-1: Container web is killed", "Condition Progressing=False (ProgressDeadlineExceeded)", "kubepulse diagnose pod/web-1 -n default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref.String(), func(t *testing.T) {
+			triage, err := TriageResource(context.Background(), client, tt.ref)
+			if err != nil {
+				t.Fatalf("TriageResource() error = %v", err)
+			}
+			if triage.Status != tt.status || !strings.HasPrefix(triage.RootCause, tt.rootCause) {
+				t.Errorf("expected %s with root cause %q, got %s: %q", tt.status, tt.rootCause, triage.Status, triage.RootCause)
+			}
+			if evidence := strings.Join(triage.Evidence, "\n"); !strings.Contains(evidence, tt.evidence) {
+				t.Errorf("expected evidence mentioning %q, got:\n%s", tt.evidence, evidence)
+			}
+			if commands := strings.Join(triage.Commands, "\n"); !strings.Contains(commands, tt.command) {
+				t.Errorf("expected command %q, got:\n%s", tt.command, commands)
+			}
+			if triage.Description.Name != tt.ref.Name || triage.Description.Error != "" || len(triage.Description.Details) == 0 {
+				t.Errorf("expected the resource described, got %+v", triage.Description)
+			}
+		})
+	}
+
+	for _, ref := range []ResourceRef{{Kind: "pod", Namespace: "default", Name: "gone"}, {Kind: "service", Namespace: "default", Name: "web"}} {
+		if _, err := TriageResource(context.Background(), client, ref); err == nil {
+			t.Errorf("expected an error triaging %s", ref)
+		}
+	}
+}
@@ -2,8 +2,12 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/kubepulse/kubepulse/pkg/ml"
+	"github.com/kubepulse/kubepulse/pkg/slo"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -15,6 +19,10 @@ const (
 	HealthStatusDegraded  HealthStatus = "degraded"
 	HealthStatusUnhealthy HealthStatus = "unhealthy"
 	HealthStatusUnknown   HealthStatus = "unknown"
+
+	// HealthStatusRestricted marks a check disabled because KubePulse lacks
+	// the permissions it needs; it doesn't count toward cluster health
+	HealthStatusRestricted HealthStatus = "restricted"
 )
 
 // CheckResult represents the result of a health check
@@ -29,6 +37,39 @@ type CheckResult struct {
 	Metrics     []Metric               `json:"metrics,omitempty"`
 	Confidence  float64                `json:"confidence"`
 	Predictions []Prediction           `json:"predictions,omitempty"`
+
+	// AffectedResources is how many resources a failure touches; it widens
+	// the failure's blast radius in the weighted health score
+	AffectedResources int `json:"affected_resources,omitempty"`
+}
+
+// MarshalJSON encodes the check error as its message so it survives the wire
+func (r CheckResult) MarshalJSON() ([]byte, error) {
+	type alias CheckResult
+	aux := struct {
+		alias
+		Error string `json:"error,omitempty"`
+	}{alias: alias(r)}
+	if r.Error != nil {
+		aux.Error = r.Error.Error()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a check result, restoring the error from its message
+func (r *CheckResult) UnmarshalJSON(data []byte) error {
+	type alias CheckResult
+	aux := struct {
+		*alias
+		Error string `json:"error,omitempty"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Error != "" {
+		r.Error = errors.New(aux.Error)
+	}
+	return nil
 }
 
 // HealthCheck defines the interface for all health checks
@@ -89,6 +130,18 @@ type Prediction struct {
 	Status      HealthStatus `json:"status"`
 	Probability float64      `json:"probability"`
 	Reason      string       `json:"reason"`
+
+	// Explanation is the evidence behind an anomaly prediction
+	Explanation *ml.AnomalyExplanation `json:"explanation,omitempty"`
+}
+
+// LogPattern represents a recurring log line pattern extracted from pod logs
+type LogPattern struct {
+	Pattern string   `json:"pattern"`        // Normalized line with variable parts masked
+	Kind    string   `json:"kind"`           // error, stack_trace
+	Count   int      `json:"count"`          // Number of occurrences across fetched logs
+	Example string   `json:"example"`        // First raw line that matched the pattern
+	Pods    []string `json:"pods,omitempty"` // namespace/name of pods emitting the pattern
 }
 
 // Alert represents an alert generated by the system
@@ -131,6 +184,9 @@ type SLO struct {
 	Target       float64       `json:"target"`
 	Window       time.Duration `json:"window"`
 	BudgetPolicy []BudgetRule  `json:"budget_policy"`
+
+	// Check names the check whose results are the SLI, if any
+	Check string `json:"check,omitempty"`
 }
 
 // BudgetRule defines actions based on error budget consumption
@@ -149,15 +205,32 @@ type ClusterHealth struct {
 	Metrics     map[string][]Metric   `json:"metrics"`
 	SLOs        map[string]*SLOStatus `json:"slos,omitempty"`
 	Alerts      []Alert               `json:"alerts,omitempty"`
+
+	// Freshness tells how current each check's result is; only live health
+	// carries it
+	Freshness map[string]CheckFreshness `json:"freshness,omitempty"`
+
+	// Restricted lists the checks disabled for lack of permissions; only
+	// live health carries it
+	Restricted []RestrictedCheck `json:"restricted,omitempty"`
+
+	// StateRevision is the revision AI insights computed now would carry;
+	// insights with an older one are stale. Only live health carries it.
+	StateRevision uint64 `json:"state_revision,omitempty"`
+
+	// Environment is the class of the cluster, such as prod or dev; only
+	// live health carries it
+	Environment string `json:"environment,omitempty"`
 }
 
 // HealthScore represents an intelligent health score
 type HealthScore struct {
-	Raw        float64 `json:"raw"`        // 0-100 raw score
-	Weighted   float64 `json:"weighted"`   // Criticality-weighted score
-	Trend      string  `json:"trend"`      // improving/stable/degrading
-	Confidence float64 `json:"confidence"` // ML confidence level
-	Forecast   string  `json:"forecast"`   // predicted state in 24h
+	Raw        float64             `json:"raw"`        // 0-100 raw score
+	Weighted   float64             `json:"weighted"`   // Severity-aware score; see weightedScore
+	Trend      string              `json:"trend"`      // improving/stable/degrading
+	Confidence float64             `json:"confidence"` // ML confidence level
+	Forecast   string              `json:"forecast"`   // predicted state in 24h
+	Breakdown  []ScoreContribution `json:"breakdown,omitempty"`
 }
 
 // SLOStatus represents the current status of an SLO
@@ -168,4 +241,9 @@ type SLOStatus struct {
 	BurnRate      float64 `json:"burn_rate"`
 	IsViolated    bool    `json:"is_violated"`
 	TimeToExhaust string  `json:"time_to_exhaust,omitempty"`
+
+	// Events and BurnRates are only set for ratio SLIs: availability,
+	// error_rate and check results
+	Events    float64              `json:"events,omitempty"`
+	BurnRates []slo.BurnRateStatus `json:"burn_rates,omitempty"`
 }
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -73,3 +76,39 @@ func TestMetric(t *testing.T) {
 		t.Errorf("expected unit 'percent', got %s", metric.Unit)
 	}
 }
+
+func TestCheckResult_JSONRoundTrip(t *testing.T) {
+	original := CheckResult{
+		Name:    "pod-health",
+		Status:  HealthStatusUnknown,
+		Message: "Check failed",
+		Error:   errors.New("connection refused"),
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"error":"connection refused"`) {
+		t.Errorf("expected error to be encoded as a string, got %s", data)
+	}
+
+	var decoded CheckResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if decoded.Name != original.Name || decoded.Status != original.Status {
+		t.Errorf("expected fields to round-trip, got %+v", decoded)
+	}
+	if decoded.Error == nil || decoded.Error.Error() != "connection refused" {
+		t.Errorf("expected error to round-trip, got %v", decoded.Error)
+	}
+
+	data, err = json.Marshal(CheckResult{Name: "node-health"})
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	if strings.Contains(string(data), `"error"`) {
+		t.Errorf("expected nil error to be omitted, got %s", data)
+	}
+}
@@ -0,0 +1,6 @@
+package core
+
+// DetailUpgradeRecommendations lists the upgrades a check recommends, such
+// as kubelets to bring within the supported version skew; the AI
+// optimization report includes them
+const DetailUpgradeRecommendations = "upgrade_recommendations"
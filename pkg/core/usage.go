@@ -0,0 +1,20 @@
+package core
+
+// Usage summarizes what the engine has done since it started. It describes
+// KubePulse itself and carries no cluster data.
+type Usage struct {
+	Checks      int   `json:"checks"`       // Registered health checks
+	CheckRuns   int64 `json:"check_runs"`   // Check executions
+	CheckErrors int64 `json:"check_errors"` // Executions that returned an error
+	StuckChecks int   `json:"stuck_checks"` // Checks the watchdog gave up on
+}
+
+// Usage returns the engine's activity counters
+func (e *Engine) Usage() Usage {
+	return Usage{
+		Checks:      len(e.checks),
+		CheckRuns:   e.checkRuns.Load(),
+		CheckErrors: e.checkErrors.Load(),
+		StuckChecks: len(e.watchdog.Stuck()),
+	}
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Watchdog tracks in-flight health check executions and abandons those that
+// run longer than a multiple of their timeout, e.g. when a Kubernetes call
+// stalls before the check context applies
+type Watchdog struct {
+	multiplier float64
+	mu         sync.Mutex
+	running    map[string]*Execution
+	abandoned  map[string]int64 // Total abandoned executions per check
+}
+
+// Execution is a single tracked run of a health check
+type Execution struct {
+	check     string
+	start     time.Time
+	deadline  time.Time
+	timer     *time.Timer
+	abandonC  chan struct{}
+	abandoned bool
+}
+
+// StuckCheck describes a check execution that overran its watchdog deadline
+type StuckCheck struct {
+	Name      string        `json:"name"`
+	StartedAt time.Time     `json:"started_at"`
+	Running   time.Duration `json:"running"`
+	Abandoned bool          `json:"abandoned"`
+}
+
+// NewWatchdog creates a watchdog that abandons checks after multiplier times their timeout
+func NewWatchdog(multiplier float64) *Watchdog {
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	return &Watchdog{
+		multiplier: multiplier,
+		running:    make(map[string]*Execution),
+		abandoned:  make(map[string]int64),
+	}
+}
+
+// Begin starts tracking an execution of the named check
+func (w *Watchdog) Begin(name string, timeout time.Duration) *Execution {
+	now := time.Now()
+	limit := time.Duration(float64(timeout) * w.multiplier)
+	exec := &Execution{
+		check:    name,
+		start:    now,
+		deadline: now.Add(limit),
+		abandonC: make(chan struct{}),
+	}
+
+	w.mu.Lock()
+	w.running[name] = exec
+	w.mu.Unlock()
+
+	exec.timer = time.AfterFunc(limit, func() { w.abandon(exec) })
+	return exec
+}
+
+// Abandoned is closed when the watchdog gives up waiting for the execution
+func (x *Execution) Abandoned() <-chan struct{} {
+	return x.abandonC
+}
+
+// abandon marks an execution as stuck so the engine stops waiting for it
+func (w *Watchdog) abandon(exec *Execution) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running[exec.check] != exec || exec.abandoned {
+		return
+	}
+
+	exec.abandoned = true
+	w.abandoned[exec.check]++
+	close(exec.abandonC)
+
+	klog.Warningf("Watchdog abandoned check %s after %v; its goroutine is still running",
+		exec.check, time.Since(exec.start).Round(time.Millisecond))
+}
+
+// Finish stops tracking an execution once its check call has returned
+func (w *Watchdog) Finish(exec *Execution) {
+	exec.timer.Stop()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running[exec.check] == exec {
+		delete(w.running, exec.check)
+	}
+	if exec.abandoned {
+		klog.Infof("Abandoned check %s returned after %v",
+			exec.check, time.Since(exec.start).Round(time.Millisecond))
+	}
+}
+
+// InFlight reports whether an execution of the named check is still running
+func (w *Watchdog) InFlight(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, exists := w.running[name]
+	return exists
+}
+
+// Stuck returns executions that have exceeded their watchdog deadline
+func (w *Watchdog) Stuck() []StuckCheck {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	stuck := make([]StuckCheck, 0)
+	for name, exec := range w.running {
+		if !exec.abandoned && now.Before(exec.deadline) {
+			continue
+		}
+		stuck = append(stuck, StuckCheck{
+			Name:      name,
+			StartedAt: exec.start,
+			Running:   now.Sub(exec.start),
+			Abandoned: exec.abandoned,
+		})
+	}
+
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].Name < stuck[j].Name
+	})
+	return stuck
+}
+
+// Metrics returns stuck and abandoned counts for every check that has been abandoned
+func (w *Watchdog) Metrics() []Metric {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	metrics := make([]Metric, 0, len(w.abandoned)*2)
+	for name, total := range w.abandoned {
+		stuck := 0.0
+		if exec, exists := w.running[name]; exists && exec.abandoned {
+			stuck = 1
+		}
+
+		labels := map[string]string{"check": name}
+		metrics = append(metrics,
+			Metric{
+				Name:      "kubepulse_check_stuck",
+				Value:     stuck,
+				Unit:      "executions",
+				Labels:    labels,
+				Timestamp: now,
+				Type:      MetricTypeGauge,
+			},
+			Metric{
+				Name:      "kubepulse_check_abandoned_total",
+				Value:     float64(total),
+				Unit:      "executions",
+				Labels:    labels,
+				Timestamp: now,
+				Type:      MetricTypeCounter,
+			},
+		)
+	}
+	return metrics
+}
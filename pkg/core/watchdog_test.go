@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// hangingHealthCheck blocks until released, ignoring its context
+type hangingHealthCheck struct {
+	mockHealthCheck
+	release chan struct{}
+}
+
+func (h *hangingHealthCheck) Check(ctx context.Context, client kubernetes.Interface) (CheckResult, error) {
+	<-h.release
+	return CheckResult{Name: h.name, Status: HealthStatusHealthy}, nil
+}
+
+func TestWatchdog_AbandonsAfterMultiple(t *testing.T) {
+	w := NewWatchdog(2)
+
+	exec := w.Begin("slow", 10*time.Millisecond)
+	if !w.InFlight("slow") {
+		t.Fatal("expected execution to be in flight")
+	}
+	if len(w.Stuck()) != 0 {
+		t.Error("expected no stuck checks before the deadline")
+	}
+
+	select {
+	case <-exec.Abandoned():
+	case <-time.After(time.Second):
+		t.Fatal("expected execution to be abandoned")
+	}
+
+	stuck := w.Stuck()
+	if len(stuck) != 1 || stuck[0].Name != "slow" || !stuck[0].Abandoned {
+		t.Fatalf("expected slow check to be reported stuck, got %+v", stuck)
+	}
+	if stuck[0].Running < 20*time.Millisecond {
+		t.Errorf("expected running time of at least 2x timeout, got %v", stuck[0].Running)
+	}
+
+	w.Finish(exec)
+	if w.InFlight("slow") {
+		t.Error("expected execution to be cleared after finishing")
+	}
+}
+
+func TestWatchdog_FinishBeforeDeadline(t *testing.T) {
+	w := NewWatchdog(2)
+
+	exec := w.Begin("fast", time.Hour)
+	w.Finish(exec)
+
+	if w.InFlight("fast") {
+		t.Error("expected execution to be cleared")
+	}
+	select {
+	case <-exec.Abandoned():
+		t.Error("expected finished execution not to be abandoned")
+	default:
+	}
+	if len(w.Metrics()) != 0 {
+		t.Error("expected no metrics when nothing was abandoned")
+	}
+}
+
+func TestEngine_WatchdogAbandonsStuckCheck(t *testing.T) {
+	engine := NewEngine(EngineConfig{
+		KubeClient:         fake.NewSimpleClientset(),
+		CheckTimeout:       10 * time.Millisecond,
+		WatchdogMultiplier: 2,
+	})
+
+	check := &hangingHealthCheck{
+		mockHealthCheck: mockHealthCheck{name: "stuck-check"},
+		release:         make(chan struct{}),
+	}
+	engine.AddCheck(check)
+
+	done := make(chan struct{})
+	go func() {
+		engine.runChecks()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected runChecks to return once the check was abandoned")
+	}
+
+	result, exists := engine.GetResult("stuck-check")
+	if !exists {
+		t.Fatal("expected a result for the abandoned check")
+	}
+	if result.Status != HealthStatusUnknown || result.Error == nil {
+		t.Errorf("expected unknown status with error, got %s (%v)", result.Status, result.Error)
+	}
+	if !strings.Contains(result.Error.Error(), "abandoned") {
+		t.Errorf("expected abandoned error, got %v", result.Error)
+	}
+
+	// A second run must not start another copy of the stuck check
+	engine.runChecks()
+	result, _ = engine.GetResult("stuck-check")
+	if !strings.Contains(result.Message, "still stuck") {
+		t.Errorf("expected skipped result, got %q", result.Message)
+	}
+
+	metrics := make(map[string]float64)
+	for _, metric := range engine.GetWatchdogMetrics() {
+		metrics[metric.Name] = metric.Value
+	}
+	if metrics["kubepulse_check_stuck"] != 1 {
+		t.Errorf("expected stuck gauge 1, got %v", metrics["kubepulse_check_stuck"])
+	}
+	if metrics["kubepulse_check_abandoned_total"] != 1 {
+		t.Errorf("expected abandoned total 1, got %v", metrics["kubepulse_check_abandoned_total"])
+	}
+	if len(engine.GetStuckChecks()) != 1 {
+		t.Errorf("expected 1 stuck check, got %d", len(engine.GetStuckChecks()))
+	}
+
+	// Releasing the call lets the leaked goroutine exit and clears the stuck state
+	close(check.release)
+	deadline := time.Now().Add(time.Second)
+	for engine.watchdog.InFlight("stuck-check") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(engine.GetStuckChecks()) != 0 {
+		t.Error("expected no stuck checks after the call returned")
+	}
+}
@@ -0,0 +1,354 @@
+// Package diagnostics watches KubePulse's own goroutine count and check
+// cycle duration and captures goroutine and heap dumps when they jump, so a
+// stuck or leaking engine can be debugged in the field.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Files written for every dump
+const (
+	FileGoroutines = "goroutines.txt" // Stacks of every goroutine
+	FileHeap       = "heap.pprof"     // Heap profile for go tool pprof
+)
+
+// metadataFile records why a dump was taken, next to its profiles
+const metadataFile = "dump.json"
+
+// ErrDumpNotFound is returned for an unknown dump or dump file
+var ErrDumpNotFound = errors.New("dump not found")
+
+// ewmaWeight is the weight of a new sample in the running baselines
+const ewmaWeight = 0.1
+
+// Config controls anomaly detection and dump retention
+type Config struct {
+	Dir           string        // Directory dumps are written to
+	MaxDumps      int           // Dumps kept; older ones are deleted
+	Interval      time.Duration // How often goroutines and the cycle are sampled
+	GrowthFactor  float64       // Multiple of the baseline that is anomalous
+	MinGoroutines int           // Goroutine counts below this are never anomalous
+	MinCycle      time.Duration // Cycle durations below this are never anomalous
+	Cooldown      time.Duration // Minimum time between automatic dumps
+
+	// Warnings, when set, reports problems KubePulse worked around, such as
+	// metrics that hit their cardinality limits; they are shown in Status
+	Warnings func() []string
+}
+
+// Dump is a captured goroutine and heap profile
+type Dump struct {
+	ID         string    `json:"id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+	Goroutines int       `json:"goroutines"`
+	Files      []string  `json:"files"`
+	Size       int64     `json:"size"` // Bytes across all files
+}
+
+// Status reports the latest samples, their baselines and the last anomaly
+type Status struct {
+	Goroutines         int           `json:"goroutines"`
+	GoroutineBaseline  float64       `json:"goroutine_baseline"`
+	CycleDuration      time.Duration `json:"cycle_duration"`
+	CycleBaseline      time.Duration `json:"cycle_baseline"`
+	LastAnomaly        string        `json:"last_anomaly,omitempty"`
+	LastAnomalyAt      time.Time     `json:"last_anomaly_at,omitzero"`
+	LastDumpAt         time.Time     `json:"last_dump_at,omitzero"`
+	LastError          string        `json:"last_error,omitempty"`
+	Dir                string        `json:"dir"`
+	MaxDumps           int           `json:"max_dumps"`
+	AutomaticDumpAfter time.Time     `json:"automatic_dump_after,omitzero"` // End of the cooldown
+	Warnings           []string      `json:"warnings,omitempty"`
+}
+
+// Monitor watches the process's goroutine count and the engine's check
+// cycle duration and captures dumps when either jumps well above its
+// running baseline
+type Monitor struct {
+	config     Config
+	cycle      func() time.Duration
+	goroutines func() int
+	now        func() time.Time
+
+	mu            sync.Mutex
+	status        Status
+	goroutineBase float64
+	cycleBase     float64
+	sampled       bool
+}
+
+// NewMonitor creates a monitor sampling the duration of the engine's
+// current or last check cycle from cycle, and creates the dump directory
+func NewMonitor(config Config, cycle func() time.Duration) (*Monitor, error) {
+	if config.MaxDumps <= 0 {
+		config.MaxDumps = 5
+	}
+	if config.Interval <= 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.GrowthFactor <= 1 {
+		config.GrowthFactor = 3
+	}
+	if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create dump directory: %w", err)
+	}
+	return &Monitor{
+		config:     config,
+		cycle:      cycle,
+		goroutines: runtime.NumGoroutine,
+		now:        time.Now,
+		status:     Status{Dir: config.Dir, MaxDumps: config.MaxDumps},
+	}, nil
+}
+
+// Run samples on every interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if reason := m.Sample(); reason != "" {
+				if _, err := m.autoCapture(reason); err != nil {
+					klog.Errorf("Failed to capture diagnostic dump: %v", err)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Sample takes one goroutine and cycle sample and returns why it is
+// anomalous, or an empty string. Anomalous samples don't move the
+// baselines, so a leak or a stuck cycle keeps being reported.
+func (m *Monitor) Sample() string {
+	goroutines := m.goroutines()
+	var cycle time.Duration
+	if m.cycle != nil {
+		cycle = m.cycle()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status.Goroutines = goroutines
+	m.status.CycleDuration = cycle
+	if !m.sampled {
+		m.goroutineBase = float64(goroutines)
+		m.cycleBase = float64(cycle)
+		m.sampled = true
+		m.updateBaselines()
+		return ""
+	}
+
+	var reason string
+	switch {
+	case goroutines >= m.config.MinGoroutines && float64(goroutines) > m.config.GrowthFactor*m.goroutineBase:
+		reason = fmt.Sprintf("goroutines at %d, %.1fx the baseline of %.0f", goroutines, float64(goroutines)/m.goroutineBase, m.goroutineBase)
+	case cycle >= m.config.MinCycle && m.cycleBase > 0 && float64(cycle) > m.config.GrowthFactor*m.cycleBase:
+		reason = fmt.Sprintf("check cycle at %s, %.1fx the baseline of %s", cycle.Round(time.Millisecond),
+			float64(cycle)/m.cycleBase, time.Duration(m.cycleBase).Round(time.Millisecond))
+	}
+	if reason != "" {
+		m.status.LastAnomaly = reason
+		m.status.LastAnomalyAt = m.now()
+		return reason
+	}
+
+	m.goroutineBase += ewmaWeight * (float64(goroutines) - m.goroutineBase)
+	m.cycleBase += ewmaWeight * (float64(cycle) - m.cycleBase)
+	m.updateBaselines()
+	return ""
+}
+
+// updateBaselines copies the baselines into the status; callers hold m.mu
+func (m *Monitor) updateBaselines() {
+	m.status.GoroutineBaseline = m.goroutineBase
+	m.status.CycleBaseline = time.Duration(m.cycleBase)
+}
+
+// Status returns the latest samples and dump state
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	status := m.status
+	m.mu.Unlock()
+	if m.config.Warnings != nil {
+		status.Warnings = m.config.Warnings()
+	}
+	return status
+}
+
+// autoCapture captures a dump for an anomaly unless one was taken within
+// the cooldown
+func (m *Monitor) autoCapture(reason string) (Dump, error) {
+	m.mu.Lock()
+	if !m.status.AutomaticDumpAfter.IsZero() && m.now().Before(m.status.AutomaticDumpAfter) {
+		m.mu.Unlock()
+		klog.V(2).Infof("Skipping diagnostic dump during cooldown: %s", reason)
+		return Dump{}, nil
+	}
+	m.status.AutomaticDumpAfter = m.now().Add(m.config.Cooldown)
+	m.mu.Unlock()
+
+	klog.Warningf("Self-diagnostics anomaly, capturing goroutine and heap dump: %s", reason)
+	return m.Capture(reason)
+}
+
+// Capture writes a goroutine and heap dump and prunes the oldest dumps
+// beyond the retention limit
+func (m *Monitor) Capture(reason string) (Dump, error) {
+	dump, err := m.capture(reason)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.status.LastError = err.Error()
+		return Dump{}, err
+	}
+	m.status.LastDumpAt = dump.CreatedAt
+	m.status.LastError = ""
+	return dump, nil
+}
+
+func (m *Monitor) capture(reason string) (Dump, error) {
+	now := m.now().UTC()
+	dump := Dump{
+		ID:         "dump-" + now.Format("20060102T150405.000000000Z"),
+		Reason:     reason,
+		CreatedAt:  now,
+		Goroutines: runtime.NumGoroutine(),
+		Files:      []string{FileGoroutines, FileHeap},
+	}
+	dir := filepath.Join(m.config.Dir, dump.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Dump{}, fmt.Errorf("failed to create dump: %w", err)
+	}
+
+	profiles := map[string]func(*os.File) error{
+		FileGoroutines: func(f *os.File) error { return pprof.Lookup("goroutine").WriteTo(f, 2) },
+		FileHeap: func(f *os.File) error {
+			runtime.GC()
+			return pprof.WriteHeapProfile(f)
+		},
+	}
+	for _, name := range dump.Files {
+		size, err := writeFile(filepath.Join(dir, name), profiles[name])
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return Dump{}, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		dump.Size += size
+	}
+
+	metadata, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return Dump{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, metadataFile), metadata, 0o600); err != nil {
+		_ = os.RemoveAll(dir)
+		return Dump{}, fmt.Errorf("failed to write dump metadata: %w", err)
+	}
+
+	klog.Infof("Wrote diagnostic dump %s (%d bytes): %s", dump.ID, dump.Size, reason)
+	if err := m.prune(); err != nil {
+		klog.Errorf("Failed to prune diagnostic dumps: %v", err)
+	}
+	return dump, nil
+}
+
+// writeFile creates path, fills it with write and returns its size
+func writeFile(path string, write func(*os.File) error) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return 0, err
+	}
+	return info.Size(), f.Close()
+}
+
+// prune deletes the oldest dumps beyond the retention limit
+func (m *Monitor) prune() error {
+	dumps, err := m.Dumps()
+	if err != nil {
+		return err
+	}
+	for len(dumps) > m.config.MaxDumps {
+		oldest := dumps[len(dumps)-1]
+		if err := os.RemoveAll(filepath.Join(m.config.Dir, oldest.ID)); err != nil {
+			return fmt.Errorf("failed to delete dump %s: %w", oldest.ID, err)
+		}
+		dumps = dumps[:len(dumps)-1]
+	}
+	return nil
+}
+
+// Dumps returns the dumps in the dump directory, newest first
+func (m *Monitor) Dumps() ([]Dump, error) {
+	entries, err := os.ReadDir(m.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dumps: %w", err)
+	}
+
+	dumps := make([]Dump, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(m.config.Dir, entry.Name(), metadataFile))
+		if err != nil {
+			continue // Not a dump, or one still being written
+		}
+		var dump Dump
+		if err := json.Unmarshal(data, &dump); err != nil || dump.ID != entry.Name() {
+			continue
+		}
+		dumps = append(dumps, dump)
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].CreatedAt.After(dumps[j].CreatedAt)
+	})
+	return dumps, nil
+}
+
+// Open opens one file of a dump for download
+func (m *Monitor) Open(id, file string) (*os.File, error) {
+	dumps, err := m.Dumps()
+	if err != nil {
+		return nil, err
+	}
+	for _, dump := range dumps {
+		if dump.ID != id {
+			continue
+		}
+		for _, name := range dump.Files {
+			if name == file {
+				return os.Open(filepath.Join(m.config.Dir, id, name))
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s", ErrDumpNotFound, id, file)
+}
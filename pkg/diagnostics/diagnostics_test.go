@@ -0,0 +1,126 @@
+package diagnostics
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitor_Sample(t *testing.T) {
+	cycle := 2 * time.Second
+	goroutines := 100
+	monitor, err := NewMonitor(Config{
+		Dir:           t.TempDir(),
+		GrowthFactor:  3,
+		MinGoroutines: 500,
+		MinCycle:      10 * time.Second,
+	}, func() time.Duration { return cycle })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	monitor.goroutines = func() int { return goroutines }
+
+	tests := []struct {
+		name       string
+		goroutines int
+		cycle      time.Duration
+		anomaly    string
+	}{
+		{"baseline", 100, 2 * time.Second, ""},
+		{"tripled but below the floor", 400, 2 * time.Second, ""},
+		{"goroutine leak", 2000, 2 * time.Second, "goroutines at 2000"},
+		{"leak keeps being reported", 2500, 2 * time.Second, "goroutines at 2500"},
+		{"back to normal", 120, 2 * time.Second, ""},
+		{"slow but below the floor", 120, 9 * time.Second, ""},
+		{"stuck cycle", 120, 40 * time.Second, "check cycle at 40s"},
+	}
+	for _, tt := range tests {
+		goroutines, cycle = tt.goroutines, tt.cycle
+		reason := monitor.Sample()
+		if (tt.anomaly == "") != (reason == "") || !strings.Contains(reason, tt.anomaly) {
+			t.Errorf("%s: expected anomaly %q, got %q", tt.name, tt.anomaly, reason)
+		}
+	}
+
+	status := monitor.Status()
+	if status.Goroutines != 120 || status.CycleDuration != 40*time.Second || !strings.Contains(status.LastAnomaly, "check cycle") {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status.GoroutineBaseline > 200 {
+		t.Errorf("expected anomalous samples kept out of the baseline, got %.0f", status.GoroutineBaseline)
+	}
+}
+
+func TestMonitor_Dumps(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	monitor, err := NewMonitor(Config{Dir: t.TempDir(), MaxDumps: 2, Cooldown: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	monitor.now = func() time.Time { return now }
+
+	first, err := monitor.autoCapture("goroutines at 2000")
+	if err != nil || first.ID == "" {
+		t.Fatalf("expected a dump, got %+v (%v)", first, err)
+	}
+	now = now.Add(time.Minute)
+	if skipped, err := monitor.autoCapture("goroutines at 2500"); err != nil || skipped.ID != "" {
+		t.Fatalf("expected no dump during the cooldown, got %+v (%v)", skipped, err)
+	}
+
+	// Manual captures ignore the cooldown; the oldest dump is pruned
+	for i := 0; i < 2; i++ {
+		now = now.Add(time.Minute)
+		if _, err := monitor.Capture("requested"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	dumps, err := monitor.Dumps()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dumps) != 2 || dumps[0].CreatedAt.Before(dumps[1].CreatedAt) || dumps[1].ID == first.ID {
+		t.Fatalf("expected the two newest dumps, newest first, got %+v", dumps)
+	}
+	if dumps[0].Size == 0 || len(dumps[0].Files) != 2 {
+		t.Errorf("expected both profiles written, got %+v", dumps[0])
+	}
+
+	f, err := monitor.Open(dumps[0].ID, FileGoroutines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	stacks, err := io.ReadAll(f)
+	if err != nil || !strings.Contains(string(stacks), "goroutine ") {
+		t.Errorf("expected goroutine stacks, got %d bytes (%v)", len(stacks), err)
+	}
+
+	for _, tt := range []struct{ id, file string }{
+		{first.ID, FileGoroutines},
+		{dumps[0].ID, "../../etc/passwd"},
+		{"..", FileHeap},
+	} {
+		if _, err := monitor.Open(tt.id, tt.file); !errors.Is(err, ErrDumpNotFound) {
+			t.Errorf("Open(%q, %q): expected ErrDumpNotFound, got %v", tt.id, tt.file, err)
+		}
+	}
+}
+
+func TestMonitor_StatusWarnings(t *testing.T) {
+	var warnings []string
+	monitor, err := NewMonitor(Config{Dir: t.TempDir(), Warnings: func() []string { return warnings }}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := monitor.Status().Warnings; len(got) != 0 {
+		t.Errorf("expected no warnings, got %v", got)
+	}
+
+	warnings = []string{"metric request_total hit its cardinality limits"}
+	if got := monitor.Status().Warnings; len(got) != 1 || got[0] != warnings[0] {
+		t.Errorf("expected the current warnings in the status, got %v", got)
+	}
+}
@@ -0,0 +1,160 @@
+package federation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers carrying a federation request's or response's signature
+const (
+	HeaderPeer      = "X-KubePulse-Peer"      // Name of the signing instance
+	HeaderTimestamp = "X-KubePulse-Timestamp" // Unix seconds when it was signed
+	HeaderSignature = "X-KubePulse-Signature" // v1=<hex HMAC-SHA256>
+)
+
+// MaxClockSkew is how far a request's timestamp may be from the spoke's
+// clock; signatures seen within it are rejected as replays
+const MaxClockSkew = 5 * time.Minute
+
+// ErrUnauthenticated is returned for requests and responses that aren't
+// signed by a known peer
+var ErrUnauthenticated = errors.New("federation peer not authenticated")
+
+// Peer is another KubePulse instance and the secret shared with it. URL is
+// only needed to reach spokes.
+type Peer struct {
+	Name   string
+	URL    string
+	Secret string
+}
+
+// Spoke authenticates hubs requesting analyses from this instance and signs
+// its responses to them
+type Spoke struct {
+	instance string
+	hubs     map[string]string // Hub name to shared secret
+	now      func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time // Request signatures until they can no longer be replayed
+}
+
+// NewSpoke creates a spoke named instance accepting requests from hubs, or
+// returns nil when no hubs are configured
+func NewSpoke(instance string, hubs []Peer) *Spoke {
+	if len(hubs) == 0 {
+		return nil
+	}
+	secrets := make(map[string]string, len(hubs))
+	for _, hub := range hubs {
+		secrets[hub.Name] = hub.Secret
+	}
+	return &Spoke{instance: instance, hubs: secrets, now: time.Now, seen: make(map[string]time.Time)}
+}
+
+// Instance is the name the spoke signs its responses with
+func (s *Spoke) Instance() string {
+	return s.instance
+}
+
+// Authenticate checks that a request with the given body was signed by a
+// configured hub within MaxClockSkew and hasn't been seen before. It returns
+// the hub's name and the request signature the response is bound to.
+func (s *Spoke) Authenticate(r *http.Request, body []byte) (string, string, error) {
+	hub := r.Header.Get(HeaderPeer)
+	secret, ok := s.hubs[hub]
+	if !ok {
+		return "", "", fmt.Errorf("%w: unknown hub %q", ErrUnauthenticated, hub)
+	}
+	timestamp := r.Header.Get(HeaderTimestamp)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid timestamp", ErrUnauthenticated)
+	}
+	now := s.now()
+	if skew := now.Sub(time.Unix(signedAt, 0)); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return "", "", fmt.Errorf("%w: request signed %s from this instance's clock", ErrUnauthenticated, skew.Round(time.Second))
+	}
+	sig := r.Header.Get(HeaderSignature)
+	want := requestSignature(secret, hub, timestamp, r.Method, r.URL.Path, body)
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return "", "", fmt.Errorf("%w: invalid signature from hub %s", ErrUnauthenticated, hub)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for seen, expires := range s.seen {
+		if now.After(expires) {
+			delete(s.seen, seen)
+		}
+	}
+	if _, replayed := s.seen[sig]; replayed {
+		return "", "", fmt.Errorf("%w: replayed request from hub %s", ErrUnauthenticated, hub)
+	}
+	s.seen[sig] = time.Unix(signedAt, 0).Add(MaxClockSkew)
+	return hub, sig, nil
+}
+
+// SignResponse sets the headers that let the hub verify a response came
+// from this instance and answers its request
+func (s *Spoke) SignResponse(header http.Header, hub, requestSig string, status int, body []byte) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	header.Set(HeaderPeer, s.instance)
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderSignature, responseSignature(s.hubs[hub], s.instance, timestamp, requestSig, status, body))
+}
+
+// signRequest sets the headers that let a spoke authenticate a request for
+// the API path, which excludes any prefix in the spoke's URL that a proxy
+// in front of it strips
+func signRequest(req *http.Request, path, instance, secret string, body []byte, now time.Time) string {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	sig := requestSignature(secret, instance, timestamp, req.Method, path, body)
+	req.Header.Set(HeaderPeer, instance)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, sig)
+	return sig
+}
+
+// verifyResponse checks that a response was signed by the spoke for the
+// request carrying requestSig
+func verifyResponse(resp *http.Response, spoke Peer, requestSig string, body []byte) error {
+	if name := resp.Header.Get(HeaderPeer); name != spoke.Name {
+		return fmt.Errorf("%w: response signed by %q, not spoke %s", ErrUnauthenticated, name, spoke.Name)
+	}
+	want := responseSignature(spoke.Secret, spoke.Name, resp.Header.Get(HeaderTimestamp), requestSig, resp.StatusCode, body)
+	if !hmac.Equal([]byte(resp.Header.Get(HeaderSignature)), []byte(want)) {
+		return fmt.Errorf("%w: invalid response signature from spoke %s", ErrUnauthenticated, spoke.Name)
+	}
+	return nil
+}
+
+// requestSignature signs a request's sender, time, method, path and body
+func requestSignature(secret, peer, timestamp, method, path string, body []byte) string {
+	return signature(secret, "request", peer, timestamp, method, path, bodyHash(body))
+}
+
+// responseSignature signs a response's sender, time, status and body, and
+// the request it answers so it can't be replayed to another request
+func responseSignature(secret, peer, timestamp, requestSig string, status int, body []byte) string {
+	return signature(secret, "response", peer, timestamp, requestSig, strconv.Itoa(status), bodyHash(body))
+}
+
+func signature(secret string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v1\n" + strings.Join(parts, "\n")))
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,277 @@
+// Package federation lets a central KubePulse instance, the hub, request AI
+// analyses from instances running next to other clusters, the spokes, and
+// combine them into one view. Hubs and spokes authenticate each other with a
+// secret shared per pair: the hub signs each request and the spoke signs its
+// response to it.
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// SpokeAnalyzePath is the spoke endpoint hubs request analyses from
+const SpokeAnalyzePath = "/api/v1/federation/spoke/analyze"
+
+// DefaultTimeout bounds how long a hub waits for each spoke
+const DefaultTimeout = time.Minute
+
+// maxResponseSize bounds how much of a spoke's response is read
+const maxResponseSize = 10 << 20
+
+// AnalysisRequest selects the checks a spoke analyzes; without checks every
+// check that isn't healthy is analyzed
+type AnalysisRequest struct {
+	Checks []string `json:"checks,omitempty"`
+}
+
+// CheckSummary is the outcome of a failing check on a spoke
+type CheckSummary struct {
+	Name    string            `json:"name"`
+	Status  core.HealthStatus `json:"status"`
+	Message string            `json:"message"`
+}
+
+// SpokeReport is a spoke's answer to an analysis request: its cluster's
+// health and, when the spoke has AI enabled, the analysis of its checks
+type SpokeReport struct {
+	Instance  string            `json:"instance"`
+	Cluster   string            `json:"cluster"`
+	Status    core.HealthStatus `json:"status"`
+	Score     float64           `json:"score"`
+	Failing   []CheckSummary    `json:"failing,omitempty"`
+	Analysis  *ai.BatchAnalysis `json:"analysis,omitempty"`
+	AIError   string            `json:"ai_error,omitempty"` // Why there is no analysis
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// SpokeResult is what a hub got back from one spoke
+type SpokeResult struct {
+	Spoke    string        `json:"spoke"`
+	Report   *SpokeReport  `json:"report,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Diagnosis is one check diagnosis from a spoke's analysis
+type Diagnosis struct {
+	Spoke   string `json:"spoke"`
+	Cluster string `json:"cluster"`
+	ai.CheckDiagnosis
+}
+
+// Report combines the analyses of several spokes
+type Report struct {
+	Status     core.HealthStatus `json:"status"` // Worst across spokes, counting unreachable ones as unknown
+	Spokes     []SpokeResult     `json:"spokes"`
+	Diagnoses  []Diagnosis       `json:"diagnoses"` // Most severe and confident first
+	AnalyzedAt time.Time         `json:"analyzed_at"`
+}
+
+// NewSpokeReport summarizes a spoke's cluster health and the analysis of
+// its checks, which is nil when the spoke has AI disabled
+func NewSpokeReport(instance string, health core.ClusterHealth, analysis *ai.BatchAnalysis) SpokeReport {
+	report := SpokeReport{
+		Instance:  instance,
+		Cluster:   health.ClusterName,
+		Status:    health.Status,
+		Score:     health.Score.Weighted,
+		Analysis:  analysis,
+		Timestamp: health.Timestamp,
+	}
+	for _, result := range health.Checks {
+		if result.Status != core.HealthStatusHealthy {
+			report.Failing = append(report.Failing, CheckSummary{Name: result.Name, Status: result.Status, Message: result.Message})
+		}
+	}
+	sort.Slice(report.Failing, func(i, j int) bool { return report.Failing[i].Name < report.Failing[j].Name })
+	return report
+}
+
+// Hub requests analyses from spokes in parallel
+type Hub struct {
+	instance string
+	spokes   []Peer
+	timeout  time.Duration
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewHub creates a hub named instance requesting analyses from spokes, each
+// given at most timeout, or returns nil when no spokes are configured
+func NewHub(instance string, spokes []Peer, timeout time.Duration) *Hub {
+	if len(spokes) == 0 {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Hub{
+		instance: instance,
+		spokes:   spokes,
+		timeout:  timeout,
+		client:   &http.Client{Timeout: timeout},
+		now:      time.Now,
+	}
+}
+
+// Spokes returns the names of the configured spokes
+func (h *Hub) Spokes() []string {
+	names := make([]string, len(h.spokes))
+	for i, spoke := range h.spokes {
+		names[i] = spoke.Name
+	}
+	return names
+}
+
+// Timeout is how long the hub waits for each spoke
+func (h *Hub) Timeout() time.Duration {
+	return h.timeout
+}
+
+// Analyze requests analyses of checks from the named spokes, or from every
+// spoke when none are named, and combines them. Spokes that fail are
+// reported with their error rather than failing the whole request.
+func (h *Hub) Analyze(ctx context.Context, spokes []string, req AnalysisRequest) (Report, error) {
+	selected := h.spokes
+	if len(spokes) > 0 {
+		byName := make(map[string]Peer, len(h.spokes))
+		for _, spoke := range h.spokes {
+			byName[spoke.Name] = spoke
+		}
+		selected = make([]Peer, 0, len(spokes))
+		for _, name := range spokes {
+			spoke, ok := byName[name]
+			if !ok {
+				return Report{}, fmt.Errorf("unknown spoke %q", name)
+			}
+			selected = append(selected, spoke)
+		}
+	}
+
+	report := Report{Spokes: make([]SpokeResult, len(selected)), Diagnoses: []Diagnosis{}, AnalyzedAt: h.now()}
+	var wg sync.WaitGroup
+	for i, spoke := range selected {
+		wg.Add(1)
+		go func(i int, spoke Peer) {
+			defer wg.Done()
+			report.Spokes[i] = h.analyzeSpoke(ctx, spoke, req)
+		}(i, spoke)
+	}
+	wg.Wait()
+
+	statuses := make([]core.HealthStatus, 0, len(report.Spokes))
+	for _, result := range report.Spokes {
+		if result.Report == nil {
+			statuses = append(statuses, core.HealthStatusUnknown)
+			continue
+		}
+		statuses = append(statuses, result.Report.Status)
+		if result.Report.Analysis == nil {
+			continue
+		}
+		for _, diagnosis := range result.Report.Analysis.Diagnoses {
+			report.Diagnoses = append(report.Diagnoses, Diagnosis{
+				Spoke:          result.Spoke,
+				Cluster:        result.Report.Cluster,
+				CheckDiagnosis: diagnosis,
+			})
+		}
+	}
+	report.Status = core.WorstStatus(statuses...)
+	sort.SliceStable(report.Diagnoses, func(i, j int) bool {
+		a, b := report.Diagnoses[i], report.Diagnoses[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) > severityRank(b.Severity)
+		}
+		return a.Confidence > b.Confidence
+	})
+	return report, nil
+}
+
+// analyzeSpoke requests one spoke's analysis within the hub's timeout
+func (h *Hub) analyzeSpoke(ctx context.Context, spoke Peer, req AnalysisRequest) SpokeResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := h.now()
+	result := SpokeResult{Spoke: spoke.Name}
+	var report SpokeReport
+	if err := h.call(ctx, spoke, SpokeAnalyzePath, req, &report); err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Report = &report
+	}
+	result.Duration = h.now().Sub(start)
+	return result
+}
+
+// call sends a signed request to a spoke and decodes its response once the
+// spoke's signature is verified
+func (h *Hub) call(ctx context.Context, spoke Peer, path string, in, out interface{}) error {
+	payload, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(spoke.URL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	requestSig := signRequest(req, path, h.instance, spoke.Secret, payload, h.now())
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		message := strings.TrimSpace(string(body))
+		if json.Unmarshal(body, &apiError) == nil && apiError.Message != "" {
+			message = apiError.Message
+		}
+		return fmt.Errorf("spoke returned status %d: %s", resp.StatusCode, message)
+	}
+	if err := verifyResponse(resp, spoke, requestSig, body); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// severityRank orders diagnosis severities, most severe highest
+func severityRank(severity ai.SeverityLevel) int {
+	switch severity {
+	case ai.SeverityCritical:
+		return 4
+	case ai.SeverityHigh:
+		return 3
+	case ai.SeverityMedium:
+		return 2
+	case ai.SeverityLow:
+		return 1
+	}
+	return 0
+}
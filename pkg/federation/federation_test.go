@@ -0,0 +1,209 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/ai"
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// newTestSpoke serves signed analyses like the API server's spoke endpoint
+func newTestSpoke(t *testing.T, name string, hubs []Peer, report SpokeReport) *httptest.Server {
+	t.Helper()
+	spoke := NewSpoke(name, hubs)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		hub, sig, err := spoke.Authenticate(r, body)
+		if err != nil {
+			http.Error(w, `{"message":"`+err.Error()+`"}`, http.StatusUnauthorized)
+			return
+		}
+		data, _ := json.Marshal(report)
+		spoke.SignResponse(w.Header(), hub, sig, http.StatusOK, data)
+		_, _ = w.Write(data)
+	}))
+}
+
+func TestHub_Analyze(t *testing.T) {
+	hubs := []Peer{{Name: "hub", Secret: testSecret}}
+	us := newTestSpoke(t, "prod-us", hubs, SpokeReport{
+		Instance: "prod-us",
+		Cluster:  "prod-us-1",
+		Status:   core.HealthStatusDegraded,
+		Analysis: &ai.BatchAnalysis{Diagnoses: []ai.CheckDiagnosis{
+			{Check: "pod-health", Severity: ai.SeverityMedium, Confidence: 0.9},
+			{Check: "node-health", Severity: ai.SeverityCritical, Confidence: 0.5},
+		}},
+	})
+	defer us.Close()
+	eu := newTestSpoke(t, "prod-eu", hubs, SpokeReport{
+		Instance: "prod-eu",
+		Cluster:  "prod-eu-1",
+		Status:   core.HealthStatusHealthy,
+		Analysis: &ai.BatchAnalysis{Diagnoses: []ai.CheckDiagnosis{{Check: "pod-health", Severity: ai.SeverityMedium, Confidence: 0.95}}},
+	})
+	defer eu.Close()
+	rogue := newTestSpoke(t, "prod-ap", []Peer{{Name: "hub", Secret: strings.Repeat("x", 32)}}, SpokeReport{})
+	defer rogue.Close()
+
+	hub := NewHub("hub", []Peer{
+		{Name: "prod-us", URL: us.URL, Secret: testSecret},
+		{Name: "prod-eu", URL: eu.URL + "/", Secret: testSecret},
+		{Name: "prod-ap", URL: rogue.URL, Secret: testSecret},
+	}, 5*time.Second)
+
+	report, err := hub.Analyze(context.Background(), nil, AnalysisRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != core.HealthStatusDegraded {
+		t.Errorf("expected the worst spoke status, got %s", report.Status)
+	}
+	if len(report.Spokes) != 3 || report.Spokes[0].Spoke != "prod-us" || report.Spokes[0].Report == nil {
+		t.Fatalf("unexpected spokes: %+v", report.Spokes)
+	}
+	if report.Spokes[2].Report != nil || !strings.Contains(report.Spokes[2].Error, "status 401") {
+		t.Errorf("expected the spoke with another secret to reject the hub, got %+v", report.Spokes[2])
+	}
+
+	var order []string
+	for _, diagnosis := range report.Diagnoses {
+		order = append(order, diagnosis.Cluster+"/"+diagnosis.Check)
+	}
+	if got := strings.Join(order, ","); got != "prod-us-1/node-health,prod-eu-1/pod-health,prod-us-1/pod-health" {
+		t.Errorf("expected diagnoses by severity then confidence, got %s", got)
+	}
+
+	report, err = hub.Analyze(context.Background(), []string{"prod-eu"}, AnalysisRequest{Checks: []string{"pod-health"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Status != core.HealthStatusHealthy || len(report.Spokes) != 1 {
+		t.Errorf("unexpected report for one spoke: %+v", report)
+	}
+
+	if _, err := hub.Analyze(context.Background(), []string{"staging"}, AnalysisRequest{}); err == nil {
+		t.Error("expected an error for an unknown spoke")
+	}
+}
+
+func TestHub_RejectsUnsignedResponses(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, r *http.Request)
+	}{
+		{
+			name: "unsigned",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(SpokeReport{Status: core.HealthStatusHealthy})
+			},
+		},
+		{
+			name: "signed by another instance",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				data, _ := json.Marshal(SpokeReport{Status: core.HealthStatusHealthy})
+				NewSpoke("impostor", []Peer{{Name: "hub", Secret: testSecret}}).
+					SignResponse(w.Header(), "hub", r.Header.Get(HeaderSignature), http.StatusOK, data)
+				_, _ = w.Write(data)
+			},
+		},
+		{
+			name: "signed for another request",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				data, _ := json.Marshal(SpokeReport{Status: core.HealthStatusHealthy})
+				NewSpoke("prod-us", []Peer{{Name: "hub", Secret: testSecret}}).
+					SignResponse(w.Header(), "hub", "v1=earlier", http.StatusOK, data)
+				_, _ = w.Write(data)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(tt.respond))
+			defer server.Close()
+
+			hub := NewHub("hub", []Peer{{Name: "prod-us", URL: server.URL, Secret: testSecret}}, time.Second)
+			var report SpokeReport
+			err := hub.call(context.Background(), hub.spokes[0], SpokeAnalyzePath, AnalysisRequest{}, &report)
+			if !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("expected ErrUnauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSpoke_Authenticate(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	spoke := NewSpoke("prod-us", []Peer{{Name: "hub", Secret: testSecret}})
+	spoke.now = func() time.Time { return now }
+
+	body := []byte(`{"checks":["pod-health"]}`)
+	request := func(peer, secret string, signedAt time.Time, body []byte) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, SpokeAnalyzePath, bytes.NewReader(body))
+		signRequest(req, SpokeAnalyzePath, peer, secret, body, signedAt)
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		req     *http.Request
+		body    []byte
+		wantErr string
+	}{
+		{"valid", request("hub", testSecret, now.Add(-time.Minute), body), body, ""},
+		{"replayed", request("hub", testSecret, now.Add(-time.Minute), body), body, "replayed"},
+		{"unknown hub", request("other", testSecret, now, body), body, "unknown hub"},
+		{"wrong secret", request("hub", strings.Repeat("x", 32), now, body), body, "invalid signature"},
+		{"tampered body", request("hub", testSecret, now, body), []byte(`{"checks":[]}`), "invalid signature"},
+		{"stale", request("hub", testSecret, now.Add(-10*time.Minute), body), body, "from this instance's clock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub, sig, err := spoke.Authenticate(tt.req, tt.body)
+			if tt.wantErr == "" {
+				if err != nil || hub != "hub" || sig == "" {
+					t.Errorf("expected hub to authenticate, got %q %q %v", hub, sig, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnauthenticated) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if NewSpoke("prod-us", nil) != nil || NewHub("hub", nil, 0) != nil {
+		t.Error("expected no spoke or hub without peers")
+	}
+}
+
+func TestNewSpokeReport(t *testing.T) {
+	report := NewSpokeReport("prod-us", core.ClusterHealth{
+		ClusterName: "prod",
+		Status:      core.HealthStatusDegraded,
+		Score:       core.HealthScore{Weighted: 72},
+		Checks: []core.CheckResult{
+			{Name: "pod-health", Status: core.HealthStatusDegraded, Message: "2 pods pending"},
+			{Name: "dns", Status: core.HealthStatusHealthy},
+			{Name: "node-health", Status: core.HealthStatusUnhealthy, Message: "1 node not ready"},
+		},
+	}, nil)
+	if report.Cluster != "prod" || report.Score != 72 || report.Analysis != nil {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Failing) != 2 || report.Failing[0].Name != "node-health" || report.Failing[1].Name != "pod-health" {
+		t.Errorf("expected failing checks sorted by name, got %+v", report.Failing)
+	}
+}
@@ -0,0 +1,185 @@
+// Package findings defines the taxonomy of problems KubePulse reports. Each
+// type has a stable ID, such as KP-NODE-001 for node memory pressure, that
+// heuristic checks and AI diagnoses both use, so the same problem found by
+// different analyses is recognized as one finding and can be tracked over
+// time.
+package findings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Category groups finding types by the resource they concern
+type Category string
+
+const (
+	CategoryNode     Category = "node"
+	CategoryPod      Category = "pod"
+	CategoryWorkload Category = "workload"
+	CategoryService  Category = "service"
+	CategoryNetwork  Category = "network"
+	CategorySecurity Category = "security"
+)
+
+// Stable IDs of the finding types. IDs are never reused or renumbered;
+// retired types stay in the catalog so recorded findings still resolve.
+const (
+	NodeMemoryPressure = "KP-NODE-001"
+	NodeDiskPressure   = "KP-NODE-002"
+	NodePIDPressure    = "KP-NODE-003"
+	NodeNotReady       = "KP-NODE-004"
+	NodeHighCPU        = "KP-NODE-005"
+	NodeHighMemory     = "KP-NODE-006"
+	NodeVersionSkew    = "KP-NODE-007"
+	NodeOutdatedOS     = "KP-NODE-008"
+	NodeRebootRequired = "KP-NODE-009"
+	NodeKernelDeadlock = "KP-NODE-010"
+	NodeReadonlyFS     = "KP-NODE-011"
+	NodeRuntimeRestart = "KP-NODE-012"
+	NodeProblem        = "KP-NODE-013"
+
+	PodCrashLoop     = "KP-POD-001"
+	PodImagePull     = "KP-POD-002"
+	PodOOMKilled     = "KP-POD-003"
+	PodUnschedulable = "KP-POD-004"
+	PodHighRestarts  = "KP-POD-005"
+	PodStartFailure  = "KP-POD-006"
+	PodFailed        = "KP-POD-007"
+	PodNotReady      = "KP-POD-008"
+
+	WorkloadWebhook = "KP-WL-001"
+	WorkloadQuota   = "KP-WL-002"
+
+	ServiceNoEndpoint = "KP-SVC-001"
+
+	MeshNoSidecar    = "KP-NET-001"
+	MeshMTLSConflict = "KP-NET-002"
+
+	SecurityPrivileged     = "KP-SEC-001"
+	SecurityHostNamespaces = "KP-SEC-002"
+	SecurityHostAccess     = "KP-SEC-003"
+	SecurityCapabilities   = "KP-SEC-004"
+	SecurityRunAsRoot      = "KP-SEC-005"
+	SecurityEscalation     = "KP-SEC-006"
+	SecuritySeccomp        = "KP-SEC-007"
+)
+
+// Type is a kind of problem with a stable ID
+type Type struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Category    Category `json:"category"`
+	Severity    string   `json:"severity"` // critical, high, medium or low
+	Description string   `json:"description"`
+}
+
+// String returns the ID and title, e.g. "KP-NODE-001 Node memory pressure"
+func (t Type) String() string {
+	return t.ID + " " + t.Title
+}
+
+var catalog = []Type{
+	{NodeMemoryPressure, "Node memory pressure", CategoryNode, "high", "The kubelet reports MemoryPressure and may evict pods"},
+	{NodeDiskPressure, "Node disk pressure", CategoryNode, "high", "The kubelet reports DiskPressure and may evict pods or garbage-collect images"},
+	{NodePIDPressure, "Node PID pressure", CategoryNode, "high", "The kubelet reports PIDPressure; new processes may fail to start"},
+	{NodeNotReady, "Node not ready", CategoryNode, "critical", "The node's Ready condition is not true and its pods are not scheduled or served"},
+	{NodeHighCPU, "High node CPU usage", CategoryNode, "medium", "Node CPU usage is above the check's threshold"},
+	{NodeHighMemory, "High node memory usage", CategoryNode, "medium", "Node memory usage is above the check's threshold"},
+	{NodeVersionSkew, "Kubelet version skew", CategoryNode, "high", "The kubelet is newer than the control plane or more minor versions behind it than Kubernetes supports"},
+	{NodeOutdatedOS, "Outdated node OS image", CategoryNode, "low", "The node runs an older release of its OS image than other nodes of the same distribution"},
+	{NodeRebootRequired, "Node reboot pending", CategoryNode, "medium", "The node needs a reboot, e.g. to finish applying kernel or package updates"},
+	{NodeKernelDeadlock, "Node kernel deadlock", CategoryNode, "critical", "Node problem detector found hung kernel tasks; processes on the node, including the container runtime, may be stuck"},
+	{NodeReadonlyFS, "Node filesystem read-only", CategoryNode, "critical", "The kernel remounted a node filesystem read-only after an I/O error; pods can't write to it"},
+	{NodeRuntimeRestart, "Node runtime restarting", CategoryNode, "high", "The kubelet or container runtime on the node keeps restarting, disrupting the pods it manages"},
+	{NodeProblem, "Node problem detected", CategoryNode, "medium", "A problem detector on the node reports a condition outside the kubelet's own"},
+	{PodCrashLoop, "Container crash looping", CategoryPod, "high", "A container keeps exiting and is in CrashLoopBackOff"},
+	{PodImagePull, "Image pull failure", CategoryPod, "high", "A container image can't be pulled: ErrImagePull, ImagePullBackOff or InvalidImageName"},
+	{PodOOMKilled, "Container OOM killed", CategoryPod, "high", "A container was killed for exceeding its memory limit"},
+	{PodUnschedulable, "Pod unschedulable", CategoryPod, "high", "The scheduler can't place the pod on any node"},
+	{PodHighRestarts, "Excessive container restarts", CategoryPod, "medium", "A pod's containers restarted more often than the restart threshold"},
+	{PodStartFailure, "Container fails to start", CategoryPod, "high", "A container can't be created or run: CreateContainerError or RunContainerError"},
+	{PodFailed, "Pod failed", CategoryPod, "high", "The pod terminated in the Failed phase"},
+	{PodNotReady, "Pod not ready", CategoryPod, "medium", "A running pod's readiness probe is failing"},
+	{WorkloadWebhook, "Pod creation rejected by admission webhook", CategoryWorkload, "high", "A controller can't create pods because an admission webhook denies them"},
+	{WorkloadQuota, "Pod creation exceeds resource quota", CategoryWorkload, "high", "A controller can't create pods because a resource quota is exhausted"},
+	{ServiceNoEndpoint, "Service has no endpoints", CategoryService, "high", "No ready pods back the service, so requests to it fail"},
+	{MeshNoSidecar, "Pod missing mesh sidecar", CategoryNetwork, "medium", "A pod in a mesh-enabled namespace runs without its proxy sidecar"},
+	{MeshMTLSConflict, "Conflicting mTLS policies", CategoryNetwork, "high", "Mesh mTLS modes disagree between callers and backends, so connections are refused"},
+	{SecurityPrivileged, "Privileged container", CategorySecurity, "high", "A container runs privileged, with full access to the node, in a namespace whose Pod Security level forbids it"},
+	{SecurityHostNamespaces, "Pod shares host namespaces", CategorySecurity, "high", "A pod uses hostNetwork, hostPID or hostIPC in a namespace whose Pod Security level forbids it"},
+	{SecurityHostAccess, "Host path or port access", CategorySecurity, "medium", "A pod mounts hostPath volumes or binds host ports in a namespace whose Pod Security level forbids it"},
+	{SecurityCapabilities, "Container capabilities exceed policy", CategorySecurity, "medium", "A container adds Linux capabilities, or keeps ones it must drop, beyond its namespace's Pod Security level"},
+	{SecurityRunAsRoot, "Container may run as root", CategorySecurity, "medium", "runAsNonRoot is not set to true, which the restricted Pod Security level requires"},
+	{SecurityEscalation, "Privilege escalation allowed", CategorySecurity, "medium", "allowPrivilegeEscalation is not set to false, which the restricted Pod Security level requires"},
+	{SecuritySeccomp, "Missing seccomp profile", CategorySecurity, "low", "No RuntimeDefault or Localhost seccomp profile is set, which the restricted Pod Security level requires"},
+}
+
+var byID = func() map[string]Type {
+	types := make(map[string]Type, len(catalog))
+	for _, t := range catalog {
+		types[t.ID] = t
+	}
+	return types
+}()
+
+// Catalog returns every finding type, ordered by ID
+func Catalog() []Type {
+	types := make([]Type, len(catalog))
+	copy(types, catalog)
+	sort.Slice(types, func(i, j int) bool { return types[i].ID < types[j].ID })
+	return types
+}
+
+// Lookup returns the type with an ID, matched case-insensitively
+func Lookup(id string) (Type, bool) {
+	t, ok := byID[strings.ToUpper(strings.TrimSpace(id))]
+	return t, ok
+}
+
+// Finding is an occurrence of a finding type on a subject
+type Finding struct {
+	ID      string `json:"id"`                // Finding type ID, e.g. KP-NODE-001
+	Subject string `json:"subject,omitempty"` // Affected resource, e.g. node/worker-1 or pod/shop/api-1
+	Message string `json:"message,omitempty"`
+}
+
+// Key identifies the finding across analyses: its type and subject
+func (f Finding) Key() string {
+	return f.ID + " " + f.Subject
+}
+
+// String describes the finding, e.g. "KP-NODE-001 Node memory pressure on
+// node/worker-1"
+func (f Finding) String() string {
+	name := f.ID
+	if t, ok := byID[f.ID]; ok {
+		name = t.String()
+	}
+	if f.Subject == "" {
+		return name
+	}
+	return fmt.Sprintf("%s on %s", name, f.Subject)
+}
+
+// Normalize canonicalizes finding IDs and subjects and drops findings of
+// unknown types and repeats of the same type and subject
+func Normalize(list []Finding) []Finding {
+	normalized := make([]Finding, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, finding := range list {
+		t, ok := Lookup(finding.ID)
+		if !ok {
+			continue
+		}
+		finding.ID = t.ID
+		finding.Subject = strings.TrimSpace(finding.Subject)
+		if seen[finding.Key()] {
+			continue
+		}
+		seen[finding.Key()] = true
+		normalized = append(normalized, finding)
+	}
+	return normalized
+}
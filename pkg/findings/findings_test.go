@@ -0,0 +1,74 @@
+package findings
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	idFormat := regexp.MustCompile(`^KP-[A-Z]+-\d{3}$`)
+	severities := map[string]bool{"critical": true, "high": true, "medium": true, "low": true}
+	seen := make(map[string]bool)
+	types := Catalog()
+	for i, typ := range types {
+		if !idFormat.MatchString(typ.ID) {
+			t.Errorf("%s: ID doesn't match KP-<CATEGORY>-<NNN>", typ.ID)
+		}
+		if seen[typ.ID] {
+			t.Errorf("%s: duplicate ID", typ.ID)
+		}
+		seen[typ.ID] = true
+		if typ.Title == "" || typ.Description == "" || typ.Category == "" {
+			t.Errorf("%s: title, description and category are required", typ.ID)
+		}
+		if !severities[typ.Severity] {
+			t.Errorf("%s: invalid severity %q", typ.ID, typ.Severity)
+		}
+		if i > 0 && types[i-1].ID >= typ.ID {
+			t.Errorf("catalog not ordered by ID at %s", typ.ID)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	typ, ok := Lookup(" kp-node-001 ")
+	if !ok || typ.ID != NodeMemoryPressure || typ.Title != "Node memory pressure" {
+		t.Errorf("Lookup() = %+v, %v", typ, ok)
+	}
+	if _, ok := Lookup("KP-NODE-999"); ok {
+		t.Error("expected unknown ID not to be found")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := Normalize([]Finding{
+		{ID: "kp-pod-001", Subject: " pod/shop/api-1 "},
+		{ID: PodCrashLoop, Subject: "pod/shop/api-1", Message: "repeat"},
+		{ID: "KP-MADE-UP-001", Subject: "pod/shop/api-1"},
+		{ID: PodCrashLoop, Subject: "pod/shop/api-2"},
+	})
+	want := []Finding{
+		{ID: PodCrashLoop, Subject: "pod/shop/api-1"},
+		{ID: PodCrashLoop, Subject: "pod/shop/api-2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	tests := []struct {
+		finding Finding
+		want    string
+	}{
+		{Finding{ID: NodeMemoryPressure, Subject: "node/worker-1"}, "KP-NODE-001 Node memory pressure on node/worker-1"},
+		{Finding{ID: ServiceNoEndpoint}, "KP-SVC-001 Service has no endpoints"},
+		{Finding{ID: "KP-X-001", Subject: "pod/a/b"}, "KP-X-001 on pod/a/b"},
+	}
+	for _, tt := range tests {
+		if got := tt.finding.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
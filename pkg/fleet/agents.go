@@ -0,0 +1,211 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Agent defaults and limits
+const (
+	DefaultAgentInterval = 30 * time.Second
+	MaxAgents            = 500            // Agents a server keeps reports for
+	MaxAgentChecks       = 100            // Checks in one report
+	AgentStaleAfter      = 3              // Missed intervals before an agent is reported unknown
+	AgentExpiry          = 24 * time.Hour // Silence after which an agent is forgotten
+)
+
+// ErrTooManyAgents is returned when a new agent reports to a server already
+// tracking MaxAgents
+var ErrTooManyAgents = errors.New("too many agents")
+
+// agentNamePattern restricts agent names to what kubeconfig context names
+// commonly use, such as EKS ARNs and user@cluster, and keeps them out of
+// control characters in logs
+var agentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:@/-]{0,252}$`)
+
+// AgentReport is what an agent pushes to the central server after each
+// round of checks
+type AgentReport struct {
+	Agent         string         `json:"agent"` // Unique name of the agent's cluster
+	ServerVersion string         `json:"server_version,omitempty"`
+	Interval      time.Duration  `json:"interval"` // How often the agent reports
+	Checks        []CheckSummary `json:"checks"`
+	CheckedAt     time.Time      `json:"checked_at"`
+}
+
+// Validate checks a report can be stored
+func (r AgentReport) Validate() error {
+	if !agentNamePattern.MatchString(r.Agent) {
+		return fmt.Errorf("agent must be 1-253 letters, digits or . _ : @ / - characters, starting with a letter or digit")
+	}
+	if len(r.Checks) > MaxAgentChecks {
+		return fmt.Errorf("a report can have at most %d checks", MaxAgentChecks)
+	}
+	for _, check := range r.Checks {
+		if check.Name == "" {
+			return fmt.Errorf("every check needs a name")
+		}
+		switch check.Status {
+		case core.HealthStatusHealthy, core.HealthStatusDegraded, core.HealthStatusUnhealthy, core.HealthStatusUnknown, core.HealthStatusRestricted:
+		default:
+			return fmt.Errorf("check %s has invalid status %q", check.Name, check.Status)
+		}
+	}
+	return nil
+}
+
+// agentState is the last report of an agent and when it arrived
+type agentState struct {
+	report   AgentReport
+	received time.Time
+}
+
+// AgentRegistry keeps the latest report of each agent, so agents appear as
+// clusters in fleet views
+type AgentRegistry struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	agents map[string]agentState
+}
+
+// NewAgentRegistry creates an empty registry
+func NewAgentRegistry() *AgentRegistry {
+	return &AgentRegistry{now: time.Now, agents: make(map[string]agentState)}
+}
+
+// Record stores an agent's report, replacing its previous one
+func (r *AgentRegistry) Record(report AgentReport) error {
+	if err := report.Validate(); err != nil {
+		return err
+	}
+	if report.Interval <= 0 {
+		report.Interval = DefaultAgentInterval
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.expire(now)
+	if _, known := r.agents[report.Agent]; !known && len(r.agents) >= MaxAgents {
+		return fmt.Errorf("%w: at most %d agents can report", ErrTooManyAgents, MaxAgents)
+	}
+	r.agents[report.Agent] = agentState{report: report, received: now}
+	return nil
+}
+
+// Summaries returns the health of every agent by name. Agents that missed
+// AgentStaleAfter reports are unknown.
+func (r *AgentRegistry) Summaries() []Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.expire(now)
+
+	summaries := make([]Summary, 0, len(r.agents))
+	for _, state := range r.agents {
+		summary := Summary{
+			Context:       state.report.Agent,
+			Status:        worstCheckStatus(state.report.Checks),
+			ServerVersion: state.report.ServerVersion,
+			Checks:        state.report.Checks,
+			Agent:         true,
+			ReportedAt:    state.received,
+		}
+		if silent := now.Sub(state.received); silent > AgentStaleAfter*state.report.Interval {
+			summary.Status = core.HealthStatusUnknown
+			summary.Error = fmt.Sprintf("no report for %s; the agent reports every %s", silent.Round(time.Second), state.report.Interval)
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Context < summaries[j].Context })
+	return summaries
+}
+
+// expire forgets agents silent for AgentExpiry; callers hold r.mu
+func (r *AgentRegistry) expire(now time.Time) {
+	for name, state := range r.agents {
+		if now.Sub(state.received) > AgentExpiry {
+			delete(r.agents, name)
+		}
+	}
+}
+
+// AgentConfig configures an Agent
+type AgentConfig struct {
+	Name     string // Name the agent's cluster reports under
+	Client   kubernetes.Interface
+	Checks   []core.HealthCheck
+	Interval time.Duration // Between rounds of checks; defaults to DefaultAgentInterval
+
+	// Push sends a report to the central server
+	Push func(ctx context.Context, report AgentReport) error
+}
+
+// Agent runs health checks on a cluster and pushes their summaries to a
+// central server. It keeps no history and runs no AI, alerting or API, so
+// it fits clusters with tight resource budgets.
+type Agent struct {
+	config AgentConfig
+}
+
+// NewAgent creates an agent, filling in the default interval
+func NewAgent(config AgentConfig) *Agent {
+	if config.Interval <= 0 {
+		config.Interval = DefaultAgentInterval
+	}
+	return &Agent{config: config}
+}
+
+// Run checks and reports every interval until ctx is done. A report that
+// can't be pushed is logged and dropped; the next round reports afresh.
+func (a *Agent) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		a.report(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// report runs one round of checks and pushes it
+func (a *Agent) report(ctx context.Context) {
+	report := a.Collect(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err := a.config.Push(ctx, report); err != nil {
+		klog.Warningf("Failed to push agent report: %v", err)
+		return
+	}
+	klog.V(2).Infof("Pushed agent report: %s with %d checks", worstCheckStatus(report.Checks), len(report.Checks))
+}
+
+// Collect runs the checks once, giving them at most an interval to finish
+func (a *Agent) Collect(ctx context.Context) AgentReport {
+	ctx, cancel := context.WithTimeout(ctx, a.config.Interval)
+	defer cancel()
+
+	report := AgentReport{Agent: a.config.Name, Interval: a.config.Interval}
+	if version, err := a.config.Client.Discovery().ServerVersion(); err == nil {
+		report.ServerVersion = version.GitVersion
+	} else {
+		klog.Warningf("Cluster unreachable: %v", err)
+	}
+	report.Checks = runChecks(ctx, a.config.Client, a.config.Checks)
+	report.CheckedAt = time.Now()
+	return report
+}
@@ -0,0 +1,159 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAgentReport_Validate(t *testing.T) {
+	tooMany := make([]CheckSummary, MaxAgentChecks+1)
+	for i := range tooMany {
+		tooMany[i] = CheckSummary{Name: fmt.Sprintf("check-%d", i), Status: core.HealthStatusHealthy}
+	}
+
+	tests := []struct {
+		name    string
+		report  AgentReport
+		wantErr string
+	}{
+		{name: "valid", report: AgentReport{Agent: "prod-eu", Checks: []CheckSummary{{Name: "node-health", Status: core.HealthStatusHealthy}}}},
+		{name: "eks context", report: AgentReport{Agent: "arn:aws:eks:eu-west-1:123456789012:cluster/prod"}},
+		{name: "no name", report: AgentReport{}, wantErr: "agent must be"},
+		{name: "bad name", report: AgentReport{Agent: "prod\nfake log line"}, wantErr: "agent must be"},
+		{name: "too many checks", report: AgentReport{Agent: "prod", Checks: tooMany}, wantErr: "at most"},
+		{name: "unnamed check", report: AgentReport{Agent: "prod", Checks: []CheckSummary{{Status: core.HealthStatusHealthy}}}, wantErr: "needs a name"},
+		{name: "bad status", report: AgentReport{Agent: "prod", Checks: []CheckSummary{{Name: "node-health", Status: "fine"}}}, wantErr: "invalid status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.report.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAgentRegistry_Summaries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	registry := NewAgentRegistry()
+	registry.now = func() time.Time { return now }
+
+	reports := []AgentReport{
+		{Agent: "staging", Interval: time.Minute, Checks: []CheckSummary{
+			{Name: "node-health", Status: core.HealthStatusHealthy},
+			{Name: "pod-health", Status: core.HealthStatusDegraded},
+		}},
+		{Agent: "prod", ServerVersion: "v1.31.0", Checks: []CheckSummary{{Name: "node-health", Status: core.HealthStatusHealthy}}},
+	}
+	for _, report := range reports {
+		if err := registry.Record(report); err != nil {
+			t.Fatalf("record %s: %v", report.Agent, err)
+		}
+	}
+
+	summaries := registry.Summaries()
+	if len(summaries) != 2 || summaries[0].Context != "prod" || summaries[1].Context != "staging" {
+		t.Fatalf("expected summaries sorted by agent, got %+v", summaries)
+	}
+	prod, staging := summaries[0], summaries[1]
+	if !prod.Agent || prod.Status != core.HealthStatusHealthy || prod.ServerVersion != "v1.31.0" || !prod.ReportedAt.Equal(now) {
+		t.Errorf("unexpected prod summary %+v", prod)
+	}
+	if staging.Status != core.HealthStatusDegraded {
+		t.Errorf("expected staging to take its worst check status, got %s", staging.Status)
+	}
+
+	// prod reports every DefaultAgentInterval and staging every minute, so
+	// after two minutes only prod has missed three reports
+	now = now.Add(2 * time.Minute)
+	summaries = registry.Summaries()
+	if summaries[0].Status != core.HealthStatusUnknown || !strings.Contains(summaries[0].Error, "no report for 2m0s") {
+		t.Errorf("expected prod to be stale, got %+v", summaries[0])
+	}
+	if summaries[1].Status != core.HealthStatusDegraded || summaries[1].Error != "" {
+		t.Errorf("expected staging to still be current, got %+v", summaries[1])
+	}
+
+	now = now.Add(AgentExpiry)
+	if summaries := registry.Summaries(); len(summaries) != 0 {
+		t.Errorf("expected silent agents to be forgotten, got %+v", summaries)
+	}
+}
+
+func TestAgentRegistry_MaxAgents(t *testing.T) {
+	registry := NewAgentRegistry()
+	for i := 0; i < MaxAgents; i++ {
+		if err := registry.Record(AgentReport{Agent: fmt.Sprintf("cluster-%d", i)}); err != nil {
+			t.Fatalf("record agent %d: %v", i, err)
+		}
+	}
+
+	if err := registry.Record(AgentReport{Agent: "one-too-many"}); !errors.Is(err, ErrTooManyAgents) {
+		t.Fatalf("expected ErrTooManyAgents, got %v", err)
+	}
+	if err := registry.Record(AgentReport{Agent: "cluster-0"}); err != nil {
+		t.Fatalf("expected a known agent to keep reporting, got %v", err)
+	}
+}
+
+func TestAgent_Run(t *testing.T) {
+	client := fake.NewSimpleClientset(readyNode("node-1", corev1.ConditionTrue))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan AgentReport, 1)
+	agent := NewAgent(AgentConfig{
+		Name:     "edge-1",
+		Client:   client,
+		Checks:   []core.HealthCheck{health.NewNodeHealthCheck()},
+		Interval: time.Hour,
+		Push: func(ctx context.Context, report AgentReport) error {
+			reports <- report
+			return nil
+		},
+	})
+	done := make(chan struct{})
+	go func() {
+		agent.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case report := <-reports:
+		if report.Agent != "edge-1" || report.Interval != time.Hour || report.CheckedAt.IsZero() {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if len(report.Checks) != 1 || report.Checks[0].Status != core.HealthStatusHealthy {
+			t.Errorf("expected a healthy node check, got %+v", report.Checks)
+		}
+		if err := report.Validate(); err != nil {
+			t.Errorf("expected the report to be valid, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the agent to report right away")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the agent to stop when its context is done")
+	}
+}
@@ -0,0 +1,177 @@
+// Package fleet probes several Kubernetes contexts at once and summarizes
+// each, for cheap visibility across a fleet of clusters without running a
+// monitoring engine per cluster. Clusters the server can't reach can run a
+// lightweight Agent that pushes its results to the server instead.
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/health"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultTimeout bounds how long a single context is probed
+const DefaultTimeout = 10 * time.Second
+
+// MaxContexts is the most contexts probed in one request
+const MaxContexts = 20
+
+// ClientFactory creates a short-lived client for a context whose requests
+// give up after timeout
+type ClientFactory func(contextName string, timeout time.Duration) (kubernetes.Interface, error)
+
+// CheckSummary is the outcome of one probe check
+type CheckSummary struct {
+	Name    string            `json:"name"`
+	Status  core.HealthStatus `json:"status"`
+	Message string            `json:"message"`
+}
+
+// Summary is the health of one context
+type Summary struct {
+	Context       string            `json:"context"`
+	Status        core.HealthStatus `json:"status"` // Unknown when the cluster couldn't be probed
+	ServerVersion string            `json:"server_version,omitempty"`
+	Checks        []CheckSummary    `json:"checks,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	TimedOut      bool              `json:"timed_out,omitempty"`
+	Duration      time.Duration     `json:"duration"`
+	Agent         bool              `json:"agent,omitempty"`      // Reported by an agent rather than probed
+	ReportedAt    time.Time         `json:"reported_at,omitzero"` // When the agent last reported
+}
+
+// Report is the health of every probed context
+type Report struct {
+	Status    core.HealthStatus `json:"status"` // Worst status across contexts
+	Contexts  []Summary         `json:"contexts"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// Prober runs a lightweight set of health checks against several contexts
+// in parallel
+type Prober struct {
+	clients ClientFactory
+	checks  func() []core.HealthCheck
+}
+
+// NewProber creates a prober that runs the node and pod checks, without
+// log or scheduling analysis, against clients from the factory
+func NewProber(clients ClientFactory) *Prober {
+	return &Prober{clients: clients, checks: probeChecks}
+}
+
+// probeChecks returns fresh instances of the checks run against each context
+func probeChecks() []core.HealthCheck {
+	pods := health.NewPodHealthCheck()
+	_ = pods.Configure(map[string]interface{}{"log_analysis": false, "scheduling_analysis": false})
+	return []core.HealthCheck{health.NewNodeHealthCheck(), pods}
+}
+
+// Probe checks each context in parallel, giving each at most timeout, and
+// returns their summaries in the order requested
+func (p *Prober) Probe(ctx context.Context, contexts []string, timeout time.Duration) Report {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	report := Report{Contexts: make([]Summary, len(contexts)), CheckedAt: time.Now()}
+	var wg sync.WaitGroup
+	for i, name := range contexts {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			report.Contexts[i] = p.probe(ctx, name, timeout)
+		}(i, name)
+	}
+	wg.Wait()
+
+	statuses := make([]core.HealthStatus, len(report.Contexts))
+	for i, summary := range report.Contexts {
+		statuses[i] = summary.Status
+	}
+	report.Status = core.WorstStatus(statuses...)
+	return report
+}
+
+// probe summarizes one context, reporting it as timed out when the checks
+// don't finish in time
+func (p *Prober) probe(ctx context.Context, name string, timeout time.Duration) Summary {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan Summary, 1)
+	go func() { done <- p.run(ctx, name, timeout) }()
+
+	select {
+	case summary := <-done:
+		summary.Duration = time.Since(start)
+		return summary
+	case <-ctx.Done():
+		return Summary{
+			Context:  name,
+			Status:   core.HealthStatusUnknown,
+			Error:    fmt.Sprintf("no answer within %s", timeout),
+			TimedOut: true,
+			Duration: time.Since(start),
+		}
+	}
+}
+
+// run creates a client for the context and runs the probe checks
+func (p *Prober) run(ctx context.Context, name string, timeout time.Duration) Summary {
+	summary := Summary{Context: name, Status: core.HealthStatusUnknown}
+	client, err := p.clients(name, timeout)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		summary.Error = fmt.Sprintf("cluster unreachable: %v", err)
+		return summary
+	}
+	summary.ServerVersion = version.GitVersion
+
+	summary.Checks = runChecks(ctx, client, p.checks())
+	summary.Status = worstCheckStatus(summary.Checks)
+	return summary
+}
+
+// runChecks runs checks in parallel and summarizes them by name; a check
+// that fails to run is unknown
+func runChecks(ctx context.Context, client kubernetes.Interface, checks []core.HealthCheck) []CheckSummary {
+	summaries := make([]CheckSummary, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check core.HealthCheck) {
+			defer wg.Done()
+			result, err := check.Check(ctx, client)
+			summaries[i] = CheckSummary{Name: check.Name(), Status: result.Status, Message: result.Message}
+			if err != nil {
+				summaries[i].Status = core.HealthStatusUnknown
+				summaries[i].Message = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// worstCheckStatus returns the worst status of the checks
+func worstCheckStatus(checks []CheckSummary) core.HealthStatus {
+	statuses := make([]core.HealthStatus, len(checks))
+	for i, check := range checks {
+		statuses[i] = check.Status
+	}
+	return core.WorstStatus(statuses...)
+}
@@ -0,0 +1,70 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readyNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+	}
+}
+
+func TestProber_Probe(t *testing.T) {
+	clusters := map[string]kubernetes.Interface{
+		"prod":    fake.NewSimpleClientset(readyNode("prod-1", corev1.ConditionTrue)),
+		"staging": fake.NewSimpleClientset(readyNode("staging-1", corev1.ConditionFalse)),
+	}
+	hang := make(chan struct{})
+	defer close(hang)
+
+	prober := NewProber(func(name string, timeout time.Duration) (kubernetes.Interface, error) {
+		if name == "slow" {
+			<-hang
+		}
+		client, ok := clusters[name]
+		if !ok {
+			return nil, fmt.Errorf("context not found: %s", name)
+		}
+		return client, nil
+	})
+
+	report := prober.Probe(context.Background(), []string{"prod", "staging", "missing", "slow"}, 200*time.Millisecond)
+
+	if len(report.Contexts) != 4 {
+		t.Fatalf("expected a summary per context, got %+v", report.Contexts)
+	}
+	prod, staging, missing, slow := report.Contexts[0], report.Contexts[1], report.Contexts[2], report.Contexts[3]
+	if prod.Context != "prod" || prod.Status != core.HealthStatusHealthy || len(prod.Checks) != 2 {
+		t.Errorf("expected healthy prod with two checks, got %+v", prod)
+	}
+	if staging.Status == core.HealthStatusHealthy {
+		t.Errorf("expected staging with a NotReady node to be degraded, got %+v", staging)
+	}
+	if missing.Status != core.HealthStatusUnknown || missing.Error == "" || missing.TimedOut {
+		t.Errorf("expected an error for the missing context, got %+v", missing)
+	}
+	if slow.Status != core.HealthStatusUnknown || !slow.TimedOut {
+		t.Errorf("expected the slow context to time out, got %+v", slow)
+	}
+	if report.Status != core.WorstStatus(staging.Status, core.HealthStatusUnknown) {
+		t.Errorf("expected the worst status across contexts, got %s", report.Status)
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	"k8s.io/klog/v2"
+)
+
+// Annotations application teams can set on their own namespaces, pods (via
+// the pod template), services and nodes to tune monitoring without changing
+// KubePulse configuration
+const (
+	// AnnotationIgnore set to "true" excludes the resource from every check
+	AnnotationIgnore = "kubepulse.io/ignore"
+	// AnnotationIgnoreChecks excludes the resource from a comma-separated
+	// list of checks, e.g. "pod-health,service-health"
+	AnnotationIgnoreChecks = "kubepulse.io/ignore-checks"
+	// AnnotationRestartThreshold overrides pod-health's restart threshold for
+	// a pod, or for every pod in an annotated namespace
+	AnnotationRestartThreshold = "kubepulse.io/restart-threshold"
+	// AnnotationRunbook links a resource, or every resource in an annotated
+	// namespace, to the runbook attached to alerts it causes
+	AnnotationRunbook = "kubepulse.io/runbook"
+	// AnnotationMaintenanceUntil announces a maintenance window on a node, or
+	// on every pod in a namespace, ending at an RFC 3339 time. Failures
+	// inside the window are reported as expected disruption.
+	AnnotationMaintenanceUntil = "kubepulse.io/maintenance-until"
+)
+
+// isIgnored reports whether annotations opt a resource out of the named check
+func isIgnored(annotations map[string]string, check string) bool {
+	if value, ok := annotations[AnnotationIgnore]; ok {
+		if ignore, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil && ignore {
+			return true
+		}
+	}
+	for _, name := range strings.Split(annotations[AnnotationIgnoreChecks], ",") {
+		if strings.TrimSpace(name) == check {
+			return true
+		}
+	}
+	return false
+}
+
+// annotatedRestartThreshold returns the restart threshold from the first
+// annotations that set a valid one, most specific first, or fallback
+func annotatedRestartThreshold(fallback int32, annotations ...map[string]string) int32 {
+	for _, a := range annotations {
+		value, ok := a[AnnotationRestartThreshold]
+		if !ok {
+			continue
+		}
+		threshold, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || threshold < 0 {
+			klog.V(2).Infof("Ignoring invalid %s annotation %q", AnnotationRestartThreshold, value)
+			continue
+		}
+		return int32(threshold)
+	}
+	return fallback
+}
+
+// annotatedRunbook returns the runbook URL from the first annotations that
+// set a valid one, most specific first
+func annotatedRunbook(annotations ...map[string]string) string {
+	for _, a := range annotations {
+		value := strings.TrimSpace(a[AnnotationRunbook])
+		if value == "" {
+			continue
+		}
+		if err := alerts.ValidateRunbookURL(value); err != nil {
+			klog.V(2).Infof("Ignoring invalid %s annotation: %v", AnnotationRunbook, err)
+			continue
+		}
+		return value
+	}
+	return ""
+}
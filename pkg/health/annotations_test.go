@@ -0,0 +1,202 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsIgnored(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{"no annotations", nil, false},
+		{"ignore all", map[string]string{AnnotationIgnore: "true"}, true},
+		{"ignore false", map[string]string{AnnotationIgnore: "false"}, false},
+		{"ignore invalid", map[string]string{AnnotationIgnore: "yes please"}, false},
+		{"ignore this check", map[string]string{AnnotationIgnoreChecks: "node-health, pod-health"}, true},
+		{"ignore other checks", map[string]string{AnnotationIgnoreChecks: "service-health"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIgnored(tt.annotations, "pod-health"); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAnnotatedRestartThreshold(t *testing.T) {
+	pod := map[string]string{AnnotationRestartThreshold: "10"}
+	namespace := map[string]string{AnnotationRestartThreshold: "20"}
+	invalid := map[string]string{AnnotationRestartThreshold: "-1"}
+
+	tests := []struct {
+		name        string
+		annotations []map[string]string
+		want        int32
+	}{
+		{"fallback", nil, 5},
+		{"pod wins over namespace", []map[string]string{pod, namespace}, 10},
+		{"namespace", []map[string]string{nil, namespace}, 20},
+		{"invalid falls through", []map[string]string{invalid, namespace}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := annotatedRestartThreshold(5, tt.annotations...); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func restartingPod(namespace, name string, restarts int32, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+		},
+	}
+}
+
+func TestPodHealthCheck_Annotations(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "batch",
+			Annotations: map[string]string{AnnotationRestartThreshold: "50"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "sandbox",
+			Annotations: map[string]string{AnnotationIgnore: "true"},
+		}},
+		restartingPod("payments", "api", 8, map[string]string{AnnotationRestartThreshold: "10"}),
+		restartingPod("payments", "flaky", 30, map[string]string{AnnotationIgnoreChecks: "pod-health"}),
+		restartingPod("batch", "worker", 30, nil),
+		restartingPod("sandbox", "experiment", 99, nil),
+	)
+
+	check := NewPodHealthCheck()
+	check.logAnalysis = false
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != core.HealthStatusHealthy {
+		t.Errorf("expected annotated restarts to be tolerated, got %s: %s", result.Status, result.Message)
+	}
+	if result.Details["total_pods"] != 2 {
+		t.Errorf("expected ignored pods to be excluded from totals, got %v", result.Details["total_pods"])
+	}
+	if got := result.Details["ignored_pods"]; !reflect.DeepEqual(got, []string{"payments/flaky"}) {
+		t.Errorf("expected ignored pod to be reported, got %v", got)
+	}
+	if got := result.Details["ignored_namespaces"]; !reflect.DeepEqual(got, []string{"sandbox"}) {
+		t.Errorf("expected ignored namespace to be reported, got %v", got)
+	}
+}
+
+func TestServiceHealthCheck_Annotations(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        "headless-job",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationIgnore: "true"},
+		}},
+	)
+
+	result, err := NewServiceHealthCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusHealthy || result.Details["total_services"] != 0 {
+		t.Errorf("expected the endpoint-less service to be ignored, got %s: %s", result.Status, result.Message)
+	}
+	if got := result.Details["ignored_services"]; !reflect.DeepEqual(got, []string{"default/headless-job"}) {
+		t.Errorf("expected ignored service to be reported, got %v", got)
+	}
+}
+
+func TestNodeHealthCheck_Annotations(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "draining",
+			Annotations: map[string]string{AnnotationIgnoreChecks: "node-health"},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	})
+
+	result, err := NewNodeHealthCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusHealthy || result.Details["total_nodes"] != 0 {
+		t.Errorf("expected the ignored node to be excluded, got %s: %s", result.Status, result.Message)
+	}
+	if got := result.Details["ignored_nodes"]; !reflect.DeepEqual(got, []string{"draining"}) {
+		t.Errorf("expected ignored node to be reported, got %v", got)
+	}
+}
+
+func TestAnnotatedRunbook(t *testing.T) {
+	pod := map[string]string{AnnotationRunbook: "https://runbooks.example.com/api"}
+	namespace := map[string]string{AnnotationRunbook: "https://runbooks.example.com/payments"}
+	invalid := map[string]string{AnnotationRunbook: "wiki/api"}
+
+	tests := []struct {
+		name        string
+		annotations []map[string]string
+		want        string
+	}{
+		{"none", nil, ""},
+		{"pod wins over namespace", []map[string]string{pod, namespace}, "https://runbooks.example.com/api"},
+		{"namespace", []map[string]string{nil, namespace}, "https://runbooks.example.com/payments"},
+		{"invalid falls through", []map[string]string{invalid, namespace}, "https://runbooks.example.com/payments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := annotatedRunbook(tt.annotations...); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPodHealthCheck_RunbookAnnotation(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "payments",
+			Annotations: map[string]string{AnnotationRunbook: "https://runbooks.example.com/payments"},
+		}},
+		restartingPod("payments", "api", 30, nil),
+	)
+
+	check := NewPodHealthCheck()
+	check.logAnalysis = false
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status == core.HealthStatusHealthy {
+		t.Fatalf("expected the restarting pod to fail the check")
+	}
+	if got := result.Details["runbook"]; got != "https://runbooks.example.com/payments" {
+		t.Errorf("expected the namespace runbook, got %v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// webhookRejection matches pod creation denied by, or failing to reach,
+	// a validating or mutating admission webhook
+	webhookRejection = regexp.MustCompile(`(?:admission webhook|failed calling webhook) "([^"]+)"`)
+	// quotaRejection matches pod creation rejected by a ResourceQuota
+	quotaRejection = regexp.MustCompile(`(?:exceeded|failed) quota: ([a-z0-9]([-a-z0-9.]*[a-z0-9])?)`)
+)
+
+// blockedDeployments finds workloads whose FailedCreate events since the
+// given time show an admission webhook or quota rejecting their pods. Each
+// workload and blocker is reported once, with the latest message.
+func blockedDeployments(events []corev1.Event, since time.Time) []core.DeploymentBlock {
+	blocks := make(map[string]*core.DeploymentBlock)
+	for _, event := range events {
+		if event.Reason != "FailedCreate" || !eventTime(event).After(since) {
+			continue
+		}
+
+		block := core.DeploymentBlock{
+			Namespace: event.Namespace,
+			Workload:  event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			Message:   event.Message,
+			Count:     event.Count,
+			LastSeen:  eventTime(event),
+		}
+		if match := webhookRejection.FindStringSubmatch(event.Message); match != nil {
+			block.Cause, block.Blocker = core.BlockedByWebhook, match[1]
+		} else if match := quotaRejection.FindStringSubmatch(event.Message); match != nil {
+			block.Cause, block.Blocker = core.BlockedByQuota, match[1]
+		} else {
+			continue
+		}
+		if block.Count == 0 {
+			block.Count = 1
+		}
+
+		key := block.Namespace + "/" + block.Workload + "/" + block.Blocker
+		existing, ok := blocks[key]
+		if !ok {
+			blocks[key] = &block
+			continue
+		}
+		existing.Count += block.Count
+		if block.LastSeen.After(existing.LastSeen) {
+			existing.Message, existing.LastSeen = block.Message, block.LastSeen
+		}
+	}
+
+	result := make([]core.DeploymentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, *block)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Workload < result[j].Workload
+	})
+	return result
+}
+
+// setBlockedDeployments records blocked deployments on a result, per
+// namespace and as events so AI diagnosis sees them without describing pods
+func setBlockedDeployments(result *core.CheckResult, blocks []core.DeploymentBlock) {
+	byNamespace := make(map[string][]string)
+	events := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		byNamespace[block.Namespace] = append(byNamespace[block.Namespace], block.String())
+		events = append(events, fmt.Sprintf("%s (%dx): %s", block, block.Count, block.Message))
+		id := findings.WorkloadQuota
+		if block.Cause == core.BlockedByWebhook {
+			id = findings.WorkloadWebhook
+		}
+		kind, name, found := strings.Cut(block.Workload, "/")
+		if !found {
+			kind, name = "workload", block.Workload
+		}
+		addFindings(result, findings.Finding{
+			ID:      id,
+			Subject: core.ResourceRef{Kind: strings.ToLower(kind), Namespace: block.Namespace, Name: name}.String(),
+			Message: block.Message,
+		})
+	}
+	result.Details[core.DetailBlockedDeployments] = blocks
+	result.Details["blocked_by_namespace"] = byNamespace
+	result.Details["events"] = events
+}
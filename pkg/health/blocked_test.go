@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newFailedCreateEvent(name, namespace, replicaSet, message string, count int32, last time.Time) *corev1.Event {
+	event := newTestEvent(name, namespace, "FailedCreate", count, last)
+	event.InvolvedObject = corev1.ObjectReference{Kind: "ReplicaSet", Namespace: namespace, Name: replicaSet}
+	event.Message = message
+	return event
+}
+
+func TestBlockedDeployments(t *testing.T) {
+	now := time.Now()
+	events := []corev1.Event{
+		*newFailedCreateEvent("webhook-1", "shop", "checkout-5c8b",
+			`Error creating: admission webhook "policy.example.com" denied the request: image tag latest is not allowed`, 3, now.Add(-2*time.Minute)),
+		*newFailedCreateEvent("webhook-2", "shop", "checkout-5c8b",
+			`Error creating: admission webhook "policy.example.com" denied the request: image tag latest is not allowed`, 2, now.Add(-time.Minute)),
+		*newFailedCreateEvent("unreachable", "mesh", "api-7d9f",
+			`Error creating: Internal error occurred: failed calling webhook "sidecar-injector.istio.io": context deadline exceeded`, 1, now.Add(-time.Minute)),
+		*newFailedCreateEvent("quota", "batch", "worker-6b4c",
+			`Error creating: pods "worker-6b4c-x2" is forbidden: exceeded quota: compute-resources, requested: limits.cpu=2, used: limits.cpu=8, limited: limits.cpu=8`, 4, now.Add(-time.Minute)),
+		*newFailedCreateEvent("old", "shop", "cart-1a2b",
+			`Error creating: pods "cart-1a2b-q" is forbidden: failed quota: shop-quota: must specify limits.memory`, 1, now.Add(-time.Hour)),
+		*newFailedCreateEvent("serviceaccount", "shop", "cart-1a2b",
+			`Error creating: pods "cart-1a2b-q" is forbidden: error looking up service account shop/cart: serviceaccount "cart" not found`, 1, now.Add(-time.Minute)),
+	}
+
+	blocks := blockedDeployments(events, now.Add(-5*time.Minute))
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocked deployments, got %d: %+v", len(blocks), blocks)
+	}
+
+	tests := []struct {
+		namespace, workload, cause, blocker string
+		count                               int32
+	}{
+		{"batch", "ReplicaSet/worker-6b4c", core.BlockedByQuota, "compute-resources", 4},
+		{"mesh", "ReplicaSet/api-7d9f", core.BlockedByWebhook, "sidecar-injector.istio.io", 1},
+		{"shop", "ReplicaSet/checkout-5c8b", core.BlockedByWebhook, "policy.example.com", 5},
+	}
+	for i, tt := range tests {
+		block := blocks[i]
+		if block.Namespace != tt.namespace || block.Workload != tt.workload || block.Cause != tt.cause ||
+			block.Blocker != tt.blocker || block.Count != tt.count {
+			t.Errorf("block %d = %+v, want %+v", i, block, tt)
+		}
+	}
+	if !blocks[2].LastSeen.Equal(now.Add(-time.Minute)) {
+		t.Errorf("expected the latest occurrence to be kept, got %v", blocks[2].LastSeen)
+	}
+}
+
+func TestEventRateCheck_ReportsBlockedDeployments(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		newFailedCreateEvent("webhook", "shop", "checkout-5c8b",
+			`Error creating: admission webhook "policy.example.com" denied the request: image tag latest is not allowed`, 2, now.Add(-time.Minute)),
+	)
+
+	result, err := NewEventRateCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != core.HealthStatusDegraded {
+		t.Errorf("expected degraded status for a blocked deployment, got %s", result.Status)
+	}
+	if !strings.Contains(result.Message, "deployment blocked by admission webhook policy.example.com: shop ReplicaSet/checkout-5c8b") {
+		t.Errorf("expected the block in the message, got %q", result.Message)
+	}
+	if blocks := core.BlockedDeployments(result); len(blocks) != 1 || blocks[0].Blocker != "policy.example.com" {
+		t.Errorf("unexpected blocked deployments: %+v", blocks)
+	}
+	byNamespace, _ := result.Details["blocked_by_namespace"].(map[string][]string)
+	if len(byNamespace["shop"]) != 1 {
+		t.Errorf("expected the block recorded against the shop namespace, got %v", byNamespace)
+	}
+	events, _ := result.Details["events"].([]string)
+	if len(events) != 1 || !strings.Contains(events[0], "image tag latest is not allowed") {
+		t.Errorf("expected the block as an AI event, got %v", events)
+	}
+	if got := core.Findings(result); len(got) != 1 || got[0].ID != findings.WorkloadWebhook || got[0].Subject != "replicaset/shop/checkout-5c8b" {
+		t.Errorf("expected a webhook finding for the ReplicaSet, got %v", got)
+	}
+}
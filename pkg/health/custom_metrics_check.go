@@ -0,0 +1,263 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Aggregated metrics APIs served by adapters such as the Prometheus adapter
+// (custom metrics about Kubernetes objects) and KEDA (external metrics)
+const (
+	CustomMetricsAPI   = "custom"
+	ExternalMetricsAPI = "external"
+
+	customMetricsPath   = "/apis/custom.metrics.k8s.io/v1beta2"
+	externalMetricsPath = "/apis/external.metrics.k8s.io/v1beta1"
+)
+
+// invalidMetricChars are replaced so adapter metric names such as KEDA's
+// s0-rabbitmq-orders become valid metric names
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// CustomMetricSpec describes a metric read from the custom or external
+// metrics API and the threshold it is held to
+type CustomMetricSpec struct {
+	Name      string // Check name
+	API       string // CustomMetricsAPI or ExternalMetricsAPI
+	Metric    string
+	Namespace string // Empty reads cluster-scoped objects; external metrics default to "default"
+	Resource  string // Custom metrics: the object resource, e.g. pods
+	Object    string // Custom metrics: the object name; empty reads every object
+	Selector  string // Label selector of the objects or external series
+
+	// Operator and Threshold, when set, fail the check with Status while a
+	// value crosses the threshold
+	Operator  string // >, >=, < or <=
+	Threshold float64
+	Status    core.HealthStatus
+}
+
+// metricValue is an item of a custom or external MetricValueList
+type metricValue struct {
+	DescribedObject struct {
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"describedObject"`
+	MetricLabels map[string]string `json:"metricLabels"`
+	Value        string            `json:"value"`
+	Timestamp    time.Time         `json:"timestamp"`
+}
+
+// CustomMetricsCheck reads application metrics served through API
+// aggregation, so signals such as queue depth or request rate join metric
+// history, anomaly detection and SLOs and can fail a check, without pushing
+// them to KubePulse separately
+type CustomMetricsCheck struct {
+	spec     CustomMetricSpec
+	interval time.Duration
+
+	// fetch reads an API path; tests replace it
+	fetch func(ctx context.Context, client kubernetes.Interface, path string) ([]byte, error)
+}
+
+// NewCustomMetricsCheck creates a check for the metric in spec
+func NewCustomMetricsCheck(spec CustomMetricSpec) *CustomMetricsCheck {
+	if spec.API == "" {
+		spec.API = CustomMetricsAPI
+	}
+	if spec.API == ExternalMetricsAPI && spec.Namespace == "" {
+		spec.Namespace = "default"
+	}
+	if spec.Status == "" {
+		spec.Status = core.HealthStatusUnhealthy
+	}
+	return &CustomMetricsCheck{
+		spec:     spec,
+		interval: 30 * time.Second,
+		fetch:    fetchAPIPath,
+	}
+}
+
+// fetchAPIPath reads a path from the API server, which proxies aggregated
+// APIs to the adapter serving them
+func fetchAPIPath(ctx context.Context, client kubernetes.Interface, path string) ([]byte, error) {
+	restClient := client.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("the client can't read %s", path)
+	}
+	return restClient.Get().AbsPath(path).DoRaw(ctx)
+}
+
+// Name returns the name of the health check
+func (c *CustomMetricsCheck) Name() string {
+	return c.spec.Name
+}
+
+// Description returns a description of the health check
+func (c *CustomMetricsCheck) Description() string {
+	return fmt.Sprintf("Monitors the %s metric %s", c.spec.API, c.spec.Metric)
+}
+
+// path returns the API path the metric is read from
+func (c *CustomMetricsCheck) path() string {
+	var path string
+	if c.spec.API == ExternalMetricsAPI {
+		path = fmt.Sprintf("%s/namespaces/%s/%s", externalMetricsPath, c.spec.Namespace, c.spec.Metric)
+	} else {
+		object := c.spec.Object
+		if object == "" {
+			object = "*"
+		}
+		path = customMetricsPath
+		if c.spec.Namespace != "" {
+			path += "/namespaces/" + c.spec.Namespace
+		}
+		path += fmt.Sprintf("/%s/%s/%s", c.spec.Resource, object, c.spec.Metric)
+	}
+	if c.spec.Selector != "" {
+		path += "?labelSelector=" + url.QueryEscape(c.spec.Selector)
+	}
+	return path
+}
+
+// Check performs the custom metrics health check
+func (c *CustomMetricsCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details: map[string]interface{}{
+			"api":    c.spec.API,
+			"metric": c.spec.Metric,
+		},
+		Metrics: []core.Metric{},
+	}
+
+	body, err := c.fetch(ctx, client, c.path())
+	if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+		// No adapter serves the API, or it doesn't know the metric
+		result.Status = core.HealthStatusUnknown
+		result.Message = fmt.Sprintf("The %s metric %s is not available: %v", c.spec.API, c.spec.Metric, err)
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to read the %s metric %s: %w", c.spec.API, c.spec.Metric, err)
+	}
+	var list struct {
+		Items []metricValue `json:"items"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return result, fmt.Errorf("failed to decode the %s metric %s: %w", c.spec.API, c.spec.Metric, err)
+	}
+
+	name := invalidMetricChars.ReplaceAllString(c.spec.Metric, "_")
+	var breaches []string
+	for _, item := range list.Items {
+		quantity, err := resource.ParseQuantity(item.Value)
+		if err != nil {
+			continue
+		}
+		value := quantity.AsApproximateFloat64()
+		labels, series := c.seriesLabels(item)
+		timestamp := item.Timestamp
+		if timestamp.IsZero() {
+			timestamp = result.Timestamp
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric(name, value, timestamp, labels))
+
+		if c.breached(value) {
+			breaches = append(breaches, fmt.Sprintf("%s = %g %s %g", series, value, c.spec.Operator, c.spec.Threshold))
+		}
+	}
+
+	result.Details["series"] = len(result.Metrics)
+	switch {
+	case len(result.Metrics) == 0:
+		result.Status = core.HealthStatusUnknown
+		result.Message = fmt.Sprintf("The %s metric %s returned no values", c.spec.API, c.spec.Metric)
+	case len(breaches) > 0:
+		result.Status = c.spec.Status
+		result.Message = fmt.Sprintf("%d of %d %s series breach the threshold: %s", len(breaches), len(result.Metrics), c.spec.Metric, strings.Join(breaches, "; "))
+		result.Details["breaches"] = breaches
+	default:
+		result.Message = fmt.Sprintf("Read %d %s series", len(result.Metrics), c.spec.Metric)
+	}
+	result.AffectedResources = len(breaches)
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// seriesLabels returns the labels of a value and the series name used in
+// messages: the described object for custom metrics, the metric labels for
+// external ones
+func (c *CustomMetricsCheck) seriesLabels(item metricValue) (map[string]string, string) {
+	labels := map[string]string{"check": c.Name()}
+	if c.spec.API == ExternalMetricsAPI {
+		labels["namespace"] = c.spec.Namespace
+		pairs := make([]string, 0, len(item.MetricLabels))
+		for key, value := range item.MetricLabels {
+			labels[key] = value
+			pairs = append(pairs, key+"="+value)
+		}
+		if len(pairs) == 0 {
+			return labels, c.spec.Metric
+		}
+		sort.Strings(pairs)
+		return labels, c.spec.Metric + "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	object := item.DescribedObject
+	labels["kind"] = object.Kind
+	labels["name"] = object.Name
+	series := object.Kind + " " + object.Name
+	if object.Namespace != "" {
+		labels["namespace"] = object.Namespace
+		series = object.Kind + " " + object.Namespace + "/" + object.Name
+	}
+	return labels, series
+}
+
+// breached reports whether a value crosses the threshold
+func (c *CustomMetricsCheck) breached(value float64) bool {
+	switch c.spec.Operator {
+	case ">":
+		return value > c.spec.Threshold
+	case ">=":
+		return value >= c.spec.Threshold
+	case "<":
+		return value < c.spec.Threshold
+	case "<=":
+		return value <= c.spec.Threshold
+	}
+	return false
+}
+
+// Configure sets up the health check with configuration
+func (c *CustomMetricsCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok && v != "" {
+		c.spec.Namespace = v
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *CustomMetricsCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *CustomMetricsCheck) Criticality() core.Criticality {
+	return core.CriticalityHigh
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const podRequestRates = `{"kind":"MetricValueList","apiVersion":"custom.metrics.k8s.io/v1beta2","items":[
+ {"describedObject":{"kind":"Pod","namespace":"shop","name":"web-1"},"metric":{"name":"http_requests_per_second"},"timestamp":"2026-01-02T09:00:00Z","value":"1500m"},
+ {"describedObject":{"kind":"Pod","namespace":"shop","name":"web-2"},"metric":{"name":"http_requests_per_second"},"timestamp":"2026-01-02T09:00:00Z","value":"12"}]}`
+
+const queueDepth = `{"kind":"ExternalMetricValueList","apiVersion":"external.metrics.k8s.io/v1beta1","items":[
+ {"metricName":"s0-rabbitmq-orders","metricLabels":{"queue":"orders"},"timestamp":"2026-01-02T09:00:00Z","value":"2k"}]}`
+
+func TestCustomMetricsCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        CustomMetricSpec
+		body        string
+		err         error
+		wantPath    string
+		wantStatus  core.HealthStatus
+		wantMessage string
+		wantValues  []float64
+	}{
+		{
+			name:        "custom metric within threshold",
+			spec:        CustomMetricSpec{Metric: "http_requests_per_second", Namespace: "shop", Resource: "pods", Selector: "app=web", Operator: ">", Threshold: 100},
+			body:        podRequestRates,
+			wantPath:    "/apis/custom.metrics.k8s.io/v1beta2/namespaces/shop/pods/*/http_requests_per_second?labelSelector=app%3Dweb",
+			wantStatus:  core.HealthStatusHealthy,
+			wantMessage: "Read 2 http_requests_per_second series",
+			wantValues:  []float64{1.5, 12},
+		},
+		{
+			name:        "custom metric below a minimum",
+			spec:        CustomMetricSpec{Metric: "http_requests_per_second", Namespace: "shop", Resource: "pods", Operator: "<", Threshold: 2, Status: core.HealthStatusDegraded},
+			body:        podRequestRates,
+			wantPath:    "/apis/custom.metrics.k8s.io/v1beta2/namespaces/shop/pods/*/http_requests_per_second",
+			wantStatus:  core.HealthStatusDegraded,
+			wantMessage: "Pod shop/web-1 = 1.5 < 2",
+			wantValues:  []float64{1.5, 12},
+		},
+		{
+			name:        "external metric over threshold",
+			spec:        CustomMetricSpec{API: ExternalMetricsAPI, Metric: "s0-rabbitmq-orders", Operator: ">=", Threshold: 1000},
+			body:        queueDepth,
+			wantPath:    "/apis/external.metrics.k8s.io/v1beta1/namespaces/default/s0-rabbitmq-orders",
+			wantStatus:  core.HealthStatusUnhealthy,
+			wantMessage: "s0-rabbitmq-orders{queue=orders} = 2000 >= 1000",
+			wantValues:  []float64{2000},
+		},
+		{
+			name:        "no adapter",
+			spec:        CustomMetricSpec{Metric: "http_requests_per_second", Namespace: "shop", Resource: "pods"},
+			err:         apierrors.NewNotFound(schema.GroupResource{Group: "custom.metrics.k8s.io", Resource: "pods"}, "*"),
+			wantPath:    "/apis/custom.metrics.k8s.io/v1beta2/namespaces/shop/pods/*/http_requests_per_second",
+			wantStatus:  core.HealthStatusUnknown,
+			wantMessage: "is not available",
+		},
+		{
+			name:        "no values",
+			spec:        CustomMetricSpec{Metric: "queue_length", Resource: "nodes", Object: "node-1"},
+			body:        `{"items":[]}`,
+			wantPath:    "/apis/custom.metrics.k8s.io/v1beta2/nodes/node-1/queue_length",
+			wantStatus:  core.HealthStatusUnknown,
+			wantMessage: "returned no values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec.Name = "app-metrics"
+			check := NewCustomMetricsCheck(tt.spec)
+			var path string
+			check.fetch = func(ctx context.Context, client kubernetes.Interface, p string) ([]byte, error) {
+				path = p
+				return []byte(tt.body), tt.err
+			}
+
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("read %s, want %s", path, tt.wantPath)
+			}
+			if result.Status != tt.wantStatus || !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("got %s %q, want %s containing %q", result.Status, result.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if len(result.Metrics) != len(tt.wantValues) {
+				t.Fatalf("expected %d metrics, got %+v", len(tt.wantValues), result.Metrics)
+			}
+			for i, metric := range result.Metrics {
+				if metric.Value != tt.wantValues[i] || metric.Labels["check"] != "app-metrics" || strings.Contains(metric.Name, "-") {
+					t.Errorf("unexpected metric %+v", metric)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CustomResourceCondition maps a status condition of a custom resource to
+// the health it warrants when the condition doesn't have the expected status
+type CustomResourceCondition struct {
+	Type     string            // Condition type, e.g. Ready
+	Expected string            // Healthy status: True, False or Unknown
+	Status   core.HealthStatus // Degraded or unhealthy
+}
+
+// CustomResourceSpec describes the custom resources a check evaluates
+type CustomResourceSpec struct {
+	Name       string // Check name
+	Resource   schema.GroupVersionResource
+	Kind       string
+	Namespace  string // Empty checks every namespace
+	Conditions []CustomResourceCondition
+}
+
+// CustomResourceCheck evaluates status.conditions of operator-managed custom
+// resources, so a KafkaCluster or Postgres cluster that isn't Ready shows up
+// like any built-in check
+type CustomResourceCheck struct {
+	spec      CustomResourceSpec
+	dynamic   dynamic.Interface
+	namespace string
+	interval  time.Duration
+}
+
+// NewCustomResourceCheck creates a check for the custom resources in spec.
+// Without conditions, resources must be Ready.
+func NewCustomResourceCheck(dynamicClient dynamic.Interface, spec CustomResourceSpec) *CustomResourceCheck {
+	if len(spec.Conditions) == 0 {
+		spec.Conditions = []CustomResourceCondition{{Type: "Ready", Expected: string(metav1.ConditionTrue), Status: core.HealthStatusUnhealthy}}
+	}
+	return &CustomResourceCheck{
+		spec:      spec,
+		dynamic:   dynamicClient,
+		namespace: spec.Namespace,
+		interval:  30 * time.Second,
+	}
+}
+
+// Name returns the name of the health check
+func (c *CustomResourceCheck) Name() string {
+	return c.spec.Name
+}
+
+// Description returns a description of the health check
+func (c *CustomResourceCheck) Description() string {
+	return fmt.Sprintf("Monitors status conditions of %s resources (%s)", c.spec.Kind, c.resourceName())
+}
+
+// resourceName returns the resource in resource.group/version form
+func (c *CustomResourceCheck) resourceName() string {
+	gvr := c.spec.Resource
+	if gvr.Group == "" {
+		return gvr.Resource + "/" + gvr.Version
+	}
+	return gvr.Resource + "." + gvr.Group + "/" + gvr.Version
+}
+
+// Relevant reports whether the API server serves the resource's group, so
+// checks of operators that aren't installed, such as Velero, don't run
+func (c *CustomResourceCheck) Relevant(inv *inventory.Inventory) (bool, string) {
+	group := c.spec.Resource.Group
+	if inventory.IsBuiltinGroup(group) {
+		return true, "Built-in API"
+	}
+	for _, api := range inv.CustomResources {
+		if api.Group == group {
+			return true, fmt.Sprintf("%s is served", group)
+		}
+	}
+	return false, fmt.Sprintf("%s is not served; its CRDs aren't installed", group)
+}
+
+// Check performs the custom resource health check
+func (c *CustomResourceCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details: map[string]interface{}{
+			"kind":     c.spec.Kind,
+			"resource": c.resourceName(),
+		},
+		Metrics: []core.Metric{},
+	}
+	if c.dynamic == nil {
+		return result, fmt.Errorf("custom resource check %s needs a dynamic client", c.Name())
+	}
+
+	resource := c.dynamic.Resource(c.spec.Resource)
+	var lister dynamic.ResourceInterface = resource
+	if c.namespace != "" {
+		lister = resource.Namespace(c.namespace)
+	}
+	list, err := lister.List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		result.Status = core.HealthStatusUnknown
+		result.Message = fmt.Sprintf("%s is not installed in the cluster", c.resourceName())
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to list %s: %w", c.resourceName(), err)
+	}
+
+	var unhealthy, degraded, ignored []string
+	var runbook string
+	failing := 0
+	for _, object := range list.Items {
+		name := object.GetName()
+		if object.GetNamespace() != "" {
+			name = object.GetNamespace() + "/" + name
+		}
+		if isIgnored(object.GetAnnotations(), c.Name()) {
+			ignored = append(ignored, c.spec.Kind+" "+name)
+			continue
+		}
+
+		failed := false
+		for _, condition := range c.spec.Conditions {
+			ok, reason := conditionIs(object.Object, condition.Type, condition.Expected, "status", "conditions")
+			if ok || (condition.Expected != string(metav1.ConditionTrue) && reason == noStatusReported) {
+				// An absent negative condition such as Degraded is healthy
+				continue
+			}
+			issue := fmt.Sprintf("%s %s not %s%s", c.spec.Kind, name, condition.Type, reason)
+			if condition.Expected != string(metav1.ConditionTrue) {
+				issue = fmt.Sprintf("%s %s is %s%s", c.spec.Kind, name, condition.Type, reason)
+			}
+			if condition.Status == core.HealthStatusUnhealthy {
+				unhealthy = append(unhealthy, issue)
+			} else {
+				degraded = append(degraded, issue)
+			}
+			failed = true
+		}
+		if failed {
+			failing++
+			if runbook == "" {
+				runbook = annotatedRunbook(object.GetAnnotations())
+			}
+		}
+	}
+
+	issues := append(append([]string{}, unhealthy...), degraded...)
+	switch {
+	case len(unhealthy) > 0:
+		result.Status = core.HealthStatusUnhealthy
+	case len(degraded) > 0:
+		result.Status = core.HealthStatusDegraded
+	}
+	total := len(list.Items) - len(ignored)
+	if len(issues) > 0 {
+		result.Message = fmt.Sprintf("%d of %d %s resources failing: %s", failing, total, c.spec.Kind, strings.Join(issues, "; "))
+		result.Details["issues"] = issues
+	} else {
+		result.Message = fmt.Sprintf("All %d %s resources are healthy", total, c.spec.Kind)
+	}
+	result.AffectedResources = failing
+	result.Details["total"] = total
+	if runbook != "" {
+		result.Details["runbook"] = runbook
+	}
+	if len(ignored) > 0 {
+		result.Details["ignored_resources"] = ignored
+	}
+
+	labels := map[string]string{"kind": c.spec.Kind, "group": c.spec.Resource.Group}
+	result.Metrics = append(result.Metrics,
+		gaugeMetric("custom_resources", float64(total), result.Timestamp, labels),
+		gaugeMetric("custom_resources_failing", float64(failing), result.Timestamp, labels),
+	)
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// Configure sets up the health check with configuration
+func (c *CustomResourceCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok {
+		c.namespace = v
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *CustomResourceCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *CustomResourceCheck) Criticality() core.Criticality {
+	return core.CriticalityHigh
+}
@@ -0,0 +1,188 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var kafkaResource = schema.GroupVersionResource{Group: "kafka.strimzi.io", Version: "v1beta2", Resource: "kafkas"}
+
+func kafka(namespace, name string, annotations map[string]interface{}, conditions ...string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kafka.strimzi.io/v1beta2",
+		"kind":       "Kafka",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace, "annotations": annotations},
+		"status":     map[string]interface{}{"conditions": statusConditions(conditions...)},
+	}}
+}
+
+// statusConditions returns a conditions list from type/status pairs
+func statusConditions(pairs ...string) []interface{} {
+	list := []interface{}{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		condition := map[string]interface{}{"type": pairs[i], "status": pairs[i+1]}
+		if pairs[i+1] != "True" {
+			condition["reason"] = "Reconciling"
+		}
+		list = append(list, condition)
+	}
+	return list
+}
+
+func newKafkaClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kafkaResource: "KafkaList"}, objects...)
+}
+
+func TestCustomResourceCheck(t *testing.T) {
+	degradedCondition := []CustomResourceCondition{
+		{Type: "Ready", Expected: "True", Status: core.HealthStatusUnhealthy},
+		{Type: "Degraded", Expected: "False", Status: core.HealthStatusDegraded},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []CustomResourceCondition
+		namespace  string
+		objects    []runtime.Object
+		wantStatus core.HealthStatus
+		wantIssue  string
+		wantTotal  int
+	}{
+		{
+			name:       "ready",
+			objects:    []runtime.Object{kafka("data", "events", nil, "Ready", "True")},
+			wantStatus: core.HealthStatusHealthy,
+			wantIssue:  "All 1 Kafka resources are healthy",
+			wantTotal:  1,
+		},
+		{
+			name:       "not ready",
+			objects:    []runtime.Object{kafka("data", "events", nil, "Ready", "False"), kafka("data", "audit", nil, "Ready", "True")},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "1 of 2 Kafka resources failing: Kafka data/events not Ready (Reconciling)",
+			wantTotal:  2,
+		},
+		{
+			name:       "never reconciled",
+			objects:    []runtime.Object{kafka("data", "events", nil)},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "Kafka data/events not Ready (no status reported)",
+			wantTotal:  1,
+		},
+		{
+			name:       "negative condition set",
+			conditions: degradedCondition,
+			objects:    []runtime.Object{kafka("data", "events", nil, "Ready", "True", "Degraded", "True")},
+			wantStatus: core.HealthStatusDegraded,
+			wantIssue:  "Kafka data/events is Degraded",
+			wantTotal:  1,
+		},
+		{
+			name:       "negative condition missing",
+			conditions: degradedCondition,
+			objects:    []runtime.Object{kafka("data", "events", nil, "Ready", "True")},
+			wantStatus: core.HealthStatusHealthy,
+			wantTotal:  1,
+		},
+		{
+			name:       "namespace scoped",
+			namespace:  "data",
+			objects:    []runtime.Object{kafka("data", "events", nil, "Ready", "True"), kafka("staging", "events", nil, "Ready", "False")},
+			wantStatus: core.HealthStatusHealthy,
+			wantTotal:  1,
+		},
+		{
+			name: "ignored",
+			objects: []runtime.Object{
+				kafka("data", "events", map[string]interface{}{AnnotationIgnoreChecks: "kafka-clusters"}, "Ready", "False"),
+			},
+			wantStatus: core.HealthStatusHealthy,
+			wantTotal:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewCustomResourceCheck(newKafkaClient(tt.objects...), CustomResourceSpec{
+				Name:       "kafka-clusters",
+				Resource:   kafkaResource,
+				Kind:       "Kafka",
+				Namespace:  tt.namespace,
+				Conditions: tt.conditions,
+			})
+
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Name != "kafka-clusters" {
+				t.Errorf("expected the configured check name, got %q", result.Name)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+			if tt.wantIssue != "" && !strings.Contains(result.Message, tt.wantIssue) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantIssue, result.Message)
+			}
+			if result.Details["total"] != tt.wantTotal {
+				t.Errorf("expected %d resources, got %v", tt.wantTotal, result.Details["total"])
+			}
+		})
+	}
+}
+
+func TestCustomResourceCheck_NotInstalled(t *testing.T) {
+	client := newKafkaClient()
+	client.PrependReactor("list", "kafkas", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(kafkaResource.GroupResource(), "")
+	})
+	check := NewCustomResourceCheck(client, CustomResourceSpec{Name: "kafka-clusters", Resource: kafkaResource, Kind: "Kafka"})
+
+	result, err := check.Check(context.Background(), fake.NewSimpleClientset())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusUnknown || !strings.Contains(result.Message, "kafkas.kafka.strimzi.io/v1beta2 is not installed") {
+		t.Errorf("expected unknown for a missing CRD, got %s: %s", result.Status, result.Message)
+	}
+}
+
+func TestCustomResourceCheck_Relevant(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource schema.GroupVersionResource
+		inv      inventory.Inventory
+		want     bool
+	}{
+		{"installed", kafkaResource, inventory.Inventory{CustomResources: []inventory.CustomResourceAPI{{Group: "kafka.strimzi.io"}}}, true},
+		{"not installed", kafkaResource, inventory.Inventory{CustomResources: []inventory.CustomResourceAPI{{Group: "velero.io"}}}, false},
+		{"built-in", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, inventory.Inventory{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewCustomResourceCheck(nil, CustomResourceSpec{Name: "resources", Resource: tt.resource, Kind: "Resource"})
+			if relevant, reason := check.Relevant(&tt.inv); relevant != tt.want || reason == "" {
+				t.Errorf("expected relevant %v, got %v: %s", tt.want, relevant, reason)
+			}
+		})
+	}
+}
+
+func TestCustomResourceCheck_NoDynamicClient(t *testing.T) {
+	check := NewCustomResourceCheck(nil, CustomResourceSpec{Name: "kafka-clusters", Resource: kafkaResource, Kind: "Kafka"})
+	if _, err := check.Check(context.Background(), fake.NewSimpleClientset()); err == nil {
+		t.Error("expected an error without a dynamic client")
+	}
+}
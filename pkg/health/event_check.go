@@ -0,0 +1,309 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventRate defines a Kubernetes event reason tracked as a rate metric
+type EventRate struct {
+	Reason    string        // Event reason, e.g. Evicted
+	Metric    string        // Metric name emitted for the rate
+	Per       time.Duration // Rate unit, e.g. per minute
+	Threshold float64       // Rate above which the check degrades (0 disables)
+}
+
+// EventRateCheck converts Kubernetes events into per-namespace rate metrics
+type EventRateCheck struct {
+	namespace string
+	interval  time.Duration
+	window    time.Duration
+	rates     []EventRate
+
+	// State carried between runs so repeated events are counted by delta
+	mu      sync.Mutex
+	seen    map[types.UID]int32
+	lastRun time.Time
+}
+
+// NewEventRateCheck creates a new event rate check
+func NewEventRateCheck() *EventRateCheck {
+	return &EventRateCheck{
+		namespace: "",
+		interval:  30 * time.Second,
+		window:    5 * time.Minute,
+		rates: []EventRate{
+			{Reason: "Evicted", Metric: "event_evictions_per_minute", Per: time.Minute, Threshold: 1},
+			{Reason: "OOMKilling", Metric: "event_oomkills_per_hour", Per: time.Hour, Threshold: 5},
+			{Reason: "FailedScheduling", Metric: "event_failed_scheduling_per_minute", Per: time.Minute, Threshold: 2},
+			{Reason: "BackOff", Metric: "event_backoff_per_minute", Per: time.Minute, Threshold: 5},
+		},
+		seen: make(map[types.UID]int32),
+	}
+}
+
+// Name returns the name of the health check
+func (e *EventRateCheck) Name() string {
+	return "event-rates"
+}
+
+// Description returns a description of the health check
+func (e *EventRateCheck) Description() string {
+	return "Tracks eviction, OOM kill, and scheduling failure event rates, and pod creation blocked by admission webhooks or quotas"
+}
+
+// Check performs the event rate check
+func (e *EventRateCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      e.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details:   make(map[string]interface{}),
+		Metrics:   []core.Metric{},
+	}
+
+	events, err := client.CoreV1().Events(e.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	counts, elapsed := e.countNewEvents(events.Items, result.Timestamp)
+
+	clusterRates := make(map[string]float64)
+	namespaceRates := make(map[string]map[string]float64)
+	var issues []string
+
+	for _, rate := range e.rates {
+		var total int32
+		for ns, count := range counts[rate.Reason] {
+			total += count
+			value := float64(count) / elapsed.Minutes() * rate.Per.Minutes()
+
+			if namespaceRates[ns] == nil {
+				namespaceRates[ns] = make(map[string]float64)
+			}
+			namespaceRates[ns][rate.Reason] = value
+
+			result.Metrics = append(result.Metrics, core.Metric{
+				Name:  rate.Metric,
+				Value: value,
+				Unit:  "events/" + formatRateUnit(rate.Per),
+				Labels: map[string]string{
+					"namespace": ns,
+					"reason":    rate.Reason,
+				},
+				Type:      core.MetricTypeGauge,
+				Timestamp: result.Timestamp,
+			})
+		}
+
+		clusterValue := float64(total) / elapsed.Minutes() * rate.Per.Minutes()
+		clusterRates[rate.Reason] = clusterValue
+
+		result.Metrics = append(result.Metrics, core.Metric{
+			Name:  rate.Metric,
+			Value: clusterValue,
+			Unit:  "events/" + formatRateUnit(rate.Per),
+			Labels: map[string]string{
+				"reason": rate.Reason,
+			},
+			Type:      core.MetricTypeGauge,
+			Timestamp: result.Timestamp,
+		})
+
+		if rate.Threshold > 0 && clusterValue > rate.Threshold {
+			issues = append(issues, fmt.Sprintf("%s: %.1f/%s (threshold %.1f)",
+				rate.Reason, clusterValue, formatRateUnit(rate.Per), rate.Threshold))
+			core.SetObservedAt(&result, earliestEvent(events.Items, rate.Reason, result.Timestamp.Add(-elapsed)))
+		}
+	}
+
+	blocks := blockedDeployments(events.Items, result.Timestamp.Add(-e.window))
+	if len(blocks) > 0 {
+		setBlockedDeployments(&result, blocks)
+	}
+
+	if len(issues) > 0 {
+		result.Status = core.HealthStatusDegraded
+		result.Message = fmt.Sprintf("Elevated event rates: %s", strings.Join(issues, ", "))
+		result.Details["issues"] = issues
+	} else {
+		result.Message = "Event rates are within thresholds"
+	}
+	if len(blocks) > 0 {
+		result.Status = core.HealthStatusDegraded
+		result.Message += "; " + blocks[0].String()
+		if len(blocks) > 1 {
+			result.Message += fmt.Sprintf(" (and %d more)", len(blocks)-1)
+		}
+	}
+
+	result.Details["rates"] = clusterRates
+	result.Details["rates_by_namespace"] = namespaceRates
+	result.Details["window"] = elapsed.String()
+
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// countNewEvents returns event counts by reason and namespace that occurred since
+// the previous run, along with the elapsed time they cover. The first run has
+// no previous counts, so it estimates the occurrences inside the window.
+func (e *EventRateCheck) countNewEvents(events []corev1.Event, now time.Time) (map[string]map[string]int32, time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tracked := make(map[string]bool, len(e.rates))
+	for _, rate := range e.rates {
+		tracked[rate.Reason] = true
+	}
+
+	firstRun := e.lastRun.IsZero()
+	elapsed := now.Sub(e.lastRun)
+	if firstRun {
+		elapsed = e.window
+	}
+	cutoff := now.Add(-elapsed)
+
+	counts := make(map[string]map[string]int32)
+	seen := make(map[types.UID]int32, len(events))
+
+	for _, event := range events {
+		if !tracked[event.Reason] {
+			continue
+		}
+
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		seen[event.UID] = count
+
+		var delta int32
+		if previous, exists := e.seen[event.UID]; exists {
+			delta = count - previous
+		} else if firstRun {
+			delta = countSince(event, count, cutoff)
+		} else {
+			delta = count
+		}
+
+		if delta <= 0 {
+			continue
+		}
+
+		if counts[event.Reason] == nil {
+			counts[event.Reason] = make(map[string]int32)
+		}
+		counts[event.Reason][event.Namespace] += delta
+	}
+
+	e.seen = seen
+	e.lastRun = now
+
+	if elapsed <= 0 {
+		elapsed = time.Second
+	}
+	return counts, elapsed
+}
+
+// Configure sets up the health check with configuration
+func (e *EventRateCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok {
+		e.namespace = v
+	}
+	if v, ok := config["window"].(time.Duration); ok && v > 0 {
+		e.window = v
+	}
+	if v, ok := config["thresholds"].(map[string]float64); ok {
+		for i, rate := range e.rates {
+			if threshold, exists := v[rate.Reason]; exists {
+				e.rates[i].Threshold = threshold
+			}
+		}
+	}
+	if v, ok := config["rates"].([]EventRate); ok {
+		for _, rate := range v {
+			if rate.Reason == "" || rate.Metric == "" || rate.Per <= 0 {
+				return fmt.Errorf("invalid event rate definition: %+v", rate)
+			}
+		}
+		e.rates = v
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (e *EventRateCheck) Interval() time.Duration {
+	return e.interval
+}
+
+// Criticality returns the importance level of this check
+func (e *EventRateCheck) Criticality() core.Criticality {
+	return core.CriticalityMedium
+}
+
+// countSince estimates how many of an event's occurrences came after cutoff.
+// An event only records its total count and its first and last times, so
+// when it began before cutoff the occurrences are spread evenly between the
+// two, counting at least the last one.
+func countSince(event corev1.Event, count int32, cutoff time.Time) int32 {
+	last := eventTime(event)
+	if !last.After(cutoff) {
+		return 0
+	}
+	first := event.FirstTimestamp.Time
+	if first.IsZero() || !first.Before(cutoff) || !last.After(first) {
+		return count
+	}
+	share := float64(last.Sub(cutoff)) / float64(last.Sub(first))
+	return max(1, int32(math.Round(float64(count)*share)))
+}
+
+// eventTime returns the most recent time an event was observed
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// earliestEvent returns when the first event with a reason since a time
+// occurred, or zero when none did
+func earliestEvent(events []corev1.Event, reason string, since time.Time) time.Time {
+	var earliest time.Time
+	for _, event := range events {
+		at := eventTime(event)
+		if event.Reason == reason && at.After(since) && (earliest.IsZero() || at.Before(earliest)) {
+			earliest = at
+		}
+	}
+	return earliest
+}
+
+// formatRateUnit renders a rate period as a unit suffix
+func formatRateUnit(per time.Duration) string {
+	switch per {
+	case time.Second:
+		return "sec"
+	case time.Minute:
+		return "min"
+	case time.Hour:
+		return "hour"
+	default:
+		return per.String()
+	}
+}
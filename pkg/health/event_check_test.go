@@ -0,0 +1,195 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestEvent(name, namespace, reason string, count int32, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(namespace + "/" + name),
+		},
+		Reason:        reason,
+		Count:         count,
+		LastTimestamp: metav1.NewTime(last),
+	}
+}
+
+func TestEventRateCheck_Basics(t *testing.T) {
+	check := NewEventRateCheck()
+
+	if check.Name() != "event-rates" {
+		t.Errorf("expected name 'event-rates', got %s", check.Name())
+	}
+	if check.Interval() != 30*time.Second {
+		t.Errorf("expected interval 30s, got %v", check.Interval())
+	}
+	if check.Criticality() != core.CriticalityMedium {
+		t.Errorf("expected criticality medium, got %v", check.Criticality())
+	}
+}
+
+func TestEventRateCheck_FirstRunUsesWindow(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		newTestEvent("evict-1", "default", "Evicted", 10, now.Add(-time.Minute)),
+		newTestEvent("evict-2", "batch", "Evicted", 5, now.Add(-2*time.Minute)),
+		newTestEvent("evict-old", "default", "Evicted", 100, now.Add(-time.Hour)),
+		newTestEvent("sched-1", "default", "FailedScheduling", 1, now.Add(-time.Minute)),
+		newTestEvent("pulled", "default", "Pulled", 50, now.Add(-time.Minute)),
+	)
+
+	check := NewEventRateCheck()
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rates, ok := result.Details["rates"].(map[string]float64)
+	if !ok {
+		t.Fatal("expected rates in details")
+	}
+
+	// 15 evictions inside the default 5 minute window
+	if rates["Evicted"] != 3 {
+		t.Errorf("expected eviction rate 3/min, got %v", rates["Evicted"])
+	}
+	if rates["FailedScheduling"] != 0.2 {
+		t.Errorf("expected scheduling failure rate 0.2/min, got %v", rates["FailedScheduling"])
+	}
+	if _, exists := rates["Pulled"]; exists {
+		t.Error("expected untracked reasons to be ignored")
+	}
+
+	if result.Status != core.HealthStatusDegraded {
+		t.Errorf("expected degraded status for eviction rate above threshold, got %s", result.Status)
+	}
+
+	byNamespace, ok := result.Details["rates_by_namespace"].(map[string]map[string]float64)
+	if !ok {
+		t.Fatal("expected per-namespace rates in details")
+	}
+	if byNamespace["batch"]["Evicted"] != 1 {
+		t.Errorf("expected batch eviction rate 1/min, got %v", byNamespace["batch"]["Evicted"])
+	}
+
+	found := false
+	for _, metric := range result.Metrics {
+		if metric.Name == "event_evictions_per_minute" && metric.Labels["namespace"] == "default" {
+			found = true
+			if metric.Value != 2 {
+				t.Errorf("expected default namespace eviction rate 2/min, got %v", metric.Value)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected per-namespace eviction metric")
+	}
+}
+
+func TestEventRateCheck_CountsDeltasBetweenRuns(t *testing.T) {
+	check := NewEventRateCheck()
+	now := time.Now()
+
+	events := []corev1.Event{
+		*newTestEvent("oom-1", "default", "OOMKilling", 4, now),
+	}
+	check.countNewEvents(events, now)
+
+	// The same event is observed again with a higher count a minute later
+	events[0].Count = 6
+	counts, elapsed := check.countNewEvents(events, now.Add(time.Minute))
+
+	if elapsed != time.Minute {
+		t.Errorf("expected elapsed 1m, got %v", elapsed)
+	}
+	if counts["OOMKilling"]["default"] != 2 {
+		t.Errorf("expected delta of 2 OOM kills, got %d", counts["OOMKilling"]["default"])
+	}
+}
+
+func TestEventRateCheck_Configure(t *testing.T) {
+	check := NewEventRateCheck()
+
+	err := check.Configure(map[string]interface{}{
+		"namespace":  "prod",
+		"window":     10 * time.Minute,
+		"thresholds": map[string]float64{"Evicted": 10},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if check.namespace != "prod" {
+		t.Errorf("expected namespace prod, got %s", check.namespace)
+	}
+	if check.window != 10*time.Minute {
+		t.Errorf("expected window 10m, got %v", check.window)
+	}
+	if check.rates[0].Threshold != 10 {
+		t.Errorf("expected eviction threshold 10, got %v", check.rates[0].Threshold)
+	}
+
+	err = check.Configure(map[string]interface{}{
+		"rates": []EventRate{{Reason: "Evicted"}},
+	})
+	if err == nil {
+		t.Error("expected error for incomplete rate definition")
+	}
+}
+
+func TestEventRateCheck_FirstRunCountsOnlyTheWindow(t *testing.T) {
+	check := NewEventRateCheck()
+	now := time.Now()
+
+	// Evicted 120 times over the last hour, so about 10 in the 5 minute window
+	old := newTestEvent("evict-1", "default", "Evicted", 120, now)
+	old.FirstTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	recent := newTestEvent("evict-2", "default", "Evicted", 3, now.Add(-time.Minute))
+	recent.FirstTimestamp = metav1.NewTime(now.Add(-2 * time.Minute))
+
+	counts, elapsed := check.countNewEvents([]corev1.Event{*old, *recent}, now)
+
+	if elapsed != 5*time.Minute {
+		t.Errorf("expected elapsed 5m, got %v", elapsed)
+	}
+	if counts["Evicted"]["default"] != 13 {
+		t.Errorf("expected 13 evictions inside the window, got %d", counts["Evicted"]["default"])
+	}
+}
+
+func TestEventRateCheck_RunAfterALongGap(t *testing.T) {
+	check := NewEventRateCheck()
+	now := time.Now()
+
+	events := []corev1.Event{
+		*newTestEvent("oom-1", "default", "OOMKilling", 4, now),
+	}
+	check.countNewEvents(events, now)
+
+	// The next run comes 20 minutes later, four windows after the last one
+	events[0].Count = 24
+	later := now.Add(20 * time.Minute)
+	events[0].LastTimestamp = metav1.NewTime(later)
+	counts, elapsed := check.countNewEvents(events, later)
+
+	if elapsed != 20*time.Minute {
+		t.Errorf("expected the deltas to cover 20m, got %v", elapsed)
+	}
+	if counts["OOMKilling"]["default"] != 20 {
+		t.Errorf("expected delta of 20 OOM kills, got %d", counts["OOMKilling"]["default"])
+	}
+	if rate := float64(counts["OOMKilling"]["default"]) / elapsed.Minutes(); rate != 1 {
+		t.Errorf("expected 1 OOM kill/min, got %v", rate)
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxFindings caps the findings a result reports
+const maxFindings = 50
+
+// addFindings classifies problems on a result by finding type so they are
+// tracked across check runs; repeats of a type and subject are skipped
+func addFindings(result *core.CheckResult, list ...findings.Finding) {
+	existing, _ := result.Details[core.DetailFindings].([]findings.Finding)
+	seen := make(map[string]bool, len(existing))
+	for _, finding := range existing {
+		seen[finding.Key()] = true
+	}
+	for _, finding := range list {
+		if len(existing) >= maxFindings {
+			break
+		}
+		if seen[finding.Key()] {
+			continue
+		}
+		seen[finding.Key()] = true
+		existing = append(existing, finding)
+	}
+	if len(existing) > 0 {
+		result.Details[core.DetailFindings] = existing
+	}
+}
+
+// podFindings classifies why a pod isn't running: failed, unschedulable,
+// or containers that can't pull their image, start, or stay up
+func podFindings(pod *corev1.Pod) []findings.Finding {
+	subject := core.ResourceRef{Kind: "pod", Namespace: pod.Namespace, Name: pod.Name}.String()
+	var list []findings.Finding
+	if pod.Status.Phase == corev1.PodFailed {
+		list = append(list, findings.Finding{ID: findings.PodFailed, Subject: subject, Message: pod.Status.Reason})
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == "Unschedulable" {
+			list = append(list, findings.Finding{ID: findings.PodUnschedulable, Subject: subject, Message: condition.Message})
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil {
+			id := ""
+			switch waiting.Reason {
+			case "CrashLoopBackOff":
+				id = findings.PodCrashLoop
+			case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+				id = findings.PodImagePull
+			case "RunContainerError", "CreateContainerError":
+				id = findings.PodStartFailure
+			}
+			if id != "" {
+				list = append(list, findings.Finding{ID: id, Subject: subject, Message: fmt.Sprintf("%s: %s", status.Name, waiting.Reason)})
+			}
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			list = append(list, findings.Finding{ID: findings.PodOOMKilled, Subject: subject, Message: status.Name + ": OOMKilled"})
+		}
+	}
+	return list
+}
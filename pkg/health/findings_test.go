@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodFindings(t *testing.T) {
+	waiting := func(reason string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}
+	}
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   []string
+	}{
+		{"failed", corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}, []string{findings.PodFailed}},
+		{"crash loop", corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{waiting("CrashLoopBackOff")}}, []string{findings.PodCrashLoop}},
+		{"init image pull", corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: []corev1.ContainerStatus{waiting("ErrImagePull")}}, []string{findings.PodImagePull}},
+		{"start failure", corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{waiting("CreateContainerError")}}, []string{findings.PodStartFailure}},
+		{"unschedulable", corev1.PodStatus{Phase: corev1.PodPending, Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"},
+		}}, []string{findings.PodUnschedulable}},
+		{"crash looping after OOM", corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:                 "app",
+			State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+		}}}, []string{findings.PodCrashLoop, findings.PodOOMKilled}},
+		{"creating", corev1.PodStatus{Phase: corev1.PodPending, ContainerStatuses: []corev1.ContainerStatus{waiting("ContainerCreating")}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop"}, Status: tt.status}
+			var got []string
+			for _, finding := range podFindings(pod) {
+				if finding.Subject != "pod/shop/api-1" {
+					t.Errorf("unexpected subject %q", finding.Subject)
+				}
+				got = append(got, finding.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("podFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFindings(t *testing.T) {
+	result := core.CheckResult{Details: map[string]interface{}{}}
+	addFindings(&result)
+	if _, ok := result.Details[core.DetailFindings]; ok {
+		t.Fatal("expected no findings detail without findings")
+	}
+
+	finding := findings.Finding{ID: findings.ServiceNoEndpoint, Subject: "service/shop/api"}
+	addFindings(&result, finding, finding)
+	for i := 0; i < maxFindings+5; i++ {
+		addFindings(&result, findings.Finding{ID: findings.PodFailed, Subject: "pod/shop/web-" + string(rune('a'+i%26)) + string(rune('a'+i/26))})
+	}
+	list := core.Findings(result)
+	if len(list) != maxFindings || list[0] != finding || list[1].ID != findings.PodFailed {
+		t.Errorf("expected repeats skipped and %d findings kept, got %d: %v", maxFindings, len(list), list[:2])
+	}
+}
+
+func TestNodeHealthCheck_Findings(t *testing.T) {
+	pressured := testNode("node-1", true, false)
+	pressured.Status.Conditions = append(pressured.Status.Conditions,
+		corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue})
+	// Nodes in maintenance are expected to misbehave and raise no findings
+	client := fake.NewSimpleClientset(pressured, testNode("node-2", false, true))
+
+	result, err := NewNodeHealthCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []findings.Finding{{ID: findings.NodeMemoryPressure, Subject: "node/node-1", Message: "node-1: MemoryPressure"}}
+	if got := core.Findings(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("Findings() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,22 @@
+package health
+
+import "github.com/kubepulse/kubepulse/pkg/core"
+
+// maxImplicatedResources caps the resources a result names for diagnosis
+const maxImplicatedResources = 10
+
+// setImplicatedResources records the resources behind a failure so AI
+// diagnosis can describe them on demand
+func setImplicatedResources(result *core.CheckResult, refs []core.ResourceRef) {
+	if len(refs) == 0 {
+		return
+	}
+	if len(refs) > maxImplicatedResources {
+		refs = refs[:maxImplicatedResources]
+	}
+	values := make([]string, len(refs))
+	for i, ref := range refs {
+		values[i] = ref.String()
+	}
+	result.Details[core.DetailImplicatedResources] = values
+}
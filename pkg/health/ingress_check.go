@@ -0,0 +1,480 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// gatewayAPIGroup is the API group of the Kubernetes Gateway API
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+// gatewayAPIVersions are tried in order; older installs only serve v1beta1
+var gatewayAPIVersions = []string{"v1", "v1beta1"}
+
+// ingressControllers maps a controller to the app.kubernetes.io/name or app
+// label values its workloads carry
+var ingressControllers = map[string][]string{
+	"ingress-nginx": {"ingress-nginx", "nginx-ingress"},
+	"traefik":       {"traefik"},
+}
+
+// IngressHealthCheck checks the ingress layer: Gateway API GatewayClasses,
+// Gateways and HTTPRoutes, and the workloads and configuration reloads of
+// common ingress controllers. A broken ingress layer is user-facing even
+// when every backend pod is healthy.
+type IngressHealthCheck struct {
+	dynamic      dynamic.Interface // Reads Gateway API resources; nil skips them
+	namespace    string
+	interval     time.Duration
+	reloadWindow time.Duration // How far back reload failures count
+}
+
+// NewIngressHealthCheck creates a new ingress health check. Gateway API
+// resources are only checked with a dynamic client.
+func NewIngressHealthCheck(dynamicClient dynamic.Interface) *IngressHealthCheck {
+	return &IngressHealthCheck{
+		dynamic:      dynamicClient,
+		interval:     30 * time.Second,
+		reloadWindow: 10 * time.Minute,
+	}
+}
+
+// Name returns the name of the health check
+func (c *IngressHealthCheck) Name() string {
+	return "ingress-health"
+}
+
+// Description returns a description of the health check
+func (c *IngressHealthCheck) Description() string {
+	return "Monitors Gateway API resources and ingress controller health"
+}
+
+// ingressFindings collects issues by the status they warrant
+type ingressFindings struct {
+	unhealthy []string
+	degraded  []string
+	ignored   []string
+	runbook   string
+}
+
+// Check performs the ingress health check
+func (c *IngressHealthCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details:   make(map[string]interface{}),
+		Metrics:   []core.Metric{},
+	}
+	var findings ingressFindings
+
+	gatewayAPI, err := c.checkGatewayAPI(ctx, &result, &findings)
+	if err != nil {
+		return result, err
+	}
+	controllers, err := c.checkControllers(ctx, client, &result, &findings)
+	if err != nil {
+		return result, err
+	}
+	result.Details["gateway_api"] = gatewayAPI
+	result.Details["controllers"] = controllers
+
+	issues := append(append([]string{}, findings.unhealthy...), findings.degraded...)
+	switch {
+	case len(findings.unhealthy) > 0:
+		result.Status = core.HealthStatusUnhealthy
+	case len(findings.degraded) > 0:
+		result.Status = core.HealthStatusDegraded
+	}
+
+	switch {
+	case len(issues) > 0:
+		result.Message = fmt.Sprintf("%d ingress issues: %s", len(issues), strings.Join(issues, "; "))
+		result.Details["issues"] = issues
+	case !gatewayAPI && len(controllers) == 0:
+		result.Message = "No Gateway API resources or ingress controllers found"
+	default:
+		result.Message = "Ingress layer is healthy"
+	}
+	result.AffectedResources = len(issues)
+	if findings.runbook != "" {
+		result.Details["runbook"] = findings.runbook
+	}
+	if len(findings.ignored) > 0 {
+		result.Details["ignored_resources"] = findings.ignored
+	}
+
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// checkGatewayAPI checks GatewayClasses, Gateways and HTTPRoutes, reporting
+// whether the Gateway API is installed
+func (c *IngressHealthCheck) checkGatewayAPI(ctx context.Context, result *core.CheckResult, findings *ingressFindings) (bool, error) {
+	if c.dynamic == nil {
+		return false, nil
+	}
+
+	version, classes, err := c.listGatewayResource(ctx, "gatewayclasses", "")
+	if apierrors.IsForbidden(err) {
+		// Without access the controllers are still worth checking
+		result.Details["gateway_api_skipped"] = err.Error()
+		return false, nil
+	}
+	if err != nil || version == "" {
+		return false, err
+	}
+	for _, class := range classes {
+		if c.ignored(class, findings) {
+			continue
+		}
+		if ok, reason := conditionTrue(class.Object, "Accepted", "status", "conditions"); !ok {
+			findings.degraded = append(findings.degraded, fmt.Sprintf("GatewayClass %s not accepted%s", class.GetName(), reason))
+		}
+	}
+
+	gateways, err := c.listGatewayVersion(ctx, version, "gateways", c.namespace)
+	if err != nil {
+		return true, err
+	}
+	notProgrammed := 0
+	for _, gateway := range gateways {
+		if c.ignored(gateway, findings) {
+			continue
+		}
+		name := gateway.GetNamespace() + "/" + gateway.GetName()
+		for _, condition := range []string{"Accepted", "Programmed"} {
+			if ok, reason := conditionTrue(gateway.Object, condition, "status", "conditions"); !ok {
+				notProgrammed++
+				findings.unhealthy = append(findings.unhealthy, fmt.Sprintf("Gateway %s not %s%s", name, strings.ToLower(condition), reason))
+				findings.noteRunbook(gateway.GetAnnotations())
+				break
+			}
+		}
+		listeners, _, _ := unstructured.NestedSlice(gateway.Object, "status", "listeners")
+		for _, listener := range listeners {
+			fields, ok := listener.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ok, reason := conditionTrue(fields, "ResolvedRefs", "conditions"); !ok {
+				listenerName, _, _ := unstructured.NestedString(fields, "name")
+				findings.degraded = append(findings.degraded, fmt.Sprintf("Gateway %s listener %s has unresolved refs%s", name, listenerName, reason))
+			}
+		}
+	}
+
+	routes, err := c.listGatewayVersion(ctx, version, "httproutes", c.namespace)
+	if err != nil {
+		return true, err
+	}
+	unresolved := 0
+	for _, route := range routes {
+		if c.ignored(route, findings) {
+			continue
+		}
+		name := route.GetNamespace() + "/" + route.GetName()
+		parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+		for _, parent := range parents {
+			fields, ok := parent.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ok, reason := conditionTrue(fields, "ResolvedRefs", "conditions"); !ok {
+				unresolved++
+				findings.degraded = append(findings.degraded, fmt.Sprintf("HTTPRoute %s has unresolved refs%s", name, reason))
+				findings.noteRunbook(route.GetAnnotations())
+				break
+			}
+			if ok, reason := conditionTrue(fields, "Accepted", "conditions"); !ok {
+				findings.degraded = append(findings.degraded, fmt.Sprintf("HTTPRoute %s not accepted%s", name, reason))
+				findings.noteRunbook(route.GetAnnotations())
+				break
+			}
+		}
+	}
+
+	result.Details["gateway_classes"] = len(classes)
+	result.Details["gateways"] = len(gateways)
+	result.Details["http_routes"] = len(routes)
+	result.Metrics = append(result.Metrics,
+		gaugeMetric("gateway_api_gateways", float64(len(gateways)), result.Timestamp, nil),
+		gaugeMetric("gateway_api_gateways_not_programmed", float64(notProgrammed), result.Timestamp, nil),
+		gaugeMetric("gateway_api_httproutes_unresolved", float64(unresolved), result.Timestamp, nil),
+	)
+	return true, nil
+}
+
+// listGatewayResource lists a Gateway API resource in the newest served
+// version, returning an empty version when the API isn't installed
+func (c *IngressHealthCheck) listGatewayResource(ctx context.Context, resource, namespace string) (string, []unstructured.Unstructured, error) {
+	for _, version := range gatewayAPIVersions {
+		items, err := c.listGatewayVersion(ctx, version, resource, namespace)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		return version, items, err
+	}
+	return "", nil, nil
+}
+
+// listGatewayVersion lists a Gateway API resource in one version
+func (c *IngressHealthCheck) listGatewayVersion(ctx context.Context, version, resource, namespace string) ([]unstructured.Unstructured, error) {
+	gvr := schema.GroupVersionResource{Group: gatewayAPIGroup, Version: version, Resource: resource}
+	var list *unstructured.UnstructuredList
+	var err error
+	if namespace == "" {
+		list, err = c.dynamic.Resource(gvr).List(ctx, metav1.ListOptions{})
+	} else {
+		list, err = c.dynamic.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", resource, err)
+	}
+	return list.Items, nil
+}
+
+// ignored reports whether a resource is annotated out of this check
+func (c *IngressHealthCheck) ignored(object unstructured.Unstructured, findings *ingressFindings) bool {
+	if !isIgnored(object.GetAnnotations(), c.Name()) {
+		return false
+	}
+	name := object.GetKind() + " " + object.GetName()
+	if object.GetNamespace() != "" {
+		name = object.GetKind() + " " + object.GetNamespace() + "/" + object.GetName()
+	}
+	findings.ignored = append(findings.ignored, name)
+	return true
+}
+
+// checkControllers checks the workloads of known ingress controllers and
+// their recent configuration reload failures, returning the controllers found
+func (c *IngressHealthCheck) checkControllers(ctx context.Context, client kubernetes.Interface, result *core.CheckResult, findings *ingressFindings) ([]string, error) {
+	deployments, err := client.AppsV1().Deployments(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	daemonSets, err := client.AppsV1().DaemonSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+
+	type workload struct {
+		kind, namespace, name string
+		labels, annotations   map[string]string
+		desired, available    int32
+	}
+	var workloads []workload
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		workloads = append(workloads, workload{"Deployment", d.Namespace, d.Name, d.Labels, d.Annotations, desired, d.Status.AvailableReplicas})
+	}
+	for _, ds := range daemonSets.Items {
+		workloads = append(workloads, workload{"DaemonSet", ds.Namespace, ds.Name, ds.Labels, ds.Annotations, ds.Status.DesiredNumberScheduled, ds.Status.NumberAvailable})
+	}
+
+	found := make(map[string]bool)
+	namespaces := make(map[string]bool)
+	for _, w := range workloads {
+		controller := ingressControllerFor(w.labels)
+		if controller == "" {
+			continue
+		}
+		name := w.namespace + "/" + w.name
+		if isIgnored(w.annotations, c.Name()) {
+			findings.ignored = append(findings.ignored, w.kind+" "+name)
+			continue
+		}
+		found[controller] = true
+		namespaces[w.namespace] = true
+
+		switch {
+		case w.desired > 0 && w.available == 0:
+			findings.unhealthy = append(findings.unhealthy, fmt.Sprintf("%s controller %s has no available replicas", controller, name))
+			findings.noteRunbook(w.annotations)
+		case w.available < w.desired:
+			findings.degraded = append(findings.degraded, fmt.Sprintf("%s controller %s has %d of %d replicas available", controller, name, w.available, w.desired))
+			findings.noteRunbook(w.annotations)
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric("ingress_controller_available_replicas", float64(w.available), result.Timestamp,
+			map[string]string{"controller": controller, "namespace": w.namespace, "name": w.name}))
+	}
+
+	for _, ns := range sortedKeys(namespaces) {
+		failures, err := c.reloadFailures(ctx, client, ns, result.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		if failures > 0 {
+			findings.degraded = append(findings.degraded, fmt.Sprintf("%d ingress configuration reload failures in %s in the last %s", failures, ns, c.reloadWindow))
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric("ingress_controller_reload_failures", float64(failures), result.Timestamp,
+			map[string]string{"namespace": ns}))
+	}
+
+	return sortedKeys(found), nil
+}
+
+// reloadFailures counts recent warning events in a controller namespace
+// reporting a failed configuration reload
+func (c *IngressHealthCheck) reloadFailures(ctx context.Context, client kubernetes.Interface, namespace string, now time.Time) (int32, error) {
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+
+	var failures int32
+	for _, event := range events.Items {
+		if event.Type != corev1.EventTypeWarning || !isReloadFailure(event) {
+			continue
+		}
+		if now.Sub(eventTime(event)) > c.reloadWindow {
+			continue
+		}
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		failures += count
+	}
+	return failures, nil
+}
+
+// isReloadFailure reports whether an event describes a failed configuration
+// reload, such as ingress-nginx's RELOAD "Error reloading NGINX" warnings
+func isReloadFailure(event corev1.Event) bool {
+	if strings.EqualFold(event.Reason, "RELOAD") || strings.EqualFold(event.Reason, "ReloadFailed") {
+		return true
+	}
+	message := strings.ToLower(event.Message)
+	return strings.Contains(message, "reload") && (strings.Contains(message, "error") || strings.Contains(message, "fail"))
+}
+
+// ingressControllerFor returns the known controller a workload's labels
+// identify, or ""
+func ingressControllerFor(labels map[string]string) string {
+	for _, key := range []string{"app.kubernetes.io/name", "app"} {
+		value := labels[key]
+		if value == "" {
+			continue
+		}
+		for controller, names := range ingressControllers {
+			for _, name := range names {
+				if value == name {
+					return controller
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// conditionTrue reports whether the named condition in the conditions list
+// at path is True, with " (reason: message)" describing it otherwise. A
+// missing condition is not true: the controller hasn't reconciled the object.
+func conditionTrue(object map[string]interface{}, conditionType string, path ...string) (bool, string) {
+	return conditionIs(object, conditionType, string(metav1.ConditionTrue), path...)
+}
+
+// conditionIs reports whether the named condition in the conditions list at
+// path has the expected status, described as conditionTrue does otherwise
+func conditionIs(object map[string]interface{}, conditionType, expected string, path ...string) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(object, path...)
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _, _ := unstructured.NestedString(condition, "type"); t != conditionType {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		if status == expected {
+			return true, ""
+		}
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		switch {
+		case reason != "" && message != "":
+			return false, fmt.Sprintf(" (%s: %s)", reason, message)
+		case reason != "":
+			return false, fmt.Sprintf(" (%s)", reason)
+		}
+		return false, ""
+	}
+	return false, noStatusReported
+}
+
+// noStatusReported describes a condition the controller hasn't set
+const noStatusReported = " (no status reported)"
+
+// noteRunbook keeps the first runbook annotated on a failing resource
+func (f *ingressFindings) noteRunbook(annotations map[string]string) {
+	if f.runbook == "" {
+		f.runbook = annotatedRunbook(annotations)
+	}
+}
+
+// gaugeMetric returns a gauge metric
+func gaugeMetric(name string, value float64, timestamp time.Time, labels map[string]string) core.Metric {
+	return core.Metric{
+		Name:      name,
+		Value:     value,
+		Labels:    labels,
+		Type:      core.MetricTypeGauge,
+		Timestamp: timestamp,
+	}
+}
+
+// sortedKeys returns a set's keys in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Configure sets up the health check with configuration
+func (c *IngressHealthCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok {
+		c.namespace = v
+	}
+	if v, ok := config["reload_window"].(string); ok {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("invalid reload_window %q", v)
+		}
+		c.reloadWindow = window
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *IngressHealthCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *IngressHealthCheck) Criticality() core.Criticality {
+	return core.CriticalityHigh
+}
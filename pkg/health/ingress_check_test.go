@@ -0,0 +1,300 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// gatewayListKinds registers the Gateway API list kinds with the fake client
+var gatewayListKinds = map[schema.GroupVersionResource]string{
+	{Group: gatewayAPIGroup, Version: "v1", Resource: "gatewayclasses"}: "GatewayClassList",
+	{Group: gatewayAPIGroup, Version: "v1", Resource: "gateways"}:       "GatewayList",
+	{Group: gatewayAPIGroup, Version: "v1", Resource: "httproutes"}:     "HTTPRouteList",
+}
+
+// gatewayResources maps Gateway API kinds to their resources; the fake
+// tracker would guess "gatewaies"
+var gatewayResources = map[string]string{
+	"GatewayClass": "gatewayclasses",
+	"Gateway":      "gateways",
+	"HTTPRoute":    "httproutes",
+}
+
+// newGatewayClient returns a fake dynamic client serving objects
+func newGatewayClient(t *testing.T, objects ...*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	t.Helper()
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for gvr, kind := range gatewayListKinds {
+		listKinds[gvr] = kind
+		listKinds[schema.GroupVersionResource{Group: gvr.Group, Version: "v1beta1", Resource: gvr.Resource}] = kind
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, object := range objects {
+		gvr := schema.GroupVersionResource{Group: gatewayAPIGroup, Version: "v1", Resource: gatewayResources[object.GetKind()]}
+		if _, err := client.Resource(gvr).Namespace(object.GetNamespace()).Create(context.Background(), object, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create %s: %v", object.GetName(), err)
+		}
+	}
+	return client
+}
+
+// gatewayObject returns a Gateway API object with the given status
+func gatewayObject(kind, namespace, name string, status map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatewayAPIGroup + "/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+		"status":     status,
+	}}
+	if namespace != "" {
+		object.SetNamespace(namespace)
+	}
+	return object
+}
+
+// conditions returns a status conditions list
+func conditions(pairs ...string) []interface{} {
+	var list []interface{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		condition := map[string]interface{}{"type": pairs[i], "status": pairs[i+1]}
+		if pairs[i+1] != "True" {
+			condition["reason"] = "Invalid"
+		}
+		list = append(list, condition)
+	}
+	return list
+}
+
+func controllerDeployment(name string, labels map[string]string, replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ingress", Labels: labels},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func TestIngressHealthCheck_GatewayAPI(t *testing.T) {
+	tests := []struct {
+		name       string
+		objects    []*unstructured.Unstructured
+		wantStatus core.HealthStatus
+		wantIssue  string
+	}{
+		{
+			name: "healthy",
+			objects: []*unstructured.Unstructured{
+				gatewayObject("GatewayClass", "", "envoy", map[string]interface{}{"conditions": conditions("Accepted", "True")}),
+				gatewayObject("Gateway", "web", "public", map[string]interface{}{"conditions": conditions("Accepted", "True", "Programmed", "True")}),
+				gatewayObject("HTTPRoute", "web", "shop", map[string]interface{}{"parents": []interface{}{
+					map[string]interface{}{"conditions": conditions("Accepted", "True", "ResolvedRefs", "True")},
+				}}),
+			},
+			wantStatus: core.HealthStatusHealthy,
+		},
+		{
+			name: "gateway not programmed",
+			objects: []*unstructured.Unstructured{
+				gatewayObject("Gateway", "web", "public", map[string]interface{}{"conditions": conditions("Accepted", "True", "Programmed", "False")}),
+			},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "Gateway web/public not programmed (Invalid)",
+		},
+		{
+			name: "gateway never reconciled",
+			objects: []*unstructured.Unstructured{
+				gatewayObject("Gateway", "web", "public", map[string]interface{}{}),
+			},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "Gateway web/public not accepted (no status reported)",
+		},
+		{
+			name: "route with unresolved refs",
+			objects: []*unstructured.Unstructured{
+				gatewayObject("HTTPRoute", "web", "shop", map[string]interface{}{"parents": []interface{}{
+					map[string]interface{}{"conditions": conditions("Accepted", "True", "ResolvedRefs", "False")},
+				}}),
+			},
+			wantStatus: core.HealthStatusDegraded,
+			wantIssue:  "HTTPRoute web/shop has unresolved refs",
+		},
+		{
+			name: "class not accepted",
+			objects: []*unstructured.Unstructured{
+				gatewayObject("GatewayClass", "", "envoy", map[string]interface{}{"conditions": conditions("Accepted", "False")}),
+			},
+			wantStatus: core.HealthStatusDegraded,
+			wantIssue:  "GatewayClass envoy not accepted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewIngressHealthCheck(newGatewayClient(t, tt.objects...))
+
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+			if tt.wantIssue != "" && !strings.Contains(result.Message, tt.wantIssue) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantIssue, result.Message)
+			}
+			if result.Details["gateway_api"] != true {
+				t.Errorf("expected the Gateway API to be detected, got %v", result.Details["gateway_api"])
+			}
+		})
+	}
+}
+
+func TestIngressHealthCheck_GatewayAPIUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"not installed", apierrors.NewNotFound(schema.GroupResource{Group: gatewayAPIGroup, Resource: "gatewayclasses"}, "")},
+		{"forbidden", apierrors.NewForbidden(schema.GroupResource{Group: gatewayAPIGroup, Resource: "gatewayclasses"}, "", nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newGatewayClient(t)
+			dynamicClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.err
+			})
+
+			result, err := NewIngressHealthCheck(dynamicClient).Check(context.Background(), fake.NewSimpleClientset())
+			if err != nil {
+				t.Fatalf("expected the controllers to still be checked, got %v", err)
+			}
+			if result.Status != core.HealthStatusHealthy || result.Details["gateway_api"] != false {
+				t.Errorf("expected a healthy result without the Gateway API, got %s %v", result.Status, result.Details)
+			}
+		})
+	}
+}
+
+func TestIngressHealthCheck_Controllers(t *testing.T) {
+	now := time.Now()
+	nginxLabels := map[string]string{"app.kubernetes.io/name": "ingress-nginx"}
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		wantStatus  core.HealthStatus
+		wantIssue   string
+		controllers []string
+	}{
+		{
+			name:        "no controllers",
+			objects:     []runtime.Object{controllerDeployment("api", map[string]string{"app": "api"}, 1, 0)},
+			wantStatus:  core.HealthStatusHealthy,
+			wantIssue:   "No Gateway API resources or ingress controllers found",
+			controllers: []string{},
+		},
+		{
+			name:        "available",
+			objects:     []runtime.Object{controllerDeployment("ingress-nginx-controller", nginxLabels, 2, 2)},
+			wantStatus:  core.HealthStatusHealthy,
+			controllers: []string{"ingress-nginx"},
+		},
+		{
+			name:        "partially available",
+			objects:     []runtime.Object{controllerDeployment("traefik", map[string]string{"app": "traefik"}, 3, 1)},
+			wantStatus:  core.HealthStatusDegraded,
+			wantIssue:   "traefik controller ingress/traefik has 1 of 3 replicas available",
+			controllers: []string{"traefik"},
+		},
+		{
+			name:        "down",
+			objects:     []runtime.Object{controllerDeployment("ingress-nginx-controller", nginxLabels, 2, 0)},
+			wantStatus:  core.HealthStatusUnhealthy,
+			wantIssue:   "has no available replicas",
+			controllers: []string{"ingress-nginx"},
+		},
+		{
+			name: "reload failures",
+			objects: []runtime.Object{
+				controllerDeployment("ingress-nginx-controller", nginxLabels, 1, 1),
+				&corev1.Event{
+					ObjectMeta:    metav1.ObjectMeta{Name: "reload", Namespace: "ingress"},
+					Type:          corev1.EventTypeWarning,
+					Reason:        "RELOAD",
+					Message:       "Error reloading NGINX: exit status 1",
+					Count:         3,
+					LastTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+				},
+				&corev1.Event{
+					ObjectMeta:    metav1.ObjectMeta{Name: "old-reload", Namespace: "ingress"},
+					Type:          corev1.EventTypeWarning,
+					Reason:        "RELOAD",
+					Message:       "Error reloading NGINX: exit status 1",
+					LastTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+				},
+			},
+			wantStatus:  core.HealthStatusDegraded,
+			wantIssue:   "3 ingress configuration reload failures in ingress",
+			controllers: []string{"ingress-nginx"},
+		},
+		{
+			name: "ignored",
+			objects: []runtime.Object{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "traefik", Namespace: "ingress", Labels: map[string]string{"app": "traefik"},
+					Annotations: map[string]string{AnnotationIgnore: "true"},
+				},
+			}},
+			wantStatus:  core.HealthStatusHealthy,
+			controllers: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewIngressHealthCheck(nil)
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset(tt.objects...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+			if tt.wantIssue != "" && !strings.Contains(result.Message, tt.wantIssue) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantIssue, result.Message)
+			}
+			if got := result.Details["controllers"].([]string); strings.Join(got, ",") != strings.Join(tt.controllers, ",") {
+				t.Errorf("expected controllers %v, got %v", tt.controllers, got)
+			}
+		})
+	}
+}
+
+func TestIngressHealthCheck_Configure(t *testing.T) {
+	check := NewIngressHealthCheck(nil)
+	if err := check.Configure(map[string]interface{}{"namespace": "ingress", "reload_window": "30m"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.namespace != "ingress" || check.reloadWindow != 30*time.Minute {
+		t.Errorf("unexpected configuration %+v", check)
+	}
+	if err := check.Configure(map[string]interface{}{"reload_window": "soon"}); err == nil {
+		t.Error("expected an invalid reload_window to be rejected")
+	}
+	if check.Criticality() != core.CriticalityHigh {
+		t.Errorf("expected high criticality, got %v", check.Criticality())
+	}
+}
@@ -0,0 +1,271 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Log pattern kinds
+const (
+	LogPatternKindError      = "error"
+	LogPatternKindStackTrace = "stack_trace"
+)
+
+var (
+	// Masks applied in order to turn a raw log line into a stable pattern
+	logMasks = []struct {
+		re          *regexp.Regexp
+		replacement string
+	}{
+		{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<ts>"},
+		{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+		{regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`), "<ip>"},
+		{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+		{regexp.MustCompile(`\b\d+\b`), "<n>"},
+	}
+
+	errorLinePattern = regexp.MustCompile(`(?i)\b(error|err|exception|fatal|panic|failed|failure|refused|timeout|timed out|denied|killed|unavailable)\b`)
+
+	stackTraceHeaders = []*regexp.Regexp{
+		regexp.MustCompile(`^panic: `),
+		regexp.MustCompile(`^goroutine \d+ \[`),
+		regexp.MustCompile(`^Traceback \(most recent call last\):`),
+		regexp.MustCompile(`^Exception in thread `),
+		regexp.MustCompile(`^[\w.$]+(?:Exception|Error)(?::|$)`),
+	}
+
+	goFramePattern = regexp.MustCompile(`^[\w./*()\[\]-]+\(.*\)$`)
+)
+
+// LogPatternAnalyzer fetches bounded logs for failing pods and extracts recurring patterns
+type LogPatternAnalyzer struct {
+	tailLines   int64
+	limitBytes  int64
+	maxPods     int
+	maxPatterns int
+	traceFrames int
+}
+
+// NewLogPatternAnalyzer creates a log pattern analyzer with conservative bounds
+func NewLogPatternAnalyzer() *LogPatternAnalyzer {
+	return &LogPatternAnalyzer{
+		tailLines:   200,
+		limitBytes:  64 * 1024,
+		maxPods:     5,
+		maxPatterns: 10,
+		traceFrames: 3,
+	}
+}
+
+// Analyze fetches recent logs for the given pods and returns the top patterns
+func (a *LogPatternAnalyzer) Analyze(ctx context.Context, client kubernetes.Interface, pods []corev1.Pod) []core.LogPattern {
+	logs := make(map[string][]string)
+
+	for i, pod := range pods {
+		if i >= a.maxPods {
+			break
+		}
+
+		key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		lines, err := a.fetchLogs(ctx, client, &pod)
+		if err != nil {
+			klog.V(3).Infof("Failed to fetch logs for pod %s: %v", key, err)
+			continue
+		}
+		logs[key] = lines
+	}
+
+	return a.ExtractPatterns(logs)
+}
+
+// ExtractPatterns clusters error lines and stack traces from logs keyed by pod
+func (a *LogPatternAnalyzer) ExtractPatterns(logs map[string][]string) []core.LogPattern {
+	patterns := make(map[string]*core.LogPattern)
+
+	// Iterate pods in a stable order so examples are deterministic
+	podNames := make([]string, 0, len(logs))
+	for pod := range logs {
+		podNames = append(podNames, pod)
+	}
+	sort.Strings(podNames)
+
+	record := func(kind, pattern, example, pod string) {
+		key := kind + "|" + pattern
+		entry, exists := patterns[key]
+		if !exists {
+			entry = &core.LogPattern{
+				Pattern: pattern,
+				Kind:    kind,
+				Example: example,
+			}
+			patterns[key] = entry
+		}
+		entry.Count++
+		if len(entry.Pods) == 0 || entry.Pods[len(entry.Pods)-1] != pod {
+			entry.Pods = append(entry.Pods, pod)
+		}
+	}
+
+	for _, pod := range podNames {
+		lines := logs[pod]
+		for i := 0; i < len(lines); i++ {
+			line := strings.TrimRight(lines[i], "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			if isStackTraceHeader(line) {
+				signature, consumed := a.traceSignature(lines[i:])
+				record(LogPatternKindStackTrace, signature, line, pod)
+				i += consumed - 1
+				continue
+			}
+
+			if errorLinePattern.MatchString(line) {
+				record(LogPatternKindError, normalizeLogLine(line), line, pod)
+			}
+		}
+	}
+
+	result := make([]core.LogPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		result = append(result, *pattern)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Pattern < result[j].Pattern
+	})
+
+	if len(result) > a.maxPatterns {
+		result = result[:a.maxPatterns]
+	}
+
+	return result
+}
+
+// fetchLogs retrieves the tail of each container's log for a pod
+func (a *LogPatternAnalyzer) fetchLogs(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) ([]string, error) {
+	var lines []string
+	var lastErr error
+
+	for _, container := range pod.Spec.Containers {
+		tailLines := a.tailLines
+		limitBytes := a.limitBytes
+		opts := &corev1.PodLogOptions{
+			Container:  container.Name,
+			TailLines:  &tailLines,
+			LimitBytes: &limitBytes,
+			Previous:   crashedPreviously(pod, container.Name),
+		}
+
+		raw, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lines = append(lines, strings.Split(string(raw), "\n")...)
+	}
+
+	if len(lines) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return lines, nil
+}
+
+// traceSignature builds a signature from a stack trace header and its first frames,
+// returning the number of lines the trace spans
+func (a *LogPatternAnalyzer) traceSignature(lines []string) (string, int) {
+	parts := []string{normalizeLogLine(strings.TrimSpace(lines[0]))}
+	consumed := 1
+
+	for consumed < len(lines) {
+		line := strings.TrimRight(lines[consumed], "\r")
+
+		// Go panics separate the message from the goroutine dump with a blank line
+		if line == "" && consumed+1 < len(lines) && strings.HasPrefix(lines[consumed+1], "goroutine ") {
+			consumed++
+			continue
+		}
+
+		if !isStackFrame(line) {
+			break
+		}
+		if len(parts) <= a.traceFrames && !isFrameLocation(line) && !strings.HasPrefix(line, "goroutine ") {
+			parts = append(parts, normalizeLogLine(strings.TrimSpace(line)))
+		}
+		consumed++
+	}
+
+	// Python prints the exception itself after the frames; use it as the signature head
+	if strings.HasPrefix(lines[0], "Traceback") && consumed < len(lines) && strings.TrimSpace(lines[consumed]) != "" {
+		parts[0] = normalizeLogLine(lines[consumed])
+		consumed++
+	}
+
+	return strings.Join(parts, " <- "), consumed
+}
+
+// normalizeLogLine masks variable parts of a log line
+func normalizeLogLine(line string) string {
+	normalized := strings.TrimSpace(line)
+	for _, mask := range logMasks {
+		normalized = mask.re.ReplaceAllString(normalized, mask.replacement)
+	}
+	if len(normalized) > 200 {
+		normalized = normalized[:200] + "..."
+	}
+	return normalized
+}
+
+// isStackTraceHeader reports whether a line starts a stack trace
+func isStackTraceHeader(line string) bool {
+	for _, header := range stackTraceHeaders {
+		if header.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStackFrame reports whether a line continues a stack trace
+func isStackFrame(line string) bool {
+	if line == "" {
+		return false
+	}
+	if line[0] == ' ' || line[0] == '\t' {
+		return true
+	}
+	if strings.HasPrefix(line, "goroutine ") || strings.HasPrefix(line, "Caused by: ") {
+		return true
+	}
+	return goFramePattern.MatchString(line)
+}
+
+// isFrameLocation reports whether a frame line is only a file location (Go traces)
+func isFrameLocation(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "/") && strings.Contains(trimmed, ".go:")
+}
+
+// crashedPreviously reports whether a container's previous instance terminated,
+// in which case the previous logs hold the failure
+func crashedPreviously(pod *corev1.Pod, containerName string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+		return status.State.Running == nil && status.LastTerminationState.Terminated != nil
+	}
+	return false
+}
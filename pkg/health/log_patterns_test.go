@@ -0,0 +1,185 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNormalizeLogLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "masks numbers",
+			line:     "failed to connect after 3 retries",
+			expected: "failed to connect after <n> retries",
+		},
+		{
+			name:     "masks timestamps and addresses",
+			line:     "2024-01-15T10:30:00Z dial tcp 10.0.0.12:5432: connection refused",
+			expected: "<ts> dial tcp <ip>: connection refused",
+		},
+		{
+			name:     "masks uuids and hex",
+			line:     "request 3f2b1c4d-1111-2222-3333-444455556666 failed at 0xdeadbeef",
+			expected: "request <uuid> failed at <hex>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeLogLine(tt.line); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogPatternAnalyzer_ExtractPatterns_ErrorLines(t *testing.T) {
+	analyzer := NewLogPatternAnalyzer()
+
+	logs := map[string][]string{
+		"default/api-1": {
+			"starting server",
+			"ERROR database connection refused after 1 attempts",
+			"ERROR database connection refused after 2 attempts",
+			"info: listening",
+		},
+		"default/api-2": {
+			"ERROR database connection refused after 5 attempts",
+			"WARN cache miss",
+			"error: config key missing",
+		},
+	}
+
+	patterns := analyzer.ExtractPatterns(logs)
+	if len(patterns) != 2 {
+		t.Fatalf("expected 2 patterns, got %d: %+v", len(patterns), patterns)
+	}
+
+	top := patterns[0]
+	if top.Count != 3 {
+		t.Errorf("expected top pattern count 3, got %d", top.Count)
+	}
+	if top.Kind != LogPatternKindError {
+		t.Errorf("expected kind %s, got %s", LogPatternKindError, top.Kind)
+	}
+	if top.Pattern != "ERROR database connection refused after <n> attempts" {
+		t.Errorf("unexpected pattern %q", top.Pattern)
+	}
+	if top.Example != "ERROR database connection refused after 1 attempts" {
+		t.Errorf("unexpected example %q", top.Example)
+	}
+	if len(top.Pods) != 2 {
+		t.Errorf("expected pattern to reference 2 pods, got %v", top.Pods)
+	}
+}
+
+func TestLogPatternAnalyzer_ExtractPatterns_StackTraces(t *testing.T) {
+	analyzer := NewLogPatternAnalyzer()
+
+	goPanic := []string{
+		"panic: runtime error: invalid memory address or nil pointer dereference",
+		"",
+		"goroutine 1 [running]:",
+		"main.handler(0xc000010000)",
+		"\t/app/main.go:42 +0x1d",
+		"main.main()",
+		"\t/app/main.go:12 +0x25",
+	}
+	pythonTrace := []string{
+		"Traceback (most recent call last):",
+		"  File \"/app/run.py\", line 10, in <module>",
+		"    main()",
+		"ValueError: invalid literal for int() with base 10: 'abc'",
+	}
+
+	logs := map[string][]string{
+		"default/go-1": append(append([]string{}, goPanic...), goPanic...),
+		"default/py-1": pythonTrace,
+	}
+
+	patterns := analyzer.ExtractPatterns(logs)
+	if len(patterns) != 2 {
+		t.Fatalf("expected 2 stack trace patterns, got %d: %+v", len(patterns), patterns)
+	}
+
+	for _, pattern := range patterns {
+		if pattern.Kind != LogPatternKindStackTrace {
+			t.Errorf("expected kind %s, got %s", LogPatternKindStackTrace, pattern.Kind)
+		}
+	}
+
+	if patterns[0].Count != 2 {
+		t.Errorf("expected repeated Go panic to cluster into one pattern with count 2, got %d", patterns[0].Count)
+	}
+	if !strings.Contains(patterns[0].Pattern, "main.handler") {
+		t.Errorf("expected Go trace signature to include first frame, got %q", patterns[0].Pattern)
+	}
+	if !strings.HasPrefix(patterns[1].Pattern, "ValueError") {
+		t.Errorf("expected Python trace signature to start with exception, got %q", patterns[1].Pattern)
+	}
+}
+
+func TestLogPatternAnalyzer_ExtractPatterns_Limit(t *testing.T) {
+	analyzer := NewLogPatternAnalyzer()
+	analyzer.maxPatterns = 2
+
+	logs := map[string][]string{
+		"default/pod": {
+			"error: alpha",
+			"error: beta",
+			"error: gamma",
+		},
+	}
+
+	if patterns := analyzer.ExtractPatterns(logs); len(patterns) != 2 {
+		t.Errorf("expected patterns to be limited to 2, got %d", len(patterns))
+	}
+}
+
+func TestPodHealthCheck_LogPatternsAttached(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodFailed},
+	}
+	client := fake.NewSimpleClientset(pod)
+
+	check := NewPodHealthCheck()
+	if err := check.Configure(map[string]interface{}{"namespace": "default"}); err != nil {
+		t.Fatalf("unexpected configure error: %v", err)
+	}
+
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusUnhealthy {
+		t.Fatalf("expected unhealthy status, got %s", result.Status)
+	}
+
+	// The fake clientset always returns "fake logs", which contains no error lines
+	if _, exists := result.Details["log_patterns"]; exists {
+		t.Error("expected no log patterns for logs without errors")
+	}
+
+	check.logAnalysis = false
+	result, err = check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := result.Details["log_patterns"]; exists {
+		t.Error("expected no log patterns when log analysis is disabled")
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// maintenanceWindow returns when an announced maintenance window ends, or the
+// zero time when the annotations announce none that is still open
+func maintenanceWindow(annotations map[string]string, now time.Time) time.Time {
+	value := strings.TrimSpace(annotations[AnnotationMaintenanceUntil])
+	if value == "" {
+		return time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		klog.V(2).Infof("Ignoring invalid %s annotation %q", AnnotationMaintenanceUntil, value)
+		return time.Time{}
+	}
+	if !until.After(now) {
+		return time.Time{}
+	}
+	return until
+}
+
+// nodeMaintenance describes why a node is intentionally out of service: an
+// open maintenance window, or a cordon. It is empty for nodes in service.
+func nodeMaintenance(node *corev1.Node, now time.Time) string {
+	if until := maintenanceWindow(node.Annotations, now); !until.IsZero() {
+		return "maintenance until " + until.UTC().Format(time.RFC3339)
+	}
+	if node.Spec.Unschedulable {
+		return "cordoned"
+	}
+	return ""
+}
+
+// nodesInMaintenance returns the maintenance reason of every node that is
+// intentionally out of service. Without access to nodes it returns nil, so
+// workload checks degrade to treating every disruption as a failure.
+func nodesInMaintenance(ctx context.Context, client kubernetes.Interface, now time.Time) map[string]string {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.V(2).Infof("Cannot list nodes to recognize maintenance: %v", err)
+		return nil
+	}
+	maintenance := make(map[string]string)
+	for i := range nodes.Items {
+		if reason := nodeMaintenance(&nodes.Items[i], now); reason != "" {
+			maintenance[nodes.Items[i].Name] = reason
+		}
+	}
+	return maintenance
+}
+
+// podDisruption explains why a pod's disruption is expected: it runs on a
+// node in maintenance, being terminated there counts as a drain, or its
+// namespace is in a maintenance window. It is empty for unexpected failures.
+func podDisruption(pod *corev1.Pod, nodes map[string]string, nsAnnotations map[string]string, now time.Time) string {
+	if reason, ok := nodes[pod.Spec.NodeName]; ok {
+		if pod.DeletionTimestamp != nil && reason == "cordoned" {
+			reason = "draining"
+		}
+		return fmt.Sprintf("node %s %s", pod.Spec.NodeName, reason)
+	}
+	if until := maintenanceWindow(nsAnnotations, now); !until.IsZero() {
+		return fmt.Sprintf("namespace %s in maintenance until %s", pod.Namespace, until.UTC().Format(time.RFC3339))
+	}
+	return ""
+}
+
+// setExpectedDisruptions records disruptions caused by planned maintenance.
+// A result whose only issues are expected is classified as expected
+// disruption rather than a failure.
+func setExpectedDisruptions(result *core.CheckResult, disruptions []string) {
+	if len(disruptions) == 0 {
+		return
+	}
+	result.Details[core.DetailExpectedDisruptions] = disruptions
+	if result.Status == core.HealthStatusHealthy {
+		result.Details[core.DetailImpact] = core.ImpactExpectedDisruption
+	}
+}
@@ -0,0 +1,210 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeMaintenance(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		unschedulable bool
+		annotations   map[string]string
+		want          string
+	}{
+		{"in service", false, nil, ""},
+		{"cordoned", true, nil, "cordoned"},
+		{"open window", false, map[string]string{AnnotationMaintenanceUntil: "2026-10-16T14:00:00Z"}, "maintenance until 2026-10-16T14:00:00Z"},
+		{"window wins over cordon", true, map[string]string{AnnotationMaintenanceUntil: "2026-10-16T14:00:00+00:00"}, "maintenance until 2026-10-16T14:00:00Z"},
+		{"closed window", false, map[string]string{AnnotationMaintenanceUntil: "2026-10-16T11:00:00Z"}, ""},
+		{"invalid window", false, map[string]string{AnnotationMaintenanceUntil: "tonight"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Annotations: tt.annotations},
+				Spec:       corev1.NodeSpec{Unschedulable: tt.unschedulable},
+			}
+			if got := nodeMaintenance(node, now); got != tt.want {
+				t.Errorf("nodeMaintenance() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodDisruption(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	nodes := map[string]string{"node-1": "cordoned"}
+	window := map[string]string{AnnotationMaintenanceUntil: "2026-10-16T14:00:00Z"}
+	deleted := metav1.NewTime(now)
+
+	tests := []struct {
+		name        string
+		node        string
+		terminating bool
+		nsAnnots    map[string]string
+		want        string
+	}{
+		{"node in service", "node-2", false, nil, ""},
+		{"cordoned node", "node-1", false, nil, "node node-1 cordoned"},
+		{"evicted from cordoned node", "node-1", true, nil, "node node-1 draining"},
+		{"namespace window", "node-2", false, window, "namespace shop in maintenance until 2026-10-16T14:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.PodSpec{NodeName: tt.node},
+			}
+			if tt.terminating {
+				pod.DeletionTimestamp = &deleted
+			}
+			if got := podDisruption(pod, nodes, tt.nsAnnots, now); got != tt.want {
+				t.Errorf("podDisruption() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func testNode(name string, ready bool, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			Capacity:    capacity,
+			Allocatable: capacity,
+		},
+	}
+}
+
+func TestNodeHealthCheck_Maintenance(t *testing.T) {
+	tests := []struct {
+		name       string
+		nodes      []*corev1.Node
+		wantStatus core.HealthStatus
+		wantImpact interface{}
+		wantIssues bool
+	}{
+		{
+			name:       "cordoned node not ready",
+			nodes:      []*corev1.Node{testNode("node-1", true, false), testNode("node-2", false, true)},
+			wantStatus: core.HealthStatusHealthy,
+			wantImpact: core.ImpactExpectedDisruption,
+		},
+		{
+			name:       "broken node alongside maintenance",
+			nodes:      []*corev1.Node{testNode("node-1", false, false), testNode("node-2", false, true)},
+			wantStatus: core.HealthStatusDegraded,
+			wantImpact: nil,
+			wantIssues: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, node := range tt.nodes {
+				if _, err := client.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("failed to create node: %v", err)
+				}
+			}
+
+			result, err := NewNodeHealthCheck().Check(context.Background(), client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+			if result.Details[core.DetailImpact] != tt.wantImpact {
+				t.Errorf("expected impact %v, got %v", tt.wantImpact, result.Details[core.DetailImpact])
+			}
+			if got := core.ExpectedDisruptions(result); !reflect.DeepEqual(got, []string{"node-2: NotReady (cordoned)"}) {
+				t.Errorf("unexpected expected disruptions %v", got)
+			}
+			if got := result.Details["maintenance_nodes"]; !reflect.DeepEqual(got, []string{"node-2"}) {
+				t.Errorf("expected node-2 in maintenance, got %v", got)
+			}
+			if _, ok := result.Details["issues"]; ok != tt.wantIssues {
+				t.Errorf("expected issues %v, got %v", tt.wantIssues, result.Details["issues"])
+			}
+			if tt.wantIssues && result.AffectedResources != 1 {
+				t.Errorf("expected only the broken node to be affected, got %d", result.AffectedResources)
+			}
+		})
+	}
+}
+
+func TestPodHealthCheck_Maintenance(t *testing.T) {
+	crashing := func(name, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+		}
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		testNode("node-1", true, false),
+		testNode("node-2", true, true),
+		running,
+		crashing("web-1", "node-2"),
+	)
+
+	check := NewPodHealthCheck()
+	check.logAnalysis = false
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusHealthy || result.Details["failed_pods"] != 0 {
+		t.Errorf("expected the pod on the cordoned node not to count as failed, got %s: %s", result.Status, result.Message)
+	}
+	if result.Details[core.DetailImpact] != core.ImpactExpectedDisruption || result.Details["disrupted_pods"] != 1 {
+		t.Errorf("expected expected disruption, got %v", result.Details)
+	}
+	if got := core.ExpectedDisruptions(result); len(got) != 1 || !strings.Contains(got[0], "shop/web-1") {
+		t.Errorf("unexpected expected disruptions %v", got)
+	}
+
+	// The same failure on a node in service is a real failure
+	if _, err := client.CoreV1().Pods("shop").Create(context.Background(), crashing("web-2", "node-1"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	result, err = check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != core.HealthStatusUnhealthy || result.Details["failed_pods"] != 1 {
+		t.Errorf("expected one real failure, got %s: %s", result.Status, result.Message)
+	}
+	if _, ok := result.Details[core.DetailImpact]; ok {
+		t.Error("expected no expected-disruption impact alongside a real failure")
+	}
+}
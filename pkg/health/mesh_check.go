@@ -0,0 +1,569 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Supported service meshes
+const (
+	MeshIstio   = "istio"
+	MeshLinkerd = "linkerd"
+)
+
+// istioRootNamespace holds mesh-wide Istio policy
+const istioRootNamespace = "istio-system"
+
+// meshProfile describes how a service mesh shows up in the cluster
+type meshProfile struct {
+	name         string
+	sidecar      string // Injected proxy container name
+	controlPlane func(labels map[string]string) bool
+	injected     func(ns *corev1.Namespace) bool // Namespace opts pods into injection
+	optedOut     func(pod *corev1.Pod) bool      // Pod opts out of injection
+}
+
+var meshProfiles = []meshProfile{
+	{
+		name:    MeshIstio,
+		sidecar: "istio-proxy",
+		controlPlane: func(labels map[string]string) bool {
+			return labels["app"] == "istiod"
+		},
+		injected: func(ns *corev1.Namespace) bool {
+			return ns.Labels["istio-injection"] == "enabled" ||
+				(ns.Labels["istio.io/rev"] != "" && ns.Labels["istio-injection"] != "disabled")
+		},
+		optedOut: func(pod *corev1.Pod) bool {
+			return pod.Annotations["sidecar.istio.io/inject"] == "false" || pod.Labels["sidecar.istio.io/inject"] == "false"
+		},
+	},
+	{
+		name:    MeshLinkerd,
+		sidecar: "linkerd-proxy",
+		controlPlane: func(labels map[string]string) bool {
+			switch labels["linkerd.io/control-plane-component"] {
+			case "destination", "identity", "proxy-injector":
+				return true
+			}
+			return false
+		},
+		injected: func(ns *corev1.Namespace) bool {
+			return ns.Annotations["linkerd.io/inject"] == "enabled"
+		},
+		optedOut: func(pod *corev1.Pod) bool {
+			return pod.Annotations["linkerd.io/inject"] == "disabled"
+		},
+	},
+}
+
+// ServiceMeshHealthCheck checks an installed Istio or Linkerd mesh: control
+// plane availability, sidecar injection coverage in namespaces that enable
+// it, sidecar restarts, and Istio mTLS policies that contradict each other.
+// Clusters without a mesh report healthy.
+type ServiceMeshHealthCheck struct {
+	dynamic          dynamic.Interface // Reads Istio policies; nil skips mTLS checks
+	namespace        string
+	interval         time.Duration
+	restartThreshold int32
+}
+
+// NewServiceMeshHealthCheck creates a new service mesh health check. Istio
+// mTLS policies are only checked with a dynamic client.
+func NewServiceMeshHealthCheck(dynamicClient dynamic.Interface) *ServiceMeshHealthCheck {
+	return &ServiceMeshHealthCheck{
+		dynamic:          dynamicClient,
+		interval:         30 * time.Second,
+		restartThreshold: 5,
+	}
+}
+
+// Name returns the name of the health check
+func (c *ServiceMeshHealthCheck) Name() string {
+	return "service-mesh"
+}
+
+// Description returns a description of the health check
+func (c *ServiceMeshHealthCheck) Description() string {
+	return "Monitors Istio or Linkerd control plane, sidecar injection and mTLS policy"
+}
+
+// meshGroups are the API groups whose CRDs a mesh installs
+var meshGroups = map[string]string{
+	"networking.istio.io":     MeshIstio,
+	"security.istio.io":       MeshIstio,
+	"policy.linkerd.io":       MeshLinkerd,
+	"linkerd.io":              MeshLinkerd,
+	"workload.linkerd.io":     MeshLinkerd,
+	"multicluster.linkerd.io": MeshLinkerd,
+}
+
+// Relevant reports whether the cluster has Istio or Linkerd CRDs or a
+// control plane
+func (c *ServiceMeshHealthCheck) Relevant(inv *inventory.Inventory) (bool, string) {
+	for _, api := range inv.CustomResources {
+		if mesh, ok := meshGroups[api.Group]; ok {
+			return true, fmt.Sprintf("%s CRDs found (%s)", mesh, api.Group)
+		}
+	}
+	for _, component := range inv.Components {
+		if component.Name == MeshIstio || component.Name == MeshLinkerd {
+			return true, fmt.Sprintf("%s control plane found (%s)", component.Name, component.Workload)
+		}
+	}
+	return false, "No Istio or Linkerd CRDs or control plane found"
+}
+
+// meshFindings collects issues by the status they warrant
+type meshFindings struct {
+	unhealthy []string
+	degraded  []string
+	ignored   []string
+	runbook   string
+}
+
+// noteRunbook keeps the first runbook annotated on a failing resource
+func (f *meshFindings) noteRunbook(annotations ...map[string]string) {
+	if f.runbook == "" {
+		f.runbook = annotatedRunbook(annotations...)
+	}
+}
+
+// Check performs the service mesh health check
+func (c *ServiceMeshHealthCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details:   make(map[string]interface{}),
+		Metrics:   []core.Metric{},
+	}
+	var findings meshFindings
+
+	controlPlanes, err := c.checkControlPlanes(ctx, client, &result, &findings)
+	if err != nil {
+		return result, err
+	}
+	injecting, err := c.injectingNamespaces(ctx, client)
+	if err != nil {
+		return result, err
+	}
+
+	meshes := make(map[string]bool)
+	for mesh := range controlPlanes {
+		meshes[mesh] = true
+	}
+	for _, namespaceMeshes := range injecting {
+		for _, mesh := range namespaceMeshes {
+			meshes[mesh] = true
+		}
+	}
+	if len(meshes) == 0 {
+		result.Message = "No service mesh detected"
+		result.Details["meshes"] = []string{}
+		result.Confidence = 1.0
+		return result, nil
+	}
+
+	for _, mesh := range sortedKeys(meshes) {
+		if !controlPlanes[mesh] && result.Details["control_plane_skipped"] == nil {
+			findings.unhealthy = append(findings.unhealthy, fmt.Sprintf("%s sidecar injection is enabled but no control plane was found", mesh))
+		}
+	}
+	if err := c.checkSidecars(ctx, client, injecting, &result, &findings); err != nil {
+		return result, err
+	}
+	if meshes[MeshIstio] {
+		if err := c.checkMTLS(ctx, &result, &findings); err != nil {
+			return result, err
+		}
+	}
+
+	result.Details["meshes"] = sortedKeys(meshes)
+	issues := append(append([]string{}, findings.unhealthy...), findings.degraded...)
+	switch {
+	case len(findings.unhealthy) > 0:
+		result.Status = core.HealthStatusUnhealthy
+	case len(findings.degraded) > 0:
+		result.Status = core.HealthStatusDegraded
+	}
+	if len(issues) > 0 {
+		result.Message = fmt.Sprintf("%d service mesh issues: %s", len(issues), strings.Join(issues, "; "))
+		result.Details["issues"] = issues
+	} else {
+		result.Message = fmt.Sprintf("Service mesh (%s) is healthy", strings.Join(sortedKeys(meshes), ", "))
+	}
+	result.AffectedResources = len(issues)
+	if findings.runbook != "" {
+		result.Details["runbook"] = findings.runbook
+	}
+	if len(findings.ignored) > 0 {
+		result.Details["ignored_resources"] = findings.ignored
+	}
+
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// checkControlPlanes checks the control plane deployments of every known
+// mesh, returning the meshes whose control plane was found. Control planes
+// live in their own namespace, so they're looked up cluster-wide.
+func (c *ServiceMeshHealthCheck) checkControlPlanes(ctx context.Context, client kubernetes.Interface, result *core.CheckResult, findings *meshFindings) (map[string]bool, error) {
+	found := make(map[string]bool)
+	deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		result.Details["control_plane_skipped"] = err.Error()
+		return found, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		mesh := controlPlaneMesh(d)
+		if mesh == "" {
+			continue
+		}
+		found[mesh] = true
+		name := d.Namespace + "/" + d.Name
+		if isIgnored(d.Annotations, c.Name()) {
+			findings.ignored = append(findings.ignored, "Deployment "+name)
+			continue
+		}
+
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		available := d.Status.AvailableReplicas
+		switch {
+		case desired > 0 && available == 0:
+			findings.unhealthy = append(findings.unhealthy, fmt.Sprintf("%s control plane %s has no available replicas", mesh, name))
+			findings.noteRunbook(d.Annotations)
+		case available < desired:
+			findings.degraded = append(findings.degraded, fmt.Sprintf("%s control plane %s has %d of %d replicas available", mesh, name, available, desired))
+			findings.noteRunbook(d.Annotations)
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric("service_mesh_control_plane_available_replicas", float64(available), result.Timestamp,
+			map[string]string{"mesh": mesh, "namespace": d.Namespace, "name": d.Name}))
+	}
+	result.Details["control_plane"] = sortedKeys(found)
+	return found, nil
+}
+
+// controlPlaneMesh returns the mesh a deployment is a control plane
+// component of, or ""
+func controlPlaneMesh(d *appsv1.Deployment) string {
+	for _, profile := range meshProfiles {
+		if profile.controlPlane(d.Labels) {
+			return profile.name
+		}
+	}
+	return ""
+}
+
+// injectingNamespaces returns the namespaces in scope that enable sidecar
+// injection, with the meshes injecting into each
+func (c *ServiceMeshHealthCheck) injectingNamespaces(ctx context.Context, client kubernetes.Interface) (map[string][]string, error) {
+	var namespaces []corev1.Namespace
+	if c.namespace != "" {
+		ns, err := client.CoreV1().Namespaces().Get(ctx, c.namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", c.namespace, err)
+		}
+		namespaces = []corev1.Namespace{*ns}
+	} else {
+		list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		namespaces = list.Items
+	}
+
+	injecting := make(map[string][]string)
+	for i := range namespaces {
+		ns := &namespaces[i]
+		if isIgnored(ns.Annotations, c.Name()) {
+			continue
+		}
+		for _, profile := range meshProfiles {
+			if profile.injected(ns) {
+				injecting[ns.Name] = append(injecting[ns.Name], profile.name)
+			}
+		}
+	}
+	return injecting, nil
+}
+
+// checkSidecars measures injection coverage in namespaces that enable it and
+// flags sidecars restarting at or above the threshold
+func (c *ServiceMeshHealthCheck) checkSidecars(ctx context.Context, client kubernetes.Interface, injecting map[string][]string, result *core.CheckResult, findings *meshFindings) error {
+	pods, err := client.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var expected, covered int
+	var missing []string
+	var restarts float64
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || pod.Spec.HostNetwork {
+			continue
+		}
+		name := pod.Namespace + "/" + pod.Name
+		if isIgnored(pod.Annotations, c.Name()) {
+			findings.ignored = append(findings.ignored, "Pod "+name)
+			continue
+		}
+
+		for _, profile := range meshProfiles {
+			sidecar, injected := sidecarStatus(pod, profile.sidecar)
+			if injected {
+				if sidecar != nil {
+					restarts += float64(sidecar.RestartCount)
+					if threshold := annotatedRestartThreshold(c.restartThreshold, pod.Annotations); threshold > 0 && sidecar.RestartCount >= threshold {
+						findings.degraded = append(findings.degraded, fmt.Sprintf("%s sidecar in pod %s restarted %d times", profile.sidecar, name, sidecar.RestartCount))
+						findings.noteRunbook(pod.Annotations)
+					}
+				}
+			}
+			if !containsString(injecting[pod.Namespace], profile.name) || profile.optedOut(pod) {
+				continue
+			}
+			expected++
+			if injected {
+				covered++
+			} else {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	coverage := 1.0
+	if expected > 0 {
+		coverage = float64(covered) / float64(expected)
+	}
+	if len(missing) > 0 {
+		findings.degraded = append(findings.degraded, fmt.Sprintf("%d pods in injection-enabled namespaces have no sidecar (%.0f%% coverage): %s",
+			len(missing), coverage*100, truncateList(missing, 5)))
+		result.Details["pods_missing_sidecar"] = missing
+	}
+	result.Details["sidecar_coverage"] = coverage
+	result.Details["injection_namespaces"] = len(injecting)
+	result.Metrics = append(result.Metrics,
+		gaugeMetric("service_mesh_sidecar_coverage", coverage, result.Timestamp, nil),
+		gaugeMetric("service_mesh_pods_missing_sidecar", float64(len(missing)), result.Timestamp, nil),
+		gaugeMetric("service_mesh_sidecar_restarts", restarts, result.Timestamp, nil),
+	)
+	return nil
+}
+
+// sidecarStatus reports whether a pod runs the named proxy, as a regular or
+// native (init) sidecar, with its container status once reported
+func sidecarStatus(pod *corev1.Pod, sidecar string) (*corev1.ContainerStatus, bool) {
+	injected := false
+	for _, container := range pod.Spec.Containers {
+		injected = injected || container.Name == sidecar
+	}
+	for _, container := range pod.Spec.InitContainers {
+		injected = injected || container.Name == sidecar
+	}
+	if !injected {
+		return nil, false
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.ContainerStatuses, pod.Status.InitContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name == sidecar {
+				return &statuses[i], true
+			}
+		}
+	}
+	return nil, true
+}
+
+// checkMTLS reports Istio mTLS policies that contradict each other: several
+// namespace-wide PeerAuthentications in one namespace, and DestinationRules
+// disabling TLS towards namespaces that require strict mTLS
+func (c *ServiceMeshHealthCheck) checkMTLS(ctx context.Context, result *core.CheckResult, findings *meshFindings) error {
+	if c.dynamic == nil {
+		return nil
+	}
+
+	policies, err := c.listIstio(ctx, "security.istio.io", "peerauthentications")
+	if apierrors.IsForbidden(err) {
+		result.Details["mtls_skipped"] = err.Error()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	rules, err := c.listIstio(ctx, "networking.istio.io", "destinationrules")
+	if apierrors.IsForbidden(err) {
+		result.Details["mtls_skipped"] = err.Error()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Namespace-wide modes; the root namespace sets the mesh-wide default
+	modes := make(map[string]string)
+	policyNames := make(map[string][]string)
+	policyNamespaces := make(map[string]bool)
+	for _, policy := range policies {
+		if selector, _, _ := unstructured.NestedMap(policy.Object, "spec", "selector"); len(selector) > 0 {
+			continue
+		}
+		namespace := policy.GetNamespace()
+		policyNames[namespace] = append(policyNames[namespace], policy.GetName())
+		policyNamespaces[namespace] = true
+		if mode, _, _ := unstructured.NestedString(policy.Object, "spec", "mtls", "mode"); mode != "" && mode != "UNSET" {
+			modes[namespace] = mode
+		}
+	}
+
+	var conflicts []string
+	for _, namespace := range sortedKeys(policyNamespaces) {
+		if names := policyNames[namespace]; len(names) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("namespace %s has %d namespace-wide PeerAuthentications (%s); only the oldest applies",
+				namespace, len(names), strings.Join(names, ", ")))
+		}
+	}
+
+	strict := func(namespace string) bool {
+		if mode, ok := modes[namespace]; ok {
+			return mode == "STRICT"
+		}
+		return modes[istioRootNamespace] == "STRICT"
+	}
+	for _, rule := range rules {
+		if c.namespace != "" && rule.GetNamespace() != c.namespace {
+			continue
+		}
+		if mode, _, _ := unstructured.NestedString(rule.Object, "spec", "trafficPolicy", "tls", "mode"); mode != "DISABLE" {
+			continue
+		}
+		host, _, _ := unstructured.NestedString(rule.Object, "spec", "host")
+		target := hostNamespace(host, rule.GetNamespace())
+		if target == "*" {
+			if containsString(mapValues(modes), "STRICT") {
+				conflicts = append(conflicts, fmt.Sprintf("DestinationRule %s/%s disables TLS for %s while strict mTLS is configured",
+					rule.GetNamespace(), rule.GetName(), host))
+				findings.noteRunbook(rule.GetAnnotations())
+			}
+			continue
+		}
+		if strict(target) {
+			conflicts = append(conflicts, fmt.Sprintf("DestinationRule %s/%s disables TLS for %s but namespace %s requires strict mTLS",
+				rule.GetNamespace(), rule.GetName(), host, target))
+			findings.noteRunbook(rule.GetAnnotations())
+		}
+	}
+
+	findings.degraded = append(findings.degraded, conflicts...)
+	if len(modes) > 0 {
+		result.Details["mtls_modes"] = modes
+	}
+	result.Metrics = append(result.Metrics, gaugeMetric("service_mesh_mtls_conflicts", float64(len(conflicts)), result.Timestamp, nil))
+	return nil
+}
+
+// listIstio lists an Istio resource in the newest served version. A resource
+// that isn't installed lists as empty.
+func (c *ServiceMeshHealthCheck) listIstio(ctx context.Context, group, resource string) ([]unstructured.Unstructured, error) {
+	for _, version := range []string{"v1", "v1beta1"} {
+		gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+		list, err := c.dynamic.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if apierrors.IsForbidden(err) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+		return list.Items, nil
+	}
+	return nil, nil
+}
+
+// hostNamespace returns the namespace a DestinationRule host refers to:
+// "svc", "svc.ns" and "svc.ns.svc.cluster.local" forms, with wildcards. A
+// bare "*" matches every namespace.
+func hostNamespace(host, ruleNamespace string) string {
+	if host == "*" {
+		return "*"
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) < 2 {
+		return ruleNamespace
+	}
+	return parts[1]
+}
+
+// mapValues returns a map's values
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateList joins up to limit items, noting how many were left out
+func truncateList(items []string, limit int) string {
+	if len(items) <= limit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:limit], ", "), len(items)-limit)
+}
+
+// Configure sets up the health check with configuration
+func (c *ServiceMeshHealthCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok {
+		c.namespace = v
+	}
+	if v, ok := config["restart_threshold"].(int); ok {
+		if v < 1 {
+			return fmt.Errorf("restart_threshold must be at least 1")
+		}
+		c.restartThreshold = int32(v)
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *ServiceMeshHealthCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *ServiceMeshHealthCheck) Criticality() core.Criticality {
+	return core.CriticalityHigh
+}
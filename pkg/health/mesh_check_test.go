@@ -0,0 +1,318 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/inventory"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// istioListKinds registers the Istio policy list kinds with the fake client
+var istioListKinds = map[schema.GroupVersionResource]string{
+	{Group: "security.istio.io", Version: "v1", Resource: "peerauthentications"}:      "PeerAuthenticationList",
+	{Group: "networking.istio.io", Version: "v1", Resource: "destinationrules"}:       "DestinationRuleList",
+	{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}: "PeerAuthenticationList",
+	{Group: "networking.istio.io", Version: "v1beta1", Resource: "destinationrules"}:  "DestinationRuleList",
+}
+
+func istioPolicy(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	group := "security.istio.io"
+	if kind == "DestinationRule" {
+		group = "networking.istio.io"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": group + "/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+	}}
+}
+
+func istiod(available int32) *appsv1.Deployment {
+	replicas := int32(2)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func meshNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func meshPod(namespace, name string, sidecarRestarts int32, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: container, RestartCount: sidecarRestarts})
+	}
+	return pod
+}
+
+func TestServiceMeshHealthCheck(t *testing.T) {
+	injected := map[string]string{"istio-injection": "enabled"}
+	optedOut := meshPod("shop", "batch", 0, "app")
+	optedOut.Annotations = map[string]string{"sidecar.istio.io/inject": "false"}
+	nativeSidecar := meshPod("shop", "native", 0, "app")
+	nativeSidecar.Spec.InitContainers = []corev1.Container{{Name: "istio-proxy"}}
+
+	tests := []struct {
+		name       string
+		objects    []runtime.Object
+		wantStatus core.HealthStatus
+		wantIssue  string
+		wantMeshes []string
+	}{
+		{
+			name:       "no mesh",
+			objects:    []runtime.Object{meshNamespace("shop", nil), meshPod("shop", "web", 0, "app")},
+			wantStatus: core.HealthStatusHealthy,
+			wantIssue:  "No service mesh detected",
+			wantMeshes: []string{},
+		},
+		{
+			name: "healthy istio",
+			objects: []runtime.Object{
+				istiod(2), meshNamespace("shop", injected),
+				meshPod("shop", "web", 1, "app", "istio-proxy"), optedOut, nativeSidecar,
+				meshPod("other", "web", 0, "app"),
+			},
+			wantStatus: core.HealthStatusHealthy,
+			wantIssue:  "Service mesh (istio) is healthy",
+			wantMeshes: []string{MeshIstio},
+		},
+		{
+			name:       "control plane down",
+			objects:    []runtime.Object{istiod(0)},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "istio control plane istio-system/istiod has no available replicas",
+			wantMeshes: []string{MeshIstio},
+		},
+		{
+			name: "injection without control plane",
+			objects: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{"linkerd.io/inject": "enabled"}}},
+			},
+			wantStatus: core.HealthStatusUnhealthy,
+			wantIssue:  "linkerd sidecar injection is enabled but no control plane was found",
+			wantMeshes: []string{MeshLinkerd},
+		},
+		{
+			name: "pods missing sidecars",
+			objects: []runtime.Object{
+				istiod(2), meshNamespace("shop", injected),
+				meshPod("shop", "web", 0, "app", "istio-proxy"), meshPod("shop", "api", 0, "app"),
+			},
+			wantStatus: core.HealthStatusDegraded,
+			wantIssue:  "1 pods in injection-enabled namespaces have no sidecar (50% coverage): shop/api",
+			wantMeshes: []string{MeshIstio},
+		},
+		{
+			name: "sidecar restarts",
+			objects: []runtime.Object{
+				istiod(2), meshNamespace("shop", injected), meshPod("shop", "web", 7, "app", "istio-proxy"),
+			},
+			wantStatus: core.HealthStatusDegraded,
+			wantIssue:  "istio-proxy sidecar in pod shop/web restarted 7 times",
+			wantMeshes: []string{MeshIstio},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := NewServiceMeshHealthCheck(nil)
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset(tt.objects...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s: %s", tt.wantStatus, result.Status, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantIssue) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantIssue, result.Message)
+			}
+			if got := result.Details["meshes"].([]string); strings.Join(got, ",") != strings.Join(tt.wantMeshes, ",") {
+				t.Errorf("expected meshes %v, got %v", tt.wantMeshes, got)
+			}
+		})
+	}
+}
+
+func TestServiceMeshHealthCheck_MTLS(t *testing.T) {
+	strict := map[string]interface{}{"mtls": map[string]interface{}{"mode": "STRICT"}}
+	permissive := map[string]interface{}{"mtls": map[string]interface{}{"mode": "PERMISSIVE"}}
+	disableTLS := func(host string) map[string]interface{} {
+		return map[string]interface{}{
+			"host":          host,
+			"trafficPolicy": map[string]interface{}{"tls": map[string]interface{}{"mode": "DISABLE"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		policies  []runtime.Object
+		wantIssue string
+	}{
+		{
+			name: "consistent",
+			policies: []runtime.Object{
+				istioPolicy("PeerAuthentication", "istio-system", "default", strict),
+				istioPolicy("DestinationRule", "shop", "legacy", map[string]interface{}{
+					"host": "legacy.shop.svc.cluster.local", "trafficPolicy": map[string]interface{}{"tls": map[string]interface{}{"mode": "ISTIO_MUTUAL"}},
+				}),
+			},
+		},
+		{
+			name: "plaintext to mesh-wide strict",
+			policies: []runtime.Object{
+				istioPolicy("PeerAuthentication", "istio-system", "default", strict),
+				istioPolicy("DestinationRule", "shop", "legacy", disableTLS("legacy")),
+			},
+			wantIssue: "DestinationRule shop/legacy disables TLS for legacy but namespace shop requires strict mTLS",
+		},
+		{
+			name: "namespace overrides mesh-wide strict",
+			policies: []runtime.Object{
+				istioPolicy("PeerAuthentication", "istio-system", "default", strict),
+				istioPolicy("PeerAuthentication", "legacy", "default", permissive),
+				istioPolicy("DestinationRule", "shop", "legacy", disableTLS("db.legacy.svc.cluster.local")),
+			},
+		},
+		{
+			name: "plaintext to strict namespace",
+			policies: []runtime.Object{
+				istioPolicy("PeerAuthentication", "payments", "default", strict),
+				istioPolicy("DestinationRule", "shop", "payments", disableTLS("*.payments.svc.cluster.local")),
+			},
+			wantIssue: "namespace payments requires strict mTLS",
+		},
+		{
+			name: "duplicate namespace-wide policies",
+			policies: []runtime.Object{
+				istioPolicy("PeerAuthentication", "shop", "default", strict),
+				istioPolicy("PeerAuthentication", "shop", "legacy", permissive),
+				istioPolicy("PeerAuthentication", "shop", "workload", map[string]interface{}{
+					"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}}, "mtls": map[string]interface{}{"mode": "DISABLE"},
+				}),
+			},
+			wantIssue: "namespace shop has 2 namespace-wide PeerAuthentications (default, legacy)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), istioListKinds, tt.policies...)
+			check := NewServiceMeshHealthCheck(dynamicClient)
+			result, err := check.Check(context.Background(), fake.NewSimpleClientset(istiod(2)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wantStatus := core.HealthStatusHealthy
+			if tt.wantIssue != "" {
+				wantStatus = core.HealthStatusDegraded
+			}
+			if result.Status != wantStatus {
+				t.Errorf("expected status %s, got %s: %s", wantStatus, result.Status, result.Message)
+			}
+			if tt.wantIssue != "" && !strings.Contains(result.Message, tt.wantIssue) {
+				t.Errorf("expected message to contain %q, got %q", tt.wantIssue, result.Message)
+			}
+		})
+	}
+}
+
+func TestServiceMeshHealthCheck_MTLSUnavailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantSkipped bool
+	}{
+		{"not installed", apierrors.NewNotFound(schema.GroupResource{Group: "security.istio.io", Resource: "peerauthentications"}, ""), false},
+		{"forbidden", apierrors.NewForbidden(schema.GroupResource{Group: "security.istio.io", Resource: "peerauthentications"}, "", nil), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), istioListKinds)
+			dynamicClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.err
+			})
+
+			result, err := NewServiceMeshHealthCheck(dynamicClient).Check(context.Background(), fake.NewSimpleClientset(istiod(2)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != core.HealthStatusHealthy {
+				t.Errorf("expected healthy, got %s: %s", result.Status, result.Message)
+			}
+			if _, skipped := result.Details["mtls_skipped"]; skipped != tt.wantSkipped {
+				t.Errorf("expected mtls_skipped %v, got %v", tt.wantSkipped, result.Details)
+			}
+		})
+	}
+}
+
+func TestServiceMeshHealthCheck_Configure(t *testing.T) {
+	check := NewServiceMeshHealthCheck(nil)
+	if err := check.Configure(map[string]interface{}{"namespace": "shop", "restart_threshold": 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.namespace != "shop" || check.restartThreshold != 10 {
+		t.Errorf("unexpected configuration %+v", check)
+	}
+	if err := check.Configure(map[string]interface{}{"restart_threshold": 0}); err == nil {
+		t.Error("expected a zero restart_threshold to be rejected")
+	}
+}
+
+func TestServiceMeshHealthCheck_Relevant(t *testing.T) {
+	tests := []struct {
+		name string
+		inv  inventory.Inventory
+		want bool
+	}{
+		{"no mesh", inventory.Inventory{CustomResources: []inventory.CustomResourceAPI{{Group: "cert-manager.io"}}}, false},
+		{"istio CRDs", inventory.Inventory{CustomResources: []inventory.CustomResourceAPI{{Group: "networking.istio.io"}}}, true},
+		{"linkerd control plane", inventory.Inventory{Components: []inventory.Component{{Name: MeshLinkerd, Workload: "deployment/linkerd-destination"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if relevant, reason := NewServiceMeshHealthCheck(nil).Relevant(&tt.inv); relevant != tt.want || reason == "" {
+				t.Errorf("expected relevant %v, got %v: %s", tt.want, relevant, reason)
+			}
+		})
+	}
+}
+
+func TestHostNamespace(t *testing.T) {
+	tests := []struct {
+		host, want string
+	}{
+		{"web", "rules"},
+		{"web.shop", "shop"},
+		{"web.shop.svc.cluster.local", "shop"},
+		{"*.shop.svc.cluster.local", "shop"},
+		{"*", "*"},
+	}
+	for _, tt := range tests {
+		if got := hostNamespace(tt.host, "rules"); got != tt.want {
+			t.Errorf("hostNamespace(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
 // NodeHealthCheck checks the health of nodes in the cluster
@@ -17,6 +19,7 @@ type NodeHealthCheck struct {
 	memoryThreshold float64
 	diskThreshold   float64
 	interval        time.Duration
+	problemWindow   time.Duration // How far back node problem detector events count
 }
 
 // NewNodeHealthCheck creates a new node health check
@@ -26,6 +29,7 @@ func NewNodeHealthCheck() *NodeHealthCheck {
 		memoryThreshold: 85.0,
 		diskThreshold:   90.0,
 		interval:        30 * time.Second,
+		problemWindow:   time.Hour,
 	}
 }
 
@@ -36,7 +40,7 @@ func (n *NodeHealthCheck) Name() string {
 
 // Description returns a description of the health check
 func (n *NodeHealthCheck) Description() string {
-	return "Monitors node conditions, resource usage, and readiness"
+	return "Monitors node conditions, node problem detector signals, resource usage, and readiness"
 }
 
 // Check performs the node health check
@@ -54,38 +58,73 @@ func (n *NodeHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 		return result, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
-	var readyNodes, notReadyNodes int
-	var nodeIssues []string
+	now := time.Now()
+	problemEvents, err := nodeProblemEvents(ctx, client, now.Add(-n.problemWindow))
+	if err != nil {
+		klog.V(2).Infof("Skipping node problem detector events: %v", err)
+	}
+
+	var readyNodes, notReadyNodes, problemNodes int
+	var nodeIssues, ignoredNodes, maintenanceNodes, expectedDisruptions, events []string
+	var implicated []core.ResourceRef
+	var runbook string
 	nodeDetails := make([]map[string]interface{}, 0)
 
 	for _, node := range nodes.Items {
+		if isIgnored(node.Annotations, n.Name()) {
+			ignoredNodes = append(ignoredNodes, node.Name)
+			continue
+		}
+
 		nodeInfo := map[string]interface{}{
 			"name": node.Name,
 		}
+		var issues []string
+		var nodeFindings []findings.Finding
+		report := func(id, issue string) {
+			issues = append(issues, issue)
+			nodeFindings = append(nodeFindings, findings.Finding{ID: id, Subject: "node/" + node.Name, Message: issue})
+		}
 
-		// Check node conditions
+		// Check node conditions, noting when failing ones began
 		isReady := false
+		var problems []string
+		var since []time.Time
 		for _, condition := range node.Status.Conditions {
+			reported := len(issues)
 			if condition.Type == corev1.NodeReady {
 				isReady = condition.Status == corev1.ConditionTrue
-				if isReady {
-					readyNodes++
-				} else {
-					notReadyNodes++
-					nodeIssues = append(nodeIssues, fmt.Sprintf("%s: NotReady", node.Name))
+				if !isReady {
+					report(findings.NodeNotReady, fmt.Sprintf("%s: NotReady", node.Name))
 				}
 			}
 
 			// Check for other problematic conditions
 			if condition.Type == corev1.NodeMemoryPressure && condition.Status == corev1.ConditionTrue {
-				nodeIssues = append(nodeIssues, fmt.Sprintf("%s: MemoryPressure", node.Name))
+				report(findings.NodeMemoryPressure, fmt.Sprintf("%s: MemoryPressure", node.Name))
 			}
 			if condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue {
-				nodeIssues = append(nodeIssues, fmt.Sprintf("%s: DiskPressure", node.Name))
+				report(findings.NodeDiskPressure, fmt.Sprintf("%s: DiskPressure", node.Name))
 			}
 			if condition.Type == corev1.NodePIDPressure && condition.Status == corev1.ConditionTrue {
-				nodeIssues = append(nodeIssues, fmt.Sprintf("%s: PIDPressure", node.Name))
+				report(findings.NodePIDPressure, fmt.Sprintf("%s: PIDPressure", node.Name))
+			}
+
+			// Kernel and runtime problems from node problem detector
+			if id := nodeProblemFinding(condition); id != "" {
+				report(id, nodeProblemIssue(node.Name, condition))
+				problems = append(problems, string(condition.Type))
 			}
+			if len(issues) > reported {
+				since = append(since, condition.LastTransitionTime.Time)
+			}
+		}
+		if len(problems) > 0 {
+			nodeInfo["problems"] = problems
+		}
+		if nodeEvents := problemEvents[node.Name]; len(nodeEvents) > 0 {
+			nodeInfo["problem_events"] = len(nodeEvents)
+			events = append(events, nodeEvents...)
 		}
 
 		// Calculate resource usage
@@ -111,12 +150,43 @@ func (n *NodeHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 
 		// Check thresholds
 		if cpuPercent > n.cpuThreshold {
-			nodeIssues = append(nodeIssues, fmt.Sprintf("%s: High CPU usage (%.1f%%)", node.Name, cpuPercent))
+			report(findings.NodeHighCPU, fmt.Sprintf("%s: High CPU usage (%.1f%%)", node.Name, cpuPercent))
 		}
 		if memoryPercent > n.memoryThreshold {
-			nodeIssues = append(nodeIssues, fmt.Sprintf("%s: High memory usage (%.1f%%)", node.Name, memoryPercent))
+			report(findings.NodeHighMemory, fmt.Sprintf("%s: High memory usage (%.1f%%)", node.Name, memoryPercent))
 		}
 
+		// Nodes taken out of service on purpose are expected to misbehave;
+		// their issues are reported but don't count against health
+		if maintenance := nodeMaintenance(&node, now); maintenance != "" {
+			nodeInfo["maintenance"] = maintenance
+			maintenanceNodes = append(maintenanceNodes, node.Name)
+			for _, issue := range issues {
+				expectedDisruptions = append(expectedDisruptions, fmt.Sprintf("%s (%s)", issue, maintenance))
+			}
+			if isReady {
+				readyNodes++
+			}
+		} else {
+			if isReady {
+				readyNodes++
+			} else {
+				notReadyNodes++
+			}
+			if len(problems) > 0 {
+				problemNodes++
+			}
+			if len(issues) > 0 {
+				nodeIssues = append(nodeIssues, issues...)
+				addFindings(&result, nodeFindings...)
+				implicated = append(implicated, core.ResourceRef{Kind: "node", Name: node.Name})
+				core.SetObservedAt(&result, since...)
+				result.AffectedResources++
+				if runbook == "" {
+					runbook = annotatedRunbook(node.Annotations)
+				}
+			}
+		}
 		nodeDetails = append(nodeDetails, nodeInfo)
 
 		// Add node-specific metrics
@@ -143,13 +213,18 @@ func (n *NodeHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 	}
 
 	// Determine overall status
-	totalNodes := len(nodes.Items)
+	totalNodes := len(nodes.Items) - len(ignoredNodes)
 	if notReadyNodes > 0 {
 		result.Status = core.HealthStatusDegraded
 		result.Message = fmt.Sprintf("%d of %d nodes are not ready", notReadyNodes, totalNodes)
+	} else if problemNodes > 0 {
+		result.Status = core.HealthStatusDegraded
+		result.Message = fmt.Sprintf("%d of %d nodes report kernel or container runtime problems", problemNodes, totalNodes)
 	} else if len(nodeIssues) > 0 {
 		result.Status = core.HealthStatusDegraded
 		result.Message = "Some nodes have resource pressure"
+	} else if len(maintenanceNodes) > 0 {
+		result.Message = fmt.Sprintf("All %d nodes in service are healthy; %d in maintenance", totalNodes-len(maintenanceNodes), len(maintenanceNodes))
 	} else {
 		result.Message = fmt.Sprintf("All %d nodes are healthy", totalNodes)
 	}
@@ -162,6 +237,24 @@ func (n *NodeHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 	if len(nodeIssues) > 0 {
 		result.Details["issues"] = nodeIssues
 	}
+	if len(events) > 0 {
+		// Node problem detector events reach AI analysis as the check's events
+		if len(events) > maxNodeProblemEvents {
+			events = events[len(events)-maxNodeProblemEvents:]
+		}
+		result.Details["events"] = events
+	}
+	if len(ignoredNodes) > 0 {
+		result.Details["ignored_nodes"] = ignoredNodes
+	}
+	if len(maintenanceNodes) > 0 {
+		result.Details["maintenance_nodes"] = maintenanceNodes
+	}
+	setExpectedDisruptions(&result, expectedDisruptions)
+	setImplicatedResources(&result, implicated)
+	if runbook != "" {
+		result.Details["runbook"] = runbook
+	}
 
 	// Add summary metrics
 	result.Metrics = append(result.Metrics,
@@ -177,6 +270,12 @@ func (n *NodeHealthCheck) Check(ctx context.Context, client kubernetes.Interface
 			Type:      core.MetricTypeGauge,
 			Timestamp: time.Now(),
 		},
+		core.Metric{
+			Name:      "node_problem_nodes",
+			Value:     float64(problemNodes),
+			Type:      core.MetricTypeGauge,
+			Timestamp: time.Now(),
+		},
 	)
 
 	result.Confidence = 1.0
@@ -195,6 +294,13 @@ func (n *NodeHealthCheck) Configure(config map[string]interface{}) error {
 	if v, ok := config["disk_threshold"].(float64); ok {
 		n.diskThreshold = v
 	}
+	if v, ok := config["problem_event_window"].(string); ok {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("invalid problem_event_window %q", v)
+		}
+		n.problemWindow = window
+	}
 	return nil
 }
 
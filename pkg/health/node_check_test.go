@@ -45,7 +45,7 @@ func TestNodeHealthCheck_Description(t *testing.T) {
 	check := NewNodeHealthCheck()
 
 	description := check.Description()
-	expected := "Monitors node conditions, resource usage, and readiness"
+	expected := "Monitors node conditions, node problem detector signals, resource usage, and readiness"
 	if description != expected {
 		t.Errorf("expected description '%s', got '%s'", expected, description)
 	}
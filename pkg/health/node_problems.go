@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Node conditions node problem detector's kernel monitor sets
+const (
+	nodeKernelDeadlock     = "KernelDeadlock"
+	nodeReadonlyFilesystem = "ReadonlyFilesystem"
+)
+
+// nodeRuntimeRestartConditions are set by node problem detector's systemd
+// monitor when the kubelet or container runtime keeps restarting
+var nodeRuntimeRestartConditions = map[string]bool{
+	"FrequentKubeletRestart":    true,
+	"FrequentDockerRestart":     true,
+	"FrequentContainerdRestart": true,
+	"FrequentCrioRestart":       true,
+}
+
+// kubeletNodeConditions are set by the kubelet or the cloud provider rather
+// than a problem detector. RebootRequired is reported by node-versions.
+var kubeletNodeConditions = map[corev1.NodeConditionType]bool{
+	corev1.NodeReady:              true,
+	corev1.NodeMemoryPressure:     true,
+	corev1.NodeDiskPressure:       true,
+	corev1.NodePIDPressure:        true,
+	corev1.NodeNetworkUnavailable: true,
+	nodeRebootCondition:           true,
+}
+
+// maxNodeProblemEvents caps the node problem events kept per check run
+const maxNodeProblemEvents = 20
+
+// nodeProblemFinding returns the finding for a node condition raised by node
+// problem detector or a similar daemon, or "" when the condition doesn't
+// report a problem. Conditions outside the kubelet's own are problems while
+// true, so custom problem daemon plugins are reported too.
+func nodeProblemFinding(condition corev1.NodeCondition) string {
+	if condition.Status != corev1.ConditionTrue || kubeletNodeConditions[condition.Type] {
+		return ""
+	}
+	switch {
+	case string(condition.Type) == nodeKernelDeadlock:
+		return findings.NodeKernelDeadlock
+	case string(condition.Type) == nodeReadonlyFilesystem:
+		return findings.NodeReadonlyFS
+	case nodeRuntimeRestartConditions[string(condition.Type)]:
+		return findings.NodeRuntimeRestart
+	default:
+		return findings.NodeProblem
+	}
+}
+
+// nodeProblemIssue describes a problem condition on a node
+func nodeProblemIssue(node string, condition corev1.NodeCondition) string {
+	if condition.Message == "" {
+		return fmt.Sprintf("%s: %s", node, condition.Type)
+	}
+	return fmt.Sprintf("%s: %s (%s)", node, condition.Type, condition.Message)
+}
+
+// isNodeProblemEvent reports whether a node event comes from one of node
+// problem detector's monitors, such as KernelOops or TaskHung from the
+// kernel monitor or ContainerdStart from the systemd monitor
+func isNodeProblemEvent(event corev1.Event) bool {
+	component := event.Source.Component
+	if component == "" {
+		component = event.ReportingController
+	}
+	return strings.HasSuffix(component, "-monitor") || component == "node-problem-detector"
+}
+
+// nodeProblemEvents returns node problem detector's events since a time,
+// keyed by node and formatted for AI analysis, latest last
+func nodeProblemEvents(ctx context.Context, client kubernetes.Interface, since time.Time) (map[string][]string, error) {
+	list, err := client.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "involvedObject.kind=Node"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node events: %w", err)
+	}
+
+	var recent []corev1.Event
+	for _, event := range list.Items {
+		if event.InvolvedObject.Kind == "Node" && isNodeProblemEvent(event) && !eventTime(event).Before(since) {
+			recent = append(recent, event)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return eventTime(recent[i]).Before(eventTime(recent[j]))
+	})
+
+	events := make(map[string][]string)
+	for _, event := range recent {
+		line := fmt.Sprintf("%s: %s: %s", event.InvolvedObject.Name, event.Reason, event.Message)
+		if event.Count > 1 {
+			line = fmt.Sprintf("%s: %s (x%d): %s", event.InvolvedObject.Name, event.Reason, event.Count, event.Message)
+		}
+		events[event.InvolvedObject.Name] = append(events[event.InvolvedObject.Name], line)
+	}
+	return events, nil
+}
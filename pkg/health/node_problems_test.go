@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func problemNode(name string, conditions ...corev1.NodeCondition) *corev1.Node {
+	conditions = append(conditions, corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue})
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: conditions,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			},
+		},
+	}
+}
+
+func nodeEvent(name, node, component, reason string, count int32, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: node},
+		Source:         corev1.EventSource{Component: component, Host: node},
+		Reason:         reason,
+		Message:        reason + " on " + node,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestNodeProblemFinding(t *testing.T) {
+	tests := []struct {
+		condition string
+		status    corev1.ConditionStatus
+		want      string
+	}{
+		{condition: "KernelDeadlock", status: corev1.ConditionTrue, want: findings.NodeKernelDeadlock},
+		{condition: "ReadonlyFilesystem", status: corev1.ConditionTrue, want: findings.NodeReadonlyFS},
+		{condition: "FrequentContainerdRestart", status: corev1.ConditionTrue, want: findings.NodeRuntimeRestart},
+		{condition: "FrequentKubeletRestart", status: corev1.ConditionTrue, want: findings.NodeRuntimeRestart},
+		{condition: "CorruptDockerOverlay2", status: corev1.ConditionTrue, want: findings.NodeProblem},
+		{condition: "KernelDeadlock", status: corev1.ConditionFalse, want: ""},
+		{condition: "Ready", status: corev1.ConditionTrue, want: ""},
+		{condition: "MemoryPressure", status: corev1.ConditionTrue, want: ""},
+		{condition: "RebootRequired", status: corev1.ConditionTrue, want: ""},
+	}
+	for _, tt := range tests {
+		got := nodeProblemFinding(corev1.NodeCondition{Type: corev1.NodeConditionType(tt.condition), Status: tt.status})
+		if got != tt.want {
+			t.Errorf("nodeProblemFinding(%s=%s) = %q, want %q", tt.condition, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNodeHealthCheck_NodeProblems(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		problemNode("worker-1",
+			corev1.NodeCondition{Type: nodeKernelDeadlock, Status: corev1.ConditionTrue, Message: "task containerd:1234 blocked for more than 120 seconds"},
+			corev1.NodeCondition{Type: nodeReadonlyFilesystem, Status: corev1.ConditionFalse}),
+		problemNode("worker-2",
+			corev1.NodeCondition{Type: "FrequentContainerdRestart", Status: corev1.ConditionTrue}),
+		problemNode("worker-3"),
+		nodeEvent("oops", "worker-1", "kernel-monitor", "KernelOops", 1, now.Add(-5*time.Minute)),
+		nodeEvent("hung", "worker-1", "kernel-monitor", "TaskHung", 3, now.Add(-10*time.Minute)),
+		nodeEvent("restart", "worker-2", "systemd-monitor", "ContainerdStart", 6, now.Add(-2*time.Minute)),
+		nodeEvent("old", "worker-3", "kernel-monitor", "KernelOops", 1, now.Add(-3*time.Hour)),
+		nodeEvent("kubelet", "worker-3", "kubelet", "NodeReady", 1, now.Add(-time.Minute)),
+	)
+
+	result, err := NewNodeHealthCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if result.Status != core.HealthStatusDegraded || result.Message != "2 of 3 nodes report kernel or container runtime problems" {
+		t.Errorf("expected node problems to degrade the check, got %s: %s", result.Status, result.Message)
+	}
+
+	var got []string
+	for _, finding := range result.Details[core.DetailFindings].([]findings.Finding) {
+		got = append(got, finding.Key())
+	}
+	sort.Strings(got)
+	want := []string{"KP-NODE-010 node/worker-1", "KP-NODE-012 node/worker-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+	issues := strings.Join(result.Details["issues"].([]string), "\n")
+	if !strings.Contains(issues, "worker-1: KernelDeadlock (task containerd:1234 blocked for more than 120 seconds)") {
+		t.Errorf("expected the condition message in the issues, got:\n%s", issues)
+	}
+
+	// Recent events from node problem detector's monitors reach AI analysis
+	wantEvents := []string{
+		"worker-1: TaskHung (x3): TaskHung on worker-1",
+		"worker-1: KernelOops: KernelOops on worker-1",
+		"worker-2: ContainerdStart (x6): ContainerdStart on worker-2",
+	}
+	events, _ := result.Details["events"].([]string)
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("got events %v, want %v", events, wantEvents)
+	}
+}
@@ -0,0 +1,370 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeRebootCondition is the node condition node problem detector's
+// reboot-required plugin sets when /var/run/reboot-required exists
+const nodeRebootCondition = "RebootRequired"
+
+// osVersionPattern finds the release in an OS image such as
+// "Ubuntu 22.04.4 LTS" or "Bottlerocket OS 1.19.2 (aws-k8s-1.29)"
+var osVersionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// NodeVersionCheck checks node software against the control plane: kubelets
+// outside the supported version skew, nodes running an older release of
+// their OS image than their peers, and nodes waiting for a reboot. It
+// recommends the upgrades that would clear them.
+type NodeVersionCheck struct {
+	interval       time.Duration
+	maxKubeletSkew uint          // Minor versions a kubelet may trail the control plane
+	rebootWindow   time.Duration // How far back reboot-required events count
+}
+
+// NewNodeVersionCheck creates a new node version check. Kubelets may trail
+// the control plane by three minor versions, the skew Kubernetes supports
+// since 1.28.
+func NewNodeVersionCheck() *NodeVersionCheck {
+	return &NodeVersionCheck{
+		interval:       30 * time.Second,
+		maxKubeletSkew: 3,
+		rebootWindow:   time.Hour,
+	}
+}
+
+// Name returns the name of the health check
+func (c *NodeVersionCheck) Name() string {
+	return "node-versions"
+}
+
+// Description returns a description of the health check
+func (c *NodeVersionCheck) Description() string {
+	return "Detects kubelet version skew, outdated node OS images and pending node reboots"
+}
+
+// nodeVersionFindings collects issues and the upgrades that would fix them
+type nodeVersionFindings struct {
+	degraded        []string
+	notes           []string // Issues that don't affect health
+	recommendations []string
+	implicated      []core.ResourceRef
+}
+
+// report records an issue on a node as a finding
+func (f *nodeVersionFindings) report(result *core.CheckResult, id, node, issue string, degrading bool) {
+	if degrading {
+		f.degraded = append(f.degraded, issue)
+	} else {
+		f.notes = append(f.notes, issue)
+	}
+	f.implicated = append(f.implicated, core.ResourceRef{Kind: "node", Name: node})
+	addFindings(result, findings.Finding{ID: id, Subject: "node/" + node, Message: issue})
+}
+
+// Check performs the node version check
+func (c *NodeVersionCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details:   make(map[string]interface{}),
+		Metrics:   []core.Metric{},
+	}
+
+	list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var nodes, ignored []corev1.Node
+	for _, node := range list.Items {
+		if isIgnored(node.Annotations, c.Name()) {
+			ignored = append(ignored, node)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	var f nodeVersionFindings
+	if err := c.checkKubeletSkew(client, nodes, &result, &f); err != nil {
+		return result, err
+	}
+	c.checkOSImages(nodes, &result, &f)
+	if err := c.checkReboots(ctx, client, nodes, &result, &f); err != nil {
+		return result, err
+	}
+
+	issues := append(append([]string{}, f.degraded...), f.notes...)
+	if len(f.degraded) > 0 {
+		result.Status = core.HealthStatusDegraded
+	}
+	if len(issues) > 0 {
+		result.Message = fmt.Sprintf("%d node version issues: %s", len(issues), strings.Join(issues, "; "))
+		result.Details["issues"] = issues
+	} else {
+		result.Message = fmt.Sprintf("All %d nodes are within supported versions and up to date", len(nodes))
+	}
+	if len(f.recommendations) > 0 {
+		result.Details[core.DetailUpgradeRecommendations] = f.recommendations
+	}
+	result.AffectedResources = len(f.degraded)
+	setImplicatedResources(&result, f.implicated)
+	result.Details["total_nodes"] = len(nodes)
+	if len(ignored) > 0 {
+		names := make([]string, len(ignored))
+		for i, node := range ignored {
+			names[i] = node.Name
+		}
+		result.Details["ignored_nodes"] = names
+	}
+
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// checkKubeletSkew compares each kubelet with the API server's version
+func (c *NodeVersionCheck) checkKubeletSkew(client kubernetes.Interface, nodes []corev1.Node, result *core.CheckResult, f *nodeVersionFindings) error {
+	serverInfo, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get the control plane version: %w", err)
+	}
+	controlPlane, err := version.ParseGeneric(serverInfo.GitVersion)
+	if err != nil {
+		// Without a readable control plane version there's nothing to compare
+		result.Details["kubelet_skew_skipped"] = fmt.Sprintf("unrecognized control plane version %q", serverInfo.GitVersion)
+		return nil
+	}
+	result.Details["control_plane_version"] = serverInfo.GitVersion
+	controlPlaneMinor := fmt.Sprintf("v%d.%d", controlPlane.Major(), controlPlane.Minor())
+
+	kubelets := make(map[string]int)
+	// Nodes by kubelet minor version
+	behind := make(map[string][]string)
+	ahead := make(map[string][]string)
+	atLimit := make(map[string][]string)
+	for _, node := range nodes {
+		kubeletVersion := node.Status.NodeInfo.KubeletVersion
+		kubelets[kubeletVersion]++
+		kubelet, err := version.ParseGeneric(kubeletVersion)
+		if err != nil {
+			continue
+		}
+		kubeletMinor := fmt.Sprintf("v%d.%d", kubelet.Major(), kubelet.Minor())
+		skew := int(controlPlane.Minor()) - int(kubelet.Minor())
+		if kubelet.Major() != controlPlane.Major() {
+			skew = int(c.maxKubeletSkew) + 1
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric("node_kubelet_minor_skew", float64(skew), result.Timestamp,
+			map[string]string{"node": node.Name}))
+
+		switch {
+		case skew < 0:
+			ahead[kubeletMinor] = append(ahead[kubeletMinor], node.Name)
+			f.report(result, findings.NodeVersionSkew, node.Name,
+				fmt.Sprintf("%s: kubelet %s is newer than the control plane %s", node.Name, kubeletVersion, serverInfo.GitVersion), true)
+		case skew > int(c.maxKubeletSkew):
+			behind[kubeletMinor] = append(behind[kubeletMinor], node.Name)
+			f.report(result, findings.NodeVersionSkew, node.Name,
+				fmt.Sprintf("%s: kubelet %s is %d minor versions behind the control plane %s, more than the supported %d", node.Name, kubeletVersion, skew, serverInfo.GitVersion, c.maxKubeletSkew), true)
+		case skew == int(c.maxKubeletSkew) && skew > 0:
+			atLimit[kubeletMinor] = append(atLimit[kubeletMinor], node.Name)
+		}
+	}
+	result.Details["kubelet_versions"] = kubelets
+
+	for _, minor := range sortedGroups(behind) {
+		f.recommendations = append(f.recommendations, fmt.Sprintf("Upgrade the kubelet on %s from %s to within %d minor versions of the control plane (%s)",
+			nodeList(behind[minor]), minor, c.maxKubeletSkew, controlPlaneMinor))
+	}
+	for _, minor := range sortedGroups(ahead) {
+		f.recommendations = append(f.recommendations, fmt.Sprintf("Upgrade the control plane to at least %s before its kubelets, or roll %s back to %s",
+			minor, nodeList(ahead[minor]), controlPlaneMinor))
+	}
+	for _, minor := range sortedGroups(atLimit) {
+		f.recommendations = append(f.recommendations, fmt.Sprintf("Upgrade the kubelet on %s from %s before upgrading the control plane past %s",
+			nodeList(atLimit[minor]), minor, controlPlaneMinor))
+	}
+	return nil
+}
+
+// checkOSImages flags nodes running an older release of their OS image than
+// the newest node of the same distribution
+func (c *NodeVersionCheck) checkOSImages(nodes []corev1.Node, result *core.CheckResult, f *nodeVersionFindings) {
+	images := make(map[string]int)
+	newest := make(map[string]*version.Version) // By distribution
+	newestImage := make(map[string]string)
+	for _, node := range nodes {
+		image := node.Status.NodeInfo.OSImage
+		if image == "" {
+			continue
+		}
+		images[image]++
+		distribution, release := osRelease(image)
+		if release == nil {
+			continue
+		}
+		if current, ok := newest[distribution]; !ok || current.LessThan(release) {
+			newest[distribution] = release
+			newestImage[distribution] = image
+		}
+	}
+	result.Details["os_images"] = images
+
+	outdated := make(map[string][]string) // Nodes by image
+	outdatedNodes := 0
+	for _, node := range nodes {
+		image := node.Status.NodeInfo.OSImage
+		distribution, release := osRelease(image)
+		if release == nil || !release.LessThan(newest[distribution]) {
+			continue
+		}
+		outdated[image] = append(outdated[image], node.Name)
+		outdatedNodes++
+		f.report(result, findings.NodeOutdatedOS, node.Name,
+			fmt.Sprintf("%s: OS image %s is older than %s", node.Name, image, newestImage[distribution]), false)
+	}
+	for _, image := range sortedGroups(outdated) {
+		distribution, _ := osRelease(image)
+		f.recommendations = append(f.recommendations, fmt.Sprintf("Replace or reimage %s from %s to %s",
+			nodeList(outdated[image]), image, newestImage[distribution]))
+	}
+	result.Metrics = append(result.Metrics, gaugeMetric("nodes_outdated_os_image", float64(outdatedNodes), result.Timestamp, nil))
+}
+
+// osRelease splits an OS image into its distribution and release version,
+// returning a nil version when the image names none
+func osRelease(image string) (string, *version.Version) {
+	location := osVersionPattern.FindStringIndex(image)
+	if location == nil {
+		return image, nil
+	}
+	release, err := version.ParseGeneric(image[location[0]:location[1]])
+	if err != nil {
+		return image, nil
+	}
+	return strings.TrimSpace(image[:location[0]]), release
+}
+
+// checkReboots finds nodes whose node problem detector reports a pending
+// reboot, through its RebootRequired condition or recent events
+func (c *NodeVersionCheck) checkReboots(ctx context.Context, client kubernetes.Interface, nodes []corev1.Node, result *core.CheckResult, f *nodeVersionFindings) error {
+	pending := make(map[string]string) // Node to the signal that reported it
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			if string(condition.Type) == nodeRebootCondition && condition.Status == corev1.ConditionTrue {
+				pending[node.Name] = condition.Message
+			}
+		}
+	}
+
+	events, err := client.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "involvedObject.kind=Node"})
+	if err != nil {
+		return fmt.Errorf("failed to list node events: %w", err)
+	}
+	known := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		known[node.Name] = true
+	}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind != "Node" || !known[event.InvolvedObject.Name] || !isRebootRequired(event) {
+			continue
+		}
+		if result.Timestamp.Sub(eventTime(event)) > c.rebootWindow {
+			continue
+		}
+		if _, ok := pending[event.InvolvedObject.Name]; !ok {
+			pending[event.InvolvedObject.Name] = event.Message
+		}
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		issue := fmt.Sprintf("%s: reboot pending", name)
+		if pending[name] != "" {
+			issue = fmt.Sprintf("%s: reboot pending (%s)", name, pending[name])
+		}
+		f.report(result, findings.NodeRebootRequired, name, issue, true)
+	}
+	if len(names) > 0 {
+		f.recommendations = append(f.recommendations, fmt.Sprintf("Drain and reboot %s, one at a time, to apply pending updates", nodeList(names)))
+	}
+	result.Metrics = append(result.Metrics, gaugeMetric("nodes_reboot_required", float64(len(names)), result.Timestamp, nil))
+	return nil
+}
+
+// isRebootRequired reports whether an event says a node needs a reboot,
+// such as node problem detector's RebootRequired
+func isRebootRequired(event corev1.Event) bool {
+	if strings.EqualFold(event.Reason, nodeRebootCondition) {
+		return true
+	}
+	message := strings.ToLower(event.Message)
+	return strings.Contains(message, "reboot-required") || strings.Contains(message, "reboot required")
+}
+
+// sortedGroups returns the keys of grouped node names in order
+func sortedGroups(groups map[string][]string) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// nodeList names a few nodes, counting the rest
+func nodeList(names []string) string {
+	sort.Strings(names)
+	const shown = 3
+	if len(names) == 1 {
+		return "node " + names[0]
+	}
+	if len(names) <= shown {
+		return "nodes " + strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("nodes %s and %d more", strings.Join(names[:shown], ", "), len(names)-shown)
+}
+
+// Configure sets up the health check with configuration
+func (c *NodeVersionCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["max_kubelet_skew"].(int); ok {
+		if v < 0 {
+			return fmt.Errorf("max_kubelet_skew must not be negative")
+		}
+		c.maxKubeletSkew = uint(v)
+	}
+	if v, ok := config["reboot_window"].(string); ok {
+		window, err := time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			return fmt.Errorf("invalid reboot_window %q", v)
+		}
+		c.rebootWindow = window
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *NodeVersionCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *NodeVersionCheck) Criticality() core.Criticality {
+	return core.CriticalityMedium
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func versionedNode(name, kubelet, osImage string, conditions ...corev1.NodeCondition) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: kubelet, OSImage: osImage},
+			Conditions: conditions,
+		},
+	}
+}
+
+func TestNodeVersionCheck(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		versionedNode("worker-1", "v1.30.2", "Ubuntu 22.04.4 LTS"),
+		versionedNode("worker-2", "v1.27.9-eks-1a2b3c", "Ubuntu 22.04.4 LTS"),
+		versionedNode("worker-3", "v1.26.1", "Ubuntu 22.04.2 LTS"),
+		versionedNode("worker-4", "v1.31.0", "Ubuntu 22.04.4 LTS",
+			corev1.NodeCondition{Type: nodeRebootCondition, Status: corev1.ConditionTrue, Message: "kernel update"}),
+		versionedNode("worker-5", "v1.30.2", "Bottlerocket OS 1.19.2 (aws-k8s-1.30)"),
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "reboot", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "worker-1"},
+			Reason:         "RebootRequired",
+			Message:        "/var/run/reboot-required exists",
+			LastTimestamp:  metav1.NewTime(now.Add(-10 * time.Minute)),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "old-reboot", Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "worker-5"},
+			Reason:         "RebootRequired",
+			LastTimestamp:  metav1.NewTime(now.Add(-3 * time.Hour)),
+		},
+	)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.4"}
+
+	result, err := NewNodeVersionCheck().Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if result.Status != core.HealthStatusDegraded {
+		t.Errorf("expected skew and reboots to degrade the check, got %s: %s", result.Status, result.Message)
+	}
+
+	var got []string
+	for _, finding := range result.Details[core.DetailFindings].([]findings.Finding) {
+		got = append(got, finding.Key())
+	}
+	sort.Strings(got)
+	want := []string{
+		"KP-NODE-007 node/worker-3",
+		"KP-NODE-007 node/worker-4",
+		"KP-NODE-008 node/worker-3",
+		"KP-NODE-009 node/worker-1",
+		"KP-NODE-009 node/worker-4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+
+	recommendations := strings.Join(result.Details[core.DetailUpgradeRecommendations].([]string), "\n")
+	for _, expected := range []string{
+		"Upgrade the kubelet on node worker-3 from v1.26",
+		"Upgrade the control plane to at least v1.31",
+		"Upgrade the kubelet on node worker-2 from v1.27 before upgrading the control plane past v1.30",
+		"Replace or reimage node worker-3 from Ubuntu 22.04.2 LTS to Ubuntu 22.04.4 LTS",
+		"Drain and reboot nodes worker-1, worker-4",
+	} {
+		if !strings.Contains(recommendations, expected) {
+			t.Errorf("expected a recommendation containing %q, got:\n%s", expected, recommendations)
+		}
+	}
+}
+
+func TestOSRelease(t *testing.T) {
+	tests := []struct {
+		image, distribution, release string
+	}{
+		{"Ubuntu 22.04.4 LTS", "Ubuntu", "22.4.4"},
+		{"Bottlerocket OS 1.19.2 (aws-k8s-1.29)", "Bottlerocket OS", "1.19.2"},
+		{"Container-Optimized OS from Google", "Container-Optimized OS from Google", ""},
+	}
+	for _, tt := range tests {
+		distribution, release := osRelease(tt.image)
+		got := ""
+		if release != nil {
+			got = release.String()
+		}
+		if distribution != tt.distribution || got != tt.release {
+			t.Errorf("osRelease(%q) = %q, %q; want %q, %q", tt.image, distribution, got, tt.distribution, tt.release)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -19,15 +20,24 @@ type PodHealthCheck struct {
 	interval              time.Duration
 	excludeNamespaces     []string
 	includeOnlyNamespaces []string
+	logAnalysis           bool
+	logAnalyzer           *LogPatternAnalyzer
+	schedulingAnalysis    bool
+	schedulingAnalyzer    *SchedulingAnalyzer
+	ownership             core.OwnershipRules
 }
 
 // NewPodHealthCheck creates a new pod health check
 func NewPodHealthCheck() *PodHealthCheck {
 	return &PodHealthCheck{
-		namespace:         "",
-		restartThreshold:  5,
-		interval:          30 * time.Second,
-		excludeNamespaces: []string{"kube-system", "kube-public"},
+		namespace:          "",
+		restartThreshold:   5,
+		interval:           30 * time.Second,
+		excludeNamespaces:  []string{"kube-system", "kube-public"},
+		logAnalysis:        true,
+		logAnalyzer:        NewLogPatternAnalyzer(),
+		schedulingAnalysis: true,
+		schedulingAnalyzer: NewSchedulingAnalyzer(),
 	}
 }
 
@@ -57,20 +67,54 @@ func (p *PodHealthCheck) Check(ctx context.Context, client kubernetes.Interface)
 		return result, fmt.Errorf("failed to get namespaces: %w", err)
 	}
 
-	var totalPods, runningPods, failedPods, pendingPods int
-	var highRestartPods []string
+	now := time.Now()
+	maintenanceNodes := nodesInMaintenance(ctx, client, now)
+
+	var totalPods, runningPods, failedPods, pendingPods, disruptedPods int
+	var highRestartPods, ignoredNamespaces, ignoredPods, expectedDisruptions []string
+	var failingPods []corev1.Pod
 	podsByNamespace := make(map[string]int)
+	nsAnnotations := make(map[string]map[string]string)
+	nsLabels := make(map[string]map[string]string)
+	var apps []core.Ownership
 
 	// Check pods in each namespace
 	for _, ns := range namespaces {
-		pods, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if isIgnored(ns.Annotations, p.Name()) {
+			ignoredNamespaces = append(ignoredNamespaces, ns.Name)
+			continue
+		}
+		nsAnnotations[ns.Name] = ns.Annotations
+		nsLabels[ns.Name] = ns.Labels
+
+		pods, err := client.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			return result, fmt.Errorf("failed to list pods in namespace %s: %w", ns, err)
+			return result, fmt.Errorf("failed to list pods in namespace %s: %w", ns.Name, err)
 		}
 
 		for _, pod := range pods.Items {
+			if isIgnored(pod.Annotations, p.Name()) {
+				ignoredPods = append(ignoredPods, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+				continue
+			}
 			totalPods++
-			podsByNamespace[ns]++
+			podsByNamespace[ns.Name]++
+			apps = append(apps, p.ownership.Resolve(pod.Labels, pod.Annotations, ns.Labels, ns.Annotations))
+
+			// Pods taken down by planned maintenance are expected to be
+			// unavailable and don't count against health
+			if pod.Status.Phase != corev1.PodSucceeded && !p.isPodReady(&pod) {
+				if disruption := podDisruption(&pod, maintenanceNodes, ns.Annotations, now); disruption != "" {
+					disruptedPods++
+					expectedDisruptions = append(expectedDisruptions,
+						fmt.Sprintf("%s/%s: %s (%s)", pod.Namespace, pod.Name, pod.Status.Phase, disruption))
+					continue
+				}
+			}
+
+			if pod.Status.Phase != corev1.PodSucceeded && !p.isPodReady(&pod) {
+				addFindings(&result, podFindings(&pod)...)
+			}
 
 			// Check pod status
 			switch pod.Status.Phase {
@@ -82,10 +126,12 @@ func (p *PodHealthCheck) Check(ctx context.Context, client kubernetes.Interface)
 				}
 			case corev1.PodFailed:
 				failedPods++
+				failingPods = append(failingPods, pod)
 			case corev1.PodPending:
 				// Check if pending pod is actually problematic
 				if p.isPodProblematic(&pod) {
 					failedPods++ // Count problematic pending pods as failed
+					failingPods = append(failingPods, pod)
 				} else {
 					pendingPods++
 				}
@@ -93,8 +139,16 @@ func (p *PodHealthCheck) Check(ctx context.Context, client kubernetes.Interface)
 
 			// Check restart count
 			restarts := p.getRestartCount(&pod)
-			if restarts > p.restartThreshold {
+			if restarts > annotatedRestartThreshold(p.restartThreshold, pod.Annotations, ns.Annotations) {
 				highRestartPods = append(highRestartPods, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+				addFindings(&result, findings.Finding{
+					ID:      findings.PodHighRestarts,
+					Subject: core.ResourceRef{Kind: "pod", Namespace: pod.Namespace, Name: pod.Name}.String(),
+					Message: fmt.Sprintf("%d restarts", restarts),
+				})
+				if pod.Status.Phase == corev1.PodRunning {
+					failingPods = append(failingPods, pod)
+				}
 			}
 		}
 	}
@@ -119,19 +173,68 @@ func (p *PodHealthCheck) Check(ctx context.Context, client kubernetes.Interface)
 			issues = append(issues, fmt.Sprintf("%d pending", pendingPods))
 		}
 		result.Message = fmt.Sprintf("Pod issues detected: %s", strings.Join(issues, ", "))
+	} else if disruptedPods > 0 {
+		result.Message = fmt.Sprintf("All pods are healthy (%d running, %d total); %d disrupted by maintenance", runningPods, totalPods, disruptedPods)
 	} else {
 		result.Message = fmt.Sprintf("All pods are healthy (%d running, %d total)", runningPods, totalPods)
 	}
 
 	// Add details
+	result.AffectedResources = len(failingPods)
+	for _, pod := range failingPods {
+		if runbook := annotatedRunbook(pod.Annotations, nsAnnotations[pod.Namespace]); runbook != "" {
+			result.Details["runbook"] = runbook
+			break
+		}
+	}
 	result.Details["total_pods"] = totalPods
 	result.Details["running_pods"] = runningPods
 	result.Details["failed_pods"] = failedPods
 	result.Details["pending_pods"] = pendingPods
 	result.Details["pods_by_namespace"] = podsByNamespace
+	if disruptedPods > 0 {
+		result.Details["disrupted_pods"] = disruptedPods
+	}
+	setExpectedDisruptions(&result, expectedDisruptions)
+	implicated := make([]core.ResourceRef, 0, len(failingPods))
+	for _, pod := range failingPods {
+		implicated = append(implicated, core.ResourceRef{Kind: "pod", Namespace: pod.Namespace, Name: pod.Name})
+	}
+	setImplicatedResources(&result, implicated)
+	owners := make([]core.Ownership, 0, len(failingPods))
+	for _, pod := range failingPods {
+		owners = append(owners, p.ownership.Resolve(pod.Labels, pod.Annotations, nsLabels[pod.Namespace], nsAnnotations[pod.Namespace]))
+	}
+	core.SetOwners(&result, core.DetailOwners, owners)
+	core.SetOwners(&result, core.DetailApps, apps)
+	if result.Status != core.HealthStatusHealthy {
+		for _, pod := range failingPods {
+			core.SetObservedAt(&result, podFailingSince(&pod))
+		}
+	}
 	if len(highRestartPods) > 0 {
 		result.Details["high_restart_pods"] = highRestartPods
 	}
+	if len(ignoredNamespaces) > 0 {
+		result.Details["ignored_namespaces"] = ignoredNamespaces
+	}
+	if len(ignoredPods) > 0 {
+		result.Details["ignored_pods"] = ignoredPods
+	}
+
+	// Attach recurring log patterns from failing pods for diagnosis
+	if p.logAnalysis && result.Status != core.HealthStatusHealthy && len(failingPods) > 0 {
+		if patterns := p.logAnalyzer.Analyze(ctx, client, failingPods); len(patterns) > 0 {
+			result.Details["log_patterns"] = patterns
+		}
+	}
+
+	// Explain why unschedulable pods can't be placed
+	if p.schedulingAnalysis && len(failingPods) > 0 {
+		if failures := p.schedulingAnalyzer.Analyze(ctx, client, failingPods); len(failures) > 0 {
+			setSchedulingFailures(&result, failures)
+		}
+	}
 
 	// Add metrics
 	result.Metrics = append(result.Metrics,
@@ -183,6 +286,21 @@ func (p *PodHealthCheck) Configure(config map[string]interface{}) error {
 	if v, ok := config["include_only_namespaces"].([]string); ok {
 		p.includeOnlyNamespaces = v
 	}
+	if v, ok := config["log_analysis"].(bool); ok {
+		p.logAnalysis = v
+	}
+	if v, ok := config["scheduling_analysis"].(bool); ok {
+		p.schedulingAnalysis = v
+	}
+	if v, ok := config["log_tail_lines"].(int); ok && v > 0 {
+		p.logAnalyzer.tailLines = int64(v)
+	}
+	if v, ok := config["log_max_pods"].(int); ok && v > 0 {
+		p.logAnalyzer.maxPods = v
+	}
+	if v, ok := config["ownership"].(core.OwnershipRules); ok {
+		p.ownership = v
+	}
 	return nil
 }
 
@@ -196,16 +314,17 @@ func (p *PodHealthCheck) Criticality() core.Criticality {
 	return core.CriticalityHigh
 }
 
-// getNamespacesToCheck returns the list of namespaces to check
-func (p *PodHealthCheck) getNamespacesToCheck(ctx context.Context, client kubernetes.Interface) ([]string, error) {
+// getNamespacesToCheck returns the namespaces to check. Annotations are only
+// available for namespaces discovered from the cluster.
+func (p *PodHealthCheck) getNamespacesToCheck(ctx context.Context, client kubernetes.Interface) ([]corev1.Namespace, error) {
 	// If specific namespaces are configured, use those
 	if len(p.includeOnlyNamespaces) > 0 {
-		return p.includeOnlyNamespaces, nil
+		return namespacesNamed(p.includeOnlyNamespaces...), nil
 	}
 
 	// If a single namespace is specified, use it
 	if p.namespace != "" {
-		return []string{p.namespace}, nil
+		return namespacesNamed(p.namespace), nil
 	}
 
 	// Otherwise, get all namespaces and apply exclusions
@@ -214,7 +333,7 @@ func (p *PodHealthCheck) getNamespacesToCheck(ctx context.Context, client kubern
 		return nil, err
 	}
 
-	var result []string
+	var result []corev1.Namespace
 	excludeMap := make(map[string]bool)
 	for _, ns := range p.excludeNamespaces {
 		excludeMap[ns] = true
@@ -222,13 +341,22 @@ func (p *PodHealthCheck) getNamespacesToCheck(ctx context.Context, client kubern
 
 	for _, ns := range namespaces.Items {
 		if !excludeMap[ns.Name] {
-			result = append(result, ns.Name)
+			result = append(result, ns)
 		}
 	}
 
 	return result, nil
 }
 
+// namespacesNamed returns namespaces known only by name
+func namespacesNamed(names ...string) []corev1.Namespace {
+	namespaces := make([]corev1.Namespace, 0, len(names))
+	for _, name := range names {
+		namespaces = append(namespaces, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	return namespaces
+}
+
 // isPodReady checks if all containers in a pod are ready
 func (p *PodHealthCheck) isPodReady(pod *corev1.Pod) bool {
 	for _, condition := range pod.Status.Conditions {
@@ -239,6 +367,26 @@ func (p *PodHealthCheck) isPodReady(pod *corev1.Pod) bool {
 	return false
 }
 
+// podFailingSince returns when a pod's failure became visible in the
+// cluster: its last container termination, or when it stopped being ready
+func podFailingSince(pod *corev1.Pod) time.Time {
+	var since time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.FinishedAt.After(since) {
+			since = terminated.FinishedAt.Time
+		}
+	}
+	if !since.IsZero() {
+		return since
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
 // getRestartCount returns the total restart count for all containers in a pod
 func (p *PodHealthCheck) getRestartCount(pod *corev1.Pod) int32 {
 	var restarts int32
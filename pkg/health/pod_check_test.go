@@ -1,10 +1,15 @@
 package health
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestNewPodHealthCheck(t *testing.T) {
@@ -132,3 +137,50 @@ func TestPodHealthCheck_Configure_InvalidTypes(t *testing.T) {
 		t.Errorf("namespace should not change with invalid type")
 	}
 }
+
+// TestPodHealthCheck_Replay guards the check's behaviour on a recorded
+// crash-looping workload, including the log patterns read from the API
+func TestPodHealthCheck_Replay(t *testing.T) {
+	rec, err := core.LoadRecording("testdata/pod-health-crashloop.json")
+	if err != nil {
+		t.Fatalf("LoadRecording() error = %v", err)
+	}
+
+	report, err := core.ReplayCheck(context.Background(), NewPodHealthCheck(), *rec)
+	if err != nil {
+		t.Fatalf("ReplayCheck() error = %v", err)
+	}
+	if !report.Matches {
+		t.Errorf("replayed result differs from the recording:\n%s", strings.Join(report.Differences, "\n"))
+	}
+}
+
+func TestPodHealthCheck_Owners(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "payments"}}},
+		pod("checkout", corev1.PodFailed, map[string]string{"app.kubernetes.io/name": "checkout", "acme.com/layer": "backend"}),
+		pod("web", corev1.PodSucceeded, map[string]string{"app": "web"}),
+	)
+	check := NewPodHealthCheck()
+	if err := check.Configure(map[string]interface{}{"ownership": core.OwnershipRules{Tier: []string{"acme.com/layer"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkout := core.Ownership{Team: "payments", App: "checkout", Tier: "backend"}
+	if owners := core.Owners(result); len(owners) != 1 || owners[0] != checkout {
+		t.Errorf("expected the failing pod's owner, got %+v", owners)
+	}
+	if apps := result.Details[core.DetailApps].([]core.Ownership); len(apps) != 2 {
+		t.Errorf("expected both pods' owners, got %+v", apps)
+	}
+}
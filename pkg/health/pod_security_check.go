@@ -0,0 +1,341 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Pod Security Standards levels, least to most restrictive
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// podSecurityEnforceLabel is the Pod Security Admission label a namespace
+// sets its enforced level with
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// maxPodSecurityViolations caps the violations a result lists
+const maxPodSecurityViolations = 50
+
+// baselineCapabilities are the capabilities the baseline level allows a
+// container to add
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true,
+	"KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true,
+	"SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// IsPodSecurityLevel reports whether a name is a Pod Security Standards level
+func IsPodSecurityLevel(level string) bool {
+	switch level {
+	case PodSecurityPrivileged, PodSecurityBaseline, PodSecurityRestricted:
+		return true
+	}
+	return false
+}
+
+// PodSecurityCheck evaluates running pods against the Pod Security
+// Standards level of their namespace. A namespace's level comes from the
+// check's configuration, then its pod-security.kubernetes.io/enforce
+// label, then the default level. Pods admitted before a namespace was
+// labelled, or in namespaces without enforcement, can still violate it.
+type PodSecurityCheck struct {
+	namespace       string
+	interval        time.Duration
+	defaultLevel    string
+	namespaceLevels map[string]string
+}
+
+// NewPodSecurityCheck creates a new Pod Security Standards check. Pods are
+// held to the baseline level by default; kube-system is privileged.
+func NewPodSecurityCheck() *PodSecurityCheck {
+	return &PodSecurityCheck{
+		interval:        30 * time.Second,
+		defaultLevel:    PodSecurityBaseline,
+		namespaceLevels: map[string]string{"kube-system": PodSecurityPrivileged},
+	}
+}
+
+// Name returns the name of the health check
+func (c *PodSecurityCheck) Name() string {
+	return "pod-security"
+}
+
+// Description returns a description of the health check
+func (c *PodSecurityCheck) Description() string {
+	return "Evaluates running pods against Pod Security Standards levels per namespace"
+}
+
+// podSecurityViolation is a pod breaking a control of its namespace's level
+type podSecurityViolation struct {
+	id        string // Finding type ID
+	container string // Empty for pod-level controls
+	message   string
+}
+
+// Check performs the Pod Security Standards check
+func (c *PodSecurityCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	result := core.CheckResult{
+		Name:      c.Name(),
+		Timestamp: time.Now(),
+		Status:    core.HealthStatusHealthy,
+		Details:   make(map[string]interface{}),
+		Metrics:   []core.Metric{},
+	}
+
+	namespaces, err := c.namespacesToCheck(ctx, client)
+	if err != nil {
+		return result, fmt.Errorf("failed to get namespaces: %w", err)
+	}
+
+	var totalPods int
+	var violations, ignored []string
+	var implicated []core.ResourceRef
+	levels := make(map[string]string)
+	violatingPods := make(map[string]int)
+	for _, ns := range namespaces {
+		if isIgnored(ns.Annotations, c.Name()) {
+			ignored = append(ignored, "namespace "+ns.Name)
+			continue
+		}
+		level := c.levelFor(&ns)
+		levels[ns.Name] = level
+		if level == PodSecurityPrivileged {
+			continue
+		}
+
+		pods, err := client.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return result, fmt.Errorf("failed to list pods in namespace %s: %w", ns.Name, err)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			ref := core.ResourceRef{Kind: "pod", Namespace: pod.Namespace, Name: pod.Name}
+			if isIgnored(pod.Annotations, c.Name()) {
+				ignored = append(ignored, ref.String())
+				continue
+			}
+			totalPods++
+
+			podViolations := evaluatePodSecurity(&pod, level)
+			if len(podViolations) == 0 {
+				continue
+			}
+			violatingPods[ns.Name]++
+			implicated = append(implicated, ref)
+			for _, violation := range podViolations {
+				message := violation.message
+				if violation.container != "" {
+					message = fmt.Sprintf("container %s: %s", violation.container, message)
+				}
+				if len(violations) < maxPodSecurityViolations {
+					violations = append(violations, fmt.Sprintf("%s (%s): %s", ref, level, message))
+				}
+				addFindings(&result, findings.Finding{ID: violation.id, Subject: ref.String(), Message: message})
+			}
+		}
+		result.Metrics = append(result.Metrics, gaugeMetric("pod_security_violating_pods", float64(violatingPods[ns.Name]), result.Timestamp,
+			map[string]string{"namespace": ns.Name, "level": level}))
+	}
+
+	violating := len(implicated)
+	if violating > 0 {
+		result.Status = core.HealthStatusDegraded
+		result.Message = fmt.Sprintf("%d of %d pods violate their namespace's Pod Security level", violating, totalPods)
+		result.Details["violations"] = violations
+		result.Details["violating_pods"] = violatingPods
+	} else {
+		result.Message = fmt.Sprintf("All %d pods meet their namespace's Pod Security level", totalPods)
+	}
+	result.AffectedResources = violating
+	setImplicatedResources(&result, implicated)
+	result.Details["total_pods"] = totalPods
+	result.Details["namespace_levels"] = levels
+	if len(ignored) > 0 {
+		result.Details["ignored_resources"] = ignored
+	}
+
+	result.Confidence = 1.0
+	return result, nil
+}
+
+// namespacesToCheck returns the configured namespace or all namespaces,
+// with their labels so enforced levels are known
+func (c *PodSecurityCheck) namespacesToCheck(ctx context.Context, client kubernetes.Interface) ([]corev1.Namespace, error) {
+	if c.namespace != "" {
+		ns, err := client.CoreV1().Namespaces().Get(ctx, c.namespace, metav1.GetOptions{})
+		if err != nil {
+			// Without access to the namespace its level can't be read
+			return namespacesNamed(c.namespace), nil
+		}
+		return []corev1.Namespace{*ns}, nil
+	}
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return namespaces.Items, nil
+}
+
+// levelFor returns the Pod Security level a namespace's pods are held to
+func (c *PodSecurityCheck) levelFor(ns *corev1.Namespace) string {
+	if level, ok := c.namespaceLevels[ns.Name]; ok {
+		return level
+	}
+	if level := ns.Labels[podSecurityEnforceLabel]; IsPodSecurityLevel(level) {
+		return level
+	}
+	return c.defaultLevel
+}
+
+// evaluatePodSecurity returns the controls of a level a pod violates. The
+// restricted level includes every baseline control.
+func evaluatePodSecurity(pod *corev1.Pod, level string) []podSecurityViolation {
+	if level == PodSecurityPrivileged {
+		return nil
+	}
+	restricted := level == PodSecurityRestricted
+	spec := &pod.Spec
+	var violations []podSecurityViolation
+
+	var hostNamespaces []string
+	if spec.HostNetwork {
+		hostNamespaces = append(hostNamespaces, "hostNetwork")
+	}
+	if spec.HostPID {
+		hostNamespaces = append(hostNamespaces, "hostPID")
+	}
+	if spec.HostIPC {
+		hostNamespaces = append(hostNamespaces, "hostIPC")
+	}
+	if len(hostNamespaces) > 0 {
+		violations = append(violations, podSecurityViolation{id: findings.SecurityHostNamespaces, message: "uses " + strings.Join(hostNamespaces, ", ")})
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			violations = append(violations, podSecurityViolation{id: findings.SecurityHostAccess, message: fmt.Sprintf("mounts hostPath volume %s (%s)", volume.Name, volume.HostPath.Path)})
+		}
+	}
+
+	podContext := spec.SecurityContext
+	if podContext == nil {
+		podContext = &corev1.PodSecurityContext{}
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		sc := container.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		violation := func(id, format string, args ...interface{}) {
+			violations = append(violations, podSecurityViolation{id: id, container: container.Name, message: fmt.Sprintf(format, args...)})
+		}
+
+		if sc.Privileged != nil && *sc.Privileged {
+			violation(findings.SecurityPrivileged, "runs privileged")
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				violation(findings.SecurityHostAccess, "binds host port %d", port.HostPort)
+			}
+		}
+
+		var added []string
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if (restricted && capability != "NET_BIND_SERVICE") || !baselineCapabilities[capability] {
+					added = append(added, string(capability))
+				}
+			}
+		}
+		if len(added) > 0 {
+			violation(findings.SecurityCapabilities, "adds capabilities %s", strings.Join(added, ", "))
+		}
+		if !restricted {
+			continue
+		}
+
+		if !dropsAllCapabilities(sc.Capabilities) {
+			violation(findings.SecurityCapabilities, "does not drop ALL capabilities")
+		}
+		if !effectiveBool(sc.RunAsNonRoot, podContext.RunAsNonRoot) {
+			violation(findings.SecurityRunAsRoot, "runAsNonRoot is not true")
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violation(findings.SecurityEscalation, "allowPrivilegeEscalation is not false")
+		}
+		profile := sc.SeccompProfile
+		if profile == nil {
+			profile = podContext.SeccompProfile
+		}
+		if profile == nil || (profile.Type != corev1.SeccompProfileTypeRuntimeDefault && profile.Type != corev1.SeccompProfileTypeLocalhost) {
+			violation(findings.SecuritySeccomp, "has no RuntimeDefault or Localhost seccomp profile")
+		}
+	}
+	return violations
+}
+
+// dropsAllCapabilities reports whether capabilities drop ALL
+func dropsAllCapabilities(capabilities *corev1.Capabilities) bool {
+	if capabilities == nil {
+		return false
+	}
+	for _, capability := range capabilities.Drop {
+		if capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveBool returns a container setting, falling back to the pod's
+func effectiveBool(container, pod *bool) bool {
+	if container != nil {
+		return *container
+	}
+	return pod != nil && *pod
+}
+
+// Configure sets up the health check with configuration
+func (c *PodSecurityCheck) Configure(config map[string]interface{}) error {
+	if v, ok := config["namespace"].(string); ok {
+		c.namespace = v
+	}
+	if v, ok := config["default_level"].(string); ok {
+		if !IsPodSecurityLevel(v) {
+			return fmt.Errorf("invalid default_level %q: must be privileged, baseline or restricted", v)
+		}
+		c.defaultLevel = v
+	}
+	if v, ok := config["namespace_levels"].(map[string]string); ok {
+		for namespace, level := range v {
+			if !IsPodSecurityLevel(level) {
+				return fmt.Errorf("invalid level %q for namespace %s: must be privileged, baseline or restricted", level, namespace)
+			}
+			c.namespaceLevels[namespace] = level
+		}
+	}
+	return nil
+}
+
+// Interval returns how often this check should run
+func (c *PodSecurityCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the importance level of this check
+func (c *PodSecurityCheck) Criticality() core.Criticality {
+	return core.CriticalityMedium
+}
@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+// compliantPod meets the restricted level
+func compliantPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   boolPtr(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name: "app",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: boolPtr(false),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestEvaluatePodSecurity(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*corev1.Pod)
+		level  string
+		want   []string
+	}{
+		{"compliant restricted", func(*corev1.Pod) {}, PodSecurityRestricted, nil},
+		{"privileged level allows anything", func(p *corev1.Pod) { p.Spec.HostNetwork = true }, PodSecurityPrivileged, nil},
+		{"privileged container", func(p *corev1.Pod) {
+			p.Spec.Containers[0].SecurityContext.Privileged = boolPtr(true)
+		}, PodSecurityBaseline, []string{findings.SecurityPrivileged}},
+		{"host namespaces", func(p *corev1.Pod) {
+			p.Spec.HostNetwork, p.Spec.HostPID = true, true
+		}, PodSecurityBaseline, []string{findings.SecurityHostNamespaces}},
+		{"hostPath and host port", func(p *corev1.Pod) {
+			p.Spec.Volumes = []corev1.Volume{{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}}
+			p.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80}}
+		}, PodSecurityBaseline, []string{findings.SecurityHostAccess, findings.SecurityHostAccess}},
+		{"baseline allows default capabilities", func(p *corev1.Pod) {
+			p.Spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"CHOWN"}
+		}, PodSecurityBaseline, nil},
+		{"added capabilities", func(p *corev1.Pod) {
+			p.Spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_ADMIN"}
+		}, PodSecurityBaseline, []string{findings.SecurityCapabilities}},
+		{"restricted allows only NET_BIND_SERVICE", func(p *corev1.Pod) {
+			p.Spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE", "CHOWN"}
+		}, PodSecurityRestricted, []string{findings.SecurityCapabilities}},
+		{"baseline ignores restricted controls", func(p *corev1.Pod) {
+			p.Spec.SecurityContext = nil
+			p.Spec.Containers[0].SecurityContext = nil
+		}, PodSecurityBaseline, nil},
+		{"missing restricted settings", func(p *corev1.Pod) {
+			p.Spec.SecurityContext = nil
+			p.Spec.Containers[0].SecurityContext = nil
+		}, PodSecurityRestricted, []string{findings.SecurityCapabilities, findings.SecurityRunAsRoot, findings.SecurityEscalation, findings.SecuritySeccomp}},
+		{"container overrides runAsNonRoot", func(p *corev1.Pod) {
+			p.Spec.Containers[0].SecurityContext.RunAsNonRoot = boolPtr(false)
+		}, PodSecurityRestricted, []string{findings.SecurityRunAsRoot}},
+		{"init containers count", func(p *corev1.Pod) {
+			p.Spec.InitContainers = []corev1.Container{{Name: "setup", SecurityContext: &corev1.SecurityContext{Privileged: boolPtr(true)}}}
+		}, PodSecurityBaseline, []string{findings.SecurityPrivileged}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := compliantPod("shop", "api")
+			tt.modify(pod)
+			var got []string
+			for _, violation := range evaluatePodSecurity(pod, tt.level) {
+				got = append(got, violation.id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodSecurityCheck(t *testing.T) {
+	privileged := compliantPod("shop", "debug")
+	privileged.Spec.Containers[0].SecurityContext.Privileged = boolPtr(true)
+	root := compliantPod("payments", "api")
+	root.Spec.SecurityContext.RunAsNonRoot = nil
+	proxy := compliantPod("kube-system", "kube-proxy")
+	proxy.Spec.HostNetwork = true
+	done := compliantPod("shop", "migrate")
+	done.Spec.HostPID = true
+	done.Status.Phase = corev1.PodSucceeded
+
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{podSecurityEnforceLabel: PodSecurityRestricted}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Labels: map[string]string{podSecurityEnforceLabel: PodSecurityRestricted}}},
+		compliantPod("shop", "web"), privileged, root, proxy, done,
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "legacy"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	)
+
+	check := NewPodSecurityCheck()
+	if err := check.Configure(map[string]interface{}{"namespace_levels": map[string]string{"legacy": PodSecurityPrivileged}}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if result.Status != core.HealthStatusDegraded || result.AffectedResources != 2 {
+		t.Errorf("expected two violating pods to degrade the check, got %s with %d: %s", result.Status, result.AffectedResources, result.Message)
+	}
+	levels := result.Details["namespace_levels"].(map[string]string)
+	want := map[string]string{"shop": PodSecurityBaseline, "payments": PodSecurityRestricted, "kube-system": PodSecurityPrivileged, "legacy": PodSecurityPrivileged}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("got namespace levels %v, want %v", levels, want)
+	}
+
+	var got []string
+	for _, finding := range result.Details[core.DetailFindings].([]findings.Finding) {
+		got = append(got, finding.Key())
+	}
+	sort.Strings(got)
+	wantFindings := []string{"KP-SEC-001 pod/shop/debug", "KP-SEC-005 pod/payments/api"}
+	if !reflect.DeepEqual(got, wantFindings) {
+		t.Errorf("got findings %v, want %v", got, wantFindings)
+	}
+}
+
+func TestPodSecurityCheck_ConfigureRejectsUnknownLevels(t *testing.T) {
+	check := NewPodSecurityCheck()
+	if err := check.Configure(map[string]interface{}{"default_level": "strict"}); err == nil {
+		t.Error("expected an unknown default level to be rejected")
+	}
+	if err := check.Configure(map[string]interface{}{"namespace_levels": map[string]string{"shop": "open"}}); err == nil {
+		t.Error("expected an unknown namespace level to be rejected")
+	}
+}
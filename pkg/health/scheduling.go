@@ -0,0 +1,442 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// nodePoolLabels identify the pool a node belongs to, most specific first
+var nodePoolLabels = []string{
+	"karpenter.sh/nodepool",
+	"eks.amazonaws.com/nodegroup",
+	"cloud.google.com/gke-nodepool",
+	"kubernetes.azure.com/agentpool",
+	"agentpool",
+	"node.kubernetes.io/instance-type",
+}
+
+// topologySpreadRejection matches the scheduler's count of nodes failing a
+// pod's topology spread constraints
+var topologySpreadRejection = regexp.MustCompile(`(\d+) node\(s\) didn't match pod topology spread constraints`)
+
+// SchedulingAnalyzer explains why pending pods can't be scheduled by
+// checking each node against the pod's selectors, tolerations and requests
+type SchedulingAnalyzer struct {
+	maxPods int
+}
+
+// NewSchedulingAnalyzer creates a scheduling analyzer that explains at most
+// 20 pods per check
+func NewSchedulingAnalyzer() *SchedulingAnalyzer {
+	return &SchedulingAnalyzer{maxPods: 20}
+}
+
+// Analyze explains the given pods the scheduler reported as unschedulable;
+// other pods are ignored
+func (a *SchedulingAnalyzer) Analyze(ctx context.Context, client kubernetes.Interface, pods []corev1.Pod) []core.SchedulingFailure {
+	var pending []corev1.Pod
+	for _, pod := range pods {
+		if _, ok := unschedulableMessage(&pod); ok && len(pending) < a.maxPods {
+			pending = append(pending, pod)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.V(2).Infof("Skipping scheduling analysis: failed to list nodes: %v", err)
+		return nil
+	}
+	// Without the pods on each node, free capacity is taken to be the whole
+	// allocatable, which only hides insufficient resources
+	var used map[string]corev1.ResourceList
+	if scheduled, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{}); err != nil {
+		klog.V(2).Infof("Scheduling analysis without node usage: failed to list pods: %v", err)
+	} else {
+		used = nodeRequests(scheduled.Items)
+	}
+
+	failures := make([]core.SchedulingFailure, 0, len(pending))
+	for i := range pending {
+		failures = append(failures, explainScheduling(&pending[i], nodes.Items, used))
+	}
+	return failures
+}
+
+// setSchedulingFailures records why pending pods can't be scheduled, both
+// structured and as one sentence per pod that answers "why is it pending"
+func setSchedulingFailures(result *core.CheckResult, failures []core.SchedulingFailure) {
+	reasons := make([]string, len(failures))
+	for i, failure := range failures {
+		reasons[i] = failure.String()
+	}
+	result.Details[core.DetailSchedulingFailures] = failures
+	result.Details[core.DetailPendingReasons] = reasons
+}
+
+// unschedulableMessage returns the scheduler's message for a pod it
+// couldn't place
+func unschedulableMessage(pod *corev1.Pod) (string, bool) {
+	if pod.Status.Phase != corev1.PodPending {
+		return "", false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Message, true
+		}
+	}
+	return "", false
+}
+
+// poolFit tracks the nodes of a pool too full for the pod and the most free
+// of each resource they lack
+type poolFit struct {
+	nodes int
+	short map[corev1.ResourceName]resource.Quantity // Most free on any node
+}
+
+// explainScheduling rules each node out by the first reason it can't take
+// the pod, in the order the scheduler filters, and summarizes the reasons
+func explainScheduling(pod *corev1.Pod, nodes []corev1.Node, used map[string]corev1.ResourceList) core.SchedulingFailure {
+	message, _ := unschedulableMessage(pod)
+	failure := core.SchedulingFailure{
+		Namespace:        pod.Namespace,
+		Pod:              pod.Name,
+		PendingSince:     pod.CreationTimestamp.Time,
+		SchedulerMessage: message,
+	}
+	total := len(nodes)
+	if total == 0 {
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{Kind: core.SchedulingNoNodes})
+		return failure
+	}
+
+	requests := podRequests(pod)
+	var cordoned, unmatched, tainted int
+	var missing, taints []string
+	seenMissing, seenTaints := make(map[string]bool), make(map[string]bool)
+	pools := make(map[string]*poolFit)
+
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Spec.Unschedulable && !toleratesUnschedulable(pod) {
+			cordoned++
+			continue
+		}
+		if requirements := unmatchedRequirements(pod, node); len(requirements) > 0 {
+			unmatched++
+			for _, requirement := range requirements {
+				if !seenMissing[requirement] {
+					seenMissing[requirement] = true
+					missing = append(missing, requirement)
+				}
+			}
+			continue
+		}
+		if taint := untoleratedTaint(pod, node); taint != "" {
+			tainted++
+			if !seenTaints[taint] {
+				seenTaints[taint] = true
+				taints = append(taints, taint)
+			}
+			continue
+		}
+		if short := insufficientResources(requests, node, used[node.Name]); len(short) > 0 {
+			pool := nodePool(node)
+			fit, ok := pools[pool]
+			if !ok {
+				fit = &poolFit{short: make(map[corev1.ResourceName]resource.Quantity)}
+				pools[pool] = fit
+			}
+			fit.nodes++
+			for name, free := range short {
+				if best, ok := fit.short[name]; !ok || free.Cmp(best) > 0 {
+					fit.short[name] = free
+				}
+			}
+		}
+	}
+
+	if cordoned > 0 {
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{Kind: core.SchedulingUnschedulable, Nodes: cordoned, Total: total})
+	}
+	if unmatched > 0 {
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{
+			Kind: core.SchedulingNodeSelector, Nodes: unmatched, Total: total, Detail: strings.Join(missing, " + "),
+		})
+	}
+	if tainted > 0 {
+		sort.Strings(taints)
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{
+			Kind: core.SchedulingTaint, Nodes: tainted, Total: total, Detail: strings.Join(taints, ", "),
+		})
+	}
+	poolNames := make([]string, 0, len(pools))
+	for pool := range pools {
+		poolNames = append(poolNames, pool)
+	}
+	sort.Strings(poolNames)
+	for _, pool := range poolNames {
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{
+			Kind: core.SchedulingInsufficient, Nodes: pools[pool].nodes, Total: total, Pool: pool, Detail: describeShortfall(requests, pools[pool].short),
+		})
+	}
+	if match := topologySpreadRejection.FindStringSubmatch(message); match != nil {
+		nodes, _ := strconv.Atoi(match[1])
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{
+			Kind: core.SchedulingTopologySpread, Nodes: nodes, Total: total, Detail: topologySpreadDetail(pod),
+		})
+	}
+	if len(failure.Reasons) == 0 && message != "" {
+		failure.Reasons = append(failure.Reasons, core.SchedulingReason{Kind: core.SchedulingOther, Total: total, Detail: message})
+	}
+	return failure
+}
+
+// podRequests returns the resources a pod needs on a node: its containers'
+// requests, or its largest init container's when that is more
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			sum := requests[name]
+			sum.Add(quantity)
+			requests[name] = sum
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity
+			}
+		}
+	}
+	return requests
+}
+
+// nodeRequests sums the requests of the pods still running on each node
+func nodeRequests(pods []corev1.Pod) map[string]corev1.ResourceList {
+	used := make(map[string]corev1.ResourceList)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		list, ok := used[pod.Spec.NodeName]
+		if !ok {
+			list = corev1.ResourceList{}
+			used[pod.Spec.NodeName] = list
+		}
+		for name, quantity := range podRequests(pod) {
+			sum := list[name]
+			sum.Add(quantity)
+			list[name] = sum
+		}
+	}
+	return used
+}
+
+// insufficientResources returns the free amount of each requested resource
+// the node doesn't have enough of
+func insufficientResources(requests corev1.ResourceList, node *corev1.Node, used corev1.ResourceList) map[corev1.ResourceName]resource.Quantity {
+	short := make(map[corev1.ResourceName]resource.Quantity)
+	for name, request := range requests {
+		if request.IsZero() {
+			continue
+		}
+		free := node.Status.Allocatable[name]
+		if inUse, ok := used[name]; ok {
+			free.Sub(inUse)
+		}
+		if free.Sign() < 0 {
+			free = resource.Quantity{Format: free.Format}
+		}
+		if request.Cmp(free) > 0 {
+			short[name] = free
+		}
+	}
+	return short
+}
+
+// describeShortfall describes what a pool lacks, e.g. "cpu (requests 4, at
+// most 1500m free on a node)"
+func describeShortfall(requests corev1.ResourceList, short map[corev1.ResourceName]resource.Quantity) string {
+	names := make([]string, 0, len(short))
+	for name := range short {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		request, free := requests[corev1.ResourceName(name)], short[corev1.ResourceName(name)]
+		parts[i] = fmt.Sprintf("%s (requests %s, at most %s free on a node)", name, request.String(), free.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nodePool returns the pool a node belongs to, or "default" for nodes
+// without a recognized pool label
+func nodePool(node *corev1.Node) string {
+	for _, label := range nodePoolLabels {
+		if pool := node.Labels[label]; pool != "" {
+			return pool
+		}
+	}
+	return "default"
+}
+
+// unmatchedRequirements returns the pod's node selector entries and
+// required node affinity expressions the node doesn't satisfy. Affinity
+// terms are alternatives, so nothing is returned when any term matches.
+func unmatchedRequirements(pod *corev1.Pod, node *corev1.Node) []string {
+	var missing []string
+	selectorKeys := make([]string, 0, len(pod.Spec.NodeSelector))
+	for key := range pod.Spec.NodeSelector {
+		selectorKeys = append(selectorKeys, key)
+	}
+	sort.Strings(selectorKeys)
+	for _, key := range selectorKeys {
+		if value := pod.Spec.NodeSelector[key]; node.Labels[key] != value {
+			missing = append(missing, key+"="+value)
+		}
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return missing
+	}
+	var termMissing []string
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		var unmet []string
+		for _, expression := range term.MatchExpressions {
+			if !matchesExpression(expression, node.Labels[expression.Key], hasLabel(node, expression.Key)) {
+				unmet = append(unmet, describeExpression(expression))
+			}
+		}
+		for _, field := range term.MatchFields {
+			if field.Key == metav1.ObjectNameField && !matchesExpression(field, node.Name, true) {
+				unmet = append(unmet, describeExpression(field))
+			}
+		}
+		if len(unmet) == 0 {
+			return missing
+		}
+		termMissing = append(termMissing, unmet...)
+	}
+	return append(missing, termMissing...)
+}
+
+func hasLabel(node *corev1.Node, key string) bool {
+	_, ok := node.Labels[key]
+	return ok
+}
+
+// matchesExpression evaluates a node selector requirement against a label
+func matchesExpression(expression corev1.NodeSelectorRequirement, value string, present bool) bool {
+	switch expression.Operator {
+	case corev1.NodeSelectorOpIn:
+		return present && containsString(expression.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !present || !containsString(expression.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return present
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !present
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !present || len(expression.Values) != 1 {
+			return false
+		}
+		actual, err1 := strconv.ParseInt(value, 10, 64)
+		bound, err2 := strconv.ParseInt(expression.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if expression.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+	return false
+}
+
+// describeExpression writes a requirement the way kubectl label selectors
+// do, e.g. zone=us-east-1a or gpu in (a100, h100)
+func describeExpression(expression corev1.NodeSelectorRequirement) string {
+	switch expression.Operator {
+	case corev1.NodeSelectorOpIn:
+		if len(expression.Values) == 1 {
+			return expression.Key + "=" + expression.Values[0]
+		}
+		return fmt.Sprintf("%s in (%s)", expression.Key, strings.Join(expression.Values, ", "))
+	case corev1.NodeSelectorOpNotIn:
+		return fmt.Sprintf("%s notin (%s)", expression.Key, strings.Join(expression.Values, ", "))
+	case corev1.NodeSelectorOpExists:
+		return expression.Key
+	case corev1.NodeSelectorOpDoesNotExist:
+		return "!" + expression.Key
+	case corev1.NodeSelectorOpGt:
+		return fmt.Sprintf("%s>%s", expression.Key, strings.Join(expression.Values, ""))
+	case corev1.NodeSelectorOpLt:
+		return fmt.Sprintf("%s<%s", expression.Key, strings.Join(expression.Values, ""))
+	}
+	return fmt.Sprintf("%s %s (%s)", expression.Key, expression.Operator, strings.Join(expression.Values, ", "))
+}
+
+// untoleratedTaint returns the first NoSchedule or NoExecute taint on the
+// node the pod doesn't tolerate
+func untoleratedTaint(pod *corev1.Pod, node *corev1.Node) string {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !tolerates(pod, taint) {
+			return taint.ToString()
+		}
+	}
+	return ""
+}
+
+// toleratesUnschedulable reports whether the pod may run on cordoned nodes,
+// as DaemonSet pods do
+func toleratesUnschedulable(pod *corev1.Pod) bool {
+	return tolerates(pod, &corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule})
+}
+
+func tolerates(pod *corev1.Pod, taint *corev1.Taint) bool {
+	for i := range pod.Spec.Tolerations {
+		if pod.Spec.Tolerations[i].ToleratesTaint(klog.Background(), taint, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// topologySpreadDetail lists the pod's hard topology spread constraints
+func topologySpreadDetail(pod *corev1.Pod) string {
+	var constraints []string
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		if constraint.WhenUnsatisfiable == corev1.DoNotSchedule {
+			constraints = append(constraints, fmt.Sprintf("max skew %d across %s", constraint.MaxSkew, constraint.TopologyKey))
+		}
+	}
+	if len(constraints) == 0 {
+		return "reported by the scheduler"
+	}
+	return strings.Join(constraints, ", ")
+}
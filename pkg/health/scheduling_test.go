@@ -0,0 +1,170 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newSchedulingNode(name, pool, cpu string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+	nodeLabels := map[string]string{"eks.amazonaws.com/nodegroup": pool}
+	for key, value := range labels {
+		nodeLabels[key] = value
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		}},
+	}
+}
+
+func newUnschedulablePod(name, cpu string, mutate func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ml"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "main",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available",
+			}},
+		},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func TestSchedulingAnalyzer_Analyze(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	objects := []runtime.Object{
+		newSchedulingNode("general-1", "general", "4", map[string]string{"topology.kubernetes.io/zone": "us-east-1a"}),
+		newSchedulingNode("general-2", "general", "4", map[string]string{"topology.kubernetes.io/zone": "us-east-1b"}),
+		newSchedulingNode("gpu-1", "gpu", "8", map[string]string{"gpu": "true", "topology.kubernetes.io/zone": "us-east-1b"}, gpuTaint),
+		// Running pods use 3 of general-1's 4 CPUs
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "default"},
+			Spec: corev1.PodSpec{NodeName: "general-1", Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want []string
+	}{
+		{
+			name: "no node has every selected label",
+			pod: newUnschedulablePod("trainer-0", "1", func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"gpu": "true"}
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1a"},
+						}},
+					}}},
+				}}
+			}),
+			want: []string{"pod ml/trainer-0 pending because no node has gpu=true + topology.kubernetes.io/zone=us-east-1a"},
+		},
+		{
+			name: "taint without toleration",
+			pod: newUnschedulablePod("trainer-1", "1", func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"gpu": "true"}
+			}),
+			want: []string{"2 of 3 node(s) lack gpu=true", "1 of 3 node(s) have untolerated taint nvidia.com/gpu=true:NoSchedule"},
+		},
+		{
+			name: "insufficient cpu per pool",
+			pod: newUnschedulablePod("batch-0", "10", func(pod *corev1.Pod) {
+				pod.Spec.Tolerations = []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+			}),
+			want: []string{
+				"2 node(s) in pool general have insufficient cpu (requests 10, at most 4 free on a node)",
+				"1 node(s) in pool gpu have insufficient cpu (requests 10, at most 8 free on a node)",
+			},
+		},
+		{
+			name: "topology spread reported by the scheduler",
+			pod: newUnschedulablePod("web-0", "100m", func(pod *corev1.Pod) {
+				pod.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+				pod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+					MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule,
+				}}
+				pod.Status.Conditions[0].Message = "0/3 nodes are available: 3 node(s) didn't match pod topology spread constraints."
+			}),
+			want: []string{"topology spread constraints can't be met: max skew 1 across topology.kubernetes.io/zone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(append(objects, tt.pod)...)
+			failures := NewSchedulingAnalyzer().Analyze(context.Background(), client, []corev1.Pod{*tt.pod})
+			if len(failures) != 1 {
+				t.Fatalf("expected one scheduling failure, got %+v", failures)
+			}
+			explanation := failures[0].String()
+			for _, want := range tt.want {
+				if !strings.Contains(explanation, want) {
+					t.Errorf("expected %q in %q", want, explanation)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulingAnalyzer_IgnoresSchedulablePods(t *testing.T) {
+	pod := newUnschedulablePod("api-0", "1", func(pod *corev1.Pod) {
+		pod.Status.Conditions = nil
+	})
+	if failures := NewSchedulingAnalyzer().Analyze(context.Background(), fake.NewSimpleClientset(), []corev1.Pod{*pod}); failures != nil {
+		t.Errorf("expected no analysis for a pod that isn't unschedulable, got %+v", failures)
+	}
+}
+
+func TestPodHealthCheck_ReportsSchedulingFailures(t *testing.T) {
+	pod := newUnschedulablePod("trainer-0", "1", func(pod *corev1.Pod) {
+		pod.Spec.NodeSelector = map[string]string{"gpu": "true"}
+	})
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ml"}},
+		newSchedulingNode("general-1", "general", "4", nil),
+		pod,
+	)
+
+	check := NewPodHealthCheck()
+	_ = check.Configure(map[string]interface{}{"log_analysis": false})
+	result, err := check.Check(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failures := core.SchedulingFailures(result)
+	if len(failures) != 1 || failures[0].Reasons[0].Kind != core.SchedulingNodeSelector {
+		t.Fatalf("expected a node selector failure, got %+v", failures)
+	}
+	reasons, _ := result.Details[core.DetailPendingReasons].([]string)
+	if len(reasons) != 1 || reasons[0] != "pod ml/trainer-0 pending because no node has gpu=true" {
+		t.Errorf("unexpected pending reasons %v", reasons)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -15,6 +16,7 @@ import (
 type ServiceHealthCheck struct {
 	namespace string
 	interval  time.Duration
+	ownership core.OwnershipRules
 }
 
 // NewServiceHealthCheck creates a new service health check
@@ -52,23 +54,44 @@ func (s *ServiceHealthCheck) Check(ctx context.Context, client kubernetes.Interf
 	}
 
 	var totalServices, healthyServices, unhealthyServices int
-	var serviceIssues []string
+	var serviceIssues, ignoredNamespaces, ignoredServices []string
+	var implicated []core.ResourceRef
+	var owners, apps []core.Ownership
+	var runbook string
 
 	for _, ns := range namespaces {
-		services, err := client.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if isIgnored(ns.Annotations, s.Name()) {
+			ignoredNamespaces = append(ignoredNamespaces, ns.Name)
+			continue
+		}
+
+		services, err := client.CoreV1().Services(ns.Name).List(ctx, metav1.ListOptions{})
 		if err != nil {
-			return result, fmt.Errorf("failed to list services in namespace %s: %w", ns, err)
+			return result, fmt.Errorf("failed to list services in namespace %s: %w", ns.Name, err)
 		}
 
 		for _, service := range services.Items {
+			if isIgnored(service.Annotations, s.Name()) {
+				ignoredServices = append(ignoredServices, fmt.Sprintf("%s/%s", service.Namespace, service.Name))
+				continue
+			}
 			totalServices++
+			owner := s.ownership.Resolve(service.Labels, service.Annotations, ns.Labels, ns.Annotations)
+			apps = append(apps, owner)
 
 			if s.isServiceHealthy(ctx, client, &service) {
 				healthyServices++
 			} else {
 				unhealthyServices++
+				if runbook == "" {
+					runbook = annotatedRunbook(service.Annotations, ns.Annotations)
+				}
 				serviceIssues = append(serviceIssues,
 					fmt.Sprintf("%s/%s: No endpoints", service.Namespace, service.Name))
+				ref := core.ResourceRef{Kind: "service", Namespace: service.Namespace, Name: service.Name}
+				implicated = append(implicated, ref)
+				owners = append(owners, owner)
+				addFindings(&result, findings.Finding{ID: findings.ServiceNoEndpoint, Subject: ref.String(), Message: "No endpoints"})
 			}
 		}
 	}
@@ -82,12 +105,25 @@ func (s *ServiceHealthCheck) Check(ctx context.Context, client kubernetes.Interf
 	}
 
 	// Add details
+	result.AffectedResources = unhealthyServices
+	setImplicatedResources(&result, implicated)
+	core.SetOwners(&result, core.DetailOwners, owners)
+	core.SetOwners(&result, core.DetailApps, apps)
+	if runbook != "" {
+		result.Details["runbook"] = runbook
+	}
 	result.Details["total_services"] = totalServices
 	result.Details["healthy_services"] = healthyServices
 	result.Details["unhealthy_services"] = unhealthyServices
 	if len(serviceIssues) > 0 {
 		result.Details["issues"] = serviceIssues
 	}
+	if len(ignoredNamespaces) > 0 {
+		result.Details["ignored_namespaces"] = ignoredNamespaces
+	}
+	if len(ignoredServices) > 0 {
+		result.Details["ignored_services"] = ignoredServices
+	}
 
 	// Add metrics
 	result.Metrics = append(result.Metrics,
@@ -114,6 +150,9 @@ func (s *ServiceHealthCheck) Configure(config map[string]interface{}) error {
 	if v, ok := config["namespace"].(string); ok {
 		s.namespace = v
 	}
+	if v, ok := config["ownership"].(core.OwnershipRules); ok {
+		s.ownership = v
+	}
 	return nil
 }
 
@@ -145,10 +184,11 @@ func (s *ServiceHealthCheck) isServiceHealthy(ctx context.Context, client kubern
 	return false
 }
 
-// getNamespacesToCheck returns the list of namespaces to check
-func (s *ServiceHealthCheck) getNamespacesToCheck(ctx context.Context, client kubernetes.Interface) ([]string, error) {
+// getNamespacesToCheck returns the namespaces to check. Annotations are only
+// available for namespaces discovered from the cluster.
+func (s *ServiceHealthCheck) getNamespacesToCheck(ctx context.Context, client kubernetes.Interface) ([]corev1.Namespace, error) {
 	if s.namespace != "" {
-		return []string{s.namespace}, nil
+		return namespacesNamed(s.namespace), nil
 	}
 
 	// Get all namespaces except system ones
@@ -157,11 +197,11 @@ func (s *ServiceHealthCheck) getNamespacesToCheck(ctx context.Context, client ku
 		return nil, err
 	}
 
-	var result []string
+	var result []corev1.Namespace
 	for _, ns := range namespaces.Items {
 		// Skip system namespaces for services
 		if ns.Name != "kube-system" && ns.Name != "kube-public" && ns.Name != "kube-node-lease" {
-			result = append(result, ns.Name)
+			result = append(result, ns)
 		}
 	}
 
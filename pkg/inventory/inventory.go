@@ -0,0 +1,349 @@
+// Package inventory takes stock of what a cluster runs: its nodes,
+// namespaces and workloads, the custom resources installed in it and the
+// versions of key components such as the API server, CoreDNS or the ingress
+// controller. Collecting it lists every workload, so callers cache the
+// result and refresh it slowly.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload kinds counted in an inventory
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+	KindDaemonSet   = "DaemonSet"
+	KindJob         = "Job"
+	KindCronJob     = "CronJob"
+	KindPod         = "Pod"
+	KindService     = "Service"
+)
+
+// Node labels naming the machine type, most specific first
+var instanceTypeLabels = []string{
+	"node.kubernetes.io/instance-type",
+	"beta.kubernetes.io/instance-type",
+}
+
+// builtinGroups are the kube-apiserver's API groups with a domain
+var builtinGroups = map[string]bool{
+	"admissionregistration.k8s.io": true,
+	"apiextensions.k8s.io":         true,
+	"apiregistration.k8s.io":       true,
+	"authentication.k8s.io":        true,
+	"authorization.k8s.io":         true,
+	"certificates.k8s.io":          true,
+	"coordination.k8s.io":          true,
+	"discovery.k8s.io":             true,
+	"events.k8s.io":                true,
+	"flowcontrol.apiserver.k8s.io": true,
+	"internal.apiserver.k8s.io":    true,
+	"networking.k8s.io":            true,
+	"node.k8s.io":                  true,
+	"rbac.authorization.k8s.io":    true,
+	"resource.k8s.io":              true,
+	"scheduling.k8s.io":            true,
+	"storage.k8s.io":               true,
+	"storagemigration.k8s.io":      true,
+}
+
+// components maps the Deployment or DaemonSet names of key cluster add-ons
+// to the component they run
+var components = map[string]string{
+	"coredns":                  "coredns",
+	"kube-dns":                 "kube-dns",
+	"kube-proxy":               "kube-proxy",
+	"metrics-server":           "metrics-server",
+	"kube-state-metrics":       "kube-state-metrics",
+	"cluster-autoscaler":       "cluster-autoscaler",
+	"karpenter":                "karpenter",
+	"ingress-nginx-controller": "ingress-nginx",
+	"traefik":                  "traefik",
+	"cert-manager":             "cert-manager",
+	"istiod":                   "istio",
+	"linkerd-destination":      "linkerd",
+	"cilium":                   "cilium",
+	"calico-node":              "calico",
+	"aws-node":                 "aws-vpc-cni",
+	"node-problem-detector":    "node-problem-detector",
+	"argocd-server":            "argo-cd",
+	"source-controller":        "flux",
+}
+
+// Inventory is a snapshot of what a cluster runs
+type Inventory struct {
+	CollectedAt     time.Time           `json:"collected_at"`
+	Duration        time.Duration       `json:"duration"`
+	ServerVersion   string              `json:"server_version,omitempty"`
+	Nodes           NodeInventory       `json:"nodes"`
+	Namespaces      int                 `json:"namespaces"`
+	Workloads       map[string]int      `json:"workloads"` // Count per kind
+	CustomResources []CustomResourceAPI `json:"custom_resources"`
+	Components      []Component         `json:"components"`
+	Errors          []string            `json:"errors,omitempty"` // Parts that couldn't be collected
+}
+
+// NodeInventory counts nodes by type and version
+type NodeInventory struct {
+	Total           int            `json:"total"`
+	InstanceTypes   map[string]int `json:"instance_types"`   // "unknown" without an instance type label
+	KubeletVersions map[string]int `json:"kubelet_versions"` // e.g. v1.30.2
+	OSImages        map[string]int `json:"os_images"`
+	Architectures   map[string]int `json:"architectures"`
+}
+
+// CustomResourceAPI is an API group beyond the built-in Kubernetes ones,
+// served by custom resource definitions or an aggregated API server
+type CustomResourceAPI struct {
+	Group     string   `json:"group"`
+	Versions  []string `json:"versions"`
+	Preferred string   `json:"preferred_version"`
+	Kinds     []string `json:"kinds,omitempty"`
+}
+
+// Component is a key cluster add-on and the version its image runs
+type Component struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"` // e.g. deployment/coredns
+	Version   string `json:"version"`  // Image tag, or digest when untagged
+	Image     string `json:"image"`
+}
+
+// Collect takes stock of a cluster. Parts that can't be listed, usually for
+// lack of RBAC permissions, are left empty and named in Errors.
+func Collect(ctx context.Context, client kubernetes.Interface) *Inventory {
+	start := time.Now()
+	inv := &Inventory{
+		CollectedAt:     start,
+		Workloads:       make(map[string]int),
+		CustomResources: []CustomResourceAPI{},
+		Components:      []Component{},
+		Nodes: NodeInventory{
+			InstanceTypes:   make(map[string]int),
+			KubeletVersions: make(map[string]int),
+			OSImages:        make(map[string]int),
+			Architectures:   make(map[string]int),
+		},
+	}
+	failed := func(part string, err error) {
+		inv.Errors = append(inv.Errors, fmt.Sprintf("%s: %v", part, err))
+	}
+	// Served from the API server's cache; exact counts aren't needed
+	cached := metav1.ListOptions{ResourceVersion: "0"}
+
+	if info, err := client.Discovery().ServerVersion(); err != nil {
+		failed("server version", err)
+	} else {
+		inv.ServerVersion = info.GitVersion
+	}
+
+	if nodes, err := client.CoreV1().Nodes().List(ctx, cached); err != nil {
+		failed("nodes", err)
+	} else {
+		inv.Nodes.count(nodes.Items)
+	}
+
+	if namespaces, err := client.CoreV1().Namespaces().List(ctx, cached); err != nil {
+		failed("namespaces", err)
+	} else {
+		inv.Namespaces = len(namespaces.Items)
+	}
+
+	if deployments, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("deployments", err)
+	} else {
+		inv.Workloads[KindDeployment] = len(deployments.Items)
+		for _, deployment := range deployments.Items {
+			inv.addComponent("deployment", deployment.ObjectMeta, deployment.Spec.Template.Spec)
+		}
+	}
+	if daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("daemonsets", err)
+	} else {
+		inv.Workloads[KindDaemonSet] = len(daemonSets.Items)
+		for _, daemonSet := range daemonSets.Items {
+			inv.addComponent("daemonset", daemonSet.ObjectMeta, daemonSet.Spec.Template.Spec)
+		}
+	}
+	if statefulSets, err := client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("statefulsets", err)
+	} else {
+		inv.Workloads[KindStatefulSet] = len(statefulSets.Items)
+	}
+	if jobs, err := client.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("jobs", err)
+	} else {
+		inv.Workloads[KindJob] = len(jobs.Items)
+	}
+	if cronJobs, err := client.BatchV1().CronJobs(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("cronjobs", err)
+	} else {
+		inv.Workloads[KindCronJob] = len(cronJobs.Items)
+	}
+	if pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("pods", err)
+	} else {
+		inv.Workloads[KindPod] = len(pods.Items)
+	}
+	if services, err := client.CoreV1().Services(metav1.NamespaceAll).List(ctx, cached); err != nil {
+		failed("services", err)
+	} else {
+		inv.Workloads[KindService] = len(services.Items)
+	}
+
+	if err := inv.collectCustomResources(client); err != nil {
+		failed("custom resources", err)
+	}
+
+	sort.Slice(inv.Components, func(i, j int) bool {
+		if inv.Components[i].Name != inv.Components[j].Name {
+			return inv.Components[i].Name < inv.Components[j].Name
+		}
+		return inv.Components[i].Namespace < inv.Components[j].Namespace
+	})
+	inv.Duration = time.Since(start)
+	return inv
+}
+
+// count adds nodes to the node inventory
+func (n *NodeInventory) count(nodes []corev1.Node) {
+	for _, node := range nodes {
+		n.Total++
+		instanceType := "unknown"
+		for _, label := range instanceTypeLabels {
+			if value := node.Labels[label]; value != "" {
+				instanceType = value
+				break
+			}
+		}
+		n.InstanceTypes[instanceType]++
+		if info := node.Status.NodeInfo; info.KubeletVersion != "" {
+			n.KubeletVersions[info.KubeletVersion]++
+		}
+		if image := node.Status.NodeInfo.OSImage; image != "" {
+			n.OSImages[image]++
+		}
+		if arch := node.Status.NodeInfo.Architecture; arch != "" {
+			n.Architectures[arch]++
+		}
+	}
+}
+
+// addComponent records a workload running a key cluster add-on
+func (inv *Inventory) addComponent(kind string, meta metav1.ObjectMeta, spec corev1.PodSpec) {
+	name, ok := components[meta.Name]
+	if !ok || len(spec.Containers) == 0 {
+		return
+	}
+	image := spec.Containers[0].Image
+	inv.Components = append(inv.Components, Component{
+		Name:      name,
+		Namespace: meta.Namespace,
+		Workload:  kind + "/" + meta.Name,
+		Version:   imageVersion(image),
+		Image:     image,
+	})
+}
+
+// imageVersion returns an image's tag, its digest when it has no tag, or
+// "latest" when it has neither
+func imageVersion(image string) string {
+	if name, digest, ok := strings.Cut(image, "@"); ok {
+		if tag := imageTag(name); tag != "" {
+			return tag
+		}
+		return digest
+	}
+	if tag := imageTag(image); tag != "" {
+		return tag
+	}
+	return "latest"
+}
+
+// imageTag returns the tag of an image reference without a digest
+func imageTag(image string) string {
+	// A colon before the last slash separates a registry port, not a tag
+	colon := strings.LastIndex(image, ":")
+	if colon < 0 || colon < strings.LastIndex(image, "/") {
+		return ""
+	}
+	return image[colon+1:]
+}
+
+// collectCustomResources lists the API groups served beyond the built-in
+// Kubernetes ones, which come from CRDs or aggregated API servers
+func (inv *Inventory) collectCustomResources(client kubernetes.Interface) error {
+	groups, err := client.Discovery().ServerGroups()
+	if err != nil {
+		return err
+	}
+	for _, group := range groups.Groups {
+		if IsBuiltinGroup(group.Name) {
+			continue
+		}
+		api := CustomResourceAPI{Group: group.Name, Preferred: group.PreferredVersion.Version}
+		for _, version := range group.Versions {
+			api.Versions = append(api.Versions, version.Version)
+		}
+		if resources, err := client.Discovery().ServerResourcesForGroupVersion(group.PreferredVersion.GroupVersion); err == nil {
+			kinds := make(map[string]bool)
+			for _, resource := range resources.APIResources {
+				if !strings.Contains(resource.Name, "/") {
+					kinds[resource.Kind] = true
+				}
+			}
+			for kind := range kinds {
+				api.Kinds = append(api.Kinds, kind)
+			}
+			sort.Strings(api.Kinds)
+		}
+		inv.CustomResources = append(inv.CustomResources, api)
+	}
+	sort.Slice(inv.CustomResources, func(i, j int) bool {
+		return inv.CustomResources[i].Group < inv.CustomResources[j].Group
+	})
+	return nil
+}
+
+// IsBuiltinGroup reports whether Kubernetes itself serves an API group: the
+// core group, groups without a domain such as apps or batch, and the
+// kube-apiserver's own k8s.io groups. Other k8s.io groups, such as Gateway
+// API or metrics.k8s.io, are installed as add-ons.
+func IsBuiltinGroup(group string) bool {
+	return group == "" || !strings.Contains(group, ".") || builtinGroups[group]
+}
+
+// Compact returns a copy for AI prompts, without the kinds of custom
+// resource groups, which run to hundreds in clusters with many operators,
+// or the collection errors. A nil inventory returns nil.
+func (inv *Inventory) Compact() *Inventory {
+	if inv == nil {
+		return nil
+	}
+	compact := *inv
+	compact.Errors = nil
+	compact.CustomResources = make([]CustomResourceAPI, len(inv.CustomResources))
+	for i, api := range inv.CustomResources {
+		api.Kinds = nil
+		compact.CustomResources[i] = api
+	}
+	return &compact
+}
+
+// Summary describes an inventory in a line, e.g. "12 nodes, 34 namespaces,
+// 120 deployments, 8 custom resource groups"
+func (inv *Inventory) Summary() string {
+	return fmt.Sprintf("%d nodes, %d namespaces, %d deployments, %d statefulsets, %d daemonsets, %d pods, %d custom resource groups",
+		inv.Nodes.Total, inv.Namespaces, inv.Workloads[KindDeployment], inv.Workloads[KindStatefulSet],
+		inv.Workloads[KindDaemonSet], inv.Workloads[KindPod], len(inv.CustomResources))
+}
@@ -0,0 +1,149 @@
+package inventory
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func node(name, instanceType, kubelet string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"node.kubernetes.io/instance-type": instanceType}},
+		Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+			KubeletVersion: kubelet,
+			OSImage:        "Ubuntu 22.04.4 LTS",
+			Architecture:   "amd64",
+		}},
+	}
+}
+
+func deployment(namespace, name, image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: name, Image: image}},
+		}}},
+	}
+}
+
+func TestCollect(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		node("worker-1", "m5.large", "v1.30.2"),
+		node("worker-2", "m5.large", "v1.30.2"),
+		node("gpu-1", "p3.2xlarge", "v1.29.8"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		deployment("kube-system", "coredns", "registry.k8s.io/coredns/coredns:v1.11.1"),
+		deployment("ingress-nginx", "ingress-nginx-controller", "registry.k8s.io/ingress-nginx/controller:v1.10.1@sha256:e24f39d3"),
+		deployment("default", "web", "nginx"),
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-proxy", Namespace: "kube-system"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "kube-proxy", Image: "registry.k8s.io/kube-proxy:v1.30.2"}},
+			}}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}},
+	)
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: "v1.30.4"}
+	discovery.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod"}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress"}}},
+		{GroupVersion: "cert-manager.io/v1", APIResources: []metav1.APIResource{
+			{Name: "certificates", Kind: "Certificate"},
+			{Name: "certificates/status", Kind: "Certificate"},
+			{Name: "issuers", Kind: "Issuer"},
+		}},
+		{GroupVersion: "gateway.networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "gateways", Kind: "Gateway"}}},
+	}
+
+	inv := Collect(context.Background(), client)
+	if len(inv.Errors) != 0 {
+		t.Fatalf("unexpected errors: %v", inv.Errors)
+	}
+	if inv.ServerVersion != "v1.30.4" || inv.Namespaces != 2 {
+		t.Errorf("unexpected server version %q or namespaces %d", inv.ServerVersion, inv.Namespaces)
+	}
+	if inv.Nodes.Total != 3 || !reflect.DeepEqual(inv.Nodes.InstanceTypes, map[string]int{"m5.large": 2, "p3.2xlarge": 1}) {
+		t.Errorf("unexpected nodes: %+v", inv.Nodes)
+	}
+	if !reflect.DeepEqual(inv.Nodes.KubeletVersions, map[string]int{"v1.30.2": 2, "v1.29.8": 1}) {
+		t.Errorf("unexpected kubelet versions: %v", inv.Nodes.KubeletVersions)
+	}
+	wantWorkloads := map[string]int{KindDeployment: 3, KindDaemonSet: 1, KindStatefulSet: 0, KindJob: 0, KindCronJob: 0, KindPod: 1, KindService: 0}
+	if !reflect.DeepEqual(inv.Workloads, wantWorkloads) {
+		t.Errorf("got workloads %v, want %v", inv.Workloads, wantWorkloads)
+	}
+
+	var groups []string
+	for _, api := range inv.CustomResources {
+		groups = append(groups, api.Group)
+	}
+	if !reflect.DeepEqual(groups, []string{"cert-manager.io", "gateway.networking.k8s.io"}) {
+		t.Errorf("expected only add-on API groups, got %v", groups)
+	}
+	if kinds := inv.CustomResources[0].Kinds; !reflect.DeepEqual(kinds, []string{"Certificate", "Issuer"}) {
+		t.Errorf("unexpected cert-manager kinds: %v", kinds)
+	}
+
+	var components []string
+	for _, component := range inv.Components {
+		components = append(components, component.Name+"@"+component.Version)
+	}
+	want := []string{"coredns@v1.11.1", "ingress-nginx@v1.10.1", "kube-proxy@v1.30.2"}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("got components %v, want %v", components, want)
+	}
+
+	compact := inv.Compact()
+	if compact.CustomResources[0].Kinds != nil || inv.CustomResources[0].Kinds == nil {
+		t.Error("expected Compact to drop kinds from a copy")
+	}
+	if !strings.Contains(inv.Summary(), "3 nodes, 2 namespaces, 3 deployments") {
+		t.Errorf("unexpected summary %q", inv.Summary())
+	}
+}
+
+func TestCollect_PartialFailure(t *testing.T) {
+	client := fake.NewSimpleClientset(node("worker-1", "m5.large", "v1.30.2"))
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, context.DeadlineExceeded
+	})
+
+	inv := Collect(context.Background(), client)
+	if inv.Nodes.Total != 1 {
+		t.Errorf("expected nodes to be collected, got %+v", inv.Nodes)
+	}
+	if len(inv.Errors) != 1 || !strings.HasPrefix(inv.Errors[0], "pods:") {
+		t.Errorf("expected a pods error, got %v", inv.Errors)
+	}
+}
+
+func TestImageVersion(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"registry.k8s.io/coredns/coredns:v1.11.1", "v1.11.1"},
+		{"localhost:5000/metrics-server:0.7.1", "0.7.1"},
+		{"localhost:5000/metrics-server", "latest"},
+		{"quay.io/cilium/cilium@sha256:abc123", "sha256:abc123"},
+		{"nginx", "latest"},
+	}
+	for _, tt := range tests {
+		if got := imageVersion(tt.image); got != tt.want {
+			t.Errorf("imageVersion(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
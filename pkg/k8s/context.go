@@ -2,17 +2,22 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 )
 
+// ErrContextNotFound is returned for a context missing from the kubeconfig
+var ErrContextNotFound = errors.New("context not found")
+
 // ContextInfo represents information about a Kubernetes context
 type ContextInfo struct {
 	Name        string `json:"name"`
@@ -21,31 +26,35 @@ type ContextInfo struct {
 	Server      string `json:"server"`
 	User        string `json:"user"`
 	Current     bool   `json:"current"`
+	Synthetic   bool   `json:"synthetic,omitempty"` // From a configured endpoint rather than the kubeconfig
 }
 
 // ContextManager manages multiple Kubernetes contexts
 type ContextManager struct {
 	kubeconfigPath string
+	endpoints      []ClusterEndpoint
 	config         *clientcmdapi.Config
 	clients        map[string]kubernetes.Interface
 	currentContext string
 	mu             sync.RWMutex
 }
 
-// NewContextManager creates a new context manager
-func NewContextManager(kubeconfigPath string) (*ContextManager, error) {
+// NewContextManager creates a new context manager. Endpoints are added as
+// synthetic contexts, and the kubeconfig may be missing when there are any.
+func NewContextManager(kubeconfigPath string, endpoints ...ClusterEndpoint) (*ContextManager, error) {
 	if kubeconfigPath == "" {
 		kubeconfigPath = clientcmd.NewDefaultClientConfigLoadingRules().GetDefaultFilename()
 	}
 
 	// Load kubeconfig
-	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	config, err := LoadConfig(kubeconfigPath, endpoints)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
 	cm := &ContextManager{
 		kubeconfigPath: kubeconfigPath,
+		endpoints:      endpoints,
 		config:         config,
 		clients:        make(map[string]kubernetes.Interface),
 		currentContext: config.CurrentContext,
@@ -82,6 +91,7 @@ func (cm *ContextManager) ListContexts() ([]ContextInfo, error) {
 			Namespace:   namespace,
 			User:        context.AuthInfo,
 			Current:     name == cm.currentContext,
+			Synthetic:   cm.synthetic(name),
 		}
 
 		if cluster != nil {
@@ -169,18 +179,9 @@ func (cm *ContextManager) getOrCreateClient(contextName string) (kubernetes.Inte
 	}
 
 	// Create new client
-	configOverrides := &clientcmd.ConfigOverrides{
-		CurrentContext: contextName,
-	}
-
-	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: cm.kubeconfigPath},
-		configOverrides,
-	)
-
-	restConfig, err := clientConfig.ClientConfig()
+	restConfig, err := cm.restConfig(cm.config, contextName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create rest config: %w", err)
+		return nil, err
 	}
 
 	client, err := kubernetes.NewForConfig(restConfig)
@@ -203,12 +204,51 @@ func (cm *ContextManager) getOrCreateClient(contextName string) (kubernetes.Inte
 	return client, nil
 }
 
+// NewClient creates an uncached client for a context whose requests give up
+// after timeout, for short-lived use such as probing several clusters at
+// once. Unlike GetClient it doesn't test the connection.
+func (cm *ContextManager) NewClient(contextName string, timeout time.Duration) (kubernetes.Interface, error) {
+	cm.mu.RLock()
+	config := cm.config
+	cm.mu.RUnlock()
+	if _, exists := config.Contexts[contextName]; !exists {
+		return nil, fmt.Errorf("%w: %s", ErrContextNotFound, contextName)
+	}
+
+	restConfig, err := cm.restConfig(config, contextName)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Timeout = timeout
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// restConfig builds the REST configuration for a context of a kubeconfig
+func (cm *ContextManager) restConfig(config *clientcmdapi.Config, contextName string) (*rest.Config, error) {
+	return restConfigFor(config, cm.kubeconfigPath, contextName)
+}
+
+// synthetic reports whether a context comes from an endpoint
+func (cm *ContextManager) synthetic(contextName string) bool {
+	for _, endpoint := range cm.endpoints {
+		if endpoint.Name == contextName {
+			return true
+		}
+	}
+	return false
+}
+
 // RefreshContexts reloads the kubeconfig and refreshes context information
 func (cm *ContextManager) RefreshContexts() error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
-	config, err := clientcmd.LoadFromFile(cm.kubeconfigPath)
+	config, err := LoadConfig(cm.kubeconfigPath, cm.endpoints)
 	if err != nil {
 		return fmt.Errorf("failed to reload kubeconfig: %w", err)
 	}
@@ -1,10 +1,16 @@
 package k8s
 
 import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -323,6 +329,90 @@ func TestContextManager_DefaultNamespace(t *testing.T) {
 	}
 }
 
+func TestContextManager_NewClient(t *testing.T) {
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	config := &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{"prod": {Server: "https://prod.example.com"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"admin": {Token: "token"}},
+		Contexts:  map[string]*clientcmdapi.Context{"prod": {Cluster: "prod", AuthInfo: "admin"}},
+	}
+	if err := writeKubeConfig(kubeconfigPath, config); err != nil {
+		t.Fatalf("failed to write test kubeconfig: %v", err)
+	}
+	cm, err := NewContextManager(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("failed to create context manager: %v", err)
+	}
+
+	// Clients are created without contacting the cluster and aren't cached
+	if client, err := cm.NewClient("prod", 5*time.Second); err != nil || client == nil {
+		t.Fatalf("expected a client for prod, got %v", err)
+	}
+	if len(cm.clients) != 0 {
+		t.Errorf("expected short-lived clients not to be cached, got %d", len(cm.clients))
+	}
+	if _, err := cm.NewClient("staging", 5*time.Second); !errors.Is(err, ErrContextNotFound) {
+		t.Errorf("expected ErrContextNotFound, got %v", err)
+	}
+}
+
+func TestContextManager_Endpoints(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+	}))
+	defer server.Close()
+	ca := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	// No kubeconfig is needed; the first endpoint becomes current
+	missing := filepath.Join(t.TempDir(), "kubeconfig")
+	cm, err := NewContextManager(missing,
+		ClusterEndpoint{Name: "prod", Server: server.URL, Namespace: "apps", Token: "secret", CAData: ca},
+		ClusterEndpoint{Name: "eks", Server: "https://eks.example.com", Exec: &ExecAuth{Command: "aws", Args: []string{"eks", "get-token"}}},
+	)
+	if err != nil {
+		t.Fatalf("failed to create context manager: %v", err)
+	}
+	current, err := cm.GetCurrentContext()
+	if err != nil || current.Name != "prod" || !current.Synthetic || current.Server != server.URL || current.Namespace != "apps" {
+		t.Fatalf("expected the synthetic prod context current, got %+v, %v", current, err)
+	}
+	if _, err := cm.GetCurrentClient(); err != nil || authorization != "Bearer secret" {
+		t.Errorf("expected the endpoint token sent, got %q, %v", authorization, err)
+	}
+	if contexts, _ := cm.ListContexts(); len(contexts) != 2 {
+		t.Errorf("expected 2 contexts, got %+v", contexts)
+	}
+	restConfig, err := RESTConfig(missing, "eks", cm.endpoints)
+	if err != nil || restConfig.ExecProvider == nil || restConfig.ExecProvider.APIVersion != defaultExecAPIVersion {
+		t.Errorf("expected the exec plugin with the default API version, got %+v, %v", restConfig, err)
+	}
+	if err := cm.RefreshContexts(); err != nil || !cm.synthetic("eks") {
+		t.Errorf("expected endpoints kept on refresh, got %v", err)
+	}
+
+	// Endpoints join the contexts of a kubeconfig without replacing its current one
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	config := &clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"dev": {Server: "https://dev.example.com"}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"admin": {Token: "token"}},
+		Contexts:       map[string]*clientcmdapi.Context{"dev": {Cluster: "dev", AuthInfo: "admin"}},
+		CurrentContext: "dev",
+	}
+	if err := writeKubeConfig(kubeconfigPath, config); err != nil {
+		t.Fatalf("failed to write test kubeconfig: %v", err)
+	}
+	merged, err := LoadConfig(kubeconfigPath, []ClusterEndpoint{{Name: "prod", Server: server.URL, Token: "secret"}})
+	if err != nil || merged.CurrentContext != "dev" || len(merged.Contexts) != 2 {
+		t.Errorf("expected prod added beside dev, got %+v, %v", merged, err)
+	}
+	if _, err := LoadConfig(kubeconfigPath, []ClusterEndpoint{{Name: "bad", Server: server.URL, Token: "secret", CAData: "not a certificate"}}); err == nil {
+		t.Error("expected an error for invalid CA data")
+	}
+}
+
 // writeKubeConfig writes a kubeconfig to a file in YAML format
 func writeKubeConfig(path string, config *clientcmdapi.Config) error {
 	// Create a simple YAML representation of the config
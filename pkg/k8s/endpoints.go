@@ -0,0 +1,162 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+)
+
+// endpointPrefix names the kubeconfig clusters and users of endpoints, so
+// they can't replace the entries of kubeconfig contexts
+const endpointPrefix = "kubepulse/"
+
+// defaultExecAPIVersion is the credential plugin API version endpoints use
+// unless they set one
+const defaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// ClusterEndpoint connects to a cluster by API server URL and credentials
+// rather than a kubeconfig entry, for containers where mounting a
+// kubeconfig is awkward. It appears as a synthetic context of its name.
+type ClusterEndpoint struct {
+	Name      string
+	Server    string // API server URL
+	Namespace string
+
+	Token     string
+	TokenFile string // Reread as it rotates, such as a projected service account token
+	Exec      *ExecAuth
+
+	CAFile   string
+	CAData   string // PEM, or base64 of it
+	Insecure bool   // Skip verifying the server certificate
+}
+
+// ExecAuth runs a credential plugin, such as aws eks get-token or
+// gke-gcloud-auth-plugin, for a token
+type ExecAuth struct {
+	Command    string
+	Args       []string
+	Env        map[string]string
+	APIVersion string // client.authentication.k8s.io/v1beta1 when empty
+}
+
+// caData returns the PEM of the endpoint's CA, decoding base64
+func (e ClusterEndpoint) caData() ([]byte, error) {
+	data := strings.TrimSpace(e.CAData)
+	if strings.HasPrefix(data, "-----BEGIN") {
+		return []byte(data), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(decoded)), "-----BEGIN") {
+		return nil, fmt.Errorf("CA data must be PEM or base64-encoded PEM")
+	}
+	return decoded, nil
+}
+
+// add adds the endpoint to a kubeconfig as a context of its name
+func (e ClusterEndpoint) add(config *clientcmdapi.Config) error {
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = e.Server
+	cluster.CertificateAuthority = e.CAFile
+	cluster.InsecureSkipTLSVerify = e.Insecure
+	if e.CAData != "" {
+		data, err := e.caData()
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", e.Name, err)
+		}
+		cluster.CertificateAuthorityData = data
+	}
+
+	user := clientcmdapi.NewAuthInfo()
+	user.Token = e.Token
+	user.TokenFile = e.TokenFile
+	if e.Exec != nil {
+		user.Exec = &clientcmdapi.ExecConfig{
+			Command:         e.Exec.Command,
+			Args:            e.Exec.Args,
+			APIVersion:      e.Exec.APIVersion,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+		if user.Exec.APIVersion == "" {
+			user.Exec.APIVersion = defaultExecAPIVersion
+		}
+		for name, value := range e.Exec.Env {
+			user.Exec.Env = append(user.Exec.Env, clientcmdapi.ExecEnvVar{Name: name, Value: value})
+		}
+	}
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = endpointPrefix + e.Name
+	context.AuthInfo = endpointPrefix + e.Name
+	context.Namespace = e.Namespace
+
+	if _, exists := config.Contexts[e.Name]; exists {
+		klog.Warningf("Cluster %s from the config file replaces the kubeconfig context of the same name", e.Name)
+	}
+	config.Clusters[context.Cluster] = cluster
+	config.AuthInfos[context.AuthInfo] = user
+	config.Contexts[e.Name] = context
+	if config.CurrentContext == "" {
+		config.CurrentContext = e.Name
+	}
+	return nil
+}
+
+// LoadConfig loads a kubeconfig and adds endpoints to it as contexts. The
+// kubeconfig may be missing when there are endpoints; the first then
+// becomes the current context.
+func LoadConfig(kubeconfigPath string, endpoints []ClusterEndpoint) (*clientcmdapi.Config, error) {
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	switch {
+	case err == nil:
+		// Certificate and token files are relative to the kubeconfig
+		if err := clientcmd.ResolveLocalPaths(config); err != nil {
+			return nil, fmt.Errorf("failed to resolve kubeconfig paths: %w", err)
+		}
+	case len(endpoints) > 0 && (errors.Is(err, fs.ErrNotExist) || kubeconfigPath == ""):
+		config = clientcmdapi.NewConfig()
+	default:
+		return nil, err
+	}
+
+	for _, endpoint := range endpoints {
+		if err := endpoint.add(config); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// RESTConfig builds the REST configuration of a context, the current one
+// when contextName is empty, from a kubeconfig and endpoints
+func RESTConfig(kubeconfigPath, contextName string, endpoints []ClusterEndpoint) (*rest.Config, error) {
+	config, err := LoadConfig(kubeconfigPath, endpoints)
+	if err != nil {
+		return nil, err
+	}
+	return restConfigFor(config, kubeconfigPath, contextName)
+}
+
+// restConfigFor builds the REST configuration of a context of a loaded
+// kubeconfig. Auth providers refreshing tokens write them back to the
+// kubeconfig file, when there is one.
+func restConfigFor(config *clientcmdapi.Config, kubeconfigPath, contextName string) (*rest.Config, error) {
+	var access clientcmd.ConfigAccess
+	if _, err := os.Stat(kubeconfigPath); err == nil {
+		access = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*config, contextName, overrides, access).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rest config: %w", err)
+	}
+	return restConfig, nil
+}
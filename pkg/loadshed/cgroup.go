@@ -0,0 +1,157 @@
+package loadshed
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCgroupRoot is where the cgroup filesystem is mounted in containers
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// usage is one reading of the cgroup's memory and cumulative CPU use;
+// limits are zero when unlimited
+type usage struct {
+	memory      int64         // Working set: usage less inactive file cache
+	memoryLimit int64         // Bytes
+	cpu         time.Duration // CPU time used since the cgroup started
+	cpuLimit    float64       // Cores
+}
+
+// readCgroup reads the usage of the cgroup mounted at root, preferring
+// cgroup v2 files and falling back to the v1 hierarchy
+func readCgroup(root string) (usage, error) {
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return readCgroupV2(root)
+	}
+	if _, err := os.Stat(filepath.Join(root, "memory")); err == nil {
+		return readCgroupV1(root)
+	}
+	return usage{}, fmt.Errorf("no cgroup filesystem at %s", root)
+}
+
+// readCgroupV2 reads the unified hierarchy
+func readCgroupV2(root string) (usage, error) {
+	var u usage
+	current, err := readInt(filepath.Join(root, "memory.current"))
+	if err != nil {
+		return usage{}, err
+	}
+	stat, err := readStat(filepath.Join(root, "memory.stat"))
+	if err != nil {
+		return usage{}, err
+	}
+	u.memory = workingSet(current, stat["inactive_file"])
+	if u.memoryLimit, err = readLimit(filepath.Join(root, "memory.max")); err != nil {
+		return usage{}, err
+	}
+
+	cpuStat, err := readStat(filepath.Join(root, "cpu.stat"))
+	if err != nil {
+		return usage{}, err
+	}
+	u.cpu = time.Duration(cpuStat["usage_usec"]) * time.Microsecond
+
+	// cpu.max holds "quota period", with quota "max" when unlimited
+	data, err := os.ReadFile(filepath.Join(root, "cpu.max")) // #nosec G304 - fixed cgroup file
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return usage{}, err
+	}
+	if fields := strings.Fields(string(data)); len(fields) == 2 && fields[0] != "max" {
+		quota, qerr := strconv.ParseFloat(fields[0], 64)
+		period, perr := strconv.ParseFloat(fields[1], 64)
+		if qerr == nil && perr == nil && period > 0 {
+			u.cpuLimit = quota / period
+		}
+	}
+	return u, nil
+}
+
+// readCgroupV1 reads the memory, cpu and cpuacct controllers
+func readCgroupV1(root string) (usage, error) {
+	var u usage
+	current, err := readInt(filepath.Join(root, "memory", "memory.usage_in_bytes"))
+	if err != nil {
+		return usage{}, err
+	}
+	stat, err := readStat(filepath.Join(root, "memory", "memory.stat"))
+	if err != nil {
+		return usage{}, err
+	}
+	u.memory = workingSet(current, stat["total_inactive_file"])
+	if u.memoryLimit, err = readLimit(filepath.Join(root, "memory", "memory.limit_in_bytes")); err != nil {
+		return usage{}, err
+	}
+	// v1 reports an unlimited cgroup as a huge page-aligned number
+	if u.memoryLimit >= 1<<62 {
+		u.memoryLimit = 0
+	}
+
+	if used, err := readInt(filepath.Join(root, "cpuacct", "cpuacct.usage")); err == nil {
+		u.cpu = time.Duration(used)
+	}
+	quota, qerr := readInt(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	period, perr := readInt(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if qerr == nil && perr == nil && quota > 0 && period > 0 {
+		u.cpuLimit = float64(quota) / float64(period)
+	}
+	return u, nil
+}
+
+// workingSet is memory use less the inactive file cache the kernel can
+// reclaim, which is what the OOM killer and kubelet count
+func workingSet(current, inactiveFile int64) int64 {
+	if inactiveFile > current {
+		return 0
+	}
+	return current - inactiveFile
+}
+
+// readLimit reads a memory limit file, returning zero for "max"
+func readLimit(path string) (int64, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - fixed cgroup file
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// readInt reads a file holding a single integer
+func readInt(path string) (int64, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - fixed cgroup file
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readStat reads a file of "key value" lines
+func readStat(path string) (map[string]int64, error) {
+	file, err := os.Open(path) // #nosec G304 - fixed cgroup file
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	stat := make(map[string]int64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			stat[fields[0]] = value
+		}
+	}
+	return stat, scanner.Err()
+}
@@ -0,0 +1,195 @@
+// Package loadshed watches KubePulse's own memory and CPU use against the
+// limits of its cgroup and switches it into a load-shedding mode as it
+// approaches them, so the monitor degrades predictably instead of being
+// OOMKilled or throttled in the middle of an incident.
+package loadshed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// recoveryFactor is the share of each threshold usage must fall below
+// before shedding stops, so usage hovering at a threshold doesn't flap
+const recoveryFactor = 0.9
+
+// Config sets when load is shed
+type Config struct {
+	Root            string        // Where the cgroup filesystem is mounted; DefaultCgroupRoot when empty
+	Interval        time.Duration // How often usage is sampled
+	MemoryThreshold float64       // Share of the memory limit that starts shedding
+	CPUThreshold    float64       // Share of the CPU limit that starts shedding
+
+	// OnChange is called with the new state when shedding starts or stops
+	OnChange func(State)
+}
+
+// State is the latest usage sample and whether load is being shed. Limits
+// are zero when the cgroup sets none; an unlimited resource never sheds.
+type State struct {
+	Shedding    bool      `json:"shedding"`
+	Reason      string    `json:"reason,omitempty"` // Which limit is being approached
+	Since       time.Time `json:"since,omitzero"`   // When shedding started
+	Transitions int       `json:"transitions"`      // Times shedding started since startup
+
+	MemoryBytes int64   `json:"memory_bytes"`           // Working set
+	MemoryLimit int64   `json:"memory_limit,omitempty"` // Bytes
+	MemoryUsage float64 `json:"memory_usage,omitempty"` // Share of the limit
+	CPUCores    float64 `json:"cpu_cores"`              // Over the last interval
+	CPULimit    float64 `json:"cpu_limit,omitempty"`    // Cores
+	CPUUsage    float64 `json:"cpu_usage,omitempty"`    // Share of the limit
+
+	MemoryThreshold float64   `json:"memory_threshold"`
+	CPUThreshold    float64   `json:"cpu_threshold"`
+	SampledAt       time.Time `json:"sampled_at,omitzero"`
+	Error           string    `json:"error,omitempty"` // Why the cgroup couldn't be read
+}
+
+// Shedder samples the cgroup and tracks whether load is being shed
+type Shedder struct {
+	config Config
+	read   func() (usage, error)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	state   State
+	last    usage
+	lastAt  time.Time
+	sampled bool
+}
+
+// NewShedder creates a shedder for the cgroup at config.Root
+func NewShedder(config Config) *Shedder {
+	if config.Root == "" {
+		config.Root = DefaultCgroupRoot
+	}
+	if config.Interval <= 0 {
+		config.Interval = 15 * time.Second
+	}
+	if config.MemoryThreshold <= 0 {
+		config.MemoryThreshold = 0.85
+	}
+	if config.CPUThreshold <= 0 {
+		config.CPUThreshold = 0.9
+	}
+	root := config.Root
+	return &Shedder{
+		config: config,
+		read:   func() (usage, error) { return readCgroup(root) },
+		now:    time.Now,
+		state:  State{MemoryThreshold: config.MemoryThreshold, CPUThreshold: config.CPUThreshold},
+	}
+}
+
+// Run samples on every interval until ctx is cancelled
+func (s *Shedder) Run(ctx context.Context) {
+	if state := s.Sample(); state.Error != "" {
+		klog.Warningf("Load shedding can't read the cgroup, so KubePulse won't shed load: %s", state.Error)
+	} else if state.MemoryLimit == 0 && state.CPULimit == 0 {
+		klog.Info("No memory or CPU limit set, so KubePulse won't shed load")
+	}
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Sample()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Sample reads the cgroup once and starts or stops shedding. Shedding
+// starts when either resource reaches its threshold and stops once both
+// are back below recoveryFactor of theirs. A failed read keeps the state.
+func (s *Shedder) Sample() State {
+	u, err := s.read()
+	now := s.now()
+
+	s.mu.Lock()
+	if err != nil {
+		s.state.Error = err.Error()
+		state := s.state
+		s.mu.Unlock()
+		return state
+	}
+	s.state.Error = ""
+	s.state.SampledAt = now
+	s.state.MemoryBytes, s.state.MemoryLimit, s.state.MemoryUsage = u.memory, u.memoryLimit, 0
+	if u.memoryLimit > 0 {
+		s.state.MemoryUsage = float64(u.memory) / float64(u.memoryLimit)
+	}
+	s.state.CPULimit = u.cpuLimit
+	if s.sampled && now.After(s.lastAt) && u.cpu >= s.last.cpu {
+		s.state.CPUCores = (u.cpu - s.last.cpu).Seconds() / now.Sub(s.lastAt).Seconds()
+	}
+	s.state.CPUUsage = 0
+	if u.cpuLimit > 0 {
+		s.state.CPUUsage = s.state.CPUCores / u.cpuLimit
+	}
+	s.last, s.lastAt, s.sampled = u, now, true
+
+	changed := false
+	switch reason := s.pressure(1); {
+	case !s.state.Shedding && reason != "":
+		s.state.Shedding, s.state.Reason, s.state.Since = true, reason, now
+		s.state.Transitions++
+		changed = true
+	case s.state.Shedding && s.pressure(recoveryFactor) == "":
+		s.state.Shedding, s.state.Reason, s.state.Since = false, "", time.Time{}
+		changed = true
+	case s.state.Shedding && reason != "":
+		s.state.Reason = reason
+	}
+	state := s.state
+	s.mu.Unlock()
+
+	if changed {
+		if state.Shedding {
+			klog.Warningf("Shedding load: %s", state.Reason)
+		} else {
+			klog.Info("Stopped shedding load: memory and CPU are back below their thresholds")
+		}
+		if s.config.OnChange != nil {
+			s.config.OnChange(state)
+		}
+	}
+	return state
+}
+
+// pressure describes the first resource at or above factor times its
+// threshold, or returns an empty string; callers hold s.mu
+func (s *Shedder) pressure(factor float64) string {
+	switch {
+	case s.state.MemoryLimit > 0 && s.state.MemoryUsage >= s.config.MemoryThreshold*factor:
+		return fmt.Sprintf("memory at %.0f%% of the %s limit", s.state.MemoryUsage*100, mebibytes(s.state.MemoryLimit))
+	case s.state.CPULimit > 0 && s.state.CPUUsage >= s.config.CPUThreshold*factor:
+		return fmt.Sprintf("CPU at %.0f%% of the %.2g-core limit", s.state.CPUUsage*100, s.state.CPULimit)
+	}
+	return ""
+}
+
+// State returns the latest sample
+func (s *Shedder) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Shedding reports whether load is being shed
+func (s *Shedder) Shedding() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Shedding
+}
+
+// mebibytes formats a byte count such as "512Mi"
+func mebibytes(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes>>20)
+}
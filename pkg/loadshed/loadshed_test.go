@@ -0,0 +1,146 @@
+package loadshed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles writes files relative to root
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadCgroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  usage
+	}{
+		{
+			name: "v2 with limits",
+			files: map[string]string{
+				"cgroup.controllers": "cpu memory",
+				"memory.current":     "600\n",
+				"memory.stat":        "anon 400\ninactive_file 100\n",
+				"memory.max":         "1000\n",
+				"cpu.stat":           "usage_usec 2000000\nuser_usec 1500000\n",
+				"cpu.max":            "50000 100000\n",
+			},
+			want: usage{memory: 500, memoryLimit: 1000, cpu: 2 * time.Second, cpuLimit: 0.5},
+		},
+		{
+			name: "v2 unlimited",
+			files: map[string]string{
+				"cgroup.controllers": "cpu memory",
+				"memory.current":     "600\n",
+				"memory.stat":        "inactive_file 100\n",
+				"memory.max":         "max\n",
+				"cpu.stat":           "usage_usec 0\n",
+				"cpu.max":            "max 100000\n",
+			},
+			want: usage{memory: 500},
+		},
+		{
+			name: "v1 with limits",
+			files: map[string]string{
+				"memory/memory.usage_in_bytes": "800\n",
+				"memory/memory.stat":           "total_inactive_file 200\n",
+				"memory/memory.limit_in_bytes": "2000\n",
+				"cpuacct/cpuacct.usage":        "3000000000\n",
+				"cpu/cpu.cfs_quota_us":         "200000\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: usage{memory: 600, memoryLimit: 2000, cpu: 3 * time.Second, cpuLimit: 2},
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{
+				"memory/memory.usage_in_bytes": "800\n",
+				"memory/memory.stat":           "total_inactive_file 200\n",
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+				"cpu/cpu.cfs_quota_us":         "-1\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: usage{memory: 600},
+		},
+	}
+	for _, tt := range tests {
+		root := t.TempDir()
+		writeFiles(t, root, tt.files)
+		got, err := readCgroup(root)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: readCgroup() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := readCgroup(t.TempDir()); err == nil {
+		t.Error("expected an error without a cgroup filesystem")
+	}
+}
+
+func TestShedder_Sample(t *testing.T) {
+	var changes []State
+	shedder := NewShedder(Config{
+		MemoryThreshold: 0.8,
+		CPUThreshold:    0.9,
+		OnChange:        func(state State) { changes = append(changes, state) },
+	})
+	now := time.Now()
+	var current usage
+	shedder.read = func() (usage, error) { return current, nil }
+	shedder.now = func() time.Time { return now }
+
+	steps := []struct {
+		name     string
+		memory   int64
+		cpu      time.Duration // Used in the 10s since the previous step
+		shedding bool
+	}{
+		{"below the thresholds", 500, 0, false},
+		{"memory at its threshold", 800, 5 * time.Second, true},
+		{"memory within the recovery margin", 750, 5 * time.Second, true},
+		{"memory recovered", 700, 5 * time.Second, false},
+		{"CPU at its threshold", 500, 19 * time.Second, true},
+		{"CPU recovered", 500, 10 * time.Second, false},
+	}
+	for _, step := range steps {
+		now = now.Add(10 * time.Second)
+		current = usage{memory: step.memory, memoryLimit: 1000, cpu: current.cpu + step.cpu, cpuLimit: 2}
+		state := shedder.Sample()
+		if state.Shedding != step.shedding {
+			t.Errorf("%s: expected shedding %v, got %+v", step.name, step.shedding, state)
+		}
+		if state.Shedding && state.Reason == "" {
+			t.Errorf("%s: expected a reason", step.name)
+		}
+	}
+	if len(changes) != 4 || shedder.State().Transitions != 2 {
+		t.Errorf("expected 4 changes over 2 transitions, got %d changes and %+v", len(changes), shedder.State())
+	}
+	if state := shedder.State(); state.CPUCores != 1 || state.CPUUsage != 0.5 {
+		t.Errorf("expected 1 of 2 cores used, got %+v", state)
+	}
+}
+
+func TestShedder_Unlimited(t *testing.T) {
+	shedder := NewShedder(Config{})
+	shedder.read = func() (usage, error) { return usage{memory: 1 << 40, cpu: time.Hour}, nil }
+	if state := shedder.Sample(); state.Shedding || state.MemoryUsage != 0 {
+		t.Errorf("expected no shedding without limits, got %+v", state)
+	}
+}
@@ -2,23 +2,29 @@ package ml
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"sync"
 	"time"
 )
 
+// explanationPoints caps the history an anomaly explanation carries
+const explanationPoints = 30
+
 // AnomalyDetector implements basic anomaly detection using statistical methods
 type AnomalyDetector struct {
 	baselines map[string]*Baseline
 	window    time.Duration
 	threshold float64
+	mu        sync.Mutex
 }
 
 // Baseline represents learned normal behavior
 type Baseline struct {
-	Mean   float64
-	StdDev float64
-	Count  int
-	Window []float64
+	Mean   float64   `json:"mean"`
+	StdDev float64   `json:"std_dev"`
+	Count  int       `json:"count"`
+	Window []float64 `json:"window"`
 }
 
 // NewAnomalyDetector creates a new anomaly detector
@@ -32,15 +38,19 @@ func NewAnomalyDetector() *AnomalyDetector {
 
 // DetectAnomalies analyzes metrics for anomalies
 func (a *AnomalyDetector) DetectAnomalies(ctx context.Context, metrics []Metric) []Prediction {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	predictions := make([]Prediction, 0)
 
 	for _, metric := range metrics {
-		if a.isAnomalous(metric) {
+		if explanation := a.explainAnomaly(metric); explanation != nil {
 			prediction := Prediction{
 				Timestamp:   time.Now().Add(time.Hour),
 				Status:      "degraded",
-				Probability: a.calculateProbability(metric),
-				Reason:      "Statistical anomaly detected",
+				Probability: math.Min(math.Abs(explanation.ZScore)/10.0, 1.0),
+				Reason:      explanation.String(),
+				Explanation: explanation,
 			}
 			predictions = append(predictions, prediction)
 		}
@@ -49,24 +59,48 @@ func (a *AnomalyDetector) DetectAnomalies(ctx context.Context, metrics []Metric)
 	return predictions
 }
 
-// isAnomalous checks if a metric value is anomalous
-func (a *AnomalyDetector) isAnomalous(metric Metric) bool {
+// explainAnomaly checks a metric value against its baseline, returning the
+// evidence when it is anomalous and nil otherwise. The baseline learns the
+// value either way.
+func (a *AnomalyDetector) explainAnomaly(metric Metric) *AnomalyExplanation {
 	baseline := a.getOrCreateBaseline(metric.Name)
+	defer a.updateBaseline(baseline, metric.Value)
 
 	if baseline.Count < 10 {
 		// Not enough data for anomaly detection
-		a.updateBaseline(baseline, metric.Value)
-		return false
+		return nil
 	}
 
-	// Calculate z-score
-	zscore := math.Abs(metric.Value-baseline.Mean) / baseline.StdDev
-	isAnomalous := zscore > a.threshold
+	zscore := (metric.Value - baseline.Mean) / baseline.StdDev
+	if math.Abs(zscore) <= a.threshold {
+		return nil
+	}
 
-	// Update baseline with new value
-	a.updateBaseline(baseline, metric.Value)
+	history := baseline.Window
+	if len(history) > explanationPoints {
+		history = history[len(history)-explanationPoints:]
+	}
+	return &AnomalyExplanation{
+		Metric:    metric.Name,
+		Labels:    metric.Labels,
+		Observed:  metric.Value,
+		Baseline:  baseline.Mean,
+		StdDev:    baseline.StdDev,
+		ZScore:    zscore,
+		Threshold: a.threshold,
+		Window:    len(baseline.Window),
+		History:   append([]float64(nil), history...),
+	}
+}
 
-	return isAnomalous
+// String describes the anomaly in a sentence
+func (e *AnomalyExplanation) String() string {
+	direction := "above"
+	if e.ZScore < 0 {
+		direction = "below"
+	}
+	return fmt.Sprintf("%s is %.4g, %.1f standard deviations %s its baseline of %.4g ± %.2g over the last %d samples",
+		e.Metric, e.Observed, math.Abs(e.ZScore), direction, e.Baseline, e.StdDev, e.Window)
 }
 
 // getOrCreateBaseline gets or creates a baseline for a metric
@@ -113,12 +147,32 @@ func (a *AnomalyDetector) updateBaseline(baseline *Baseline, value float64) {
 	}
 }
 
-// calculateProbability calculates the probability of the anomaly
-func (a *AnomalyDetector) calculateProbability(metric Metric) float64 {
-	baseline := a.baselines[metric.Name]
-	zscore := math.Abs(metric.Value-baseline.Mean) / baseline.StdDev
+// Baselines returns a copy of the learned baselines, keyed by metric name
+func (a *AnomalyDetector) Baselines() map[string]Baseline {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	baselines := make(map[string]Baseline, len(a.baselines))
+	for name, baseline := range a.baselines {
+		copied := *baseline
+		copied.Window = append([]float64(nil), baseline.Window...)
+		baselines[name] = copied
+	}
+	return baselines
+}
 
-	// Convert z-score to probability (0-1)
-	probability := math.Min(zscore/10.0, 1.0)
-	return probability
+// RestoreBaselines replaces the learned baselines of the given metrics, so a
+// restarted detector doesn't have to relearn normal behavior
+func (a *AnomalyDetector) RestoreBaselines(baselines map[string]Baseline) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for name, baseline := range baselines {
+		restored := baseline
+		restored.Window = append(make([]float64, 0, 100), baseline.Window...)
+		if restored.StdDev == 0 {
+			restored.StdDev = 1
+		}
+		a.baselines[name] = &restored
+	}
 }
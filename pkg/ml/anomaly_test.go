@@ -3,6 +3,7 @@ package ml
 import (
 	"context"
 	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -102,8 +103,8 @@ func TestAnomalyDetector_DetectAnomalies_Anomalous(t *testing.T) {
 		if prediction.Probability <= 0 {
 			t.Errorf("expected positive probability, got %f", prediction.Probability)
 		}
-		if prediction.Reason != "Statistical anomaly detected" {
-			t.Errorf("expected reason 'Statistical anomaly detected', got %s", prediction.Reason)
+		if !strings.HasPrefix(prediction.Reason, "cpu is 500, ") || prediction.Explanation == nil {
+			t.Errorf("expected the reason to explain the anomaly, got %s", prediction.Reason)
 		}
 	}
 }
@@ -209,30 +210,40 @@ func TestAnomalyDetector_UpdateBaseline_ZeroStdDev(t *testing.T) {
 	}
 }
 
-func TestAnomalyDetector_CalculateProbability(t *testing.T) {
+func TestAnomalyDetector_ExplainAnomaly(t *testing.T) {
 	detector := NewAnomalyDetector()
 
 	// Set up a baseline manually
-	baseline := &Baseline{
-		Mean:   50.0,
-		StdDev: 10.0,
-		Count:  10,
-		Window: []float64{40, 45, 50, 55, 60},
+	window := make([]float64, 40)
+	for i := range window {
+		window[i] = 50.0
 	}
-	detector.baselines["test_metric"] = baseline
+	detector.baselines["test_metric"] = &Baseline{Mean: 50.0, StdDev: 10.0, Count: 40, Window: window}
 
-	metric := Metric{
-		Name:  "test_metric",
-		Value: 80.0, // 3 standard deviations away
+	predictions := detector.DetectAnomalies(context.Background(), []Metric{
+		{Name: "test_metric", Value: 20.0, Labels: map[string]string{"node": "worker-1"}}, // 3 standard deviations below
+	})
+	if len(predictions) != 1 || predictions[0].Explanation == nil {
+		t.Fatalf("expected an explained anomaly, got %+v", predictions)
 	}
 
-	probability := detector.calculateProbability(metric)
-
-	expectedZscore := 3.0 // |80 - 50| / 10
-	expectedProbability := math.Min(expectedZscore/10.0, 1.0)
-
-	if math.Abs(probability-expectedProbability) > 0.001 {
-		t.Errorf("expected probability %f, got %f", expectedProbability, probability)
+	explanation := predictions[0].Explanation
+	if explanation.Observed != 20.0 || explanation.Baseline != 50.0 || explanation.StdDev != 10.0 ||
+		math.Abs(explanation.ZScore+3.0) > 0.001 || explanation.Threshold != 2.0 || explanation.Window != 40 {
+		t.Errorf("unexpected explanation %+v", explanation)
+	}
+	if explanation.Labels["node"] != "worker-1" {
+		t.Errorf("expected the metric's labels, got %v", explanation.Labels)
+	}
+	if len(explanation.History) != explanationPoints {
+		t.Errorf("expected %d history points, got %d", explanationPoints, len(explanation.History))
+	}
+	if math.Abs(predictions[0].Probability-0.3) > 0.001 {
+		t.Errorf("expected probability 0.3 from the z-score, got %f", predictions[0].Probability)
+	}
+	want := "test_metric is 20, 3.0 standard deviations below its baseline of 50 ± 10 over the last 40 samples"
+	if predictions[0].Reason != want {
+		t.Errorf("expected reason %q, got %q", want, predictions[0].Reason)
 	}
 }
 
@@ -242,18 +253,18 @@ func TestAnomalyDetector_IsAnomalous(t *testing.T) {
 	// Build baseline with normal values
 	for i := 0; i < 12; i++ {
 		metric := Metric{Name: "test", Value: 50.0}
-		detector.isAnomalous(metric)
+		detector.explainAnomaly(metric)
 	}
 
 	// Test normal value
 	normalMetric := Metric{Name: "test", Value: 52.0}
-	if detector.isAnomalous(normalMetric) {
+	if detector.explainAnomaly(normalMetric) != nil {
 		t.Error("expected normal value to not be anomalous")
 	}
 
 	// Test anomalous value (way outside threshold)
 	anomalousMetric := Metric{Name: "test", Value: 500.0}
-	if !detector.isAnomalous(anomalousMetric) {
+	if detector.explainAnomaly(anomalousMetric) == nil {
 		t.Error("expected anomalous value to be detected")
 	}
 }
@@ -13,8 +13,23 @@ type Metric struct {
 
 // Prediction represents a predicted future state
 type Prediction struct {
-	Timestamp   time.Time `json:"timestamp"`
-	Status      string    `json:"status"`
-	Probability float64   `json:"probability"`
-	Reason      string    `json:"reason"`
+	Timestamp   time.Time           `json:"timestamp"`
+	Status      string              `json:"status"`
+	Probability float64             `json:"probability"`
+	Reason      string              `json:"reason"`
+	Explanation *AnomalyExplanation `json:"explanation,omitempty"` // Why a metric was flagged anomalous
+}
+
+// AnomalyExplanation is the evidence behind an anomaly: the observed value
+// against the baseline learned from the metric's recent samples
+type AnomalyExplanation struct {
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Observed  float64           `json:"observed"`
+	Baseline  float64           `json:"baseline"` // Mean of the window
+	StdDev    float64           `json:"std_dev"`
+	ZScore    float64           `json:"z_score"`   // Signed; positive when above the baseline
+	Threshold float64           `json:"threshold"` // Absolute z-score above which values are anomalous
+	Window    int               `json:"window"`    // Samples the baseline was learned from
+	History   []float64         `json:"history"`   // Most recent samples before the observation, oldest first, for sparklines
 }
@@ -0,0 +1,366 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ProtocolVersion is the version of the exec protocol KubePulse speaks with
+// check plugins
+const ProtocolVersion = 1
+
+const (
+	// describeTimeout bounds a plugin's answer to describe
+	describeTimeout = 10 * time.Second
+	// maxPluginOutput bounds what a plugin may print; the rest is dropped
+	maxPluginOutput = 1 << 20
+	// failuresBeforeBackoff is how many runs in a row may fail before runs
+	// are skipped
+	failuresBeforeBackoff = 3
+	// maxBackoff bounds how long a failing plugin is skipped
+	maxBackoff = 10 * time.Minute
+)
+
+// pluginNamePattern is what check names look like
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ErrPluginBackoff is returned while a plugin that kept failing is skipped
+var ErrPluginBackoff = errors.New("plugin is backing off after repeated failures")
+
+// PluginInfo is a plugin's answer to describe
+type PluginInfo struct {
+	Protocol    int              `json:"protocol"`
+	Name        string           `json:"name,omitempty"` // Defaults to the file name without its extension
+	Description string           `json:"description,omitempty"`
+	Interval    string           `json:"interval,omitempty"` // Go duration; defaults to the monitoring interval
+	Timeout     string           `json:"timeout,omitempty"`  // Go duration, at most the configured timeout
+	Criticality core.Criticality `json:"criticality,omitempty"`
+}
+
+// CheckRequest is what a plugin reads on stdin when asked to check
+type CheckRequest struct {
+	Protocol   int                    `json:"protocol"`
+	Name       string                 `json:"name"`
+	Kubeconfig string                 `json:"kubeconfig,omitempty"` // Empty for the default loading rules
+	Context    string                 `json:"context,omitempty"`    // kubeconfig context KubePulse monitors
+	Config     map[string]interface{} `json:"config,omitempty"`
+}
+
+// ExecOptions configure discovered plugins
+type ExecOptions struct {
+	Timeout    time.Duration                     // Bounds each run; defaults to 30s
+	Kubeconfig string                            // Passed to plugins in CheckRequest and KUBECONFIG
+	Context    string                            // Passed to plugins in CheckRequest
+	Config     map[string]map[string]interface{} // Configuration by plugin name
+}
+
+// ExecCheck is a health check run by an external executable. Every run
+// execs the plugin afresh, so replacing the binary takes effect on the next
+// run; a plugin that keeps failing is skipped for a growing backoff.
+type ExecCheck struct {
+	path        string
+	name        string
+	description string
+	interval    time.Duration
+	timeout     time.Duration
+	criticality core.Criticality
+	kubeconfig  string
+	context     string
+
+	mu           sync.Mutex
+	config       map[string]interface{}
+	failures     int
+	backoffUntil time.Time
+}
+
+// Discover describes every executable in a directory as a check plugin.
+// A missing directory has no plugins; plugins that don't answer describe
+// properly are left out and reported in the errors.
+func Discover(ctx context.Context, dir string, options ExecOptions) ([]*ExecCheck, []error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to read plugin directory %s: %w", dir, err)}
+	}
+
+	var checks []*ExecCheck
+	var errs []error
+	names := make(map[string]string)
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if strings.HasPrefix(entry.Name(), ".") || !executable(path) {
+			continue
+		}
+		check, err := NewExecCheck(ctx, path, options)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if other, ok := names[check.name]; ok {
+			errs = append(errs, fmt.Errorf("plugin %s: check %s is already provided by %s", path, check.name, other))
+			continue
+		}
+		names[check.name] = path
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].name < checks[j].name })
+	return checks, errs
+}
+
+// executable reports whether a path is a regular file someone may execute
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// NewExecCheck asks a plugin to describe itself and returns its check
+func NewExecCheck(ctx context.Context, path string, options ExecOptions) (*ExecCheck, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+	stdout, err := runPlugin(ctx, path, "describe", nil, options.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: describe failed: %w", path, err)
+	}
+	var info PluginInfo
+	if err := json.Unmarshal(stdout, &info); err != nil {
+		return nil, fmt.Errorf("plugin %s: describe printed invalid JSON: %w", path, err)
+	}
+	if info.Protocol != ProtocolVersion {
+		return nil, fmt.Errorf("plugin %s speaks protocol %d, want %d", path, info.Protocol, ProtocolVersion)
+	}
+
+	check := &ExecCheck{
+		path:        path,
+		name:        info.Name,
+		description: info.Description,
+		timeout:     options.Timeout,
+		criticality: info.Criticality,
+		kubeconfig:  options.Kubeconfig,
+		context:     options.Context,
+	}
+	if check.name == "" {
+		check.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if !pluginNamePattern.MatchString(check.name) {
+		return nil, fmt.Errorf("plugin %s: name %q must be lowercase letters, digits and '-'", path, check.name)
+	}
+	if check.description == "" {
+		check.description = "Check plugin " + filepath.Base(path)
+	}
+	switch check.criticality {
+	case "":
+		check.criticality = core.CriticalityMedium
+	case core.CriticalityCritical, core.CriticalityHigh, core.CriticalityMedium, core.CriticalityLow:
+	default:
+		return nil, fmt.Errorf("plugin %s: criticality must be critical, high, medium or low", path)
+	}
+	if info.Interval != "" {
+		if check.interval, err = time.ParseDuration(info.Interval); err != nil || check.interval <= 0 {
+			return nil, fmt.Errorf("plugin %s: invalid interval %q", path, info.Interval)
+		}
+	}
+	if info.Timeout != "" {
+		timeout, err := time.ParseDuration(info.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("plugin %s: invalid timeout %q", path, info.Timeout)
+		}
+		if timeout < check.timeout {
+			check.timeout = timeout
+		}
+	}
+	if config, ok := options.Config[check.name]; ok {
+		check.config = config
+	}
+	return check, nil
+}
+
+// runPlugin runs a plugin command with input on stdin and returns what it
+// printed. Output past maxPluginOutput is dropped, and the plugin is killed
+// when ctx ends.
+func runPlugin(ctx context.Context, path, command string, input []byte, kubeconfig string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, path, command) // #nosec G204 - plugins are executables the operator installed
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBEPULSE_PLUGIN_PROTOCOL=%d", ProtocolVersion))
+	if kubeconfig != "" {
+		cmd.Env = append(cmd.Env, "KUBECONFIG="+kubeconfig)
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	stdout := &limitedBuffer{limit: maxPluginOutput}
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out: %w", ctx.Err())
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return stdout.Bytes(), fmt.Errorf("%w: %s", err, message)
+		}
+		return stdout.Bytes(), err
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// Name returns the plugin's check name
+func (c *ExecCheck) Name() string {
+	return c.name
+}
+
+// Description returns the plugin's description
+func (c *ExecCheck) Description() string {
+	return c.description
+}
+
+// Path returns the plugin executable
+func (c *ExecCheck) Path() string {
+	return c.path
+}
+
+// Interval returns how often the plugin asked to run, or zero for the
+// monitoring interval
+func (c *ExecCheck) Interval() time.Duration {
+	return c.interval
+}
+
+// Criticality returns the plugin's criticality
+func (c *ExecCheck) Criticality() core.Criticality {
+	return c.criticality
+}
+
+// Config returns the configuration passed to the plugin
+func (c *ExecCheck) Config() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.config
+}
+
+// Configure replaces the configuration passed to the plugin
+func (c *ExecCheck) Configure(config map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = config
+	return nil
+}
+
+// Check runs the plugin and decodes the check result it prints. Plugins
+// reach the cluster themselves, through the kubeconfig KubePulse was given.
+func (c *ExecCheck) Check(ctx context.Context, client kubernetes.Interface) (core.CheckResult, error) {
+	start := time.Now()
+	c.mu.Lock()
+	if start.Before(c.backoffUntil) {
+		until := c.backoffUntil
+		c.mu.Unlock()
+		return core.CheckResult{}, fmt.Errorf("%w until %s", ErrPluginBackoff, until.Format(time.RFC3339))
+	}
+	request, err := json.Marshal(CheckRequest{
+		Protocol:   ProtocolVersion,
+		Name:       c.name,
+		Kubeconfig: c.kubeconfig,
+		Context:    c.context,
+		Config:     c.config,
+	})
+	c.mu.Unlock()
+	if err != nil {
+		return core.CheckResult{}, fmt.Errorf("failed to encode request for plugin %s: %w", c.name, err)
+	}
+
+	result, err := c.run(ctx, request)
+	c.observe(err)
+	if err != nil {
+		return core.CheckResult{}, err
+	}
+	result.Name = c.name
+	result.Timestamp = start
+	result.Duration = time.Since(start)
+	for i := range result.Metrics {
+		if result.Metrics[i].Timestamp.IsZero() {
+			result.Metrics[i].Timestamp = start
+		}
+	}
+	return result, nil
+}
+
+// run runs the check command and decodes its result. A plugin may exit
+// non-zero after printing a result, e.g. for an unhealthy status.
+func (c *ExecCheck) run(ctx context.Context, request []byte) (core.CheckResult, error) {
+	if !executable(c.path) {
+		return core.CheckResult{}, fmt.Errorf("plugin %s is no longer executable at %s", c.name, c.path)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	stdout, runErr := runPlugin(ctx, c.path, "check", request, c.kubeconfig)
+
+	var result core.CheckResult
+	if err := json.Unmarshal(bytes.TrimSpace(stdout), &result); err != nil || result.Status == "" {
+		if runErr != nil {
+			return core.CheckResult{}, fmt.Errorf("plugin %s failed: %w", c.name, runErr)
+		}
+		return core.CheckResult{}, fmt.Errorf("plugin %s printed no check result", c.name)
+	}
+	switch result.Status {
+	case core.HealthStatusHealthy, core.HealthStatusDegraded, core.HealthStatusUnhealthy, core.HealthStatusUnknown:
+	default:
+		return core.CheckResult{}, fmt.Errorf("plugin %s reported unknown status %q", c.name, result.Status)
+	}
+	return result, nil
+}
+
+// observe counts failed runs, backing off after failuresBeforeBackoff in a
+// row for twice as long each time
+func (c *ExecCheck) observe(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures < failuresBeforeBackoff {
+		return
+	}
+	backoff := c.timeout << (c.failures - failuresBeforeBackoff)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	c.backoffUntil = time.Now().Add(backoff)
+	klog.Warningf("Plugin %s failed %d times in a row; skipping it for %s: %v", c.name, c.failures, backoff, err)
+}
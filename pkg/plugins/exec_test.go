@@ -0,0 +1,176 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// writePlugin writes a shell script plugin answering describe and check
+func writePlugin(t *testing.T, dir, file, describe, check string) string {
+	t.Helper()
+	path := filepath.Join(dir, file)
+	script := "#!/bin/sh\ncase \"$1\" in\ndescribe)\n" + describe + "\n;;\ncheck)\n" + check + "\n;;\nesac\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "disk-pressure.sh",
+		`echo '{"protocol":1,"description":"Disk pressure","interval":"1m","criticality":"high"}'`, "")
+	writePlugin(t, dir, "certs",
+		`echo '{"protocol":1,"name":"cert-expiry"}'`, "")
+	writePlugin(t, dir, "old", `echo '{"protocol":0}'`, "")
+	writePlugin(t, dir, "broken", `exit 1`, "")
+	writePlugin(t, dir, "clash", `echo '{"protocol":1,"name":"cert-expiry"}'`, "")
+	writePlugin(t, dir, ".hidden", `echo '{"protocol":1}'`, "")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, errs := Discover(context.Background(), dir, ExecOptions{})
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	if strings.Join(names, ",") != "cert-expiry,disk-pressure" {
+		t.Errorf("discovered %v, want cert-expiry and disk-pressure", names)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected errors for old, broken and clash, got %v", errs)
+	}
+
+	disk := checks[1]
+	if disk.Description() != "Disk pressure" || disk.Interval() != time.Minute || disk.Criticality() != core.CriticalityHigh {
+		t.Errorf("unexpected disk-pressure plugin %+v", disk)
+	}
+	if checks[0].Criticality() != core.CriticalityMedium {
+		t.Errorf("default criticality = %s, want medium", checks[0].Criticality())
+	}
+
+	if checks, errs := Discover(context.Background(), filepath.Join(dir, "missing"), ExecOptions{}); len(checks) != 0 || len(errs) != 0 {
+		t.Errorf("expected a missing directory to hold no plugins, got %v, %v", checks, errs)
+	}
+}
+
+func TestExecCheck_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		check      string
+		wantStatus core.HealthStatus
+		wantErr    string
+	}{
+		{
+			name:       "healthy",
+			check:      `cat > "$(dirname "$0")/request.json"; echo '{"status":"healthy","message":"ok","metrics":[{"name":"free","value":42}]}'`,
+			wantStatus: core.HealthStatusHealthy,
+		},
+		{
+			name:       "unhealthy with non-zero exit",
+			check:      `echo '{"status":"unhealthy","message":"disk full"}'; exit 2`,
+			wantStatus: core.HealthStatusUnhealthy,
+		},
+		{
+			name:    "failure without result",
+			check:   `echo "no kubeconfig" >&2; exit 1`,
+			wantErr: "no kubeconfig",
+		},
+		{
+			name:    "no result",
+			check:   `echo hello`,
+			wantErr: "printed no check result",
+		},
+		{
+			name:    "unknown status",
+			check:   `echo '{"status":"fine"}'`,
+			wantErr: `unknown status "fine"`,
+		},
+		{
+			name:    "timeout",
+			check:   `sleep 5`,
+			wantErr: "timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writePlugin(t, dir, "disk", `echo '{"protocol":1,"timeout":"500ms"}'`, tt.check)
+			check, err := NewExecCheck(context.Background(), path, ExecOptions{
+				Context: "prod",
+				Config:  map[string]map[string]interface{}{"disk": {"threshold": 90}},
+			})
+			if err != nil {
+				t.Fatalf("NewExecCheck() error = %v", err)
+			}
+
+			result, err := check.Check(context.Background(), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if result.Status != tt.wantStatus || result.Name != "disk" || result.Timestamp.IsZero() {
+				t.Errorf("unexpected result %+v", result)
+			}
+			if request, err := os.ReadFile(filepath.Join(dir, "request.json")); err == nil {
+				if want := `{"protocol":1,"name":"disk","context":"prod","config":{"threshold":90}}`; strings.TrimSpace(string(request)) != want {
+					t.Errorf("request = %s, want %s", request, want)
+				}
+				if len(result.Metrics) != 1 || result.Metrics[0].Value != 42 {
+					t.Errorf("metrics = %+v", result.Metrics)
+				}
+			}
+		})
+	}
+}
+
+func TestExecCheck_Lifecycle(t *testing.T) {
+	dir := t.TempDir()
+	path := writePlugin(t, dir, "flaky", `echo '{"protocol":1}'`, `exit 1`)
+	check, err := NewExecCheck(context.Background(), path, ExecOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewExecCheck() error = %v", err)
+	}
+
+	// After repeated failures runs are skipped
+	for i := 0; i < failuresBeforeBackoff; i++ {
+		if _, err := check.Check(context.Background(), nil); err == nil || errors.Is(err, ErrPluginBackoff) {
+			t.Fatalf("run %d: expected the plugin to fail, got %v", i, err)
+		}
+	}
+	if _, err := check.Check(context.Background(), nil); !errors.Is(err, ErrPluginBackoff) {
+		t.Errorf("expected ErrPluginBackoff, got %v", err)
+	}
+
+	// A replaced binary takes effect on the next run
+	check.backoffUntil = time.Time{}
+	writePlugin(t, dir, "flaky", `echo '{"protocol":1}'`, `echo '{"status":"healthy"}'`)
+	if result, err := check.Check(context.Background(), nil); err != nil || result.Status != core.HealthStatusHealthy {
+		t.Errorf("expected the replaced plugin to pass, got %+v, %v", result, err)
+	}
+	if check.failures != 0 {
+		t.Errorf("failures = %d after a success, want 0", check.failures)
+	}
+
+	// A removed binary fails without running
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := check.Check(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "no longer executable") {
+		t.Errorf("expected a removed plugin to fail, got %v", err)
+	}
+}
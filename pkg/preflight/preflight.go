@@ -0,0 +1,364 @@
+// Package preflight verifies that the environment KubePulse runs in has what
+// it needs: cluster access, RBAC permissions, optional cluster add-ons, the AI
+// provider, a free listen port and reachable runbook links.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/alerts"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Status is the outcome of a single preflight check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // Works, with reduced functionality
+	StatusFail Status = "fail" // KubePulse or a built-in check won't work
+	StatusSkip Status = "skip"
+)
+
+// Categories group related checks in reports
+const (
+	CategoryCluster = "cluster"
+	CategoryRBAC    = "rbac"
+	CategoryAddons  = "addons"
+	CategoryAI      = "ai"
+	CategoryServer  = "server"
+	CategoryAlerts  = "alerts"
+)
+
+// metricsGroupVersion is the API served by metrics-server
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+// Result is the outcome of one check with a fix when it didn't pass
+type Result struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Status   Status `json:"status"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// Report is the outcome of a preflight run
+type Report struct {
+	Results   []Result  `json:"results"`
+	Passed    bool      `json:"passed"` // False when any check failed
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Config describes the environment to verify
+type Config struct {
+	Client    kubernetes.Interface
+	ClientErr error // Why Client could not be created, if it is nil
+
+	AIEnabled  bool
+	ClaudePath string // AI provider CLI; defaults to "claude"
+	AIProvider string // Name of the AI provider; the CLI when empty
+	AIEndpoint string // Address of an AI provider API, checked instead of the CLI when set
+
+	ListenAddr string // host:port the server listens on; empty skips the check
+	Serving    bool   // The server is already listening on ListenAddr
+
+	Runbooks   []string     // Runbook URLs alerts link to
+	HTTPClient *http.Client // Used to reach runbooks and AI provider APIs; defaults to a 5s timeout
+
+	// LookPath and RunCommand are replaceable for tests
+	LookPath   func(file string) (string, error)
+	RunCommand func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// Permission is an RBAC permission needed by a built-in check or feature
+type Permission struct {
+	Check     string `json:"check,omitempty"` // Check or feature that needs it
+	Verb      string `json:"verb"`
+	Group     string `json:"group,omitempty"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"` // Empty is cluster-wide
+}
+
+// Permissions lists the cluster-wide access each built-in check needs
+var Permissions = []Permission{
+	{Check: "pod-health", Verb: "list", Resource: "namespaces"},
+	{Check: "pod-health", Verb: "list", Resource: "pods"},
+	{Check: "pod-health", Verb: "get", Resource: "pods/log"},
+	{Check: "pod-health", Verb: "list", Resource: "nodes"},
+	{Check: "node-health", Verb: "list", Resource: "nodes"},
+	{Check: "node-health", Verb: "list", Resource: "events"},
+	{Check: "service-health", Verb: "list", Resource: "namespaces"},
+	{Check: "service-health", Verb: "list", Resource: "services"},
+	{Check: "service-health", Verb: "get", Resource: "endpoints"},
+	{Check: "event-rates", Verb: "list", Resource: "events"},
+	{Check: "ingress-health", Verb: "list", Resource: "events"},
+	{Check: "ingress-health", Verb: "list", Group: "apps", Resource: "deployments"},
+	{Check: "ingress-health", Verb: "list", Group: "apps", Resource: "daemonsets"},
+	{Check: "ingress-health", Verb: "list", Group: "gateway.networking.k8s.io", Resource: "gatewayclasses"},
+	{Check: "ingress-health", Verb: "list", Group: "gateway.networking.k8s.io", Resource: "gateways"},
+	{Check: "ingress-health", Verb: "list", Group: "gateway.networking.k8s.io", Resource: "httproutes"},
+	{Check: "service-mesh", Verb: "get", Resource: "namespaces"},
+	{Check: "service-mesh", Verb: "list", Resource: "namespaces"},
+	{Check: "service-mesh", Verb: "list", Resource: "pods"},
+	{Check: "service-mesh", Verb: "list", Group: "apps", Resource: "deployments"},
+	{Check: "service-mesh", Verb: "list", Group: "security.istio.io", Resource: "peerauthentications"},
+	{Check: "service-mesh", Verb: "list", Group: "networking.istio.io", Resource: "destinationrules"},
+	{Check: "pod-security", Verb: "get", Resource: "namespaces"},
+	{Check: "pod-security", Verb: "list", Resource: "namespaces"},
+	{Check: "pod-security", Verb: "list", Resource: "pods"},
+	{Check: "node-versions", Verb: "list", Resource: "nodes"},
+	{Check: "node-versions", Verb: "list", Resource: "events"},
+}
+
+// Run executes all preflight checks
+func Run(ctx context.Context, config Config) Report {
+	if config.ClaudePath == "" {
+		config.ClaudePath = "claude"
+	}
+	if config.LookPath == nil {
+		config.LookPath = exec.LookPath
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	if config.RunCommand == nil {
+		config.RunCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return exec.CommandContext(ctx, name, args...).CombinedOutput() // #nosec G204 - fixed arguments
+		}
+	}
+
+	results := []Result{checkCluster(ctx, config)}
+	if results[0].Status == StatusPass {
+		results = append(results, checkRBAC(ctx, config.Client)...)
+		results = append(results, checkMetricsServer(config.Client))
+	}
+	results = append(results, checkAIProvider(ctx, config), checkPort(config))
+	results = append(results, CheckRunbooks(ctx, config.Runbooks, config.HTTPClient)...)
+
+	report := Report{Results: results, Passed: true, CheckedAt: time.Now()}
+	for _, result := range results {
+		if result.Status == StatusFail {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+// CheckRunbooks verifies that each runbook link loads. An unreachable
+// runbook is a warning: alerts still fire, but without a working next step.
+func CheckRunbooks(ctx context.Context, links []string, client *http.Client) []Result {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	results := make([]Result, len(links))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		go func(i int, link string) {
+			defer wg.Done()
+			result := Result{Name: "runbook " + link, Category: CategoryAlerts, Status: StatusPass, Message: "Reachable"}
+			if err := alerts.CheckRunbook(ctx, client, link); err != nil {
+				result.Status = StatusWarn
+				result.Message = err.Error()
+				result.Fix = "Fix or replace the runbook URL so on-call engineers have a next step"
+			}
+			results[i] = result
+		}(i, link)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkCluster verifies the kubeconfig resolves to a reachable API server
+func checkCluster(ctx context.Context, config Config) Result {
+	result := Result{Name: "kubeconfig", Category: CategoryCluster}
+
+	if config.Client == nil {
+		result.Status = StatusFail
+		result.Message = "No Kubernetes client could be created"
+		if config.ClientErr != nil {
+			result.Message = fmt.Sprintf("No Kubernetes client could be created: %v", config.ClientErr)
+		}
+		result.Fix = "Point --kubeconfig or KUBECONFIG at a valid kubeconfig, select a context with --context, or run in-cluster with a ServiceAccount"
+		return result
+	}
+
+	info, err := config.Client.Discovery().ServerVersion()
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("API server unreachable: %v", err)
+		result.Fix = "Check network access to the cluster and that your credentials haven't expired (kubectl cluster-info)"
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Connected to Kubernetes %s", info.GitVersion)
+	return result
+}
+
+// checkRBAC verifies each built-in check's permissions with SelfSubjectAccessReviews
+func checkRBAC(ctx context.Context, client kubernetes.Interface) []Result {
+	results := make([]Result, 0, len(Permissions))
+	for _, perm := range Permissions {
+		result := Result{
+			Name:     fmt.Sprintf("%s: %s %s", perm.Check, perm.Verb, perm.Resource),
+			Category: CategoryRBAC,
+		}
+
+		resource, subresource, _ := strings.Cut(perm.Resource, "/")
+
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:        perm.Verb,
+					Group:       perm.Group,
+					Resource:    resource,
+					Subresource: subresource,
+				},
+			},
+		}
+		response, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		switch {
+		case err != nil:
+			result.Status = StatusWarn
+			result.Message = fmt.Sprintf("Could not verify permission: %v", err)
+			result.Fix = "Allow create on selfsubjectaccessreviews.authorization.k8s.io so permissions can be verified"
+		case response.Status.Allowed:
+			result.Status = StatusPass
+			result.Message = "Allowed cluster-wide"
+		default:
+			result.Status = StatusFail
+			result.Message = fmt.Sprintf("Denied; %s will fail", perm.Check)
+			if response.Status.Reason != "" {
+				result.Message += ": " + response.Status.Reason
+			}
+			result.Fix = fmt.Sprintf("Grant %q on %q in a ClusterRole bound to this identity (see the kubepulse ClusterRole in deploy/kubernetes/base/deployment.yaml)", perm.Verb, perm.Resource)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkMetricsServer reports whether resource usage metrics are available
+func checkMetricsServer(client kubernetes.Interface) Result {
+	result := Result{Name: "metrics-server", Category: CategoryAddons}
+
+	if _, err := client.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion); err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s is not available; node and pod resource usage won't be reported", metricsGroupVersion)
+		result.Fix = "Install metrics-server: kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml"
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s is available", metricsGroupVersion)
+	return result
+}
+
+// checkAIProvider verifies the AI provider CLI is installed and runs, or
+// that the API of another provider answers
+func checkAIProvider(ctx context.Context, config Config) Result {
+	result := Result{Name: "ai-provider", Category: CategoryAI}
+
+	if !config.AIEnabled {
+		result.Status = StatusSkip
+		result.Message = "AI features are disabled"
+		return result
+	}
+	if config.AIEndpoint != "" {
+		return checkAIEndpoint(ctx, config, result)
+	}
+
+	path, err := config.LookPath(config.ClaudePath)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s not found in PATH; AI diagnostics will be unavailable", config.ClaudePath)
+		result.Fix = "Install the Claude Code CLI (npm install -g @anthropic-ai/claude-code) or disable AI features"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	output, err := config.RunCommand(ctx, path, "--version")
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s failed to run: %v", path, err)
+		result.Fix = "Run the CLI manually to finish setup and sign in"
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s (%s)", path, trimOutput(output))
+	return result
+}
+
+// checkAIEndpoint verifies the API of an AI provider answers; any HTTP
+// status will do, as the root of most APIs isn't a route
+func checkAIEndpoint(ctx context.Context, config Config, result Result) Result {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.AIEndpoint, nil)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("invalid %s address %s: %v", config.AIProvider, config.AIEndpoint, err)
+		result.Fix = "Set ai.base_url to the provider's API address"
+		return result
+	}
+	resp, err := config.HTTPClient.Do(req)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s API at %s unreachable; AI diagnostics will be unavailable: %v", config.AIProvider, config.AIEndpoint, err)
+		result.Fix = "Check ai.base_url and that the model server is running and reachable from KubePulse"
+		return result
+	}
+	_ = resp.Body.Close()
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s API at %s", config.AIProvider, config.AIEndpoint)
+	return result
+}
+
+// checkPort verifies the server can listen on its configured address
+func checkPort(config Config) Result {
+	result := Result{Name: "listen-port", Category: CategoryServer}
+
+	switch {
+	case config.ListenAddr == "":
+		result.Status = StatusSkip
+		result.Message = "No listen address configured"
+		return result
+	case config.Serving:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Serving on %s", config.ListenAddr)
+		return result
+	}
+
+	listener, err := net.Listen("tcp", config.ListenAddr)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Cannot listen on %s: %v", config.ListenAddr, err)
+		result.Fix = "Stop the process using the port or choose another with --port or server.port"
+		return result
+	}
+	_ = listener.Close()
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s is available", config.ListenAddr)
+	return result
+}
+
+// trimOutput returns the first line of command output
+func trimOutput(output []byte) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,199 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newClient returns a fake client that denies the given resources
+func newClient(denied ...string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		for _, resource := range denied {
+			if review.Spec.ResourceAttributes.Resource == resource {
+				review.Status.Allowed = false
+				review.Status.Reason = "no RBAC policy matched"
+			}
+		}
+		return true, review, nil
+	})
+	return client
+}
+
+func resultsByName(report Report) map[string]Result {
+	byName := make(map[string]Result)
+	for _, result := range report.Results {
+		byName[result.Name] = result
+	}
+	return byName
+}
+
+func TestRun(t *testing.T) {
+	lookPathFound := func(file string) (string, error) { return "/usr/local/bin/" + file, nil }
+	lookPathMissing := func(file string) (string, error) { return "", errors.New("not found") }
+	runOK := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("1.0.0 (Claude Code)\n"), nil
+	}
+
+	tests := []struct {
+		name       string
+		config     func() Config
+		wantPassed bool
+		want       map[string]Status
+	}{
+		{
+			name: "ready",
+			config: func() Config {
+				client := newClient()
+				client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+					{GroupVersion: metricsGroupVersion},
+				}
+				return Config{Client: client, AIEnabled: true, LookPath: lookPathFound, RunCommand: runOK}
+			},
+			wantPassed: true,
+			want: map[string]Status{
+				"kubeconfig":               StatusPass,
+				"pod-health: get pods/log": StatusPass,
+				"metrics-server":           StatusPass,
+				"ai-provider":              StatusPass,
+				"listen-port":              StatusSkip,
+			},
+		},
+		{
+			name: "missing permission and optional components",
+			config: func() Config {
+				return Config{Client: newClient("events"), AIEnabled: true, LookPath: lookPathMissing}
+			},
+			want: map[string]Status{
+				"event-rates: list events": StatusFail,
+				"node-health: list nodes":  StatusPass,
+				"metrics-server":           StatusWarn,
+				"ai-provider":              StatusWarn,
+			},
+		},
+		{
+			name: "no cluster access",
+			config: func() Config {
+				return Config{ClientErr: errors.New("no configuration has been provided")}
+			},
+			want: map[string]Status{
+				"kubeconfig":  StatusFail,
+				"ai-provider": StatusSkip,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Run(context.Background(), tt.config())
+			if report.Passed != tt.wantPassed {
+				t.Errorf("expected passed=%v, got %+v", tt.wantPassed, report.Results)
+			}
+
+			byName := resultsByName(report)
+			for name, status := range tt.want {
+				result, ok := byName[name]
+				if !ok {
+					t.Errorf("missing result %q", name)
+					continue
+				}
+				if result.Status != status {
+					t.Errorf("%s: expected %s, got %s (%s)", name, status, result.Status, result.Message)
+				}
+				if (status == StatusFail || status == StatusWarn) && result.Fix == "" {
+					t.Errorf("%s: expected a fix", name)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_SkipsClusterChecksWithoutAccess(t *testing.T) {
+	report := Run(context.Background(), Config{})
+	for _, result := range report.Results {
+		if result.Category == CategoryRBAC || result.Category == CategoryAddons {
+			t.Errorf("expected no %s checks without cluster access, got %s", result.Category, result.Name)
+		}
+	}
+}
+
+func TestCheckPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	addr := listener.Addr().String()
+
+	if result := checkPort(Config{ListenAddr: addr}); result.Status != StatusFail || result.Fix == "" {
+		t.Errorf("expected busy port to fail with a fix, got %+v", result)
+	}
+	if result := checkPort(Config{ListenAddr: addr, Serving: true}); result.Status != StatusPass {
+		t.Errorf("expected port used by the running server to pass, got %+v", result)
+	}
+	if result := checkPort(Config{ListenAddr: "127.0.0.1:0"}); result.Status != StatusPass {
+		t.Errorf("expected free port to pass, got %+v", result)
+	}
+}
+
+func TestCheckAIEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	lookPathMissing := func(file string) (string, error) { return "", errors.New("not found") }
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     Status
+	}{
+		{"answering", server.URL, StatusPass},
+		{"unreachable", closed.URL, StatusWarn},
+		{"invalid", "http://ollama:11434/\x7f", StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The CLI isn't looked for when a provider API is configured
+			result := checkAIProvider(context.Background(), Config{AIEnabled: true, AIProvider: "ollama", AIEndpoint: tt.endpoint,
+				HTTPClient: server.Client(), LookPath: lookPathMissing})
+			if result.Status != tt.want {
+				t.Errorf("expected %s, got %+v", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestCheckRunbooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pods" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	results := CheckRunbooks(context.Background(), []string{server.URL + "/pods", server.URL + "/gone"}, server.Client())
+	if len(results) != 2 {
+		t.Fatalf("expected a result per runbook, got %d", len(results))
+	}
+	if results[0].Status != StatusPass || results[0].Category != CategoryAlerts {
+		t.Errorf("expected the reachable runbook to pass, got %+v", results[0])
+	}
+	if results[1].Status != StatusWarn || results[1].Fix == "" {
+		t.Errorf("expected the missing runbook to warn with a fix, got %+v", results[1])
+	}
+}
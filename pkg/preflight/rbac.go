@@ -0,0 +1,301 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Features beyond the health checks that read the cluster
+const (
+	FeatureInventory       = "inventory"
+	FeatureChangeFeed      = "change-feed"
+	FeatureDescribe        = "describe"
+	FeatureTelemetry       = "telemetry"
+	FeatureChaos           = "chaos"
+	FeatureNamespaceHealth = "namespace-health"
+)
+
+// FeaturePermissions lists the cluster-wide access each feature beyond the
+// health checks needs
+var FeaturePermissions = []Permission{
+	{Check: FeatureInventory, Verb: "list", Resource: "nodes"},
+	{Check: FeatureInventory, Verb: "list", Resource: "namespaces"},
+	{Check: FeatureInventory, Verb: "list", Resource: "pods"},
+	{Check: FeatureInventory, Verb: "list", Resource: "services"},
+	{Check: FeatureInventory, Verb: "list", Group: "apps", Resource: "deployments"},
+	{Check: FeatureInventory, Verb: "list", Group: "apps", Resource: "daemonsets"},
+	{Check: FeatureInventory, Verb: "list", Group: "apps", Resource: "statefulsets"},
+	{Check: FeatureInventory, Verb: "list", Group: "batch", Resource: "jobs"},
+	{Check: FeatureInventory, Verb: "list", Group: "batch", Resource: "cronjobs"},
+	{Check: FeatureChangeFeed, Verb: "list", Resource: "nodes"},
+	{Check: FeatureChangeFeed, Verb: "list", Group: "apps", Resource: "replicasets"},
+	{Check: FeatureDescribe, Verb: "get", Resource: "pods"},
+	{Check: FeatureDescribe, Verb: "list", Resource: "pods"},
+	{Check: FeatureDescribe, Verb: "get", Resource: "services"},
+	{Check: FeatureDescribe, Verb: "get", Resource: "endpoints"},
+	{Check: FeatureDescribe, Verb: "get", Resource: "nodes"},
+	{Check: FeatureDescribe, Verb: "list", Resource: "events"},
+	{Check: FeatureTelemetry, Verb: "list", Resource: "nodes"},
+	{Check: FeatureTelemetry, Verb: "list", Resource: "namespaces"},
+	{Check: FeatureTelemetry, Verb: "list", Resource: "pods"},
+	{Check: FeatureNamespaceHealth, Verb: "list", Resource: "pods"},
+	{Check: FeatureNamespaceHealth, Verb: "watch", Resource: "pods"},
+	{Check: FeatureNamespaceHealth, Verb: "list", Resource: "persistentvolumeclaims"},
+	{Check: FeatureNamespaceHealth, Verb: "watch", Resource: "persistentvolumeclaims"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "podchaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "networkchaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "stresschaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "iochaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "timechaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "dnschaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "chaos-mesh.org", Resource: "httpchaos"},
+	{Check: FeatureChaos, Verb: "list", Group: "litmuschaos.io", Resource: "chaosengines"},
+}
+
+// selfReviews are the reviews every authenticated identity may create through
+// the system:basic-user ClusterRole. KubePulse uses them to verify its own
+// access, so granting them is never excessive.
+var selfReviews = map[string]bool{
+	"authorization.k8s.io/selfsubjectaccessreviews": true,
+	"authorization.k8s.io/selfsubjectrulesreviews":  true,
+	"authentication.k8s.io/selfsubjectreviews":      true,
+}
+
+// RequiredPermissions returns the permissions the given checks and features
+// need, followed by extra ones such as those of custom resource checks
+func RequiredPermissions(checks, features []string, extra ...Permission) []Permission {
+	enabled := make(map[string]bool, len(checks)+len(features))
+	for _, name := range append(checks, features...) {
+		enabled[name] = true
+	}
+
+	var required []Permission
+	for _, perm := range append(Permissions, FeaturePermissions...) {
+		if enabled[perm.Check] {
+			required = append(required, perm)
+		}
+	}
+	return append(required, extra...)
+}
+
+// RBACAudit compares the permissions KubePulse has with those its enabled
+// checks and features need
+type RBACAudit struct {
+	Namespace string       `json:"namespace"` // Where granted rules were reviewed
+	Required  []Permission `json:"required"`
+	Missing   []Permission `json:"missing"`   // Required but not granted
+	Excessive []Permission `json:"excessive"` // Granted but not required
+
+	// Incomplete is set when an authorizer couldn't list its rules, such as
+	// a webhook; permissions it grants show up as missing
+	Incomplete      bool   `json:"incomplete,omitempty"`
+	EvaluationError string `json:"evaluation_error,omitempty"`
+}
+
+// AuditRBAC reviews the rules granted to the client's identity with
+// SelfSubjectRulesReviews and compares them with the required permissions.
+// Cluster-wide permissions are reviewed in namespace, whose rules include
+// those bound cluster-wide; namespaced ones in their own namespace.
+func AuditRBAC(ctx context.Context, client kubernetes.Interface, namespace string, required []Permission) (*RBACAudit, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	audit := &RBACAudit{Namespace: namespace, Required: required, Missing: []Permission{}, Excessive: []Permission{}}
+
+	rules := make(map[string][]authorizationv1.ResourceRule)
+	review := func(namespace string) ([]authorizationv1.ResourceRule, error) {
+		if cached, ok := rules[namespace]; ok {
+			return cached, nil
+		}
+		response, err := client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+			Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to review rules in namespace %s: %w", namespace, err)
+		}
+		if response.Status.Incomplete {
+			audit.Incomplete = true
+			audit.EvaluationError = response.Status.EvaluationError
+		}
+		rules[namespace] = response.Status.ResourceRules
+		return response.Status.ResourceRules, nil
+	}
+
+	granted, err := review(namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, perm := range required {
+		scope := granted
+		if perm.Namespace != "" {
+			if scope, err = review(perm.Namespace); err != nil {
+				return nil, err
+			}
+		}
+		if !allows(scope, perm) {
+			audit.Missing = append(audit.Missing, perm)
+		}
+	}
+
+	audit.Excessive = excessive(granted, required)
+	return audit, nil
+}
+
+// allows reports whether the rules grant a permission. Rules limited to
+// resource names can't grant list, and KubePulse reads objects by any name,
+// so they never count.
+func allows(rules []authorizationv1.ResourceRule, perm Permission) bool {
+	_, subresource, _ := strings.Cut(perm.Resource, "/")
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 || !matches(rule.Verbs, perm.Verb) || !matches(rule.APIGroups, perm.Group) {
+			continue
+		}
+		for _, resource := range rule.Resources {
+			if resource == "*" || resource == perm.Resource || (subresource != "" && resource == "*/"+subresource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches reports whether a rule's values include value or the * wildcard
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// excessive returns each verb, group and resource the rules grant that no
+// required permission asks for. Wildcards are reported as granted.
+func excessive(rules []authorizationv1.ResourceRule, required []Permission) []Permission {
+	needed := make(map[Permission]bool, len(required))
+	for _, perm := range required {
+		needed[Permission{Verb: perm.Verb, Group: perm.Group, Resource: perm.Resource}] = true
+	}
+
+	seen := make(map[Permission]bool)
+	extra := []Permission{}
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				if selfReviews[group+"/"+resource] {
+					continue
+				}
+				for _, verb := range rule.Verbs {
+					perm := Permission{Verb: verb, Group: group, Resource: resource}
+					if needed[perm] || seen[perm] {
+						continue
+					}
+					seen[perm] = true
+					extra = append(extra, perm)
+				}
+			}
+		}
+	}
+
+	sort.Slice(extra, func(i, j int) bool {
+		a, b := extra[i], extra[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Verb < b.Verb
+	})
+	return extra
+}
+
+// RBACManifest returns a ClusterRole granting exactly the required
+// cluster-wide permissions, and a Role for each namespace that namespaced
+// permissions are required in
+func RBACManifest(name string, required []Permission) (*rbacv1.ClusterRole, []*rbacv1.Role) {
+	labels := map[string]string{"app": "kubepulse"}
+	byNamespace := make(map[string][]Permission)
+	for _, perm := range required {
+		byNamespace[perm.Namespace] = append(byNamespace[perm.Namespace], perm)
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Rules:      policyRules(byNamespace[""]),
+	}
+
+	namespaces := make([]string, 0, len(byNamespace))
+	for namespace := range byNamespace {
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	roles := make([]*rbacv1.Role, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		roles = append(roles, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Rules:      policyRules(byNamespace[namespace]),
+		})
+	}
+	return clusterRole, roles
+}
+
+// policyRules merges permissions into one rule per API group and set of
+// verbs, in a stable order
+func policyRules(perms []Permission) []rbacv1.PolicyRule {
+	type groupResource struct{ group, resource string }
+	verbs := make(map[groupResource]map[string]bool)
+	for _, perm := range perms {
+		key := groupResource{perm.Group, perm.Resource}
+		if verbs[key] == nil {
+			verbs[key] = make(map[string]bool)
+		}
+		verbs[key][perm.Verb] = true
+	}
+
+	type ruleKey struct{ group, verbs string }
+	resources := make(map[ruleKey][]string)
+	for key, set := range verbs {
+		list := make([]string, 0, len(set))
+		for verb := range set {
+			list = append(list, verb)
+		}
+		sort.Strings(list)
+		rule := ruleKey{key.group, strings.Join(list, ",")}
+		resources[rule] = append(resources[rule], key.resource)
+	}
+
+	keys := make([]ruleKey, 0, len(resources))
+	for key := range resources {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].verbs < keys[j].verbs
+	})
+
+	rules := make([]rbacv1.PolicyRule, 0, len(keys))
+	for _, key := range keys {
+		sort.Strings(resources[key])
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{key.group},
+			Resources: resources[key],
+			Verbs:     strings.Split(key.verbs, ","),
+		})
+	}
+	return rules
+}
@@ -0,0 +1,136 @@
+package preflight
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// rulesClient returns a fake client whose SelfSubjectRulesReviews return the
+// rules for each namespace
+func rulesClient(rules map[string][]authorizationv1.ResourceRule) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = rules[review.Spec.Namespace]
+		return true, review, nil
+	})
+	return client
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	kafka := Permission{Check: "kafka", Verb: "list", Group: "kafka.strimzi.io", Resource: "kafkas", Namespace: "streaming"}
+	required := RequiredPermissions([]string{"event-rates"}, []string{FeatureChangeFeed}, kafka)
+
+	want := []Permission{
+		{Check: "event-rates", Verb: "list", Resource: "events"},
+		{Check: FeatureChangeFeed, Verb: "list", Resource: "nodes"},
+		{Check: FeatureChangeFeed, Verb: "list", Group: "apps", Resource: "replicasets"},
+		kafka,
+	}
+	if !reflect.DeepEqual(required, want) {
+		t.Errorf("got %+v, want %+v", required, want)
+	}
+}
+
+func TestAuditRBAC(t *testing.T) {
+	required := []Permission{
+		{Check: "event-rates", Verb: "list", Resource: "events"},
+		{Check: "pod-health", Verb: "list", Resource: "pods"},
+		{Check: "pod-health", Verb: "get", Resource: "pods/log"},
+		{Check: FeatureInventory, Verb: "list", Group: "batch", Resource: "jobs"},
+		{Check: "kafka", Verb: "list", Group: "kafka.strimzi.io", Resource: "kafkas", Namespace: "streaming"},
+	}
+	client := rulesClient(map[string][]authorizationv1.ResourceRule{
+		"default": {
+			{Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectaccessreviews", "selfsubjectrulesreviews"}},
+			{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods", "events"}},
+			{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"*/log"}},
+			{Verbs: []string{"list"}, APIGroups: []string{"batch"}, Resources: []string{"jobs"}, ResourceNames: []string{"backup"}},
+			{Verbs: []string{"*"}, APIGroups: []string{""}, Resources: []string{"secrets"}},
+		},
+		"streaming": {
+			{Verbs: []string{"list"}, APIGroups: []string{"kafka.strimzi.io"}, Resources: []string{"kafkas"}},
+		},
+	})
+
+	audit, err := AuditRBAC(context.Background(), client, "", required)
+	if err != nil {
+		t.Fatalf("AuditRBAC() error = %v", err)
+	}
+	if audit.Namespace != "default" {
+		t.Errorf("expected rules reviewed in default, got %q", audit.Namespace)
+	}
+	// A rule limited to resource names doesn't grant list
+	if want := []Permission{required[3]}; !reflect.DeepEqual(audit.Missing, want) {
+		t.Errorf("got missing %+v, want %+v", audit.Missing, want)
+	}
+	wantExcessive := []Permission{
+		{Verb: "get", Resource: "*/log"},
+		{Verb: "get", Resource: "events"},
+		{Verb: "watch", Resource: "events"},
+		{Verb: "get", Resource: "pods"},
+		{Verb: "watch", Resource: "pods"},
+		{Verb: "*", Resource: "secrets"},
+	}
+	if !reflect.DeepEqual(audit.Excessive, wantExcessive) {
+		t.Errorf("got excessive %+v, want %+v", audit.Excessive, wantExcessive)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		name string
+		rule authorizationv1.ResourceRule
+		perm Permission
+		want bool
+	}{
+		{"exact", authorizationv1.ResourceRule{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+			Permission{Verb: "list", Group: "apps", Resource: "deployments"}, true},
+		{"other group", authorizationv1.ResourceRule{Verbs: []string{"list"}, APIGroups: []string{"extensions"}, Resources: []string{"deployments"}},
+			Permission{Verb: "list", Group: "apps", Resource: "deployments"}, false},
+		{"wildcards", authorizationv1.ResourceRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			Permission{Verb: "get", Resource: "pods/log"}, true},
+		{"resource without subresource", authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}},
+			Permission{Verb: "get", Resource: "pods/log"}, false},
+		{"other verb", authorizationv1.ResourceRule{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"nodes"}},
+			Permission{Verb: "list", Resource: "nodes"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allows([]authorizationv1.ResourceRule{tt.rule}, tt.perm); got != tt.want {
+				t.Errorf("allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRBACManifest(t *testing.T) {
+	clusterRole, roles := RBACManifest("kubepulse", []Permission{
+		{Check: "pod-health", Verb: "list", Resource: "pods"},
+		{Check: FeatureDescribe, Verb: "get", Resource: "pods"},
+		{Check: "pod-health", Verb: "get", Resource: "pods/log"},
+		{Check: "node-health", Verb: "list", Resource: "nodes"},
+		{Check: FeatureDescribe, Verb: "get", Resource: "nodes"},
+		{Check: FeatureInventory, Verb: "list", Group: "batch", Resource: "jobs"},
+		{Check: "kafka", Verb: "list", Group: "kafka.strimzi.io", Resource: "kafkas", Namespace: "streaming"},
+	})
+
+	wantRules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get"}},
+		{APIGroups: []string{""}, Resources: []string{"nodes", "pods"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"list"}},
+	}
+	if clusterRole.Kind != "ClusterRole" || clusterRole.Name != "kubepulse" || !reflect.DeepEqual(clusterRole.Rules, wantRules) {
+		t.Errorf("unexpected ClusterRole %+v", clusterRole)
+	}
+	if len(roles) != 1 || roles[0].Namespace != "streaming" || roles[0].Rules[0].Resources[0] != "kafkas" {
+		t.Errorf("expected a Role for kafkas in streaming, got %+v", roles)
+	}
+}
@@ -2,16 +2,32 @@ package slo
 
 import (
 	"math"
+	"sort"
 	"sync"
 	"time"
 )
 
+// maxBuckets bounds the event buckets kept per SLO; buckets span the
+// window divided by it, at least a second
+const maxBuckets = 43200
+
 // Tracker manages SLO tracking and error budget calculation
 type Tracker struct {
 	slos    map[string]SLO
 	status  map[string]*SLOStatus
 	metrics map[string][]Metric
+	events  map[string][]eventBucket // Of ratio SLIs, oldest first
 	mu      sync.RWMutex
+
+	now func() time.Time
+}
+
+// eventBucket counts a ratio SLI's events in a slice of its window
+type eventBucket struct {
+	start  time.Time
+	total  float64
+	good   float64 // availability and check SLIs
+	errors float64 // error_rate SLIs
 }
 
 // NewTracker creates a new SLO tracker
@@ -20,6 +36,8 @@ func NewTracker() *Tracker {
 		slos:    make(map[string]SLO),
 		status:  make(map[string]*SLOStatus),
 		metrics: make(map[string][]Metric),
+		events:  make(map[string][]eventBucket),
+		now:     time.Now,
 	}
 }
 
@@ -36,6 +54,9 @@ func (t *Tracker) AddSLO(slo SLO) {
 		BurnRate:     0.0,
 		IsViolated:   false,
 	}
+	if slo.Ratio() {
+		t.calculateSLOStatus(slo.Name)
+	}
 }
 
 // UpdateMetrics updates metrics for SLO calculation
@@ -43,6 +64,81 @@ func (t *Tracker) UpdateMetrics(sloName string, metrics []Metric) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	t.addMetrics(sloName, metrics)
+}
+
+// Observe feeds metrics to every SLO that selects them
+func (t *Tracker) Observe(metrics []Metric) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name, slo := range t.slos {
+		var selected []Metric
+		for _, metric := range metrics {
+			if slo.Selects(metric) {
+				selected = append(selected, metric)
+			}
+		}
+		if len(selected) > 0 {
+			t.addMetrics(name, selected)
+		}
+	}
+}
+
+// ObserveCheck feeds a check run to the SLOs whose SLI is the check's
+// results; runs that weren't unhealthy are good
+func (t *Tracker) ObserveCheck(check string, good bool, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name, slo := range t.slos {
+		if slo.Check != check {
+			continue
+		}
+		event := eventBucket{total: 1}
+		if good {
+			event.good = 1
+		}
+		t.addEvent(name, event, at)
+		t.calculateSLOStatus(name)
+	}
+}
+
+// Evaluate recalculates every SLO's status as its windows slide, even
+// without new events, and returns the statuses
+func (t *Tracker) Evaluate() map[string]*SLOStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[string]*SLOStatus, len(t.status))
+	for name := range t.slos {
+		t.calculateSLOStatus(name)
+		result[name] = t.statusCopy(name)
+	}
+	return result
+}
+
+// addMetrics records metrics for an SLO and recalculates its status; callers
+// hold mu
+func (t *Tracker) addMetrics(sloName string, metrics []Metric) {
+	if slo := t.slos[sloName]; slo.Ratio() {
+		names := slo.metricNames()
+		for _, metric := range metrics {
+			var event eventBucket
+			switch {
+			case len(names) > 0 && metric.Name == names[0]:
+				event.total = metric.Value
+			case len(names) > 1 && metric.Name == names[1] && slo.SLI == "error_rate":
+				event.errors = metric.Value
+			case len(names) > 1 && metric.Name == names[1]:
+				event.good = metric.Value
+			default:
+				continue
+			}
+			t.addEvent(sloName, event, metric.Timestamp)
+		}
+	}
+
 	t.metrics[sloName] = append(t.metrics[sloName], metrics...)
 
 	// Keep only recent metrics (within SLO window)
@@ -53,13 +149,45 @@ func (t *Tracker) UpdateMetrics(sloName string, metrics []Metric) {
 	t.calculateSLOStatus(sloName)
 }
 
+// addEvent counts an event in the bucket of its time and drops buckets
+// that left the window; callers hold mu
+func (t *Tracker) addEvent(sloName string, event eventBucket, at time.Time) {
+	window := t.slos[sloName].window()
+	width := window / maxBuckets
+	if width < time.Second {
+		width = time.Second
+	}
+	now := t.now()
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+	start := at.Truncate(width)
+
+	buckets := t.events[sloName]
+	i := sort.Search(len(buckets), func(i int) bool { return !buckets[i].start.Before(start) })
+	if i == len(buckets) || !buckets[i].start.Equal(start) {
+		buckets = append(buckets, eventBucket{})
+		copy(buckets[i+1:], buckets[i:])
+		buckets[i] = eventBucket{start: start}
+	}
+	buckets[i].total += event.total
+	buckets[i].good += event.good
+	buckets[i].errors += event.errors
+
+	cutoff := now.Add(-window)
+	drop := sort.Search(len(buckets), func(i int) bool { return buckets[i].start.After(cutoff) })
+	t.events[sloName] = buckets[drop:]
+}
+
 // GetSLOStatus returns current SLO status
 func (t *Tracker) GetSLOStatus(sloName string) (*SLOStatus, bool) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	status, exists := t.status[sloName]
-	return status, exists
+	if _, exists := t.status[sloName]; !exists {
+		return nil, false
+	}
+	return t.statusCopy(sloName), true
 }
 
 // GetAllSLOs returns all SLO statuses
@@ -68,12 +196,35 @@ func (t *Tracker) GetAllSLOs() map[string]*SLOStatus {
 	defer t.mu.RUnlock()
 
 	result := make(map[string]*SLOStatus)
-	for name, status := range t.status {
-		result[name] = status
+	for name := range t.status {
+		result[name] = t.statusCopy(name)
 	}
 	return result
 }
 
+// statusCopy returns a copy of an SLO's status that later calculations
+// leave alone; callers hold mu
+func (t *Tracker) statusCopy(sloName string) *SLOStatus {
+	status := *t.status[sloName]
+	status.BurnRates = append([]BurnRateStatus(nil), status.BurnRates...)
+	return &status
+}
+
+// SLOs returns the tracked SLO definitions by name
+func (t *Tracker) SLOs() []SLO {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	slos := make([]SLO, 0, len(t.slos))
+	for _, slo := range t.slos {
+		slos = append(slos, slo)
+	}
+	sort.Slice(slos, func(i, j int) bool {
+		return slos[i].Name < slos[j].Name
+	})
+	return slos
+}
+
 // calculateSLOStatus calculates the current SLO status
 func (t *Tracker) calculateSLOStatus(sloName string) {
 	slo, exists := t.slos[sloName]
@@ -82,6 +233,10 @@ func (t *Tracker) calculateSLOStatus(sloName string) {
 	}
 
 	status := t.status[sloName]
+	if slo.Ratio() {
+		t.calculateRatioStatus(slo, status)
+		return
+	}
 	metrics := t.metrics[sloName]
 
 	if len(metrics) == 0 {
@@ -90,12 +245,8 @@ func (t *Tracker) calculateSLOStatus(sloName string) {
 
 	// Calculate current value based on SLI type
 	switch slo.SLI {
-	case "availability":
-		status.CurrentValue = t.calculateAvailability(metrics)
 	case "latency":
 		status.CurrentValue = t.calculateLatency(metrics)
-	case "error_rate":
-		status.CurrentValue = t.calculateErrorRate(metrics)
 	default:
 		status.CurrentValue = t.calculateGeneric(metrics)
 	}
@@ -123,28 +274,79 @@ func (t *Tracker) calculateSLOStatus(sloName string) {
 	}
 }
 
-// calculateAvailability calculates availability percentage
-func (t *Tracker) calculateAvailability(metrics []Metric) float64 {
-	if len(metrics) == 0 {
-		return 100.0
+// calculateRatioStatus calculates the status of a ratio SLI from its events.
+// The error budget is the share of events the target lets fail over the
+// window; a burn rate of 1 uses it up exactly at the end of the window.
+func (t *Tracker) calculateRatioStatus(slo SLO, status *SLOStatus) {
+	now := t.now()
+	window := slo.window()
+	allowed := 1 - slo.Target/100
+	if allowed <= 0 {
+		// A 100% target has no budget; any failure exhausts it
+		allowed = 1e-9
 	}
 
-	var totalRequests, successfulRequests float64
+	total, bad := t.countEvents(slo, now.Add(-window))
+	status.Events = total
+	status.CurrentValue = 100.0
+	status.ErrorBudget = 100.0
+	if total > 0 {
+		status.CurrentValue = 100 * (1 - bad/total)
+		status.ErrorBudget = math.Max(0, 100*(1-bad/total/allowed))
+	}
+	status.IsViolated = status.CurrentValue < slo.Target
 
-	for _, metric := range metrics {
-		switch metric.Name {
-		case "request_total":
-			totalRequests += metric.Value
-		case "request_success":
-			successfulRequests += metric.Value
+	burnRate := func(span time.Duration) float64 {
+		total, bad := t.countEvents(slo, now.Add(-span))
+		if total == 0 {
+			return 0
+		}
+		return bad / total / allowed
+	}
+	alerts := slo.burnRateAlerts()
+	status.BurnRates = make([]BurnRateStatus, len(alerts))
+	for i, alert := range alerts {
+		long, short := burnRate(alert.LongWindow), burnRate(alert.ShortWindow)
+		status.BurnRates[i] = BurnRateStatus{
+			BurnRateAlert: alert,
+			LongBurnRate:  long,
+			ShortBurnRate: short,
+			Firing:        long > alert.BurnRate && short > alert.BurnRate,
 		}
 	}
 
-	if totalRequests == 0 {
-		return 100.0
+	// The burn rate is that of the fastest alert's long window, and the
+	// budget is reported exhausting when that rate would use it up within
+	// a week
+	status.BurnRate = 0
+	if len(status.BurnRates) > 0 {
+		status.BurnRate = status.BurnRates[0].LongBurnRate
 	}
+	status.TimeToExhaust = ""
+	if status.BurnRate > 0 && status.ErrorBudget > 0 {
+		left := time.Duration(status.ErrorBudget / 100 * float64(window) / status.BurnRate)
+		if left < 168*time.Hour {
+			status.TimeToExhaust = left.Round(time.Second).String()
+		}
+	}
+}
 
-	return (successfulRequests / totalRequests) * 100.0
+// countEvents sums a ratio SLI's events since a time, returning the total
+// and the failed events
+func (t *Tracker) countEvents(slo SLO, since time.Time) (total, bad float64) {
+	var good, errors float64
+	for _, bucket := range t.events[slo.Name] {
+		if bucket.start.Before(since) {
+			continue
+		}
+		total += bucket.total
+		good += bucket.good
+		errors += bucket.errors
+	}
+	if slo.SLI == "error_rate" && slo.Check == "" {
+		return total, math.Min(total, errors)
+	}
+	return total, math.Max(0, total-good)
 }
 
 // calculateLatency calculates latency percentile
@@ -165,26 +367,6 @@ func (t *Tracker) calculateLatency(metrics []Metric) float64 {
 	return t.percentile(latencies, 95)
 }
 
-// calculateErrorRate calculates error rate percentage
-func (t *Tracker) calculateErrorRate(metrics []Metric) float64 {
-	var totalRequests, errorRequests float64
-
-	for _, metric := range metrics {
-		switch metric.Name {
-		case "request_total":
-			totalRequests += metric.Value
-		case "request_errors":
-			errorRequests += metric.Value
-		}
-	}
-
-	if totalRequests == 0 {
-		return 0.0
-	}
-
-	return 100.0 - (errorRequests/totalRequests)*100.0 // Return success rate
-}
-
 // calculateGeneric calculates generic metric average
 func (t *Tracker) calculateGeneric(metrics []Metric) float64 {
 	if len(metrics) == 0 {
@@ -1,6 +1,7 @@
 package slo
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -140,3 +141,93 @@ func TestTracker_UpdateMetrics(t *testing.T) {
 		t.Error("expected non-nil status after updating metrics")
 	}
 }
+
+func TestTracker_Observe(t *testing.T) {
+	tracker := NewTracker()
+	tracker.AddSLO(SLO{Name: "checkout", SLI: "availability", Target: 99, Labels: map[string]string{"source": "checkout"}})
+	tracker.AddSLO(SLO{Name: "queue", SLI: "custom", Target: 50, Metrics: []string{"queue_ok"}})
+
+	now := time.Now()
+	tracker.Observe([]Metric{
+		{Name: "request_total", Value: 200, Labels: map[string]string{"source": "checkout"}, Timestamp: now},
+		{Name: "request_success", Value: 199, Labels: map[string]string{"source": "checkout"}, Timestamp: now},
+		{Name: "request_total", Value: 100, Labels: map[string]string{"source": "billing"}, Timestamp: now},
+		{Name: "queue_ok", Value: 80, Timestamp: now},
+	})
+
+	tests := []struct {
+		slo  string
+		want float64
+	}{
+		{"checkout", 99.5},
+		{"queue", 80},
+	}
+	for _, tt := range tests {
+		status, _ := tracker.GetSLOStatus(tt.slo)
+		if status.CurrentValue != tt.want {
+			t.Errorf("%s: expected current value %v, got %v", tt.slo, tt.want, status.CurrentValue)
+		}
+	}
+}
+
+func TestTracker_BurnRates(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+	tracker.AddSLO(SLO{Name: "web", SLI: "availability", Target: 99, Window: 30 * 24 * time.Hour, Check: "web-health"})
+	tracker.AddSLO(SLO{Name: "api", SLI: "error_rate", Target: 99.9, Window: 30 * 24 * time.Hour})
+
+	// A day of good runs, then ten minutes of failures: 10% of the runs of
+	// the last hour failed against a 1% budget
+	for at := now.Add(-24 * time.Hour); at.Before(now.Add(-10 * time.Minute)); at = at.Add(time.Minute) {
+		tracker.ObserveCheck("web-health", true, at)
+	}
+	for at := now.Add(-10 * time.Minute); !at.After(now); at = at.Add(time.Minute) {
+		tracker.ObserveCheck("web-health", false, at)
+	}
+	tracker.ObserveCheck("other-check", false, now)
+	tracker.Observe([]Metric{
+		{Name: "request_total", Value: 10000, Timestamp: now.Add(-2 * time.Hour)},
+		{Name: "request_errors", Value: 5, Timestamp: now.Add(-2 * time.Hour)},
+	})
+
+	statuses := tracker.Evaluate()
+	web := statuses["web"]
+	if web.Events != 1441 {
+		t.Errorf("web events = %v, want 1441", web.Events)
+	}
+	fast := web.BurnRates[0]
+	if fast.LongWindow != time.Hour || fast.ShortWindow != 5*time.Minute || fast.BurnRate != 14.4 {
+		t.Errorf("unexpected default fast-burn alert %+v", fast.BurnRateAlert)
+	}
+	// 11 of 60 runs in the hour failed and all 5 in the last five minutes
+	if !fast.Firing || fast.ShortBurnRate < 99 || fast.LongBurnRate < 18 {
+		t.Errorf("expected the fast-burn alert to fire, got %+v", fast)
+	}
+	if web.ErrorBudget <= 0 || web.ErrorBudget >= 100 || web.TimeToExhaust == "" {
+		t.Errorf("expected part of the budget left and burning, got %+v", web)
+	}
+
+	api := statuses["api"]
+	if api.CurrentValue != 99.95 || math.Abs(api.ErrorBudget-50) > 1e-6 {
+		t.Errorf("expected half of the api budget spent, got %+v", api)
+	}
+	for _, burn := range api.BurnRates[:2] {
+		if burn.Firing || burn.ShortBurnRate != 0 {
+			t.Errorf("expected no recent burn for api, got %+v", burn)
+		}
+	}
+
+	// Windows slide without new events: the failures age out of the short
+	// windows and the alerts resolve
+	now = now.Add(45 * time.Minute)
+	if web := tracker.Evaluate()["web"]; web.BurnRates[0].Firing || web.BurnRates[0].ShortBurnRate != 0 {
+		t.Errorf("expected the fast-burn alert to resolve, got %+v", web.BurnRates[0])
+	}
+
+	// Events older than the window are dropped
+	now = now.Add(31 * 24 * time.Hour)
+	if web := tracker.Evaluate()["web"]; web.Events != 0 || web.ErrorBudget != 100 {
+		t.Errorf("expected the window to be empty, got %+v", web)
+	}
+}
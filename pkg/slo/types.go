@@ -10,6 +10,123 @@ type SLO struct {
 	Target       float64       `json:"target"`
 	Window       time.Duration `json:"window"`
 	BudgetPolicy []BudgetRule  `json:"budget_policy"`
+
+	// Metrics names the metrics the SLI is computed from; empty selects the
+	// standard names for the SLI, e.g. request_total and request_success
+	// for availability. Labels, when set, must all match.
+	Metrics []string          `json:"metrics,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+
+	// Check makes the SLI the share of the named check's runs that weren't
+	// unhealthy, instead of metrics
+	Check string `json:"check,omitempty"`
+
+	// BurnRateAlerts fire while the error budget burns too fast; empty
+	// selects DefaultBurnRateAlerts for the window
+	BurnRateAlerts []BurnRateAlert `json:"burn_rate_alerts,omitempty"`
+}
+
+// DefaultWindow applies to SLOs without a window
+const DefaultWindow = 30 * 24 * time.Hour
+
+// Severities of burn-rate alerts
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+)
+
+// BurnRateAlert fires while the error budget burns more than BurnRate
+// times faster than the window allows, over both windows. The long window
+// makes it significant, the short one lets it resolve soon after recovery.
+type BurnRateAlert struct {
+	LongWindow  time.Duration `json:"long_window"`
+	ShortWindow time.Duration `json:"short_window"`
+	BurnRate    float64       `json:"burn_rate"`
+	Severity    string        `json:"severity"` // critical or warning
+}
+
+// BurnRateStatus is a burn-rate alert with its current burn rates
+type BurnRateStatus struct {
+	BurnRateAlert
+	LongBurnRate  float64 `json:"long_burn_rate"`
+	ShortBurnRate float64 `json:"short_burn_rate"`
+	Firing        bool    `json:"firing"`
+}
+
+// DefaultBurnRateAlerts are the multi-window alerts of the Google SRE
+// workbook, scaled from a 30-day window: pages when 2% of the budget burns
+// in an hour or 5% in six hours, and a warning when 10% burns in three days
+func DefaultBurnRateAlerts(window time.Duration) []BurnRateAlert {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return []BurnRateAlert{
+		{LongWindow: window / 720, ShortWindow: window / 8640, BurnRate: 14.4, Severity: SeverityCritical},
+		{LongWindow: window / 120, ShortWindow: window / 1440, BurnRate: 6, Severity: SeverityCritical},
+		{LongWindow: window / 10, ShortWindow: window / 120, BurnRate: 1, Severity: SeverityWarning},
+	}
+}
+
+// Ratio reports whether the SLI is a ratio of good to total events, which
+// gives the SLO an error budget measured in events and burn rates
+func (s SLO) Ratio() bool {
+	return s.Check != "" || s.SLI == "availability" || s.SLI == "error_rate"
+}
+
+// window returns the SLO's window, or DefaultWindow
+func (s SLO) window() time.Duration {
+	if s.Window <= 0 {
+		return DefaultWindow
+	}
+	return s.Window
+}
+
+// burnRateAlerts returns the SLO's burn-rate alerts, or the defaults
+func (s SLO) burnRateAlerts() []BurnRateAlert {
+	if len(s.BurnRateAlerts) > 0 {
+		return s.BurnRateAlerts
+	}
+	return DefaultBurnRateAlerts(s.window())
+}
+
+// metricNames returns the names of the metrics the SLI is computed from.
+// For availability and error_rate the first counts requests and the second
+// successes or errors.
+func (s SLO) metricNames() []string {
+	if len(s.Metrics) > 0 {
+		return s.Metrics
+	}
+	return sliMetrics[s.SLI]
+}
+
+// sliMetrics are the metric names each built-in SLI is computed from
+var sliMetrics = map[string][]string{
+	"availability": {"request_total", "request_success"},
+	"error_rate":   {"request_total", "request_errors"},
+	"latency":      {"request_duration"},
+}
+
+// Selects reports whether a metric feeds the SLO
+func (s SLO) Selects(metric Metric) bool {
+	if s.Check != "" {
+		return false
+	}
+	selected := false
+	for _, name := range s.metricNames() {
+		if name == metric.Name {
+			selected = true
+			break
+		}
+	}
+	if !selected {
+		return false
+	}
+	for key, value := range s.Labels {
+		if metric.Labels[key] != value {
+			return false
+		}
+	}
+	return true
 }
 
 // BudgetRule defines actions based on error budget consumption
@@ -26,11 +143,17 @@ type SLOStatus struct {
 	BurnRate      float64 `json:"burn_rate"`
 	IsViolated    bool    `json:"is_violated"`
 	TimeToExhaust string  `json:"time_to_exhaust,omitempty"`
+
+	// Events counts the SLI's events in the window and BurnRates holds the
+	// burn-rate alerts; only ratio SLIs have them
+	Events    float64          `json:"events,omitempty"`
+	BurnRates []BurnRateStatus `json:"burn_rates,omitempty"`
 }
 
 // Metric represents a metric for SLO calculation (local copy to avoid cycle)
 type Metric struct {
-	Name      string    `json:"name"`
-	Value     float64   `json:"value"`
-	Timestamp time.Time `json:"timestamp"`
+	Name      string            `json:"name"`
+	Value     float64           `json:"value"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
 }
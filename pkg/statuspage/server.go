@@ -0,0 +1,191 @@
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultHistoryDays is how many days of availability are shown
+	DefaultHistoryDays = 7
+
+	// pageTTL is how long a built page is served before it's rebuilt, so
+	// public traffic doesn't turn into work for the engine
+	pageTTL = 15 * time.Second
+)
+
+// Config configures the status page server
+type Config struct {
+	Host        string
+	Port        int
+	Title       string
+	Components  []Component // Empty shows one component per check
+	HistoryDays int
+	Source      Source
+}
+
+// Server serves the status page on its own listener. It only answers GET
+// requests and exposes nothing but component states and availability, so
+// it can be published to stakeholders without dashboard access.
+type Server struct {
+	source     Source
+	title      string
+	components []Component
+	days       int
+	server     *http.Server
+	tmpl       *template.Template
+
+	mu      sync.Mutex
+	page    Page
+	builtAt time.Time
+}
+
+// NewServer creates a status page server
+func NewServer(config Config) *Server {
+	if config.HistoryDays <= 0 {
+		config.HistoryDays = DefaultHistoryDays
+	}
+	if config.Title == "" {
+		config.Title = "Service Status"
+	}
+
+	s := &Server{
+		source:     config.Source,
+		title:      config.Title,
+		components: config.Components,
+		days:       config.HistoryDays,
+		tmpl:       template.Must(template.New("status").Funcs(templateFuncs).Parse(pageTemplate)),
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/", s.handlePage).Methods("GET", "HEAD")
+	router.HandleFunc("/api/v1/status", s.handleStatus).Methods("GET", "HEAD")
+
+	s.server = &http.Server{
+		Addr:         net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		Handler:      router,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	return s
+}
+
+// Handler returns the server's HTTP handler
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start serves the status page until Stop is called
+func (s *Server) Start() error {
+	klog.Infof("Starting status page on %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// Stop stops the status page server
+func (s *Server) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Page returns the current page, rebuilding it when older than pageTTL
+func (s *Server) Page() Page {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.builtAt.IsZero() || now.Sub(s.builtAt) >= pageTTL {
+		s.page = Build(s.source, s.title, s.components, s.days, now)
+		s.builtAt = now
+	}
+	return s.page
+}
+
+// handleStatus returns the page as JSON
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(pageTTL.Seconds())))
+	if err := json.NewEncoder(w).Encode(s.Page()); err != nil {
+		klog.Errorf("Failed to encode status page: %v", err)
+	}
+}
+
+// handlePage renders the page as HTML
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(pageTTL.Seconds())))
+	if err := s.tmpl.Execute(w, s.Page()); err != nil {
+		klog.Errorf("Failed to render status page: %v", err)
+	}
+}
+
+// templateFuncs format page values for the HTML template
+var templateFuncs = template.FuncMap{
+	"percent": func(value *float64) string {
+		if value == nil {
+			return "no data"
+		}
+		return fmt.Sprintf("%.2f%%", *value)
+	},
+	"label": func(state string) string {
+		switch state {
+		case StateOperational:
+			return "Operational"
+		case StateDegraded:
+			return "Degraded performance"
+		case StateOutage:
+			return "Outage"
+		}
+		return "Unknown"
+	},
+}
+
+// pageTemplate is the self-contained HTML status page
+const pageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="60">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 760px; margin: 2rem auto; padding: 0 1rem; color: #1f2933; }
+.banner { padding: 1rem; border-radius: 6px; color: #fff; font-weight: 600; }
+.component { border-bottom: 1px solid #e4e7eb; padding: 1rem 0; }
+.component header { display: flex; justify-content: space-between; }
+.description, .meta { color: #616e7c; font-size: 0.9rem; }
+.history { display: flex; gap: 2px; margin-top: 0.5rem; }
+.history span { flex: 1; height: 24px; border-radius: 2px; }
+.operational { background: #3ebd93; } .degraded { background: #f0b429; }
+.outage { background: #e12d39; } .unknown { background: #9aa5b1; }
+.state-operational { color: #199473; } .state-degraded { color: #cb6e17; }
+.state-outage { color: #ab091e; } .state-unknown { color: #616e7c; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.State}}">{{label .State}}</div>
+{{range .Components}}
+<section class="component">
+<header><strong>{{.Name}}</strong><span class="state-{{.State}}">{{label .State}}</span></header>
+{{if .Description}}<div class="description">{{.Description}}</div>{{end}}
+<div class="history">{{range .History}}<span class="{{.State}}" title="{{.Date}}: {{percent .Availability}}"></span>{{end}}</div>
+<div class="meta">Availability {{percent .Availability}}{{range .SLOs}} · {{.Name}} {{printf "%.2f" .Current}} (target {{printf "%.2f" .Target}}){{end}}</div>
+</section>
+{{end}}
+<p class="meta">Updated {{.UpdatedAt.Format "2006-01-02 15:04:05 MST"}}</p>
+</body>
+</html>
+`
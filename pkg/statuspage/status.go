@@ -0,0 +1,271 @@
+package statuspage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// Component states shown on the status page, from best to worst
+const (
+	StateOperational = "operational"
+	StateDegraded    = "degraded"
+	StateOutage      = "outage"
+	StateUnknown     = "unknown"
+)
+
+// Component groups the checks and SLOs shown as one line on the page
+type Component struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Checks      []string `json:"checks,omitempty"`
+	SLOs        []string `json:"slos,omitempty"`
+}
+
+// Source provides the current and recorded health the page is built from;
+// *core.Engine implements it
+type Source interface {
+	GetResults() map[string]core.CheckResult
+	GetSLOStatuses() map[string]*core.SLOStatus
+	CheckTimeline(name string, from, to time.Time) []core.CheckResult
+}
+
+// Page is the public status: per-component state and availability only,
+// never check messages or resource names
+type Page struct {
+	Title      string            `json:"title"`
+	State      string            `json:"state"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// ComponentStatus is a component's current state and its daily history
+type ComponentStatus struct {
+	Name         string      `json:"name"`
+	Description  string      `json:"description,omitempty"`
+	State        string      `json:"state"`
+	Availability *float64    `json:"availability,omitempty"` // Percent over the history; nil without data
+	SLOs         []SLOStatus `json:"slos,omitempty"`
+	History      []Day       `json:"history"` // Oldest first
+}
+
+// SLOStatus is an SLO's attainment against its target
+type SLOStatus struct {
+	Name    string  `json:"name"`
+	Target  float64 `json:"target"`
+	Current float64 `json:"current"`
+	Met     bool    `json:"met"`
+}
+
+// Day is a component's availability and worst state on one day
+type Day struct {
+	Date         string   `json:"date"` // YYYY-MM-DD in the server's time zone
+	State        string   `json:"state"`
+	Availability *float64 `json:"availability,omitempty"`
+}
+
+// Build computes the page at now from the source. A component is down while
+// any of its checks is unhealthy; degraded time counts as available. Without
+// components, every check is shown as its own component.
+func Build(source Source, title string, components []Component, days int, now time.Time) Page {
+	results := source.GetResults()
+	slos := source.GetSLOStatuses()
+	if len(components) == 0 {
+		components = checkComponents(results)
+	}
+
+	page := Page{
+		Title:      title,
+		State:      StateOperational,
+		UpdatedAt:  now,
+		Components: make([]ComponentStatus, 0, len(components)),
+	}
+	if len(components) == 0 {
+		page.State = StateUnknown
+	}
+	for _, component := range components {
+		status := buildComponent(source, component, results, slos, days, now)
+		page.State = worst(page.State, status.State)
+		page.Components = append(page.Components, status)
+	}
+	return page
+}
+
+// checkComponents returns one component per check, sorted by name
+func checkComponents(results map[string]core.CheckResult) []Component {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	components := make([]Component, len(names))
+	for i, name := range names {
+		components[i] = Component{Name: name, Checks: []string{name}}
+	}
+	return components
+}
+
+// buildComponent computes a component's current state and history
+func buildComponent(source Source, component Component, results map[string]core.CheckResult, slos map[string]*core.SLOStatus, days int, now time.Time) ComponentStatus {
+	status := ComponentStatus{
+		Name:        component.Name,
+		Description: component.Description,
+	}
+
+	state := ""
+	for _, name := range component.Checks {
+		result, ok := results[name]
+		if !ok {
+			state = worst(state, StateUnknown)
+			continue
+		}
+		state = worst(state, checkState(result.Status))
+	}
+	for _, name := range component.SLOs {
+		slo, ok := slos[name]
+		if !ok {
+			state = worst(state, StateUnknown)
+			continue
+		}
+		status.SLOs = append(status.SLOs, SLOStatus{
+			Name:    name,
+			Target:  slo.SLO.Target,
+			Current: slo.CurrentValue,
+			Met:     !slo.IsViolated,
+		})
+		if slo.IsViolated {
+			state = worst(state, StateDegraded)
+		} else {
+			state = worst(state, StateOperational)
+		}
+	}
+	if state == "" {
+		state = StateUnknown
+	}
+	status.State = state
+
+	// Daily history from midnight days-1 days ago up to now
+	year, month, day := now.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+	timelines := make([][]core.CheckResult, 0, len(component.Checks))
+	for _, name := range component.Checks {
+		timelines = append(timelines, source.CheckTimeline(name, start, now))
+	}
+
+	var up, observed time.Duration
+	for i := 0; i < days; i++ {
+		from := start.AddDate(0, 0, i)
+		to := from.AddDate(0, 0, 1)
+		if to.After(now) {
+			to = now
+		}
+		day := Day{Date: from.Format("2006-01-02"), State: StateUnknown}
+		dayUp, dayObserved, dayState := availability(timelines, from, to)
+		if dayObserved > 0 {
+			day.State = dayState
+			day.Availability = percent(dayUp, dayObserved)
+			up += dayUp
+			observed += dayObserved
+		}
+		status.History = append(status.History, day)
+	}
+	if observed > 0 {
+		status.Availability = percent(up, observed)
+	}
+	return status
+}
+
+// availability returns how long the checks were observed between from and
+// to, how long none of them was unhealthy, and the worst state seen
+func availability(timelines [][]core.CheckResult, from, to time.Time) (up, observed time.Duration, state string) {
+	// Every change of any check starts a new segment
+	boundaries := []time.Time{from}
+	for _, timeline := range timelines {
+		for _, result := range timeline {
+			if result.Timestamp.After(from) && result.Timestamp.Before(to) {
+				boundaries = append(boundaries, result.Timestamp)
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+	boundaries = append(boundaries, to)
+
+	for i := 0; i+1 < len(boundaries); i++ {
+		length := boundaries[i+1].Sub(boundaries[i])
+		if length <= 0 {
+			continue
+		}
+
+		segment := ""
+		for _, timeline := range timelines {
+			if result, ok := resultAt(timeline, boundaries[i]); ok {
+				segment = worst(segment, checkState(result.Status))
+			}
+		}
+		if segment == "" || segment == StateUnknown {
+			continue
+		}
+		observed += length
+		if segment != StateOutage {
+			up += length
+		}
+		state = worst(state, segment)
+	}
+	return up, observed, state
+}
+
+// resultAt returns the result in effect at t
+func resultAt(timeline []core.CheckResult, t time.Time) (core.CheckResult, bool) {
+	i := sort.Search(len(timeline), func(i int) bool {
+		return timeline[i].Timestamp.After(t)
+	}) - 1
+	if i < 0 {
+		return core.CheckResult{}, false
+	}
+	return timeline[i], true
+}
+
+// checkState maps a check's health to a component state
+func checkState(status core.HealthStatus) string {
+	switch status {
+	case core.HealthStatusHealthy:
+		return StateOperational
+	case core.HealthStatusDegraded:
+		return StateDegraded
+	case core.HealthStatusUnhealthy:
+		return StateOutage
+	}
+	return StateUnknown
+}
+
+// worst returns the worse of two states; empty means not yet known
+func worst(a, b string) string {
+	if stateRank(b) > stateRank(a) {
+		return b
+	}
+	return a
+}
+
+// stateRank orders states by severity. Unknown ranks above operational so
+// a component with missing data isn't shown as fine.
+func stateRank(state string) int {
+	switch state {
+	case StateOperational:
+		return 1
+	case StateUnknown:
+		return 2
+	case StateDegraded:
+		return 3
+	case StateOutage:
+		return 4
+	}
+	return 0
+}
+
+// percent returns part as a percentage of total
+func percent(part, total time.Duration) *float64 {
+	value := float64(part) / float64(total) * 100
+	return &value
+}
@@ -0,0 +1,147 @@
+package statuspage
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+)
+
+// fakeSource serves fixed results, SLOs and timelines
+type fakeSource struct {
+	results   map[string]core.CheckResult
+	slos      map[string]*core.SLOStatus
+	timelines map[string][]core.CheckResult
+}
+
+func (f *fakeSource) GetResults() map[string]core.CheckResult { return f.results }
+
+func (f *fakeSource) GetSLOStatuses() map[string]*core.SLOStatus { return f.slos }
+
+func (f *fakeSource) CheckTimeline(name string, from, to time.Time) []core.CheckResult {
+	return f.timelines[name]
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	midnight := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	source := &fakeSource{
+		results: map[string]core.CheckResult{
+			"pod-health":     {Name: "pod-health", Status: core.HealthStatusHealthy, Message: "secret-pod crashlooping"},
+			"service-health": {Name: "service-health", Status: core.HealthStatusDegraded},
+		},
+		slos: map[string]*core.SLOStatus{
+			"api-availability": {SLO: core.SLO{Target: 99.9}, CurrentValue: 99.5, IsViolated: true},
+		},
+		timelines: map[string][]core.CheckResult{
+			// Healthy all of yesterday, then down for the first 3 of today's 12 hours
+			"pod-health": {
+				{Name: "pod-health", Status: core.HealthStatusHealthy, Timestamp: midnight.Add(-24 * time.Hour)},
+				{Name: "pod-health", Status: core.HealthStatusUnhealthy, Timestamp: midnight},
+				{Name: "pod-health", Status: core.HealthStatusHealthy, Timestamp: midnight.Add(3 * time.Hour)},
+			},
+		},
+	}
+	components := []Component{
+		{Name: "Checkout", Checks: []string{"pod-health"}},
+		{Name: "API", Checks: []string{"service-health"}, SLOs: []string{"api-availability"}},
+		{Name: "Search", Checks: []string{"search-health"}},
+	}
+
+	page := Build(source, "Status", components, 3, now)
+	if page.State != StateDegraded {
+		t.Errorf("page state = %s, want degraded", page.State)
+	}
+
+	checkout := page.Components[0]
+	if checkout.State != StateOperational {
+		t.Errorf("checkout state = %s, want operational", checkout.State)
+	}
+	if len(checkout.History) != 3 {
+		t.Fatalf("expected 3 days of history, got %d", len(checkout.History))
+	}
+	if day := checkout.History[0]; day.State != StateUnknown || day.Availability != nil {
+		t.Errorf("expected no data two days ago, got %+v", day)
+	}
+	if day := checkout.History[1]; day.Date != "2026-10-15" || day.State != StateOperational || *day.Availability != 100 {
+		t.Errorf("expected yesterday fully available, got %+v", day)
+	}
+	if day := checkout.History[2]; day.State != StateOutage || *day.Availability != 75 {
+		t.Errorf("expected today 75%% available with an outage, got %+v", day)
+	}
+	// 3 hours down out of 36 observed
+	if want := 100 * 33.0 / 36.0; checkout.Availability == nil || math.Abs(*checkout.Availability-want) > 0.001 {
+		t.Errorf("checkout availability = %v, want %.3f", checkout.Availability, want)
+	}
+
+	api := page.Components[1]
+	if api.State != StateDegraded || len(api.SLOs) != 1 || api.SLOs[0].Met {
+		t.Errorf("expected API degraded with a missed SLO, got %+v", api)
+	}
+	if api.Availability != nil {
+		t.Errorf("expected no availability without history, got %v", *api.Availability)
+	}
+
+	if search := page.Components[2]; search.State != StateUnknown {
+		t.Errorf("expected a component without results to be unknown, got %s", search.State)
+	}
+}
+
+func TestBuild_DefaultComponents(t *testing.T) {
+	source := &fakeSource{
+		results: map[string]core.CheckResult{
+			"service-health": {Name: "service-health", Status: core.HealthStatusUnhealthy},
+			"node-health":    {Name: "node-health", Status: core.HealthStatusHealthy},
+		},
+	}
+
+	page := Build(source, "Status", nil, 1, time.Now())
+	if len(page.Components) != 2 || page.Components[0].Name != "node-health" || page.Components[1].Name != "service-health" {
+		t.Fatalf("expected one component per check sorted by name, got %+v", page.Components)
+	}
+	if page.State != StateOutage {
+		t.Errorf("page state = %s, want outage", page.State)
+	}
+}
+
+func TestServer(t *testing.T) {
+	source := &fakeSource{
+		results: map[string]core.CheckResult{
+			"pod-health": {Name: "pod-health", Status: core.HealthStatusUnhealthy, Message: "payments/api-7f crashlooping"},
+		},
+	}
+	server := NewServer(Config{Title: "Acme Status", Source: source})
+
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var page Page
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("failed to decode page: %v", err)
+	}
+	if page.Title != "Acme Status" || page.State != StateOutage || len(page.Components[0].History) != DefaultHistoryDays {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	if strings.Contains(rec.Body.String(), "payments") {
+		t.Errorf("status page leaked check details: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Outage") {
+		t.Errorf("expected the HTML page to show the outage, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/status", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
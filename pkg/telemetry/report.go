@@ -0,0 +1,172 @@
+// Package telemetry reports anonymized KubePulse usage to the maintainers
+// when an operator opts in. Reports describe KubePulse itself: its version,
+// the features enabled, coarse cluster size buckets and how often its own
+// checks fail. They never contain names, labels, messages, addresses or any
+// other data read from the cluster, and carry no installation identifier.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SchemaVersion is bumped whenever the report format changes
+const SchemaVersion = 1
+
+// NoData is reported for sizes and rates that could not be measured
+const NoData = "unknown"
+
+// sizeBuckets are the upper bounds of the size ranges reported
+var sizeBuckets = []int{0, 10, 50, 200, 1000}
+
+// Report is the complete content of a telemetry submission
+type Report struct {
+	Schema   int         `json:"schema"`
+	Version  string      `json:"version"`
+	OS       string      `json:"os"`
+	Arch     string      `json:"arch"`
+	Features []string    `json:"features"` // Sorted feature names, e.g. "alerts.slack"
+	Cluster  ClusterSize `json:"cluster"`
+	Checks   CheckUsage  `json:"checks"`
+}
+
+// ClusterSize holds bucketed object counts, e.g. "11-50"
+type ClusterSize struct {
+	Nodes      string `json:"nodes"`
+	Namespaces string `json:"namespaces"`
+	Pods       string `json:"pods"`
+}
+
+// CheckUsage holds bucketed counts and error rates of KubePulse's own checks
+type CheckUsage struct {
+	Registered string `json:"registered"`
+	Runs       string `json:"runs"`
+	ErrorRate  string `json:"error_rate"` // Share of runs that returned an error
+	Stuck      string `json:"stuck"`
+}
+
+// Collector gathers the inputs for a report
+type Collector struct {
+	Client   kubernetes.Interface // Counts nodes, namespaces and pods; may be nil
+	Features []string
+	Usage    func() core.Usage // Engine counters; nil outside a running server
+}
+
+// Collect builds a report. Objects that cannot be listed are reported as
+// NoData rather than failing the report.
+func (c Collector) Collect(ctx context.Context) Report {
+	features := append([]string{}, c.Features...)
+	sort.Strings(features)
+
+	report := Report{
+		Schema:   SchemaVersion,
+		Version:  version.Version,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Features: features,
+		Cluster:  ClusterSize{Nodes: NoData, Namespaces: NoData, Pods: NoData},
+		Checks:   CheckUsage{Registered: NoData, Runs: NoData, ErrorRate: NoData, Stuck: NoData},
+	}
+
+	if c.Client != nil {
+		report.Cluster.Nodes = countBucket(func(opts metav1.ListOptions) (int, string, error) {
+			list, err := c.Client.CoreV1().Nodes().List(ctx, opts)
+			if err != nil {
+				return 0, "", err
+			}
+			return len(list.Items), list.Continue, nil
+		})
+		report.Cluster.Namespaces = countBucket(func(opts metav1.ListOptions) (int, string, error) {
+			list, err := c.Client.CoreV1().Namespaces().List(ctx, opts)
+			if err != nil {
+				return 0, "", err
+			}
+			return len(list.Items), list.Continue, nil
+		})
+		report.Cluster.Pods = countBucket(func(opts metav1.ListOptions) (int, string, error) {
+			list, err := c.Client.CoreV1().Pods("").List(ctx, opts)
+			if err != nil {
+				return 0, "", err
+			}
+			return len(list.Items), list.Continue, nil
+		})
+	}
+
+	if c.Usage != nil {
+		usage := c.Usage()
+		report.Checks = CheckUsage{
+			Registered: SizeBucket(usage.Checks),
+			Runs:       SizeBucket(int(usage.CheckRuns)),
+			ErrorRate:  RateBucket(usage.CheckErrors, usage.CheckRuns),
+			Stuck:      SizeBucket(usage.StuckChecks),
+		}
+	}
+	return report
+}
+
+// countBucket counts objects with list, stopping once the count exceeds the
+// largest bucket so large clusters aren't listed in full
+func countBucket(list func(metav1.ListOptions) (int, string, error)) string {
+	limit := sizeBuckets[len(sizeBuckets)-1]
+	count, more, err := list(metav1.ListOptions{Limit: int64(limit)})
+	if err != nil {
+		return NoData
+	}
+	if more != "" {
+		count = limit + 1
+	}
+	return SizeBucket(count)
+}
+
+// SizeBucket returns the range a count falls in: "0", "1-10", "11-50",
+// "51-200", "201-1000" or "1000+"
+func SizeBucket(n int) string {
+	lower := 1
+	for _, upper := range sizeBuckets {
+		if n <= upper {
+			if upper == 0 {
+				return "0"
+			}
+			return fmt.Sprintf("%d-%d", lower, upper)
+		}
+		lower = upper + 1
+	}
+	return fmt.Sprintf("%d+", sizeBuckets[len(sizeBuckets)-1])
+}
+
+// RateBucket returns the range errors/total falls in: "0%", "<1%", "1-5%",
+// "5-20%" or "20%+", or NoData without any runs
+func RateBucket(errors, total int64) string {
+	if total <= 0 {
+		return NoData
+	}
+	rate := float64(errors) / float64(total)
+	switch {
+	case errors == 0:
+		return "0%"
+	case rate < 0.01:
+		return "<1%"
+	case rate < 0.05:
+		return "1-5%"
+	case rate < 0.2:
+		return "5-20%"
+	default:
+		return "20%+"
+	}
+}
+
+// OptedOut reports whether the DO_NOT_TRACK convention disables telemetry
+// regardless of configuration
+func OptedOut() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv("DO_NOT_TRACK")))
+	return value != "" && value != "0" && value != "false"
+}
@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSizeBucket(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{1, "1-10"},
+		{10, "1-10"},
+		{11, "11-50"},
+		{200, "51-200"},
+		{1000, "201-1000"},
+		{1001, "1000+"},
+	}
+	for _, tt := range tests {
+		if got := SizeBucket(tt.n); got != tt.want {
+			t.Errorf("SizeBucket(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRateBucket(t *testing.T) {
+	tests := []struct {
+		errors, total int64
+		want          string
+	}{
+		{0, 0, NoData},
+		{0, 100, "0%"},
+		{1, 1000, "<1%"},
+		{3, 100, "1-5%"},
+		{10, 100, "5-20%"},
+		{20, 100, "20%+"},
+	}
+	for _, tt := range tests {
+		if got := RateBucket(tt.errors, tt.total); got != tt.want {
+			t.Errorf("RateBucket(%d, %d) = %q, want %q", tt.errors, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "payments-node-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "checkout-7d9f", Namespace: "payments", Labels: map[string]string{"team": "checkout"}}},
+	)
+	collector := Collector{
+		Client:   client,
+		Features: []string{"web", "alerts"},
+		Usage: func() core.Usage {
+			return core.Usage{Checks: 3, CheckRuns: 120, CheckErrors: 6}
+		},
+	}
+
+	report := collector.Collect(context.Background())
+	if !reflect.DeepEqual(report.Features, []string{"alerts", "web"}) {
+		t.Errorf("expected sorted features, got %v", report.Features)
+	}
+	wantCluster := ClusterSize{Nodes: "1-10", Namespaces: "1-10", Pods: "1-10"}
+	if report.Cluster != wantCluster {
+		t.Errorf("Cluster = %+v, want %+v", report.Cluster, wantCluster)
+	}
+	wantChecks := CheckUsage{Registered: "1-10", Runs: "51-200", ErrorRate: "5-20%", Stuck: "0"}
+	if report.Checks != wantChecks {
+		t.Errorf("Checks = %+v, want %+v", report.Checks, wantChecks)
+	}
+
+	// Nothing read from the cluster leaves in a report
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("failed to encode report: %v", err)
+	}
+	for _, secret := range []string{"payments", "checkout", "team"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("report leaks %q: %s", secret, data)
+		}
+	}
+}
+
+func TestCollector_Unmeasured(t *testing.T) {
+	report := Collector{}.Collect(context.Background())
+	if report.Cluster.Nodes != NoData || report.Checks.ErrorRate != NoData {
+		t.Errorf("expected values without a client or engine to be %q, got %+v", NoData, report)
+	}
+	if report.Schema != SchemaVersion || report.Version == "" {
+		t.Errorf("expected schema and version to be set, got %+v", report)
+	}
+}
+
+func TestOptedOut(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"0", false},
+		{"false", false},
+		{"1", true},
+		{"true", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("DO_NOT_TRACK", tt.value)
+		if got := OptedOut(); got != tt.want {
+			t.Errorf("OptedOut() with DO_NOT_TRACK=%q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
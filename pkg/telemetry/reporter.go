@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"k8s.io/klog/v2"
+)
+
+// Status describes the reporter's settings and its most recent submission
+type Status struct {
+	Enabled    bool      `json:"enabled"`
+	Endpoint   string    `json:"endpoint,omitempty"`
+	Interval   string    `json:"interval"`
+	LastSentAt time.Time `json:"last_sent_at,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Reporter sends a report to the endpoint on every interval while enabled.
+// A disabled reporter still previews what it would send.
+type Reporter struct {
+	enabled  bool
+	endpoint string
+	interval time.Duration
+	collect  func(ctx context.Context) Report
+	client   *http.Client
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewReporter returns a reporter posting reports from collect to endpoint
+func NewReporter(enabled bool, endpoint string, interval time.Duration, collect func(ctx context.Context) Report) *Reporter {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	return &Reporter{
+		enabled:  enabled,
+		endpoint: endpoint,
+		interval: interval,
+		collect:  collect,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		status: Status{
+			Enabled:  enabled,
+			Endpoint: endpoint,
+			Interval: interval.String(),
+		},
+	}
+}
+
+// Run sends a report on every interval until ctx is cancelled. The first
+// report waits a full interval so it covers a representative period.
+func (r *Reporter) Run(ctx context.Context) {
+	if !r.enabled {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Send(ctx); err != nil {
+				klog.V(2).Infof("Telemetry report failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Preview returns the report that would be sent now
+func (r *Reporter) Preview(ctx context.Context) Report {
+	return r.collect(ctx)
+}
+
+// Send collects and posts a report
+func (r *Reporter) Send(ctx context.Context) error {
+	err := r.send(ctx, r.collect(ctx))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.status.LastError = err.Error()
+		return err
+	}
+	r.status.LastSentAt = time.Now()
+	r.status.LastError = ""
+	return nil
+}
+
+func (r *Reporter) send(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kubepulse/"+version.Version)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry report: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("telemetry endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Status returns the reporter's settings and last submission
+func (r *Reporter) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
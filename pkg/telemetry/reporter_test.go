@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReporter_Send(t *testing.T) {
+	var received Report
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode report: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	collect := func(ctx context.Context) Report {
+		return Report{Schema: SchemaVersion, Features: []string{"web"}}
+	}
+	reporter := NewReporter(true, server.URL, time.Hour, collect)
+
+	if err := reporter.Send(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// What is sent is exactly what the preview shows
+	preview := reporter.Preview(context.Background())
+	if received.Schema != preview.Schema || len(received.Features) != 1 || received.Features[0] != preview.Features[0] {
+		t.Errorf("sent %+v, previewed %+v", received, preview)
+	}
+	if got := reporter.Status(); got.LastSentAt.IsZero() || got.LastError != "" || !got.Enabled {
+		t.Errorf("unexpected status after a successful send: %+v", got)
+	}
+
+	status = http.StatusInternalServerError
+	if err := reporter.Send(context.Background()); err == nil {
+		t.Fatal("expected an error when the endpoint fails")
+	}
+	if got := reporter.Status(); got.LastError == "" {
+		t.Error("expected the failure to be recorded in the status")
+	}
+}
+
+func TestReporter_DisabledRunReturns(t *testing.T) {
+	reporter := NewReporter(false, "", 0, func(ctx context.Context) Report { return Report{} })
+	done := make(chan struct{})
+	go func() {
+		reporter.Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return immediately when disabled")
+	}
+	if got := reporter.Status(); got.Enabled || got.Interval != "24h0m0s" {
+		t.Errorf("unexpected status %+v", got)
+	}
+}
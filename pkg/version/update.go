@@ -0,0 +1,242 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultRepository is the GitHub repository checked for new releases
+const DefaultRepository = "charles-adedotun/kubepulse"
+
+// maxHighlights caps the changelog entries surfaced with an update
+const maxHighlights = 8
+
+// UpdateConfig configures the release checker
+type UpdateConfig struct {
+	Repository string        // owner/name on GitHub; defaults to DefaultRepository
+	Interval   time.Duration // Time between background checks; defaults to 24h
+	Features   []string      // Enabled feature keywords used to pick changelog highlights
+	APIURL     string        // GitHub API base URL; defaults to https://api.github.com
+	HTTPClient *http.Client
+}
+
+// UpdateStatus is the result of comparing the running version to the latest release
+type UpdateStatus struct {
+	CurrentVersion  string    `json:"current_version"`
+	LatestVersion   string    `json:"latest_version"`
+	UpdateAvailable bool      `json:"update_available"`
+	ReleaseURL      string    `json:"release_url,omitempty"`
+	PublishedAt     time.Time `json:"published_at"`
+	Highlights      []string  `json:"highlights,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+}
+
+// UpdateChecker periodically compares the running version against the
+// latest GitHub release
+type UpdateChecker struct {
+	config UpdateConfig
+	mu     sync.RWMutex
+	status *UpdateStatus
+}
+
+// githubRelease is the subset of the GitHub release payload we use
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	Body        string    `json:"body"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// NewUpdateChecker creates a release checker
+func NewUpdateChecker(config UpdateConfig) *UpdateChecker {
+	if config.Repository == "" {
+		config.Repository = DefaultRepository
+	}
+	if config.Interval <= 0 {
+		config.Interval = 24 * time.Hour
+	}
+	if config.APIURL == "" {
+		config.APIURL = "https://api.github.com"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &UpdateChecker{config: config}
+}
+
+// Check fetches the latest release and records the comparison
+func (c *UpdateChecker) Check(ctx context.Context) (*UpdateStatus, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimRight(c.config.APIURL, "/"), c.config.Repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "kubepulse/"+Version)
+
+	resp, err := c.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("latest release lookup returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	status := &UpdateStatus{
+		CurrentVersion: Version,
+		LatestVersion:  strings.TrimPrefix(release.TagName, "v"),
+		ReleaseURL:     release.HTMLURL,
+		PublishedAt:    release.PublishedAt,
+		CheckedAt:      time.Now(),
+	}
+	status.UpdateAvailable = CompareVersions(status.LatestVersion, status.CurrentVersion) > 0
+	if status.UpdateAvailable {
+		status.Highlights = Highlights(release.Body, c.config.Features)
+	}
+
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+
+	return status, nil
+}
+
+// Status returns the most recent check result, or nil if none succeeded yet
+func (c *UpdateChecker) Status() *UpdateStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+// Run checks immediately and then on every interval until ctx is cancelled
+func (c *UpdateChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	notified := ""
+	for {
+		status, err := c.Check(ctx)
+		switch {
+		case err != nil:
+			klog.V(2).Infof("Update check failed: %v", err)
+		case status.UpdateAvailable && status.LatestVersion != notified:
+			notified = status.LatestVersion
+			klog.Infof("KubePulse %s is available (running %s): %s",
+				status.LatestVersion, status.CurrentVersion, status.ReleaseURL)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CompareVersions compares two semantic versions, returning -1, 0 or 1.
+// A leading "v" is ignored and a pre-release sorts before its release.
+func CompareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if coreA[i] != coreB[i] {
+			if coreA[i] < coreB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	case preA < preB:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// splitVersion parses "v1.2.3-rc.1+meta" into [1 2 3] and "rc.1"
+func splitVersion(v string) ([3]int, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	pre := ""
+	if i := strings.Index(v, "-"); i >= 0 {
+		v, pre = v[:i], v[i+1:]
+	}
+
+	var parts [3]int
+	for i, field := range strings.SplitN(v, ".", 3) {
+		parts[i], _ = strconv.Atoi(field)
+	}
+	return parts, pre
+}
+
+// Highlights picks notable changelog entries from release notes: bullets
+// mentioning an enabled feature, plus breaking and security changes. Without
+// features the first entries are returned.
+func Highlights(notes string, features []string) []string {
+	highlights := make([]string, 0)
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") && !strings.HasPrefix(line, "* ") {
+			continue
+		}
+		entry := strings.TrimSpace(line[2:])
+		if entry == "" {
+			continue
+		}
+
+		if len(features) > 0 && !mentionsAny(entry, features) &&
+			!mentionsAny(entry, []string{"breaking", "security", "cve"}) {
+			continue
+		}
+
+		highlights = append(highlights, entry)
+		if len(highlights) == maxHighlights {
+			break
+		}
+	}
+	return highlights
+}
+
+// mentionsAny reports whether text mentions any keyword as a whole word
+// (plurals included), case-insensitively
+func mentionsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		pattern := `(?i)\b` + regexp.QuoteMeta(keyword) + `s?\b`
+		if matched, _ := regexp.MatchString(pattern, text); matched {
+			return true
+		}
+	}
+	return false
+}
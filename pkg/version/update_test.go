@@ -0,0 +1,92 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.2.0", 0},
+		{"v1.3.0", "1.2.9", 1},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"2.0.0", "2.0.0-rc.1", 1},
+		{"2.0.0-rc.2", "2.0.0-rc.1", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.2.3+build.5", "1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	notes := `## What's new
+- Add SLO burn rate alerts
+- Improve dashboard build times
+- Fix typo in docs
+* **Breaking:** rename config key
+- Faster maintenance of node pools`
+
+	got := Highlights(notes, []string{"slo", "ai"})
+	if len(got) != 2 || got[0] != "Add SLO burn rate alerts" || got[1] != "**Breaking:** rename config key" {
+		t.Errorf("unexpected highlights %q", got)
+	}
+
+	if all := Highlights(notes, nil); len(all) != 5 {
+		t.Errorf("expected every entry without features, got %d", len(all))
+	}
+}
+
+func TestUpdateChecker_Check(t *testing.T) {
+	original := Version
+	defer func() { Version = original }()
+	Version = "1.0.0"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/kubepulse/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"tag_name": "v1.1.0",
+			"html_url": "https://github.com/acme/kubepulse/releases/tag/v1.1.0",
+			"body": "- New alert rule suggestions\n- Unrelated change",
+			"published_at": "2026-01-02T03:04:05Z"
+		}`))
+	}))
+	defer ts.Close()
+
+	checker := NewUpdateChecker(UpdateConfig{Repository: "acme/kubepulse", APIURL: ts.URL, Features: []string{"alert"}})
+	if checker.Status() != nil {
+		t.Fatal("expected no status before the first check")
+	}
+
+	status, err := checker.Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !status.UpdateAvailable || status.LatestVersion != "1.1.0" || status.CurrentVersion != "1.0.0" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if len(status.Highlights) != 1 || status.Highlights[0] != "New alert rule suggestions" {
+		t.Errorf("unexpected highlights %q", status.Highlights)
+	}
+	if checker.Status() != status {
+		t.Error("expected the last check to be cached")
+	}
+
+	missing := NewUpdateChecker(UpdateConfig{Repository: "acme/missing", APIURL: ts.URL})
+	if _, err := missing.Check(context.Background()); err == nil {
+		t.Error("expected error for a repository without releases")
+	}
+}
@@ -0,0 +1,60 @@
+// Package version exposes build information injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/kubepulse/kubepulse/pkg/version.Version=1.2.0"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set via -ldflags -X at build time
+var (
+	Version   = "0.1.0"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information, falling back to the VCS metadata the Go
+// toolchain embeds when the commit or date weren't injected
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitCommit == "unknown":
+				info.GitCommit = setting.Value
+				if len(info.GitCommit) > 7 {
+					info.GitCommit = info.GitCommit[:7]
+				}
+			case setting.Key == "vcs.time" && info.BuildDate == "unknown":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+
+	return info
+}
+
+// String formats the build information for humans
+func (i Info) String() string {
+	return fmt.Sprintf("kubepulse %s (commit %s, built %s, %s, %s)",
+		i.Version, i.GitCommit, i.BuildDate, i.GoVersion, i.Platform)
+}
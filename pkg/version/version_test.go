@@ -0,0 +1,27 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	original := Version
+	defer func() { Version = original }()
+	Version = "1.2.3"
+
+	info := Get()
+	if info.Version != "1.2.3" {
+		t.Errorf("expected injected version, got %s", info.Version)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+	if info.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("unexpected platform %s", info.Platform)
+	}
+	if !strings.Contains(info.String(), "kubepulse 1.2.3") {
+		t.Errorf("unexpected string form %q", info.String())
+	}
+}
@@ -0,0 +1,424 @@
+// Package webhooks posts check results to external automation, such as
+// scaling scripts or ticketing bots, when a check's status changes. Unlike
+// alert notifications, which are written for people, a webhook receives the
+// full structured check result, and filters select the transitions it
+// cares about.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/version"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// EventCheckTransition is sent when a check's status changes
+const EventCheckTransition = "check.transition"
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-KubePulse-Event"
+	HeaderDelivery  = "X-KubePulse-Delivery"
+	HeaderSignature = "X-KubePulse-Signature" // sha256=<hex HMAC-SHA256 of the body>; only with a secret
+)
+
+// Fields filters select transitions by
+const (
+	FieldCheck     = "check"
+	FieldFrom      = "from"
+	FieldTo        = "to"
+	FieldNamespace = "namespace" // Namespaces of the resources the result implicates
+)
+
+// Sources a webhook can be registered from
+const (
+	SourceConfig = "config" // The webhooks section of the config file
+	SourceAPI    = "api"    // POST /api/v1/webhooks; lost on restart
+)
+
+const (
+	// maxAttempts is how often a delivery is tried before it is dropped
+	maxAttempts = 3
+	// maxConcurrentDeliveries bounds deliveries in flight across webhooks
+	maxConcurrentDeliveries = 4
+	// queueSize bounds the deliveries waiting to be sent; more are dropped
+	queueSize = 256
+)
+
+var (
+	// ErrWebhookExists is returned when registering a name already in use
+	ErrWebhookExists = errors.New("webhook already exists")
+
+	// ErrWebhookNotFound is returned when removing an unknown webhook
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrConfigured is returned when removing a webhook from the config
+	// file, which would come back on restart
+	ErrConfigured = errors.New("webhook is defined in the config file")
+)
+
+var filterFields = map[string]bool{FieldCheck: true, FieldFrom: true, FieldTo: true, FieldNamespace: true}
+
+// Webhook is an endpoint notified of the check transitions its filter selects
+type Webhook struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Filter string `json:"filter,omitempty"` // Label selector over check, from, to and namespace; empty selects every transition
+	Secret string `json:"secret,omitempty"` // Signs deliveries; never returned by the API
+	Source string `json:"source"`
+}
+
+// Status is a webhook, without its secret, and how its deliveries went
+type Status struct {
+	Webhook
+	Delivered    int       `json:"delivered"`
+	Failed       int       `json:"failed"` // Dropped after every attempt failed
+	LastDelivery time.Time `json:"last_delivery,omitzero"`
+	LastError    string    `json:"last_error,omitempty"`
+}
+
+// Event is the body of a delivery
+type Event struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Cluster    string            `json:"cluster,omitempty"`
+	Check      string            `json:"check"`
+	From       core.HealthStatus `json:"from"`
+	To         core.HealthStatus `json:"to"`
+	Namespaces []string          `json:"namespaces,omitempty"`
+	Result     core.CheckResult  `json:"result"`
+}
+
+// ParseFilter parses a filter: a Kubernetes label selector over the check,
+// from, to and namespace fields, such as
+// "to=unhealthy,namespace=payments" or "check in (pod-health,node-health),from=healthy"
+func ParseFilter(filter string) (labels.Selector, error) {
+	selector, err := labels.Parse(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", filter, err)
+	}
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		if !filterFields[requirement.Key()] {
+			return nil, fmt.Errorf("invalid filter %q: unknown field %s; use check, from, to or namespace", filter, requirement.Key())
+		}
+	}
+	return selector, nil
+}
+
+// registration is a webhook with its parsed filter and delivery counts
+type registration struct {
+	status   Status
+	selector labels.Selector
+}
+
+// delivery is an event waiting to be posted to a webhook
+type delivery struct {
+	webhook Webhook
+	event   Event
+}
+
+// Dispatcher watches check results and posts each status transition to the
+// webhooks whose filters select it. Deliveries are sent in the background
+// and retried with backoff, so a slow endpoint never delays the engine.
+type Dispatcher struct {
+	cluster string
+	client  *http.Client
+	backoff time.Duration // Before the second attempt; doubles after
+
+	mu       sync.Mutex
+	webhooks map[string]*registration
+	last     map[string]core.HealthStatus // Latest status per check
+	seq      int
+
+	queue chan delivery
+}
+
+// NewDispatcher returns a dispatcher for the named cluster
+func NewDispatcher(cluster string) *Dispatcher {
+	return &Dispatcher{
+		cluster:  cluster,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		backoff:  2 * time.Second,
+		webhooks: make(map[string]*registration),
+		last:     make(map[string]core.HealthStatus),
+		queue:    make(chan delivery, queueSize),
+	}
+}
+
+// Register adds a webhook
+func (d *Dispatcher) Register(webhook Webhook) error {
+	if webhook.Name == "" {
+		return fmt.Errorf("webhook needs a name")
+	}
+	if parsed, err := url.Parse(webhook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("webhook %s: url must be an absolute http or https URL", webhook.Name)
+	}
+	selector, err := ParseFilter(webhook.Filter)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", webhook.Name, err)
+	}
+	if webhook.Source == "" {
+		webhook.Source = SourceAPI
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.webhooks[webhook.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrWebhookExists, webhook.Name)
+	}
+	d.webhooks[webhook.Name] = &registration{status: Status{Webhook: webhook}, selector: selector}
+	return nil
+}
+
+// Unregister removes a webhook registered through the API
+func (d *Dispatcher) Unregister(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reg, ok := d.webhooks[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrWebhookNotFound, name)
+	}
+	if reg.status.Source == SourceConfig {
+		return fmt.Errorf("%w: %s", ErrConfigured, name)
+	}
+	delete(d.webhooks, name)
+	return nil
+}
+
+// List returns the webhooks by name, without their secrets
+func (d *Dispatcher) List() []Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	list := make([]Status, 0, len(d.webhooks))
+	for _, reg := range d.webhooks {
+		status := reg.status
+		status.Secret = ""
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Observe queues a delivery to each matching webhook when a check's status
+// differs from its previous result. It is meant to be registered with
+// Engine.OnCheckResult; a check's first result is not a transition.
+func (d *Dispatcher) Observe(result core.CheckResult) {
+	d.mu.Lock()
+	previous, seen := d.last[result.Name]
+	d.last[result.Name] = result.Status
+	if !seen || previous == result.Status {
+		d.mu.Unlock()
+		return
+	}
+
+	event := Event{
+		Type:       EventCheckTransition,
+		Timestamp:  time.Now(),
+		Cluster:    d.cluster,
+		Check:      result.Name,
+		From:       previous,
+		To:         result.Status,
+		Namespaces: namespaces(result),
+		Result:     result,
+	}
+	var matched []delivery
+	for _, reg := range d.webhooks {
+		if !matches(reg.selector, event) {
+			continue
+		}
+		d.seq++
+		event.ID = fmt.Sprintf("%s-%d-%d", reg.status.Name, event.Timestamp.Unix(), d.seq)
+		matched = append(matched, delivery{webhook: reg.status.Webhook, event: event})
+	}
+	d.mu.Unlock()
+
+	for _, queued := range matched {
+		select {
+		case d.queue <- queued:
+		default:
+			klog.Warningf("Webhook queue is full; dropping %s of %s to %s", queued.event.ID, result.Name, queued.webhook.Name)
+			d.record(queued.webhook.Name, fmt.Errorf("delivery queue full"))
+		}
+	}
+}
+
+// Run delivers queued events until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	slots := make(chan struct{}, maxConcurrentDeliveries)
+	for {
+		select {
+		case queued := <-d.queue:
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				defer func() { <-slots }()
+				d.record(queued.webhook.Name, d.deliver(ctx, queued))
+			}()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver posts an event, retrying failures with exponential backoff
+func (d *Dispatcher) deliver(ctx context.Context, queued delivery) error {
+	body, err := json.Marshal(queued.event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	backoff := d.backoff
+	for attempt := 1; ; attempt++ {
+		err = d.post(ctx, queued.webhook, queued.event.ID, body)
+		if err == nil || attempt == maxAttempts {
+			break
+		}
+		klog.V(2).Infof("Webhook %s attempt %d failed, retrying in %s: %v", queued.webhook.Name, attempt, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		klog.Warningf("Dropping %s to webhook %s after %d attempts: %v", queued.event.ID, queued.webhook.Name, maxAttempts, err)
+	}
+	return err
+}
+
+// post sends one delivery attempt
+func (d *Dispatcher) post(ctx context.Context, webhook Webhook, id string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kubepulse/"+version.Version)
+	req.Header.Set(HeaderEvent, EventCheckTransition)
+	req.Header.Set(HeaderDelivery, id)
+	if webhook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// record counts the outcome of a delivery
+func (d *Dispatcher) record(name string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reg, ok := d.webhooks[name]
+	if !ok {
+		return
+	}
+	reg.status.LastDelivery = time.Now()
+	if err != nil {
+		reg.status.Failed++
+		reg.status.LastError = err.Error()
+		return
+	}
+	reg.status.Delivered++
+	reg.status.LastError = ""
+}
+
+// Sign returns the signature header value for a body, so receivers can
+// check a delivery came from KubePulse
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matches reports whether a filter selects an event. Namespace
+// requirements hold when any implicated namespace satisfies them; for a
+// result implicating none, namespace is treated as unset.
+func matches(selector labels.Selector, event Event) bool {
+	if selector.Empty() {
+		return true
+	}
+	fields := labels.Set{
+		FieldCheck: event.Check,
+		FieldFrom:  string(event.From),
+		FieldTo:    string(event.To),
+	}
+
+	requirements, _ := selector.Requirements()
+	for _, requirement := range requirements {
+		if requirement.Key() != FieldNamespace {
+			if !requirement.Matches(fields) {
+				return false
+			}
+			continue
+		}
+		if len(event.Namespaces) == 0 {
+			if !requirement.Matches(labels.Set{}) {
+				return false
+			}
+			continue
+		}
+		matched := false
+		for _, namespace := range event.Namespaces {
+			if requirement.Matches(labels.Set{FieldNamespace: namespace}) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// namespaces returns the sorted namespaces of the resources a result
+// implicates or reports findings on
+func namespaces(result core.CheckResult) []string {
+	seen := make(map[string]bool)
+	for _, ref := range core.ImplicatedResources(result) {
+		if ref.Namespace != "" {
+			seen[ref.Namespace] = true
+		}
+	}
+	for _, finding := range core.Findings(result) {
+		if ref, ok := core.ParseResourceRef(finding.Subject); ok && ref.Namespace != "" {
+			seen[ref.Namespace] = true
+		}
+	}
+
+	list := make([]string, 0, len(seen))
+	for namespace := range seen {
+		list = append(list, namespace)
+	}
+	sort.Strings(list)
+	return list
+}
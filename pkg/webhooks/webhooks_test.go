@@ -0,0 +1,187 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubepulse/kubepulse/pkg/core"
+	"github.com/kubepulse/kubepulse/pkg/findings"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		wantErr bool
+	}{
+		{"", false},
+		{"to=unhealthy", false},
+		{"check in (pod-health,node-health),from!=healthy", false},
+		{"namespace=payments,to=unhealthy", false},
+		{"!namespace", false},
+		{"severity=critical", true},
+		{"to in (", true},
+	}
+	for _, tt := range tests {
+		if _, err := ParseFilter(tt.filter); (err != nil) != tt.wantErr {
+			t.Errorf("ParseFilter(%q) error = %v, wantErr %v", tt.filter, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	payments := Event{Check: "pod-health", From: core.HealthStatusHealthy, To: core.HealthStatusUnhealthy, Namespaces: []string{"checkout", "payments"}}
+	cluster := Event{Check: "node-health", From: core.HealthStatusHealthy, To: core.HealthStatusDegraded}
+
+	tests := []struct {
+		filter string
+		event  Event
+		want   bool
+	}{
+		{"", cluster, true},
+		{"to=unhealthy", payments, true},
+		{"to=unhealthy", cluster, false},
+		{"namespace=payments,to=unhealthy", payments, true},
+		{"namespace=payments", cluster, false},
+		{"namespace!=payments", cluster, true},
+		{"namespace notin (payments,checkout)", payments, false},
+		{"!namespace", cluster, true},
+		{"namespace", cluster, false},
+		{"check in (node-health),from=healthy", cluster, true},
+	}
+	for _, tt := range tests {
+		selector, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) error = %v", tt.filter, err)
+		}
+		if got := matches(selector, tt.event); got != tt.want {
+			t.Errorf("matches(%q, %s) = %v, want %v", tt.filter, tt.event.Check, got, tt.want)
+		}
+	}
+}
+
+func TestDispatcher_DeliversTransitions(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+	var signatures []string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("failed to decode delivery: %v", err)
+		}
+		mu.Lock()
+		received = append(received, event)
+		signatures = append(signatures, r.Header.Get(HeaderSignature))
+		if r.Header.Get(HeaderSignature) != Sign("s3cret", body) || r.Header.Get(HeaderDelivery) != event.ID {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		mu.Unlock()
+	}))
+	defer endpoint.Close()
+
+	d := NewDispatcher("prod-eu")
+	if err := d.Register(Webhook{Name: "payments", URL: endpoint.URL, Filter: "to=unhealthy,namespace=payments", Secret: "s3cret"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.Run(ctx)
+
+	failing := core.CheckResult{
+		Name:   "pod-health",
+		Status: core.HealthStatusUnhealthy,
+		Details: map[string]interface{}{
+			core.DetailFindings: []findings.Finding{{ID: findings.PodCrashLoop, Subject: "pod/payments/api-1"}},
+		},
+	}
+	d.Observe(core.CheckResult{Name: "pod-health", Status: core.HealthStatusHealthy}) // First result, not a transition
+	d.Observe(failing)
+	d.Observe(failing) // Unchanged
+	d.Observe(core.CheckResult{Name: "node-health", Status: core.HealthStatusHealthy})
+	d.Observe(core.CheckResult{Name: "node-health", Status: core.HealthStatusUnhealthy}) // No namespace
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if statuses := d.List(); statuses[0].Delivered == 1 {
+			if statuses[0].Secret != "" || statuses[0].Source != SourceAPI {
+				t.Errorf("unexpected status %+v", statuses[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the delivery")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(received))
+	}
+	event := received[0]
+	if event.Type != EventCheckTransition || event.Cluster != "prod-eu" || event.From != core.HealthStatusHealthy || event.To != core.HealthStatusUnhealthy {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if len(core.Findings(event.Result)) != 1 || event.Namespaces[0] != "payments" {
+		t.Errorf("expected the full check result, got %+v", event)
+	}
+}
+
+func TestDispatcher_RetriesAndCountsFailures(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer endpoint.Close()
+
+	d := NewDispatcher("")
+	d.backoff = time.Millisecond
+	if err := d.Register(Webhook{Name: "tickets", URL: endpoint.URL}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	event := Event{ID: "tickets-1", Type: EventCheckTransition}
+	err := d.deliver(context.Background(), delivery{webhook: Webhook{Name: "tickets", URL: endpoint.URL}, event: event})
+	d.record("tickets", err)
+
+	if err == nil || attempts != maxAttempts {
+		t.Errorf("expected %d failed attempts, got %d: %v", maxAttempts, attempts, err)
+	}
+	if status := d.List()[0]; status.Failed != 1 || status.LastError == "" {
+		t.Errorf("expected the failure to be counted, got %+v", status)
+	}
+}
+
+func TestDispatcher_Register(t *testing.T) {
+	d := NewDispatcher("")
+	if err := d.Register(Webhook{Name: "scaler", URL: "https://automation.example.com", Source: SourceConfig}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if err := d.Register(Webhook{Name: "scaler", URL: "https://other.example.com"}); !errors.Is(err, ErrWebhookExists) {
+		t.Errorf("expected ErrWebhookExists, got %v", err)
+	}
+	if err := d.Register(Webhook{Name: "bot", URL: "automation.example.com"}); err == nil {
+		t.Error("expected an error for a URL without a scheme")
+	}
+	if err := d.Register(Webhook{Name: "bot", URL: "https://bot.example.com", Filter: "severity=critical"}); err == nil {
+		t.Error("expected an error for an unknown filter field")
+	}
+	if err := d.Unregister("scaler"); !errors.Is(err, ErrConfigured) {
+		t.Errorf("expected ErrConfigured, got %v", err)
+	}
+	if err := d.Unregister("bot"); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+#!/bin/bash
+
+# Generate (and optionally publish) KubePulse API clients from api/openapi.yaml
+#
+# Usage: scripts/generate-clients.sh [validate|python|typescript|all|publish]
+#
+# Uses the openapi-generator jar named by OPENAPI_GENERATOR_JAR when set, as in
+# the Docker build, else the openapi-generator Docker image when Docker is
+# available, and the npm wrapper otherwise. Output goes to clients/<language>.
+# The dashboard imports the TypeScript client from clients/typescript.
+
+set -e
+
+ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+SPEC="api/openapi.yaml"
+OUT_DIR="${CLIENTS_DIR:-clients}"
+GENERATOR_VERSION="${OPENAPI_GENERATOR_VERSION:-v7.10.0}"
+VERSION="${VERSION:-$(grep -E '^  version:' "${ROOT_DIR}/${SPEC}" | head -1 | awk '{print $2}')}"
+
+cd "${ROOT_DIR}"
+
+# openapi_generator runs openapi-generator-cli with the repo mounted at /local
+openapi_generator() {
+    if [ -n "${OPENAPI_GENERATOR_JAR}" ]; then
+        java -jar "${OPENAPI_GENERATOR_JAR}" "$@"
+    elif command -v docker > /dev/null 2>&1; then
+        docker run --rm -u "$(id -u):$(id -g)" -v "${ROOT_DIR}:/local" -w /local \
+            "openapitools/openapi-generator-cli:${GENERATOR_VERSION}" "$@"
+    elif command -v npx > /dev/null 2>&1; then
+        OPENAPI_GENERATOR_VERSION="${GENERATOR_VERSION#v}" \
+            npx --yes @openapitools/openapi-generator-cli "$@"
+    else
+        echo "❌ Docker or npx is required to run openapi-generator" >&2
+        exit 1
+    fi
+}
+
+validate() {
+    for spec in "${SPEC}" api/openapi-v2beta.yaml; do
+        echo "🔍 Validating ${spec}..."
+        openapi_generator validate -i "${spec}"
+    done
+}
+
+generate_python() {
+    echo "🐍 Generating Python client ${VERSION} into ${OUT_DIR}/python..."
+    rm -rf "${OUT_DIR}/python"
+    openapi_generator generate \
+        -i "${SPEC}" \
+        -g python \
+        -o "${OUT_DIR}/python" \
+        --package-name kubepulse_client \
+        --additional-properties "projectName=kubepulse-client,packageVersion=${VERSION}"
+}
+
+generate_typescript() {
+    echo "📦 Generating TypeScript client ${VERSION} into ${OUT_DIR}/typescript..."
+    rm -rf "${OUT_DIR}/typescript"
+    openapi_generator generate \
+        -i "${SPEC}" \
+        -g typescript-fetch \
+        -o "${OUT_DIR}/typescript" \
+        --additional-properties "npmName=@kubepulse/client,npmVersion=${VERSION},supportsES6=true,withInterfaces=true"
+}
+
+publish() {
+    if [ ! -d "${OUT_DIR}/python" ] || [ ! -d "${OUT_DIR}/typescript" ]; then
+        echo "❌ Clients not generated; run '$0 all' first" >&2
+        exit 1
+    fi
+
+    echo "🚀 Publishing Python client to PyPI..."
+    (cd "${OUT_DIR}/python" && python3 -m pip install --quiet build twine && \
+        python3 -m build && python3 -m twine upload --non-interactive dist/*)
+
+    echo "🚀 Publishing TypeScript client to npm..."
+    (cd "${OUT_DIR}/typescript" && npm install && npm run build && \
+        npm publish --access public)
+}
+
+case "${1:-all}" in
+    validate)
+        validate
+        ;;
+    python)
+        validate
+        generate_python
+        ;;
+    typescript)
+        validate
+        generate_typescript
+        ;;
+    all)
+        validate
+        generate_python
+        generate_typescript
+        ;;
+    publish)
+        publish
+        ;;
+    *)
+        echo "Usage: $0 [validate|python|typescript|all|publish]" >&2
+        exit 1
+        ;;
+esac
+
+echo "✅ Done"
@@ -109,7 +109,7 @@ func TestAnomalyDetector_MultipleMetrics(t *testing.T) {
 	// Should detect the memory anomaly but not CPU
 	foundMemoryAnomaly := false
 	for _, pred := range predictions {
-		if pred.Reason == "Statistical anomaly detected" {
+		if pred.Explanation != nil && pred.Explanation.Metric == "memory_usage" {
 			foundMemoryAnomaly = true
 			break
 		}